@@ -25,13 +25,19 @@ import (
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/util/db"
 	"github.com/algorand/go-algorand/util/execpool"
+	"github.com/algorand/go-algorand/util/metrics"
 	"github.com/algorand/go-algorand/util/timers"
+	"github.com/algorand/go-algorand/util/tracing"
 )
 
 const (
 	defaultCadaverName = "agreement"
 )
 
+var agreementRound = metrics.MakeGauge(metrics.AgreementRound)
+var agreementPeriod = metrics.MakeGauge(metrics.AgreementPeriod)
+var agreementStep = metrics.MakeGauge(metrics.AgreementStep)
+
 // Service represents an instance of an execution of Algorand's agreement protocol.
 type Service struct {
 	parameters
@@ -195,6 +201,8 @@ func (s *Service) mainLoop(input <-chan externalEvent, output chan<- []action, r
 		s.Clock = clock
 	}
 
+	var spans stepSpans
+	var reported player
 	for {
 		output <- a
 		ready <- externalDemuxSignals{Deadline: status.Deadline, FastRecoveryDeadline: status.FastRecoveryDeadline, CurrentRound: status.Round}
@@ -204,6 +212,13 @@ func (s *Service) mainLoop(input <-chan externalEvent, output chan<- []action, r
 		}
 
 		status, a = router.submitTop(s.tracer, status, e)
+		spans.update(status)
+		if status.Round != reported.Round || status.Period != reported.Period || status.Step != reported.Step {
+			agreementRound.Set(float64(status.Round), nil)
+			agreementPeriod.Set(float64(status.Period), nil)
+			agreementStep.Set(float64(status.Step), nil)
+			reported = status
+		}
 
 		if persistent(a) {
 			s.persistRouter = router
@@ -211,9 +226,45 @@ func (s *Service) mainLoop(input <-chan externalEvent, output chan<- []action, r
 			s.persistActions = a
 		}
 	}
+	spans.end()
 	close(output)
 }
 
+// stepSpans traces the rounds of the state machine, and the periods and steps within them.
+type stepSpans struct {
+	status    player
+	roundCtx  context.Context
+	roundSpan *tracing.Span
+	stepSpan  *tracing.Span
+}
+
+// update starts new spans once the state machine moves to a new round or step.
+func (t *stepSpans) update(status player) {
+	if !tracing.Enabled() {
+		t.end()
+		return
+	}
+	if t.roundSpan == nil || status.Round != t.status.Round {
+		t.end()
+		t.roundCtx, t.roundSpan = tracing.StartSpan(context.Background(), "agreement.round", tracing.SpanKindInternal,
+			tracing.Attr(logging.RoundField, uint64(status.Round)))
+	} else if status.Period == t.status.Period && status.Step == t.status.Step {
+		return
+	}
+	t.stepSpan.End()
+	_, t.stepSpan = tracing.StartSpan(t.roundCtx, "agreement.step", tracing.SpanKindInternal,
+		tracing.Attr(logging.RoundField, uint64(status.Round)),
+		tracing.Attr("period", uint64(status.Period)),
+		tracing.Attr("step", uint64(status.Step)))
+	t.status = status
+}
+
+func (t *stepSpans) end() {
+	t.stepSpan.End()
+	t.roundSpan.End()
+	t.stepSpan, t.roundSpan = nil, nil
+}
+
 // persistState encodes the existing state of the agreement service and enqueue the
 // encoded state to the persistence loop so it will get stored asynchronously.
 // the done channel would get closed once operation complete successfully, or return an
@@ -18,10 +18,13 @@ package catchup
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/algorand/go-deadlock"
+
 	"github.com/algorand/go-algorand/agreement"
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
@@ -35,10 +38,30 @@ import (
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/rpcs"
 	"github.com/algorand/go-algorand/util/execpool"
+	"github.com/algorand/go-algorand/util/metrics"
 )
 
 const catchupPeersForSync = 10
 
+var catchupBlocksTotal = metrics.MakeCounter(metrics.CatchupBlocksTotal)
+var catchupBadBlocksTotal = metrics.MakeCounter(metrics.CatchupBadBlocksTotal)
+
+// SyncProgress is the progress of the current catchup
+type SyncProgress struct {
+	// Blocks is the number of blocks fetched, verified and written to the ledger since the catchup started
+	Blocks uint64
+	// BlocksPerSecond is the rate at which the blocks were written
+	BlocksPerSecond float64
+	// Sources are the peers that served the blocks, the most productive first
+	Sources []SyncSource
+}
+
+// SyncSource is a peer that served blocks to the current catchup
+type SyncSource struct {
+	Address string
+	Blocks  uint64
+}
+
 // Service represents the catchup service. Once started and until it is stopped, it ensures that the ledger is up to date with network.
 type Service struct {
 	syncStartNS     int64 // at top of struct to keep 64 bit aligned for atomic.* ops
@@ -59,6 +82,11 @@ type Service struct {
 	InitialSyncDone     chan struct{}
 	initialSyncNotified uint32
 	protocolErrorLogged bool
+
+	// progressMu guards the blocks written by the current sync, counted by the address of their source
+	progressMu    deadlock.Mutex
+	syncedBlocks  uint64
+	syncedSources map[string]uint64
 }
 
 // MakeService creates a catchup service instance from its constituent components
@@ -114,6 +142,54 @@ func (s *Service) SynchronizingTime() time.Duration {
 	return time.Duration(timeInNS - startNS)
 }
 
+// Progress returns the progress of the current catchup, which is empty if the service isn't catching up
+func (s *Service) Progress() (progress SyncProgress) {
+	elapsed := s.SynchronizingTime()
+	if elapsed == 0 {
+		return
+	}
+
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+
+	progress.Blocks = s.syncedBlocks
+	progress.BlocksPerSecond = float64(s.syncedBlocks) / elapsed.Seconds()
+	for address, blocks := range s.syncedSources {
+		progress.Sources = append(progress.Sources, SyncSource{Address: address, Blocks: blocks})
+	}
+	sort.Slice(progress.Sources, func(i, j int) bool {
+		if progress.Sources[i].Blocks != progress.Sources[j].Blocks {
+			return progress.Sources[i].Blocks > progress.Sources[j].Blocks
+		}
+		return progress.Sources[i].Address < progress.Sources[j].Address
+	})
+	return
+}
+
+func (s *Service) resetProgress() {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	s.syncedBlocks = 0
+	s.syncedSources = make(map[string]uint64)
+}
+
+// blockSynced records a block written to the ledger, which the source at address served
+func (s *Service) blockSynced(address string) {
+	catchupBlocksTotal.Inc(nil)
+
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	s.syncedBlocks++
+	s.syncedSources[address]++
+}
+
+// badBlock makes the fetcher avoid a source that served a block which failed verification
+func (s *Service) badBlock(fetcher rpcs.Fetcher, client rpcs.FetcherClient) {
+	catchupBadBlocksTotal.Inc(nil)
+	fetcher.ReportBadBlock(client)
+	client.Close()
+}
+
 // function scope to make a bunch of defer statements better
 func (s *Service) innerFetch(fetcher rpcs.Fetcher, r basics.Round) (blk *bookkeeping.Block, cert *agreement.Certificate, rpcc rpcs.FetcherClient, err error) {
 	ctx, cf := context.WithTimeout(s.ctx, rpcs.DefaultFetchTimeout)
@@ -175,7 +251,7 @@ func (s *Service) fetchAndWrite(fetcher rpcs.Fetcher, r basics.Round, prevFetchC
 		// Check that the block's contents match the block header (necessary with an untrusted block because b.Hash() only hashes the header)
 		if !block.ContentsMatchHeader() {
 			s.log.Warnf("fetchAndWrite(%v): block contents do not match header (attempt %d)", r, i)
-			client.Close()
+			s.badBlock(fetcher, client)
 			continue // retry the fetch
 		}
 
@@ -196,7 +272,7 @@ func (s *Service) fetchAndWrite(fetcher rpcs.Fetcher, r basics.Round, prevFetchC
 		err = cert.Authenticate(*block, s.ledger, s.certVerifier)
 		if err != nil {
 			s.log.Warnf("fetchAndWrite(%v): cert did not authenticate block (attempt %d): %v", r, i, err)
-			client.Close()
+			s.badBlock(fetcher, client)
 			continue // retry the fetch
 		}
 
@@ -225,6 +301,7 @@ func (s *Service) fetchAndWrite(fetcher rpcs.Fetcher, r basics.Round, prevFetchC
 					return false
 				}
 				s.log.Debugf("fetchAndWrite(%v): Wrote block to ledger", r)
+				s.blockSynced(client.Address())
 				return true
 			}
 			s.log.Warnf("fetchAndWrite(%v): previous block doesn't exist (perhaps fetching block %v failed)", r, r-1)
@@ -391,6 +468,7 @@ func (s *Service) sync() {
 		return
 	}
 	defer atomic.StoreInt64(&s.syncStartNS, 0)
+	s.resetProgress()
 
 	pr := s.ledger.LastRound()
 
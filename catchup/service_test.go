@@ -111,6 +111,7 @@ type MockedFetcher struct {
 	client      MockClient
 	latency     time.Duration
 	predictable bool
+	badBlocks   uint32
 	mu          deadlock.Mutex
 }
 
@@ -155,6 +156,10 @@ func (m *MockedFetcher) OutOfPeers(round basics.Round) bool {
 	return m.tries[round] > m.NumPeers()
 }
 
+func (m *MockedFetcher) ReportBadBlock(client rpcs.FetcherClient) {
+	atomic.AddUint32(&m.badBlocks, 1)
+}
+
 func (m *MockedFetcher) Close() { // noop
 }
 
@@ -327,6 +332,28 @@ func TestServiceFetchBlocksMalformed(t *testing.T) {
 	s.sync()
 	require.Equal(t, lastRoundAtStart, local.LastRound())
 	require.True(t, s.fetcherFactory.(*MockedFetcherFactory).fetcher.client.closed)
+	require.NotZero(t, atomic.LoadUint32(&s.fetcherFactory.(*MockedFetcherFactory).fetcher.badBlocks))
+}
+
+func TestServiceProgress(t *testing.T) {
+	// Make Ledger
+	numberOfBlocks := 10
+	remote, local, release, _ := testingenv(t, 10, numberOfBlocks)
+	defer release()
+
+	s := MakeService(logging.Base(), defaultConfig, &mocks.MockNetwork{}, local, nil, nil)
+	s.fetcherFactory = &MockedFetcherFactory{fetcher: &MockedFetcher{ledger: remote, timeout: false, errorRound: -1, fail: 0, tries: make(map[basics.Round]int)}}
+
+	// No progress outside of a sync
+	require.Equal(t, SyncProgress{}, s.Progress())
+
+	// Hold the sync start, so that the progress is reported as during a sync
+	s.sync()
+	atomic.StoreInt64(&s.syncStartNS, time.Now().Add(-time.Second).UnixNano())
+	progress := s.Progress()
+	require.Equal(t, uint64(numberOfBlocks), progress.Blocks)
+	require.True(t, progress.BlocksPerSecond > 0)
+	require.Equal(t, []SyncSource{{Address: "mock.address.", Blocks: uint64(numberOfBlocks)}}, progress.Sources)
 }
 
 const defaultRewardUnit = 1e6
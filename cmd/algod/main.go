@@ -51,6 +51,29 @@ var listenIP = flag.String("l", "", "Override config.EndpointAddress (REST liste
 var sessionGUID = flag.String("s", "", "Telemetry Session GUID to use")
 var telemetryOverride = flag.String("t", "", `Override telemetry setting if supported (Use "true", "false", "0" or "1"`)
 var seed = flag.String("seed", "", "input to math/rand.Seed()")
+var profileName = flag.String("profile", "", "Config profile whose settings replace the defaults, one of "+strings.Join(config.ProfileNames(), ", ")+"; defaults to the "+config.ProfileEnvVariable+" environment variable")
+
+// configOverrideFlags collects the -set key=value flags
+type configOverrideFlags []config.ConfigOverride
+
+func (f *configOverrideFlags) String() string {
+	return fmt.Sprintf("%v", *f)
+}
+
+func (f *configOverrideFlags) Set(arg string) error {
+	override, err := config.ParseOverride(arg)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, override)
+	return nil
+}
+
+var configOverrides configOverrideFlags
+
+func init() {
+	flag.Var(&configOverrides, "set", "Override a config.json setting with key=value; can be repeated, and takes precedence over "+config.EnvOverridePrefix+"<KEY> environment variables")
+}
 
 func main() {
 	flag.Parse()
@@ -147,8 +170,9 @@ func main() {
 	// Enable telemetry hook in daemon to send logs to cloud
 	// If ALGOTEST env variable is set, telemetry is disabled - allows disabling telemetry for tests
 	isTest := os.Getenv("ALGOTEST") != ""
+	var telemetryConfig logging.TelemetryConfig
 	if !isTest {
-		telemetryConfig, err := logging.EnsureTelemetryConfig(nil, genesis.ID())
+		telemetryConfig, err = logging.EnsureTelemetryConfig(nil, genesis.ID())
 		if err != nil {
 			fmt.Fprintln(os.Stdout, "error loading telemetry config", err)
 		}
@@ -194,15 +218,41 @@ func main() {
 	}
 
 	s := algod.Server{
-		RootPath: absolutePath,
-		Genesis:  genesis,
+		RootPath:      absolutePath,
+		Genesis:       genesis,
+		LoggingConfig: telemetryConfig,
 	}
 
-	cfg, err := config.LoadConfigFromDisk(s.RootPath)
+	if *profileName == "" {
+		*profileName = os.Getenv(config.ProfileEnvVariable)
+	}
+	if *profileName != "" {
+		profile, err := config.GetProfile(*profileName)
+		if err != nil {
+			log.Fatalf("Cannot load config: %v", err)
+		}
+		s.Profile = &profile
+	}
+
+	cfg, validation, err := config.LoadProfiledConfigFromDisk(s.RootPath, s.Profile)
 	if err != nil && !os.IsNotExist(err) {
 		// log is not setup yet, this will log to stderr
 		log.Fatalf("Cannot load config: %v", err)
 	}
+	for _, warning := range validation.Warnings {
+		log.Warnf("Config file %s: %v", config.ConfigFilename, warning)
+	}
+
+	overrides, unknownEnv := config.EnvironmentOverrides(os.Environ())
+	for _, name := range unknownEnv {
+		log.Warnf("Ignoring environment variable %s, it doesn't name a config setting", name)
+	}
+	overrides = append(overrides, configOverrides...)
+	if err = config.ApplyOverrides(&cfg, overrides); err != nil {
+		log.Fatalf("Cannot override config: %v", err)
+	}
+	s.ConfigOverrides = overrides
+	s.DiskConfig = cfg
 
 	// Generate a REST API token if one was not provided
 	apiToken, wroteNewToken, err := tokens.ValidateOrGenerateAPIToken(s.RootPath, tokens.AlgodTokenFilename)
@@ -269,6 +319,7 @@ func main() {
 	}
 	fmt.Fprintf(os.Stdout, "Deadlock detection is set to: %s (Default state is '%s')\n", deadlockState, config.DefaultDeadlock)
 
+	defer logging.CaptureCrash()
 	s.Start()
 }
 
@@ -0,0 +1,222 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/daemon/kmd/config"
+	"github.com/algorand/go-algorand/daemon/kmd/wallet/driver"
+	"github.com/algorand/go-algorand/data/account"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+func TestLedgerDatabases(t *testing.T) {
+	dir, err := ioutil.TempDir("", "algodump")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	genesisBlock := bookkeeping.Block{}
+	genesisBlock.CurrentProtocol = protocol.ConsensusCurrentVersion
+	genesisBlock.BlockHeader.GenesisID = "algodump"
+	crypto.RandBytes(genesisBlock.FeeSink[:])
+	crypto.RandBytes(genesisBlock.RewardsPool[:])
+	accts := map[basics.Address]basics.AccountData{
+		genesisBlock.FeeSink:     {Status: basics.NotParticipating, MicroAlgos: basics.MicroAlgos{Raw: 1000000}},
+		genesisBlock.RewardsPool: {Status: basics.NotParticipating, MicroAlgos: basics.MicroAlgos{Raw: 2000000}},
+	}
+	var online basics.Address
+	crypto.RandBytes(online[:])
+	accts[online] = basics.AccountData{Status: basics.Online, MicroAlgos: basics.MicroAlgos{Raw: 5000000}}
+
+	log := logging.NewLogger()
+	log.SetLevel(logging.Error)
+	l, err := ledger.OpenLedger(log, filepath.Join(dir, "ledger"), false, []bookkeeping.Block{genesisBlock}, accts, genesisBlock.BlockHeader.GenesisHash)
+	require.NoError(t, err)
+	l.Close()
+
+	tracker, err := openDatabase(filepath.Join(dir, "ledger.tracker.sqlite"))
+	require.NoError(t, err)
+	defer tracker.close()
+	require.Equal(t, []string{kindTracker}, tracker.kinds)
+
+	schema, err := tracker.schema()
+	require.NoError(t, err)
+	require.Len(t, schema.Tables, 3)
+	for _, table := range schema.Tables {
+		if table.Name == "accountbase" {
+			require.Equal(t, int64(3), table.Rows)
+			require.Equal(t, "address", table.Columns[0].Name)
+			require.True(t, table.Columns[0].PrimaryKey)
+		}
+	}
+
+	stats, err := tracker.trackerStats()
+	require.NoError(t, err)
+	require.Equal(t, 3, stats.Accounts)
+	require.Equal(t, uint64(8000000), stats.TotalMicroAlgos)
+	require.Equal(t, uint64(5000000), stats.Online.MicroAlgos)
+	require.Equal(t, 2, stats.AccountsByStatus[basics.NotParticipating.String()])
+	require.Equal(t, online.String(), stats.Largest[0].Address)
+
+	records, err := tracker.records(recordQuery{address: &online, limit: 10})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	record := records[0].(accountRecord)
+	require.Equal(t, uint64(5000000), record.MicroAlgos)
+	require.Contains(t, string(record.Data), `"onl": 1`)
+
+	rnd := basics.Round(0)
+	_, err = tracker.records(recordQuery{round: &rnd, limit: 10})
+	require.Error(t, err)
+
+	blocks, err := openDatabase(filepath.Join(dir, "ledger.block.sqlite"))
+	require.NoError(t, err)
+	defer blocks.close()
+	require.Equal(t, []string{kindBlocks}, blocks.kinds)
+
+	bstats, err := blocks.blockStats()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), bstats.Blocks)
+	require.Equal(t, "algodump", bstats.GenesisID)
+	require.Equal(t, int64(1), bstats.Protocols[string(protocol.ConsensusCurrentVersion)])
+
+	records, err = blocks.records(recordQuery{round: &rnd, limit: 10, full: true})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	brecord := records[0].(blockRecord)
+	require.Equal(t, crypto.Digest(genesisBlock.Hash()).String(), brecord.Hash)
+	require.NotEmpty(t, brecord.Block)
+
+	// the generic dump base64 encodes the blobs
+	records, err = blocks.records(recordQuery{table: "blocks", limit: 1})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+}
+
+func TestPartKeyDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "algodump")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var parent basics.Address
+	crypto.RandBytes(parent[:])
+	file := filepath.Join(dir, "test.0.100.partkey")
+	store, err := db.MakeAccessor(file, false, false)
+	require.NoError(t, err)
+	part, err := account.FillDBWithParticipationKeys(store, parent, 0, 100, 10)
+	require.NoError(t, err)
+	part.Close()
+
+	d, err := openDatabase(file)
+	require.NoError(t, err)
+	defer d.close()
+	require.Equal(t, []string{kindPartKey}, d.kinds)
+
+	stats, err := d.partKeyStats()
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Accounts)
+	require.Equal(t, account.PartTableSchemaVersion, stats.SchemaVersions[account.PartTableSchemaName])
+	key := stats.Keys[0]
+	require.Equal(t, parent.String(), key.Parent)
+	require.Equal(t, basics.Round(100), key.LastValid)
+	require.Equal(t, part.VRF.PK[:], key.SelectionKey)
+	require.Equal(t, part.Voting.OneTimeSignatureVerifier[:], key.VoteKey)
+	require.True(t, strings.HasPrefix(key.VotingSecrets, "<redacted"))
+
+	records, err := d.records(recordQuery{limit: 10, showSecrets: true})
+	require.NoError(t, err)
+	require.False(t, strings.HasPrefix(records[0].(partKeyRecord).VotingSecrets, "<redacted"))
+
+	records, err = d.records(recordQuery{table: "ParticipationAccount", limit: 10})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	generic, err := d.genericRecords(recordQuery{table: "ParticipationAccount", limit: 10})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(generic[0].(map[string]interface{})["vrf"].(string), "<redacted"))
+}
+
+func TestWalletDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "algodump")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.KMDConfig{DataDir: dir}
+	cfg.DriverConfig.SQLiteWalletDriverConfig.UnsafeScrypt = true
+	cfg.DriverConfig.SQLiteWalletDriverConfig.ScryptParams = config.ScryptParams{ScryptN: 2, ScryptR: 1, ScryptP: 1}
+	var swd driver.SQLiteWalletDriver
+	require.NoError(t, swd.InitWithConfig(cfg))
+	require.NoError(t, swd.CreateWallet([]byte("dump"), []byte("dumpid"), []byte("pw"), crypto.MasterDerivationKey{}))
+	w, err := swd.FetchWallet([]byte("dumpid"))
+	require.NoError(t, err)
+	require.NoError(t, w.Init([]byte("pw")))
+	derived, err := w.GenerateKey(false)
+	require.NoError(t, err)
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	secrets := crypto.GenerateSignatureSecrets(seed)
+	_, err = w.ImportKey(crypto.PrivateKey(secrets.SK))
+	require.NoError(t, err)
+	msig, err := w.ImportMultisigAddr(1, 1, []crypto.PublicKey{crypto.PublicKey(derived), secrets.SignatureVerifier})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.db"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	d, err := openDatabase(files[0])
+	require.NoError(t, err)
+	defer d.close()
+	require.Equal(t, []string{kindWallet}, d.kinds)
+
+	stats, err := d.walletStats()
+	require.NoError(t, err)
+	require.Equal(t, "dump", stats.WalletName)
+	require.Equal(t, int64(2), stats.Keys)
+	require.Equal(t, int64(1), stats.DerivedKeys)
+	require.Equal(t, int64(1), stats.MultisigAddrs)
+
+	records, err := d.records(recordQuery{limit: 10})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	for _, record := range records {
+		require.True(t, strings.HasPrefix(record.(walletKeyRecord).SecretKeyEncrypted, "<redacted"))
+	}
+
+	records, err = d.records(recordQuery{table: "msig_addrs", limit: 10})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	mrecord := records[0].(multisigRecord)
+	require.Equal(t, basics.Address(msig).String(), mrecord.Address)
+	require.Equal(t, []string{basics.Address(derived).String(), basics.Address(secrets.SignatureVerifier).String()}, mrecord.PKs)
+
+	records, err = d.records(recordQuery{table: "metadata", limit: 10})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(records[0].(map[string]interface{})["mdk_encrypted"].(string), "<redacted"))
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+var (
+	recordsTable       string
+	recordsLimit       int
+	recordsOffset      int
+	recordsAddress     string
+	recordsRound       int64
+	recordsFull        bool
+	recordsShowSecrets bool
+)
+
+func init() {
+	recordsCmd.Flags().StringVarP(&recordsTable, "table", "t", "", "Table to dump (defaults to the main table of the database: accountbase, blocks, ParticipationAccount or keys)")
+	recordsCmd.Flags().IntVarP(&recordsLimit, "limit", "n", 100, "Maximum number of records to dump")
+	recordsCmd.Flags().IntVar(&recordsOffset, "offset", 0, "Number of records to skip")
+	recordsCmd.Flags().StringVarP(&recordsAddress, "address", "a", "", "Only dump the records of this address (accountbase, ParticipationAccount, keys and msig_addrs)")
+	recordsCmd.Flags().Int64VarP(&recordsRound, "round", "r", -1, "Only dump the block of this round (blocks)")
+	recordsCmd.Flags().BoolVar(&recordsFull, "full", false, "Include the transactions and certificates of the dumped blocks")
+	recordsCmd.Flags().BoolVar(&recordsShowSecrets, "show-secrets", false, "Include the participation secrets and encrypted wallet keys rather than redacting them")
+}
+
+var schemaCmd = &cobra.Command{
+	Use:     "schema [database file]",
+	Short:   "Dump the tables, columns, indexes and row counts of a database",
+	Example: "algodump schema ~/node/data/testnet-v1.0/ledger.tracker.sqlite",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		d := mustOpenDatabase(args[0])
+		defer d.close()
+		schema, err := d.schema()
+		if err != nil {
+			reportErrorf("Error reading the schema of %s: %v", args[0], err)
+		}
+		printJSON(schema)
+	},
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [database file]",
+	Short: "Dump the key statistics of a database",
+	Long: "Dump the key statistics of a database, according to its kind: the round, totals and account counts of a ledger tracker database, " +
+		"the round range and protocols of a block database, the validity and remaining keys of a participation key database, " +
+		"or the key counts of a kmd wallet database.",
+	Example: "algodump stats ~/node/data/testnet-v1.0/ledger.block.sqlite",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		d := mustOpenDatabase(args[0])
+		defer d.close()
+		stats, err := d.stats()
+		if err != nil {
+			reportErrorf("Error computing the statistics of %s: %v", args[0], err)
+		}
+		printJSON(stats)
+	},
+}
+
+var recordsCmd = &cobra.Command{
+	Use:   "records [database file]",
+	Short: "Dump selected records of a database",
+	Long: "Dump selected records of a database. The records of the known tables are decoded; the others are dumped as is, with blobs base64 encoded.\n" +
+		"Participation secrets and encrypted wallet keys are redacted unless --show-secrets is given.",
+	Example: "algodump records ~/node/data/testnet-v1.0/ledger.tracker.sqlite -a 2QJLF3YT...\n" +
+		"algodump records ~/node/data/testnet-v1.0/ledger.block.sqlite -r 1000 --full",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		d := mustOpenDatabase(args[0])
+		defer d.close()
+		q := recordQuery{
+			table:       recordsTable,
+			limit:       recordsLimit,
+			offset:      recordsOffset,
+			full:        recordsFull,
+			showSecrets: recordsShowSecrets,
+		}
+		if recordsAddress != "" {
+			addr, err := basics.UnmarshalChecksumAddress(recordsAddress)
+			if err != nil {
+				reportErrorf("Invalid address '%s': %v", recordsAddress, err)
+			}
+			q.address = &addr
+		}
+		if recordsRound >= 0 {
+			rnd := basics.Round(recordsRound)
+			q.round = &rnd
+		}
+		records, err := d.records(q)
+		if err != nil {
+			reportErrorf("Error reading the records of %s: %v", args[0], err)
+		}
+		printJSON(records)
+	},
+}
+
+func mustOpenDatabase(file string) *database {
+	d, err := openDatabase(file)
+	if err != nil {
+		reportErrorf("Error opening %s: %v", file, err)
+	}
+	return d
+}
@@ -0,0 +1,267 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	// the sqlite3 driver is registered by its package initialization
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// The kinds of databases that algodump knows how to decode.
+const (
+	kindTracker = "tracker"
+	kindBlocks  = "blocks"
+	kindPartKey = "partkey"
+	kindWallet  = "wallet"
+)
+
+// kindTables lists the tables identifying each kind of database, along with the table dumped by default.
+var kindTables = []struct {
+	kind         string
+	tables       []string
+	defaultTable string
+}{
+	{kindTracker, []string{"acctrounds", "accounttotals", "accountbase"}, "accountbase"},
+	{kindBlocks, []string{"blocks"}, "blocks"},
+	{kindPartKey, []string{"ParticipationAccount"}, "ParticipationAccount"},
+	{kindWallet, []string{"metadata", "keys", "msig_addrs"}, "keys"},
+}
+
+// secretColumns lists the columns, by table, holding secrets or encrypted secrets that are redacted by default.
+var secretColumns = map[string]map[string]bool{
+	"ParticipationAccount": {"vrf": true, "voting": true},
+	"metadata":             {"mep_encrypted": true, "mdk_encrypted": true, "max_key_idx_encrypted": true},
+	"keys":                 {"secret_key_encrypted": true},
+}
+
+// database is a node sqlite database, opened read-only.
+type database struct {
+	file   string
+	handle *sql.DB
+	tables map[string]bool
+	kinds  []string
+}
+
+// recordQuery selects the records dumped by the records command.
+type recordQuery struct {
+	table       string
+	limit       int
+	offset      int
+	address     *basics.Address
+	round       *basics.Round
+	full        bool
+	showSecrets bool
+}
+
+// openDatabase opens the given sqlite file read-only and identifies its kind. Unlike util/db, it never changes the
+// journal mode nor migrates the schema, so that it can't modify the databases it inspects.
+func openDatabase(file string) (*database, error) {
+	if _, err := os.Stat(file); err != nil {
+		return nil, err
+	}
+	query := "mode=ro&_busy_timeout=5000"
+	if _, err := os.Stat(file + "-wal"); os.IsNotExist(err) {
+		// sqlite can't open a WAL database read-only once its last writer removed the -wal and -shm files.
+		// No process has the database open in that case, so it is safe to open it as immutable.
+		query += "&immutable=1"
+	}
+	uri := (&url.URL{Scheme: "file", Opaque: file, RawQuery: query}).String()
+	handle, err := sql.Open("sqlite3", uri)
+	if err != nil {
+		return nil, err
+	}
+	d := &database{file: file, handle: handle, tables: make(map[string]bool)}
+	names, err := d.tableNames()
+	if err != nil {
+		handle.Close()
+		return nil, err
+	}
+	for _, name := range names {
+		d.tables[name] = true
+	}
+	for _, kt := range kindTables {
+		found := true
+		for _, table := range kt.tables {
+			found = found && d.tables[table]
+		}
+		if found {
+			d.kinds = append(d.kinds, kt.kind)
+		}
+	}
+	return d, nil
+}
+
+func (d *database) close() {
+	d.handle.Close()
+}
+
+func (d *database) hasKind(kind string) bool {
+	for _, k := range d.kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *database) tableNames() (names []string, err error) {
+	rows, err := d.handle.Query("SELECT name FROM sqlite_master WHERE type='table' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// quoteIdentifier quotes a table name read from sqlite_master for use in a statement.
+func quoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// redact returns the representation of a secret blob: its base64 encoding if secrets are shown, or its length otherwise.
+func redact(data []byte, showSecrets bool) string {
+	if showSecrets {
+		return base64.StdEncoding.EncodeToString(data)
+	}
+	return fmt.Sprintf("<redacted, %d bytes>", len(data))
+}
+
+// records dumps the records selected by the query, decoding the tables whose format is known.
+func (d *database) records(q recordQuery) ([]interface{}, error) {
+	if q.table == "" {
+		for _, kt := range kindTables {
+			if d.hasKind(kt.kind) {
+				q.table = kt.defaultTable
+				break
+			}
+		}
+		if q.table == "" {
+			return nil, fmt.Errorf("unknown database kind, a --table has to be given")
+		}
+	}
+	if !d.tables[q.table] {
+		return nil, fmt.Errorf("no such table '%s'", q.table)
+	}
+
+	switch {
+	case q.table == "accountbase" && d.hasKind(kindTracker):
+		return d.accountRecords(q)
+	case q.table == "blocks" && d.hasKind(kindBlocks):
+		return d.blockRecords(q)
+	case q.table == "ParticipationAccount" && d.hasKind(kindPartKey):
+		return d.partKeyRecords(q)
+	case q.table == "keys" && d.hasKind(kindWallet):
+		return d.walletKeyRecords(q)
+	case q.table == "msig_addrs" && d.hasKind(kindWallet):
+		return d.multisigRecords(q)
+	}
+	if q.address != nil || q.round != nil {
+		return nil, fmt.Errorf("table '%s' can't be filtered by address or round", q.table)
+	}
+	return d.genericRecords(q)
+}
+
+// addressFilter returns the where clause and arguments selecting the records of the queried address, if any.
+func addressFilter(q recordQuery, table string, column string) (string, []interface{}, error) {
+	if q.round != nil {
+		return "", nil, fmt.Errorf("table %s can't be filtered by round", table)
+	}
+	if q.address == nil {
+		return "", []interface{}{}, nil
+	}
+	return fmt.Sprintf(" WHERE %s=?", column), []interface{}{q.address[:]}, nil
+}
+
+// genericRecords dumps the rows of a table as column name to value maps, base64 encoding the blobs.
+func (d *database) genericRecords(q recordQuery) ([]interface{}, error) {
+	rows, err := d.handle.Query(fmt.Sprintf("SELECT * FROM %s LIMIT ? OFFSET ?", quoteIdentifier(q.table)), q.limit, q.offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		record := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			value := values[i]
+			if data, ok := value.([]byte); ok {
+				if secretColumns[q.table][column] {
+					value = redact(data, q.showSecrets)
+				} else {
+					value = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			record[column] = value
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// stats computes the statistics of every kind the database was identified as.
+func (d *database) stats() (map[string]interface{}, error) {
+	stats := map[string]interface{}{
+		"file":  d.file,
+		"kinds": d.kinds,
+	}
+	var err error
+	for _, kind := range d.kinds {
+		switch kind {
+		case kindTracker:
+			stats[kind], err = d.trackerStats()
+		case kindBlocks:
+			stats[kind], err = d.blockStats()
+		case kindPartKey:
+			stats[kind], err = d.partKeyStats()
+		case kindWallet:
+			stats[kind], err = d.walletStats()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", kind, err)
+		}
+	}
+	return stats, nil
+}
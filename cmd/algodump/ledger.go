@@ -0,0 +1,245 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// algoCount mirrors ledger.AlgoCount, which the ledger keeps in the accounttotals table.
+type algoCount struct {
+	MicroAlgos  uint64 `json:"microalgos"`
+	RewardUnits uint64 `json:"rewardunits"`
+}
+
+type trackerStats struct {
+	Round            basics.Round    `json:"round"`
+	Online           algoCount       `json:"online"`
+	Offline          algoCount       `json:"offline"`
+	NotParticipating algoCount       `json:"notparticipating"`
+	RewardsLevel     uint64          `json:"rewardslevel"`
+	Accounts         int             `json:"accounts"`
+	AccountsByStatus map[string]int  `json:"accountsbystatus"`
+	TotalMicroAlgos  uint64          `json:"totalmicroalgos"`
+	Largest          []accountRecord `json:"largest"`
+	AccountDataBytes int64           `json:"accountdatabytes"`
+}
+
+type accountRecord struct {
+	Address    string          `json:"address"`
+	MicroAlgos uint64          `json:"microalgos"`
+	Data       json.RawMessage `json:"data,omitempty"`
+}
+
+// largestAccounts is the number of largest accounts reported by the tracker statistics.
+const largestAccounts = 10
+
+func (d *database) trackerStats() (stats trackerStats, err error) {
+	err = d.handle.QueryRow("SELECT rnd FROM acctrounds WHERE id='acctbase'").Scan(&stats.Round)
+	if err != nil {
+		return
+	}
+	err = d.handle.QueryRow("SELECT online, onlinerewardunits, offline, offlinerewardunits, notparticipating, notparticipatingrewardunits, rewardslevel FROM accounttotals").Scan(
+		&stats.Online.MicroAlgos, &stats.Online.RewardUnits,
+		&stats.Offline.MicroAlgos, &stats.Offline.RewardUnits,
+		&stats.NotParticipating.MicroAlgos, &stats.NotParticipating.RewardUnits,
+		&stats.RewardsLevel)
+	if err != nil {
+		return
+	}
+
+	rows, err := d.handle.Query("SELECT address, data FROM accountbase")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	stats.AccountsByStatus = make(map[string]int)
+	for rows.Next() {
+		var addrbuf, databuf []byte
+		if err = rows.Scan(&addrbuf, &databuf); err != nil {
+			return
+		}
+		var data basics.AccountData
+		if err = protocol.Decode(databuf, &data); err != nil {
+			return
+		}
+		stats.Accounts++
+		stats.AccountsByStatus[data.Status.String()]++
+		stats.TotalMicroAlgos += data.MicroAlgos.Raw
+		stats.AccountDataBytes += int64(len(databuf))
+
+		// keep the largest accounts sorted by decreasing balance
+		var addr basics.Address
+		copy(addr[:], addrbuf)
+		i := len(stats.Largest)
+		for i > 0 && stats.Largest[i-1].MicroAlgos < data.MicroAlgos.Raw {
+			i--
+		}
+		if i < largestAccounts {
+			stats.Largest = append(stats.Largest, accountRecord{})
+			copy(stats.Largest[i+1:], stats.Largest[i:])
+			stats.Largest[i] = accountRecord{Address: addr.String(), MicroAlgos: data.MicroAlgos.Raw}
+			if len(stats.Largest) > largestAccounts {
+				stats.Largest = stats.Largest[:largestAccounts]
+			}
+		}
+	}
+	return stats, rows.Err()
+}
+
+func (d *database) accountRecords(q recordQuery) ([]interface{}, error) {
+	where, args, err := addressFilter(q, "accountbase", "address")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := d.handle.Query("SELECT address, data FROM accountbase"+where+" ORDER BY address LIMIT ? OFFSET ?", append(args, q.limit, q.offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []interface{}{}
+	for rows.Next() {
+		var addrbuf, databuf []byte
+		if err = rows.Scan(&addrbuf, &databuf); err != nil {
+			return nil, err
+		}
+		var data basics.AccountData
+		if err = protocol.Decode(databuf, &data); err != nil {
+			return nil, err
+		}
+		var addr basics.Address
+		copy(addr[:], addrbuf)
+		records = append(records, accountRecord{Address: addr.String(), MicroAlgos: data.MicroAlgos.Raw, Data: protocol.EncodeJSON(data)})
+	}
+	return records, rows.Err()
+}
+
+type blockStats struct {
+	Blocks          int64            `json:"blocks"`
+	FirstRound      basics.Round     `json:"firstround"`
+	LatestRound     basics.Round     `json:"latestround"`
+	Protocols       map[string]int64 `json:"protocols"`
+	GenesisID       string           `json:"genesisid"`
+	LatestTimestamp int64            `json:"latesttimestamp"`
+	BlockBytes      int64            `json:"blockbytes"`
+	CertBytes       int64            `json:"certbytes"`
+}
+
+func (d *database) blockStats() (stats blockStats, err error) {
+	err = d.handle.QueryRow("SELECT COUNT(*), IFNULL(MIN(rnd), 0), IFNULL(MAX(rnd), 0), IFNULL(SUM(LENGTH(blkdata)), 0), IFNULL(SUM(LENGTH(certdata)), 0) FROM blocks").Scan(
+		&stats.Blocks, &stats.FirstRound, &stats.LatestRound, &stats.BlockBytes, &stats.CertBytes)
+	if err != nil || stats.Blocks == 0 {
+		return
+	}
+
+	rows, err := d.handle.Query("SELECT proto, COUNT(*) FROM blocks GROUP BY proto")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	stats.Protocols = make(map[string]int64)
+	for rows.Next() {
+		var proto string
+		var count int64
+		if err = rows.Scan(&proto, &count); err != nil {
+			return
+		}
+		stats.Protocols[proto] = count
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	var hdrbuf []byte
+	if err = d.handle.QueryRow("SELECT hdrdata FROM blocks WHERE rnd=?", stats.LatestRound).Scan(&hdrbuf); err != nil {
+		return
+	}
+	var hdr bookkeeping.BlockHeader
+	if err = protocol.Decode(hdrbuf, &hdr); err != nil {
+		return
+	}
+	stats.GenesisID = hdr.GenesisID
+	stats.LatestTimestamp = hdr.TimeStamp
+	return stats, nil
+}
+
+type blockRecord struct {
+	Round       basics.Round    `json:"round"`
+	Proto       string          `json:"proto"`
+	Hash        string          `json:"hash"`
+	Txns        int             `json:"txns"`
+	BlockBytes  int             `json:"blockbytes"`
+	CertBytes   int             `json:"certbytes"`
+	Header      json.RawMessage `json:"header"`
+	Block       json.RawMessage `json:"block,omitempty"`
+	Certificate json.RawMessage `json:"certificate,omitempty"`
+}
+
+func (d *database) blockRecords(q recordQuery) ([]interface{}, error) {
+	if q.address != nil {
+		return nil, fmt.Errorf("table blocks can't be filtered by address")
+	}
+	query := "SELECT rnd, proto, blkdata, certdata FROM blocks"
+	args := []interface{}{}
+	if q.round != nil {
+		query += " WHERE rnd=?"
+		args = append(args, *q.round)
+	}
+	rows, err := d.handle.Query(query+" ORDER BY rnd LIMIT ? OFFSET ?", append(args, q.limit, q.offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []interface{}{}
+	for rows.Next() {
+		var record blockRecord
+		var blkbuf, certbuf []byte
+		if err = rows.Scan(&record.Round, &record.Proto, &blkbuf, &certbuf); err != nil {
+			return nil, err
+		}
+		var blk bookkeeping.Block
+		if err = protocol.Decode(blkbuf, &blk); err != nil {
+			return nil, fmt.Errorf("round %d: %v", record.Round, err)
+		}
+		record.Hash = crypto.Digest(blk.Hash()).String()
+		record.Txns = len(blk.Payset)
+		record.BlockBytes = len(blkbuf)
+		record.CertBytes = len(certbuf)
+		record.Header = protocol.EncodeJSON(blk.BlockHeader)
+		if q.full {
+			record.Block = protocol.EncodeJSON(blk)
+			if len(certbuf) > 0 {
+				var cert agreement.Certificate
+				if err = protocol.Decode(certbuf, &cert); err != nil {
+					return nil, fmt.Errorf("round %d: %v", record.Round, err)
+				}
+				record.Certificate = protocol.EncodeJSON(cert)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
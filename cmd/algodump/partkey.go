@@ -0,0 +1,119 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+type partKeyRecord struct {
+	Parent       string       `json:"parent"`
+	FirstValid   basics.Round `json:"firstvalid"`
+	LastValid    basics.Round `json:"lastvalid"`
+	KeyDilution  uint64       `json:"keydilution"`
+	SelectionKey []byte       `json:"selectionkey"`
+	VoteKey      []byte       `json:"votekey"`
+	// FirstBatch is the first batch of voting keys still in the database; the older ones were deleted as they expired.
+	FirstBatch    uint64 `json:"firstbatch"`
+	Batches       int    `json:"batches"`
+	VRFSecrets    string `json:"vrfsecrets"`
+	VotingSecrets string `json:"votingsecrets"`
+}
+
+type partKeyStats struct {
+	SchemaVersions map[string]int  `json:"schemaversions"`
+	Accounts       int             `json:"accounts"`
+	Keys           []partKeyRecord `json:"keys"`
+}
+
+func (d *database) partKeyRecords(q recordQuery) ([]interface{}, error) {
+	where, args, err := addressFilter(q, "ParticipationAccount", "parent")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := d.handle.Query("SELECT parent, vrf, voting, firstValid, lastValid, keyDilution FROM ParticipationAccount"+where+" LIMIT ? OFFSET ?", append(args, q.limit, q.offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []interface{}{}
+	for rows.Next() {
+		var record partKeyRecord
+		var parentbuf, vrfbuf, votingbuf []byte
+		if err = rows.Scan(&parentbuf, &vrfbuf, &votingbuf, &record.FirstValid, &record.LastValid, &record.KeyDilution); err != nil {
+			return nil, err
+		}
+		var parent basics.Address
+		copy(parent[:], parentbuf)
+		record.Parent = parent.String()
+
+		// the public keys are decoded from the secrets, and only they are reported unless the secrets are requested.
+		var vrf crypto.VRFSecrets
+		if err = protocol.Decode(vrfbuf, &vrf); err != nil {
+			return nil, fmt.Errorf("%s: vrf: %v", record.Parent, err)
+		}
+		var voting crypto.OneTimeSignatureSecrets
+		if err = protocol.Decode(votingbuf, &voting); err != nil {
+			return nil, fmt.Errorf("%s: voting: %v", record.Parent, err)
+		}
+		record.SelectionKey = vrf.PK[:]
+		record.VoteKey = voting.OneTimeSignatureVerifier[:]
+		record.FirstBatch = voting.FirstBatch
+		record.Batches = len(voting.Batches)
+		record.VRFSecrets = redact(vrfbuf, q.showSecrets)
+		record.VotingSecrets = redact(votingbuf, q.showSecrets)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (d *database) partKeyStats() (stats partKeyStats, err error) {
+	stats.SchemaVersions = make(map[string]int)
+	if d.tables["schema"] {
+		rows, err := d.handle.Query("SELECT tablename, version FROM schema")
+		if err != nil {
+			return stats, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var table string
+			var version int
+			if err = rows.Scan(&table, &version); err != nil {
+				return stats, err
+			}
+			stats.SchemaVersions[table] = version
+		}
+		if err = rows.Err(); err != nil {
+			return stats, err
+		}
+	}
+
+	records, err := d.partKeyRecords(recordQuery{limit: -1})
+	if err != nil {
+		return
+	}
+	stats.Accounts = len(records)
+	for _, record := range records {
+		stats.Keys = append(stats.Keys, record.(partKeyRecord))
+	}
+	return stats, nil
+}
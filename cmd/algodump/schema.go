@@ -0,0 +1,138 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// schemaReport describes the layout of a database.
+type schemaReport struct {
+	File        string        `json:"file"`
+	Kinds       []string      `json:"kinds"`
+	Size        int64         `json:"size"`
+	PageSize    int64         `json:"pagesize"`
+	PageCount   int64         `json:"pagecount"`
+	JournalMode string        `json:"journalmode"`
+	UserVersion int64         `json:"userversion"`
+	Tables      []tableSchema `json:"tables"`
+}
+
+type tableSchema struct {
+	Name    string         `json:"name"`
+	Rows    int64          `json:"rows"`
+	SQL     string         `json:"sql"`
+	Columns []columnSchema `json:"columns"`
+	Indexes []string       `json:"indexes,omitempty"`
+}
+
+type columnSchema struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	NotNull    bool   `json:"notnull,omitempty"`
+	PrimaryKey bool   `json:"primarykey,omitempty"`
+}
+
+func (d *database) schema() (report schemaReport, err error) {
+	report.File = d.file
+	report.Kinds = d.kinds
+	if info, err := os.Stat(d.file); err == nil {
+		report.Size = info.Size()
+	}
+	for pragma, dest := range map[string]interface{}{
+		"page_size":    &report.PageSize,
+		"page_count":   &report.PageCount,
+		"journal_mode": &report.JournalMode,
+		"user_version": &report.UserVersion,
+	} {
+		if err = d.handle.QueryRow("PRAGMA " + pragma).Scan(dest); err != nil {
+			return
+		}
+	}
+
+	rows, err := d.handle.Query("SELECT name, sql FROM sqlite_master WHERE type='table' ORDER BY name")
+	if err != nil {
+		return
+	}
+	for rows.Next() {
+		var table tableSchema
+		var tableSQL sql.NullString
+		if err = rows.Scan(&table.Name, &tableSQL); err != nil {
+			rows.Close()
+			return
+		}
+		table.SQL = tableSQL.String
+		report.Tables = append(report.Tables, table)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for i := range report.Tables {
+		table := &report.Tables[i]
+		quoted := quoteIdentifier(table.Name)
+		if err = d.handle.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", quoted)).Scan(&table.Rows); err != nil {
+			return
+		}
+		if table.Columns, err = d.columns(quoted); err != nil {
+			return
+		}
+		if table.Indexes, err = d.indexes(table.Name); err != nil {
+			return
+		}
+	}
+	return report, nil
+}
+
+func (d *database) columns(quotedTable string) (columns []columnSchema, err error) {
+	rows, err := d.handle.Query(fmt.Sprintf("PRAGMA table_info(%s)", quotedTable))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cid, notNull, pk int
+		var column columnSchema
+		var defaultValue interface{}
+		if err = rows.Scan(&cid, &column.Name, &column.Type, &notNull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+		column.NotNull = notNull != 0
+		column.PrimaryKey = pk != 0
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+func (d *database) indexes(table string) (indexes []string, err error) {
+	rows, err := d.handle.Query("SELECT name FROM sqlite_master WHERE type='index' AND tbl_name=? ORDER BY name", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, name)
+	}
+	return indexes, rows.Err()
+}
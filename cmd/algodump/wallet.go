@@ -0,0 +1,129 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+type walletStats struct {
+	DriverName    string `json:"drivername"`
+	DriverVersion int    `json:"driverversion"`
+	WalletID      string `json:"walletid"`
+	WalletName    string `json:"walletname"`
+	Keys          int64  `json:"keys"`
+	DerivedKeys   int64  `json:"derivedkeys"`
+	ImportedKeys  int64  `json:"importedkeys"`
+	MultisigAddrs int64  `json:"multisigaddrs"`
+}
+
+type walletKeyRecord struct {
+	Address            string `json:"address"`
+	Derived            bool   `json:"derived"`
+	Index              int64  `json:"index,omitempty"`
+	SecretKeyEncrypted string `json:"secretkeyencrypted"`
+}
+
+type multisigRecord struct {
+	Address   string   `json:"address"`
+	Version   int      `json:"version"`
+	Threshold int      `json:"threshold"`
+	PKs       []string `json:"pks"`
+}
+
+func (d *database) walletStats() (stats walletStats, err error) {
+	err = d.handle.QueryRow("SELECT driver_name, driver_version, wallet_id, wallet_name FROM metadata").Scan(
+		&stats.DriverName, &stats.DriverVersion, &stats.WalletID, &stats.WalletName)
+	if err != nil {
+		return
+	}
+	err = d.handle.QueryRow("SELECT COUNT(*), COUNT(key_idx) FROM keys").Scan(&stats.Keys, &stats.DerivedKeys)
+	if err != nil {
+		return
+	}
+	stats.ImportedKeys = stats.Keys - stats.DerivedKeys
+	err = d.handle.QueryRow("SELECT COUNT(*) FROM msig_addrs").Scan(&stats.MultisigAddrs)
+	return
+}
+
+func (d *database) walletKeyRecords(q recordQuery) ([]interface{}, error) {
+	where, args, err := addressFilter(q, "keys", "address")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := d.handle.Query("SELECT address, key_idx, secret_key_encrypted FROM keys"+where+" ORDER BY key_idx, address LIMIT ? OFFSET ?", append(args, q.limit, q.offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []interface{}{}
+	for rows.Next() {
+		var record walletKeyRecord
+		var addrbuf, secretbuf []byte
+		var keyIndex sql.NullInt64
+		if err = rows.Scan(&addrbuf, &keyIndex, &secretbuf); err != nil {
+			return nil, err
+		}
+		var addr basics.Address
+		copy(addr[:], addrbuf)
+		record.Address = addr.String()
+		record.Derived = keyIndex.Valid
+		record.Index = keyIndex.Int64
+		record.SecretKeyEncrypted = redact(secretbuf, q.showSecrets)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (d *database) multisigRecords(q recordQuery) ([]interface{}, error) {
+	where, args, err := addressFilter(q, "msig_addrs", "address")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := d.handle.Query("SELECT address, version, threshold, pks FROM msig_addrs"+where+" ORDER BY address LIMIT ? OFFSET ?", append(args, q.limit, q.offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []interface{}{}
+	for rows.Next() {
+		var record multisigRecord
+		var addrbuf, pksbuf []byte
+		if err = rows.Scan(&addrbuf, &record.Version, &record.Threshold, &pksbuf); err != nil {
+			return nil, err
+		}
+		var addr basics.Address
+		copy(addr[:], addrbuf)
+		record.Address = addr.String()
+		var pks []crypto.PublicKey
+		if err = protocol.Decode(pksbuf, &pks); err != nil {
+			return nil, fmt.Errorf("%s: %v", record.Address, err)
+		}
+		for _, pk := range pks {
+			record.PKs = append(record.PKs, basics.Address(pk).String())
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
@@ -27,4 +27,5 @@ type Client interface {
 	Status() (models.NodeStatus, error)
 	Block(round uint64) (models.Block, error)
 	GetGoRoutines(ctx context.Context) (string, error)
+	HealthCheck() error
 }
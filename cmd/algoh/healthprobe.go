@@ -0,0 +1,81 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+)
+
+// healthProber probes algod through its REST API, and reports it as unhealthy when it repeatedly fails the probes,
+// or when its round doesn't advance.
+type healthProber struct {
+	client      Client
+	interval    time.Duration
+	maxFailures int
+	stallLimit  time.Duration
+
+	failures      int
+	lastRound     uint64
+	lastRoundTime time.Time
+}
+
+// check probes algod once, and returns the reason it's unhealthy for, if it is.
+func (p *healthProber) check(now time.Time) string {
+	var status models.NodeStatus
+	err := p.client.HealthCheck()
+	if err == nil {
+		status, err = p.client.Status()
+	}
+	if err != nil {
+		p.failures++
+		if p.maxFailures > 0 && p.failures >= p.maxFailures {
+			return fmt.Sprintf("failed %d consecutive health checks: %v", p.failures, err)
+		}
+		return ""
+	}
+	p.failures = 0
+
+	if p.lastRoundTime.IsZero() || status.LastRound != p.lastRound {
+		p.lastRound = status.LastRound
+		p.lastRoundTime = now
+		return ""
+	}
+	if p.stallLimit > 0 && now.Sub(p.lastRoundTime) >= p.stallLimit {
+		return fmt.Sprintf("round %d didn't advance for %v", p.lastRound, now.Sub(p.lastRoundTime).Round(time.Second))
+	}
+	return ""
+}
+
+// run probes algod until it's found unhealthy, returning the reason, or until the context is done.
+func (p *healthProber) run(ctx context.Context) string {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ""
+		case now := <-ticker.C:
+			if reason := p.check(now); reason != "" {
+				return reason
+			}
+		}
+	}
+}
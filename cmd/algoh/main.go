@@ -17,6 +17,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -110,36 +111,82 @@ func main() {
 		reportErrorf("Error getting ExeDir: %v\n", err)
 	}
 
+	// Handle signals cleanly
+	c := make(chan os.Signal)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	signal.Ignore(syscall.SIGHUP)
+	go func() {
+		sig := <-c
+		fmt.Printf("Exiting algoh on %v\n", sig)
+		os.Exit(0)
+	}()
+
+	hostName, _ := os.Hostname()
+	notifications := makeNotifier(config, hostName)
+	supervisor := makeSupervisor(config)
+	for {
+		exit := runAlgod(nc, config, log, absolutePath, notifications)
+		restart, delay, giveUp := supervisor.next(exit, time.Now())
+		details := map[string]interface{}{"exit": exit.String(), "uptime": exit.uptime.Round(time.Second).String()}
+		if giveUp {
+			message := fmt.Sprintf("algod %s, and was restarted %d times within %v; giving up", exit, len(supervisor.restarts), supervisor.window)
+			log.Errorln(message)
+			notifications.notify(algodFailedEvent, message, details)
+			notifications.close()
+			fmt.Fprintln(os.Stderr, message)
+			os.Exit(1)
+		}
+		if !restart {
+			if exit.failed() {
+				notifications.notify(algodFailedEvent, fmt.Sprintf("algod %s", exit), details)
+			}
+			break
+		}
+		message := fmt.Sprintf("algod %s; restarting it in %v", exit, delay)
+		log.Infoln(message)
+		details["delay"] = delay.String()
+		notifications.notify(algodRestartEvent, message, details)
+		time.Sleep(delay)
+	}
+	notifications.close()
+	fmt.Println("Exiting algoh normally...")
+}
+
+// runAlgod runs algod along with its watchers until it exits, or until it's found unhealthy and stopped.
+func runAlgod(nc nodecontrol.NodeController, config algoh.HostConfig, log logging.Logger, absolutePath string, notifications *notifier) (exit algodExit) {
 	var errorOutput stdCollector
 	var output stdCollector
 	done := make(chan struct{})
-	go func() {
-		args := make([]string, len(os.Args)-1)
-		copy(args, os.Args[1:]) // Copy our arguments (skip the executable)
-		if log.GetTelemetryEnabled() {
-			args = append(args, "-s", log.GetTelemetrySession())
-		}
-		algodPath := filepath.Join(exeDir, algodFileName)
-		cmd := exec.Command(algodPath, args...)
-		cmd.Stderr = &errorOutput
-		cmd.Stdout = &output
+	startTime := time.Now()
 
-		err = cmd.Start()
-		if err != nil {
-			reportErrorf("Error starting algod: %v", err)
-		}
-		cmd.Wait()
+	args := make([]string, len(os.Args)-1)
+	copy(args, os.Args[1:]) // Copy our arguments (skip the executable)
+	if log.GetTelemetryEnabled() {
+		args = append(args, "-s", log.GetTelemetrySession())
+	}
+	algodPath := filepath.Join(exeDir, algodFileName)
+	cmd := exec.Command(algodPath, args...)
+	cmd.Stderr = &errorOutput
+	cmd.Stdout = &output
+
+	err := cmd.Start()
+	if err != nil {
+		reportErrorf("Error starting algod: %v", err)
+	}
+	go func() {
+		exit.err = cmd.Wait()
+		exit.uptime = time.Since(startTime)
 		close(done)
 
 		log.Infoln("++++++++++++++++++++++++++++++++++++++++")
-		log.Infoln("algod exited.  Exiting...")
+		log.Infoln("algod exited.")
 		log.Infoln("++++++++++++++++++++++++++++++++++++++++")
 	}()
 
 	// Set up error capturing in case algod exits before we can get REST client
 	defer func() {
 		if errorOutput.output != "" {
-			fmt.Fprintf(os.Stderr, errorOutput.output)
+			fmt.Fprint(os.Stderr, errorOutput.output)
 			details := telemetryspec.ErrorOutputEventDetails{
 				Error:  errorOutput.output,
 				Output: output.output,
@@ -157,20 +204,49 @@ func main() {
 		}
 	}()
 
-	// Handle signals cleanly
-	c := make(chan os.Signal)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
-	signal.Ignore(syscall.SIGHUP)
-	go func() {
-		sig := <-c
-		fmt.Printf("Exiting algoh on %v\n", sig)
-		os.Exit(0)
-	}()
-
 	client, err := waitForClient(nc, done)
 	if err != nil {
-		reportErrorf("error creating Rest Client: %v\n", err)
+		// algod exited before it started serving its REST API
+		<-done
+		return
+	}
+
+	var unhealthy string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var probes sync.WaitGroup
+	if config.HealthCheckIntervalSec > 0 || config.RoundStallRestartSec > 0 {
+		prober := healthProber{
+			client:      client,
+			interval:    time.Duration(config.HealthCheckIntervalSec) * time.Second,
+			maxFailures: config.HealthCheckFailures,
+			stallLimit:  time.Duration(config.RoundStallRestartSec) * time.Second,
+		}
+		if prober.interval == 0 {
+			prober.interval = defaultHealthCheckInterval
+		}
+		probes.Add(1)
+		go func() {
+			defer probes.Done()
+			select {
+			case <-done:
+				return
+			default:
+			}
+			reason := prober.run(ctx)
+			if reason == "" {
+				return
+			}
+			unhealthy = reason
+			log.Warnf("algod is unhealthy, stopping it: %s", reason)
+			notifications.notify(algodUnhealthyEvent, "algod is unhealthy: "+reason, map[string]interface{}{"round": prober.lastRound})
+			stopAlgod(cmd, done)
+		}()
 	}
+	go func() {
+		<-done
+		cancel()
+	}()
 
 	var wg sync.WaitGroup
 
@@ -190,7 +266,26 @@ func main() {
 	wg.Add(1)
 
 	wg.Wait()
-	fmt.Println("Exiting algoh normally...")
+	probes.Wait()
+	<-done
+	exit.unhealthy = unhealthy
+	return
+}
+
+// defaultHealthCheckInterval is the interval of the round stall checks when the REST health probes are disabled.
+const defaultHealthCheckInterval = 10 * time.Second
+
+// algodStopTimeout is the time algod is given to exit after being asked to, before it's killed.
+const algodStopTimeout = 30 * time.Second
+
+// stopAlgod asks algod to exit, and kills it if it doesn't exit in time.
+func stopAlgod(cmd *exec.Cmd, done <-chan struct{}) {
+	cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-done:
+	case <-time.After(algodStopTimeout):
+		cmd.Process.Kill()
+	}
 }
 
 func waitForClient(nc nodecontrol.NodeController, abort chan struct{}) (client client.RestClient, err error) {
@@ -347,4 +442,12 @@ func validateConfig(config algoh.HostConfig) {
 	if config.DeadManTimeSec > 0 && config.DeadManTimeSec < 30 {
 		reportErrorf("Config.DeadManTimeSec should be >= 30 seconds (set to %v)\n", config.DeadManTimeSec)
 	}
+	switch config.RestartPolicy {
+	case algoh.RestartNever, algoh.RestartOnFailure, algoh.RestartAlways:
+	default:
+		reportErrorf("Config.RestartPolicy should be one of '%s', '%s' or '%s' (set to '%s')\n", algoh.RestartNever, algoh.RestartOnFailure, algoh.RestartAlways, config.RestartPolicy)
+	}
+	if config.NotifySMTPServer != "" && (config.NotifyEmailFrom == "" || len(config.NotifyEmailTo) == 0) {
+		reportErrorf("Config.NotifyEmailFrom and Config.NotifyEmailTo are required when Config.NotifySMTPServer is set\n")
+	}
 }
@@ -49,6 +49,7 @@ type mockClient struct {
 	StatusCalls        int
 	BlockCalls         map[uint64]int
 	GetGoRoutinesCalls int
+	HealthCheckCalls   int
 	error              []error
 	status             []models.NodeStatus
 	routine            []string
@@ -110,3 +111,8 @@ func (c *mockClient) GetGoRoutines(ctx context.Context) (r string, e error) {
 	e = c.nextError()
 	return
 }
+
+func (c *mockClient) HealthCheck() error {
+	c.HealthCheckCalls++
+	return c.nextError()
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand/shared/algoh"
+	"github.com/algorand/go-algorand/util/webhook"
+)
+
+// The events algoh notifies about.
+const (
+	algodRestartEvent   = "algod.restart"
+	algodUnhealthyEvent = "algod.unhealthy"
+	algodFailedEvent    = "algod.failed"
+)
+
+// notifyTimeout is the time algoh waits for the pending notifications to be sent before exiting.
+const notifyTimeout = 30 * time.Second
+
+// notifier sends the supervision events to the configured webhooks and email recipients.
+type notifier struct {
+	node     string
+	webhooks *webhook.Dispatcher
+
+	smtpServer string
+	smtpAuth   smtp.Auth
+	from       string
+	to         []string
+
+	// sendMail is smtp.SendMail, unless overridden by tests
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	emails   sync.WaitGroup
+}
+
+func makeNotifier(cfg algoh.HostConfig, node string) *notifier {
+	n := &notifier{
+		node:       node,
+		smtpServer: cfg.NotifySMTPServer,
+		from:       cfg.NotifyEmailFrom,
+		to:         cfg.NotifyEmailTo,
+		sendMail:   smtp.SendMail,
+	}
+	if len(cfg.NotifyWebhookURLs) > 0 {
+		n.webhooks = webhook.MakeDispatcher(webhook.Config{URLs: cfg.NotifyWebhookURLs, Secret: cfg.NotifyWebhookSecret}, log)
+		n.webhooks.Start()
+	}
+	if cfg.NotifySMTPUsername != "" {
+		host, _, err := net.SplitHostPort(cfg.NotifySMTPServer)
+		if err != nil {
+			host = cfg.NotifySMTPServer
+		}
+		n.smtpAuth = smtp.PlainAuth("", cfg.NotifySMTPUsername, cfg.NotifySMTPPassword, host)
+	}
+	return n
+}
+
+// notify sends the given event in the background.
+func (n *notifier) notify(eventType string, message string, details map[string]interface{}) {
+	event := webhook.Event{
+		Type:    eventType,
+		Time:    time.Now().UTC(),
+		Node:    n.node,
+		Message: message,
+		Details: details,
+	}
+	if n.webhooks != nil {
+		n.webhooks.Send(event)
+	}
+	if n.smtpServer != "" && len(n.to) > 0 {
+		msg := formatEmail(n.from, n.to, event)
+		n.emails.Add(1)
+		go func() {
+			defer n.emails.Done()
+			if err := n.sendMail(n.smtpServer, n.smtpAuth, n.from, n.to, msg); err != nil {
+				log.Warnf("unable to email %s event: %v", eventType, err)
+			}
+		}()
+	}
+}
+
+// close waits for the pending notifications to be sent, up to notifyTimeout.
+func (n *notifier) close() {
+	emailsSent := make(chan struct{})
+	go func() {
+		n.emails.Wait()
+		close(emailsSent)
+	}()
+	if n.webhooks != nil {
+		n.webhooks.Drain(notifyTimeout)
+	}
+	select {
+	case <-emailsSent:
+	case <-time.After(notifyTimeout):
+	}
+}
+
+func formatEmail(from string, to []string, event webhook.Event) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: [algoh] %s on %s: %s\r\n", event.Type, event.Node, event.Message)
+	fmt.Fprintf(&b, "Date: %s\r\n", event.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "\r\n%s\r\n\r\n", event.Message)
+	keys := make([]string, 0, len(event.Details))
+	for key := range event.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s: %v\r\n", key, event.Details[key])
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"time"
+
+	"github.com/algorand/go-algorand/shared/algoh"
+)
+
+// algodExit describes how a single run of algod ended.
+type algodExit struct {
+	// err is the error algod exited with, if any
+	err error
+	// unhealthy is the reason algoh stopped algod for, if it was found unhealthy
+	unhealthy string
+	uptime    time.Duration
+}
+
+func (e algodExit) failed() bool {
+	return e.err != nil || e.unhealthy != ""
+}
+
+func (e algodExit) String() string {
+	switch {
+	case e.unhealthy != "":
+		return "stopped as unhealthy: " + e.unhealthy
+	case e.err != nil:
+		return "exited with " + e.err.Error()
+	default:
+		return "exited normally"
+	}
+}
+
+// supervisor decides whether and when algod is restarted after it exits, according to the restart policy.
+type supervisor struct {
+	policy       string
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	factor       float64
+	resetAfter   time.Duration
+	maxRestarts  int
+	window       time.Duration
+
+	// delay is the delay before the next restart, and restarts the times of the restarts within the window
+	delay    time.Duration
+	restarts []time.Time
+}
+
+func makeSupervisor(cfg algoh.HostConfig) *supervisor {
+	s := &supervisor{
+		policy:       cfg.RestartPolicy,
+		initialDelay: time.Duration(cfg.RestartDelayMS) * time.Millisecond,
+		maxDelay:     time.Duration(cfg.RestartMaxDelayMS) * time.Millisecond,
+		factor:       cfg.RestartBackoffFactor,
+		resetAfter:   time.Duration(cfg.RestartResetSec) * time.Second,
+		maxRestarts:  cfg.MaxRestarts,
+		window:       time.Duration(cfg.MaxRestartsWindowSec) * time.Second,
+	}
+	if s.factor < 1 {
+		s.factor = 1
+	}
+	if s.maxDelay < s.initialDelay {
+		s.maxDelay = s.initialDelay
+	}
+	return s
+}
+
+// next returns whether algod should be restarted after the given exit, and the delay before restarting it.
+// giveUp is set when algod is restarted too often, and is considered to be persistently failing.
+func (s *supervisor) next(exit algodExit, now time.Time) (restart bool, delay time.Duration, giveUp bool) {
+	switch s.policy {
+	case algoh.RestartAlways:
+	case algoh.RestartOnFailure:
+		if !exit.failed() {
+			return false, 0, false
+		}
+	default:
+		return false, 0, false
+	}
+
+	recent := s.restarts[:0]
+	for _, t := range s.restarts {
+		if now.Sub(t) < s.window {
+			recent = append(recent, t)
+		}
+	}
+	s.restarts = recent
+	if s.maxRestarts > 0 && len(s.restarts) >= s.maxRestarts {
+		return false, 0, true
+	}
+	s.restarts = append(s.restarts, now)
+
+	if s.delay == 0 || exit.uptime >= s.resetAfter {
+		s.delay = s.initialDelay
+	} else {
+		s.delay = time.Duration(float64(s.delay) * s.factor)
+		if s.delay > s.maxDelay {
+			s.delay = s.maxDelay
+		}
+	}
+	return true, s.delay, false
+}
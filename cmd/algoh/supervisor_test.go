@@ -0,0 +1,163 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/shared/algoh"
+)
+
+func testSupervisorConfig(policy string) algoh.HostConfig {
+	cfg, _ := algoh.LoadConfigFromFile("")
+	cfg.RestartPolicy = policy
+	return cfg
+}
+
+func TestSupervisorPolicies(t *testing.T) {
+	failure := algodExit{err: errors.New("exit status 1")}
+	unhealthy := algodExit{unhealthy: "round 5 didn't advance for 1m0s"}
+	clean := algodExit{}
+	now := time.Now()
+
+	never := makeSupervisor(testSupervisorConfig(algoh.RestartNever))
+	restart, _, giveUp := never.next(failure, now)
+	require.False(t, restart)
+	require.False(t, giveUp)
+
+	onFailure := makeSupervisor(testSupervisorConfig(algoh.RestartOnFailure))
+	restart, _, _ = onFailure.next(clean, now)
+	require.False(t, restart)
+	restart, _, _ = onFailure.next(failure, now)
+	require.True(t, restart)
+	restart, _, _ = onFailure.next(unhealthy, now)
+	require.True(t, restart)
+
+	always := makeSupervisor(testSupervisorConfig(algoh.RestartAlways))
+	restart, _, _ = always.next(clean, now)
+	require.True(t, restart)
+}
+
+func TestSupervisorBackoff(t *testing.T) {
+	cfg := testSupervisorConfig(algoh.RestartOnFailure)
+	cfg.RestartDelayMS = 1000
+	cfg.RestartMaxDelayMS = 5000
+	cfg.RestartBackoffFactor = 2
+	cfg.RestartResetSec = 60
+	cfg.MaxRestarts = 0
+	s := makeSupervisor(cfg)
+
+	failure := algodExit{err: errors.New("exit status 1"), uptime: time.Second}
+	now := time.Now()
+	var delays []time.Duration
+	for i := 0; i < 5; i++ {
+		restart, delay, giveUp := s.next(failure, now)
+		require.True(t, restart)
+		require.False(t, giveUp)
+		delays = append(delays, delay)
+	}
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+
+	// a long enough run resets the backoff
+	failure.uptime = time.Minute
+	_, delay, _ := s.next(failure, now)
+	require.Equal(t, time.Second, delay)
+}
+
+func TestSupervisorGivesUp(t *testing.T) {
+	cfg := testSupervisorConfig(algoh.RestartAlways)
+	cfg.MaxRestarts = 3
+	cfg.MaxRestartsWindowSec = 60
+	s := makeSupervisor(cfg)
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		restart, _, giveUp := s.next(algodExit{}, now.Add(time.Duration(i)*time.Second))
+		require.True(t, restart)
+		require.False(t, giveUp)
+	}
+	restart, _, giveUp := s.next(algodExit{}, now.Add(10*time.Second))
+	require.False(t, restart)
+	require.True(t, giveUp)
+
+	// once the earlier restarts leave the window, algod may be restarted again
+	s = makeSupervisor(cfg)
+	for i := 0; i < 3; i++ {
+		s.next(algodExit{}, now)
+	}
+	restart, _, giveUp = s.next(algodExit{}, now.Add(time.Minute))
+	require.True(t, restart)
+	require.False(t, giveUp)
+}
+
+func TestHealthProberFailures(t *testing.T) {
+	client := makeMockClient([]error{errors.New("connection refused")}, makeNodeStatuses(1), nil, nil)
+	p := healthProber{client: &client, maxFailures: 3}
+
+	now := time.Now()
+	require.Empty(t, p.check(now))
+	require.Empty(t, p.check(now))
+	require.Contains(t, p.check(now), "failed 3 consecutive health checks")
+	require.Equal(t, 3, client.HealthCheckCalls)
+	require.Equal(t, 0, client.StatusCalls)
+}
+
+func TestHealthProberStall(t *testing.T) {
+	client := makeMockClient(nil, makeNodeStatuses(5, 6, 6), nil, nil)
+	p := healthProber{client: &client, maxFailures: 3, stallLimit: time.Minute}
+
+	now := time.Now()
+	require.Empty(t, p.check(now))
+	require.Empty(t, p.check(now.Add(30*time.Second)))
+	require.Equal(t, uint64(6), p.lastRound)
+	require.Empty(t, p.check(now.Add(80*time.Second)))
+	require.Equal(t, "round 6 didn't advance for 1m0s", p.check(now.Add(90*time.Second)))
+}
+
+func TestNotifierEmail(t *testing.T) {
+	cfg := testSupervisorConfig(algoh.RestartAlways)
+	cfg.NotifySMTPServer = "smtp.example.com:25"
+	cfg.NotifyEmailFrom = "algoh@example.com"
+	cfg.NotifyEmailTo = []string{"ops@example.com", "oncall@example.com"}
+	n := makeNotifier(cfg, "relay1")
+
+	var mu sync.Mutex
+	var sent []string
+	n.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, cfg.NotifySMTPServer, addr)
+		require.Equal(t, cfg.NotifyEmailTo, to)
+		sent = append(sent, string(msg))
+		return nil
+	}
+	n.notify(algodRestartEvent, "algod exited with exit status 1; restarting it in 1s", map[string]interface{}{"uptime": "5s", "delay": "1s"})
+	n.close()
+
+	require.Len(t, sent, 1)
+	require.True(t, strings.HasPrefix(sent[0], "From: algoh@example.com\r\nTo: ops@example.com, oncall@example.com\r\n"))
+	require.Contains(t, sent[0], fmt.Sprintf("Subject: [algoh] %s on relay1: algod exited with exit status 1", algodRestartEvent))
+	require.True(t, strings.HasSuffix(sent[0], "delay: 1s\r\nuptime: 5s\r\n"))
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/algorand/go-algorand/tools/network/cloudflare"
+	"github.com/algorand/go-algorand/util"
+)
+
+// dnsApplyRetries is the number of times a failing DNS change is retried before giving up on it.
+const dnsApplyRetries = 3
+
+// dnsApplyRetryDelay is the delay before the first retry; it doubles on every subsequent retry.
+var dnsApplyRetryDelay = 500 * time.Millisecond
+
+// dnsRecordsAPI is the subset of the cloudflare DNS API used for applying record changes.
+type dnsRecordsAPI interface {
+	ListDNSRecord(ctx context.Context, recordType string, name string, content string, order string, direction string, match string) ([]cloudflare.DNSRecordResponseEntry, error)
+	CreateDNSRecord(ctx context.Context, recordType string, name string, content string, ttl uint, priority uint, proxied bool) error
+	CreateSRVRecord(ctx context.Context, name string, target string, ttl uint, priority uint, port uint, service string, protocol string, weight uint) error
+	UpdateDNSRecord(ctx context.Context, recordID string, recordType string, name string, content string, ttl uint, priority uint, proxied bool) error
+	UpdateSRVRecord(ctx context.Context, recordID string, name string, target string, ttl uint, priority uint, port uint, service string, protocol string, weight uint) error
+	DeleteDNSRecord(ctx context.Context, recordID string) error
+}
+
+// applyDNSRecordChanges applies the given changes using up to concurrency concurrent API calls, retrying each failing
+// change up to retries times. Deletions are only started once all the creations and updates are done, so that the
+// network is never left without records. The returned errors slice has an entry for every change.
+func applyDNSRecordChanges(ctx context.Context, api dnsRecordsAPI, changes []dnsRecordChange, concurrency int, retries int) []error {
+	errs := make([]error, len(changes))
+	for _, deletes := range []bool{false, true} {
+		phase := []int{}
+		for i, change := range changes {
+			if (change.Action == dnsActionDelete) == deletes {
+				phase = append(phase, i)
+			}
+		}
+		util.RunConcurrently(len(phase), concurrency, func(i int) {
+			errs[phase[i]] = applyDNSRecordChangeWithRetries(ctx, api, changes[phase[i]], retries)
+		})
+	}
+	return errs
+}
+
+// applyDNSRecordChangeWithRetries applies a single change, retrying on failure. Before retrying a creation, it verifies
+// that the record wasn't created by the failed attempt, so that retries never create duplicate records.
+func applyDNSRecordChangeWithRetries(ctx context.Context, api dnsRecordsAPI, change dnsRecordChange, retries int) (err error) {
+	delay := dnsApplyRetryDelay
+	for attempt := 0; ; attempt++ {
+		err = applyDNSRecordChange(ctx, api, change)
+		if err == nil || attempt >= retries {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if change.Action == dnsActionCreate {
+			if exists, listErr := dnsRecordExists(ctx, api, change.Desired); listErr == nil && exists {
+				return nil
+			}
+		}
+	}
+}
+
+// dnsRecordExists checks whether a record with the same identity as the given one already exists.
+func dnsRecordExists(ctx context.Context, api dnsRecordsAPI, r dnsRecordSpec) (bool, error) {
+	content := r.Content
+	if r.Type == "SRV" {
+		// SRV records are looked up by name and filtered by their target.
+		content = ""
+	}
+	entries, err := api.ListDNSRecord(ctx, r.Type, r.Name, content, "", "", "")
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		existing := dnsRecordSpec{Type: entry.Type, Name: entry.Name, Content: entry.Content}
+		if existing.key() == r.key() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func applyDNSRecordChange(ctx context.Context, api dnsRecordsAPI, change dnsRecordChange) error {
+	r := change.Desired
+	switch change.Action {
+	case dnsActionDelete:
+		return api.DeleteDNSRecord(ctx, change.Current.id)
+	case dnsActionCreate, dnsActionUpdate:
+		if r.Type != "SRV" {
+			if change.Action == dnsActionCreate {
+				return api.CreateDNSRecord(ctx, r.Type, r.Name, r.Content, r.TTL, r.Priority, r.Proxied)
+			}
+			return api.UpdateDNSRecord(ctx, r.id, r.Type, r.Name, r.Content, r.TTL, r.Priority, r.Proxied)
+		}
+		weight, port, target, err := r.srvContent()
+		if err != nil {
+			return err
+		}
+		service, protocol, name, err := r.srvName()
+		if err != nil {
+			return err
+		}
+		if change.Action == dnsActionCreate {
+			return api.CreateSRVRecord(ctx, name, target, r.TTL, r.Priority, port, service, protocol, weight)
+		}
+		return api.UpdateSRVRecord(ctx, r.id, name, target, r.TTL, r.Priority, port, service, protocol, weight)
+	default:
+		return fmt.Errorf("unknown action '%s'", change.Action)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/tools/network/cloudflare"
+)
+
+// fakeDNSRecordsAPI keeps the records in memory, and fails the first failures calls of each record.
+type fakeDNSRecordsAPI struct {
+	mu       sync.Mutex
+	records  map[string]cloudflare.DNSRecordResponseEntry
+	failures int
+	// silentFailures makes the failing calls apply the change before returning the error.
+	silentFailures bool
+	calls          map[string]int
+	nextID         int
+}
+
+func makeFakeDNSRecordsAPI(failures int, silentFailures bool) *fakeDNSRecordsAPI {
+	return &fakeDNSRecordsAPI{
+		records:        make(map[string]cloudflare.DNSRecordResponseEntry),
+		failures:       failures,
+		silentFailures: silentFailures,
+		calls:          make(map[string]int),
+	}
+}
+
+func (f *fakeDNSRecordsAPI) call(key string, apply func()) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[key]++
+	if f.calls[key] <= f.failures {
+		if f.silentFailures {
+			apply()
+		}
+		return fmt.Errorf("failed call %d for %s", f.calls[key], key)
+	}
+	apply()
+	return nil
+}
+
+func (f *fakeDNSRecordsAPI) create(recordType, name, content string) error {
+	return f.call(recordType+name+content, func() {
+		f.nextID++
+		id := fmt.Sprintf("id%d", f.nextID)
+		f.records[id] = cloudflare.DNSRecordResponseEntry{ID: id, Type: recordType, Name: name, Content: content}
+	})
+}
+
+func (f *fakeDNSRecordsAPI) ListDNSRecord(ctx context.Context, recordType string, name string, content string, order string, direction string, match string) ([]cloudflare.DNSRecordResponseEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := []cloudflare.DNSRecordResponseEntry{}
+	for _, r := range f.records {
+		if r.Type == recordType && (name == "" || r.Name == name) && (content == "" || r.Content == content) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeDNSRecordsAPI) CreateDNSRecord(ctx context.Context, recordType string, name string, content string, ttl uint, priority uint, proxied bool) error {
+	return f.create(recordType, name, content)
+}
+
+func (f *fakeDNSRecordsAPI) CreateSRVRecord(ctx context.Context, name string, target string, ttl uint, priority uint, port uint, service string, protocol string, weight uint) error {
+	return f.create("SRV", service+"."+protocol+"."+name, fmt.Sprintf("%d %d %s", weight, port, target))
+}
+
+func (f *fakeDNSRecordsAPI) UpdateDNSRecord(ctx context.Context, recordID string, recordType string, name string, content string, ttl uint, priority uint, proxied bool) error {
+	return f.call(recordID, func() {
+		f.records[recordID] = cloudflare.DNSRecordResponseEntry{ID: recordID, Type: recordType, Name: name, Content: content}
+	})
+}
+
+func (f *fakeDNSRecordsAPI) UpdateSRVRecord(ctx context.Context, recordID string, name string, target string, ttl uint, priority uint, port uint, service string, protocol string, weight uint) error {
+	return f.UpdateDNSRecord(ctx, recordID, "SRV", service+"."+protocol+"."+name, fmt.Sprintf("%d %d %s", weight, port, target), ttl, priority, false)
+}
+
+func (f *fakeDNSRecordsAPI) DeleteDNSRecord(ctx context.Context, recordID string) error {
+	return f.call(recordID, func() {
+		delete(f.records, recordID)
+	})
+}
+
+func TestApplyDNSRecordChanges(t *testing.T) {
+	dnsApplyRetryDelay = time.Millisecond
+
+	changes := []dnsRecordChange{}
+	for i := 0; i < 20; i++ {
+		changes = append(changes, dnsRecordChange{Action: dnsActionCreate, Desired: dnsRecordSpec{Type: "A", Name: fmt.Sprintf("r%d.test.algodev.network", i), Content: "10.0.0.1"}})
+	}
+	changes = append(changes, dnsRecordChange{Action: dnsActionCreate, Desired: dnsRecordSpec{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: "1 4160 r1.test.algodev.network"}})
+
+	// every call fails once; the retries should take care of that.
+	api := makeFakeDNSRecordsAPI(1, false)
+	for _, err := range applyDNSRecordChanges(context.Background(), api, changes, 4, dnsApplyRetries) {
+		require.NoError(t, err)
+	}
+	require.Equal(t, len(changes), len(api.records))
+
+	// a failing call that did create the record should not be retried into a duplicate.
+	api = makeFakeDNSRecordsAPI(1, true)
+	for _, err := range applyDNSRecordChanges(context.Background(), api, changes, 4, dnsApplyRetries) {
+		require.NoError(t, err)
+	}
+	require.Equal(t, len(changes), len(api.records))
+
+	// too many failures are reported per change.
+	api = makeFakeDNSRecordsAPI(dnsApplyRetries+1, false)
+	errs := applyDNSRecordChanges(context.Background(), api, changes[:2], 4, dnsApplyRetries)
+	require.Equal(t, 2, len(errs))
+	for _, err := range errs {
+		require.Error(t, err)
+	}
+	require.Empty(t, api.records)
+}
@@ -40,6 +40,7 @@ var (
 	recordType     string
 	noPrompt       bool
 	excludePattern string
+	dnsDryRun      bool
 )
 
 func init() {
@@ -52,11 +53,13 @@ func init() {
 	addCmd.MarkFlagRequired("from")
 	addCmd.Flags().StringVarP(&addToAddress, "to", "t", "", "To address to map new DNS entry to")
 	addCmd.MarkFlagRequired("to")
+	addCmd.Flags().BoolVar(&dnsDryRun, "dry-run", false, "Print the DNS API calls instead of executing them")
 
 	deleteCmd.Flags().StringVarP(&deleteNetwork, "network", "n", "", "Network name for records to delete")
 	deleteCmd.MarkFlagRequired("network")
 	deleteCmd.Flags().BoolVarP(&noPrompt, "no-prompt", "y", false, "No prompting for records deletion")
 	deleteCmd.Flags().StringVarP(&excludePattern, "exclude", "e", "", "name records exclude pattern")
+	deleteCmd.Flags().BoolVar(&dnsDryRun, "dry-run", false, "Print the DNS API calls instead of executing them")
 
 	listCmd.Flags().StringVarP(&listNetwork, "network", "n", "", "Domain name for records to list")
 	listCmd.Flags().StringVarP(&recordType, "recordType", "t", "", "DNS record type to list (A, CNAME, SRV)")
@@ -120,7 +123,7 @@ var addCmd = &cobra.Command{
 	Example: "algons dns add -f a.test.algodev.network -t r1.algodev.network\n" +
 		"algons dns add -f a.test.algodev.network -t 192.168.100.10",
 	Run: func(cmd *cobra.Command, args []string) {
-		err := doAddDNS(addFromName, addToAddress)
+		err := doAddDNS(addFromName, addToAddress, dnsDryRun)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error adding DNS entry: %v\n", err)
 			os.Exit(1)
@@ -134,19 +137,20 @@ var deleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete DNS and SRV records for a specified network",
 	Run: func(cmd *cobra.Command, args []string) {
-		if !doDeleteDNS(deleteNetwork, noPrompt, excludePattern) {
+		if !doDeleteDNS(deleteNetwork, noPrompt, excludePattern, dnsDryRun) {
 			os.Exit(1)
 		}
 	},
 }
 
-func doAddDNS(from string, to string) (err error) {
+func doAddDNS(from string, to string, dryRun bool) (err error) {
 	cfZoneID, cfEmail, cfKey, err := getClouldflareCredentials()
 	if err != nil {
 		return fmt.Errorf("error getting DNS credentials: %v", err)
 	}
 
 	cloudflareDNS := cloudflare.NewDNS(cfZoneID, cfEmail, cfKey)
+	cloudflareDNS.SetDryRun(dryRun)
 
 	const priority = 1
 	const proxied = false
@@ -161,9 +165,7 @@ func doAddDNS(from string, to string) (err error) {
 	} else {
 		recordType = "CNAME"
 	}
-	cloudflareDNS.SetDNSRecord(context.Background(), recordType, from, to, cloudflare.AutomaticTTL, priority, proxied)
-
-	return
+	return cloudflareDNS.SetDNSRecord(context.Background(), recordType, from, to, cloudflare.AutomaticTTL, priority, proxied)
 }
 
 func getClouldflareCredentials() (zoneID string, email string, authKey string, err error) {
@@ -213,7 +215,7 @@ func checkSrvRecord(dnsBootstrap string) {
 	}
 }
 
-func doDeleteDNS(network string, noPrompt bool, excludePattern string) bool {
+func doDeleteDNS(network string, noPrompt bool, excludePattern string, dryRun bool) bool {
 
 	if network == "" || network == "testnet" || network == "devnet" || network == "mainnet" {
 		fmt.Fprintf(os.Stderr, "Deletion of network '%s' using this tool is not allowed\n", network)
@@ -237,6 +239,7 @@ func doDeleteDNS(network string, noPrompt bool, excludePattern string) bool {
 	}
 
 	cloudflareDNS := cloudflare.NewDNS(cfZoneID, cfEmail, cfKey)
+	cloudflareDNS.SetDryRun(dryRun)
 
 	idsToDelete := make(map[string]string) // Maps record ID to Name
 
@@ -285,17 +288,7 @@ func doDeleteDNS(network string, noPrompt bool, excludePattern string) bool {
 		return true
 	}
 
-	var text string
-	if !noPrompt {
-		reader := bufio.NewReader(os.Stdin)
-		fmt.Printf("Delete these %d entries (type 'yes' to delete)? ", len(idsToDelete))
-		text, _ = reader.ReadString('\n')
-		text = strings.Replace(text, "\n", "", -1)
-	} else {
-		text = "yes"
-	}
-
-	if text == "yes" {
+	if noPrompt || dryRun || promptYes(fmt.Sprintf("Delete these %d entries (type 'yes' to delete)? ", len(idsToDelete))) {
 		for id, name := range idsToDelete {
 			fmt.Fprintf(os.Stdout, "Deleting %s\n", name)
 			err = cloudflareDNS.DeleteDNSRecord(context.Background(), id)
@@ -307,6 +300,15 @@ func doDeleteDNS(network string, noPrompt bool, excludePattern string) bool {
 	return true
 }
 
+// promptYes prints the given prompt and returns true if the user typed 'yes'.
+func promptYes(prompt string) bool {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf("%s", prompt)
+	text, _ := reader.ReadString('\n')
+	text = strings.Replace(text, "\n", "", -1)
+	return text == "yes"
+}
+
 func listEntries(listNetwork string, recordType string) {
 	cfZoneID, cfEmail, cfKey, err := getClouldflareCredentials()
 	if err != nil {
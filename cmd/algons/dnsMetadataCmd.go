@@ -0,0 +1,183 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/tools/network"
+	"github.com/algorand/go-algorand/tools/network/cloudflare"
+)
+
+var (
+	metadataNetwork     string
+	metadataGenesisFile string
+	metadataKeyFile     string
+	metadataMinProtocol string
+	metadataCatchpoints []string
+	metadataSigner      string
+	metadataDryRun      bool
+)
+
+func init() {
+	dnsCmd.AddCommand(metadataCmd)
+	metadataCmd.AddCommand(metadataPublishCmd)
+	metadataCmd.AddCommand(metadataShowCmd)
+
+	metadataCmd.PersistentFlags().StringVarP(&metadataNetwork, "network", "n", "", "Bootstrap domain name of the network, such as devnet.algodev.network")
+	metadataCmd.MarkPersistentFlagRequired("network")
+
+	metadataPublishCmd.Flags().StringVarP(&metadataGenesisFile, "genesis", "g", "", "Genesis file of the network")
+	metadataPublishCmd.MarkFlagRequired("genesis")
+	metadataPublishCmd.Flags().StringVarP(&metadataKeyFile, "keyfile", "k", "", "Key file (as generated by algokey) used to sign the metadata")
+	metadataPublishCmd.MarkFlagRequired("keyfile")
+	metadataPublishCmd.Flags().StringVar(&metadataMinProtocol, "minproto", "", "Recommended minimal consensus protocol version")
+	metadataPublishCmd.Flags().StringSliceVar(&metadataCatchpoints, "catchpoint", nil, "Catchpoint label to publish; may be repeated")
+	metadataPublishCmd.Flags().BoolVar(&metadataDryRun, "dry-run", false, "Print the DNS API calls instead of executing them")
+
+	metadataShowCmd.Flags().StringVarP(&metadataSigner, "signer", "s", "", "Address of the expected metadata signer; if omitted, the signature is checked against the record's own signer")
+}
+
+var metadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "Manage the signed network metadata TXT record",
+	Long:  "Manage the signed network metadata TXT record published alongside the SRV bootstrap records",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+var metadataPublishCmd = &cobra.Command{
+	Use:     "publish",
+	Short:   "Sign and publish the network metadata TXT record",
+	Example: "algons dns metadata publish -n devnet.algodev.network -g genesis.json -k devnet.key --catchpoint 1000#ABCD",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := doPublishMetadata(metadataNetwork, metadataGenesisFile, metadataKeyFile, protocol.ConsensusVersion(metadataMinProtocol), metadataCatchpoints, metadataDryRun); err != nil {
+			fmt.Fprintf(os.Stderr, "Error publishing network metadata: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var metadataShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Resolve, verify and print the network metadata TXT record",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := doShowMetadata(metadataNetwork, metadataSigner); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading network metadata: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func doPublishMetadata(bootstrapID string, genesisFile string, keyFile string, minProtocol protocol.ConsensusVersion, catchpoints []string, dryRun bool) error {
+	genesis, err := bookkeeping.LoadGenesisFromFile(genesisFile)
+	if err != nil {
+		return fmt.Errorf("unable to load genesis file %s: %v", genesisFile, err)
+	}
+	seedBytes, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to read key file %s: %v", keyFile, err)
+	}
+	var seed crypto.Seed
+	if len(seedBytes) != len(seed) {
+		return fmt.Errorf("key file %s is not a valid key seed", keyFile)
+	}
+	copy(seed[:], seedBytes)
+	secrets := crypto.GenerateSignatureSecrets(seed)
+
+	signed := network.SignNetworkMetadata(secrets, network.NetworkMetadata{
+		GenesisID:          genesis.ID(),
+		GenesisHash:        crypto.HashObj(genesis),
+		MinProtocolVersion: minProtocol,
+		CatchpointLabels:   catchpoints,
+	})
+
+	cfZoneID, cfEmail, cfKey, err := getClouldflareCredentials()
+	if err != nil {
+		return fmt.Errorf("error getting DNS credentials: %v", err)
+	}
+	cloudflareDNS := cloudflare.NewDNS(cfZoneID, cfEmail, cfKey)
+	cloudflareDNS.SetDryRun(dryRun)
+	ctx := context.Background()
+
+	name := network.NetworkMetadataPrefix + bootstrapID
+	entries, err := cloudflareDNS.ListDNSRecord(ctx, "TXT", name, "", "", "", "")
+	if err != nil {
+		return fmt.Errorf("error listing TXT records for %s: %v", name, err)
+	}
+	fmt.Printf("Publishing metadata of %s signed by %s\n", genesis.ID(), basics.Address(signed.Signer).String())
+	if len(entries) == 0 {
+		return cloudflareDNS.CreateDNSRecord(ctx, "TXT", name, signed.EncodeTXT(), cloudflare.AutomaticTTL, 0, false)
+	}
+	// replace the existing metadata record, and remove any stale duplicates.
+	if err = cloudflareDNS.UpdateDNSRecord(ctx, entries[0].ID, "TXT", name, signed.EncodeTXT(), cloudflare.AutomaticTTL, 0, false); err != nil {
+		return err
+	}
+	for _, entry := range entries[1:] {
+		if err = cloudflareDNS.DeleteDNSRecord(ctx, entry.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func doShowMetadata(bootstrapID string, signerAddress string) error {
+	records, err := net.LookupTXT(network.NetworkMetadataPrefix + bootstrapID)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, txt := range records {
+		signed, err := network.DecodeNetworkMetadataTXT(txt)
+		if err != nil {
+			continue
+		}
+		found = true
+		signer := signed.Signer
+		if signerAddress != "" {
+			addr, err := basics.UnmarshalChecksumAddress(signerAddress)
+			if err != nil {
+				return fmt.Errorf("invalid signer address '%s': %v", signerAddress, err)
+			}
+			signer = crypto.PublicKey(addr)
+		}
+		status := "valid"
+		if err := signed.Verify(signer); err != nil {
+			status = err.Error()
+		}
+		fmt.Printf("Signer:              %s (%s)\n", basics.Address(signed.Signer).String(), status)
+		fmt.Printf("Genesis ID:          %s\n", signed.Metadata.GenesisID)
+		fmt.Printf("Genesis hash:        %s\n", signed.Metadata.GenesisHash.String())
+		fmt.Printf("Min protocol:        %s\n", signed.Metadata.MinProtocolVersion)
+		fmt.Printf("Catchpoint labels:   %v\n", signed.Metadata.CatchpointLabels)
+	}
+	if !found {
+		return fmt.Errorf("no network metadata records found for %s", bootstrapID)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/tools/network"
+	"github.com/algorand/go-algorand/tools/network/cloudflare"
+)
+
+var (
+	proxyName          string
+	proxyEnable        bool
+	proxyDisable       bool
+	proxyDryRun        bool
+	proxyVerifyTimeout time.Duration
+)
+
+func init() {
+	dnsCmd.AddCommand(proxyCmd)
+
+	proxyCmd.Flags().StringVarP(&proxyName, "name", "n", "", "Name of the A or CNAME record to update")
+	proxyCmd.MarkFlagRequired("name")
+	proxyCmd.Flags().BoolVar(&proxyEnable, "enable", false, "Proxy the record through cloudflare")
+	proxyCmd.Flags().BoolVar(&proxyDisable, "disable", false, "Stop proxying the record through cloudflare")
+	proxyCmd.Flags().BoolVar(&proxyDryRun, "dry-run", false, "Print the DNS API calls instead of executing them")
+	proxyCmd.Flags().DurationVar(&proxyVerifyTimeout, "verify-timeout", 2*time.Minute, "How long to wait for the record to resolve as expected after the update; 0 disables the verification")
+}
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Enable or disable cloudflare proxying of a DNS record",
+	Long: "Enable or disable cloudflare proxying of an A or CNAME record, and verify that the record resolves to the expected addresses afterwards.\n" +
+		"Records that are referenced by SRV records are never proxied, as the relays behind them must expose their port directly.",
+	Example: "algons dns proxy -n r1.devnet.algodev.network --disable",
+	Run: func(cmd *cobra.Command, args []string) {
+		if proxyEnable == proxyDisable {
+			fmt.Fprintf(os.Stderr, "Exactly one of --enable and --disable must be specified\n")
+			os.Exit(1)
+		}
+		if err := doSetProxied(proxyName, proxyEnable, proxyDryRun, proxyVerifyTimeout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating proxied flag: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// srvTargets returns the set of (lower case) names that are the targets of the given SRV records.
+func srvTargets(records []dnsRecordSpec) map[string]bool {
+	targets := make(map[string]bool)
+	for _, r := range records {
+		if r.Type != "SRV" {
+			continue
+		}
+		if _, _, target, err := r.srvContent(); err == nil {
+			targets[strings.ToLower(strings.TrimSuffix(target, "."))] = true
+		}
+	}
+	return targets
+}
+
+// checkProxiedRecords returns an error if any of the proxied records is referenced by an SRV record.
+func checkProxiedRecords(records []dnsRecordSpec, srvRecords []dnsRecordSpec) error {
+	targets := srvTargets(srvRecords)
+	for _, r := range records {
+		if r.Proxied && targets[strings.ToLower(r.Name)] {
+			return fmt.Errorf("record %s is referenced by an SRV record and cannot be proxied", r.Name)
+		}
+	}
+	return nil
+}
+
+// verifyResolution checks that the given name resolves as expected: directly to the origin addresses when it isn't
+// proxied, or to none of them when it is.
+func verifyResolution(lookupIP func(host string) ([]net.IP, error), name string, origin []net.IP, proxied bool) error {
+	resolved, err := lookupIP(name)
+	if err != nil {
+		return err
+	}
+	matches := 0
+	for _, ip := range resolved {
+		for _, originIP := range origin {
+			if ip.Equal(originIP) {
+				matches++
+				break
+			}
+		}
+	}
+	if proxied && matches > 0 {
+		return fmt.Errorf("%s still resolves to its origin address (%v)", name, resolved)
+	}
+	if !proxied && (matches == 0 || matches != len(resolved)) {
+		return fmt.Errorf("%s resolves to %v rather than its origin address %v", name, resolved, origin)
+	}
+	return nil
+}
+
+func doSetProxied(name string, proxied bool, dryRun bool, verifyTimeout time.Duration) error {
+	cfZoneID, cfEmail, cfKey, err := getClouldflareCredentials()
+	if err != nil {
+		return fmt.Errorf("error getting DNS credentials: %v", err)
+	}
+	cloudflareDNS := cloudflare.NewDNS(cfZoneID, cfEmail, cfKey)
+	cloudflareDNS.SetDryRun(dryRun)
+	ctx := context.Background()
+
+	var entries []cloudflare.DNSRecordResponseEntry
+	for _, recordType := range []string{"A", "CNAME"} {
+		typeEntries, err := cloudflareDNS.ListDNSRecord(ctx, recordType, name, "", "", "", "")
+		if err != nil {
+			return fmt.Errorf("error listing %s records for %s: %v", recordType, name, err)
+		}
+		entries = append(entries, typeEntries...)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no A or CNAME records found for %s", name)
+	}
+
+	if proxied {
+		srvEntries, err := cloudflareDNS.ListDNSRecord(ctx, "SRV", "", "", "", "", "")
+		if err != nil {
+			return fmt.Errorf("error listing SRV records: %v", err)
+		}
+		srvRecords := make([]dnsRecordSpec, 0, len(srvEntries))
+		for _, entry := range srvEntries {
+			srvRecords = append(srvRecords, dnsRecordSpec{Type: entry.Type, Name: entry.Name, Content: entry.Content})
+		}
+		if err = checkProxiedRecords([]dnsRecordSpec{{Name: name, Proxied: true}}, srvRecords); err != nil {
+			return err
+		}
+	}
+
+	var resolver network.Resolver
+	lookupIP := func(host string) ([]net.IP, error) {
+		addrs, err := resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		ips := make([]net.IP, len(addrs))
+		for i, addr := range addrs {
+			ips[i] = addr.IP
+		}
+		return ips, nil
+	}
+
+	origin := []net.IP{}
+	for _, entry := range entries {
+		if !entry.Proxiable && proxied {
+			return fmt.Errorf("record %s -> %s cannot be proxied by cloudflare", entry.Name, entry.Content)
+		}
+		if entry.Type == "A" {
+			origin = append(origin, net.ParseIP(entry.Content))
+		} else if ips, err := lookupIP(entry.Content); err == nil {
+			origin = append(origin, ips...)
+		}
+		if entry.Proxied == proxied {
+			fmt.Printf("%s -> %s is already %s\n", entry.Name, entry.Content, proxiedString(proxied))
+			continue
+		}
+		fmt.Printf("Updating %s -> %s to be %s\n", entry.Name, entry.Content, proxiedString(proxied))
+		err = cloudflareDNS.UpdateDNSRecord(ctx, entry.ID, entry.Type, entry.Name, entry.Content, uint(entry.TTL), uint(entry.Priority), proxied)
+		if err != nil {
+			return err
+		}
+	}
+
+	if dryRun || verifyTimeout == 0 {
+		return nil
+	}
+	fmt.Printf("Verifying that %s resolves %s...\n", name, proxiedString(proxied))
+	deadline := time.Now().Add(verifyTimeout)
+	for {
+		err = verifyResolution(lookupIP, name, origin, proxied)
+		if err == nil {
+			fmt.Printf("%s resolves as expected\n", name)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("verification failed: %v", err)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func proxiedString(proxied bool) string {
+	if proxied {
+		return "proxied"
+	}
+	return "direct"
+}
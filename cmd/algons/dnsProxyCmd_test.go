@@ -0,0 +1,60 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckProxiedRecords(t *testing.T) {
+	srv := []dnsRecordSpec{
+		{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: "1 4160 R1.test.algodev.network."},
+	}
+	require.Error(t, checkProxiedRecords([]dnsRecordSpec{{Type: "A", Name: "r1.test.algodev.network", Proxied: true}}, srv))
+	require.NoError(t, checkProxiedRecords([]dnsRecordSpec{{Type: "A", Name: "r1.test.algodev.network"}}, srv))
+	require.NoError(t, checkProxiedRecords([]dnsRecordSpec{{Type: "A", Name: "www.test.algodev.network", Proxied: true}}, srv))
+
+	f := dnsRecordsFile{Network: "test.algodev.network", Records: append(srv, dnsRecordSpec{Type: "A", Name: "r1.test.algodev.network", Content: "10.0.0.1", Proxied: true})}
+	require.Error(t, f.validate())
+}
+
+func TestVerifyResolution(t *testing.T) {
+	origin := []net.IP{net.ParseIP("10.0.0.1")}
+	resolved := map[string][]net.IP{
+		"direct.test.algodev.network":  {net.ParseIP("10.0.0.1")},
+		"proxied.test.algodev.network": {net.ParseIP("104.16.0.1"), net.ParseIP("104.16.0.2")},
+		"mixed.test.algodev.network":   {net.ParseIP("10.0.0.1"), net.ParseIP("104.16.0.1")},
+	}
+	lookupIP := func(host string) ([]net.IP, error) {
+		if ips, has := resolved[host]; has {
+			return ips, nil
+		}
+		return nil, fmt.Errorf("no such host")
+	}
+
+	require.NoError(t, verifyResolution(lookupIP, "direct.test.algodev.network", origin, false))
+	require.Error(t, verifyResolution(lookupIP, "direct.test.algodev.network", origin, true))
+	require.NoError(t, verifyResolution(lookupIP, "proxied.test.algodev.network", origin, true))
+	require.Error(t, verifyResolution(lookupIP, "proxied.test.algodev.network", origin, false))
+	require.Error(t, verifyResolution(lookupIP, "mixed.test.algodev.network", origin, true))
+	require.Error(t, verifyResolution(lookupIP, "mixed.test.algodev.network", origin, false))
+	require.Error(t, verifyResolution(lookupIP, "missing.test.algodev.network", origin, false))
+}
@@ -0,0 +1,196 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/tools/network/cloudflare"
+	"github.com/algorand/go-algorand/util"
+)
+
+var (
+	pruneNetwork     string
+	pruneService     string
+	pruneTimeout     time.Duration
+	pruneConcurrency int
+	pruneMaxDead     float64
+	pruneDelete      bool
+	pruneNoPrompt    bool
+	pruneDryRun      bool
+)
+
+func init() {
+	dnsCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().StringVarP(&pruneNetwork, "network", "n", "", "Domain name of the network whose SRV records should be checked, such as devnet.algodev.network")
+	pruneCmd.MarkFlagRequired("network")
+	pruneCmd.Flags().StringVarP(&pruneService, "service", "s", "_algobootstrap", "SRV service to check")
+	pruneCmd.Flags().DurationVar(&pruneTimeout, "timeout", 5*time.Second, "Timeout for connecting to each relay")
+	pruneCmd.Flags().IntVarP(&pruneConcurrency, "concurrency", "c", 16, "Number of relays to check concurrently")
+	pruneCmd.Flags().Float64Var(&pruneMaxDead, "max-dead", 0.5, "Share of the relays above which none is deleted, since so many failures are more likely local than the relays being down")
+	pruneCmd.Flags().BoolVar(&pruneDelete, "delete", false, "Delete the SRV records of unreachable relays; otherwise they are only reported")
+	pruneCmd.Flags().BoolVarP(&pruneNoPrompt, "no-prompt", "y", false, "No prompting for records deletion")
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Print the DNS API calls instead of executing them")
+}
+
+var pruneCmd = &cobra.Command{
+	Use:     "prune",
+	Short:   "Check the relays referenced by the SRV records of a network and prune the unreachable ones",
+	Long:    "Connect to each of the targets of the network SRV records, report the unreachable ones and optionally delete their SRV records",
+	Example: "algons dns prune -n devnet.algodev.network --delete --dry-run",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := doPruneDNS(pruneNetwork, pruneService, pruneTimeout, pruneConcurrency, pruneMaxDead, pruneDelete, pruneNoPrompt, pruneDryRun); err != nil {
+			fmt.Fprintf(os.Stderr, "Error pruning SRV records: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// relayHealth is the result of checking a single SRV record target.
+type relayHealth struct {
+	record  dnsRecordSpec
+	address string
+	err     error
+	// invalid is set when the record content couldn't be parsed, in which case the relay wasn't checked.
+	invalid bool
+}
+
+// checkRelaysHealth attempts to connect to the target of each of the given SRV records, using up to concurrency
+// connections at a time. The results are returned in the same order as the records.
+func checkRelaysHealth(ctx context.Context, records []dnsRecordSpec, timeout time.Duration, concurrency int) []relayHealth {
+	results := make([]relayHealth, len(records))
+	util.RunConcurrently(len(records), concurrency, func(i int) {
+		results[i] = checkRelayHealth(ctx, records[i], timeout)
+	})
+	return results
+}
+
+func checkRelayHealth(ctx context.Context, record dnsRecordSpec, timeout time.Duration) (health relayHealth) {
+	health.record = record
+	_, port, target, err := record.srvContent()
+	if err != nil {
+		health.err = err
+		health.invalid = true
+		return
+	}
+	health.address = net.JoinHostPort(target, strconv.Itoa(int(port)))
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", health.address)
+	if err != nil {
+		health.err = err
+		return
+	}
+	conn.Close()
+	return
+}
+
+// deadRelays returns the checked relays which are unreachable. It fails when more than maxDeadShare of them are, or all
+// of them, as that's more likely caused by a local network outage than by so many relays being down.
+func deadRelays(results []relayHealth, maxDeadShare float64) (dead []relayHealth, err error) {
+	checked := 0
+	for _, health := range results {
+		if health.invalid {
+			continue
+		}
+		checked++
+		if health.err != nil {
+			dead = append(dead, health)
+		}
+	}
+	if len(dead) > 0 && (len(dead) == checked || float64(len(dead)) > maxDeadShare*float64(checked)) {
+		return nil, fmt.Errorf("%d out of %d relays are unreachable, which is more than the %.0f%% allowed to be pruned at once", len(dead), checked, maxDeadShare*100)
+	}
+	return dead, nil
+}
+
+func doPruneDNS(network string, service string, timeout time.Duration, concurrency int, maxDeadShare float64, deleteRecords bool, noPrompt bool, dryRun bool) error {
+	cfZoneID, cfEmail, cfKey, err := getClouldflareCredentials()
+	if err != nil {
+		return fmt.Errorf("error getting DNS credentials: %v", err)
+	}
+
+	cloudflareDNS := cloudflare.NewDNS(cfZoneID, cfEmail, cfKey)
+	cloudflareDNS.SetDryRun(dryRun)
+	ctx := context.Background()
+
+	srvName := service + "._tcp." + network
+	entries, err := cloudflareDNS.ListDNSRecord(ctx, "SRV", srvName, "", "", "", "")
+	if err != nil {
+		return fmt.Errorf("error listing SRV records for %s: %v", srvName, err)
+	}
+	records := make([]dnsRecordSpec, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, dnsRecordSpec{Type: entry.Type, Name: entry.Name, Content: entry.Content, TTL: uint(entry.TTL), Priority: uint(entry.Priority), id: entry.ID})
+	}
+	if len(records) == 0 {
+		fmt.Printf("No SRV records found for %s\n", srvName)
+		return nil
+	}
+
+	fmt.Printf("Checking %d relays of %s...\n", len(records), srvName)
+	results := checkRelaysHealth(ctx, records, timeout, concurrency)
+	for _, health := range results {
+		switch {
+		case health.invalid:
+			fmt.Printf("SKIP %s : %v\n", health.record.Content, health.err)
+		case health.err != nil:
+			fmt.Printf("DEAD %s : %v\n", health.record.Content, health.err)
+		default:
+			fmt.Printf("OK   %s\n", health.address)
+		}
+	}
+	dead, err := deadRelays(results, maxDeadShare)
+	if err != nil {
+		if deleteRecords {
+			return fmt.Errorf("not deleting any record: %v", err)
+		}
+		fmt.Println(err)
+		return nil
+	}
+	fmt.Printf("%d relays are unreachable\n", len(dead))
+
+	if len(dead) == 0 || !deleteRecords {
+		return nil
+	}
+	if !noPrompt && !dryRun && !promptYes(fmt.Sprintf("Delete the SRV records of these %d relays (type 'yes' to delete)? ", len(dead))) {
+		return nil
+	}
+	changes := make([]dnsRecordChange, 0, len(dead))
+	for _, health := range dead {
+		fmt.Printf("Deleting %s -> %s\n", health.record.Name, health.record.Content)
+		changes = append(changes, dnsRecordChange{Action: dnsActionDelete, Current: health.record})
+	}
+	failed := 0
+	for i, err := range applyDNSRecordChanges(ctx, cloudflareDNS, changes, concurrency, dnsApplyRetries) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, " !! error deleting %s -> %s: %v\n", changes[i].Current.Name, changes[i].Current.Content, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d out of %d records", failed, len(dead))
+	}
+	return nil
+}
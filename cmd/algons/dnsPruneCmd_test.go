@@ -0,0 +1,89 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRelaysHealth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	livePort := listener.Addr().(*net.TCPAddr).Port
+
+	// grab a port and release it so that nobody is listening on it.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	deadPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+
+	records := []dnsRecordSpec{
+		{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: fmt.Sprintf("1 %d 127.0.0.1", livePort)},
+		{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: fmt.Sprintf("1 %d 127.0.0.1", deadPort)},
+		{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: "bad content"},
+	}
+	results := checkRelaysHealth(context.Background(), records, time.Second, 2)
+	require.Equal(t, len(records), len(results))
+	require.NoError(t, results[0].err)
+	require.Equal(t, fmt.Sprintf("127.0.0.1:%d", livePort), results[0].address)
+	require.Error(t, results[1].err)
+	require.Error(t, results[2].err)
+	require.False(t, results[1].invalid)
+	require.True(t, results[2].invalid)
+}
+
+func TestDeadRelays(t *testing.T) {
+	ok := relayHealth{}
+	dead := relayHealth{err: errors.New("connection refused")}
+	invalid := relayHealth{err: errors.New("bad content"), invalid: true}
+
+	results, err := deadRelays([]relayHealth{ok, ok, dead, invalid}, 0.5)
+	require.NoError(t, err)
+	require.Equal(t, []relayHealth{dead}, results)
+
+	// records which can't be parsed are neither pruned nor counted
+	results, err = deadRelays([]relayHealth{ok, dead, invalid, invalid}, 0.5)
+	require.NoError(t, err)
+	require.Equal(t, []relayHealth{dead}, results)
+
+	results, err = deadRelays([]relayHealth{ok, invalid}, 0.5)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// too many failures are more likely a local outage
+	_, err = deadRelays([]relayHealth{ok, dead, dead}, 0.5)
+	require.Error(t, err)
+	_, err = deadRelays([]relayHealth{dead, dead, invalid}, 1)
+	require.Error(t, err)
+}
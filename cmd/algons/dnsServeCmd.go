@@ -0,0 +1,186 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/netdeploy"
+	"github.com/algorand/go-algorand/tools/network/dnsserver"
+)
+
+var (
+	serveListenAddress string
+	serveRecordsFile   string
+	serveNetworkDir    string
+	serveDomain        string
+	serveTTL           uint
+)
+
+func init() {
+	dnsCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVarP(&serveListenAddress, "listen", "l", ":53", "Address to serve DNS requests on, over both UDP and TCP")
+	serveCmd.Flags().StringVarP(&serveRecordsFile, "file", "f", "", "Records file (.json, .yaml or .yml, as generated by 'algons dns export') to serve")
+	serveCmd.Flags().StringVarP(&serveNetworkDir, "network-dir", "r", "", "Root directory of a private network created by 'goal network create'; its running relays are served as SRV bootstrap records")
+	serveCmd.Flags().StringVarP(&serveDomain, "domain", "n", "", "Bootstrap domain name to serve the private network relays under, such as private.algodev.network")
+	serveCmd.Flags().UintVar(&serveTTL, "ttl", 60, "TTL of the records generated from the private network relays")
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an authoritative DNS server for a private network",
+	Long: "Run an authoritative DNS server that serves either the records of a records file, or SRV bootstrap records for the relays of a private network.\n" +
+		"Point the private network nodes' DNSBootstrapID and FallbackDNSResolverAddress to this server to use the standard DNS bootstrapping.",
+	Example: "algons dns serve -f private.yaml -l 127.0.0.1:5353\n" +
+		"algons dns serve -r ~/networks/private -n private.algodev.network",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := doServeDNS(serveListenAddress, serveRecordsFile, serveNetworkDir, serveDomain, uint32(serveTTL)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error serving DNS: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// recordSpecToServerRecord converts a records file entry into a record served by the dns server.
+func recordSpecToServerRecord(r dnsRecordSpec) (record dnsserver.Record, err error) {
+	record = dnsserver.Record{Type: r.Type, Name: r.Name, TTL: uint32(r.TTL)}
+	switch r.Type {
+	case "A":
+		record.IP = net.ParseIP(r.Content)
+		if record.IP == nil {
+			err = fmt.Errorf("invalid IP address '%s' for %s", r.Content, r.Name)
+		}
+	case "CNAME":
+		record.Target = r.Content
+	case "TXT":
+		record.Text = r.Content
+	case "SRV":
+		var weight, port uint
+		weight, port, record.Target, err = r.srvContent()
+		record.Weight, record.Port, record.Priority = uint16(weight), uint16(port), uint16(r.Priority)
+	default:
+		err = fmt.Errorf("record %s has unsupported type '%s'", r.Name, r.Type)
+	}
+	return
+}
+
+// networkRelayRecords generates the SRV bootstrap records (and the A records of their targets) for the given relay addresses.
+func networkRelayRecords(domain string, relayAddresses []string, ttl uint32) ([]dnsserver.Record, error) {
+	records := []dnsserver.Record{}
+	for i, relayAddress := range relayAddresses {
+		hostPort := relayAddress
+		if parsed, err := url.Parse(relayAddress); err == nil && parsed.Host != "" {
+			hostPort = parsed.Host
+		}
+		host, portStr, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			return nil, fmt.Errorf("invalid relay address '%s': %v", relayAddress, err)
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid relay address '%s': %v", relayAddress, err)
+		}
+		target := host
+		if ip := net.ParseIP(host); ip != nil {
+			// SRV targets have to be names, so give the relay a name of its own.
+			target = fmt.Sprintf("relay%d.%s", i+1, domain)
+			recordType := "A"
+			if ip.To4() == nil {
+				recordType = "AAAA"
+			}
+			if ip.IsUnspecified() {
+				ip = net.IPv4(127, 0, 0, 1)
+				recordType = "A"
+			}
+			records = append(records, dnsserver.Record{Type: recordType, Name: target, IP: ip, TTL: ttl})
+		}
+		records = append(records, dnsserver.Record{Type: "SRV", Name: "_algobootstrap._tcp." + domain, Target: target, Port: uint16(port), Priority: 1, Weight: 1, TTL: ttl})
+	}
+	return records, nil
+}
+
+func doServeDNS(listenAddress string, recordsFileName string, networkDir string, domain string, ttl uint32) error {
+	var records []dnsserver.Record
+	switch {
+	case recordsFileName != "" && networkDir != "":
+		return fmt.Errorf("only one of --file and --network-dir may be specified")
+	case recordsFileName != "":
+		recordsFile, err := loadRecordsFile(recordsFileName)
+		if err != nil {
+			return err
+		}
+		for _, r := range recordsFile.Records {
+			record, err := recordSpecToServerRecord(r)
+			if err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		domain = recordsFile.Network
+	case networkDir != "":
+		if domain == "" {
+			return fmt.Errorf("--domain is required when serving a private network")
+		}
+		network, err := netdeploy.LoadNetwork(networkDir)
+		if err != nil {
+			return fmt.Errorf("unable to load network from %s: %v", networkDir, err)
+		}
+		relayAddresses := network.GetPeerAddresses("")
+		if len(relayAddresses) == 0 {
+			return fmt.Errorf("no running relays were found in %s", networkDir)
+		}
+		records, err = networkRelayRecords(domain, relayAddresses, ttl)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("either --file or --network-dir must be specified")
+	}
+
+	server, err := dnsserver.MakeServer(domain, records)
+	if err != nil {
+		return err
+	}
+	udpConn, err := net.ListenPacket("udp", listenAddress)
+	if err != nil {
+		return err
+	}
+	defer udpConn.Close()
+	tcpListener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return err
+	}
+	defer tcpListener.Close()
+
+	for _, r := range records {
+		fmt.Printf("%-5s %s\n", r.Type, strings.TrimSuffix(r.Name, "."))
+	}
+	fmt.Printf("Serving %d records of %s on %s\n", len(records), domain, listenAddress)
+
+	errCh := make(chan error, 2)
+	go func() { errCh <- server.ServeUDP(udpConn) }()
+	go func() { errCh <- server.ServeTCP(tcpListener) }()
+	return <-errCh
+}
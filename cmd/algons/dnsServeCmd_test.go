@@ -0,0 +1,60 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkRelayRecords(t *testing.T) {
+	records, err := networkRelayRecords("private.algodev.network", []string{"http://127.0.0.1:4160", "[::]:4161", "relay.example.com:4162"}, 60)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(records))
+
+	require.Equal(t, "A", records[0].Type)
+	require.Equal(t, "relay1.private.algodev.network", records[0].Name)
+	require.True(t, net.ParseIP("127.0.0.1").Equal(records[0].IP))
+	require.Equal(t, "SRV", records[1].Type)
+	require.Equal(t, "relay1.private.algodev.network", records[1].Target)
+	require.Equal(t, uint16(4160), records[1].Port)
+
+	// unspecified listening addresses are served as the loopback address.
+	require.Equal(t, "A", records[2].Type)
+	require.True(t, net.ParseIP("127.0.0.1").Equal(records[2].IP))
+
+	require.Equal(t, "SRV", records[4].Type)
+	require.Equal(t, "relay.example.com", records[4].Target)
+	require.Equal(t, uint16(4162), records[4].Port)
+
+	_, err = networkRelayRecords("private.algodev.network", []string{"127.0.0.1"}, 60)
+	require.Error(t, err)
+}
+
+func TestRecordSpecToServerRecord(t *testing.T) {
+	record, err := recordSpecToServerRecord(dnsRecordSpec{Type: "SRV", Name: "_algobootstrap._tcp.private.algodev.network", Content: "2 4160 r1.private.algodev.network", Priority: 3, TTL: 60})
+	require.NoError(t, err)
+	require.Equal(t, uint16(2), record.Weight)
+	require.Equal(t, uint16(4160), record.Port)
+	require.Equal(t, uint16(3), record.Priority)
+	require.Equal(t, "r1.private.algodev.network", record.Target)
+
+	_, err = recordSpecToServerRecord(dnsRecordSpec{Type: "A", Name: "r1.private.algodev.network", Content: "r1"})
+	require.Error(t, err)
+}
@@ -0,0 +1,420 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/algorand/go-algorand/tools/network/cloudflare"
+)
+
+var (
+	exportNetwork    string
+	exportFile       string
+	applyFile        string
+	applyDryRun      bool
+	applyNoPrompt    bool
+	applyNoDeletes   bool
+	applyConcurrency int
+	applyDNSSEC      bool
+)
+
+// syncRecordTypes are the record types that are exported and managed by the export / apply commands.
+var syncRecordTypes = []string{"A", "CNAME", "SRV", "TXT"}
+
+func init() {
+	dnsCmd.AddCommand(exportCmd)
+	dnsCmd.AddCommand(applyCmd)
+
+	exportCmd.Flags().StringVarP(&exportNetwork, "network", "n", "", "Domain name for records to export")
+	exportCmd.MarkFlagRequired("network")
+	exportCmd.Flags().StringVarP(&exportFile, "output", "o", "", "Output file name (.json, .yaml or .yml); prints JSON to stdout if omitted")
+
+	applyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "Records file (.json, .yaml or .yml) to apply")
+	applyCmd.MarkFlagRequired("file")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Print the changes and the DNS API calls without executing them")
+	applyCmd.Flags().BoolVarP(&applyNoPrompt, "no-prompt", "y", false, "No prompting before applying the changes")
+	applyCmd.Flags().BoolVar(&applyNoDeletes, "no-deletes", false, "Don't delete records that are missing from the file")
+	applyCmd.Flags().IntVarP(&applyConcurrency, "concurrency", "c", 8, "Number of DNS API calls to execute concurrently")
+	applyCmd.Flags().BoolVar(&applyDNSSEC, "dnssec", false, "Enable DNSSEC signing of the zone, if it isn't enabled yet, and print its DS record")
+}
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	Short:   "Export the DNS/SRV records of the given network to a file",
+	Long:    "Export all the A, CNAME, SRV and TXT records of the given network domain to a JSON or YAML file",
+	Example: "algons dns export -n devnet.algodev.network -o devnet.yaml",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := doExportDNS(exportNetwork, exportFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting DNS records: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var applyCmd = &cobra.Command{
+	Use:     "apply",
+	Short:   "Apply a DNS records file to the network domain",
+	Long:    "Compare the records in the given file against the current records of its network domain, print the create/update/delete changes and apply them",
+	Example: "algons dns apply -f devnet.yaml --dry-run",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := doApplyDNS(applyFile, applyDryRun, applyNoPrompt, applyNoDeletes, applyConcurrency, applyDNSSEC); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying DNS records: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// dnsRecordsFile is the file format used by the export and apply commands.
+type dnsRecordsFile struct {
+	Network string          `json:"network" yaml:"network"`
+	Records []dnsRecordSpec `json:"records" yaml:"records"`
+}
+
+// dnsRecordSpec describes a single DNS record. For SRV records, the content is
+// stored the way cloudflare reports it - "<weight> <port> <target>".
+type dnsRecordSpec struct {
+	Type     string `json:"type" yaml:"type"`
+	Name     string `json:"name" yaml:"name"`
+	Content  string `json:"content" yaml:"content"`
+	TTL      uint   `json:"ttl" yaml:"ttl"`
+	Priority uint   `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Proxied  bool   `json:"proxied,omitempty" yaml:"proxied,omitempty"`
+
+	// id is the cloudflare record ID; it's only known for existing records.
+	id string
+}
+
+// dnsRecordChange is a single create/update/delete operation computed by diffDNSRecords.
+type dnsRecordChange struct {
+	Action  string
+	Desired dnsRecordSpec
+	Current dnsRecordSpec
+}
+
+const (
+	dnsActionCreate = "create"
+	dnsActionUpdate = "update"
+	dnsActionDelete = "delete"
+)
+
+// key returns the identity of the record; two records with the same key are the same record, possibly with different attributes.
+func (r dnsRecordSpec) key() string {
+	content := r.Content
+	if r.Type == "SRV" {
+		if _, _, target, err := r.srvContent(); err == nil {
+			content = target
+		}
+	}
+	return strings.ToUpper(r.Type) + "|" + strings.ToLower(r.Name) + "|" + strings.ToLower(content)
+}
+
+// equal compares the attributes of two records with the same key.
+func (r dnsRecordSpec) equal(other dnsRecordSpec) bool {
+	return r.Content == other.Content && r.TTL == other.TTL && r.Priority == other.Priority && r.Proxied == other.Proxied
+}
+
+// srvContent parses the content of an SRV record.
+func (r dnsRecordSpec) srvContent() (weight uint, port uint, target string, err error) {
+	fields := strings.Fields(r.Content)
+	if len(fields) != 3 {
+		err = fmt.Errorf("invalid SRV content '%s' for %s; expected '<weight> <port> <target>'", r.Content, r.Name)
+		return
+	}
+	w, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		err = fmt.Errorf("invalid SRV weight '%s' for %s: %v", fields[0], r.Name, err)
+		return
+	}
+	p, err := strconv.ParseUint(fields[1], 10, 16)
+	if err != nil {
+		err = fmt.Errorf("invalid SRV port '%s' for %s: %v", fields[1], r.Name, err)
+		return
+	}
+	return uint(w), uint(p), fields[2], nil
+}
+
+// srvName splits an SRV record name such as _algobootstrap._tcp.devnet.algodev.network into its components.
+func (r dnsRecordSpec) srvName() (service string, protocol string, name string, err error) {
+	parts := strings.SplitN(r.Name, ".", 3)
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "_") || !strings.HasPrefix(parts[1], "_") {
+		err = fmt.Errorf("invalid SRV name '%s'; expected '_<service>._<protocol>.<name>'", r.Name)
+		return
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func (r dnsRecordSpec) String() string {
+	s := fmt.Sprintf("%-5s %s -> %s (ttl %d", r.Type, r.Name, r.Content, r.TTL)
+	if r.Priority != 0 {
+		s += fmt.Sprintf(", priority %d", r.Priority)
+	}
+	if r.Proxied {
+		s += ", proxied"
+	}
+	return s + ")"
+}
+
+func (c dnsRecordChange) String() string {
+	switch c.Action {
+	case dnsActionCreate:
+		return fmt.Sprintf("+ %v", c.Desired)
+	case dnsActionDelete:
+		return fmt.Sprintf("- %v", c.Current)
+	default:
+		return fmt.Sprintf("~ %v\n    was %v", c.Desired, c.Current)
+	}
+}
+
+// validate verifies that the records file is well formed, that all of its records belong to its network, and that
+// none of the records referenced by its SRV records is proxied.
+func (f *dnsRecordsFile) validate() error {
+	if f.Network == "" {
+		return fmt.Errorf("records file is missing the network domain")
+	}
+	seen := make(map[string]bool)
+	for i, r := range f.Records {
+		r.Type = strings.ToUpper(r.Type)
+		f.Records[i].Type = r.Type
+		if !isSyncRecordType(r.Type) {
+			return fmt.Errorf("record %s has unsupported type '%s'", r.Name, r.Type)
+		}
+		if !inNetwork(r.Name, f.Network) {
+			return fmt.Errorf("record %s does not belong to network %s", r.Name, f.Network)
+		}
+		if r.Type == "SRV" {
+			if _, _, _, err := r.srvContent(); err != nil {
+				return err
+			}
+			if _, _, _, err := r.srvName(); err != nil {
+				return err
+			}
+		}
+		if seen[r.key()] {
+			return fmt.Errorf("duplicate record %v", r)
+		}
+		seen[r.key()] = true
+	}
+	return checkProxiedRecords(f.Records, f.Records)
+}
+
+// inNetwork returns whether the record name is the network domain itself or one of its subdomains. Sibling domains
+// which merely end with the same characters, such as mydevnet.algodev.network for devnet.algodev.network, are not.
+func inNetwork(name string, network string) bool {
+	name = strings.ToLower(name)
+	network = strings.ToLower(network)
+	return name == network || strings.HasSuffix(name, "."+network)
+}
+
+func isSyncRecordType(recordType string) bool {
+	for _, t := range syncRecordTypes {
+		if t == recordType {
+			return true
+		}
+	}
+	return false
+}
+
+// diffDNSRecords computes the changes required to turn the current records into the desired ones.
+// The returned changes are sorted so that deletions come last, to avoid leaving a network without records mid-way.
+func diffDNSRecords(current []dnsRecordSpec, desired []dnsRecordSpec, noDeletes bool) []dnsRecordChange {
+	currentByKey := make(map[string]dnsRecordSpec, len(current))
+	for _, r := range current {
+		currentByKey[r.key()] = r
+	}
+	changes := []dnsRecordChange{}
+	desiredKeys := make(map[string]bool, len(desired))
+	for _, r := range desired {
+		desiredKeys[r.key()] = true
+		existing, has := currentByKey[r.key()]
+		if !has {
+			changes = append(changes, dnsRecordChange{Action: dnsActionCreate, Desired: r})
+			continue
+		}
+		if !existing.equal(r) {
+			r.id = existing.id
+			changes = append(changes, dnsRecordChange{Action: dnsActionUpdate, Desired: r, Current: existing})
+		}
+	}
+	if !noDeletes {
+		for _, r := range current {
+			if !desiredKeys[r.key()] {
+				changes = append(changes, dnsRecordChange{Action: dnsActionDelete, Current: r})
+			}
+		}
+	}
+	actionOrder := map[string]int{dnsActionCreate: 0, dnsActionUpdate: 1, dnsActionDelete: 2}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return actionOrder[changes[i].Action] < actionOrder[changes[j].Action]
+	})
+	return changes
+}
+
+// fetchNetworkRecords returns all the managed records of the given network domain.
+func fetchNetworkRecords(ctx context.Context, cloudflareDNS *cloudflare.DNS, network string) ([]dnsRecordSpec, error) {
+	records := []dnsRecordSpec{}
+	for _, recType := range syncRecordTypes {
+		entries, err := cloudflareDNS.ListDNSRecord(ctx, recType, "", "", "", "", "")
+		if err != nil {
+			return nil, fmt.Errorf("error listing %s records: %v", recType, err)
+		}
+		for _, entry := range entries {
+			if !inNetwork(entry.Name, network) {
+				continue
+			}
+			records = append(records, dnsRecordSpec{
+				Type:     entry.Type,
+				Name:     entry.Name,
+				Content:  entry.Content,
+				TTL:      uint(entry.TTL),
+				Priority: uint(entry.Priority),
+				Proxied:  entry.Proxied,
+				id:       entry.ID,
+			})
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].key() < records[j].key()
+	})
+	return records, nil
+}
+
+func marshalRecordsFile(fileName string, recordsFile dnsRecordsFile) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		return yaml.Marshal(recordsFile)
+	default:
+		return json.MarshalIndent(recordsFile, "", "\t")
+	}
+}
+
+func loadRecordsFile(fileName string) (recordsFile dnsRecordsFile, err error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &recordsFile)
+	default:
+		err = json.Unmarshal(data, &recordsFile)
+	}
+	if err != nil {
+		err = fmt.Errorf("unable to parse %s: %v", fileName, err)
+		return
+	}
+	err = recordsFile.validate()
+	return
+}
+
+func doExportDNS(network string, outputFile string) error {
+	cfZoneID, cfEmail, cfKey, err := getClouldflareCredentials()
+	if err != nil {
+		return fmt.Errorf("error getting DNS credentials: %v", err)
+	}
+
+	cloudflareDNS := cloudflare.NewDNS(cfZoneID, cfEmail, cfKey)
+	records, err := fetchNetworkRecords(context.Background(), cloudflareDNS, network)
+	if err != nil {
+		return err
+	}
+
+	data, err := marshalRecordsFile(outputFile, dnsRecordsFile{Network: network, Records: records})
+	if err != nil {
+		return err
+	}
+	if outputFile == "" {
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	if err = ioutil.WriteFile(outputFile, data, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d records to %s\n", len(records), outputFile)
+	return nil
+}
+
+func doApplyDNS(fileName string, dryRun bool, noPrompt bool, noDeletes bool, concurrency int, dnssec bool) error {
+	recordsFile, err := loadRecordsFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	cfZoneID, cfEmail, cfKey, err := getClouldflareCredentials()
+	if err != nil {
+		return fmt.Errorf("error getting DNS credentials: %v", err)
+	}
+
+	cloudflareDNS := cloudflare.NewDNS(cfZoneID, cfEmail, cfKey)
+	cloudflareDNS.SetDryRun(dryRun)
+	ctx := context.Background()
+	current, err := fetchNetworkRecords(ctx, cloudflareDNS, recordsFile.Network)
+	if err != nil {
+		return err
+	}
+
+	changes := diffDNSRecords(current, recordsFile.Records, noDeletes)
+	if len(changes) == 0 && !dnssec {
+		fmt.Printf("No changes required for %s\n", recordsFile.Network)
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Printf("%v\n", change)
+	}
+	pending := len(changes)
+	if dnssec {
+		// DNSSEC is only enabled once confirmed, along with the record changes
+		fmt.Printf("Enable DNSSEC signing of the zone, if it isn't enabled yet\n")
+		pending++
+	}
+	if !noPrompt && !dryRun && !promptYes(fmt.Sprintf("Apply these %d changes (type 'yes' to apply)? ", pending)) {
+		return nil
+	}
+
+	failed := 0
+	for i, err := range applyDNSRecordChanges(ctx, cloudflareDNS, changes, concurrency, dnsApplyRetries) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, " !! error applying '%v': %v\n", changes[i], err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d out of %d changes failed", failed, len(changes))
+	}
+	if dryRun {
+		fmt.Printf("Dry run: %d changes were not applied\n", pending)
+		return nil
+	}
+	if dnssec {
+		if err = ensureDNSSEC(ctx, cloudflareDNS); err != nil {
+			return fmt.Errorf("error enabling DNSSEC: %v", err)
+		}
+	}
+	fmt.Printf("Applied %d changes\n", len(changes))
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffDNSRecords(t *testing.T) {
+	current := []dnsRecordSpec{
+		{Type: "A", Name: "r1.test.algodev.network", Content: "10.0.0.1", TTL: 1, id: "id1"},
+		{Type: "CNAME", Name: "r2.test.algodev.network", Content: "relay2.algodev.network", TTL: 1, id: "id2"},
+		{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: "1 4160 r1.test.algodev.network", TTL: 1, Priority: 1, id: "id3"},
+		{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: "1 4160 r2.test.algodev.network", TTL: 1, Priority: 1, id: "id4"},
+	}
+	desired := []dnsRecordSpec{
+		{Type: "A", Name: "r1.test.algodev.network", Content: "10.0.0.1", TTL: 1},
+		{Type: "CNAME", Name: "R2.test.algodev.network", Content: "relay2.algodev.network", TTL: 300},
+		{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: "1 4161 r1.test.algodev.network", TTL: 1, Priority: 1},
+		{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: "1 4160 r3.test.algodev.network", TTL: 1, Priority: 1},
+	}
+
+	changes := diffDNSRecords(current, desired, false)
+	require.Equal(t, 4, len(changes))
+	require.Equal(t, dnsActionCreate, changes[0].Action)
+	require.Equal(t, "1 4160 r3.test.algodev.network", changes[0].Desired.Content)
+	require.Equal(t, dnsActionUpdate, changes[1].Action)
+	require.Equal(t, "id2", changes[1].Desired.id)
+	require.Equal(t, uint(300), changes[1].Desired.TTL)
+	require.Equal(t, dnsActionUpdate, changes[2].Action)
+	require.Equal(t, "id3", changes[2].Desired.id)
+	require.Equal(t, dnsActionDelete, changes[3].Action)
+	require.Equal(t, "id4", changes[3].Current.id)
+
+	changes = diffDNSRecords(current, desired, true)
+	require.Equal(t, 3, len(changes))
+
+	require.Empty(t, diffDNSRecords(current, current, false))
+}
+
+func TestDNSRecordsFileValidate(t *testing.T) {
+	f := dnsRecordsFile{
+		Network: "test.algodev.network",
+		Records: []dnsRecordSpec{
+			{Type: "srv", Name: "_algobootstrap._tcp.test.algodev.network", Content: "1 4160 r1.test.algodev.network"},
+		},
+	}
+	require.NoError(t, f.validate())
+	require.Equal(t, "SRV", f.Records[0].Type)
+
+	service, protocol, name, err := f.Records[0].srvName()
+	require.NoError(t, err)
+	require.Equal(t, "_algobootstrap", service)
+	require.Equal(t, "_tcp", protocol)
+	require.Equal(t, "test.algodev.network", name)
+
+	f.Records = append(f.Records, dnsRecordSpec{Type: "A", Name: "r1.other.algodev.network", Content: "10.0.0.1"})
+	require.Error(t, f.validate())
+
+	// a sibling zone ending with the network domain doesn't belong to it.
+	f.Records[1] = dnsRecordSpec{Type: "A", Name: "r1.mytest.algodev.network", Content: "10.0.0.1"}
+	require.Error(t, f.validate())
+	f.Records[1] = dnsRecordSpec{Type: "A", Name: "mytest.algodev.network", Content: "10.0.0.1"}
+	require.Error(t, f.validate())
+	f.Records[1] = dnsRecordSpec{Type: "TXT", Name: "Test.AlgoDev.Network", Content: "v=spf1 -all"}
+	require.NoError(t, f.validate())
+
+	f.Records[1] = dnsRecordSpec{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: "4160 r1.test.algodev.network"}
+	require.Error(t, f.validate())
+
+	f.Records[1] = dnsRecordSpec{Type: "MX", Name: "test.algodev.network", Content: "mail.test.algodev.network"}
+	require.Error(t, f.validate())
+}
+
+func TestInNetwork(t *testing.T) {
+	tests := []struct {
+		name      string
+		inNetwork bool
+	}{
+		{"devnet.algodev.network", true},
+		{"r1.devnet.algodev.network", true},
+		{"_algobootstrap._tcp.DevNet.AlgoDev.Network", true},
+		{"mydevnet.algodev.network", false},
+		{"r1.mydevnet.algodev.network", false},
+		{"algodev.network", false},
+		{"devnet.algodev.network.example.com", false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.inNetwork, inNetwork(test.name, "devnet.algodev.network"), test.name)
+	}
+}
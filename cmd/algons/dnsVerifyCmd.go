@@ -0,0 +1,226 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/tools/network/dnsserver"
+	"github.com/algorand/go-algorand/util"
+)
+
+var (
+	verifyFile          string
+	verifyResolvers     []string
+	verifyAuthoritative bool
+	verifyConcurrency   int
+	verifyFix           bool
+	verifyDryRun        bool
+	verifyNoPrompt      bool
+	verifyNoDeletes     bool
+)
+
+// cloudflareAutomaticTTL is the TTL cloudflare serves for records whose configured TTL is 1, meaning 'automatic'.
+const cloudflareAutomaticTTL = 300
+
+func init() {
+	dnsCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringVarP(&verifyFile, "file", "f", "", "Records file (.json, .yaml or .yml) with the desired records")
+	verifyCmd.MarkFlagRequired("file")
+	verifyCmd.Flags().StringArrayVarP(&verifyResolvers, "resolver", "r", []string{"8.8.8.8", "1.1.1.1"}, "DNS resolver to query, as host or host:port; may be repeated")
+	verifyCmd.Flags().BoolVar(&verifyAuthoritative, "authoritative", false, "The resolvers are authoritative servers of the zone, so the TTLs must match exactly rather than not exceed the desired ones")
+	verifyCmd.Flags().IntVarP(&verifyConcurrency, "concurrency", "c", 8, "Number of DNS queries to execute concurrently")
+	verifyCmd.Flags().BoolVar(&verifyFix, "fix", false, "Apply the records file when discrepancies are found, the same way the apply command does")
+	verifyCmd.Flags().BoolVar(&verifyDryRun, "dry-run", false, "With --fix, print the changes and the DNS API calls without executing them")
+	verifyCmd.Flags().BoolVarP(&verifyNoPrompt, "no-prompt", "y", false, "With --fix, no prompting before applying the changes")
+	verifyCmd.Flags().BoolVar(&verifyNoDeletes, "no-deletes", false, "With --fix, don't delete records that are missing from the file")
+}
+
+var verifyCmd = &cobra.Command{
+	Use:     "verify",
+	Short:   "Verify that the live DNS answers match a DNS records file",
+	Long:    "Query the given resolvers for every record of the records file and report missing and unexpected answers and TTLs exceeding the desired ones, such as stale cached SRV entries and partially applied updates. With --fix, the records file is applied when discrepancies are found",
+	Example: "algons dns verify -f devnet.yaml -r 8.8.8.8 -r 1.1.1.1 --fix",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := doVerifyDNS(verifyFile, verifyResolvers, verifyAuthoritative, verifyConcurrency, verifyFix, verifyDryRun, verifyNoPrompt, verifyNoDeletes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error verifying DNS records: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// dnsQueryFunc queries the given resolver for the records of the given name and type.
+type dnsQueryFunc func(ctx context.Context, resolver string, name string, recordType string) ([]dnsserver.Record, error)
+
+// dnsDiscrepancy is a difference between the answer of a resolver and the desired records.
+type dnsDiscrepancy struct {
+	Resolver string
+	Type     string
+	Name     string
+	Problem  string
+}
+
+func (d dnsDiscrepancy) String() string {
+	return fmt.Sprintf("%s: %-5s %s %s", d.Resolver, d.Type, d.Name, d.Problem)
+}
+
+// dnsRecordSet is the set of desired records sharing a name and a type, which a single query verifies.
+type dnsRecordSet struct {
+	recordType string
+	name       string
+	records    []dnsserver.Record
+}
+
+// liveRecordValue formats the attributes of a record, other than its name and TTL, so that the records file entries
+// and the resolver answers can be compared.
+func liveRecordValue(r dnsserver.Record) string {
+	switch r.Type {
+	case "A", "AAAA":
+		return r.IP.String()
+	case "CNAME":
+		return strings.ToLower(strings.TrimSuffix(r.Target, "."))
+	case "SRV":
+		return fmt.Sprintf("%d %d %s (priority %d)", r.Weight, r.Port, strings.ToLower(strings.TrimSuffix(r.Target, ".")), r.Priority)
+	default:
+		return r.Text
+	}
+}
+
+// groupDNSRecordSets groups the desired records by name and type, in the order they first appear. Proxied records
+// are skipped, since resolvers answer them with the proxy addresses; the number of skipped records is returned.
+func groupDNSRecordSets(desired []dnsRecordSpec) (sets []dnsRecordSet, proxied int, err error) {
+	index := make(map[string]int)
+	for _, r := range desired {
+		if r.Proxied {
+			proxied++
+			continue
+		}
+		record, err := recordSpecToServerRecord(r)
+		if err != nil {
+			return nil, 0, err
+		}
+		if record.TTL == 1 {
+			record.TTL = cloudflareAutomaticTTL
+		}
+		key := strings.ToUpper(r.Type) + "|" + strings.ToLower(r.Name)
+		i, has := index[key]
+		if !has {
+			i = len(sets)
+			index[key] = i
+			sets = append(sets, dnsRecordSet{recordType: strings.ToUpper(r.Type), name: strings.ToLower(r.Name)})
+		}
+		sets[i].records = append(sets[i].records, record)
+	}
+	return sets, proxied, nil
+}
+
+// compareDNSRecordSet compares the answer of a resolver with the desired record set. Caching resolvers count the
+// TTLs down, so unless the resolver is authoritative, only an answer TTL exceeding the desired one is a discrepancy.
+func compareDNSRecordSet(resolver string, set dnsRecordSet, answer []dnsserver.Record, authoritative bool) []dnsDiscrepancy {
+	discrepancies := []dnsDiscrepancy{}
+	report := func(format string, args ...interface{}) {
+		discrepancies = append(discrepancies, dnsDiscrepancy{Resolver: resolver, Type: set.recordType, Name: set.name, Problem: fmt.Sprintf(format, args...)})
+	}
+	live := make(map[string]dnsserver.Record)
+	for _, r := range answer {
+		if r.Type == set.recordType && strings.EqualFold(strings.TrimSuffix(r.Name, "."), set.name) {
+			live[liveRecordValue(r)] = r
+		}
+	}
+	desired := make(map[string]bool)
+	for _, r := range set.records {
+		value := liveRecordValue(r)
+		desired[value] = true
+		answered, has := live[value]
+		switch {
+		case !has:
+			report("missing %s", value)
+		case answered.TTL > r.TTL || (authoritative && answered.TTL != r.TTL):
+			report("%s has ttl %d instead of %d", value, answered.TTL, r.TTL)
+		}
+	}
+	for _, r := range answer {
+		value := liveRecordValue(r)
+		if _, has := live[value]; has && !desired[value] {
+			report("unexpected %s (ttl %d)", value, r.TTL)
+			delete(live, value)
+		}
+	}
+	return discrepancies
+}
+
+// verifyDNSRecords queries each of the resolvers for every desired record set, using up to concurrency queries at a
+// time, and returns the discrepancies ordered by resolver and then by record set.
+func verifyDNSRecords(ctx context.Context, query dnsQueryFunc, resolvers []string, sets []dnsRecordSet, authoritative bool, concurrency int) []dnsDiscrepancy {
+	results := make([][]dnsDiscrepancy, len(resolvers)*len(sets))
+	util.RunConcurrently(len(results), concurrency, func(i int) {
+		resolver, set := resolvers[i/len(sets)], sets[i%len(sets)]
+		answer, err := query(ctx, resolver, set.name, set.recordType)
+		if err != nil {
+			results[i] = []dnsDiscrepancy{{Resolver: resolver, Type: set.recordType, Name: set.name, Problem: fmt.Sprintf("query failed: %v", err)}}
+			return
+		}
+		results[i] = compareDNSRecordSet(resolver, set, answer, authoritative)
+	})
+	discrepancies := []dnsDiscrepancy{}
+	for _, result := range results {
+		discrepancies = append(discrepancies, result...)
+	}
+	return discrepancies
+}
+
+func doVerifyDNS(fileName string, resolvers []string, authoritative bool, concurrency int, fix bool, dryRun bool, noPrompt bool, noDeletes bool) error {
+	recordsFile, err := loadRecordsFile(fileName)
+	if err != nil {
+		return err
+	}
+	if len(resolvers) == 0 {
+		return fmt.Errorf("no resolvers were given")
+	}
+	sets, proxied, err := groupDNSRecordSets(recordsFile.Records)
+	if err != nil {
+		return err
+	}
+	if proxied > 0 {
+		fmt.Printf("Skipping %d proxied records\n", proxied)
+	}
+
+	discrepancies := verifyDNSRecords(context.Background(), dnsserver.Query, resolvers, sets, authoritative, concurrency)
+	for _, d := range discrepancies {
+		fmt.Printf("%v\n", d)
+	}
+	if len(discrepancies) == 0 {
+		fmt.Printf("All %d record sets of %s match on %d resolvers\n", len(sets), recordsFile.Network, len(resolvers))
+		return nil
+	}
+	if !fix {
+		return fmt.Errorf("%d discrepancies found", len(discrepancies))
+	}
+
+	fmt.Printf("Found %d discrepancies; applying %s\n", len(discrepancies), fileName)
+	if err = doApplyDNS(fileName, dryRun, noPrompt, noDeletes, concurrency, false); err != nil {
+		return err
+	}
+	fmt.Printf("Discrepancies caused by cached answers clear once their TTLs expire\n")
+	return nil
+}
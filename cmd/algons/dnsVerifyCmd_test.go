@@ -0,0 +1,90 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/tools/network/dnsserver"
+)
+
+func TestVerifyDNSRecords(t *testing.T) {
+	desired := []dnsRecordSpec{
+		{Type: "A", Name: "r1.test.algodev.network", Content: "10.0.0.1", TTL: 1},
+		{Type: "CNAME", Name: "r2.test.algodev.network", Content: "relay2.algodev.network", TTL: 60},
+		{Type: "CNAME", Name: "www.test.algodev.network", Content: "site.algodev.network", Proxied: true},
+		{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: "1 4160 r1.test.algodev.network", TTL: 60, Priority: 1},
+		{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Content: "1 4160 r3.test.algodev.network", TTL: 60, Priority: 1},
+	}
+	sets, proxied, err := groupDNSRecordSets(desired)
+	require.NoError(t, err)
+	require.Equal(t, 1, proxied)
+	require.Equal(t, 3, len(sets))
+	require.Equal(t, 2, len(sets[2].records))
+	require.Equal(t, uint32(cloudflareAutomaticTTL), sets[0].records[0].TTL)
+
+	answers := map[string][]dnsserver.Record{
+		"good|A":     {{Type: "A", Name: "r1.test.algodev.network", IP: net.ParseIP("10.0.0.1"), TTL: 200}},
+		"good|CNAME": {{Type: "CNAME", Name: "r2.test.algodev.network.", Target: "Relay2.algodev.network.", TTL: 60}},
+		"good|SRV": {
+			{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Target: "r1.test.algodev.network", Weight: 1, Port: 4160, Priority: 1, TTL: 10},
+			{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Target: "r3.test.algodev.network", Weight: 1, Port: 4160, Priority: 1, TTL: 10},
+		},
+		// a stale cache: the old TTL, a removed relay and a relay that wasn't added yet.
+		"stale|A":     {{Type: "A", Name: "r1.test.algodev.network", IP: net.ParseIP("10.0.0.1"), TTL: 3600}},
+		"stale|CNAME": {{Type: "CNAME", Name: "r2.test.algodev.network", Target: "relay2.algodev.network", TTL: 60}},
+		"stale|SRV": {
+			{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Target: "r1.test.algodev.network", Weight: 1, Port: 4160, Priority: 1, TTL: 10},
+			{Type: "SRV", Name: "_algobootstrap._tcp.test.algodev.network", Target: "r2.test.algodev.network", Weight: 1, Port: 4160, Priority: 1, TTL: 10},
+		},
+	}
+	query := func(ctx context.Context, resolver string, name string, recordType string) ([]dnsserver.Record, error) {
+		if resolver == "down" {
+			return nil, fmt.Errorf("timeout")
+		}
+		return answers[resolver+"|"+recordType], nil
+	}
+
+	require.Empty(t, verifyDNSRecords(context.Background(), query, []string{"good"}, sets, false, 2))
+
+	discrepancies := verifyDNSRecords(context.Background(), query, []string{"good", "stale", "down"}, sets, false, 2)
+	problems := []string{}
+	for _, d := range discrepancies {
+		problems = append(problems, d.String())
+	}
+	require.Equal(t, []string{
+		"stale: A     r1.test.algodev.network 10.0.0.1 has ttl 3600 instead of 300",
+		"stale: SRV   _algobootstrap._tcp.test.algodev.network missing 1 4160 r3.test.algodev.network (priority 1)",
+		"stale: SRV   _algobootstrap._tcp.test.algodev.network unexpected 1 4160 r2.test.algodev.network (priority 1) (ttl 10)",
+		"down: A     r1.test.algodev.network query failed: timeout",
+		"down: CNAME r2.test.algodev.network query failed: timeout",
+		"down: SRV   _algobootstrap._tcp.test.algodev.network query failed: timeout",
+	}, problems)
+
+	// authoritative servers must serve the exact TTLs.
+	discrepancies = verifyDNSRecords(context.Background(), query, []string{"good"}, sets, true, 2)
+	require.Equal(t, 3, len(discrepancies))
+	require.True(t, strings.HasPrefix(discrepancies[0].Problem, "10.0.0.1 has ttl 200"))
+	require.Equal(t, "SRV", discrepancies[1].Type)
+	require.Equal(t, "SRV", discrepancies[2].Type)
+}
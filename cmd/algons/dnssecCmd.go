@@ -0,0 +1,161 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/tools/network/cloudflare"
+)
+
+var (
+	dnssecDryRun   bool
+	dnssecNoPrompt bool
+)
+
+func init() {
+	dnsCmd.AddCommand(dnssecCmd)
+	dnssecCmd.AddCommand(dnssecStatusCmd)
+	dnssecCmd.AddCommand(dnssecEnableCmd)
+	dnssecCmd.AddCommand(dnssecDisableCmd)
+	dnssecCmd.AddCommand(dnssecRotateCmd)
+
+	for _, cmd := range []*cobra.Command{dnssecEnableCmd, dnssecDisableCmd, dnssecRotateCmd} {
+		cmd.Flags().BoolVar(&dnssecDryRun, "dry-run", false, "Print the DNS API calls instead of executing them")
+	}
+	dnssecDisableCmd.Flags().BoolVarP(&dnssecNoPrompt, "no-prompt", "y", false, "No prompting before disabling DNSSEC")
+	dnssecRotateCmd.Flags().BoolVarP(&dnssecNoPrompt, "no-prompt", "y", false, "No prompting before rotating the keys")
+}
+
+var dnssecCmd = &cobra.Command{
+	Use:   "dnssec",
+	Short: "Manage DNSSEC signing of the DNS zone",
+	Long: "Manage DNSSEC signing of the DNS zone. Once signing is enabled, the DS record it prints has to be added at the registrar " +
+		"for validating resolvers to trust the zone.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+var dnssecStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the DNSSEC status of the zone and its DS record material",
+	Run: func(cmd *cobra.Command, args []string) {
+		cloudflareDNS := makeDNSSECClient(false)
+		result, err := cloudflareDNS.GetDNSSEC(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting DNSSEC status: %v\n", err)
+			os.Exit(1)
+		}
+		printDNSSECResult(result)
+	},
+}
+
+var dnssecEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enable DNSSEC signing of the zone",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ensureDNSSEC(context.Background(), makeDNSSECClient(dnssecDryRun)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error enabling DNSSEC: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var dnssecDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable DNSSEC signing of the zone",
+	Long:  "Disable DNSSEC signing of the zone. Remove the DS record at the registrar, and wait for its TTL to expire, before disabling the signing.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if !dnssecNoPrompt && !dnssecDryRun && !promptYes("Has the DS record been removed at the registrar (type 'yes' to disable DNSSEC)? ") {
+			return
+		}
+		result, err := makeDNSSECClient(dnssecDryRun).DisableDNSSEC(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error disabling DNSSEC: %v\n", err)
+			os.Exit(1)
+		}
+		printDNSSECResult(result)
+	},
+}
+
+var dnssecRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate the DNSSEC keys of the zone",
+	Long: "Rotate the DNSSEC keys of the zone. The zone is unsigned while the keys are replaced, and the DS record at the registrar " +
+		"has to be replaced with the newly printed one before validating resolvers would trust the zone again.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if !dnssecNoPrompt && !dnssecDryRun && !promptYes("Rotating the keys invalidates the current DS record (type 'yes' to rotate)? ") {
+			return
+		}
+		result, err := makeDNSSECClient(dnssecDryRun).RotateDNSSECKeys(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rotating DNSSEC keys: %v\n", err)
+			os.Exit(1)
+		}
+		printDNSSECResult(result)
+	},
+}
+
+func makeDNSSECClient(dryRun bool) *cloudflare.DNS {
+	cfZoneID, cfEmail, cfKey, err := getClouldflareCredentials()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error getting DNS credentials: %v\n", err)
+		os.Exit(1)
+	}
+	cloudflareDNS := cloudflare.NewDNS(cfZoneID, cfEmail, cfKey)
+	cloudflareDNS.SetDryRun(dryRun)
+	return cloudflareDNS
+}
+
+// ensureDNSSEC enables DNSSEC signing of the zone unless it is already enabled, and prints the DS record material
+// that has to be added at the registrar.
+func ensureDNSSEC(ctx context.Context, cloudflareDNS *cloudflare.DNS) error {
+	result, err := cloudflareDNS.GetDNSSEC(ctx)
+	if err != nil {
+		return err
+	}
+	if result.Status == cloudflare.DNSSECStatusDisabled {
+		fmt.Printf("Enabling DNSSEC\n")
+		result, err = cloudflareDNS.EnableDNSSEC(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	printDNSSECResult(result)
+	return nil
+}
+
+func printDNSSECResult(result cloudflare.DNSSECResult) {
+	fmt.Printf("DNSSEC status: %s\n", result.Status)
+	if result.DS == "" {
+		return
+	}
+	fmt.Printf("DS record:     %s\n", result.DS)
+	fmt.Printf("Key tag:       %d\n", result.KeyTag)
+	fmt.Printf("Algorithm:     %s\n", result.Algorithm)
+	fmt.Printf("Digest type:   %s (%s)\n", result.DigestType, result.DigestAlgorithm)
+	fmt.Printf("Digest:        %s\n", result.Digest)
+	fmt.Printf("Public key:    %s\n", result.PublicKey)
+	if result.Status == cloudflare.DNSSECStatusPending {
+		fmt.Printf("Add the DS record at the registrar to complete the DNSSEC setup\n")
+	}
+}
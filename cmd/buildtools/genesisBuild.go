@@ -0,0 +1,111 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/gen"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var (
+	allocationsFile    string
+	buildNetworkName   string
+	buildSchemaID      string
+	buildProto         string
+	buildTimestamp     int64
+	buildComment       string
+	buildFeeSink       string
+	buildRewardsPool   string
+	feeSinkBalance     uint64
+	rewardsPoolBalance uint64
+	buildOutputFile    string
+)
+
+func init() {
+	buildCmd.Flags().StringVarP(&allocationsFile, "allocations", "a", "", "CSV (with a header line) or YAML (.yaml or .yml) file of the genesis accounts")
+	buildCmd.MarkFlagRequired("allocations")
+	buildCmd.Flags().StringVarP(&buildNetworkName, "network", "n", "", "The network name for the genesis file")
+	buildCmd.MarkFlagRequired("network")
+	buildCmd.Flags().StringVarP(&buildSchemaID, "schema", "s", "", "The schema ID of the genesis file (defaults to v1)")
+	buildCmd.Flags().StringVarP(&buildProto, "proto", "p", "", "The consensus protocol of the genesis block (defaults to the current protocol)")
+	buildCmd.Flags().Int64VarP(&buildTimestamp, "timestamp", "t", 0, "The genesis timestamp (in unix time); 0 leaves it to be initialized on release")
+	buildCmd.Flags().StringVarP(&buildComment, "comment", "c", "", "The genesis comment")
+	buildCmd.Flags().StringVar(&buildFeeSink, "feesink", "", "The fee sink address (defaults to the address used by private networks)")
+	buildCmd.Flags().StringVar(&buildRewardsPool, "rewardspool", "", "The rewards pool address (defaults to the address used by private networks)")
+	buildCmd.Flags().Uint64Var(&feeSinkBalance, "feesink-balance", 0, "The fee sink balance in microAlgos, unless given in the allocations file (defaults to the minimum balance)")
+	buildCmd.Flags().Uint64Var(&rewardsPoolBalance, "rewardspool-balance", 0, "The rewards pool balance in microAlgos, unless given in the allocations file")
+	buildCmd.Flags().StringVarP(&buildOutputFile, "output", "o", "genesis.json", "The genesis file to write; its hash is written next to it, with a .hash suffix")
+	genesisCmd.AddCommand(buildCmd)
+}
+
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build a genesis file from a list of account allocations",
+	Long: `Build a genesis file from a list of account addresses, balances and participation status, and report its ID, hash and stake distribution.
+The allocations file columns (or YAML keys) are address, microalgos, and optionally comment, status (Online, Offline or NotParticipating),
+and for online accounts either partkey (a participation key file) or selectionkey, votekey, votefirst, votelast and votekd.`,
+	Example: "buildtools genesis build -a accounts.csv -n private -o genesis.json",
+	Run: func(cmd *cobra.Command, args []string) {
+		params := gen.GenesisParams{
+			NetworkName:        buildNetworkName,
+			SchemaID:           buildSchemaID,
+			ConsensusProtocol:  protocol.ConsensusVersion(buildProto),
+			Timestamp:          buildTimestamp,
+			Comment:            buildComment,
+			FeeSinkBalance:     feeSinkBalance,
+			RewardsPoolBalance: rewardsPoolBalance,
+		}
+		var err error
+		if buildFeeSink != "" {
+			if params.FeeSink, err = basics.UnmarshalChecksumAddress(buildFeeSink); err != nil {
+				reportErrorf("Invalid fee sink address: %v", err)
+			}
+		}
+		if buildRewardsPool != "" {
+			if params.RewardsPool, err = basics.UnmarshalChecksumAddress(buildRewardsPool); err != nil {
+				reportErrorf("Invalid rewards pool address: %v", err)
+			}
+		}
+
+		allocs, err := gen.LoadAllocations(allocationsFile)
+		if err != nil {
+			reportErrorf("Error loading allocations: %v", err)
+		}
+		genesis, err := gen.BuildGenesis(params, allocs)
+		if err != nil {
+			reportErrorf("Error building genesis: %v", err)
+		}
+		err = gen.WriteGenesisFile(genesis, buildOutputFile)
+		if err != nil {
+			reportErrorf("Error writing genesis file '%s': %v", buildOutputFile, err)
+		}
+		summary := gen.SummarizeGenesis(genesis)
+		err = ioutil.WriteFile(buildOutputFile+".hash", []byte(summary.Hash.String()), 0666)
+		if err != nil {
+			reportErrorf("Error writing hash file '%s.hash': %v", buildOutputFile, err)
+		}
+
+		reportInfof("Wrote %s", buildOutputFile)
+		summary.Print(os.Stdout)
+	},
+}
@@ -18,20 +18,26 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/rpcs"
 	tools_network "github.com/algorand/go-algorand/tools/network"
 )
 
@@ -40,117 +46,411 @@ var serversFlag = flag.String("servers", "", "Semicolon-separated list of origin
 var networkFlag = flag.String("network", "", "Network ID to obtain servers via DNS SRV")
 var genesisFlag = flag.String("genesis", "", "Genesis ID")
 var connsFlag = flag.Int("conns", 2, "Number of connections per server")
+var parallelFlag = flag.Int("parallel", 0, "Number of blocks downloaded in parallel (default conns per server times the number of servers)")
+var retriesFlag = flag.Int("retries", 5, "Number of attempts to download a block, rotating between the servers, before giving up")
+var lastFlag = flag.Uint64("last", 0, "Last round to download (default: probe the servers for their last round)")
+var verifyFlag = flag.Bool("verify", true, "Verify that every downloaded block decodes, matches its round and is certified by its certificate")
+var progressFlag = flag.Duration("progress", 10*time.Second, "Interval between progress reports (0 disables them)")
 
-var serverList []string
-var nextBlk uint64
+// partialSuffix is appended to the name of the blocks being downloaded, whose download is resumed after a failure.
+const partialSuffix = ".partial"
+
+// errBlockNotFound is returned for the blocks the server doesn't have.
+var errBlockNotFound = errors.New("block not found")
 
 func blockToString(blk uint64) string {
 	return strconv.FormatUint(blk, 36)
 }
 
-func blockDir() string {
-	return filepath.Join(*dirFlag, fmt.Sprintf("v1/%s/block", *genesisFlag))
+func blockDir(dir string, genesisID string) string {
+	return filepath.Join(dir, fmt.Sprintf("v1/%s/block", genesisID))
 }
 
-func blockFile(blk uint64) string {
-	return filepath.Join(blockDir(), blockToString(blk))
+func blockURL(server string, genesisID string, blk uint64) string {
+	return fmt.Sprintf("http://%s/v1/%s/block/%s", server, genesisID, blockToString(blk))
 }
 
-func blockURL(server string, blk uint64) string {
-	return fmt.Sprintf("http://%s/v1/%s/block/%s", server, *genesisFlag, blockToString(blk))
+// downloader downloads the blocks of a network from its relays into the catchup server directory, resuming the
+// interrupted downloads.
+type downloader struct {
+	dir       string
+	genesisID string
+	servers   []string
+	client    *http.Client
+	retries   int
+	verify    bool
+	// retryDelay is the delay before the first retry, which grows linearly with the retries
+	retryDelay time.Duration
+
+	// next is the next round to download, and end the first round that isn't downloaded
+	next uint64
+	end  uint64
+
+	downloaded uint64
+	skipped    uint64
+	bytes      uint64
+
+	mu     sync.Mutex
+	failed []uint64
 }
 
-func fetchBlock(server string, blk uint64) error {
-	fn := blockFile(blk)
+func (d *downloader) blockFile(blk uint64) string {
+	return filepath.Join(blockDir(d.dir, d.genesisID), blockToString(blk))
+}
+
+// fetchBlock downloads the given block from the given server, unless it was already downloaded. It resumes the
+// previous attempt when the server supports range requests.
+func (d *downloader) fetchBlock(ctx context.Context, server string, blk uint64) error {
+	fn := d.blockFile(blk)
 	_, err := os.Stat(fn)
 	if err == nil {
+		atomic.AddUint64(&d.skipped, 1)
 		return nil
 	}
-
 	if !os.IsNotExist(err) {
 		return err
 	}
 
-	fmt.Printf("Fetching %d from %s..\n", blk, server)
-	resp, err := http.Get(blockURL(server, blk))
+	partial := fn + partialSuffix
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+
+	request, err := http.NewRequest("GET", blockURL(server, d.genesisID, blk), nil)
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(ctx)
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := d.client.Do(request)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
+	flags := os.O_WRONLY | os.O_CREATE
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// the server doesn't support range requests, or there was nothing to resume
+		flags |= os.O_TRUNC
+	case http.StatusPartialContent:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			os.Remove(partial)
+			return fmt.Errorf("unexpected content range '%s'", resp.Header.Get("Content-Range"))
+		}
+		flags |= os.O_APPEND
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial block is longer than the block, so it can't be resumed
+		os.Remove(partial)
+		return fmt.Errorf("HTTP response: %s", resp.Status)
+	case http.StatusNotFound:
+		return errBlockNotFound
+	default:
 		return fmt.Errorf("HTTP response: %s", resp.Status)
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	f, err := os.OpenFile(partial, flags, 0666)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, resp.Body)
+	atomic.AddUint64(&d.bytes, uint64(n))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
 		return err
 	}
 
-	return ioutil.WriteFile(fn, body, 0666)
+	if d.verify {
+		data, err := ioutil.ReadFile(partial)
+		if err != nil {
+			return err
+		}
+		if err = verifyBlock(data, blk); err != nil {
+			os.Remove(partial)
+			return err
+		}
+	}
+	if err = os.Rename(partial, fn); err != nil {
+		return err
+	}
+	atomic.AddUint64(&d.downloaded, 1)
+	return nil
 }
 
-func fetcher(server string, wg *sync.WaitGroup) {
-	for {
-		myBlock := atomic.AddUint64(&nextBlk, 1) - 1
+// verifyBlock checks that the given encoded block and certificate decode, belong to the given round, and that the
+// certificate is for this very block.
+func verifyBlock(data []byte, blk uint64) error {
+	var entry rpcs.EncodedBlockCert
+	if err := protocol.Decode(data, &entry); err != nil {
+		return fmt.Errorf("cannot decode block: %v", err)
+	}
+	if uint64(entry.Block.Round()) != blk {
+		return fmt.Errorf("received block of round %d", entry.Block.Round())
+	}
+	if blk == 0 {
+		// the genesis block has no certificate
+		return nil
+	}
+	if uint64(entry.Certificate.Round) != blk {
+		return fmt.Errorf("received certificate of round %d", entry.Certificate.Round)
+	}
+	if entry.Certificate.Proposal.BlockDigest != entry.Block.Digest() {
+		return fmt.Errorf("certificate is for block %v rather than %v", entry.Certificate.Proposal.BlockDigest, entry.Block.Digest())
+	}
+	return nil
+}
+
+// fetchWithRetries downloads the given block, rotating between the servers on failures. It returns errBlockNotFound
+// only when none of the servers has the block.
+func (d *downloader) fetchWithRetries(ctx context.Context, worker int, blk uint64) (err error) {
+	notFound := make(map[string]bool)
+	for attempt := 0; attempt < d.retries; attempt++ {
+		server := d.servers[(worker+attempt)%len(d.servers)]
+		err = d.fetchBlock(ctx, server, blk)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if err == errBlockNotFound {
+			notFound[server] = true
+			if len(notFound) == len(d.servers) {
+				return
+			}
+			continue
+		}
+		fmt.Printf("Fetching %d from %s (attempt %d of %d): %v\n", blk, server, attempt+1, d.retries, err)
+		select {
+		case <-time.After(time.Duration(attempt+1) * d.retryDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return
+}
+
+func (d *downloader) fetcher(ctx context.Context, worker int, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for ctx.Err() == nil {
+		blk := atomic.AddUint64(&d.next, 1) - 1
+		if blk >= atomic.LoadUint64(&d.end) {
+			return
+		}
 
-		err := fetchBlock(server, myBlock)
+		err := d.fetchWithRetries(ctx, worker, blk)
+		switch {
+		case err == nil:
+		case err == errBlockNotFound:
+			// none of the servers has this block, which marks the end of the chain
+			for {
+				end := atomic.LoadUint64(&d.end)
+				if blk >= end || atomic.CompareAndSwapUint64(&d.end, end, blk) {
+					break
+				}
+			}
+		default:
+			fmt.Printf("Giving up on block %d: %v\n", blk, err)
+			d.mu.Lock()
+			d.failed = append(d.failed, blk)
+			d.mu.Unlock()
+		}
+	}
+}
+
+// probeLastRound estimates the last round the given server has, using an exponential and then a binary search.
+func (d *downloader) probeLastRound(ctx context.Context, server string) (uint64, error) {
+	has := func(blk uint64) (bool, error) {
+		request, err := http.NewRequest("GET", blockURL(server, d.genesisID, blk), nil)
+		if err != nil {
+			return false, err
+		}
+		resp, err := d.client.Do(request.WithContext(ctx))
+		if err != nil {
+			return false, err
+		}
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return true, nil
+		case http.StatusNotFound:
+			return false, nil
+		default:
+			return false, fmt.Errorf("HTTP response: %s", resp.Status)
+		}
+	}
+	found, err := has(0)
+	if err != nil || !found {
+		return 0, fmt.Errorf("cannot probe %s: %v", server, err)
+	}
+	low, high := uint64(0), uint64(1024)
+	for {
+		found, err = has(high)
 		if err != nil {
-			fmt.Printf("Fetching %d from %s: %v\n", myBlock, server, err)
+			return 0, err
+		}
+		if !found {
 			break
 		}
+		low, high = high, high*2
 	}
+	// low is available and high isn't
+	for high-low > 1 {
+		mid := low + (high-low)/2
+		found, err = has(mid)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
 
-	wg.Done()
+// reportProgress periodically prints the download progress, and the estimated time to complete it when the last
+// round is known.
+func (d *downloader) reportProgress(ctx context.Context, interval time.Duration, start uint64, last uint64) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	startTime := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		downloaded := atomic.LoadUint64(&d.downloaded)
+		done := downloaded + atomic.LoadUint64(&d.skipped)
+		elapsed := time.Since(startTime)
+		rate := float64(downloaded) / elapsed.Seconds()
+		megabytes := float64(atomic.LoadUint64(&d.bytes)) / (1 << 20)
+		if last == math.MaxUint64 || rate == 0 {
+			fmt.Printf("Downloaded %d blocks (%.1f MB, %.1f blocks/s), %d already present\n", downloaded, megabytes, rate, done-downloaded)
+			continue
+		}
+		total := last - start + 1
+		remaining := float64(0)
+		if total > done {
+			remaining = float64(total - done)
+		}
+		eta := time.Duration(remaining / rate * float64(time.Second))
+		fmt.Printf("Downloaded %d of %d blocks (%.1f%%, %.1f MB, %.1f blocks/s), ETA %v\n",
+			done, total, 100*float64(done)/float64(total), megabytes, rate, eta.Round(time.Second))
+	}
 }
 
-func download() {
-	if *genesisFlag == "" {
-		panic("Must specify -genesis")
+// run downloads the blocks with the given number of parallel fetchers, and returns the rounds it failed to download.
+// The expected last round is only used for estimating the remaining time.
+func (d *downloader) run(ctx context.Context, parallel int, expectedLast uint64, progressInterval time.Duration) []uint64 {
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	if progressInterval > 0 {
+		go d.reportProgress(progressCtx, progressInterval, d.next, expectedLast)
 	}
 
-	if *serversFlag != "" {
-		serverList = strings.Split(*serversFlag, ";")
-	} else if *networkFlag != "" {
-		cfg := config.GetDefaultLocal()
-		bootstrapID := cfg.DNSBootstrap(protocol.NetworkID(*networkFlag))
-		_, records, err := net.LookupSRV("algobootstrap", "tcp", bootstrapID)
-		if err != nil {
-			dnsAddr, err2 := net.ResolveIPAddr("ip", cfg.FallbackDNSResolverAddress)
-			if err2 != nil {
-				// Report original LookupSRV error
-				panic(err)
-			}
+	var wg sync.WaitGroup
+	wg.Add(parallel)
+	for i := 0; i < parallel; i++ {
+		go d.fetcher(ctx, i, &wg)
+	}
+	wg.Wait()
+	stopProgress()
+	sort.Slice(d.failed, func(i, j int) bool { return d.failed[i] < d.failed[j] })
+	return d.failed
+}
 
-			var resolver tools_network.Resolver
-			resolver.DNSAddress = *dnsAddr
-			_, records, err = resolver.LookupSRV(context.Background(), "algobootstrap", "tcp", bootstrapID)
-			if err != nil {
-				panic(err)
-			}
+func lookupServers() (servers []string) {
+	if *serversFlag != "" {
+		return strings.Split(*serversFlag, ";")
+	}
+	if *networkFlag == "" {
+		panic("Must specify -servers or -network")
+	}
+	cfg := config.GetDefaultLocal()
+	bootstrapID := cfg.DNSBootstrap(protocol.NetworkID(*networkFlag))
+	_, records, err := net.LookupSRV("algobootstrap", "tcp", bootstrapID)
+	if err != nil {
+		dnsAddr, err2 := net.ResolveIPAddr("ip", cfg.FallbackDNSResolverAddress)
+		if err2 != nil {
+			// Report original LookupSRV error
+			panic(err)
 		}
 
-		for _, srv := range records {
-			serverList = append(serverList, fmt.Sprintf("%s:%d", srv.Target, srv.Port))
+		var resolver tools_network.Resolver
+		resolver.DNSAddress = *dnsAddr
+		_, records, err = resolver.LookupSRV(context.Background(), "algobootstrap", "tcp", bootstrapID)
+		if err != nil {
+			panic(err)
 		}
-	} else {
-		panic("Must specify -servers or -network")
+	}
+
+	for _, srv := range records {
+		servers = append(servers, fmt.Sprintf("%s:%d", srv.Target, srv.Port))
+	}
+	return
+}
+
+func download() {
+	if *genesisFlag == "" {
+		panic("Must specify -genesis")
+	}
+	servers := lookupServers()
+	if len(servers) == 0 {
+		panic("No servers to download from")
 	}
 
 	http.DefaultTransport.(*http.Transport).MaxConnsPerHost = *connsFlag
 	http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost = *connsFlag
 
-	os.MkdirAll(blockDir(), 0777)
-
-	var wg sync.WaitGroup
+	d := &downloader{
+		dir:        *dirFlag,
+		genesisID:  *genesisFlag,
+		servers:    servers,
+		client:     http.DefaultClient,
+		retries:    *retriesFlag,
+		verify:     *verifyFlag,
+		retryDelay: time.Second,
+		end:        math.MaxUint64,
+	}
+	if d.retries < 1 {
+		d.retries = 1
+	}
+	os.MkdirAll(blockDir(d.dir, d.genesisID), 0777)
 
-	fetchPerServer := *connsFlag
-	for _, srv := range serverList {
-		wg.Add(fetchPerServer)
-		for i := 0; i < fetchPerServer; i++ {
-			go fetcher(srv, &wg)
+	ctx := context.Background()
+	expectedLast := uint64(math.MaxUint64)
+	if *lastFlag > 0 {
+		d.end = *lastFlag + 1
+		expectedLast = *lastFlag
+	} else if *progressFlag > 0 {
+		// the servers may have more blocks by the time these are downloaded, so the download still goes on until
+		// none of them has the next block
+		for _, server := range servers {
+			probed, err := d.probeLastRound(ctx, server)
+			if err != nil {
+				fmt.Printf("%v\n", err)
+				continue
+			}
+			fmt.Printf("%s has blocks up to round %d\n", server, probed)
+			if expectedLast == math.MaxUint64 || probed > expectedLast {
+				expectedLast = probed
+			}
 		}
 	}
 
-	wg.Wait()
+	parallel := *parallelFlag
+	if parallel <= 0 {
+		parallel = *connsFlag * len(servers)
+	}
+	failed := d.run(ctx, parallel, expectedLast, *progressFlag)
+	fmt.Printf("Downloaded %d blocks (%d already present)\n", d.downloaded, d.skipped)
+	if len(failed) > 0 {
+		fmt.Printf("Failed to download %d blocks, such as %d; run again to resume\n", len(failed), failed[0])
+		os.Exit(1)
+	}
 }
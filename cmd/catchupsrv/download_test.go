@@ -0,0 +1,173 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/rpcs"
+)
+
+func encodeTestBlock(rnd uint64) []byte {
+	var entry rpcs.EncodedBlockCert
+	entry.Block.BlockHeader = bookkeeping.BlockHeader{Round: basics.Round(rnd), TimeStamp: int64(rnd)}
+	entry.Certificate = agreement.Certificate{Round: basics.Round(rnd)}
+	entry.Certificate.Proposal.BlockDigest = entry.Block.Digest()
+	return protocol.Encode(entry)
+}
+
+// testBlockServer serves the given number of blocks. It cuts the first response of every block short, after
+// sending half of it, unless the block was requested with a range.
+type testBlockServer struct {
+	blocks    int
+	interrupt bool
+
+	mu        sync.Mutex
+	requested map[string]int
+	ranges    int
+}
+
+func (s *testBlockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	roundStr := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	rnd, err := strconv.ParseUint(roundStr, 36, 64)
+	if err != nil || rnd >= uint64(s.blocks) {
+		http.NotFound(w, r)
+		return
+	}
+	data := encodeTestBlock(rnd)
+
+	s.mu.Lock()
+	s.requested[roundStr]++
+	first := s.requested[roundStr] == 1
+	if r.Header.Get("Range") != "" {
+		s.ranges++
+	}
+	s.mu.Unlock()
+
+	if s.interrupt && first {
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(data[:len(data)/2])
+		w.(http.Flusher).Flush()
+		// hijack the connection to drop it before the whole block was sent
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-algorand-block-v1")
+	http.ServeContent(w, r, roundStr, time.Time{}, bytes.NewReader(data))
+}
+
+func makeTestDownloader(t *testing.T, servers ...string) *downloader {
+	dir, err := ioutil.TempDir("", "catchupsrv")
+	require.NoError(t, err)
+	d := &downloader{
+		dir:        dir,
+		genesisID:  "test-v1",
+		servers:    servers,
+		client:     &http.Client{},
+		retries:    3,
+		verify:     true,
+		retryDelay: time.Millisecond,
+		end:        math.MaxUint64,
+	}
+	require.NoError(t, os.MkdirAll(blockDir(d.dir, d.genesisID), 0777))
+	return d
+}
+
+func TestDownloadResumesInterruptedBlocks(t *testing.T) {
+	server := &testBlockServer{blocks: 20, interrupt: true, requested: make(map[string]int)}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	d := makeTestDownloader(t, strings.TrimPrefix(ts.URL, "http://"))
+	defer os.RemoveAll(d.dir)
+
+	failed := d.run(context.Background(), 4, math.MaxUint64, 0)
+	require.Empty(t, failed)
+	require.Equal(t, uint64(20), d.downloaded)
+	require.Equal(t, uint64(20), d.end)
+	require.Equal(t, 20, server.ranges)
+	for rnd := uint64(0); rnd < 20; rnd++ {
+		data, err := ioutil.ReadFile(d.blockFile(rnd))
+		require.NoError(t, err)
+		require.Equal(t, encodeTestBlock(rnd), data)
+		_, err = os.Stat(d.blockFile(rnd) + partialSuffix)
+		require.True(t, os.IsNotExist(err))
+	}
+
+	// downloading again skips the blocks that were already downloaded
+	d.next, d.end, d.downloaded = 0, math.MaxUint64, 0
+	require.Empty(t, d.run(context.Background(), 2, math.MaxUint64, 0))
+	require.Zero(t, d.downloaded)
+	require.Equal(t, uint64(20), d.skipped)
+}
+
+func TestDownloadVerifiesBlocks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// serve the block of the next round
+		w.Write(encodeTestBlock(2))
+	}))
+	defer ts.Close()
+
+	d := makeTestDownloader(t, strings.TrimPrefix(ts.URL, "http://"))
+	defer os.RemoveAll(d.dir)
+	d.end = 2
+	d.retries = 1
+
+	failed := d.run(context.Background(), 1, 1, 0)
+	require.Equal(t, []uint64{0, 1}, failed)
+	_, err := os.Stat(d.blockFile(1))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(d.blockFile(1) + partialSuffix)
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, verifyBlock(encodeTestBlock(7), 7))
+	require.Error(t, verifyBlock(encodeTestBlock(7), 8))
+	require.Error(t, verifyBlock([]byte("garbage"), 7))
+}
+
+func TestProbeLastRound(t *testing.T) {
+	server := &testBlockServer{blocks: 3001, requested: make(map[string]int)}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	d := makeTestDownloader(t, strings.TrimPrefix(ts.URL, "http://"))
+	defer os.RemoveAll(d.dir)
+	last, err := d.probeLastRound(context.Background(), d.servers[0])
+	require.NoError(t, err)
+	require.Equal(t, uint64(3000), last)
+}
@@ -19,9 +19,9 @@ package main
 import (
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"strconv"
+	"os"
+	"time"
 
 	"github.com/algorand/websocket"
 	"github.com/gorilla/mux"
@@ -84,18 +84,18 @@ func main() {
 		roundStr := pathVars["round"]
 		genesisID := pathVars["genesisID"]
 
-		data, err := ioutil.ReadFile(fmt.Sprintf("%s/v%s/%s/block/%s",
+		f, err := os.Open(fmt.Sprintf("%s/v%s/%s/block/%s",
 			*dirFlag, versionStr, genesisID, roundStr))
 		if err != nil {
 			fmt.Printf("%s %s: %v\n", r.Method, r.URL, err)
 			http.NotFound(w, r)
 			return
 		}
+		defer f.Close()
 
+		// ServeContent handles the range requests of the downloads resuming an interrupted block
 		w.Header().Set("Content-Type", "application/x-algorand-block-v1")
-		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
-		w.WriteHeader(http.StatusOK)
-		w.Write(data)
+		http.ServeContent(w, r, roundStr, time.Time{}, f)
 	})
 
 	err := srv.ListenAndServe()
@@ -18,18 +18,28 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/crypto/passphrase"
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
 	algodAcct "github.com/algorand/go-algorand/data/account"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
@@ -58,18 +68,59 @@ var (
 	partKeyOutDir      string
 	importDefault      bool
 	mnemonic           string
+	accountInfoJSON    bool
+	rekeyToAddress     string
+	newAccountCount    int
+	newDerived         bool
+	newDerivedIndex    uint64
+	partKeyFile        string
+	forcePartKeyDelete bool
+	keystoreFile       string
+	passphrasePrompt   bool
+	balanceAddrs       []string
+	balanceAll         bool
+	listPending        bool
+	vanityPrefix       string
+	vanitySuffix       string
+	vanityRegex        string
+	vanityThreads      int
+	vanityImport       bool
+
+	nonparticipatingNoPrompt bool
+	onlineAddrs              []string
+	onlineBatchFile          string
+	partkeyVerify            bool
+	multisigName             string
+	multisigOutFile          string
+	multisigInFile           string
+	listLimit                int
+	listOffset               int
+	listFilter               string
+	rewardsRounds            uint64
+	balanceWatchFlag         bool
+	balanceAbove             uint64
+	balanceBelow             uint64
+	partKeyProgress          bool
+	partkeyVerifyAddrs       []string
 )
 
 func init() {
 	accountCmd.AddCommand(newCmd)
+	accountCmd.AddCommand(vanityCmd)
 	accountCmd.AddCommand(deleteCmd)
 	accountCmd.AddCommand(listCmd)
+	accountCmd.AddCommand(repairListCmd)
 	accountCmd.AddCommand(renameCmd)
 	accountCmd.AddCommand(balanceCmd)
+	accountCmd.AddCommand(accountInfoCmd)
 	accountCmd.AddCommand(rewardsCmd)
 	accountCmd.AddCommand(changeOnlineCmd)
+	accountCmd.AddCommand(markNonparticipatingCmd)
+	accountCmd.AddCommand(rekeyCmd)
 	accountCmd.AddCommand(addParticipationKeyCmd)
 	accountCmd.AddCommand(listParticipationKeysCmd)
+	accountCmd.AddCommand(deleteParticipationKeyCmd)
+	accountCmd.AddCommand(installParticipationKeyCmd)
 	accountCmd.AddCommand(importCmd)
 	accountCmd.AddCommand(exportCmd)
 	accountCmd.AddCommand(importRootKeysCmd)
@@ -78,6 +129,8 @@ func init() {
 	accountMultisigCmd.AddCommand(newMultisigCmd)
 	accountMultisigCmd.AddCommand(deleteMultisigCmd)
 	accountMultisigCmd.AddCommand(infoMultisigCmd)
+	accountMultisigCmd.AddCommand(exportMultisigCmd)
+	accountMultisigCmd.AddCommand(importMultisigCmd)
 
 	accountCmd.AddCommand(renewParticipationKeyCmd)
 	accountCmd.AddCommand(renewAllParticipationKeyCmd)
@@ -92,6 +145,17 @@ func init() {
 
 	// New Account flag
 	newCmd.Flags().BoolVarP(&defaultAccount, "default", "f", false, "Set this account as the default one")
+	newCmd.Flags().IntVarP(&newAccountCount, "count", "n", 1, "Number of accounts to create; the name, if given, is used as a prefix")
+	newCmd.Flags().BoolVar(&newDerived, "derived", false, "Derive the account at --index from the wallet's master derivation key, instead of the next one in sequence")
+	newCmd.Flags().Uint64Var(&newDerivedIndex, "index", 0, "With --derived, index of the key to derive, starting at 1; with --count, the index of the first account")
+
+	// Vanity account flags
+	vanityCmd.Flags().StringVar(&vanityPrefix, "prefix", "", "Prefix the address must start with")
+	vanityCmd.Flags().StringVar(&vanitySuffix, "suffix", "", "Suffix the address must end with")
+	vanityCmd.Flags().StringVar(&vanityRegex, "regex", "", "Regular expression the address must match")
+	vanityCmd.Flags().IntVarP(&vanityThreads, "threads", "t", runtime.NumCPU(), "Number of keys to generate concurrently")
+	vanityCmd.Flags().BoolVar(&vanityImport, "import", false, "Import the found key into the wallet, rather than printing its mnemonic")
+	vanityCmd.Flags().BoolVarP(&defaultAccount, "default", "f", false, "With --import, set the account as the default one")
 
 	// Delete account flag
 	deleteCmd.Flags().StringVarP(&accountAddress, "addr", "a", "", "Address of account to delete")
@@ -100,6 +164,8 @@ func init() {
 	// New Multisig account flag
 	newMultisigCmd.Flags().Uint8VarP(&threshold, "threshold", "T", 1, "Number of signatures required to spend from this address")
 	newMultisigCmd.MarkFlagRequired("threshold")
+	newMultisigCmd.Flags().StringVar(&multisigName, "name", "", "Name to give the new multisig account")
+	newMultisigCmd.Flags().StringVar(&multisigOutFile, "output-msig-file", "", "Write the multisig account's version, threshold and public keys to this file, for use on another machine")
 
 	// Delete multisig account flag
 	deleteMultisigCmd.Flags().StringVarP(&accountAddress, "addr", "a", "", "Address of multisig account to delete")
@@ -107,27 +173,71 @@ func init() {
 
 	// Lookup info for multisig account flag
 	infoMultisigCmd.Flags().StringVarP(&accountAddress, "addr", "a", "", "Address of multisig account to look up")
-	infoMultisigCmd.MarkFlagRequired("addr")
+	infoMultisigCmd.Flags().StringVar(&multisigInFile, "msig-file", "", "Read the multisig account from this metadata file instead of the wallet")
+	infoMultisigCmd.Flags().StringVarP(&txFilename, "txfile", "t", "", "Read the multisig account from the multisig signatures of this transaction file instead of the wallet")
+
+	// Export and import multisig account flags
+	exportMultisigCmd.Flags().StringVarP(&accountAddress, "addr", "a", "", "Address of multisig account to export")
+	exportMultisigCmd.Flags().StringVarP(&multisigOutFile, "out", "o", "", "File to write the multisig account metadata to")
+	exportMultisigCmd.MarkFlagRequired("addr")
+	exportMultisigCmd.MarkFlagRequired("out")
+	importMultisigCmd.Flags().StringVarP(&multisigInFile, "infile", "i", "", "Multisig account metadata file, as written by export or new --output-msig-file")
+	importMultisigCmd.Flags().StringVar(&multisigName, "name", "", "Name to give the imported multisig account")
+	importMultisigCmd.MarkFlagRequired("infile")
+
+	// List flags
+	listCmd.Flags().BoolVar(&listPending, "pending", false, "Also show the number of pending transactions of each account and their effect on its balance")
+	listCmd.Flags().StringVar(&listFilter, "filter", "", "Only list the accounts whose name or address contains this text, ignoring case")
+	listCmd.Flags().IntVar(&listOffset, "offset", 0, "Number of accounts to skip, after filtering")
+	listCmd.Flags().IntVar(&listLimit, "limit", 0, "Maximum number of accounts to list; 0 lists them all")
 
 	// Balance flags
-	balanceCmd.Flags().StringVarP(&accountAddress, "address", "a", "", "Account address to retrieve balance (required)")
-	balanceCmd.MarkFlagRequired("address")
+	balanceCmd.Flags().StringArrayVarP(&balanceAddrs, "address", "a", nil, "Account address to retrieve the balance of, may be repeated")
+	balanceCmd.Flags().BoolVar(&balanceAll, "all", false, "Retrieve the balance of every account of the wallet")
+	balanceCmd.Flags().BoolVar(&balanceWatchFlag, "watch", false, "Keep watching the balance of the account, printing it again whenever it changes")
+	balanceCmd.Flags().Uint64Var(&balanceAbove, "above", 0, "With --watch, exit once the balance is at least this many microAlgos")
+	balanceCmd.Flags().Uint64Var(&balanceBelow, "below", 0, "With --watch, exit once the balance is at most this many microAlgos")
+
+	// Info flags
+	accountInfoCmd.Flags().StringVarP(&accountAddress, "address", "a", "", "Account address to retrieve the information of (required)")
+	accountInfoCmd.MarkFlagRequired("address")
+	accountInfoCmd.Flags().BoolVar(&accountInfoJSON, "json", false, "Print the account information as JSON")
 
 	// Rewards flags
 	rewardsCmd.Flags().StringVarP(&accountAddress, "address", "a", "", "Account address to retrieve rewards (required)")
 	rewardsCmd.MarkFlagRequired("address")
+	rewardsCmd.Flags().Uint64Var(&rewardsRounds, "rounds", 0, "Also report the rewards of the last number of rounds, scanning their blocks")
 
 	// changeOnlineStatus flags
-	changeOnlineCmd.Flags().StringVarP(&accountAddress, "address", "a", "", "Account address to change (required)")
-	changeOnlineCmd.MarkFlagRequired("address")
+	changeOnlineCmd.Flags().StringArrayVarP(&onlineAddrs, "address", "a", nil, "Account address to change, may be repeated")
+	changeOnlineCmd.Flags().StringVar(&onlineBatchFile, "batch", "", "File with the addresses of the accounts to change, one per line")
 	changeOnlineCmd.Flags().BoolVarP(&online, "online", "o", true, "Set this account to online or offline")
 	changeOnlineCmd.MarkFlagRequired("online")
 	changeOnlineCmd.Flags().Uint64VarP(&transactionFee, "fee", "f", 0, "The Fee to set on the status change transaction (defaults to suggested fee)")
 	changeOnlineCmd.Flags().Uint64VarP(&onlineFirstRound, "firstRound", "", 0, "FirstValid for the status change transaction (0 for current)")
 	changeOnlineCmd.Flags().Uint64VarP(&onlineValidRounds, "validRounds", "v", 0, "The validity period for the status change transaction")
 	changeOnlineCmd.Flags().StringVarP(&onlineTxFile, "txfile", "t", "", "Write status change transaction to this file")
+	changeOnlineCmd.Flags().StringVarP(&leaseBase64, "lease", "x", "", "Lease of the status change transaction (base64 encoded 32 bytes), held through its last valid round once committed")
 	changeOnlineCmd.Flags().BoolVarP(&noWaitAfterSend, "no-wait", "N", false, "Don't wait for transaction to commit")
 
+	// Mark nonparticipating flags
+	markNonparticipatingCmd.Flags().StringVarP(&accountAddress, "address", "a", "", "Account address to mark as nonparticipating (required)")
+	markNonparticipatingCmd.MarkFlagRequired("address")
+	markNonparticipatingCmd.Flags().Uint64VarP(&transactionFee, "fee", "f", 0, "The Fee to set on the status change transaction (defaults to suggested fee)")
+	markNonparticipatingCmd.Flags().Uint64VarP(&onlineFirstRound, "firstRound", "", 0, "FirstValid for the status change transaction (0 for current)")
+	markNonparticipatingCmd.Flags().Uint64VarP(&onlineValidRounds, "validRounds", "v", 0, "The validity period for the status change transaction")
+	markNonparticipatingCmd.Flags().StringVarP(&onlineTxFile, "txfile", "t", "", "Write status change transaction to this file")
+	markNonparticipatingCmd.Flags().BoolVarP(&noWaitAfterSend, "no-wait", "N", false, "Don't wait for transaction to commit")
+	markNonparticipatingCmd.Flags().BoolVarP(&nonparticipatingNoPrompt, "no-prompt", "y", false, "Don't ask for confirmation before broadcasting the transaction")
+
+	// rekey flags
+	rekeyCmd.Flags().StringVarP(&accountAddress, "address", "a", "", "Account address to rekey (required)")
+	rekeyCmd.MarkFlagRequired("address")
+	rekeyCmd.Flags().StringVarP(&rekeyToAddress, "to", "t", "", "Address whose key will authorize transactions from the account (required)")
+	rekeyCmd.MarkFlagRequired("to")
+	rekeyCmd.Flags().Uint64VarP(&transactionFee, "fee", "f", 0, "The Fee to set on the rekey transaction (defaults to suggested fee)")
+	rekeyCmd.Flags().BoolVarP(&noWaitAfterSend, "no-wait", "N", false, "Don't wait for transaction to commit")
+
 	// addParticipationKey flags
 	addParticipationKeyCmd.Flags().StringVarP(&accountAddress, "address", "a", "", "Account to associate with the generated partkey")
 	addParticipationKeyCmd.MarkFlagRequired("address")
@@ -137,13 +247,28 @@ func init() {
 	addParticipationKeyCmd.MarkFlagRequired("roundLastValid")
 	addParticipationKeyCmd.Flags().StringVarP(&partKeyOutDir, "outdir", "o", "", "Save participation key file to specified output directory to (for offline creation)")
 	addParticipationKeyCmd.Flags().Uint64VarP(&keyDilution, "keyDilution", "", 0, "Key dilution for two-level participation keys")
+	addParticipationKeyCmd.Flags().BoolVar(&partKeyProgress, "progress", false, "Report the progress of the key generation, with an estimate of the time left")
+
+	// deleteParticipationKey flags
+	deleteParticipationKeyCmd.Flags().StringVar(&partKeyFile, "file", "", "Participation key database to delete, as listed by listpartkeys")
+	deleteParticipationKeyCmd.Flags().StringVarP(&accountAddress, "address", "a", "", "Delete the participation keys of this account")
+	deleteParticipationKeyCmd.Flags().BoolVar(&forcePartKeyDelete, "force", false, "Also delete participation keys that have not expired yet")
+
+	// installParticipationKey flags
+	installParticipationKeyCmd.Flags().StringVar(&partKeyFile, "partkeyfile", "", "Participation key database to install")
+	installParticipationKeyCmd.MarkFlagRequired("partkeyfile")
 
 	// import flags
 	importCmd.Flags().BoolVarP(&importDefault, "default", "f", false, "Set this account as the default one")
 	importCmd.Flags().StringVarP(&mnemonic, "mnemonic", "m", "", "Mnemonic to import (will prompt otherwise)")
+	importCmd.Flags().StringVar(&keystoreFile, "keyfile", "", "Encrypted keystore file to import, as written by export --keyfile")
+	importCmd.Flags().BoolVar(&passphrasePrompt, "passphrase-prompt", false, "Prompt for the keystore passphrase, rather than reading it from the first line of stdin")
 	// export flags
 	exportCmd.Flags().StringVarP(&accountAddress, "address", "a", "", "Address of account to export")
 	exportCmd.MarkFlagRequired("address")
+	exportCmd.Flags().StringVar(&keystoreFile, "keyfile", "", "Write the key to this encrypted keystore file, rather than printing its mnemonic")
+	exportCmd.Flags().BoolVar(&passphrasePrompt, "passphrase-prompt", false, "Prompt for the keystore passphrase, rather than reading it from the first line of stdin")
+	addQRCodeFlags(exportCmd, "the mnemonic")
 	// importRootKeys flags
 	importRootKeysCmd.Flags().BoolVarP(&unencryptedWallet, "unencrypted-wallet", "u", false, "Import into the default unencrypted wallet, potentially creating it")
 
@@ -162,6 +287,9 @@ func init() {
 	renewAllParticipationKeyCmd.MarkFlagRequired("roundLastValid")
 	renewAllParticipationKeyCmd.Flags().Uint64VarP(&keyDilution, "keyDilution", "", 0, "Key dilution for two-level participation keys")
 	renewAllParticipationKeyCmd.Flags().BoolVarP(&noWaitAfterSend, "no-wait", "N", false, "Don't wait for transaction to commit")
+
+	partkeyInfoCmd.Flags().BoolVar(&partkeyVerify, "verify", false, "Compare every key with the participation key registered on chain for its account")
+	partkeyInfoCmd.Flags().StringArrayVarP(&partkeyVerifyAddrs, "address", "a", nil, "With --verify, also check this account, which may have no local keys; may be repeated")
 }
 
 var accountCmd = &cobra.Command{
@@ -234,9 +362,21 @@ var renameCmd = &cobra.Command{
 var newCmd = &cobra.Command{
 	Use:   "new",
 	Short: "Create a new account",
-	Long:  `Coordinates the creation of a new account with KMD. The name specified here is stored in a local configuration file and is only used by goal when working against that specific node instance.`,
+	Long:  `Coordinates the creation of a new account with KMD. The name specified here is stored in a local configuration file and is only used by goal when working against that specific node instance. With --count, creates that many accounts named prefix-0, prefix-1 and so on, where the prefix is the given name, and prints their addresses as a JSON array. With --derived, the account is the key at --index of the wallet's deterministic key sequence, so a wallet restored from its mnemonic can be rebuilt in the same order, e.g. with --derived --index 1 --count N.`,
 	Args:  cobra.RangeArgs(0, 1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if cmd.Flags().Changed("index") && !newDerived {
+			reportErrorln(errorIndexWithoutDerived)
+		}
+		if newDerived && !cmd.Flags().Changed("index") {
+			reportErrorln(errorDerivedNeedsIndex)
+		}
+
+		if cmd.Flags().Changed("count") {
+			newAccounts(args)
+			return
+		}
+
 		accountList := makeAccountsList(ensureSingleDataDir())
 		// Choose an account name
 		if len(args) == 0 {
@@ -262,7 +402,7 @@ var newCmd = &cobra.Command{
 
 		// Generate a new address in the default wallet
 		client := ensureKmdClient(dataDir)
-		genAddr, err := client.GenerateAddress(wh)
+		genAddr, err := generateAccountAddress(client, wh, 0)
 		if err != nil {
 			reportErrorf(errorRequestFail, err)
 		}
@@ -279,6 +419,257 @@ var newCmd = &cobra.Command{
 	},
 }
 
+// generateAccountAddress adds a key to the wallet, either the next one in
+// sequence or, with --derived, the one at newDerivedIndex plus offset
+func generateAccountAddress(client libgoal.Client, wh []byte, offset uint64) (string, error) {
+	if newDerived {
+		return client.DeriveAddress(wh, newDerivedIndex+offset)
+	}
+	return client.GenerateAddress(wh)
+}
+
+// newAccounts creates newAccountCount accounts in a single wallet session
+func newAccounts(args []string) {
+	if newAccountCount < 1 {
+		reportErrorf(errorAccountCount, newAccountCount)
+	}
+	if defaultAccount && newAccountCount > 1 {
+		reportErrorln(errorDefaultWithCount)
+	}
+
+	prefix := "Unnamed"
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+	if ok, err := isValidName(prefix); !ok {
+		reportErrorln(err)
+	}
+
+	dataDir := ensureSingleDataDir()
+	accountList := makeAccountsList(dataDir)
+	wh := ensureWalletHandle(dataDir, walletName)
+	client := ensureKmdClient(dataDir)
+
+	addrs := make([]string, 0, newAccountCount)
+	rows := make([][]string, 0, newAccountCount)
+	for i := 0; i < newAccountCount; i++ {
+		genAddr, err := generateAccountAddress(client, wh, uint64(i))
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+
+		name := accountList.getUnusedName(prefix)
+		accountList.addAccount(name, genAddr)
+		if defaultAccount {
+			accountList.setDefault(name)
+		}
+
+		addrs = append(addrs, genAddr)
+		rows = append(rows, []string{genAddr, name})
+	}
+
+	reportRows(addrs, []string{"ADDRESS", "NAME"}, rows, func() {
+		data, err := json.MarshalIndent(addrs, "", "  ")
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		fmt.Println(string(data))
+	})
+}
+
+var vanityCmd = &cobra.Command{
+	Use:   "vanity [name]",
+	Short: "Search for an account whose address matches a pattern",
+	Long:  `Generates keys on all the cores until the address matches the given --prefix, --suffix and --regex, reporting the throughput along the way. Every prefix or suffix character makes the search 32 times longer. The mnemonic of the found key is printed, or with --import, the key is imported into the wallet under the given name.`,
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		matcher, err := makeVanityMatcher(vanityPrefix, vanitySuffix, vanityRegex)
+		if err != nil {
+			reportErrorf(errorVanityPattern, err)
+		}
+		if vanityThreads < 1 {
+			reportErrorf(errorVanityThreads, vanityThreads)
+		}
+
+		// Check the account name before the search, rather than after it
+		var dataDir string
+		var accountList *AccountsList
+		if vanityImport {
+			dataDir = ensureSingleDataDir()
+			accountList = makeAccountsList(dataDir)
+			if len(args) == 0 {
+				accountName = accountList.getUnnamed()
+			} else {
+				accountName = args[0]
+			}
+			if ok, err := isValidName(accountName); !ok {
+				reportErrorln(err)
+			}
+			if accountList.isTaken(accountName) {
+				reportErrorf(errorNameAlreadyTaken, accountName)
+			}
+		} else if defaultAccount || len(args) > 0 {
+			reportErrorln(errorVanityNameWithoutImport)
+		}
+
+		reportInfof(infoVanitySearch, vanityThreads, matcher)
+		var attempts uint64
+		start := time.Now()
+		done := make(chan struct{})
+		go func() {
+			ticker := time.NewTicker(vanityProgressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					n := atomic.LoadUint64(&attempts)
+					reportInfof(infoVanityProgress, n, float64(n)/time.Since(start).Seconds())
+				}
+			}
+		}()
+		seed, addr := searchVanityAddress(matcher, vanityThreads, &attempts)
+		close(done)
+
+		result := vanityResult{
+			Address:       addr,
+			Attempts:      atomic.LoadUint64(&attempts),
+			KeysPerSecond: float64(atomic.LoadUint64(&attempts)) / time.Since(start).Seconds(),
+		}
+		if vanityImport {
+			client := ensureKmdClient(dataDir)
+			wh := ensureWalletHandle(dataDir, walletName)
+			if _, err := client.ImportKey(wh, seed[:]); err != nil {
+				reportErrorf(errorRequestFail, err)
+			}
+			accountList.addAccount(accountName, addr)
+			if defaultAccount {
+				accountList.setDefault(accountName)
+			}
+			result.Name = accountName
+		} else {
+			result.Mnemonic, err = passphrase.KeyToMnemonic(seed[:])
+			if err != nil {
+				reportErrorf(errorRequestFail, err)
+			}
+		}
+
+		reportResult(result, addr, func() {
+			fmt.Printf(infoVanityFound+"\n", addr, result.Attempts, result.KeysPerSecond)
+			if vanityImport {
+				fmt.Printf(infoImportedKey+"\n", addr)
+			} else {
+				fmt.Printf(infoVanityMnemonic+"\n", result.Mnemonic)
+			}
+		})
+	},
+}
+
+// vanityProgressInterval is how often the vanity search reports its throughput
+const vanityProgressInterval = 5 * time.Second
+
+// vanityResult is what `goal account vanity` reports in the structured output modes
+type vanityResult struct {
+	Address       string  `json:"address"`
+	Name          string  `json:"name,omitempty"`
+	Mnemonic      string  `json:"mnemonic,omitempty"`
+	Attempts      uint64  `json:"attempts"`
+	KeysPerSecond float64 `json:"keysPerSecond"`
+}
+
+// vanityMatcher checks addresses against the patterns of the vanity search
+type vanityMatcher struct {
+	prefix string
+	suffix string
+	re     *regexp.Regexp
+}
+
+// base32Alphabet is the set of characters addresses are made of
+const base32Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+
+// makeVanityMatcher validates the patterns; the prefix and suffix are case
+// insensitive, since addresses are upper case.
+func makeVanityMatcher(prefix, suffix, pattern string) (m vanityMatcher, err error) {
+	if prefix == "" && suffix == "" && pattern == "" {
+		return m, fmt.Errorf("specify a --prefix, a --suffix or a --regex")
+	}
+	m.prefix = strings.ToUpper(prefix)
+	m.suffix = strings.ToUpper(suffix)
+	for _, s := range []string{m.prefix, m.suffix} {
+		if i := strings.IndexFunc(s, func(r rune) bool { return !strings.ContainsRune(base32Alphabet, r) }); i >= 0 {
+			return m, fmt.Errorf("'%c' can't appear in an address, which is made of the characters %s", s[i], base32Alphabet)
+		}
+	}
+	if len(m.prefix)+len(m.suffix) > len(basics.Address{}.GetChecksumAddress().String()) {
+		return m, fmt.Errorf("the prefix and suffix are longer than an address")
+	}
+	if pattern != "" {
+		m.re, err = regexp.Compile(pattern)
+	}
+	return
+}
+
+func (m vanityMatcher) matches(addr string) bool {
+	return strings.HasPrefix(addr, m.prefix) && strings.HasSuffix(addr, m.suffix) && (m.re == nil || m.re.MatchString(addr))
+}
+
+func (m vanityMatcher) String() string {
+	parts := []string{}
+	if m.prefix != "" {
+		parts = append(parts, fmt.Sprintf("prefix %s", m.prefix))
+	}
+	if m.suffix != "" {
+		parts = append(parts, fmt.Sprintf("suffix %s", m.suffix))
+	}
+	if m.re != nil {
+		parts = append(parts, fmt.Sprintf("regex %s", m.re))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// searchVanityAddress generates keys on the given number of goroutines until
+// one of their addresses matches, counting the generated keys in attempts.
+func searchVanityAddress(matcher vanityMatcher, threads int, attempts *uint64) (crypto.Seed, string) {
+	type match struct {
+		seed crypto.Seed
+		addr string
+	}
+	found := make(chan match, threads)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for t := 0; t < threads; t++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var seed crypto.Seed
+			for {
+				// Count in batches to keep the goroutines off the shared counter
+				for i := 0; i < 64; i++ {
+					crypto.RandBytes(seed[:])
+					secrets := crypto.GenerateSignatureSecrets(seed)
+					addr := basics.Address(secrets.SignatureVerifier).GetChecksumAddress().String()
+					if matcher.matches(addr) {
+						atomic.AddUint64(attempts, uint64(i+1))
+						found <- match{seed: seed, addr: addr}
+						return
+					}
+				}
+				atomic.AddUint64(attempts, 64)
+				select {
+				case <-stop:
+					return
+				default:
+				}
+			}
+		}()
+	}
+	m := <-found
+	close(stop)
+	wg.Wait()
+	return m.seed, m.addr
+}
+
 var deleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete an account",
@@ -302,12 +693,29 @@ var deleteCmd = &cobra.Command{
 var newMultisigCmd = &cobra.Command{
 	Use:   "new [addr1 addr2 ...]",
 	Short: "Create a new multisig account",
-	Long:  `Create a new multisig account from a list of existing non-multisig addresses`,
+	Long:  `Create a new multisig account from a list of existing non-multisig addresses. Account names may be given in place of addresses, and the two may be mixed.`,
 	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		dataDir := ensureSingleDataDir()
 		accountList := makeAccountsList(dataDir)
 
+		// Check the account name before creating the account
+		name := multisigName
+		if name == "" {
+			name = accountList.getUnnamed()
+		}
+		if ok, err := isValidName(name); !ok {
+			reportErrorln(err)
+		}
+		if accountList.isTaken(name) {
+			reportErrorf(errorNameAlreadyTaken, name)
+		}
+
+		addrs, err := resolveMultisigMembers(accountList, args)
+		if err != nil {
+			reportErrorln(err.Error())
+		}
+
 		// Get a wallet handle to the default wallet
 		client := ensureKmdClient(dataDir)
 
@@ -316,7 +724,7 @@ var newMultisigCmd = &cobra.Command{
 
 		// Detect duplicate PKs
 		duplicateDetector := make(map[string]int)
-		for _, addrStr := range args {
+		for _, addrStr := range addrs {
 			duplicateDetector[addrStr]++
 		}
 		duplicatesDetected := false
@@ -330,18 +738,40 @@ var newMultisigCmd = &cobra.Command{
 			reportWarnln(warnMultisigDuplicatesDetected)
 		}
 		// Generate a new address in the default wallet
-		addr, err := client.CreateMultisigAccount(wh, threshold, args)
+		addr, err := client.CreateMultisigAccount(wh, threshold, addrs)
 		if err != nil {
 			reportErrorf(errorRequestFail, err)
 		}
 
 		// Add account to list
-		accountList.addAccount(accountList.getUnnamed(), addr)
+		accountList.addAccount(name, addr)
 
 		reportInfof(infoCreatedNewAccount, addr)
+
+		if multisigOutFile != "" {
+			info := lookupMultisigAccount(client, wh, addr)
+			if err := writeMultisigFile(multisigOutFile, info); err != nil {
+				reportErrorf(fileWriteError, multisigOutFile, err)
+			}
+			reportInfof(infoMultisigFileWritten, multisigOutFile)
+		}
 	},
 }
 
+// resolveMultisigMembers returns the addresses of the multisig members, which may be given as addresses or as
+// account names in the list
+func resolveMultisigMembers(accountList *AccountsList, members []string) ([]string, error) {
+	addrs := make([]string, len(members))
+	for i, member := range members {
+		addr := accountList.getAddressByName(member)
+		if _, err := basics.UnmarshalChecksumAddress(addr); err != nil {
+			return nil, fmt.Errorf(errorMultisigMember, member)
+		}
+		addrs[i] = addr
+	}
+	return addrs, nil
+}
+
 var deleteMultisigCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a multisig account",
@@ -365,177 +795,936 @@ var deleteMultisigCmd = &cobra.Command{
 var infoMultisigCmd = &cobra.Command{
 	Use:   "info",
 	Short: "Print information about a multisig account",
+	Long:  `Print the version, threshold and public keys of a multisig account. They are looked up in the wallet, or without a wallet, read from a metadata file written by 'goal account multisig export', or rebuilt from the multisig signature of a signed or partially-signed transaction. With a transaction file, --addr picks the multisig account among those of its transactions.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		var info multisigAccountInfo
+		switch {
+		case multisigInFile != "" && txFilename != "":
+			reportErrorln(errorMultisigInfoSources)
+		case multisigInFile != "":
+			var err error
+			info, err = readMultisigFile(multisigInFile)
+			if err != nil {
+				reportErrorf(fileReadError, multisigInFile, err)
+			}
+			if accountAddress != "" && accountAddress != info.Address {
+				reportErrorf(errorMultisigInfoAddress, multisigInFile, info.Address)
+			}
+		case txFilename != "":
+			var err error
+			info, err = multisigInfoFromTxns(readSignedTxnFile(txFilename), accountAddress)
+			if err != nil {
+				reportErrorln(err.Error())
+			}
+		case accountAddress == "":
+			reportErrorln(errorMultisigInfoNoAddr)
+		default:
+			dataDir := ensureSingleDataDir()
+			client := ensureKmdClient(dataDir)
+			wh := ensureWalletHandle(dataDir, walletName)
+			info = lookupMultisigAccount(client, wh, accountAddress)
+		}
+
+		reportResult(info, "", func() {
+			if accountAddress == "" {
+				fmt.Printf("Address: %s\n", info.Address)
+			}
+			fmt.Printf("Version: %d\n", info.Version)
+			fmt.Printf("Threshold: %d\n", info.Threshold)
+			fmt.Printf("Public keys:\n")
+			for _, pk := range info.PKs {
+				fmt.Printf("  %s\n", pk)
+			}
+		})
+	},
+}
+
+// multisigAccountInfo is the result of goal account multisig info.
+type multisigAccountInfo struct {
+	Address   string   `json:"address"`
+	Version   uint8    `json:"version"`
+	Threshold uint8    `json:"threshold"`
+	PKs       []string `json:"pks"`
+}
+
+var exportMultisigCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the metadata of a multisig account to a file",
+	Long:  `Write the version, threshold and public keys of a multisig account in the wallet to a file, so that a cosigner can import the account into a wallet on another machine and sign for it there.`,
 	Args:  validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
 		dataDir := ensureSingleDataDir()
 		client := ensureKmdClient(dataDir)
 		wh := ensureWalletHandle(dataDir, walletName)
 
-		multisigInfo, err := client.LookupMultisigAccount(wh, accountAddress)
-		if err != nil {
-			reportErrorf(errorRequestFail, err)
-		}
-
-		fmt.Printf("Version: %d\n", multisigInfo.Version)
-		fmt.Printf("Threshold: %d\n", multisigInfo.Threshold)
-		fmt.Printf("Public keys:\n")
-		for _, pk := range multisigInfo.PKs {
-			fmt.Printf("  %s\n", pk)
+		info := lookupMultisigAccount(client, wh, accountAddress)
+		if err := writeMultisigFile(multisigOutFile, info); err != nil {
+			reportErrorf(fileWriteError, multisigOutFile, err)
 		}
+		reportInfof(infoMultisigFileWritten, multisigOutFile)
 	},
 }
 
-var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "Show the list of Algorand accounts on this machine",
-	Long:  `Show the list of Algorand accounts on this machine. Also indicates whether the account is [offline] or [online], and if the account is the default account for goal.`,
+var importMultisigCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a multisig account from a metadata file",
+	Long:  `Add a multisig account to the wallet from the metadata file written by 'goal account multisig export' or 'goal account multisig new --output-msig-file' on another machine. The wallet can then add the signatures of its keys to the account's transactions.`,
 	Args:  validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
 		dataDir := ensureSingleDataDir()
 		accountList := makeAccountsList(dataDir)
 
-		// Get a wallet handle to the specified wallet
-		wh := ensureWalletHandle(dataDir, walletName)
+		info, err := readMultisigFile(multisigInFile)
+		if err != nil {
+			reportErrorf(fileReadError, multisigInFile, err)
+		}
+
+		name := multisigName
+		if name == "" {
+			name = accountList.getUnnamed()
+		}
+		if ok, err := isValidName(name); !ok {
+			reportErrorln(err)
+		}
+		if accountList.isTaken(name) {
+			reportErrorf(errorNameAlreadyTaken, name)
+		}
 
-		// List the addresses in the wallet
 		client := ensureKmdClient(dataDir)
-		addrs, err := client.ListAddressesWithInfo(wh)
+		wh := ensureWalletHandle(dataDir, walletName)
+
+		addr, err := client.CreateMultisigAccount(wh, info.Threshold, info.PKs)
 		if err != nil {
 			reportErrorf(errorRequestFail, err)
 		}
+		accountList.addAccount(name, addr)
 
-		// Special response if there are no addresses
-		if len(addrs) == 0 {
-			reportInfoln(infoNoAccounts)
-			os.Exit(0)
+		reportInfof(infoImportedKey, addr)
+	},
+}
+
+// lookupMultisigAccount returns the metadata of the multisig account in the wallet
+func lookupMultisigAccount(client libgoal.Client, wh []byte, addr string) multisigAccountInfo {
+	multisigInfo, err := client.LookupMultisigAccount(wh, addr)
+	if err != nil {
+		reportErrorf(errorRequestFail, err)
+	}
+	return multisigAccountInfo{
+		Address:   addr,
+		Version:   multisigInfo.Version,
+		Threshold: multisigInfo.Threshold,
+		PKs:       multisigInfo.PKs,
+	}
+}
+
+// multisigInfoFromTxns rebuilds the metadata of a multisig account from the multisig signature of the first
+// transaction signed by it, or by any multisig account if addr is empty
+func multisigInfoFromTxns(txns []transactions.SignedTxn, addr string) (info multisigAccountInfo, err error) {
+	for _, stxn := range txns {
+		if stxn.Msig.Blank() {
+			continue
+		}
+		digest, err := crypto.MultisigAddrGenWithSubsigs(stxn.Msig.Version, stxn.Msig.Threshold, stxn.Msig.Subsigs)
+		if err != nil {
+			return info, fmt.Errorf(errorInvalidMultisigTxn, stxn.ID().String(), err)
+		}
+		msigAddr := basics.Address(digest).GetUserAddress()
+		if addr != "" && msigAddr != addr {
+			continue
+		}
+		info = multisigAccountInfo{
+			Address:   msigAddr,
+			Version:   stxn.Msig.Version,
+			Threshold: stxn.Msig.Threshold,
+		}
+		for _, subsig := range stxn.Msig.Subsigs {
+			info.PKs = append(info.PKs, basics.Address(subsig.Key).GetUserAddress())
+		}
+		return info, nil
+	}
+	if addr != "" {
+		return info, fmt.Errorf(errorMultisigNotInTxns, addr)
+	}
+	return info, fmt.Errorf(errorNoMultisigInTxns)
+}
+
+// writeMultisigFile writes the multisig account metadata to filename, as JSON
+func writeMultisigFile(filename string, info multisigAccountInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// readMultisigFile reads the multisig account metadata from filename, checking that the version, threshold and
+// public keys make up the address it gives
+func readMultisigFile(filename string) (info multisigAccountInfo, err error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(data, &info); err != nil {
+		return
+	}
+	pks := make([]crypto.PublicKey, len(info.PKs))
+	for i, pk := range info.PKs {
+		addr, err := basics.UnmarshalChecksumAddress(pk)
+		if err != nil {
+			return info, err
+		}
+		pks[i] = crypto.PublicKey(addr)
+	}
+	digest, err := crypto.MultisigAddrGen(info.Version, info.Threshold, pks)
+	if err != nil {
+		return
+	}
+	if addr := basics.Address(digest).GetUserAddress(); addr != info.Address {
+		err = fmt.Errorf(errorMultisigFileAddress, info.Address, addr)
+	}
+	return
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the list of Algorand accounts on this machine",
+	Long:  `Show the list of Algorand accounts on this machine. Also indicates whether the account is [offline] or [online], and if the account is the default account for goal. With several data directories, the accounts of each of them are listed in turn. For large wallets, --filter, --offset and --limit select the accounts to list, in the wallet's order, and only those are looked up.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		if listOffset < 0 || listLimit < 0 {
+			reportErrorln(errorListPaging)
 		}
+		onDataDirsReportingErrors(listAccounts)
+	},
+}
+
+func listAccounts(dataDir string) error {
+	accountList := makeAccountsList(dataDir)
+
+	// Get a wallet handle to the specified wallet
+	wh := ensureWalletHandle(dataDir, walletName)
+
+	// List the addresses in the wallet
+	client := ensureKmdClient(dataDir)
+	addrs, err := client.ListAddressesWithInfo(wh)
+	if err != nil {
+		return fmt.Errorf(errorRequestFail, err)
+	}
+
+	// Special response if there are no addresses
+	if len(addrs) == 0 {
+		reportInfoln(infoNoAccounts)
+		return nil
+	}
+	addrs = selectListedAddresses(addrs, accountList, listFilter, listOffset, listLimit)
+	if len(addrs) == 0 {
+		reportInfoln(infoNoMatchingAccounts)
+		return nil
+	}
 
-		// For each address, request information about it from algod
+	var pending map[string]*listedPending
+	columns := listedAccountColumns
+	if listPending {
+		pool, err := client.GetPendingTransactions(0)
+		if err != nil {
+			return fmt.Errorf(errorRequestFail, err)
+		}
+		pending = make(map[string]*listedPending, len(addrs))
 		for _, addr := range addrs {
-			response, _ := client.AccountInformation(addr.Addr)
-			// it's okay to procede with out algod info
+			pending[addr.Addr] = &listedPending{}
+		}
+		addPendingEffects(pending, pool.TruncatedTxns.Transactions)
+		columns = append(append([]string{}, columns...), listedPendingColumns...)
+	}
 
-			// Display this information to the user
-			if addr.Multisig {
+	// Request information about the addresses from algod, a few at a time
+	accounts := make([]listedAccount, len(addrs))
+	errs := make([]error, len(addrs))
+	queries := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < balanceQueryParallelism && i < len(addrs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queries {
+				addr := addrs[i]
+				response, _ := client.AccountInformation(addr.Addr)
+				// it's okay to procede with out algod info
+
+				if !addr.Multisig {
+					accounts[i] = accountList.listAccount(addr.Addr, response, nil)
+					continue
+				}
 				multisigInfo, err := client.LookupMultisigAccount(wh, addr.Addr)
 				if err != nil {
-					fmt.Println("multisig lookup err")
-					reportErrorf(errorRequestFail, err)
+					errs[i] = err
+					continue
 				}
+				accounts[i] = accountList.listAccount(addr.Addr, response, &multisigInfo)
+			}
+		}()
+	}
+	for i := range addrs {
+		queries <- i
+	}
+	close(queries)
+	wg.Wait()
 
-				accountList.outputAccount(addr.Addr, response, &multisigInfo)
-			} else {
-				accountList.outputAccount(addr.Addr, response, nil)
+	rows := make([][]string, 0, len(addrs))
+	for i := range accounts {
+		if errs[i] != nil {
+			return fmt.Errorf(errorRequestFail, errs[i])
+		}
+		accounts[i].Pending = pending[addrs[i].Addr]
+		rows = append(rows, accounts[i].row())
+	}
+
+	// Display this information to the user
+	reportRows(accounts, columns, rows, func() {
+		for _, account := range accounts {
+			fmt.Println(account.text())
+		}
+	})
+	return nil
+}
+
+// selectListedAddresses returns the addresses goal account list shows: those whose address or account name contains
+// filter, ignoring case, past the first offset of them and up to limit of them if limit isn't 0
+func selectListedAddresses(addrs []libgoal.ListedAddress, accountList *AccountsList, filter string, offset, limit int) []libgoal.ListedAddress {
+	if filter != "" {
+		filter = strings.ToLower(filter)
+		var matching []libgoal.ListedAddress
+		for _, addr := range addrs {
+			if strings.Contains(strings.ToLower(addr.Addr), filter) || strings.Contains(strings.ToLower(accountList.Accounts[addr.Addr]), filter) {
+				matching = append(matching, addr)
 			}
 		}
+		addrs = matching
+	}
+	if offset >= len(addrs) {
+		return nil
+	}
+	addrs = addrs[offset:]
+	if limit > 0 && limit < len(addrs) {
+		addrs = addrs[:limit]
+	}
+	return addrs
+}
+
+// accountListRepair reports the changes goal account repair-list made to the account list.
+type accountListRepair struct {
+	Fixed   []string `json:"fixed"`
+	Removed []string `json:"removed"`
+	Added   []string `json:"added"`
+}
+
+var repairListCmd = &cobra.Command{
+	Use:   "repair-list",
+	Short: "Reconcile the list of account names with the wallets",
+	Long:  `Reconcile the list of account names goal keeps with the accounts of all the kmd wallets: the names of the accounts that are in no wallet are removed, the accounts without a name are given one, and invalid or duplicate names are fixed. The wallets may ask for their password.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		onDataDirsReportingErrors(repairAccountList)
 	},
 }
 
+func repairAccountList(dataDir string) error {
+	accountList := makeAccountsList(dataDir)
+	kmd := ensureKmdClient(dataDir)
+	wallets, err := kmd.ListWallets()
+	if err != nil {
+		return fmt.Errorf(errCouldNotListWallets, err)
+	}
+
+	walletAddresses := make(map[string]bool)
+	for _, wallet := range wallets {
+		wh, _, err := getWalletHandleMaybePassword(dataDir, wallet.Name, false)
+		if err != nil {
+			return err
+		}
+		addrs, err := kmd.ListAddresses(wh)
+		if err != nil {
+			return fmt.Errorf(errorRequestFail, err)
+		}
+		for _, addr := range addrs {
+			walletAddresses[addr] = true
+		}
+	}
+
+	repair := accountListRepair{Fixed: []string{}, Removed: []string{}, Added: []string{}}
+	repair.Fixed = append(repair.Fixed, accountList.normalize()...)
+	removed, added := accountList.reconcile(walletAddresses)
+	repair.Removed = append(repair.Removed, removed...)
+	repair.Added = append(repair.Added, added...)
+	if accountList.DefaultAccount == "" && len(accountList.Accounts) == 1 {
+		for address := range accountList.Accounts {
+			accountList.DefaultAccount = address
+		}
+	}
+	accountList.dumpList()
+
+	changes := len(repair.Fixed) + len(repair.Removed) + len(repair.Added)
+	reportResult(repair, fmt.Sprintf("%d", changes), func() {
+		for _, fix := range repair.Fixed {
+			fmt.Println(fix)
+		}
+		for _, removed := range repair.Removed {
+			fmt.Printf(infoAccountListRemoved+"\n", removed)
+		}
+		for _, added := range repair.Added {
+			fmt.Printf(infoAccountListAdded+"\n", added)
+		}
+		if changes == 0 {
+			fmt.Println(infoAccountListRepaired)
+		}
+	})
+	return nil
+}
+
 var balanceCmd = &cobra.Command{
 	Use:   "balance",
-	Short: "Retrieve the balance for the specified account, in microAlgos",
-	Long:  `Retrieve the balance for the specified account, in microAlgos`,
+	Short: "Retrieve the balance for the specified accounts, in microAlgos",
+	Long:  `Retrieve the balance for the specified accounts, in microAlgos. With several accounts, or --all for every account of the wallet, the node is queried for all of them at once, and their balances are listed along with their total. With --watch, the balance of a single account is printed again, along with its round, every time a new round changes it; --above and --below then make goal exit once the balance crosses a threshold.`,
 	Args:  validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
-		dataDir := ensureSingleDataDir()
-		client := ensureAlgodClient(dataDir)
-		response, err := client.AccountInformation(accountAddress)
+		if len(balanceAddrs) == 0 && !balanceAll {
+			reportErrorln(errorBalanceNoAccount)
+		}
+		addrs := make([]string, len(balanceAddrs))
+		for i, addr := range balanceAddrs {
+			addrs[i] = resolveAccountName(addr)
+		}
+
+		watch := balanceWatch{
+			above:    balanceAbove,
+			below:    balanceBelow,
+			hasAbove: cmd.Flags().Changed("above"),
+			hasBelow: cmd.Flags().Changed("below"),
+		}
+		if !balanceWatchFlag {
+			if watch.hasAbove || watch.hasBelow {
+				reportErrorln(errorBalanceThresholdNoWatch)
+			}
+		} else {
+			if len(addrs) != 1 || balanceAll {
+				reportErrorln(errorBalanceWatchOne)
+			}
+			if err := watchBalance(ensureSingleDataDir(), addrs[0], watch); err != nil {
+				reportErrorln(err.Error())
+			}
+			return
+		}
+
+		if len(addrs) == 1 && !balanceAll {
+			onDataDirsReportingErrors(func(dataDir string) error {
+				client := ensureAlgodClient(dataDir)
+				response, err := client.AccountInformation(addrs[0])
+				if err != nil {
+					return fmt.Errorf(errorRequestFail, err)
+				}
+
+				reportResult(response, fmt.Sprintf("%d", response.Amount), func() {
+					fmt.Printf("%v microAlgos\n", response.Amount)
+				})
+				return nil
+			})
+			return
+		}
+
+		onDataDirsReportingErrors(func(dataDir string) error {
+			return reportBalances(dataDir, addrs)
+		})
+	},
+}
+
+// balanceWatch holds the thresholds at which goal account balance --watch stops watching
+type balanceWatch struct {
+	above, below       uint64
+	hasAbove, hasBelow bool
+}
+
+// reached tells whether the balance crossed one of the thresholds
+func (watch balanceWatch) reached(amount uint64) bool {
+	return (watch.hasAbove && amount >= watch.above) || (watch.hasBelow && amount <= watch.below)
+}
+
+// balanceUpdate is a balance that goal account balance --watch reports, as of a round
+type balanceUpdate struct {
+	Round  uint64 `json:"round"`
+	Amount uint64 `json:"amount"`
+}
+
+// watchBalance reports the balance of addr as of the latest round, then again every time a new round changes it,
+// until it crosses a threshold of watch
+func watchBalance(dataDir string, addr string, watch balanceWatch) error {
+	client := ensureAlgodClient(dataDir)
+	stat, err := client.Status()
+	if err != nil {
+		return fmt.Errorf(errorRequestFail, err)
+	}
+	reported := false
+	var last uint64
+	for {
+		response, err := client.AccountInformation(addr)
 		if err != nil {
-			reportErrorf(errorRequestFail, err)
+			return fmt.Errorf(errorRequestFail, err)
+		}
+		if !reported || response.Amount != last {
+			// Accounts that never held anything have no round
+			update := balanceUpdate{Round: response.Round, Amount: response.Amount}
+			if update.Round == 0 {
+				update.Round = stat.LastRound
+			}
+			reportResult(update, fmt.Sprintf("%d", update.Amount), func() {
+				fmt.Printf(infoBalanceUpdate+"\n", update.Round, update.Amount)
+			})
+			reported, last = true, response.Amount
+		}
+		if watch.reached(response.Amount) {
+			return nil
+		}
+
+		stat, err = client.WaitForRound(stat.LastRound + 1)
+		if err != nil {
+			return fmt.Errorf(errorRequestFail, err)
+		}
+	}
+}
+
+// balanceQueryParallelism is the number of balances goal account balance and goal account list query the node for
+// at once
+const balanceQueryParallelism = 16
+
+// accountBalance is the balance of one of the accounts goal account balance reports on
+type accountBalance struct {
+	Address string `json:"address"`
+	Name    string `json:"name,omitempty"`
+	Amount  uint64 `json:"amount"`
+	// Error is the reason the balance couldn't be retrieved, empty if it was
+	Error string `json:"error,omitempty"`
+}
+
+// accountBalances is what goal account balance reports on several accounts
+type accountBalances struct {
+	Accounts []accountBalance `json:"accounts"`
+	Total    uint64           `json:"total"`
+}
+
+// reportBalances reports the balances of addrs, and with --all of every account of the wallet as well
+func reportBalances(dataDir string, addrs []string) error {
+	if balanceAll {
+		wh := ensureWalletHandle(dataDir, walletName)
+		kmd := ensureKmdClient(dataDir)
+		walletAddrs, err := kmd.ListAddresses(wh)
+		if err != nil {
+			return fmt.Errorf(errorRequestFail, err)
+		}
+		addrs = append(append([]string{}, addrs...), walletAddrs...)
+	}
+
+	accountList := makeAccountsList(dataDir)
+	balances := accountBalances{Accounts: make([]accountBalance, 0, len(addrs))}
+	seen := make(map[string]bool)
+	for _, addr := range addrs {
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		balances.Accounts = append(balances.Accounts, accountBalance{Address: addr, Name: accountList.Accounts[addr]})
+	}
+
+	client := ensureAlgodClient(dataDir)
+	queries := make(chan *accountBalance)
+	var wg sync.WaitGroup
+	for i := 0; i < balanceQueryParallelism && i < len(balances.Accounts); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for balance := range queries {
+				response, err := client.AccountInformation(balance.Address)
+				if err != nil {
+					balance.Error = err.Error()
+					continue
+				}
+				balance.Amount = response.Amount
+			}
+		}()
+	}
+	for i := range balances.Accounts {
+		queries <- &balances.Accounts[i]
+	}
+	close(queries)
+	wg.Wait()
+
+	failed := 0
+	rows := make([][]string, 0, len(balances.Accounts)+1)
+	for _, balance := range balances.Accounts {
+		if balance.Error != "" {
+			failed++
+			reportWarnf(errorAccountBalance, balance.Address, balance.Error)
+			rows = append(rows, []string{balance.Address, balance.Name, "-"})
+			continue
 		}
+		balances.Total += balance.Amount
+		rows = append(rows, []string{balance.Address, balance.Name, fmt.Sprintf("%d", balance.Amount)})
+	}
+	if !report.quiet {
+		rows = append(rows, []string{"TOTAL", "", fmt.Sprintf("%d", balances.Total)})
+	}
+
+	reportRows(balances, []string{"ADDRESS", "NAME", "MICROALGOS"}, rows, func() {
+		rowFormat := "%-58s  %-20s  %20s\n"
+		fmt.Printf(rowFormat, "Address", "Name", "microAlgos")
+		for _, row := range rows {
+			fmt.Printf(rowFormat, row[0], row[1], row[2])
+		}
+	})
+	if failed > 0 {
+		return fmt.Errorf(errorBalancesIncomplete, failed, len(balances.Accounts))
+	}
+	return nil
+}
 
-		fmt.Printf("%v microAlgos\n", response.Amount)
+// accountInfo is the state of an account, along with the effect of its pending transactions.
+type accountInfo struct {
+	models.Account
+	PendingTxns uint64 `json:"pendingtxns"`
+	// PendingSent is the amount the pending transactions send to other accounts, closing amounts excluded
+	PendingSent uint64 `json:"pendingsent"`
+	PendingFees uint64 `json:"pendingfees"`
+	// PendingClose is the account the pending transactions close the account to, if any
+	PendingClose string `json:"pendingclose,omitempty"`
+}
+
+var accountInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Retrieve the complete information of the specified account",
+	Long:  `Retrieve the complete information of the specified account: its balance and rewards, its online status and participation key, and the effect of its transactions pending in the transaction pool`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		onDataDirsReportingErrors(func(dataDir string) error {
+			client := ensureAlgodClient(dataDir)
+			response, err := client.AccountInformation(accountAddress)
+			if err != nil {
+				return fmt.Errorf(errorRequestFail, err)
+			}
+			pending, err := client.SenderPendingTransactions(accountAddress, 0)
+			if err != nil {
+				return fmt.Errorf(errorRequestFail, err)
+			}
+
+			info := accountInfo{Account: response, PendingTxns: pending.SenderTxns}
+			for _, txn := range pending.TruncatedTxns.Transactions {
+				info.PendingFees += txn.Fee
+				if txn.Payment == nil {
+					continue
+				}
+				if txn.Payment.To != info.Address {
+					info.PendingSent += txn.Payment.Amount
+				}
+				if txn.Payment.CloseRemainderTo != "" {
+					info.PendingClose = txn.Payment.CloseRemainderTo
+				}
+			}
+
+			if accountInfoJSON && !report.structured() {
+				data, err := json.MarshalIndent(info, "", "  ")
+				if err != nil {
+					return fmt.Errorf(errorRequestFail, err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+			reportResult(info, info.Address, func() {
+				printAccountInfo(info)
+			})
+			return nil
+		})
 	},
 }
 
+func printAccountInfo(info accountInfo) {
+	fmt.Printf("Address: %s\n", info.Address)
+	fmt.Printf("Round: %d\n", info.Round)
+	fmt.Printf("Balance: %d microAlgos\n", info.Amount)
+	fmt.Printf("Pending rewards: %d microAlgos\n", info.PendingRewards)
+	fmt.Printf("Total rewards: %d microAlgos\n", info.Rewards)
+	fmt.Printf("Status: %s\n", info.Status)
+	if info.AuthAddr != "" {
+		fmt.Printf("Authorized by: %s\n", info.AuthAddr)
+	}
+	if part := info.Participation; part != nil {
+		fmt.Printf("Participation key: %s\n", base64.StdEncoding.EncodeToString(part.ParticipationPK))
+		fmt.Printf("Selection key: %s\n", base64.StdEncoding.EncodeToString(part.VRFPK))
+		fmt.Printf("Participation key validity: rounds %d to %d\n", part.VoteFirst, part.VoteLast)
+		fmt.Printf("Key dilution: %d\n", part.VoteKeyDilution)
+	} else {
+		fmt.Printf("Participation key: none\n")
+	}
+	if len(info.AssetParams) > 0 {
+		fmt.Printf("Created assets:\n")
+		for _, index := range createdAssetIndices(info.Account) {
+			params := info.AssetParams[index]
+			fmt.Printf("\tID %d, %s, supply %d %s\n", index, params.AssetName, params.Total, params.UnitName)
+		}
+	}
+	if len(info.Assets) > 0 {
+		fmt.Printf("Assets:\n")
+		for _, index := range heldAssetIndices(info.Account) {
+			holding := info.Assets[index]
+			frozen := ""
+			if holding.Frozen {
+				frozen = " (frozen)"
+			}
+			fmt.Printf("\tID %d, %d units%s, created by %s\n", index, holding.Amount, frozen, holding.Creator)
+		}
+	}
+	fmt.Printf("Pending transactions: %d\n", info.PendingTxns)
+	if info.PendingTxns > 0 {
+		fmt.Printf("Pending amount sent: %d microAlgos\n", info.PendingSent)
+		fmt.Printf("Pending fees: %d microAlgos\n", info.PendingFees)
+		if info.PendingClose != "" {
+			fmt.Printf("Pending close to: %s\n", info.PendingClose)
+		}
+	}
+}
+
+// createdAssetIndices returns the indices of the assets the account created, in increasing order
+func createdAssetIndices(acct models.Account) []uint64 {
+	indices := make([]uint64, 0, len(acct.AssetParams))
+	for index := range acct.AssetParams {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	return indices
+}
+
+// heldAssetIndices returns the indices of the assets the account holds, in increasing order
+func heldAssetIndices(acct models.Account) []uint64 {
+	indices := make([]uint64, 0, len(acct.Assets))
+	for index := range acct.Assets {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	return indices
+}
+
 var rewardsCmd = &cobra.Command{
 	Use:   "rewards",
 	Short: "Retrieve the rewards for the specified account",
-	Long:  `Retrieve the rewards for the specified account`,
+	Long:  `Retrieve the rewards the specified account has received and those still pending, the current rewards rate of the network, and an estimate of what the account earns per round and per day at its current balance. The estimate assumes the rate and the participating stake stay the same. With --rounds, also scan the blocks of the last rounds for the rewards the account accrued and those its transactions realized.`,
 	Args:  validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
-		dataDir := ensureSingleDataDir()
-		client := ensureAlgodClient(dataDir)
-		response, err := client.AccountInformation(accountAddress)
-		if err != nil {
-			reportErrorf(errorRequestFail, err)
-		}
-
-		fmt.Printf("%v microAlgos\n", response.Rewards)
+		onDataDirsReportingErrors(func(dataDir string) error {
+			return reportRewards(ensureAlgodClient(dataDir), accountAddress, rewardsRounds)
+		})
 	},
 }
 
 var changeOnlineCmd = &cobra.Command{
 	Use:   "changeonlinestatus",
 	Short: "Change online status for the specified account",
-	Long:  `Change online status for the specified account. Set online should be 1 to set online, 0 to set offline. The broadcast transaction will be valid for a limited number of rounds. goal will provide the TXID of the transaction if successful. Going online requires that the given account have a valid participation key.`,
+	Long:  `Change online status for the specified account. Set online should be 1 to set online, 0 to set offline. The broadcast transaction will be valid for a limited number of rounds. goal will provide the TXID of the transaction if successful. Going online requires that the given account have a valid participation key. Several accounts can be changed at once with repeated -a flags or a --batch file; their transactions are all broadcast before waiting for them to commit, and a failure of one account doesn't stop the others. With --txfile, the transactions of all the accounts are written to the file.`,
 	Args:  validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Pull the current round for use in our new transactions
-		dataDir := ensureSingleDataDir()
-		client := ensureFullClient(dataDir)
+		addrs := append([]string{}, onlineAddrs...)
+		if onlineBatchFile != "" {
+			addrs = append(addrs, readAddrFile(onlineBatchFile)...)
+		}
+		if len(addrs) == 0 {
+			reportErrorln(errorOnlineNoAccount)
+		}
+		seen := make(map[string]bool)
+		uniqueAddrs := addrs[:0]
+		for _, addr := range addrs {
+			addr = resolveAccountName(addr)
+			if !seen[addr] {
+				seen[addr] = true
+				uniqueAddrs = append(uniqueAddrs, addr)
+			}
+		}
+		addrs = uniqueAddrs
 
-		err := changeAccountOnlineStatus(accountAddress, nil, online, onlineTxFile, walletName, onlineFirstRound, onlineValidRounds, transactionFee, dataDir, client)
-		if err != nil {
-			reportErrorf(err.Error())
+		if onlineTxFile != "" && len(getDataDirs()) > 1 {
+			reportErrorln(errorTxFileMultipleDataDirs)
 		}
+		lease := parseLease(leaseBase64)
+		onDataDirsReportingErrors(func(dataDir string) error {
+			client := ensureFullClient(dataDir)
+			if len(addrs) == 1 {
+				return changeAccountOnlineStatus(addrs[0], nil, online, onlineTxFile, walletName, onlineFirstRound, onlineValidRounds, transactionFee, lease, dataDir, client)
+			}
+			return changeAccountsOnlineStatus(addrs, online, onlineTxFile, walletName, onlineFirstRound, onlineValidRounds, transactionFee, lease, dataDir, client)
+		})
 	},
 }
 
-func changeAccountOnlineStatus(acct string, part *algodAcct.Participation, goOnline bool, txFile string, wallet string, firstTxRound, validTxRounds, fee uint64, dataDir string, client libgoal.Client) error {
+// onlineStatusChange is the outcome of the status change of one of the accounts of a batch
+type onlineStatusChange struct {
+	Address        string `json:"address"`
+	TxID           string `json:"txid,omitempty"`
+	ConfirmedRound uint64 `json:"confirmedRound,omitempty"`
+	// Error is the reason the status change failed, empty if it didn't
+	Error string `json:"error,omitempty"`
+}
+
+// changeAccountsOnlineStatus changes the status of several accounts. All the
+// transactions are broadcast, or written to txFile, before waiting for any of
+// them, and a failing account doesn't stop the others.
+func changeAccountsOnlineStatus(addrs []string, goOnline bool, txFile string, wallet string, firstTxRound, validTxRounds, fee uint64, lease [32]byte, dataDir string, client libgoal.Client) error {
+	changes := make([]onlineStatusChange, len(addrs))
+	utxs := make([]transactions.Transaction, len(addrs))
+	for i, addr := range addrs {
+		changes[i].Address = addr
+		var err error
+		if goOnline {
+			utxs[i], err = client.MakeUnsignedGoOnlineTx(addr, nil, firstTxRound, validTxRounds, fee, lease)
+		} else {
+			utxs[i], err = client.MakeUnsignedGoOfflineTx(addr, firstTxRound, validTxRounds, fee, lease)
+		}
+		if err != nil {
+			changes[i].Error = err.Error()
+		}
+	}
+
+	if txFile != "" {
+		var data []byte
+		for i := range changes {
+			if changes[i].Error != "" {
+				continue
+			}
+			stxn, err := transactions.AssembleSignedTxn(utxs[i], crypto.Signature{}, crypto.MultisigSig{})
+			if err != nil {
+				changes[i].Error = fmt.Sprintf(errorConstructingTX, err)
+				continue
+			}
+			stxn = populateBlankMultisig(client, dataDir, wallet, stxn)
+			data = append(data, protocol.Encode(stxn)...)
+			changes[i].TxID = utxs[i].ID().String()
+		}
+		if err := ioutil.WriteFile(txFile, data, 0600); err != nil {
+			return fmt.Errorf(fileWriteError, txFile, err)
+		}
+	} else {
+		wh, pw := ensureWalletHandleMaybePassword(dataDir, wallet, true)
+		for i := range changes {
+			if changes[i].Error != "" {
+				continue
+			}
+			txid, err := client.SignAndBroadcastTransaction(wh, pw, utxs[i])
+			if err != nil {
+				changes[i].Error = fmt.Sprintf(errorOnlineTX, err)
+				continue
+			}
+			changes[i].TxID = txid
+		}
+		if !noWaitAfterSend {
+			waitForStatusChanges(client, changes)
+		}
+	}
+
+	failed := 0
+	rows := make([][]string, 0, len(changes))
+	for _, change := range changes {
+		status := fmt.Sprintf("%d", change.ConfirmedRound)
+		if change.Error != "" {
+			failed++
+			status = change.Error
+		}
+		rows = append(rows, []string{change.Address, change.TxID, status})
+	}
+	reportRows(changes, []string{"ADDRESS", "TXID", "ROUND"}, rows, func() {
+		for _, change := range changes {
+			fmt.Printf(infoOnlineStatusAccount+"\n", change.Address)
+			if change.TxID != "" {
+				fmt.Printf("Transaction id for status change transaction: %s\n", change.TxID)
+			}
+			switch {
+			case change.Error != "":
+				fmt.Println(change.Error)
+			case change.ConfirmedRound > 0:
+				fmt.Printf(infoTxCommitted+"\n", change.TxID, change.ConfirmedRound)
+			case txFile == "":
+				fmt.Println("Note: status will not change until transaction is finalized")
+			}
+		}
+	})
+	if failed > 0 {
+		return fmt.Errorf(errorOnlineStatusIncomplete, failed, len(changes))
+	}
+	return nil
+}
+
+// waitForStatusChanges waits, a round at a time, until every broadcast
+// status change either commits or is kicked out of the pool
+func waitForStatusChanges(client libgoal.Client, changes []onlineStatusChange) {
+	stat, err := client.Status()
+	for {
+		pending := 0
+		for i := range changes {
+			change := &changes[i]
+			if change.TxID == "" || change.Error != "" || change.ConfirmedRound > 0 {
+				continue
+			}
+			if err != nil {
+				change.Error = fmt.Sprintf(errorRequestFail, err)
+				continue
+			}
+			txn, txnErr := client.PendingTransactionInformation(change.TxID)
+			switch {
+			case txnErr != nil:
+				change.Error = fmt.Sprintf(errorRequestFail, txnErr)
+			case txn.ConfirmedRound > 0:
+				change.ConfirmedRound = txn.ConfirmedRound
+			case txn.PoolError != "":
+				change.Error = fmt.Sprintf(txPoolError, change.TxID, txn.PoolError)
+			default:
+				pending++
+			}
+		}
+		if pending == 0 {
+			return
+		}
+		reportVerbosef(infoOnlineStatusPending, pending, stat.LastRound)
+		stat, err = client.WaitForRound(stat.LastRound + 1)
+	}
+}
+
+func changeAccountOnlineStatus(acct string, part *algodAcct.Participation, goOnline bool, txFile string, wallet string, firstTxRound, validTxRounds, fee uint64, lease [32]byte, dataDir string, client libgoal.Client) error {
 	// Generate an unsigned online/offline tx
 	var utx transactions.Transaction
 	var err error
 	if goOnline {
-		utx, err = client.MakeUnsignedGoOnlineTx(acct, part, firstTxRound, validTxRounds, fee)
+		utx, err = client.MakeUnsignedGoOnlineTx(acct, part, firstTxRound, validTxRounds, fee, lease)
 	} else {
-		utx, err = client.MakeUnsignedGoOfflineTx(acct, firstTxRound, validTxRounds, fee)
+		utx, err = client.MakeUnsignedGoOfflineTx(acct, firstTxRound, validTxRounds, fee, lease)
 	}
 	if err != nil {
 		return err
 	}
+	return sendStatusChangeTx(utx, txFile, wallet, dataDir, client)
+}
 
+// sendStatusChangeTx signs, broadcasts and waits for a status change
+// transaction, or writes it unsigned to txFile if given
+func sendStatusChangeTx(utx transactions.Transaction, txFile string, wallet string, dataDir string, client libgoal.Client) error {
 	if txFile == "" {
 		// Sign & broadcast the transaction
 		wh, pw := ensureWalletHandleMaybePassword(dataDir, wallet, true)
 		txid, err := client.SignAndBroadcastTransaction(wh, pw, utx)
 		if err != nil {
-			return fmt.Errorf(errorOnlineTX, err)
-		}
-		fmt.Printf("Transaction id for status change transaction: %s\n", txid)
-
-		if noWaitAfterSend {
-			fmt.Println("Note: status will not change until transaction is finalized")
-			return nil
-		}
-
-		// Get current round information
-		stat, err := client.Status()
-		if err != nil {
-			return fmt.Errorf(errorRequestFail, err)
-		}
-
-		for {
-			// Check if we know about the transaction yet
-			txn, err := client.PendingTransactionInformation(txid)
-			if err != nil {
-				return fmt.Errorf(errorRequestFail, err)
-			}
-
-			if txn.ConfirmedRound > 0 {
-				reportInfof(infoTxCommitted, txid, txn.ConfirmedRound)
-				break
-			}
+			return fmt.Errorf(errorOnlineTX, err)
+		}
+		fmt.Printf("Transaction id for status change transaction: %s\n", txid)
 
-			if txn.PoolError != "" {
-				return fmt.Errorf(txPoolError, txid, txn.PoolError)
-			}
+		if noWaitAfterSend {
+			fmt.Println("Note: status will not change until transaction is finalized")
+			return nil
+		}
 
-			reportInfof(infoTxPending, txid, stat.LastRound)
-			stat, err = client.WaitForRound(stat.LastRound + 1)
-			if err != nil {
-				return fmt.Errorf(errorRequestFail, err)
-			}
+		if _, err = waitForCommit(client, txid); err != nil {
+			return err
 		}
 	} else {
 		// Wrap in a transactions.SignedTxn with an empty sig.
@@ -556,74 +1745,193 @@ func changeAccountOnlineStatus(acct string, part *algodAcct.Participation, goOnl
 	return nil
 }
 
-var addParticipationKeyCmd = &cobra.Command{
-	Use:   "addpartkey",
-	Short: "Generate a participation key for the specified account",
-	Long:  `Generate a participation key for the specified account`,
+var markNonparticipatingCmd = &cobra.Command{
+	Use:   "marknonparticipating",
+	Short: "Permanently mark an account as not participating (i.e. offline and earns no rewards)",
+	Long:  `Permanently mark an account as not participating in consensus. The account goes offline, stops earning rewards, and can never register participation keys again; this cannot be undone. goal asks for confirmation before broadcasting the transaction, unless --no-prompt is given. With --txfile, the unsigned transaction is written to the file instead, for signing offline.`,
 	Args:  validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
-		dataDir := ensureSingleDataDir()
-
-		if partKeyOutDir != "" && !util.IsDir(partKeyOutDir) {
-			reportErrorf(errorDirectoryNotExist, partKeyOutDir)
+		if onlineTxFile != "" && len(getDataDirs()) > 1 {
+			reportErrorln(errorTxFileMultipleDataDirs)
 		}
-
-		// Generate a participation keys database and install it
-		client := ensureFullClient(dataDir)
-
-		_, _, err := client.GenParticipationKeysTo(accountAddress, roundFirstValid, roundLastValid, keyDilution, partKeyOutDir)
-		if err != nil {
-			reportErrorf(errorRequestFail, err)
-		}
-		fmt.Println("Participation key generation successful")
+		onDataDirsReportingErrors(func(dataDir string) error {
+			client := ensureFullClient(dataDir)
+			utx, err := client.MakeUnsignedBecomeNonparticipatingTx(accountAddress, onlineFirstRound, onlineValidRounds, transactionFee, [32]byte{})
+			if err != nil {
+				return err
+			}
+			if onlineTxFile == "" && !nonparticipatingNoPrompt && !confirmNonparticipating(os.Stdin, accountAddress) {
+				return fmt.Errorf(errorNonpartNotConfirmed)
+			}
+			return sendStatusChangeTx(utx, onlineTxFile, walletName, dataDir, client)
+		})
 	},
 }
 
-var renewParticipationKeyCmd = &cobra.Command{
-	Use:   "renewpartkey",
-	Short: "Renew an account's participation key",
-	Long:  `Generate a participation key for the specified account and register it`,
+// confirmNonparticipating asks whether to mark addr as nonparticipating, and
+// returns true only if the answer read from in is yes
+func confirmNonparticipating(in io.Reader, addr string) bool {
+	fmt.Printf(infoNonpartConfirm, addr)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(answer), "yes")
+}
+
+var rekeyCmd = &cobra.Command{
+	Use:   "rekey",
+	Short: "Change the key that authorizes transactions from the specified account",
+	Long:  `Change the key that authorizes transactions from the specified account. The account keeps its address and balance, but after the rekey transaction commits only the key of the --to address can sign for it. Rekeying an account to its own address restores its original key. The rekey transaction is signed with the key that currently authorizes the account, which must be in the wallet.`,
 	Args:  validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
 		dataDir := ensureSingleDataDir()
+		client := ensureFullClient(dataDir)
 
-		client := ensureAlgodClient(dataDir)
-
-		currentRound, err := client.CurrentRound()
+		addr, toAddr := accountAddress, rekeyToAddress
+		rekeyTo, err := basics.UnmarshalChecksumAddress(toAddr)
 		if err != nil {
-			reportErrorf(errorRequestFail, err)
+			reportErrorf(errorConstructingTX, err)
 		}
 
-		params, err := client.SuggestedParams()
+		info, err := client.AccountInformation(addr)
 		if err != nil {
 			reportErrorf(errorRequestFail, err)
 		}
-		proto := config.Consensus[protocol.ConsensusVersion(params.ConsensusVersion)]
 
-		if roundLastValid <= (currentRound + proto.MaxTxnLife) {
-			reportErrorf(errLastRoundInvalid, currentRound)
+		// A rekey is a zero payment to the account itself
+		utx, err := client.ConstructPayment(addr, addr, transactionFee, 0, nil, "", [32]byte{})
+		if err != nil {
+			reportErrorf(errorConstructingTX, err)
 		}
+		utx.RekeyTo = rekeyTo
 
-		// Make sure we don't already have a partkey valid for (or after) specified roundLastValid
-		parts, err := client.ListParticipationKeys()
+		wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
+		stxn, err := client.SignTransactionWithWalletAndSigner(wh, pw, info.AuthAddr, utx)
 		if err != nil {
-			reportErrorf(errorRequestFail, err)
+			reportErrorf(errorSigningTX, err)
 		}
-		for _, part := range parts {
-			if part.Address().GetChecksumAddress().String() == accountAddress {
-				if part.LastValid >= basics.Round(roundLastValid) {
-					reportErrorf(errExistingPartKey, roundLastValid, part.LastValid)
-				}
+		txid, err := client.BroadcastTransaction(stxn)
+		if err != nil {
+			reportErrorf(errorBroadcastingTX, err)
+		}
+		reportInfof(infoRekeyIssued, addr, toAddr, txid)
+
+		sent := sentTransaction{TxID: txid, Fee: utx.Fee.Raw}
+		if !noWaitAfterSend {
+			sent.ConfirmedRound, err = waitForCommit(client, txid)
+			if err != nil {
+				reportErrorln(err)
 			}
 		}
+		reportResult(sent, txid, nil)
+	},
+}
 
-		err = generateAndRegisterPartKey(accountAddress, currentRound, roundLastValid, proto.MaxTxnLife, transactionFee, keyDilution, walletName, dataDir, client)
-		if err != nil {
-			reportErrorf(err.Error())
+var addParticipationKeyCmd = &cobra.Command{
+	Use:   "addpartkey",
+	Short: "Generate a participation key for the specified account",
+	Long:  `Generate a participation key for the specified account. The keys are generated on all the cores; for long validity ranges, which take minutes, --progress reports how far along the generation is.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		if partKeyOutDir != "" {
+			if !util.IsDir(partKeyOutDir) {
+				reportErrorf(errorDirectoryNotExist, partKeyOutDir)
+			}
+			if len(getDataDirs()) > 1 {
+				reportErrorln(errorOutDirMultipleDataDirs)
+			}
+		}
+
+		onDataDirsReportingErrors(func(dataDir string) error {
+			// Generate a participation keys database and install it
+			client := ensureFullClient(dataDir)
+
+			var progress crypto.KeyGenProgress
+			if partKeyProgress {
+				progress = keyGenProgressReporter()
+			}
+			_, _, err := client.GenParticipationKeysProgress(accountAddress, roundFirstValid, roundLastValid, keyDilution, partKeyOutDir, progress)
+			if err != nil {
+				return fmt.Errorf(errorRequestFail, err)
+			}
+			fmt.Println("Participation key generation successful")
+			return nil
+		})
+	},
+}
+
+// keyGenProgressInterval is how often goal account addpartkey --progress reports the progress of the key generation
+const keyGenProgressInterval = time.Second
+
+// keyGenProgressReporter returns a crypto.KeyGenProgress that reports the progress of a participation key generation,
+// with an estimate of the time left, at most once every keyGenProgressInterval and when the generation completes
+func keyGenProgressReporter() crypto.KeyGenProgress {
+	start := time.Now()
+	var last time.Time
+	return func(done, total uint64) {
+		now := time.Now()
+		if done < total && now.Sub(last) < keyGenProgressInterval {
+			return
 		}
+		last = now
+		reportInfof(infoKeyGenProgress, done, total, 100*float64(done)/float64(total), estimateTimeLeft(now.Sub(start), done, total))
+	}
+}
+
+// estimateTimeLeft estimates the time the rest of the work takes, from the time done of total units took
+func estimateTimeLeft(elapsed time.Duration, done, total uint64) time.Duration {
+	if done == 0 || done >= total {
+		return 0
+	}
+	left := time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+	return left.Round(time.Second)
+}
+
+var renewParticipationKeyCmd = &cobra.Command{
+	Use:   "renewpartkey",
+	Short: "Renew an account's participation key",
+	Long:  `Generate a participation key for the specified account and register it`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		onDataDirsReportingErrors(renewPartKey)
 	},
 }
 
+func renewPartKey(dataDir string) error {
+	client := ensureAlgodClient(dataDir)
+
+	currentRound, err := client.CurrentRound()
+	if err != nil {
+		return fmt.Errorf(errorRequestFail, err)
+	}
+
+	params, err := client.SuggestedParams()
+	if err != nil {
+		return fmt.Errorf(errorRequestFail, err)
+	}
+	proto := config.Consensus[protocol.ConsensusVersion(params.ConsensusVersion)]
+
+	if roundLastValid <= (currentRound + proto.MaxTxnLife) {
+		return fmt.Errorf(errLastRoundInvalid, currentRound)
+	}
+
+	// Make sure we don't already have a partkey valid for (or after) specified roundLastValid
+	parts, err := client.ListParticipationKeys()
+	if err != nil {
+		return fmt.Errorf(errorRequestFail, err)
+	}
+	for _, part := range parts {
+		if part.Address().GetChecksumAddress().String() == accountAddress {
+			if part.LastValid >= basics.Round(roundLastValid) {
+				return fmt.Errorf(errExistingPartKey, roundLastValid, part.LastValid)
+			}
+		}
+	}
+
+	return generateAndRegisterPartKey(accountAddress, currentRound, roundLastValid, proto.MaxTxnLife, transactionFee, keyDilution, walletName, dataDir, client)
+}
+
 func generateAndRegisterPartKey(address string, currentRound, lastValidRound, maxTxnLife uint64, fee, dilution uint64, wallet string, dataDir string, client libgoal.Client) error {
 	// Generate a participation keys database and install it
 	part, keyPath, err := client.GenParticipationKeysTo(address, currentRound, lastValidRound, dilution, "")
@@ -635,7 +1943,7 @@ func generateAndRegisterPartKey(address string, currentRound, lastValidRound, ma
 	// Now register it as our new online participation key
 	goOnline := true
 	txFile := ""
-	err = changeAccountOnlineStatus(address, &part, goOnline, txFile, wallet, currentRound, maxTxnLife, fee, dataDir, client)
+	err = changeAccountOnlineStatus(address, &part, goOnline, txFile, wallet, currentRound, maxTxnLife, fee, [32]byte{}, dataDir, client)
 	if err != nil {
 		part.Close()
 		os.Remove(keyPath)
@@ -730,32 +2038,142 @@ var listParticipationKeysCmd = &cobra.Command{
 	Short: "List participation keys",
 	Args:  validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
-		dataDir := ensureSingleDataDir()
+		onDataDirsReportingErrors(func(dataDir string) error {
+			client := ensureGoalClient(dataDir, libgoal.DynamicClient)
+			parts, err := client.ListParticipationKeys()
+			if err != nil {
+				return fmt.Errorf(errorRequestFail, err)
+			}
 
-		client := ensureGoalClient(dataDir, libgoal.DynamicClient)
-		parts, err := client.ListParticipationKeys()
-		if err != nil {
-			reportErrorf(errorRequestFail, err)
-		}
+			var filenames []string
+			for fn := range parts {
+				filenames = append(filenames, fn)
+			}
+			sort.Strings(filenames)
+
+			keys := make([]partkeyListing, 0, len(filenames))
+			rows := make([][]string, 0, len(filenames))
+			for _, fn := range filenames {
+				first, last := parts[fn].ValidInterval()
+				key := partkeyListing{
+					File:       fn,
+					Address:    parts[fn].Address().GetUserAddress(),
+					FirstValid: first,
+					LastValid:  last,
+					FirstKey:   fmt.Sprintf("%d.%d", parts[fn].Voting.FirstBatch, parts[fn].Voting.FirstOffset),
+				}
+				keys = append(keys, key)
+				rows = append(rows, []string{key.File, key.Address, fmt.Sprintf("%d", key.FirstValid), fmt.Sprintf("%d", key.LastValid), key.FirstKey})
+			}
+
+			reportRows(keys, []string{"FILE", "ADDRESS", "FIRST", "LAST", "FIRST KEY"}, rows, func() {
+				rowFormat := "%-80s\t%-60s\t%12s\t%12s\t%12s\n"
+				fmt.Printf(rowFormat, "Filename", "Parent address", "First round", "Last round", "First key")
+				for _, row := range rows {
+					fmt.Printf(rowFormat, row[0], row[1], row[2], row[3], row[4])
+				}
+			})
+			return nil
+		})
+	},
+}
 
-		var filenames []string
-		for fn := range parts {
-			filenames = append(filenames, fn)
+var deleteParticipationKeyCmd = &cobra.Command{
+	Use:   "deletepartkey",
+	Short: "Delete participation keys",
+	Long:  `Delete a participation key database, or those of an account, from the data directory. The node stops using the deleted keys within a minute. Keys that have not expired yet are kept unless --force is given.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		if (partKeyFile == "") == (accountAddress == "") {
+			reportErrorln(errorPartKeyDeleteSelection)
+		}
+		var address basics.Address
+		if accountAddress != "" {
+			var err error
+			address, err = basics.UnmarshalChecksumAddress(accountAddress)
+			if err != nil {
+				reportErrorf(errorParseAddr, accountAddress, err)
+			}
 		}
-		sort.Strings(filenames)
 
-		rowFormat := "%-80s\t%-60s\t%12s\t%12s\t%12s\n"
-		fmt.Printf(rowFormat, "Filename", "Parent address", "First round", "Last round", "First key")
-		for _, fn := range filenames {
-			first, last := parts[fn].ValidInterval()
-			fmt.Printf(rowFormat, fn, parts[fn].Address().GetUserAddress(),
-				fmt.Sprintf("%d", first),
-				fmt.Sprintf("%d", last),
-				fmt.Sprintf("%d.%d", parts[fn].Voting.FirstBatch, parts[fn].Voting.FirstOffset))
+		onDataDirsReportingErrors(func(dataDir string) error {
+			client := ensureGoalClient(dataDir, libgoal.DynamicClient)
+			parts, err := client.ListParticipationKeys()
+			if err != nil {
+				return fmt.Errorf(errorRequestFail, err)
+			}
+
+			var filenames []string
+			if partKeyFile != "" {
+				filename := filepath.Base(partKeyFile)
+				if _, ok := parts[filename]; !ok {
+					return fmt.Errorf(errorPartKeyNotFound, filename)
+				}
+				filenames = append(filenames, filename)
+			} else {
+				for filename, part := range parts {
+					if part.Address() == address {
+						filenames = append(filenames, filename)
+					}
+				}
+				if len(filenames) == 0 {
+					return fmt.Errorf(errorNoPartKeys, accountAddress)
+				}
+				sort.Strings(filenames)
+			}
+
+			var currentRound basics.Round
+			if !forcePartKeyDelete {
+				status, err := client.Status()
+				if err != nil {
+					return fmt.Errorf(errorRequestFail, err)
+				}
+				currentRound = basics.Round(status.LastRound)
+			}
+
+			for _, filename := range filenames {
+				if !forcePartKeyDelete && parts[filename].LastValid >= currentRound {
+					reportWarnf(warnPartKeyNotExpired, filename, parts[filename].LastValid)
+					continue
+				}
+				err = client.DeleteParticipationKeys(filename)
+				if err != nil {
+					return fmt.Errorf(errorRequestFail, err)
+				}
+				reportInfof(infoPartKeyDeleted, filename)
+			}
+			return nil
+		})
+	},
+}
+
+var installParticipationKeyCmd = &cobra.Command{
+	Use:   "installpartkey",
+	Short: "Install a participation key",
+	Long:  `Install a participation key database generated elsewhere, for instance with addpartkey --outdir or algokey, into the data directory. The node starts using it within a minute.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureGoalClient(dataDir, libgoal.DynamicClient)
+
+		part, filePath, err := client.InstallParticipationKeys(partKeyFile)
+		if err != nil {
+			reportErrorf(errorInstallPartKey, err)
 		}
+		first, last := part.ValidInterval()
+		reportInfof(infoPartKeyInstalled, part.Address().GetUserAddress(), first, last, filePath)
 	},
 }
 
+// partkeyListing is a participation key as listed by goal account listpartkeys.
+type partkeyListing struct {
+	File       string       `json:"file"`
+	Address    string       `json:"address"`
+	FirstValid basics.Round `json:"first"`
+	LastValid  basics.Round `json:"last"`
+	FirstKey   string       `json:"firstkey"`
+}
+
 var importCmd = &cobra.Command{
 	Use:   "import",
 	Short: "Import an account key from mnemonic",
@@ -784,20 +2202,28 @@ var importCmd = &cobra.Command{
 		wh := ensureWalletHandle(dataDir, walletName)
 		//wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
 
-		if mnemonic == "" {
-			fmt.Println(infoRecoveryPrompt)
-			reader := bufio.NewReader(os.Stdin)
-			resp, err := reader.ReadString('\n')
-			resp = strings.TrimSpace(resp)
+		var key []byte
+		if keystoreFile != "" {
+			if mnemonic != "" {
+				reportErrorln(errorKeyfileAndMnemonic)
+			}
+			key = readKeystoreKey(keystoreFile)
+		} else {
+			if mnemonic == "" {
+				fmt.Println(infoRecoveryPrompt)
+				reader := bufio.NewReader(os.Stdin)
+				resp, err := reader.ReadString('\n')
+				resp = strings.TrimSpace(resp)
+				if err != nil {
+					reportErrorf(errorFailedToReadResponse, err)
+				}
+				mnemonic = resp
+			}
+			var err error
+			key, err = passphrase.MnemonicToKey(mnemonic)
 			if err != nil {
-				reportErrorf(errorFailedToReadResponse, err)
+				reportErrorf(errorBadMnemonic, err)
 			}
-			mnemonic = resp
-		}
-		var key []byte
-		key, err := passphrase.MnemonicToKey(mnemonic)
-		if err != nil {
-			reportErrorf(errorBadMnemonic, err)
 		}
 
 		importedKey, err := client.ImportKey(wh, key)
@@ -819,6 +2245,11 @@ var exportCmd = &cobra.Command{
 	Short: "Export an account key for use with account import",
 	Long:  "Export an account mnemonic seed, for use with account import. This exports the seed for a single account and should not be confused with the wallet mnemonic.",
 	Run: func(cmd *cobra.Command, args []string) {
+		if keystoreFile != "" && qrCodeRequested() {
+			reportErrorln(errorKeyfileAndQRCode)
+		}
+		ensureQRCodePNGFileFree()
+
 		dataDir := ensureSingleDataDir()
 		client := ensureKmdClient(dataDir)
 
@@ -837,6 +2268,23 @@ var exportCmd = &cobra.Command{
 			reportErrorf(errorSeedConversion, accountAddress, err)
 		}
 
+		if keystoreFile != "" {
+			// Don't ask for a passphrase only to fail writing the file
+			if util.FileExists(keystoreFile) {
+				reportErrorf(fileWriteError, keystoreFile, "the file already exists")
+			}
+			ks, err := libgoal.EncryptKeystore(seed, readKeystorePassphrase(true))
+			if err != nil {
+				reportErrorf(errorKeystoreEncrypt, err)
+			}
+			err = libgoal.WriteKeystoreFile(keystoreFile, ks)
+			if err != nil {
+				reportErrorf(fileWriteError, keystoreFile, err)
+			}
+			reportInfof(infoExportedKeyfile, accountAddress, keystoreFile)
+			return
+		}
+
 		privKeyAsMnemonic, err := passphrase.KeyToMnemonic(seed[:])
 
 		if err != nil {
@@ -844,9 +2292,50 @@ var exportCmd = &cobra.Command{
 		}
 
 		reportInfof(infoExportedKey, accountAddress, privKeyAsMnemonic)
+		reportQRCode(privKeyAsMnemonic)
 	},
 }
 
+// readKeystoreKey decrypts the account seed of a keystore file
+func readKeystoreKey(filename string) []byte {
+	ks, err := libgoal.ReadKeystoreFile(filename)
+	if err != nil {
+		reportErrorf(fileReadError, filename, err)
+	}
+	seed, err := ks.Decrypt(readKeystorePassphrase(false))
+	if err != nil {
+		reportErrorf(errorKeystoreDecrypt, filename, err)
+	}
+	return seed[:]
+}
+
+// readKeystorePassphrase returns the passphrase of a keystore file, which is prompted for with --passphrase-prompt,
+// and asked twice when confirm is set. Otherwise it is the first line of stdin, for scripts.
+func readKeystorePassphrase(confirm bool) []byte {
+	var pass []byte
+	if passphrasePrompt {
+		fmt.Printf(infoKeystorePassphrasePrompt)
+		pass = ensurePassword()
+		if confirm {
+			fmt.Printf(infoPasswordConfirmation)
+			if !bytes.Equal(pass, ensurePassword()) {
+				reportErrorln(errorPasswordConfirmation)
+			}
+		}
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			reportErrorf(errorFailedToReadPassword, err)
+		}
+		pass = []byte(strings.TrimRight(line, "\r\n"))
+	}
+
+	if len(pass) == 0 {
+		reportErrorln(errorEmptyKeystorePassphrase)
+	}
+	return pass
+}
+
 var importRootKeysCmd = &cobra.Command{
 	Use:   "importrootkey",
 	Short: "Import .rootkey files from the data directory into a kmd wallet",
@@ -934,45 +2423,175 @@ var importRootKeysCmd = &cobra.Command{
 }
 
 type partkeyInfo struct {
-	_struct         struct{}                        `codec:",omitempty,omitemptyarray"`
-	Address         string                          `codec:"acct"`
-	FirstValid      basics.Round                    `codec:"first"`
-	LastValid       basics.Round                    `codec:"last"`
-	VoteID          crypto.OneTimeSignatureVerifier `codec:"vote"`
-	SelectionID     crypto.VRFVerifier              `codec:"sel"`
-	VoteKeyDilution uint64                          `codec:"voteKD"`
+	_struct         struct{}     `codec:",omitempty,omitemptyarray"`
+	Address         string       `codec:"acct" json:"acct"`
+	FirstValid      basics.Round `codec:"first" json:"first"`
+	LastValid       basics.Round `codec:"last" json:"last"`
+	VoteID          []byte       `codec:"vote" json:"vote"`
+	SelectionID     []byte       `codec:"sel" json:"sel"`
+	VoteKeyDilution uint64       `codec:"voteKD" json:"voteKD"`
+	Status          string       `codec:"status" json:"status,omitempty"`
 }
 
 var partkeyInfoCmd = &cobra.Command{
 	Use:   "partkeyinfo",
 	Short: "Output details about all available part keys",
-	Long:  `Output details about all available part keys in the specified data directory(ies)`,
-	Args:  validateNoPosArgsFn,
+	Long: `Output details about all available part keys in the specified data directory(ies). The structured output formats map the file of every key to its details.
+
+With --verify, every key is compared with the participation key registered on chain for its account, and its status is one of "registered", "not registered", or "mismatch" followed by the fields that differ from the registration. Accounts that are online but have no matching local key are reported too, including the accounts given with -a that have no local keys at all. The command fails if any key mismatches or any online account has no matching key.`,
+	Args: validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
+		onDataDirsReportingErrors(dumpPartkeyInfo)
+	},
+}
 
-		onDataDirs(func(dataDir string) {
-			fmt.Printf("Dumping participation key info from %s...\n", dataDir)
-			client := ensureGoalClient(dataDir, libgoal.DynamicClient)
+// dumpPartkeyInfo reports the participation keys of dataDir, verifying them
+// against the chain with --verify
+func dumpPartkeyInfo(dataDir string) error {
+	reportInfof("Dumping participation key info from %s...", dataDir)
+	client := ensureGoalClient(dataDir, libgoal.DynamicClient)
 
-			// Make sure we don't already have a partkey valid for (or after) specified roundLastValid
-			parts, err := client.ListParticipationKeys()
+	// Make sure we don't already have a partkey valid for (or after) specified roundLastValid
+	parts, err := client.ListParticipationKeys()
+	if err != nil {
+		return fmt.Errorf(errorRequestFail, err)
+	}
+
+	var filenames []string
+	for fn := range parts {
+		filenames = append(filenames, fn)
+	}
+	sort.Strings(filenames)
+
+	infos := make(map[string]partkeyInfo, len(parts))
+	for _, filename := range filenames {
+		part := parts[filename]
+		voteID := part.VotingSecrets().OneTimeSignatureVerifier
+		selectionID := part.VRFSecrets().PK
+		infos[filename] = partkeyInfo{
+			Address:         part.Address().GetChecksumAddress().String(),
+			FirstValid:      part.FirstValid,
+			LastValid:       part.LastValid,
+			VoteID:          voteID[:],
+			SelectionID:     selectionID[:],
+			VoteKeyDilution: part.KeyDilution,
+		}
+	}
+
+	problems := 0
+	var unmatched []string
+	if partkeyVerify {
+		accounts := make(map[string]models.Account)
+		addrs := make([]string, 0, len(partkeyVerifyAddrs))
+		for _, addr := range partkeyVerifyAddrs {
+			addrs = append(addrs, resolveAccountName(addr))
+		}
+		for _, info := range infos {
+			addrs = append(addrs, info.Address)
+		}
+		for _, addr := range addrs {
+			if _, ok := accounts[addr]; ok {
+				continue
+			}
+			accounts[addr], err = client.AccountInformation(addr)
 			if err != nil {
-				reportErrorf(errorRequestFail, err)
+				return fmt.Errorf(errorRequestFail, err)
 			}
+		}
 
-			for filename, part := range parts {
-				fmt.Println("------------------------------------------------------------------")
-				info := partkeyInfo{
-					Address:         part.Address().GetChecksumAddress().String(),
-					FirstValid:      part.FirstValid,
-					LastValid:       part.LastValid,
-					VoteID:          part.VotingSecrets().OneTimeSignatureVerifier,
-					SelectionID:     part.VRFSecrets().PK,
-					VoteKeyDilution: part.KeyDilution,
-				}
-				infoString := protocol.EncodeJSON(&info)
-				fmt.Printf("File: %s\n%s\n", filename, string(infoString))
+		for _, filename := range filenames {
+			info := infos[filename]
+			info.Status = partkeyStatus(info, accounts[info.Address])
+			if info.Status != partkeyRegistered && info.Status != partkeyNotRegistered {
+				problems++
 			}
-		})
-	},
+			infos[filename] = info
+		}
+		unmatched = unmatchedOnlineAccounts(infos, accounts)
+		problems += len(unmatched)
+	}
+
+	header := []string{"FILE", "ADDRESS", "FIRST", "LAST", "KEY DILUTION"}
+	if partkeyVerify {
+		header = append(header, "STATUS")
+	}
+	rows := make([][]string, 0, len(parts))
+	for _, filename := range filenames {
+		info := infos[filename]
+		row := []string{filename, info.Address, fmt.Sprintf("%d", info.FirstValid), fmt.Sprintf("%d", info.LastValid), fmt.Sprintf("%d", info.VoteKeyDilution)}
+		if partkeyVerify {
+			row = append(row, info.Status)
+		}
+		rows = append(rows, row)
+	}
+
+	reportRows(infos, header, rows, func() {
+		for _, filename := range filenames {
+			info := infos[filename]
+			fmt.Println("------------------------------------------------------------------")
+			infoString := protocol.EncodeJSON(&info)
+			fmt.Printf("File: %s\n%s\n", filename, string(infoString))
+		}
+	})
+
+	for _, addr := range unmatched {
+		reportWarnf(warnOnlineWithoutPartkey, addr)
+	}
+	if problems > 0 {
+		return fmt.Errorf(errorPartkeyVerify, problems)
+	}
+	return nil
+}
+
+// Statuses of a local participation key, compared with the chain
+const (
+	partkeyRegistered    = "registered"
+	partkeyNotRegistered = "not registered"
+)
+
+// partkeyStatus compares a local participation key with the one registered
+// on chain for its account. A key whose voting and selection keys are
+// registered with different parameters is a mismatch, listing the fields
+// that differ.
+func partkeyStatus(info partkeyInfo, acct models.Account) string {
+	reg := acct.Participation
+	if acct.Status != basics.Online.String() || reg == nil ||
+		!bytes.Equal(reg.ParticipationPK, info.VoteID) || !bytes.Equal(reg.VRFPK, info.SelectionID) {
+		return partkeyNotRegistered
+	}
+
+	var diffs []string
+	if reg.VoteFirst != uint64(info.FirstValid) {
+		diffs = append(diffs, "first valid")
+	}
+	if reg.VoteLast != uint64(info.LastValid) {
+		diffs = append(diffs, "last valid")
+	}
+	if reg.VoteKeyDilution != info.VoteKeyDilution {
+		diffs = append(diffs, "key dilution")
+	}
+	if len(diffs) > 0 {
+		return "mismatch: " + strings.Join(diffs, ", ")
+	}
+	return partkeyRegistered
+}
+
+// unmatchedOnlineAccounts returns the sorted addresses of the online accounts
+// none of whose local keys is registered
+func unmatchedOnlineAccounts(infos map[string]partkeyInfo, accounts map[string]models.Account) []string {
+	matched := make(map[string]bool)
+	for _, info := range infos {
+		if info.Status == partkeyRegistered {
+			matched[info.Address] = true
+		}
+	}
+
+	var unmatched []string
+	for addr, acct := range accounts {
+		if acct.Status == basics.Online.String() && !matched[addr] {
+			unmatched = append(unmatched, addr)
+		}
+	}
+	sort.Strings(unmatched)
+	return unmatched
 }
@@ -0,0 +1,160 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/libgoal"
+)
+
+// defaultTransactionsRounds is the number of latest rounds goal account transactions walks by default
+const defaultTransactionsRounds = 1000
+
+var (
+	transactionsAddress    string
+	transactionsFirstRound uint64
+	transactionsLastRound  uint64
+	transactionsMax        uint64
+)
+
+func init() {
+	accountCmd.AddCommand(accountTransactionsCmd)
+
+	accountTransactionsCmd.Flags().StringVarP(&transactionsAddress, "address", "a", "", "Account to list the transactions of")
+	accountTransactionsCmd.Flags().Uint64Var(&transactionsFirstRound, "firstRound", 0, "First round to list the transactions of (default 999 rounds before the last round)")
+	accountTransactionsCmd.Flags().Uint64Var(&transactionsLastRound, "lastRound", 0, "Last round to list the transactions of (default the latest round)")
+	accountTransactionsCmd.Flags().Uint64Var(&transactionsMax, "max", 100, "Number of most recent transactions to list when the node runs the transaction indexer and no round is given")
+	accountTransactionsCmd.MarkFlagRequired("address")
+}
+
+var accountTransactionsCmd = &cobra.Command{
+	Use:   "transactions",
+	Short: "List the transactions of an account",
+	Long: `List the committed transactions that involve an account: those it sent, and those that paid it or closed to it. The node walks the blocks of the given rounds, by default the last 1000 rounds. Without --firstRound nor --lastRound, a node running the transaction indexer lists the --max most recent transactions instead.
+The amount is the change of balance of the account the transaction made, fees excluded, and the fee is the one the account paid.`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		firstGiven, lastGiven := cmd.Flags().Changed("firstRound"), cmd.Flags().Changed("lastRound")
+		onDataDirsReportingErrors(func(dataDir string) error {
+			client := ensureAlgodClient(dataDir)
+
+			if !firstGiven && !lastGiven {
+				list, err := client.RecentTransactionsByAddress(transactionsAddress, transactionsMax)
+				if err == nil {
+					reportAccountTransactions(list.Transactions)
+					return nil
+				}
+				reportVerbosef(infoNoTransactionIndex, err)
+			}
+
+			first, last, err := transactionsRounds(client, firstGiven, lastGiven)
+			if err != nil {
+				return err
+			}
+			list, err := client.TransactionsByAddress(transactionsAddress, first, last)
+			if err != nil {
+				return fmt.Errorf(errorRequestFail, err)
+			}
+			reportAccountTransactions(list.Transactions)
+			return nil
+		})
+	},
+}
+
+// transactionsRounds returns the rounds goal account transactions walks
+func transactionsRounds(client libgoal.Client, firstGiven, lastGiven bool) (first, last uint64, err error) {
+	first, last = transactionsFirstRound, transactionsLastRound
+	if !lastGiven {
+		status, err := client.Status()
+		if err != nil {
+			return 0, 0, fmt.Errorf(errorRequestFail, err)
+		}
+		last = status.LastRound
+	}
+	if !firstGiven {
+		first = 0
+		if last >= defaultTransactionsRounds {
+			first = last - defaultTransactionsRounds + 1
+		}
+	}
+	if first > last {
+		return 0, 0, fmt.Errorf(errorTransactionsRounds, first, last)
+	}
+	return first, last, nil
+}
+
+// accountTransaction is a transaction as listed by goal account transactions, from the point of view of the account
+type accountTransaction struct {
+	Round uint64 `json:"round"`
+	TxID  string `json:"txid"`
+	Type  string `json:"type"`
+	// Counterparty is the receiver of the transactions the account sent, and the sender of the others
+	Counterparty string `json:"counterparty,omitempty"`
+	// Amount is the change of balance of the account the transaction made, fees and rewards excluded
+	Amount int64 `json:"amount"`
+	// Fee is the fee the account paid, 0 if it didn't send the transaction
+	Fee uint64 `json:"fee"`
+}
+
+// makeAccountTransaction returns txn from the point of view of the account addr
+func makeAccountTransaction(addr string, txn models.Transaction) accountTransaction {
+	listed := accountTransaction{
+		Round: txn.ConfirmedRound,
+		TxID:  txn.TxID,
+		Type:  string(txn.Type),
+	}
+	if txn.From == addr {
+		listed.Fee = txn.Fee
+	} else {
+		listed.Counterparty = txn.From
+	}
+	if payment := txn.Payment; payment != nil {
+		if txn.From == addr {
+			listed.Counterparty = payment.To
+			listed.Amount -= int64(payment.Amount + payment.CloseAmount)
+		}
+		if payment.To == addr {
+			listed.Amount += int64(payment.Amount)
+		}
+		if payment.CloseRemainderTo == addr {
+			listed.Amount += int64(payment.CloseAmount)
+		}
+	}
+	return listed
+}
+
+func reportAccountTransactions(txns []models.Transaction) {
+	listed := make([]accountTransaction, len(txns))
+	rows := make([][]string, len(txns))
+	for i, txn := range txns {
+		listed[i] = makeAccountTransaction(transactionsAddress, txn)
+		rows[i] = []string{listed[i].TxID, fmt.Sprintf("%d", listed[i].Round), listed[i].Type, listed[i].Counterparty,
+			fmt.Sprintf("%+d", listed[i].Amount), fmt.Sprintf("%d", listed[i].Fee)}
+	}
+	if len(listed) == 0 {
+		reportInfoln(infoNoTransactions)
+	}
+	reportRows(listed, []string{"TXID", "ROUND", "TYPE", "COUNTERPARTY", "AMOUNT", "FEE"}, rows, func() {
+		for _, row := range rows {
+			fmt.Printf("%s\tround %s\t%s\t%s\t%s microAlgos\tfee %s\n", row[0], row[1], row[2], row[3], row[4], row[5])
+		}
+	})
+}
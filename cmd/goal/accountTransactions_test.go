@@ -0,0 +1,41 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+)
+
+func TestMakeAccountTransaction(t *testing.T) {
+	a, b, c := testAddress(1), testAddress(2), testAddress(3)
+	pay := models.Transaction{TxID: "T", ConfirmedRound: 7, Type: "pay", From: a, Fee: 1000,
+		Payment: &models.PaymentTransactionType{To: b, Amount: 50, CloseRemainderTo: c, CloseAmount: 20}}
+
+	require.Equal(t, accountTransaction{Round: 7, TxID: "T", Type: "pay", Counterparty: b, Amount: -70, Fee: 1000}, makeAccountTransaction(a, pay))
+	require.Equal(t, accountTransaction{Round: 7, TxID: "T", Type: "pay", Counterparty: a, Amount: 50}, makeAccountTransaction(b, pay))
+	require.Equal(t, accountTransaction{Round: 7, TxID: "T", Type: "pay", Counterparty: a, Amount: 20}, makeAccountTransaction(c, pay))
+
+	self := models.Transaction{TxID: "S", Type: "pay", From: a, Fee: 1000, Payment: &models.PaymentTransactionType{To: a, Amount: 50}}
+	require.Equal(t, accountTransaction{TxID: "S", Type: "pay", Counterparty: a, Fee: 1000}, makeAccountTransaction(a, self))
+
+	keyreg := models.Transaction{TxID: "K", Type: "keyreg", From: a, Fee: 1000}
+	require.Equal(t, accountTransaction{TxID: "K", Type: "keyreg", Fee: 1000}, makeAccountTransaction(a, keyreg))
+}
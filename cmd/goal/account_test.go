@@ -0,0 +1,271 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/passphrase"
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/libgoal"
+)
+
+func TestVanityMatcher(t *testing.T) {
+	_, err := makeVanityMatcher("", "", "")
+	require.Error(t, err)
+	_, err = makeVanityMatcher("AB1", "", "")
+	require.Error(t, err)
+	_, err = makeVanityMatcher("", "", "[")
+	require.Error(t, err)
+	_, err = makeVanityMatcher(strings.Repeat("A", 40), strings.Repeat("A", 20), "")
+	require.Error(t, err)
+
+	m, err := makeVanityMatcher("ab", "q", "^AB7")
+	require.NoError(t, err)
+	require.Equal(t, "prefix AB, suffix Q, regex ^AB7", m.String())
+	require.True(t, m.matches("AB7XQ"))
+	require.False(t, m.matches("AB6XQ"))
+	require.False(t, m.matches("AB7XA"))
+	require.False(t, m.matches("XAB7Q"))
+}
+
+func TestSearchVanityAddress(t *testing.T) {
+	m, err := makeVanityMatcher("A", "", "")
+	require.NoError(t, err)
+
+	var attempts uint64
+	seed, addr := searchVanityAddress(m, 4, &attempts)
+	require.True(t, strings.HasPrefix(addr, "A"))
+	require.NotZero(t, attempts)
+
+	// the seed is the key of the address, and exports as a mnemonic
+	secrets := crypto.GenerateSignatureSecrets(seed)
+	require.Equal(t, addr, basics.Address(secrets.SignatureVerifier).GetChecksumAddress().String())
+	mnemonic, err := passphrase.KeyToMnemonic(seed[:])
+	require.NoError(t, err)
+	key, err := passphrase.MnemonicToKey(mnemonic)
+	require.NoError(t, err)
+	require.Equal(t, seed[:], key)
+}
+
+func TestConfirmNonparticipating(t *testing.T) {
+	require.True(t, confirmNonparticipating(strings.NewReader("yes\n"), "A"))
+	require.True(t, confirmNonparticipating(strings.NewReader(" YES"), "A"))
+	require.False(t, confirmNonparticipating(strings.NewReader("y\n"), "A"))
+	require.False(t, confirmNonparticipating(strings.NewReader(""), "A"))
+}
+
+func TestPartkeyStatus(t *testing.T) {
+	info := partkeyInfo{
+		Address:         "A",
+		FirstValid:      1,
+		LastValid:       1000,
+		VoteID:          []byte{1},
+		SelectionID:     []byte{2},
+		VoteKeyDilution: 100,
+	}
+	reg := models.Participation{
+		ParticipationPK: []byte{1},
+		VRFPK:           []byte{2},
+		VoteFirst:       1,
+		VoteLast:        1000,
+		VoteKeyDilution: 100,
+	}
+	online := basics.Online.String()
+
+	require.Equal(t, partkeyRegistered, partkeyStatus(info, models.Account{Status: online, Participation: &reg}))
+	require.Equal(t, partkeyNotRegistered, partkeyStatus(info, models.Account{Status: basics.Offline.String()}))
+	require.Equal(t, partkeyNotRegistered, partkeyStatus(info, models.Account{Status: online}))
+
+	other := reg
+	other.VRFPK = []byte{3}
+	require.Equal(t, partkeyNotRegistered, partkeyStatus(info, models.Account{Status: online, Participation: &other}))
+
+	other = reg
+	other.VoteLast = 2000
+	other.VoteKeyDilution = 10
+	require.Equal(t, "mismatch: last valid, key dilution", partkeyStatus(info, models.Account{Status: online, Participation: &other}))
+}
+
+func TestUnmatchedOnlineAccounts(t *testing.T) {
+	infos := map[string]partkeyInfo{
+		"a.partkey":     {Address: "A", Status: partkeyRegistered},
+		"b.partkey":     {Address: "B", Status: partkeyNotRegistered},
+		"old-a.partkey": {Address: "A", Status: partkeyNotRegistered},
+	}
+	accounts := map[string]models.Account{
+		"A": {Status: basics.Online.String()},
+		"B": {Status: basics.Online.String()},
+		"C": {Status: basics.Online.String()},
+		"D": {Status: basics.Offline.String()},
+	}
+	require.Equal(t, []string{"B", "C"}, unmatchedOnlineAccounts(infos, accounts))
+}
+
+func TestResolveMultisigMembers(t *testing.T) {
+	var addrs []string
+	for i := 0; i < 2; i++ {
+		var seed crypto.Seed
+		seed[0] = byte(i)
+		addrs = append(addrs, basics.Address(crypto.GenerateSignatureSecrets(seed).SignatureVerifier).GetUserAddress())
+	}
+	accountList := &AccountsList{Accounts: map[string]string{addrs[0]: "alice"}}
+
+	members, err := resolveMultisigMembers(accountList, []string{"alice", addrs[1]})
+	require.NoError(t, err)
+	require.Equal(t, addrs, members)
+
+	_, err = resolveMultisigMembers(accountList, []string{"alice", "bob"})
+	require.Error(t, err)
+}
+
+func TestMultisigFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "goal-msig")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	var pks []crypto.PublicKey
+	var addrs []string
+	for i := 0; i < 3; i++ {
+		var seed crypto.Seed
+		seed[0] = byte(i)
+		pk := crypto.GenerateSignatureSecrets(seed).SignatureVerifier
+		pks = append(pks, crypto.PublicKey(pk))
+		addrs = append(addrs, basics.Address(pk).GetUserAddress())
+	}
+	digest, err := crypto.MultisigAddrGen(1, 2, pks)
+	require.NoError(t, err)
+	info := multisigAccountInfo{Address: basics.Address(digest).GetUserAddress(), Version: 1, Threshold: 2, PKs: addrs}
+
+	filename := filepath.Join(tempDir, "msig.json")
+	require.NoError(t, writeMultisigFile(filename, info))
+	read, err := readMultisigFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, info, read)
+
+	// the metadata has to make up the address it gives
+	info.Threshold = 3
+	require.NoError(t, writeMultisigFile(filename, info))
+	_, err = readMultisigFile(filename)
+	require.Error(t, err)
+}
+
+func TestSelectListedAddresses(t *testing.T) {
+	addrs := []libgoal.ListedAddress{{Addr: "AAAB"}, {Addr: "ABBB"}, {Addr: "CCCC", Multisig: true}, {Addr: "DDDD"}}
+	accountList := &AccountsList{Accounts: map[string]string{"CCCC": "Team", "DDDD": "bob"}}
+
+	require.Equal(t, addrs, selectListedAddresses(addrs, accountList, "", 0, 0))
+	require.Equal(t, addrs[1:3], selectListedAddresses(addrs, accountList, "", 1, 2))
+	require.Equal(t, addrs[3:], selectListedAddresses(addrs, accountList, "", 3, 10))
+	require.Empty(t, selectListedAddresses(addrs, accountList, "", 4, 0))
+
+	// the filter matches addresses and names, ignoring case, before paging
+	require.Equal(t, addrs[:2], selectListedAddresses(addrs, accountList, "ab", 0, 0))
+	require.Equal(t, addrs[2:3], selectListedAddresses(addrs, accountList, "team", 0, 0))
+	require.Equal(t, addrs[1:2], selectListedAddresses(addrs, accountList, "a", 1, 1))
+	require.Empty(t, selectListedAddresses(addrs, accountList, "zzz", 0, 0))
+}
+
+func TestEstimateRewards(t *testing.T) {
+	// 10 Algos of 1000 participating ones earn a hundredth of the rate
+	require.Equal(t, float64(5), estimateRewardsPerRound(10000000, 500, 1000000000, 1000000))
+	// fractions of a reward unit earn nothing
+	require.Equal(t, float64(0), estimateRewardsPerRound(999999, 500, 1000000000, 1000000))
+	require.Equal(t, float64(0), estimateRewardsPerRound(10000000, 500, 0, 1000000))
+
+	require.Equal(t, float64(24*60*60/4), roundsPerDay(models.Block{Round: 100, Timestamp: 1000}, models.Block{Round: 200, Timestamp: 1400}))
+	require.Equal(t, float64(0), roundsPerDay(models.Block{Round: 100, Timestamp: 1000}, models.Block{Round: 100, Timestamp: 1000}))
+
+	txns := []models.Transaction{
+		{From: "A", FromRewards: 1, Payment: &models.PaymentTransactionType{To: "B", ToRewards: 10}},
+		{From: "B", FromRewards: 100, Payment: &models.PaymentTransactionType{To: "C", CloseRemainderTo: "A", CloseRewards: 1000}},
+		{From: "A", FromRewards: 10000},
+	}
+	require.Equal(t, uint64(11001), realizedRewards("A", txns))
+	require.Equal(t, uint64(110), realizedRewards("B", txns))
+	require.Equal(t, uint64(0), realizedRewards("C", txns))
+}
+
+func TestMultisigInfoFromTxns(t *testing.T) {
+	var pks []crypto.PublicKey
+	var addrs []string
+	for i := 0; i < 3; i++ {
+		var seed crypto.Seed
+		seed[0] = byte(i)
+		pk := crypto.GenerateSignatureSecrets(seed).SignatureVerifier
+		pks = append(pks, crypto.PublicKey(pk))
+		addrs = append(addrs, basics.Address(pk).GetUserAddress())
+	}
+	msig := func(threshold uint8, pks []crypto.PublicKey) crypto.MultisigSig {
+		m := crypto.MultisigSig{Version: 1, Threshold: threshold}
+		for _, pk := range pks {
+			m.Subsigs = append(m.Subsigs, crypto.MultisigSubsig{Key: pk})
+		}
+		return m
+	}
+	digest, err := crypto.MultisigAddrGen(1, 2, pks)
+	require.NoError(t, err)
+	addr := basics.Address(digest).GetUserAddress()
+	other, err := crypto.MultisigAddrGen(1, 1, pks[:2])
+	require.NoError(t, err)
+
+	txns := []transactions.SignedTxn{{}, {Msig: msig(1, pks[:2])}, {Msig: msig(2, pks)}}
+	info, err := multisigInfoFromTxns(txns, "")
+	require.NoError(t, err)
+	require.Equal(t, basics.Address(other).GetUserAddress(), info.Address)
+
+	info, err = multisigInfoFromTxns(txns, addr)
+	require.NoError(t, err)
+	require.Equal(t, multisigAccountInfo{Address: addr, Version: 1, Threshold: 2, PKs: addrs}, info)
+
+	_, err = multisigInfoFromTxns(txns[:2], addr)
+	require.Error(t, err)
+	_, err = multisigInfoFromTxns(txns[:1], "")
+	require.Error(t, err)
+}
+
+func TestBalanceWatchReached(t *testing.T) {
+	require.False(t, balanceWatch{}.reached(0))
+	require.False(t, balanceWatch{above: 100}.reached(1000))
+
+	watch := balanceWatch{above: 100, hasAbove: true}
+	require.False(t, watch.reached(99))
+	require.True(t, watch.reached(100))
+
+	watch = balanceWatch{below: 10, hasBelow: true, above: 100, hasAbove: true}
+	require.True(t, watch.reached(0))
+	require.True(t, watch.reached(10))
+	require.False(t, watch.reached(50))
+	require.True(t, watch.reached(200))
+}
+
+func TestEstimateTimeLeft(t *testing.T) {
+	require.Equal(t, time.Duration(0), estimateTimeLeft(time.Minute, 0, 100))
+	require.Equal(t, 3*time.Minute, estimateTimeLeft(time.Minute, 25, 100))
+	require.Equal(t, time.Duration(0), estimateTimeLeft(time.Minute, 100, 100))
+	require.Equal(t, 2*time.Second, estimateTimeLeft(1400*time.Millisecond, 400000, 1000000))
+}
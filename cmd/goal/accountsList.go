@@ -23,22 +23,36 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/libgoal"
 )
 
+// accountListVersion is the version of the account list file format. The files of version 1 have no Version, and
+// store the data directory they were last written from.
+const accountListVersion = 2
+
 // AccountsList holds a mapping between the account's address, its friendly name and whether it's a default one.
+// The list of a public network is shared by the data directories of its nodes, so DefaultWalletIDs keeps the
+// default wallet of each of them, and DefaultWalletID the one most recently chosen, which the others fall back to.
 type AccountsList struct {
-	Accounts        map[string]string
-	DefaultAccount  string
-	DefaultWalletID string
-	DataDir         string
+	Version          int
+	Accounts         map[string]string
+	DefaultAccount   string
+	DefaultWalletID  string
+	DefaultWalletIDs map[string]string `json:",omitempty"`
+	DataDir          string            `json:"-"`
 }
 
 func makeAccountsList(dataDir string) *AccountsList {
 	acctList := &AccountsList{
+		Version:  accountListVersion,
 		DataDir:  dataDir,
 		Accounts: map[string]string{},
 	}
@@ -46,6 +60,91 @@ func makeAccountsList(dataDir string) *AccountsList {
 	return acctList
 }
 
+// decodeAccountList decodes an account list file, and returns the version it was written with.
+func decodeAccountList(raw []byte) (decoded AccountsList, version int, err error) {
+	if err = json.Unmarshal(raw, &decoded); err != nil {
+		return
+	}
+	version = decoded.Version
+	if version == 0 {
+		version = 1
+	}
+	if version > accountListVersion {
+		err = fmt.Errorf(errorAccountListVersion, version, accountListVersion)
+		return
+	}
+	if decoded.Accounts == nil {
+		decoded.Accounts = map[string]string{}
+	}
+	return decoded, version, nil
+}
+
+// normalize fixes the entries the commands can't deal with: the invalid addresses are dropped, the names that are
+// addresses and the duplicate names are renamed, and the default account is cleared if it isn't in the list.
+// It returns a description of each fix.
+func (accountList *AccountsList) normalize() (fixes []string) {
+	addresses := make([]string, 0, len(accountList.Accounts))
+	for address := range accountList.Accounts {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	taken := make(map[string]bool)
+	for _, address := range addresses {
+		name := accountList.Accounts[address]
+		if _, err := basics.UnmarshalChecksumAddress(address); err != nil {
+			delete(accountList.Accounts, address)
+			fixes = append(fixes, fmt.Sprintf(infoAccountListDroppedInvalid, name, address))
+			continue
+		}
+		newName := name
+		if ok, _ := isValidName(name); !ok || name == "" {
+			newName = accountList.getUnnamed()
+		} else if taken[name] {
+			for i := 2; accountList.isTaken(newName); i++ {
+				newName = fmt.Sprintf("%s-%d", name, i)
+			}
+		}
+		if newName != name {
+			accountList.Accounts[address] = newName
+			fixes = append(fixes, fmt.Sprintf(infoAccountListRenamed, address, name, newName))
+		}
+		taken[newName] = true
+	}
+
+	if _, ok := accountList.Accounts[accountList.DefaultAccount]; !ok && accountList.DefaultAccount != "" {
+		fixes = append(fixes, fmt.Sprintf(infoAccountListClearedDefault, accountList.DefaultAccount))
+		accountList.DefaultAccount = ""
+	}
+	return
+}
+
+// reconcile makes the list name exactly the given wallet addresses: the names of the addresses that aren't in any
+// wallet are removed, and the unnamed wallet addresses are given a name.
+func (accountList *AccountsList) reconcile(walletAddresses map[string]bool) (removed []string, added []string) {
+	for address, name := range accountList.Accounts {
+		if !walletAddresses[address] {
+			delete(accountList.Accounts, address)
+			removed = append(removed, fmt.Sprintf("%s (%s)", name, address))
+		}
+	}
+	sort.Strings(removed)
+
+	addresses := make([]string, 0, len(walletAddresses))
+	for address := range walletAddresses {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	for _, address := range addresses {
+		if _, ok := accountList.Accounts[address]; !ok {
+			name := accountList.getUnnamed()
+			accountList.Accounts[address] = name
+			added = append(added, fmt.Sprintf("%s (%s)", name, address))
+		}
+	}
+	return
+}
+
 func isValidName(name string) (bool, string) {
 	if _, err := basics.UnmarshalChecksumAddress(name); err == nil {
 		return false, "An Algorand address cannot be used as an account name."
@@ -60,14 +159,23 @@ func (accountList *AccountsList) accountListFileName() string {
 	if err != nil {
 		reportErrorln(fmt.Sprintf(errorGenesisIDFail, err, dataDir))
 	}
+	fileName, err := accountListFilePath(dataDir, gid)
+	if err != nil {
+		reportErrorln("could not get current user info")
+	}
+	return fileName
+}
+
+// accountListFilePath returns the path of the account list file of the given data directory and genesis.
+func accountListFilePath(dataDir string, genesisID string) (string, error) {
 	if libgoal.AlgorandDataIsPrivate(dataDir) {
-		return filepath.Join(dataDir, gid, "accountList.json")
+		return filepath.Join(dataDir, genesisID, "accountList.json"), nil
 	}
 	cu, err := user.Current()
 	if err != nil {
-		reportErrorln("could not get current user info")
+		return "", err
 	}
-	return filepath.Join(cu.HomeDir, ".algorand", gid, "accountList.json")
+	return filepath.Join(cu.HomeDir, ".algorand", genesisID, "accountList.json"), nil
 }
 
 // isDefault returns true, if the account is marked is default, false otherwise. If account doesn't exist isDefault
@@ -77,15 +185,30 @@ func (accountList *AccountsList) isDefault(accountAddress string) bool {
 }
 
 func (accountList *AccountsList) setDefaultWalletID(ID []byte) {
-	// Update the default ID
+	// Update the default ID, of this data directory and of those without one
 	accountList.DefaultWalletID = string(ID)
+	if accountList.DefaultWalletIDs == nil {
+		accountList.DefaultWalletIDs = map[string]string{}
+	}
+	accountList.DefaultWalletIDs[accountList.dataDirKey()] = string(ID)
 	accountList.dumpList()
 }
 
 func (accountList *AccountsList) getDefaultWalletID() []byte {
+	if ID, ok := accountList.DefaultWalletIDs[accountList.dataDirKey()]; ok {
+		return []byte(ID)
+	}
 	return []byte(accountList.DefaultWalletID)
 }
 
+// dataDirKey identifies the data directory of the list in DefaultWalletIDs
+func (accountList *AccountsList) dataDirKey() string {
+	if dir, err := filepath.Abs(accountList.DataDir); err == nil {
+		return dir
+	}
+	return accountList.DataDir
+}
+
 // setDefault sets the account to default
 func (accountList *AccountsList) setDefault(accountName string) {
 	// Get account address
@@ -122,11 +245,16 @@ func (accountList *AccountsList) rename(oldName, newName string) {
 
 // getUnnamed returns the next available unnamed string
 func (accountList *AccountsList) getUnnamed() string {
+	return accountList.getUnusedName("Unnamed")
+}
+
+// getUnusedName returns the first name of the form prefix-N that isn't taken
+func (accountList *AccountsList) getUnusedName(prefix string) string {
 	var highest int
 	var proposedName string
 
 	for {
-		proposedName = fmt.Sprintf("Unnamed-%d", highest)
+		proposedName = fmt.Sprintf("%s-%d", prefix, highest)
 		if !accountList.isTaken(proposedName) {
 			return proposedName
 		}
@@ -156,8 +284,11 @@ func (accountList *AccountsList) removeAccount(address string) {
 	accountList.dumpList()
 }
 
-// getDefaultAccount returns the default account address
+// getDefaultAccount returns the default account address, which the active profile sets for its data directory
 func (accountList *AccountsList) getDefaultAccount() string {
+	if activeProfile != nil && activeProfile.Account != "" && activeProfile.DataDir == accountList.dataDirKey() {
+		return accountList.getAddressByName(activeProfile.Account)
+	}
 	return accountList.DefaultAccount
 }
 
@@ -171,6 +302,84 @@ func (accountList *AccountsList) getAddressByName(accountName string) string {
 	return accountName
 }
 
+// addressFlags are the names of the flags that take an account address, to which an account name may be given
+// instead
+var addressFlags = map[string]bool{
+	"address":  true,
+	"addr":     true,
+	"from":     true,
+	"to":       true,
+	"close-to": true,
+	"sender":   true,
+
+	"creator":       true,
+	"manager":       true,
+	"reserve":       true,
+	"freezer":       true,
+	"clawback":      true,
+	"clawback-from": true,
+	"new-manager":   true,
+	"new-reserve":   true,
+	"new-freezer":   true,
+	"new-clawback":  true,
+
+	"owner":     true,
+	"receiver":  true,
+	"receiver1": true,
+	"receiver2": true,
+}
+
+// resolveAddressFlags replaces the account names given to the address flags of cmd with their addresses, so that
+// the commands only ever see addresses. The flags that may be repeated are left to the commands.
+func resolveAddressFlags(cmd *cobra.Command) {
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if !addressFlags[flag.Name] || flag.Value.Type() != "string" {
+			return
+		}
+		if address := resolveAccountName(flag.Value.String()); address != flag.Value.String() {
+			flag.Value.Set(address)
+		}
+	})
+}
+
+// resolveAccountName returns the address of the account with the given name in the account lists of the data
+// directories. Addresses, and names that no list has, are returned as they are.
+func resolveAccountName(accountName string) string {
+	if accountName == "" {
+		return accountName
+	}
+	if _, err := basics.UnmarshalChecksumAddress(accountName); err == nil {
+		return accountName
+	}
+
+	var lists []*AccountsList
+	for _, dataDir := range getDataDirs() {
+		lists = append(lists, makeAccountsList(dataDir))
+	}
+	address, err := lookupAccountName(accountName, lists)
+	if err != nil {
+		reportErrorln(err.Error())
+	}
+	return address
+}
+
+// lookupAccountName returns the address that the lists give to the account name, or the name itself if no list
+// has it. It fails when several lists give the name to different accounts.
+func lookupAccountName(accountName string, lists []*AccountsList) (string, error) {
+	resolved := accountName
+	for _, list := range lists {
+		address := list.getAddressByName(accountName)
+		if address == accountName {
+			continue
+		}
+		if resolved != accountName && resolved != address {
+			return "", fmt.Errorf(errorAccountNameAmbiguous, accountName, resolved, address)
+		}
+		resolved = address
+	}
+	return resolved, nil
+}
+
 // getNameByAddress returns an account address given its name. If it doesn't exist, it returns the address itself
 func (accountList *AccountsList) getNameByAddress(address string) string {
 	if name, ok := accountList.Accounts[address]; ok {
@@ -192,40 +401,181 @@ func (accountList *AccountsList) dumpList() {
 }
 
 // loadList loads the account list from the json file, if the latter doesn't exist, it creates a new *in-memory* one.
+// The files of older versions are migrated in place, keeping a backup of the original file.
 func (accountList *AccountsList) loadList() {
 	// First, check if the file exists.
 	filename := accountList.accountListFileName()
-	if _, err := os.Stat(filename); err == nil {
-		raw, err := ioutil.ReadFile(filename)
-		if err != nil {
-			log.Error(err.Error())
+	if _, err := os.Stat(filename); err != nil {
+		return
+	}
+	raw, err := ioutil.ReadFile(filename)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	decoded, version, err := decodeAccountList(raw)
+	if err != nil {
+		reportErrorf(errorAccountListLoad, filename, err)
+	}
+	accountList.Accounts, accountList.DefaultAccount = decoded.Accounts, decoded.DefaultAccount
+	accountList.DefaultWalletID, accountList.DefaultWalletIDs = decoded.DefaultWalletID, decoded.DefaultWalletIDs
+
+	if version < accountListVersion {
+		backup := fmt.Sprintf("%s.v%d.bak", filename, version)
+		if err := ioutil.WriteFile(backup, raw, 0644); err != nil {
+			reportErrorf(errorAccountListLoad, filename, err)
+		}
+		for _, fix := range accountList.normalize() {
+			log.Infof("%s: %s", filename, fix)
 		}
-		json.Unmarshal(raw, &accountList)
+		accountList.dumpList()
+		log.Infof("Migrated %s from version %d to %d, keeping the original in %s", filename, version, accountListVersion, backup)
 	}
 }
 
-func (accountList *AccountsList) outputAccount(addr string, acctInfo models.Account, multisigInfo *libgoal.MultisigInfo) {
-	if acctInfo.Address == "" {
-		fmt.Printf("[n/a]\t%s\t%s\t[n/a] microAlgos", accountList.getNameByAddress(addr), addr)
-	} else {
-		var status string
+// listedAccountColumns are the table columns of goal account list.
+var listedAccountColumns = []string{"ADDRESS", "NAME", "STATUS", "AMOUNT", "MULTISIG", "DEFAULT"}
+
+// listedAccount is an account as listed by goal account list. Status and
+// Amount are left empty when algod could not be reached.
+type listedAccount struct {
+	Address  string          `json:"address"`
+	Name     string          `json:"name"`
+	Status   string          `json:"status,omitempty"`
+	Amount   *uint64         `json:"amount,omitempty"`
+	Multisig *listedMultisig `json:"multisig,omitempty"`
+	Default  bool            `json:"default"`
+	Pending  *listedPending  `json:"pending,omitempty"`
+	Assets   []listedAsset   `json:"assets,omitempty"`
+}
+
+// listedAsset is the holding of an asset by a listed account.
+type listedAsset struct {
+	Index  uint64 `json:"index"`
+	Amount uint64 `json:"amount"`
+	Frozen bool   `json:"frozen,omitempty"`
+}
+
+// listedMultisig is the threshold and number of keys of a listed multisig account.
+type listedMultisig struct {
+	Threshold uint8 `json:"threshold"`
+	Size      int   `json:"size"`
+}
+
+// listedPendingColumns are the table columns goal account list --pending adds.
+var listedPendingColumns = []string{"PENDING", "PENDING DELTA"}
+
+// listedPending is the effect on a listed account of the transactions pending in the transaction pool.
+type listedPending struct {
+	// Txns is the number of pending transactions the account sends
+	Txns uint64 `json:"txns"`
+	// Delta is the change of balance the pending transactions make: the amounts the account receives minus those
+	// it sends and the fees it pays. The remainder of a closed account isn't known until the closing is committed.
+	Delta int64 `json:"delta"`
+	// CloseTo is the account a pending transaction closes the account to, if any
+	CloseTo string `json:"closeto,omitempty"`
+}
+
+// addPendingEffects adds the effect of the pending transactions txns to those of the accounts in pending
+func addPendingEffects(pending map[string]*listedPending, txns []models.Transaction) {
+	for _, txn := range txns {
+		sender := pending[txn.From]
+		if sender != nil {
+			sender.Txns++
+			sender.Delta -= int64(txn.Fee)
+		}
+		if txn.Payment == nil || txn.Payment.To == txn.From {
+			continue
+		}
+		if sender != nil {
+			sender.Delta -= int64(txn.Payment.Amount)
+			if txn.Payment.CloseRemainderTo != "" {
+				sender.CloseTo = txn.Payment.CloseRemainderTo
+			}
+		}
+		if receiver := pending[txn.Payment.To]; receiver != nil {
+			receiver.Delta += int64(txn.Payment.Amount)
+		}
+	}
+}
+
+func (accountList *AccountsList) listAccount(addr string, acctInfo models.Account, multisigInfo *libgoal.MultisigInfo) listedAccount {
+	account := listedAccount{
+		Address: addr,
+		Name:    accountList.getNameByAddress(addr),
+		Default: accountList.isDefault(addr),
+	}
+	if acctInfo.Address != "" {
 		switch acctInfo.Status {
 		case basics.Online.String():
-			status = "online"
+			account.Status = "online"
 		case basics.Offline.String():
-			status = "offline"
+			account.Status = "offline"
 		case basics.NotParticipating.String():
-			status = "excluded"
+			account.Status = "excluded"
 		default:
 			panic(fmt.Sprintf("unexpected account status: %v", acctInfo.Status))
 		}
-		fmt.Printf("[%s]\t%s\t%s\t%d microAlgos", status, accountList.getNameByAddress(addr), addr, acctInfo.Amount)
+		amount := acctInfo.Amount
+		account.Amount = &amount
+		for _, index := range heldAssetIndices(acctInfo) {
+			holding := acctInfo.Assets[index]
+			account.Assets = append(account.Assets, listedAsset{Index: index, Amount: holding.Amount, Frozen: holding.Frozen})
+		}
 	}
 	if multisigInfo != nil {
-		fmt.Printf("\t[%d/%d multisig]", multisigInfo.Threshold, len(multisigInfo.PKs))
+		account.Multisig = &listedMultisig{Threshold: multisigInfo.Threshold, Size: len(multisigInfo.PKs)}
 	}
-	if accountList.isDefault(addr) {
-		fmt.Printf("\t*Default")
+	return account
+}
+
+func (account listedAccount) row() []string {
+	row := []string{account.Address, account.Name, "n/a", "n/a", "", ""}
+	if account.Amount != nil {
+		row[2] = account.Status
+		row[3] = fmt.Sprintf("%d", *account.Amount)
+	}
+	if account.Multisig != nil {
+		row[4] = fmt.Sprintf("%d/%d", account.Multisig.Threshold, account.Multisig.Size)
+	}
+	if account.Default {
+		row[5] = "*"
+	}
+	if account.Pending != nil {
+		row = append(row, fmt.Sprintf("%d", account.Pending.Txns), fmt.Sprintf("%+d", account.Pending.Delta))
+	}
+	return row
+}
+
+func (account listedAccount) text() string {
+	var line string
+	if account.Amount == nil {
+		line = fmt.Sprintf("[n/a]\t%s\t%s\t[n/a] microAlgos", account.Name, account.Address)
+	} else {
+		line = fmt.Sprintf("[%s]\t%s\t%s\t%d microAlgos", account.Status, account.Name, account.Address, *account.Amount)
+	}
+	if account.Multisig != nil {
+		line += fmt.Sprintf("\t[%d/%d multisig]", account.Multisig.Threshold, account.Multisig.Size)
+	}
+	if account.Default {
+		line += "\t*Default"
+	}
+	if len(account.Assets) > 0 {
+		holdings := make([]string, len(account.Assets))
+		for i, asset := range account.Assets {
+			holdings[i] = fmt.Sprintf("%d of #%d", asset.Amount, asset.Index)
+			if asset.Frozen {
+				holdings[i] += " (frozen)"
+			}
+		}
+		line += "\t[assets: " + strings.Join(holdings, ", ") + "]"
+	}
+	if pending := account.Pending; pending != nil && (pending.Txns > 0 || pending.Delta != 0) {
+		line += fmt.Sprintf("\t[pending: %d txns, %+d microAlgos", pending.Txns, pending.Delta)
+		if pending.CloseTo != "" {
+			line += ", closing to " + pending.CloseTo
+		}
+		line += "]"
 	}
-	fmt.Print("\n")
+	return line
 }
@@ -0,0 +1,128 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+func testAddress(b byte) string {
+	var addr basics.Address
+	addr[0] = b
+	return addr.GetChecksumAddress().String()
+}
+
+func TestDecodeAccountList(t *testing.T) {
+	a := testAddress(1)
+	decoded, version, err := decodeAccountList([]byte(`{"Accounts":{"` + a + `":"alice"},"DefaultAccount":"` + a + `","DefaultWalletID":"w","DataDir":"/tmp/x"}`))
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+	require.Equal(t, map[string]string{a: "alice"}, decoded.Accounts)
+	require.Equal(t, a, decoded.DefaultAccount)
+	require.Equal(t, "w", decoded.DefaultWalletID)
+
+	decoded, version, err = decodeAccountList([]byte(`{"Version":2,"Accounts":null,"DefaultWalletIDs":{"/tmp/x":"v"}}`))
+	require.NoError(t, err)
+	require.Equal(t, accountListVersion, version)
+	require.Equal(t, map[string]string{}, decoded.Accounts)
+	require.Equal(t, map[string]string{"/tmp/x": "v"}, decoded.DefaultWalletIDs)
+
+	_, _, err = decodeAccountList([]byte(`{"Version":3}`))
+	require.Error(t, err)
+}
+
+func TestAccountListNormalize(t *testing.T) {
+	a, b, c := testAddress(1), testAddress(2), testAddress(3)
+	list := AccountsList{
+		Accounts: map[string]string{
+			a:             "alice",
+			b:             "alice",
+			c:             a,
+			"not-address": "bob",
+		},
+		DefaultAccount: "not-address",
+	}
+	fixes := list.normalize()
+	require.Len(t, fixes, 4)
+	require.Equal(t, map[string]string{a: "alice", b: "alice-2", c: "Unnamed-0"}, list.Accounts)
+	require.Empty(t, list.DefaultAccount)
+	require.Empty(t, list.normalize())
+}
+
+func TestAccountListReconcile(t *testing.T) {
+	a, b, c := testAddress(1), testAddress(2), testAddress(3)
+	list := AccountsList{Accounts: map[string]string{a: "alice", b: "bob"}}
+	removed, added := list.reconcile(map[string]bool{a: true, c: true})
+	require.Equal(t, []string{"bob (" + b + ")"}, removed)
+	require.Equal(t, []string{"Unnamed-0 (" + c + ")"}, added)
+	require.Equal(t, map[string]string{a: "alice", c: "Unnamed-0"}, list.Accounts)
+}
+
+func TestAccountListUnusedName(t *testing.T) {
+	list := AccountsList{Accounts: map[string]string{testAddress(1): "node-0", testAddress(2): "node-2"}}
+	require.Equal(t, "node-1", list.getUnusedName("node"))
+	require.Equal(t, "Unnamed-0", list.getUnnamed())
+}
+
+func TestLookupAccountName(t *testing.T) {
+	a, b := testAddress(1), testAddress(2)
+	primary := &AccountsList{Accounts: map[string]string{a: "alice", b: "bob"}}
+	secondary := &AccountsList{Accounts: map[string]string{a: "alice", b: "carol"}}
+	other := &AccountsList{Accounts: map[string]string{b: "alice"}}
+
+	address, err := lookupAccountName("alice", []*AccountsList{primary, secondary})
+	require.NoError(t, err)
+	require.Equal(t, a, address)
+
+	// A name that only some lists have resolves through them
+	address, err = lookupAccountName("carol", []*AccountsList{primary, secondary})
+	require.NoError(t, err)
+	require.Equal(t, b, address)
+
+	// Unknown names are left for the commands to report
+	address, err = lookupAccountName("dave", []*AccountsList{primary, secondary})
+	require.NoError(t, err)
+	require.Equal(t, "dave", address)
+
+	_, err = lookupAccountName("alice", []*AccountsList{primary, other})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ambiguous")
+}
+
+func TestAddPendingEffects(t *testing.T) {
+	a, b, c := testAddress(1), testAddress(2), testAddress(3)
+	pay := func(from, to string, amount, fee uint64, closeTo string) models.Transaction {
+		return models.Transaction{From: from, Fee: fee, Payment: &models.PaymentTransactionType{To: to, Amount: amount, CloseRemainderTo: closeTo}}
+	}
+
+	pending := map[string]*listedPending{a: {}, b: {}}
+	addPendingEffects(pending, []models.Transaction{
+		pay(a, b, 100, 1, ""),
+		pay(a, a, 50, 1, ""),
+		pay(c, b, 20, 1, ""),
+		{From: b, Fee: 2},
+		pay(b, c, 10, 1, a),
+	})
+	require.Equal(t, listedPending{Txns: 2, Delta: -102}, *pending[a])
+	require.Equal(t, listedPending{Txns: 2, Delta: 100 + 20 - 2 - 10 - 1, CloseTo: a}, *pending[b])
+	require.Nil(t, pending[c])
+}
@@ -0,0 +1,380 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/libgoal"
+)
+
+var (
+	assetCreator       string
+	assetIndex         uint64
+	assetTotal         uint64
+	assetUnitName      string
+	assetName          string
+	assetURL           string
+	assetDefaultFrozen bool
+	assetManager       string
+	assetReserve       string
+	assetFreezer       string
+	assetClawback      string
+	assetNewManager    string
+	assetNewReserve    string
+	assetNewFreezer    string
+	assetNewClawback   string
+	assetClawbackFrom  string
+	assetFreezeAccount string
+	assetFrozen        bool
+)
+
+func init() {
+	assetCmd.AddCommand(createAssetCmd)
+	assetCmd.AddCommand(configAssetCmd)
+	assetCmd.AddCommand(destroyAssetCmd)
+	assetCmd.AddCommand(sendAssetCmd)
+	assetCmd.AddCommand(freezeAssetCmd)
+	assetCmd.AddCommand(infoAssetCmd)
+
+	assetCmd.PersistentFlags().StringVarP(&walletName, "wallet", "w", "", "Set the wallet to be used for the selected operation")
+
+	createAssetCmd.Flags().StringVar(&assetCreator, "creator", "", "Account address for creating an asset (required)")
+	createAssetCmd.Flags().Uint64Var(&assetTotal, "total", 0, "Total amount of tokens for created asset (required)")
+	createAssetCmd.Flags().StringVar(&assetUnitName, "unitname", "", "Name for the unit of asset")
+	createAssetCmd.Flags().StringVar(&assetName, "name", "", "Name for the entire asset")
+	createAssetCmd.Flags().StringVar(&assetURL, "asseturl", "", "URL where more information about the asset can be retrieved")
+	createAssetCmd.Flags().BoolVar(&assetDefaultFrozen, "defaultfrozen", false, "Freeze the holdings of the asset by default, until the freeze address unfreezes them")
+	createAssetCmd.Flags().StringVar(&assetManager, "manager", "", "Manager address of the asset, which can reconfigure and destroy it (the creator by default; empty to disable for good)")
+	createAssetCmd.Flags().StringVar(&assetReserve, "reserve", "", "Reserve address of the asset, whose holdings are reported as not minted (the creator by default; empty for none)")
+	createAssetCmd.Flags().StringVar(&assetFreezer, "freezer", "", "Freeze address of the asset, which can freeze and unfreeze holdings (the creator by default; empty to disable for good)")
+	createAssetCmd.Flags().StringVar(&assetClawback, "clawback", "", "Clawback address of the asset, which can take units from any holder (the creator by default; empty to disable for good)")
+	createAssetCmd.MarkFlagRequired("creator")
+	createAssetCmd.MarkFlagRequired("total")
+
+	configAssetCmd.Flags().StringVar(&assetManager, "manager", "", "Manager address of the asset, which sends the transaction (the current manager by default)")
+	configAssetCmd.Flags().StringVar(&assetNewManager, "new-manager", "", "New manager address (empty to disable for good)")
+	configAssetCmd.Flags().StringVar(&assetNewReserve, "new-reserve", "", "New reserve address (empty for none)")
+	configAssetCmd.Flags().StringVar(&assetNewFreezer, "new-freezer", "", "New freeze address (empty to disable for good)")
+	configAssetCmd.Flags().StringVar(&assetNewClawback, "new-clawback", "", "New clawback address (empty to disable for good)")
+
+	destroyAssetCmd.Flags().StringVar(&assetManager, "manager", "", "Manager address of the asset, which sends the transaction (the current manager by default)")
+
+	sendAssetCmd.Flags().StringVarP(&account, "from", "f", "", "Account address to send the asset from (If not specified, uses default account)")
+	sendAssetCmd.Flags().StringVarP(&toAddress, "to", "t", "", "Address to send the asset to (required)")
+	sendAssetCmd.Flags().Uint64VarP(&amount, "amount", "a", 0, "The amount of the asset to transfer (required), in units of the asset")
+	sendAssetCmd.Flags().StringVarP(&closeToAddress, "close-to", "c", "", "Remove the asset from the sending account, and send the remaining units to this address")
+	sendAssetCmd.Flags().StringVar(&assetClawbackFrom, "clawback-from", "", "Take the units from this account instead, which only the clawback address of the asset can do; --from must be the clawback address")
+	sendAssetCmd.MarkFlagRequired("to")
+	sendAssetCmd.MarkFlagRequired("amount")
+
+	freezeAssetCmd.Flags().StringVar(&assetFreezer, "freezer", "", "Freeze address of the asset, which sends the transaction (the current freeze address by default)")
+	freezeAssetCmd.Flags().StringVar(&assetFreezeAccount, "account", "", "Account address whose holding of the asset to freeze or unfreeze (required)")
+	freezeAssetCmd.Flags().BoolVar(&assetFrozen, "freeze", false, "Freeze the holding; pass --freeze=false to unfreeze it (required)")
+	freezeAssetCmd.MarkFlagRequired("account")
+	freezeAssetCmd.MarkFlagRequired("freeze")
+
+	for _, cmd := range []*cobra.Command{configAssetCmd, destroyAssetCmd, sendAssetCmd, freezeAssetCmd, infoAssetCmd} {
+		cmd.Flags().StringVar(&assetCreator, "creator", "", "Account address of the creator of the asset (required)")
+		cmd.Flags().Uint64Var(&assetIndex, "assetid", 0, "Index of the asset (required)")
+		cmd.MarkFlagRequired("creator")
+		cmd.MarkFlagRequired("assetid")
+	}
+
+	for _, cmd := range []*cobra.Command{createAssetCmd, configAssetCmd, destroyAssetCmd, sendAssetCmd, freezeAssetCmd} {
+		cmd.Flags().Uint64Var(&fee, "fee", 0, "The transaction fee (automatically determined by default), in microAlgos")
+		cmd.Flags().Uint64Var(&firstValid, "firstvalid", 0, "The first round where the transaction may be committed to the ledger")
+		cmd.Flags().Uint64Var(&lastValid, "lastvalid", 0, "The last round where the transaction may be committed to the ledger")
+		cmd.Flags().BoolVarP(&noWaitAfterSend, "no-wait", "N", false, "Don't wait for transaction to commit")
+	}
+}
+
+var assetCmd = &cobra.Command{
+	Use:   "asset",
+	Short: "Manage assets",
+	Long: `Create, configure, send, freeze and destroy assets. An asset is named by the address of the account that created it, given with --creator, and its index, given with --assetid.
+An account must accept an asset before it can receive any: it accepts one by sending itself 0 units, with goal asset send --amount 0 --from ACCOUNT --to ACCOUNT.`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		//If no arguments passed, we should fallback to help
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+// sentAssetTransaction is what the goal asset commands report once they broadcast a transaction
+type sentAssetTransaction struct {
+	sentTransaction
+	// AssetIndex is the index of the asset goal asset create created, once the transaction committed
+	AssetIndex uint64 `json:"assetIndex,omitempty"`
+}
+
+// sendAssetTransaction fills in the header of tx with the given sender and the validity and fee flags, signs it
+// with the wallet and broadcasts it, then waits for it to commit unless --no-wait is given.
+func sendAssetTransaction(dataDir string, client libgoal.Client, sender string, tx transactions.Transaction) sentAssetTransaction {
+	// Make sure that back-to-back, similar transactions will have a different txid
+	tx.Note = make([]byte, 8)
+	crypto.RandBytes(tx.Note)
+
+	tx, err := client.FillUnsignedTxTemplate(sender, firstValid, lastValid, fee, tx)
+	if err != nil {
+		reportErrorf(errorConstructingTX, err)
+	}
+
+	// Sign the transaction, with the key the sender has been rekeyed to if any
+	info, err := client.AccountInformation(sender)
+	if err != nil {
+		reportErrorf(errorRequestFail, err)
+	}
+	wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
+	stxn, err := client.SignTransactionWithWalletAndSigner(wh, pw, info.AuthAddr, tx)
+	if err != nil {
+		reportErrorf(errorSigningTX, err)
+	}
+
+	txid, err := client.BroadcastTransaction(stxn)
+	if err != nil {
+		reportErrorf(errorBroadcastingTX, err)
+	}
+	reportInfof(infoAssetTxIssued, tx.Type, txid, tx.Fee.Raw)
+
+	sent := sentAssetTransaction{sentTransaction: sentTransaction{TxID: txid, Fee: tx.Fee.Raw}}
+	if !noWaitAfterSend {
+		sent.ConfirmedRound, err = waitForCommit(client, txid)
+		if err != nil {
+			reportErrorln(err)
+		}
+	}
+	return sent
+}
+
+// lookupAsset returns the parameters of the asset given with --creator and --assetid
+func lookupAsset(client libgoal.Client) models.AssetParams {
+	params, err := client.AssetInformation(assetCreator, assetIndex)
+	if err != nil {
+		reportErrorf(errorRequestFail, err)
+	}
+	return params
+}
+
+var createAssetCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an asset",
+	Long:  `Create an asset, of which the creator account holds all the units. The manager, reserve, freeze and clawback addresses are the creator by default; set one to an empty string to disable it, which can't be undone. Once the transaction commits, the index of the new asset is reported.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureFullClient(dataDir)
+
+		addrs := []*string{&assetManager, &assetReserve, &assetFreezer, &assetClawback}
+		for i, flag := range []string{"manager", "reserve", "freezer", "clawback"} {
+			if !cmd.Flags().Changed(flag) {
+				*addrs[i] = assetCreator
+			}
+		}
+
+		tx, err := client.MakeUnsignedAssetCreateTx(assetTotal, assetDefaultFrozen, assetManager, assetReserve, assetFreezer, assetClawback, assetUnitName, assetName, assetURL)
+		if err != nil {
+			reportErrorf(errorConstructingTX, err)
+		}
+
+		// The creator's existing assets tell which one the transaction creates
+		before, err := client.AccountInformation(assetCreator)
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+
+		sent := sendAssetTransaction(dataDir, client, assetCreator, tx)
+		if sent.ConfirmedRound > 0 {
+			after, err := client.AccountInformation(assetCreator)
+			if err != nil {
+				reportErrorf(errorRequestFail, err)
+			}
+			sent.AssetIndex = createdAssetIndex(before, after)
+			if sent.AssetIndex != 0 {
+				reportInfof(infoAssetCreated, sent.AssetIndex)
+			}
+		}
+		reportResult(sent, sent.TxID, nil)
+	},
+}
+
+// createdAssetIndex returns the index of the newest asset the account created between the two lookups, or 0 if
+// there is none. Asset indices only ever increase, so the newest asset has the largest one.
+func createdAssetIndex(before, after models.Account) uint64 {
+	var newest uint64
+	for index := range after.AssetParams {
+		if _, ok := before.AssetParams[index]; !ok && index > newest {
+			newest = index
+		}
+	}
+	return newest
+}
+
+var configAssetCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Change the addresses of an asset",
+	Long:  `Change the manager, reserve, freeze and clawback addresses of an asset. The manager of the asset sends the transaction. The addresses not given keep their current value; an address set to an empty string is disabled, which can't be undone.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureFullClient(dataDir)
+
+		if assetManager == "" {
+			assetManager = lookupAsset(client).ManagerAddr
+		}
+
+		// The addresses not given are left alone, and those given empty are cleared
+		var newAddrs [4]*string
+		for i, flag := range []string{"new-manager", "new-reserve", "new-freezer", "new-clawback"} {
+			if cmd.Flags().Changed(flag) {
+				value, _ := cmd.Flags().GetString(flag)
+				newAddrs[i] = &value
+			}
+		}
+
+		tx, err := client.MakeUnsignedAssetConfigTx(assetCreator, assetIndex, newAddrs[0], newAddrs[1], newAddrs[2], newAddrs[3])
+		if err != nil {
+			reportErrorf(errorConstructingTX, err)
+		}
+		sent := sendAssetTransaction(dataDir, client, assetManager, tx)
+		reportResult(sent, sent.TxID, nil)
+	},
+}
+
+var destroyAssetCmd = &cobra.Command{
+	Use:   "destroy",
+	Short: "Destroy an asset",
+	Long:  `Destroy an asset. The manager of the asset sends the transaction, and the creator account must hold all the units of the asset.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureFullClient(dataDir)
+
+		if assetManager == "" {
+			assetManager = lookupAsset(client).ManagerAddr
+		}
+
+		tx, err := client.MakeUnsignedAssetDestroyTx(assetCreator, assetIndex)
+		if err != nil {
+			reportErrorf(errorConstructingTX, err)
+		}
+		sent := sendAssetTransaction(dataDir, client, assetManager, tx)
+		reportResult(sent, sent.TxID, nil)
+	},
+}
+
+var sendAssetCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Transfer assets",
+	Long:  `Transfer units of an asset from one account to another, which must have accepted the asset. Sending 0 units from an account to itself makes it accept the asset. With --close-to, the sending account gives up the asset, and the units it has left go to the --close-to address. With --clawback-from, the clawback address of the asset, given with --from, takes the units from that account, even if its holding is frozen.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureFullClient(dataDir)
+
+		// Check if from was specified, else use default
+		if account == "" {
+			account = makeAccountsList(dataDir).getDefaultAccount()
+		}
+
+		tx, err := client.MakeUnsignedAssetSendTx(assetCreator, assetIndex, amount, toAddress, closeToAddress, assetClawbackFrom)
+		if err != nil {
+			reportErrorf(errorConstructingTX, err)
+		}
+		sent := sendAssetTransaction(dataDir, client, account, tx)
+		reportResult(sent, sent.TxID, nil)
+	},
+}
+
+var freezeAssetCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Freeze or unfreeze the holding of an asset by an account",
+	Long:  `Freeze or unfreeze the holding of an asset by an account. A frozen holding can neither send nor receive units, except through the clawback address of the asset. The freeze address of the asset sends the transaction.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureFullClient(dataDir)
+
+		if assetFreezer == "" {
+			assetFreezer = lookupAsset(client).FreezeAddr
+		}
+
+		tx, err := client.MakeUnsignedAssetFreezeTx(assetCreator, assetIndex, resolveAccountName(assetFreezeAccount), assetFrozen)
+		if err != nil {
+			reportErrorf(errorConstructingTX, err)
+		}
+		sent := sendAssetTransaction(dataDir, client, assetFreezer, tx)
+		reportResult(sent, sent.TxID, nil)
+	},
+}
+
+var infoAssetCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Look up the parameters of an asset",
+	Long:  `Look up the parameters of an asset: its names, its total supply, the units held in its reserve, and its manager, reserve, freeze and clawback addresses.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		client := ensureAlgodClient(ensureSingleDataDir())
+		params, err := client.AssetInformation(assetCreator, assetIndex)
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+
+		var reserve *uint64
+		if params.ReserveAddr != "" {
+			info, err := client.AccountInformation(params.ReserveAddr)
+			if err != nil {
+				reportErrorf(errorRequestFail, err)
+			}
+			amount := info.Assets[assetIndex].Amount
+			reserve = &amount
+		}
+
+		reportResult(params, fmt.Sprintf("%d", assetIndex), func() {
+			printAssetParams(assetIndex, params, reserve)
+		})
+	},
+}
+
+func printAssetParams(index uint64, params models.AssetParams, reserve *uint64) {
+	orNone := func(s string) string {
+		if s == "" {
+			return "<none>"
+		}
+		return s
+	}
+	fmt.Printf("Asset ID:         %d\n", index)
+	fmt.Printf("Creator:          %s\n", params.Creator)
+	fmt.Printf("Asset name:       %s\n", params.AssetName)
+	fmt.Printf("Unit name:        %s\n", params.UnitName)
+	fmt.Printf("URL:              %s\n", params.URL)
+	fmt.Printf("Maximum issue:    %d %s\n", params.Total, params.UnitName)
+	if reserve != nil {
+		fmt.Printf("Reserve amount:   %d %s\n", *reserve, params.UnitName)
+		fmt.Printf("Issued:           %d %s\n", params.Total-*reserve, params.UnitName)
+	}
+	fmt.Printf("Default frozen:   %v\n", params.DefaultFrozen)
+	fmt.Printf("Manager address:  %s\n", orNone(params.ManagerAddr))
+	fmt.Printf("Reserve address:  %s\n", orNone(params.ReserveAddr))
+	fmt.Printf("Freeze address:   %s\n", orNone(params.FreezeAddr))
+	fmt.Printf("Clawback address: %s\n", orNone(params.ClawbackAddr))
+}
@@ -0,0 +1,254 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	algodAcct "github.com/algorand/go-algorand/data/account"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/libgoal"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/logging/telemetryspec"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var (
+	renewThresholdRounds uint64
+	renewValidRounds     uint64
+)
+
+// renewRetryRounds is the number of rounds autorenew waits before trying again to renew a key it failed to renew
+const renewRetryRounds = 10
+
+// renewNodeRetryDelay is how long autorenew waits before asking the node for the next round again when it is unreachable
+const renewNodeRetryDelay = 5 * time.Second
+
+func init() {
+	accountCmd.AddCommand(autoRenewParticipationKeyCmd)
+
+	autoRenewParticipationKeyCmd.Flags().Uint64Var(&renewThresholdRounds, "threshold-rounds", 0, "Renew a participation key once it expires within this many rounds")
+	autoRenewParticipationKeyCmd.MarkFlagRequired("threshold-rounds")
+	autoRenewParticipationKeyCmd.Flags().Uint64Var(&renewValidRounds, "validrounds", 0, "Number of rounds the renewed participation keys are valid for")
+	autoRenewParticipationKeyCmd.MarkFlagRequired("validrounds")
+	autoRenewParticipationKeyCmd.Flags().Uint64VarP(&transactionFee, "fee", "f", 0, "The Fee to set on the status change transactions (defaults to suggested fee)")
+	autoRenewParticipationKeyCmd.Flags().Uint64VarP(&keyDilution, "keyDilution", "", 0, "Key dilution for two-level participation keys")
+}
+
+var autoRenewParticipationKeyCmd = &cobra.Command{
+	Use:   "autorenew",
+	Short: "Keep renewing participation keys before they expire",
+	Long:  `Watch the participation keys of the online accounts of the node, and generate and register a replacement for each key as soon as it expires within --threshold-rounds rounds. Runs until interrupted. Keys that already expired are left to renewpartkey. Renewals are reported to telemetry when it is enabled.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if renewValidRounds <= renewThresholdRounds {
+			reportErrorf(errorRenewValidRounds, renewThresholdRounds)
+		}
+
+		dataDir := ensureSingleDataDir()
+		client := ensureFullClient(dataDir)
+
+		// Ask for the wallet password once, rather than for every renewal
+		walletID, name, err := getWalletID(dataDir, walletName)
+		if err != nil {
+			reportErrorln(err)
+		}
+		var pw []byte
+		if !client.WalletIsUnencrypted(walletID) {
+			pw = ensurePasswordForWallet(name)
+		}
+		_, err = client.GetWalletHandleTokenCached(walletID, pw)
+		if err != nil {
+			reportErrorf(errGettingToken, name, walletID, err)
+		}
+
+		genesisID, err := client.GenesisID()
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		enableRenewalTelemetry(genesisID)
+
+		renewer := partKeyRenewer{
+			client:   &walletRenewalClient{Client: client, walletID: walletID, pw: pw},
+			failedAt: make(map[basics.Address]uint64),
+		}
+		status, err := client.Status()
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		reportInfof(infoAutoRenewStarted, renewThresholdRounds, status.LastRound)
+		for {
+			renewer.renewExpiring(status.LastRound)
+
+			next, err := client.WaitForRound(status.LastRound + 1)
+			for err != nil {
+				reportWarnf(warnAutoRenewNode, err)
+				time.Sleep(renewNodeRetryDelay)
+				next, err = client.WaitForRound(status.LastRound + 1)
+			}
+			status = next
+		}
+	},
+}
+
+// enableRenewalTelemetry sends the renewal events to telemetry, if the telemetry of the host is enabled
+func enableRenewalTelemetry(genesisID string) {
+	// Same as algod, keep telemetry off for tests
+	if os.Getenv("ALGOTEST") != "" {
+		return
+	}
+	cfg, err := logging.EnsureTelemetryConfig(nil, genesisID)
+	if err != nil || !cfg.Enable {
+		return
+	}
+	err = log.EnableTelemetry(cfg)
+	if err != nil {
+		reportWarnf(warnAutoRenewTelemetry, err)
+	}
+}
+
+// partKeyRenewalClient is what partKeyRenewer needs from the node: its keys, the status of their accounts, and a way
+// to generate and register a new key
+type partKeyRenewalClient interface {
+	ListParticipationKeys() (map[string]algodAcct.Participation, error)
+	AccountInformation(address string) (models.Account, error)
+	RenewParticipationKey(address, authAddr string, first, last uint64) (txid string, err error)
+}
+
+// partKeyRenewer renews the participation keys of a node that are about to expire
+type partKeyRenewer struct {
+	client partKeyRenewalClient
+
+	// failedAt holds the round of the last failed renewal of each account, to hold off retrying
+	failedAt map[basics.Address]uint64
+}
+
+// renewExpiring renews the latest participation key of every online account that expires within the threshold
+func (r *partKeyRenewer) renewExpiring(round uint64) {
+	parts, err := r.client.ListParticipationKeys()
+	if err != nil {
+		reportWarnf(errorRequestFail, err)
+		return
+	}
+
+	latest := make(map[basics.Address]algodAcct.Participation)
+	for _, part := range parts {
+		if existing, has := latest[part.Address()]; !has || part.LastValid > existing.LastValid {
+			latest[part.Address()] = part
+		}
+	}
+	addresses := make([]basics.Address, 0, len(latest))
+	for address := range latest {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].String() < addresses[j].String() })
+
+	for _, address := range addresses {
+		part := latest[address]
+		if uint64(part.LastValid) < round || uint64(part.LastValid) > round+renewThresholdRounds {
+			continue
+		}
+		if failed, has := r.failedAt[address]; has && round < failed+renewRetryRounds {
+			continue
+		}
+
+		info, err := r.client.AccountInformation(address.GetUserAddress())
+		if err != nil {
+			reportWarnf(errorRequestFail, err)
+			continue
+		}
+		if info.Status != basics.Online.String() {
+			continue
+		}
+
+		first, last := round, round+renewValidRounds
+		txid, err := r.client.RenewParticipationKey(address.GetUserAddress(), info.AuthAddr, first, last)
+		if err != nil {
+			r.failedAt[address] = round
+			reportWarnf(warnPartKeyRenewalFailed, address.GetUserAddress(), err)
+			log.EventWithDetails(telemetryspec.Accounts, telemetryspec.PartKeyRenewalFailedEvent, telemetryspec.PartKeyRenewalFailedEventDetails{
+				Address:       address.GetUserAddress(),
+				PrevLastValid: uint64(part.LastValid),
+				Error:         err.Error(),
+			})
+			continue
+		}
+		delete(r.failedAt, address)
+		reportInfof(infoPartKeyRenewed, address.GetUserAddress(), first, last, part.LastValid)
+		log.EventWithDetails(telemetryspec.Accounts, telemetryspec.PartKeyRenewedEvent, telemetryspec.PartKeyRenewedEventDetails{
+			Address:       address.GetUserAddress(),
+			FirstValid:    first,
+			LastValid:     last,
+			PrevLastValid: uint64(part.LastValid),
+			TxID:          txid,
+		})
+	}
+}
+
+// walletRenewalClient registers the renewed participation keys with transactions signed by a wallet of the node
+type walletRenewalClient struct {
+	libgoal.Client
+	walletID []byte
+	pw       []byte
+}
+
+// RenewParticipationKey generates a participation key valid from first to last for address, and registers it with a
+// transaction signed by the key of authAddr, if the account was rekeyed
+func (c *walletRenewalClient) RenewParticipationKey(address, authAddr string, first, last uint64) (txid string, err error) {
+	params, err := c.SuggestedParams()
+	if err != nil {
+		return
+	}
+	proto := config.Consensus[protocol.ConsensusVersion(params.ConsensusVersion)]
+
+	part, keyPath, err := c.GenParticipationKeysTo(address, first, last, keyDilution, "")
+	if err != nil {
+		return
+	}
+	defer func() {
+		// Only drop the new key when it cannot have been registered
+		if err != nil && txid == "" {
+			part.Close()
+			os.Remove(keyPath)
+		}
+	}()
+
+	utx, err := c.MakeUnsignedGoOnlineTx(address, &part, first, proto.MaxTxnLife, transactionFee, [32]byte{})
+	if err != nil {
+		return
+	}
+	wh, err := c.GetWalletHandleTokenCached(c.walletID, c.pw)
+	if err != nil {
+		return
+	}
+	stx, err := c.SignTransactionWithWalletAndSigner(wh, c.pw, authAddr, utx)
+	if err != nil {
+		return
+	}
+	txid, err = c.BroadcastTransaction(stx)
+	if err != nil {
+		return
+	}
+	_, err = waitForCommit(c.Client, txid)
+	return
+}
@@ -0,0 +1,182 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	algodAcct "github.com/algorand/go-algorand/data/account"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+type renewal struct {
+	address     string
+	first, last uint64
+}
+
+// mockRenewalClient serves participation keys and account statuses, and records the renewals asked of it. Renewed keys
+// show up in its participation keys, as they do on a node.
+type mockRenewalClient struct {
+	parts    map[string]algodAcct.Participation
+	offline  map[basics.Address]bool
+	failures map[string]int // number of renewals of each address that fail before one succeeds
+	renewed  []renewal
+}
+
+func (c *mockRenewalClient) ListParticipationKeys() (map[string]algodAcct.Participation, error) {
+	return c.parts, nil
+}
+
+func (c *mockRenewalClient) AccountInformation(address string) (models.Account, error) {
+	addr, err := basics.UnmarshalChecksumAddress(address)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if c.offline[addr] {
+		return models.Account{Address: address, Status: basics.Offline.String()}, nil
+	}
+	return models.Account{Address: address, Status: basics.Online.String()}, nil
+}
+
+func (c *mockRenewalClient) RenewParticipationKey(address, authAddr string, first, last uint64) (string, error) {
+	c.renewed = append(c.renewed, renewal{address, first, last})
+	if c.failures[address] > 0 {
+		c.failures[address]--
+		return "", errors.New("registration failed")
+	}
+	addr, err := basics.UnmarshalChecksumAddress(address)
+	if err != nil {
+		return "", err
+	}
+	c.parts[fmt.Sprintf("renewed%d.partkey", len(c.renewed))] = algodAcct.Participation{
+		Parent:     addr,
+		FirstValid: basics.Round(first),
+		LastValid:  basics.Round(last),
+	}
+	return fmt.Sprintf("txid-%d", len(c.renewed)), nil
+}
+
+func TestRenewExpiring(t *testing.T) {
+	defer func(threshold, valid uint64) {
+		renewThresholdRounds, renewValidRounds = threshold, valid
+	}(renewThresholdRounds, renewValidRounds)
+	renewThresholdRounds, renewValidRounds = 100, 1000
+
+	a := basics.Address{1}
+	b := basics.Address{2}
+	part := func(address basics.Address, lastValid basics.Round) algodAcct.Participation {
+		return algodAcct.Participation{Parent: address, FirstValid: 0, LastValid: lastValid}
+	}
+
+	type step struct {
+		round   uint64
+		renewed []basics.Address
+	}
+	tests := []struct {
+		name     string
+		parts    []algodAcct.Participation
+		offline  []basics.Address
+		failures map[basics.Address]int
+		steps    []step
+	}{
+		{
+			name:  "within the threshold window",
+			parts: []algodAcct.Participation{part(a, 600), part(b, 450)},
+			steps: []step{
+				{round: 400, renewed: []basics.Address{b}}, // a expires in 200 rounds, b in 50
+				{round: 500, renewed: []basics.Address{a}}, // a expires in 100 rounds, b was renewed
+				{round: 501},
+			},
+		},
+		{
+			name:  "expired keys",
+			parts: []algodAcct.Participation{part(a, 499), part(b, 550)},
+			steps: []step{
+				// a's key expired and is left to renewpartkey
+				{round: 500, renewed: []basics.Address{b}},
+			},
+		},
+		{
+			name:  "latest key per account",
+			parts: []algodAcct.Participation{part(a, 550), part(a, 2000), part(b, 520), part(b, 560)},
+			steps: []step{
+				// a was already renewed, b's latest key is due
+				{round: 500, renewed: []basics.Address{b}},
+			},
+		},
+		{
+			name:    "offline accounts",
+			parts:   []algodAcct.Participation{part(a, 550), part(b, 550)},
+			offline: []basics.Address{a},
+			steps: []step{
+				{round: 500, renewed: []basics.Address{b}},
+			},
+		},
+		{
+			name:     "retry after a failed registration",
+			parts:    []algodAcct.Participation{part(a, 550), part(b, 550)},
+			failures: map[basics.Address]int{a: 2},
+			steps: []step{
+				{round: 500, renewed: []basics.Address{a, b}},
+				{round: 501},
+				{round: 500 + renewRetryRounds - 1},
+				{round: 500 + renewRetryRounds, renewed: []basics.Address{a}},
+				{round: 500 + 2*renewRetryRounds - 1},
+				{round: 500 + 2*renewRetryRounds, renewed: []basics.Address{a}},
+				{round: 500 + 2*renewRetryRounds + 1},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &mockRenewalClient{
+				parts:    make(map[string]algodAcct.Participation),
+				offline:  make(map[basics.Address]bool),
+				failures: make(map[string]int),
+			}
+			for i, p := range test.parts {
+				client.parts[fmt.Sprintf("key%d.partkey", i)] = p
+			}
+			for _, address := range test.offline {
+				client.offline[address] = true
+			}
+			for address, failures := range test.failures {
+				client.failures[address.GetUserAddress()] = failures
+			}
+			renewer := partKeyRenewer{client: client, failedAt: make(map[basics.Address]uint64)}
+
+			for _, step := range test.steps {
+				client.renewed = nil
+				renewer.renewExpiring(step.round)
+
+				expected := make([]renewal, len(step.renewed))
+				for i, address := range step.renewed {
+					expected[i] = renewal{address.GetUserAddress(), step.round, step.round + renewValidRounds}
+				}
+				if len(expected) == 0 {
+					expected = nil
+				}
+				require.Equal(t, expected, client.renewed, "round %d", step.round)
+			}
+		})
+	}
+}
@@ -17,17 +17,24 @@
 package main
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/passphrase"
+	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/libgoal"
 	"github.com/algorand/go-algorand/protocol"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 var (
@@ -42,9 +49,16 @@ var (
 	rejectsFilename string
 	noteBase64      string
 	noteText        string
+	leaseBase64     string
 	sign            bool
 	closeToAddress  string
 	noWaitAfterSend bool
+	signerConfig    libgoal.RemoteSignerConfig
+	programFilename string
+	programArgsB64  []string
+	mnemonicPrompt  bool
+
+	firstValidOffset uint64
 )
 
 func init() {
@@ -61,12 +75,15 @@ func init() {
 	sendCmd.Flags().StringVarP(&toAddress, "to", "t", "", "Address to send to money to (required)")
 	sendCmd.Flags().Uint64VarP(&amount, "amount", "a", 0, "The amount to be transferred (required), in microAlgos")
 	sendCmd.Flags().Uint64Var(&fee, "fee", 0, "The transaction fee (automatically determined by default), in microAlgos")
-	sendCmd.Flags().Uint64Var(&firstValid, "firstvalid", 0, "The first round where the transaction may be committed to the ledger (currently ignored)")
-	sendCmd.Flags().Uint64Var(&lastValid, "lastvalid", 0, "The last round where the transaction may be committed to the ledger (currently ignored)")
+	sendCmd.Flags().Uint64Var(&firstValid, "firstvalid", 0, "The first round where the transaction may be committed to the ledger (use with -o)")
+	sendCmd.Flags().Uint64Var(&firstValidOffset, "firstvalid-offset", 0, "Make the transaction valid from this many rounds after the current round, instead of --firstvalid (use with -o)")
+	sendCmd.Flags().Uint64Var(&lastValid, "lastvalid", 0, "The last round where the transaction may be committed to the ledger (use with -o)")
 	sendCmd.Flags().StringVar(&noteBase64, "noteb64", "", "Note (URL-base64 encoded)")
 	sendCmd.Flags().StringVarP(&noteText, "note", "n", "", "Note text (ignored if --noteb64 used also)")
+	sendCmd.Flags().StringVarP(&leaseBase64, "lease", "x", "", "Lease of the transaction (base64 encoded 32 bytes), held through its last valid round once committed")
 	sendCmd.Flags().StringVarP(&txFilename, "out", "o", "", "Dump an unsigned tx to the given file. In order to dump a signed transaction, pass -s")
 	sendCmd.Flags().BoolVarP(&sign, "sign", "s", false, "Use with -o to indicate that the dumped transaction should be signed")
+	addQRCodeFlags(sendCmd, "the base64 of the dumped transaction (use with -o)")
 	sendCmd.Flags().StringVarP(&closeToAddress, "close-to", "c", "", "Close account and send remainder to this address")
 	sendCmd.Flags().BoolVarP(&noWaitAfterSend, "no-wait", "N", false, "Don't wait for transaction to commit")
 
@@ -81,8 +98,145 @@ func init() {
 
 	signCmd.Flags().StringVarP(&txFilename, "infile", "i", "", "Partially-signed transaction file to add signature to")
 	signCmd.Flags().StringVarP(&outFilename, "outfile", "o", "", "Filename for writing the signed transaction")
+	signCmd.Flags().StringVarP(&programFilename, "program", "p", "", "Compiled TEAL program to sign the transactions with, as a LogicSig")
+	signCmd.Flags().StringSliceVar(&programArgsB64, "argb64", nil, "Base64 encoded argument to pass to the --program, repeatable")
+	signCmd.Flags().BoolVar(&mnemonicPrompt, "mnemonic-prompt", false, "Sign with the key of a mnemonic typed at a prompt, without kmd or a node")
 	signCmd.MarkFlagRequired("infile")
 	signCmd.MarkFlagRequired("outfile")
+
+	addRemoteSignerFlags(sendCmd)
+	addRemoteSignerFlags(signCmd)
+}
+
+// parseNote returns the note given with the --noteb64 or --note flag of cmd,
+// or random bytes if neither is
+func parseNote(cmd *cobra.Command) []byte {
+	if cmd.Flags().Changed("noteb64") {
+		noteBytes, err := base64.StdEncoding.DecodeString(noteBase64)
+		if err != nil {
+			reportErrorf(malformedNote, noteBase64, err)
+		}
+		return noteBytes
+	}
+	if cmd.Flags().Changed("note") {
+		return []byte(noteText)
+	}
+	// Make sure that back-to-back, similar transactions will have a different txid
+	noteBytes := make([]byte, 8)
+	crypto.RandBytes(noteBytes[:])
+	return noteBytes
+}
+
+// parseLease decodes a --lease flag, which is either empty, for no lease, or
+// the base64 encoding of 32 bytes
+func parseLease(leaseB64 string) (lease [32]byte) {
+	if leaseB64 == "" {
+		return
+	}
+	leaseBytes, err := base64.StdEncoding.DecodeString(leaseB64)
+	if err != nil {
+		reportErrorf(malformedLease, leaseB64, err)
+	}
+	if len(leaseBytes) != len(lease) {
+		reportErrorf(malformedLeaseLength, leaseB64, len(leaseBytes), len(lease))
+	}
+	copy(lease[:], leaseBytes)
+	return
+}
+
+// mnemonicSigner returns a function signing transactions with the key of a
+// mnemonic typed at a prompt, which needs neither kmd nor a node
+func mnemonicSigner() func(transactions.Transaction) (transactions.SignedTxn, error) {
+	fmt.Print(infoMnemonicPrompt)
+	resp, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Printf("\n")
+	if err != nil {
+		reportErrorf(errorFailedToReadResponse, err)
+	}
+	key, err := passphrase.MnemonicToKey(strings.TrimSpace(string(resp)))
+	if err != nil {
+		reportErrorf(errorBadMnemonic, err)
+	}
+
+	var seed crypto.Seed
+	copy(seed[:], key)
+	secrets := crypto.GenerateSignatureSecrets(seed)
+	signer := basics.Address(secrets.SignatureVerifier)
+	return func(tx transactions.Transaction) (transactions.SignedTxn, error) {
+		if tx.Sender != signer {
+			return transactions.SignedTxn{}, fmt.Errorf(errorMnemonicSender, signer.GetUserAddress(), tx.Sender.GetUserAddress(), tx.ID())
+		}
+		return tx.Sign(secrets), nil
+	}
+}
+
+// logicSigner returns a function signing transactions with a LogicSig of the
+// program in programFile, delegated to by the sender through kmd unless the
+// sender is the escrow account of the program.
+func logicSigner(programFile string, argsB64 []string) func(transactions.Transaction) (transactions.SignedTxn, error) {
+	if signerConfig.URL != "" {
+		reportErrorf(errorProgramRemoteSigner)
+	}
+	program, err := ioutil.ReadFile(programFile)
+	if err != nil {
+		reportErrorf(fileReadError, programFile, err)
+	}
+	args := make([][]byte, len(argsB64))
+	for i, argB64 := range argsB64 {
+		args[i], err = base64.StdEncoding.DecodeString(argB64)
+		if err != nil {
+			reportErrorf(errorProgramArg, argB64, err)
+		}
+	}
+	escrow := basics.Address(logic.HashProgram(program))
+
+	// the wallet is only needed, and opened once, for delegated programs
+	var client libgoal.Client
+	var wh, pw []byte
+	delegations := make(map[basics.Address]crypto.Signature)
+	return func(tx transactions.Transaction) (stxn transactions.SignedTxn, err error) {
+		stxn.Txn = tx
+		stxn.Lsig = transactions.LogicSig{Logic: program, Args: args}
+		if tx.Sender == escrow {
+			return stxn, nil
+		}
+
+		sig, ok := delegations[tx.Sender]
+		if !ok {
+			if wh == nil {
+				dataDir := ensureSingleDataDir()
+				client = ensureKmdClient(dataDir)
+				wh, pw = ensureWalletHandleMaybePassword(dataDir, walletName, true)
+			}
+			sig, err = client.SignProgramWithWallet(wh, pw, tx.Sender.GetUserAddress(), program)
+			if err != nil {
+				return stxn, fmt.Errorf(errorSigningProgram, tx.Sender.GetUserAddress(), err)
+			}
+			delegations[tx.Sender] = sig
+		}
+		stxn.Lsig.Sig = sig
+		return stxn, nil
+	}
+}
+
+func addRemoteSignerFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&signerConfig.URL, "signer", "", "URL of a signing service to sign with instead of kmd")
+	cmd.Flags().StringVar(&signerConfig.CertFile, "signer-cert", "", "Client certificate to authenticate to the signing service with")
+	cmd.Flags().StringVar(&signerConfig.KeyFile, "signer-key", "", "Private key of the --signer-cert client certificate")
+	cmd.Flags().StringVar(&signerConfig.CAFile, "signer-ca", "", "Certificate authorities to check the signing service certificate against, instead of the system ones")
+}
+
+// remoteSigner returns the signing service set with --signer, or nil when
+// transactions are to be signed by kmd.
+func remoteSigner() *libgoal.RemoteSigner {
+	if signerConfig.URL == "" {
+		return nil
+	}
+	signer, err := libgoal.MakeRemoteSigner(signerConfig)
+	if err != nil {
+		reportErrorf(errorRemoteSigner, err)
+	}
+	return signer
 }
 
 var clerkCmd = &cobra.Command{
@@ -96,16 +250,30 @@ var clerkCmd = &cobra.Command{
 	},
 }
 
+// sentTransaction is what `goal clerk send` reports once it broadcast a transaction
+type sentTransaction struct {
+	TxID           string `json:"txid"`
+	Fee            uint64 `json:"fee"`
+	ConfirmedRound uint64 `json:"confirmedRound,omitempty"`
+}
+
 var sendCmd = &cobra.Command{
 	Use:   "send",
 	Short: "Send money to an address",
-	Long:  `Send money from one account to another. Note: by default, the money will be withdrawn from the default account. Creates a transaction sending amount tokens from fromAddr to toAddr. If the optional --fee is not provided, the transaction will use the recommended amount. If the optional --firstvalid and --lastvalid are provided, the transaction will only be valid from round firstValid to round lastValid. If broadcast of the transaction is successful, the transaction ID will be returned.`,
+	Long:  `Send money from one account to another. Note: by default, the money will be withdrawn from the default account. Creates a transaction sending amount tokens from fromAddr to toAddr. If the optional --fee is not provided, the transaction will use the recommended amount. If the optional --firstvalid and --lastvalid are provided with -o, the transaction will only be valid from round firstValid to round lastValid; --firstvalid-offset N makes it valid from N rounds after the current round instead, and goal clerk scheduler can broadcast such a transaction once its first valid round arrives. If broadcast of the transaction is successful, the transaction ID will be returned. With --signer, the transaction is signed by a signing service instead of kmd, as described in goal clerk sign --help. With --lease, the committed transaction holds the lease until its last valid round passes, so that a retry of the payment with the same lease, but a different fee or note, can't be committed as well.`,
 	Args:  validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
 		// -s is invalid without -o
 		if txFilename == "" && sign {
 			reportErrorln(soFlagError)
 		}
+		if txFilename == "" && qrCodeRequested() {
+			reportErrorln(qrFlagError)
+		}
+		if txFilename == "" && firstValidOffset != 0 {
+			reportErrorln(firstValidOffsetFlagError)
+		}
+		ensureQRCodePNGFileFree()
 
 		dataDir := ensureSingleDataDir()
 		accountList := makeAccountsList(dataDir)
@@ -115,37 +283,34 @@ var sendCmd = &cobra.Command{
 			account = accountList.getDefaultAccount()
 		}
 
-		// Resolving friendly names
-		fromAddressResolved := accountList.getAddressByName(account)
-		toAddressResolved := accountList.getAddressByName(toAddress)
+		// The flags already hold addresses, as the friendly names are resolved before any command runs
+		fromAddressResolved := account
+		toAddressResolved := toAddress
 
-		// Parse notes field
-		var noteBytes []byte
+		noteBytes := parseNote(cmd)
 		var err error
-		if cmd.Flags().Changed("noteb64") {
-			noteBytes, err = base64.StdEncoding.DecodeString(noteBase64)
-			if err != nil {
-				reportErrorf(malformedNote, noteBase64, err)
-			}
-		} else if cmd.Flags().Changed("note") {
-			noteBytes = []byte(noteText)
-		} else {
-			// Make sure that back-to-back, similar transactions will have a different txid
-			noteBytes = make([]byte, 8)
-			crypto.RandBytes(noteBytes[:])
-		}
 
-		// If closing an account, resolve that address as well
-		var closeToAddressResolved string
-		if closeToAddress != "" {
-			closeToAddressResolved = accountList.getAddressByName(closeToAddress)
-		}
+		closeToAddressResolved := closeToAddress
+		lease := parseLease(leaseBase64)
 
 		client := ensureFullClient(dataDir)
 		if txFilename == "" {
 			// Sign and broadcast the tx
-			wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
-			tx, err := client.SendPaymentFromWallet(wh, pw, fromAddressResolved, toAddressResolved, fee, amount, noteBytes, closeToAddressResolved)
+			var tx transactions.Transaction
+			if signer := remoteSigner(); signer != nil {
+				tx, err = client.ConstructPayment(fromAddressResolved, toAddressResolved, fee, amount, noteBytes, closeToAddressResolved, lease)
+				if err != nil {
+					reportErrorf(errorConstructingTX, err)
+				}
+				stxn, err := signer.SignTransaction(tx)
+				if err != nil {
+					reportErrorf(errorRemoteSigning, err)
+				}
+				_, err = client.BroadcastTransaction(stxn)
+			} else {
+				wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
+				tx, err = client.SendPaymentFromWallet(wh, pw, fromAddressResolved, toAddressResolved, fee, amount, noteBytes, closeToAddressResolved, lease)
+			}
 
 			// update information from Transaction
 			txid := tx.ID().String()
@@ -157,51 +322,45 @@ var sendCmd = &cobra.Command{
 
 			// Report tx details to user
 			reportInfof(infoTxIssued, amount, fromAddressResolved, toAddressResolved, txid, fee)
+			sent := sentTransaction{TxID: txid, Fee: fee}
 
 			if noWaitAfterSend {
+				reportResult(sent, txid, nil)
 				return
 			}
 
-			// Get current round information
-			stat, err := client.Status()
+			sent.ConfirmedRound, err = waitForCommit(client, txid)
 			if err != nil {
-				reportErrorf(errorRequestFail, err)
-			}
-
-			for {
-				// Check if we know about the transaction yet
-				txn, err := client.PendingTransactionInformation(txid)
-				if err != nil {
-					reportErrorf(errorRequestFail, err)
-				}
-
-				if txn.ConfirmedRound > 0 {
-					reportInfof(infoTxCommitted, txid, txn.ConfirmedRound)
-					break
-				}
-
-				if txn.PoolError != "" {
-					reportErrorf(txPoolError, txid, txn.PoolError)
-				}
-
-				reportInfof(infoTxPending, txid, stat.LastRound)
-				stat, err = client.WaitForRound(stat.LastRound + 1)
-				if err != nil {
-					reportErrorf(errorRequestFail, err)
-				}
+				reportErrorln(err)
 			}
+			reportResult(sent, txid, nil)
 		} else {
-			payment, err := client.ConstructPayment(fromAddressResolved, toAddressResolved, fee, amount, noteBytes, closeToAddressResolved)
+			payment, err := client.ConstructPayment(fromAddressResolved, toAddressResolved, fee, amount, noteBytes, closeToAddressResolved, lease)
+			if err != nil {
+				reportErrorf(errorConstructingTX, err)
+			}
+			err = setValidRounds(&payment)
 			if err != nil {
 				reportErrorf(errorConstructingTX, err)
 			}
 			var stxn transactions.SignedTxn
 			if sign {
 				// Sign the transaction
-				wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
-				stxn, err = client.SignTransactionWithWallet(wh, pw, payment)
-				if err != nil {
-					reportErrorf(errorConstructingTX, err)
+				if signer := remoteSigner(); signer != nil {
+					stxn, err = signer.SignTransaction(payment)
+					if err != nil {
+						reportErrorf(errorRemoteSigning, err)
+					}
+				} else {
+					info, err := client.AccountInformation(fromAddressResolved)
+					if err != nil {
+						reportErrorf(errorRequestFail, err)
+					}
+					wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
+					stxn, err = client.SignTransactionWithWalletAndSigner(wh, pw, info.AuthAddr, payment)
+					if err != nil {
+						reportErrorf(errorConstructingTX, err)
+					}
 				}
 			} else {
 				// Wrap in a transactions.SignedTxn with an empty sig.
@@ -214,19 +373,22 @@ var sendCmd = &cobra.Command{
 				stxn = populateBlankMultisig(client, dataDir, walletName, stxn)
 			}
 			// Write the SignedTxn to the output file
-			err = ioutil.WriteFile(txFilename, protocol.Encode(stxn), 0600)
+			encoded := protocol.Encode(stxn)
+			err = ioutil.WriteFile(txFilename, encoded, 0600)
 			if err != nil {
 				reportErrorf(fileWriteError, txFilename, err)
 			}
+			reportQRCode(base64.StdEncoding.EncodeToString(encoded))
 		}
 	},
 }
 
 var rawsendCmd = &cobra.Command{
-	Use:   "rawsend",
-	Short: "Send raw transactions",
-	Long:  `Send raw transactions.  The transactions must be stored in a file, encoded using msgpack as transactions.SignedTxn. Multiple transactions can be concatenated together in a file.`,
-	Args:  validateNoPosArgsFn,
+	Use:     "rawsend",
+	Aliases: []string{"broadcast"},
+	Short:   "Send raw transactions",
+	Long:    `Send raw transactions.  The transactions must be stored in a file, encoded using msgpack as transactions.SignedTxn. Multiple transactions can be concatenated together in a file. Consecutive transactions of the same group, as made by goal clerk group, are sent together as an atomic transaction group. goal clerk broadcast is the same command, the last step of the offline signing workflow described in goal clerk construct --help.`,
+	Args:    validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, args []string) {
 		if rejectsFilename == "" {
 			rejectsFilename = txFilename + ".rej"
@@ -241,6 +403,7 @@ var rawsendCmd = &cobra.Command{
 		client := ensureAlgodClient(ensureSingleDataDir())
 
 		txns := make(map[transactions.Txid]transactions.SignedTxn)
+		var txnList []transactions.SignedTxn
 		for {
 			var txn transactions.SignedTxn
 			err = dec.Decode(&txn)
@@ -257,59 +420,57 @@ var rawsendCmd = &cobra.Command{
 			}
 
 			txns[txn.ID()] = txn
+			txnList = append(txnList, txn)
 		}
 
 		txnErrors := make(map[transactions.Txid]string)
 		pendingTxns := make(map[transactions.Txid]string)
-		for txid, txn := range txns {
-			// Broadcast the transaction
-			txidStr, err := client.BroadcastTransaction(txn)
+		for _, txgroup := range transactionGroups(txnList) {
+			if txgroup[0].Txn.Group == (crypto.Digest{}) {
+				// Broadcast the transaction
+				txid := txgroup[0].ID()
+				txidStr, err := client.BroadcastTransaction(txgroup[0])
+				if err != nil {
+					txnErrors[txid] = err.Error()
+					reportWarnf(errorBroadcastingTX, err)
+					continue
+				}
+
+				reportInfof(infoRawTxIssued, txidStr)
+				pendingTxns[txid] = txidStr
+				continue
+			}
+
+			// Broadcast the transactions of the group together
+			err = client.BroadcastTransactionGroup(txgroup)
 			if err != nil {
-				txnErrors[txid] = err.Error()
+				for _, txn := range txgroup {
+					txnErrors[txn.ID()] = err.Error()
+				}
 				reportWarnf(errorBroadcastingTX, err)
 				continue
 			}
 
-			reportInfof(infoRawTxIssued, txidStr)
-			pendingTxns[txid] = txidStr
+			reportInfof(infoRawTxGroupIssued, len(txgroup), txgroup[0].ID())
+			for _, txn := range txgroup {
+				pendingTxns[txn.ID()] = txn.ID().String()
+			}
 		}
 
 		if noWaitAfterSend {
 			return
 		}
 
-		// Get current round information
-		stat, err := client.Status()
-		if err != nil {
-			reportErrorf(errorRequestFail, err)
-		}
-
 		for txid, txidStr := range pendingTxns {
-			for {
-				// Check if we know about the transaction yet
-				txn, err := client.PendingTransactionInformation(txidStr)
-				if err != nil {
-					txnErrors[txid] = err.Error()
-					reportWarnf(errorRequestFail, err)
-					continue
-				}
-
-				if txn.ConfirmedRound > 0 {
-					reportInfof(infoTxCommitted, txidStr, txn.ConfirmedRound)
-					break
-				}
-
-				if txn.PoolError != "" {
-					txnErrors[txid] = txn.PoolError
-					reportWarnf(txPoolError, txidStr, txn.PoolError)
-					continue
-				}
-
-				reportInfof(infoTxPending, txidStr, stat.LastRound)
-				stat, err = client.WaitForRound(stat.LastRound + 1)
-				if err != nil {
-					reportErrorf(errorRequestFail, err)
-				}
+			txn, err := client.WaitForConfirmation(txidStr, context.Background())
+			if poolErr, ok := err.(libgoal.TxnPoolError); ok {
+				txnErrors[txid] = poolErr.Reason
+				reportWarnf(txPoolError, txidStr, poolErr.Reason)
+			} else if err != nil {
+				txnErrors[txid] = err.Error()
+				reportWarnf(errorRequestFail, err)
+			} else {
+				reportInfof(infoTxCommitted, txidStr, txn.ConfirmedRound)
 			}
 		}
 
@@ -373,17 +534,49 @@ var inspectCmd = &cobra.Command{
 var signCmd = &cobra.Command{
 	Use:   "sign -i INFILE -o OUTFILE",
 	Short: "Sign a transaction file",
-	Long:  `Sign the passed transaction file, which may contain one or more transactions. If the infile and the outfile are the same, this overwrites the file with the new, signed data.`,
-	Args:  validateNoPosArgsFn,
+	Long: `Sign the passed transaction file, which may contain one or more transactions. If the infile and the outfile are the same, this overwrites the file with the new, signed data.
+With --program, the transactions are signed with a LogicSig carrying the compiled TEAL program and its --argb64 arguments instead. A transaction whose sender
+is the address of the program, an escrow account, needs nothing more; for any other sender, kmd signs the program with the sender's key, delegating to the
+program the authority to approve transactions from that account.
+With --signer, the transactions are signed by a signing service instead of kmd: goal posts the address of the sender, the transaction ID and the bytes to sign
+as a JSON object {"address", "txid", "bytes"} to the service URL, and expects a JSON object {"signature"} back, with the bytes and the Ed25519 signature
+base64 encoded. The signature is checked before it is used. Use --signer-cert and --signer-key to authenticate to the service with a client certificate.`,
+	Example: "goal clerk sign -i unsigned.tx -o signed.tx --signer https://signer.example.com/sign --signer-cert goal.crt --signer-key goal.key",
+	Args:    validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, _ []string) {
 		data, err := ioutil.ReadFile(txFilename)
 		if err != nil {
 			reportErrorf(fileReadError, txFilename, err)
 		}
 
-		dataDir := ensureSingleDataDir()
-		client := ensureKmdClient(dataDir)
-		wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
+		var signTxn func(transactions.Transaction) (transactions.SignedTxn, error)
+		if mnemonicPrompt {
+			if programFilename != "" || remoteSigner() != nil {
+				reportErrorln(errorMnemonicPromptFlags)
+			}
+			signTxn = mnemonicSigner()
+		} else if programFilename != "" {
+			signTxn = logicSigner(programFilename, programArgsB64)
+		} else if signer := remoteSigner(); signer != nil {
+			signTxn = func(tx transactions.Transaction) (transactions.SignedTxn, error) {
+				stxn, err := signer.SignTransaction(tx)
+				if err != nil {
+					return stxn, fmt.Errorf(errorRemoteSigning, err)
+				}
+				return stxn, nil
+			}
+		} else {
+			dataDir := ensureSingleDataDir()
+			client := ensureKmdClient(dataDir)
+			wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
+			signTxn = func(tx transactions.Transaction) (transactions.SignedTxn, error) {
+				stxn, err := client.SignTransactionWithWallet(wh, pw, tx)
+				if err != nil {
+					return stxn, fmt.Errorf(errorSigningTX, err)
+				}
+				return stxn, nil
+			}
+		}
 
 		var outData []byte
 		dec := protocol.NewDecoderBytes(data)
@@ -398,9 +591,9 @@ var signCmd = &cobra.Command{
 				reportErrorf(txDecodeError, txFilename, err)
 			}
 
-			signedTxn, err := client.SignTransactionWithWallet(wh, pw, unsignedTxn.Txn)
+			signedTxn, err := signTxn(unsignedTxn.Txn)
 			if err != nil {
-				reportErrorf(errorSigningTX, err)
+				reportErrorln(err)
 			}
 
 			outData = append(outData, protocol.Encode(signedTxn)...)
@@ -61,6 +61,9 @@ func init() {
 	// clerk.go
 	rootCmd.AddCommand(clerkCmd)
 
+	// asset.go
+	rootCmd.AddCommand(assetCmd)
+
 	// node.go
 	rootCmd.AddCommand(nodeCmd)
 
@@ -76,6 +79,9 @@ func init() {
 	// ledger.go
 	rootCmd.AddCommand(ledgerCmd)
 
+	// debug.go
+	rootCmd.AddCommand(debugCmd)
+
 	// Config
 	defaultDataDirValue := []string{""}
 	rootCmd.PersistentFlags().StringArrayVarP(&dataDirs, "datadir", "d", defaultDataDirValue, "Data directory for the node")
@@ -131,8 +137,7 @@ func main() {
 	}
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		report.usageError(err)
 	}
 }
 
@@ -213,7 +218,8 @@ func resolveKmdDataDir(dataDir string) string {
 
 func resolveDataDir() string {
 	// Figure out what data directory to tell algod to use.
-	// If not specified on cmdline with '-d', look for default in environment.
+	// If not specified on cmdline with '-d', look for default in environment,
+	// and then for the data directory of the network selected with 'goal network use'.
 	var dir string
 	if len(dataDirs) > 0 {
 		dir = dataDirs[0]
@@ -221,6 +227,9 @@ func resolveDataDir() string {
 	if dir == "" {
 		dir = os.Getenv("ALGORAND_DATA")
 	}
+	if dir == "" {
+		dir = registeredDataDir()
+	}
 	return dir
 }
 
@@ -252,16 +261,40 @@ func getDataDirs() (dirs []string) {
 
 func onDataDirs(action func(dataDir string)) {
 	dirs := getDataDirs()
-	report := len(dirs) > 1
+	several := len(dirs) > 1
 
 	for _, dir := range dirs {
-		if report {
+		if several {
 			reportInfof(infoDataDir, dir)
 		}
 		action(dir)
 	}
 }
 
+// onDataDirsReportingErrors runs the action on each data directory like onDataDirs, but carries on with the
+// remaining directories when it fails on one of them. The errors are reported along with their data directory,
+// and the command exits with an error once all the directories were processed.
+func onDataDirsReportingErrors(action func(dataDir string) error) {
+	dirs := getDataDirs()
+	if len(dirs) == 1 {
+		if err := action(dirs[0]); err != nil {
+			reportErrorln(err)
+		}
+		return
+	}
+
+	var failed []string
+	onDataDirs(func(dataDir string) {
+		if err := action(dataDir); err != nil {
+			report.printError(fmt.Sprintf(errorDataDir, dataDir, err))
+			failed = append(failed, dataDir)
+		}
+	})
+	if len(failed) > 0 {
+		reportErrorf(errorDataDirsFailed, len(failed), len(dirs), strings.Join(failed, ", "))
+	}
+}
+
 func ensureCacheDir(dataDir string) string {
 	var err error
 	if libgoal.AlgorandDataIsPrivate(dataDir) {
@@ -304,6 +337,7 @@ func ensureGoalClient(dataDir string, clientType libgoal.ClientType) libgoal.Cli
 		KMDDataDir:   resolveKmdDataDir(dataDir),
 		CacheDir:     ensureCacheDir(dataDir),
 	}
+	clientConfig.AlgodURL, clientConfig.AlgodAPIToken = profileAlgodEndpoint(dataDir)
 	client, err := libgoal.MakeClientFromConfig(clientConfig, clientType)
 	if err != nil {
 		reportErrorf(errorNodeStatus, err)
@@ -322,11 +356,47 @@ func ensureWalletHandleMaybePassword(dataDir string, walletName string, getPassw
 		reportErrorln(err)
 	}
 
+	// Signing with a hardware wallet blocks until the user approves it
+	if getPassword {
+		if kmd := ensureKmdClient(dataDir); kmd.WalletIsHardware(wh) {
+			reportInfoln(infoApproveOnDevice)
+		}
+	}
+
 	return wh, pw
 }
 
 func getWalletHandleMaybePassword(dataDir string, walletName string, getPassword bool) (wh []byte, pw []byte, err error) {
-	var walletID []byte
+	kmd := ensureKmdClient(dataDir)
+	walletID, walletName, err := getWalletID(dataDir, walletName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Try getting a cached token, authing with a blank password if required
+	token, err := kmd.GetWalletHandleTokenCached(walletID, nil)
+	if err == nil {
+		if getPassword && !kmd.WalletIsUnencrypted(walletID) {
+			return token, ensurePasswordForWallet(walletName), nil
+		}
+		return token, nil, nil
+	}
+
+	// Assume any errors were "wrong password" errors, until we have actual
+	// API error codes
+	pw = ensurePasswordForWallet(walletName)
+
+	// Try fetching the wallet again, this time with a password
+	token, err = kmd.GetWalletHandleTokenCached(walletID, pw)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errGettingToken, walletName, walletID, err)
+	}
+	return token, pw, nil
+}
+
+// getWalletID looks up the ID of the named wallet, or of the default one if walletName is empty, and returns it
+// along with the wallet name.
+func getWalletID(dataDir string, walletName string) (walletID []byte, name string, err error) {
 	var dup bool
 
 	accountList := makeAccountsList(dataDir)
@@ -340,16 +410,16 @@ func getWalletHandleMaybePassword(dataDir string, walletName string, getPassword
 			// If there is, make it the default and continue
 			wallets, err := kmd.ListWallets()
 			if err != nil {
-				return nil, nil, fmt.Errorf(errCouldNotListWallets, err)
+				return nil, "", fmt.Errorf(errCouldNotListWallets, err)
 			}
 			if len(wallets) == 1 {
 				// Only one wallet, so it's unambigious
 				walletID = []byte(wallets[0].ID)
 				accountList.setDefaultWalletID(walletID)
 			} else if len(wallets) == 0 {
-				return nil, nil, fmt.Errorf(errNoWallets)
+				return nil, "", fmt.Errorf(errNoWallets)
 			} else {
-				return nil, nil, fmt.Errorf(errNoDefaultWallet)
+				return nil, "", fmt.Errorf(errNoDefaultWallet)
 			}
 		}
 		// Fetch the wallet name (useful for error messages, and to check
@@ -357,50 +427,32 @@ func getWalletHandleMaybePassword(dataDir string, walletName string, getPassword
 		var wnBytes []byte
 		wnBytes, dup, err = kmd.FindWalletNameByID(walletID)
 		if dup {
-			return nil, nil, fmt.Errorf(errWalletIDDuplicate, walletID)
+			return nil, "", fmt.Errorf(errWalletIDDuplicate, walletID)
 		}
 		if err != nil {
-			return nil, nil, fmt.Errorf(errGettingWalletName, walletID, err)
+			return nil, "", fmt.Errorf(errGettingWalletName, walletID, err)
 		}
 		if len(wnBytes) == 0 {
-			return nil, nil, fmt.Errorf(errDefaultWalletNotFound, walletID)
+			return nil, "", fmt.Errorf(errDefaultWalletNotFound, walletID)
 		}
 		walletName = string(wnBytes)
 	} else {
 		// The user manually specified a wallet, so look up the ID
 		walletID, dup, err = kmd.FindWalletIDByName([]byte(walletName))
 		if err != nil {
-			return nil, nil, fmt.Errorf(errFindingWallet, err)
+			return nil, "", fmt.Errorf(errFindingWallet, err)
 		}
 		if dup {
-			return nil, nil, fmt.Errorf(errWalletNameAmbiguous, walletName)
+			return nil, "", fmt.Errorf(errWalletNameAmbiguous, walletName)
 		}
 	}
 
 	// If walletID is still blank, we couldn't find the wallet
 	if len(walletID) == 0 {
-		return nil, nil, fmt.Errorf(errWalletNotFound, walletName)
-	}
-
-	// Try getting a cached token, authing with a blank password if required
-	token, err := kmd.GetWalletHandleTokenCached(walletID, nil)
-	if err == nil {
-		if getPassword && !kmd.WalletIsUnencrypted(walletID) {
-			return token, ensurePasswordForWallet(walletName), nil
-		}
-		return token, nil, nil
+		return nil, "", fmt.Errorf(errWalletNotFound, walletName)
 	}
 
-	// Assume any errors were "wrong password" errors, until we have actual
-	// API error codes
-	pw = ensurePasswordForWallet(walletName)
-
-	// Try fetching the wallet again, this time with a password
-	token, err = kmd.GetWalletHandleTokenCached(walletID, pw)
-	if err != nil {
-		return nil, nil, fmt.Errorf(errGettingToken, walletName, walletID, err)
-	}
-	return token, pw, nil
+	return walletID, walletName, nil
 }
 
 func ensurePasswordForWallet(walletName string) []byte {
@@ -416,36 +468,3 @@ func ensurePassword() []byte {
 	fmt.Printf("\n")
 	return password
 }
-
-func reportInfoln(args ...interface{}) {
-	fmt.Println(args...)
-	// log.Infoln(args...)
-}
-
-func reportInfof(format string, args ...interface{}) {
-	fmt.Printf(format+"\n", args...)
-	// log.Infof(format, args...)
-}
-
-func reportWarnln(args ...interface{}) {
-	fmt.Print("Warning: ")
-	fmt.Println(args...)
-	// log.Warnln(args...)
-}
-
-func reportWarnf(format string, args ...interface{}) {
-	fmt.Printf("Warning: "+format+"\n", args...)
-	// log.Warnf(format, args...)
-}
-
-func reportErrorln(args ...interface{}) {
-	fmt.Println(args...)
-	// log.Warnln(args...)
-	os.Exit(1)
-}
-
-func reportErrorf(format string, args ...interface{}) {
-	fmt.Printf(format+"\n", args...)
-	// log.Warnf(format, args...)
-	os.Exit(1)
-}
@@ -29,3 +29,15 @@ func TestEnsureDataDirReturnsWhenDataDirIsProvided(t *testing.T) {
 	actualDir := ensureFirstDataDir()
 	require.Equal(t, expectedDir, actualDir)
 }
+
+func TestOnDataDirsReportingErrorsVisitsAllDirs(t *testing.T) {
+	defer func(dirs []string) { dataDirs = dirs }(dataDirs)
+	dataDirs = []string{"node1", "node2", "node3"}
+
+	var visited []string
+	onDataDirsReportingErrors(func(dataDir string) error {
+		visited = append(visited, dataDir)
+		return nil
+	})
+	require.Equal(t, dataDirs, visited)
+}
@@ -16,9 +16,60 @@
 
 package main
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/util"
+	"github.com/algorand/go-algorand/util/qrcode"
+)
 
 // validateNoPosArgsFn is a reusable cobra positional argument validation function
 // for generating proper error messages when commands see unexpected arguments when they expect no args.
 // We don't use cobra.NoArgs directly, in case we want to customize behavior later.
 var validateNoPosArgsFn = cobra.NoArgs
+
+var (
+	qrTerminal    bool
+	qrPNGFilename string
+)
+
+// addQRCodeFlags adds the flags of reportQRCode to cmd, describing what is encoded
+func addQRCodeFlags(cmd *cobra.Command, what string) {
+	cmd.Flags().BoolVar(&qrTerminal, "qr", false, fmt.Sprintf("Print %s as a QR code on the terminal", what))
+	cmd.Flags().StringVar(&qrPNGFilename, "qr-png", "", fmt.Sprintf("Write %s as a QR code to this new PNG file", what))
+}
+
+// qrCodeRequested reports whether --qr or --qr-png was given
+func qrCodeRequested() bool {
+	return qrTerminal || qrPNGFilename != ""
+}
+
+// ensureQRCodePNGFileFree fails before any work is done if the --qr-png file exists, since it would not be overwritten
+func ensureQRCodePNGFileFree() {
+	if qrPNGFilename != "" && util.FileExists(qrPNGFilename) {
+		reportErrorf(fileWriteError, qrPNGFilename, "the file already exists")
+	}
+}
+
+// reportQRCode prints text as a QR code with --qr, and writes it to a PNG file with --qr-png
+func reportQRCode(text string) {
+	if !qrCodeRequested() {
+		return
+	}
+	code, err := qrcode.Encode(text)
+	if err != nil {
+		reportErrorf(errorQRCode, err)
+	}
+	if qrTerminal {
+		fmt.Print(code.Terminal())
+	}
+	if qrPNGFilename != "" {
+		err = code.WritePNG(qrPNGFilename)
+		if err != nil {
+			reportErrorf(fileWriteError, qrPNGFilename, err)
+		}
+		reportInfof(infoWroteQRCode, qrPNGFilename)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+)
+
+var (
+	disassemble     bool
+	noProgramOutput bool
+)
+
+func init() {
+	clerkCmd.AddCommand(compileCmd)
+
+	compileCmd.Flags().BoolVarP(&disassemble, "disassemble", "D", false, "Disassemble a compiled program")
+	compileCmd.Flags().BoolVarP(&noProgramOutput, "no-out", "n", false, "Don't write the compiled program, only print its address")
+	compileCmd.Flags().StringVarP(&outFilename, "outfile", "o", "", "Filename to write the program to, instead of the input filename with .tok appended (or stdout for -D)")
+}
+
+// compiledProgram is what `goal clerk compile` reports for every program it assembles
+type compiledProgram struct {
+	Filename string `json:"filename"`
+	Address  string `json:"address"`
+	Outfile  string `json:"outfile,omitempty"`
+}
+
+var compileCmd = &cobra.Command{
+	Use:   "compile [input file 1] [input file 2]...",
+	Short: "Compile a TEAL program",
+	Long: `Assemble each TEAL program file into bytecode, written to the input filename with .tok appended, and print the address of the account the program controls: the escrow address of the program, to use with logic signatures.
+With -D, disassemble the bytecode of each compiled program file back into TEAL source instead. The language is described in data/transactions/logic/README.md.`,
+	Example: "goal clerk compile escrow.teal\ngoal clerk compile -D escrow.teal.tok",
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if outFilename != "" && len(args) > 1 {
+			reportErrorln(errorCompileOutfileArgs)
+		}
+
+		for _, fname := range args {
+			data, err := ioutil.ReadFile(fname)
+			if err != nil {
+				reportErrorf(fileReadError, fname, err)
+			}
+
+			if disassemble {
+				text, err := logic.Disassemble(data)
+				if err != nil {
+					reportErrorf(errorDisassemble, fname, err)
+				}
+				if outFilename == "" {
+					fmt.Print(text)
+					continue
+				}
+				err = ioutil.WriteFile(outFilename, []byte(text), 0666)
+				if err != nil {
+					reportErrorf(fileWriteError, outFilename, err)
+				}
+				continue
+			}
+
+			program, err := logic.AssembleString(string(data))
+			if err != nil {
+				reportErrorf(errorAssemble, fname, err)
+			}
+
+			compiled := compiledProgram{
+				Filename: fname,
+				Address:  basics.Address(logic.HashProgram(program)).GetUserAddress(),
+			}
+			if !noProgramOutput {
+				compiled.Outfile = outFilename
+				if compiled.Outfile == "" {
+					compiled.Outfile = fname + ".tok"
+				}
+				err = ioutil.WriteFile(compiled.Outfile, program, 0666)
+				if err != nil {
+					reportErrorf(fileWriteError, compiled.Outfile, err)
+				}
+			}
+			reportResult(compiled, compiled.Address, func() {
+				fmt.Printf("%s: %s\n", compiled.Filename, compiled.Address)
+			})
+		}
+	},
+}
@@ -0,0 +1,583 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/algorand/go-algorand/config"
+)
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+	completionCmd.AddCommand(bashCompletionCmd)
+	completionCmd.AddCommand(zshCompletionCmd)
+	completionCmd.AddCommand(fishCompletionCmd)
+	completionCmd.AddCommand(listCompletionsCmd)
+}
+
+// The kinds of values goal completes dynamically, listed by 'goal completion list'.
+const (
+	accountCompletions = "accounts"
+	walletCompletions  = "wallets"
+	dataDirCompletions = "datadirs"
+)
+
+// accountFlags are the names of the flags which take an account address or name.
+var accountFlags = map[string]bool{"address": true, "from": true, "to": true, "close-to": true}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion",
+	Short: "Shell completion helper",
+	Long: `Generate the shell completion script of goal for bash, zsh or fish.
+Besides commands and flags, the scripts complete account names (from the account list of the data directory), wallet names and data directories.`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		//If no arguments passed, we should fallback to help
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+var bashCompletionCmd = &cobra.Command{
+	Use:   "bash",
+	Short: "Generate the bash completion script",
+	Long: `Generate the bash completion script. To load the completions in the current shell:
+  source <(goal completion bash)
+To load them for every new session, add the above line to your ~/.bashrc`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := genBashCompletion(rootCmd, os.Stdout); err != nil {
+			reportErrorf(errorCompletion, err)
+		}
+	},
+}
+
+var zshCompletionCmd = &cobra.Command{
+	Use:   "zsh",
+	Short: "Generate the zsh completion script",
+	Long: `Generate the zsh completion script. To load the completions in the current shell:
+  source <(goal completion zsh)
+To load them for every new session, add the above line to your ~/.zshrc`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := genZshCompletion(rootCmd, os.Stdout); err != nil {
+			reportErrorf(errorCompletion, err)
+		}
+	},
+}
+
+var fishCompletionCmd = &cobra.Command{
+	Use:   "fish",
+	Short: "Generate the fish completion script",
+	Long: `Generate the fish completion script. To load the completions in the current shell:
+  goal completion fish | source
+To load them for every new session:
+  goal completion fish > ~/.config/fish/completions/goal.fish`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := genFishCompletion(rootCmd, os.Stdout); err != nil {
+			reportErrorf(errorCompletion, err)
+		}
+	},
+}
+
+var listCompletionsCmd = &cobra.Command{
+	Use:    "list [accounts|wallets|datadirs] [prefix]",
+	Short:  "List the dynamic completions of the given kind",
+	Hidden: true,
+	Args:   cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var completions []string
+		switch args[0] {
+		case accountCompletions:
+			completions = listAccountNames(resolveDataDir())
+		case walletCompletions:
+			completions = listWalletNames(resolveDataDir())
+		case dataDirCompletions:
+			prefix := ""
+			if len(args) > 1 {
+				prefix = args[1]
+			}
+			completions = listDataDirs(prefix)
+		default:
+			reportErrorf(errorCompletionKind, args[0])
+		}
+		for _, completion := range completions {
+			fmt.Println(completion)
+		}
+	},
+}
+
+// listAccountNames returns the names of the accounts in the account list of the given data directory.
+// It never fails, as its output is only used for completion.
+func listAccountNames(dataDir string) (names []string) {
+	if dataDir == "" {
+		return
+	}
+	genesis, err := readGenesis(dataDir)
+	if err != nil {
+		return
+	}
+	fileName, err := accountListFilePath(dataDir, genesis.ID())
+	if err != nil {
+		return
+	}
+	raw, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return
+	}
+	var accountList AccountsList
+	if err = json.Unmarshal(raw, &accountList); err != nil {
+		return
+	}
+	for _, name := range accountList.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// listWalletNames returns the names of the wallets kmd manages for the given data directory.
+func listWalletNames(dataDir string) (names []string) {
+	if dataDir == "" {
+		return
+	}
+	client := ensureKmdClient(dataDir)
+	wallets, err := client.ListWallets()
+	if err != nil {
+		return
+	}
+	for _, wallet := range wallets {
+		names = append(names, wallet.Name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// listDataDirs returns the directories matching the given prefix. Directories holding a genesis file are returned
+// as they are, while the others get a trailing separator, so that their content would be completed next.
+func listDataDirs(prefix string) (dirs []string) {
+	if env := os.Getenv("ALGORAND_DATA"); env != "" && strings.HasPrefix(env, prefix) {
+		dirs = append(dirs, env)
+	}
+	matches, _ := filepath.Glob(prefix + "*")
+	for _, match := range matches {
+		if info, err := os.Stat(match); err != nil || !info.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(match, config.GenesisJSONFile)); err == nil {
+			if match != os.Getenv("ALGORAND_DATA") {
+				dirs = append(dirs, match)
+			}
+			continue
+		}
+		dirs = append(dirs, match+string(filepath.Separator))
+	}
+	return
+}
+
+// annotateCompletions marks the flags which take account names, wallet names or data directories, so that the
+// generated scripts would complete their values.
+func annotateCompletions(root *cobra.Command) {
+	var annotate func(cmd *cobra.Command)
+	annotate = func(cmd *cobra.Command) {
+		for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()} {
+			flags.VisitAll(func(flag *pflag.Flag) {
+				if accountFlags[flag.Name] && flag.Value.Type() == "string" {
+					flags.SetAnnotation(flag.Name, cobra.BashCompCustom, []string{"__goal_accounts"})
+				}
+				if flag.Name == "wallet" {
+					flags.SetAnnotation(flag.Name, cobra.BashCompCustom, []string{"__goal_wallets"})
+				}
+			})
+		}
+		for _, sub := range cmd.Commands() {
+			annotate(sub)
+		}
+	}
+	annotate(root)
+
+	cobra.MarkFlagCustom(accountCmd.Flags(), "default", "__goal_accounts")
+	cobra.MarkFlagCustom(walletCmd.Flags(), "default", "__goal_wallets")
+	cobra.MarkFlagCustom(root.PersistentFlags(), "datadir", "__goal_datadirs")
+	root.PersistentFlags().SetAnnotation("kmddir", cobra.BashCompSubdirsInDir, []string{})
+}
+
+// completedArgs maps the commands whose positional arguments are account or wallet names to their completion.
+var completedArgs = map[string]string{
+	"goal_account_rename": "__goal_accounts",
+}
+
+// bashCompletionFunctions are the bash functions completing the dynamic values, through 'goal completion list'.
+// The data directories selected on the command line are passed along, as the account and wallet names depend on them.
+const bashCompletionFunctions = `
+__goal_list()
+{
+    local datadir="${flaghash[-d]:-${flaghash[--datadir]:-${flaghash[--datadir=]}}}"
+    local kmddir="${flaghash[-k]:-${flaghash[--kmddir]:-${flaghash[--kmddir=]}}}"
+    local args=()
+    [[ -n "${datadir}" ]] && args+=(-d "${datadir}")
+    [[ -n "${kmddir}" ]] && args+=(-k "${kmddir}")
+    local IFS=$'\n'
+    COMPREPLY=( $(compgen -W "$(goal completion list "$1" "${args[@]}" 2>/dev/null)" -- "$cur") )
+}
+
+__goal_accounts()
+{
+    __goal_list accounts
+}
+
+__goal_wallets()
+{
+    __goal_list wallets
+}
+
+__goal_datadirs()
+{
+    local IFS=$'\n'
+    COMPREPLY=( $(goal completion list datadirs "$cur" 2>/dev/null) )
+    if [[ ${#COMPREPLY[@]} -eq 1 && "${COMPREPLY[0]}" == */ && $(type -t compopt) = "builtin" ]]; then
+        compopt -o nospace
+    fi
+}
+`
+
+func customCompletionFunc() string {
+	var b strings.Builder
+	b.WriteString("\n__custom_func()\n{\n    case ${last_command} in\n")
+	commands := make([]string, 0, len(completedArgs))
+	for command := range completedArgs {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	for _, command := range commands {
+		fmt.Fprintf(&b, "        %s)\n            %s\n            return\n            ;;\n", command, completedArgs[command])
+	}
+	b.WriteString("        *)\n            ;;\n    esac\n}\n")
+	return b.String()
+}
+
+func genBashCompletion(root *cobra.Command, w io.Writer) error {
+	annotateCompletions(root)
+	root.BashCompletionFunction = bashCompletionFunctions + customCompletionFunc()
+	return root.GenBashCompletion(w)
+}
+
+// zshConversions adapt the bash completion script to the bash completion emulation of zsh, replacing the bash
+// builtins and bash-completion helpers zsh lacks with the functions of zshPreamble.
+var zshConversions = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`declare -F`), `whence -w`},
+	{regexp.MustCompile(`_get_comp_words_by_ref "\$@"`), `_get_comp_words_by_ref "$$*"`},
+	{regexp.MustCompile(`local ([a-zA-Z0-9_]*)=`), `local $1; $1=`},
+	{regexp.MustCompile(`flags\+=\("(--.*)="\)`), `flags+=("$1"); two_word_flags+=("$1")`},
+	{regexp.MustCompile(`must_have_one_flag\+=\("(--.*)="\)`), `must_have_one_flag+=("$1")`},
+	{regexp.MustCompile(`\b_filedir\b`), `__goal_filedir`},
+	{regexp.MustCompile(`\b_get_comp_words_by_ref\b`), `__goal_get_comp_words_by_ref`},
+	{regexp.MustCompile(`\b__ltrim_colon_completions\b`), `__goal_ltrim_colon_completions`},
+	{regexp.MustCompile(`\bcompgen\b`), `__goal_compgen`},
+	{regexp.MustCompile(`\bcompopt\b`), `__goal_compopt`},
+	{regexp.MustCompile(`\bdeclare\b`), `builtin declare`},
+	{regexp.MustCompile(`\$\(type\b`), `$$(__goal_type`},
+}
+
+const zshPreamble = `#compdef goal
+
+__goal_bash_source() {
+    alias shopt=':'
+    alias _expand=_bash_expand
+    alias _complete=_bash_comp
+    emulate -L sh
+    setopt kshglob noshglob braceexpand
+    source "$@"
+}
+
+__goal_type() {
+    # -t is not supported by zsh
+    if [ "$1" = "-t" ]; then
+        shift
+        # pretend compopt is a builtin, so that the script would toggle the trailing spaces through it
+        if [ "$1" = "__goal_compopt" ]; then
+            echo builtin
+            return 0
+        fi
+    fi
+    type "$@"
+}
+
+__goal_compgen() {
+    local completions w
+    completions=( $(compgen "$@") ) || return $?
+    # filter by the given word as prefix
+    while [[ "$1" = -* && "$1" != -- ]]; do
+        shift
+        shift
+    done
+    if [[ "$1" == -- ]]; then
+        shift
+    fi
+    for w in "${completions[@]}"; do
+        if [[ "${w}" = "$1"* ]]; then
+            echo "${w}"
+        fi
+    done
+}
+
+__goal_compopt() {
+    true # not supported by the bash completion emulation
+}
+
+__goal_ltrim_colon_completions() {
+    if [[ "$1" == *:* && "$COMP_WORDBREAKS" == *:* ]]; then
+        # Remove colon-word prefix from COMPREPLY items
+        local colon_word=${1%${1##*:}}
+        local i=${#COMPREPLY[*]}
+        while [[ $((--i)) -ge 0 ]]; do
+            COMPREPLY[$i]=${COMPREPLY[$i]#"$colon_word"}
+        done
+    fi
+}
+
+__goal_get_comp_words_by_ref() {
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[${COMP_CWORD}-1]}"
+    words=("${COMP_WORDS[@]}")
+    cword=("${COMP_CWORD[@]}")
+}
+
+__goal_filedir() {
+    local RET OLD_IFS w
+    OLD_IFS="$IFS"
+    IFS=$'\n'
+    if [ "$1" = "-d" ]; then
+        shift
+        RET=( $(compgen -d) )
+    else
+        RET=( $(compgen -f) )
+    fi
+    IFS="$OLD_IFS"
+    for w in ${RET[@]}; do
+        if [[ ! "${w}" = "${cur}"* ]]; then
+            continue
+        fi
+        if [ -d "${w}" ]; then
+            COMPREPLY+=("${w}/")
+        else
+            COMPREPLY+=("${w}")
+        fi
+    done
+}
+
+autoload -U +X bashcompinit && bashcompinit
+
+__goal_bash_source <(cat <<'BASH_COMPLETION_EOF'
+`
+
+const zshPostscript = `BASH_COMPLETION_EOF
+)
+`
+
+// convertBashToZsh converts the bash completion script to be sourced by zsh.
+func convertBashToZsh(bash string) string {
+	for _, conversion := range zshConversions {
+		bash = conversion.pattern.ReplaceAllString(bash, conversion.replacement)
+	}
+	return bash
+}
+
+func genZshCompletion(root *cobra.Command, w io.Writer) error {
+	var bash bytes.Buffer
+	if err := genBashCompletion(root, &bash); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, zshPreamble+convertBashToZsh(bash.String())+zshPostscript)
+	return err
+}
+
+const fishPreamble = `# fish completion for goal
+
+# __goal_command_path prints the command path of the current command line, skipping the flags and their values.
+function __goal_command_path
+    set -l tokens (commandline -opc)
+    set -l path goal
+    set -l skip 0
+    for token in $tokens[2..-1]
+        if test $skip -eq 1
+            set skip 0
+            continue
+        end
+        switch $token
+            case '--*=*'
+            case '-*'
+                if contains -- $token $__goal_value_flags
+                    set skip 1
+                end
+            case '*'
+                set path $path $token
+        end
+    end
+    echo $path
+end
+
+function __goal_using_command
+    test (__goal_command_path) = "$argv"
+end
+
+function __goal_seen_command
+    string match -q -- "$argv*" (__goal_command_path)
+end
+
+# __goal_list lists the dynamic completions, for the data directory selected on the command line.
+function __goal_list
+    set -l args
+    set -l tokens (commandline -opc)
+    for i in (seq (count $tokens))
+        switch $tokens[$i]
+            case -d --datadir -k --kmddir
+                set -l next (math $i + 1)
+                if test $next -le (count $tokens)
+                    set args $args $tokens[$i] $tokens[$next]
+                end
+            case '--datadir=*' '--kmddir=*'
+                set args $args $tokens[$i]
+        end
+    end
+    goal completion list $argv $args 2>/dev/null
+end
+
+`
+
+// fishQuote quotes the given string for fish.
+func fishQuote(s string) string {
+	return "'" + strings.Replace(strings.Replace(s, `\`, `\\`, -1), "'", `\'`, -1) + "'"
+}
+
+// fishFlagCompletion returns the fish expression completing the value of the given flag, if any.
+func fishFlagCompletion(flag *pflag.Flag) string {
+	for key, values := range flag.Annotations {
+		if key == cobra.BashCompSubdirsInDir {
+			return "-x -a '(__fish_complete_directories)'"
+		}
+		if key != cobra.BashCompCustom || len(values) == 0 {
+			continue
+		}
+		switch values[0] {
+		case "__goal_accounts":
+			return "-x -a '(__goal_list " + accountCompletions + ")'"
+		case "__goal_wallets":
+			return "-x -a '(__goal_list " + walletCompletions + ")'"
+		case "__goal_datadirs":
+			return "-x -a '(__goal_list " + dataDirCompletions + " (commandline -ct))'"
+		}
+	}
+	if flag.NoOptDefVal == "" {
+		return "-r -F"
+	}
+	return ""
+}
+
+func writeFishFlag(w io.Writer, condition string, flag *pflag.Flag) {
+	if flag.Hidden {
+		return
+	}
+	fmt.Fprintf(w, "complete -c goal -n %s", fishQuote(condition))
+	if flag.Shorthand != "" {
+		fmt.Fprintf(w, " -s %s", flag.Shorthand)
+	}
+	fmt.Fprintf(w, " -l %s", flag.Name)
+	if completion := fishFlagCompletion(flag); completion != "" {
+		fmt.Fprintf(w, " %s", completion)
+	}
+	fmt.Fprintf(w, " -d %s\n", fishQuote(flag.Usage))
+}
+
+func genFishCompletion(root *cobra.Command, w io.Writer) error {
+	annotateCompletions(root)
+	var b bytes.Buffer
+	b.WriteString(fishPreamble)
+
+	// the flags taking a value, whose value isn't part of the command path
+	valueFlags := map[string]bool{}
+	var collect func(cmd *cobra.Command)
+	collect = func(cmd *cobra.Command) {
+		for _, flags := range []*pflag.FlagSet{cmd.LocalNonPersistentFlags(), cmd.PersistentFlags()} {
+			flags.VisitAll(func(flag *pflag.Flag) {
+				if flag.NoOptDefVal == "" {
+					valueFlags["--"+flag.Name] = true
+					if flag.Shorthand != "" {
+						valueFlags["-"+flag.Shorthand] = true
+					}
+				}
+			})
+		}
+		for _, sub := range cmd.Commands() {
+			collect(sub)
+		}
+	}
+	collect(root)
+	names := make([]string, 0, len(valueFlags))
+	for name := range valueFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(&b, "set -g __goal_value_flags %s\n\n", strings.Join(names, " "))
+	b.WriteString("complete -c goal -f\n")
+
+	var gen func(cmd *cobra.Command)
+	gen = func(cmd *cobra.Command) {
+		path := cmd.CommandPath()
+		for _, sub := range cmd.Commands() {
+			if !sub.IsAvailableCommand() {
+				continue
+			}
+			fmt.Fprintf(&b, "complete -c goal -n %s -a %s -d %s\n", fishQuote("__goal_using_command "+path), sub.Name(), fishQuote(sub.Short))
+		}
+		cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+			writeFishFlag(&b, "__goal_using_command "+path, flag)
+		})
+		cmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+			writeFishFlag(&b, "__goal_seen_command "+path, flag)
+		})
+		if completion, ok := completedArgs[strings.Replace(path, " ", "_", -1)]; ok {
+			kind := strings.TrimPrefix(completion, "__goal_")
+			fmt.Fprintf(&b, "complete -c goal -n %s -a '(__goal_list %s)'\n", fishQuote("__goal_using_command "+path), kind)
+		}
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				gen(sub)
+			}
+		}
+	}
+	gen(root)
+
+	_, err := b.WriteTo(w)
+	return err
+}
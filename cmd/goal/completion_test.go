@@ -0,0 +1,99 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestListCompletionsFromDataDir(t *testing.T) {
+	root, err := ioutil.TempDir("", "goal-completion")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	dataDir := filepath.Join(root, "node")
+	genesis := bookkeeping.Genesis{SchemaID: "v1", Network: "testnet", Proto: protocol.ConsensusCurrentVersion}
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, genesis.ID()), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dataDir, config.GenesisJSONFile), protocol.EncodeJSON(genesis), 0600))
+	accountList := `{"Accounts": {"ADDRESS1": "bob", "ADDRESS2": "alice"}}`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dataDir, genesis.ID(), "accountList.json"), []byte(accountList), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "nested", "node"), 0700))
+
+	require.Equal(t, []string{"alice", "bob"}, listAccountNames(dataDir))
+	require.Empty(t, listAccountNames(filepath.Join(root, "nested")))
+
+	os.Setenv("ALGORAND_DATA", "")
+	dirs := listDataDirs(filepath.Join(root, "n"))
+	require.Equal(t, []string{filepath.Join(root, "nested") + string(filepath.Separator), dataDir}, dirs)
+}
+
+func TestBashCompletion(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, genBashCompletion(rootCmd, &out))
+	script := out.String()
+
+	require.Contains(t, script, "__goal_accounts()")
+	require.Contains(t, script, "        goal_account_rename)\n            __goal_accounts\n")
+	require.Contains(t, script, "    flags_with_completion+=(\"--from\")\n    flags_completion+=(\"__goal_accounts\")\n")
+	require.Contains(t, script, "    flags_with_completion+=(\"--wallet\")\n    flags_completion+=(\"__goal_wallets\")\n")
+	require.Contains(t, script, "    flags_with_completion+=(\"--datadir\")\n    flags_completion+=(\"__goal_datadirs\")\n")
+}
+
+func TestConvertBashToZsh(t *testing.T) {
+	bash := `    local c=0
+    flags+=("--datadir=")
+    must_have_one_flag+=("--address=")
+    declare -F __custom_func >/dev/null && __custom_func
+    if [[ $(type -t compopt) = "builtin" ]]; then
+    COMPREPLY=( $(compgen -W "${allflags[*]}" -- "$cur") )
+    _get_comp_words_by_ref "$@" cur prev words cword
+    _filedir -d
+`
+	zsh := `    local c; c=0
+    flags+=("--datadir"); two_word_flags+=("--datadir")
+    must_have_one_flag+=("--address")
+    whence -w __custom_func >/dev/null && __custom_func
+    if [[ $(__goal_type -t __goal_compopt) = "builtin" ]]; then
+    COMPREPLY=( $(__goal_compgen -W "${allflags[*]}" -- "$cur") )
+    __goal_get_comp_words_by_ref "$*" cur prev words cword
+    __goal_filedir -d
+`
+	require.Equal(t, zsh, convertBashToZsh(bash))
+}
+
+func TestFishCompletion(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, genFishCompletion(rootCmd, &out))
+	script := out.String()
+
+	require.Contains(t, script, "complete -c goal -n '__goal_using_command goal' -a completion -d 'Shell completion helper'\n")
+	require.Contains(t, script, "complete -c goal -n '__goal_using_command goal clerk send' -s f -l from -x -a '(__goal_list accounts)'")
+	require.Contains(t, script, "complete -c goal -n '__goal_seen_command goal clerk' -s w -l wallet -x -a '(__goal_list wallets)'")
+	require.Contains(t, script, "complete -c goal -n '__goal_seen_command goal' -s d -l datadir -x -a '(__goal_list datadirs (commandline -ct))'")
+	require.Contains(t, script, "complete -c goal -n '__goal_using_command goal account rename' -a '(__goal_list accounts)'\n")
+	require.NotContains(t, script, "-a list -d 'List the dynamic completions")
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/libgoal"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var paramsFilename string
+
+func init() {
+	clerkCmd.AddCommand(paramsCmd)
+	clerkCmd.AddCommand(constructCmd)
+
+	paramsCmd.Flags().StringVarP(&paramsFilename, "out", "o", "", "File to write the transaction params to (required)")
+	paramsCmd.MarkFlagRequired("out")
+
+	constructCmd.Flags().StringVarP(&account, "from", "f", "", "Account address to send the money from (required)")
+	constructCmd.Flags().StringVarP(&toAddress, "to", "t", "", "Address to send the money to (required)")
+	constructCmd.Flags().Uint64VarP(&amount, "amount", "a", 0, "The amount to be transferred (required), in microAlgos")
+	constructCmd.Flags().Uint64Var(&fee, "fee", 0, "The transaction fee (determined from the params by default), in microAlgos")
+	constructCmd.Flags().Uint64Var(&firstValid, "firstvalid", 0, "The first round where the transaction may be committed to the ledger (the last round of the params by default)")
+	constructCmd.Flags().Uint64Var(&firstValidOffset, "firstvalid-offset", 0, "Make the transaction valid from this many rounds after the last round of the params, instead of --firstvalid")
+	constructCmd.Flags().Uint64Var(&lastValid, "lastvalid", 0, "The last round where the transaction may be committed to the ledger (the first valid round plus MaxTxnLife by default)")
+	constructCmd.Flags().StringVar(&noteBase64, "noteb64", "", "Note (URL-base64 encoded)")
+	constructCmd.Flags().StringVarP(&noteText, "note", "n", "", "Note text (ignored if --noteb64 used also)")
+	constructCmd.Flags().StringVarP(&leaseBase64, "lease", "x", "", "Lease of the transaction (base64 encoded 32 bytes), held through its last valid round once committed")
+	constructCmd.Flags().StringVarP(&closeToAddress, "close-to", "c", "", "Close account and send remainder to this address")
+	constructCmd.Flags().StringVarP(&paramsFilename, "params", "p", "", "File of transaction params written by goal clerk params, used instead of asking the node")
+	constructCmd.Flags().StringVarP(&txFilename, "out", "o", "", "File to write the unsigned transaction to (required)")
+
+	constructCmd.MarkFlagRequired("from")
+	constructCmd.MarkFlagRequired("to")
+	constructCmd.MarkFlagRequired("amount")
+	constructCmd.MarkFlagRequired("out")
+}
+
+// readTxnParams reads a file written by goal clerk params
+func readTxnParams(filename string) (params models.TransactionParams) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		reportErrorf(fileReadError, filename, err)
+	}
+	err = json.Unmarshal(data, &params)
+	if err != nil {
+		reportErrorf(errorParamsDecode, filename, err)
+	}
+	return
+}
+
+// constructPayment builds the payment goal clerk construct asks for from params
+func constructPayment(params models.TransactionParams, note []byte, lease [32]byte) (transactions.Transaction, error) {
+	tx, err := libgoal.MakePayment(params, account, toAddress, fee, amount, note, closeToAddress, lease)
+	if err != nil {
+		return tx, err
+	}
+	err = setValidRounds(&tx)
+	return tx, err
+}
+
+// setValidRounds moves the validity window of tx, which starts at the last round of the node and lasts MaxTxnLife
+// rounds as made by libgoal, to the one given by --firstvalid or --firstvalid-offset, and --lastvalid, if any
+func setValidRounds(tx *transactions.Transaction) error {
+	life := tx.LastValid - tx.FirstValid
+	switch {
+	case firstValid != 0 && firstValidOffset != 0:
+		return errors.New(errorFirstValidFlags)
+	case firstValid != 0:
+		tx.FirstValid = basics.Round(firstValid)
+	case firstValidOffset != 0:
+		tx.FirstValid += basics.Round(firstValidOffset)
+	}
+	tx.LastValid = tx.FirstValid + life
+	if lastValid != 0 {
+		tx.LastValid = basics.Round(lastValid)
+	}
+	if tx.LastValid < tx.FirstValid || tx.LastValid-tx.FirstValid > life {
+		return fmt.Errorf(errorValidRounds, tx.FirstValid, tx.LastValid, life)
+	}
+	return nil
+}
+
+var paramsCmd = &cobra.Command{
+	Use:   "params -o FILE",
+	Short: "Save the transaction params of the network to a file",
+	Long:  `Save the params goal clerk construct needs to build a transaction without a node: the suggested fee, the last round, the consensus protocol and the genesis ID and hash of the network. Transactions built from the params are valid until MaxTxnLife rounds after their last round.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		client := ensureAlgodClient(ensureSingleDataDir())
+		params, err := client.SuggestedParams()
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+
+		data, _ := json.MarshalIndent(params, "", "  ")
+		err = ioutil.WriteFile(paramsFilename, data, 0644)
+		if err != nil {
+			reportErrorf(fileWriteError, paramsFilename, err)
+		}
+		reportInfof(infoParamsWritten, params.LastRound, paramsFilename)
+	},
+}
+
+var constructCmd = &cobra.Command{
+	Use:   "construct",
+	Short: "Construct an unsigned payment transaction",
+	Long: `Construct an unsigned payment transaction and write it to a file, to be signed with goal clerk sign and sent with goal clerk broadcast. With --params, the transaction is built from a file written by goal clerk params instead of the params of a node, so that no node is needed.
+This makes for an offline signing workflow, where the keys never leave an air-gapped machine:
+  online:     goal clerk params -o params.json
+  air-gapped: goal clerk construct --params params.json -f FROM -t TO -a AMOUNT -o unsigned.tx
+  air-gapped: goal clerk sign --mnemonic-prompt -i unsigned.tx -o signed.tx
+  online:     goal clerk broadcast -f signed.tx
+With --firstvalid or --firstvalid-offset, the transaction is only valid from a later round, for instance to pre-sign a payroll payment. Hand the signed
+transaction file to goal clerk scheduler to broadcast it once that round arrives.`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		var params models.TransactionParams
+		if paramsFilename != "" {
+			params = readTxnParams(paramsFilename)
+		} else {
+			client := ensureAlgodClient(ensureSingleDataDir())
+			var err error
+			params, err = client.SuggestedParams()
+			if err != nil {
+				reportErrorf(errorRequestFail, err)
+			}
+		}
+
+		tx, err := constructPayment(params, parseNote(cmd), parseLease(leaseBase64))
+		if err != nil {
+			reportErrorf(errorConstructingTX, err)
+		}
+
+		// Wrap in a transactions.SignedTxn with an empty sig, as goal clerk sign expects
+		stxn, err := transactions.AssembleSignedTxn(tx, crypto.Signature{}, crypto.MultisigSig{})
+		if err != nil {
+			reportErrorf(errorConstructingTX, err)
+		}
+		err = ioutil.WriteFile(txFilename, protocol.Encode(stxn), 0600)
+		if err != nil {
+			reportErrorf(fileWriteError, txFilename, err)
+		}
+		reportInfof(infoTxConstructed, tx.ID(), txFilename)
+		reportResult(sentTransaction{TxID: tx.ID().String(), Fee: tx.Fee.Raw}, tx.ID().String(), nil)
+	},
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestConstructPayment(t *testing.T) {
+	var from, to basics.Address
+	crypto.RandBytes(from[:])
+	crypto.RandBytes(to[:])
+	genesisHash := crypto.Hash([]byte("genesis"))
+	params := models.TransactionParams{
+		Fee:              1,
+		GenesisID:        "test-v1",
+		GenesisHash:      genesisHash[:],
+		LastRound:        100,
+		ConsensusVersion: string(protocol.ConsensusCurrentVersion),
+	}
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+
+	// goal clerk params writes the params as JSON, which construct reads back
+	dir, err := ioutil.TempDir("", "construct")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "params.json")
+	data, err := json.Marshal(params)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filename, data, 0644))
+	require.Equal(t, params, readTxnParams(filename))
+
+	defer func() {
+		account, toAddress, amount, fee, firstValid, firstValidOffset, lastValid = "", "", 0, 0, 0, 0, 0
+	}()
+	account, toAddress, amount = from.GetUserAddress(), to.GetUserAddress(), 5
+
+	tx, err := constructPayment(params, nil, [32]byte{})
+	require.NoError(t, err)
+	require.Equal(t, from, tx.Sender)
+	require.Equal(t, to, tx.Receiver)
+	require.Equal(t, uint64(5), tx.Amount.Raw)
+	require.Equal(t, "test-v1", tx.GenesisID)
+	require.Equal(t, genesisHash, tx.GenesisHash)
+	require.Equal(t, basics.Round(100), tx.FirstValid)
+	require.Equal(t, basics.Round(100+proto.MaxTxnLife), tx.LastValid)
+	require.True(t, tx.Fee.Raw >= proto.MinTxnFee)
+
+	firstValid = 200
+	tx, err = constructPayment(params, nil, [32]byte{})
+	require.NoError(t, err)
+	require.Equal(t, basics.Round(200), tx.FirstValid)
+	require.Equal(t, basics.Round(200+proto.MaxTxnLife), tx.LastValid)
+
+	lastValid = 250
+	tx, err = constructPayment(params, nil, [32]byte{})
+	require.NoError(t, err)
+	require.Equal(t, basics.Round(200), tx.FirstValid)
+	require.Equal(t, basics.Round(250), tx.LastValid)
+
+	// --firstvalid-offset counts from the last round of the params
+	firstValid, lastValid, firstValidOffset = 0, 0, 50
+	tx, err = constructPayment(params, nil, [32]byte{})
+	require.NoError(t, err)
+	require.Equal(t, basics.Round(150), tx.FirstValid)
+	require.Equal(t, basics.Round(150+proto.MaxTxnLife), tx.LastValid)
+
+	firstValid = 200
+	_, err = constructPayment(params, nil, [32]byte{})
+	require.Error(t, err)
+
+	// the window can't end before it starts, nor last more than MaxTxnLife rounds
+	firstValid, firstValidOffset = 0, 0
+	for _, last := range []uint64{99, 101 + proto.MaxTxnLife} {
+		lastValid = last
+		_, err = constructPayment(params, nil, [32]byte{})
+		require.Error(t, err, "lastvalid %d", last)
+	}
+	lastValid = 0
+
+	params.ConsensusVersion = "unknown"
+	_, err = constructPayment(params, nil, [32]byte{})
+	require.Error(t, err)
+}
@@ -0,0 +1,190 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/algorand/go-deadlock"
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/tools/bench"
+)
+
+var (
+	benchNames       []string
+	benchDir         string
+	benchDuration    time.Duration
+	benchParallelism int
+	benchAccounts    int
+	benchOutputFile  string
+	benchJSON        bool
+	benchBaseline    string
+	benchThreshold   float64
+)
+
+func init() {
+	debugCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().StringSliceVarP(&benchNames, "benchmarks", "b", nil, "Benchmarks to run (default all of "+strings.Join(bench.Names(), ", ")+")")
+	benchCmd.Flags().StringVar(&benchDir, "dir", "", "Directory to run the disk benchmarks in (defaults to the data directory, or the current directory if there is none)")
+	benchCmd.Flags().DurationVarP(&benchDuration, "time", "t", 5*time.Second, "Time to run each benchmark for")
+	benchCmd.Flags().IntVarP(&benchParallelism, "parallelism", "p", 0, "Number of goroutines used by the parallel benchmarks (0 uses all the CPUs)")
+	benchCmd.Flags().IntVar(&benchAccounts, "accounts", 10000, "Number of accounts of the synthetic ledger used for evaluating blocks")
+	benchCmd.Flags().StringVarP(&benchOutputFile, "out", "o", "", "Write the JSON report to this file")
+	benchCmd.Flags().BoolVar(&benchJSON, "json", false, "Print the JSON report rather than a summary (implied by --output json)")
+	benchCmd.Flags().StringVar(&benchBaseline, "baseline", "", "JSON report of an earlier run to compare against; a slowdown beyond the threshold fails the command")
+	benchCmd.Flags().Float64Var(&benchThreshold, "threshold", 10, "Slowdown, in percent of the baseline rate, that counts as a regression")
+}
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Diagnose the node and the host it runs on",
+	Long:  "Collection of commands for diagnosing the node and the host it runs on",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		// If no arguments passed, we should fallback to help
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark the host resources a node depends on",
+	Long: `Run standardized benchmarks of signature verification throughput, block evaluation rate and database commit latency, and report their results.
+The disk benchmarks run in the data directory, so that they measure the disk the node uses. The JSON report of a run can be kept
+and later passed as --baseline, to detect regressions between releases or hosts.`,
+	Example: "goal debug bench -d ~/node/data -o bench.json\ngoal debug bench -b sigverify,dbcommit --baseline bench.json",
+	Args:    validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		benchJSON = benchJSON || report.json()
+		dir := benchDir
+		if dir == "" {
+			dir = resolveDataDir()
+		}
+		if dir == "" {
+			dir = "."
+		}
+		var baseline bench.Report
+		if benchBaseline != "" {
+			var err error
+			if baseline, err = bench.LoadReport(benchBaseline); err != nil {
+				reportErrorf(errorBenchReport, benchBaseline, err)
+			}
+		}
+
+		// the deadlock detection isn't enabled on production nodes; don't let its overhead skew the results.
+		deadlock.Opts.Disable = true
+		opts := bench.Options{
+			Dir:         dir,
+			Duration:    benchDuration,
+			Parallelism: benchParallelism,
+			Accounts:    benchAccounts,
+		}
+		report, err := bench.Run(benchNames, opts, func(name string) {
+			if !benchJSON {
+				reportInfof(infoBenchRunning, name, bench.Describe(name))
+			}
+		})
+		if err != nil {
+			reportErrorf(errorBench, err)
+		}
+
+		if benchOutputFile != "" {
+			if err = bench.SaveReport(report, benchOutputFile); err != nil {
+				reportErrorf(errorBenchReport, benchOutputFile, err)
+			}
+		}
+		if benchJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				reportErrorf(errorBench, err)
+			}
+			fmt.Println(string(data))
+		} else {
+			printBenchReport(os.Stdout, report)
+		}
+
+		failed := false
+		for _, result := range report.Results {
+			failed = failed || result.Error != ""
+		}
+		if benchBaseline != "" {
+			comparisons := bench.Compare(baseline, report, benchThreshold)
+			if !benchJSON {
+				printBenchComparison(os.Stdout, baseline, comparisons)
+			}
+			for _, c := range comparisons {
+				failed = failed || c.Regression
+			}
+		}
+		if failed {
+			os.Exit(exitError)
+		}
+	},
+}
+
+func printBenchReport(out io.Writer, report bench.Report) {
+	fmt.Fprintf(out, "\n%s, %s/%s, %d CPUs, disk benchmarks in %s\n", report.BuildVersion, report.Host.OS, report.Host.Arch, report.Host.CPUs, report.Dir)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BENCHMARK\tRATE\tDETAILS")
+	for _, result := range report.Results {
+		if result.Error != "" {
+			fmt.Fprintf(w, "%s\tfailed\t%s\n", result.Name, result.Error)
+			continue
+		}
+		var details []string
+		if result.Latency != nil {
+			details = append(details, fmt.Sprintf("p50 %v, p99 %v, max %v", result.Latency.P50, result.Latency.P99, result.Latency.Max))
+		}
+		for _, name := range sortedKeys(result.Extra) {
+			details = append(details, fmt.Sprintf("%s %.0f", name, result.Extra[name]))
+		}
+		fmt.Fprintf(w, "%s\t%.1f %s\t%s\n", result.Name, result.Rate, result.Unit, strings.Join(details, ", "))
+	}
+	w.Flush()
+}
+
+func printBenchComparison(out io.Writer, baseline bench.Report, comparisons []bench.Comparison) {
+	fmt.Fprintf(out, "\nCompared to %s on %s (%s):\n", baseline.BuildVersion, baseline.Host.Hostname, baseline.Started.Format(time.RFC3339))
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BENCHMARK\tBASELINE\tCURRENT\tCHANGE\t")
+	for _, c := range comparisons {
+		flag := ""
+		if c.Regression {
+			flag = "REGRESSION"
+		}
+		fmt.Fprintf(w, "%s\t%.1f\t%.1f\t%+.1f%%\t%s\n", c.Name, c.Baseline, c.Current, c.Change, flag)
+	}
+	w.Flush()
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var (
+	dryrunTrace bool
+	dryrunProto string
+)
+
+func init() {
+	clerkCmd.AddCommand(dryrunCmd)
+
+	dryrunCmd.Flags().StringVarP(&txFilename, "txfile", "t", "", "Signed transaction file to dry run")
+	dryrunCmd.Flags().BoolVar(&dryrunTrace, "trace", false, "Print the execution trace of every program")
+	dryrunCmd.Flags().StringVarP(&dryrunProto, "proto", "P", "", "Consensus protocol version to evaluate with, instead of the current protocol of the node")
+	dryrunCmd.MarkFlagRequired("txfile")
+}
+
+// dryrunResult is what `goal clerk dryrun` reports for every transaction of the file
+type dryrunResult struct {
+	TxID     string `json:"txid"`
+	LogicSig bool   `json:"logicsig"`
+	Cost     int    `json:"cost,omitempty"`
+	Pass     bool   `json:"pass"`
+	Error    string `json:"error,omitempty"`
+	Trace    string `json:"trace,omitempty"`
+}
+
+var dryrunCmd = &cobra.Command{
+	Use:   "dryrun -t SIGNED_TXN_FILE",
+	Short: "Test a program offline",
+	Long: `Run the LogicSig programs of the transactions of the file against the consensus parameters of the node, without sending anything, and print whether each program approves its transaction and the cost of the program.
+The programs of a group see the whole group, as they do once sent. With --trace, also print the ops each program ran and the top of the stack after every one of them.
+With --proto, the programs are evaluated with the parameters of that consensus protocol version, and no node is needed.`,
+	Example: "goal clerk dryrun -t signed.txn --trace",
+	Args:    validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		txns := readSignedTxnFile(txFilename)
+		proto := dryrunConsensusParams()
+
+		var results []dryrunResult
+		for _, txgroup := range transactionGroups(txns) {
+			for i := range txgroup {
+				results = append(results, dryrunTxn(txgroup, i, proto))
+			}
+		}
+
+		reportResult(results, "", func() {
+			for i, res := range results {
+				switch {
+				case !res.LogicSig:
+					fmt.Printf("tx[%d] %s: no LogicSig\n", i, res.TxID)
+				case res.Error != "":
+					fmt.Printf("tx[%d] %s: ERROR, cost %d: %s\n", i, res.TxID, res.Cost, res.Error)
+				case res.Pass:
+					fmt.Printf("tx[%d] %s: PASS, cost %d\n", i, res.TxID, res.Cost)
+				default:
+					fmt.Printf("tx[%d] %s: REJECT, cost %d\n", i, res.TxID, res.Cost)
+				}
+				if res.Trace != "" {
+					fmt.Print(res.Trace)
+				}
+			}
+		})
+	},
+}
+
+// dryrunConsensusParams returns the parameters of the --proto version, or of
+// the current protocol of the node
+func dryrunConsensusParams() config.ConsensusParams {
+	version := protocol.ConsensusVersion(dryrunProto)
+	if version == "" {
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+		params, err := client.SuggestedParams()
+		if err != nil {
+			reportErrorf(errorNodeStatus, err)
+		}
+		version = protocol.ConsensusVersion(params.ConsensusVersion)
+	}
+	proto, ok := config.Consensus[version]
+	if !ok {
+		reportErrorf(errorDryrunProto, version)
+	}
+	return proto
+}
+
+// dryrunTxn checks and evaluates the LogicSig program of the transaction at
+// groupIndex of txgroup
+func dryrunTxn(txgroup []transactions.SignedTxn, groupIndex int, proto config.ConsensusParams) (res dryrunResult) {
+	stxn := &txgroup[groupIndex]
+	res.TxID = stxn.ID().String()
+	if stxn.Lsig.Blank() {
+		return
+	}
+	res.LogicSig = true
+
+	ep := logic.EvalParams{
+		Txn:        stxn,
+		Proto:      &proto,
+		TxnGroup:   txgroup,
+		GroupIndex: groupIndex,
+	}
+	cost, err := logic.Check(stxn.Lsig.Logic, ep)
+	if err != nil {
+		res.Error = err.Error()
+		return
+	}
+	res.Cost = cost
+	if uint64(cost) > proto.LogicSigMaxCost {
+		res.Error = fmt.Sprintf(errorDryrunCost, cost, proto.LogicSigMaxCost)
+		return
+	}
+
+	if dryrunTrace {
+		ep.Trace = &strings.Builder{}
+	}
+	res.Pass, err = logic.Eval(stxn.Lsig.Logic, ep)
+	if err != nil {
+		res.Error = err.Error()
+	}
+	if ep.Trace != nil {
+		res.Trace = ep.Trace.String()
+	}
+	return
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestDryrunTxn(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusFuture]
+
+	// approves the second transaction of a group of two
+	program, err := logic.AssembleString("global GroupSize\nint 2\n==\ntxn GroupIndex\nint 1\n==\n&&")
+	require.NoError(t, err)
+
+	txgroup := make([]transactions.SignedTxn, 2)
+	txgroup[0].Txn.Type = protocol.PaymentTx
+	txgroup[1].Txn.Type = protocol.PaymentTx
+	txgroup[0].Lsig.Logic = program
+	txgroup[1].Lsig.Logic = program
+
+	res := dryrunTxn(txgroup, 0, proto)
+	require.True(t, res.LogicSig)
+	require.False(t, res.Pass)
+	require.Empty(t, res.Error)
+	require.NotZero(t, res.Cost)
+
+	res = dryrunTxn(txgroup, 1, proto)
+	require.True(t, res.Pass)
+	require.Empty(t, res.Trace)
+
+	dryrunTrace = true
+	defer func() { dryrunTrace = false }()
+	res = dryrunTxn(txgroup, 1, proto)
+	require.True(t, res.Pass)
+	require.Contains(t, res.Trace, "global GroupSize")
+
+	// without a LogicSig there is nothing to run
+	txgroup[1].Lsig = transactions.LogicSig{}
+	res = dryrunTxn(txgroup, 1, proto)
+	require.False(t, res.LogicSig)
+	require.False(t, res.Pass)
+
+	// the protocol doesn't support LogicSig programs
+	res = dryrunTxn(txgroup, 0, config.Consensus[protocol.ConsensusCurrentVersion])
+	require.NotEmpty(t, res.Error)
+
+	// an error in the program
+	txgroup[0].Lsig.Logic, err = logic.AssembleString("int 1\nint 0\n/")
+	require.NoError(t, err)
+	res = dryrunTxn(txgroup, 0, proto)
+	require.False(t, res.Pass)
+	require.Contains(t, res.Error, "/ 0")
+	require.Contains(t, res.Trace, "ERROR")
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	clerkCmd.AddCommand(feeInfoCmd)
+}
+
+var feeInfoCmd = &cobra.Command{
+	Use:   "feeinfo",
+	Short: "Show the fees transactions currently need",
+	Long: `Show the suggested fee per byte, how full the transaction pool of the node is, and the fees per byte a new transaction needs for the pool to accept it and to likely be included in the next block.
+A transaction of L bytes should pay the highest of these fees per byte times L, and at least the minimum fee.`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+		info, err := client.FeeInfo()
+		if err != nil {
+			reportErrorf(errorNodeStatus, err)
+		}
+
+		reportResult(info, "", func() {
+			fmt.Printf("Suggested fee: %d microAlgos/byte\n", info.Fee)
+			fmt.Printf("Minimum fee: %d microAlgos\n", info.MinFee)
+			fmt.Printf("Transaction pool: %d of %d transactions, %d bytes\n", info.PoolTxns, info.PoolSize, info.PoolBytes)
+			if info.PoolMinFee != 0 {
+				fmt.Printf("Fee to enter the full pool: %d microAlgos/byte\n", info.PoolMinFee)
+			}
+			if info.NextBlockFee != 0 {
+				fmt.Printf("Fee for the next block: %d microAlgos/byte\n", info.NextBlockFee)
+			} else {
+				fmt.Printf("Fee for the next block: any, the pending transactions fit in it\n")
+			}
+		})
+	},
+}
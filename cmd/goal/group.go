@@ -0,0 +1,135 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/transactions"
+)
+
+func init() {
+	clerkCmd.AddCommand(groupCmd)
+	clerkCmd.AddCommand(splitCmd)
+
+	groupCmd.Flags().StringVarP(&txFilename, "infile", "i", "", "File with the unsigned transactions to group together")
+	groupCmd.Flags().StringVarP(&outFilename, "outfile", "o", "", "Filename for writing the grouped transactions")
+	groupCmd.MarkFlagRequired("infile")
+	groupCmd.MarkFlagRequired("outfile")
+
+	splitCmd.Flags().StringVarP(&txFilename, "infile", "i", "", "File with the transactions to split")
+	splitCmd.Flags().StringVarP(&outFilename, "outfile", "o", "", "Base filename for writing the individual transactions; each transaction is written to this name with its index inserted before the extension")
+	splitCmd.MarkFlagRequired("infile")
+	splitCmd.MarkFlagRequired("outfile")
+}
+
+var groupCmd = &cobra.Command{
+	Use:   "group -i INFILE -o OUTFILE",
+	Short: "Group transactions together",
+	Long: `Form an atomic transaction group from the transactions of INFILE, in the order of the file: either all the transactions of the group are committed, in this order, or none of them is.
+The transactions must not be signed yet, since grouping them changes them. Sign the grouped transactions afterwards, with goal clerk sign, or split them with goal clerk split to have each sender sign theirs.
+Send the signed group with goal clerk rawsend.`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		txns := readSignedTxnFile(txFilename)
+		grouped, err := groupTransactions(txns)
+		if err != nil {
+			reportErrorln(err)
+		}
+		writeSignedTxnFile(outFilename, grouped)
+		reportInfof(infoTxGrouped, len(grouped), grouped[0].Txn.Group, outFilename)
+	},
+}
+
+var splitCmd = &cobra.Command{
+	Use:   "split -i INFILE -o OUTFILE",
+	Short: "Split a file of transactions into one file per transaction",
+	Long:  `Write each transaction of INFILE to a file of its own, so that the transactions of a group can be handed to their senders to sign. The i'th transaction goes to OUTFILE with -i inserted before its extension, e.g. out-0.txn, out-1.txn, etc. for -o out.txn. Concatenate the signed files back, in the same order, to send the group.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		txns := readSignedTxnFile(txFilename)
+		for i, txn := range txns {
+			filename := splitFilename(outFilename, i)
+			writeSignedTxnFile(filename, []transactions.SignedTxn{txn})
+			reportInfof(infoTxSplit, txn.ID(), filename)
+		}
+	},
+}
+
+// groupTransactions sets the group of the unsigned transactions txns to the ID of the group they make up
+func groupTransactions(txns []transactions.SignedTxn) ([]transactions.SignedTxn, error) {
+	if len(txns) == 0 {
+		return nil, fmt.Errorf(errorGroupEmpty)
+	}
+
+	txgroup := make([]transactions.Transaction, len(txns))
+	for i, stxn := range txns {
+		if isSigned(stxn) {
+			return nil, fmt.Errorf(errorGroupSigned, i, stxn.ID())
+		}
+		txgroup[i] = stxn.Txn
+	}
+
+	group := transactions.ComputeGroupID(txgroup)
+	grouped := make([]transactions.SignedTxn, len(txns))
+	for i, stxn := range txns {
+		stxn.Txn.Group = group
+		stxn.ResetCaches()
+		grouped[i] = stxn
+	}
+	return grouped, nil
+}
+
+// isSigned returns whether stxn carries a signature, or any multisig subsignature
+func isSigned(stxn transactions.SignedTxn) bool {
+	if stxn.Sig != (crypto.Signature{}) {
+		return true
+	}
+	for _, subsig := range stxn.Msig.Subsigs {
+		if subsig.Sig != (crypto.Signature{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitFilename returns the name of the file goal clerk split writes the i'th transaction to
+func splitFilename(outFilename string, i int) string {
+	ext := filepath.Ext(outFilename)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(outFilename, ext), i, ext)
+}
+
+// transactionGroups cuts txns into the groups they make up: consecutive transactions of the same group
+// form one, and every transaction without a group forms its own
+func transactionGroups(txns []transactions.SignedTxn) (txgroups [][]transactions.SignedTxn) {
+	for len(txns) > 0 {
+		groupLen := 1
+		if group := txns[0].Txn.Group; group != (crypto.Digest{}) {
+			for groupLen < len(txns) && txns[groupLen].Txn.Group == group {
+				groupLen++
+			}
+		}
+		txgroups = append(txgroups, txns[:groupLen])
+		txns = txns[groupLen:]
+	}
+	return
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestGroupTransactions(t *testing.T) {
+	var txns []transactions.SignedTxn
+	for i := 0; i < 3; i++ {
+		var stxn transactions.SignedTxn
+		stxn.Txn.Type = protocol.PaymentTx
+		crypto.RandBytes(stxn.Txn.Sender[:])
+		stxn.Txn.Amount = basics.MicroAlgos{Raw: uint64(i)}
+		txns = append(txns, stxn)
+	}
+
+	grouped, err := groupTransactions(txns)
+	require.NoError(t, err)
+	require.Len(t, grouped, 3)
+	group := grouped[0].Txn.Group
+	require.NotEqual(t, crypto.Digest{}, group)
+	for i, stxn := range grouped {
+		require.Equal(t, group, stxn.Txn.Group)
+		require.NotEqual(t, txns[i].ID(), stxn.ID())
+	}
+	require.Equal(t, [][]transactions.SignedTxn{grouped}, transactionGroups(grouped))
+
+	// ungrouped transactions each make their own group
+	mixed := append([]transactions.SignedTxn{txns[0]}, grouped...)
+	mixed = append(mixed, txns[1])
+	require.Equal(t, [][]transactions.SignedTxn{txns[:1], grouped, txns[1:2]}, transactionGroups(mixed))
+
+	_, err = groupTransactions(nil)
+	require.Error(t, err)
+
+	txns[1].Msig.Subsigs = []crypto.MultisigSubsig{{}}
+	_, err = groupTransactions(txns)
+	require.NoError(t, err)
+	crypto.RandBytes(txns[1].Msig.Subsigs[0].Sig[:])
+	_, err = groupTransactions(txns)
+	require.Error(t, err)
+}
+
+func TestSplitFilename(t *testing.T) {
+	require.Equal(t, "out-0.txn", splitFilename("out.txn", 0))
+	require.Equal(t, "dir/out-12", splitFilename("dir/out", 12))
+}
@@ -0,0 +1,251 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/passphrase"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+var bulkImportFile string
+
+func init() {
+	accountCmd.AddCommand(importBulkCmd)
+
+	importBulkCmd.Flags().StringVar(&bulkImportFile, "file", "", "JSON or CSV file listing the accounts to import")
+	importBulkCmd.MarkFlagRequired("file")
+}
+
+// bulkImportEntry is an account of a goal account importbulk file. It gives either the mnemonic of the key, or the
+// key itself in base64, as a 32-byte seed or a 64-byte secret key. Accounts without a name get an Unnamed-N one.
+type bulkImportEntry struct {
+	Name     string `json:"name"`
+	Mnemonic string `json:"mnemonic"`
+	Key      string `json:"key"`
+}
+
+// bulkImportKey is a checked account of a goal account importbulk file
+type bulkImportKey struct {
+	Name    string
+	Address string
+	Seed    crypto.Seed
+}
+
+// bulkImportResult is the outcome of importing one account, as reported by goal account importbulk
+type bulkImportResult struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+	Error   string `json:"error,omitempty"`
+}
+
+var importBulkCmd = &cobra.Command{
+	Use:   "importbulk --file FILE",
+	Short: "Import many account keys from a file",
+	Long: `Import the account keys a JSON or CSV file lists into the wallet, under the names the file gives them. A JSON file holds an array of objects with "name" and either "mnemonic" or "key" fields; a CSV file has a header row naming the same columns. A key is the base64 of a 32-byte seed or of a 64-byte secret key.
+
+The whole file is checked before anything is imported: every key has to decode, and no name or address may appear twice, be taken in the account list or, for addresses, already be in the wallet. The accounts are then imported with a single wallet handle, and a summary reports the outcome of each.`,
+	Example: `goal account importbulk --file accounts.json
+goal account importbulk --file accounts.csv -w custody`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		data, err := ioutil.ReadFile(bulkImportFile)
+		if err != nil {
+			reportErrorf(fileReadError, bulkImportFile, err)
+		}
+		entries, err := parseBulkImportFile(bulkImportFile, data)
+		if err != nil {
+			reportErrorf(errorBulkImportDecode, bulkImportFile, err)
+		}
+		if len(entries) == 0 {
+			reportErrorf(errorBulkImportEmpty, bulkImportFile)
+		}
+
+		dataDir := ensureSingleDataDir()
+		accountList := makeAccountsList(dataDir)
+		client := ensureKmdClient(dataDir)
+		wh := ensureWalletHandle(dataDir, walletName)
+
+		walletAddrs, err := client.ListAddresses(wh)
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		inWallet := make(map[string]bool, len(walletAddrs))
+		for _, addr := range walletAddrs {
+			inWallet[addr] = true
+		}
+
+		keys, problems := checkBulkImport(entries, accountList, inWallet)
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				reportWarnln(problem)
+			}
+			reportErrorf(errorBulkImportInvalid, len(problems))
+		}
+
+		results := make([]bulkImportResult, 0, len(keys))
+		rows := make([][]string, 0, len(keys))
+		failed := 0
+		for _, key := range keys {
+			result := bulkImportResult{Address: key.Address, Name: key.Name}
+			status := "imported"
+			if _, err := client.ImportKey(wh, key.Seed[:]); err != nil {
+				failed++
+				result.Error = fmt.Sprintf(errorRequestFail, err)
+				status = result.Error
+			} else {
+				if result.Name == "" {
+					result.Name = accountList.getUnnamed()
+				}
+				accountList.addAccount(result.Name, result.Address)
+			}
+			results = append(results, result)
+			rows = append(rows, []string{result.Address, result.Name, status})
+		}
+
+		reportRows(results, []string{"ADDRESS", "NAME", "STATUS"}, rows, func() {
+			for _, result := range results {
+				if result.Error != "" {
+					fmt.Printf("%s\t%s\n", result.Address, result.Error)
+					continue
+				}
+				fmt.Printf("%s\t%s\n", result.Address, result.Name)
+			}
+		})
+		reportInfof(infoBulkImported, len(keys)-failed, len(keys))
+		if failed > 0 {
+			reportErrorf(errorBulkImportIncomplete, failed, len(keys))
+		}
+	},
+}
+
+// parseBulkImportFile decodes the entries of a goal account importbulk file, as CSV if its name ends with .csv and
+// as JSON otherwise
+func parseBulkImportFile(filename string, data []byte) ([]bulkImportEntry, error) {
+	if !strings.EqualFold(filepath.Ext(filename), ".csv") {
+		var entries []bulkImportEntry
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if column != "name" && column != "mnemonic" && column != "key" {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		columns[column] = i
+	}
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	entries := make([]bulkImportEntry, 0, len(records)-1)
+	for _, record := range records[1:] {
+		entries = append(entries, bulkImportEntry{
+			Name:     field(record, "name"),
+			Mnemonic: field(record, "mnemonic"),
+			Key:      field(record, "key"),
+		})
+	}
+	return entries, nil
+}
+
+// checkBulkImport decodes the keys of the entries and checks that they can all be imported, returning the problems
+// it finds, one per line, rather than the first one
+func checkBulkImport(entries []bulkImportEntry, accountList *AccountsList, inWallet map[string]bool) (keys []bulkImportKey, problems []string) {
+	names := make(map[string]int)
+	addrs := make(map[string]int)
+	for i, entry := range entries {
+		n := i + 1
+		key := bulkImportKey{Name: entry.Name}
+		seed, err := decodeBulkImportKey(entry)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf(errorBulkImportEntry, n, err))
+			continue
+		}
+		copy(key.Seed[:], seed)
+		key.Address = basics.Address(crypto.GenerateSignatureSecrets(key.Seed).SignatureVerifier).GetUserAddress()
+
+		if first, ok := addrs[key.Address]; ok {
+			problems = append(problems, fmt.Sprintf(errorBulkImportEntry, n, fmt.Sprintf("address %s is also that of entry %d", key.Address, first)))
+		} else {
+			addrs[key.Address] = n
+		}
+		if inWallet[key.Address] {
+			problems = append(problems, fmt.Sprintf(errorBulkImportEntry, n, fmt.Sprintf("address %s is already in the wallet", key.Address)))
+		}
+
+		if key.Name != "" {
+			if ok, err := isValidName(key.Name); !ok {
+				problems = append(problems, fmt.Sprintf(errorBulkImportEntry, n, err))
+			} else if first, ok := names[key.Name]; ok {
+				problems = append(problems, fmt.Sprintf(errorBulkImportEntry, n, fmt.Sprintf("name '%s' is also that of entry %d", key.Name, first)))
+			} else if accountList.isTaken(key.Name) {
+				problems = append(problems, fmt.Sprintf(errorBulkImportEntry, n, fmt.Sprintf(errorNameAlreadyTaken, key.Name)))
+			}
+			names[key.Name] = n
+		}
+		keys = append(keys, key)
+	}
+	return
+}
+
+// decodeBulkImportKey returns the seed of the key that the entry gives
+func decodeBulkImportKey(entry bulkImportEntry) ([]byte, error) {
+	switch {
+	case entry.Mnemonic != "" && entry.Key != "":
+		return nil, fmt.Errorf("give either a mnemonic or a key, not both")
+	case entry.Mnemonic != "":
+		return passphrase.MnemonicToKey(entry.Mnemonic)
+	case entry.Key != "":
+		key, err := base64.StdEncoding.DecodeString(entry.Key)
+		if err != nil {
+			return nil, fmt.Errorf("key is not base64: %v", err)
+		}
+		if len(key) != len(crypto.Seed{}) && len(key) != len(crypto.PrivateKey{}) {
+			return nil, fmt.Errorf("key is %d bytes, rather than a %d-byte seed or a %d-byte secret key", len(key), len(crypto.Seed{}), len(crypto.PrivateKey{}))
+		}
+		return key[:len(crypto.Seed{})], nil
+	default:
+		return nil, fmt.Errorf("no mnemonic or key")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/passphrase"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+func TestParseBulkImportFile(t *testing.T) {
+	entries, err := parseBulkImportFile("accounts.json", []byte(`[{"name": "a", "mnemonic": "m"}, {"key": "k"}]`))
+	require.NoError(t, err)
+	require.Equal(t, []bulkImportEntry{{Name: "a", Mnemonic: "m"}, {Key: "k"}}, entries)
+	_, err = parseBulkImportFile("accounts.json", []byte(`[{"name": "a", "seed": "s"}]`))
+	require.Error(t, err)
+
+	entries, err = parseBulkImportFile("accounts.CSV", []byte("Name,key\na, k1 \n,k2\n"))
+	require.NoError(t, err)
+	require.Equal(t, []bulkImportEntry{{Name: "a", Key: "k1"}, {Key: "k2"}}, entries)
+	_, err = parseBulkImportFile("accounts.csv", []byte("name,seed\na,s\n"))
+	require.Error(t, err)
+}
+
+func TestCheckBulkImport(t *testing.T) {
+	var seeds []crypto.Seed
+	var addrs []string
+	for i := 0; i < 3; i++ {
+		var seed crypto.Seed
+		seed[0] = byte(i)
+		seeds = append(seeds, seed)
+		addrs = append(addrs, basics.Address(crypto.GenerateSignatureSecrets(seed).SignatureVerifier).GetUserAddress())
+	}
+	mnemonic, err := passphrase.KeyToMnemonic(seeds[0][:])
+	require.NoError(t, err)
+	sk := crypto.GenerateSignatureSecrets(seeds[1]).SK
+	accountList := &AccountsList{Accounts: map[string]string{"TAKEN": "taken"}}
+
+	entries := []bulkImportEntry{
+		{Name: "a", Mnemonic: mnemonic},
+		{Name: "b", Key: base64.StdEncoding.EncodeToString(sk[:])},
+		{Key: base64.StdEncoding.EncodeToString(seeds[2][:])},
+	}
+	keys, problems := checkBulkImport(entries, accountList, nil)
+	require.Empty(t, problems)
+	require.Len(t, keys, 3)
+	for i, key := range keys {
+		require.Equal(t, addrs[i], key.Address)
+		require.Equal(t, seeds[i], key.Seed)
+	}
+	require.Empty(t, keys[2].Name)
+
+	entries = append(entries,
+		bulkImportEntry{Name: "a", Key: base64.StdEncoding.EncodeToString(seeds[0][:])},
+		bulkImportEntry{Name: "taken", Key: "AAAA"},
+		bulkImportEntry{Name: "c"},
+		bulkImportEntry{Name: "d", Mnemonic: mnemonic, Key: "AAAA"},
+	)
+	_, problems = checkBulkImport(entries, accountList, map[string]bool{addrs[2]: true})
+	// entry 3 is in the wallet; entry 4 repeats the address and name of entry 1; entries 5 to 7 don't decode
+	require.Len(t, problems, 6)
+}
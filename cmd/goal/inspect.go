@@ -32,9 +32,22 @@ import (
 type inspectSignedTxn struct {
 	_struct struct{} `codec:",omitempty,omitemptyarray"`
 
-	Sig  crypto.Signature   `codec:"sig"`
-	Msig inspectMultisigSig `codec:"msig"`
-	Txn  inspectTransaction `codec:"txn"`
+	Sig      crypto.Signature   `codec:"sig"`
+	Msig     inspectMultisigSig `codec:"msig"`
+	Lsig     inspectLogicSig    `codec:"lsig"`
+	Txn      inspectTransaction `codec:"txn"`
+	AuthAddr checksumAddress    `codec:"sgnr"`
+}
+
+// inspectLogicSig is isomorphic to LogicSig but uses different
+// types to print public keys using algorand's address format in JSON.
+type inspectLogicSig struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Logic []byte             `codec:"l"`
+	Sig   crypto.Signature   `codec:"sig"`
+	Msig  inspectMultisigSig `codec:"msig"`
+	Args  [][]byte           `codec:"arg"`
 }
 
 // inspectMultisigSig is isomorphic to MultisigSig but uses different
@@ -65,6 +78,9 @@ type inspectTransaction struct {
 	inspectTxnHeader
 	transactions.KeyregTxnFields
 	inspectPaymentTxnFields
+	inspectAssetConfigTxnFields
+	inspectAssetTransferTxnFields
+	inspectAssetFreezeTxnFields
 }
 
 // inspectTxnHeader is isomorphic to Header but uses different
@@ -79,6 +95,9 @@ type inspectTxnHeader struct {
 	Note        []byte            `codec:"note"`
 	GenesisID   string            `codec:"gen"`
 	GenesisHash crypto.Digest     `codec:"gh"`
+	RekeyTo     checksumAddress   `codec:"rekey"`
+	Group       crypto.Digest     `codec:"grp"`
+	Lease       [32]byte          `codec:"lx"`
 }
 
 // inspectPaymentTxnFields is isomorphic to Header but uses different
@@ -91,6 +110,62 @@ type inspectPaymentTxnFields struct {
 	CloseRemainderTo checksumAddress   `codec:"close"`
 }
 
+// inspectAssetConfigTxnFields is isomorphic to AssetConfigTxnFields but uses
+// different types to print public keys using algorand's address format in JSON.
+type inspectAssetConfigTxnFields struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	ConfigAsset inspectAssetID     `codec:"caid"`
+	AssetParams inspectAssetParams `codec:"apar"`
+}
+
+// inspectAssetTransferTxnFields is isomorphic to AssetTransferTxnFields but uses
+// different types to print public keys using algorand's address format in JSON.
+type inspectAssetTransferTxnFields struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	XferAsset     inspectAssetID  `codec:"xaid"`
+	AssetAmount   uint64          `codec:"aamt"`
+	AssetSender   checksumAddress `codec:"asnd"`
+	AssetReceiver checksumAddress `codec:"arcv"`
+	AssetCloseTo  checksumAddress `codec:"aclose"`
+}
+
+// inspectAssetFreezeTxnFields is isomorphic to AssetFreezeTxnFields but uses
+// different types to print public keys using algorand's address format in JSON.
+type inspectAssetFreezeTxnFields struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	FreezeAccount checksumAddress `codec:"fadd"`
+	FreezeAsset   inspectAssetID  `codec:"faid"`
+	AssetFrozen   bool            `codec:"afrz"`
+}
+
+// inspectAssetID is isomorphic to AssetID but uses different types to print
+// public keys using algorand's address format in JSON.
+type inspectAssetID struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Creator checksumAddress   `codec:"c"`
+	Index   basics.AssetIndex `codec:"i"`
+}
+
+// inspectAssetParams is isomorphic to AssetParams but uses different types to
+// print public keys using algorand's address format in JSON.
+type inspectAssetParams struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Total         uint64          `codec:"t"`
+	DefaultFrozen bool            `codec:"df"`
+	UnitName      string          `codec:"un"`
+	AssetName     string          `codec:"an"`
+	URL           string          `codec:"au"`
+	Manager       checksumAddress `codec:"m"`
+	Reserve       checksumAddress `codec:"r"`
+	Freeze        checksumAddress `codec:"f"`
+	Clawback      checksumAddress `codec:"c"`
+}
+
 // checksumAddress is a checksummed address, for use with text encodings
 // like JSON.
 type checksumAddress basics.Address
@@ -127,17 +202,39 @@ func inspectTxn(stxn transactions.SignedTxn) (sti inspectSignedTxn, err error) {
 
 func stxnToInspect(stxn transactions.SignedTxn) inspectSignedTxn {
 	return inspectSignedTxn{
-		Txn:  txnToInspect(stxn.Txn),
-		Sig:  stxn.Sig,
-		Msig: msigToInspect(stxn.Msig),
+		Txn:      txnToInspect(stxn.Txn),
+		Sig:      stxn.Sig,
+		Msig:     msigToInspect(stxn.Msig),
+		Lsig:     lsigToInspect(stxn.Lsig),
+		AuthAddr: checksumAddress(stxn.AuthAddr),
 	}
 }
 
 func stxnFromInspect(sti inspectSignedTxn) transactions.SignedTxn {
 	return transactions.SignedTxn{
-		Txn:  txnFromInspect(sti.Txn),
-		Sig:  sti.Sig,
-		Msig: msigFromInspect(sti.Msig),
+		Txn:      txnFromInspect(sti.Txn),
+		Sig:      sti.Sig,
+		Msig:     msigFromInspect(sti.Msig),
+		Lsig:     lsigFromInspect(sti.Lsig),
+		AuthAddr: basics.Address(sti.AuthAddr),
+	}
+}
+
+func lsigToInspect(lsig transactions.LogicSig) inspectLogicSig {
+	return inspectLogicSig{
+		Logic: lsig.Logic,
+		Sig:   lsig.Sig,
+		Msig:  msigToInspect(lsig.Msig),
+		Args:  lsig.Args,
+	}
+}
+
+func lsigFromInspect(lsi inspectLogicSig) transactions.LogicSig {
+	return transactions.LogicSig{
+		Logic: lsi.Logic,
+		Sig:   lsi.Sig,
+		Msig:  msigFromInspect(lsi.Msig),
+		Args:  lsi.Args,
 	}
 }
 
@@ -184,6 +281,9 @@ func txnToInspect(txn transactions.Transaction) inspectTransaction {
 			Note:        txn.Note,
 			GenesisID:   txn.GenesisID,
 			GenesisHash: txn.GenesisHash,
+			RekeyTo:     checksumAddress(txn.RekeyTo),
+			Group:       txn.Group,
+			Lease:       txn.Lease,
 		},
 		KeyregTxnFields: txn.KeyregTxnFields,
 		inspectPaymentTxnFields: inspectPaymentTxnFields{
@@ -191,6 +291,46 @@ func txnToInspect(txn transactions.Transaction) inspectTransaction {
 			Amount:           txn.Amount,
 			CloseRemainderTo: checksumAddress(txn.CloseRemainderTo),
 		},
+		inspectAssetConfigTxnFields: inspectAssetConfigTxnFields{
+			ConfigAsset: assetIDToInspect(txn.ConfigAsset),
+			AssetParams: inspectAssetParams{
+				Total:         txn.AssetParams.Total,
+				DefaultFrozen: txn.AssetParams.DefaultFrozen,
+				UnitName:      txn.AssetParams.UnitName,
+				AssetName:     txn.AssetParams.AssetName,
+				URL:           txn.AssetParams.URL,
+				Manager:       checksumAddress(txn.AssetParams.Manager),
+				Reserve:       checksumAddress(txn.AssetParams.Reserve),
+				Freeze:        checksumAddress(txn.AssetParams.Freeze),
+				Clawback:      checksumAddress(txn.AssetParams.Clawback),
+			},
+		},
+		inspectAssetTransferTxnFields: inspectAssetTransferTxnFields{
+			XferAsset:     assetIDToInspect(txn.XferAsset),
+			AssetAmount:   txn.AssetAmount,
+			AssetSender:   checksumAddress(txn.AssetSender),
+			AssetReceiver: checksumAddress(txn.AssetReceiver),
+			AssetCloseTo:  checksumAddress(txn.AssetCloseTo),
+		},
+		inspectAssetFreezeTxnFields: inspectAssetFreezeTxnFields{
+			FreezeAccount: checksumAddress(txn.FreezeAccount),
+			FreezeAsset:   assetIDToInspect(txn.FreezeAsset),
+			AssetFrozen:   txn.AssetFrozen,
+		},
+	}
+}
+
+func assetIDToInspect(id basics.AssetID) inspectAssetID {
+	return inspectAssetID{
+		Creator: checksumAddress(id.Creator),
+		Index:   id.Index,
+	}
+}
+
+func assetIDFromInspect(idi inspectAssetID) basics.AssetID {
+	return basics.AssetID{
+		Creator: basics.Address(idi.Creator),
+		Index:   idi.Index,
 	}
 }
 
@@ -205,6 +345,9 @@ func txnFromInspect(txi inspectTransaction) transactions.Transaction {
 			Note:        txi.Note,
 			GenesisID:   txi.GenesisID,
 			GenesisHash: txi.GenesisHash,
+			RekeyTo:     basics.Address(txi.RekeyTo),
+			Group:       txi.Group,
+			Lease:       txi.Lease,
 		},
 		KeyregTxnFields: txi.KeyregTxnFields,
 		PaymentTxnFields: transactions.PaymentTxnFields{
@@ -212,5 +355,31 @@ func txnFromInspect(txi inspectTransaction) transactions.Transaction {
 			Amount:           txi.Amount,
 			CloseRemainderTo: basics.Address(txi.CloseRemainderTo),
 		},
+		AssetConfigTxnFields: transactions.AssetConfigTxnFields{
+			ConfigAsset: assetIDFromInspect(txi.ConfigAsset),
+			AssetParams: basics.AssetParams{
+				Total:         txi.AssetParams.Total,
+				DefaultFrozen: txi.AssetParams.DefaultFrozen,
+				UnitName:      txi.AssetParams.UnitName,
+				AssetName:     txi.AssetParams.AssetName,
+				URL:           txi.AssetParams.URL,
+				Manager:       basics.Address(txi.AssetParams.Manager),
+				Reserve:       basics.Address(txi.AssetParams.Reserve),
+				Freeze:        basics.Address(txi.AssetParams.Freeze),
+				Clawback:      basics.Address(txi.AssetParams.Clawback),
+			},
+		},
+		AssetTransferTxnFields: transactions.AssetTransferTxnFields{
+			XferAsset:     assetIDFromInspect(txi.XferAsset),
+			AssetAmount:   txi.AssetAmount,
+			AssetSender:   basics.Address(txi.AssetSender),
+			AssetReceiver: basics.Address(txi.AssetReceiver),
+			AssetCloseTo:  basics.Address(txi.AssetCloseTo),
+		},
+		AssetFreezeTxnFields: transactions.AssetFreezeTxnFields{
+			FreezeAccount: basics.Address(txi.FreezeAccount),
+			FreezeAsset:   assetIDFromInspect(txi.FreezeAsset),
+			AssetFrozen:   txi.AssetFrozen,
+		},
 	}
 }
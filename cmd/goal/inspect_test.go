@@ -58,6 +58,49 @@ func TestInspect(t *testing.T) {
 	_, err = inspectTxn(keyreg)
 	require.NoError(t, err)
 
+	var assetConfig transactions.SignedTxn
+	assetConfig.Txn.Type = protocol.AssetConfigTx
+	crypto.RandBytes(assetConfig.Txn.Sender[:])
+	crypto.RandBytes(assetConfig.Txn.ConfigAsset.Creator[:])
+	assetConfig.Txn.ConfigAsset.Index = basics.AssetIndex(crypto.RandUint64())
+	assetConfig.Txn.AssetParams.Total = crypto.RandUint64()
+	assetConfig.Txn.AssetParams.UnitName = "units"
+	crypto.RandBytes(assetConfig.Txn.AssetParams.Manager[:])
+	crypto.RandBytes(assetConfig.Txn.AssetParams.Clawback[:])
+	_, err = inspectTxn(assetConfig)
+	require.NoError(t, err)
+
+	var assetTransfer transactions.SignedTxn
+	assetTransfer.Txn.Type = protocol.AssetTransferTx
+	crypto.RandBytes(assetTransfer.Txn.Sender[:])
+	crypto.RandBytes(assetTransfer.Txn.XferAsset.Creator[:])
+	assetTransfer.Txn.XferAsset.Index = basics.AssetIndex(crypto.RandUint64())
+	assetTransfer.Txn.AssetAmount = crypto.RandUint64()
+	crypto.RandBytes(assetTransfer.Txn.AssetSender[:])
+	crypto.RandBytes(assetTransfer.Txn.AssetReceiver[:])
+	crypto.RandBytes(assetTransfer.Txn.AssetCloseTo[:])
+	_, err = inspectTxn(assetTransfer)
+	require.NoError(t, err)
+
+	var assetFreeze transactions.SignedTxn
+	assetFreeze.Txn.Type = protocol.AssetFreezeTx
+	crypto.RandBytes(assetFreeze.Txn.Sender[:])
+	crypto.RandBytes(assetFreeze.Txn.FreezeAccount[:])
+	crypto.RandBytes(assetFreeze.Txn.FreezeAsset.Creator[:])
+	assetFreeze.Txn.AssetFrozen = true
+	_, err = inspectTxn(assetFreeze)
+	require.NoError(t, err)
+
+	var logicSig transactions.SignedTxn
+	logicSig.Txn.Type = protocol.PaymentTx
+	crypto.RandBytes(logicSig.Txn.Sender[:])
+	crypto.RandBytes(logicSig.Txn.Receiver[:])
+	logicSig.Lsig.Logic = []byte{0x01, 0x20, 0x01, 0x01, 0x22}
+	crypto.RandBytes(logicSig.Lsig.Sig[:])
+	logicSig.Lsig.Args = [][]byte{[]byte("arg0"), []byte("arg1")}
+	_, err = inspectTxn(logicSig)
+	require.NoError(t, err)
+
 	var full transactions.SignedTxn
 	crypto.RandBytes(full.Sig[:])
 	full.Msig.Version = uint8(crypto.RandUint64())
@@ -84,6 +127,8 @@ func TestInspect(t *testing.T) {
 	full.Txn.Amount.Raw = crypto.RandUint64()
 	crypto.RandBytes(full.Txn.Receiver[:])
 	crypto.RandBytes(full.Txn.CloseRemainderTo[:])
+	crypto.RandBytes(full.Txn.Group[:])
+	crypto.RandBytes(full.Txn.Lease[:])
 	_, err = inspectTxn(full)
 	require.NoError(t, err)
 }
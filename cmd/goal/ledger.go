@@ -17,13 +17,32 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+)
+
+var (
+	rawBlock     bool
+	rawBlockFile string
+	headerJSON   bool
 )
 
 func init() {
 	ledgerCmd.AddCommand(supplyCmd)
+	ledgerCmd.AddCommand(blockCmd)
+	ledgerCmd.AddCommand(headerCmd)
+
+	blockCmd.Flags().BoolVar(&rawBlock, "raw", false, "Fetch the msgpack encoded block and certificate, rather than the decoded block")
+	blockCmd.Flags().StringVarP(&rawBlockFile, "out", "o", "", "File to write the raw block to, rather than stdout (with --raw)")
+	headerCmd.Flags().BoolVar(&headerJSON, "json", false, "Print the block header as JSON")
 }
 
 var ledgerCmd = &cobra.Command{
@@ -49,6 +68,118 @@ var supplyCmd = &cobra.Command{
 			reportErrorf(errorRequestFail, err)
 		}
 
-		fmt.Printf("Round: %v microAlgos\nTotal Money: %v microAlgos\nOnline Money: %v microAlgos\n", response.Round, response.TotalMoney, response.OnlineMoney)
+		reportResult(response, "", func() {
+			fmt.Printf("Round: %v microAlgos\nTotal Money: %v microAlgos\nOnline Money: %v microAlgos\n", response.Round, response.TotalMoney, response.OnlineMoney)
+		})
+	},
+}
+
+func parseRoundArg(arg string) uint64 {
+	round, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		reportErrorf(errorParseRound, arg, err)
+	}
+	return round
+}
+
+var blockCmd = &cobra.Command{
+	Use:   "block [round]",
+	Short: "Show the block of a round",
+	Long:  "Show the block of the given round, including its transactions, as JSON. With --raw, the msgpack encoded block and its certificate are written instead, as they are served to catching up nodes.",
+	Example: "goal ledger block 1000\n" +
+		"goal ledger block 1000 --raw -o 1000.block",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		round := parseRoundArg(args[0])
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+
+		if rawBlock {
+			raw, err := client.RawBlock(round)
+			if err != nil {
+				reportErrorf(errorRequestFail, err)
+			}
+			if rawBlockFile == "" {
+				os.Stdout.Write(raw)
+				return
+			}
+			if err = ioutil.WriteFile(rawBlockFile, raw, 0644); err != nil {
+				reportErrorf(errorWriteRawBlock, rawBlockFile, err)
+			}
+			return
+		}
+
+		block, err := client.Block(round)
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		data, err := json.MarshalIndent(block, "", "  ")
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		fmt.Println(string(data))
+	},
+}
+
+// blockHeader is the JSON representation of a block without its transactions.
+type blockHeader struct {
+	models.Block
+	// Txns hides the transactions of the embedded block
+	Txns *struct{} `json:"txns,omitempty"`
+	// TransactionCount is the number of transactions in the block
+	TransactionCount int `json:"txnCount"`
+}
+
+var headerCmd = &cobra.Command{
+	Use:   "header [round]",
+	Short: "Show the header of the block of a round",
+	Long:  "Show the header of the block of the given round: its hashes, proposer, rewards and consensus upgrade state.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		round := parseRoundArg(args[0])
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+		block, err := client.Block(round)
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+
+		header := blockHeader{Block: block, TransactionCount: len(block.Txns.Transactions)}
+		if headerJSON && !report.structured() {
+			data, err := json.MarshalIndent(header, "", "  ")
+			if err != nil {
+				reportErrorf(errorRequestFail, err)
+			}
+			fmt.Println(string(data))
+			return
+		}
+		reportResult(header, header.Hash, func() {
+			printBlockHeader(header)
+		})
 	},
 }
+
+func printBlockHeader(header blockHeader) {
+	fmt.Printf("Round: %d\n", header.Round)
+	fmt.Printf("Hash: %s\n", header.Hash)
+	fmt.Printf("Previous block hash: %s\n", header.PreviousBlockHash)
+	fmt.Printf("Seed: %s\n", header.Seed)
+	fmt.Printf("Proposer: %s\n", header.Proposer)
+	fmt.Printf("Timestamp: %s\n", time.Unix(header.Timestamp, 0).UTC().Format(time.RFC3339))
+	fmt.Printf("Transactions: %d\n", header.TransactionCount)
+	fmt.Printf("Transactions root: %s\n", header.TransactionsRoot)
+	fmt.Printf("Rewards level: %d\n", header.RewardsLevel)
+	fmt.Printf("Rewards rate: %d\n", header.RewardsRate)
+	fmt.Printf("Rewards residue: %d\n", header.RewardsResidue)
+	fmt.Printf("Current protocol: %s\n", header.CurrentProtocol)
+	if header.NextProtocol != "" {
+		fmt.Printf("Next protocol: %s\n", header.NextProtocol)
+		fmt.Printf("Next protocol approvals: %d\n", header.NextProtocolApprovals)
+		fmt.Printf("Next protocol vote before: %d\n", header.NextProtocolVoteBefore)
+		fmt.Printf("Next protocol switch on: %d\n", header.NextProtocolSwitchOn)
+	}
+	if header.UpgradePropose != "" {
+		fmt.Printf("Upgrade proposed: %s\n", header.UpgradePropose)
+	}
+	fmt.Printf("Upgrade approved: %v\n", header.UpgradeApprove != nil && *header.UpgradeApprove)
+}
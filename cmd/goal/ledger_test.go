@@ -0,0 +1,41 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+)
+
+func TestBlockHeaderJSON(t *testing.T) {
+	block := models.Block{Round: 7, Hash: "HASH"}
+	block.Txns.Transactions = []models.Transaction{{TxID: "A"}, {TxID: "B"}}
+
+	data, err := json.Marshal(blockHeader{Block: block, TransactionCount: len(block.Txns.Transactions)})
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.NotContains(t, fields, "txns")
+	require.Equal(t, float64(2), fields["txnCount"])
+	require.Equal(t, float64(7), fields["round"])
+	require.Equal(t, "HASH", fields["hash"])
+}
@@ -20,24 +20,50 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
 	"github.com/algorand/go-algorand/logging"
 )
 
 var (
 	nodeName string
+
+	telemetryURI           string
+	telemetryIndex         string
+	telemetryUserName      string
+	telemetryPassword      string
+	telemetryAPIKey        string
+	telemetryTLSSkipVerify bool
+	telemetryTLSCAFile     string
+
+	logLevelSave bool
 )
 
 func init() {
 	loggingCmd.AddCommand(enableCmd)
 	loggingCmd.AddCommand(disableCmd)
 	loggingCmd.AddCommand(loggingSendCmd)
+	loggingCmd.AddCommand(loggingSetCmd)
+	loggingCmd.AddCommand(loggingLevelCmd)
+	loggingCmd.AddCommand(crashReportsCmd)
+	crashReportsCmd.AddCommand(crashReportsListCmd)
+	crashReportsCmd.AddCommand(crashReportsSendCmd)
 
 	// Enable Logging : node name
 	enableCmd.Flags().StringVarP(&nodeName, "name", "n", "", "Friendly-name to use for node")
+
+	loggingSetCmd.Flags().StringVar(&telemetryURI, "uri", "", "Telemetry endpoint URI, such as https://elasticsearch.example.com:9200; empty restores the default endpoint")
+	loggingSetCmd.Flags().StringVar(&telemetryIndex, "index", "", "Elasticsearch index to send the telemetry to; empty uses the network name")
+	loggingSetCmd.Flags().StringVar(&telemetryUserName, "user", "", "User name to authenticate with")
+	loggingSetCmd.Flags().StringVar(&telemetryPassword, "password", "", "Password to authenticate with")
+	loggingSetCmd.Flags().StringVar(&telemetryAPIKey, "api-key", "", "Elasticsearch API key to authenticate with, instead of the user name and password")
+	loggingSetCmd.Flags().BoolVar(&telemetryTLSSkipVerify, "tls-skip-verify", false, "Don't verify the telemetry endpoint certificate")
+	loggingLevelCmd.Flags().BoolVar(&logLevelSave, "save", false, "Also save the level into logging.config, so that it is used after the node restarts")
+	loggingSetCmd.Flags().StringVar(&telemetryTLSCAFile, "tls-ca-file", "", "PEM file of the certificate authorities to verify the telemetry endpoint certificate with")
 }
 
 var loggingCmd = &cobra.Command{
@@ -98,6 +124,156 @@ var disableCmd = &cobra.Command{
 	},
 }
 
+var loggingSetCmd = &cobra.Command{
+	Use:     "set",
+	Short:   "Configure the Algorand remote logging endpoint",
+	Long:    `Configure the endpoint that remote logging is sent to. Only the settings whose flags are specified are changed; the nodes pick up the changes once restarted.`,
+	Example: "goal logging set --uri https://elasticsearch.example.com:9200 --index mynodes --api-key <key> --tls-ca-file ca.pem",
+	Args:    validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		cfg, err := logging.EnsureTelemetryConfig(nil, "")
+		if err != nil {
+			reportErrorf("%v", err)
+		}
+		flags := cmd.Flags()
+		if flags.Changed("uri") {
+			cfg.URI = telemetryURI
+		}
+		if flags.Changed("index") {
+			cfg.Index = telemetryIndex
+		}
+		if flags.Changed("user") {
+			cfg.UserName = telemetryUserName
+		}
+		if flags.Changed("password") {
+			cfg.Password = telemetryPassword
+		}
+		if flags.Changed("api-key") {
+			cfg.APIKey = telemetryAPIKey
+		}
+		if flags.Changed("tls-skip-verify") {
+			cfg.TLSSkipVerify = telemetryTLSSkipVerify
+		}
+		if flags.Changed("tls-ca-file") {
+			if telemetryTLSCAFile != "" {
+				if telemetryTLSCAFile, err = filepath.Abs(telemetryTLSCAFile); err != nil {
+					reportErrorf("%v", err)
+				}
+			}
+			cfg.TLSCAFile = telemetryTLSCAFile
+		}
+		if err = cfg.Save(cfg.FilePath); err != nil {
+			reportErrorf("Unable to save logging config %s: %v", cfg.FilePath, err)
+		}
+		fmt.Printf("Logging endpoint updated: URI = %s, Index = %s\n", cfg.URI, cfg.Index)
+	},
+}
+
+var loggingLevelCmd = &cobra.Command{
+	Use:   "level [subsystem level]",
+	Short: "Show or set the log level of the node subsystems",
+	Long: "Show the log level of each of the node subsystems (" + strings.Join(logging.Subsystems, ", ") + "), or set the level of one of them, so that, for instance, " +
+		"the debug logs of a single subsystem could be enabled. The level is one of panic, fatal, error, warn, info and debug, or default to log at the node log level again.",
+	Example: "goal logging level\ngoal logging level agreement debug",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 && len(args) != 2 {
+			return fmt.Errorf("expected either no arguments, or a subsystem and a level")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		onDataDirs(func(dataDir string) {
+			client := ensureAlgodClient(dataDir)
+			var levels models.LogLevels
+			var err error
+			if len(args) == 0 {
+				levels, err = client.LogLevels()
+			} else {
+				levels, err = client.SetLogLevel(args[0], args[1])
+			}
+			if err != nil {
+				reportErrorf(errorNodeStatus, err)
+			}
+			for _, subsystem := range logging.Subsystems {
+				fmt.Printf("%-10s %s\n", subsystem, levels.Levels[subsystem])
+			}
+		})
+
+		if len(args) == 2 && logLevelSave {
+			cfg, err := logging.EnsureTelemetryConfig(nil, "")
+			if err != nil {
+				reportErrorf("%v", err)
+			}
+			if args[1] == "default" {
+				delete(cfg.SubsystemLogLevels, args[0])
+			} else {
+				if cfg.SubsystemLogLevels == nil {
+					cfg.SubsystemLogLevels = make(map[string]string)
+				}
+				cfg.SubsystemLogLevels[args[0]] = args[1]
+			}
+			if err = cfg.Save(cfg.FilePath); err != nil {
+				reportErrorf("Unable to save logging config %s: %v", cfg.FilePath, err)
+			}
+		}
+	},
+}
+
+var crashReportsCmd = &cobra.Command{
+	Use:   "crashreports",
+	Short: "List and send the node crash reports",
+	Long:  "List and send the reports the node stored when it crashed. Crash reports are only stored when EnableCrashReports is set in the node config.json",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		//Fall back
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+var crashReportsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the node crash reports",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		onDataDirs(func(dataDir string) {
+			reports, err := logging.ListCrashReports(dataDir)
+			if err != nil {
+				reportErrorf("Unable to list crash reports: %v", err)
+			}
+			if len(reports) == 0 {
+				fmt.Println("No crash reports")
+			}
+			for _, report := range reports {
+				status := "pending"
+				if report.Sent {
+					status = "sent"
+				}
+				fmt.Printf("%s  %-7s  %s  %s\n", report.Report.Time.Local().Format(time.RFC3339), status, filepath.Base(report.Path), report.Report.Message)
+			}
+		})
+	},
+}
+
+var crashReportsSendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Upload the node crash reports that weren't sent yet",
+	Long:  "Upload the node crash reports that weren't sent yet to Algorand for analysis, regardless of whether telemetry is enabled.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		cfg, err := logging.EnsureTelemetryConfig(nil, "")
+		if err != nil {
+			reportErrorf("%v", err)
+		}
+		onDataDirs(func(dataDir string) {
+			sent, err := logging.SendCrashReports(dataDir, cfg.GUID)
+			if err != nil {
+				reportErrorf("Unable to send crash reports: %v", err)
+			}
+			fmt.Printf("Sent %d crash reports\n", sent)
+		})
+	},
+}
+
 var loggingSendCmd = &cobra.Command{
 	Use:   "send",
 	Short: "Upload logs and debugging information for analysis",
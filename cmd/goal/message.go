@@ -0,0 +1,103 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/libgoal"
+)
+
+var (
+	messageAddr      string
+	messageText      string
+	messageSignature string
+)
+
+func init() {
+	accountCmd.AddCommand(signMessageCmd)
+	accountCmd.AddCommand(verifyMessageCmd)
+
+	signMessageCmd.Flags().StringVarP(&messageAddr, "address", "a", "", "Account whose key signs the message")
+	signMessageCmd.Flags().StringVarP(&messageText, "message", "m", "", "Message to sign")
+	signMessageCmd.MarkFlagRequired("address")
+	signMessageCmd.MarkFlagRequired("message")
+
+	verifyMessageCmd.Flags().StringVarP(&messageAddr, "address", "a", "", "Account that signed the message")
+	verifyMessageCmd.Flags().StringVarP(&messageText, "message", "m", "", "Message that was signed")
+	verifyMessageCmd.Flags().StringVarP(&messageSignature, "signature", "s", "", "Base64 signature made by goal account sign-message")
+	verifyMessageCmd.MarkFlagRequired("address")
+	verifyMessageCmd.MarkFlagRequired("message")
+	verifyMessageCmd.MarkFlagRequired("signature")
+}
+
+// signedMessage is what goal account sign-message reports
+type signedMessage struct {
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+var signMessageCmd = &cobra.Command{
+	Use:     "sign-message -a ADDR -m MESSAGE",
+	Short:   "Sign a message with the key of an account, to prove control of it",
+	Long:    "Sign a message with the key of an account in the wallet and print the base64 signature. The message is signed in its own domain, so that the signature can't pass for that of a transaction. Anyone can check the signature with goal account verify-message, without a wallet or a node, which lets a service check that whoever it deals with controls an address.",
+	Example: "goal account sign-message -a ADDR -m \"Withdrawals to this address for customer 1234\"",
+	Args:    validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureKmdClient(dataDir)
+		wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
+
+		sig, err := client.SignMessage(wh, pw, messageAddr, messageText)
+		if err != nil {
+			reportErrorf(errorSigningMessage, err)
+		}
+		signed := signedMessage{
+			Address:   messageAddr,
+			Message:   messageText,
+			Signature: base64.StdEncoding.EncodeToString(sig[:]),
+		}
+		reportResult(signed, "", func() {
+			fmt.Println(signed.Signature)
+		})
+	},
+}
+
+var verifyMessageCmd = &cobra.Command{
+	Use:   "verify-message -a ADDR -m MESSAGE -s SIGNATURE",
+	Short: "Verify a message signed with goal account sign-message",
+	Long:  "Check that the signature of the message was made by goal account sign-message with the key of the account. This needs neither a wallet nor a node. The key is that of the address itself, whether or not the account was rekeyed.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		raw, err := base64.StdEncoding.DecodeString(messageSignature)
+		if err != nil || len(raw) != len(crypto.Signature{}) {
+			reportErrorf(errorMessageSignature, messageSignature)
+		}
+		var sig crypto.Signature
+		copy(sig[:], raw)
+
+		if err := libgoal.VerifyMessage(messageAddr, messageText, sig); err != nil {
+			reportErrorf(errorMessageInvalid, err)
+		}
+		reportInfof(infoMessageVerified, messageAddr)
+	},
+}
@@ -18,31 +18,94 @@ package main
 
 const (
 	// General
-	errorNoDataDirectory     = "Data directory not specified.  Please use -d or set $ALGORAND_DATA in your environment. Exiting."
+	errorNoDataDirectory     = "Data directory not specified.  Please use -d, set $ALGORAND_DATA in your environment or select a network with 'goal network use'. Exiting."
 	errorOneDataDirSupported = "One one data directory can be specified for this command."
 	errorRequestFail         = "Error processing command: %s"
 	errorGenesisIDFail       = "Error determining kmd folder (%s). Ensure the node is running in %s."
 	errorDirectoryNotExist   = "Specified directory '%s' does not exist."
+	errorDataDir             = "[Data Directory: %s] Error: %v"
+	errorDataDirsFailed      = "The command failed on %d of %d data directories: %s"
+	errorOutputFormat        = "Unsupported output format '%s', expected one of %s"
+	errorEncodeOutput        = "Couldn't encode the command output: %v"
 
 	// Account
 	infoNoAccounts                 = "Did not find any account. Please import or create a new one."
+	infoNoMatchingAccounts         = "No account matches the filter and offset."
+	errorListPaging                = "--offset and --limit cannot be negative"
 	infoRenamedAccount             = "Renamed account '%s' to '%s'"
 	infoImportedKey                = "Imported %s"
 	infoExportedKey                = "Exported key for account %s: \"%s\""
+	infoExportedKeyfile            = "Exported key for account %s to %s"
 	infoImportedNKeys              = "Imported %d key%s"
 	infoCreatedNewAccount          = "Created new account with address %s"
 	errorNameAlreadyTaken          = "The account name '%s' is already taken, please choose another."
+	errorAccountCount              = "Cannot create %d accounts, the count must be at least 1"
+	errorDefaultWithCount          = "Only a single new account can be set as the default one"
+	errorIndexWithoutDerived       = "--index can only be used together with --derived"
+	errorDerivedNeedsIndex         = "Specify the index of the key to derive with --index"
 	errorNameDoesntExist           = "An account named '%s' does not exist."
 	infoSetAccountToDefault        = "Set account '%s' to be the default account"
 	errorSigningTX                 = "Couldn't sign tx with kmd: %s"
+	errorRemoteSigning             = "Couldn't sign tx with the signing service: %s"
+	errorRemoteSigner              = "Couldn't set up the signing service: %s"
 	errorOnlineTX                  = "Couldn't sign tx: %s (for multisig accounts, write tx to file and sign manually)"
 	errorConstructingTX            = "Couldn't construct tx: %s"
 	errorBroadcastingTX            = "Couldn't broadcast tx with algod: %s"
 	warnMultisigDuplicatesDetected = "Warning: one or more duplicate addresses detected in multisig account creation. This will effectively give the duplicated address(es) extra signature weight. Continuing multisig account creation."
 	errLastRoundInvalid            = "roundLastValid needs to be well after the current round (%d)"
 	errExistingPartKey             = "Account already has a participation key valid at least until roundLastValid (%d) - current is %d"
+	errorParseAddr                 = "Invalid address %s: %v"
+	errorPartKeyDeleteSelection    = "Exactly one of --file and --address must be given"
+	errorPartKeyNotFound           = "No participation key database named %s"
+	errorNoPartKeys                = "Account %s has no participation keys"
+	warnPartKeyNotExpired          = "Keeping %s, which is valid until round %d (use --force to delete it anyway)"
+	infoPartKeyDeleted             = "Deleted participation key database %s"
+	errorInstallPartKey            = "Couldn't install the participation key: %s"
+	infoPartKeyInstalled           = "Installed participation key for %s (Valid %d - %d) as %s"
+	errorRenewValidRounds          = "--validrounds must be more than --threshold-rounds (%d)"
+	infoAutoRenewStarted           = "Renewing participation keys expiring within %d rounds, from round %d"
+	infoPartKeyRenewed             = "Renewed participation key for %s (Valid %d - %d), replacing the one valid until %d"
+	warnPartKeyRenewalFailed       = "Couldn't renew the participation key for %s: %v"
+	warnAutoRenewNode              = "Couldn't get the next round from the node, retrying: %v"
+	warnAutoRenewTelemetry         = "Couldn't enable telemetry: %v"
 	errorSeedConversion            = "Got private key for account %s, but was unable to convert to seed: %s"
 	errorMnemonicConversion        = "Got seed for account %s, but was unable to convert to mnemonic: %s"
+	errorTxFileMultipleDataDirs    = "A transaction file can't be written for more than one data directory."
+	errorOutDirMultipleDataDirs    = "An output directory can't be used with more than one data directory."
+	errorAccountListLoad           = "Cannot load the account list %s: %v"
+	errorAccountListVersion        = "the file has version %d, but this goal only supports up to version %d"
+	errorAccountNameAmbiguous      = "Account name '%s' is ambiguous: it names %s in one data directory and %s in another"
+	infoAccountListDroppedInvalid  = "Dropped account '%s', since %s is not a valid address"
+	infoAccountListRenamed         = "Renamed account %s from '%s' to '%s'"
+	infoAccountListClearedDefault  = "Cleared the default account %s, since it is not in the list"
+	infoAccountListRemoved         = "Removed account %s, which is in no wallet"
+	infoAccountListAdded           = "Added account %s"
+	infoAccountListRepaired        = "The account list is consistent with the wallets"
+	errorBalanceNoAccount          = "Specify the accounts with -a, or --all for every account of the wallet"
+	infoKeyGenProgress             = "Generated %d of %d key batches (%.0f%%), about %v left"
+	errorBalanceWatchOne           = "--watch watches the balance of a single account, given with -a"
+	errorBalanceThresholdNoWatch   = "--above and --below only apply with --watch"
+	infoBalanceUpdate              = "Round %d: %d microAlgos"
+	errorAccountBalance            = "Couldn't retrieve the balance of %s: %v"
+	errorBalancesIncomplete        = "Couldn't retrieve the balance of %d of the %d accounts"
+	infoNoTransactionIndex         = "The node doesn't list the recent transactions, walking the latest rounds instead: %v"
+	errorTransactionsRounds        = "The first round %d is after the last round %d"
+	infoNoTransactions             = "The account has no transactions to list"
+	infoNonpartConfirm             = "Marking %s as nonparticipating is irreversible: it goes offline, stops earning rewards and can never register participation keys again. Type 'yes' to continue: "
+	errorNonpartNotConfirmed       = "The account was not marked as nonparticipating"
+	errorOnlineNoAccount           = "Specify the accounts with -a or --batch"
+	errorOnlineStatusIncomplete    = "Couldn't change the status of %d of the %d accounts"
+	infoOnlineStatusAccount        = "[Account: %s]"
+	infoOnlineStatusPending        = "%d status change transactions still pending as of round %d"
+	warnOnlineWithoutPartkey       = "Account %s is online, but none of its local participation keys is the registered one"
+	errorPartkeyVerify             = "Participation key verification found %d problem(s)"
+	errorVanityPattern             = "Invalid vanity pattern: %v"
+	errorVanityThreads             = "Cannot search with %d threads, the count must be at least 1"
+	errorVanityNameWithoutImport   = "An account name and --default only apply with --import"
+	infoVanitySearch               = "Searching with %d threads for an address with %s"
+	infoVanityProgress             = "Tried %d keys, %.0f keys/sec"
+	infoVanityFound                = "Found %s after %d keys, %.0f keys/sec"
+	infoVanityMnemonic             = "Private key mnemonic: %s"
 
 	// KMD
 	infoKMDStopped        = "Stopped kmd"
@@ -53,41 +116,128 @@ const (
 	errorKMDFailedToStop  = "Failed to stop kmd: %s"
 
 	// Node
-	infoNodeStart                    = "Algorand node successfully started!"
-	infoNodeAlreadyStarted           = "Algorand node was already started!"
-	infoTryingToStopNode             = "Trying to stop the node..."
-	infoNodeSuccessfullyStopped      = "The node was successfully stopped."
-	infoNodeStatus                   = "Last committed block: %d\nTime since last block: %s\nSync Time: %s\nLast consensus protocol: %s\nNext consensus protocol: %s\nRound for next consensus protocol: %d\nNext consensus protocol supported: %v"
-	errorNodeNotDetected             = "Algorand node does not appear to be running: %s"
-	errorNodeStatus                  = "Cannot contact Algorand node: %s."
-	errorNodeFailedToStart           = "Algorand node failed to start: %s"
-	errorNodeRunning                 = "Node must be stopped before writing APIToken"
-	errorNodeFailGenToken            = "Cannot generate API token: %s"
-	errorKill                        = "Cannot kill node: %s"
-	errorCloningNode                 = "Error cloning the node: %s"
-	infoNodeCloned                   = "Node cloned successfully to: %s"
-	infoNodeWroteToken               = "Successfully wrote new API token: %s"
-	infoNodePendingTxnsDescription   = "Pending Transactions (Truncated max=%d, Total in pool=%d): "
-	infoNodeNoPendingTxnsDescription = "None"
-	infoDataDir                      = "[Data Directory: %s]"
-	errLoadingConfig                 = "Error loading Config file from '%s': %v"
+	infoNodeStart                        = "Algorand node successfully started!"
+	infoNodeAlreadyStarted               = "Algorand node was already started!"
+	infoTryingToStopNode                 = "Trying to stop the node..."
+	infoNodeSuccessfullyStopped          = "The node was successfully stopped."
+	infoNodeStatus                       = "Last committed block: %d\nTime since last block: %s\nSync Time: %s\nLast consensus protocol: %s\nNext consensus protocol: %s\nRound for next consensus protocol: %d\nNext consensus protocol supported: %v"
+	infoNodeCatchupProgress              = "Catchup progress: %d blocks, %.1f blocks/sec"
+	infoNodeCatchupSource                = "Catchup source: %s (%d blocks)"
+	errorNodeNotDetected                 = "Algorand node does not appear to be running: %s"
+	errorNodeStatus                      = "Cannot contact Algorand node: %s."
+	errorNodeFailedToStart               = "Algorand node failed to start: %s"
+	errorNodeRunning                     = "Node must be stopped before writing APIToken"
+	errorNodeFailGenToken                = "Cannot generate API token: %s"
+	errorReadingAuditLog                 = "Cannot read the audit log: %s"
+	errorKill                            = "Cannot kill node: %s"
+	errorCloningNode                     = "Error cloning the node: %s"
+	infoNodeCloned                       = "Node cloned successfully to: %s"
+	infoNodeWroteToken                   = "Successfully wrote new API token: %s"
+	infoNodePendingTxnsDescription       = "Pending Transactions (Truncated max=%d, Total in pool=%d): "
+	infoNodeNoPendingTxnsDescription     = "None"
+	infoNodeSenderPendingTxnsDescription = "Pending Transactions of %s (Truncated max=%d, Sender total=%d, Sender limit=%d, Total in pool=%d): "
+	infoDataDir                          = "[Data Directory: %s]"
+	errLoadingConfig                     = "Error loading Config file from '%s': %v"
+	infoConfigNotFound                   = "No config file at %s, the node runs with the default settings"
+	infoConfigValid                      = "%s is valid (config version %d)"
+	infoConfigNeedsMigration             = "%s uses config version %d and will be migrated to version %d when the node starts"
+	errorConfigInvalid                   = "%s has %d invalid setting(s)"
+	infoConfigMatchesProfile             = "The configuration matches the %s profile"
+	infoConfigDeviatesFromProfile        = "%d setting(s) deviate from the %s profile (%s):"
+	infoNodeReloadConfig                 = "Asked the node to reload %s; the node log lists the applied settings and those that need a restart"
+	errorNodeReloadConfig                = "Cannot signal the node to reload its config: %s"
+	infoServiceInstalled                 = "Installed the %s service %s at %s"
+	infoServiceStarted                   = "Enabled and started %s; it restarts with the machine"
+	infoServiceUninstalled               = "Uninstalled the %s service %s"
+	infoServiceStatus                    = "Service manager: %s\nService: %s\nFile: %s\nInstalled: %v\nEnabled: %v\nActive: %v"
+	errorServiceUnsupported              = "Node services are not supported on %s, only systemd on linux and launchd on darwin are"
+	errorServiceExists                   = "%s already exists, use --force to overwrite it"
+	errorServiceNotInstalled             = "The service %s is not installed, %s doesn't exist"
+	errorServiceNodeRunning              = "The node is already running outside of the service; stop it with goal node stop first, or install with --no-start"
+	errorServiceRestart                  = "Invalid restart policy '%s', use always, on-failure or never"
+	errorServiceWrite                    = "Cannot write %s: %v"
+	errorServiceCommand                  = "'%s' failed: %v %s"
 
 	// Clerk
-	infoTxIssued    = "Sent %d MicroAlgos from account %s to address %s, transaction ID: %s. Fee set to %d"
-	infoTxCommitted = "Transaction %s committed in round %d"
-	infoTxPending   = "Transaction %s still pending as of round %d"
-	malformedNote   = "Cannot base64-decode note %s: %s"
-	fileReadError   = "Cannot read file %s: %s"
-	fileWriteError  = "Cannot write file %s: %s"
-	txDecodeError   = "Cannot decode transactions from %s: %s"
-	txDupError      = "Duplicate transaction %s in %s"
-	txLengthError   = "Transaction list length mismatch"
-	txMergeMismatch = "Cannot merge transactions: transaction IDs differ"
-	txMergeError    = "Cannot merge signatures: %v"
-	txNoFilesError  = "No input filenames specified"
-	soFlagError     = "-s is not meaningful without -o"
-	infoRawTxIssued = "Raw transaction ID %s issued"
-	txPoolError     = "Transaction %s kicked out of local node pool: %s"
+	infoTxIssued         = "Sent %d MicroAlgos from account %s to address %s, transaction ID: %s. Fee set to %d"
+	infoTxCommitted      = "Transaction %s committed in round %d"
+	errorTxWaitTimeout   = "Transaction %s not committed after %d rounds"
+	infoRekeyIssued      = "Rekeying account %s to %s, transaction ID: %s"
+	malformedNote        = "Cannot base64-decode note %s: %s"
+	malformedLease       = "Cannot base64-decode lease %s: %s"
+	malformedLeaseLength = "Lease %s is %d bytes long instead of %d"
+	fileReadError        = "Cannot read file %s: %s"
+	fileWriteError       = "Cannot write file %s: %s"
+	txDecodeError        = "Cannot decode transactions from %s: %s"
+	txDupError           = "Duplicate transaction %s in %s"
+	txLengthError        = "Transaction list length mismatch"
+	txMergeMismatch      = "Cannot merge transactions: transaction IDs differ"
+	txMergeError         = "Cannot merge signatures: %v"
+	txNoFilesError       = "No input filenames specified"
+	soFlagError          = "-s is not meaningful without -o"
+	qrFlagError          = "--qr and --qr-png are not meaningful without -o"
+	infoRawTxIssued      = "Raw transaction ID %s issued"
+	txPoolError          = "Transaction %s kicked out of local node pool: %s"
+	infoTxConstructed    = "Wrote unsigned transaction %s to %s"
+	infoParamsWritten    = "Wrote the transaction params as of round %d to %s"
+	errorParamsDecode    = "Cannot decode transaction params from %s: %s"
+	infoMnemonicPrompt   = "Please type the mnemonic of the signing key, and hit return: "
+	errorMnemonicSender  = "The mnemonic is the key of %s, not of %s, the sender of transaction %s"
+
+	errorMnemonicPromptFlags = "--mnemonic-prompt cannot be used with --program or --signer"
+
+	firstValidOffsetFlagError = "--firstvalid-offset is not meaningful without -o"
+	errorFirstValidFlags      = "--firstvalid and --firstvalid-offset cannot be used together"
+	errorValidRounds          = "invalid validity window from round %d to round %d, it can be at most %d rounds long"
+
+	infoSchedulerStarted  = "Broadcasting the transaction files of %s once they are valid, as of round %d"
+	infoSchedulerWaiting  = "%s waits for round %d"
+	infoSchedulerSent     = "Broadcast %s, transaction ID %s"
+	warnSchedulerFailed   = "Moved %s to %s: %v"
+	warnSchedulerNode     = "Couldn't get the next round from the node, retrying: %v"
+	errorSchedulerDir     = "Cannot use the directory %s: %v"
+	errorSchedulerMove    = "Cannot move %s to %s: %v"
+	errorSchedulerExpired = "its last valid round %d has passed"
+
+	errorTemplate          = "Couldn't fill in the %s template: %v"
+	errorTemplateHashImage = "Cannot base64-decode hash image %s: %v"
+
+	infoPoolEmpty   = "No matching transaction pending, out of %d in the transaction pool"
+	infoPoolListed  = "%d matching transactions, out of %d in the transaction pool, in the order they are considered for the next block:"
+	infoPoolDropped = "Dropped transaction %s from the transaction pool"
+	errorPoolDrop   = "Couldn't drop transaction %s: %v"
+
+	infoTxGrouped        = "Grouped %d transactions into group %s, written to %s"
+	infoTxSplit          = "Wrote transaction %s to %s"
+	infoRawTxGroupIssued = "Raw transaction group of %d transactions issued, first transaction ID %s"
+	errorGroupEmpty      = "No transaction to group"
+	errorGroupSigned     = "Transaction #%d (%s) is already signed; group transactions before signing them"
+
+	errorAssemble           = "Cannot assemble %s: %s"
+	errorDisassemble        = "Cannot disassemble %s: %s"
+	errorCompileOutfileArgs = "-o can only be used with a single input file"
+
+	errorProgramArg          = "Invalid base64 program argument %s: %s"
+	errorProgramRemoteSigner = "--program cannot be used with --signer"
+	errorSigningProgram      = "Couldn't sign program with kmd for %s: %s"
+	errorDryrunProto         = "Unknown consensus protocol version %s"
+	errorDryrunCost          = "program cost %d is over the limit of %d"
+
+	infoAssetTxIssued = "Issued %s transaction %s. Fee set to %d"
+	infoAssetCreated  = "Created asset with asset index %d"
+
+	errorNotMultisigTxn         = "Transaction %s has no multisig signature"
+	errorInvalidMultisigTxn     = "Transaction %s has an invalid multisig signature: %v"
+	errorMultisigSignerMismatch = "Transaction %s carries the multisig of %s, but has to be signed by %s"
+	infoMultisigMerged          = "Merged %d transactions into %s"
+	errorMultisigMember         = "'%s' is neither an address nor the name of an account"
+	infoMultisigFileWritten     = "Wrote the multisig account metadata to %s"
+	errorMultisigFileAddress    = "the metadata is for address %s, but makes up address %s"
+	errorMultisigInfoSources    = "Give either --msig-file or --txfile, not both"
+	errorMultisigInfoNoAddr     = "Give the multisig account with --addr, or read it from --msig-file or --txfile"
+	errorMultisigInfoAddress    = "%s holds multisig account %s, not the one given with --addr"
+	errorMultisigNotInTxns      = "No transaction of the file is signed by multisig account %s"
+	errorNoMultisigInTxns       = "No transaction of the file has a multisig signature"
 
 	infoAutoFeeSet = "Automatically set fee to %d MicroAlgos"
 
@@ -103,11 +253,40 @@ const (
 	infoNetworkStarted       = "Network Started under %s"
 	infoNetworkStopped       = "Network Stopped under %s"
 	infoNetworkDeleted       = "Network Deleted under %s"
+	infoNetworkSnapshot      = "Network saved to %s (rounds %s)"
+	infoNetworkRestored      = "Network restored under %s (rounds %s)"
+	errorSnapshotNetwork     = "Error taking a snapshot of the network: %s"
+	errorRestoreNetwork      = "Error restoring the network: %s"
+
+	infoNetworkRegistered            = "Registered data directory '%s' (%s) for network %s"
+	infoNetworkUnregistered          = "Unregistered network %s"
+	infoNetworkDataDirUnregistered   = "Unregistered data directory '%s' of network %s"
+	infoNetworkInUse                 = "Using data directory '%s' (%s) of network %s"
+	infoNoRegisteredNetworks         = "No network is registered. Register one with 'goal network register'."
+	infoUsingNetworkDataDir          = "Using data directory %s of network %s"
+	errorNetworkNotRegistered        = "Network %s is not registered"
+	errorNetworkDataDirNotRegistered = "Data directory '%s' is not registered for network %s"
+	errorLoadingNetworkRegistry      = "Cannot load the network registry: %v"
+	errorSavingNetworkRegistry       = "Cannot save the network registry: %v"
+
+	// Profile
+	infoProfileSaved            = "Saved profile %s for data directory %s"
+	infoProfileDeleted          = "Deleted profile %s"
+	infoProfileInUse            = "Using profile %s"
+	infoNoActiveProfile         = "No profile is active"
+	infoNoProfiles              = "No profile is saved. Save one with 'goal profile add'."
+	infoUsingProfile            = "Using profile %s"
+	errorProfileNotFound        = "Profile %s does not exist"
+	errorProfileTokenWithoutURL = "--algod-token only applies with --algod-url"
+	errorProfileUseArgs         = "Specify either a profile name or --none"
+	errorLoadingProfiles        = "Cannot load the profile registry: %v"
+	errorSavingProfiles         = "Cannot save the profile registry: %v"
 
 	// Wallet
 	infoRecoveryPrompt           = "Please type your recovery mnemonic below, and hit return when you are done: "
 	infoChoosePasswordPrompt     = "Please choose a password for wallet '%s': "
 	infoPasswordConfirmation     = "Please confirm the password: "
+	infoKeystorePassphrasePrompt = "Please enter the keystore passphrase: "
 	infoCreatingWallet           = "Creating wallet..."
 	infoCreatedWallet            = "Created wallet '%s'"
 	infoBackupExplanation        = "Your new wallet has a backup phrase that can be used for recovery.\nKeeping this backup phrase safe is extremely important.\nWould you like to see it now? (Y/n): "
@@ -121,12 +300,20 @@ const (
 	errorCouldntListWallets      = "Couldn't list wallets: %s"
 	errorPasswordConfirmation    = "Password confirmation did not match"
 	errorBadMnemonic             = "Problem with mnemonic: %s"
+	errorKeyfileAndMnemonic      = "Specify either a mnemonic or a keystore file, not both"
+	errorKeyfileAndQRCode        = "A keystore file cannot be exported as a QR code"
+	errorQRCode                  = "Cannot encode a QR code: %v"
+	infoWroteQRCode              = "Wrote the QR code to %s"
+	errorKeystoreDecrypt         = "Cannot decrypt the keystore file %s: %v"
+	errorKeystoreEncrypt         = "Cannot encrypt the key: %v"
+	errorEmptyKeystorePassphrase = "The keystore passphrase cannot be empty"
 	errorBadRecoveredKey         = "Recovered invalid key"
 	errorFailedToReadResponse    = "Couldn't read response: %s"
 	errorFailedToReadPassword    = "Couldn't read password: %s"
 
 	// Commands
 	infoPasswordPrompt       = "Please enter the password for wallet '%s': "
+	infoApproveOnDevice      = "Approve the transaction on your hardware wallet to sign it"
 	infoSetWalletToDefault   = "Set wallet '%s' to be the default wallet"
 	errCouldNotListWallets   = "Couldn't list wallets: %s"
 	errNoWallets             = "No wallets found. Create a new wallet with `goal wallet new [wallet name]`"
@@ -138,4 +325,60 @@ const (
 	errWalletNotFound        = "Wallet '%s' not found"
 	errDefaultWalletNotFound = "Wallet with ID '%s' not found. Was the default wallet deleted?"
 	errGettingToken          = "Couldn't get token for wallet '%s' (ID: %s): %s"
+
+	// Completion
+	errorCompletion     = "Couldn't generate the completion script: %v"
+	errorCompletionKind = "Unknown completion kind '%s'"
+
+	// Ledger
+	errorParseRound    = "Couldn't parse the round '%s': %v"
+	errorWriteRawBlock = "Couldn't write the block to %s: %v"
+
+	// Bulk import
+	infoBulkImported          = "Imported %d of %d accounts"
+	errorBulkImportDecode     = "Couldn't decode %s: %v"
+	errorBulkImportEmpty      = "%s lists no accounts"
+	errorBulkImportEntry      = "Entry %d: %v"
+	errorBulkImportInvalid    = "Found %d problem(s) in the file, so no account was imported"
+	errorBulkImportIncomplete = "Failed to import %d of %d accounts"
+
+	// Send many
+	infoSendManyResuming     = "Skipping the %d payments that %s records as sent"
+	infoSendManySent         = "Sent %d of %d payments, recorded in %s"
+	errorSendManyDecode      = "Couldn't decode %s: %v"
+	errorSendManyEmpty       = "%s lists no payments"
+	errorSendManyConcurrency = "--concurrency must be at least 1, not %d"
+	errorSendManyNoGroups    = "The current protocol doesn't support transaction groups"
+	errorSendManyIncomplete  = "Failed to send %d of %d payments; run the command again to retry them"
+
+	// Rewards
+	infoRewardsPending    = "Pending rewards: %d microAlgos"
+	infoRewardsRate       = "Rewards rate: %d microAlgos per round for the whole network, as of round %d"
+	infoRewardsProjection = "Estimated earnings at the current balance: %.2f microAlgos per round, %.0f per day"
+	infoRewardsHistory    = "Over the last %d rounds: about %d microAlgos accrued, %d realized by transactions"
+
+	// Messages
+	infoMessageVerified   = "The message was signed with the key of %s"
+	errorSigningMessage   = "Couldn't sign the message: %v"
+	errorMessageSignature = "'%s' is not a base64 signature"
+	errorMessageInvalid   = "The signature is invalid: %v"
+
+	// Reserves
+	infoReservesWritten           = "Wrote the report of %d accounts holding %d microAlgos as of round %d to %s"
+	infoReservesValid             = "The report is signed by all its %d accounts, holding %d microAlgos as of round %d (block %s)"
+	warnReservesBalancesUnchecked = "The node no longer holds the balances of round %d, so they were not compared with the report"
+	errorReservesNoAccounts       = "No accounts to report on, give them with --address or --addrfile"
+	errorReservesSign             = "Couldn't sign the report: %v"
+	errorReservesFutureRound      = "Round %d has not been reached yet, the last round is %d"
+	errorReservesDecode           = "Couldn't decode the report %s: %v"
+	errorReservesInvalid          = "The report is invalid: %v"
+	errorReservesGenesis          = "The report is of network %s, but the node is on %s"
+	errorReservesBlockHash        = "The report names block %[2]s for round %[1]d, but the node has block %[3]s"
+	errorReservesBalance          = "The report gives account %s %d microAlgos, but the node has %d"
+	errorReservesSigner           = "The report doesn't match the node about the key account %s was rekeyed to (node: '%s')"
+
+	// Debug
+	infoBenchRunning = "Running %s: %s..."
+	errorBench       = "Couldn't run the benchmarks: %v"
+	errorBenchReport = "Couldn't access the benchmark report %s: %v"
 )
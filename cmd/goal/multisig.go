@@ -17,6 +17,7 @@
 package main
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 
@@ -45,6 +46,15 @@ func init() {
 
 	mergeSigCmd.Flags().StringVarP(&txFilename, "out", "o", "", "Output file for merged transactions")
 	mergeSigCmd.MarkFlagRequired("out")
+
+	accountMultisigCmd.AddCommand(multisigStatusCmd)
+	accountMultisigCmd.AddCommand(multisigMergeCmd)
+
+	multisigStatusCmd.Flags().StringVarP(&txFilename, "txfile", "t", "", "Partially-signed transaction file to examine")
+	multisigStatusCmd.MarkFlagRequired("txfile")
+
+	multisigMergeCmd.Flags().StringVarP(&txFilename, "out", "o", "", "Output file for the merged transactions")
+	multisigMergeCmd.MarkFlagRequired("out")
 }
 
 var multisigCmd = &cobra.Command{
@@ -114,65 +124,210 @@ var mergeSigCmd = &cobra.Command{
 
 		var txnLists [][]transactions.SignedTxn
 		for _, arg := range args {
-			data, err := ioutil.ReadFile(arg)
-			if err != nil {
-				reportErrorf(fileReadError, arg, err)
-			}
+			txnLists = append(txnLists, readSignedTxnFile(arg))
+		}
 
-			dec := protocol.NewDecoderBytes(data)
-			var txns []transactions.SignedTxn
-			for {
-				var txn transactions.SignedTxn
-				err = dec.Decode(&txn)
-				if err == io.EOF {
-					break
-				}
-				if err != nil {
-					reportErrorf(txDecodeError, arg, err)
-				}
-				txns = append(txns, txn)
-			}
+		writeSignedTxnFile(txFilename, mergeMultisigTxns(txnLists))
+	},
+}
+
+var multisigStatusCmd = &cobra.Command{
+	Use:   "status -t TXFILE",
+	Short: "Show the signatures collected on multisig transactions",
+	Long:  `Show, for each multisig transaction of a partially-signed transaction file, which subsignatures are present, missing or invalid, and how many more are needed to reach the threshold`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		reportMultisigStatus(readSignedTxnFile(txFilename))
+	},
+}
+
+var multisigMergeCmd = &cobra.Command{
+	Use:   "merge -o MERGEDTXFILE TXFILE1 TXFILE2",
+	Short: "Merge two partially-signed multisig transaction files",
+	Long:  `Combine the subsignatures of two partially-signed copies of the same multisig transactions, write the merged transactions out, and show the signatures they now carry`,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		merged := mergeMultisigTxns([][]transactions.SignedTxn{readSignedTxnFile(args[0]), readSignedTxnFile(args[1])})
+		writeSignedTxnFile(txFilename, merged)
+		reportInfof(infoMultisigMerged, len(merged), txFilename)
+		reportMultisigStatus(merged)
+	},
+}
 
-			txnLists = append(txnLists, txns)
+// readSignedTxnFile reads the signed transactions of a transaction file
+func readSignedTxnFile(filename string) []transactions.SignedTxn {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		reportErrorf(fileReadError, filename, err)
+	}
+
+	dec := protocol.NewDecoderBytes(data)
+	var txns []transactions.SignedTxn
+	for {
+		var txn transactions.SignedTxn
+		err = dec.Decode(&txn)
+		if err == io.EOF {
+			break
 		}
+		if err != nil {
+			reportErrorf(txDecodeError, filename, err)
+		}
+		txns = append(txns, txn)
+	}
+	return txns
+}
 
-		// Ensure that all lists are the same length
-		for _, txnList := range txnLists {
-			if len(txnList) != len(txnLists[0]) {
-				reportErrorf(txLengthError)
-			}
+// writeSignedTxnFile writes signed transactions out to a transaction file
+func writeSignedTxnFile(filename string, txns []transactions.SignedTxn) {
+	var data []byte
+	for _, txn := range txns {
+		data = append(data, protocol.Encode(txn)...)
+	}
+
+	err := ioutil.WriteFile(filename, data, 0600)
+	if err != nil {
+		reportErrorf(fileWriteError, filename, err)
+	}
+}
+
+// mergeMultisigTxns merges the multisig signatures of the i'th transactions of every list
+func mergeMultisigTxns(txnLists [][]transactions.SignedTxn) []transactions.SignedTxn {
+	// Ensure that all lists are the same length
+	for _, txnList := range txnLists {
+		if len(txnList) != len(txnLists[0]) {
+			reportErrorf(txLengthError)
 		}
+	}
 
-		// Merge multisigs
-		var mergedTxns []transactions.SignedTxn
-		for i, tx0 := range txnLists[0] {
-			// Merge tx0 with every other i'th transaction, and check for txn equality
-			for _, txnList := range txnLists {
-				if tx0.ID() != txnList[i].ID() {
-					reportErrorf(txMergeMismatch)
-				}
-
-				var err error
-				tx0.Msig, err = crypto.MultisigMerge(tx0.Msig, txnList[i].Msig)
-				if err != nil {
-					reportErrorf(txMergeError, err)
-				}
+	// Merge multisigs
+	var mergedTxns []transactions.SignedTxn
+	for i, tx0 := range txnLists[0] {
+		// Merge tx0 with every other i'th transaction, and check for txn equality
+		for _, txnList := range txnLists {
+			if tx0.ID() != txnList[i].ID() {
+				reportErrorf(txMergeMismatch)
 			}
 
-			mergedTxns = append(mergedTxns, tx0)
+			var err error
+			tx0.Msig, err = crypto.MultisigMerge(tx0.Msig, txnList[i].Msig)
+			if err != nil {
+				reportErrorf(txMergeError, err)
+			}
 		}
 
-		// Write out the transactions to the output file
-		var mergedData []byte
-		for _, txn := range mergedTxns {
-			mergedData = append(mergedData, protocol.Encode(txn)...)
+		mergedTxns = append(mergedTxns, tx0)
+	}
+	return mergedTxns
+}
+
+// Subsignature states reported by goal account multisig status
+const (
+	subsigSigned  = "signed"
+	subsigMissing = "missing"
+	subsigInvalid = "invalid"
+)
+
+// multisigSubsigStatus is the state of one subsignature of a multisig transaction
+type multisigSubsigStatus struct {
+	Address string `json:"address"`
+	Status  string `json:"status"`
+}
+
+// multisigTxnStatus is the signing progress of a multisig transaction, as reported by goal account multisig status
+type multisigTxnStatus struct {
+	TxID      string                 `json:"txid"`
+	Sender    string                 `json:"sender"`
+	Multisig  string                 `json:"multisig"`
+	Version   uint8                  `json:"version"`
+	Threshold uint8                  `json:"threshold"`
+	Signed    int                    `json:"signed"`
+	Invalid   int                    `json:"invalid"`
+	Subsigs   []multisigSubsigStatus `json:"subsigs"`
+	Ready     bool                   `json:"ready"`
+}
+
+// Missing returns how many more valid subsignatures the transaction needs
+func (status multisigTxnStatus) Missing() int {
+	if status.Signed >= int(status.Threshold) {
+		return 0
+	}
+	return int(status.Threshold) - status.Signed
+}
+
+// multisigStatus checks the multisig of stxn against the address which has to sign it, and each of its
+// subsignatures against the transaction
+func multisigStatus(stxn transactions.SignedTxn) (status multisigTxnStatus, err error) {
+	txid := stxn.ID().String()
+	if stxn.Msig.Blank() {
+		return status, fmt.Errorf(errorNotMultisigTxn, txid)
+	}
+
+	msigAddr, err := crypto.MultisigAddrGenWithSubsigs(stxn.Msig.Version, stxn.Msig.Threshold, stxn.Msig.Subsigs)
+	if err != nil {
+		return status, fmt.Errorf(errorInvalidMultisigTxn, txid, err)
+	}
+	// The multisig has to be the one of the account that signs the transaction, or no signature counts
+	signer := stxn.Authorizer()
+	if basics.Address(msigAddr) != signer {
+		return status, fmt.Errorf(errorMultisigSignerMismatch, txid, basics.Address(msigAddr).GetUserAddress(), signer.GetUserAddress())
+	}
+
+	status = multisigTxnStatus{
+		TxID:      txid,
+		Sender:    stxn.Txn.Sender.GetUserAddress(),
+		Multisig:  signer.GetUserAddress(),
+		Version:   stxn.Msig.Version,
+		Threshold: stxn.Msig.Threshold,
+	}
+	for _, subsig := range stxn.Msig.Subsigs {
+		subsigStatus := multisigSubsigStatus{Address: basics.Address(subsig.Key).GetUserAddress(), Status: subsigMissing}
+		if subsig.Sig != (crypto.Signature{}) {
+			if subsig.Key.Verify(stxn.Txn, subsig.Sig) {
+				subsigStatus.Status = subsigSigned
+				status.Signed++
+			} else {
+				subsigStatus.Status = subsigInvalid
+				status.Invalid++
+			}
 		}
+		status.Subsigs = append(status.Subsigs, subsigStatus)
+	}
+	// An invalid subsignature makes the whole multisig fail to verify, even with enough valid ones
+	status.Ready = status.Missing() == 0 && status.Invalid == 0
+	return status, nil
+}
 
-		err := ioutil.WriteFile(txFilename, mergedData, 0600)
+func reportMultisigStatus(txns []transactions.SignedTxn) {
+	var statuses []multisigTxnStatus
+	var rows [][]string
+	for _, stxn := range txns {
+		status, err := multisigStatus(stxn)
 		if err != nil {
-			reportErrorf(fileWriteError, txFilename, err)
+			reportErrorln(err)
 		}
-	},
+		statuses = append(statuses, status)
+		for _, subsig := range status.Subsigs {
+			rows = append(rows, []string{status.TxID, subsig.Address, subsig.Status})
+		}
+	}
+
+	reportRows(statuses, []string{"TXID", "ADDRESS", "SIGNATURE"}, rows, func() {
+		for _, status := range statuses {
+			fmt.Printf("Transaction %s from %s\n", status.TxID, status.Sender)
+			fmt.Printf("Multisig %s: version %d, threshold %d of %d\n", status.Multisig, status.Version, status.Threshold, len(status.Subsigs))
+			for _, subsig := range status.Subsigs {
+				fmt.Printf("  %-8s %s\n", subsig.Status, subsig.Address)
+			}
+			switch {
+			case status.Ready:
+				fmt.Printf("%d of %d required signatures present, ready to send\n", status.Signed, status.Threshold)
+			case status.Invalid > 0:
+				fmt.Printf("%d of %d required signatures present, %d invalid ones to remove\n", status.Signed, status.Threshold, status.Invalid)
+			default:
+				fmt.Printf("%d of %d required signatures present, %d more needed\n", status.Signed, status.Threshold, status.Missing())
+			}
+		}
+	})
 }
 
 func populateBlankMultisig(client libgoal.Client, dataDir string, walletName string, stxn transactions.SignedTxn) transactions.SignedTxn {
@@ -0,0 +1,87 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestMultisigStatus(t *testing.T) {
+	var secrets []*crypto.SignatureSecrets
+	var pks []crypto.PublicKey
+	for i := 0; i < 3; i++ {
+		var seed crypto.Seed
+		crypto.RandBytes(seed[:])
+		s := crypto.GenerateSignatureSecrets(seed)
+		secrets = append(secrets, s)
+		pks = append(pks, s.SignatureVerifier)
+	}
+	msigAddr, err := crypto.MultisigAddrGen(1, 2, pks)
+	require.NoError(t, err)
+
+	var stxn transactions.SignedTxn
+	stxn.Txn.Type = protocol.PaymentTx
+	stxn.Txn.Sender = basics.Address(msigAddr)
+	stxn.Msig = crypto.MultisigPreimageFromPKs(1, 2, pks)
+
+	status, err := multisigStatus(stxn)
+	require.NoError(t, err)
+	require.Equal(t, basics.Address(msigAddr).GetUserAddress(), status.Multisig)
+	require.Equal(t, 0, status.Signed)
+	require.Equal(t, 2, status.Missing())
+	require.False(t, status.Ready)
+	for _, subsig := range status.Subsigs {
+		require.Equal(t, subsigMissing, subsig.Status)
+	}
+
+	// One signature for this transaction, one for another one
+	stxn.Msig.Subsigs[0].Sig = secrets[0].Sign(stxn.Txn)
+	var other transactions.Transaction
+	other.Type = protocol.PaymentTx
+	stxn.Msig.Subsigs[2].Sig = secrets[2].Sign(other)
+
+	status, err = multisigStatus(stxn)
+	require.NoError(t, err)
+	require.Equal(t, []string{subsigSigned, subsigMissing, subsigInvalid},
+		[]string{status.Subsigs[0].Status, status.Subsigs[1].Status, status.Subsigs[2].Status})
+	require.Equal(t, 1, status.Signed)
+	require.Equal(t, 1, status.Missing())
+	require.False(t, status.Ready)
+
+	stxn.Msig.Subsigs[1].Sig = secrets[1].Sign(stxn.Txn)
+	stxn.Msig.Subsigs[2].Sig = crypto.Signature{}
+	status, err = multisigStatus(stxn)
+	require.NoError(t, err)
+	require.Equal(t, 2, status.Signed)
+	require.Equal(t, 0, status.Missing())
+	require.True(t, status.Ready)
+
+	// The multisig of another account doesn't count
+	stxn.Txn.Sender = basics.Address{}
+	_, err = multisigStatus(stxn)
+	require.Error(t, err)
+
+	_, err = multisigStatus(transactions.SignedTxn{})
+	require.Error(t, err)
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -33,11 +35,13 @@ var networkTemplateFile string
 var startNode string
 var noImportKeys bool
 var noClean bool
+var registeredDataDirName string
+var snapshotDir string
+var snapshotRound uint64
+var startAfterSnapshot bool
 
 func init() {
 	networkCmd.AddCommand(networkCreateCmd)
-	networkCmd.PersistentFlags().StringVarP(&networkRootDir, "rootdir", "r", "", "Root directory for the private network directories")
-	networkCmd.MarkPersistentFlagRequired("rootdir")
 
 	networkCreateCmd.Flags().StringVarP(&networkName, "network", "n", "", "Specify the name to use for the private network")
 	networkCreateCmd.MarkFlagRequired("network")
@@ -53,6 +57,29 @@ func init() {
 	networkCmd.AddCommand(networkStopCmd)
 	networkCmd.AddCommand(networkStatusCmd)
 	networkCmd.AddCommand(networkDeleteCmd)
+	networkCmd.AddCommand(networkSnapshotCmd)
+	networkCmd.AddCommand(networkRestoreCmd)
+
+	networkSnapshotCmd.Flags().Uint64Var(&snapshotRound, "round", 0, "Wait for the primary node to reach this round before taking the snapshot")
+	for _, cmd := range []*cobra.Command{networkSnapshotCmd, networkRestoreCmd} {
+		cmd.Flags().StringVarP(&snapshotDir, "snapshotdir", "s", "", "Directory holding the snapshot of the private network")
+		cmd.MarkFlagRequired("snapshotdir")
+		cmd.Flags().BoolVar(&startAfterSnapshot, "start", false, "Start the network once done (by default it is left stopped)")
+	}
+
+	// the registry commands don't work on private network directories, so only the other commands take a root directory
+	for _, cmd := range []*cobra.Command{networkCreateCmd, networkStartCmd, networkRestartCmd, networkStopCmd, networkStatusCmd, networkDeleteCmd, networkSnapshotCmd, networkRestoreCmd} {
+		cmd.Flags().StringVarP(&networkRootDir, "rootdir", "r", "", "Root directory for the private network directories")
+		cmd.MarkFlagRequired("rootdir")
+	}
+
+	networkCmd.AddCommand(networkRegisterCmd)
+	networkCmd.AddCommand(networkUnregisterCmd)
+	networkCmd.AddCommand(networkUseCmd)
+	networkCmd.AddCommand(networkListCmd)
+	networkRegisterCmd.Flags().StringVarP(&registeredDataDirName, "name", "n", "", "Name of the data directory within the network; defaults to the directory name")
+	networkUnregisterCmd.Flags().StringVarP(&registeredDataDirName, "name", "n", "", "Only unregister the named data directory rather than the whole network")
+	networkUseCmd.Flags().StringVarP(&registeredDataDirName, "name", "n", "", "Also make the named data directory the default one of the network")
 }
 
 var networkCmd = &cobra.Command{
@@ -209,3 +236,169 @@ var networkDeleteCmd = &cobra.Command{
 		reportInfof(infoNetworkDeleted, networkRootDir)
 	},
 }
+
+var networkSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save the state of a deployed private network",
+	Long:  "Stop a deployed private network and save the ledgers, wallets and configuration of all its nodes to a snapshot directory, from which 'goal network restore' can bring the network back to the same rounds.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		network, binDir := getNetworkAndBinDir()
+		snapshot, err := network.Snapshot(binDir, snapshotDir, snapshotRound)
+		if err != nil {
+			reportErrorf(errorSnapshotNetwork, err)
+		}
+		reportInfof(infoNetworkSnapshot, snapshotDir, formatSnapshotRounds(snapshot))
+		if startAfterSnapshot {
+			startNetwork(network, binDir)
+		}
+	},
+}
+
+var networkRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a private network from a snapshot",
+	Long:  "Restore a private network from a snapshot taken with 'goal network snapshot'. The network deployed under the root directory, if any, is stopped and replaced. NOTE: This does not prompt first - so be careful before you do this!",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		binDir, err := util.ExeDir()
+		if err != nil {
+			panic(err)
+		}
+		network, snapshot, err := netdeploy.RestoreNetwork(snapshotDir, networkRootDir, binDir)
+		if err != nil {
+			reportErrorf(errorRestoreNetwork, err)
+		}
+		reportInfof(infoNetworkRestored, networkRootDir, formatSnapshotRounds(snapshot))
+		if startAfterSnapshot {
+			startNetwork(network, binDir)
+		}
+	},
+}
+
+func startNetwork(network netdeploy.Network, binDir string) {
+	err := network.Start(binDir, false)
+	if err != nil {
+		reportErrorf(errorStartingNetwork, err)
+	}
+	reportInfof(infoNetworkStarted, networkRootDir)
+}
+
+// formatSnapshotRounds lists the round of each node of a snapshot, in node order
+func formatSnapshotRounds(snapshot netdeploy.NetworkSnapshot) string {
+	nodes := make([]string, 0, len(snapshot.Rounds))
+	for node := range snapshot.Rounds {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	rounds := make([]string, len(nodes))
+	for i, node := range nodes {
+		rounds[i] = fmt.Sprintf("%s@%d", node, snapshot.Rounds[node])
+	}
+	return strings.Join(rounds, ", ")
+}
+
+var networkRegisterCmd = &cobra.Command{
+	Use:   "register [network] [data directory]",
+	Short: "Register a data directory for a network",
+	Long:  "Register the data directory of a node under a network name, such as mainnet, testnet or the name of a private network, so that 'goal network use' can select it.",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		network := args[0]
+		dataDir, err := filepath.Abs(args[1])
+		if err != nil {
+			reportErrorf(errorDirectoryNotExist, args[1])
+		}
+		if !util.IsDir(dataDir) {
+			reportErrorf(errorDirectoryNotExist, dataDir)
+		}
+		name := registeredDataDirName
+		if name == "" {
+			name = filepath.Base(dataDir)
+		}
+
+		registry := ensureNetworkRegistry()
+		registry.register(network, name, dataDir)
+		saveNetworkRegistry(registry)
+		reportInfof(infoNetworkRegistered, name, dataDir, network)
+	},
+}
+
+var networkUnregisterCmd = &cobra.Command{
+	Use:   "unregister [network]",
+	Short: "Unregister a network or one of its data directories",
+	Long:  "Remove a network, or only one of its data directories with --name, from the network registry. The data directories themselves are left untouched.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		registry := ensureNetworkRegistry()
+		if err := registry.unregister(args[0], registeredDataDirName); err != nil {
+			reportErrorln(err)
+		}
+		saveNetworkRegistry(registry)
+		if registeredDataDirName == "" {
+			reportInfof(infoNetworkUnregistered, args[0])
+		} else {
+			reportInfof(infoNetworkDataDirUnregistered, registeredDataDirName, args[0])
+		}
+	},
+}
+
+var networkUseCmd = &cobra.Command{
+	Use:   "use [network]",
+	Short: "Select the network goal works against",
+	Long:  "Make goal work against the default data directory of a registered network whenever neither -d nor ALGORAND_DATA give it a data directory.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		registry := ensureNetworkRegistry()
+		if err := registry.use(args[0], registeredDataDirName); err != nil {
+			reportErrorln(err)
+		}
+		saveNetworkRegistry(registry)
+		registered := registry.Networks[args[0]]
+		reportInfof(infoNetworkInUse, registered.Default, registered.DataDirs[registered.Default], args[0])
+	},
+}
+
+var networkListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the registered networks and their data directories",
+	Long:  "List the registered networks and their data directories. The active network and the default data directory of each network are marked with a *.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		registry := ensureNetworkRegistry()
+		reportResult(registry, registry.Active, func() {
+			if len(registry.Networks) == 0 {
+				reportInfoln(infoNoRegisteredNetworks)
+				return
+			}
+			for _, network := range registry.names() {
+				fmt.Printf("%s%s\n", activeMark(network == registry.Active), network)
+				registered := registry.Networks[network]
+				for _, name := range registered.names() {
+					fmt.Printf("  %s%s\t%s\n", activeMark(name == registered.Default), name, registered.DataDirs[name])
+				}
+			}
+		})
+	},
+}
+
+func activeMark(active bool) string {
+	if active {
+		return "* "
+	}
+	return "  "
+}
+
+func ensureNetworkRegistry() *NetworkRegistry {
+	registry, err := loadNetworkRegistry()
+	if err != nil {
+		reportErrorf(errorLoadingNetworkRegistry, err)
+	}
+	return registry
+}
+
+func saveNetworkRegistry(registry *NetworkRegistry) {
+	if err := registry.save(); err != nil {
+		reportErrorf(errorSavingNetworkRegistry, err)
+	}
+}
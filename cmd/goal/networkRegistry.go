@@ -0,0 +1,181 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/algorand/go-algorand/config"
+)
+
+// networkRegistryFilename is the name of the network registry file, in the global config directory (~/.algorand)
+const networkRegistryFilename = "goal-networks.json"
+
+// NetworkRegistry maps network names (such as mainnet, testnet or the name of a private network) to the named data
+// directories of the nodes goal manages on them. goal works against the default data directory of the active
+// network when neither -d nor ALGORAND_DATA give it one.
+type NetworkRegistry struct {
+	Active   string
+	Networks map[string]*RegisteredNetwork
+}
+
+// RegisteredNetwork holds the data directories registered for a network, by name
+type RegisteredNetwork struct {
+	DataDirs map[string]string
+	Default  string
+}
+
+func networkRegistryPath() (string, error) {
+	return config.GetConfigFilePath(networkRegistryFilename)
+}
+
+// loadNetworkRegistry loads the network registry, returning an empty one if it doesn't exist yet
+func loadNetworkRegistry() (*NetworkRegistry, error) {
+	registry := &NetworkRegistry{Networks: map[string]*RegisteredNetwork{}}
+	filename, err := networkRegistryPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, registry); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %v", filename, err)
+	}
+	if registry.Networks == nil {
+		registry.Networks = map[string]*RegisteredNetwork{}
+	}
+	return registry, nil
+}
+
+func (registry *NetworkRegistry) save() error {
+	filename, err := networkRegistryPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// register adds the data directory under the given name to the network, creating the network if needed.
+// The first data directory of a network becomes its default one.
+func (registry *NetworkRegistry) register(network, name, dataDir string) {
+	registered, ok := registry.Networks[network]
+	if !ok {
+		registered = &RegisteredNetwork{DataDirs: map[string]string{}}
+		registry.Networks[network] = registered
+	}
+	registered.DataDirs[name] = dataDir
+	if registered.Default == "" {
+		registered.Default = name
+	}
+}
+
+// unregister removes the named data directory from the network, or the whole network if name is empty
+func (registry *NetworkRegistry) unregister(network, name string) error {
+	registered, ok := registry.Networks[network]
+	if !ok {
+		return fmt.Errorf(errorNetworkNotRegistered, network)
+	}
+	if name != "" {
+		if _, ok := registered.DataDirs[name]; !ok {
+			return fmt.Errorf(errorNetworkDataDirNotRegistered, name, network)
+		}
+		delete(registered.DataDirs, name)
+		if registered.Default == name {
+			registered.Default = ""
+			if names := registered.names(); len(names) > 0 {
+				registered.Default = names[0]
+			}
+		}
+	}
+	if name == "" || len(registered.DataDirs) == 0 {
+		delete(registry.Networks, network)
+		if registry.Active == network {
+			registry.Active = ""
+		}
+	}
+	return nil
+}
+
+// use makes the network active, and the named data directory its default one unless name is empty
+func (registry *NetworkRegistry) use(network, name string) error {
+	registered, ok := registry.Networks[network]
+	if !ok {
+		return fmt.Errorf(errorNetworkNotRegistered, network)
+	}
+	if name != "" {
+		if _, ok := registered.DataDirs[name]; !ok {
+			return fmt.Errorf(errorNetworkDataDirNotRegistered, name, network)
+		}
+		registered.Default = name
+	}
+	registry.Active = network
+	return nil
+}
+
+// activeDataDir returns the default data directory of the active network, if any
+func (registry *NetworkRegistry) activeDataDir() (network, dataDir string) {
+	registered, ok := registry.Networks[registry.Active]
+	if !ok {
+		return "", ""
+	}
+	return registry.Active, registered.DataDirs[registered.Default]
+}
+
+func (registry *NetworkRegistry) names() []string {
+	names := make([]string, 0, len(registry.Networks))
+	for name := range registry.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (registered *RegisteredNetwork) names() []string {
+	names := make([]string, 0, len(registered.DataDirs))
+	for name := range registered.DataDirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registeredDataDir returns the data directory of the active network, or an empty string if there is none
+func registeredDataDir() string {
+	registry, err := loadNetworkRegistry()
+	if err != nil {
+		reportWarnf(errorLoadingNetworkRegistry, err)
+		return ""
+	}
+	network, dataDir := registry.activeDataDir()
+	if dataDir != "" {
+		reportVerbosef(infoUsingNetworkDataDir, dataDir, network)
+	}
+	return dataDir
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+)
+
+func TestNetworkRegistry(t *testing.T) {
+	a := require.New(t)
+
+	tempDir, err := ioutil.TempDir("", "goal-networks")
+	a.NoError(err)
+	defer os.RemoveAll(tempDir)
+	defer config.SetGlobalConfigFileRoot(config.SetGlobalConfigFileRoot(tempDir))
+
+	registry, err := loadNetworkRegistry()
+	a.NoError(err)
+	network, dataDir := registry.activeDataDir()
+	a.Empty(network)
+	a.Empty(dataDir)
+
+	registry.register("testnet", "node", "/var/lib/algorand/testnet")
+	registry.register("testnet", "relay", "/var/lib/algorand/testnet-relay")
+	registry.register("private", "primary", "/tmp/net/Primary")
+	a.Error(registry.use("mainnet", ""))
+	a.Error(registry.use("testnet", "nosuchnode"))
+	a.NoError(registry.use("testnet", ""))
+	a.NoError(registry.save())
+
+	registry, err = loadNetworkRegistry()
+	a.NoError(err)
+	network, dataDir = registry.activeDataDir()
+	a.Equal("testnet", network)
+	a.Equal("/var/lib/algorand/testnet", dataDir)
+
+	a.NoError(registry.use("testnet", "relay"))
+	_, dataDir = registry.activeDataDir()
+	a.Equal("/var/lib/algorand/testnet-relay", dataDir)
+
+	// unregistering the default data directory falls back to another one
+	a.NoError(registry.unregister("testnet", "relay"))
+	_, dataDir = registry.activeDataDir()
+	a.Equal("/var/lib/algorand/testnet", dataDir)
+
+	// unregistering the active network leaves no network active
+	a.NoError(registry.unregister("testnet", ""))
+	a.Empty(registry.Active)
+	a.Equal([]string{"private"}, registry.names())
+	a.Error(registry.unregister("testnet", ""))
+}
@@ -21,12 +21,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/daemon/algod/audit"
 	"github.com/algorand/go-algorand/libgoal"
 	"github.com/algorand/go-algorand/nodecontrol"
 	"github.com/algorand/go-algorand/util"
@@ -40,7 +44,11 @@ var noLedger bool
 var runUnderHost bool
 var telemetryOverride string
 var maxPendingTransactions uint64
+var pendingTxnsSender string
 var waitSec uint32
+var auditLogLast int
+var auditLogJSON bool
+var nodeProfile string
 
 func init() {
 	nodeCmd.AddCommand(startCmd)
@@ -52,6 +60,12 @@ func init() {
 	nodeCmd.AddCommand(generateTokenCmd)
 	nodeCmd.AddCommand(pendingTxnsCmd)
 	nodeCmd.AddCommand(waitCmd)
+	nodeCmd.AddCommand(auditLogCmd)
+	nodeCmd.AddCommand(nodeConfigCmd)
+
+	nodeConfigCmd.AddCommand(validateConfigCmd)
+	nodeConfigCmd.AddCommand(reloadConfigCmd)
+	nodeConfigCmd.AddCommand(diffProfileCmd)
 
 	startCmd.Flags().StringVarP(&peerDial, "peer", "p", "", "Peer address to dial for initial connection")
 	startCmd.Flags().StringVarP(&listenIP, "listen", "l", "", "Endpoint / REST address to listen on")
@@ -63,8 +77,14 @@ func init() {
 	restartCmd.Flags().BoolVarP(&runUnderHost, "hosted", "H", false, "Run algod hosted by algoh")
 	startCmd.Flags().StringVarP(&telemetryOverride, "telemetry", "t", "", `Enable telemetry if supported (Use "true", "false", "0" or "1")`)
 	restartCmd.Flags().StringVarP(&telemetryOverride, "telemetry", "t", "", `Enable telemetry if supported (Use "true", "false", "0" or "1")`)
+	profileUsage := "Run the node with a config profile, one of " + strings.Join(config.ProfileNames(), ", ") + "; " + config.ConfigFilename + " settings take precedence over it"
+	startCmd.Flags().StringVar(&nodeProfile, "profile", "", profileUsage)
+	restartCmd.Flags().StringVar(&nodeProfile, "profile", "", profileUsage)
 	pendingTxnsCmd.Flags().Uint64VarP(&maxPendingTransactions, "maxPendingTxn", "m", 0, "Cap the number of txns to fetch")
+	pendingTxnsCmd.Flags().StringVarP(&pendingTxnsSender, "sender", "s", "", "Only fetch the txns sent by this address, along with their position in the pool")
 
+	auditLogCmd.Flags().IntVarP(&auditLogLast, "last", "n", 0, "Only show the last N entries; 0 shows all of them")
+	auditLogCmd.Flags().BoolVar(&auditLogJSON, "json", false, "Print the entries as line-delimited JSON (implied by --output json)")
 	waitCmd.Flags().Uint32VarP(&waitSec, "waittime", "w", 5, "Time (in seconds) to wait for node to make progress")
 }
 
@@ -89,6 +109,7 @@ var startCmd = &cobra.Command{
 		if err != nil {
 			panic(err)
 		}
+		ensureProfile(nodeProfile)
 		onDataDirs(func(dataDir string) {
 			nc := nodecontrol.MakeNodeController(binDir, dataDir)
 			nodeArgs := nodecontrol.AlgodStartArgs{
@@ -97,6 +118,7 @@ var startCmd = &cobra.Command{
 				RedirectOutput:    false,
 				RunUnderHost:      runUnderHost,
 				TelemetryOverride: telemetryOverride,
+				Profile:           nodeProfile,
 			}
 
 			if getRunHostedConfigFlag(dataDir) {
@@ -117,6 +139,16 @@ var startCmd = &cobra.Command{
 	},
 }
 
+// ensureProfile exits with an error unless name is empty or names a config profile
+func ensureProfile(name string) {
+	if name == "" {
+		return
+	}
+	if _, err := config.GetProfile(name); err != nil {
+		reportErrorln(err)
+	}
+}
+
 func getRunHostedConfigFlag(dataDir string) bool {
 	// See if this instance wants to run Hosted, even if '-H' wasn't specified on our cmdline
 	cfg, err := config.LoadConfigFromDisk(dataDir)
@@ -126,6 +158,129 @@ func getRunHostedConfigFlag(dataDir string) bool {
 	return cfg.RunHosted
 }
 
+var nodeConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the node configuration file",
+	Long:  "Inspect the " + config.ConfigFilename + " configuration file in the data directory",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		// If no arguments passed, we should fallback to help
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+var validateConfigCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the node configuration file for mistakes",
+	Long:  "Check that every setting in " + config.ConfigFilename + " is known and holds a valid value. Unknown settings are reported as warnings since the node ignores them, and invalid values as errors since the node refuses to start with them.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		onDataDirsReportingErrors(func(dataDir string) error {
+			filename := filepath.Join(dataDir, config.ConfigFilename)
+			validation, err := config.ValidateConfigFile(filename)
+			if os.IsNotExist(err) {
+				reportInfof(infoConfigNotFound, filename)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf(errLoadingConfig, dataDir, err)
+			}
+
+			reportResult(validation, "", func() {
+				for _, warning := range validation.Warnings {
+					reportWarnln(warning)
+				}
+				for _, issue := range validation.Errors {
+					report.printError(issue.String())
+				}
+				if validation.Valid() {
+					reportInfof(infoConfigValid, filename, validation.Version)
+				}
+				if validation.NeedsMigration() {
+					reportInfof(infoConfigNeedsMigration, filename, validation.Version, config.GetDefaultLocal().Version)
+				}
+			})
+			if !validation.Valid() {
+				return fmt.Errorf(errorConfigInvalid, filename, len(validation.Errors))
+			}
+			return nil
+		})
+	},
+}
+
+var reloadConfigCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Apply configuration changes to the running node",
+	Long:  "Validate " + config.ConfigFilename + " and have the running node reload it. The node applies the settings that can change while it runs, such as log levels and peer caps, and reports in its log which of the changed settings only take effect after a restart.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		binDir, err := util.ExeDir()
+		if err != nil {
+			panic(err)
+		}
+		onDataDirsReportingErrors(func(dataDir string) error {
+			validation, err := config.ValidateConfigFile(filepath.Join(dataDir, config.ConfigFilename))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf(errLoadingConfig, dataDir, err)
+			}
+			if !validation.Valid() {
+				return fmt.Errorf(errorConfigInvalid, config.ConfigFilename, len(validation.Errors))
+			}
+
+			nc := nodecontrol.MakeNodeController(binDir, dataDir)
+			if err = nc.ReloadAlgodConfig(); err != nil {
+				return fmt.Errorf(errorNodeReloadConfig, err)
+			}
+			reportInfof(infoNodeReloadConfig, config.ConfigFilename)
+			return nil
+		})
+	},
+}
+
+var diffProfileCmd = &cobra.Command{
+	Use:   "diff-profile [profile]",
+	Short: "Show how the node configuration deviates from a config profile",
+	Long:  "List the settings of a config profile (one of " + strings.Join(config.ProfileNames(), ", ") + ") that the node configuration sets to other values.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile, err := config.GetProfile(args[0])
+		if err != nil {
+			reportErrorln(err)
+		}
+		onDataDirsReportingErrors(func(dataDir string) error {
+			cfg, err := config.LoadConfigFromDisk(dataDir)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf(errLoadingConfig, dataDir, err)
+			}
+			deviations, err := profile.Deviations(cfg)
+			if err != nil {
+				return err
+			}
+
+			reportResult(deviations, "", func() {
+				if len(deviations) == 0 {
+					reportInfof(infoConfigMatchesProfile, profile.Name)
+					return
+				}
+				reportInfof(infoConfigDeviatesFromProfile, len(deviations), profile.Name, profile.Description)
+				for _, d := range deviations {
+					fmt.Printf("  %s: profile %v, configured %v\n", d.Key, formatSetting(d.Profile), formatSetting(d.Configured))
+				}
+			})
+			return nil
+		})
+	},
+}
+
+// formatSetting formats a setting value the way it would be written in the config file
+func formatSetting(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
 var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "stop the specified Algorand node",
@@ -161,6 +316,7 @@ var restartCmd = &cobra.Command{
 		if err != nil {
 			panic(err)
 		}
+		ensureProfile(nodeProfile)
 		onDataDirs(func(dataDir string) {
 			nc := nodecontrol.MakeNodeController(binDir, dataDir)
 
@@ -188,6 +344,7 @@ var restartCmd = &cobra.Command{
 				RedirectOutput:    false,
 				RunUnderHost:      runUnderHost,
 				TelemetryOverride: telemetryOverride,
+				Profile:           nodeProfile,
 			}
 
 			if getRunHostedConfigFlag(dataDir) {
@@ -235,6 +392,11 @@ var generateTokenCmd = &cobra.Command{
 				reportErrorf(errorNodeFailGenToken, err)
 			}
 
+			if cfg, err := config.LoadConfigFromDisk(dataDir); err == nil && !cfg.DisableAuditLog {
+				auditLog := audit.MakeLog(dataDir, cfg.AuditLogSizeLimit, cfg.AuditLogArchiveCount)
+				auditLog.Record(audit.Entry{Action: "token rotation", Token: audit.TokenIdentity(apiToken)})
+			}
+
 			// Report the new token back to the user
 			reportInfof(infoNodeWroteToken, apiToken)
 		})
@@ -258,19 +420,35 @@ var statusCmd = &cobra.Command{
 				reportErrorf(errorNodeStatus, err)
 			}
 
-			fmt.Println(makeStatusString(stat))
-			if vers.GenesisID != nil {
-				fmt.Printf("Genesis ID: %s\n", *vers.GenesisID)
-			}
-			fmt.Printf("Genesis hash: %s\n", base64.StdEncoding.EncodeToString(vers.GenesisHash[:]))
+			reportResult(nodeStatusOutput{NodeStatus: stat, GenesisID: vers.GenesisID, GenesisHash: vers.GenesisHash}, "", func() {
+				fmt.Println(makeStatusString(stat))
+				if vers.GenesisID != nil {
+					fmt.Printf("Genesis ID: %s\n", *vers.GenesisID)
+				}
+				fmt.Printf("Genesis hash: %s\n", base64.StdEncoding.EncodeToString(vers.GenesisHash[:]))
+			})
 		})
 	},
 }
 
+// nodeStatusOutput is what `goal node status` reports in JSON mode
+type nodeStatusOutput struct {
+	models.NodeStatus
+	GenesisID   *string `json:"genesisID,omitempty"`
+	GenesisHash []byte  `json:"genesisHash"`
+}
+
 func makeStatusString(stat models.NodeStatus) string {
 	lastRoundTime := fmt.Sprintf("%.1fs", time.Duration(stat.TimeSinceLastRound).Seconds())
 	catchupTime := fmt.Sprintf("%.1fs", time.Duration(stat.CatchupTime).Seconds())
-	return fmt.Sprintf(infoNodeStatus, stat.LastRound, lastRoundTime, catchupTime, stat.LastVersion, stat.NextVersion, stat.NextVersionRound, stat.NextVersionSupported)
+	status := fmt.Sprintf(infoNodeStatus, stat.LastRound, lastRoundTime, catchupTime, stat.LastVersion, stat.NextVersion, stat.NextVersionRound, stat.NextVersionSupported)
+	if stat.CatchupTime > 0 {
+		status += "\n" + fmt.Sprintf(infoNodeCatchupProgress, stat.CatchupBlocks, stat.CatchupBlocksPerSecond)
+		for _, source := range stat.CatchupSources {
+			status += "\n" + fmt.Sprintf(infoNodeCatchupSource, source.Address, source.Blocks)
+		}
+	}
+	return status
 }
 
 var lastroundCmd = &cobra.Command{
@@ -284,7 +462,9 @@ var lastroundCmd = &cobra.Command{
 				reportErrorf(errorNodeStatus, err)
 			}
 
-			reportInfof("%d\n", round)
+			reportResult(map[string]uint64{"round": round}, fmt.Sprintf("%d", round), func() {
+				fmt.Println(round)
+			})
 		})
 	},
 }
@@ -313,24 +493,37 @@ var cloneCmd = &cobra.Command{
 var pendingTxnsCmd = &cobra.Command{
 	Use:   "pendingtxns",
 	Short: "Get a snapshot of current pending transactions on this node",
-	Long:  `Get a snapshot of current pending transactions on this node, cut off at MAX transactions (-m), default 0. If MAX=0, fetches as many transactions as possible.`,
+	Long:  `Get a snapshot of current pending transactions on this node, cut off at MAX transactions (-m), default 0. If MAX=0, fetches as many transactions as possible. With --sender (-s), only the transactions of that address are fetched, along with their position in the pool.`,
 	Args:  validateNoPosArgsFn,
 	Run: func(cmd *cobra.Command, _ []string) {
 		onDataDirs(func(dataDir string) {
 			client := ensureAlgodClient(dataDir)
-			statusTxnPool, err := client.GetPendingTransactions(maxPendingTransactions)
-			if err != nil {
-				reportErrorf(errorNodeStatus, err)
+			var pendingTxns models.TransactionList
+			var positions []uint64
+			if pendingTxnsSender != "" {
+				senderTxnPool, err := client.SenderPendingTransactions(pendingTxnsSender, maxPendingTransactions)
+				if err != nil {
+					reportErrorf(errorNodeStatus, err)
+				}
+				pendingTxns, positions = senderTxnPool.TruncatedTxns, senderTxnPool.Positions
+				reportInfof(infoNodeSenderPendingTxnsDescription, pendingTxnsSender, maxPendingTransactions, senderTxnPool.SenderTxns, senderTxnPool.SenderLimit, senderTxnPool.TotalTxns)
+			} else {
+				statusTxnPool, err := client.GetPendingTransactions(maxPendingTransactions)
+				if err != nil {
+					reportErrorf(errorNodeStatus, err)
+				}
+				pendingTxns = statusTxnPool.TruncatedTxns
+				// do this inline for now, break it out when we need to reuse a Txn->String function
+				reportInfof(infoNodePendingTxnsDescription, maxPendingTransactions, statusTxnPool.TotalTxns)
 			}
 
-			pendingTxns := statusTxnPool.TruncatedTxns
-
-			// do this inline for now, break it out when we need to reuse a Txn->String function
-			reportInfof(infoNodePendingTxnsDescription, maxPendingTransactions, statusTxnPool.TotalTxns)
 			if pendingTxns.Transactions == nil || len(pendingTxns.Transactions) == 0 {
 				reportInfof(infoNodeNoPendingTxnsDescription)
 			} else {
-				for _, pendingTxn := range pendingTxns.Transactions {
+				for i, pendingTxn := range pendingTxns.Transactions {
+					if positions != nil {
+						fmt.Printf("Position %d:\n", positions[i])
+					}
 					pendingTxnStr, err := json.MarshalIndent(pendingTxn, "", "    ")
 					if err != nil {
 						// json parsing of the txn failed, so let's just skip printing it
@@ -374,3 +567,54 @@ var waitCmd = &cobra.Command{
 		}
 	},
 }
+
+var auditLogCmd = &cobra.Command{
+	Use:   "auditlog",
+	Short: "Show the node audit log",
+	Long:  `Show the mutating REST API calls and the startups and shutdowns recorded in the audit log of the node, oldest first. The audit log is read directly from the data directory, so the node doesn't need to be running.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		onDataDirs(func(dataDir string) {
+			entries, err := audit.ReadLog(dataDir)
+			if err != nil {
+				reportErrorf(errorReadingAuditLog, err)
+			}
+			if auditLogLast > 0 && len(entries) > auditLogLast {
+				entries = entries[len(entries)-auditLogLast:]
+			}
+			for _, entry := range entries {
+				if auditLogJSON || report.json() {
+					line, _ := json.Marshal(entry)
+					fmt.Println(string(line))
+					continue
+				}
+				fmt.Println(formatAuditEntry(entry))
+			}
+		})
+	},
+}
+
+func formatAuditEntry(entry audit.Entry) string {
+	line := fmt.Sprintf("%s %s", entry.Time.Local().Format(time.RFC3339), entry.Action)
+	if entry.Status != 0 {
+		line += fmt.Sprintf(" status=%d", entry.Status)
+	}
+	if entry.Source != "" {
+		line += " source=" + entry.Source
+	}
+	if entry.Token != "" {
+		line += " token=" + entry.Token
+	}
+	keys := make([]string, 0, len(entry.Params))
+	for k := range entry.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		line += fmt.Sprintf(" %s=%s", k, entry.Params[k])
+	}
+	if entry.BodySize > 0 {
+		line += fmt.Sprintf(" body=%dB sha256:%s", entry.BodySize, entry.BodyDigest)
+	}
+	return line
+}
@@ -0,0 +1,467 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/nodecontrol"
+	"github.com/algorand/go-algorand/util"
+)
+
+var (
+	serviceName    string
+	serviceUser    bool
+	serviceRunAs   string
+	serviceRestart string
+	serviceNoStart bool
+	serviceForce   bool
+	servicePrint   bool
+)
+
+func init() {
+	nodeCmd.AddCommand(installServiceCmd)
+	nodeCmd.AddCommand(uninstallServiceCmd)
+	nodeCmd.AddCommand(serviceStatusCmd)
+
+	for _, cmd := range []*cobra.Command{installServiceCmd, uninstallServiceCmd, serviceStatusCmd} {
+		cmd.Flags().StringVar(&serviceName, "name", "", "Service name; defaults to algorand- followed by the data directory name")
+		cmd.Flags().BoolVar(&serviceUser, "user", false, "Use a per-user service (systemd --user, or a launchd agent) rather than a system one")
+	}
+	installServiceCmd.Flags().StringVar(&serviceRunAs, "run-as", "", "User running the node of a system service; defaults to the invoking user, or to $SUDO_USER under sudo")
+	installServiceCmd.Flags().StringVar(&serviceRestart, "restart", serviceRestartAlways, "Restart policy, one of always, on-failure or never")
+	installServiceCmd.Flags().BoolVar(&serviceNoStart, "no-start", false, "Only register the service to start on boot, without starting it now")
+	installServiceCmd.Flags().BoolVar(&serviceForce, "force", false, "Overwrite an existing service definition")
+	installServiceCmd.Flags().BoolVar(&servicePrint, "print", false, "Print the service definition rather than installing it")
+}
+
+var installServiceCmd = &cobra.Command{
+	Use:   "install-service",
+	Short: "Run the node as a systemd (Linux) or launchd (macOS) service",
+	Long:  `Writes a systemd unit (Linux) or a launchd plist (macOS) running algod, or algoh for hosted nodes, on the data directory, and registers it so that the node restarts on failure and starts on boot. System services need root; with --user, the service runs in the invoking user's session instead.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		manager := ensureServiceManager()
+		spec := makeServiceSpec(dataDir)
+		definition := manager.render(spec)
+		if servicePrint {
+			fmt.Print(definition)
+			return
+		}
+
+		path := manager.path(spec)
+		if util.FileExists(path) && !serviceForce {
+			reportErrorf(errorServiceExists, path)
+		}
+		if !serviceNoStart && nodeRunning(dataDir) {
+			reportErrorln(errorServiceNodeRunning)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			reportErrorf(errorServiceWrite, path, err)
+		}
+		if err := ioutil.WriteFile(path, []byte(definition), 0644); err != nil {
+			reportErrorf(errorServiceWrite, path, err)
+		}
+		reportInfof(infoServiceInstalled, manager.name, spec.Name, path)
+
+		for _, args := range manager.install(spec, !serviceNoStart) {
+			if err := runServiceCommand(args); err != nil {
+				reportErrorln(err)
+			}
+		}
+		if !serviceNoStart {
+			reportInfof(infoServiceStarted, spec.Name)
+		}
+	},
+}
+
+var uninstallServiceCmd = &cobra.Command{
+	Use:   "uninstall-service",
+	Short: "Stop the node service and remove its definition",
+	Long:  `Stops the service installed by install-service, disables it from starting on boot, and removes its systemd unit or launchd plist. The data directory is left untouched.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		manager := ensureServiceManager()
+		spec := makeServiceSpec(dataDir)
+		path := manager.path(spec)
+		if !util.FileExists(path) {
+			reportErrorf(errorServiceNotInstalled, spec.Name, path)
+		}
+
+		// Keep going when the service isn't loaded, so that a broken service can always be removed
+		for _, args := range manager.stop(spec) {
+			if err := runServiceCommand(args); err != nil {
+				reportWarnf("%v", err)
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			reportErrorf(errorServiceWrite, path, err)
+		}
+		for _, args := range manager.cleanup(spec) {
+			if err := runServiceCommand(args); err != nil {
+				reportWarnf("%v", err)
+			}
+		}
+		reportInfof(infoServiceUninstalled, manager.name, spec.Name)
+	},
+}
+
+var serviceStatusCmd = &cobra.Command{
+	Use:   "service-status",
+	Short: "Report whether the node service is installed, enabled and running",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		manager := ensureServiceManager()
+		spec := makeServiceSpec(dataDir)
+		status := serviceStatus{Manager: manager.name, Name: spec.Name, File: manager.path(spec)}
+		status.Installed = util.FileExists(status.File)
+		if status.Installed {
+			status.Enabled, status.Active = manager.status(spec)
+		}
+		reportResult(status, fmt.Sprintf("%v", status.Active), func() {
+			fmt.Printf(infoServiceStatus+"\n", status.Manager, status.Name, status.File, status.Installed, status.Enabled, status.Active)
+		})
+	},
+}
+
+const (
+	serviceRestartAlways    = "always"
+	serviceRestartOnFailure = "on-failure"
+	serviceRestartNever     = "never"
+)
+
+// serviceSpec describes the service running the node of a data directory
+type serviceSpec struct {
+	Name    string
+	DataDir string
+	// Args is the command line of the service, the algod or algoh binary followed by its arguments
+	Args    []string
+	User    bool
+	RunAs   string
+	Group   string
+	Restart string
+	Home    string
+}
+
+// serviceStatus is what `goal node service-status` reports
+type serviceStatus struct {
+	Manager   string `json:"manager"`
+	Name      string `json:"name"`
+	File      string `json:"file"`
+	Installed bool   `json:"installed"`
+	Enabled   bool   `json:"enabled"`
+	Active    bool   `json:"active"`
+}
+
+// serviceManager abstracts over systemd and launchd; the command lines it
+// returns are run in order.
+type serviceManager struct {
+	name    string
+	path    func(spec serviceSpec) string
+	render  func(spec serviceSpec) string
+	install func(spec serviceSpec, start bool) [][]string
+	stop    func(spec serviceSpec) [][]string
+	cleanup func(spec serviceSpec) [][]string
+	status  func(spec serviceSpec) (enabled bool, active bool)
+}
+
+// serviceManagerFor returns the service manager of the given GOOS, if it has one
+func serviceManagerFor(goos string) (serviceManager, bool) {
+	switch goos {
+	case "linux":
+		return systemdManager, true
+	case "darwin":
+		return launchdManager, true
+	default:
+		return serviceManager{}, false
+	}
+}
+
+func ensureServiceManager() serviceManager {
+	manager, ok := serviceManagerFor(runtime.GOOS)
+	if !ok {
+		reportErrorf(errorServiceUnsupported, runtime.GOOS)
+	}
+	return manager
+}
+
+// makeServiceSpec builds the service of the data directory from the command line flags
+func makeServiceSpec(dataDir string) serviceSpec {
+	absDataDir, err := filepath.Abs(dataDir)
+	if err != nil {
+		reportErrorf(errorServiceWrite, dataDir, err)
+	}
+	binDir, err := util.ExeDir()
+	if err != nil {
+		reportErrorln(err)
+	}
+	switch serviceRestart {
+	case serviceRestartAlways, serviceRestartOnFailure, serviceRestartNever:
+	default:
+		reportErrorf(errorServiceRestart, serviceRestart)
+	}
+
+	spec := serviceSpec{
+		Name:    serviceName,
+		DataDir: absDataDir,
+		Args:    []string{filepath.Join(binDir, "algod"), "-d", absDataDir},
+		User:    serviceUser,
+		Restart: serviceRestart,
+	}
+	if spec.Name == "" {
+		spec.Name = defaultServiceName(absDataDir)
+	}
+	if getRunHostedConfigFlag(dataDir) {
+		spec.Args[0] = filepath.Join(binDir, "algoh")
+	}
+
+	if spec.Home, err = os.UserHomeDir(); err != nil {
+		reportErrorln(err)
+	}
+	if !spec.User {
+		spec.RunAs = serviceRunAs
+		if spec.RunAs == "" {
+			current, err := user.Current()
+			if err != nil {
+				reportErrorln(err)
+			}
+			spec.RunAs = current.Username
+			if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
+				spec.RunAs = sudoUser
+			}
+		}
+		runAs, err := user.Lookup(spec.RunAs)
+		if err != nil {
+			reportErrorln(err)
+		}
+		if group, err := user.LookupGroupId(runAs.Gid); err == nil {
+			spec.Group = group.Name
+		}
+	}
+	return spec
+}
+
+// defaultServiceName derives the service name from the data directory name
+func defaultServiceName(dataDir string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, filepath.Base(dataDir))
+	return "algorand-" + strings.Trim(name, "-.")
+}
+
+// nodeRunning returns whether algod runs on the data directory and answers
+func nodeRunning(dataDir string) bool {
+	binDir, err := util.ExeDir()
+	if err != nil {
+		return false
+	}
+	nc := nodecontrol.MakeNodeController(binDir, dataDir)
+	if _, err := nc.GetAlgodPID(); err != nil {
+		return false
+	}
+	client, err := nc.AlgodClient()
+	return err == nil && client.HealthCheck() == nil
+}
+
+func runServiceCommand(args []string) error {
+	reportVerbosef("Running %s", strings.Join(args, " "))
+	output, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf(errorServiceCommand, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+var systemdManager = serviceManager{
+	name: "systemd",
+	path: func(spec serviceSpec) string {
+		if spec.User {
+			return filepath.Join(spec.Home, ".config", "systemd", "user", spec.Name+".service")
+		}
+		return filepath.Join("/etc", "systemd", "system", spec.Name+".service")
+	},
+	render: renderSystemdUnit,
+	install: func(spec serviceSpec, start bool) [][]string {
+		enable := append(systemctl(spec), "enable")
+		if start {
+			enable = append(enable, "--now")
+		}
+		return [][]string{append(systemctl(spec), "daemon-reload"), append(enable, spec.Name+".service")}
+	},
+	stop: func(spec serviceSpec) [][]string {
+		return [][]string{append(systemctl(spec), "disable", "--now", spec.Name+".service")}
+	},
+	cleanup: func(spec serviceSpec) [][]string {
+		return [][]string{append(systemctl(spec), "daemon-reload")}
+	},
+	status: func(spec serviceSpec) (enabled bool, active bool) {
+		// is-enabled and is-active exit with a non-zero status when the answer is no
+		enabled = exec.Command(systemctl(spec)[0], append(systemctl(spec)[1:], "is-enabled", "--quiet", spec.Name+".service")...).Run() == nil
+		active = exec.Command(systemctl(spec)[0], append(systemctl(spec)[1:], "is-active", "--quiet", spec.Name+".service")...).Run() == nil
+		return
+	},
+}
+
+func systemctl(spec serviceSpec) []string {
+	if spec.User {
+		return []string{"systemctl", "--user"}
+	}
+	return []string{"systemctl"}
+}
+
+// systemdQuote quotes an ExecStart argument when it has spaces or quotes
+func systemdQuote(arg string) string {
+	if !strings.ContainsAny(arg, " \t\"'\\") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+func renderSystemdUnit(spec serviceSpec) string {
+	args := make([]string, len(spec.Args))
+	for i, arg := range spec.Args {
+		args[i] = systemdQuote(arg)
+	}
+	restart := map[string]string{serviceRestartAlways: "always", serviceRestartOnFailure: "on-failure", serviceRestartNever: "no"}[spec.Restart]
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Generated by goal node install-service; remove with goal node uninstall-service\n")
+	fmt.Fprintf(&b, "[Unit]\n")
+	fmt.Fprintf(&b, "Description=Algorand node on %s\n", spec.DataDir)
+	fmt.Fprintf(&b, "Wants=network-online.target\n")
+	fmt.Fprintf(&b, "After=network-online.target\n")
+	fmt.Fprintf(&b, "AssertPathExists=%s\n", spec.DataDir)
+	fmt.Fprintf(&b, "\n[Service]\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(args, " "))
+	if !spec.User {
+		fmt.Fprintf(&b, "User=%s\n", spec.RunAs)
+		if spec.Group != "" {
+			fmt.Fprintf(&b, "Group=%s\n", spec.Group)
+		}
+	}
+	fmt.Fprintf(&b, "Restart=%s\n", restart)
+	fmt.Fprintf(&b, "RestartSec=5s\n")
+	fmt.Fprintf(&b, "LimitNOFILE=65536\n")
+	fmt.Fprintf(&b, "\n[Install]\n")
+	if spec.User {
+		fmt.Fprintf(&b, "WantedBy=default.target\n")
+	} else {
+		fmt.Fprintf(&b, "WantedBy=multi-user.target\n")
+	}
+	return b.String()
+}
+
+var launchdManager = serviceManager{
+	name:   "launchd",
+	path:   launchdPath,
+	render: renderLaunchdPlist,
+	install: func(spec serviceSpec, start bool) [][]string {
+		// A loaded job starts right away, since it runs at load; unloaded ones load on the next boot or login
+		if !start {
+			return nil
+		}
+		return [][]string{{"launchctl", "load", "-w", launchdPath(spec)}}
+	},
+	stop: func(spec serviceSpec) [][]string {
+		return [][]string{{"launchctl", "unload", "-w", launchdPath(spec)}}
+	},
+	cleanup: func(spec serviceSpec) [][]string {
+		return nil
+	},
+	status: func(spec serviceSpec) (enabled bool, active bool) {
+		// launchctl list prints the job as a dictionary with a PID entry while it runs
+		output, err := exec.Command("launchctl", "list", launchdLabel(spec)).Output()
+		if err != nil {
+			return true, false
+		}
+		return true, strings.Contains(string(output), `"PID"`)
+	},
+}
+
+func launchdLabel(spec serviceSpec) string {
+	return "com.algorand." + spec.Name
+}
+
+func launchdPath(spec serviceSpec) string {
+	if spec.User {
+		return filepath.Join(spec.Home, "Library", "LaunchAgents", launchdLabel(spec)+".plist")
+	}
+	return filepath.Join("/Library", "LaunchDaemons", launchdLabel(spec)+".plist")
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func renderLaunchdPlist(spec serviceSpec) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(&b, "<!DOCTYPE plist PUBLIC \"-//Apple//DTD PLIST 1.0//EN\" \"http://www.apple.com/DTDs/PropertyList-1.0.dtd\">\n")
+	fmt.Fprintf(&b, "<!-- Generated by goal node install-service; remove with goal node uninstall-service -->\n")
+	fmt.Fprintf(&b, "<plist version=\"1.0\">\n<dict>\n")
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", xmlEscape(launchdLabel(spec)))
+	fmt.Fprintf(&b, "\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range spec.Args {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	fmt.Fprintf(&b, "\t</array>\n")
+	if !spec.User {
+		fmt.Fprintf(&b, "\t<key>UserName</key>\n\t<string>%s</string>\n", xmlEscape(spec.RunAs))
+		if spec.Group != "" {
+			fmt.Fprintf(&b, "\t<key>GroupName</key>\n\t<string>%s</string>\n", xmlEscape(spec.Group))
+		}
+	}
+	fmt.Fprintf(&b, "\t<key>WorkingDirectory</key>\n\t<string>%s</string>\n", xmlEscape(spec.DataDir))
+	fmt.Fprintf(&b, "\t<key>RunAtLoad</key>\n\t<true/>\n")
+	switch spec.Restart {
+	case serviceRestartAlways:
+		fmt.Fprintf(&b, "\t<key>KeepAlive</key>\n\t<true/>\n")
+	case serviceRestartOnFailure:
+		fmt.Fprintf(&b, "\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	default:
+		fmt.Fprintf(&b, "\t<key>KeepAlive</key>\n\t<false/>\n")
+	}
+	fmt.Fprintf(&b, "\t<key>ThrottleInterval</key>\n\t<integer>5</integer>\n")
+	fmt.Fprintf(&b, "\t<key>SoftResourceLimits</key>\n\t<dict>\n\t\t<key>NumberOfFiles</key>\n\t\t<integer>65536</integer>\n\t</dict>\n")
+	fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", xmlEscape(filepath.Join(spec.DataDir, "algod-out.log")))
+	fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", xmlEscape(filepath.Join(spec.DataDir, "algod-err.log")))
+	fmt.Fprintf(&b, "</dict>\n</plist>\n")
+	return b.String()
+}
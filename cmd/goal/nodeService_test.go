@@ -0,0 +1,95 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultServiceName(t *testing.T) {
+	require.Equal(t, "algorand-mainnet", defaultServiceName("/var/lib/algorand/mainnet"))
+	require.Equal(t, "algorand-my-node_1", defaultServiceName("/home/algo/My Node_1"))
+	require.Equal(t, "algorand-data", defaultServiceName("/home/algo/.data"))
+}
+
+func TestRenderSystemdUnit(t *testing.T) {
+	spec := serviceSpec{
+		Name:    "algorand-mainnet",
+		DataDir: "/var/lib/algorand/main net",
+		Args:    []string{"/usr/bin/algod", "-d", "/var/lib/algorand/main net"},
+		RunAs:   "algorand",
+		Group:   "algorand",
+		Restart: serviceRestartOnFailure,
+		Home:    "/home/algo",
+	}
+	unit := renderSystemdUnit(spec)
+	require.Contains(t, unit, "ExecStart=/usr/bin/algod -d \"/var/lib/algorand/main net\"\n")
+	require.Contains(t, unit, "User=algorand\nGroup=algorand\n")
+	require.Contains(t, unit, "Restart=on-failure\n")
+	require.Contains(t, unit, "WantedBy=multi-user.target\n")
+	require.Equal(t, "/etc/systemd/system/algorand-mainnet.service", systemdManager.path(spec))
+	require.Equal(t, [][]string{{"systemctl", "daemon-reload"}, {"systemctl", "enable", "--now", "algorand-mainnet.service"}}, systemdManager.install(spec, true))
+
+	spec.User = true
+	spec.Restart = serviceRestartNever
+	unit = renderSystemdUnit(spec)
+	require.NotContains(t, unit, "User=")
+	require.Contains(t, unit, "Restart=no\n")
+	require.Contains(t, unit, "WantedBy=default.target\n")
+	require.Equal(t, "/home/algo/.config/systemd/user/algorand-mainnet.service", systemdManager.path(spec))
+	require.Equal(t, [][]string{{"systemctl", "--user", "daemon-reload"}, {"systemctl", "--user", "enable", "algorand-mainnet.service"}}, systemdManager.install(spec, false))
+}
+
+func TestRenderLaunchdPlist(t *testing.T) {
+	spec := serviceSpec{
+		Name:    "algorand-mainnet",
+		DataDir: "/Users/algo/R&D",
+		Args:    []string{"/usr/local/bin/algoh", "-d", "/Users/algo/R&D"},
+		RunAs:   "algo",
+		Group:   "staff",
+		Restart: serviceRestartAlways,
+		Home:    "/Users/algo",
+	}
+	plist := renderLaunchdPlist(spec)
+	require.Contains(t, plist, "<string>com.algorand.algorand-mainnet</string>")
+	require.Contains(t, plist, "\t\t<string>/usr/local/bin/algoh</string>\n\t\t<string>-d</string>\n\t\t<string>/Users/algo/R&amp;D</string>\n")
+	require.Contains(t, plist, "<key>UserName</key>\n\t<string>algo</string>")
+	require.Contains(t, plist, "<key>KeepAlive</key>\n\t<true/>")
+	require.Equal(t, "/Library/LaunchDaemons/com.algorand.algorand-mainnet.plist", launchdManager.path(spec))
+
+	spec.User = true
+	spec.Restart = serviceRestartOnFailure
+	plist = renderLaunchdPlist(spec)
+	require.False(t, strings.Contains(plist, "UserName"))
+	require.Contains(t, plist, "<key>SuccessfulExit</key>\n\t\t<false/>")
+	require.Equal(t, "/Users/algo/Library/LaunchAgents/com.algorand.algorand-mainnet.plist", launchdManager.path(spec))
+	require.Empty(t, launchdManager.install(spec, false))
+}
+
+func TestServiceManagerFor(t *testing.T) {
+	manager, ok := serviceManagerFor("linux")
+	require.True(t, ok)
+	require.Equal(t, "systemd", manager.name)
+	manager, ok = serviceManagerFor("darwin")
+	require.True(t, ok)
+	require.Equal(t, "launchd", manager.name)
+	_, ok = serviceManagerFor("windows")
+	require.False(t, ok)
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	poolSender  string
+	poolMaxTxns uint64
+)
+
+func init() {
+	clerkCmd.AddCommand(poolCmd)
+	poolCmd.AddCommand(poolListCmd)
+	poolCmd.AddCommand(poolDropCmd)
+
+	poolListCmd.Flags().StringVarP(&poolSender, "sender", "s", "", "Only list the transactions sent by this address")
+	poolListCmd.Flags().Uint64VarP(&poolMaxTxns, "max", "m", 0, "Cap the number of transactions to list, 0 for all of them")
+}
+
+var poolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Inspect and manage the transaction pool of the node",
+	Long:  `Inspect the transactions pending in the transaction pool of the node, and evict the ones stuck in it.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		cmd.HelpFunc()(cmd, nil)
+	},
+}
+
+var poolListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the transactions pending in the transaction pool",
+	Long: `List the transactions pending in the transaction pool of the node, or only the ones of --sender, in the order the node considers them for the next block: by decreasing fee per byte, and by arrival among equal fees per byte.
+Every transaction is shown with its position in that order, its fee and fee per byte, and whether it was submitted through this node, which keeps rebroadcasting it.`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+		pool, err := client.PoolTransactions(poolSender, poolMaxTxns)
+		if err != nil {
+			reportErrorf(errorNodeStatus, err)
+		}
+
+		columns := []string{"TXID", "POSITION", "SENDER", "TYPE", "FEE", "SIZE", "FEE/BYTE", "LASTVALID", "LOCAL"}
+		rows := make([][]string, len(pool.Transactions))
+		for i, txn := range pool.Transactions {
+			rows[i] = []string{
+				txn.TxID,
+				strconv.FormatUint(txn.Position, 10),
+				txn.Sender,
+				txn.Type,
+				strconv.FormatUint(txn.Fee, 10),
+				strconv.FormatUint(txn.Size, 10),
+				strconv.FormatUint(txn.FeePerByte, 10),
+				strconv.FormatUint(txn.LastValid, 10),
+				strconv.FormatBool(txn.Local),
+			}
+		}
+
+		reportRows(pool, columns, rows, func() {
+			if len(pool.Transactions) == 0 {
+				reportInfof(infoPoolEmpty, pool.TotalTxns)
+				return
+			}
+			reportInfof(infoPoolListed, len(pool.Transactions), pool.TotalTxns)
+			for _, txn := range pool.Transactions {
+				local := ""
+				if txn.Local {
+					local = ", local"
+				}
+				group := ""
+				if txn.Group != "" {
+					group = ", group " + txn.Group
+				}
+				fmt.Printf("%d: %s %s from %s, fee %d microAlgos for %d bytes (%d/byte), valid until round %d%s%s\n",
+					txn.Position, txn.TxID, txn.Type, txn.Sender, txn.Fee, txn.Size, txn.FeePerByte, txn.LastValid, group, local)
+			}
+		})
+	},
+}
+
+var poolDropCmd = &cobra.Command{
+	Use:   "drop [txid]",
+	Short: "Evict a transaction from the transaction pool",
+	Long: `Evict a stuck transaction from the transaction pool of the node, along with the rest of its group, and stop rebroadcasting them if they were submitted through this node.
+This only affects this node: the transactions may still be committed if other nodes hold them, until their last valid round passes.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		txid := args[0]
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+		dropped, err := client.DropPoolTransaction(txid)
+		if err != nil {
+			reportErrorf(errorPoolDrop, txid, err)
+		}
+
+		reportResult(dropped, "", func() {
+			for _, id := range dropped.TxIDs {
+				reportInfof(infoPoolDropped, id)
+			}
+		})
+	},
+}
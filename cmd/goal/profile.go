@@ -0,0 +1,224 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/util"
+)
+
+var (
+	profileName       string
+	profileWallet     string
+	profileAccount    string
+	profileAlgodURL   string
+	profileAlgodToken string
+	profileUseNone    bool
+
+	// activeProfile is the profile applied to the running command, if any
+	activeProfile *Profile
+)
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Name of the profile whose data directory, wallet, default account and algod endpoint to use")
+
+	profileCmd.AddCommand(profileAddCmd)
+	profileCmd.AddCommand(profileDeleteCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileListCmd)
+
+	profileAddCmd.Flags().StringVarP(&profileWallet, "wallet", "w", "", "Wallet to use; defaults to the default wallet of the data directory")
+	profileAddCmd.Flags().StringVarP(&profileAccount, "account", "a", "", "Name or address of the account to use when a command needs one and none is given")
+	profileAddCmd.Flags().StringVar(&profileAlgodURL, "algod-url", "", "URL of the algod to talk to, instead of the one running on the data directory")
+	profileAddCmd.Flags().StringVar(&profileAlgodToken, "algod-token", "", "API token of the algod at --algod-url")
+	profileUseCmd.Flags().BoolVar(&profileUseNone, "none", false, "Deactivate profiles, so that goal goes back to using -d, ALGORAND_DATA or the active network")
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named profiles of goal settings",
+	Long: `Manage profiles, which each name a data directory along with the wallet, default account and algod endpoint to use with it. Select a profile for a single command with --profile, or for all commands with 'goal profile use'.
+
+The settings of the selected profile apply unless the command line overrides them: -d replaces the data directory, and -w the wallet. ALGORAND_DATA also takes precedence over the active profile, though not over one given with --profile.`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		//Fall back
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Save a profile",
+	Long:  "Save the data directory given with -d, or found in ALGORAND_DATA or the active network, as a profile, with the wallet, default account and algod endpoint given. An existing profile with the same name is replaced.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if profileAlgodToken != "" && profileAlgodURL == "" {
+			reportErrorln(errorProfileTokenWithoutURL)
+		}
+		dataDir, err := filepath.Abs(ensureSingleDataDir())
+		if err != nil || !util.IsDir(dataDir) {
+			reportErrorf(errorDirectoryNotExist, dataDir)
+		}
+
+		registry := ensureProfileRegistry()
+		registry.Profiles[args[0]] = &Profile{
+			DataDir:    dataDir,
+			Wallet:     profileWallet,
+			Account:    profileAccount,
+			AlgodURL:   profileAlgodURL,
+			AlgodToken: profileAlgodToken,
+		}
+		saveProfileRegistry(registry)
+		reportInfof(infoProfileSaved, args[0], dataDir)
+	},
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a profile",
+	Long:  "Delete a profile. The data directory, wallet and accounts it names are left untouched.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		registry := ensureProfileRegistry()
+		if err := registry.remove(args[0]); err != nil {
+			reportErrorln(err)
+		}
+		saveProfileRegistry(registry)
+		reportInfof(infoProfileDeleted, args[0])
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Select the profile goal works with",
+	Long:  "Make goal apply the settings of a profile to every command that doesn't select one with --profile. With --none, no profile applies any more.",
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if profileUseNone == (len(args) == 1) {
+			reportErrorln(errorProfileUseArgs)
+		}
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+
+		registry := ensureProfileRegistry()
+		if err := registry.use(name); err != nil {
+			reportErrorln(err)
+		}
+		saveProfileRegistry(registry)
+		if name == "" {
+			reportInfoln(infoNoActiveProfile)
+		} else {
+			reportInfof(infoProfileInUse, name)
+		}
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the saved profiles",
+	Long:  "List the saved profiles and their settings. The active profile is marked with a *. The algod API tokens are not shown.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		registry := ensureProfileRegistry()
+		names := registry.names()
+		listed := make(map[string]Profile, len(names))
+		rows := make([][]string, 0, len(names))
+		for _, name := range names {
+			profile := *registry.Profiles[name]
+			profile.AlgodToken = ""
+			listed[name] = profile
+			rows = append(rows, []string{activeMark(name == registry.Active) + name, profile.DataDir, profile.Wallet, profile.Account, profile.AlgodURL})
+		}
+		reportRows(listed, []string{"NAME", "DATA DIRECTORY", "WALLET", "ACCOUNT", "ALGOD"}, rows, func() {
+			if len(names) == 0 {
+				reportInfoln(infoNoProfiles)
+				return
+			}
+			for _, row := range rows {
+				fmt.Printf("%s\t%s\n", row[0], row[1])
+				for i, label := range []string{"Wallet", "Account", "Algod"} {
+					if row[i+2] != "" {
+						fmt.Printf("    %s:\t%s\n", label, row[i+2])
+					}
+				}
+			}
+		})
+	},
+}
+
+// applyProfile makes the selected profile, if any, provide the settings the command line doesn't give. It
+// runs before any command, but leaves the profile commands alone so that they manage profiles rather than
+// use them.
+func applyProfile(cmd *cobra.Command) {
+	if cmd == profileCmd || cmd.Parent() == profileCmd {
+		return
+	}
+	registry, err := loadProfileRegistry()
+	if err != nil {
+		reportErrorf(errorLoadingProfiles, err)
+	}
+	name, profile, explicit, err := registry.selected(profileName)
+	if err != nil {
+		reportErrorln(err)
+	}
+	if profile == nil {
+		return
+	}
+	reportVerbosef(infoUsingProfile, name)
+	activeProfile = profile
+
+	if !cmd.Flags().Changed("datadir") && (explicit || os.Getenv("ALGORAND_DATA") == "") {
+		dataDirs = []string{profile.DataDir}
+	}
+	if walletName == "" {
+		walletName = profile.Wallet
+	}
+}
+
+// profileAlgodEndpoint returns the algod endpoint the active profile sets for the data directory, if any
+func profileAlgodEndpoint(dataDir string) (url, token string) {
+	if activeProfile == nil || activeProfile.AlgodURL == "" {
+		return "", ""
+	}
+	if dir, err := filepath.Abs(dataDir); err != nil || dir != activeProfile.DataDir {
+		return "", ""
+	}
+	return activeProfile.AlgodURL, activeProfile.AlgodToken
+}
+
+func ensureProfileRegistry() *ProfileRegistry {
+	registry, err := loadProfileRegistry()
+	if err != nil {
+		reportErrorf(errorLoadingProfiles, err)
+	}
+	return registry
+}
+
+func saveProfileRegistry(registry *ProfileRegistry) {
+	if err := registry.save(); err != nil {
+		reportErrorf(errorSavingProfiles, err)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/algorand/go-algorand/config"
+)
+
+// profileRegistryFilename is the name of the profile registry file, in the global config directory (~/.algorand).
+// It may hold algod API tokens, so only the user can read it.
+const profileRegistryFilename = "goal-profiles.json"
+
+// ProfileRegistry maps profile names to the settings goal applies when the profile is selected, with --profile or
+// by making it the active one.
+type ProfileRegistry struct {
+	Active   string
+	Profiles map[string]*Profile
+}
+
+// Profile is a data directory along with the wallet, default account and algod endpoint to use with it. The
+// wallet, account and endpoint are optional.
+type Profile struct {
+	DataDir    string
+	Wallet     string `json:",omitempty"`
+	Account    string `json:",omitempty"`
+	AlgodURL   string `json:",omitempty"`
+	AlgodToken string `json:",omitempty"`
+}
+
+func profileRegistryPath() (string, error) {
+	return config.GetConfigFilePath(profileRegistryFilename)
+}
+
+// loadProfileRegistry loads the profile registry, returning an empty one if it doesn't exist yet
+func loadProfileRegistry() (*ProfileRegistry, error) {
+	registry := &ProfileRegistry{Profiles: map[string]*Profile{}}
+	filename, err := profileRegistryPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, registry); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %v", filename, err)
+	}
+	if registry.Profiles == nil {
+		registry.Profiles = map[string]*Profile{}
+	}
+	return registry, nil
+}
+
+func (registry *ProfileRegistry) save() error {
+	filename, err := profileRegistryPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0600)
+}
+
+// remove deletes the named profile, deactivating it if it was the active one
+func (registry *ProfileRegistry) remove(name string) error {
+	if _, ok := registry.Profiles[name]; !ok {
+		return fmt.Errorf(errorProfileNotFound, name)
+	}
+	delete(registry.Profiles, name)
+	if registry.Active == name {
+		registry.Active = ""
+	}
+	return nil
+}
+
+// use makes the named profile the active one, or deactivates profiles if name is empty
+func (registry *ProfileRegistry) use(name string) error {
+	if _, ok := registry.Profiles[name]; !ok && name != "" {
+		return fmt.Errorf(errorProfileNotFound, name)
+	}
+	registry.Active = name
+	return nil
+}
+
+// selected returns the profile named by --profile, or else the active one. The second result tells whether the
+// profile was named explicitly.
+func (registry *ProfileRegistry) selected(flag string) (name string, profile *Profile, explicit bool, err error) {
+	name, explicit = flag, flag != ""
+	if !explicit {
+		name = registry.Active
+	}
+	if name == "" {
+		return "", nil, false, nil
+	}
+	profile, ok := registry.Profiles[name]
+	if !ok {
+		return "", nil, false, fmt.Errorf(errorProfileNotFound, name)
+	}
+	return name, profile, explicit, nil
+}
+
+func (registry *ProfileRegistry) names() []string {
+	names := make([]string, 0, len(registry.Profiles))
+	for name := range registry.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+)
+
+func TestProfileRegistry(t *testing.T) {
+	a := require.New(t)
+
+	tempDir, err := ioutil.TempDir("", "goal-profiles")
+	a.NoError(err)
+	defer os.RemoveAll(tempDir)
+	defer config.SetGlobalConfigFileRoot(config.SetGlobalConfigFileRoot(tempDir))
+
+	registry, err := loadProfileRegistry()
+	a.NoError(err)
+	_, profile, _, err := registry.selected("")
+	a.NoError(err)
+	a.Nil(profile)
+
+	registry.Profiles["node"] = &Profile{DataDir: "/var/lib/algorand", Wallet: "hot", Account: "main"}
+	registry.Profiles["remote"] = &Profile{DataDir: "/tmp/remote", AlgodURL: "http://10.0.0.1:8080", AlgodToken: "secret"}
+	a.Error(registry.use("nosuchprofile"))
+	a.NoError(registry.use("node"))
+	a.NoError(registry.save())
+
+	registry, err = loadProfileRegistry()
+	a.NoError(err)
+	a.Equal([]string{"node", "remote"}, registry.names())
+	name, profile, explicit, err := registry.selected("")
+	a.NoError(err)
+	a.Equal("node", name)
+	a.False(explicit)
+	a.Equal("hot", profile.Wallet)
+
+	// --profile takes precedence over the active profile
+	name, profile, explicit, err = registry.selected("remote")
+	a.NoError(err)
+	a.Equal("remote", name)
+	a.True(explicit)
+	a.Equal("secret", profile.AlgodToken)
+	_, _, _, err = registry.selected("nosuchprofile")
+	a.Error(err)
+
+	// removing the active profile leaves no profile active
+	a.NoError(registry.remove("node"))
+	a.Empty(registry.Active)
+	a.Equal([]string{"remote"}, registry.names())
+	a.Error(registry.remove("node"))
+
+	a.NoError(registry.use("remote"))
+	a.NoError(registry.use(""))
+	a.Empty(registry.Active)
+}
@@ -0,0 +1,256 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/algorand/go-codec/codec"
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// Output formats accepted by the global --output flag
+const (
+	outputText    = "text"
+	outputJSON    = "json"
+	outputMsgpack = "msgpack"
+	outputTable   = "table"
+)
+
+var outputFormats = []string{outputText, outputJSON, outputMsgpack, outputTable}
+
+// Exit codes shared by every goal command
+const (
+	exitSuccess = 0
+	exitError   = 1
+	exitUsage   = 2
+)
+
+// reporter is the single place goal writes user-facing output through. It
+// honors the global --output, --quiet and --verbose flags so that commands
+// do not need to grow their own output flags to be scriptable.
+//
+// In text mode results and informational messages go to stdout, while
+// warnings, errors and verbose messages go to stderr. In JSON mode every
+// line written to stdout is a JSON value: results are encoded as-is and
+// informational messages are wrapped as {"message": ...}. msgpack mode is
+// the same with every stdout value msgpack encoded instead; stderr stays
+// JSON so that it remains readable in a terminal. Table mode prints lists
+// as aligned columns and everything else like text mode. Quiet mode
+// suppresses informational messages and reduces results to their ID.
+type reporter struct {
+	out    io.Writer
+	errOut io.Writer
+	exit   func(code int)
+
+	format  string
+	quiet   bool
+	verbose bool
+}
+
+var report = &reporter{
+	out:    os.Stdout,
+	errOut: os.Stderr,
+	exit:   os.Exit,
+	format: outputText,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&report.format, "output", outputText, "Output format, one of "+strings.Join(outputFormats, ", "))
+	rootCmd.PersistentFlags().BoolVar(&report.quiet, "quiet", false, "Only print the essential result of a command, such as a transaction or account ID")
+	rootCmd.PersistentFlags().BoolVar(&report.verbose, "verbose", false, "Print additional diagnostic messages to stderr")
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if err := report.validate(); err != nil {
+			report.usageError(err)
+		}
+		applyProfile(cmd)
+		resolveAddressFlags(cmd)
+	}
+}
+
+func (r *reporter) validate() error {
+	r.format = strings.ToLower(r.format)
+	for _, format := range outputFormats {
+		if r.format == format {
+			return nil
+		}
+	}
+	return fmt.Errorf(errorOutputFormat, r.format, strings.Join(outputFormats, ", "))
+}
+
+func (r *reporter) json() bool {
+	return r.format == outputJSON
+}
+
+// structured returns whether output is meant for programs rather than
+// people, in which case every value on stdout is encoded.
+func (r *reporter) structured() bool {
+	return r.format == outputJSON || r.format == outputMsgpack
+}
+
+// write encodes v on w in the current structured format.
+func (r *reporter) write(w io.Writer, v interface{}) {
+	if r.format == outputMsgpack && w == r.out {
+		if err := codec.NewEncoder(w, protocol.CodecHandle).Encode(v); err != nil {
+			fmt.Fprintf(r.errOut, errorEncodeOutput+"\n", err)
+			r.exit(exitError)
+		}
+		return
+	}
+	r.writeJSON(w, v)
+}
+
+func (r *reporter) writeJSON(w io.Writer, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(r.errOut, errorEncodeOutput+"\n", err)
+		r.exit(exitError)
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
+
+func (r *reporter) info(msg string) {
+	if r.quiet {
+		return
+	}
+	if r.structured() {
+		r.write(r.out, map[string]string{"message": msg})
+		return
+	}
+	fmt.Fprintln(r.out, msg)
+}
+
+func (r *reporter) warn(msg string) {
+	if r.structured() {
+		r.write(r.errOut, map[string]string{"warning": msg})
+		return
+	}
+	fmt.Fprintln(r.errOut, "Warning: "+msg)
+}
+
+func (r *reporter) debug(msg string) {
+	if !r.verbose {
+		return
+	}
+	if r.structured() {
+		r.write(r.errOut, map[string]string{"debug": msg})
+		return
+	}
+	fmt.Fprintln(r.errOut, msg)
+}
+
+// printError reports an error without exiting, for commands that carry on
+// after a failure and settle the exit code later.
+func (r *reporter) printError(msg string) {
+	if r.structured() {
+		r.write(r.errOut, map[string]string{"error": msg})
+		return
+	}
+	fmt.Fprintln(r.errOut, msg)
+}
+
+func (r *reporter) fail(code int, msg string) {
+	r.printError(msg)
+	r.exit(code)
+}
+
+func (r *reporter) usageError(err error) {
+	r.fail(exitUsage, err.Error())
+}
+
+// result reports the outcome of a command. value is what gets encoded in
+// the structured modes, id is printed on its own in quiet mode (an empty id
+// falls back to the text output), and text prints the human readable form.
+func (r *reporter) result(value interface{}, id string, text func()) {
+	switch {
+	case r.structured():
+		r.write(r.out, value)
+	case r.quiet && id != "":
+		fmt.Fprintln(r.out, id)
+	case text != nil:
+		text()
+	}
+}
+
+// rows reports a list. value is what gets encoded in the structured modes,
+// table mode prints rows as columns under header, quiet mode prints the
+// first column of every row, and text prints the human readable form.
+func (r *reporter) rows(value interface{}, header []string, rows [][]string, text func()) {
+	switch {
+	case r.structured():
+		r.write(r.out, value)
+	case r.quiet:
+		for _, row := range rows {
+			if len(row) > 0 {
+				fmt.Fprintln(r.out, row[0])
+			}
+		}
+	case r.format == outputTable:
+		tw := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		tw.Flush()
+	case text != nil:
+		text()
+	}
+}
+
+func reportInfoln(args ...interface{}) {
+	report.info(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+func reportInfof(format string, args ...interface{}) {
+	report.info(fmt.Sprintf(format, args...))
+}
+
+func reportWarnln(args ...interface{}) {
+	report.warn(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+func reportWarnf(format string, args ...interface{}) {
+	report.warn(fmt.Sprintf(format, args...))
+}
+
+func reportVerbosef(format string, args ...interface{}) {
+	report.debug(fmt.Sprintf(format, args...))
+}
+
+func reportErrorln(args ...interface{}) {
+	report.fail(exitError, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+func reportErrorf(format string, args ...interface{}) {
+	report.fail(exitError, fmt.Sprintf(format, args...))
+}
+
+func reportResult(value interface{}, id string, text func()) {
+	report.result(value, id, text)
+}
+
+func reportRows(value interface{}, header []string, rows [][]string, text func()) {
+	report.rows(value, header, rows, text)
+}
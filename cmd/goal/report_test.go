@@ -0,0 +1,143 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func makeTestReporter(format string, quiet bool) (r *reporter, out, errOut *bytes.Buffer, exitCode *int) {
+	out = &bytes.Buffer{}
+	errOut = &bytes.Buffer{}
+	exitCode = new(int)
+	*exitCode = -1
+	r = &reporter{
+		out:    out,
+		errOut: errOut,
+		exit:   func(code int) { *exitCode = code },
+		format: format,
+		quiet:  quiet,
+	}
+	return
+}
+
+func TestReporterTextMode(t *testing.T) {
+	r, out, errOut, exitCode := makeTestReporter(outputText, false)
+	r.info("hello")
+	r.warn("careful")
+	r.debug("hidden unless verbose")
+	r.result(map[string]int{"a": 1}, "ID", func() { out.WriteString("text result\n") })
+	require.Equal(t, "hello\ntext result\n", out.String())
+	require.Equal(t, "Warning: careful\n", errOut.String())
+
+	r.fail(exitError, "boom")
+	require.Equal(t, exitError, *exitCode)
+	require.Equal(t, "Warning: careful\nboom\n", errOut.String())
+}
+
+func TestReporterQuietMode(t *testing.T) {
+	r, out, errOut, _ := makeTestReporter(outputText, true)
+	r.info("hello")
+	r.result(nil, "TXID", func() { out.WriteString("text result\n") })
+	r.result(nil, "", func() { out.WriteString("no id\n") })
+	require.Equal(t, "TXID\nno id\n", out.String())
+	require.Empty(t, errOut.String())
+}
+
+func TestReporterJSONMode(t *testing.T) {
+	r, out, errOut, exitCode := makeTestReporter(outputJSON, false)
+	r.info("hello")
+	r.result(map[string]int{"a": 1}, "ID", func() { out.WriteString("text result\n") })
+	require.Equal(t, "{\"message\":\"hello\"}\n{\"a\":1}\n", out.String())
+
+	r.fail(exitUsage, "bad flag")
+	require.Equal(t, exitUsage, *exitCode)
+	require.Equal(t, "{\"error\":\"bad flag\"}\n", errOut.String())
+}
+
+func TestReporterValidate(t *testing.T) {
+	r, _, _, _ := makeTestReporter("JSON", false)
+	require.NoError(t, r.validate())
+	require.True(t, r.json())
+
+	r.format = "Table"
+	require.NoError(t, r.validate())
+	require.False(t, r.structured())
+
+	r.format = "msgpack"
+	require.NoError(t, r.validate())
+	require.True(t, r.structured())
+
+	r.format = "yaml"
+	require.Error(t, r.validate())
+}
+
+func TestReporterMsgpackMode(t *testing.T) {
+	r, out, errOut, _ := makeTestReporter(outputMsgpack, false)
+	r.warn("careful")
+	r.result(partkeyListing{File: "a.partkey", FirstValid: 1, LastValid: 2}, "ID", func() { out.WriteString("text result\n") })
+	require.Equal(t, "{\"warning\":\"careful\"}\n", errOut.String())
+
+	var decoded partkeyListing
+	require.NoError(t, protocol.Decode(out.Bytes(), &decoded))
+	require.Equal(t, partkeyListing{File: "a.partkey", FirstValid: 1, LastValid: 2}, decoded)
+
+	var fields map[string]interface{}
+	require.NoError(t, protocol.Decode(out.Bytes(), &fields))
+	require.Contains(t, fields, "file")
+}
+
+func TestReporterRows(t *testing.T) {
+	header := []string{"ADDRESS", "NAME"}
+	rows := [][]string{{"AAAA", "first"}, {"BBBBBBBB", "second"}}
+	value := []map[string]string{{"address": "AAAA"}, {"address": "BBBBBBBB"}}
+	text := func(out *bytes.Buffer) func() {
+		return func() { out.WriteString("text rows\n") }
+	}
+
+	r, out, _, _ := makeTestReporter(outputTable, false)
+	r.rows(value, header, rows, text(out))
+	require.Equal(t, "ADDRESS   NAME\nAAAA      first\nBBBBBBBB  second\n", out.String())
+
+	r, out, _, _ = makeTestReporter(outputTable, true)
+	r.rows(value, header, rows, text(out))
+	require.Equal(t, "AAAA\nBBBBBBBB\n", out.String())
+
+	r, out, _, _ = makeTestReporter(outputText, false)
+	r.rows(value, header, rows, text(out))
+	require.Equal(t, "text rows\n", out.String())
+
+	r, out, _, _ = makeTestReporter(outputJSON, false)
+	r.rows(value, header, rows, text(out))
+	require.Equal(t, "[{\"address\":\"AAAA\"},{\"address\":\"BBBBBBBB\"}]\n", out.String())
+}
+
+func TestListedAccountText(t *testing.T) {
+	amount := uint64(100)
+	account := listedAccount{Address: "ADDR", Name: "main", Status: "online", Amount: &amount, Multisig: &listedMultisig{Threshold: 2, Size: 3}, Default: true}
+	require.Equal(t, "[online]\tmain\tADDR\t100 microAlgos\t[2/3 multisig]\t*Default", account.text())
+	require.Equal(t, []string{"ADDR", "main", "online", "100", "2/3", "*"}, account.row())
+
+	account = listedAccount{Address: "ADDR", Name: "other"}
+	require.Equal(t, "[n/a]\tother\tADDR\t[n/a] microAlgos", account.text())
+	require.Equal(t, []string{"ADDR", "other", "n/a", "n/a", "", ""}, account.row())
+}
@@ -0,0 +1,244 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/libgoal"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var (
+	reservesRound    uint64
+	reservesAddrs    []string
+	reservesAddrFile string
+	reservesFile     string
+	reservesOffline  bool
+)
+
+func init() {
+	ledgerCmd.AddCommand(reservesCmd)
+	reservesCmd.AddCommand(createReservesCmd)
+	reservesCmd.AddCommand(verifyReservesCmd)
+
+	createReservesCmd.Flags().Uint64VarP(&reservesRound, "round", "r", 0, "Round to report the balances at (default the latest round)")
+	createReservesCmd.Flags().StringArrayVarP(&reservesAddrs, "address", "a", nil, "Account to report on, may be repeated")
+	createReservesCmd.Flags().StringVar(&reservesAddrFile, "addrfile", "", "File listing the accounts to report on, one per line")
+	createReservesCmd.Flags().StringVarP(&reservesFile, "out", "o", "", "File to write the signed report to")
+	createReservesCmd.Flags().StringVarP(&walletName, "wallet", "w", "", "Set the wallet holding the keys of the accounts")
+	createReservesCmd.MarkFlagRequired("out")
+
+	verifyReservesCmd.Flags().StringVarP(&reservesFile, "infile", "i", "", "Signed report to verify")
+	verifyReservesCmd.Flags().BoolVar(&reservesOffline, "offline", false, "Only check the signatures and the total, without comparing the report with the node")
+	verifyReservesCmd.MarkFlagRequired("infile")
+}
+
+var reservesCmd = &cobra.Command{
+	Use:   "reserves",
+	Short: "Create and verify signed reports of account balances",
+	Long:  "Create and verify proof of reserves reports: the balances of a set of accounts as of a round, signed by the key of every account to prove control of it.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		// If no arguments passed, we should fallback to help
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+// reservesResult is what goal reports about a reserve report
+type reservesResult struct {
+	Round     uint64 `json:"round"`
+	BlockHash string `json:"blockHash"`
+	Accounts  int    `json:"accounts"`
+	Total     uint64 `json:"total"`
+	// BalancesChecked tells whether the balances were compared with those
+	// of the node
+	BalancesChecked bool `json:"balancesChecked"`
+}
+
+func makeReservesResult(report libgoal.ReserveReport) reservesResult {
+	return reservesResult{
+		Round:     uint64(report.Round),
+		BlockHash: report.BlockHash.String(),
+		Accounts:  len(report.Accounts),
+		Total:     report.Total.Raw,
+	}
+}
+
+// readAddrFile returns the non-empty lines of file
+func readAddrFile(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		reportErrorf(fileReadError, file, err)
+	}
+	defer f.Close()
+
+	var addrs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			addrs = append(addrs, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		reportErrorf(fileReadError, file, err)
+	}
+	return addrs
+}
+
+var createReservesCmd = &cobra.Command{
+	Use:   "create -a ADDR [-a ADDR ...] -o FILE",
+	Short: "Create a signed report of account balances",
+	Long:  "Look up the balances of the given accounts as of a round and sign the report with the key of every account, which must all be in the wallet. The node only holds the balances of its most recent rounds, so the round must be one of them. The report names the block of the round, so that anyone can check it against a node of their own with goal ledger reserves verify.",
+	Example: "goal ledger reserves create -r 1000 -a ADDR1 -a ADDR2 -o reserves.json\n" +
+		"goal ledger reserves create --addrfile hot-wallets.txt -o reserves.json",
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dataDir := ensureSingleDataDir()
+		client := ensureFullClient(dataDir)
+
+		addrs := reservesAddrs
+		if reservesAddrFile != "" {
+			addrs = append(addrs, readAddrFile(reservesAddrFile)...)
+		}
+		if len(addrs) == 0 {
+			reportErrorln(errorReservesNoAccounts)
+		}
+		for i, addr := range addrs {
+			addrs[i] = resolveAccountName(addr)
+		}
+
+		stat, err := client.Status()
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		round := reservesRound
+		if round == 0 {
+			round = stat.LastRound
+		}
+		if round > stat.LastRound {
+			reportErrorf(errorReservesFutureRound, round, stat.LastRound)
+		}
+
+		report, err := client.MakeReserveReport(round, addrs)
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+
+		wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
+		signed, err := client.SignReserveReport(wh, pw, report)
+		if err != nil {
+			reportErrorf(errorReservesSign, err)
+		}
+
+		err = ioutil.WriteFile(reservesFile, protocol.EncodeJSON(signed), 0644)
+		if err != nil {
+			reportErrorf(fileWriteError, reservesFile, err)
+		}
+
+		result := makeReservesResult(report)
+		reportInfof(infoReservesWritten, result.Accounts, result.Total, result.Round, reservesFile)
+		reportResult(result, reservesFile, nil)
+	},
+}
+
+var verifyReservesCmd = &cobra.Command{
+	Use:   "verify -i FILE",
+	Short: "Verify a signed report of account balances",
+	Long:  "Check that a report made with goal ledger reserves create is signed by the key of every account it lists and that its total adds up. Unless --offline is given, also check that the report belongs to the chain of the node, that its block is the one the node has for the round and, if the node still holds the balances of the round, that the balances match.",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		data, err := ioutil.ReadFile(reservesFile)
+		if err != nil {
+			reportErrorf(fileReadError, reservesFile, err)
+		}
+		var signed libgoal.SignedReserveReport
+		err = protocol.DecodeJSON(data, &signed)
+		if err != nil {
+			reportErrorf(errorReservesDecode, reservesFile, err)
+		}
+
+		report := signed.Report
+		if err = signed.Verify(); err != nil {
+			reportErrorf(errorReservesInvalid, err)
+		}
+		result := makeReservesResult(report)
+
+		if !reservesOffline {
+			result.BalancesChecked = checkReservesWithNode(report)
+		}
+
+		reportResult(result, "", func() {
+			reportInfof(infoReservesValid, result.Accounts, result.Total, result.Round, result.BlockHash)
+			if !reservesOffline && !result.BalancesChecked {
+				reportWarnf(warnReservesBalancesUnchecked, result.Round)
+			}
+		})
+	},
+}
+
+// checkReservesWithNode checks report against the node, and reports whether
+// the node could still check its balances
+func checkReservesWithNode(report libgoal.ReserveReport) bool {
+	client := ensureAlgodClient(ensureSingleDataDir())
+
+	params, err := client.SuggestedParams()
+	if err != nil {
+		reportErrorf(errorRequestFail, err)
+	}
+	if params.GenesisID != report.GenesisID || !bytes.Equal(params.GenesisHash, report.GenesisHash[:]) {
+		reportErrorf(errorReservesGenesis, report.GenesisID, params.GenesisID)
+	}
+
+	block, err := client.Block(uint64(report.Round))
+	if err != nil {
+		reportErrorf(errorRequestFail, err)
+	}
+	if block.Hash != report.BlockHash.String() {
+		reportErrorf(errorReservesBlockHash, report.Round, report.BlockHash, block.Hash)
+	}
+
+	addrs := make([]string, len(report.Accounts))
+	for i, acct := range report.Accounts {
+		addrs[i] = acct.Address.GetUserAddress()
+	}
+	balances, err := client.BalancesAtRound(uint64(report.Round), addrs)
+	if err != nil {
+		return false
+	}
+	for i, balance := range balances.Accounts {
+		acct := report.Accounts[i]
+		if balance.Amount != acct.Amount.Raw {
+			reportErrorf(errorReservesBalance, acct.Address.GetUserAddress(), acct.Amount.Raw, balance.Amount)
+		}
+		var authAddr string
+		if acct.AuthAddr != (basics.Address{}) {
+			authAddr = acct.AuthAddr.GetUserAddress()
+		}
+		if balance.AuthAddr != authAddr {
+			reportErrorf(errorReservesSigner, acct.Address.GetUserAddress(), balance.AuthAddr)
+		}
+	}
+	return true
+}
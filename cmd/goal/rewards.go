@@ -0,0 +1,168 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/libgoal"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// rewardsTimingRounds is the number of recent rounds whose block timestamps goal account rewards averages to
+// estimate how many rounds make a day
+const rewardsTimingRounds = 100
+
+// accountRewards is the result of goal account rewards
+type accountRewards struct {
+	Address string `json:"address"`
+	Round   uint64 `json:"round"`
+	// Total is the rewards the account has received since it was created, including the pending ones
+	Total   uint64 `json:"total"`
+	Pending uint64 `json:"pending"`
+	// Rate is the number of microAlgos the rewards pool distributes to all the reward units of the network in the
+	// next round
+	Rate uint64 `json:"rate"`
+	// PerRound and PerDay estimate what the account earns at its current balance and the current rate
+	PerRound float64 `json:"perRound"`
+	PerDay   float64 `json:"perDay"`
+
+	History *rewardsHistory `json:"history,omitempty"`
+}
+
+// rewardsHistory is what an account earned over the last rounds
+type rewardsHistory struct {
+	Rounds uint64 `json:"rounds"`
+	// Accrued estimates the rewards the current balance earned over the rounds, from the rewards level of the blocks
+	Accrued uint64 `json:"accrued"`
+	// Realized is the rewards the transactions of the rounds applied to the account
+	Realized uint64 `json:"realized"`
+}
+
+// estimateRewardsPerRound returns the rewards a balance earns in a round, given the rate at which the rewards pool
+// distributes microAlgos to the reward units of the network and the participating microAlgos
+func estimateRewardsPerRound(balance, rate, participating, rewardUnit uint64) float64 {
+	if rewardUnit == 0 || participating < rewardUnit {
+		return 0
+	}
+	units := balance / rewardUnit
+	return float64(units) * float64(rate) / float64(participating/rewardUnit)
+}
+
+// realizedRewards returns the rewards that the transactions applied to the account
+func realizedRewards(addr string, txns []models.Transaction) (realized uint64) {
+	for _, txn := range txns {
+		if txn.From == addr {
+			realized += txn.FromRewards
+		}
+		if txn.Payment == nil {
+			continue
+		}
+		if txn.Payment.To == addr {
+			realized += txn.Payment.ToRewards
+		}
+		if txn.Payment.CloseRemainderTo == addr {
+			realized += txn.Payment.CloseRewards
+		}
+	}
+	return
+}
+
+// roundsPerDay estimates how many rounds make a day from the timestamps of two blocks
+func roundsPerDay(first, last models.Block) float64 {
+	if last.Round <= first.Round || last.Timestamp <= first.Timestamp {
+		return 0
+	}
+	return 24 * 60 * 60 * float64(last.Round-first.Round) / float64(last.Timestamp-first.Timestamp)
+}
+
+func reportRewards(client libgoal.Client, addr string, historyRounds uint64) error {
+	account, err := client.AccountInformation(addr)
+	if err != nil {
+		return fmt.Errorf(errorRequestFail, err)
+	}
+	last, err := client.Block(account.Round)
+	if err != nil {
+		return fmt.Errorf(errorRequestFail, err)
+	}
+	supply, err := client.LedgerSupply()
+	if err != nil {
+		return fmt.Errorf(errorRequestFail, err)
+	}
+	proto := config.Consensus[protocol.ConsensusVersion(last.CurrentProtocol)]
+
+	rewards := accountRewards{
+		Address: addr,
+		Round:   account.Round,
+		Total:   account.Rewards,
+		Pending: account.PendingRewards,
+		Rate:    last.RewardsRate,
+	}
+	if account.Status != basics.NotParticipating.String() {
+		rewards.PerRound = estimateRewardsPerRound(account.AmountWithoutPendingRewards, last.RewardsRate, supply.TotalMoney, proto.RewardUnit)
+	}
+	if last.Round > 1 {
+		first := uint64(1)
+		if last.Round > rewardsTimingRounds {
+			first = last.Round - rewardsTimingRounds
+		}
+		firstBlock, err := client.Block(first)
+		if err != nil {
+			return fmt.Errorf(errorRequestFail, err)
+		}
+		rewards.PerDay = rewards.PerRound * roundsPerDay(firstBlock, last)
+	}
+
+	if historyRounds > 0 {
+		if historyRounds > last.Round {
+			historyRounds = last.Round
+		}
+		history := rewardsHistory{Rounds: historyRounds}
+		first, err := client.Block(last.Round - historyRounds)
+		if err != nil {
+			return fmt.Errorf(errorRequestFail, err)
+		}
+		if proto.RewardUnit > 0 {
+			history.Accrued = (last.RewardsLevel - first.RewardsLevel) * (account.AmountWithoutPendingRewards / proto.RewardUnit)
+		}
+		for round := first.Round + 1; round <= last.Round; round++ {
+			block := last
+			if round != last.Round {
+				block, err = client.Block(round)
+				if err != nil {
+					return fmt.Errorf(errorRequestFail, err)
+				}
+			}
+			history.Realized += realizedRewards(addr, block.Txns.Transactions)
+		}
+		rewards.History = &history
+	}
+
+	reportResult(rewards, "", func() {
+		fmt.Printf("%v microAlgos\n", rewards.Total)
+		fmt.Printf(infoRewardsPending+"\n", rewards.Pending)
+		fmt.Printf(infoRewardsRate+"\n", rewards.Rate, rewards.Round)
+		fmt.Printf(infoRewardsProjection+"\n", rewards.PerRound, rewards.PerDay)
+		if rewards.History != nil {
+			fmt.Printf(infoRewardsHistory+"\n", rewards.History.Rounds, rewards.History.Accrued, rewards.History.Realized)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/libgoal"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var schedulerOnce bool
+
+// schedulerNodeRetryDelay is how long the scheduler waits before asking the node for the next round again when it
+// is unreachable
+const schedulerNodeRetryDelay = 5 * time.Second
+
+// The scheduler moves the files it is done with to these subdirectories of its directory
+const (
+	schedulerSentDir   = "sent"
+	schedulerFailedDir = "failed"
+)
+
+func init() {
+	clerkCmd.AddCommand(schedulerCmd)
+
+	schedulerCmd.Flags().BoolVar(&schedulerOnce, "once", false, "Broadcast the files that are due and exit, rather than run until interrupted")
+}
+
+// scheduledFile is a file of signed transactions that goal clerk scheduler holds until they are valid
+type scheduledFile struct {
+	txns []transactions.SignedTxn
+
+	// firstValid and lastValid are the rounds all the transactions of the file are valid in
+	firstValid basics.Round
+	lastValid  basics.Round
+}
+
+// readScheduledFile decodes a file holding a single signed transaction, or a transaction group as goal clerk group
+// makes it
+func readScheduledFile(data []byte) (f scheduledFile, err error) {
+	dec := protocol.NewDecoderBytes(data)
+	for {
+		var txn transactions.SignedTxn
+		err = dec.Decode(&txn)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return
+		}
+		f.txns = append(f.txns, txn)
+	}
+	if len(f.txns) == 0 {
+		return f, errors.New("no transaction in the file")
+	}
+
+	f.firstValid, f.lastValid = f.txns[0].Txn.FirstValid, f.txns[0].Txn.LastValid
+	for _, txn := range f.txns[1:] {
+		if txn.Txn.Group.IsZero() || txn.Txn.Group != f.txns[0].Txn.Group {
+			return f, errors.New("the transactions of the file are not one transaction group")
+		}
+		if txn.Txn.FirstValid > f.firstValid {
+			f.firstValid = txn.Txn.FirstValid
+		}
+		if txn.Txn.LastValid < f.lastValid {
+			f.lastValid = txn.Txn.LastValid
+		}
+	}
+	return f, nil
+}
+
+// due returns whether the transactions of f can go in the block of round next, and whether they never can anymore
+func (f scheduledFile) due(next basics.Round) (due bool, expired bool) {
+	if next > f.lastValid {
+		return false, true
+	}
+	return next >= f.firstValid, false
+}
+
+// txnScheduler broadcasts the transaction files of a directory as their first valid round arrives
+type txnScheduler struct {
+	client libgoal.Client
+	dir    string
+
+	// waiting holds the files already reported as waiting, so that they are reported once
+	waiting map[string]bool
+}
+
+// sendDue broadcasts the files of the directory that can go in the block after round last
+func (s *txnScheduler) sendDue(last uint64) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		reportErrorf(errorSchedulerDir, s.dir, err)
+	}
+
+	next := basics.Round(last + 1)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			reportWarnf(fileReadError, name, err)
+			continue
+		}
+		f, err := readScheduledFile(data)
+		if err != nil {
+			s.done(name, schedulerFailedDir, err)
+			continue
+		}
+
+		due, expired := f.due(next)
+		switch {
+		case expired:
+			s.done(name, schedulerFailedDir, fmt.Errorf(errorSchedulerExpired, f.lastValid))
+		case !due:
+			if !s.waiting[name] {
+				reportInfof(infoSchedulerWaiting, name, f.firstValid)
+				s.waiting[name] = true
+			}
+		case len(f.txns) == 1:
+			_, err = s.client.BroadcastTransaction(f.txns[0])
+			s.sent(name, f, err)
+		default:
+			err = s.client.BroadcastTransactionGroup(f.txns)
+			s.sent(name, f, err)
+		}
+	}
+}
+
+// sent reports the outcome of the broadcast of a file and moves it out of the way
+func (s *txnScheduler) sent(name string, f scheduledFile, err error) {
+	if err != nil {
+		s.done(name, schedulerFailedDir, err)
+		return
+	}
+	reportInfof(infoSchedulerSent, name, f.txns[0].ID())
+	s.done(name, schedulerSentDir, nil)
+}
+
+// done moves a file the scheduler is done with to the subdir, warning about why if it failed
+func (s *txnScheduler) done(name string, subdir string, reason error) {
+	delete(s.waiting, name)
+	err := os.Rename(filepath.Join(s.dir, name), filepath.Join(s.dir, subdir, name))
+	if err != nil {
+		reportErrorf(errorSchedulerMove, name, subdir, err)
+	}
+	if reason != nil {
+		reportWarnf(warnSchedulerFailed, name, subdir, reason)
+	}
+}
+
+var schedulerCmd = &cobra.Command{
+	Use:   "scheduler [dir]",
+	Short: "Broadcast signed transaction files when they become valid",
+	Long: `Hold the signed transaction files of a directory, such as the ones goal clerk send -o -s and goal clerk sign write for transactions valid from a later round, and broadcast each once its first valid round arrives. A file holds a single transaction, or a transaction group as goal clerk group makes it.
+Files that were broadcast move to the sent subdirectory, and files that can't be broadcast, for instance because their last valid round passed, to the failed subdirectory. Runs until interrupted, checking the directory every round, so files can be added at any time; with --once, it broadcasts the files that are due and exits.`,
+	Example: `goal clerk send -f SENDER -t RECEIVER -a 1000000 --firstvalid-offset 20000 -o scheduled/payroll.tx -s
+goal clerk scheduler scheduled`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := args[0]
+		for _, subdir := range []string{schedulerSentDir, schedulerFailedDir} {
+			err := os.MkdirAll(filepath.Join(dir, subdir), 0700)
+			if err != nil {
+				reportErrorf(errorSchedulerDir, dir, err)
+			}
+		}
+
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+		status, err := client.Status()
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+
+		scheduler := txnScheduler{client: client, dir: dir, waiting: make(map[string]bool)}
+		if schedulerOnce {
+			scheduler.sendDue(status.LastRound)
+			return
+		}
+
+		reportInfof(infoSchedulerStarted, dir, status.LastRound)
+		for {
+			scheduler.sendDue(status.LastRound)
+
+			next, err := client.WaitForRound(status.LastRound + 1)
+			for err != nil {
+				reportWarnf(warnSchedulerNode, err)
+				time.Sleep(schedulerNodeRetryDelay)
+				next, err = client.WaitForRound(status.LastRound + 1)
+			}
+			status = next
+		}
+	},
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestScheduledFile(t *testing.T) {
+	payment := func(first, last basics.Round, group crypto.Digest) transactions.SignedTxn {
+		return transactions.SignedTxn{Txn: transactions.Transaction{
+			Type:   protocol.PaymentTx,
+			Header: transactions.Header{FirstValid: first, LastValid: last, Group: group},
+		}}
+	}
+
+	f, err := readScheduledFile(protocol.Encode(payment(100, 200, crypto.Digest{})))
+	require.NoError(t, err)
+	require.Len(t, f.txns, 1)
+
+	for _, test := range []struct {
+		next         basics.Round
+		due, expired bool
+	}{
+		{99, false, false},
+		{100, true, false},
+		{200, true, false},
+		{201, false, true},
+	} {
+		due, expired := f.due(test.next)
+		require.Equal(t, test.due, due, "round %d", test.next)
+		require.Equal(t, test.expired, expired, "round %d", test.next)
+	}
+
+	// a group is valid in the rounds all its transactions are
+	group := crypto.Hash([]byte("group"))
+	data := append(protocol.Encode(payment(100, 200, group)), protocol.Encode(payment(150, 180, group))...)
+	f, err = readScheduledFile(data)
+	require.NoError(t, err)
+	require.Len(t, f.txns, 2)
+	require.Equal(t, basics.Round(150), f.firstValid)
+	require.Equal(t, basics.Round(180), f.lastValid)
+
+	// but unrelated transactions don't go in one file
+	data = append(protocol.Encode(payment(100, 200, crypto.Digest{})), protocol.Encode(payment(100, 200, crypto.Digest{}))...)
+	_, err = readScheduledFile(data)
+	require.Error(t, err)
+
+	_, err = readScheduledFile(nil)
+	require.Error(t, err)
+	_, err = readScheduledFile([]byte("not msgpack"))
+	require.Error(t, err)
+}
@@ -0,0 +1,360 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var (
+	sendManyFile        string
+	sendManyProgress    string
+	sendManyGroup       bool
+	sendManyConcurrency int
+)
+
+func init() {
+	clerkCmd.AddCommand(sendManyCmd)
+
+	sendManyCmd.Flags().StringVar(&sendManyFile, "csv", "", "CSV file of the payments to send, with address, amount and note columns")
+	sendManyCmd.Flags().StringVarP(&account, "from", "f", "", "Account address to send the money from (If not specified, uses default account)")
+	sendManyCmd.Flags().Uint64Var(&fee, "fee", 0, "The fee of each transaction (automatically determined by default), in microAlgos")
+	sendManyCmd.Flags().BoolVar(&sendManyGroup, "group", false, "Send the payments in atomic groups of as many transactions as the protocol allows")
+	sendManyCmd.Flags().IntVar(&sendManyConcurrency, "concurrency", 4, "Number of transactions, or groups, to broadcast at a time")
+	sendManyCmd.Flags().StringVar(&sendManyProgress, "progress", "", "File recording the payments sent so far, to resume an interrupted run from (default is the CSV filename with .progress appended)")
+	sendManyCmd.MarkFlagRequired("csv")
+}
+
+// sendManyPayment is a payment of a goal clerk sendmany file
+type sendManyPayment struct {
+	Row    int
+	To     string
+	Amount uint64
+	Note   []byte
+}
+
+// sendManyResult is the outcome of sending one payment, as reported by goal clerk sendmany
+type sendManyResult struct {
+	Row        int    `json:"row"`
+	To         string `json:"to"`
+	Amount     uint64 `json:"amount"`
+	TxID       string `json:"txid,omitempty"`
+	Error      string `json:"error,omitempty"`
+	SentBefore bool   `json:"sent_before,omitempty"`
+}
+
+var sendManyCmd = &cobra.Command{
+	Use:   "sendmany --csv FILE",
+	Short: "Send money to many addresses",
+	Long: `Send a payment from one account to each address of a CSV file, for an airdrop or a payroll. The file has a header row naming its columns: "address" and "amount", in microAlgos, and optionally "note", the text of the note of the payment.
+The whole file is checked before anything is sent. The transactions are signed with kmd and broadcast --concurrency at a time, without waiting for them to be committed; with --group, the payments are sent in atomic groups instead, so that all the payments of a group are committed together or not at all.
+Every payment broadcast is recorded in the --progress file, with its transaction ID. Running the same command again after an interruption skips the payments recorded there; delete the file to send them all again.`,
+	Example: `goal clerk sendmany --csv airdrop.csv -f ADDRESS
+goal clerk sendmany --csv payroll.csv --group --concurrency 1`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if sendManyConcurrency < 1 {
+			reportErrorf(errorSendManyConcurrency, sendManyConcurrency)
+		}
+		data, err := ioutil.ReadFile(sendManyFile)
+		if err != nil {
+			reportErrorf(fileReadError, sendManyFile, err)
+		}
+		payments, err := parseSendManyFile(data)
+		if err != nil {
+			reportErrorf(errorSendManyDecode, sendManyFile, err)
+		}
+		if len(payments) == 0 {
+			reportErrorf(errorSendManyEmpty, sendManyFile)
+		}
+
+		progressFile := sendManyProgress
+		if progressFile == "" {
+			progressFile = sendManyFile + ".progress"
+		}
+		sent, err := readSendManyProgress(progressFile)
+		if err != nil {
+			reportErrorf(fileReadError, progressFile, err)
+		}
+		var pending []sendManyPayment
+		for _, payment := range payments {
+			if _, ok := sent[payment.Row]; !ok {
+				pending = append(pending, payment)
+			}
+		}
+		if len(pending) < len(payments) {
+			reportInfof(infoSendManyResuming, len(payments)-len(pending), progressFile)
+		}
+
+		dataDir := ensureSingleDataDir()
+		if account == "" {
+			account = makeAccountsList(dataDir).getDefaultAccount()
+		}
+		client := ensureFullClient(dataDir)
+		params, err := client.SuggestedParams()
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		groupSize := 1
+		if sendManyGroup {
+			groupSize = config.Consensus[protocol.ConsensusVersion(params.ConsensusVersion)].MaxTxGroupSize
+			if groupSize < 2 {
+				reportErrorln(errorSendManyNoGroups)
+			}
+		}
+		info, err := client.AccountInformation(account)
+		if err != nil {
+			reportErrorf(errorRequestFail, err)
+		}
+		wh, pw := ensureWalletHandleMaybePassword(dataDir, walletName, true)
+
+		progress, err := os.OpenFile(progressFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			reportErrorf(fileWriteError, progressFile, err)
+		}
+		defer progress.Close()
+
+		results := make(map[int]*sendManyResult, len(payments))
+		for _, payment := range payments {
+			results[payment.Row] = &sendManyResult{Row: payment.Row, To: payment.To, Amount: payment.Amount, TxID: sent[payment.Row], SentBefore: sent[payment.Row] != ""}
+		}
+
+		// sign the batches one at a time with kmd, and broadcast them concurrently
+		type signedBatch struct {
+			payments []sendManyPayment
+			txgroup  []transactions.SignedTxn
+		}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		signed := make(chan signedBatch)
+		for i := 0; i < sendManyConcurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for batch := range signed {
+					var err error
+					if len(batch.txgroup) == 1 {
+						_, err = client.BroadcastTransaction(batch.txgroup[0])
+					} else {
+						err = client.BroadcastTransactionGroup(batch.txgroup)
+					}
+
+					mu.Lock()
+					for i, payment := range batch.payments {
+						result := results[payment.Row]
+						if err != nil {
+							result.Error = err.Error()
+							continue
+						}
+						result.TxID = batch.txgroup[i].ID().String()
+						fmt.Fprintf(progress, "%d\t%s\n", payment.Row, result.TxID)
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+
+		construct := func(payment sendManyPayment) (transactions.Transaction, error) {
+			note := payment.Note
+			if note == nil {
+				// Make sure that payments of the same amount to the same address have different txids
+				note = make([]byte, 8)
+				crypto.RandBytes(note)
+			}
+			return client.ConstructPayment(account, payment.To, fee, payment.Amount, note, "", [32]byte{})
+		}
+		sign := func(tx transactions.Transaction) (transactions.SignedTxn, error) {
+			return client.SignTransactionWithWalletAndSigner(wh, pw, info.AuthAddr, tx)
+		}
+		for _, batch := range sendManyBatches(pending, groupSize) {
+			txgroup, err := signSendManyBatch(batch, construct, sign)
+			if err != nil {
+				mu.Lock()
+				for _, payment := range batch {
+					results[payment.Row].Error = fmt.Sprintf(errorSigningTX, err)
+				}
+				mu.Unlock()
+				continue
+			}
+			signed <- signedBatch{payments: batch, txgroup: txgroup}
+		}
+		close(signed)
+		wg.Wait()
+
+		failed := 0
+		list := make([]sendManyResult, 0, len(payments))
+		rows := make([][]string, 0, len(payments))
+		for _, payment := range payments {
+			result := results[payment.Row]
+			status := "sent"
+			switch {
+			case result.Error != "":
+				failed++
+				status = result.Error
+			case result.SentBefore:
+				status = "sent before"
+			}
+			list = append(list, *result)
+			rows = append(rows, []string{strconv.Itoa(result.Row), result.To, strconv.FormatUint(result.Amount, 10), result.TxID, status})
+		}
+		reportRows(list, []string{"ROW", "ADDRESS", "AMOUNT", "TXID", "STATUS"}, rows, func() {
+			for _, result := range list {
+				if result.Error != "" {
+					fmt.Printf("%d\t%s\t%s\n", result.Row, result.To, result.Error)
+					continue
+				}
+				fmt.Printf("%d\t%s\t%s\n", result.Row, result.To, result.TxID)
+			}
+		})
+		reportInfof(infoSendManySent, len(payments)-failed, len(payments), progressFile)
+		if failed > 0 {
+			reportErrorf(errorSendManyIncomplete, failed, len(payments))
+		}
+	},
+}
+
+// parseSendManyFile decodes and checks the payments of a goal clerk sendmany CSV file
+func parseSendManyFile(data []byte) ([]sendManyPayment, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if column != "address" && column != "amount" && column != "note" {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		columns[column] = i
+	}
+	for _, column := range []string{"address", "amount"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("no %s column", column)
+		}
+	}
+
+	payments := make([]sendManyPayment, 0, len(records)-1)
+	for i, record := range records[1:] {
+		// rows are numbered as in a spreadsheet, the header being row 1
+		row := i + 2
+		to := strings.TrimSpace(record[columns["address"]])
+		if _, err := basics.UnmarshalChecksumAddress(to); err != nil {
+			return nil, fmt.Errorf("row %d: invalid address %s: %v", row, to, err)
+		}
+		amount, err := strconv.ParseUint(strings.TrimSpace(record[columns["amount"]]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid amount: %v", row, err)
+		}
+		payment := sendManyPayment{Row: row, To: to, Amount: amount}
+		if i, ok := columns["note"]; ok && record[i] != "" {
+			payment.Note = []byte(record[i])
+		}
+		payments = append(payments, payment)
+	}
+	return payments, nil
+}
+
+// readSendManyProgress returns the transaction IDs of the payments that a
+// progress file records as sent, by row. A missing file records nothing.
+func readSendManyProgress(filename string) (map[int]string, error) {
+	sent := make(map[int]string)
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return sent, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			// a line cut short by an interruption
+			continue
+		}
+		row, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		sent[row] = fields[1]
+	}
+	return sent, scanner.Err()
+}
+
+// sendManyBatches cuts payments into the batches that are broadcast
+// together, of up to groupSize payments
+func sendManyBatches(payments []sendManyPayment, groupSize int) (batches [][]sendManyPayment) {
+	for len(payments) > 0 {
+		n := groupSize
+		if n > len(payments) {
+			n = len(payments)
+		}
+		batches = append(batches, payments[:n])
+		payments = payments[n:]
+	}
+	return
+}
+
+// signSendManyBatch builds the transactions of a batch of payments, grouping
+// them when there are several, and signs them
+func signSendManyBatch(batch []sendManyPayment, construct func(sendManyPayment) (transactions.Transaction, error), sign func(transactions.Transaction) (transactions.SignedTxn, error)) ([]transactions.SignedTxn, error) {
+	txns := make([]transactions.Transaction, len(batch))
+	for i, payment := range batch {
+		tx, err := construct(payment)
+		if err != nil {
+			return nil, err
+		}
+		txns[i] = tx
+	}
+	if len(txns) > 1 {
+		group := transactions.ComputeGroupID(txns)
+		for i := range txns {
+			txns[i].Group = group
+		}
+	}
+
+	txgroup := make([]transactions.SignedTxn, len(txns))
+	for i, tx := range txns {
+		stxn, err := sign(tx)
+		if err != nil {
+			return nil, err
+		}
+		txgroup[i] = stxn
+	}
+	return txgroup, nil
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+)
+
+func TestParseSendManyFile(t *testing.T) {
+	var a, b basics.Address
+	crypto.RandBytes(a[:])
+	crypto.RandBytes(b[:])
+	addrA := a.GetUserAddress()
+	addrB := b.GetUserAddress()
+
+	payments, err := parseSendManyFile([]byte(fmt.Sprintf("Address,amount,note\n%s, 100 ,thanks\n%s,200,\n", addrA, addrB)))
+	require.NoError(t, err)
+	require.Equal(t, []sendManyPayment{
+		{Row: 2, To: addrA, Amount: 100, Note: []byte("thanks")},
+		{Row: 3, To: addrB, Amount: 200},
+	}, payments)
+
+	// the note column is optional, and the columns come in any order
+	payments, err = parseSendManyFile([]byte(fmt.Sprintf("amount,address\n5,%s\n", addrA)))
+	require.NoError(t, err)
+	require.Equal(t, []sendManyPayment{{Row: 2, To: addrA, Amount: 5}}, payments)
+
+	bad := []string{
+		"address,amount,memo\n",
+		"address\n" + addrA + "\n",
+		"address,amount\nnot-an-address,5\n",
+		fmt.Sprintf("address,amount\n%s,-5\n", addrA),
+		fmt.Sprintf("address,amount\n%s,5\n%s\n", addrA, addrB),
+	}
+	for _, data := range bad {
+		_, err = parseSendManyFile([]byte(data))
+		require.Error(t, err, data)
+	}
+}
+
+func TestSendManyProgress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sendmany")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "payments.csv.progress")
+
+	sent, err := readSendManyProgress(filename)
+	require.NoError(t, err)
+	require.Empty(t, sent)
+
+	// the last line was cut short by an interruption
+	err = ioutil.WriteFile(filename, []byte("2\tTXID2\n4\tTXID4\n5"), 0600)
+	require.NoError(t, err)
+	sent, err = readSendManyProgress(filename)
+	require.NoError(t, err)
+	require.Equal(t, map[int]string{2: "TXID2", 4: "TXID4"}, sent)
+}
+
+func TestSendManyBatches(t *testing.T) {
+	var payments []sendManyPayment
+	for row := 2; row < 7; row++ {
+		payments = append(payments, sendManyPayment{Row: row})
+	}
+	batches := sendManyBatches(payments, 2)
+	require.Len(t, batches, 3)
+	require.Equal(t, payments[4:], batches[2])
+	require.Len(t, sendManyBatches(payments, 1), 5)
+	require.Empty(t, sendManyBatches(nil, 16))
+}
+
+func TestSignSendManyBatch(t *testing.T) {
+	batch := []sendManyPayment{{Row: 2, Amount: 1}, {Row: 3, Amount: 2}, {Row: 4, Amount: 3}}
+	construct := func(payment sendManyPayment) (transactions.Transaction, error) {
+		var tx transactions.Transaction
+		tx.Amount.Raw = payment.Amount
+		return tx, nil
+	}
+	sign := func(tx transactions.Transaction) (transactions.SignedTxn, error) {
+		return transactions.SignedTxn{Txn: tx}, nil
+	}
+
+	txgroup, err := signSendManyBatch(batch, construct, sign)
+	require.NoError(t, err)
+	require.Len(t, txgroup, 3)
+	txns := make([]transactions.Transaction, len(txgroup))
+	for i, stxn := range txgroup {
+		require.Equal(t, batch[i].Amount, stxn.Txn.Amount.Raw)
+		txns[i] = stxn.Txn
+		txns[i].Group = crypto.Digest{}
+	}
+	group := transactions.ComputeGroupID(txns)
+	for _, stxn := range txgroup {
+		require.Equal(t, group, stxn.Txn.Group)
+	}
+
+	// a single payment is not grouped
+	txgroup, err = signSendManyBatch(batch[:1], construct, sign)
+	require.NoError(t, err)
+	require.Equal(t, crypto.Digest{}, txgroup[0].Txn.Group)
+
+	_, err = signSendManyBatch(batch, construct, func(tx transactions.Transaction) (transactions.SignedTxn, error) {
+		return transactions.SignedTxn{}, fmt.Errorf("locked wallet")
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,307 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions/logic/templates"
+)
+
+var (
+	templateOut      string
+	templateSource   string
+	templateMaxFee   uint64
+	templateTimeout  uint64
+	templateOwner    string
+	templateReceiver string
+
+	htlcHashFn    string
+	htlcHashImage string
+
+	splitReceiver2 string
+	splitRatio1    uint64
+	splitRatio2    uint64
+	splitMinPay    uint64
+
+	periodicAmount   uint64
+	periodicPeriod   uint64
+	periodicDuration uint64
+
+	limitSwapN    uint64
+	limitSwapD    uint64
+	limitMinTrade uint64
+)
+
+func init() {
+	clerkCmd.AddCommand(templateCmd)
+
+	for _, cmd := range []*cobra.Command{htlcTemplateCmd, splitTemplateCmd, periodicTemplateCmd, limitOrderTemplateCmd} {
+		templateCmd.AddCommand(cmd)
+		cmd.Flags().StringVarP(&templateOut, "out", "o", "", "File to write the compiled program to (default is the template name with .teal.tok appended)")
+		cmd.Flags().StringVar(&templateSource, "source", "", "File to also write the filled in TEAL source to")
+		cmd.Flags().Uint64Var(&templateMaxFee, "max-fee", 10000, "Highest fee the transactions spending from the escrow may pay, in microAlgos")
+		cmd.Flags().Uint64Var(&templateTimeout, "timeout", 0, "Round after which the escrow can be closed (required)")
+		cmd.MarkFlagRequired("timeout")
+	}
+
+	htlcTemplateCmd.Flags().StringVar(&templateOwner, "owner", "", "Account the escrow goes back to after the timeout (required)")
+	htlcTemplateCmd.Flags().StringVar(&templateReceiver, "receiver", "", "Account that can claim the escrow with the preimage (required)")
+	htlcTemplateCmd.Flags().StringVar(&htlcHashFn, "hashfn", "sha256", "Hash function of the image, sha256, keccak256 or sha512_256")
+	htlcTemplateCmd.Flags().StringVar(&htlcHashImage, "hashimg", "", "Base64 encoded hash of the preimage (required)")
+	htlcTemplateCmd.MarkFlagRequired("owner")
+	htlcTemplateCmd.MarkFlagRequired("receiver")
+	htlcTemplateCmd.MarkFlagRequired("hashimg")
+
+	splitTemplateCmd.Flags().StringVar(&templateOwner, "owner", "", "Account the escrow goes back to after the timeout (required)")
+	splitTemplateCmd.Flags().StringVar(&templateReceiver, "receiver1", "", "Account that gets the first share of every payment (required)")
+	splitTemplateCmd.Flags().StringVar(&splitReceiver2, "receiver2", "", "Account that gets the second share of every payment (required)")
+	splitTemplateCmd.Flags().Uint64Var(&splitRatio1, "ratio1", 0, "First side of the ratio the payments are split in (required)")
+	splitTemplateCmd.Flags().Uint64Var(&splitRatio2, "ratio2", 0, "Second side of the ratio the payments are split in (required)")
+	splitTemplateCmd.Flags().Uint64Var(&splitMinPay, "minpay", 0, "Smallest amount the first receiver may be paid, in microAlgos")
+	splitTemplateCmd.MarkFlagRequired("owner")
+	splitTemplateCmd.MarkFlagRequired("receiver1")
+	splitTemplateCmd.MarkFlagRequired("receiver2")
+	splitTemplateCmd.MarkFlagRequired("ratio1")
+	splitTemplateCmd.MarkFlagRequired("ratio2")
+
+	periodicTemplateCmd.Flags().StringVar(&templateReceiver, "receiver", "", "Account that can withdraw from the escrow (required)")
+	periodicTemplateCmd.Flags().Uint64Var(&periodicAmount, "amount", 0, "Amount of every withdrawal, in microAlgos (required)")
+	periodicTemplateCmd.Flags().Uint64Var(&periodicPeriod, "period", 0, "Number of rounds between withdrawals (required)")
+	periodicTemplateCmd.Flags().Uint64Var(&periodicDuration, "duration", 0, "Number of rounds every withdrawal transaction is valid for, at most the period (required)")
+	periodicTemplateCmd.Flags().StringVarP(&leaseBase64, "lease", "x", "", "Lease the withdrawals must carry (base64 encoded 32 bytes, random by default)")
+	periodicTemplateCmd.MarkFlagRequired("receiver")
+	periodicTemplateCmd.MarkFlagRequired("amount")
+	periodicTemplateCmd.MarkFlagRequired("period")
+	periodicTemplateCmd.MarkFlagRequired("duration")
+
+	limitOrderTemplateCmd.Flags().StringVar(&templateOwner, "owner", "", "Account that gets the asset, and that the escrow goes back to after the timeout (required)")
+	limitOrderTemplateCmd.Flags().StringVar(&assetCreator, "creator", "", "Account address of the creator of the asset (required)")
+	limitOrderTemplateCmd.Flags().Uint64Var(&assetIndex, "assetid", 0, "Index of the asset (required)")
+	limitOrderTemplateCmd.Flags().Uint64Var(&limitSwapN, "swapn", 0, "microAlgos the escrow pays for every --swapd units of the asset (required)")
+	limitOrderTemplateCmd.Flags().Uint64Var(&limitSwapD, "swapd", 0, "Units of the asset the escrow wants for every --swapn microAlgos (required)")
+	limitOrderTemplateCmd.Flags().Uint64Var(&limitMinTrade, "mintrade", 0, "Smallest amount the escrow pays in a trade, in microAlgos")
+	limitOrderTemplateCmd.MarkFlagRequired("owner")
+	limitOrderTemplateCmd.MarkFlagRequired("creator")
+	limitOrderTemplateCmd.MarkFlagRequired("assetid")
+	limitOrderTemplateCmd.MarkFlagRequired("swapn")
+	limitOrderTemplateCmd.MarkFlagRequired("swapd")
+}
+
+// filledTemplate is what `goal clerk template` reports for the contract it compiled
+type filledTemplate struct {
+	Template     string   `json:"template"`
+	Address      string   `json:"address"`
+	Program      string   `json:"program"`
+	Instructions []string `json:"instructions"`
+}
+
+// parseTemplateAddress parses the address given to a template flag
+func parseTemplateAddress(address string) basics.Address {
+	addr, err := basics.UnmarshalChecksumAddress(address)
+	if err != nil {
+		reportErrorf(errorParseAddr, address, err)
+	}
+	return addr
+}
+
+// compileTemplate compiles the filled in template t, writes out the program, and reports its escrow address
+// along with the instructions returned by instructions, given the escrow address and the program file
+func compileTemplate(name string, t templates.Template, instructions func(escrow, program string) []string) {
+	program, escrow, err := templates.Compile(t)
+	if err != nil {
+		reportErrorf(errorTemplate, name, err)
+	}
+	if templateSource != "" {
+		// Compile already checked that there is one
+		source, _ := t.Source()
+		err = ioutil.WriteFile(templateSource, []byte(source), 0666)
+		if err != nil {
+			reportErrorf(fileWriteError, templateSource, err)
+		}
+	}
+	if templateOut == "" {
+		templateOut = name + ".teal.tok"
+	}
+	err = ioutil.WriteFile(templateOut, program, 0666)
+	if err != nil {
+		reportErrorf(fileWriteError, templateOut, err)
+	}
+
+	filled := filledTemplate{
+		Template: name,
+		Address:  escrow.GetUserAddress(),
+		Program:  templateOut,
+	}
+	filled.Instructions = append(instructions(filled.Address, filled.Program),
+		fmt.Sprintf("Every transaction spending from the escrow may pay a fee of at most %d microAlgos, and can't rekey it.", templateMaxFee))
+	reportResult(filled, filled.Address, func() {
+		fmt.Printf("Escrow address: %s\n", filled.Address)
+		fmt.Printf("Program: %s\n", filled.Program)
+		for _, line := range filled.Instructions {
+			fmt.Println(line)
+		}
+	})
+}
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Generate contracts from built-in TEAL templates",
+	Long: `Fill in one of the built-in TEAL contract templates with the given parameters, compile it, and print the address of the escrow account it controls along with the goal commands that spend from it.
+Fund the escrow by sending Algos to its address; every spending transaction is signed with goal clerk sign --program and the compiled program.`,
+	Args: validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		cmd.HelpFunc()(cmd, nil)
+	},
+}
+
+var htlcTemplateCmd = &cobra.Command{
+	Use:   "htlc",
+	Short: "Hash time-locked contract",
+	Long:  `Generate a hash time-locked contract: the receiver can close the escrow to itself by passing the preimage of the hash image as the argument of the program, or the owner can close it to itself once the timeout round has passed.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		image, err := base64.StdEncoding.DecodeString(htlcHashImage)
+		if err != nil {
+			reportErrorf(errorTemplateHashImage, htlcHashImage, err)
+		}
+		t := templates.HTLC{
+			Owner:     parseTemplateAddress(templateOwner),
+			Receiver:  parseTemplateAddress(templateReceiver),
+			HashFn:    htlcHashFn,
+			HashImage: image,
+			Timeout:   basics.Round(templateTimeout),
+			MaxFee:    templateMaxFee,
+		}
+		compileTemplate("htlc", t, func(escrow, program string) []string {
+			return []string{
+				"Claim the escrow, as the receiver, with the base64 encoded preimage:",
+				fmt.Sprintf("  goal clerk construct -f %s -t %s -c %s -a 0 -o claim.tx", escrow, templateReceiver, templateReceiver),
+				fmt.Sprintf("  goal clerk sign -i claim.tx -o claim.stx --program %s --argb64 PREIMAGE", program),
+				"  goal clerk broadcast -f claim.stx",
+				fmt.Sprintf("Or close it back to the owner once round %d has passed:", templateTimeout),
+				fmt.Sprintf("  goal clerk construct -f %s -t %s -c %s -a 0 -o refund.tx", escrow, templateOwner, templateOwner),
+				fmt.Sprintf("  goal clerk sign -i refund.tx -o refund.stx --program %s", program),
+				"  goal clerk broadcast -f refund.stx",
+			}
+		})
+	},
+}
+
+var splitTemplateCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split payment contract",
+	Long:  `Generate a split payment contract: the escrow pays out in groups of two payments, to the first and to the second receiver, split in the ratio of --ratio1 to --ratio2, of which the first pays at least --minpay. The owner can close the escrow to itself once the timeout round has passed.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		t := templates.Split{
+			Owner:     parseTemplateAddress(templateOwner),
+			Receiver1: parseTemplateAddress(templateReceiver),
+			Receiver2: parseTemplateAddress(splitReceiver2),
+			Ratio1:    splitRatio1,
+			Ratio2:    splitRatio2,
+			MinPay:    splitMinPay,
+			Timeout:   basics.Round(templateTimeout),
+			MaxFee:    templateMaxFee,
+		}
+		compileTemplate("split", t, func(escrow, program string) []string {
+			return []string{
+				fmt.Sprintf("Pay out A1 and A2 microAlgos, where A1:A2 is %d:%d and A1 is at least %d:", splitRatio1, splitRatio2, splitMinPay),
+				fmt.Sprintf("  goal clerk construct -f %s -t %s -a A1 -o split-1.tx", escrow, templateReceiver),
+				fmt.Sprintf("  goal clerk construct -f %s -t %s -a A2 -o split-2.tx", escrow, splitReceiver2),
+				"  cat split-1.tx split-2.tx > split.tx",
+				"  goal clerk group -i split.tx -o split.gtx",
+				fmt.Sprintf("  goal clerk sign -i split.gtx -o split.stx --program %s", program),
+				"  goal clerk broadcast -f split.stx",
+				fmt.Sprintf("Or close it back to the owner once round %d has passed:", templateTimeout),
+				fmt.Sprintf("  goal clerk construct -f %s -t %s -c %s -a 0 -o refund.tx", escrow, templateOwner, templateOwner),
+				fmt.Sprintf("  goal clerk sign -i refund.tx -o refund.stx --program %s", program),
+				"  goal clerk broadcast -f refund.stx",
+			}
+		})
+	},
+}
+
+var periodicTemplateCmd = &cobra.Command{
+	Use:   "periodic",
+	Short: "Periodic payment contract",
+	Long:  `Generate a periodic payment contract: the receiver can withdraw --amount from the escrow once every --period rounds, with a transaction valid for --duration rounds from a multiple of the period that carries the lease of the contract. The lease keeps a second withdrawal from committing in the same period. The receiver can close the escrow to itself once the timeout round has passed.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		lease := parseLease(leaseBase64)
+		if leaseBase64 == "" {
+			crypto.RandBytes(lease[:])
+		}
+		t := templates.PeriodicPayment{
+			Receiver: parseTemplateAddress(templateReceiver),
+			Amount:   periodicAmount,
+			Period:   periodicPeriod,
+			Duration: periodicDuration,
+			Lease:    lease,
+			Timeout:  basics.Round(templateTimeout),
+			MaxFee:   templateMaxFee,
+		}
+		leaseB64 := base64.StdEncoding.EncodeToString(lease[:])
+		compileTemplate("periodic", t, func(escrow, program string) []string {
+			return []string{
+				fmt.Sprintf("Withdraw, as the receiver, from a round R that is a multiple of %d:", periodicPeriod),
+				fmt.Sprintf("  goal clerk construct -f %s -t %s -a %d --firstvalid R --lastvalid R+%d --lease %s -o withdraw.tx", escrow, templateReceiver, periodicAmount, periodicDuration, leaseB64),
+				fmt.Sprintf("  goal clerk sign -i withdraw.tx -o withdraw.stx --program %s", program),
+				"  goal clerk broadcast -f withdraw.stx",
+				fmt.Sprintf("Once round %d has passed, construct the withdrawal with -a 0 -c %s instead to close the escrow.", templateTimeout, templateReceiver),
+			}
+		})
+	},
+}
+
+var limitOrderTemplateCmd = &cobra.Command{
+	Use:   "limitorder",
+	Short: "Limit order contract",
+	Long:  `Generate a limit order contract: the escrow pays at least --mintrade microAlgos to anyone who transfers the asset to the owner in the same group, at --swapn microAlgos for every --swapd units of the asset or better. The owner can close the escrow to itself once the timeout round has passed.`,
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, _ []string) {
+		t := templates.LimitOrder{
+			Owner:        parseTemplateAddress(templateOwner),
+			AssetCreator: parseTemplateAddress(assetCreator),
+			AssetIndex:   assetIndex,
+			SwapN:        limitSwapN,
+			SwapD:        limitSwapD,
+			MinTrade:     limitMinTrade,
+			Timeout:      basics.Round(templateTimeout),
+			MaxFee:       templateMaxFee,
+		}
+		compileTemplate("limitorder", t, func(escrow, program string) []string {
+			return []string{
+				fmt.Sprintf("Trade, as a buyer, with a group of two transactions: the escrow pays A microAlgos to the buyer, at least %d,", limitMinTrade),
+				fmt.Sprintf("then the buyer transfers U units of asset %d of %s to %s, where A*%d <= U*%d.", assetIndex, assetCreator, templateOwner, limitSwapD, limitSwapN),
+				"Group the two with goal clerk group, split them with goal clerk split, sign the payment with",
+				fmt.Sprintf("  goal clerk sign --program %s", program),
+				"and the transfer with the key of the buyer, then concatenate the signed files in order and goal clerk broadcast them.",
+				fmt.Sprintf("Or close the escrow back to the owner once round %d has passed:", templateTimeout),
+				fmt.Sprintf("  goal clerk construct -f %s -t %s -c %s -a 0 -o refund.tx", escrow, templateOwner, templateOwner),
+				fmt.Sprintf("  goal clerk sign -i refund.tx -o refund.stx --program %s", program),
+				"  goal clerk broadcast -f refund.stx",
+			}
+		})
+	},
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand/libgoal"
+
+	"github.com/spf13/cobra"
+)
+
+var waitTimeoutRounds uint64
+
+func init() {
+	clerkCmd.AddCommand(waitTxnCmd)
+
+	waitTxnCmd.Flags().Uint64Var(&waitTimeoutRounds, "timeout-rounds", 0, "Give up if the transaction hasn't committed after this many rounds (0 waits until it commits or leaves the pool)")
+}
+
+// waitForCommit waits for txid to commit and returns the round it committed in.
+func waitForCommit(client libgoal.Client, txid string) (uint64, error) {
+	txn, err := client.WaitForConfirmation(txid, context.Background())
+	if err != nil {
+		return 0, waitError(txid, err)
+	}
+	reportInfof(infoTxCommitted, txid, txn.ConfirmedRound)
+	return txn.ConfirmedRound, nil
+}
+
+// waitError turns an error of WaitForConfirmation into the message goal reports
+func waitError(txid string, err error) error {
+	if poolErr, ok := err.(libgoal.TxnPoolError); ok {
+		return fmt.Errorf(txPoolError, txid, poolErr.Reason)
+	}
+	return fmt.Errorf(errorRequestFail, err)
+}
+
+// cancelAfterRounds returns a context that is canceled once the node reaches
+// rounds rounds after its current round
+func cancelAfterRounds(client libgoal.Client, rounds uint64) (context.Context, context.CancelFunc, error) {
+	stat, err := client.Status()
+	if err != nil {
+		return nil, nil, err
+	}
+	deadline := stat.LastRound + rounds
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer cancel()
+		for ctx.Err() == nil {
+			// WaitForRound returns once the round after its argument is there,
+			// or after a minute
+			stat, err := client.WaitForRound(deadline - 1)
+			if err != nil || stat.LastRound >= deadline {
+				return
+			}
+		}
+	}()
+	return ctx, cancel, nil
+}
+
+var waitTxnCmd = &cobra.Command{
+	Use:   "wait [txid]",
+	Short: "Wait for a transaction to commit",
+	Long: `Wait for a transaction broadcast earlier, for instance with --no-wait or through the REST API, to commit, and report the round it committed in.
+The node must know the transaction: it has to be in its pool, or to have committed within the last MaxTxnLife rounds. With --timeout-rounds, goal gives up with an error if the transaction hasn't committed by then.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		txid := args[0]
+		dataDir := ensureSingleDataDir()
+		client := ensureAlgodClient(dataDir)
+
+		ctx := context.Background()
+		if waitTimeoutRounds > 0 {
+			var cancel context.CancelFunc
+			var err error
+			ctx, cancel, err = cancelAfterRounds(client, waitTimeoutRounds)
+			if err != nil {
+				reportErrorf(errorRequestFail, err)
+			}
+			defer cancel()
+		}
+
+		txn, err := client.WaitForConfirmation(txid, ctx)
+		if err == context.Canceled {
+			reportErrorf(errorTxWaitTimeout, txid, waitTimeoutRounds)
+		}
+		if err != nil {
+			reportErrorln(waitError(txid, err))
+		}
+
+		reportInfof(infoTxCommitted, txid, txn.ConfirmedRound)
+		sent := sentTransaction{TxID: txid, Fee: txn.Fee, ConfirmedRound: txn.ConfirmedRound}
+		reportResult(sent, txid, nil)
+	},
+}
@@ -39,6 +39,7 @@ var nodeHostsToGenerate int
 var walletsToGenerate int
 var nodeTemplatePath string
 var relayTemplatePath string
+var networkSpecPath string
 
 func init() {
 	rootCmd.AddCommand(generateCmd)
@@ -54,6 +55,7 @@ func init() {
 	generateCmd.Flags().IntVarP(&nodesToGenerate, "nodes", "n", -1, "Nodes to generate")
 	generateCmd.Flags().StringVarP(&nodeTemplatePath, "node-template", "", "", "json for one node")
 	generateCmd.Flags().StringVarP(&relayTemplatePath, "relay-template", "", "", "json for a relay node")
+	generateCmd.Flags().StringVarP(&networkSpecPath, "spec", "s", "", "json network spec describing the node classes, regions and stake distribution of a heterogeneous network")
 
 	longParts := make([]string, len(generateTemplateLines)+1)
 	longParts[0] = generateCmd.Long
@@ -67,6 +69,7 @@ var generateTemplateLines = []string{
 	"otwt => OneThousandWallets network template",
 	"otwg => OneThousandWallets genesis data",
 	"ohwg => OneHundredWallets genesis data",
+	"spec => recipe directory (-o) with the network template, genesis data and topology of the --spec network spec",
 }
 
 var generateCmd = &cobra.Command{
@@ -125,6 +128,18 @@ template modes for -t:`,
 			}
 
 			err = generateNetworkTemplate(outputFilename, walletsToGenerate, relaysToGenerate, nodeHostsToGenerate, nodesToGenerate, baseNode, baseRelay)
+		case "spec":
+			if networkSpecPath == "" {
+				reportErrorf("must specify the network spec with --spec")
+			}
+			spec, err := loadNetworkSpec(networkSpecPath)
+			if err != nil {
+				reportErrorf("%s: bad network spec, %s", networkSpecPath, err)
+			}
+			err = generateRecipe(spec, outputFilename)
+			if err != nil {
+				reportErrorf("error generating recipe: %v\n", err)
+			}
 		case "otwt":
 			err = generateNetworkTemplate(outputFilename, 1000, 10, 20, 100, baseNode, baseRelay)
 		case "otwg":
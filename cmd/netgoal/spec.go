@@ -0,0 +1,458 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand/gen"
+	"github.com/algorand/go-algorand/netdeploy/remote"
+	"github.com/algorand/go-algorand/util/codecs"
+)
+
+// The roles of the node classes of a network spec.
+const (
+	relayRole         = "relay"
+	archivalRole      = "archival"
+	participationRole = "participation"
+	apiRole           = "api"
+)
+
+var nodeRoles = []string{relayRole, archivalRole, participationRole, apiRole}
+
+// The stake distribution curves of a network spec.
+const (
+	evenStakeCurve        = "even"
+	linearStakeCurve      = "linear"
+	powerStakeCurve       = "power"
+	exponentialStakeCurve = "exponential"
+)
+
+// regionToken is replaced by the region name in the host templates of the node classes.
+const regionToken = "{{Region}}"
+
+// networkSpec declaratively describes a heterogeneous network, from which netgoal generates the network template,
+// genesis data and topology of a recipe.
+type networkSpec struct {
+	// ConfigFile and HostTemplatesFile are copied to the generated recipe, and are relative to its directory.
+	ConfigFile        string
+	HostTemplatesFile string
+
+	// Genesis is the base of the generated genesis data, whose wallets are generated.
+	Genesis gen.GenesisData
+	// Wallets is the number of wallets, which are spread across the participation nodes.
+	Wallets int
+	// StakeCurve is the distribution of the stake between the wallets, one of even, linear, power or exponential.
+	StakeCurve string
+	// StakeCurveParam is the exponent of the power curve (1 by default), or the ratio between the stake of the
+	// richest and the poorest wallets of the exponential curve (100 by default).
+	StakeCurveParam float64 `json:",omitempty"`
+	// OfflineWallets is the number of the poorest wallets that aren't online at genesis.
+	OfflineWallets int `json:",omitempty"`
+
+	// Regions are the geographic regions the hosts are spread across, by weight.
+	Regions []regionSpec
+	// Node is the node template of the classes that don't have their own.
+	Node remote.NodeConfig
+	// Classes are the node classes of the network.
+	Classes []nodeClassSpec
+}
+
+// regionSpec is a geographic region the hosts are spread across.
+type regionSpec struct {
+	Name   string
+	Weight float64
+}
+
+// nodeClassSpec describes a class of nodes sharing a role, configuration and host template.
+type nodeClassSpec struct {
+	// Name prefixes the names of the class nodes, such as relay1.
+	Name string
+	// Role is one of relay, archival, participation or api.
+	Role string
+	// Count is the number of nodes of the class.
+	Count int
+	// NodesPerHost is the number of class nodes sharing a host; 0 means 1.
+	NodesPerHost int `json:",omitempty"`
+	// HostPrefix prefixes the names of the class hosts; it defaults to the capitalized first letter of the class name.
+	HostPrefix string `json:",omitempty"`
+	// HostTemplate is the host template of the class hosts, in which {{Region}} is replaced by the host region.
+	HostTemplate string
+	// Regions restricts the class hosts to the named regions; all the regions are used by default.
+	Regions []string `json:",omitempty"`
+	// Relay makes the archival nodes of the class relays as well.
+	Relay bool `json:",omitempty"`
+	// Node overrides the node template of the network spec.
+	Node *remote.NodeConfig `json:",omitempty"`
+	// Config is merged into the config.json of the class nodes, over the role defaults.
+	Config map[string]interface{} `json:",omitempty"`
+}
+
+// topologySpec is the cloudspec.config topology of a network, assigning a host template to every host.
+type topologySpec struct {
+	Hosts []topologyHost
+}
+
+type topologyHost struct {
+	Name     string
+	Template string
+}
+
+func loadNetworkSpec(file string) (spec networkSpec, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&spec); err != nil {
+		return
+	}
+	err = spec.validate()
+	return
+}
+
+func (spec networkSpec) validate() error {
+	if len(spec.Classes) == 0 {
+		return fmt.Errorf("the spec must have at least one node class")
+	}
+	if len(spec.Regions) == 0 {
+		return fmt.Errorf("the spec must have at least one region")
+	}
+	regions := make(map[string]bool)
+	for _, r := range spec.Regions {
+		if r.Name == "" || r.Weight <= 0 {
+			return fmt.Errorf("region '%s' must have a name and a positive weight", r.Name)
+		}
+		regions[r.Name] = true
+	}
+	names := make(map[string]bool)
+	hostPrefixes := make(map[string]string)
+	participationNodes := 0
+	for _, class := range spec.Classes {
+		if class.Name == "" {
+			return fmt.Errorf("node classes must have a name")
+		}
+		if names[class.Name] {
+			return fmt.Errorf("duplicate node class '%s'", class.Name)
+		}
+		names[class.Name] = true
+		if !isNodeRole(class.Role) {
+			return fmt.Errorf("node class '%s' has unknown role '%s'; the roles are %s", class.Name, class.Role, strings.Join(nodeRoles, ", "))
+		}
+		if class.Count <= 0 || class.NodesPerHost < 0 {
+			return fmt.Errorf("node class '%s' must have a positive count of nodes", class.Name)
+		}
+		if class.HostTemplate == "" {
+			return fmt.Errorf("node class '%s' has no host template", class.Name)
+		}
+		if class.Relay && class.Role != archivalRole {
+			return fmt.Errorf("node class '%s' sets Relay, which only applies to archival nodes", class.Name)
+		}
+		for _, r := range class.Regions {
+			if !regions[r] {
+				return fmt.Errorf("node class '%s' refers to unknown region '%s'", class.Name, r)
+			}
+		}
+		prefix := class.hostPrefix()
+		if other, ok := hostPrefixes[prefix]; ok {
+			return fmt.Errorf("node classes '%s' and '%s' have the same host prefix '%s'; set HostPrefix", other, class.Name, prefix)
+		}
+		hostPrefixes[prefix] = class.Name
+		if class.Role == participationRole {
+			participationNodes += class.Count
+		}
+	}
+	if spec.Wallets < 0 || spec.OfflineWallets < 0 || spec.OfflineWallets > spec.Wallets {
+		return fmt.Errorf("invalid number of wallets (%d, of which %d offline)", spec.Wallets, spec.OfflineWallets)
+	}
+	if spec.Wallets > 0 && participationNodes == 0 {
+		return fmt.Errorf("the wallets require at least one participation node")
+	}
+	_, err := stakeCurve(spec.StakeCurve, spec.StakeCurveParam, 1)
+	return err
+}
+
+func isNodeRole(role string) bool {
+	for _, r := range nodeRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (class nodeClassSpec) hostPrefix() string {
+	if class.HostPrefix != "" {
+		return class.HostPrefix
+	}
+	return strings.ToUpper(class.Name[:1])
+}
+
+// stakeCurve returns the percent of the stake held by each of the given number of wallets, from the richest to the poorest.
+func stakeCurve(curve string, param float64, wallets int) ([]float64, error) {
+	weights := make([]float64, wallets)
+	for i := range weights {
+		switch curve {
+		case evenStakeCurve, "":
+			weights[i] = 1
+		case linearStakeCurve:
+			weights[i] = float64(wallets - i)
+		case powerStakeCurve:
+			if param == 0 {
+				param = 1
+			}
+			weights[i] = 1 / math.Pow(float64(i+1), param)
+		case exponentialStakeCurve:
+			if param == 0 {
+				param = 100
+			}
+			if param < 1 {
+				return nil, fmt.Errorf("the exponential stake curve parameter must be at least 1")
+			}
+			if wallets > 1 {
+				weights[i] = math.Pow(param, -float64(i)/float64(wallets-1))
+			} else {
+				weights[i] = 1
+			}
+		default:
+			return nil, fmt.Errorf("unknown stake curve '%s'; the curves are even, linear, power and exponential", curve)
+		}
+	}
+	total := float64(0)
+	for _, w := range weights {
+		total += w
+	}
+	stakes := make([]float64, wallets)
+	stakeSum := float64(0)
+	for i, w := range weights {
+		if i == wallets-1 {
+			// use the last wallet to workaround roundoff and get back to 100
+			stakes[i] = 100.0 - stakeSum
+			break
+		}
+		stakes[i] = 100.0 * w / total
+		stakeSum += stakes[i]
+	}
+	return stakes, nil
+}
+
+// spreadAcrossRegions assigns each of the given number of hosts to a region, in proportion to the region weights,
+// using the largest remainder method. The hosts of a region are consecutive.
+func spreadAcrossRegions(regions []regionSpec, hosts int) []string {
+	total := float64(0)
+	for _, r := range regions {
+		total += r.Weight
+	}
+	counts := make([]int, len(regions))
+	remainders := make([]float64, len(regions))
+	assigned := 0
+	for i, r := range regions {
+		share := float64(hosts) * r.Weight / total
+		counts[i] = int(share)
+		remainders[i] = share - float64(counts[i])
+		assigned += counts[i]
+	}
+	order := make([]int, len(regions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return remainders[order[i]] > remainders[order[j]] })
+	for i := 0; assigned < hosts; i++ {
+		counts[order[i%len(order)]]++
+		assigned++
+	}
+	out := make([]string, 0, hosts)
+	for i, r := range regions {
+		for j := 0; j < counts[i]; j++ {
+			out = append(out, r.Name)
+		}
+	}
+	return out
+}
+
+// mergeConfigJSON merges the given config values over the raw config JSON override of a node template.
+func mergeConfigJSON(override string, values ...map[string]interface{}) (string, error) {
+	merged := make(map[string]interface{})
+	if strings.TrimSpace(override) != "" {
+		if err := json.Unmarshal([]byte(override), &merged); err != nil {
+			return "", fmt.Errorf("invalid ConfigJSONOverride: %v", err)
+		}
+	}
+	for _, v := range values {
+		for key, value := range v {
+			merged[key] = value
+		}
+	}
+	if len(merged) == 0 {
+		return "", nil
+	}
+	out, err := json.Marshal(merged)
+	return string(out), err
+}
+
+// roleDefaults returns the node template changes and the config values implied by the role of a node class.
+func roleDefaults(class nodeClassSpec, node *remote.NodeConfig) map[string]interface{} {
+	switch class.Role {
+	case relayRole:
+		node.IsRelay = true
+	case archivalRole:
+		node.IsRelay = class.Relay
+		return map[string]interface{}{"Archival": true}
+	case apiRole:
+		if node.APIEndpoint == "" {
+			node.APIEndpoint = "{{APIEndpoint}}"
+		}
+		if node.APIToken == "" {
+			node.APIToken = "{{APIToken}}"
+		}
+		return map[string]interface{}{"IsIndexerActive": true}
+	}
+	return nil
+}
+
+// generate generates the network template, genesis data and topology of the spec.
+func (spec networkSpec) generate() (network remote.DeployedNetworkConfig, genesis gen.GenesisData, topology topologySpec, err error) {
+	// the host and node indices of the participation nodes
+	var participationNodes [][2]int
+	for _, class := range spec.Classes {
+		base := spec.Node
+		if class.Node != nil {
+			base = *class.Node
+		}
+		base.AltConfigs = nil
+		base.NodeNameMatchRegex = ""
+		base.FractionApply = 0
+		base.Wallets = nil
+		defaults := roleDefaults(class, &base)
+		base.ConfigJSONOverride, err = mergeConfigJSON(base.ConfigJSONOverride, defaults, class.Config)
+		if err != nil {
+			err = fmt.Errorf("node class '%s': %v", class.Name, err)
+			return
+		}
+
+		regions := spec.Regions
+		if len(class.Regions) > 0 {
+			regions = nil
+			for _, r := range spec.Regions {
+				for _, name := range class.Regions {
+					if r.Name == name {
+						regions = append(regions, r)
+					}
+				}
+			}
+		}
+		nodesPerHost := class.NodesPerHost
+		if nodesPerHost == 0 {
+			nodesPerHost = 1
+		}
+		hosts := (class.Count + nodesPerHost - 1) / nodesPerHost
+		hostRegions := spreadAcrossRegions(regions, hosts)
+
+		firstHost := len(network.Hosts)
+		for i := 0; i < hosts; i++ {
+			name := class.hostPrefix() + strconv.Itoa(i+1)
+			network.Hosts = append(network.Hosts, remote.HostConfig{Name: name})
+			topology.Hosts = append(topology.Hosts, topologyHost{
+				Name:     name,
+				Template: strings.Replace(class.HostTemplate, regionToken, hostRegions[i], -1),
+			})
+		}
+		for i := 0; i < class.Count; i++ {
+			node := base
+			node.Name = class.Name + strconv.Itoa(i+1)
+			hosti := firstHost + i/nodesPerHost
+			network.Hosts[hosti].Nodes = append(network.Hosts[hosti].Nodes, node)
+			if class.Role == participationRole {
+				participationNodes = append(participationNodes, [2]int{hosti, len(network.Hosts[hosti].Nodes) - 1})
+			}
+		}
+	}
+
+	genesis = spec.Genesis
+	if genesis.LastPartKeyRound == 0 {
+		genesis.FirstPartKeyRound = gen.DefaultGenesis.FirstPartKeyRound
+		genesis.LastPartKeyRound = gen.DefaultGenesis.LastPartKeyRound
+	}
+	stakes, err := stakeCurve(spec.StakeCurve, spec.StakeCurveParam, spec.Wallets)
+	if err != nil {
+		return
+	}
+	genesis.Wallets = make([]gen.WalletData, spec.Wallets)
+	for i, stake := range stakes {
+		name := "Wallet" + strconv.Itoa(i+1) // Wallet names are 1-based for this template
+		genesis.Wallets[i] = gen.WalletData{
+			Name:   name,
+			Stake:  stake,
+			Online: i < spec.Wallets-spec.OfflineWallets,
+		}
+		// deal the wallets, from the richest, to the participation nodes in turn, so that the stake is spread evenly
+		index := participationNodes[i%len(participationNodes)]
+		node := &network.Hosts[index[0]].Nodes[index[1]]
+		node.Wallets = append(node.Wallets, remote.NodeWalletData{Name: name, ParticipationOnly: true})
+	}
+	return
+}
+
+// generateRecipe writes the recipe of the given spec, along with the network template, genesis data and topology
+// it refers to, into the given directory.
+func generateRecipe(spec networkSpec, dir string) error {
+	network, genesis, topology, err := spec.generate()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	r := recipe{
+		ConfigFile:        spec.ConfigFile,
+		GenesisFile:       "genesis.json",
+		HostTemplatesFile: spec.HostTemplatesFile,
+		NetworkFile:       "net.json",
+		TopologyFile:      "topology.json",
+	}
+	if err = saveTemplateToDisk(network, filepath.Join(dir, r.NetworkFile)); err != nil {
+		return err
+	}
+	if err = saveGenesisDataToDisk(genesis, filepath.Join(dir, r.GenesisFile)); err != nil {
+		return err
+	}
+	if err = saveJSONToDisk(topology, filepath.Join(dir, r.TopologyFile)); err != nil {
+		return err
+	}
+	return saveJSONToDisk(r, filepath.Join(dir, "recipe.json"))
+}
+
+func saveJSONToDisk(object interface{}, filename string) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err == nil {
+		defer f.Close()
+
+		enc := codecs.NewFormattedJSONEncoder(f)
+		err = enc.Encode(object)
+	}
+	return err
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/gen"
+	"github.com/algorand/go-algorand/netdeploy/remote"
+)
+
+func TestStakeCurve(t *testing.T) {
+	for _, curve := range []string{evenStakeCurve, linearStakeCurve, powerStakeCurve, exponentialStakeCurve} {
+		stakes, err := stakeCurve(curve, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, stakes, 10)
+		sum := float64(0)
+		for i, s := range stakes {
+			sum += s
+			if i > 0 {
+				require.True(t, s <= stakes[i-1]+1e-9, "%s stakes must not increase", curve)
+			}
+		}
+		require.InDelta(t, 100, sum, 1e-9)
+	}
+
+	stakes, err := stakeCurve(exponentialStakeCurve, 100, 3)
+	require.NoError(t, err)
+	require.InDelta(t, 100, stakes[0]/stakes[2], 1e-9)
+
+	_, err = stakeCurve("zipf", 0, 10)
+	require.Error(t, err)
+}
+
+func TestSpreadAcrossRegions(t *testing.T) {
+	regions := []regionSpec{{Name: "a", Weight: 3}, {Name: "b", Weight: 2}, {Name: "c", Weight: 1}}
+	require.Equal(t, []string{"a", "a", "a", "b", "b", "c"}, spreadAcrossRegions(regions, 6))
+	require.Equal(t, []string{"a", "b"}, spreadAcrossRegions(regions, 2))
+	require.Len(t, spreadAcrossRegions(regions, 7), 7)
+}
+
+func TestGenerateRecipeFromSpec(t *testing.T) {
+	spec, err := loadNetworkSpec(filepath.Join("..", "..", "test", "testdata", "deployednettemplates", "specs", "heterogeneous.json"))
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "netgoal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, generateRecipe(spec, dir))
+
+	var network remote.DeployedNetworkConfig
+	loadJSON(t, filepath.Join(dir, "net.json"), &network)
+	var genesis gen.GenesisData
+	loadJSON(t, filepath.Join(dir, "genesis.json"), &genesis)
+	var topology topologySpec
+	loadJSON(t, filepath.Join(dir, "topology.json"), &topology)
+	var r recipe
+	loadJSON(t, filepath.Join(dir, "recipe.json"), &r)
+	require.Equal(t, spec.ConfigFile, r.ConfigFile)
+
+	// 8 relays, 2 archival relays, 10 hosts of 4 participation nodes and 2 api nodes
+	require.Len(t, network.Hosts, 22)
+	require.Len(t, topology.Hosts, 22)
+	templates := make(map[string]string)
+	for _, host := range topology.Hosts {
+		templates[host.Name] = host.Template
+	}
+	require.Equal(t, "AWS-US-EAST-1-Large", templates["R1"])
+	require.Equal(t, "AWS-AP-SOUTHEAST-1-Large", templates["R8"])
+	require.Equal(t, "AWS-US-EAST-1-m5d.4xl", templates["A1"])
+	require.Equal(t, "AWS-EU-CENTRAL-1-m5d.4xl", templates["A2"])
+	require.Equal(t, "AWS-US-EAST-1-Small", templates["P2"])
+
+	roles := make(map[string]int)
+	wallets := make(map[string]bool)
+	for _, host := range network.Hosts {
+		for _, node := range host.Nodes {
+			var config map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(node.ConfigJSONOverride), &config))
+			require.Equal(t, float64(-1), config["DeadlockDetection"])
+			switch host.Name[0] {
+			case 'R':
+				roles["relay"]++
+				require.True(t, node.IsRelay)
+				require.Equal(t, float64(1000), config["IncomingConnectionsLimit"])
+			case 'A':
+				roles["archival"]++
+				require.True(t, node.IsRelay)
+				require.Equal(t, true, config["Archival"])
+			case 'N':
+				roles["participation"]++
+				require.False(t, node.IsRelay)
+				require.True(t, len(node.Wallets) == 2 || len(node.Wallets) == 3)
+			case 'P':
+				roles["api"]++
+				require.Equal(t, "{{APIEndpoint}}", node.APIEndpoint)
+				require.Equal(t, true, config["IsIndexerActive"])
+				require.Equal(t, "0.0.0.0:8080", config["EndpointAddress"])
+			}
+			if host.Name[0] != 'N' {
+				require.Empty(t, node.Wallets)
+			}
+			for _, w := range node.Wallets {
+				require.True(t, w.ParticipationOnly)
+				wallets[w.Name] = true
+			}
+		}
+	}
+	require.Equal(t, map[string]int{"relay": 8, "archival": 2, "participation": 40, "api": 2}, roles)
+	require.Len(t, wallets, 100)
+	require.Len(t, genesis.Wallets, 100)
+	require.True(t, genesis.Wallets[89].Online)
+	require.False(t, genesis.Wallets[90].Online)
+	require.True(t, genesis.Wallets[0].Stake > genesis.Wallets[99].Stake)
+}
+
+func TestNetworkSpecValidate(t *testing.T) {
+	valid := networkSpec{
+		Regions: []regionSpec{{Name: "a", Weight: 1}},
+		Classes: []nodeClassSpec{{Name: "node", Role: participationRole, Count: 1, HostTemplate: "t"}},
+		Wallets: 1,
+	}
+	require.NoError(t, valid.validate())
+
+	invalid := []func(spec *networkSpec){
+		func(spec *networkSpec) { spec.Regions = nil },
+		func(spec *networkSpec) { spec.Classes[0].Role = "indexer" },
+		func(spec *networkSpec) { spec.Classes[0].Regions = []string{"b"} },
+		func(spec *networkSpec) { spec.Classes[0].Relay = true },
+		func(spec *networkSpec) { spec.Classes[0].Role = relayRole },
+		func(spec *networkSpec) { spec.StakeCurve = "zipf" },
+		func(spec *networkSpec) { spec.Classes[0].Role = apiRole },
+		func(spec *networkSpec) {
+			spec.Classes = append(spec.Classes, nodeClassSpec{Name: "nonparticipating", Role: apiRole, Count: 1, HostTemplate: "t"})
+		},
+	}
+	for i, modify := range invalid {
+		spec := valid
+		spec.Classes = append([]nodeClassSpec(nil), valid.Classes...)
+		modify(&spec)
+		require.Error(t, spec.validate(), "case %d", i)
+	}
+}
+
+func loadJSON(t *testing.T, file string, object interface{}) {
+	data, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, object))
+}
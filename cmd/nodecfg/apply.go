@@ -17,6 +17,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -34,6 +35,10 @@ var applyHostName string
 var applyRootDir string
 var applyRootNodeDir string
 var applyPublicAddress string
+var applyDNSDryRun bool
+var applyDNSProvider string
+var applyTemplate string
+var applyConfigOverride string
 
 func init() {
 	applyCmd.Flags().StringVarP(&applyChannel, "channel", "c", "", "Channel for the nodes we are configuring")
@@ -47,6 +52,14 @@ func init() {
 	applyCmd.Flags().StringVarP(&applyRootNodeDir, "rootnodedir", "n", "", "The root directory for node directories")
 
 	applyCmd.Flags().StringVarP(&applyPublicAddress, "publicaddress", "a", "", "The public address to use if registering Relay or for Metrics")
+
+	applyCmd.Flags().BoolVar(&applyDNSDryRun, "dns-dry-run", false, "Print the DNS / SRV record API calls instead of executing them")
+
+	applyCmd.Flags().StringVar(&applyDNSProvider, "dns-provider", nodecfg.DNSProviderCloudflare, "DNS provider to register the DNS / SRV records with (cloudflare or route53)")
+
+	applyCmd.Flags().StringVarP(&applyTemplate, "template", "t", "", "Name of the host configuration to apply, when the host is configured from a shared host class rather than its own configuration")
+
+	applyCmd.Flags().StringVar(&applyConfigOverride, "config-override", "", "Raw json to merge into the config.json of every node, after the node's own overrides")
 }
 
 var applyCmd = &cobra.Command{
@@ -73,13 +86,13 @@ var applyCmd = &cobra.Command{
 			reportErrorf("Error creating data dir: %v", err)
 		}
 
-		if err := doApply(applyRootDir, applyRootNodeDir, applyChannel, applyHostName, applyPublicAddress); err != nil {
+		if err := doApply(applyRootDir, applyRootNodeDir, applyChannel, applyHostName, applyTemplate, applyPublicAddress, applyConfigOverride, applyDNSProvider, applyDNSDryRun); err != nil {
 			reportErrorf("Error applying configuration: %v", err)
 		}
 	},
 }
 
-func doApply(rootDir string, rootNodeDir, channel string, hostName string, dnsName string) (err error) {
+func doApply(rootDir string, rootNodeDir, channel string, hostName string, template string, dnsName string, configOverride string, dnsProvider string, dnsDryRun bool) (err error) {
 	var missing bool
 	var cfg remote.DeployedNetworkConfig
 	if rootDir == "" {
@@ -115,9 +128,15 @@ func doApply(rootDir string, rootNodeDir, channel string, hostName string, dnsNa
 		return fmt.Errorf("error loading configuration file: %v", err)
 	}
 
-	hostCfg, has := cfg.TryGetHostConfig(hostName)
+	if template == "" {
+		template = hostName
+	}
+	hostCfg, has := cfg.TryGetHostConfig(template)
 	if !has {
-		return fmt.Errorf("configuration does not include this host: %s", hostName)
+		return fmt.Errorf("configuration does not include this host: %s", template)
+	}
+	if err = applyConfigOverrideToNodes(hostCfg.Nodes, configOverride); err != nil {
+		return
 	}
 
 	if hostNeedsDNSName(hostCfg) && dnsName == "" {
@@ -125,11 +144,50 @@ func doApply(rootDir string, rootNodeDir, channel string, hostName string, dnsNa
 	}
 
 	fmt.Fprintf(os.Stdout, "Applying config for host '%s' (%d nodes)...\n", hostName, len(hostCfg.Nodes))
-	err = nodecfg.ApplyConfigurationToHost(hostCfg, rootDir, rootNodeDir, dnsName)
+	err = nodecfg.ApplyConfigurationToHost(hostCfg, rootDir, rootNodeDir, nodecfg.DNSOptions{
+		HostName:      hostName,
+		PublicAddress: dnsName,
+		Provider:      dnsProvider,
+		DryRun:        dnsDryRun,
+	})
 
 	return
 }
 
+// applyConfigOverrideToNodes merges the given config.json override into the overrides of each of the nodes.
+func applyConfigOverrideToNodes(nodes []remote.NodeConfig, configOverride string) error {
+	for i := range nodes {
+		merged, err := mergeConfigOverrides(nodes[i].ConfigJSONOverride, configOverride)
+		if err != nil {
+			return fmt.Errorf("error merging config overrides of node %s: %v", nodes[i].Name, err)
+		}
+		nodes[i].ConfigJSONOverride = merged
+	}
+	return nil
+}
+
+// mergeConfigOverrides merges the given config.json overrides, in order; later overrides take precedence.
+func mergeConfigOverrides(overrides ...string) (string, error) {
+	merged := make(map[string]json.RawMessage)
+	for _, override := range overrides {
+		if override == "" {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(override), &fields); err != nil {
+			return "", fmt.Errorf("invalid config override '%s': %v", override, err)
+		}
+		for name, value := range fields {
+			merged[name] = value
+		}
+	}
+	if len(merged) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(merged)
+	return string(data), err
+}
+
 func hostNeedsDNSName(config remote.HostConfig) bool {
 	for _, node := range config.Nodes {
 		if node.IsRelay || node.EnableMetrics {
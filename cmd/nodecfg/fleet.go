@@ -0,0 +1,507 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/algorand/go-algorand/netdeploy/remote/nodecfg"
+	"github.com/algorand/go-algorand/util/tar"
+)
+
+var fleetFile string
+var fleetHosts []string
+var fleetParallel int
+var fleetLogDir string
+var fleetDNSDryRun bool
+
+func init() {
+	rootCmd.AddCommand(fleetCmd)
+
+	fleetCmd.Flags().StringVarP(&fleetFile, "file", "f", "", "Fleet file (.json, .yaml or .yml) describing the hosts to configure")
+	fleetCmd.MarkFlagRequired("file")
+	fleetCmd.Flags().StringSliceVarP(&fleetHosts, "hosts", "H", nil, "Only configure the given hosts of the fleet (e.g. to retry the ones that failed)")
+	fleetCmd.Flags().IntVarP(&fleetParallel, "parallel", "p", 0, "Number of hosts to configure concurrently (overrides the fleet file; 0 uses the fleet file setting)")
+	fleetCmd.Flags().StringVarP(&fleetLogDir, "logdir", "l", "", "Directory to write the output of each host to (defaults to a new temp directory)")
+	fleetCmd.Flags().BoolVar(&fleetDNSDryRun, "dns-dry-run", false, "Print the DNS / SRV record API calls on the hosts instead of executing them")
+}
+
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "apply a node configuration to a fleet of hosts over ssh",
+	Long: `apply a node configuration to each of the hosts of a fleet file, by running 'nodecfg apply' on them over ssh.
+Each host is configured from its class (a shared host configuration plus config.json overrides), and registered with the DNS provider
+using the credentials of the local environment. Hosts are configured concurrently, and the status of each host is reported at the end.`,
+	Example: "nodecfg fleet -f relays.yaml -p 10",
+	Run: func(cmd *cobra.Command, args []string) {
+		spec, err := loadFleetSpec(fleetFile)
+		if err != nil {
+			reportErrorf("Error loading fleet file: %v", err)
+		}
+		hosts, err := spec.selectHosts(fleetHosts)
+		if err != nil {
+			reportErrorf("Error selecting hosts: %v", err)
+		}
+		if fleetParallel > 0 {
+			spec.Parallel = fleetParallel
+		}
+		env, err := dnsProviderEnv(spec.DNSProvider, os.Getenv)
+		if err != nil {
+			reportErrorf("Error getting DNS credentials: %v", err)
+		}
+		var archive []byte
+		if spec.RootDir != "" {
+			var buf bytes.Buffer
+			if err = tar.Compress(spec.RootDir, &buf); err != nil {
+				reportErrorf("Error packaging configuration rootdir: %v", err)
+			}
+			archive = buf.Bytes()
+		}
+		if fleetLogDir == "" {
+			if fleetLogDir, err = ioutil.TempDir("", "nodecfg-fleet-"); err != nil {
+				reportErrorf("Error creating log directory: %v", err)
+			}
+		} else if err = os.MkdirAll(fleetLogDir, 0700); err != nil {
+			reportErrorf("Error creating log directory: %v", err)
+		}
+
+		reportInfof("Configuring %d hosts of '%s' (%d at a time), logging to %s", len(hosts), spec.Channel, spec.parallel(), fleetLogDir)
+		fleet := fleetRunner{
+			spec:    spec,
+			env:     env,
+			archive: archive,
+			dnsDry:  fleetDNSDryRun,
+			logDir:  fleetLogDir,
+			run:     runSSH,
+		}
+		statuses := fleet.apply(context.Background(), hosts)
+		printFleetStatus(os.Stdout, statuses)
+		for _, status := range statuses {
+			if status.Err != nil {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// fleetSpec is the declarative configuration of a fleet of hosts.
+type fleetSpec struct {
+	// Channel is the channel of the configuration package the hosts are configured from.
+	Channel string `json:"channel" yaml:"channel"`
+	// RootDir is a local configuration rootdir to upload to the hosts; when empty, the hosts download the latest
+	// configuration package of the channel.
+	RootDir string `json:"rootdir,omitempty" yaml:"rootdir,omitempty"`
+	// RootNodeDir is the root directory for node directories on the hosts, as for 'nodecfg apply -n'.
+	RootNodeDir string `json:"rootnodedir,omitempty" yaml:"rootnodedir,omitempty"`
+	// Nodecfg is the path of nodecfg on the hosts; it defaults to the one installed in RootNodeDir.
+	Nodecfg string `json:"nodecfg,omitempty" yaml:"nodecfg,omitempty"`
+	// DNSProvider is the DNS provider to register the hosts with (cloudflare or route53).
+	DNSProvider string `json:"dnsprovider,omitempty" yaml:"dnsprovider,omitempty"`
+	// Parallel is the number of hosts to configure concurrently.
+	Parallel int                   `json:"parallel,omitempty" yaml:"parallel,omitempty"`
+	SSH      fleetSSH              `json:"ssh,omitempty" yaml:"ssh,omitempty"`
+	Classes  map[string]fleetClass `json:"classes,omitempty" yaml:"classes,omitempty"`
+	Hosts    []fleetHost           `json:"hosts" yaml:"hosts"`
+}
+
+// fleetSSH holds the ssh connection settings shared by all the hosts.
+type fleetSSH struct {
+	User         string   `json:"user,omitempty" yaml:"user,omitempty"`
+	Port         int      `json:"port,omitempty" yaml:"port,omitempty"`
+	IdentityFile string   `json:"identityfile,omitempty" yaml:"identityfile,omitempty"`
+	Options      []string `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// fleetClass is a class of identical hosts, such as the relays of a network.
+type fleetClass struct {
+	// HostConfig is the name of the host configuration (of the configuration package) applied to the hosts of
+	// the class; it defaults to the host names, for classes that only share config.json overrides.
+	HostConfig string `json:"hostconfig,omitempty" yaml:"hostconfig,omitempty"`
+	// ConfigOverride is raw json merged into the config.json of the nodes of the class.
+	ConfigOverride string `json:"configoverride,omitempty" yaml:"configoverride,omitempty"`
+}
+
+// fleetHost is a single host of the fleet.
+type fleetHost struct {
+	// Name is the name the host is configured and registered in DNS under, e.g. R1 for r1.<network>.algodev.network
+	Name string `json:"name" yaml:"name"`
+	// Address is the ssh address of the host; it defaults to PublicAddress.
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+	// PublicAddress is the public name or IP address of the host, as for 'nodecfg apply -a'.
+	PublicAddress  string `json:"publicaddress,omitempty" yaml:"publicaddress,omitempty"`
+	Class          string `json:"class,omitempty" yaml:"class,omitempty"`
+	ConfigOverride string `json:"configoverride,omitempty" yaml:"configoverride,omitempty"`
+}
+
+// defaultFleetParallel is the number of hosts configured concurrently when the fleet file doesn't say.
+const defaultFleetParallel = 8
+
+func loadFleetSpec(fileName string) (spec fleetSpec, err error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, &spec)
+	default:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&spec)
+	}
+	if err != nil {
+		err = fmt.Errorf("unable to parse %s: %v", fileName, err)
+		return
+	}
+	if spec.RootDir != "" && !filepath.IsAbs(spec.RootDir) {
+		spec.RootDir = filepath.Join(filepath.Dir(fileName), spec.RootDir)
+	}
+	err = spec.validate()
+	return
+}
+
+func (spec fleetSpec) validate() error {
+	if spec.Channel == "" {
+		return fmt.Errorf("channel not specified")
+	}
+	if len(spec.Hosts) == 0 {
+		return fmt.Errorf("no hosts specified")
+	}
+	if nodecfg.DNSProviderEnvironment(spec.DNSProvider) == nil {
+		return fmt.Errorf("unknown DNS provider '%s'", spec.DNSProvider)
+	}
+	for name, class := range spec.Classes {
+		if _, err := mergeConfigOverrides(class.ConfigOverride); err != nil {
+			return fmt.Errorf("class %s: %v", name, err)
+		}
+	}
+	names := make(map[string]bool)
+	for _, host := range spec.Hosts {
+		if host.Name == "" {
+			return fmt.Errorf("host without a name")
+		}
+		if names[host.Name] {
+			return fmt.Errorf("host %s is specified more than once", host.Name)
+		}
+		names[host.Name] = true
+		if host.Address == "" && host.PublicAddress == "" {
+			return fmt.Errorf("host %s has neither an address nor a public address", host.Name)
+		}
+		if _, has := spec.Classes[host.Class]; host.Class != "" && !has {
+			return fmt.Errorf("host %s has an unknown class '%s'", host.Name, host.Class)
+		}
+		if _, err := mergeConfigOverrides(host.ConfigOverride); err != nil {
+			return fmt.Errorf("host %s: %v", host.Name, err)
+		}
+	}
+	return nil
+}
+
+// selectHosts returns the hosts of the given names, or all the hosts if no names are given.
+func (spec fleetSpec) selectHosts(names []string) ([]fleetHost, error) {
+	if len(names) == 0 {
+		return spec.Hosts, nil
+	}
+	var hosts []fleetHost
+	for _, name := range names {
+		found := false
+		for _, host := range spec.Hosts {
+			if strings.EqualFold(host.Name, name) {
+				hosts = append(hosts, host)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("host %s is not part of the fleet", name)
+		}
+	}
+	return hosts, nil
+}
+
+func (spec fleetSpec) parallel() int {
+	if spec.Parallel > 0 {
+		return spec.Parallel
+	}
+	return defaultFleetParallel
+}
+
+// sshArgs returns the ssh arguments for running a shell script, fed on stdin, on the given host.
+func (spec fleetSpec) sshArgs(host fleetHost) []string {
+	args := []string{"-o", "BatchMode=yes"}
+	if spec.SSH.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(spec.SSH.Port))
+	}
+	if spec.SSH.IdentityFile != "" {
+		args = append(args, "-i", spec.SSH.IdentityFile)
+	}
+	for _, option := range spec.SSH.Options {
+		args = append(args, "-o", option)
+	}
+	target := host.Address
+	if target == "" {
+		target = host.PublicAddress
+	}
+	if spec.SSH.User != "" {
+		target = spec.SSH.User + "@" + target
+	}
+	return append(args, target, "bash -s")
+}
+
+// hostScript returns the shell script configuring the given host. The script is fed to the remote shell on stdin, so
+// that the DNS credentials and the configuration package don't show up in the command line of any process.
+func (spec fleetSpec) hostScript(host fleetHost, env map[string]string, archive []byte, dnsDryRun bool) (string, error) {
+	class := spec.Classes[host.Class]
+	override, err := mergeConfigOverrides(class.ConfigOverride, host.ConfigOverride)
+	if err != nil {
+		return "", err
+	}
+
+	var script bytes.Buffer
+	script.WriteString("set -e\n")
+	for _, name := range nodecfg.DNSProviderEnvironment(spec.DNSProvider) {
+		if value, has := env[name]; has {
+			fmt.Fprintf(&script, "export %s=%s\n", name, shellQuote(value))
+		}
+	}
+
+	nodecfgPath := spec.Nodecfg
+	if nodecfgPath == "" {
+		nodecfgPath = "nodecfg"
+		if spec.RootNodeDir != "" {
+			nodecfgPath = strings.TrimSuffix(spec.RootNodeDir, "/") + "/nodecfg"
+		}
+	}
+	args := []string{remotePath(nodecfgPath), "apply", "-c", shellQuote(spec.Channel), "-H", shellQuote(host.Name)}
+	if class.HostConfig != "" {
+		args = append(args, "-t", shellQuote(class.HostConfig))
+	}
+	if host.PublicAddress != "" {
+		args = append(args, "-a", shellQuote(host.PublicAddress))
+	}
+	if spec.RootNodeDir != "" {
+		args = append(args, "-n", remotePath(spec.RootNodeDir))
+	}
+	if spec.DNSProvider != "" {
+		args = append(args, "--dns-provider", shellQuote(spec.DNSProvider))
+	}
+	if dnsDryRun {
+		args = append(args, "--dns-dry-run")
+	}
+	if override != "" {
+		args = append(args, "--config-override", shellQuote(override))
+	}
+
+	if archive != nil {
+		configDir := "\"$HOME\"/.nodecfg/" + shellQuote(spec.Channel)
+		fmt.Fprintf(&script, "rm -rf %s\nmkdir -p %s\n", configDir, configDir)
+		fmt.Fprintf(&script, "base64 -d > %s.tar.gz <<'NODECFG_CONFIG_PACKAGE'\n", configDir)
+		encoded := base64.StdEncoding.EncodeToString(archive)
+		for len(encoded) > 76 {
+			script.WriteString(encoded[:76] + "\n")
+			encoded = encoded[76:]
+		}
+		script.WriteString(encoded + "\nNODECFG_CONFIG_PACKAGE\n")
+		fmt.Fprintf(&script, "tar -C %s -xzmf %s.tar.gz\nrm -f %s.tar.gz\n", configDir, configDir, configDir)
+		args = append(args, "-r", configDir)
+	}
+	script.WriteString(strings.Join(args, " ") + "\n")
+	return script.String(), nil
+}
+
+// shellQuote quotes the given string for the remote shell.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// remotePath quotes the given path for the remote shell, expanding a leading ~ to the remote home directory.
+func remotePath(path string) string {
+	if path == "~" {
+		return "\"$HOME\""
+	}
+	if strings.HasPrefix(path, "~/") {
+		return "\"$HOME\"/" + shellQuote(path[2:])
+	}
+	return shellQuote(path)
+}
+
+// dnsProviderEnv collects the credentials of the given DNS provider from the local environment.
+func dnsProviderEnv(provider string, getenv func(string) string) (map[string]string, error) {
+	env := make(map[string]string)
+	var missing []string
+	for _, name := range nodecfg.DNSProviderEnvironment(provider) {
+		if value := getenv(name); value != "" {
+			env[name] = value
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing %s from ENV", strings.Join(missing, ", "))
+	}
+	return env, nil
+}
+
+// sshRunFunc runs ssh with the given arguments and stdin, writing its combined output to the given writer.
+type sshRunFunc func(ctx context.Context, args []string, stdin io.Reader, output io.Writer) error
+
+func runSSH(ctx context.Context, args []string, stdin io.Reader, output io.Writer) error {
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = output
+	cmd.Stderr = output
+	return cmd.Run()
+}
+
+// fleetHostStatus is the outcome of configuring a single host.
+type fleetHostStatus struct {
+	Host     fleetHost
+	Err      error
+	Duration time.Duration
+	LogFile  string
+	// LastLine is the last line of output of the host, which usually explains failures.
+	LastLine string
+}
+
+type fleetRunner struct {
+	spec    fleetSpec
+	env     map[string]string
+	archive []byte
+	dnsDry  bool
+	logDir  string
+	run     sshRunFunc
+
+	outputMu sync.Mutex
+}
+
+// apply configures the given hosts, at most spec.Parallel at a time, and returns their statuses in the order of the hosts.
+func (f *fleetRunner) apply(ctx context.Context, hosts []fleetHost) []fleetHostStatus {
+	statuses := make([]fleetHostStatus, len(hosts))
+	slots := make(chan struct{}, f.spec.parallel())
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host fleetHost) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			statuses[i] = f.applyHost(ctx, host)
+
+			f.outputMu.Lock()
+			defer f.outputMu.Unlock()
+			if statuses[i].Err != nil {
+				fmt.Printf("[%s] failed after %v: %v\n", host.Name, statuses[i].Duration, statuses[i].Err)
+			} else {
+				fmt.Printf("[%s] done in %v\n", host.Name, statuses[i].Duration)
+			}
+		}(i, host)
+	}
+	wg.Wait()
+	return statuses
+}
+
+func (f *fleetRunner) applyHost(ctx context.Context, host fleetHost) (status fleetHostStatus) {
+	status.Host = host
+	start := time.Now()
+	defer func() {
+		status.Duration = time.Since(start).Round(time.Second)
+	}()
+
+	script, err := f.spec.hostScript(host, f.env, f.archive, f.dnsDry)
+	if err != nil {
+		status.Err = err
+		return
+	}
+	status.LogFile = filepath.Join(f.logDir, host.Name+".log")
+	logFile, err := os.Create(status.LogFile)
+	if err != nil {
+		status.Err = err
+		return
+	}
+	defer logFile.Close()
+
+	var tail lastLineWriter
+	status.Err = f.run(ctx, f.spec.sshArgs(host), strings.NewReader(script), io.MultiWriter(logFile, &tail))
+	status.LastLine = tail.String()
+	return
+}
+
+// lastLineWriter keeps the last non empty line written to it.
+type lastLineWriter struct {
+	last    []byte
+	current []byte
+}
+
+func (w *lastLineWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b != '\n' {
+			w.current = append(w.current, b)
+			continue
+		}
+		if len(bytes.TrimSpace(w.current)) > 0 {
+			w.last = append(w.last[:0], w.current...)
+		}
+		w.current = w.current[:0]
+	}
+	return len(p), nil
+}
+
+func (w *lastLineWriter) String() string {
+	if len(bytes.TrimSpace(w.current)) > 0 {
+		return string(bytes.TrimSpace(w.current))
+	}
+	return string(bytes.TrimSpace(w.last))
+}
+
+func printFleetStatus(out io.Writer, statuses []fleetHostStatus) {
+	failed := 0
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HOST\tCLASS\tSTATUS\tDURATION\tDETAILS")
+	for _, status := range statuses {
+		result, details := "ok", ""
+		if status.Err != nil {
+			failed++
+			result = "FAILED"
+			details = status.Err.Error()
+			if status.LastLine != "" {
+				details = status.LastLine
+			}
+			if status.LogFile != "" {
+				details += " (see " + status.LogFile + ")"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%s\n", status.Host.Name, status.Host.Class, result, status.Duration, details)
+	}
+	w.Flush()
+	fmt.Fprintf(out, "%d of %d hosts configured successfully\n", len(statuses)-failed, len(statuses))
+}
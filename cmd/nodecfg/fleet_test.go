@@ -0,0 +1,228 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/util/tar"
+)
+
+const testFleetYAML = `channel: testnet
+rootnodedir: ~/algorand/testnet
+dnsprovider: route53
+parallel: 2
+ssh:
+  user: ubuntu
+  port: 2222
+  identityfile: /keys/fleet
+  options: ["StrictHostKeyChecking=no"]
+classes:
+  relay:
+    hostconfig: R1
+    configoverride: '{"IncomingConnectionsLimit": 1000, "BaseLoggerDebugLevel": 4}'
+hosts:
+  - name: R1
+    publicaddress: 10.0.0.1
+    class: relay
+  - name: R2
+    address: r2.internal
+    publicaddress: r2.example.com
+    class: relay
+    configoverride: '{"BaseLoggerDebugLevel": 5}'
+`
+
+func writeTestFleet(t *testing.T, dir string, text string) string {
+	file := filepath.Join(dir, "fleet.yaml")
+	require.NoError(t, ioutil.WriteFile(file, []byte(text), 0666))
+	return file
+}
+
+func TestLoadFleetSpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fleet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	spec, err := loadFleetSpec(writeTestFleet(t, dir, testFleetYAML))
+	require.NoError(t, err)
+	require.Equal(t, "testnet", spec.Channel)
+	require.Equal(t, 2, spec.parallel())
+	require.Equal(t, "R1", spec.Classes["relay"].HostConfig)
+	require.Equal(t, 2, len(spec.Hosts))
+
+	require.Equal(t, []string{"-o", "BatchMode=yes", "-p", "2222", "-i", "/keys/fleet", "-o", "StrictHostKeyChecking=no", "ubuntu@10.0.0.1", "bash -s"}, spec.sshArgs(spec.Hosts[0]))
+	require.Equal(t, "ubuntu@r2.internal", spec.sshArgs(spec.Hosts[1])[8])
+
+	hosts, err := spec.selectHosts([]string{"r2"})
+	require.NoError(t, err)
+	require.Equal(t, []fleetHost{spec.Hosts[1]}, hosts)
+	_, err = spec.selectHosts([]string{"R3"})
+	require.Error(t, err)
+
+	testcases := []struct {
+		text string
+		err  string
+	}{
+		{strings.Replace(testFleetYAML, "channel: testnet", "", 1), "channel not specified"},
+		{strings.Replace(testFleetYAML, "route53", "dyndns", 1), "unknown DNS provider"},
+		{strings.Replace(testFleetYAML, "name: R2", "name: R1", 1), "specified more than once"},
+		{strings.Replace(testFleetYAML, "class: relay\n  - name", "class: archive\n  - name", 1), "unknown class 'archive'"},
+		{strings.Replace(testFleetYAML, `'{"BaseLoggerDebugLevel": 5}'`, "nope", 1), "invalid config override"},
+		{testFleetYAML + "unknown: true\n", "unknown"},
+	}
+	for _, testcase := range testcases {
+		_, err = loadFleetSpec(writeTestFleet(t, dir, testcase.text))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), testcase.err)
+	}
+}
+
+func TestMergeConfigOverrides(t *testing.T) {
+	merged, err := mergeConfigOverrides(`{"A": 1, "B": "x"}`, "", `{"B": "y", "C": true}`)
+	require.NoError(t, err)
+	require.Equal(t, `{"A":1,"B":"y","C":true}`, merged)
+
+	merged, err = mergeConfigOverrides("", "")
+	require.NoError(t, err)
+	require.Equal(t, "", merged)
+
+	_, err = mergeConfigOverrides(`[1]`)
+	require.Error(t, err)
+}
+
+func TestDNSProviderEnv(t *testing.T) {
+	env := map[string]string{"ROUTE53_HOSTED_ZONE_ID": "zone", "AWS_ACCESS_KEY_ID": "id"}
+	_, err := dnsProviderEnv("route53", func(name string) string { return env[name] })
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "AWS_SECRET_ACCESS_KEY")
+
+	env["AWS_SECRET_ACCESS_KEY"] = "it's secret"
+	values, err := dnsProviderEnv("route53", func(name string) string { return env[name] })
+	require.NoError(t, err)
+	require.Equal(t, env, values)
+}
+
+// TestHostScript runs the generated script locally, against a nodecfg stub that prints its arguments.
+func TestHostScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	dir, err := ioutil.TempDir("", "fleet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configDir := filepath.Join(dir, "config")
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "genesisdata"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(configDir, "genesisdata", "genesis.json"), []byte("{}"), 0666))
+	var archive bytes.Buffer
+	require.NoError(t, tar.Compress(configDir, &archive))
+
+	home := filepath.Join(dir, "home")
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "algorand", "testnet"), 0700))
+	stub := "#!/bin/bash\nfor arg in \"$@\"; do echo \"[$arg]\"; done\necho \"zone=$ROUTE53_HOSTED_ZONE_ID secret=$AWS_SECRET_ACCESS_KEY\"\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(home, "algorand", "testnet", "nodecfg"), []byte(stub), 0700))
+
+	spec, err := loadFleetSpec(writeTestFleet(t, dir, testFleetYAML))
+	require.NoError(t, err)
+	env := map[string]string{"ROUTE53_HOSTED_ZONE_ID": "zone", "AWS_ACCESS_KEY_ID": "id", "AWS_SECRET_ACCESS_KEY": "it's secret"}
+	script, err := spec.hostScript(spec.Hosts[1], env, archive.Bytes(), true)
+	require.NoError(t, err)
+	require.NotContains(t, script, "it's secret")
+
+	cmd := exec.Command("bash", "-s")
+	cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+	cmd.Stdin = strings.NewReader(script)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	require.NoError(t, err, stderr.String())
+
+	expected := fmt.Sprintf("[apply]\n[-c]\n[testnet]\n[-H]\n[R2]\n[-t]\n[R1]\n[-a]\n[r2.example.com]\n[-n]\n[%s/algorand/testnet]\n"+
+		"[--dns-provider]\n[route53]\n[--dns-dry-run]\n[--config-override]\n[{\"BaseLoggerDebugLevel\":5,\"IncomingConnectionsLimit\":1000}]\n"+
+		"[-r]\n[%s/.nodecfg/testnet]\nzone=zone secret=it's secret\n", home, home)
+	require.Equal(t, expected, string(output))
+	require.FileExists(t, filepath.Join(home, ".nodecfg", "testnet", "genesisdata", "genesis.json"))
+}
+
+func TestFleetRunner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fleet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	spec := fleetSpec{Channel: "testnet", Parallel: 2}
+	for i := 1; i <= 5; i++ {
+		spec.Hosts = append(spec.Hosts, fleetHost{Name: fmt.Sprintf("R%d", i), PublicAddress: fmt.Sprintf("10.0.0.%d", i)})
+	}
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	run := func(ctx context.Context, args []string, stdin io.Reader, output io.Writer) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+
+		script, _ := ioutil.ReadAll(stdin)
+		fmt.Fprintf(output, "connecting to %s\n", args[len(args)-2])
+		if strings.Contains(string(script), "'R3'") {
+			fmt.Fprintf(output, "Error applying configuration: configuration does not include this host: R3\n\n")
+			return fmt.Errorf("exit status 1")
+		}
+		return nil
+	}
+	fleet := fleetRunner{spec: spec, logDir: dir, run: run}
+	statuses := fleet.apply(context.Background(), spec.Hosts)
+	require.Equal(t, 5, len(statuses))
+	require.True(t, maxRunning <= 2)
+	for i, status := range statuses {
+		require.Equal(t, spec.Hosts[i], status.Host)
+		if status.Host.Name == "R3" {
+			require.Error(t, status.Err)
+			require.Equal(t, "Error applying configuration: configuration does not include this host: R3", status.LastLine)
+		} else {
+			require.NoError(t, status.Err)
+		}
+		log, err := ioutil.ReadFile(status.LogFile)
+		require.NoError(t, err)
+		require.Contains(t, string(log), "connecting to "+status.Host.PublicAddress)
+	}
+
+	var report bytes.Buffer
+	printFleetStatus(&report, statuses)
+	require.Contains(t, report.String(), "FAILED")
+	require.Contains(t, report.String(), "R3.log")
+	require.Contains(t, report.String(), "4 of 5 hosts configured successfully")
+}
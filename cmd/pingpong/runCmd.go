@@ -49,6 +49,7 @@ var saveConfig bool
 var useDefault bool
 var quietish bool
 var randomNote bool
+var profileFile string
 
 func init() {
 	rootCmd.AddCommand(runCmd)
@@ -73,6 +74,7 @@ func init() {
 	runCmd.Flags().BoolVar(&useDefault, "reset", false, "Reset to the default configuration (not read from disk)")
 	runCmd.Flags().BoolVar(&quietish, "quiet", false, "quietish stdout logging")
 	runCmd.Flags().BoolVar(&randomNote, "randomnote", false, "generates a random byte array between 0-1024 bytes long")
+	runCmd.Flags().StringVar(&profileFile, "profile", "", "Workload profile file (JSON) describing the transaction types, note sizes, rate and number of accounts to use")
 }
 
 var runCmd = &cobra.Command{
@@ -176,6 +178,16 @@ var runCmd = &cobra.Command{
 		if randomNote {
 			cfg.RandomNote = true
 		}
+		if profileFile != "" {
+			profile, err := pingpong.LoadProfileFromFile(profileFile)
+			if err != nil {
+				reportErrorf("Error loading workload profile from '%s': %v\n", profileFile, err)
+			}
+			cfg.Profile = &profile
+		}
+		if cfg.Profile != nil && cfg.Profile.Accounts > 0 {
+			cfg.NumPartAccounts = cfg.Profile.Accounts
+		}
 
 		reportInfof("Preparing to initialize PingPong with config:\n")
 		cfg.Dump(os.Stdout)
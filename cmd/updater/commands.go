@@ -33,6 +33,9 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(sendCmd)
 	rootCmd.AddCommand(getToolsCmd)
+	rootCmd.AddCommand(signCmd)
+	rootCmd.AddCommand(deltaCmd)
+	rootCmd.AddCommand(healthCmd)
 
 	rootCmd.PersistentFlags().StringVarP(&channel, "channel", "c", "", "Channel on which to publish the update (required)")
 	rootCmd.MarkPersistentFlagRequired("channel")
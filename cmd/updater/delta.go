@@ -0,0 +1,240 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// deltaBlockSize is the size of the base archive blocks the delta generation looks for in the target archive.
+const deltaBlockSize = 4096
+
+// deltaSuffix is the suffix of delta files.
+const deltaSuffix = ".delta"
+
+// bundleDelta reconstructs the tar archive of a release bundle from the tar archive of an earlier release.
+type bundleDelta struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	BaseDigest   crypto.Digest `codec:"base"`
+	TargetDigest crypto.Digest `codec:"target"`
+	TargetSize   uint64        `codec:"size"`
+	Ops          []deltaOp     `codec:"ops"`
+}
+
+// deltaOp either copies Length bytes of the base archive from Offset, or appends Data when it's not empty.
+type deltaOp struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Offset uint64 `codec:"off"`
+	Length uint64 `codec:"len"`
+	Data   []byte `codec:"data"`
+}
+
+// rollingChecksum is the adler32 like weak checksum of a window of bytes, which can be rolled one byte at a time.
+type rollingChecksum struct {
+	a, b uint32
+	n    uint32
+}
+
+func newRollingChecksum(window []byte) (c rollingChecksum) {
+	c.n = uint32(len(window))
+	for i, v := range window {
+		c.a += uint32(v)
+		c.b += (c.n - uint32(i)) * uint32(v)
+	}
+	return
+}
+
+func (c *rollingChecksum) roll(out, in byte) {
+	c.a += uint32(in) - uint32(out)
+	c.b += c.a - c.n*uint32(out)
+}
+
+func (c rollingChecksum) sum() uint32 {
+	return (c.a & 0xffff) | (c.b << 16)
+}
+
+// makeDelta computes the delta from the base archive to the target archive, by looking up the blocks of the base
+// archive in the target archive, rsync style.
+func makeDelta(base []byte, target []byte) bundleDelta {
+	delta := bundleDelta{
+		BaseDigest:   crypto.Hash(base),
+		TargetDigest: crypto.Hash(target),
+		TargetSize:   uint64(len(target)),
+	}
+	blocks := make(map[uint32][]int)
+	for offset := 0; offset+deltaBlockSize <= len(base); offset += deltaBlockSize {
+		sum := newRollingChecksum(base[offset : offset+deltaBlockSize]).sum()
+		blocks[sum] = append(blocks[sum], offset)
+	}
+
+	literalStart := 0
+	addCopy := func(offset, length int) {
+		if last := len(delta.Ops) - 1; last >= 0 && len(delta.Ops[last].Data) == 0 && delta.Ops[last].Offset+delta.Ops[last].Length == uint64(offset) {
+			delta.Ops[last].Length += uint64(length)
+			return
+		}
+		delta.Ops = append(delta.Ops, deltaOp{Offset: uint64(offset), Length: uint64(length)})
+	}
+	flushLiteral := func(end int) {
+		if end > literalStart {
+			delta.Ops = append(delta.Ops, deltaOp{Length: uint64(end - literalStart), Data: target[literalStart:end]})
+		}
+	}
+
+	pos := 0
+	var checksum rollingChecksum
+	if len(target) >= deltaBlockSize {
+		checksum = newRollingChecksum(target[:deltaBlockSize])
+	}
+	for pos+deltaBlockSize <= len(target) {
+		match := -1
+		for _, offset := range blocks[checksum.sum()] {
+			if bytes.Equal(base[offset:offset+deltaBlockSize], target[pos:pos+deltaBlockSize]) {
+				match = offset
+				break
+			}
+		}
+		if match < 0 {
+			if pos+deltaBlockSize < len(target) {
+				checksum.roll(target[pos], target[pos+deltaBlockSize])
+			}
+			pos++
+			continue
+		}
+		flushLiteral(pos)
+		// extend the match past the block, as far as the archives agree
+		length := deltaBlockSize
+		for match+length < len(base) && pos+length < len(target) && base[match+length] == target[pos+length] {
+			length++
+		}
+		addCopy(match, length)
+		pos += length
+		literalStart = pos
+		if pos+deltaBlockSize <= len(target) {
+			checksum = newRollingChecksum(target[pos : pos+deltaBlockSize])
+		}
+	}
+	flushLiteral(len(target))
+	return delta
+}
+
+// apply reconstructs the target archive from the base archive.
+func (delta bundleDelta) apply(base []byte) ([]byte, error) {
+	if crypto.Hash(base) != delta.BaseDigest {
+		return nil, fmt.Errorf("the delta doesn't apply to this base archive")
+	}
+	target := make([]byte, 0, delta.TargetSize)
+	for _, op := range delta.Ops {
+		if len(op.Data) > 0 {
+			target = append(target, op.Data...)
+			continue
+		}
+		if op.Offset+op.Length > uint64(len(base)) || op.Offset+op.Length < op.Offset {
+			return nil, fmt.Errorf("delta copies past the end of the base archive")
+		}
+		target = append(target, base[op.Offset:op.Offset+op.Length]...)
+	}
+	if crypto.Hash(target) != delta.TargetDigest {
+		return nil, fmt.Errorf("the reconstructed archive doesn't match the delta digest")
+	}
+	return target, nil
+}
+
+// literalSize returns the number of literal bytes the delta carries.
+func (delta bundleDelta) literalSize() (size uint64) {
+	for _, op := range delta.Ops {
+		size += uint64(len(op.Data))
+	}
+	return
+}
+
+func writeDelta(w io.Writer, delta bundleDelta) error {
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(protocol.Encode(delta)); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func readDelta(r io.Reader) (delta bundleDelta, err error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return
+	}
+	data, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(data, &delta)
+	return
+}
+
+// readBundleArchive returns the uncompressed tar archive of the given bundle file.
+func readBundleArchive(bundleFile string) ([]byte, error) {
+	f, err := os.Open(bundleFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(gz)
+}
+
+// writeBundleArchive writes the given tar archive into a gzip compressed bundle file.
+func writeBundleArchive(bundleFile string, archive []byte) error {
+	f, err := os.Create(bundleFile)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	_, err = gz.Write(archive)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// versionString formats a version number returned by getVersionFromName as major.minor.build
+func versionString(version uint64) string {
+	return fmt.Sprintf("%d.%d.%d", version>>32, (version>>16)&0xffff, version&0xffff)
+}
+
+// deltaName returns the name of the delta from the given version to the given bundle,
+// e.g. nodedelta_stable_linux-amd64_1.0.5_1.0.4.delta for node_stable_linux-amd64_1.0.5.tar.gz
+// Deltas use their own prefix, so that they'd never be mistaken for bundles.
+func deltaName(bundleName string, fromVersion uint64) string {
+	name := strings.TrimSuffix(bundleName, ".tar.gz")
+	name = strings.TrimPrefix(name, "node_")
+	return fmt.Sprintf("nodedelta_%s_%s%s", name, versionString(fromVersion), deltaSuffix)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeltaRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	base := make([]byte, 20*deltaBlockSize+123)
+	rng.Read(base)
+
+	// the target inserts, removes and modifies data throughout the base
+	var target []byte
+	target = append(target, []byte("new header")...)
+	target = append(target, base[:5*deltaBlockSize]...)
+	target = append(target, bytes.Repeat([]byte{7}, 3000)...)
+	target = append(target, base[7*deltaBlockSize:15*deltaBlockSize]...)
+	target[len(target)-100] ^= 0xff
+	target = append(target, base[15*deltaBlockSize+17:]...)
+
+	delta := makeDelta(base, target)
+	require.True(t, delta.literalSize() < uint64(3*deltaBlockSize), "delta carries %d literal bytes", delta.literalSize())
+
+	var encoded bytes.Buffer
+	require.NoError(t, writeDelta(&encoded, delta))
+	decoded, err := readDelta(&encoded)
+	require.NoError(t, err)
+
+	reconstructed, err := decoded.apply(base)
+	require.NoError(t, err)
+	require.Equal(t, target, reconstructed)
+
+	// the delta refuses to apply to another base
+	base[0] ^= 0xff
+	_, err = decoded.apply(base)
+	require.Error(t, err)
+}
+
+func TestDeltaSmallArchives(t *testing.T) {
+	for _, test := range []struct{ base, target []byte }{
+		{nil, []byte("target")},
+		{[]byte("base"), nil},
+		{[]byte("same"), []byte("same")},
+	} {
+		delta := makeDelta(test.base, test.target)
+		reconstructed, err := delta.apply(test.base)
+		require.NoError(t, err)
+		require.Equal(t, len(test.target), len(reconstructed))
+		require.True(t, bytes.Equal(test.target, reconstructed))
+	}
+}
+
+func TestDeltaName(t *testing.T) {
+	version, err := getVersionFromName("node_stable_linux-amd64_1.0.4.tar.gz")
+	require.NoError(t, err)
+	name := deltaName("node_stable_linux-amd64_1.0.5.tar.gz", version)
+	require.Equal(t, "nodedelta_stable_linux-amd64_1.0.5_1.0.4.delta", name)
+
+	// the delta is named after the version it reconstructs
+	target, err := getVersionFromName(name)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.5", versionString(target))
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	healthDataDir string
+	healthBinDir  string
+	healthTimeout time.Duration
+)
+
+func init() {
+	healthCmd.Flags().StringVarP(&healthDataDir, "datadir", "d", "", "Data directory of the node to check (required)")
+	healthCmd.Flags().StringVarP(&healthBinDir, "bindir", "p", "", "Binaries directory of the node; defaults to the updater directory")
+	healthCmd.Flags().DurationVarP(&healthTimeout, "timeout", "t", 5*time.Minute, "How long to wait for the node to become healthy")
+	healthCmd.MarkFlagRequired("datadir")
+}
+
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Wait for a node to become healthy",
+	Long:  "Wait for a node to serve its REST API and advance its round, failing after the timeout. Used to roll back failed updates.",
+	Run: func(cmd *cobra.Command, args []string) {
+		connect := func() (nodeClient, error) {
+			return makeNodeClient(binDirOrDefault(healthBinDir), healthDataDir)
+		}
+		if err := waitForHealthyNode(connect, healthTimeout, 5*time.Second); err != nil {
+			exitErrorf("Node in %s is unhealthy: %v", healthDataDir, err)
+		}
+		fmt.Printf("Node in %s is healthy\n", healthDataDir)
+	},
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/nodecontrol"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// defaultRolloutApproval is the share of the recent block proposers which must approve the protocol of a release,
+// before a staged rollout installs it.
+const defaultRolloutApproval = 0.5
+
+// nodeClient is the subset of the algod REST client the updater uses.
+type nodeClient interface {
+	Status() (models.NodeStatus, error)
+	Block(round uint64) (models.Block, error)
+}
+
+func makeNodeClient(binDir, dataDir string) (nodeClient, error) {
+	nc := nodecontrol.MakeNodeController(binDir, dataDir)
+	return nc.AlgodClient()
+}
+
+// rolloutReady decides whether a staged rollout of a release supporting the given protocol may proceed, based on the
+// upgrade state of the latest block: the network majority runs the release once most proposers approve its protocol.
+func rolloutReady(block models.Block, releaseProtocol string, approval float64) (ready bool, reason string) {
+	if releaseProtocol == "" {
+		return false, "the release doesn't declare its protocol"
+	}
+	if block.CurrentProtocol == releaseProtocol {
+		return true, fmt.Sprintf("the network already runs protocol %s", releaseProtocol)
+	}
+	params, known := config.Consensus[protocol.ConsensusVersion(block.CurrentProtocol)]
+	if !known {
+		return true, fmt.Sprintf("the network runs protocol %s, which this node doesn't support", block.CurrentProtocol)
+	}
+	if block.NextProtocol != releaseProtocol {
+		return false, fmt.Sprintf("the network isn't voting for protocol %s yet", releaseProtocol)
+	}
+
+	var elapsed uint64
+	if voteStart := block.NextProtocolVoteBefore - params.UpgradeVoteRounds; block.Round > voteStart {
+		elapsed = block.Round - voteStart
+	}
+	if elapsed == 0 {
+		return false, fmt.Sprintf("the vote for protocol %s just started", releaseProtocol)
+	}
+	share := float64(block.NextProtocolApprovals) / float64(elapsed)
+	if share > 1 {
+		share = 1
+	}
+	if share < approval {
+		return false, fmt.Sprintf("%.1f%% of the last %d blocks approved protocol %s", share*100, elapsed, releaseProtocol)
+	}
+	return true, fmt.Sprintf("%.1f%% of the last %d blocks approved protocol %s", share*100, elapsed, releaseProtocol)
+}
+
+// checkRollout checks the latest block known to the node against rolloutReady.
+func checkRollout(client nodeClient, releaseProtocol string, approval float64) (bool, string, error) {
+	status, err := client.Status()
+	if err != nil {
+		return false, "", err
+	}
+	block, err := client.Block(status.LastRound)
+	if err != nil {
+		return false, "", err
+	}
+	ready, reason := rolloutReady(block, releaseProtocol, approval)
+	return ready, reason, nil
+}
+
+// waitForHealthyNode waits until the node serves its REST API and its round advances. The client is recreated on
+// failures, as the node may still be starting.
+func waitForHealthyNode(connect func() (nodeClient, error), timeout time.Duration, interval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var client nodeClient
+	var firstRound uint64
+	started := false
+	lastErr := fmt.Errorf("node didn't respond")
+	for time.Now().Before(deadline) {
+		if client == nil {
+			client, lastErr = connect()
+		}
+		if client != nil {
+			var status models.NodeStatus
+			status, lastErr = client.Status()
+			switch {
+			case lastErr != nil:
+				client = nil
+			case !started:
+				started = true
+				firstRound = status.LastRound
+			case status.LastRound > firstRound:
+				return nil
+			default:
+				lastErr = fmt.Errorf("round %d didn't advance", firstRound)
+			}
+		}
+		time.Sleep(interval)
+	}
+	return fmt.Errorf("node isn't healthy after %v: %v", timeout, lastErr)
+}
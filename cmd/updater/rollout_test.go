@@ -0,0 +1,99 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestRolloutReady(t *testing.T) {
+	current := string(protocol.ConsensusCurrentVersion)
+	voteRounds := config.Consensus[protocol.ConsensusCurrentVersion].UpgradeVoteRounds
+	voting := models.Block{
+		Round:                  1000 + 100,
+		CurrentProtocol:        current,
+		NextProtocol:           "future",
+		NextProtocolVoteBefore: 1000 + voteRounds,
+	}
+
+	ready, _ := rolloutReady(models.Block{CurrentProtocol: current}, current, defaultRolloutApproval)
+	require.True(t, ready)
+
+	ready, _ = rolloutReady(models.Block{CurrentProtocol: "unknown"}, "future", defaultRolloutApproval)
+	require.True(t, ready)
+
+	ready, reason := rolloutReady(models.Block{CurrentProtocol: current}, "future", defaultRolloutApproval)
+	require.False(t, ready)
+	require.Contains(t, reason, "isn't voting")
+
+	voting.NextProtocolApprovals = 40
+	ready, reason = rolloutReady(voting, "future", defaultRolloutApproval)
+	require.False(t, ready)
+	require.Contains(t, reason, "40.0% of the last 100 blocks")
+
+	voting.NextProtocolApprovals = 60
+	ready, _ = rolloutReady(voting, "future", defaultRolloutApproval)
+	require.True(t, ready)
+
+	ready, _ = rolloutReady(voting, "", defaultRolloutApproval)
+	require.False(t, ready)
+}
+
+type testNodeClient struct {
+	rounds []uint64
+	errors int
+}
+
+func (c *testNodeClient) Status() (models.NodeStatus, error) {
+	if c.errors > 0 {
+		c.errors--
+		return models.NodeStatus{}, fmt.Errorf("connection refused")
+	}
+	status := models.NodeStatus{LastRound: c.rounds[0]}
+	if len(c.rounds) > 1 {
+		c.rounds = c.rounds[1:]
+	}
+	return status, nil
+}
+
+func (c *testNodeClient) Block(round uint64) (models.Block, error) {
+	return models.Block{Round: round}, nil
+}
+
+func TestWaitForHealthyNode(t *testing.T) {
+	client := &testNodeClient{rounds: []uint64{5, 5, 6}, errors: 2}
+	connections := 0
+	connect := func() (nodeClient, error) {
+		connections++
+		return client, nil
+	}
+	require.NoError(t, waitForHealthyNode(connect, 10*time.Second, time.Millisecond))
+	require.Equal(t, 3, connections)
+
+	stalled := &testNodeClient{rounds: []uint64{5}}
+	err := waitForHealthyNode(func() (nodeClient, error) { return stalled, nil }, 50*time.Millisecond, time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "round 5 didn't advance")
+}
@@ -26,6 +26,7 @@ import (
 	"regexp"
 	"runtime"
 	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -170,6 +171,10 @@ func (helper *s3Helper) getPackageVersion(pkg string, channel string, specificVe
 	for _, item := range result.Contents {
 		var version uint64
 		name := string(*item.Key)
+		if !strings.HasSuffix(name, ".tar.gz") {
+			// skip the signatures published along with the bundles
+			continue
+		}
 		version, err = getVersionFromName(name)
 		if err != nil {
 			return
@@ -205,6 +210,15 @@ func (helper *s3Helper) downloadFile(name string, writer io.WriterAt) error {
 	return nil
 }
 
+// downloadBytes downloads a small object, such as a bundle signature, into memory.
+func (helper *s3Helper) downloadBytes(name string) ([]byte, error) {
+	buf := aws.NewWriteAtBuffer(nil)
+	if err := helper.downloadFile(name, buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func (helper *s3Helper) uploadFiles(files []string) error {
 	for _, f := range files {
 		fmt.Printf("Uploading file: %s\n", f)
@@ -34,7 +34,7 @@ func init() {
 var sendCmd = &cobra.Command{
 	Use:   "send",
 	Short: "Upload versions to S3",
-	Long:  "Uploads *.tar.gz files from specified path",
+	Long:  "Uploads *.tar.gz files from specified path, along with their signatures and deltas",
 	Run: func(cmd *cobra.Command, args []string) {
 		s3, err := makeS3SessionForUpload(sendBucket)
 		if err != nil {
@@ -42,7 +42,7 @@ var sendCmd = &cobra.Command{
 		}
 
 		var files []string
-		if files, err = getPackageFilesInPath(sourcePath); err == nil {
+		if files, err = getReleaseFilesInPath(sourcePath); err == nil {
 			err = s3.uploadFiles(files)
 		}
 		if err != nil {
@@ -56,3 +56,16 @@ func getPackageFilesInPath(sourcePath string) ([]string, error) {
 	paths, err := filepath.Glob(pattern)
 	return paths, err
 }
+
+// getReleaseFilesInPath returns the bundles of the given path, along with their signatures and deltas.
+func getReleaseFilesInPath(sourcePath string) ([]string, error) {
+	var files []string
+	for _, pattern := range []string{"*.tar.gz", "*.tar.gz" + signatureSuffix, "*" + deltaSuffix} {
+		paths, err := filepath.Glob(filepath.Join(sourcePath, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, paths...)
+	}
+	return files, nil
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var (
+	signKeyFile    string
+	signSourcePath string
+	signProtocol   string
+	deltaBaseFile  string
+	deltaNewFile   string
+	deltaOutputDir string
+)
+
+func init() {
+	signCmd.AddCommand(keygenCmd)
+	signCmd.AddCommand(signBundlesCmd)
+
+	signCmd.PersistentFlags().StringVarP(&signKeyFile, "key", "k", "", "Release signing key file (required)")
+	signCmd.MarkPersistentFlagRequired("key")
+	signBundlesCmd.Flags().StringVarP(&signSourcePath, "sourcePath", "s", "", "Path containing the bundles to sign (required)")
+	signBundlesCmd.Flags().StringVarP(&signProtocol, "protocol", "P", string(protocol.ConsensusCurrentVersion), "Latest consensus protocol supported by the release, used by staged rollouts")
+	signBundlesCmd.MarkFlagRequired("sourcePath")
+
+	deltaCmd.Flags().StringVarP(&deltaBaseFile, "base", "f", "", "Bundle of the earlier release the delta applies to (required)")
+	deltaCmd.Flags().StringVarP(&deltaNewFile, "new", "n", "", "Bundle of the new release (required)")
+	deltaCmd.Flags().StringVarP(&deltaOutputDir, "outputDir", "o", "", "Directory to write the delta into; defaults to the directory of the new bundle")
+	deltaCmd.MarkFlagRequired("base")
+	deltaCmd.MarkFlagRequired("new")
+}
+
+var signCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "Manage release signing keys and sign release bundles",
+	Long:  `Manage release signing keys and sign release bundles`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Fall back
+		cmd.HelpFunc()(cmd, args)
+	},
+}
+
+var keygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate a new release signing key",
+	Long:  "Generate a new release signing key, and print the public key to pin in " + pinnedKeysFileName,
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := os.Stat(signKeyFile); err == nil {
+			exitErrorf("%s already exists", signKeyFile)
+		}
+		secrets, err := generateSigningKey(signKeyFile)
+		if err != nil {
+			exitErrorf("Error generating signing key: %v", err)
+		}
+		fmt.Println(base64.StdEncoding.EncodeToString(secrets.SignatureVerifier[:]))
+	},
+}
+
+var signBundlesCmd = &cobra.Command{
+	Use:   "bundles",
+	Short: "Sign release bundles",
+	Long:  "Writes a signature file next to each *.tar.gz file of the specified path",
+	Run: func(cmd *cobra.Command, args []string) {
+		secrets, err := loadSigningKey(signKeyFile)
+		if err != nil {
+			exitErrorf("Error loading signing key: %v", err)
+		}
+		files, err := getPackageFilesInPath(signSourcePath)
+		if err != nil {
+			exitErrorf("Error listing bundles: %v", err)
+		}
+		for _, file := range files {
+			if _, err := signBundle(file, secrets, signProtocol); err != nil {
+				exitErrorf("Error signing %s: %v", file, err)
+			}
+			fmt.Printf("Signed %s\n", file)
+		}
+	},
+}
+
+var deltaCmd = &cobra.Command{
+	Use:   "delta",
+	Short: "Generate the delta between two release bundles",
+	Long:  "Generate the binary delta which reconstructs the new release bundle from the bundle of an earlier release",
+	Run: func(cmd *cobra.Command, args []string) {
+		fromVersion, err := getVersionFromName(filepath.Base(deltaBaseFile))
+		if err != nil {
+			exitErrorf("Error parsing base bundle version: %v", err)
+		}
+		base, err := readBundleArchive(deltaBaseFile)
+		if err != nil {
+			exitErrorf("Error reading %s: %v", deltaBaseFile, err)
+		}
+		target, err := readBundleArchive(deltaNewFile)
+		if err != nil {
+			exitErrorf("Error reading %s: %v", deltaNewFile, err)
+		}
+		delta := makeDelta(base, target)
+
+		outputDir := deltaOutputDir
+		if outputDir == "" {
+			outputDir = filepath.Dir(deltaNewFile)
+		}
+		deltaFile := filepath.Join(outputDir, deltaName(filepath.Base(deltaNewFile), fromVersion))
+		file, err := os.Create(deltaFile)
+		if err != nil {
+			exitErrorf("Error creating %s: %v", deltaFile, err)
+		}
+		defer file.Close()
+		if err = writeDelta(file, delta); err != nil {
+			exitErrorf("Error writing %s: %v", deltaFile, err)
+		}
+		fmt.Printf("Wrote %s (%d of %d bytes are new)\n", deltaFile, delta.literalSize(), delta.TargetSize)
+	},
+}
+
+// pinnedKeysFile returns the pinned keys file next to the updater executable.
+func pinnedKeysFile() string {
+	ex, err := os.Executable()
+	if err != nil {
+		return pinnedKeysFileName
+	}
+	return filepath.Join(filepath.Dir(ex), pinnedKeysFileName)
+}
+
+// loadTrustedKeys loads the release keys bundles must be signed by: the given keys file, or else the keys built into the
+// updater, or else the pinned keys file next to it. Having no trusted keys at all is an error, so that bundles are never
+// installed unverified by accident.
+func loadTrustedKeys(keysFile string) ([]crypto.PublicKey, error) {
+	if keysFile != "" {
+		return loadPinnedKeys(keysFile)
+	}
+	if embeddedReleaseKeys != "" {
+		return decodeReleaseKeys(strings.Split(embeddedReleaseKeys, ","), "the updater build")
+	}
+	keysFile = pinnedKeysFile()
+	if _, err := os.Stat(keysFile); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no release keys are built into the updater, and %s doesn't exist", keysFile)
+	}
+	return loadPinnedKeys(keysFile)
+}
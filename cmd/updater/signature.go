@@ -0,0 +1,209 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// signatureSuffix is appended to the name of a release bundle to get the name of its signature.
+const signatureSuffix = ".sig"
+
+// pinnedKeysFileName is the file, next to the updater, listing the keys release bundles must be signed by.
+const pinnedKeysFileName = "releasekeys.json"
+
+// embeddedReleaseKeys is the comma separated list of the base64 encoded release keys built into the updater, set with
+// -ldflags "-X main.embeddedReleaseKeys=...". They're trusted unless a pinned keys file is given explicitly.
+var embeddedReleaseKeys string
+
+// releaseBundle describes the signed content of a release bundle. Both the digest of the compressed bundle and of the
+// tar archive within it are signed, so that a bundle reconstructed from a delta could be verified as well.
+type releaseBundle struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Name      string        `codec:"name"`
+	Digest    crypto.Digest `codec:"digest"`
+	TarDigest crypto.Digest `codec:"tar"`
+	// Protocol is the latest consensus protocol supported by the release, used for staged rollouts.
+	Protocol string `codec:"proto"`
+}
+
+// ToBeHashed implements the crypto.Hashable interface.
+func (b releaseBundle) ToBeHashed() (protocol.HashID, []byte) {
+	return protocol.ReleaseBundle, protocol.Encode(b)
+}
+
+// bundleSignature is the content of the signature file published along with each release bundle.
+type bundleSignature struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Bundle releaseBundle    `codec:"bundle"`
+	Key    crypto.PublicKey `codec:"key"`
+	Sig    crypto.Signature `codec:"sig"`
+}
+
+// pinnedKeys lists the public keys which are trusted to sign release bundles.
+type pinnedKeys struct {
+	Keys []string
+}
+
+// bundleDigests returns the digest of the given gzip compressed bundle, and of the tar archive within it.
+func bundleDigests(r io.Reader) (digest crypto.Digest, tarDigest crypto.Digest, err error) {
+	bundleHash := crypto.NewHash()
+	gz, err := gzip.NewReader(io.TeeReader(r, bundleHash))
+	if err != nil {
+		return
+	}
+	tarHash := crypto.NewHash()
+	if _, err = io.Copy(tarHash, gz); err != nil {
+		return
+	}
+	// consume any trailing data, so that it's covered by the bundle digest as well
+	if _, err = io.Copy(ioutil.Discard, r); err != nil {
+		return
+	}
+	copy(digest[:], bundleHash.Sum(nil))
+	copy(tarDigest[:], tarHash.Sum(nil))
+	return
+}
+
+func bundleFileDigests(bundleFile string) (digest crypto.Digest, tarDigest crypto.Digest, err error) {
+	f, err := os.Open(bundleFile)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	return bundleDigests(f)
+}
+
+// signBundle signs the given bundle file, and writes its signature next to it.
+func signBundle(bundleFile string, secrets *crypto.SignatureSecrets, proto string) (sig bundleSignature, err error) {
+	sig.Bundle.Name = filepath.Base(bundleFile)
+	sig.Bundle.Protocol = proto
+	sig.Bundle.Digest, sig.Bundle.TarDigest, err = bundleFileDigests(bundleFile)
+	if err != nil {
+		return
+	}
+	sig.Key = crypto.PublicKey(secrets.SignatureVerifier)
+	sig.Sig = secrets.Sign(sig.Bundle)
+	err = ioutil.WriteFile(bundleFile+signatureSuffix, protocol.EncodeJSON(sig), 0644)
+	return
+}
+
+// verify checks that the signature was made by one of the pinned keys.
+func (sig bundleSignature) verify(keys []crypto.PublicKey) error {
+	for _, key := range keys {
+		if key != sig.Key {
+			continue
+		}
+		if !crypto.SignatureVerifier(key).Verify(sig.Bundle, sig.Sig) {
+			return fmt.Errorf("invalid signature for bundle %s", sig.Bundle.Name)
+		}
+		return nil
+	}
+	return fmt.Errorf("bundle %s is signed by an unknown key %s", sig.Bundle.Name, base64.StdEncoding.EncodeToString(sig.Key[:]))
+}
+
+func parseBundleSignature(data []byte) (sig bundleSignature, err error) {
+	err = protocol.DecodeJSON(data, &sig)
+	return
+}
+
+// verifyBundleFile checks that the given bundle file matches its signature, and that the signature is trusted.
+func verifyBundleFile(bundleFile string, sig bundleSignature, keys []crypto.PublicKey) error {
+	if err := sig.verify(keys); err != nil {
+		return err
+	}
+	digest, tarDigest, err := bundleFileDigests(bundleFile)
+	if err != nil {
+		return err
+	}
+	// a bundle reconstructed from a delta is recompressed, so only its tar archive has to match.
+	if tarDigest != sig.Bundle.TarDigest {
+		return fmt.Errorf("%s doesn't match the signed digest of %s", bundleFile, sig.Bundle.Name)
+	}
+	if digest != sig.Bundle.Digest {
+		log.Infof("%s was recompressed; its content matches the signed digest of %s", bundleFile, sig.Bundle.Name)
+	}
+	return nil
+}
+
+// loadPinnedKeys loads the trusted release keys from the given file.
+func loadPinnedKeys(keysFile string) (keys []crypto.PublicKey, err error) {
+	data, err := ioutil.ReadFile(keysFile)
+	if err != nil {
+		return
+	}
+	var pinned pinnedKeys
+	if err = json.Unmarshal(data, &pinned); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", keysFile, err)
+	}
+	return decodeReleaseKeys(pinned.Keys, keysFile)
+}
+
+// decodeReleaseKeys decodes the given base64 encoded release keys, listed in source.
+func decodeReleaseKeys(encodedKeys []string, source string) (keys []crypto.PublicKey, err error) {
+	for _, encoded := range encodedKeys {
+		var key crypto.PublicKey
+		var decoded []byte
+		decoded, err = base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(decoded) != len(key) {
+			return nil, fmt.Errorf("invalid release key '%s' in %s", encoded, source)
+		}
+		copy(key[:], decoded)
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no release keys are listed in %s", source)
+	}
+	return keys, nil
+}
+
+// generateSigningKey creates a new release signing key, writing its seed to the given file.
+func generateSigningKey(keyFile string) (*crypto.SignatureSecrets, error) {
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	if err := ioutil.WriteFile(keyFile, seed[:], 0600); err != nil {
+		return nil, err
+	}
+	return crypto.GenerateSignatureSecrets(seed), nil
+}
+
+// loadSigningKey loads a release signing key created by generateSigningKey.
+func loadSigningKey(keyFile string) (*crypto.SignatureSecrets, error) {
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	var seed crypto.Seed
+	if len(data) != len(seed) {
+		return nil, fmt.Errorf("%s is not a release signing key", keyFile)
+	}
+	copy(seed[:], data)
+	return crypto.GenerateSignatureSecrets(seed), nil
+}
@@ -0,0 +1,167 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+)
+
+// makeTestBundle returns a gzip compressed tar archive holding the given files.
+func makeTestBundle(t *testing.T, files map[string][]byte) []byte {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	for _, name := range []string{"bin/algod", "bin/goal", "bin/update.sh"} {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content))}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	var bundle bytes.Buffer
+	gz := gzip.NewWriter(&bundle)
+	_, err := gz.Write(archive.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return bundle.Bytes()
+}
+
+func writePinnedKeys(t *testing.T, dir string, keys ...crypto.SignatureVerifier) string {
+	var pinned pinnedKeys
+	for _, key := range keys {
+		pinned.Keys = append(pinned.Keys, base64.StdEncoding.EncodeToString(key[:]))
+	}
+	data, err := json.Marshal(pinned)
+	require.NoError(t, err)
+	keysFile := filepath.Join(dir, pinnedKeysFileName)
+	require.NoError(t, ioutil.WriteFile(keysFile, data, 0644))
+	return keysFile
+}
+
+func TestBundleSignature(t *testing.T) {
+	dir, err := ioutil.TempDir("", "updater")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	secrets, err := generateSigningKey(filepath.Join(dir, "release.key"))
+	require.NoError(t, err)
+	loaded, err := loadSigningKey(filepath.Join(dir, "release.key"))
+	require.NoError(t, err)
+	require.Equal(t, secrets.SignatureVerifier, loaded.SignatureVerifier)
+
+	bundleFile := filepath.Join(dir, "node_stable_linux-amd64_1.0.5.tar.gz")
+	require.NoError(t, ioutil.WriteFile(bundleFile, makeTestBundle(t, map[string][]byte{"bin/algod": []byte("algod 1.0.5")}), 0644))
+	_, err = signBundle(bundleFile, secrets, "test-protocol")
+	require.NoError(t, err)
+
+	keys, err := loadPinnedKeys(writePinnedKeys(t, dir, secrets.SignatureVerifier))
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(bundleFile + signatureSuffix)
+	require.NoError(t, err)
+	sig, err := parseBundleSignature(data)
+	require.NoError(t, err)
+	require.Equal(t, "node_stable_linux-amd64_1.0.5.tar.gz", sig.Bundle.Name)
+	require.Equal(t, "test-protocol", sig.Bundle.Protocol)
+	require.NoError(t, verifyBundleFile(bundleFile, sig, keys))
+
+	// a recompressed bundle with the same content verifies as well
+	archive, err := readBundleArchive(bundleFile)
+	require.NoError(t, err)
+	recompressed := filepath.Join(dir, "recompressed.tar.gz")
+	require.NoError(t, writeBundleArchive(recompressed, archive))
+	require.NoError(t, verifyBundleFile(recompressed, sig, keys))
+
+	// a modified bundle doesn't
+	tampered := filepath.Join(dir, "tampered.tar.gz")
+	require.NoError(t, ioutil.WriteFile(tampered, makeTestBundle(t, map[string][]byte{"bin/algod": []byte("algod 6.6.6")}), 0644))
+	require.Error(t, verifyBundleFile(tampered, sig, keys))
+
+	// and neither do modified signatures
+	forged := sig
+	forged.Bundle.Protocol = "other-protocol"
+	require.Error(t, forged.verify(keys))
+
+	other := crypto.GenerateSignatureSecrets(crypto.Seed{1})
+	require.Contains(t, sig.verify([]crypto.PublicKey{crypto.PublicKey(other.SignatureVerifier)}).Error(), "unknown key")
+}
+
+func TestLoadPinnedKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "updater")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	keysFile := filepath.Join(dir, pinnedKeysFileName)
+	require.NoError(t, ioutil.WriteFile(keysFile, []byte(`{"Keys": []}`), 0644))
+	_, err = loadPinnedKeys(keysFile)
+	require.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(keysFile, []byte(`{"Keys": ["bm90IGEga2V5"]}`), 0644))
+	_, err = loadPinnedKeys(keysFile)
+	require.Error(t, err)
+
+	first := crypto.GenerateSignatureSecrets(crypto.Seed{1})
+	second := crypto.GenerateSignatureSecrets(crypto.Seed{2})
+	keys, err := loadPinnedKeys(writePinnedKeys(t, dir, first.SignatureVerifier, second.SignatureVerifier))
+	require.NoError(t, err)
+	require.Equal(t, []crypto.PublicKey{crypto.PublicKey(first.SignatureVerifier), crypto.PublicKey(second.SignatureVerifier)}, keys)
+}
+
+func TestLoadTrustedKeys(t *testing.T) {
+	defer func(keys string) { embeddedReleaseKeys = keys }(embeddedReleaseKeys)
+
+	dir, err := ioutil.TempDir("", "updater")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	first := crypto.GenerateSignatureSecrets(crypto.Seed{1})
+	second := crypto.GenerateSignatureSecrets(crypto.Seed{2})
+
+	// without any keys, bundles can't be verified, which is an error rather than a reason to skip verification
+	embeddedReleaseKeys = ""
+	_, err = loadTrustedKeys("")
+	require.Error(t, err)
+
+	embeddedReleaseKeys = base64.StdEncoding.EncodeToString(first.SignatureVerifier[:]) + "," + base64.StdEncoding.EncodeToString(second.SignatureVerifier[:])
+	keys, err := loadTrustedKeys("")
+	require.NoError(t, err)
+	require.Equal(t, []crypto.PublicKey{crypto.PublicKey(first.SignatureVerifier), crypto.PublicKey(second.SignatureVerifier)}, keys)
+
+	// an explicit keys file takes precedence over the built in keys
+	keys, err = loadTrustedKeys(writePinnedKeys(t, dir, second.SignatureVerifier))
+	require.NoError(t, err)
+	require.Equal(t, []crypto.PublicKey{crypto.PublicKey(second.SignatureVerifier)}, keys)
+
+	embeddedReleaseKeys = "bm90IGEga2V5"
+	_, err = loadTrustedKeys("")
+	require.Error(t, err)
+}
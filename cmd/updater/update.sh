@@ -18,6 +18,9 @@ HOSTEDSPEC=""
 BUCKET="" # updater defaults to using 'updatekey.json' if bucket is not set.
 GENESIS_NETWORK_DIR=""
 GENESIS_NETWORK_DIR_SPEC=""
+STAGED=0
+HEALTH_TIMEOUT=300
+HEALTHSPEC=""
 
 # If someone set the environment variable asking us to cleanup
 # when we're done, install a trap to do so
@@ -82,6 +85,14 @@ while [ "$1" != "" ]; do
             shift
             BUCKET="-b $1"
             ;;
+        -staged)
+            STAGED=1
+            ;;
+        -healthtimeout)
+            shift
+            HEALTH_TIMEOUT=$1
+            HEALTHSPEC="-healthtimeout $1"
+            ;;
         *)
             echo "Unknown option" "$1"
             UNKNOWNARGS+=("$1")
@@ -111,6 +122,10 @@ ROLLBACKBIN=0
 ROLLBACKDATA=()
 NEW_LEDGER=0
 RESTART_NODE=0
+NODES_STARTED=0
+
+# The bundle of the installed version is kept, so that the next update could download only the delta from it.
+RELEASE_BUNDLE="${BINDIR}/backup/release.tar.gz"
 
 function check_install_valid() {
     # Check for key files that indicate a valid install that can be updated
@@ -138,7 +153,12 @@ function determine_current_version() {
 
 function check_for_update() {
     determine_current_version
-    LATEST="$(${SCRIPTPATH}/updater ver check -c ${CHANNEL} ${BUCKET})"
+    STAGEDSPEC=""
+    if [ ${STAGED} -ne 0 ]; then
+        # Only update once the network majority runs the new version
+        STAGEDSPEC="--staged -d ${DATADIRS[0]} -p ${BINDIR}"
+    fi
+    LATEST="$(${SCRIPTPATH}/updater ver check -c ${CHANNEL} ${BUCKET} ${STAGEDSPEC})"
     if [ $? -ne 0 ]; then
         echo No remote updates found
         return 1
@@ -173,7 +193,12 @@ function download_update() {
     UPDATESRCDIR=${TEMPDIR}/a
     mkdir ${UPDATESRCDIR}
 
-    ${SCRIPTPATH}/updater ver get -c ${CHANNEL} -o ${TARFILE} ${BUCKET} ${SPECIFIC_VERSION}
+    DELTASPEC=""
+    if [ "${SPECIFIC_VERSION}" = "" ] && [ -f "${RELEASE_BUNDLE}" ]; then
+        DELTASPEC="--delta-base ${RELEASE_BUNDLE} --delta-from ${CURRENTVER}"
+    fi
+
+    ${SCRIPTPATH}/updater ver get -c ${CHANNEL} -o ${TARFILE} ${BUCKET} ${SPECIFIC_VERSION} ${DELTASPEC}
 
     if [ $? -ne 0 ]; then
         echo Error downloading update file
@@ -241,6 +266,7 @@ function backup_binaries() {
     BACKUPFILES="algod kmd carpenter doberman goal update.sh updater updatekey.json diagcfg"
     # add node_exporter to the files list we're going to backup, but only we if had it previously deployed.
     [ -f ${BINDIR}/node_exporter ] && BACKUPFILES="${BACKUPFILES} node_exporter"
+    [ -f ${BINDIR}/releasekeys.json ] && BACKUPFILES="${BACKUPFILES} releasekeys.json"
     tar -zcf ${BINDIR}/backup/bin-v${CURRENTVER}.tar.gz -C ${BINDIR} ${BACKUPFILES} >/dev/null 2>&1
 }
 
@@ -403,6 +429,30 @@ function startup_nodes() {
     for DD in ${DATADIRS[@]}; do
         startup_node ${DD}
     done
+    NODES_STARTED=1
+}
+
+function check_nodes_health() {
+    if [ "${NOSTART}" != "" ] || [ "${HEALTH_TIMEOUT}" = "0" ]; then
+        return 0
+    fi
+
+    for DD in ${DATADIRS[@]}; do
+        echo "Checking node health in ${DD}..."
+        ${BINDIR}/updater health -c ${CHANNEL} -d ${DD} -p ${BINDIR} -t ${HEALTH_TIMEOUT}s
+        if [ $? -ne 0 ]; then
+            return 1
+        fi
+    done
+    return 0
+}
+
+function save_release_bundle() {
+    BUNDLE=$(ls ${TEMPDIR}/*.tar.gz 2>/dev/null | head -n 1)
+    if [ "${BUNDLE}" != "" ]; then
+        mkdir -p ${BINDIR}/backup
+        cp ${BUNDLE} ${RELEASE_BUNDLE}
+    fi
 }
 
 function rollback() {
@@ -419,6 +469,10 @@ function fail_and_exit() {
     echo "*** UPDATE FAILED: $1 ***"
     if [ ${ROLLBACK} -ne 0 ]; then
         ROLLBACK=0
+        if [ ${NODES_STARTED} -ne 0 ]; then
+            # The nodes already run the new version; stop them before restoring the previous one.
+            shutdown_node
+        fi
         rollback
         check_install_valid
         if [ ${RESTART_NODE} -ne 0 ]; then
@@ -490,7 +544,7 @@ if [ ${RESUME_INSTALL} -eq 0 ]; then
     # Note that the SCRIPTPATH we're passing in should be our binaries directory, which is what we expect to be
     # passed as the last argument (if any)
     echo "Starting the new update script to complete the installation..."
-    exec "${UPDATESRCDIR}/bin/${FILENAME}" ${INSTALLOPT} -r -c ${CHANNEL} ${DATADIRSPEC} ${NOSTART} ${BINDIRSPEC} ${HOSTEDSPEC} ${GENESIS_NETWORK_DIR_SPEC} "${UNKNOWNARGS[@]}"
+    exec "${UPDATESRCDIR}/bin/${FILENAME}" ${INSTALLOPT} -r -c ${CHANNEL} ${DATADIRSPEC} ${NOSTART} ${BINDIRSPEC} ${HOSTEDSPEC} ${GENESIS_NETWORK_DIR_SPEC} ${HEALTHSPEC} "${UNKNOWNARGS[@]}"
 
     # If we're still here, exec failed.
     fail_and_exit "Error executing the new update script - unable to continue"
@@ -545,6 +599,12 @@ if [ "${NOSTART}" != "" ]; then
     echo "Install complete - restart node manually"
 else
     startup_nodes
+    check_nodes_health
+    if [ $? -ne 0 ]; then
+        fail_and_exit "Node failed the health check after the update"
+    fi
 fi
 
+save_release_bundle
+
 exit 0
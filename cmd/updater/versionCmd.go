@@ -17,16 +17,28 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/crypto"
 )
 
 var (
 	destFile        string
 	versionBucket   string
 	specificVersion uint64
+	keysFile        string
+	skipSignature   bool
+	deltaBase       string
+	deltaFrom       uint64
+	stagedRollout   bool
+	rolloutApproval float64
+	nodeDataDir     string
+	nodeBinDir      string
 )
 
 func init() {
@@ -34,10 +46,20 @@ func init() {
 	versionCmd.AddCommand(getCmd)
 
 	checkCmd.Flags().StringVarP(&versionBucket, "bucket", "b", "", "S3 bucket to check for updates.")
+	checkCmd.Flags().BoolVar(&stagedRollout, "staged", false, "Report the latest version only once the network majority approves its protocol")
+	checkCmd.Flags().Float64Var(&rolloutApproval, "approval", defaultRolloutApproval, "Share of the recent blocks which must approve the release protocol for a staged rollout")
+	checkCmd.Flags().StringVarP(&nodeDataDir, "datadir", "d", "", "Data directory of the node to query for a staged rollout")
+	checkCmd.Flags().StringVarP(&nodeBinDir, "bindir", "p", "", "Binaries directory of the node to query for a staged rollout; defaults to the updater directory")
+	checkCmd.Flags().StringVarP(&keysFile, "keys", "k", "", "Pinned release keys file; defaults to the keys built into the updater, or "+pinnedKeysFileName+" next to it")
+	checkCmd.Flags().BoolVar(&skipSignature, "skip-signature", false, "Don't verify the signature of the release bundle (unsafe)")
 
 	getCmd.Flags().StringVarP(&destFile, "outputFile", "o", "", "Path for downloaded file (required)")
 	getCmd.Flags().StringVarP(&versionBucket, "bucket", "b", "", "S3 bucket to check for updates.")
 	getCmd.Flags().Uint64VarP(&specificVersion, "version", "v", 0, "Specific version to download")
+	getCmd.Flags().StringVarP(&keysFile, "keys", "k", "", "Pinned release keys file; defaults to the keys built into the updater, or "+pinnedKeysFileName+" next to it")
+	getCmd.Flags().BoolVar(&skipSignature, "skip-signature", false, "Install the release bundle without verifying its signature (unsafe)")
+	getCmd.Flags().StringVar(&deltaBase, "delta-base", "", "Bundle of the installed version, to download only the delta from")
+	getCmd.Flags().Uint64Var(&deltaFrom, "delta-from", 0, "Installed version, which the delta base bundle belongs to")
 	getCmd.MarkFlagRequired("outputFile")
 }
 
@@ -60,7 +82,7 @@ var checkCmd = &cobra.Command{
 		if err != nil {
 			exitErrorf("Error creating s3 session %s\n", err.Error())
 		} else {
-			version, _, err := s3.getLatestVersion(channel)
+			version, name, err := s3.getLatestVersion(channel)
 			if err != nil {
 				exitErrorf("Error getting latest version from s3 %s\n", err.Error())
 			}
@@ -70,6 +92,26 @@ var checkCmd = &cobra.Command{
 				os.Exit(1)
 			}
 
+			if stagedRollout {
+				sig, err := downloadSignature(&s3, name, trustedKeys())
+				if err != nil {
+					exitErrorf("Error getting signature of %s: %v", name, err)
+				}
+				client, err := makeNodeClient(binDirOrDefault(nodeBinDir), nodeDataDir)
+				if err != nil {
+					exitErrorf("Error connecting to node in %s: %v", nodeDataDir, err)
+				}
+				ready, reason, err := checkRollout(client, sig.Bundle.Protocol, rolloutApproval)
+				if err != nil {
+					exitErrorf("Error getting network upgrade state: %v", err)
+				}
+				if !ready {
+					fmt.Fprintf(os.Stderr, "version %s isn't rolled out yet: %s\n", versionString(version), reason)
+					os.Exit(1)
+				}
+				fmt.Fprintf(os.Stderr, "staged rollout of version %s: %s\n", versionString(version), reason)
+			}
+
 			fmt.Fprintf(os.Stdout, "%d\n", version)
 		}
 	},
@@ -92,17 +134,111 @@ var getCmd = &cobra.Command{
 				exitErrorf("No updates found\n")
 			}
 
-			file, err := os.Create(os.ExpandEnv(destFile))
-			defer file.Close()
-			if err != nil {
-				exitErrorf("Error creating output file: %s\n", err.Error())
+			keys := trustedKeys()
+			var sig bundleSignature
+			if keys != nil {
+				sig, err = downloadSignature(&s3, name, keys)
+				if err != nil {
+					exitErrorf("Error getting signature of %s: %v", name, err)
+				}
 			}
 
-			err = s3.downloadFile(name, file)
-			if err != nil {
-				exitErrorf("Error downloading file: %s\n", err.Error())
-				// script should delete the file.
+			outputFile := os.ExpandEnv(destFile)
+			downloaded := false
+			if deltaBase != "" && deltaFrom != 0 && deltaFrom != version {
+				err = downloadDelta(&s3, name, deltaBase, deltaFrom, outputFile)
+				if err == nil {
+					downloaded = true
+					fmt.Fprintf(os.Stderr, "Reconstructed %s from the delta to version %s\n", name, versionString(deltaFrom))
+				} else {
+					fmt.Fprintf(os.Stderr, "Unable to use a delta (%v), downloading the full bundle\n", err)
+				}
+			}
+			if !downloaded {
+				file, err := os.Create(outputFile)
+				if err != nil {
+					exitErrorf("Error creating output file: %s\n", err.Error())
+				}
+				err = s3.downloadFile(name, file)
+				file.Close()
+				if err != nil {
+					exitErrorf("Error downloading file: %s\n", err.Error())
+					// script should delete the file.
+				}
+			}
+
+			if keys == nil {
+				fmt.Fprintf(os.Stderr, "Warning: the signature of %s wasn't verified\n", name)
+				return
+			}
+			if err = verifyBundleFile(outputFile, sig, keys); err != nil {
+				os.Remove(outputFile)
+				exitErrorf("Error verifying %s: %v", name, err)
 			}
 		}
 	},
 }
+
+// trustedKeys returns the keys release bundles must be signed by, or nil when signatures are explicitly skipped.
+func trustedKeys() []crypto.PublicKey {
+	if skipSignature {
+		return nil
+	}
+	keys, err := loadTrustedKeys(keysFile)
+	if err != nil {
+		exitErrorf("Error loading release keys: %v", err)
+	}
+	return keys
+}
+
+// downloadSignature downloads the signature of the given bundle, and verifies it's trusted when keys are given.
+func downloadSignature(s3 *s3Helper, name string, keys []crypto.PublicKey) (sig bundleSignature, err error) {
+	data, err := s3.downloadBytes(name + signatureSuffix)
+	if err != nil {
+		return
+	}
+	sig, err = parseBundleSignature(data)
+	if err != nil {
+		return
+	}
+	if sig.Bundle.Name != name {
+		return sig, fmt.Errorf("the signature is of %s", sig.Bundle.Name)
+	}
+	if keys != nil {
+		err = sig.verify(keys)
+	}
+	return
+}
+
+// downloadDelta reconstructs the given bundle from the installed version bundle and the delta between them.
+func downloadDelta(s3 *s3Helper, name string, baseFile string, fromVersion uint64, outputFile string) error {
+	base, err := readBundleArchive(baseFile)
+	if err != nil {
+		return err
+	}
+	data, err := s3.downloadBytes(deltaName(name, fromVersion))
+	if err != nil {
+		return err
+	}
+	delta, err := readDelta(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	target, err := delta.apply(base)
+	if err != nil {
+		return err
+	}
+	return writeBundleArchive(outputFile, target)
+}
+
+// binDirOrDefault returns the given binaries directory, or the updater directory if none is given.
+func binDirOrDefault(binDir string) string {
+	if binDir != "" {
+		return binDir
+	}
+	ex, err := os.Executable()
+	if err != nil {
+		return "."
+	}
+	return filepath.Dir(ex)
+}
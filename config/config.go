@@ -201,6 +201,54 @@ type ConsensusParams struct {
 
 	// domain-separated credentials
 	CredentialDomainSeparationEnabled bool
+
+	// SupportRekeying indicates support for account rekeying (the RekeyTo and AuthAddr fields)
+	SupportRekeying bool
+
+	// SupportBecomeNonParticipatingTransactions indicates support for keyreg transactions that irreversibly mark
+	// their sender as NotParticipating (the Nonparticipation field)
+	SupportBecomeNonParticipatingTransactions bool
+
+	// SupportTransactionLeases indicates support for transaction leases (the
+	// Lease field): while a transaction holds a lease, no other transaction
+	// with the same sender and lease can be committed
+	SupportTransactionLeases bool
+
+	// MaxTxGroupSize is the maximum number of transactions in an atomic
+	// transaction group; 0 means transaction groups are not supported
+	MaxTxGroupSize int
+
+	// SupportTxnCounter indicates support for the TxnCounter field of block
+	// headers, which counts the transactions committed to the ledger
+	SupportTxnCounter bool
+
+	// MaxAssetsPerAccount is the maximum number of assets an account can
+	// create, and the maximum number of assets it can hold; 0 means assets
+	// are not supported
+	MaxAssetsPerAccount int
+
+	// MaxAssetUnitNameBytes, MaxAssetNameBytes and MaxAssetURLBytes are the
+	// maximum lengths of the unit name, name and URL of an asset
+	MaxAssetUnitNameBytes int
+	MaxAssetNameBytes     int
+	MaxAssetURLBytes      int
+
+	// MinBalancePerAsset is the amount by which every asset an account
+	// holds raises its MinBalance, so that the account pays for the space
+	// its holdings take in the ledger
+	MinBalancePerAsset uint64
+
+	// LogicSigVersion is the highest version of TEAL programs that logic
+	// signatures can run; 0 means logic signatures are not supported
+	LogicSigVersion uint64
+
+	// LogicSigMaxSize is the maximum total size of a logic signature
+	// program and its arguments, in bytes
+	LogicSigMaxSize uint64
+
+	// LogicSigMaxCost is the maximum total cost of the ops of a logic
+	// signature program
+	LogicSigMaxCost uint64
 }
 
 // Consensus tracks the protocol-level settings for different versions of the
@@ -440,6 +488,38 @@ func initConsensusProtocols() {
 
 	// v16 can be upgraded to v17.
 	v16.ApprovedUpgrades[protocol.ConsensusV17] = true
+
+	// ConsensusFuture is used to test features that are implemented
+	// but not yet released in a production protocol version.
+	vFuture := v17
+	vFuture.ApprovedUpgrades = map[protocol.ConsensusVersion]bool{}
+
+	// Enable rekeying
+	vFuture.SupportRekeying = true
+
+	// Enable atomic transaction groups
+	vFuture.MaxTxGroupSize = 16
+
+	// Enable assets, numbered after the transaction counter
+	vFuture.SupportTxnCounter = true
+	vFuture.MaxAssetsPerAccount = 1000
+	vFuture.MaxAssetUnitNameBytes = 8
+	vFuture.MaxAssetNameBytes = 32
+	vFuture.MaxAssetURLBytes = 32
+	vFuture.MinBalancePerAsset = 100000
+
+	// Enable logic signatures
+	vFuture.LogicSigVersion = 1
+	vFuture.LogicSigMaxSize = 1000
+	vFuture.LogicSigMaxCost = 20000
+
+	// Enable keyreg transactions marking accounts as non-participating
+	vFuture.SupportBecomeNonParticipatingTransactions = true
+
+	// Enable transaction leases
+	vFuture.SupportTransactionLeases = true
+
+	Consensus[protocol.ConsensusFuture] = vFuture
 }
 
 func initConsensusTestProtocols() {
@@ -583,18 +663,98 @@ type Local struct {
 	// API endpoint address
 	EndpointAddress string
 
+	// GRPCEndpointAddress is the address of the gRPC interface of the node, such as 127.0.0.1:8081. It takes the
+	// same API token as the REST API, in the x-algo-api-token metadata. Empty disables it.
+	GRPCEndpointAddress string
+
 	// SRV-based phonebook
 	DNSBootstrapID string
 
+	// DNSBootstrapMetadataSigner is the address of the key that signs the network metadata TXT record published
+	// alongside the SRV bootstrap records. When set, the SRV bootstrap records are only used if the network
+	// metadata record is signed by this key and matches our genesis.
+	DNSBootstrapMetadataSigner string
+
 	// Log file size limit in bytes
 	LogSizeLimit uint64
 
+	// TracingEndpoint is the base URL of an OpenTelemetry collector accepting OTLP/HTTP, such as http://localhost:4318
+	// When set, the node exports traces of the REST API calls, transaction pool admission, block validation and
+	// agreement steps to it.
+	TracingEndpoint string
+
+	// StructuredLogging makes the node log line-delimited JSON with stable field names (subsystem, round, peer, txid),
+	// so that log aggregation systems could index the node logs.
+	StructuredLogging bool
+
+	// DisableAuditLog stops the node from recording the mutating REST API calls and the shutdowns into node.audit.log
+	DisableAuditLog bool
+
+	// AuditLogSizeLimit is the size in bytes at which the audit log is rotated; 0 uses the default of 64MB
+	AuditLogSizeLimit uint64
+
+	// AuditLogArchiveCount is the number of rotated audit log files that are kept; 0 uses the default of 5
+	AuditLogArchiveCount int
+
+	// EnableCrashReports makes the node store a crash report (stack, log tail, version and config fingerprint)
+	// into the crashreports directory when it panics, and upload the reports on the next start if telemetry is enabled
+	EnableCrashReports bool
+
+	// AlertWebhookURLs is a semicolon separated list of URLs the node posts JSON operational alerts to, such as
+	// the node stalling or falling behind, participation keys expiring, the disk running low or losing all peers.
+	// Empty disables the alerts.
+	AlertWebhookURLs string
+
+	// AlertWebhookSecret is the key the alerts are signed with, using HMAC-SHA256 in the X-Algorand-Signature header
+	AlertWebhookSecret string
+
+	// AlertStallSeconds is the time without a new round after which the node is considered stalled; 0 uses 120 seconds
+	AlertStallSeconds int
+
+	// AlertBehindRounds is the estimated number of rounds the node may fall behind the network before alerting;
+	// 0 uses 100 rounds
+	AlertBehindRounds uint64
+
+	// AlertPartKeyExpiryRounds is the number of rounds before a participation key expires that the node alerts about
+	// it; 0 uses 200000 rounds, about a week and a half
+	AlertPartKeyExpiryRounds uint64
+
+	// AlertMinFreeDiskMB is the free space of the data directory disk below which the node alerts; 0 uses 1024MB
+	AlertMinFreeDiskMB uint64
+
+	// DiskSafeModeFreeMB is the free space of the data directory disk below which the node enters safe mode: it keeps
+	// following consensus and writing the ledger, but refuses new transactions from the REST API, so that a full disk
+	// doesn't corrupt the ledger databases. The node leaves safe mode once twice that space is free again.
+	// 0 uses 256MB, and a negative value disables safe mode
+	DiskSafeModeFreeMB int64
+
+	// ShutdownRoundTimeoutSeconds is how long a shutting down node that participates in consensus waits for the round
+	// in progress to complete before stopping; 0 uses the default of 10 seconds, and a negative value stops right away
+	ShutdownRoundTimeoutSeconds int
+
+	// DisableStartupChecks skips the checks the node runs before starting: the ledger matching the genesis, the
+	// integrity of the databases, the permissions of the API token files and the clock skew
+	DisableStartupChecks bool
+
+	// StartupClockCheckServer is the NTP server the startup checks compare the clock with; empty uses pool.ntp.org,
+	// and "none" skips the clock check
+	StartupClockCheckServer string
+
+	// WarningDeduplicationSeconds is the window within which repetitions of an identical warning, such as those of a
+	// flapping peer, are counted rather than logged, and summarized once the window ends; 0 uses 60 seconds, and a
+	// negative value logs every warning
+	WarningDeduplicationSeconds int
+
 	// number of consecutive attempts to catchup after which we replace the peers we're connected to
 	CatchupFailurePeerRefreshRate int
 
 	// where should the node exporter listen for metrics
 	NodeExporterListenAddress string
 
+	// MetricsListenAddress is the address of a dedicated listener serving the node metrics in the prometheus format,
+	// such as 127.0.0.1:9100. Unlike the REST API /metrics endpoint, it doesn't require the API token. Empty disables it.
+	MetricsListenAddress string
+
 	// enable metric reporting flag
 	EnableMetricReporting bool
 
@@ -621,6 +781,36 @@ type Local struct {
 	// TxPoolSize is the number of transactions that fit in the transaction pool
 	TxPoolSize int
 
+	// TxPoolMaxPendingPerSender is the number of transactions a single sender may have pending in the transaction pool;
+	// 0 uses the default of 1000, and a negative value removes the limit
+	TxPoolMaxPendingPerSender int
+
+	// TxRebroadcastIntervalSeconds is how often the transactions submitted through the REST API are rebroadcast while
+	// they remain unconfirmed; 0 uses the default of 30 seconds, and a negative value disables the rebroadcasting
+	TxRebroadcastIntervalSeconds int64
+
+	// TxRebroadcastMaxTracked is the number of submitted transactions tracked for rebroadcasting; 0 uses the default of 10000
+	TxRebroadcastMaxTracked int
+
+	// APIMinFeeMultiplier makes the transactions submitted through the REST and gRPC APIs pay at least this many
+	// times the minimum transaction fee; 0 and 1 only require the minimum fee. Gossiped transactions aren't affected.
+	APIMinFeeMultiplier uint64
+
+	// APIMaxNoteSize is the largest note, in bytes, of the transactions submitted through the APIs; 0 keeps the
+	// protocol limit
+	APIMaxNoteSize int
+
+	// APIBannedAddresses is a semicolon separated list of the addresses whose transactions the node refuses through
+	// the APIs, whether they send, receive, close to or rekey to them
+	APIBannedAddresses string
+
+	// APIMaxRoundsBehind is the number of rounds the node may lag the network by and still accept transactions through
+	// the APIs. Further behind, the APIs answer 503 with the node's sync status, rather than accepting transactions that
+	// might expire before the node catches up, so that the clients fail over to healthier nodes. The check only applies
+	// while the node is catching up, and the lag is estimated from the age of the node's last block then. 0 uses 10
+	// rounds, and a negative value disables the check
+	APIMaxRoundsBehind int
+
 	// number of seconds allowed for syncing transactions
 	TxSyncTimeoutSeconds int64
 
@@ -673,6 +863,11 @@ type Local struct {
 	// Note -- Indexer cannot operate on non Archival nodes
 	IsIndexerActive bool
 
+	// IndexerNotePrefixLength is the number of leading bytes of the transaction notes the indexer keeps, to let
+	// transactions be searched by note prefix. 0 disables note indexing. Only the transactions indexed while it
+	// is set can be found by note.
+	IndexerNotePrefixLength int
+
 	// UseXForwardedForAddress indicates whether or not the node should use the X-Forwarded-For HTTP Header when
 	// determining the source of a connection.  If used, it should be set to the string "X-Forwarded-For", unless the
 	// proxy vendor provides another header field.  In the case of CloudFlare proxy, the "CF-Connecting-IP" header
@@ -704,7 +899,12 @@ func LoadConfigFromDisk(custom string) (c Local, err error) {
 }
 
 func loadConfigFromFile(configFile string) (c Local, err error) {
-	c = defaultLocal
+	return loadConfigFromFileWithBase(configFile, defaultLocal)
+}
+
+// loadConfigFromFileWithBase is loadConfigFromFile with base in place of the defaults
+func loadConfigFromFileWithBase(configFile string, base Local) (c Local, err error) {
+	c = base
 	c.Version = 0 // Reset to 0 so we get the version from the loaded file.
 	c, err = mergeConfigFromFile(configFile, c)
 	if err != nil {
@@ -759,6 +959,22 @@ func (cfg Local) DNSBootstrap(network protocol.NetworkID) string {
 	return strings.Replace(cfg.DNSBootstrapID, "<network>", string(network), -1)
 }
 
+// defaultTxPoolMaxPendingPerSender is the per-sender pending transactions limit used when TxPoolMaxPendingPerSender is 0
+const defaultTxPoolMaxPendingPerSender = 1000
+
+// TxPoolSenderLimit returns the number of transactions a single sender may have pending in the transaction pool,
+// or 0 if the number isn't limited
+func (cfg Local) TxPoolSenderLimit() int {
+	switch {
+	case cfg.TxPoolMaxPendingPerSender < 0:
+		return 0
+	case cfg.TxPoolMaxPendingPerSender == 0:
+		return defaultTxPoolMaxPendingPerSender
+	default:
+		return cfg.TxPoolMaxPendingPerSender
+	}
+}
+
 // SaveToDisk writes the Local settings into a root/ConfigFilename file
 func (cfg Local) SaveToDisk(root string) error {
 	configpath := filepath.Join(root, ConfigFilename)
@@ -0,0 +1,142 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// EnvOverridePrefix prefixes the environment variables that override config settings: ALGOD_<KEY>=value
+// overrides the setting named KEY. Keys match setting names regardless of case and underscores, so
+// ALGOD_GOSSIPFANOUT and ALGOD_GOSSIP_FANOUT both override GossipFanout.
+//
+// Settings are resolved in this order, each source taking precedence over the previous ones:
+//  1. the defaults of this build
+//  2. the config.json file of the data directory
+//  3. ALGOD_<KEY> environment variables
+//  4. algod -set key=value command line flags, in the order they are given
+//  5. the dedicated algod command line flags, such as -l and -p
+const EnvOverridePrefix = "ALGOD_"
+
+// ConfigOverride replaces the value of a single setting
+type ConfigOverride struct {
+	// Key is the name of the setting, as in config.json
+	Key   string
+	Value string
+	// Source describes where the override comes from, for error messages
+	Source string
+}
+
+// EnvironmentOverrides returns the overrides set by the ALGOD_<KEY> variables of environ, which holds
+// key=value entries like os.Environ. Variables whose key doesn't name a setting are returned separately,
+// since the ALGOD_ prefix is also used by tools that talk to algod.
+func EnvironmentOverrides(environ []string) (overrides []ConfigOverride, unknown []string) {
+	for _, entry := range environ {
+		if !strings.HasPrefix(entry, EnvOverridePrefix) {
+			continue
+		}
+		variable := strings.SplitN(entry, "=", 2)
+		if len(variable) != 2 || variable[0] == ProfileEnvVariable {
+			continue
+		}
+		key, ok := settingName(strings.TrimPrefix(variable[0], EnvOverridePrefix))
+		if !ok {
+			unknown = append(unknown, variable[0])
+			continue
+		}
+		overrides = append(overrides, ConfigOverride{Key: key, Value: variable[1], Source: "environment variable " + variable[0]})
+	}
+	return
+}
+
+// ParseOverride parses a key=value override, as given to algod -set
+func ParseOverride(arg string) (ConfigOverride, error) {
+	parts := strings.SplitN(arg, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return ConfigOverride{}, fmt.Errorf("invalid override '%s', expected key=value", arg)
+	}
+	key, ok := settingName(parts[0])
+	if !ok {
+		return ConfigOverride{}, fmt.Errorf("invalid override '%s': unknown setting %s", arg, parts[0])
+	}
+	return ConfigOverride{Key: key, Value: parts[1], Source: "flag -set " + arg}, nil
+}
+
+// ApplyOverrides applies the overrides to cfg in order, so later overrides of a setting win over earlier ones.
+// Values are JSON, as they would be written in config.json, except that strings don't need quotes and
+// durations can also be written like 30s or 5m.
+func ApplyOverrides(cfg *Local, overrides []ConfigOverride) error {
+	cfgValue := reflect.ValueOf(cfg).Elem()
+	for _, override := range overrides {
+		field := cfgValue.FieldByName(override.Key)
+		if !field.IsValid() {
+			return fmt.Errorf("%s: unknown setting %s", override.Source, override.Key)
+		}
+		value, err := parseOverrideValue(field.Type(), override.Value)
+		if err != nil {
+			return fmt.Errorf("%s: expected a value of type %v: %v", override.Source, field.Type(), err)
+		}
+		if r, ok := configRanges[override.Key]; ok && !r.contains(value) {
+			return fmt.Errorf("%s: %s is out of the allowed range [%d, %d]", override.Source, override.Value, r.min, r.max)
+		}
+		field.Set(value)
+	}
+
+	// as for settings loaded from config.json, relays are also Archival
+	if cfg.NetAddress != "" {
+		cfg.Archival = true
+	}
+	return nil
+}
+
+func parseOverrideValue(t reflect.Type, raw string) (reflect.Value, error) {
+	value := reflect.New(t)
+	if t == reflect.TypeOf(time.Duration(0)) {
+		if d, err := time.ParseDuration(raw); err == nil {
+			value.Elem().SetInt(int64(d))
+			return value.Elem(), nil
+		}
+	}
+	if t.Kind() == reflect.String && !strings.HasPrefix(raw, `"`) {
+		value.Elem().SetString(raw)
+		return value.Elem(), nil
+	}
+	if err := json.Unmarshal([]byte(raw), value.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return value.Elem(), nil
+}
+
+// settingName returns the name of the setting key refers to, ignoring case and underscores.
+// The config Version can't be overridden, since it only tracks the file format.
+func settingName(key string) (string, bool) {
+	normalized := strings.Replace(key, "_", "", -1)
+	localType := reflect.TypeOf(Local{})
+	for i := 0; i < localType.NumField(); i++ {
+		if localType.Field(i).Name == "Version" {
+			continue
+		}
+		if strings.EqualFold(localType.Field(i).Name, normalized) {
+			return localType.Field(i).Name, true
+		}
+	}
+	return "", false
+}
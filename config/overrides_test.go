@@ -0,0 +1,82 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentOverrides(t *testing.T) {
+	a := require.New(t)
+
+	overrides, unknown := EnvironmentOverrides([]string{
+		"HOME=/root",
+		"ALGOD_GOSSIP_FANOUT=8",
+		"ALGOD_NOT_A_SETTING=1",
+		"ALGOD_TOKEN=abc",
+		"ALGOD_DNSBootstrapID=<network>.example.com",
+		"ALGOD_VERSION=1",
+	})
+	a.Equal([]string{"ALGOD_NOT_A_SETTING", "ALGOD_TOKEN", "ALGOD_VERSION"}, unknown)
+	a.Len(overrides, 2)
+	a.Equal("GossipFanout", overrides[0].Key)
+	a.Equal("8", overrides[0].Value)
+	a.Equal("DNSBootstrapID", overrides[1].Key)
+
+	cfg := GetDefaultLocal()
+	a.NoError(ApplyOverrides(&cfg, overrides))
+	a.Equal(8, cfg.GossipFanout)
+	a.Equal("<network>.example.com", cfg.DNSBootstrapID)
+}
+
+func TestApplyOverrides(t *testing.T) {
+	a := require.New(t)
+
+	parse := func(arg string) ConfigOverride {
+		override, err := ParseOverride(arg)
+		a.NoError(err)
+		return override
+	}
+
+	cfg := GetDefaultLocal()
+	err := ApplyOverrides(&cfg, []ConfigOverride{
+		parse("ReconnectTime=30s"),
+		parse("TxPoolSize=100"),
+		parse("tx_pool_size=200"),
+		parse("PriorityPeers={\"a:1\": true}"),
+		parse("NetAddress=:4160"),
+		parse(`PublicAddress="relay.example.com"`),
+	})
+	a.NoError(err)
+	a.Equal(30*time.Second, cfg.ReconnectTime)
+	a.Equal(200, cfg.TxPoolSize)
+	a.Equal(map[string]bool{"a:1": true}, cfg.PriorityPeers)
+	a.Equal(":4160", cfg.NetAddress)
+	a.Equal("relay.example.com", cfg.PublicAddress)
+	a.True(cfg.Archival)
+
+	_, err = ParseOverride("NoSuchSetting=1")
+	a.Error(err)
+	_, err = ParseOverride("GossipFanout")
+	a.Error(err)
+
+	a.Error(ApplyOverrides(&cfg, []ConfigOverride{parse("GossipFanout=many")}))
+	a.Error(ApplyOverrides(&cfg, []ConfigOverride{parse("BaseLoggerDebugLevel=9")}))
+}
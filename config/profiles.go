@@ -0,0 +1,153 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ProfileEnvVariable selects the profile of a node when algod isn't given the -profile flag
+const ProfileEnvVariable = EnvOverridePrefix + "PROFILE"
+
+// Profile is a named group of settings for a common kind of node. A profile replaces the defaults
+// of its settings, so config.json, environment and command line overrides still take precedence.
+type Profile struct {
+	Name        string
+	Description string
+	// Settings maps setting names to values, written like ApplyOverrides values
+	Settings map[string]string
+}
+
+var profiles = map[string]Profile{
+	"relay": {
+		Name:        "relay",
+		Description: "Relay node: accepts incoming connections on port 4160, keeps the whole ledger and filters duplicate messages",
+		Settings: map[string]string{
+			"NetAddress":                            ":4160",
+			"Archival":                              "true",
+			"IncomingConnectionsLimit":              "10000",
+			"BroadcastConnectionsLimit":             "-1",
+			"MaxConnectionsPerIP":                   "30",
+			"EnableIncomingMessageFilter":           "true",
+			"EnableOutgoingNetworkMessageFiltering": "true",
+			"IsIndexerActive":                       "false",
+			"TxPoolSize":                            "50000",
+			"EnableMetricReporting":                 "true",
+		},
+	},
+	"archival": {
+		Name:        "archival",
+		Description: "Archival node: keeps the whole ledger and indexes transactions for the REST API, without serving other nodes",
+		Settings: map[string]string{
+			"NetAddress":            "",
+			"Archival":              "true",
+			"IsIndexerActive":       "true",
+			"CatchupParallelBlocks": "50",
+		},
+	},
+	"participation": {
+		Name:        "participation",
+		Description: "Participation node: takes part in consensus with a small footprint, only connecting out to relays",
+		Settings: map[string]string{
+			"NetAddress":               "",
+			"Archival":                 "false",
+			"IsIndexerActive":          "false",
+			"AnnounceParticipationKey": "true",
+			"GossipFanout":             "4",
+			"EnableAssembleStats":      "true",
+			"EnableAgreementReporting": "true",
+		},
+	},
+	"dev": {
+		Name:        "dev",
+		Description: "Development node: verbose logging, deadlock detection and a fixed local REST endpoint",
+		Settings: map[string]string{
+			"EndpointAddress":         "127.0.0.1:8080",
+			"BaseLoggerDebugLevel":    "5",
+			"DeadlockDetection":       "1",
+			"Archival":                "true",
+			"IsIndexerActive":         "true",
+			"EnableProcessBlockStats": "true",
+			"EnableAssembleStats":     "true",
+		},
+	},
+}
+
+// GetProfile returns the named profile
+func GetProfile(name string) (Profile, error) {
+	profile, ok := profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown config profile '%s', expected one of %v", name, ProfileNames())
+	}
+	return profile, nil
+}
+
+// ProfileNames returns the names of the profiles, sorted
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Overrides returns the settings of the profile as overrides, sorted by setting
+func (p Profile) Overrides() []ConfigOverride {
+	overrides := make([]ConfigOverride, 0, len(p.Settings))
+	for key, value := range p.Settings {
+		overrides = append(overrides, ConfigOverride{Key: key, Value: value, Source: "profile " + p.Name})
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].Key < overrides[j].Key })
+	return overrides
+}
+
+// Apply sets the settings of the profile on cfg
+func (p Profile) Apply(cfg *Local) error {
+	return ApplyOverrides(cfg, p.Overrides())
+}
+
+// ProfileDeviation is a setting of a profile that a config sets to another value
+type ProfileDeviation struct {
+	Key        string
+	Profile    interface{}
+	Configured interface{}
+}
+
+// Deviations returns the settings of the profile that cfg sets to other values, sorted by setting
+func (p Profile) Deviations(cfg Local) ([]ProfileDeviation, error) {
+	profiled := cfg
+	if err := p.Apply(&profiled); err != nil {
+		return nil, err
+	}
+	var deviations []ProfileDeviation
+	for _, key := range CompareConfigs(cfg, profiled).all() {
+		deviations = append(deviations, ProfileDeviation{
+			Key:        key,
+			Profile:    settingValue(profiled, key),
+			Configured: settingValue(cfg, key),
+		})
+	}
+	sort.Slice(deviations, func(i, j int) bool { return deviations[i].Key < deviations[j].Key })
+	return deviations, nil
+}
+
+func settingValue(cfg Local, key string) interface{} {
+	return reflect.ValueOf(cfg).FieldByName(key).Interface()
+}
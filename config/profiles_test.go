@@ -0,0 +1,95 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilesApply(t *testing.T) {
+	a := require.New(t)
+
+	for _, name := range ProfileNames() {
+		profile, err := GetProfile(name)
+		a.NoError(err)
+		cfg := GetDefaultLocal()
+		a.NoError(profile.Apply(&cfg), name)
+
+		deviations, err := profile.Deviations(cfg)
+		a.NoError(err)
+		a.Empty(deviations, name)
+	}
+
+	_, err := GetProfile("nosuchprofile")
+	a.Error(err)
+}
+
+func TestProfileDeviations(t *testing.T) {
+	a := require.New(t)
+
+	relay, err := GetProfile("relay")
+	a.NoError(err)
+
+	cfg := GetDefaultLocal()
+	cfg.NetAddress = ":4160"
+	cfg.Archival = true
+	cfg.IncomingConnectionsLimit = 100
+	deviations, err := relay.Deviations(cfg)
+	a.NoError(err)
+
+	keys := make([]string, len(deviations))
+	for i, d := range deviations {
+		keys[i] = d.Key
+	}
+	a.Contains(keys, "IncomingConnectionsLimit")
+	a.NotContains(keys, "NetAddress")
+	a.NotContains(keys, "Archival")
+	for _, d := range deviations {
+		if d.Key == "IncomingConnectionsLimit" {
+			a.Equal(10000, d.Profile)
+			a.Equal(100, d.Configured)
+		}
+	}
+}
+
+func TestConfigFileTakesPrecedenceOverProfile(t *testing.T) {
+	a := require.New(t)
+
+	tempDir, err := ioutil.TempDir("", "config-profile")
+	a.NoError(err)
+	defer os.RemoveAll(tempDir)
+
+	relay, err := GetProfile("relay")
+	a.NoError(err)
+
+	// without a config file, the node runs with the profile settings
+	c, _, err := LoadProfiledConfigFromDisk(tempDir, &relay)
+	a.True(os.IsNotExist(err))
+	a.Equal(":4160", c.NetAddress)
+
+	a.NoError(ioutil.WriteFile(filepath.Join(tempDir, ConfigFilename), []byte(`{"Version": 4, "NetAddress": ":4161"}`), 0600))
+	c, _, err = LoadProfiledConfigFromDisk(tempDir, &relay)
+	a.NoError(err)
+	a.Equal(":4161", c.NetAddress)
+	a.Equal(10000, c.IncomingConnectionsLimit)
+	a.True(c.EnableIncomingMessageFilter)
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"reflect"
+)
+
+// reloadableSettings are the settings a running node applies when it reloads its config file.
+// Changes to any other setting only take effect once the node restarts.
+var reloadableSettings = map[string]bool{
+	"BaseLoggerDebugLevel":      true,
+	"GossipFanout":              true,
+	"MaxConnectionsPerIP":       true,
+	"BroadcastConnectionsLimit": true,
+	"TxPoolMaxPendingPerSender": true,
+	"EnableAssembleStats":       true,
+	"APIMinFeeMultiplier":       true,
+	"APIMaxNoteSize":            true,
+	"APIBannedAddresses":        true,
+	"APIMaxRoundsBehind":        true,
+}
+
+// ConfigChanges lists the settings that differ between two configs
+type ConfigChanges struct {
+	// Reloaded are the changed settings a running node applies on reload
+	Reloaded []string
+	// RestartRequired are the changed settings that only take effect after a restart
+	RestartRequired []string
+}
+
+func (changes ConfigChanges) all() []string {
+	return append(append([]string{}, changes.Reloaded...), changes.RestartRequired...)
+}
+
+// IsReloadable returns true if a running node applies changes to the named setting when reloading its config
+func IsReloadable(setting string) bool {
+	return reloadableSettings[setting]
+}
+
+// CompareConfigs returns the settings whose values differ between current and updated,
+// split by whether a running node can apply them. Settings are listed in declaration order.
+func CompareConfigs(current, updated Local) (changes ConfigChanges) {
+	currentValue := reflect.ValueOf(current)
+	updatedValue := reflect.ValueOf(updated)
+	localType := currentValue.Type()
+	for i := 0; i < localType.NumField(); i++ {
+		if reflect.DeepEqual(currentValue.Field(i).Interface(), updatedValue.Field(i).Interface()) {
+			continue
+		}
+		name := localType.Field(i).Name
+		if IsReloadable(name) {
+			changes.Reloaded = append(changes.Reloaded, name)
+		} else {
+			changes.RestartRequired = append(changes.RestartRequired, name)
+		}
+	}
+	return
+}
+
+// CopySettings copies the named settings from src to dst, leaving the others untouched
+func CopySettings(dst *Local, src Local, settings []string) {
+	dstValue := reflect.ValueOf(dst).Elem()
+	srcValue := reflect.ValueOf(src)
+	for _, name := range settings {
+		dstValue.FieldByName(name).Set(srcValue.FieldByName(name))
+	}
+}
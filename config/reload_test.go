@@ -0,0 +1,45 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareConfigs(t *testing.T) {
+	a := require.New(t)
+
+	current := GetDefaultLocal()
+	updated := current
+	a.Equal(ConfigChanges{}, CompareConfigs(current, updated))
+
+	updated.BaseLoggerDebugLevel = 5
+	updated.GossipFanout = current.GossipFanout + 1
+	updated.TxPoolSize = current.TxPoolSize + 1
+	updated.PriorityPeers = map[string]bool{"a": true}
+	changes := CompareConfigs(current, updated)
+	a.Equal([]string{"GossipFanout", "BaseLoggerDebugLevel"}, changes.Reloaded)
+	a.Equal([]string{"PriorityPeers", "TxPoolSize"}, changes.RestartRequired)
+
+	CopySettings(&current, updated, changes.Reloaded)
+	a.Equal(uint32(5), current.BaseLoggerDebugLevel)
+	a.Equal(updated.GossipFanout, current.GossipFanout)
+	a.NotEqual(updated.TxPoolSize, current.TxPoolSize)
+	a.Equal(ConfigChanges{RestartRequired: changes.RestartRequired}, CompareConfigs(current, updated))
+}
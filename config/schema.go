@@ -0,0 +1,276 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConfigIssue is a single problem found while validating a config file
+type ConfigIssue struct {
+	Key     string
+	Message string
+}
+
+func (issue ConfigIssue) String() string {
+	return fmt.Sprintf("%s: %s", issue.Key, issue.Message)
+}
+
+// ConfigValidation is the outcome of validating a config file against the Local schema.
+// Warnings are about settings the node ignores (such as misspelled keys), while Errors
+// are about settings the node can't use.
+type ConfigValidation struct {
+	// Version is the config version recorded in the file, 0 if it has none
+	Version  uint32
+	Warnings []ConfigIssue
+	Errors   []ConfigIssue
+}
+
+// Valid returns true if the config file can be loaded
+func (v ConfigValidation) Valid() bool {
+	return len(v.Errors) == 0
+}
+
+// NeedsMigration returns true if the file was written for an older config version
+func (v ConfigValidation) NeedsMigration() bool {
+	return v.Version < configVersion
+}
+
+// configRange bounds the values of an integer setting
+type configRange struct {
+	min int64
+	max int64
+}
+
+// configRanges lists the integer settings whose values are restricted. Negative values are
+// often meaningful (e.g. -1 meaning unlimited), so only settings listed here are checked.
+var configRanges = map[string]configRange{
+	"Version":                          {0, int64(configVersion)},
+	"BaseLoggerDebugLevel":             {0, 5},
+	"BroadcastConnectionsLimit":        {-1, math.MaxInt64},
+	"IncomingConnectionsLimit":         {-1, math.MaxInt64},
+	"MaxConnectionsPerIP":              {0, math.MaxInt64},
+	"PeerPingPeriodSeconds":            {0, math.MaxInt64},
+	"ReconnectTime":                    {0, math.MaxInt64},
+	"DeadlockDetection":                {-1, 1},
+	"CatchupFailurePeerRefreshRate":    {0, math.MaxInt64},
+	"CatchupParallelBlocks":            {1, math.MaxInt64},
+	"SuggestedFeeBlockHistory":         {0, math.MaxInt64},
+	"TxPoolSize":                       {1, math.MaxInt64},
+	"TxPoolExponentialIncreaseFactor":  {1, math.MaxInt64},
+	"TxSyncTimeoutSeconds":             {0, math.MaxInt64},
+	"TxSyncIntervalSeconds":            {0, math.MaxInt64},
+	"TxSyncServeResponseSize":          {0, math.MaxInt64},
+	"IncomingMessageFilterBucketCount": {0, math.MaxInt64},
+	"IncomingMessageFilterBucketSize":  {0, math.MaxInt64},
+	"OutgoingMessageFilterBucketCount": {0, math.MaxInt64},
+	"OutgoingMessageFilterBucketSize":  {0, math.MaxInt64},
+	"AuditLogArchiveCount":             {0, math.MaxInt64},
+	"AlertStallSeconds":                {0, math.MaxInt64},
+	"WarningDeduplicationSeconds":      {0, math.MaxInt64},
+}
+
+// ValidateConfigFile validates the config file at the given path, see ValidateConfig
+func ValidateConfigFile(filename string) (ConfigValidation, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return ConfigValidation{}, err
+	}
+	defer f.Close()
+	return ValidateConfig(f)
+}
+
+// ValidateConfig checks a config file against the Local schema: every key must name a Local
+// setting, every value must decode into the type of its setting and fall within its allowed
+// range, and the version must be one this build can migrate from. An error is returned only
+// if the file isn't a JSON object at all.
+func ValidateConfig(reader io.Reader) (v ConfigValidation, err error) {
+	var raw map[string]json.RawMessage
+	if err = json.NewDecoder(reader).Decode(&raw); err != nil {
+		return
+	}
+
+	fields := make(map[string]reflect.StructField)
+	localType := reflect.TypeOf(Local{})
+	for i := 0; i < localType.NumField(); i++ {
+		fields[localType.Field(i).Name] = localType.Field(i)
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			v.Warnings = append(v.Warnings, ConfigIssue{Key: key, Message: unknownKeyMessage(key, fields)})
+			continue
+		}
+
+		value := reflect.New(field.Type)
+		dec := json.NewDecoder(bytes.NewReader(raw[key]))
+		if err := dec.Decode(value.Interface()); err != nil {
+			v.Errors = append(v.Errors, ConfigIssue{Key: key, Message: fmt.Sprintf("expected a value of type %v: %v", field.Type, err)})
+			continue
+		}
+		if key == "Version" {
+			v.Version = uint32(value.Elem().Uint())
+		}
+		if r, ok := configRanges[key]; ok && !r.contains(value.Elem()) {
+			v.Errors = append(v.Errors, ConfigIssue{Key: key, Message: fmt.Sprintf("%s is out of the allowed range [%d, %d]", raw[key], r.min, r.max)})
+		}
+	}
+	return
+}
+
+func (r configRange) contains(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() >= r.min && value.Int() <= r.max
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// unsigned values are never below a negative minimum
+		if r.min > 0 && value.Uint() < uint64(r.min) {
+			return false
+		}
+		return value.Uint() <= uint64(r.max)
+	}
+	return true
+}
+
+// unknownKeyMessage explains why key isn't a setting, suggesting the setting it was most likely meant to be
+func unknownKeyMessage(key string, fields map[string]reflect.StructField) string {
+	best := ""
+	bestDistance := len(key)/3 + 1
+	for name := range fields {
+		if strings.EqualFold(name, key) {
+			return fmt.Sprintf("unknown setting, settings are case sensitive: did you mean %s?", name)
+		}
+		d := editDistance(strings.ToLower(key), strings.ToLower(name))
+		if d < bestDistance || (d == bestDistance && best != "" && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	if best == "" {
+		return "unknown setting, it is ignored"
+	}
+	return fmt.Sprintf("unknown setting, it is ignored: did you mean %s?", best)
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// LoadValidatedConfigFromDisk loads the config file from the custom dir like LoadConfigFromDisk, but validates
+// it first. A file that fails validation is not loaded. A file written for an older config version is migrated
+// and rewritten in place, after copying the original to a backup file next to it. The returned validation lists
+// the warnings about the file, which the caller should surface; failing to rewrite the file is one of them.
+func LoadValidatedConfigFromDisk(custom string) (c Local, v ConfigValidation, err error) {
+	return LoadProfiledConfigFromDisk(custom, nil)
+}
+
+// LoadProfiledConfigFromDisk is LoadValidatedConfigFromDisk for a node running with the given profile, if not nil:
+// the settings of the profile replace the defaults, and config.json takes precedence over them.
+func LoadProfiledConfigFromDisk(custom string, profile *Profile) (c Local, v ConfigValidation, err error) {
+	base := defaultLocal
+	if profile != nil {
+		if err = profile.Apply(&base); err != nil {
+			return defaultLocal, v, err
+		}
+	}
+
+	filename := filepath.Join(custom, ConfigFilename)
+	v, err = ValidateConfigFile(filename)
+	if err != nil {
+		return base, v, err
+	}
+	if !v.Valid() {
+		issues := make([]string, len(v.Errors))
+		for i, issue := range v.Errors {
+			issues[i] = issue.String()
+		}
+		return base, v, fmt.Errorf("invalid config file %s: %s", filename, strings.Join(issues, "; "))
+	}
+
+	c, err = loadConfigFromFileWithBase(filename, base)
+	if err != nil {
+		return
+	}
+
+	if v.NeedsMigration() {
+		if migrateErr := migrateConfigFile(filename, v.Version); migrateErr != nil {
+			v.Warnings = append(v.Warnings, ConfigIssue{Key: "Version", Message: fmt.Sprintf("could not migrate the file from version %d to %d in place: %v", v.Version, configVersion, migrateErr)})
+		}
+	}
+	return
+}
+
+// ConfigBackupFilename returns the name of the backup kept when a config file of the given version is migrated
+func ConfigBackupFilename(filename string, version uint32) string {
+	return fmt.Sprintf("%s.v%d.bak", filename, version)
+}
+
+func migrateConfigFile(filename string, version uint32) error {
+	// the file is rewritten from the defaults rather than from a profile, so that its settings don't end up in it
+	migrated, err := loadConfigFromFile(filename)
+	if err != nil {
+		return err
+	}
+	original, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(ConfigBackupFilename(filename, version), original, 0600); err != nil {
+		return err
+	}
+	return migrated.SaveToFile(filename)
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	a := require.New(t)
+
+	v, err := ValidateConfig(strings.NewReader(`{
+		"Version": 4,
+		"GossipFanout": 8,
+		"gossipfanout": 2,
+		"TxPoolSzie": 100,
+		"Archival": "yes",
+		"BaseLoggerDebugLevel": 9,
+		"IncomingConnectionsLimit": -1
+	}`))
+	a.NoError(err)
+	a.Equal(uint32(4), v.Version)
+	a.False(v.NeedsMigration())
+	a.False(v.Valid())
+
+	a.Len(v.Warnings, 2)
+	a.Equal("TxPoolSzie", v.Warnings[0].Key)
+	a.Contains(v.Warnings[0].Message, "did you mean TxPoolSize?")
+	a.Equal("gossipfanout", v.Warnings[1].Key)
+	a.Contains(v.Warnings[1].Message, "case sensitive")
+
+	a.Len(v.Errors, 2)
+	a.Equal("Archival", v.Errors[0].Key)
+	a.Equal("BaseLoggerDebugLevel", v.Errors[1].Key)
+
+	_, err = ValidateConfig(strings.NewReader(`[1, 2]`))
+	a.Error(err)
+
+	v, err = ValidateConfig(strings.NewReader(`{"Version": 99}`))
+	a.NoError(err)
+	a.False(v.Valid())
+}
+
+func TestValidateShippedConfigs(t *testing.T) {
+	a := require.New(t)
+
+	configsPath := filepath.Join("..", "test", "testdata", "configs")
+	for _, name := range []string{"config-v0.json", "config-v1.json", "config-v2.json", "config-v3.json", "config-v4.json"} {
+		v, err := ValidateConfigFile(filepath.Join(configsPath, name))
+		a.NoError(err, name)
+		a.True(v.Valid(), "%s: %v", name, v.Errors)
+		a.Empty(v.Warnings, name)
+	}
+}
+
+func TestLoadValidatedConfigMigratesInPlace(t *testing.T) {
+	a := require.New(t)
+
+	tempDir, err := ioutil.TempDir("", "config-migrate")
+	a.NoError(err)
+	defer os.RemoveAll(tempDir)
+
+	original, err := ioutil.ReadFile(filepath.Join("..", "test", "testdata", "configs", "config-v2.json"))
+	a.NoError(err)
+	filename := filepath.Join(tempDir, ConfigFilename)
+	a.NoError(ioutil.WriteFile(filename, original, 0600))
+
+	c, v, err := LoadValidatedConfigFromDisk(tempDir)
+	a.NoError(err)
+	a.Equal(uint32(2), v.Version)
+	a.Empty(v.Warnings)
+	a.Equal(defaultLocal, c)
+
+	backup, err := ioutil.ReadFile(ConfigBackupFilename(filename, 2))
+	a.NoError(err)
+	a.Equal(original, backup)
+
+	v, err = ValidateConfigFile(filename)
+	a.NoError(err)
+	a.Equal(configVersion, v.Version)
+	a.False(v.NeedsMigration())
+
+	a.NoError(ioutil.WriteFile(filename, []byte(`{"Version": 4, "TxPoolSize": 0}`), 0600))
+	_, _, err = LoadValidatedConfigFromDisk(tempDir)
+	a.Error(err)
+}
@@ -27,6 +27,7 @@ import (
 	"fmt"
 
 	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/util/metrics"
 )
 
@@ -174,6 +175,13 @@ func (s *SignatureSecrets) Sign(message Hashable) Signature {
 	return s.signBytes(hashRep(message))
 }
 
+// SignBytes signs a message with no special meaning, prefixed with
+// protocol.Message so that the signature can never pass as one of a
+// transaction or any other object the protocol signs.
+func (s *SignatureSecrets) SignBytes(message []byte) Signature {
+	return s.signBytes(append([]byte(protocol.Message), message...))
+}
+
 // signBytes signs a message directly, without first hashing.
 // Caller is responsible for domain separation.
 func (s *SignatureSecrets) signBytes(message []byte) Signature {
@@ -191,6 +199,11 @@ func (v SignatureVerifier) Verify(message Hashable, sig Signature) bool {
 	return ed25519Verify(ed25519PublicKey(v), hashRep(message), ed25519Signature(sig))
 }
 
+// VerifyBytes verifies a signature made by SignBytes.
+func (v SignatureVerifier) VerifyBytes(message []byte, sig Signature) bool {
+	return v.verifyBytes(append([]byte(protocol.Message), message...), sig)
+}
+
 // verifyBytes verifies a signature, where the message is not hashed first.
 // Caller is responsible for domain separation.
 // If the message is a Hashable, Verify() can be used instead.
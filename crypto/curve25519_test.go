@@ -56,6 +56,21 @@ func TestCurve25519SignVerify(t *testing.T) {
 	signVerify(t, makeCurve25519Secret(), makeCurve25519Secret())
 }
 
+func TestSignBytes(t *testing.T) {
+	s := makeCurve25519Secret()
+	msg := []byte("reserves")
+	sig := s.SignBytes(msg)
+	if !s.SignatureVerifier.VerifyBytes(msg, sig) {
+		t.Errorf("SignBytes signature failed to verify")
+	}
+	if s.SignatureVerifier.verifyBytes(msg, sig) {
+		t.Errorf("SignBytes signature verified without its domain separation prefix")
+	}
+	if makeCurve25519Secret().SignatureVerifier.VerifyBytes(msg, sig) {
+		t.Errorf("SignBytes signature verified with the wrong key")
+	}
+}
+
 func TestVRFProveVerify(t *testing.T) {
 	proveVerifyVrf(t, GenerateVRFSecrets(), GenerateVRFSecrets())
 }
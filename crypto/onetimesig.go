@@ -19,6 +19,7 @@ package crypto
 import (
 	"encoding/binary"
 	"fmt"
+	"sync"
 
 	"github.com/algorand/go-deadlock"
 
@@ -183,24 +184,7 @@ func GenerateOneTimeSignatureSecretsRNG(startBatch uint64, numBatches uint64, rn
 
 	subkeys := make([]ephemeralSubkey, numBatches)
 	for i := uint64(0); i < numBatches; i++ {
-		pk, sk := ed25519GenerateKeyRNG(rng)
-		batchnum := startBatch + i
-
-		// Generate the old-style signature in case we need to sign a message
-		// compatible with the old-style protocol.  Can eventually go away,
-		// once we never need to sign messages in an old protocol.
-		oldid := OneTimeSignatureIdentifier{Batch: batchnum}
-		oldsig := ed25519Sign(ephemeralSec, append(pk[:], oldid.BatchBytes()...))
-
-		newid := OneTimeSignatureSubkeyBatchID{SubKeyPK: pk, Batch: batchnum}
-		newsig := ed25519Sign(ephemeralSec, hashRep(newid))
-
-		subkeys[i] = ephemeralSubkey{
-			PK:       pk,
-			SK:       sk,
-			PKSigOld: oldsig,
-			PKSigNew: newsig,
-		}
+		subkeys[i] = generateBatchSubkey(ephemeralSec, startBatch+i, rng)
 	}
 
 	s.OneTimeSignatureVerifier = OneTimeSignatureVerifier(master)
@@ -210,12 +194,93 @@ func GenerateOneTimeSignatureSecretsRNG(startBatch uint64, numBatches uint64, rn
 	return s
 }
 
+// generateBatchSubkey generates the ephemeral subkey of a batch, signed by
+// the master key.
+func generateBatchSubkey(ephemeralSec ed25519PrivateKey, batchnum uint64, rng RNG) ephemeralSubkey {
+	pk, sk := ed25519GenerateKeyRNG(rng)
+
+	// Generate the old-style signature in case we need to sign a message
+	// compatible with the old-style protocol.  Can eventually go away,
+	// once we never need to sign messages in an old protocol.
+	oldid := OneTimeSignatureIdentifier{Batch: batchnum}
+	oldsig := ed25519Sign(ephemeralSec, append(pk[:], oldid.BatchBytes()...))
+
+	newid := OneTimeSignatureSubkeyBatchID{SubKeyPK: pk, Batch: batchnum}
+	newsig := ed25519Sign(ephemeralSec, hashRep(newid))
+
+	return ephemeralSubkey{
+		PK:       pk,
+		SK:       sk,
+		PKSigOld: oldsig,
+		PKSigNew: newsig,
+	}
+}
+
 // GenerateOneTimeSignatureSecrets is a version of GenerateOneTimeSignatureSecretsRNG
 // that uses the system-wide randomness source.
 func GenerateOneTimeSignatureSecrets(startBatch uint64, numBatches uint64) *OneTimeSignatureSecrets {
 	return GenerateOneTimeSignatureSecretsRNG(startBatch, numBatches, SystemRNG)
 }
 
+// KeyGenProgress is told how many of the total keys have been generated so
+// far. It is called from one goroutine at a time.
+type KeyGenProgress func(done, total uint64)
+
+// oneTimeSignatureChunk is the number of batches a goroutine of
+// GenerateOneTimeSignatureSecretsParallel generates at a time, and so the
+// granularity of its progress reports.
+const oneTimeSignatureChunk = 256
+
+// GenerateOneTimeSignatureSecretsParallel is a version of
+// GenerateOneTimeSignatureSecrets that generates the batches on the given
+// number of goroutines, reporting its progress to progress if it isn't nil.
+func GenerateOneTimeSignatureSecretsParallel(startBatch uint64, numBatches uint64, workers int, progress KeyGenProgress) *OneTimeSignatureSecrets {
+	s := new(OneTimeSignatureSecrets)
+
+	master, ephemeralSec := ed25519GenerateKeyRNG(SystemRNG)
+
+	subkeys := make([]ephemeralSubkey, numBatches)
+	chunks := make(chan uint64)
+	var mu deadlock.Mutex
+	var done uint64
+	var wg sync.WaitGroup
+	if workers < 1 {
+		workers = 1
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for first := range chunks {
+				end := first + oneTimeSignatureChunk
+				if end > numBatches {
+					end = numBatches
+				}
+				for i := first; i < end; i++ {
+					subkeys[i] = generateBatchSubkey(ephemeralSec, startBatch+i, SystemRNG)
+				}
+				if progress != nil {
+					mu.Lock()
+					done += end - first
+					progress(done, numBatches)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for first := uint64(0); first < numBatches; first += oneTimeSignatureChunk {
+		chunks <- first
+	}
+	close(chunks)
+	wg.Wait()
+
+	s.OneTimeSignatureVerifier = OneTimeSignatureVerifier(master)
+	s.FirstBatch = startBatch
+	s.Batches = subkeys
+	s.rng = SystemRNG
+	return s
+}
+
 // getRNG returns the RNG for OneTimeSignatureSecrets.
 // If we serialized and de-serialized the OneTimeSignatureSecrets,
 // the private rng field might be nil.  Since rng is used only
@@ -87,6 +87,23 @@ func TestOneTimeSignVerifyNewStyle(t *testing.T) {
 	testOneTimeSignVerifyNewStyle(t, c, c2)
 }
 
+func TestOneTimeSignVerifyParallel(t *testing.T) {
+	var reports, last uint64
+	progress := func(done, total uint64) {
+		if total != 1000 || done <= last || done > total {
+			t.Errorf("bad progress report: %d of %d after %d", done, total, last)
+		}
+		reports++
+		last = done
+	}
+	c := GenerateOneTimeSignatureSecretsParallel(0, 1000, 3, progress)
+	if last != 1000 || reports != (1000+oneTimeSignatureChunk-1)/oneTimeSignatureChunk {
+		t.Errorf("progress ended at %d after %d reports", last, reports)
+	}
+	c2 := GenerateOneTimeSignatureSecretsParallel(0, 1000, 1, nil)
+	testOneTimeSignVerifyNewStyle(t, c, c2)
+}
+
 func TestOneTimeSignVerifyMixedStyle(t *testing.T) {
 	c := GenerateOneTimeSignatureSecrets(0, 1000)
 	c2 := GenerateOneTimeSignatureSecrets(0, 1000)
@@ -65,6 +65,138 @@ type Account struct {
 	// NotParticipating - indicates that the associated account is neither a delegator nor a delegate.
 	// Required: true
 	Status string `json:"status"`
+
+	// Participation is the participation key registered for the account, if any
+	Participation *Participation `json:"participation,omitempty"`
+
+	// AuthAddr is the address whose key authorizes transactions from this
+	// account, if the account has been rekeyed
+	//
+	// required: false
+	AuthAddr string `json:"auth-addr,omitempty"`
+
+	// AssetParams specifies the parameters of assets created by this account, by asset index.
+	//
+	// required: false
+	AssetParams map[uint64]AssetParams `json:"thisassettotal,omitempty"`
+
+	// Assets specifies the holdings of assets by this account, by asset index.
+	//
+	// required: false
+	Assets map[uint64]AssetHolding `json:"assets,omitempty"`
+}
+
+// AssetParams specifies the parameters for an asset.
+// swagger:model AssetParams
+type AssetParams struct {
+	// Creator specifies the address that created this asset.
+	// This is the address where the parameters for this asset
+	// can be found, and also the address where unwanted asset
+	// units can be sent in the worst case.
+	//
+	// required: true
+	Creator string `json:"creator"`
+
+	// Total specifies the total number of units of this asset.
+	//
+	// required: true
+	Total uint64 `json:"total"`
+
+	// DefaultFrozen specifies whether holdings in this asset
+	// are frozen by default.
+	//
+	// required: false
+	DefaultFrozen bool `json:"defaultfrozen"`
+
+	// UnitName specifies the name of a unit of this asset,
+	// as supplied by the creator.
+	//
+	// required: false
+	UnitName string `json:"unitname,omitempty"`
+
+	// AssetName specifies the name of this asset,
+	// as supplied by the creator.
+	//
+	// required: false
+	AssetName string `json:"assetname,omitempty"`
+
+	// URL specifies a URL where more information about the asset can be
+	// retrieved
+	//
+	// required: false
+	URL string `json:"url,omitempty"`
+
+	// ManagerAddr specifies the address used to manage the keys of this
+	// asset and to destroy it.
+	//
+	// required: false
+	ManagerAddr string `json:"managerkey,omitempty"`
+
+	// ReserveAddr specifies the address holding reserve (non-minted)
+	// units of this asset.
+	//
+	// required: false
+	ReserveAddr string `json:"reserveaddr,omitempty"`
+
+	// FreezeAddr specifies the address used to freeze holdings of
+	// this asset.  If empty, freezing is not permitted.
+	//
+	// required: false
+	FreezeAddr string `json:"freezeaddr,omitempty"`
+
+	// ClawbackAddr specifies the address used to clawback holdings of
+	// this asset.  If empty, clawback is not permitted.
+	//
+	// required: false
+	ClawbackAddr string `json:"clawbackaddr,omitempty"`
+}
+
+// AssetHolding describes an asset held by an account.
+// swagger:model AssetHolding
+type AssetHolding struct {
+	// Creator specifies the address that created this asset.
+	//
+	// required: true
+	Creator string `json:"creator"`
+
+	// Amount specifies the number of units held.
+	//
+	// required: true
+	Amount uint64 `json:"amount"`
+
+	// Frozen specifies whether this holding is frozen.
+	//
+	// required: false
+	Frozen bool `json:"frozen"`
+}
+
+// Participation Description
+// swagger:model Participation
+type Participation struct {
+	// ParticipationPK is the root participation public key currently registered for the account
+	//
+	// required: true
+	ParticipationPK []byte `json:"partpkb64"`
+
+	// VRFPK is the selection public key currently registered for the account
+	//
+	// required: true
+	VRFPK []byte `json:"vrfpkb64"`
+
+	// VoteFirst is the first round for which the participation key is valid
+	//
+	// required: true
+	VoteFirst uint64 `json:"votefst"`
+
+	// VoteLast is the last round for which the participation key is valid
+	//
+	// required: true
+	VoteLast uint64 `json:"votelst"`
+
+	// VoteKeyDilution is the number of subkeys in each batch of the participation key
+	//
+	// required: true
+	VoteKeyDilution uint64 `json:"votekd"`
 }
 
 // Block contains a block information
@@ -154,10 +286,35 @@ type Block struct {
 	Txns TransactionList `json:"txns,omitempty"`
 }
 
+// CatchupSource is a peer serving blocks to the current catchup
+// swagger:model CatchupSource
+type CatchupSource struct {
+
+	// Address of the peer
+	// Required: true
+	Address string `json:"address"`
+
+	// Blocks is the number of blocks the peer served
+	// Required: true
+	Blocks uint64 `json:"blocks"`
+}
+
 // NodeStatus contains the information about a node status
 // swagger:model NodeStatus
 type NodeStatus struct {
 
+	// CatchupBlocks is the number of blocks the current catchup fetched, verified and wrote to the ledger
+	// Required: false
+	CatchupBlocks uint64 `json:"catchupBlocks,omitempty"`
+
+	// CatchupBlocksPerSecond is the rate at which the current catchup writes blocks
+	// Required: false
+	CatchupBlocksPerSecond float64 `json:"catchupBlocksPerSecond,omitempty"`
+
+	// CatchupSources are the peers serving blocks to the current catchup, the most productive first
+	// Required: false
+	CatchupSources []CatchupSource `json:"catchupSources,omitempty"`
+
 	// CatchupTime in nanoseconds
 	// Required: true
 	CatchupTime int64 `json:"catchupTime"`
@@ -232,6 +389,195 @@ type PendingTransactions struct {
 	TotalTxns uint64 `json:"totalTxns"`
 }
 
+// SenderPendingTransactions represents the transactions of a single sender currently in the node's transaction
+// pool, in the order they are considered for inclusion in a block.
+// swagger:model SenderPendingTransactions
+type SenderPendingTransactions struct {
+	// TruncatedTxns are the pending transactions of the sender
+	// required: true
+	TruncatedTxns TransactionList `json:"truncatedTxns"`
+	// Positions are the positions of the transactions in TruncatedTxns among all the pending transactions,
+	// starting at 0 for the transaction that is considered first
+	// required: true
+	Positions []uint64 `json:"positions"`
+	// SenderTxns is the number of transactions of the sender currently in the pool
+	// required: true
+	SenderTxns uint64 `json:"senderTxns"`
+	// TotalTxns is the number of transactions currently in the pool
+	// required: true
+	TotalTxns uint64 `json:"totalTxns"`
+	// SenderLimit is the number of transactions a sender may have in the pool, or 0 if it isn't limited
+	// required: true
+	SenderLimit uint64 `json:"senderLimit"`
+}
+
+// RebroadcastStatus reports the rebroadcasting of a transaction that was submitted through this node
+// swagger:model RebroadcastStatus
+type RebroadcastStatus struct {
+	// TxID is the transaction ID
+	//
+	// required: true
+	TxID string `json:"txid"`
+
+	// State is one of "pending", "confirmed", "expired" or "rejected"
+	//
+	// required: true
+	State string `json:"state"`
+
+	// Submitted is the time the transaction was submitted, in seconds since epoch
+	//
+	// required: true
+	Submitted int64 `json:"submitted"`
+
+	// LastBroadcast is the last time the transaction was sent to peers, in seconds since epoch
+	//
+	// required: true
+	LastBroadcast int64 `json:"lastBroadcast"`
+
+	// Broadcasts is the number of times the transaction was sent, including its original broadcast
+	//
+	// required: true
+	Broadcasts uint64 `json:"broadcasts"`
+
+	// Peers is the number of distinct peers the transaction was sent to
+	//
+	// required: true
+	Peers uint64 `json:"peers"`
+
+	// LastValid is the last round the transaction can be confirmed in
+	//
+	// required: true
+	LastValid uint64 `json:"lastValid"`
+
+	// ConfirmedRound is the round the transaction was included in, if it's confirmed
+	ConfirmedRound uint64 `json:"confirmedRound,omitempty"`
+
+	// Error is the reason the transaction was rejected, if it's rejected
+	Error string `json:"error,omitempty"`
+}
+
+// PoolTransaction is a transaction pending in the transaction pool, as the node operator sees it
+// swagger:model PoolTransaction
+type PoolTransaction struct {
+	// TxID is the transaction ID
+	//
+	// required: true
+	TxID string `json:"txid"`
+
+	// Position is the position of the transaction among all the pending transactions, starting at 0 for the
+	// transaction that is considered first for inclusion in a block
+	//
+	// required: true
+	Position uint64 `json:"position"`
+
+	// Sender is the address of the sender
+	//
+	// required: true
+	Sender string `json:"sender"`
+
+	// Type is the type of the transaction
+	//
+	// required: true
+	Type string `json:"type"`
+
+	// Fee is the fee of the transaction, in micro-Algos
+	//
+	// required: true
+	Fee uint64 `json:"fee"`
+
+	// Size is the length of the encoded signed transaction, in bytes
+	//
+	// required: true
+	Size uint64 `json:"size"`
+
+	// FeePerByte is the fee per byte the pool prioritizes the transaction by, rounded up
+	//
+	// required: true
+	FeePerByte uint64 `json:"feePerByte"`
+
+	// LastValid is the last round the transaction can be confirmed in
+	//
+	// required: true
+	LastValid uint64 `json:"lastValid"`
+
+	// Group is the base64 ID of the group of the transaction, if it's part of one
+	Group string `json:"group,omitempty"`
+
+	// Local is whether the transaction was submitted through this node, which keeps rebroadcasting it
+	//
+	// required: true
+	Local bool `json:"local"`
+}
+
+// PoolTransactions contains the transactions pending in the transaction pool, in the order they are considered
+// for inclusion in a block
+// swagger:model PoolTransactions
+type PoolTransactions struct {
+	// Transactions are the pending transactions, of the requested sender if any, truncated at the requested
+	// maximum
+	//
+	// required: true
+	Transactions []PoolTransaction `json:"transactions"`
+
+	// TotalTxns is the number of transactions currently in the pool
+	//
+	// required: true
+	TotalTxns uint64 `json:"totalTxns"`
+}
+
+// DroppedTransactions contains the IDs of the transactions evicted from the transaction pool
+// swagger:model DroppedTransactions
+type DroppedTransactions struct {
+	// TxIDs are the IDs of the evicted transaction and of the rest of its group
+	//
+	// required: true
+	TxIDs []string `json:"txids"`
+}
+
+// LogLevels contains the log level of each of the node subsystems
+// swagger:model LogLevels
+type LogLevels struct {
+
+	// Levels maps each subsystem to the level it logs at, such as "debug"
+	// Required: true
+	Levels map[string]string `json:"levels"`
+
+	// Overrides lists the subsystems whose level differs from the node log level
+	// Required: true
+	Overrides []string `json:"overrides"`
+}
+
+// Balances contains the balances of a set of accounts as of a round
+// swagger:model Balances
+type Balances struct {
+	// Round is the round the balances are as of
+	// Required: true
+	Round uint64 `json:"round"`
+
+	// Accounts holds the balance of every requested account, in the order
+	// they were requested
+	// Required: true
+	Accounts []Balance `json:"accounts"`
+}
+
+// Balance is the balance of an account as of a round
+// swagger:model Balance
+type Balance struct {
+	// Address is the address of the account
+	// Required: true
+	Address string `json:"address"`
+
+	// Amount is the number of MicroAlgos in the account, including its
+	// rewards up to the round
+	// Required: true
+	Amount uint64 `json:"amount"`
+
+	// AuthAddr is the address whose key authorizes transactions from the
+	// account as of the round, if the account has been rekeyed
+	// Required: false
+	AuthAddr string `json:"auth-addr,omitempty"`
+}
+
 // Supply represents the current supply of MicroAlgos in the system
 // swagger:model Supply
 type Supply struct {
@@ -287,7 +633,10 @@ type Transaction struct {
 	TxID string `json:"tx"`
 
 	// payment
-	Payment *PaymentTransactionType `json:"payment,omitempty"`
+	Payment       *PaymentTransactionType       `json:"payment,omitempty"`
+	AssetConfig   *AssetConfigTransactionType   `json:"curcfg,omitempty"`
+	AssetTransfer *AssetTransferTransactionType `json:"curxfer,omitempty"`
+	AssetFreeze   *AssetFreezeTransactionType   `json:"curfrz,omitempty"`
 
 	// FromRewards is the amount of pending rewards applied to the From
 	// account as part of this transaction.
@@ -307,6 +656,21 @@ type Transaction struct {
 	//
 	// required: true
 	GenesisHash []byte `json:"genesishashb64"`
+
+	// RekeyTo is the address this transaction rekeys the sender to, if any
+	//
+	// required: false
+	RekeyTo string `json:"rekey,omitempty"`
+
+	// Group is the ID of the atomic transaction group this transaction belongs to, if any
+	//
+	// required: false
+	Group []byte `json:"group,omitempty"`
+
+	// Lease is the lease this transaction holds through its last round once committed, if any
+	//
+	// required: false
+	Lease []byte `json:"lease,omitempty"`
 }
 
 // TransactionFee contains the suggested fee
@@ -318,6 +682,42 @@ type TransactionFee struct {
 	Fee uint64 `json:"fee"`
 }
 
+// TransactionFeeInfo contains the suggested fee along with how congested the transaction pool is, so that
+// transactions can be priced during congestion.
+// All the fees per byte are in units of micro-Algos per byte of the encoded signed transaction, and a transaction
+// must still have a fee of at least MinFee.
+// swagger:model TransactionFeeInfo
+type TransactionFeeInfo struct {
+	// Fee is the suggested fee per byte, as returned by /transactions/fee
+	// Required: true
+	Fee uint64 `json:"fee"`
+
+	// MinFee is the minimum fee of a transaction in the current network protocol
+	// Required: true
+	MinFee uint64 `json:"minFee"`
+
+	// PoolTxns is the number of transactions pending in the transaction pool
+	// Required: true
+	PoolTxns uint64 `json:"poolTxns"`
+
+	// PoolSize is the number of transactions that fit in the transaction pool
+	// Required: true
+	PoolSize uint64 `json:"poolSize"`
+
+	// PoolBytes is the total length of the transactions pending in the transaction pool
+	// Required: true
+	PoolBytes uint64 `json:"poolBytes"`
+
+	// PoolMinFee is the fee per byte the transaction pool requires from new transactions, or 0 while it isn't full
+	// Required: true
+	PoolMinFee uint64 `json:"poolMinFee"`
+
+	// NextBlockFee is the fee per byte a new transaction likely needs to be included in the next block, ahead
+	// of the pending transactions that don't fit in it, or 0 if they all fit
+	// Required: true
+	NextBlockFee uint64 `json:"nextBlockFee"`
+}
+
 // TransactionParams contains the parameters that help a client construct
 // a new transaction.
 // swagger:model TransactionParams
@@ -358,6 +758,131 @@ type TransactionID struct {
 	TxID string `json:"txId"`
 }
 
+// TransactionBatchResults contains the outcome of every transaction of a batch
+// swagger:model TransactionBatchResults
+type TransactionBatchResults struct {
+	// Accepted is the number of transactions of the batch the node accepted
+	// Required: true
+	Accepted uint64 `json:"accepted"`
+
+	// Results holds the outcome of every transaction, in the order of the batch
+	// Required: true
+	Results []TransactionBatchResult `json:"results"`
+}
+
+// TransactionBatchResult is the outcome of one transaction of a batch
+// swagger:model TransactionBatchResult
+type TransactionBatchResult struct {
+	// TxId is the string encoding of the transaction hash
+	// Required: true
+	TxID string `json:"txId"`
+
+	// Error is the reason the node rejected the transaction, empty if it accepted it
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
+
+// NodeBehind is the sync status of a node that refuses transactions because it lags the network
+// swagger:model NodeBehind
+type NodeBehind struct {
+	// Message explains why the node refused the transactions
+	// Required: true
+	Message string `json:"message"`
+
+	// LastRound is the last round of the node's ledger
+	// Required: true
+	LastRound uint64 `json:"lastRound"`
+
+	// RoundsBehind is the estimated number of rounds the network is ahead of the node
+	// Required: true
+	RoundsBehind uint64 `json:"roundsBehind"`
+
+	// TimeSinceLastRound in nanoseconds, since the timestamp of the block of the last round
+	// Required: true
+	TimeSinceLastRound int64 `json:"timeSinceLastRound"`
+
+	// CatchupTime in nanoseconds, 0 if the node isn't catching up
+	// Required: true
+	CatchupTime int64 `json:"catchupTime"`
+}
+
+// AssetConfigTransactionType contains the additional fields for an asset config transaction
+// swagger:model AssetConfigTransactionType
+type AssetConfigTransactionType struct {
+	// AssetID is the index of the asset being configured or destroyed.
+	// A zero value means allocation.
+	//
+	// required: false
+	AssetID uint64 `json:"id"`
+
+	// Params specifies the new asset parameters, or the parameters of
+	// the asset being created.  The creator of the asset is also in
+	// the parameters.  Empty parameters mean destroying the asset.
+	//
+	// required: false
+	Params AssetParams `json:"params"`
+}
+
+// AssetTransferTransactionType contains the additional fields for an asset transfer transaction
+// swagger:model AssetTransferTransactionType
+type AssetTransferTransactionType struct {
+	// AssetID is the index of the asset being transferred.
+	//
+	// required: true
+	AssetID uint64 `json:"id"`
+
+	// Creator is the address of the account that created the asset.
+	//
+	// required: true
+	Creator string `json:"creator"`
+
+	// Amount is the amount being transferred.
+	//
+	// required: true
+	Amount uint64 `json:"amt"`
+
+	// Sender is the source account, if this is a clawback of the
+	// asset by its clawback address.
+	//
+	// required: false
+	Sender string `json:"snd,omitempty"`
+
+	// Receiver is the recipient account.
+	//
+	// required: true
+	Receiver string `json:"rcv"`
+
+	// CloseTo is the destination for remaining asset holdings, if the
+	// sender closes out of the asset.
+	//
+	// required: false
+	CloseTo string `json:"closeto,omitempty"`
+}
+
+// AssetFreezeTransactionType contains the additional fields for an asset freeze transaction
+// swagger:model AssetFreezeTransactionType
+type AssetFreezeTransactionType struct {
+	// AssetID is the index of the asset being frozen or un-frozen.
+	//
+	// required: true
+	AssetID uint64 `json:"id"`
+
+	// Creator is the address of the account that created the asset.
+	//
+	// required: true
+	Creator string `json:"creator"`
+
+	// Account specifies the account where the asset is being frozen or thawed.
+	//
+	// required: true
+	Account string `json:"acct"`
+
+	// NewFreezeStatus specifies the new freeze status.
+	//
+	// required: true
+	NewFreezeStatus bool `json:"freeze"`
+}
+
 // TransactionList contains a list of transactions
 // swagger:model TransactionList
 type TransactionList struct {
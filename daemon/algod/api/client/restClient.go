@@ -19,6 +19,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -42,13 +43,17 @@ const (
 
 // unversionedPaths ais a set of paths that should not be prefixed by the API version
 var unversionedPaths = map[string]bool{
-	"/versions": true,
-	"/health":   true,
+	"/versions":              true,
+	"/health":                true,
+	"/v2/transactions":       true,
+	"/v2/balances":           true,
+	"/v2/transactions/batch": true,
 }
 
 // rawRequestPaths is a set of paths where the body should not be urlencoded
 var rawRequestPaths = map[string]bool{
-	"/transactions": true,
+	"/transactions":          true,
+	"/v2/transactions/batch": true,
 }
 
 // RestClient manages the REST interface for a calling user.
@@ -91,8 +96,8 @@ func (client RestClient) submitForm(response interface{}, path string, request i
 	queryURL := client.serverURL
 	queryURL.Path = path
 
-	// Handle version prefix
-	if !unversionedPaths[path] {
+	// Handle version prefix; v2 paths carry their own
+	if !unversionedPaths[path] && !strings.HasPrefix(path, "/v2/") {
 		queryURL.Path = strings.Join([]string{apiVersionPathPrefix, path}, "")
 	}
 
@@ -194,6 +199,39 @@ func (client RestClient) GetPendingTransactions(maxTxns uint64) (response models
 	return
 }
 
+// SenderPendingTransactions asks algod for a snapshot of the pending txns of the given sender, bounded by maxTxns.
+// If maxTxns = 0, fetches all the pending transactions of the sender.
+func (client RestClient) SenderPendingTransactions(address string, maxTxns uint64) (response models.SenderPendingTransactions, err error) {
+	err = client.get(&response, fmt.Sprintf("/account/%s/transactions/pending", address), pendingTransactionsParams{maxTxns})
+	return
+}
+
+type poolTransactionsParams struct {
+	Sender string `url:"sender,omitempty"`
+	Max    uint64 `url:"max"`
+}
+
+// PoolTransactions asks algod for the transactions pending in its pool along with their fees and order, only the
+// ones of sender if it isn't empty, bounded by maxTxns. If maxTxns = 0, fetches all the matching transactions.
+func (client RestClient) PoolTransactions(sender string, maxTxns uint64) (response models.PoolTransactions, err error) {
+	err = client.get(&response, "/transactions/pool", poolTransactionsParams{sender, maxTxns})
+	return
+}
+
+// DropPoolTransaction asks algod to evict a transaction, along with the rest of its group, from its pool
+func (client RestClient) DropPoolTransaction(transactionID string) (response models.DroppedTransactions, err error) {
+	transactionID = stripTransaction(transactionID)
+	err = client.submitForm(&response, fmt.Sprintf("/transactions/pool/%s", transactionID), nil, "DELETE", false /* encodeJSON */)
+	return
+}
+
+// RebroadcastStatus asks algod for the rebroadcast status of a transaction submitted through it
+func (client RestClient) RebroadcastStatus(transactionID string) (response models.RebroadcastStatus, err error) {
+	transactionID = stripTransaction(transactionID)
+	err = client.get(&response, fmt.Sprintf("/transactions/rebroadcast/%s", transactionID), nil)
+	return
+}
+
 // Versions retrieves the VersionResponse from the running node
 // the VersionResponse includes data like version number and genesis ID
 func (client RestClient) Versions() (response models.Version, err error) {
@@ -207,6 +245,27 @@ func (client RestClient) LedgerSupply() (response models.Supply, err error) {
 	return
 }
 
+// LogLevels gets the log level of each of the node subsystems
+func (client RestClient) LogLevels() (response models.LogLevels, err error) {
+	err = client.get(&response, "/logging/levels", nil)
+	return
+}
+
+type logLevelParams struct {
+	Level string `url:"level"`
+}
+
+// SetLogLevel sets the log level of the given node subsystem, until the node restarts
+func (client RestClient) SetLogLevel(subsystem string, level string) (response models.LogLevels, err error) {
+	err = client.post(&response, fmt.Sprintf("/logging/levels/%s", subsystem), logLevelParams{Level: level})
+	return
+}
+
+// Shutdown asks the node to shut down gracefully; it returns once the shutdown started
+func (client RestClient) Shutdown() error {
+	return client.post(nil, "/shutdown", nil)
+}
+
 type transactionsByAddrParams struct {
 	FirstRound uint64 `url:"firstRound"`
 	LastRound  uint64 `url:"lastRound"`
@@ -219,6 +278,52 @@ func (client RestClient) TransactionsByAddr(addr string, first, last uint64) (re
 	return
 }
 
+type recentTransactionsByAddrParams struct {
+	Max uint64 `url:"max"`
+}
+
+// RecentTransactionsByAddr returns the [max] most recent transactions of
+// [addr], as found by the indexer of the node.
+func (client RestClient) RecentTransactionsByAddr(addr string, max uint64) (response models.TransactionList, err error) {
+	err = client.get(&response, fmt.Sprintf("/account/%s/transactions", addr), recentTransactionsByAddrParams{max})
+	return
+}
+
+type transactionsByNotePrefixParams struct {
+	NotePrefix string `url:"note-prefix"`
+	Max        uint64 `url:"max"`
+}
+
+// TransactionsByNotePrefix returns the [max] most recent transactions whose
+// note starts with [prefix], as found by the indexer of the node.
+func (client RestClient) TransactionsByNotePrefix(prefix []byte, max uint64) (response models.TransactionList, err error) {
+	err = client.get(&response, "/v2/transactions", transactionsByNotePrefixParams{base64.StdEncoding.EncodeToString(prefix), max})
+	return
+}
+
+type balancesAtRoundParams struct {
+	Round   uint64   `url:"round"`
+	Address []string `url:"address"`
+}
+
+// BalancesAtRound returns the balances of the accounts of [addresses] as of
+// [round], which must be one of the recent rounds the ledger still holds.
+func (client RestClient) BalancesAtRound(round uint64, addresses []string) (response models.Balances, err error) {
+	err = client.get(&response, "/v2/balances", balancesAtRoundParams{round, addresses})
+	return
+}
+
+type accountInformationAtRoundParams struct {
+	Round uint64 `url:"round"`
+}
+
+// AccountInformationAtRound gets the AccountInformationResponse associated with the passed address as of
+// [round], which must be one of the recent rounds the ledger still holds.
+func (client RestClient) AccountInformationAtRound(address string, round uint64) (response models.Account, err error) {
+	err = client.get(&response, fmt.Sprintf("/v2/accounts/%s", address), accountInformationAtRoundParams{round})
+	return
+}
+
 // AccountInformation also gets the AccountInformationResponse associated with the passed address
 func (client RestClient) AccountInformation(address string) (response models.Account, err error) {
 	err = client.get(&response, fmt.Sprintf("/account/%s", address), nil)
@@ -253,6 +358,12 @@ func (client RestClient) SuggestedFee() (response models.TransactionFee, err err
 	return
 }
 
+// FeeInfo gets the suggested transaction fee along with the congestion of the node's transaction pool
+func (client RestClient) FeeInfo() (response models.TransactionFeeInfo, err error) {
+	err = client.get(&response, "/transactions/feeinfo", nil)
+	return
+}
+
 // SuggestedParams gets the suggested transaction parameters
 func (client RestClient) SuggestedParams() (response models.TransactionParams, err error) {
 	err = client.get(&response, "/transactions/params", nil)
@@ -265,12 +376,40 @@ func (client RestClient) SendRawTransaction(txn transactions.SignedTxn) (respons
 	return
 }
 
+// SendRawTransactionGroup gets the SignedTxns of an atomic transaction group and broadcasts them to the network
+// as a whole, returning the ID of the first one
+func (client RestClient) SendRawTransactionGroup(txgroup []transactions.SignedTxn) (response models.TransactionID, err error) {
+	var enc []byte
+	for _, txn := range txgroup {
+		enc = append(enc, protocol.Encode(txn)...)
+	}
+	err = client.post(&response, "/transactions", enc)
+	return
+}
+
+// SendRawTransactionBatch gets a batch of SignedTxns from a client and broadcasts them to the network,
+// returning the outcome of each of them
+func (client RestClient) SendRawTransactionBatch(txns []transactions.SignedTxn) (response models.TransactionBatchResults, err error) {
+	var enc []byte
+	for _, txn := range txns {
+		enc = append(enc, protocol.Encode(txn)...)
+	}
+	err = client.post(&response, "/v2/transactions/batch", enc)
+	return
+}
+
 // Block gets the block info for the given round
 func (client RestClient) Block(round uint64) (response models.Block, err error) {
 	err = client.get(&response, fmt.Sprintf("/block/%d", round), nil)
 	return
 }
 
+// RawBlock gets the msgpack encoded block and certificate for the given round
+func (client RestClient) RawBlock(round uint64) (response []byte, err error) {
+	raw, err := client.doGetWithQuery(context.Background(), fmt.Sprintf("%s/block/%d/raw", apiVersionPathPrefix, round), nil)
+	return []byte(raw), err
+}
+
 // GetGoRoutines gets a dump of the goroutines from pprof
 // Not supported
 func (client RestClient) GetGoRoutines(ctx context.Context) (goRoutines string, err error) {
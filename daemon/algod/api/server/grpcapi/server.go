@@ -0,0 +1,211 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package grpcapi serves the gRPC interface of algod, defined in algodpb.
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/daemon/algod/api/algodpb"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/node"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// TokenMetadataKey is the gRPC metadata key holding the API token, the counterpart of the X-Algo-API-Token header
+const TokenMetadataKey = "x-algo-api-token"
+
+// NewServer returns a gRPC server serving the algodpb.Algod service of node to the clients presenting apiToken
+func NewServer(log logging.Logger, node node.Full, apiToken string) *grpc.Server {
+	auth := tokenAuth{apiToken: []byte(apiToken)}
+	s := grpc.NewServer(grpc.UnaryInterceptor(auth.unary), grpc.StreamInterceptor(auth.stream))
+	algodpb.RegisterAlgodServer(s, &algodServer{log: log, node: node})
+	return s
+}
+
+// tokenAuth rejects the calls which don't carry the API token
+type tokenAuth struct {
+	apiToken []byte
+}
+
+func (a tokenAuth) check(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	provided := md.Get(TokenMetadataKey)
+	// Check the token in constant time
+	if len(provided) != 1 || subtle.ConstantTimeCompare([]byte(provided[0]), a.apiToken) != 1 {
+		return status.Error(codes.Unauthenticated, "Invalid API Token")
+	}
+	return nil
+}
+
+func (a tokenAuth) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.check(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a tokenAuth) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.check(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// algodServer implements algodpb.AlgodServer on top of the node, the same way the REST handlers do
+type algodServer struct {
+	log  logging.Logger
+	node node.Full
+}
+
+func (s *algodServer) Status(ctx context.Context, req *algodpb.StatusRequest) (*algodpb.NodeStatus, error) {
+	stat, err := s.node.Status()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &algodpb.NodeStatus{
+		LastRound:            uint64(stat.LastRound),
+		LastVersion:          string(stat.LastVersion),
+		NextVersion:          string(stat.NextVersion),
+		NextVersionRound:     uint64(stat.NextVersionRound),
+		NextVersionSupported: stat.NextVersionSupported,
+		TimeSinceLastRound:   stat.TimeSinceLastRound().Nanoseconds(),
+		CatchupTime:          stat.CatchupTime.Nanoseconds(),
+	}, nil
+}
+
+func (s *algodServer) AccountInformation(ctx context.Context, req *algodpb.AccountRequest) (*algodpb.Account, error) {
+	addr, err := basics.UnmarshalChecksumAddress(req.Address)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse the address: %v", err)
+	}
+
+	amount, rewards, amountWithoutPendingRewards, accountStatus, round, err := s.node.GetBalanceAndStatus(addr)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	pendingRewards, overflowed := basics.OSubA(amount, amountWithoutPendingRewards)
+	if overflowed {
+		return nil, status.Errorf(codes.Internal, "overflowed pending rewards: %v - %v", amount, amountWithoutPendingRewards)
+	}
+	data, _, err := s.node.GetAccountData(addr)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	account := &algodpb.Account{
+		Round:                       uint64(round),
+		Address:                     addr.GetUserAddress(),
+		Amount:                      amount.Raw,
+		PendingRewards:              pendingRewards.Raw,
+		AmountWithoutPendingRewards: amountWithoutPendingRewards.Raw,
+		Rewards:                     rewards.Raw,
+		Status:                      accountStatus.String(),
+	}
+	if data.VoteID != (crypto.OneTimeSignatureVerifier{}) {
+		account.Participation = &algodpb.Participation{
+			VotePk:          data.VoteID[:],
+			SelectionPk:     data.SelectionID[:],
+			VoteFirst:       uint64(data.VoteFirstValid),
+			VoteLast:        uint64(data.VoteLastValid),
+			VoteKeyDilution: data.VoteKeyDilution,
+		}
+	}
+	if data.AuthAddr != (basics.Address{}) {
+		account.AuthAddr = data.AuthAddr.GetUserAddress()
+	}
+	return account, nil
+}
+
+func (s *algodServer) SendRawTransaction(ctx context.Context, req *algodpb.RawTransaction) (*algodpb.TransactionID, error) {
+	var st transactions.SignedTxn
+	err := protocol.Decode(req.SignedTxn, &st)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	txid, err := s.node.BroadcastSignedTxn(ctx, st)
+	if _, behind := err.(node.NodeBehindError); behind || err == node.ErrSafeMode || err == node.ErrShuttingDown {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &algodpb.TransactionID{Txid: txid.String()}, nil
+}
+
+func (s *algodServer) PendingTransaction(ctx context.Context, req *algodpb.TransactionID) (*algodpb.PendingTransactionStatus, error) {
+	var txID transactions.Txid
+	if txID.UnmarshalText([]byte(req.Txid)) != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse the transaction ID %s", req.Txid)
+	}
+
+	txn, ok := s.node.GetPendingTransaction(txID)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "couldn't find the transaction in the transaction pool or the ledger")
+	}
+	return &algodpb.PendingTransactionStatus{
+		Txid:           txID.String(),
+		ConfirmedRound: uint64(txn.ConfirmedRound),
+		PoolError:      txn.PoolError,
+		SignedTxn:      protocol.Encode(txn.Txn),
+	}, nil
+}
+
+func (s *algodServer) StreamBlocks(req *algodpb.StreamBlocksRequest, stream algodpb.Algod_StreamBlocksServer) error {
+	rnd := basics.Round(req.FromRound)
+	if rnd == 0 {
+		rnd = s.node.LatestRound() + 1
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.node.WaitForRound(rnd):
+		}
+
+		blk, _, err := s.node.GetBlock(rnd)
+		if err != nil {
+			// Non-archival nodes only keep the recent blocks
+			return status.Errorf(codes.NotFound, "block %d is not available: %v", rnd, err)
+		}
+		err = stream.Send(&algodpb.Block{
+			Round:        uint64(blk.Round()),
+			Hash:         crypto.Digest(blk.Hash()).String(),
+			PreviousHash: crypto.Digest(blk.Branch).String(),
+			Timestamp:    blk.TimeStamp,
+			TxnCount:     uint32(len(blk.Payset)),
+			Block:        protocol.Encode(blk),
+		})
+		if err != nil {
+			return err
+		}
+		rnd++
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/daemon/algod/api/algodpb"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/node"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+const testToken = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+// fakeNode serves the status and the blocks up to lastRound; the rest of node.Full isn't used by these tests
+type fakeNode struct {
+	node.Full
+	lastRound basics.Round
+}
+
+func (n *fakeNode) Status() (node.StatusReport, error) {
+	return node.StatusReport{LastRound: n.lastRound, LastVersion: protocol.ConsensusCurrentVersion}, nil
+}
+
+func (n *fakeNode) LatestRound() basics.Round {
+	return n.lastRound
+}
+
+func (n *fakeNode) WaitForRound(r basics.Round) chan struct{} {
+	ch := make(chan struct{})
+	if r <= n.lastRound {
+		close(ch)
+	}
+	return ch
+}
+
+func (n *fakeNode) GetBlock(r basics.Round) (bookkeeping.Block, agreement.Certificate, error) {
+	var blk bookkeeping.Block
+	blk.BlockHeader.Round = r
+	blk.BlockHeader.TimeStamp = int64(r) * 1000
+	return blk, agreement.Certificate{}, nil
+}
+
+func startTestServer(t *testing.T, n node.Full) (algodpb.AlgodClient, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := NewServer(logging.TestingLog(t), n, testToken)
+	go s.Serve(listener)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	stop := func() {
+		conn.Close()
+		s.Stop()
+	}
+	return algodpb.NewAlgodClient(conn), stop
+}
+
+func TestTokenAuth(t *testing.T) {
+	client, stop := startTestServer(t, &fakeNode{lastRound: 7})
+	defer stop()
+
+	_, err := client.Status(context.Background(), &algodpb.StatusRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), TokenMetadataKey, "wrong")
+	_, err = client.Status(ctx, &algodpb.StatusRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), TokenMetadataKey, testToken)
+	stat, err := client.Status(ctx, &algodpb.StatusRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), stat.LastRound)
+	require.Equal(t, string(protocol.ConsensusCurrentVersion), stat.LastVersion)
+}
+
+func TestStreamBlocks(t *testing.T) {
+	client, stop := startTestServer(t, &fakeNode{lastRound: 5})
+	defer stop()
+
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(context.Background(), TokenMetadataKey, testToken))
+	defer cancel()
+	stream, err := client.StreamBlocks(ctx, &algodpb.StreamBlocksRequest{FromRound: 3})
+	require.NoError(t, err)
+
+	for rnd := uint64(3); rnd <= 5; rnd++ {
+		blk, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, rnd, blk.Round)
+		require.Equal(t, int64(rnd)*1000, blk.Timestamp)
+
+		var decoded bookkeeping.Block
+		require.NoError(t, protocol.Decode(blk.Block, &decoded))
+		require.Equal(t, basics.Round(rnd), decoded.Round())
+	}
+
+	// Round 6 doesn't exist yet, so the stream waits until the client gives up
+	cancel()
+	_, err = stream.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestAccountInformationBadAddress(t *testing.T) {
+	client, stop := startTestServer(t, &fakeNode{})
+	defer stop()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), TokenMetadataKey, testToken)
+	_, err := client.AccountInformation(ctx, &algodpb.AccountRequest{Address: "not an address"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
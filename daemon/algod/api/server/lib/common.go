@@ -51,6 +51,8 @@ type ReqContext struct {
 	Node          node.Full
 	Log           logging.Logger
 	StaticDataDir string
+	// Shutdown shuts the node down gracefully
+	Shutdown func()
 }
 
 // ErrorResponse sets the specified status code (should != 200), and fills in the
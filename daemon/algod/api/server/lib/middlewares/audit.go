@@ -0,0 +1,112 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/algorand/go-algorand/daemon/algod/audit"
+	"github.com/algorand/go-algorand/logging"
+)
+
+// auditedBody hashes and counts the request body as the handler reads it.
+type auditedBody struct {
+	io.ReadCloser
+	hash hash.Hash
+	size int64
+}
+
+func (b *auditedBody) Read(p []byte) (n int, err error) {
+	n, err = b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	b.size += int64(n)
+	return
+}
+
+// Audit is a gorilla/mux middleware that records every mutating API call into the audit log. It runs ahead of Auth on
+// purpose, so that the calls refused for a missing or wrong token are recorded too, under the identity of the token
+// they provided and with their 401 status. Neither the token nor the path it may be part of are recorded as is.
+func Audit(log logging.Logger, auditLog *audit.Log) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" || r.Method == "HEAD" || r.Method == "OPTIONS" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body := &auditedBody{ReadCloser: r.Body, hash: sha256.New()}
+			r.Body = body
+			wrapper := ResponseWriterWrapper(w)
+			next.ServeHTTP(wrapper, r)
+
+			token := r.Header.Get(TokenHeader)
+			params := make(map[string]string)
+			for k, v := range mux.Vars(r) {
+				if k == "apiToken" {
+					token = v
+					continue
+				}
+				params[k] = v
+			}
+			for k, v := range r.URL.Query() {
+				if len(v) > 0 {
+					params[k] = v[0]
+				}
+			}
+			entry := audit.Entry{
+				Action: r.Method + " " + auditedPath(r),
+				Token:  audit.TokenIdentity(token),
+				Source: r.RemoteAddr,
+				Params: params,
+				Status: wrapper.statusCode,
+			}
+			if body.size > 0 {
+				entry.BodySize = body.size
+				entry.BodyDigest = hex.EncodeToString(body.hash.Sum(nil))
+			}
+			if err := auditLog.Record(entry); err != nil {
+				log.Warnf("unable to record %s in the audit log: %v", entry.Action, err)
+			}
+		})
+	}
+}
+
+// auditedPath returns the path template of the route the request matched, which leaves out the API token of the
+// /urlAuth routes, or the request path with the token segment redacted when it matched no route.
+func auditedPath(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	path := r.URL.Path
+	if strings.HasPrefix(path, urlAuthPrefix) {
+		rest := strings.TrimPrefix(path, urlAuthPrefix)
+		if i := strings.Index(rest, "/"); i >= 0 {
+			return urlAuthPrefix + "{apiToken}" + rest[i:]
+		}
+		return urlAuthPrefix + "{apiToken}"
+	}
+	return path
+}
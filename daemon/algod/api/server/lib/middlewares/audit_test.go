@@ -0,0 +1,103 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package middlewares
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/daemon/algod/audit"
+	"github.com/algorand/go-algorand/logging"
+)
+
+func TestAudit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	const apiToken = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	const wrongToken = "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	log := logging.TestingLog(t)
+
+	// the middlewares are in the order of the algod router
+	router := mux.NewRouter()
+	router.Use(Audit(log, audit.MakeLog(dir, 1<<20, 1)))
+	router.Use(Auth(log, apiToken))
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}
+	router.HandleFunc("/v1/account/{addr}/transactions", ok).Methods("POST")
+	router.PathPrefix("/urlAuth/{apiToken:[0-9a-f]+}").PathPrefix("/debug/pprof/").HandlerFunc(ok).Name(debugRouteName)
+
+	request := func(path string, token string) {
+		req := httptest.NewRequest("POST", path, strings.NewReader("body"))
+		if token != "" {
+			req.Header.Set(TokenHeader, token)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	request("/v1/account/ADDR/transactions", apiToken)
+	request("/v1/account/ADDR/transactions", wrongToken)
+	request("/v1/account/ADDR/transactions", "")
+	request("/urlAuth/"+apiToken+"/debug/pprof/symbol", "")
+	request("/urlAuth/"+wrongToken+"/debug/pprof/symbol", "")
+
+	entries, err := audit.ReadLog(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+
+	require.Equal(t, "POST /v1/account/{addr}/transactions", entries[0].Action)
+	require.Equal(t, map[string]string{"addr": "ADDR"}, entries[0].Params)
+	require.Equal(t, audit.TokenIdentity(apiToken), entries[0].Token)
+	require.Equal(t, http.StatusOK, entries[0].Status)
+	require.Equal(t, int64(len("body")), entries[0].BodySize)
+
+	// the calls Auth refuses are recorded as well, under the token they provided
+	require.Equal(t, audit.TokenIdentity(wrongToken), entries[1].Token)
+	require.Equal(t, http.StatusUnauthorized, entries[1].Status)
+	require.Empty(t, entries[2].Token)
+	require.Equal(t, http.StatusUnauthorized, entries[2].Status)
+
+	// tokens given in the path are never recorded as is
+	require.Equal(t, audit.TokenIdentity(apiToken), entries[3].Token)
+	require.Equal(t, http.StatusOK, entries[3].Status)
+	require.Equal(t, audit.TokenIdentity(wrongToken), entries[4].Token)
+	require.Equal(t, http.StatusUnauthorized, entries[4].Status)
+	for _, entry := range entries {
+		require.NotContains(t, entry.Action, apiToken)
+		require.NotContains(t, entry.Action, wrongToken)
+		for _, value := range entry.Params {
+			require.NotContains(t, value, apiToken)
+			require.NotContains(t, value, wrongToken)
+		}
+	}
+}
+
+func TestAuditedPath(t *testing.T) {
+	const token = "0123456789abcdef"
+	require.Equal(t, "/urlAuth/{apiToken}/debug/pprof/symbol", auditedPath(httptest.NewRequest("POST", "/urlAuth/"+token+"/debug/pprof/symbol", nil)))
+	require.Equal(t, "/urlAuth/{apiToken}", auditedPath(httptest.NewRequest("POST", "/urlAuth/"+token, nil)))
+	require.Equal(t, "/v1/transactions", auditedPath(httptest.NewRequest("POST", "/v1/transactions", nil)))
+}
@@ -32,6 +32,7 @@ import (
 const TokenHeader = "X-Algo-API-Token"
 
 const urlAuthFormatter = "/urlAuth/%s"
+const urlAuthPrefix = "/urlAuth/"
 const debugRouteName = "debug"
 
 // Allowed auth bypass names
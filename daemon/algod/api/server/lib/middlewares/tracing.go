@@ -0,0 +1,52 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/algorand/go-algorand/util/tracing"
+)
+
+// Tracing is a gorilla/mux middleware that traces the handling of each API request, continuing the trace of the
+// caller when the request carries a traceparent header.
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !tracing.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		name := r.URL.Path
+		if route := mux.CurrentRoute(r); route != nil && route.GetName() != "" {
+			name = route.GetName()
+		}
+		ctx, span := tracing.StartSpan(tracing.Extract(r.Context(), r.Header), fmt.Sprintf("%s %s", r.Method, name), tracing.SpanKindServer,
+			tracing.Attr("http.method", r.Method),
+			tracing.Attr("http.target", r.URL.Path))
+		defer span.End()
+
+		wrapper := ResponseWriterWrapper(w)
+		next.ServeHTTP(wrapper, r.WithContext(ctx))
+		span.SetAttributes(tracing.Attr("http.status_code", wrapper.statusCode))
+		if wrapper.statusCode >= http.StatusInternalServerError {
+			span.SetError(fmt.Errorf("%s", http.StatusText(wrapper.statusCode)))
+		}
+	})
+}
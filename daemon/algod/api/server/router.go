@@ -69,12 +69,15 @@ import (
 	"github.com/algorand/go-algorand/daemon/algod/api/server/lib"
 	"github.com/algorand/go-algorand/daemon/algod/api/server/lib/middlewares"
 	"github.com/algorand/go-algorand/daemon/algod/api/server/v1/routes"
+	v2routes "github.com/algorand/go-algorand/daemon/algod/api/server/v2/routes"
+	"github.com/algorand/go-algorand/daemon/algod/audit"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/node"
 )
 
 const (
 	apiV1Tag              = "v1"
+	apiV2Tag              = "v2"
 	debugRouteName        = "debug"
 	urlAuthEndpointPrefix = "/urlAuth/{apiToken:[0-9a-f]+}"
 )
@@ -105,17 +108,23 @@ func registerHandlers(router *mux.Router, prefix string, routes lib.Routes, ctx
 	}
 }
 
-// NewRouter builds and returns a new router from routes
-func NewRouter(logger logging.Logger, node node.Full, apiToken string, staticFileDir string) *mux.Router {
+// NewRouter builds and returns a new router from routes. The mutating calls are recorded into auditLog, unless it is nil.
+// The shutdown endpoint calls shutdown.
+func NewRouter(logger logging.Logger, node node.Full, apiToken string, staticFileDir string, auditLog *audit.Log, shutdown func()) *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
+	logger = logger.WithSubsystem(logging.RESTSubsystem)
 
 	// Middleware
 	router.Use(middlewares.Logger(logger))
+	router.Use(middlewares.Tracing)
+	if auditLog != nil {
+		router.Use(middlewares.Audit(logger, auditLog))
+	}
 	router.Use(middlewares.Auth(logger, apiToken))
 	router.Use(middlewares.CORS)
 
 	// Request Context
-	ctx := lib.ReqContext{Node: node, Log: logger, StaticDataDir: staticFileDir}
+	ctx := lib.ReqContext{Node: node, Log: logger, StaticDataDir: staticFileDir, Shutdown: shutdown}
 
 	// Registers /debug/pprof handler under root path and under /urlAuth path
 	// to support header or url-provided token.
@@ -130,5 +139,8 @@ func NewRouter(logger logging.Logger, node node.Full, apiToken string, staticFil
 	// Registering v1 routes
 	registerHandlers(router, apiV1Tag, routes.Routes, ctx)
 
+	// Registering v2 routes
+	registerHandlers(router, apiV2Tag, v2routes.Routes, ctx)
+
 	return router
 }
@@ -26,8 +26,17 @@ var (
 	errFailedLookingUpLedger               = "failed to retrieve information from the ledger"
 	errTransactionNotFound                 = "couldn't find the required transaction in the required range"
 	errFailedLookingUpTransactionPool      = "failed to retrieve information from the transaction pool"
+	errTransactionNotTracked               = "the transaction isn't tracked for rebroadcasting"
+	errTransactionNotPending               = "the transaction isn't pending in the transaction pool"
 	errBlockHashBeenDeletedArchival        = "this is a non-archival node and the requested block has been already deleted"
 	errFailedGettingInformationFromIndexer = "failed retrieving information from the indexer"
 	errIndexerNotRunning                   = "indexer isn't running, this call is disabled"
 	errNoRoundsSpecified                   = "Indexer is not enabled, firstRound and lastRound must be specified"
+	errNoNotePrefixSpecified               = "no note prefix was specified"
+	errFailedParsingNotePrefix             = "failed to parse the note prefix, it must be base64 encoded"
+	errRoundInTheFuture                    = "the round has not been reached yet"
+	errBalancesNotAvailable                = "the ledger no longer holds the balances of that round"
+	errEmptyTransactionBatch               = "the batch holds no transaction"
+	errEmptyTransactionGroup               = "the request holds no transaction"
+	errTransactionBatchTooLarge            = "the batch holds too many transactions"
 )
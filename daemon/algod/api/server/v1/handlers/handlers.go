@@ -17,8 +17,12 @@
 package handlers
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
@@ -33,25 +37,37 @@ import (
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/node"
+	"github.com/algorand/go-algorand/node/indexer"
 	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/rpcs"
 )
 
+// rawBlockContentType is the content type of the encoded blocks served by GetRawBlock.
+const rawBlockContentType = "application/x-algorand-block-v1"
+
 func nodeStatus(node node.Full) (res NodeStatus, err error) {
 	stat, err := node.Status()
 	if err != nil {
 		return NodeStatus{}, err
 	}
 
-	return NodeStatus{
-		LastRound:            uint64(stat.LastRound),
-		LastVersion:          string(stat.LastVersion),
-		NextVersion:          string(stat.NextVersion),
-		NextVersionRound:     uint64(stat.NextVersionRound),
-		NextVersionSupported: stat.NextVersionSupported,
-		TimeSinceLastRound:   stat.TimeSinceLastRound().Nanoseconds(),
-		CatchupTime:          stat.CatchupTime.Nanoseconds(),
-	}, nil
+	res = NodeStatus{
+		LastRound:              uint64(stat.LastRound),
+		LastVersion:            string(stat.LastVersion),
+		NextVersion:            string(stat.NextVersion),
+		NextVersionRound:       uint64(stat.NextVersionRound),
+		NextVersionSupported:   stat.NextVersionSupported,
+		TimeSinceLastRound:     stat.TimeSinceLastRound().Nanoseconds(),
+		CatchupTime:            stat.CatchupTime.Nanoseconds(),
+		CatchupBlocks:          stat.CatchupProgress.Blocks,
+		CatchupBlocksPerSecond: stat.CatchupProgress.BlocksPerSecond,
+	}
+	for _, source := range stat.CatchupProgress.Sources {
+		res.CatchupSources = append(res.CatchupSources, CatchupSource{Address: source.Address, Blocks: source.Blocks})
+	}
+	return res, nil
 }
 
 func paymentTxEncode(tx transactions.Transaction, ad transactions.ApplyData) Transaction {
@@ -67,7 +83,7 @@ func paymentTxEncode(tx transactions.Transaction, ad transactions.ApplyData) Tra
 		payment.CloseAmount = ad.ClosingAmount.Raw
 	}
 
-	return Transaction{
+	encoded := Transaction{
 		Type:        tx.Type,
 		TxID:        tx.ID().String(),
 		From:        tx.Src().GetChecksumAddress().String(),
@@ -80,6 +96,72 @@ func paymentTxEncode(tx transactions.Transaction, ad transactions.ApplyData) Tra
 		GenesisID:   tx.GenesisID,
 		GenesisHash: tx.GenesisHash[:],
 	}
+	if tx.RekeyTo != (basics.Address{}) {
+		encoded.RekeyTo = tx.RekeyTo.GetChecksumAddress().String()
+	}
+	if tx.Group != (crypto.Digest{}) {
+		encoded.Group = tx.Group[:]
+	}
+	if tx.Lease != [32]byte{} {
+		encoded.Lease = tx.Lease[:]
+	}
+
+	switch tx.Type {
+	case protocol.AssetConfigTx:
+		encoded.AssetConfig = &AssetConfigTransactionType{
+			AssetID: uint64(tx.ConfigAsset.Index),
+			Params:  assetParams(tx.ConfigAsset.Creator, tx.AssetParams),
+		}
+		if tx.ConfigAsset == (basics.AssetID{}) {
+			encoded.AssetConfig.Params.Creator = tx.Sender.GetChecksumAddress().String()
+		}
+	case protocol.AssetTransferTx:
+		encoded.AssetTransfer = &AssetTransferTransactionType{
+			AssetID:  uint64(tx.XferAsset.Index),
+			Creator:  tx.XferAsset.Creator.GetChecksumAddress().String(),
+			Amount:   tx.AssetAmount,
+			Receiver: tx.AssetReceiver.GetChecksumAddress().String(),
+		}
+		if tx.AssetSender != (basics.Address{}) {
+			encoded.AssetTransfer.Sender = tx.AssetSender.GetChecksumAddress().String()
+		}
+		if tx.AssetCloseTo != (basics.Address{}) {
+			encoded.AssetTransfer.CloseTo = tx.AssetCloseTo.GetChecksumAddress().String()
+		}
+	case protocol.AssetFreezeTx:
+		encoded.AssetFreeze = &AssetFreezeTransactionType{
+			AssetID:         uint64(tx.FreezeAsset.Index),
+			Creator:         tx.FreezeAsset.Creator.GetChecksumAddress().String(),
+			Account:         tx.FreezeAccount.GetChecksumAddress().String(),
+			NewFreezeStatus: tx.AssetFrozen,
+		}
+	}
+
+	return encoded
+}
+
+// checksumAddressOrEmpty returns the checksummed form of addr, or the
+// empty string for the zero address.
+func checksumAddressOrEmpty(addr basics.Address) string {
+	if addr == (basics.Address{}) {
+		return ""
+	}
+	return addr.GetChecksumAddress().String()
+}
+
+func assetParams(creator basics.Address, params basics.AssetParams) AssetParams {
+	return AssetParams{
+		Creator:       checksumAddressOrEmpty(creator),
+		Total:         params.Total,
+		DefaultFrozen: params.DefaultFrozen,
+		UnitName:      params.UnitName,
+		AssetName:     params.AssetName,
+		URL:           params.URL,
+		ManagerAddr:   checksumAddressOrEmpty(params.Manager),
+		ReserveAddr:   checksumAddressOrEmpty(params.Reserve),
+		FreezeAddr:    checksumAddressOrEmpty(params.Freeze),
+		ClawbackAddr:  checksumAddressOrEmpty(params.Clawback),
+	}
 }
 
 func txWithStatusEncode(tr node.TxnWithStatus) Transaction {
@@ -213,11 +295,30 @@ func WaitForBlock(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
 	SendJSON(response, w, ctx.Log)
 }
 
+// sendNodeBehind answers 503 with the sync status of a node too far behind the network to accept transactions,
+// so that the clients can tell it from the other refusals and submit them to another node
+func sendNodeBehind(behind node.NodeBehindError, w http.ResponseWriter, log logging.Logger) {
+	log.Info(behind)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	err := writeJSON(&NodeBehind{
+		Message:            behind.Error(),
+		LastRound:          uint64(behind.LastRound),
+		RoundsBehind:       behind.RoundsBehind,
+		TimeSinceLastRound: behind.TimeSinceLastRound.Nanoseconds(),
+		CatchupTime:        behind.CatchupTime.Nanoseconds(),
+	}, w)
+	if err != nil {
+		log.Warnf("algod failed to write an object to the response stream: %v", err)
+	}
+}
+
 // RawTransaction is an httpHandler for route POST /v1/transactions
 func RawTransaction(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /v1/transactions RawTransaction
 	// ---
 	//     Summary: Broadcasts a raw transaction to the network.
+	//     Description: Takes the msgpack encoding of a signed transaction, or the concatenated msgpack encodings of the signed transactions of an atomic transaction group, in the order of the group. The transactions of a group are admitted into the transaction pool and broadcast as a whole, and the response holds the ID of the first one.
 	//     Produces:
 	//     - application/json
 	//     Consumes:
@@ -231,7 +332,7 @@ func RawTransaction(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request)
 	//           type: string
 	//           format: binary
 	//         required: true
-	//         description: The byte encoded signed transaction to broadcast to network
+	//         description: The byte encoded signed transaction, or transaction group, to broadcast to network
 	//     Responses:
 	//       200:
 	//         "$ref": "#/responses/TransactionIDResponse"
@@ -241,16 +342,41 @@ func RawTransaction(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request)
 	//       500:
 	//         description: Internal Error
 	//         schema: {type: string}
+	//       503:
+	//         description: The node is shutting down, in safe mode because its disk is running out of space, or too far behind the network, in which case the body is the JSON NodeBehind sync status
+	//         schema: {type: string}
 	//       401: { description: Invalid API Token }
 	//       default: { description: Unknown Error }
-	var st transactions.SignedTxn
-	err := protocol.NewDecoder(r.Body).Decode(&st)
-	if err != nil {
+	var txgroup []transactions.SignedTxn
+	dec := protocol.NewDecoder(r.Body)
+	for {
+		var st transactions.SignedTxn
+		err := dec.Decode(&st)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			lib.ErrorResponse(w, http.StatusBadRequest, err, err.Error(), ctx.Log)
+			return
+		}
+		txgroup = append(txgroup, st)
+	}
+	if len(txgroup) == 0 {
+		err := errors.New(errEmptyTransactionGroup)
 		lib.ErrorResponse(w, http.StatusBadRequest, err, err.Error(), ctx.Log)
 		return
 	}
 
-	txid, err := ctx.Node.BroadcastSignedTxn(st)
+	err := ctx.Node.BroadcastSignedTxnGroup(r.Context(), txgroup)
+	txid := txgroup[0].ID()
+	if behind, ok := err.(node.NodeBehindError); ok {
+		sendNodeBehind(behind, w, ctx.Log)
+		return
+	}
+	if err == node.ErrSafeMode || err == node.ErrShuttingDown {
+		lib.ErrorResponse(w, http.StatusServiceUnavailable, err, err.Error(), ctx.Log)
+		return
+	}
 	if err != nil {
 		lib.ErrorResponse(w, http.StatusBadRequest, err, err.Error(), ctx.Log)
 		return
@@ -259,6 +385,90 @@ func RawTransaction(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request)
 	SendJSON(TransactionIDResponse{&TransactionID{TxID: txid.String()}}, w, ctx.Log)
 }
 
+// maxTransactionBatchSize is the largest number of transactions POST /v2/transactions/batch takes at once
+const maxTransactionBatchSize = 1024
+
+// RawTransactionBatch is an httpHandler for route POST /v2/transactions/batch
+func RawTransactionBatch(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /v2/transactions/batch RawTransactionBatch
+	// ---
+	//     Summary: Broadcasts a batch of raw transactions to the network.
+	//     Description: Takes the concatenated msgpack encodings of up to 1024 signed transactions, and admits them into the transaction pool in a row, as if they were posted one by one without other transactions in between. The node broadcasts the transactions it accepts, and reports the outcome of each transaction, in the order of the batch.
+	//     Produces:
+	//     - application/json
+	//     Consumes:
+	//     - application/x-binary
+	//     Schemes:
+	//     - http
+	//     Parameters:
+	//       - name: rawtxns
+	//         in: body
+	//         schema:
+	//           type: string
+	//           format: binary
+	//         required: true
+	//         description: The byte encoded signed transactions to broadcast to network, one after the other
+	//     Responses:
+	//       200:
+	//         "$ref": "#/responses/TransactionBatchResponse"
+	//       400:
+	//         description: Bad Request
+	//         schema: {type: string}
+	//       503:
+	//         description: The node is shutting down, in safe mode because its disk is running out of space, or too far behind the network, in which case the body is the JSON NodeBehind sync status
+	//         schema: {type: string}
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+	var txns []transactions.SignedTxn
+	dec := protocol.NewDecoder(r.Body)
+	for {
+		var st transactions.SignedTxn
+		err := dec.Decode(&st)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			lib.ErrorResponse(w, http.StatusBadRequest, err, err.Error(), ctx.Log)
+			return
+		}
+		if len(txns) == maxTransactionBatchSize {
+			lib.ErrorResponse(w, http.StatusBadRequest, errors.New(errTransactionBatchTooLarge), errTransactionBatchTooLarge, ctx.Log)
+			return
+		}
+		txns = append(txns, st)
+	}
+	if len(txns) == 0 {
+		lib.ErrorResponse(w, http.StatusBadRequest, errors.New(errEmptyTransactionBatch), errEmptyTransactionBatch, ctx.Log)
+		return
+	}
+
+	errs, err := ctx.Node.BroadcastSignedTxnBatch(r.Context(), txns)
+	if behind, ok := err.(node.NodeBehindError); ok {
+		sendNodeBehind(behind, w, ctx.Log)
+		return
+	}
+	if err == node.ErrSafeMode || err == node.ErrShuttingDown {
+		lib.ErrorResponse(w, http.StatusServiceUnavailable, err, err.Error(), ctx.Log)
+		return
+	}
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, err, err.Error(), ctx.Log)
+		return
+	}
+
+	results := TransactionBatchResults{Results: make([]TransactionBatchResult, len(txns))}
+	for i, st := range txns {
+		results.Results[i].TxID = st.ID().String()
+		if errs[i] != nil {
+			results.Results[i].Error = errs[i].Error()
+			continue
+		}
+		results.Accepted++
+	}
+
+	SendJSON(TransactionBatchResponse{&results}, w, ctx.Log)
+}
+
 // AccountInformation is an httpHandler for route GET /v1/account/{addr:[A-Z0-9]{KeyLength}}
 func AccountInformation(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /v1/account/{address} AccountInformation
@@ -307,13 +517,111 @@ func AccountInformation(ctx lib.ReqContext, w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	pendingRewards, overflowed := basics.OSubA(amount, amountWithoutPendingRewards)
-	if overflowed {
-		err = fmt.Errorf("overflowed pending rewards: %v - %v", amount, amountWithoutPendingRewards)
+	data, _, err := ctx.Node.GetAccountData(basics.Address(addr))
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusInternalServerError, err, errFailedLookingUpLedger, ctx.Log)
+		return
+	}
+
+	accountInfo, err := makeAccount(basics.Address(addr), amount, rewards, amountWithoutPendingRewards, status, round, data)
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusInternalServerError, err, errInternalFailure, ctx.Log)
+		return
+	}
+
+	SendJSON(AccountInformationResponse{&accountInfo}, w, ctx.Log)
+}
+
+// AccountInformationAtRound is an httpHandler for route GET /v2/accounts/{addr:[A-Z0-9]{KeyLength}}
+func AccountInformationAtRound(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v2/accounts/{address} AccountInformationAtRound
+	// ---
+	//     Summary: Get account information as of a round.
+	//     Description: Given a specific account public key, this call returns the accounts status, balance and spendable amounts as they were at the end of the given round, or of the latest round if none is given. The ledger keeps the account state of the most recent rounds only, at least MaxBalLookback of them.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Parameters:
+	//       - name: address
+	//         in: path
+	//         type: string
+	//         pattern: "[A-Z0-9]{58}"
+	//         required: true
+	//         description: An account public key
+	//       - name: round
+	//         in: query
+	//         type: integer
+	//         format: int64
+	//         required: false
+	//         description: The round to look the account up at.
+	//     Responses:
+	//       200:
+	//         "$ref": '#/responses/AccountInformationResponse'
+	//       400:
+	//         description: Bad Request
+	//         schema: {type: string}
+	//       500:
+	//         description: Internal Error
+	//         schema: {type: string}
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+	queryAddr := mux.Vars(r)["addr"]
+
+	if queryAddr == "" {
+		lib.ErrorResponse(w, http.StatusBadRequest, errors.New(errNoAccountSpecified), errNoAccountSpecified, ctx.Log)
+		return
+	}
+
+	addr, err := basics.UnmarshalChecksumAddress(queryAddr)
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, err, errFailedToParseAddress, ctx.Log)
+		return
+	}
+
+	latest := ctx.Node.LatestRound()
+	round := latest
+	if queryRound := r.FormValue("round"); queryRound != "" {
+		parsed, err := strconv.ParseUint(queryRound, 10, 64)
+		if err != nil {
+			lib.ErrorResponse(w, http.StatusBadRequest, err, errFailedParsingRoundNumber, ctx.Log)
+			return
+		}
+		round = basics.Round(parsed)
+	}
+	if round > latest {
+		lib.ErrorResponse(w, http.StatusBadRequest, errors.New(errRoundInTheFuture), errRoundInTheFuture, ctx.Log)
+		return
+	}
+
+	amount, rewards, amountWithoutPendingRewards, status, err := ctx.Node.GetBalanceAndStatusAtRound(addr, round)
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, err, errBalancesNotAvailable, ctx.Log)
+		return
+	}
+
+	data, err := ctx.Node.GetAccountDataAtRound(addr, round)
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, err, errBalancesNotAvailable, ctx.Log)
+		return
+	}
+
+	accountInfo, err := makeAccount(addr, amount, rewards, amountWithoutPendingRewards, status, round, data)
+	if err != nil {
 		lib.ErrorResponse(w, http.StatusInternalServerError, err, errInternalFailure, ctx.Log)
 		return
 	}
 
+	SendJSON(AccountInformationResponse{&accountInfo}, w, ctx.Log)
+}
+
+// makeAccount builds the Account model from the balances and account data looked up at round
+func makeAccount(addr basics.Address, amount, rewards, amountWithoutPendingRewards basics.MicroAlgos, status basics.Status, round basics.Round, data basics.AccountData) (Account, error) {
+	pendingRewards, overflowed := basics.OSubA(amount, amountWithoutPendingRewards)
+	if overflowed {
+		return Account{}, fmt.Errorf("overflowed pending rewards: %v - %v", amount, amountWithoutPendingRewards)
+	}
+
 	accountInfo := Account{
 		Round:                       uint64(round),
 		Address:                     addr.GetChecksumAddress().String(),
@@ -324,7 +632,36 @@ func AccountInformation(ctx lib.ReqContext, w http.ResponseWriter, r *http.Reque
 		Status:                      status.String(),
 	}
 
-	SendJSON(AccountInformationResponse{&accountInfo}, w, ctx.Log)
+	if data.VoteID != (crypto.OneTimeSignatureVerifier{}) {
+		accountInfo.Participation = &Participation{
+			ParticipationPK: data.VoteID[:],
+			VRFPK:           data.SelectionID[:],
+			VoteFirst:       uint64(data.VoteFirstValid),
+			VoteLast:        uint64(data.VoteLastValid),
+			VoteKeyDilution: data.VoteKeyDilution,
+		}
+	}
+	if data.AuthAddr != (basics.Address{}) {
+		accountInfo.AuthAddr = data.AuthAddr.GetChecksumAddress().String()
+	}
+	if len(data.AssetParams) > 0 {
+		accountInfo.AssetParams = make(map[uint64]AssetParams, len(data.AssetParams))
+		for index, params := range data.AssetParams {
+			accountInfo.AssetParams[uint64(index)] = assetParams(addr, params)
+		}
+	}
+	if len(data.Assets) > 0 {
+		accountInfo.Assets = make(map[uint64]AssetHolding, len(data.Assets))
+		for id, holding := range data.Assets {
+			accountInfo.Assets[uint64(id.Index)] = AssetHolding{
+				Creator: id.Creator.GetChecksumAddress().String(),
+				Amount:  holding.Amount,
+				Frozen:  holding.Frozen,
+			}
+		}
+	}
+
+	return accountInfo, nil
 }
 
 // TransactionInformation is an httpHandler for route GET /v1/account/{addr:[A-Z0-9]{KeyLength}}/transaction/{txid:[A-Z0-9]+}
@@ -487,6 +824,67 @@ func PendingTransactionInformation(ctx lib.ReqContext, w http.ResponseWriter, r
 	return
 }
 
+// TransactionRebroadcastStatus is an httpHandler for route GET /v1/transactions/rebroadcast/{txid:[A-Z0-9]+}
+func TransactionRebroadcastStatus(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v1/transactions/rebroadcast/{txid} TransactionRebroadcastStatus
+	// ---
+	//     Summary: Get the rebroadcast status of a transaction submitted through this node.
+	//     Description: >
+	//       Given a transaction id of a transaction submitted through this node, it returns
+	//       whether the node keeps rebroadcasting it, or why it stopped doing so. The status of
+	//       a transaction is kept for a while after it was confirmed, expired or rejected.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Parameters:
+	//       - name: txid
+	//         in: path
+	//         type: string
+	//         pattern: "[A-Z0-9]+"
+	//         required: true
+	//         description: A transaction id
+	//     Responses:
+	//       200:
+	//         "$ref": '#/responses/RebroadcastStatusResponse'
+	//       400:
+	//         description: Bad Request
+	//         schema: {type: string}
+	//       404:
+	//         description: Transaction Not Tracked
+	//         schema: {type: string}
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+
+	queryTxID := mux.Vars(r)["txid"]
+	txID := transactions.Txid{}
+	if queryTxID == "" || txID.UnmarshalText([]byte(queryTxID)) != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, errors.New(errNoTxnSpecified), errNoTxnSpecified, ctx.Log)
+		return
+	}
+
+	status, ok := ctx.Node.RebroadcastStatus(txID)
+	if !ok {
+		lib.ErrorResponse(w, http.StatusNotFound, errors.New(errTransactionNotTracked), errTransactionNotTracked, ctx.Log)
+		return
+	}
+
+	response := RebroadcastStatusResponse{
+		Body: &RebroadcastStatus{
+			TxID:           status.TxID.String(),
+			State:          status.State,
+			Submitted:      status.Submitted.Unix(),
+			LastBroadcast:  status.LastBroadcast.Unix(),
+			Broadcasts:     uint64(status.Broadcasts),
+			Peers:          uint64(status.Peers),
+			LastValid:      uint64(status.LastValid),
+			ConfirmedRound: uint64(status.ConfirmedRound),
+			Error:          status.Error,
+		},
+	}
+	SendJSON(response, w, ctx.Log)
+}
+
 // GetPendingTransactions is an httpHandler for route GET /v1/transactions/pending.
 func GetPendingTransactions(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /v1/transactions/pending GetPendingTransactions
@@ -552,43 +950,342 @@ func GetPendingTransactions(ctx lib.ReqContext, w http.ResponseWriter, r *http.R
 	SendJSON(response, w, ctx.Log)
 }
 
-// SuggestedFee is an httpHandler for route GET /v1/transactions/fee
-func SuggestedFee(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
-	// swagger:operation GET /v1/transactions/fee SuggestedFee
+// GetSenderPendingTransactions is an httpHandler for route GET /v1/account/{addr:[A-Z0-9]{KeyLength}}/transactions/pending.
+func GetSenderPendingTransactions(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v1/account/{address}/transactions/pending GetSenderPendingTransactions
 	// ---
-	//     Summary: Get the suggested fee
+	//     Summary: Get the list of unconfirmed transactions of a sender currently in the transaction pool.
 	//     Description: >
-	//       Suggested Fee is returned in units of micro-Algos per byte.
-	//       Suggested Fee may fall to zero but submitted transactions
-	//       must still have a fee of at least MinTxnFee for the current
-	//       network protocol.
+	//       Get the list of pending transactions sent by the given address, in the order
+	//       they are considered for inclusion in a block, truncated at the end at MAX.
+	//       If MAX = 0, returns all the pending transactions of the sender.
 	//     Produces:
 	//     - application/json
 	//     Schemes:
 	//     - http
+	//     Parameters:
+	//       - name: address
+	//         in: path
+	//         type: string
+	//         pattern: "[A-Z0-9]{58}"
+	//         required: true
+	//         description: An account public key
+	//       - name: max
+	//         in: query
+	//         type: integer
+	//         format: int64
+	//         minimum: 0
+	//         required: false
+	//         description: Truncated number of transactions to display. If max=0, returns all pending txns of the sender.
 	//     Responses:
 	//       "200":
-	//         "$ref": '#/responses/TransactionFeeResponse'
+	//         "$ref": '#/responses/SenderPendingTransactionsResponse'
+	//       400:
+	//         description: Bad Request
+	//         schema: {type: string}
+	//       500:
+	//         description: Internal Error
+	//         schema: {type: string}
 	//       401: { description: Invalid API Token }
 	//       default: { description: Unknown Error }
-	fee := TransactionFee{Fee: ctx.Node.SuggestedFee().Raw}
-	SendJSON(TransactionFeeResponse{&fee}, w, ctx.Log)
-}
+	queryAddr := mux.Vars(r)["addr"]
+	if queryAddr == "" {
+		lib.ErrorResponse(w, http.StatusBadRequest, errors.New(errNoAccountSpecified), errNoAccountSpecified, ctx.Log)
+		return
+	}
 
-// SuggestedParams is an httpHandler for route GET /v1/transactions/params
-func SuggestedParams(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
-	// swagger:operation GET /v1/transactions/params TransactionParams
-	// ---
-	//     Summary: Get parameters for constructing a new transaction
-	//     Produces:
-	//     - application/json
-	//     Schemes:
-	//     - http
-	//     Responses:
-	//       "200":
-	//         "$ref": '#/responses/TransactionParamsResponse'
-	//       401: { description: Invalid API Token }
-	//       default: { description: Unknown Error }
+	addr, err := basics.UnmarshalChecksumAddress(queryAddr)
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, err, errFailedToParseAddress, ctx.Log)
+		return
+	}
+
+	max, err := strconv.ParseUint(r.FormValue("max"), 10, 64)
+	if err != nil {
+		max = 0
+	}
+
+	queue, err := ctx.Node.GetPendingTxnsFromSender(addr)
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusInternalServerError, err, errFailedLookingUpTransactionPool, ctx.Log)
+		return
+	}
+
+	senderTxns := uint64(len(queue.Txns))
+	txs, positions := queue.Txns, queue.Positions
+	if max > 0 && senderTxns > max {
+		txs, positions = txs[:max], positions[:max]
+	}
+
+	responseTxs := make([]Transaction, len(txs))
+	responsePositions := make([]uint64, len(txs))
+	for i, twr := range txs {
+		responseTxs[i] = paymentTxEncode(twr.Txn, transactions.ApplyData{})
+		responsePositions[i] = uint64(positions[i])
+	}
+
+	response := SenderPendingTransactionsResponse{
+		Body: &SenderPendingTransactions{
+			TruncatedTxns: TransactionList{
+				Transactions: responseTxs,
+			},
+			Positions:   responsePositions,
+			SenderTxns:  senderTxns,
+			TotalTxns:   uint64(queue.NumOutstanding),
+			SenderLimit: uint64(queue.SenderLimit),
+		},
+	}
+
+	SendJSON(response, w, ctx.Log)
+}
+
+// ListPoolTransactions is an httpHandler for route GET /v1/transactions/pool
+func ListPoolTransactions(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v1/transactions/pool ListPoolTransactions
+	// ---
+	//     Summary: List the transactions pending in the transaction pool, for the node operator.
+	//     Description: >
+	//       Get the pending transactions, optionally only the ones of SENDER,
+	//       in the order they are considered for inclusion in a block, along
+	//       with their fees, their position in that order, and whether they
+	//       were submitted through this node. The list is truncated at the
+	//       end at MAX. If MAX = 0, returns all the matching transactions.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Parameters:
+	//       - name: sender
+	//         in: query
+	//         type: string
+	//         pattern: "[A-Z0-9]{58}"
+	//         required: false
+	//         description: Only list the transactions of this address
+	//       - name: max
+	//         in: query
+	//         type: integer
+	//         format: int64
+	//         minimum: 0
+	//         required: false
+	//         description: Truncated number of transactions to display. If max=0, returns all the matching txns.
+	//     Responses:
+	//       "200":
+	//         "$ref": '#/responses/PoolTransactionsResponse'
+	//       400:
+	//         description: Bad Request
+	//         schema: {type: string}
+	//       500:
+	//         description: Internal Error
+	//         schema: {type: string}
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+	max, err := strconv.ParseUint(r.FormValue("max"), 10, 64)
+	if err != nil {
+		max = 0
+	}
+
+	var txs []transactions.SignedTxn
+	var positions []int
+	var totalTxns int
+	if querySender := r.FormValue("sender"); querySender != "" {
+		sender, err := basics.UnmarshalChecksumAddress(querySender)
+		if err != nil {
+			lib.ErrorResponse(w, http.StatusBadRequest, err, errFailedToParseAddress, ctx.Log)
+			return
+		}
+		queue, err := ctx.Node.GetPendingTxnsFromSender(sender)
+		if err != nil {
+			lib.ErrorResponse(w, http.StatusInternalServerError, err, errFailedLookingUpTransactionPool, ctx.Log)
+			return
+		}
+		txs, positions, totalTxns = queue.Txns, queue.Positions, queue.NumOutstanding
+	} else {
+		txs, err = ctx.Node.GetPendingTxnsFromPool()
+		if err != nil {
+			lib.ErrorResponse(w, http.StatusInternalServerError, err, errFailedLookingUpTransactionPool, ctx.Log)
+			return
+		}
+		positions = make([]int, len(txs))
+		for i := range positions {
+			positions[i] = i
+		}
+		totalTxns = len(txs)
+	}
+
+	if max > 0 && uint64(len(txs)) > max {
+		txs, positions = txs[:max], positions[:max]
+	}
+
+	responseTxs := make([]PoolTransaction, len(txs))
+	for i, tx := range txs {
+		txid := tx.ID()
+		status, tracked := ctx.Node.RebroadcastStatus(txid)
+		responseTxs[i] = PoolTransaction{
+			TxID:       txid.String(),
+			Position:   uint64(positions[i]),
+			Sender:     tx.Txn.Sender.String(),
+			Type:       string(tx.Txn.Type),
+			Fee:        tx.Txn.Fee.Raw,
+			Size:       uint64(tx.GetEncodedLength()),
+			FeePerByte: priorityFeePerByte(tx.Priority()),
+			LastValid:  uint64(tx.Txn.LastValid),
+			Local:      tracked && status.State == node.RebroadcastPending,
+		}
+		if !tx.Txn.Group.IsZero() {
+			responseTxs[i].Group = base64.StdEncoding.EncodeToString(tx.Txn.Group[:])
+		}
+	}
+
+	response := PoolTransactionsResponse{
+		Body: &PoolTransactions{
+			Transactions: responseTxs,
+			TotalTxns:    uint64(totalTxns),
+		},
+	}
+
+	SendJSON(response, w, ctx.Log)
+}
+
+// DropPoolTransaction is an httpHandler for route DELETE /v1/transactions/pool/{txid:[A-Z0-9]+}
+func DropPoolTransaction(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /v1/transactions/pool/{txid} DropPoolTransaction
+	// ---
+	//     Summary: Evict a transaction from the transaction pool, for the node operator.
+	//     Description: >
+	//       Given the id of a transaction pending in the transaction pool, it
+	//       evicts the transaction along with the rest of its group, and stops
+	//       rebroadcasting them if they were submitted through this node. This
+	//       only affects this node: the transactions may still be confirmed if
+	//       other nodes hold them.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Parameters:
+	//       - name: txid
+	//         in: path
+	//         type: string
+	//         pattern: "[A-Z0-9]+"
+	//         required: true
+	//         description: A transaction id
+	//     Responses:
+	//       200:
+	//         "$ref": '#/responses/DroppedTransactionsResponse'
+	//       400:
+	//         description: Bad Request
+	//         schema: {type: string}
+	//       404:
+	//         description: Transaction Not Pending
+	//         schema: {type: string}
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+	queryTxID := mux.Vars(r)["txid"]
+	txID := transactions.Txid{}
+	if queryTxID == "" || txID.UnmarshalText([]byte(queryTxID)) != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, errors.New(errNoTxnSpecified), errNoTxnSpecified, ctx.Log)
+		return
+	}
+
+	dropped, err := ctx.Node.DropPendingTransaction(txID)
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusNotFound, err, errTransactionNotPending, ctx.Log)
+		return
+	}
+
+	txids := make([]string, len(dropped))
+	for i, id := range dropped {
+		txids[i] = id.String()
+	}
+	SendJSON(DroppedTransactionsResponse{&DroppedTransactions{TxIDs: txids}}, w, ctx.Log)
+}
+
+// SuggestedFee is an httpHandler for route GET /v1/transactions/fee
+func SuggestedFee(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v1/transactions/fee SuggestedFee
+	// ---
+	//     Summary: Get the suggested fee
+	//     Description: >
+	//       Suggested Fee is returned in units of micro-Algos per byte.
+	//       Suggested Fee may fall to zero but submitted transactions
+	//       must still have a fee of at least MinTxnFee for the current
+	//       network protocol.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Responses:
+	//       "200":
+	//         "$ref": '#/responses/TransactionFeeResponse'
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+	fee := TransactionFee{Fee: ctx.Node.SuggestedFee().Raw}
+	SendJSON(TransactionFeeResponse{&fee}, w, ctx.Log)
+}
+
+// FeeInfo is an httpHandler for route GET /v1/transactions/feeinfo
+func FeeInfo(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v1/transactions/feeinfo FeeInfo
+	// ---
+	//     Summary: Get the suggested fee and the congestion of the transaction pool
+	//     Description: >
+	//       Returns the suggested fee along with how full the transaction
+	//       pool is, the fee it requires from new transactions, and the fee
+	//       a new transaction likely needs to be included in the next block.
+	//       Fees are returned in units of micro-Algos per byte, and submitted
+	//       transactions must still have a fee of at least MinTxnFee for the
+	//       current network protocol.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Responses:
+	//       "200":
+	//         "$ref": '#/responses/TransactionFeeInfoResponse'
+	//       401: { description: Invalid API Token }
+	//       500:
+	//         description: Internal Error
+	//         schema: {type: string}
+	//       default: { description: Unknown Error }
+	info, err := ctx.Node.FeeInfo()
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusInternalServerError, err, errFailedLookingUpTransactionPool, ctx.Log)
+		return
+	}
+
+	feeInfo := TransactionFeeInfo{
+		Fee:        info.SuggestedFee.Raw,
+		MinFee:     info.MinTxnFee.Raw,
+		PoolTxns:   uint64(info.Count),
+		PoolSize:   uint64(info.Size),
+		PoolBytes:  uint64(info.Bytes),
+		PoolMinFee: priorityFeePerByte(info.MinPriority),
+	}
+	if info.NextBlockPriority != 0 {
+		// the next block takes the transactions of a higher priority only
+		feeInfo.NextBlockFee = priorityFeePerByte(info.NextBlockPriority + 1)
+	}
+	SendJSON(TransactionFeeInfoResponse{&feeInfo}, w, ctx.Log)
+}
+
+// priorityFeePerByte returns the fee per byte, rounded up, a transaction needs to reach a pool priority
+func priorityFeePerByte(priority transactions.TxnPriority) uint64 {
+	return uint64(math.Ceil(priority.FeePerByte()))
+}
+
+// SuggestedParams is an httpHandler for route GET /v1/transactions/params
+func SuggestedParams(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v1/transactions/params TransactionParams
+	// ---
+	//     Summary: Get parameters for constructing a new transaction
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Responses:
+	//       "200":
+	//         "$ref": '#/responses/TransactionParamsResponse'
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
 	stat, err := ctx.Node.Status()
 	if err != nil {
 		lib.ErrorResponse(w, http.StatusInternalServerError, err, errFailedRetrievingNodeStatus, ctx.Log)
@@ -655,6 +1352,55 @@ func GetBlock(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
 	SendJSON(BlockResponse{&block}, w, ctx.Log)
 }
 
+// GetRawBlock is an httpHandler for route GET /v1/block/{round}/raw
+func GetRawBlock(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v1/block/{round}/raw GetRawBlock
+	// ---
+	//     Summary: Get the msgpack encoded block and certificate for the given round.
+	//     Description: >
+	//       Get the block of the given round along with its certificate, encoded the same way
+	//       as the blocks that are served to catching up nodes.
+	//     Produces:
+	//     - application/x-algorand-block-v1
+	//     Schemes:
+	//     - http
+	//     Parameters:
+	//       - name: round
+	//         in: path
+	//         type: integer
+	//         format: int64
+	//         minimum: 0
+	//         required: true
+	//         description: The round from which to fetch the block.
+	//     Responses:
+	//       200:
+	//         description: The encoded block and certificate
+	//         schema: {type: string, format: binary}
+	//       400:
+	//         description: Bad Request
+	//         schema: {type: string}
+	//       500:
+	//         description: Internal Error
+	//         schema: {type: string}
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+	queryRound, err := strconv.ParseUint(mux.Vars(r)["round"], 10, 64)
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, err, errFailedParsingRoundNumber, ctx.Log)
+		return
+	}
+
+	b, c, err := ctx.Node.GetBlock(basics.Round(queryRound))
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusInternalServerError, err, errFailedLookingUpLedger, ctx.Log)
+		return
+	}
+
+	w.Header().Set("Content-Type", rawBlockContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(protocol.Encode(rpcs.EncodedBlockCert{Block: b, Certificate: c}))
+}
+
 // GetSupply is an httpHandler for route GET /v1/ledger/supply
 func GetSupply(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /v1/ledger/supply GetSupply
@@ -678,6 +1424,158 @@ func GetSupply(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
 	SendJSON(SupplyResponse{&supply}, w, ctx.Log)
 }
 
+// BalancesAtRound is an httpHandler for route GET /v2/balances
+func BalancesAtRound(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v2/balances BalancesAtRound
+	// ---
+	//     Summary: Get the balances of accounts as of a round.
+	//     Description: Returns the balances of the given accounts, including their rewards, as they were at the end of the given round. The ledger keeps the balances of the most recent rounds only, at least MaxBalLookback of them.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Parameters:
+	//       - name: round
+	//         in: query
+	//         type: integer
+	//         format: int64
+	//         required: true
+	//         description: The round to look the balances up at.
+	//       - name: address
+	//         in: query
+	//         type: array
+	//         items:
+	//           type: string
+	//         collectionFormat: multi
+	//         required: true
+	//         description: The addresses of the accounts to look up.
+	//     Responses:
+	//       200:
+	//         "$ref": '#/responses/BalancesResponse'
+	//       400:
+	//         description: Bad Request
+	//         schema: {type: string}
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+
+	round, err := strconv.ParseUint(r.FormValue("round"), 10, 64)
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, err, errFailedParsingRoundNumber, ctx.Log)
+		return
+	}
+	if basics.Round(round) > ctx.Node.LatestRound() {
+		lib.ErrorResponse(w, http.StatusBadRequest, errors.New(errRoundInTheFuture), errRoundInTheFuture, ctx.Log)
+		return
+	}
+
+	queryAddrs := r.Form["address"]
+	if len(queryAddrs) == 0 {
+		lib.ErrorResponse(w, http.StatusBadRequest, errors.New(errNoAccountSpecified), errNoAccountSpecified, ctx.Log)
+		return
+	}
+
+	balances := Balances{Round: round, Accounts: make([]Balance, 0, len(queryAddrs))}
+	for _, queryAddr := range queryAddrs {
+		addr, err := basics.UnmarshalChecksumAddress(queryAddr)
+		if err != nil {
+			lib.ErrorResponse(w, http.StatusBadRequest, err, errFailedToParseAddress, ctx.Log)
+			return
+		}
+
+		data, err := ctx.Node.GetAccountDataAtRound(addr, basics.Round(round))
+		if err != nil {
+			lib.ErrorResponse(w, http.StatusBadRequest, err, errBalancesNotAvailable, ctx.Log)
+			return
+		}
+
+		balance := Balance{Address: queryAddr, Amount: data.MicroAlgos.Raw}
+		if data.AuthAddr != (basics.Address{}) {
+			balance.AuthAddr = data.AuthAddr.GetChecksumAddress().String()
+		}
+		balances.Accounts = append(balances.Accounts, balance)
+	}
+
+	SendJSON(BalancesResponse{&balances}, w, ctx.Log)
+}
+
+func logLevels() LogLevels {
+	levels := LogLevels{Levels: make(map[string]string), Overrides: logging.SubsystemLevelOverrides()}
+	for subsystem, level := range logging.SubsystemLevels() {
+		levels.Levels[subsystem] = level.String()
+	}
+	return levels
+}
+
+// GetLogLevels is an httpHandler for route GET /v1/logging/levels
+func GetLogLevels(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v1/logging/levels GetLogLevels
+	//---
+	//     Summary: Get the log level of each of the node subsystems.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Responses:
+	//       200:
+	//         "$ref": '#/responses/LogLevelsResponse'
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+	levels := logLevels()
+	SendJSON(LogLevelsResponse{&levels}, w, ctx.Log)
+}
+
+// SetLogLevel is an httpHandler for route POST /v1/logging/levels/{subsystem:[a-z]+}
+func SetLogLevel(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /v1/logging/levels/{subsystem} SetLogLevel
+	//---
+	//     Summary: Set the log level of a node subsystem, until the node restarts.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Parameters:
+	//       - name: subsystem
+	//         in: path
+	//         type: string
+	//         enum: [agreement, network, ledger, txpool, rest]
+	//         required: true
+	//         description: The subsystem to set the log level of
+	//       - name: level
+	//         in: query
+	//         type: string
+	//         enum: [panic, fatal, error, warn, info, debug, default]
+	//         required: true
+	//         description: The level to log at; default makes the subsystem log at the node log level again
+	//     Responses:
+	//       200:
+	//         "$ref": '#/responses/LogLevelsResponse'
+	//       400:
+	//         description: Bad Request
+	//         schema: {type: string}
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+	subsystem := mux.Vars(r)["subsystem"]
+	levelName := r.URL.Query().Get("level")
+	var err error
+	if levelName == "default" {
+		err = logging.ClearSubsystemLevel(subsystem)
+	} else {
+		var level logging.Level
+		level, err = logging.ParseLevel(levelName)
+		if err == nil {
+			err = logging.SetSubsystemLevel(subsystem, level)
+		}
+	}
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, err, err.Error(), ctx.Log)
+		return
+	}
+	ctx.Log.Infof("Log level of subsystem %s set to %s", subsystem, levelName)
+
+	levels := logLevels()
+	SendJSON(LogLevelsResponse{&levels}, w, ctx.Log)
+}
+
 func parseTime(t string) (res time.Time, err error) {
 	// check for just date
 	res, err = time.Parse("2006-01-02", t)
@@ -942,3 +1840,125 @@ func GetTransactionByID(ctx lib.ReqContext, w http.ResponseWriter, r *http.Reque
 	lib.ErrorResponse(w, http.StatusNotFound, errors.New(errTransactionNotFound), errTransactionNotFound, ctx.Log)
 	return
 }
+
+// TransactionsByNotePrefix is an httpHandler for route GET /v2/transactions
+func TransactionsByNotePrefix(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /v2/transactions TransactionsByNotePrefix
+	// ---
+	//     Summary: Search confirmed transactions by note.
+	//     Description: Returns the most recent confirmed transactions whose note starts with the given prefix. This call is available only when the indexer is running with IndexerNotePrefixLength set, and finds the transactions indexed since. The prefix can be at most IndexerNotePrefixLength bytes long.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Parameters:
+	//       - name: note-prefix
+	//         in: query
+	//         type: string
+	//         format: byte
+	//         required: true
+	//         description: The base64 encoded prefix of the notes to look for.
+	//       - name: max
+	//         in: query
+	//         type: integer
+	//         format: int64
+	//         required: false
+	//         description: maximum transactions to show (default to 100)
+	//     Responses:
+	//       200:
+	//         "$ref": '#/responses/TransactionsResponse'
+	//       400:
+	//         description: Bad Request
+	//         schema: {type: string}
+	//       500:
+	//         description: Internal Error
+	//         schema: {type: string}
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+
+	idx, err := ctx.Node.Indexer()
+	if err != nil {
+		lib.ErrorResponse(w, http.StatusBadRequest, err, errIndexerNotRunning, ctx.Log)
+		return
+	}
+
+	queryPrefix := r.FormValue("note-prefix")
+	if queryPrefix == "" {
+		lib.ErrorResponse(w, http.StatusBadRequest, errors.New(errNoNotePrefixSpecified), errNoNotePrefixSpecified, ctx.Log)
+		return
+	}
+	prefix, err := base64.StdEncoding.DecodeString(queryPrefix)
+	if err != nil {
+		// a '+' left unescaped in the query string arrives as a space
+		prefix, err = base64.URLEncoding.DecodeString(queryPrefix)
+		if err != nil {
+			lib.ErrorResponse(w, http.StatusBadRequest, err, errFailedParsingNotePrefix, ctx.Log)
+			return
+		}
+	}
+
+	max, err := strconv.ParseUint(r.FormValue("max"), 10, 64)
+	if err != nil {
+		max = 100
+	}
+
+	found, err := idx.GetTransactionsByNotePrefix(prefix, max)
+	if err != nil {
+		switch err.(type) {
+		case indexer.ErrNotePrefixTooLong:
+			lib.ErrorResponse(w, http.StatusBadRequest, err, err.Error(), ctx.Log)
+			return
+		}
+		if err == indexer.ErrNotesNotIndexed {
+			lib.ErrorResponse(w, http.StatusBadRequest, err, err.Error(), ctx.Log)
+			return
+		}
+		lib.ErrorResponse(w, http.StatusInternalServerError, err, errFailedGettingInformationFromIndexer, ctx.Log)
+		return
+	}
+
+	responseTxs := make([]Transaction, 0, len(found))
+	for _, f := range found {
+		var txID transactions.Txid
+		if err := txID.UnmarshalText([]byte(f.TXID)); err != nil {
+			lib.ErrorResponse(w, http.StatusInternalServerError, err, errFailedGettingInformationFromIndexer, ctx.Log)
+			return
+		}
+		txn, err := ctx.Node.GetTransactionByID(txID, basics.Round(f.Round))
+		if err != nil {
+			lib.ErrorResponse(w, http.StatusInternalServerError, err, errFailedLookingUpLedger, ctx.Log)
+			return
+		}
+		responseTxs = append(responseTxs, txWithStatusEncode(txn))
+	}
+
+	response := TransactionsResponse{
+		&TransactionList{
+			Transactions: responseTxs,
+		},
+	}
+
+	SendJSON(response, w, ctx.Log)
+}
+
+// Shutdown is an httpHandler for route POST /v1/shutdown
+func Shutdown(ctx lib.ReqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /v1/shutdown Shutdown
+	//---
+	//     Summary: Shuts the node down gracefully.
+	//     Description: The node stops accepting new transactions, completes the REST API calls and the round in progress, and writes its state to disk before exiting. The call returns once the shutdown started.
+	//     Produces:
+	//     - application/json
+	//     Schemes:
+	//     - http
+	//     Responses:
+	//       200:
+	//         description: OK.
+	//       401: { description: Invalid API Token }
+	//       default: { description: Unknown Error }
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(nil)
+
+	// the shutdown waits for the API calls in progress, including this one, to complete
+	go ctx.Shutdown()
+}
@@ -15,3 +15,124 @@
 // along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
 
 package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/daemon/algod/api/server/lib"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/node"
+)
+
+// historyNode holds the account state of the rounds from oldestRound to lastRound, where the balance of every account
+// is 1000 times the round; the rest of node.Full isn't used by these tests
+type historyNode struct {
+	node.Full
+	oldestRound basics.Round
+	lastRound   basics.Round
+}
+
+func (n *historyNode) LatestRound() basics.Round {
+	return n.lastRound
+}
+
+func (n *historyNode) lookup(round basics.Round) error {
+	if round < n.oldestRound || round > n.lastRound {
+		return fmt.Errorf("round %d not in the ledger", round)
+	}
+	return nil
+}
+
+func (n *historyNode) GetBalanceAndStatusAtRound(address basics.Address, round basics.Round) (money basics.MicroAlgos, rewards basics.MicroAlgos, moneyWithoutPendingRewards basics.MicroAlgos, status basics.Status, err error) {
+	if err = n.lookup(round); err != nil {
+		return
+	}
+	money = basics.MicroAlgos{Raw: 1000 * uint64(round)}
+	return money, basics.MicroAlgos{}, money, basics.Online, nil
+}
+
+func (n *historyNode) GetAccountDataAtRound(address basics.Address, round basics.Round) (basics.AccountData, error) {
+	if err := n.lookup(round); err != nil {
+		return basics.AccountData{}, err
+	}
+	return basics.AccountData{Status: basics.Online, MicroAlgos: basics.MicroAlgos{Raw: 1000 * uint64(round)}}, nil
+}
+
+func TestAccountInformationAtRound(t *testing.T) {
+	ctx := lib.ReqContext{Node: &historyNode{oldestRound: 50, lastRound: 100}, Log: logging.TestingLog(t)}
+	router := mux.NewRouter()
+	router.HandleFunc("/v2/accounts/{addr}", func(w http.ResponseWriter, r *http.Request) {
+		AccountInformationAtRound(ctx, w, r)
+	})
+
+	var addr basics.Address
+	crypto.RandBytes(addr[:])
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+	accountAt := func(query string) Account {
+		w := get("/v2/accounts/" + addr.GetUserAddress() + query)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var account Account
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &account))
+		require.Equal(t, addr.GetUserAddress(), account.Address)
+		require.Equal(t, basics.Online.String(), account.Status)
+		return account
+	}
+
+	t.Run("current round", func(t *testing.T) {
+		account := accountAt("")
+		require.Equal(t, uint64(100), account.Round)
+		require.Equal(t, uint64(100000), account.Amount)
+
+		account = accountAt("?round=100")
+		require.Equal(t, uint64(100), account.Round)
+		require.Equal(t, uint64(100000), account.Amount)
+	})
+
+	t.Run("past round", func(t *testing.T) {
+		account := accountAt("?round=60")
+		require.Equal(t, uint64(60), account.Round)
+		require.Equal(t, uint64(60000), account.Amount)
+		require.Equal(t, uint64(60000), account.AmountWithoutPendingRewards)
+	})
+
+	t.Run("unavailable rounds", func(t *testing.T) {
+		for query, expected := range map[string]string{
+			"?round=101":       errRoundInTheFuture,
+			"?round=49":        errBalancesNotAvailable,
+			"?round=0":         errBalancesNotAvailable,
+			"?round=not-round": errFailedParsingRoundNumber,
+		} {
+			w := get("/v2/accounts/" + addr.GetUserAddress() + query)
+			require.Equal(t, http.StatusBadRequest, w.Code, query)
+			require.Equal(t, expected, w.Body.String(), query)
+		}
+	})
+
+	t.Run("bad address", func(t *testing.T) {
+		// an address with a wrong checksum
+		checksummed := addr.GetUserAddress()
+		first := "A"
+		if checksummed[0] == 'A' {
+			first = "B"
+		}
+		corrupted := first + checksummed[1:]
+		for _, bad := range []string{"NOTANADDRESS", addr.String()[:20], corrupted} {
+			w := get("/v2/accounts/" + bad)
+			require.Equal(t, http.StatusBadRequest, w.Code, bad)
+			require.Equal(t, errFailedToParseAddress, w.Body.String(), bad)
+		}
+	})
+}
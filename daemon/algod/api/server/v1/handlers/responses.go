@@ -89,6 +89,30 @@ func (r TransactionIDResponse) getBody() interface{} {
 	return r.Body
 }
 
+// TransactionBatchResponse contains the outcome of every transaction of a batch
+//
+// swagger:response TransactionBatchResponse
+type TransactionBatchResponse struct {
+	// in: body
+	Body *TransactionBatchResults
+}
+
+func (r TransactionBatchResponse) getBody() interface{} {
+	return r.Body
+}
+
+// NodeBehindResponse contains the sync status of a node that refuses transactions because it lags the network
+//
+// swagger:response NodeBehindResponse
+type NodeBehindResponse struct {
+	// in: body
+	Body *NodeBehind
+}
+
+func (r NodeBehindResponse) getBody() interface{} {
+	return r.Body
+}
+
 // AccountInformationResponse contains an account information
 //
 // swagger:response AccountInformationResponse
@@ -137,6 +161,18 @@ func (r TransactionFeeResponse) getBody() interface{} {
 	return r.Body
 }
 
+// TransactionFeeInfoResponse contains the suggested fee and the congestion of the transaction pool
+//
+// swagger:response TransactionFeeInfoResponse
+type TransactionFeeInfoResponse struct {
+	// in: body
+	Body *TransactionFeeInfo
+}
+
+func (r TransactionFeeInfoResponse) getBody() interface{} {
+	return r.Body
+}
+
 // TransactionParamsResponse contains the parameters for
 // constructing a new transaction.
 //
@@ -174,6 +210,30 @@ func (r SupplyResponse) getBody() interface{} {
 	return r.Body
 }
 
+// BalancesResponse contains the balances of a set of accounts as of a round
+//
+// swagger:response BalancesResponse
+type BalancesResponse struct {
+	// in: body
+	Body *Balances
+}
+
+func (r BalancesResponse) getBody() interface{} {
+	return r.Body
+}
+
+// LogLevelsResponse contains the log levels of the node subsystems
+//
+// swagger:response LogLevelsResponse
+type LogLevelsResponse struct {
+	// in: body
+	Body *LogLevels
+}
+
+func (r LogLevelsResponse) getBody() interface{} {
+	return r.Body
+}
+
 /* Errors */
 
 // PendingTransactionsResponse contains a (potentially truncated) list of transactions and
@@ -188,3 +248,52 @@ type PendingTransactionsResponse struct {
 func (r PendingTransactionsResponse) getBody() interface{} {
 	return r.Body
 }
+
+// RebroadcastStatusResponse contains the rebroadcast status of a submitted transaction
+//
+// swagger:response RebroadcastStatusResponse
+type RebroadcastStatusResponse struct {
+	// in: body
+	Body *RebroadcastStatus
+}
+
+func (r RebroadcastStatusResponse) getBody() interface{} {
+	return r.Body
+}
+
+// SenderPendingTransactionsResponse contains a (potentially truncated) list of the transactions of a sender
+// currently in the pool.
+//
+// swagger:response SenderPendingTransactionsResponse
+type SenderPendingTransactionsResponse struct {
+	// in: body
+	Body *SenderPendingTransactions
+}
+
+func (r SenderPendingTransactionsResponse) getBody() interface{} {
+	return r.Body
+}
+
+// PoolTransactionsResponse contains the transactions pending in the transaction pool, with their fees and order
+//
+// swagger:response PoolTransactionsResponse
+type PoolTransactionsResponse struct {
+	// in: body
+	Body *PoolTransactions
+}
+
+func (r PoolTransactionsResponse) getBody() interface{} {
+	return r.Body
+}
+
+// DroppedTransactionsResponse contains the IDs of the transactions evicted from the transaction pool
+//
+// swagger:response DroppedTransactionsResponse
+type DroppedTransactionsResponse struct {
+	// in: body
+	Body *DroppedTransactions
+}
+
+func (r DroppedTransactionsResponse) getBody() interface{} {
+	return r.Body
+}
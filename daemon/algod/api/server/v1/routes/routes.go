@@ -70,6 +70,13 @@ var Routes = lib.Routes{
 		HandlerFunc: handlers.SuggestedFee,
 	},
 
+	lib.Route{
+		Name:        "fee-info",
+		Method:      "GET",
+		Path:        "/transactions/feeinfo",
+		HandlerFunc: handlers.FeeInfo,
+	},
+
 	lib.Route{
 		Name:        "suggested-params",
 		Method:      "GET",
@@ -91,6 +98,13 @@ var Routes = lib.Routes{
 		HandlerFunc: handlers.GetBlock,
 	},
 
+	lib.Route{
+		Name:        "raw-block",
+		Method:      "GET",
+		Path:        "/block/{round:[0-9]+}/raw",
+		HandlerFunc: handlers.GetRawBlock,
+	},
+
 	lib.Route{
 		Name:        "ledger-supply",
 		Method:      "GET",
@@ -105,6 +119,13 @@ var Routes = lib.Routes{
 		HandlerFunc: handlers.GetPendingTransactions,
 	},
 
+	lib.Route{
+		Name:        "list-sender-pending-transactions",
+		Method:      "GET",
+		Path:        fmt.Sprintf("/account/{addr:[A-Z0-9]{%d}}/transactions/pending", KeyLength),
+		HandlerFunc: handlers.GetSenderPendingTransactions,
+	},
+
 	lib.Route{
 		Name:        "pending-transaction-information",
 		Method:      "GET",
@@ -112,6 +133,48 @@ var Routes = lib.Routes{
 		HandlerFunc: handlers.PendingTransactionInformation,
 	},
 
+	lib.Route{
+		Name:        "pool-transactions",
+		Method:      "GET",
+		Path:        "/transactions/pool",
+		HandlerFunc: handlers.ListPoolTransactions,
+	},
+
+	lib.Route{
+		Name:        "drop-pool-transaction",
+		Method:      "DELETE",
+		Path:        "/transactions/pool/{txid:[A-Z0-9]+}",
+		HandlerFunc: handlers.DropPoolTransaction,
+	},
+
+	lib.Route{
+		Name:        "transaction-rebroadcast-status",
+		Method:      "GET",
+		Path:        "/transactions/rebroadcast/{txid:[A-Z0-9]+}",
+		HandlerFunc: handlers.TransactionRebroadcastStatus,
+	},
+
+	lib.Route{
+		Name:        "get-log-levels",
+		Method:      "GET",
+		Path:        "/logging/levels",
+		HandlerFunc: handlers.GetLogLevels,
+	},
+
+	lib.Route{
+		Name:        "set-log-level",
+		Method:      "POST",
+		Path:        "/logging/levels/{subsystem:[a-z]+}",
+		HandlerFunc: handlers.SetLogLevel,
+	},
+
+	lib.Route{
+		Name:        "shutdown",
+		Method:      "POST",
+		Path:        "/shutdown",
+		HandlerFunc: handlers.Shutdown,
+	},
+
 	// ----- This can only be active when indexer is live
 
 	lib.Route{
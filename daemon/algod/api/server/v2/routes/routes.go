@@ -0,0 +1,56 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package routes
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/daemon/algod/api/server/lib"
+	"github.com/algorand/go-algorand/daemon/algod/api/server/v1/handlers"
+	v1routes "github.com/algorand/go-algorand/daemon/algod/api/server/v1/routes"
+)
+
+// Routes contains all routes for v2. The v2 handlers share their models with v1.
+var Routes = lib.Routes{
+	lib.Route{
+		Name:        "transactions-by-note-prefix",
+		Method:      "GET",
+		Path:        "/transactions",
+		HandlerFunc: handlers.TransactionsByNotePrefix,
+	},
+
+	lib.Route{
+		Name:        "balances-at-round",
+		Method:      "GET",
+		Path:        "/balances",
+		HandlerFunc: handlers.BalancesAtRound,
+	},
+
+	lib.Route{
+		Name:        "account-information-at-round",
+		Method:      "GET",
+		Path:        fmt.Sprintf("/accounts/{addr:[A-Z0-9]{%d}}", v1routes.KeyLength),
+		HandlerFunc: handlers.AccountInformationAtRound,
+	},
+
+	lib.Route{
+		Name:        "raw-transaction-batch",
+		Method:      "POST",
+		Path:        "/transactions/batch",
+		HandlerFunc: handlers.RawTransactionBatch,
+	},
+}
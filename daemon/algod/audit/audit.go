@@ -0,0 +1,143 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package audit records the administrative operations performed on a node, such as the mutating REST API calls,
+// into a dedicated append-only audit log file.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/algorand/go-algorand/logging"
+)
+
+const (
+	// LogFilename is the name of the audit log file, in the node data directory.
+	LogFilename = "node.audit.log"
+	// ArchiveFilename is the name of the most recently rotated audit log file, in the node data directory.
+	ArchiveFilename = "node.audit.archive.log"
+
+	// DefaultSizeLimit is the size at which the audit log is rotated, unless configured otherwise.
+	DefaultSizeLimit = 64 * 1024 * 1024
+	// DefaultArchiveCount is the number of rotated audit log files kept, unless configured otherwise.
+	DefaultArchiveCount = 5
+)
+
+// Entry is a single audit log record.
+type Entry struct {
+	Time time.Time `json:"time"`
+	// Action describes the operation, such as "POST /v1/transactions" or "shutdown".
+	Action string `json:"action"`
+	// Token identifies the API token used for the operation, without disclosing it.
+	Token string `json:"token,omitempty"`
+	// Source is the remote address the operation originated from.
+	Source string `json:"source,omitempty"`
+	// Params holds the path and query parameters of the operation.
+	Params map[string]string `json:"params,omitempty"`
+	// BodySize and BodyDigest describe the request body, which isn't recorded as is.
+	BodySize   int64  `json:"bodySize,omitempty"`
+	BodyDigest string `json:"bodyDigest,omitempty"`
+	// Status is the http status the operation completed with.
+	Status int `json:"status,omitempty"`
+}
+
+// Log is an append-only audit log, rotated by size.
+type Log struct {
+	writer *logging.CyclicFileWriter
+}
+
+// MakeLog opens the audit log of the given data directory. A zero sizeLimit or archiveCount uses the defaults.
+func MakeLog(dataDir string, sizeLimit uint64, archiveCount int) *Log {
+	if sizeLimit == 0 {
+		sizeLimit = DefaultSizeLimit
+	}
+	if archiveCount == 0 {
+		archiveCount = DefaultArchiveCount
+	}
+	policy := logging.LogRotationPolicy{MaxSize: sizeLimit, MaxFiles: archiveCount}
+	return &Log{
+		writer: logging.MakeCyclicFileWriterWithPolicy(filepath.Join(dataDir, LogFilename), filepath.Join(dataDir, ArchiveFilename), policy),
+	}
+}
+
+// Record appends the entry to the audit log, setting its time if it isn't set.
+func (l *Log) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = l.writer.Write(append(line, '\n'))
+	return err
+}
+
+// TokenIdentity returns a stable identifier of the given API token, which doesn't disclose the token itself.
+func TokenIdentity(token string) string {
+	if token == "" {
+		return ""
+	}
+	digest := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(digest[:8])
+}
+
+// ReadLog returns the entries of the audit log of the given data directory, oldest first, including the
+// entries of the rotated files.
+func ReadLog(dataDir string) (entries []Entry, err error) {
+	filenames := []string{LogFilename, ArchiveFilename}
+	for i := 1; ; i++ {
+		filename := fmt.Sprintf("node.audit.archive.%d.log", i)
+		if _, err := os.Stat(filepath.Join(dataDir, filename)); err != nil {
+			break
+		}
+		filenames = append(filenames, filename)
+	}
+	for i := len(filenames) - 1; i >= 0; i-- {
+		fileEntries, err := readFile(filepath.Join(dataDir, filenames[i]))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+func readFile(path string) (entries []Entry, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			// skip lines that were only partially written
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package audit
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	entries, err := ReadLog(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	log := MakeLog(dir, 0, 0)
+	require.NoError(t, log.Record(Entry{Action: "startup"}))
+	require.NoError(t, log.Record(Entry{Action: "POST /v1/transactions", Token: TokenIdentity("secret"), Source: "127.0.0.1:1234", Status: 200}))
+
+	entries, err = ReadLog(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "startup", entries[0].Action)
+	require.False(t, entries[0].Time.IsZero())
+	require.Equal(t, "POST /v1/transactions", entries[1].Action)
+	require.Equal(t, 200, entries[1].Status)
+	require.NotContains(t, entries[1].Token, "secret")
+	require.Equal(t, TokenIdentity("secret"), entries[1].Token)
+}
+
+func TestRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	log := MakeLog(dir, 200, 1)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, log.Record(Entry{Action: "shutdown"}))
+	}
+	entries, err := ReadLog(dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	require.True(t, len(entries) < 10)
+
+	_, err = os.Stat(dir + "/" + ArchiveFilename)
+	require.NoError(t, err)
+}
@@ -0,0 +1,227 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package embedded runs an Algorand node within a Go program rather than as an algod process, so that
+// integration tests and specialized services can start, stop and query the node directly instead of
+// shelling out to the binaries. The node serves its REST API as algod would.
+package embedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/algorand/go-deadlock"
+	"github.com/gofrs/flock"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/daemon/algod"
+	"github.com/algorand/go-algorand/data"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/pools"
+	"github.com/algorand/go-algorand/node"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/tokens"
+)
+
+// ErrNodeStopped is returned by Start and WaitForRound once the node stopped
+var ErrNodeStopped = errors.New("the node stopped")
+
+// Options customize how Open loads a node
+type Options struct {
+	// Config is the config of the node; nil loads config.json from the data directory, as algod does
+	Config *config.Local
+	// GenesisFile is the genesis of the node; empty uses genesis.json in the data directory
+	GenesisFile string
+	// PhonebookDir is the directory of the phonebook.json file; empty uses the data directory
+	PhonebookDir string
+	// Peers replace the phonebook and the DNS bootstrap, as the algod -p flag does
+	Peers []string
+}
+
+// Node is an Algorand node embedded in the program. Its methods are safe for concurrent use.
+type Node struct {
+	server   algod.Server
+	lock     *flock.Flock
+	apiToken string
+	peers    []string
+
+	mu      deadlock.Mutex
+	started bool
+	stopped chan struct{}
+}
+
+// Open loads the node of dataDir without starting it. Like algod, it generates the REST API token if the data
+// directory has none, and holds the data directory lock, so that no algod runs on it until Stop.
+// The node logs to node.log in dataDir, through the base logger of the process.
+func Open(dataDir string, opts Options) (*Node, error) {
+	dataDir, err := filepath.Abs(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	config.UpdateVersionDataDir(dataDir)
+
+	genesisFile := opts.GenesisFile
+	if genesisFile == "" {
+		genesisFile = filepath.Join(dataDir, config.GenesisJSONFile)
+	}
+	genesisText, err := ioutil.ReadFile(genesisFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read genesis file %s: %v", genesisFile, err)
+	}
+	var genesis bookkeeping.Genesis
+	err = protocol.DecodeJSON(genesisText, &genesis)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse genesis file %s: %v", genesisFile, err)
+	}
+
+	var cfg config.Local
+	if opts.Config != nil {
+		cfg = *opts.Config
+	} else {
+		cfg, err = config.LoadConfigFromDisk(dataDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("cannot load config: %v", err)
+		}
+	}
+	if len(opts.Peers) > 0 {
+		// see the -p flag of algod
+		cfg.DNSBootstrapID = ""
+		if cfg.GossipFanout > len(opts.Peers) {
+			cfg.GossipFanout = len(opts.Peers)
+		}
+	}
+
+	phonebookDir := opts.PhonebookDir
+	if phonebookDir == "" {
+		phonebookDir = dataDir
+	}
+
+	lock := flock.New(filepath.Join(dataDir, "algod.lock"))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("cannot lock the data directory: %v", err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("cannot lock the data directory %s: a node already runs on it", dataDir)
+	}
+
+	apiToken, _, err := tokens.ValidateOrGenerateAPIToken(dataDir, tokens.AlgodTokenFilename)
+	if err != nil {
+		lock.Unlock()
+		return nil, fmt.Errorf("API token error: %v", err)
+	}
+
+	n := &Node{
+		server: algod.Server{
+			RootPath:     dataDir,
+			Genesis:      genesis,
+			DiskConfig:   cfg,
+			PhonebookDir: phonebookDir,
+		},
+		lock:     lock,
+		apiToken: apiToken,
+		peers:    opts.Peers,
+		stopped:  make(chan struct{}),
+	}
+	err = n.server.Initialize(cfg)
+	if err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	return n, nil
+}
+
+// Start starts the node and its REST API, and returns once they run
+func (n *Node) Start() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.isStopped() {
+		return ErrNodeStopped
+	}
+	if n.started {
+		return nil
+	}
+	if len(n.peers) > 0 {
+		n.server.OverridePhonebook(n.peers...)
+	}
+	n.started = true
+	return n.server.StartServices()
+}
+
+// Stop shuts the node down gracefully, as algod does on SIGTERM, and releases the data directory
+func (n *Node) Stop() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.isStopped() {
+		return
+	}
+	if n.started {
+		n.server.Stop()
+	}
+	n.lock.Unlock()
+	close(n.stopped)
+}
+
+func (n *Node) isStopped() bool {
+	select {
+	case <-n.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// Full returns the node itself, for the queries and operations the REST API offers
+func (n *Node) Full() *node.AlgorandFullNode {
+	return n.server.Node()
+}
+
+// Ledger returns the ledger of the node
+func (n *Node) Ledger() *data.Ledger {
+	return n.server.Node().Ledger()
+}
+
+// TransactionPool returns the transaction pool of the node
+func (n *Node) TransactionPool() *pools.TransactionPool {
+	return n.server.Node().TransactionPool()
+}
+
+// APIAddress returns the address the REST API listens on, once the node started
+func (n *Node) APIAddress() string {
+	return n.server.APIAddress()
+}
+
+// APIToken returns the token of the REST API
+func (n *Node) APIToken() string {
+	return n.apiToken
+}
+
+// WaitForRound waits until the ledger of the node has round, the context is done, or the node stops
+func (n *Node) WaitForRound(ctx context.Context, round basics.Round) error {
+	select {
+	case <-n.Ledger().Wait(round):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-n.stopped:
+		return ErrNodeStopped
+	}
+}
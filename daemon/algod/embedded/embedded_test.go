@@ -0,0 +1,110 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package embedded
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/gen"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// makeDataDir makes the data directory of a node holding all the stake of a new network
+func makeDataDir(t *testing.T, dir string) string {
+	genDir := filepath.Join(dir, "gen")
+	err := gen.GenerateGenesisFiles(gen.GenesisData{
+		NetworkName:       "embeddedtest",
+		FirstPartKeyRound: 0,
+		LastPartKeyRound:  1000,
+		Wallets:           []gen.WalletData{{Name: "Wallet", Stake: 100, Online: true}},
+	}, genDir)
+	require.NoError(t, err)
+
+	genesisText, err := ioutil.ReadFile(filepath.Join(genDir, config.GenesisJSONFile))
+	require.NoError(t, err)
+	var genesis bookkeeping.Genesis
+	require.NoError(t, protocol.DecodeJSON(genesisText, &genesis))
+
+	dataDir := filepath.Join(dir, "node")
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, genesis.ID()), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dataDir, config.GenesisJSONFile), genesisText, 0600))
+	files, err := ioutil.ReadDir(genDir)
+	require.NoError(t, err)
+	for _, file := range files {
+		if file.Name() != config.GenesisJSONFile {
+			require.NoError(t, os.Rename(filepath.Join(genDir, file.Name()), filepath.Join(dataDir, genesis.ID(), file.Name())))
+		}
+	}
+	return dataDir
+}
+
+func TestEmbeddedNode(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dir, err := ioutil.TempDir("", "embedded")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dataDir := makeDataDir(t, dir)
+
+	cfg := config.GetDefaultLocal()
+	cfg.EndpointAddress = "127.0.0.1:0"
+	cfg.DNSBootstrapID = ""
+	cfg.DisableStartupChecks = true
+	node, err := Open(dataDir, Options{Config: &cfg})
+	require.NoError(t, err)
+	defer node.Stop()
+
+	_, err = Open(dataDir, Options{Config: &cfg})
+	require.Error(t, err, "the data directory is locked")
+
+	require.NoError(t, node.Start())
+	require.NoError(t, node.Start(), "starting twice is a no-op")
+	require.NoError(t, node.WaitForRound(context.Background(), 0), "the genesis round is there from the start")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, node.WaitForRound(ctx, basics.Round(1000)))
+	require.Empty(t, node.TransactionPool().Pending())
+
+	request, err := http.NewRequest("GET", "http://"+node.APIAddress()+"/v1/status", nil)
+	require.NoError(t, err)
+	request.Header.Set("X-Algo-API-Token", node.APIToken())
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	node.Stop()
+	require.Equal(t, ErrNodeStopped, node.WaitForRound(context.Background(), basics.Round(1000)))
+	require.Equal(t, ErrNodeStopped, node.Start())
+
+	// the data directory is free again
+	node, err = Open(dataDir, Options{Config: &cfg})
+	require.NoError(t, err)
+	node.Stop()
+}
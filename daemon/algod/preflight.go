@@ -0,0 +1,163 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package algod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/util"
+	"github.com/algorand/go-algorand/util/db"
+	"github.com/algorand/go-algorand/util/tokens"
+)
+
+const (
+	defaultClockCheckServer = "pool.ntp.org"
+	clockCheckTimeout       = 3 * time.Second
+	maxClockSkew            = 5 * time.Second
+)
+
+// preflightFailure is a startup check that failed. The node refuses to start when a critical check fails, and starts
+// degraded otherwise.
+type preflightFailure struct {
+	check    string
+	critical bool
+	err      error
+}
+
+func (f preflightFailure) String() string {
+	return fmt.Sprintf("%s: %v", f.check, f.err)
+}
+
+// runPreflightChecks checks that the data directory is in a state the node can run with, so that the node fails
+// right away with a clear reason rather than obscurely later on.
+func runPreflightChecks(rootDir string, genesis bookkeeping.Genesis, cfg config.Local) (failures []preflightFailure) {
+	fail := func(check string, critical bool, err error) {
+		failures = append(failures, preflightFailure{check: check, critical: critical, err: err})
+	}
+
+	genesisDir := filepath.Join(rootDir, genesis.ID())
+	ledgerPrefix := filepath.Join(genesisDir, config.LedgerFilenamePrefix)
+	if err := checkLedgerGenesis(ledgerPrefix, crypto.HashObj(genesis)); err != nil {
+		fail("genesis", true, err)
+	}
+
+	trackerDBFilename, blockDBFilename := ledger.DBFilenames(ledgerPrefix)
+	critical := map[string]bool{trackerDBFilename: true, blockDBFilename: true}
+	databases := []string{blockDBFilename, filepath.Join(genesisDir, config.CrashFilename)}
+	if trackerDBFilename != blockDBFilename {
+		databases = append(databases, trackerDBFilename)
+	}
+	partKeys, _ := filepath.Glob(filepath.Join(genesisDir, "*.partkey"))
+	for _, filename := range append(databases, partKeys...) {
+		if err := checkDatabase(filename); err != nil {
+			fail("database "+filepath.Base(filename), critical[filename], err)
+		}
+	}
+
+	tokenFiles := []string{filepath.Join(rootDir, tokens.AlgodTokenFilename)}
+	kmdTokenFiles, _ := filepath.Glob(filepath.Join(rootDir, "kmd-*", tokens.KmdTokenFilename))
+	for _, filename := range append(tokenFiles, kmdTokenFiles...) {
+		if err := checkTokenPermissions(filename); err != nil {
+			fail("token "+filename, false, err)
+		}
+	}
+
+	server := cfg.StartupClockCheckServer
+	if server == "" {
+		server = defaultClockCheckServer
+	}
+	if server != "none" {
+		offset, err := util.NTPClockOffset(server, clockCheckTimeout)
+		if err != nil {
+			fail("clock", false, fmt.Errorf("unable to compare the clock with %s: %v", server, err))
+		} else if offset > maxClockSkew || offset < -maxClockSkew {
+			fail("clock", false, fmt.Errorf("the clock is %v off %s", offset.Round(time.Millisecond), server))
+		}
+	}
+	return
+}
+
+// checkLedgerGenesis checks that the ledger stored under ledgerPrefix, if any, was created from the given genesis.
+func checkLedgerGenesis(ledgerPrefix string, genesisHash crypto.Digest) error {
+	hdr, err := ledger.LatestBlockHeader(ledgerPrefix)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read the ledger: %v", err)
+	}
+	if hdr.GenesisHash != (crypto.Digest{}) && hdr.GenesisHash != genesisHash {
+		return fmt.Errorf("the ledger was created from genesis %v, but genesis.json hashes to %v", hdr.GenesisHash, genesisHash)
+	}
+	return nil
+}
+
+// checkDatabase runs the sqlite quick check on the database file, if it exists.
+func checkDatabase(filename string) error {
+	if _, err := os.Stat(filename); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	accessor, err := db.MakeAccessor(filename, true, false)
+	if err != nil {
+		return err
+	}
+	defer accessor.Close()
+	return accessor.IntegrityCheck()
+}
+
+// checkTokenPermissions checks that the token file, if it exists, can't be read or replaced by other users.
+func checkTokenPermissions(filename string) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("the file is accessible to other users (mode %v); restrict it with chmod 600", info.Mode().Perm())
+	}
+	return nil
+}
+
+// preflight runs the startup checks, logging the failed ones. It returns an error when a critical check failed.
+func (s *Server) preflight(cfg config.Local) error {
+	var critical []string
+	for _, failure := range runPreflightChecks(s.RootPath, s.Genesis, cfg) {
+		if failure.critical {
+			s.log.Errorf("Startup check failed: %v", failure)
+			critical = append(critical, failure.String())
+		} else {
+			s.log.Warnf("Startup check failed, starting degraded: %v", failure)
+		}
+	}
+	if len(critical) > 0 {
+		return fmt.Errorf("refusing to start the node, since the startup checks failed (set DisableStartupChecks to start anyway):\n%s", strings.Join(critical, "\n"))
+	}
+	return nil
+}
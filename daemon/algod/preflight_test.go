@@ -0,0 +1,61 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package algod
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/util/tokens"
+)
+
+func TestPreflightChecks(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "preflight")
+	require.NoError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	genesis := bookkeeping.Genesis{SchemaID: "v1", Network: "preflight"}
+	cfg := config.GetDefaultLocal()
+	cfg.StartupClockCheckServer = "none"
+	require.Empty(t, runPreflightChecks(rootDir, genesis, cfg))
+
+	// a token readable by other users only degrades the node
+	tokenFile := filepath.Join(rootDir, tokens.AlgodTokenFilename)
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token"), 0644))
+	failures := runPreflightChecks(rootDir, genesis, cfg)
+	require.Len(t, failures, 1)
+	require.False(t, failures[0].critical)
+	require.NoError(t, os.Chmod(tokenFile, 0600))
+	require.Empty(t, runPreflightChecks(rootDir, genesis, cfg))
+
+	// a corrupted ledger is critical
+	genesisDir := filepath.Join(rootDir, genesis.ID())
+	require.NoError(t, os.Mkdir(genesisDir, 0700))
+	blockDB := filepath.Join(genesisDir, config.LedgerFilenamePrefix+".block.sqlite")
+	require.NoError(t, ioutil.WriteFile(blockDB, []byte("not a database, not a database, not a database"), 0600))
+	failures = runPreflightChecks(rootDir, genesis, cfg)
+	require.NotEmpty(t, failures)
+	for _, failure := range failures {
+		require.True(t, failure.critical)
+	}
+}
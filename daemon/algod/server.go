@@ -28,25 +28,43 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/algorand/go-deadlock"
+	"google.golang.org/grpc"
 
 	"github.com/algorand/go-algorand/config"
 	apiServer "github.com/algorand/go-algorand/daemon/algod/api/server"
+	"github.com/algorand/go-algorand/daemon/algod/api/server/grpcapi"
+	"github.com/algorand/go-algorand/daemon/algod/audit"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/logging/telemetryspec"
 	"github.com/algorand/go-algorand/node"
 	"github.com/algorand/go-algorand/util/metrics"
 	"github.com/algorand/go-algorand/util/tokens"
+	"github.com/algorand/go-algorand/util/tracing"
 )
 
-var server http.Server
+// apiDrainTimeout is how long a shutting down node waits for the REST API requests in progress to complete
+const apiDrainTimeout = 5 * time.Second
 
 // Server represents an instance of the REST API HTTP server
 type Server struct {
-	RootPath             string
-	Genesis              bookkeeping.Genesis
+	RootPath      string
+	Genesis       bookkeeping.Genesis
+	LoggingConfig logging.TelemetryConfig
+	// DiskConfig is the config as loaded from RootPath with ConfigOverrides applied, before the dedicated command
+	// line overrides. Config reloads are compared against it, so that overridden settings are kept unless they change.
+	DiskConfig config.Local
+	// Profile is the config profile the node runs with, if any
+	Profile *config.Profile
+	// ConfigOverrides are the environment and -set overrides, which config reloads apply again
+	ConfigOverrides []config.ConfigOverride
+	// PhonebookDir is the directory of the phonebook.json file; empty uses the directory of the executable
+	PhonebookDir         string
+	apiServer            http.Server
+	serveErr             chan error
 	pidFile              string
 	netFile              string
 	netListenFile        string
@@ -54,9 +72,14 @@ type Server struct {
 	node                 *node.AlgorandFullNode
 	metricCollector      *metrics.MetricService
 	metricServiceStarted bool
+	metricsListener      *metrics.MetricsListener
+	grpcServer           *grpc.Server
+	auditLog             *audit.Log
 
 	stopping deadlock.Mutex
 	stopped  bool
+
+	reloading deadlock.Mutex
 }
 
 // Initialize creates a Node instance with applicable network services
@@ -67,10 +90,17 @@ func (s *Server) Initialize(cfg config.Local) error {
 	liveLog := fmt.Sprintf("%s/node.log", s.RootPath)
 	archive := fmt.Sprintf("%s/node.archive.log", s.RootPath)
 	fmt.Println("Logging to: ", liveLog)
-	logWriter := logging.MakeCyclicFileWriter(liveLog, archive, cfg.LogSizeLimit)
+	logWriter := logging.MakeCyclicFileWriterWithPolicy(liveLog, archive, s.LoggingConfig.LogRotationPolicy(cfg.LogSizeLimit))
 	s.log.SetOutput(logWriter)
-	s.log.SetJSONFormatter()
+	if cfg.StructuredLogging {
+		s.log.SetStructuredJSONFormatter()
+	} else {
+		s.log.SetJSONFormatter()
+	}
 	s.log.SetLevel(logging.Level(cfg.BaseLoggerDebugLevel))
+	if err := logging.SetSubsystemLevels(s.LoggingConfig.SubsystemLogLevels); err != nil {
+		s.log.Warnf("Ignoring the subsystem log levels of %s : %v", s.LoggingConfig.FilePath, err)
+	}
 	setupDeadlockLogger()
 
 	// configure the deadlock detector library
@@ -100,6 +130,13 @@ func (s *Server) Initialize(cfg config.Local) error {
 	}
 	fmt.Fprintln(logWriter, "++++++++++++++++++++++++++++++++++++++++")
 
+	if cfg.EnableCrashReports {
+		logging.EnableCrashReports(s.RootPath)
+		if s.log.GetTelemetryEnabled() {
+			go s.sendCrashReports()
+		}
+	}
+
 	metricLabels := map[string]string{}
 	if s.log.GetTelemetryEnabled() {
 		metricLabels["telemetry_session"] = s.log.GetTelemetrySession()
@@ -111,12 +148,27 @@ func (s *Server) Initialize(cfg config.Local) error {
 			NodeExporterPath:          cfg.NodeExporterPath,
 		})
 
-	ex, err := os.Executable()
-	if err != nil {
-		return fmt.Errorf("cannot locate node executable: %s", err)
+	phonebookDir := s.PhonebookDir
+	if phonebookDir == "" {
+		ex, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("cannot locate node executable: %s", err)
+		}
+		phonebookDir = filepath.Dir(ex)
+	}
+
+	// loaded configs are always migrated to the latest version, so a zero version means DiskConfig wasn't set
+	if s.DiskConfig.Version == 0 {
+		s.DiskConfig = cfg
+	}
+
+	if !cfg.DisableStartupChecks {
+		if err := s.preflight(cfg); err != nil {
+			return err
+		}
 	}
-	phonebookDir := filepath.Dir(ex)
 
+	var err error
 	s.node, err = node.MakeFull(s.log, s.RootPath, cfg, phonebookDir, s.Genesis)
 	if os.IsNotExist(err) {
 		return fmt.Errorf("node has not been installed: %s", err)
@@ -144,16 +196,80 @@ func makeListener(addr string) (net.Listener, error) {
 	return net.Listen("tcp", addr)
 }
 
-// Start starts a Node instance and its network services
+// Start starts the node and its API services, and serves the API until the node stops. This is what algod runs:
+// it handles the termination and config reload signals, and exits the process if the services can't start.
 func (s *Server) Start() {
-	s.log.Info("Trying to start an Algorand node")
 	fmt.Print("Initializing the Algorand node... ")
+	err := s.StartServices()
+	if err != nil {
+		fmt.Printf("Could not start node: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Success!")
+
+	defer s.Stop()
+
+	// Handle signals cleanly
+	c := make(chan os.Signal)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-c
+		fmt.Printf("Exiting on %v\n", sig)
+		s.Stop()
+		os.Exit(0)
+	}()
+
+	// SIGHUP reloads the config file rather than terminating the node
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if _, err := s.ReloadConfig(); err != nil {
+				s.log.Warnf("Unable to reload the config: %v", err)
+			}
+		}
+	}()
+
+	fmt.Printf("Node running and accepting RPC requests over HTTP on port %v. Press Ctrl-C to exit\n", s.APIAddress())
+	err = <-s.serveErr
+	if err != nil && err != http.ErrServerClosed {
+		s.log.Warn(err)
+	} else {
+		s.log.Info("Node exited successfully")
+	}
+}
+
+// StartServices starts the node and its API services, and returns once they run. Unlike Start, it doesn't handle
+// signals nor block, so that programs can embed the node: they call Initialize, then StartServices, and Stop
+// once they are done with the node, or if StartServices failed.
+func (s *Server) StartServices() error {
+	s.log.Info("Trying to start an Algorand node")
 	s.node.Start()
 	s.log.Info("Successfully started an Algorand node.")
-	fmt.Println("Success!")
 
 	cfg := s.node.Config()
 
+	if cfg.TracingEndpoint != "" {
+		err := tracing.Start(tracing.Config{
+			Endpoint:           cfg.TracingEndpoint,
+			ServiceName:        "algod",
+			ResourceAttributes: []tracing.Attribute{tracing.Attr("algorand.genesis_id", s.Genesis.ID())},
+		})
+		if err != nil {
+			s.log.Warnf("Unable to start tracing : %v", err)
+		}
+	}
+
+	if cfg.MetricsListenAddress != "" {
+		s.metricsListener = metrics.MakeMetricsListener(cfg.MetricsListenAddress, nil)
+		if err := s.metricsListener.Start(); err != nil {
+			s.log.Warnf("Unable to start metrics listener on %s : %v", cfg.MetricsListenAddress, err)
+			s.metricsListener = nil
+		} else {
+			s.log.Infof("Serving metrics on %s", s.metricsListener.Addr())
+		}
+	}
+
 	if cfg.EnableMetricReporting {
 		if err := s.metricCollector.Start(context.Background()); err != nil {
 			// log this error
@@ -164,13 +280,28 @@ func (s *Server) Start() {
 
 	apiToken, err := tokens.GetAndValidateAPIToken(s.RootPath, tokens.AlgodTokenFilename)
 	if err != nil {
-		fmt.Printf("APIToken error: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("APIToken error: %v", err)
+	}
+
+	if !cfg.DisableAuditLog {
+		s.auditLog = audit.MakeLog(s.RootPath, cfg.AuditLogSizeLimit, cfg.AuditLogArchiveCount)
+		s.recordAudit("startup")
 	}
 
 	// use the data dir as the static file dir (for our API server), there's
 	// no need to separate the two yet. This lets us serve the swagger.json file.
-	handler := apiServer.NewRouter(s.log, s.node, apiToken, s.RootPath)
+	handler := apiServer.NewRouter(s.log, s.node, apiToken, s.RootPath, s.auditLog, s.Stop)
+
+	if cfg.GRPCEndpointAddress != "" {
+		grpcListener, err := net.Listen("tcp", cfg.GRPCEndpointAddress)
+		if err != nil {
+			s.log.Warnf("Unable to start the gRPC interface on %s : %v", cfg.GRPCEndpointAddress, err)
+		} else {
+			s.grpcServer = grpcapi.NewServer(s.log, s.node, apiToken)
+			s.log.Infof("Serving gRPC on %s", grpcListener.Addr())
+			go s.grpcServer.Serve(grpcListener)
+		}
+	}
 
 	addr := cfg.EndpointAddress
 	if addr == "" {
@@ -178,22 +309,17 @@ func (s *Server) Start() {
 	}
 
 	listener, err := makeListener(addr)
-
 	if err != nil {
-		fmt.Printf("Could not start node: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
 	addr = listener.Addr().String()
-	server = http.Server{Addr: addr, Handler: handler}
-
-	defer s.Stop()
+	s.apiServer = http.Server{Addr: addr, Handler: handler}
 
 	tcpListener := listener.(*net.TCPListener)
-	errChan := make(chan error, 1)
+	s.serveErr = make(chan error, 1)
 	go func() {
-		err = server.Serve(tcpListener)
-		errChan <- err
+		s.serveErr <- s.apiServer.Serve(tcpListener)
 	}()
 
 	// Set up files for our PID and our listening address
@@ -207,28 +333,78 @@ func (s *Server) Start() {
 		s.netListenFile = filepath.Join(s.RootPath, "algod-listen.net")
 		ioutil.WriteFile(s.netListenFile, []byte(fmt.Sprintf("%s\n", listenAddr)), 0644)
 	}
+	return nil
+}
 
-	// Handle signals cleanly
-	c := make(chan os.Signal)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
-	signal.Ignore(syscall.SIGHUP)
-	go func() {
-		sig := <-c
-		fmt.Printf("Exiting on %v\n", sig)
-		s.Stop()
-		os.Exit(0)
-	}()
+// Node returns the node the server runs, for the programs that embed it to access its ledger and transaction pool
+func (s *Server) Node() *node.AlgorandFullNode {
+	return s.node
+}
+
+// APIAddress returns the address the REST API listens on, once StartServices returned
+func (s *Server) APIAddress() string {
+	return s.apiServer.Addr
+}
+
+// ReloadConfig re-reads the config file from RootPath and applies the changed settings a running node can
+// apply (see config.IsReloadable). The changed settings that need a restart are reported in the log, and
+// keep being reported by later reloads until the node restarts.
+func (s *Server) ReloadConfig() (changes config.ConfigChanges, err error) {
+	s.reloading.Lock()
+	defer s.reloading.Unlock()
 
-	fmt.Printf("Node running and accepting RPC requests over HTTP on port %v. Press Ctrl-C to exit\n", addr)
-	err = <-errChan
+	cfg, validation, err := config.LoadProfiledConfigFromDisk(s.RootPath, s.Profile)
+	if err != nil && !os.IsNotExist(err) {
+		return
+	}
+	err = nil
+	for _, warning := range validation.Warnings {
+		s.log.Warnf("Config file %s: %v", config.ConfigFilename, warning)
+	}
+	if err = config.ApplyOverrides(&cfg, s.ConfigOverrides); err != nil {
+		return
+	}
+
+	changes = config.CompareConfigs(s.DiskConfig, cfg)
+	if len(changes.Reloaded) > 0 {
+		updated := s.node.Config()
+		config.CopySettings(&updated, cfg, changes.Reloaded)
+		s.node.ApplyConfig(updated)
+		config.CopySettings(&s.DiskConfig, cfg, changes.Reloaded)
+	}
+
+	s.log.Infof("Reloaded the config: applied %v", changes.Reloaded)
+	if len(changes.RestartRequired) > 0 {
+		s.log.Warnf("Reloaded the config: the node must be restarted to apply %v", changes.RestartRequired)
+	}
+	s.recordAudit("config-reload")
+	return
+}
+
+// sendCrashReports uploads the crash reports of the previous runs of the node.
+func (s *Server) sendCrashReports() {
+	sent, err := logging.SendCrashReports(s.RootPath, s.LoggingConfig.GUID)
 	if err != nil {
-		s.log.Warn(err)
-	} else {
-		s.log.Info("Node exited successfully")
+		s.log.Warnf("Unable to send crash reports : %v", err)
+	}
+	if sent > 0 {
+		s.log.Infof("Sent %d crash reports", sent)
+	}
+}
+
+// recordAudit records a node lifecycle operation into the audit log, if it is enabled.
+func (s *Server) recordAudit(action string) {
+	if s.auditLog == nil {
+		return
+	}
+	if err := s.auditLog.Record(audit.Entry{Action: action, Params: map[string]string{"pid": fmt.Sprintf("%d", os.Getpid())}}); err != nil {
+		s.log.Warnf("unable to record %s in the audit log: %v", action, err)
 	}
 }
 
-// Stop initiates a graceful shutdown of the node by shutting down the network server.
+// Stop shuts the node down gracefully: it stops accepting new transactions, drains the REST API requests in
+// progress, and shuts the node down once the round in progress completes. The time each step took is logged and
+// reported in the shutdown telemetry event.
 func (s *Server) Stop() {
 	s.stopping.Lock()
 	defer s.stopping.Unlock()
@@ -237,13 +413,36 @@ func (s *Server) Stop() {
 		return
 	}
 
-	// Attempt to log a shutdown event before we exit...
-	s.log.Event(telemetryspec.ApplicationState, telemetryspec.ShutdownEvent)
+	s.recordAudit("shutdown")
+	start := time.Now()
+	s.node.BeginShutdown()
 
-	err := server.Shutdown(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), apiDrainTimeout)
+	err := s.apiServer.Shutdown(ctx)
+	cancel()
 	if err != nil {
 		s.log.Error(err)
 	}
+	if s.grpcServer != nil {
+		// Block streams never complete on their own, so don't wait for the calls in progress
+		s.grpcServer.Stop()
+		s.grpcServer = nil
+	}
+	steps := []node.ShutdownStep{{Subsystem: "api", Duration: time.Since(start)}}
+	steps = append(steps, s.node.Shutdown()...)
+
+	details := telemetryspec.ShutdownEventDetails{StepsMs: make(map[string]int64, len(steps))}
+	for _, step := range steps {
+		if step.TimedOut {
+			s.log.Warnf("Shutdown: gave up on %s after %v", step.Subsystem, step.Duration)
+		} else {
+			s.log.Infof("Shutdown: stopped %s in %v", step.Subsystem, step.Duration)
+		}
+		details.StepsMs[step.Subsystem] = int64(step.Duration / time.Millisecond)
+	}
+	details.TotalMs = int64(time.Since(start) / time.Millisecond)
+	s.log.Infof("Shutdown: the node stopped in %v", time.Since(start))
+	s.log.EventWithDetails(telemetryspec.ApplicationState, telemetryspec.ShutdownEvent, details)
 
 	if s.metricServiceStarted {
 		if err := s.metricCollector.Shutdown(); err != nil {
@@ -253,6 +452,14 @@ func (s *Server) Stop() {
 		s.metricServiceStarted = false
 	}
 
+	if s.metricsListener != nil {
+		if err := s.metricsListener.Shutdown(); err != nil {
+			s.log.Infof("Unable to shutdown metrics listener : %v", err)
+		}
+		s.metricsListener = nil
+	}
+
+	tracing.Shutdown()
 	s.log.CloseTelemetry()
 
 	os.Remove(s.pidFile)
@@ -685,6 +685,59 @@ func postKeyHandler(ctx reqContext, w http.ResponseWriter, r *http.Request) {
 	successResponse(w, resp)
 }
 
+// postKeyDeriveHandler handles `POST /v1/key/derive`
+func postKeyDeriveHandler(ctx reqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /v1/key/derive DeriveKey
+	//---
+	//    Summary: Derive a key
+	//    Produces:
+	//    - application/json
+	//    Description: >
+	//      Derives the key at the given index of the deterministic key sequence (as determined by the master
+	//      derivation key) and adds it to the wallet, returning the public key. Indices start at 1, so deriving
+	//      indices 1 through N rebuilds the first N keys generated by the wallet.
+	//    Parameters:
+	//      - name: Derive Key Request
+	//        in: body
+	//        required: true
+	//        schema:
+	//          "$ref": "#/definitions/DeriveKeyRequest"
+	//    Responses:
+	//      "200":
+	//        "$ref": "#/responses/DeriveKeyResponse"
+	var req kmdapi.APIV1POSTKeyDeriveRequest
+
+	// Decode the request
+	decoder := protocol.NewJSONDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, errCouldNotDecode)
+		return
+	}
+
+	// Fetch the wallet from the WalletHandleToken
+	wallet, _, err := ctx.sm.AuthWithWalletHandleToken([]byte(req.WalletHandleToken))
+	if err != nil {
+		errorResponse(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Derive the key
+	addr, err := wallet.DeriveKey(req.Index)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Build the response
+	resp := kmdapi.APIV1POSTKeyDeriveResponse{
+		Address: encodeAddress(addr),
+	}
+
+	// Return and encode the response
+	successResponse(w, resp)
+}
+
 // deleteKeyHandler handles `DELETE /v1/key`
 func deleteKeyHandler(ctx reqContext, w http.ResponseWriter, r *http.Request) {
 	// swagger:operation DELETE /v1/key DeleteKey
@@ -837,7 +890,7 @@ func postTransactionSignHandler(ctx reqContext, w http.ResponseWriter, r *http.R
 	}
 
 	// Sign the transaction
-	stx, err := wallet.SignTransaction(tx, []byte(req.WalletPassword))
+	stx, err := wallet.SignTransaction(tx, req.PublicKey, []byte(req.WalletPassword))
 	if err != nil {
 		errorResponse(w, http.StatusBadRequest, err)
 		return
@@ -1016,6 +1069,112 @@ func postMultisigExportHandler(ctx reqContext, w http.ResponseWriter, r *http.Re
 	successResponse(w, resp)
 }
 
+// postDataSignHandler handles `POST /v1/data/sign`
+func postDataSignHandler(ctx reqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /v1/data/sign SignData
+	//---
+	//    Summary: Sign data
+	//    Description: >
+	//      Signs the passed data with the key of the passed public key. The
+	//      signature covers the data prefixed with "MX", so that it can never
+	//      be used as the signature of a transaction.
+	//    Produces:
+	//    - application/json
+	//    Parameters:
+	//      - name: Sign Data Request
+	//        in: body
+	//        required: true
+	//        schema:
+	//          "$ref": "#/definitions/SignDataRequest"
+	//    Responses:
+	//      "200":
+	//        "$ref": "#/responses/SignDataResponse"
+	var req kmdapi.APIV1POSTDataSignRequest
+
+	// Decode the request
+	decoder := protocol.NewJSONDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, errCouldNotDecode)
+		return
+	}
+
+	// Fetch the wallet from the WalletHandleToken
+	wallet, _, err := ctx.sm.AuthWithWalletHandleToken([]byte(req.WalletHandleToken))
+	if err != nil {
+		errorResponse(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Sign the data
+	sig, err := wallet.SignData(req.Data, req.PublicKey, []byte(req.WalletPassword))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Build the response
+	resp := kmdapi.APIV1POSTDataSignResponse{
+		Signature: sig,
+	}
+
+	// Return and encode the response
+	successResponse(w, resp)
+}
+
+// postProgramSignHandler handles `POST /v1/program/sign`
+func postProgramSignHandler(ctx reqContext, w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /v1/program/sign SignProgram
+	//---
+	//    Summary: Sign a program
+	//    Description: >
+	//      Signs the passed TEAL program with the key of the passed public
+	//      key, delegating to the program the authority to approve
+	//      transactions from its account through a LogicSig.
+	//    Produces:
+	//    - application/json
+	//    Parameters:
+	//      - name: Sign Program Request
+	//        in: body
+	//        required: true
+	//        schema:
+	//          "$ref": "#/definitions/SignProgramRequest"
+	//    Responses:
+	//      "200":
+	//        "$ref": "#/responses/SignProgramResponse"
+	var req kmdapi.APIV1POSTProgramSignRequest
+
+	// Decode the request
+	decoder := protocol.NewJSONDecoder(r.Body)
+	err := decoder.Decode(&req)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, errCouldNotDecode)
+		return
+	}
+
+	// Fetch the wallet from the WalletHandleToken
+	wallet, _, err := ctx.sm.AuthWithWalletHandleToken([]byte(req.WalletHandleToken))
+	if err != nil {
+		errorResponse(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Sign the program
+	sig, err := wallet.SignProgram(req.Program, req.PublicKey, []byte(req.WalletPassword))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Build the response
+	resp := kmdapi.APIV1POSTProgramSignResponse{
+		Signature: sig,
+	}
+
+	// Return and encode the response
+	successResponse(w, resp)
+}
+
 // postMultisigTransactionSignHandler handles `POST /v1/multisig/sign`
 func postMultisigTransactionSignHandler(ctx reqContext, w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /v1/multisig/sign SignMultisigTransaction
@@ -1181,6 +1340,7 @@ func RegisterHandlers(router *mux.Router, sm *session.Manager, log logging.Logge
 	router.HandleFunc("/key/list", wrapCtx(ctx, postKeyListHandler)).Methods("POST")
 	router.HandleFunc("/key/import", wrapCtx(ctx, postKeyImportHandler)).Methods("POST")
 	router.HandleFunc("/key/export", wrapCtx(ctx, postKeyExportHandler)).Methods("POST")
+	router.HandleFunc("/key/derive", wrapCtx(ctx, postKeyDeriveHandler)).Methods("POST")
 	router.HandleFunc("/key", wrapCtx(ctx, postKeyHandler)).Methods("POST")
 	router.HandleFunc("/key", wrapCtx(ctx, deleteKeyHandler)).Methods("DELETE")
 
@@ -1191,4 +1351,8 @@ func RegisterHandlers(router *mux.Router, sm *session.Manager, log logging.Logge
 	router.HandleFunc("/multisig", wrapCtx(ctx, deleteMultisigHandler)).Methods("DELETE")
 
 	router.HandleFunc("/transaction/sign", wrapCtx(ctx, postTransactionSignHandler)).Methods("POST")
+
+	router.HandleFunc("/data/sign", wrapCtx(ctx, postDataSignHandler)).Methods("POST")
+
+	router.HandleFunc("/program/sign", wrapCtx(ctx, postProgramSignHandler)).Methods("POST")
 }
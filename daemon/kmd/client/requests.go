@@ -111,6 +111,9 @@ func getPathAndMethod(req kmdapi.APIV1Request) (reqPath string, reqMethod string
 	case kmdapi.APIV1POSTKeyRequest:
 		reqPath = "v1/key"
 		reqMethod = "POST"
+	case kmdapi.APIV1POSTKeyDeriveRequest:
+		reqPath = "v1/key/derive"
+		reqMethod = "POST"
 	case kmdapi.APIV1DELETEKeyRequest:
 		reqPath = "v1/key"
 		reqMethod = "DELETE"
@@ -120,6 +123,12 @@ func getPathAndMethod(req kmdapi.APIV1Request) (reqPath string, reqMethod string
 	case kmdapi.APIV1POSTTransactionSignRequest:
 		reqPath = "v1/transaction/sign"
 		reqMethod = "POST"
+	case kmdapi.APIV1POSTDataSignRequest:
+		reqPath = "v1/data/sign"
+		reqMethod = "POST"
+	case kmdapi.APIV1POSTProgramSignRequest:
+		reqPath = "v1/program/sign"
+		reqMethod = "POST"
 	case kmdapi.APIV1POSTMultisigListRequest:
 		reqPath = "v1/multisig/list"
 		reqMethod = "POST"
@@ -47,6 +47,16 @@ func (kcl KMDClient) GenerateKey(walletHandle []byte) (resp kmdapi.APIV1POSTKeyR
 	return
 }
 
+// DeriveKey wraps kmdapi.APIV1POSTKeyDeriveRequest
+func (kcl KMDClient) DeriveKey(walletHandle []byte, index uint64) (resp kmdapi.APIV1POSTKeyDeriveResponse, err error) {
+	req := kmdapi.APIV1POSTKeyDeriveRequest{
+		WalletHandleToken: string(walletHandle),
+		Index:             index,
+	}
+	err = kcl.DoV1Request(req, &resp)
+	return
+}
+
 // CreateWallet wraps kmdapi.APIV1POSTWalletRequest
 func (kcl KMDClient) CreateWallet(walletName []byte, walletDriverName string, walletPassword []byte, walletMDK crypto.MasterDerivationKey) (resp kmdapi.APIV1POSTWalletResponse, err error) {
 	req := kmdapi.APIV1POSTWalletRequest{
@@ -162,6 +172,15 @@ func (kcl KMDClient) RenewWalletHandle(walletHandle []byte) (resp kmdapi.APIV1PO
 	return
 }
 
+// GetWalletInfo wraps kmdapi.APIV1POSTWalletInfoRequest
+func (kcl KMDClient) GetWalletInfo(walletHandle []byte) (resp kmdapi.APIV1POSTWalletInfoResponse, err error) {
+	req := kmdapi.APIV1POSTWalletInfoRequest{
+		WalletHandleToken: string(walletHandle),
+	}
+	err = kcl.DoV1Request(req, &resp)
+	return
+}
+
 // ImportKey wraps kmdapi.APIV1POSTKeyImportRequest
 func (kcl KMDClient) ImportKey(walletHandle []byte, secretKey crypto.PrivateKey) (resp kmdapi.APIV1POSTKeyImportResponse, err error) {
 	req := kmdapi.APIV1POSTKeyImportRequest{
@@ -182,13 +201,39 @@ func (kcl KMDClient) ExportMasterDerivationKey(walletHandle []byte, walletPasswo
 	return
 }
 
-// SignTransaction wraps kmdapi.APIV1POSTTransactionSignRequest
-func (kcl KMDClient) SignTransaction(walletHandle, pw []byte, tx transactions.Transaction) (resp kmdapi.APIV1POSTTransactionSignResponse, err error) {
+// SignData wraps kmdapi.APIV1POSTDataSignRequest
+func (kcl KMDClient) SignData(walletHandle, pw []byte, pk crypto.PublicKey, data []byte) (resp kmdapi.APIV1POSTDataSignResponse, err error) {
+	req := kmdapi.APIV1POSTDataSignRequest{
+		WalletHandleToken: string(walletHandle),
+		WalletPassword:    string(pw),
+		Data:              data,
+		PublicKey:         pk,
+	}
+	err = kcl.DoV1Request(req, &resp)
+	return
+}
+
+// SignProgram wraps kmdapi.APIV1POSTProgramSignRequest
+func (kcl KMDClient) SignProgram(walletHandle, pw []byte, pk crypto.PublicKey, program []byte) (resp kmdapi.APIV1POSTProgramSignResponse, err error) {
+	req := kmdapi.APIV1POSTProgramSignRequest{
+		WalletHandleToken: string(walletHandle),
+		WalletPassword:    string(pw),
+		Program:           program,
+		PublicKey:         pk,
+	}
+	err = kcl.DoV1Request(req, &resp)
+	return
+}
+
+// SignTransaction wraps kmdapi.APIV1POSTTransactionSignRequest. pk is the
+// key to sign with, the sender's when it is zero.
+func (kcl KMDClient) SignTransaction(walletHandle, pw []byte, pk crypto.PublicKey, tx transactions.Transaction) (resp kmdapi.APIV1POSTTransactionSignResponse, err error) {
 	txBytes := protocol.Encode(tx)
 	req := kmdapi.APIV1POSTTransactionSignRequest{
 		WalletHandleToken: string(walletHandle),
 		WalletPassword:    string(pw),
 		Transaction:       txBytes,
+		PublicKey:         pk,
 	}
 	err = kcl.DoV1Request(req, &resp)
 	return
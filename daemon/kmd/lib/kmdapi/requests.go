@@ -133,6 +133,15 @@ type APIV1POSTKeyRequest struct {
 	DisplayMnemonic   bool   `json:"display_mnemonic"`
 }
 
+// APIV1POSTKeyDeriveRequest is the request for `POST /v1/key/derive`
+//
+// swagger:model DeriveKeyRequest
+type APIV1POSTKeyDeriveRequest struct {
+	APIV1RequestEnvelope
+	WalletHandleToken string `json:"wallet_handle_token"`
+	Index             uint64 `json:"index"`
+}
+
 // APIV1DELETEKeyRequest is the request for `DELETE /v1/key`
 //
 // swagger:model DeleteKeyRequest
@@ -158,7 +167,32 @@ type APIV1POSTTransactionSignRequest struct {
 	APIV1RequestEnvelope
 	WalletHandleToken string `json:"wallet_handle_token"`
 	Transaction       Bytes  `json:"transaction"`
-	WalletPassword    string `json:"wallet_password"`
+	// PublicKey is the key to sign with, for a sender that has been
+	// rekeyed. The sender's key is used when it is empty.
+	PublicKey      crypto.PublicKey `json:"public_key"`
+	WalletPassword string           `json:"wallet_password"`
+}
+
+// APIV1POSTDataSignRequest is the request for `POST /v1/data/sign`
+//
+// swagger:model SignDataRequest
+type APIV1POSTDataSignRequest struct {
+	APIV1RequestEnvelope
+	WalletHandleToken string           `json:"wallet_handle_token"`
+	Data              Bytes            `json:"data"`
+	PublicKey         crypto.PublicKey `json:"public_key"`
+	WalletPassword    string           `json:"wallet_password"`
+}
+
+// APIV1POSTProgramSignRequest is the request for `POST /v1/program/sign`
+//
+// swagger:model SignProgramRequest
+type APIV1POSTProgramSignRequest struct {
+	APIV1RequestEnvelope
+	WalletHandleToken string           `json:"wallet_handle_token"`
+	Program           Bytes            `json:"program"`
+	PublicKey         crypto.PublicKey `json:"public_key"`
+	WalletPassword    string           `json:"wallet_password"`
 }
 
 // APIV1POSTMultisigListRequest is the request for `POST /v1/multisig/list`
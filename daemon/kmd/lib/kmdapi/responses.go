@@ -18,6 +18,8 @@ package kmdapi
 
 import (
 	"errors"
+
+	"github.com/algorand/go-algorand/crypto"
 )
 
 // APIV1Response is the interface that all API V1 responses must satisfy
@@ -130,6 +132,13 @@ type APIV1POSTKeyResponse struct {
 	Address string `json:"address"`
 }
 
+// APIV1POSTKeyDeriveResponse is the response to `POST /v1/key/derive`
+// friendly:DeriveKeyResponse
+type APIV1POSTKeyDeriveResponse struct {
+	APIV1ResponseEnvelope
+	Address string `json:"address"`
+}
+
 // APIV1DELETEKeyResponse is the response to `DELETE /v1/key`
 // friendly:DeleteKeyResponse
 type APIV1DELETEKeyResponse struct {
@@ -150,6 +159,20 @@ type APIV1POSTTransactionSignResponse struct {
 	SignedTransaction Bytes `json:"signed_transaction"`
 }
 
+// APIV1POSTDataSignResponse is the response to `POST /v1/data/sign`
+// friendly:SignDataResponse
+type APIV1POSTDataSignResponse struct {
+	APIV1ResponseEnvelope
+	Signature crypto.Signature `json:"signature"`
+}
+
+// APIV1POSTProgramSignResponse is the response to `POST /v1/program/sign`
+// friendly:SignProgramResponse
+type APIV1POSTProgramSignResponse struct {
+	APIV1ResponseEnvelope
+	Signature crypto.Signature `json:"signature"`
+}
+
 // APIV1POSTMultisigListResponse is the response to `POST /v1/multisig/list`
 // friendly:ListMultisigResponse
 type APIV1POSTMultisigListResponse struct {
@@ -18,13 +18,19 @@ package driver
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
 
 	"github.com/algorand/go-deadlock"
 
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/daemon/kmd/config"
 	"github.com/algorand/go-algorand/daemon/kmd/wallet"
+	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/protocol"
 )
@@ -32,6 +38,26 @@ import (
 const (
 	ledgerWalletDriverName    = "ledger"
 	ledgerWalletDriverVersion = 1
+
+	// ledgerAccountsFilename is the file in the kmd data dir that remembers
+	// which accounts of each device have been added to its wallet
+	ledgerAccountsFilename    = "ledger_accounts.json"
+	ledgerAccountsPermissions = 0600
+
+	// ledgerMaxAccount is the highest account index the device derives,
+	// since accounts are hardened BIP32 indices
+	ledgerMaxAccount = 1<<31 - 1
+
+	// APDU instructions and parameters understood by the Algorand app
+	ledgerCLA             = 0x80
+	ledgerInsGetPublicKey = 0x03
+	ledgerInsSignMsgpack  = 0x08
+	ledgerP1First         = 0x00
+	ledgerP1WithAccount   = 0x01
+	ledgerP1More          = 0x80
+	ledgerP2Last          = 0x00
+	ledgerP2More          = 0x80
+	ledgerChunkSize       = 250
 )
 
 var ledgerWalletSupportedTxs = []protocol.TxType{protocol.PaymentTx, protocol.KeyRegistrationTx}
@@ -41,6 +67,10 @@ var ledgerWalletSupportedTxs = []protocol.TxType{protocol.PaymentTx, protocol.Ke
 // application from https://github.com/algorand/ledger-app-algorand
 type LedgerWalletDriver struct {
 	wallets map[string]*LedgerWallet
+
+	// accountsMu protects the accounts file shared by all devices
+	accountsMu   deadlock.Mutex
+	accountsPath string
 }
 
 // LedgerWallet represents a particular wallet under the
@@ -49,13 +79,19 @@ type LedgerWalletDriver struct {
 type LedgerWallet struct {
 	mu  deadlock.Mutex
 	dev LedgerUSB
+
+	driver *LedgerWalletDriver
+
+	// accounts are the device account indices added to this wallet. Account
+	// 0 is always present. addrs caches the address of each of them.
+	accounts []uint32
+	addrs    map[uint32]crypto.Digest
 }
 
 // CreateWallet implements the Driver interface.  There is
-// currently no way to create new wallet keys; there is one
-// key in a hardware wallet, derived from the device master
-// secret.  We could, in principle, derive multiple keys.
-// This is not supported at the moment.
+// no way to create new wallets; there is one wallet per
+// device, holding the accounts derived from the device
+// master secret.
 func (lwd *LedgerWalletDriver) CreateWallet(name []byte, id []byte, pw []byte, mdk crypto.MasterDerivationKey) error {
 	return errNotSupported
 }
@@ -73,23 +109,91 @@ func (lwd *LedgerWalletDriver) FetchWallet(id []byte) (w wallet.Wallet, err erro
 
 // InitWithConfig accepts a driver configuration.  Currently, the Ledger
 // driver does not have any configuration parameters.  However, we use
-// this to enumerate the USB devices.
+// this to enumerate the USB devices, and to load the accounts previously
+// added to each of them from the kmd data dir.
 func (lwd *LedgerWalletDriver) InitWithConfig(cfg config.KMDConfig) error {
 	devs, err := LedgerEnumerate()
 	if err != nil {
 		return err
 	}
 
+	lwd.accountsPath = filepath.Join(cfg.DataDir, ledgerAccountsFilename)
+	known, err := lwd.loadAccounts()
+	if err != nil {
+		return err
+	}
+
 	lwd.wallets = make(map[string]*LedgerWallet)
 	for _, dev := range devs {
 		id := dev.USBInfo().Path
-		lwd.wallets[id] = &LedgerWallet{
-			dev: dev,
+		lw := &LedgerWallet{
+			dev:    dev,
+			driver: lwd,
+			addrs:  make(map[uint32]crypto.Digest),
 		}
+		lw.accounts = normalizeLedgerAccounts(known[lw.accountsKey()])
+		lwd.wallets[id] = lw
 	}
 	return nil
 }
 
+// loadAccounts reads the account indices of every known device, keyed by
+// device serial number
+func (lwd *LedgerWalletDriver) loadAccounts() (map[string][]uint32, error) {
+	lwd.accountsMu.Lock()
+	defer lwd.accountsMu.Unlock()
+
+	known := make(map[string][]uint32)
+	data, err := ioutil.ReadFile(lwd.accountsPath)
+	if os.IsNotExist(err) {
+		return known, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &known)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %v", lwd.accountsPath, err)
+	}
+	return known, nil
+}
+
+// storeAccounts records the account indices of one device, keeping those of
+// the other devices in the file
+func (lwd *LedgerWalletDriver) storeAccounts(key string, accounts []uint32) error {
+	known, err := lwd.loadAccounts()
+	if err != nil {
+		return err
+	}
+	known[key] = accounts
+
+	lwd.accountsMu.Lock()
+	defer lwd.accountsMu.Unlock()
+
+	data, err := json.Marshal(known)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(lwd.accountsPath, data, ledgerAccountsPermissions)
+}
+
+// normalizeLedgerAccounts sorts and dedupes account indices, making sure the
+// device's first account is always present
+func normalizeLedgerAccounts(accounts []uint32) []uint32 {
+	seen := map[uint32]bool{0: true}
+	out := []uint32{0}
+	for _, acct := range accounts {
+		if acct > ledgerMaxAccount || seen[acct] {
+			continue
+		}
+		seen[acct] = true
+		out = append(out, acct)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
 // ListWalletMetadatas returns all wallets supported by this driver.
 func (lwd *LedgerWalletDriver) ListWalletMetadatas() (metadatas []wallet.Metadata, err error) {
 	for _, w := range lwd.wallets {
@@ -109,6 +213,17 @@ func (lwd *LedgerWalletDriver) RenameWallet(newName []byte, id []byte, pw []byte
 	return errNotSupported
 }
 
+// accountsKey identifies the device in the accounts file. The USB path
+// changes when the device is plugged into another port, so prefer the
+// serial number.
+func (lw *LedgerWallet) accountsKey() string {
+	info := lw.dev.USBInfo()
+	if info.Serial != "" {
+		return info.Serial
+	}
+	return info.Path
+}
+
 // Init implements the wallet interface.
 func (lw *LedgerWallet) Init(pw []byte) error {
 	return nil
@@ -144,14 +259,75 @@ func (lw *LedgerWallet) ListKeys() ([]crypto.Digest, error) {
 	lw.mu.Lock()
 	defer lw.mu.Unlock()
 
-	reply, err := lw.dev.Exchange([]byte{0x80, 0x03, 0x00, 0x00, 0x00})
+	addrs := make([]crypto.Digest, 0, len(lw.accounts))
+	for _, acct := range lw.accounts {
+		addr, err := lw.addressLocked(acct)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// addressLocked returns the address of a device account, asking the device
+// for its public key the first time
+func (lw *LedgerWallet) addressLocked(acct uint32) (crypto.Digest, error) {
+	if addr, ok := lw.addrs[acct]; ok {
+		return addr, nil
+	}
+
+	var idx [4]byte
+	binary.BigEndian.PutUint32(idx[:], acct)
+	reply, err := lw.dev.Exchange(ledgerAPDU(ledgerInsGetPublicKey, 0x00, 0x00, idx[:]))
 	if err != nil {
-		return nil, err
+		return crypto.Digest{}, err
+	}
+	if len(reply) != len(crypto.Digest{}) {
+		return crypto.Digest{}, fmt.Errorf("unexpected public key length %d from device", len(reply))
 	}
 
 	var addr crypto.Digest
 	copy(addr[:], reply)
-	return []crypto.Digest{addr}, nil
+	lw.addrs[acct] = addr
+	return addr, nil
+}
+
+// accountLocked returns the device account index of a wallet address
+func (lw *LedgerWallet) accountLocked(addr crypto.Digest) (uint32, error) {
+	for _, acct := range lw.accounts {
+		acctAddr, err := lw.addressLocked(acct)
+		if err != nil {
+			return 0, err
+		}
+		if acctAddr == addr {
+			return acct, nil
+		}
+	}
+	return 0, errKeyNotFound
+}
+
+// addAccountLocked adds a device account to the wallet and records it in the
+// accounts file
+func (lw *LedgerWallet) addAccountLocked(acct uint32) (crypto.Digest, error) {
+	for _, known := range lw.accounts {
+		if known == acct {
+			return crypto.Digest{}, errKeyExists
+		}
+	}
+
+	addr, err := lw.addressLocked(acct)
+	if err != nil {
+		return crypto.Digest{}, err
+	}
+
+	accounts := normalizeLedgerAccounts(append(append([]uint32(nil), lw.accounts...), acct))
+	err = lw.driver.storeAccounts(lw.accountsKey(), accounts)
+	if err != nil {
+		return crypto.Digest{}, err
+	}
+	lw.accounts = accounts
+	return addr, nil
 }
 
 // ImportKey implements the Wallet interface.
@@ -164,14 +340,62 @@ func (lw *LedgerWallet) ExportKey(pk crypto.Digest, pw []byte) (crypto.PrivateKe
 	return crypto.PrivateKey{}, errNotSupported
 }
 
-// GenerateKey implements the Wallet interface.
+// GenerateKey adds the device account following the highest one in the
+// wallet. The key itself never leaves the device.
 func (lw *LedgerWallet) GenerateKey(displayMnemonic bool) (crypto.Digest, error) {
-	return crypto.Digest{}, errNotSupported
+	if displayMnemonic {
+		return crypto.Digest{}, errNotSupported
+	}
+
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	highest := lw.accounts[len(lw.accounts)-1]
+	if highest == ledgerMaxAccount {
+		return crypto.Digest{}, errTooManyKeys
+	}
+	return lw.addAccountLocked(highest + 1)
 }
 
-// DeleteKey implements the Wallet interface.
+// DeriveKey adds the device account at index to the wallet. Index 1 is the
+// device's first account, matching the numbering of the sqlite wallet.
+func (lw *LedgerWallet) DeriveKey(index uint64) (crypto.Digest, error) {
+	if index == 0 || index > ledgerMaxAccount+1 {
+		return crypto.Digest{}, errLedgerKeyIndex
+	}
+
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	return lw.addAccountLocked(uint32(index - 1))
+}
+
+// DeleteKey removes a device account from the wallet. The device's first
+// account can't be removed.
 func (lw *LedgerWallet) DeleteKey(pk crypto.Digest, pw []byte) error {
-	return errNotSupported
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	acct, err := lw.accountLocked(pk)
+	if err != nil {
+		return err
+	}
+	if acct == 0 {
+		return errNotSupported
+	}
+
+	var accounts []uint32
+	for _, known := range lw.accounts {
+		if known != acct {
+			accounts = append(accounts, known)
+		}
+	}
+	err = lw.driver.storeAccounts(lw.accountsKey(), accounts)
+	if err != nil {
+		return err
+	}
+	lw.accounts = accounts
+	return nil
 }
 
 // ImportMultisigAddr implements the Wallet interface.
@@ -194,17 +418,28 @@ func (lw *LedgerWallet) DeleteMultisigAddr(addr crypto.Digest, pw []byte) error
 	return errNotSupported
 }
 
-// SignTransaction implements the Wallet interface.
-func (lw *LedgerWallet) SignTransaction(tx transactions.Transaction, pw []byte) ([]byte, error) {
-	sig, err := lw.signTransactionHelper(tx)
+// SignTransaction signs tx on the device with the account of pk, or of the
+// sender if pk is empty. The user has to approve the transaction on the
+// device.
+func (lw *LedgerWallet) SignTransaction(tx transactions.Transaction, pk crypto.PublicKey, pw []byte) ([]byte, error) {
+	signer := tx.Src()
+	if pk != (crypto.PublicKey{}) {
+		signer = basics.Address(pk)
+	}
+
+	sig, err := lw.signTransactionHelper(tx, signer)
 	if err != nil {
 		return nil, err
 	}
 
-	return protocol.Encode(transactions.SignedTxn{
+	stx := transactions.SignedTxn{
 		Txn: tx,
 		Sig: sig,
-	}), nil
+	}
+	if signer != tx.Src() {
+		stx.AuthAddr = signer
+	}
+	return protocol.Encode(stx), nil
 }
 
 // MultisigSignTransaction implements the Wallet interface.
@@ -212,64 +447,77 @@ func (lw *LedgerWallet) MultisigSignTransaction(tx transactions.Transaction, pk
 	return crypto.MultisigSig{}, errNotSupported
 }
 
-func uint64le(i uint64) []byte {
-	var buf [8]byte
-	binary.LittleEndian.PutUint64(buf[:], i)
-	return buf[:]
+// SignData implements the Wallet interface.
+func (lw *LedgerWallet) SignData(data []byte, pk crypto.PublicKey, pw []byte) (crypto.Signature, error) {
+	return crypto.Signature{}, errNotSupported
 }
 
-func (lw *LedgerWallet) signTransactionHelper(tx transactions.Transaction) (sig crypto.Signature, err error) {
-	lw.mu.Lock()
-	defer lw.mu.Unlock()
+// SignProgram implements the Wallet interface.
+func (lw *LedgerWallet) SignProgram(program []byte, pk crypto.PublicKey, pw []byte) (crypto.Signature, error) {
+	return crypto.Signature{}, errNotSupported
+}
 
-	var msg []byte
-	msg = append(msg, 0x80)
+// ledgerAPDU builds a command for the Algorand app
+func ledgerAPDU(ins, p1, p2 byte, data []byte) []byte {
+	apdu := []byte{ledgerCLA, ins, p1, p2, byte(len(data))}
+	return append(apdu, data...)
+}
 
-	switch tx.Type {
-	case protocol.PaymentTx:
-		msg = append(msg, 0x04)
-	case protocol.KeyRegistrationTx:
-		msg = append(msg, 0x05)
-	default:
-		err = fmt.Errorf("transaction type %s not supported", tx.Type)
-		return
-	}
+// ledgerSignChunks splits the msgpack encoding of a transaction into the
+// commands that sign it with account acct. The first command carries the
+// account index.
+func ledgerSignChunks(acct uint32, txBytes []byte) [][]byte {
+	var idx [4]byte
+	binary.BigEndian.PutUint32(idx[:], acct)
+	payload := append(idx[:], txBytes...)
+
+	var apdus [][]byte
+	for offset := 0; offset < len(payload); offset += ledgerChunkSize {
+		end := offset + ledgerChunkSize
+		if end > len(payload) {
+			end = len(payload)
+		}
 
-	if len(tx.Note) != 0 {
-		err = fmt.Errorf("transaction notes not supported")
-		return
+		p1 := byte(ledgerP1More)
+		if offset == 0 {
+			p1 = ledgerP1First | ledgerP1WithAccount
+		}
+		p2 := byte(ledgerP2More)
+		if end == len(payload) {
+			p2 = ledgerP2Last
+		}
+		apdus = append(apdus, ledgerAPDU(ledgerInsSignMsgpack, p1, p2, payload[offset:end]))
 	}
+	return apdus
+}
 
-	msg = append(msg, tx.Sender[:]...)
-	msg = append(msg, uint64le(tx.Fee.Raw)...)
-	msg = append(msg, uint64le(uint64(tx.FirstValid))...)
-	msg = append(msg, uint64le(uint64(tx.LastValid))...)
+func (lw *LedgerWallet) signTransactionHelper(tx transactions.Transaction, signer basics.Address) (sig crypto.Signature, err error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
 
-	var genbuf [32]byte
-	if len(tx.GenesisID) > len(genbuf) {
-		err = fmt.Errorf("genesis ID %s too long (%d)", tx.GenesisID, len(tx.GenesisID))
+	acct, err := lw.accountLocked(crypto.Digest(signer))
+	if err != nil {
 		return
 	}
 
-	copy(genbuf[:], []byte(tx.GenesisID))
-	msg = append(msg, genbuf[:]...)
-	msg = append(msg, tx.GenesisHash[:]...)
-
-	switch tx.Type {
-	case protocol.PaymentTx:
-		msg = append(msg, tx.Receiver[:]...)
-		msg = append(msg, uint64le(tx.Amount.Raw)...)
-		msg = append(msg, tx.CloseRemainderTo[:]...)
-	case protocol.KeyRegistrationTx:
-		msg = append(msg, tx.VotePK[:]...)
-		msg = append(msg, tx.SelectionPK[:]...)
+	var reply []byte
+	for _, apdu := range ledgerSignChunks(acct, protocol.Encode(&tx)) {
+		reply, err = lw.dev.Exchange(apdu)
+		if err != nil {
+			return
+		}
 	}
 
-	reply, err := lw.dev.Exchange(msg)
-	if err != nil {
+	if len(reply) != len(sig) {
+		err = fmt.Errorf("unexpected signature length %d from device", len(reply))
 		return
 	}
-
 	copy(sig[:], reply)
+
+	// Make sure the device signed what we asked it to sign
+	if !crypto.SignatureVerifier(signer).Verify(tx, sig) {
+		err = errLedgerBadSignature
+		return
+	}
 	return
 }
@@ -21,3 +21,5 @@ import (
 )
 
 var errNotSupported = fmt.Errorf("operation not supported by wallet")
+var errLedgerKeyIndex = fmt.Errorf("key index must be between 1 and %d", ledgerMaxAccount+1)
+var errLedgerBadSignature = fmt.Errorf("signature from the device does not verify, was the transaction changed?")
@@ -0,0 +1,50 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package driver
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerSignChunks(t *testing.T) {
+	// A short transaction fits in a single command carrying the account
+	apdus := ledgerSignChunks(3, []byte{0xaa, 0xbb})
+	require.Equal(t, [][]byte{{0x80, 0x08, 0x01, 0x00, 0x06, 0, 0, 0, 3, 0xaa, 0xbb}}, apdus)
+
+	// A long one is split, and only the last command is marked as such
+	tx := bytes.Repeat([]byte{0xcc}, 2*ledgerChunkSize)
+	apdus = ledgerSignChunks(0, tx)
+	require.Len(t, apdus, 3)
+	require.Equal(t, []byte{0x80, 0x08, 0x01, 0x80, ledgerChunkSize}, apdus[0][:5])
+	require.Equal(t, []byte{0x80, 0x08, 0x80, 0x80, ledgerChunkSize}, apdus[1][:5])
+	require.Equal(t, []byte{0x80, 0x08, 0x80, 0x00, 4}, apdus[2][:5])
+
+	var payload []byte
+	for _, apdu := range apdus {
+		require.Equal(t, int(apdu[4]), len(apdu)-5)
+		payload = append(payload, apdu[5:]...)
+	}
+	require.Equal(t, append([]byte{0, 0, 0, 0}, tx...), payload)
+}
+
+func TestNormalizeLedgerAccounts(t *testing.T) {
+	require.Equal(t, []uint32{0}, normalizeLedgerAccounts(nil))
+	require.Equal(t, []uint32{0, 1, 4}, normalizeLedgerAccounts([]uint32{4, 1, 0, 4, ledgerMaxAccount + 1}))
+}
@@ -32,7 +32,9 @@ import (
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/daemon/kmd/config"
 	"github.com/algorand/go-algorand/daemon/kmd/wallet"
+	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-codec/codec"
 )
@@ -50,7 +52,7 @@ const (
 	sqliteWalletHasMasterKey    = true
 )
 
-var sqliteWalletSupportedTxs = []protocol.TxType{protocol.PaymentTx, protocol.KeyRegistrationTx}
+var sqliteWalletSupportedTxs = []protocol.TxType{protocol.PaymentTx, protocol.KeyRegistrationTx, protocol.AssetConfigTx, protocol.AssetTransferTx, protocol.AssetFreezeTx}
 var disallowedFilenameRegex = regexp.MustCompile("[^a-zA-Z0-9_-]*")
 var databaseFilenameRegex = regexp.MustCompile("^.*\\.db$")
 
@@ -855,10 +857,92 @@ func (sw *SQLiteWallet) GenerateKey(displayMnemonic bool) (addr crypto.Digest, e
 	return addr, nil
 }
 
-// generateKeyTxLocked is a helper for GenerateKey that accepts a locked tx,
-// computes the next key that should be generated, inserts it, and returns
-// its address
-func (sw *SQLiteWallet) generateKeyTxLocked(tx *sqlx.Tx) (addr crypto.Digest, err error) {
+// DeriveKey derives the key at the passed index of the deterministic key
+// sequence (as determined by the master derivation key) and adds it to the
+// wallet. Indices start at 1, matching the keys produced by GenerateKey
+func (sw *SQLiteWallet) DeriveKey(index uint64) (addr crypto.Digest, err error) {
+	if index == 0 || index >= sqliteIntOverflow {
+		err = errInvalidKeyIndex
+		return
+	}
+
+	// Connect to the database
+	db, err := sqlx.Connect("sqlite3", dbConnectionURL(sw.dbPath))
+	if err != nil {
+		err = errDatabaseConnect
+		return
+	}
+	defer db.Close()
+
+	// Begin an exclusive database transaction (we set _tx_lock=exclusive on the
+	// database connection string)
+	tx, err := db.Beginx()
+	if err != nil {
+		err = errDatabase
+		return
+	}
+
+	// Derive and insert the key
+	addr, err = sw.deriveKeyTxLocked(tx, index)
+	if err != nil {
+		// Rollback in case any part of the tx failed
+		tx.Rollback()
+		return
+	}
+
+	// Commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		err = errDatabase
+		return
+	}
+
+	return addr, nil
+}
+
+// deriveKeyTxLocked is a helper for DeriveKey that accepts a locked tx,
+// computes the key at index, inserts it, and bumps the highest index so that
+// GenerateKey continues after it
+func (sw *SQLiteWallet) deriveKeyTxLocked(tx *sqlx.Tx, index uint64) (addr crypto.Digest, err error) {
+	highestIndex, err := sw.fetchMaxKeyIndexTxLocked(tx)
+	if err != nil {
+		return
+	}
+
+	// Compute the secret key and public key for index
+	genPK, genSK, err := extractKeyWithIndex(sw.masterDerivationKey, index)
+	if err != nil {
+		return
+	}
+	genAddr := publicKeyToAddress(genPK)
+
+	// Encrypt the encoded secret key
+	skEncrypted, err := encryptBlobWithKey(msgpackEncode(genSK), PTSecretKey, sw.masterEncryptionKey)
+	if err != nil {
+		return
+	}
+
+	// Insert the key into the database. This fails with errKeyExists if the
+	// key was already generated, derived or imported
+	_, err = tx.Exec("INSERT INTO keys (address, secret_key_encrypted, key_idx) VALUES(?, ?, ?)", genAddr[:], skEncrypted, index)
+	err = checkDBError(err)
+	if err != nil {
+		return
+	}
+
+	if index > highestIndex {
+		err = sw.storeMaxKeyIndexTxLocked(tx, index)
+		if err != nil {
+			return
+		}
+	}
+
+	return genAddr, nil
+}
+
+// fetchMaxKeyIndexTxLocked decrypts and returns the highest key index that
+// has been generated in this wallet
+func (sw *SQLiteWallet) fetchMaxKeyIndexTxLocked(tx *sqlx.Tx) (highestIndex uint64, err error) {
 	// Fetch the encrypted highest index
 	var encryptedHighestIndexBlob []byte
 	err = tx.Get(&encryptedHighestIndexBlob, "SELECT max_key_idx_encrypted FROM metadata LIMIT 1")
@@ -874,8 +958,29 @@ func (sw *SQLiteWallet) generateKeyTxLocked(tx *sqlx.Tx) (addr crypto.Digest, er
 	}
 
 	// Decode the highest index
-	var highestIndex uint64
 	err = msgpackDecode(highestIndexBlob, &highestIndex)
+	return
+}
+
+// storeMaxKeyIndexTxLocked encrypts and stores the highest key index that has
+// been generated in this wallet
+func (sw *SQLiteWallet) storeMaxKeyIndexTxLocked(tx *sqlx.Tx, index uint64) error {
+	// Encrypt the new max key index
+	encryptedIdxBlob, err := encryptBlobWithKey(msgpackEncode(index), PTMaxKeyIdx, sw.masterEncryptionKey)
+	if err != nil {
+		return err
+	}
+
+	// Update the metadata row
+	_, err = tx.Exec("UPDATE metadata SET max_key_idx_encrypted = ?", encryptedIdxBlob)
+	return err
+}
+
+// generateKeyTxLocked is a helper for GenerateKey that accepts a locked tx,
+// computes the next key that should be generated, inserts it, and returns
+// its address
+func (sw *SQLiteWallet) generateKeyTxLocked(tx *sqlx.Tx) (addr crypto.Digest, err error) {
+	highestIndex, err := sw.fetchMaxKeyIndexTxLocked(tx)
 	if err != nil {
 		return
 	}
@@ -935,14 +1040,7 @@ func (sw *SQLiteWallet) generateKeyTxLocked(tx *sqlx.Tx) (addr crypto.Digest, er
 		return
 	}
 
-	// Encrypt the new max key index
-	encryptedIdxBlob, err := encryptBlobWithKey(msgpackEncode(nextIndex), PTMaxKeyIdx, sw.masterEncryptionKey)
-	if err != nil {
-		return
-	}
-
-	// Update the metadata row
-	_, err = tx.Exec("UPDATE metadata SET max_key_idx_encrypted = ?", encryptedIdxBlob)
+	err = sw.storeMaxKeyIndexTxLocked(tx, nextIndex)
 	if err != nil {
 		return
 	}
@@ -1085,9 +1183,10 @@ func (sw *SQLiteWallet) ListMultisigAddrs() (addrs []crypto.Digest, err error) {
 	return
 }
 
-// SignTransaction signs the passed transaction, inferring the required private
-// key from the transaction itself
-func (sw *SQLiteWallet) SignTransaction(tx transactions.Transaction, pw []byte) (stx []byte, err error) {
+// SignTransaction signs the passed transaction with the private key of pk, or
+// infers the required private key from the transaction itself if pk is zero.
+// Signing with a key other than the sender's is how a rekeyed account spends.
+func (sw *SQLiteWallet) SignTransaction(tx transactions.Transaction, pk crypto.PublicKey, pw []byte) (stx []byte, err error) {
 	// Check the password
 	err = sw.CheckPassword(pw)
 	if err != nil {
@@ -1095,7 +1194,11 @@ func (sw *SQLiteWallet) SignTransaction(tx transactions.Transaction, pw []byte)
 	}
 
 	// Fetch the required key
-	sk, err := sw.fetchSecretKey(crypto.Digest(tx.Src()))
+	signer := tx.Src()
+	if pk != (crypto.PublicKey{}) {
+		signer = basics.Address(pk)
+	}
+	sk, err := sw.fetchSecretKey(crypto.Digest(signer))
 	if err != nil {
 		return
 	}
@@ -1108,7 +1211,65 @@ func (sw *SQLiteWallet) SignTransaction(tx transactions.Transaction, pw []byte)
 	}
 
 	// Sign the transaction
-	stx = protocol.Encode(tx.Sign(secrets))
+	signed := tx.Sign(secrets)
+	if signer != tx.Src() {
+		signed.AuthAddr = signer
+	}
+	stx = protocol.Encode(signed)
+	return
+}
+
+// SignData signs data with the private key of pk. The signature is made with
+// crypto.SignatureSecrets.SignBytes, so it can't be passed off as the
+// signature of a transaction.
+func (sw *SQLiteWallet) SignData(data []byte, pk crypto.PublicKey, pw []byte) (sig crypto.Signature, err error) {
+	// Check the password
+	err = sw.CheckPassword(pw)
+	if err != nil {
+		return
+	}
+
+	// Fetch the required key
+	sk, err := sw.fetchSecretKey(publicKeyToAddress(pk))
+	if err != nil {
+		return
+	}
+
+	// Generate the signature secrets
+	secrets, err := crypto.SecretKeyToSignatureSecrets(sk)
+	if err != nil {
+		err = errSKToPK
+		return
+	}
+
+	sig = secrets.SignBytes(data)
+	return
+}
+
+// SignProgram signs a TEAL program with the private key of pk, delegating
+// to the program the authority to approve transactions from pk's account
+// through a LogicSig
+func (sw *SQLiteWallet) SignProgram(program []byte, pk crypto.PublicKey, pw []byte) (sig crypto.Signature, err error) {
+	// Check the password
+	err = sw.CheckPassword(pw)
+	if err != nil {
+		return
+	}
+
+	// Fetch the required key
+	sk, err := sw.fetchSecretKey(publicKeyToAddress(pk))
+	if err != nil {
+		return
+	}
+
+	// Generate the signature secrets
+	secrets, err := crypto.SecretKeyToSignatureSecrets(sk)
+	if err != nil {
+		err = errSKToPK
+		return
+	}
+
+	sig = secrets.Sign(logic.Program(program))
 	return
 }
 
@@ -32,6 +32,7 @@ var errDeriveKey = fmt.Errorf("scrypt lib could not derive key from password")
 var errWrongDriver = fmt.Errorf("found database with wrong driver name in wallets dir")
 var errRandBytes = fmt.Errorf("error reading random bytes")
 var errTooManyKeys = fmt.Errorf("too many keys")
+var errInvalidKeyIndex = fmt.Errorf("key index must be between 1 and %d", uint64(sqliteIntOverflow-1))
 var errWrongDriverVer = fmt.Errorf("found database with wrong driver version in wallets dir")
 var errDecrypt = fmt.Errorf("error decrypting. wrong password?")
 var errTypeMismatch = fmt.Errorf("error decrypting, found the wrong type of data. something fishy is going on with this wallet")
@@ -44,6 +44,7 @@ type Wallet interface {
 	ImportKey(sk crypto.PrivateKey) (crypto.Digest, error)
 	ExportKey(pk crypto.Digest, pw []byte) (crypto.PrivateKey, error)
 	GenerateKey(displayMnemonic bool) (crypto.Digest, error)
+	DeriveKey(index uint64) (crypto.Digest, error)
 	DeleteKey(pk crypto.Digest, pw []byte) error
 
 	ImportMultisigAddr(version, threshold uint8, pks []crypto.PublicKey) (crypto.Digest, error)
@@ -51,9 +52,13 @@ type Wallet interface {
 	ListMultisigAddrs() (addrs []crypto.Digest, err error)
 	DeleteMultisigAddr(addr crypto.Digest, pw []byte) error
 
-	SignTransaction(tx transactions.Transaction, pw []byte) ([]byte, error)
+	SignTransaction(tx transactions.Transaction, pk crypto.PublicKey, pw []byte) ([]byte, error)
 
 	MultisigSignTransaction(tx transactions.Transaction, pk crypto.PublicKey, partial crypto.MultisigSig, pw []byte) (crypto.MultisigSig, error)
+
+	SignData(data []byte, pk crypto.PublicKey, pw []byte) (crypto.Signature, error)
+
+	SignProgram(program []byte, pk crypto.PublicKey, pw []byte) (crypto.Signature, error)
 }
 
 // Metadata represents high-level information about a wallet, like its name, id
@@ -19,12 +19,13 @@ package account
 import (
 	"database/sql"
 	"fmt"
-	"github.com/algorand/go-algorand/logging"
+	"runtime"
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/util/db"
 )
@@ -147,6 +148,13 @@ func (part Participation) GenerateRegistrationTransaction(fee basics.MicroAlgos,
 
 // FillDBWithParticipationKeys initializes the passed database with participation keys
 func FillDBWithParticipationKeys(store db.Accessor, address basics.Address, firstValid, lastValid basics.Round, keyDilution uint64) (part Participation, err error) {
+	return FillDBWithParticipationKeysProgress(store, address, firstValid, lastValid, keyDilution, nil)
+}
+
+// FillDBWithParticipationKeysProgress is a version of FillDBWithParticipationKeys
+// that reports the progress of the key generation to progress, if it isn't nil.
+// The keys are generated on all the cores.
+func FillDBWithParticipationKeysProgress(store db.Accessor, address basics.Address, firstValid, lastValid basics.Round, keyDilution uint64, progress crypto.KeyGenProgress) (part Participation, err error) {
 	if lastValid < firstValid {
 		err = fmt.Errorf("FillDBWithParticipationKeys: lastValid %d is after firstValid %d", lastValid, firstValid)
 		return
@@ -158,7 +166,7 @@ func FillDBWithParticipationKeys(store db.Accessor, address basics.Address, firs
 	numBatches := lastID.Batch - firstID.Batch + 1
 
 	// Generate them
-	v := crypto.GenerateOneTimeSignatureSecrets(firstID.Batch, numBatches)
+	v := crypto.GenerateOneTimeSignatureSecretsParallel(firstID.Batch, numBatches, runtime.NumCPU(), progress)
 
 	// Also generate a new VRF key, which lives in the participation keys db
 	vrf := crypto.GenerateVRFSecrets()
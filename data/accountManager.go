@@ -117,6 +117,21 @@ func (manager *AccountManager) AddParticipation(participation account.Participat
 	return true
 }
 
+// RemoveParticipation stops managing the account.Participation valid for the given interval, closing its database.
+// The return value indicates if such a key was managed.
+func (manager *AccountManager) RemoveParticipation(interval account.ParticipationInterval) bool {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	part, has := manager.partIntervals[interval]
+	if !has {
+		return false
+	}
+	delete(manager.partIntervals, interval)
+	part.Close()
+	return true
+}
+
 // DeleteOldKeys deletes all accounts' ephemeral keys strictly older than the
 // current round.
 func (manager *AccountManager) DeleteOldKeys(current basics.Round, proto config.ConsensusParams) {
@@ -17,6 +17,9 @@
 package basics
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/logging"
@@ -103,6 +106,100 @@ type AccountData struct {
 	VoteFirstValid  Round  `codec:"voteFst"`
 	VoteLastValid   Round  `codec:"voteLst"`
 	VoteKeyDilution uint64 `codec:"voteKD"`
+
+	// If this account has been rekeyed, AuthAddr is the address of the
+	// key that is authorized to spend from it. It is the zero address
+	// when the account's own key is.
+	AuthAddr Address `codec:"spend"`
+
+	// AssetParams lists the assets created by this account, by index,
+	// along with their parameters.  An asset is identified by the
+	// address of the account that created it and by its index.
+	AssetParams map[AssetIndex]AssetParams `codec:"apar"`
+
+	// Assets lists the assets this account holds, whether it created
+	// them or accepted them, along with their amount.
+	Assets map[AssetID]AssetHolding `codec:"asset"`
+}
+
+// AssetIndex is the index of an asset.  Indices are allocated from the
+// ledger's transaction counter, so no two assets ever share an index.
+type AssetIndex uint64
+
+// AssetID names an asset by the account that created it and its index.
+type AssetID struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Creator Address    `codec:"c"`
+	Index   AssetIndex `codec:"i"`
+}
+
+// String returns a human-readable form of the asset ID.
+func (id AssetID) String() string {
+	return fmt.Sprintf("%d/%v", id.Index, id.Creator)
+}
+
+// AssetHolding describes an asset held by an account.
+type AssetHolding struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Amount uint64 `codec:"a"`
+
+	// Frozen holdings can be neither sent nor received, except by the
+	// clawback address of the asset.
+	Frozen bool `codec:"f"`
+}
+
+// AssetParams describes the parameters of an asset.
+type AssetParams struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	// Total specifies the total number of units of this asset
+	// created.
+	Total uint64 `codec:"t"`
+
+	// DefaultFrozen specifies whether slots for this asset
+	// in user accounts are frozen by default or not.
+	DefaultFrozen bool `codec:"df"`
+
+	// UnitName specifies a hint for the name of a unit of
+	// this asset.
+	UnitName string `codec:"un"`
+
+	// AssetName specifies a hint for the name of the asset.
+	AssetName string `codec:"an"`
+
+	// URL specifies a URL where more information about the asset can be
+	// retrieved
+	URL string `codec:"au"`
+
+	// Manager specifies an account that is allowed to change the
+	// non-zero addresses in this AssetParams, and to destroy the asset.
+	Manager Address `codec:"m"`
+
+	// Reserve specifies an account whose holdings of this asset
+	// should be reported as "not minted".
+	Reserve Address `codec:"r"`
+
+	// Freeze specifies an account that is allowed to change the
+	// frozen state of holdings of this asset.
+	Freeze Address `codec:"f"`
+
+	// Clawback specifies an account that is allowed to take units
+	// of this asset from any account.
+	Clawback Address `codec:"c"`
+}
+
+// IsZero checks if an AccountData value is the same as its zero value.
+// Empty asset maps count as zero, as they encode the same way.
+func (u AccountData) IsZero() bool {
+	if len(u.AssetParams) == 0 {
+		u.AssetParams = nil
+	}
+	if len(u.Assets) == 0 {
+		u.Assets = nil
+	}
+	return reflect.DeepEqual(u, AccountData{})
 }
 
 // AccountDetail encapsulates meaningful details about a given account, for external consumption
@@ -138,6 +235,12 @@ func (u AccountData) Money(proto config.ConsensusParams, rewardsLevel uint64) (m
 	return e.MicroAlgos, e.RewardedMicroAlgos
 }
 
+// MinBalance returns the minimum balance of the account: the MinBalance of
+// the protocol, raised by MinBalancePerAsset for every asset it holds.
+func (u AccountData) MinBalance(proto config.ConsensusParams) MicroAlgos {
+	return MicroAlgos{Raw: proto.MinBalance + uint64(len(u.Assets))*proto.MinBalancePerAsset}
+}
+
 // WithUpdatedRewards returns an updated number of algos in an AccountData
 // to reflect rewards up to some rewards level.
 func (u AccountData) WithUpdatedRewards(proto config.ConsensusParams, rewardsLevel uint64) AccountData {
@@ -61,6 +61,15 @@ type (
 		// Genesis hash to which this block belongs.
 		GenesisHash crypto.Digest `codec:"gh"`
 
+		// TxnCounter counts the number of transactions committed in the
+		// ledger, from the time this feature was introduced.
+		//
+		// Specifically, TxnCounter is the number of the next transaction
+		// that will be committed after this block.  It is 0 when no
+		// transactions have ever been committed (since TxnCounter
+		// started being supported).
+		TxnCounter uint64 `codec:"tc"`
+
 		// Rewards.
 		//
 		// When a block is applied, some amount of rewards are accrued to
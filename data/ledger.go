@@ -198,17 +198,24 @@ func (l *Ledger) BalanceRecord(r basics.Round, addr basics.Address) (basics.Bala
 // BalanceAndStatus returns Balance and DelegationStatus as one call
 func (l *Ledger) BalanceAndStatus(addr basics.Address) (money basics.MicroAlgos, rewards basics.MicroAlgos, moneyWithoutPendingRewards basics.MicroAlgos, status basics.Status, latest basics.Round, err error) {
 	latest = l.Latest()
-	data, err := l.Lookup(latest, addr)
+	money, rewards, moneyWithoutPendingRewards, status, err = l.BalanceAndStatusAtRound(addr, latest)
+	return
+}
+
+// BalanceAndStatusAtRound returns Balance and DelegationStatus as of rnd, which must be one of the recent rounds
+// whose account state the ledger still holds
+func (l *Ledger) BalanceAndStatusAtRound(addr basics.Address, rnd basics.Round) (money basics.MicroAlgos, rewards basics.MicroAlgos, moneyWithoutPendingRewards basics.MicroAlgos, status basics.Status, err error) {
+	data, err := l.Lookup(rnd, addr)
 	if err != nil {
 		return
 	}
 
-	totals, err := l.Totals(latest)
+	totals, err := l.Totals(rnd)
 	if err != nil {
 		return
 	}
 
-	hdr, err := l.BlockHdr(latest)
+	hdr, err := l.BlockHdr(rnd)
 	if err != nil {
 		return
 	}
@@ -220,7 +227,7 @@ func (l *Ledger) BalanceAndStatus(addr basics.Address) (money basics.MicroAlgos,
 	money, rewards = data.Money(proto, totals.RewardsLevel)
 	status = data.Status
 
-	dataWithoutRewards, err := l.LookupWithoutRewards(latest, addr)
+	dataWithoutRewards, err := l.LookupWithoutRewards(rnd, addr)
 	if err != nil {
 		return
 	}
@@ -352,6 +359,7 @@ func (*Ledger) AssemblePayset(pool *pools.TransactionPool, eval *ledger.BlockEva
 	stats.StopReason = telemetryspec.AssembleBlockEmpty
 	first := true
 	totalFees := uint64(0)
+	assembledGroups := make(map[crypto.Digest]bool)
 
 	for true {
 		txn := pheap.Next()
@@ -363,7 +371,20 @@ func (*Ledger) AssemblePayset(pool *pools.TransactionPool, eval *ledger.BlockEva
 			break
 		}
 
-		err := eval.Transaction(*txn, nil)
+		txgroup := []transactions.SignedTxn{*txn}
+		if group := txn.Txn.Group; group != (crypto.Digest{}) {
+			// The whole group is added when the first of its transactions comes up
+			if assembledGroups[group] {
+				continue
+			}
+			assembledGroups[group] = true
+			txgroup = pool.PendingGroup(group)
+			if txgroup == nil {
+				continue
+			}
+		}
+
+		err := eval.TransactionGroup(txgroup, nil)
 		if err == ledger.ErrNoSpace {
 			stats.StopReason = telemetryspec.AssembleBlockFull
 			break
@@ -378,7 +399,7 @@ func (*Ledger) AssemblePayset(pool *pools.TransactionPool, eval *ledger.BlockEva
 			case ledger.TransactionInLedgerError:
 				logAt = logging.Base().Debug
 				stats.CommittedCount++
-			case transactions.MinFeeError:
+			case transactions.MinFeeError, ledger.LeaseInLedgerError:
 				logAt = logging.Base().Info
 				pool.Remove(txn.ID(), err)
 				stats.InvalidCount++
@@ -390,39 +411,41 @@ func (*Ledger) AssemblePayset(pool *pools.TransactionPool, eval *ledger.BlockEva
 
 			logAt(msg)
 		} else {
-			fee := txn.Txn.Fee.Raw
-			encodedLen := txn.GetEncodedLength()
-			priority := uint64(txn.PtrPriority())
-
-			stats.IncludedCount++
-			totalFees += fee
-
-			if first {
-				first = false
-				stats.MinFee = fee
-				stats.MaxFee = fee
-				stats.MinLength = encodedLen
-				stats.MaxLength = encodedLen
-				stats.MinPriority = priority
-				stats.MaxPriority = priority
-			} else {
-				if fee < stats.MinFee {
+			for _, txn := range txgroup {
+				fee := txn.Txn.Fee.Raw
+				encodedLen := txn.GetEncodedLength()
+				priority := uint64(txn.PtrPriority())
+
+				stats.IncludedCount++
+				totalFees += fee
+
+				if first {
+					first = false
 					stats.MinFee = fee
-				} else if fee > stats.MaxFee {
 					stats.MaxFee = fee
-				}
-				if encodedLen < stats.MinLength {
 					stats.MinLength = encodedLen
-				} else if encodedLen > stats.MaxLength {
 					stats.MaxLength = encodedLen
-				}
-				if priority < stats.MinPriority {
 					stats.MinPriority = priority
-				} else if priority > stats.MaxPriority {
 					stats.MaxPriority = priority
+				} else {
+					if fee < stats.MinFee {
+						stats.MinFee = fee
+					} else if fee > stats.MaxFee {
+						stats.MaxFee = fee
+					}
+					if encodedLen < stats.MinLength {
+						stats.MinLength = encodedLen
+					} else if encodedLen > stats.MaxLength {
+						stats.MaxLength = encodedLen
+					}
+					if priority < stats.MinPriority {
+						stats.MinPriority = priority
+					} else if priority > stats.MaxPriority {
+						stats.MaxPriority = priority
+					}
 				}
+				stats.TotalLength += uint64(encodedLen)
 			}
-			stats.TotalLength += uint64(encodedLen)
 		}
 
 		if stats.IncludedCount != 0 {
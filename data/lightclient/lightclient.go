@@ -0,0 +1,94 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package lightclient verifies the blocks and transactions served by untrusted nodes against a block header the
+// client trusts, without running a node. The client gets the hash of a recent block from a source it trusts, and
+// can then verify the header of that block, the headers of all the earlier blocks, and the transactions of any of
+// these blocks, whichever node serves them.
+//
+// Block headers only commit to the transactions of their block, through TxnRoot, and to the previous block, through
+// Branch. They don't commit to the account balances, so balances can't be verified without the ledger. Since the
+// transaction commitment is a hash of the whole payset, verifying a transaction takes its whole block.
+package lightclient
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+)
+
+// ErrTransactionNotFound is returned by FindTransaction when a verified block doesn't have the transaction
+var ErrTransactionNotFound = errors.New("the block doesn't have the transaction")
+
+// VerifyHeader checks that hdr is the header of the block with hash trusted
+func VerifyHeader(trusted bookkeeping.BlockHash, hdr bookkeeping.BlockHeader) error {
+	if hdr.Hash() != trusted {
+		return fmt.Errorf("the header of round %d has hash %v rather than the trusted %v", hdr.Round, hdr.Hash(), trusted)
+	}
+	return nil
+}
+
+// VerifyAncestors checks that ancestors are the headers of the blocks preceding the one of the verified header hdr,
+// from the most recent to the oldest, so that they can be trusted as well.
+func VerifyAncestors(hdr bookkeeping.BlockHeader, ancestors []bookkeeping.BlockHeader) error {
+	for _, ancestor := range ancestors {
+		if ancestor.Round+1 != hdr.Round {
+			return fmt.Errorf("the header of round %d doesn't precede the one of round %d", ancestor.Round, hdr.Round)
+		}
+		if hdr.Branch != ancestor.Hash() {
+			return fmt.Errorf("the header of round %d isn't the one the header of round %d follows", ancestor.Round, hdr.Round)
+		}
+		hdr = ancestor
+	}
+	return nil
+}
+
+// VerifyBlock checks that block is the block of the verified header hdr, transactions included
+func VerifyBlock(hdr bookkeeping.BlockHeader, block bookkeeping.Block) error {
+	if block.BlockHeader.Hash() != hdr.Hash() {
+		return fmt.Errorf("the block of round %d doesn't have the verified header", block.Round())
+	}
+	if _, ok := config.Consensus[hdr.CurrentProtocol]; !ok {
+		return fmt.Errorf("the block of round %d has the unsupported protocol %s", hdr.Round, hdr.CurrentProtocol)
+	}
+	if !block.ContentsMatchHeader() {
+		return fmt.Errorf("the transactions of the block of round %d don't match its header", hdr.Round)
+	}
+	return nil
+}
+
+// FindTransaction verifies block against the verified header hdr, and returns the transaction txid of the block,
+// with the effects it had. Since the block is verified, ErrTransactionNotFound proves that the transaction isn't
+// in the block.
+func FindTransaction(hdr bookkeeping.BlockHeader, block bookkeeping.Block, txid transactions.Txid) (transactions.SignedTxnWithAD, error) {
+	err := VerifyBlock(hdr, block)
+	if err != nil {
+		return transactions.SignedTxnWithAD{}, err
+	}
+	payset, err := block.DecodePaysetWithAD()
+	if err != nil {
+		return transactions.SignedTxnWithAD{}, err
+	}
+	for _, txn := range payset {
+		if txn.ID() == txid {
+			return txn, nil
+		}
+	}
+	return transactions.SignedTxnWithAD{}, ErrTransactionNotFound
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package lightclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var genesisHash = crypto.Hash([]byte("lightclient test"))
+
+func makeTxn(amount uint64) transactions.SignedTxn {
+	return transactions.SignedTxn{
+		Txn: transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Fee:         basics.MicroAlgos{Raw: 1000},
+				FirstValid:  1,
+				LastValid:   1000,
+				GenesisHash: genesisHash,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Amount: basics.MicroAlgos{Raw: amount},
+			},
+		},
+	}
+}
+
+// makeChain returns blocks of rounds 1 to n, the one of round i holding a payment of i microAlgos
+func makeChain(t *testing.T, n int) []bookkeeping.Block {
+	var blocks []bookkeeping.Block
+	prev := bookkeeping.BlockHeader{Round: 0, UpgradeState: bookkeeping.UpgradeState{CurrentProtocol: protocol.ConsensusCurrentVersion}}
+	for i := 1; i <= n; i++ {
+		block := bookkeeping.Block{BlockHeader: bookkeeping.BlockHeader{
+			Round:        basics.Round(i),
+			Branch:       prev.Hash(),
+			GenesisHash:  genesisHash,
+			UpgradeState: prev.UpgradeState,
+		}}
+		stib, err := block.EncodeSignedTxn(makeTxn(uint64(i)), transactions.ApplyData{})
+		require.NoError(t, err)
+		block.Payset = transactions.Payset{stib}
+		block.TxnRoot = block.Payset.Commit(config.Consensus[protocol.ConsensusCurrentVersion].PaysetCommitFlat)
+		blocks = append(blocks, block)
+		prev = block.BlockHeader
+	}
+	return blocks
+}
+
+func TestVerifyHeaders(t *testing.T) {
+	blocks := makeChain(t, 4)
+	trusted := blocks[3].BlockHeader
+	require.NoError(t, VerifyHeader(trusted.Hash(), trusted))
+	require.Error(t, VerifyHeader(trusted.Hash(), blocks[2].BlockHeader))
+
+	ancestors := []bookkeeping.BlockHeader{blocks[2].BlockHeader, blocks[1].BlockHeader, blocks[0].BlockHeader}
+	require.NoError(t, VerifyAncestors(trusted, ancestors))
+	require.Error(t, VerifyAncestors(trusted, ancestors[1:]), "a header is missing")
+
+	forged := blocks[1].BlockHeader
+	forged.TimeStamp++
+	require.Error(t, VerifyAncestors(trusted, []bookkeeping.BlockHeader{blocks[2].BlockHeader, forged}))
+}
+
+func TestFindTransaction(t *testing.T) {
+	blocks := makeChain(t, 2)
+	hdr := blocks[1].BlockHeader
+	txid := makeTxn(2).ID()
+
+	txn, err := FindTransaction(hdr, blocks[1], txid)
+	require.NoError(t, err)
+	require.Equal(t, txid, txn.ID())
+
+	_, err = FindTransaction(hdr, blocks[1], makeTxn(3).ID())
+	require.Equal(t, ErrTransactionNotFound, err)
+
+	_, err = FindTransaction(hdr, blocks[0], txid)
+	require.Error(t, err, "the block isn't the one of the header")
+
+	// a node can't add a transaction the header doesn't commit to
+	forged := blocks[1]
+	stib, err := forged.EncodeSignedTxn(makeTxn(3), transactions.ApplyData{})
+	require.NoError(t, err)
+	forged.Payset = append(transactions.Payset{stib}, forged.Payset...)
+	_, err = FindTransaction(hdr, forged, makeTxn(3).ID())
+	require.Error(t, err)
+	require.NotEqual(t, ErrTransactionNotFound, err)
+}
@@ -24,18 +24,26 @@ import (
 	"github.com/algorand/go-deadlock"
 
 	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/logging/telemetryspec"
+	"github.com/algorand/go-algorand/util/metrics"
 )
 
+var transactionPoolRememberedTotal = metrics.MakeCounter(metrics.TransactionPoolRememberedTotal)
+var transactionPoolRejectedTotal = metrics.MakeCounter(metrics.TransactionPoolRejectedTotal)
+var transactionPoolEvictedTotal = metrics.MakeCounter(metrics.TransactionPoolEvictedTotal)
+
 // Ledger allows retrieving the amount of spendable MicroAlgos
 // and also checking if a transaction has already been committed.
 type Ledger interface {
 	BalanceAndStatus(basics.Address) (basics.MicroAlgos, basics.MicroAlgos, basics.MicroAlgos, basics.Status, basics.Round, error)
 	Committed(transactions.SignedTxn) (bool, error)
+	Leased(transactions.SignedTxn) (bool, error)
+	Lookup(basics.Round, basics.Address) (basics.AccountData, error)
 	ConsensusParams(basics.Round) (config.ConsensusParams, error)
 	BlockHdr(rnd basics.Round) (blk bookkeeping.BlockHeader, err error)
 	LastRound() basics.Round
@@ -47,6 +55,8 @@ type TransactionPool struct {
 	mu                              deadlock.RWMutex
 	txPriorityQueue                 *txPriorityQueue
 	pendingTxns                     map[transactions.Txid]transactions.SignedTxn // note: digests do not include signatures to reduce spam
+	pendingGroups                   map[crypto.Digest][]transactions.Txid        // the transactions of each pending group, in order
+	pendingLeases                   map[transactions.Txlease]transactions.Txid   // the pending transaction holding each lease
 	expiredTxCount                  map[basics.Round]int
 	exponentialPriorityGrowthFactor uint64
 	algosPendingSpend               accountsToPendingTransactions
@@ -54,19 +64,25 @@ type TransactionPool struct {
 	statusCache                     *statusCache
 	logStats                        bool
 	size                            int
+	maxPendingPerSender             int
+	pendingBytes                    int
+	log                             logging.Logger
 }
 
 // MakeTransactionPool is the constructor, it uses Ledger to ensure that no account has pending transactions that together overspend.
 // The pool can contain up to transactionPoolSize transactions.
 // When the transaction pool is full, the priority of a new transaction must be at least exponentialPriorityGrowthFactor
 // times greater than the minimum-priority of a transaction already in the pool (otherwise the new transaction is discarded).
+// A single sender can have up to maxPendingPerSender transactions pending in the pool, unless maxPendingPerSender is 0.
 //
 // The pool also contains status information for the last transactionPoolSize
 // transactions that were removed from the pool without being committed.
-func MakeTransactionPool(ledger Ledger, exponentialPriorityGrowthFactor uint64, transactionPoolSize int, logStats bool) *TransactionPool {
+func MakeTransactionPool(ledger Ledger, exponentialPriorityGrowthFactor uint64, transactionPoolSize int, maxPendingPerSender int, logStats bool) *TransactionPool {
 	pool := TransactionPool{
 		txPriorityQueue:                 makeTxPriorityQueue(transactionPoolSize),
 		pendingTxns:                     make(map[transactions.Txid]transactions.SignedTxn),
+		pendingGroups:                   make(map[crypto.Digest][]transactions.Txid),
+		pendingLeases:                   make(map[transactions.Txlease]transactions.Txid),
 		expiredTxCount:                  make(map[basics.Round]int),
 		exponentialPriorityGrowthFactor: exponentialPriorityGrowthFactor,
 		algosPendingSpend:               make(map[basics.Address]pendingTransactions),
@@ -74,10 +90,21 @@ func MakeTransactionPool(ledger Ledger, exponentialPriorityGrowthFactor uint64,
 		statusCache:                     makeStatusCache(transactionPoolSize),
 		logStats:                        logStats,
 		size:                            transactionPoolSize,
+		maxPendingPerSender:             maxPendingPerSender,
+		log:                             logging.Base().WithSubsystem(logging.TxPoolSubsystem),
 	}
 	return &pool
 }
 
+// Reconfigure changes the per-sender pending transactions limit and whether block statistics are logged.
+// Senders that are already above a lowered limit keep their pending transactions, but can't add new ones.
+func (pool *TransactionPool) Reconfigure(maxPendingPerSender int, logStats bool) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.maxPendingPerSender = maxPendingPerSender
+	pool.logStats = logStats
+}
+
 // TODO I moved this number to be a constant in the module, we should consider putting it in the local config
 const expiredHistory = 10
 
@@ -103,31 +130,63 @@ func (pool *TransactionPool) PendingTxIDs() []transactions.Txid {
 	return ids
 }
 
-// Pending returns an array of transactions valid for the given round, sorted by priority in decreasing order
+// Pending returns an array of transactions valid for the given round, sorted by priority in decreasing order,
+// and in arrival order among the transactions of equal priority.
 // If no txns, returns empty slice.
 func (pool *TransactionPool) Pending() []transactions.SignedTxn {
 	pool.mu.Lock()
 
-	txns := make([]transactions.SignedTxn, len(pool.pendingTxns))
-	sorti := make([]int, len(pool.pendingTxns))
-	i := 0
-	for _, txn := range pool.pendingTxns {
-		txns[i] = txn
-		sorti[i] = i
-		i++
+	pending := pendingByPriority{
+		txns:     make([]transactions.SignedTxn, 0, len(pool.pendingTxns)),
+		arrivals: make([]uint64, 0, len(pool.pendingTxns)),
+	}
+	for txid, txn := range pool.pendingTxns {
+		seq, _ := pool.txPriorityQueue.arrival(txid)
+		pending.txns = append(pending.txns, txn)
+		pending.arrivals = append(pending.arrivals, seq)
 	}
 
 	pool.mu.Unlock()
 
-	// TODO: return unsorted pending, let calling code sort or not as needed, or make a heap, or whatever
-	sort.SliceStable(sorti, func(i, j int) bool {
-		return txns[sorti[j]].PtrPriority().LessThan(txns[sorti[i]].PtrPriority())
-	})
-	out := make([]transactions.SignedTxn, len(pool.pendingTxns))
-	for i, p := range sorti {
-		out[i] = txns[p]
+	sort.Sort(pending)
+	return pending.txns
+}
+
+// PendingFrom returns the transactions of the given sender that are pending in the pool, in the order in which
+// Pending returns them, along with the position of each of them in that order and the overall number of pending
+// transactions.
+func (pool *TransactionPool) PendingFrom(sender basics.Address) (txns []transactions.SignedTxn, positions []int, total int) {
+	pending := pool.Pending()
+	for i, txn := range pending {
+		if txn.Txn.Src() == sender {
+			txns = append(txns, txn)
+			positions = append(positions, i)
+		}
 	}
-	return out
+	return txns, positions, len(pending)
+}
+
+// pendingByPriority sorts transactions by decreasing priority, and by arrival among equal priorities.
+type pendingByPriority struct {
+	txns     []transactions.SignedTxn
+	arrivals []uint64
+}
+
+func (p pendingByPriority) Len() int {
+	return len(p.txns)
+}
+
+func (p pendingByPriority) Less(i, j int) bool {
+	pi, pj := p.txns[i].PtrPriority(), p.txns[j].PtrPriority()
+	if pi == pj {
+		return p.arrivals[i] < p.arrivals[j]
+	}
+	return pj.LessThan(pi)
+}
+
+func (p pendingByPriority) Swap(i, j int) {
+	p.txns[i], p.txns[j] = p.txns[j], p.txns[i]
+	p.arrivals[i], p.arrivals[j] = p.arrivals[j], p.arrivals[i]
 }
 
 // PendingUnsorted returns an array of transactions valid for the given round.
@@ -152,6 +211,52 @@ func (pool *TransactionPool) PendingCount() int {
 	return len(pool.pendingTxns)
 }
 
+// Occupancy returns the number of transactions pending in the pool, their total encoded length, and the lowest
+// priority among them, which new transactions have to beat once the pool is full.
+func (pool *TransactionPool) Occupancy() (count int, bytes int, minPriority transactions.TxnPriority) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	_, minPriority = pool.txPriorityQueue.getMin()
+	return len(pool.pendingTxns), pool.pendingBytes, minPriority
+}
+
+// Congestion describes how full the pool is, and the priorities a new transaction has to reach to get into the
+// pool and into the next block.
+type Congestion struct {
+	// Count is the number of transactions pending in the pool, out of Size
+	Count int
+	Size  int
+	// Bytes is the total encoded length of the pending transactions
+	Bytes int
+	// MinPriority is the priority a new transaction needs for the pool to accept it, or 0 while the pool isn't full
+	MinPriority transactions.TxnPriority
+	// NextBlockPriority is the priority a new transaction has to exceed to be considered ahead of the pending
+	// transactions that don't fit in the next block, or 0 if they all fit
+	NextBlockPriority transactions.TxnPriority
+}
+
+// Congestion returns how congested the pool is, given the maximum number of transaction bytes in a block.
+func (pool *TransactionPool) Congestion(maxBlockBytes int) (c Congestion) {
+	pending := pool.Pending()
+
+	pool.mu.RLock()
+	c.Size = pool.size
+	if len(pool.pendingTxns) >= pool.size {
+		_, minPriority := pool.txPriorityQueue.getMin()
+		c.MinPriority = minPriority.Mul(pool.exponentialPriorityGrowthFactor)
+	}
+	pool.mu.RUnlock()
+
+	c.Count = len(pending)
+	for _, txn := range pending {
+		c.Bytes += txn.GetEncodedLength()
+		if c.Bytes > maxBlockBytes && c.NextBlockPriority == 0 {
+			c.NextBlockPriority = txn.Priority()
+		}
+	}
+	return c
+}
+
 // Test checks whether a transaction could be remembered in the pool, but does not actually store this transaction
 // in the pool
 func (pool *TransactionPool) Test(t transactions.SignedTxn) error {
@@ -170,6 +275,19 @@ func (pool *TransactionPool) test(t transactions.SignedTxn) (accountDeductions,
 		return accountDeductions{}, false, transactions.Txid{}, errors.New("TransactionPool.test: transaction already in the pool")
 	}
 
+	if pool.maxPendingPerSender > 0 {
+		if pending := len(pool.algosPendingSpend[t.Txn.Src()].txids); pending >= pool.maxPendingPerSender {
+			return accountDeductions{}, false, transactions.Txid{}, fmt.Errorf("TransactionPool.test: sender %v already has %d transactions pending", t.Txn.Src(), pending)
+		}
+	}
+
+	txl, leased := t.Txn.Txlease()
+	if leased {
+		if holder, has := pool.pendingLeases[txl]; has {
+			return accountDeductions{}, false, transactions.Txid{}, fmt.Errorf("TransactionPool.test: lease %x of sender %v is held by pending transaction %v", txl.Lease, txl.Sender, holder)
+		}
+	}
+
 	var minTransactionID transactions.Txid
 	isFull := len(pool.pendingTxns) >= pool.size
 
@@ -192,6 +310,31 @@ func (pool *TransactionPool) test(t transactions.SignedTxn) (accountDeductions,
 		return accountDeductions{}, isFull, transactions.Txid{}, fmt.Errorf("TransactionPool.test: transaction with ID %v has already been committed", t.ID())
 	}
 
+	// check if a committed transaction holds the lease
+	if leased {
+		held, err := pool.ledger.Leased(t)
+		if err != nil {
+			return accountDeductions{}, isFull, transactions.Txid{}, fmt.Errorf("TransactionPool.test: failed to call Leased(): %v", err)
+		}
+		if held {
+			return accountDeductions{}, isFull, transactions.Txid{}, fmt.Errorf("TransactionPool.test: lease %x of sender %v is held by a committed transaction", txl.Lease, txl.Sender)
+		}
+	}
+
+	// check that the transaction is signed by the key currently authorized to spend from the sender, so that
+	// transactions no block would take can't take the place of the sender's own in the pool
+	record, err := pool.ledger.Lookup(pool.ledger.LastRound(), t.Txn.Sender)
+	if err != nil {
+		return accountDeductions{}, isFull, transactions.Txid{}, fmt.Errorf("TransactionPool.test: failed to look up sender %v: %v", t.Txn.Sender, err)
+	}
+	authorizer := record.AuthAddr
+	if authorizer == (basics.Address{}) {
+		authorizer = t.Txn.Sender
+	}
+	if t.Authorizer() != authorizer {
+		return accountDeductions{}, isFull, transactions.Txid{}, fmt.Errorf("TransactionPool.test: transaction %v should have been authorized by %v but was actually authorized by %v", t.ID(), authorizer, t.Authorizer())
+	}
+
 	// compute the deductions following this transaction
 	deductions, err := pool.computeDeductions(t)
 	if err != nil {
@@ -204,38 +347,205 @@ func (pool *TransactionPool) test(t transactions.SignedTxn) (accountDeductions,
 // Remember stores the provided transaction
 // Precondition: Only Remember() properly-signed and well-formed transactions (i.e., ensure t.WellFormed())
 func (pool *TransactionPool) Remember(t transactions.SignedTxn) error {
-	t.InitCaches()
+	return pool.RememberGroup([]transactions.SignedTxn{t})
+}
+
+// RememberGroup stores the transactions of an atomic transaction group, in the order of the group: either
+// all of them are remembered, or none is. A transaction that isn't part of a group makes a group on its own.
+// Once remembered, the transactions of a group are removed from the pool together.
+// Precondition: as for Remember
+func (pool *TransactionPool) RememberGroup(txgroup []transactions.SignedTxn) error {
+	for i := range txgroup {
+		txgroup[i].InitCaches()
+	}
 
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	return pool.rememberGroup(txgroup)
+}
+
+// rememberGroup stores the provided transaction group; pool.mu must be held
+func (pool *TransactionPool) rememberGroup(txgroup []transactions.SignedTxn) error {
+	if len(txgroup) == 0 {
+		return fmt.Errorf("TransactionPool.Remember: empty transaction group")
+	}
+	group := txgroup[0].Txn.Group
+	if len(txgroup) == 1 && group == (crypto.Digest{}) {
+		return pool.remember(txgroup[0], nil)
+	}
+
+	consensusParams, err := pool.ledger.ConsensusParams(pool.ledger.LastRound())
+	if err != nil {
+		return fmt.Errorf("TransactionPool.Remember: %v", err)
+	}
+	err = transactions.WellFormedGroup(txgroup, consensusParams)
+	if err != nil {
+		transactionPoolRejectedTotal.Inc(nil)
+		return fmt.Errorf("TransactionPool.Remember: %v", err)
+	}
+	if _, has := pool.pendingGroups[group]; has {
+		transactionPoolRejectedTotal.Inc(nil)
+		return fmt.Errorf("TransactionPool.Remember: transaction group %v already in the pool", group)
+	}
+
+	// the pending transactions evicted to make room for the group are put back if the group isn't remembered
+	var evicted [][]transactions.SignedTxn
+	txids := make([]transactions.Txid, 0, len(txgroup))
+	for _, t := range txgroup {
+		err = pool.remember(t, &evicted)
+		if err != nil {
+			break
+		}
+		txids = append(txids, t.ID())
+	}
+	if err == nil {
+		// making room for the last transactions may have evicted the first ones
+		for _, txid := range txids {
+			if _, has := pool.pendingTxns[txid]; !has {
+				err = fmt.Errorf("TransactionPool.Remember: transaction pool is full and the priority of transaction group %v is too low", group)
+				break
+			}
+		}
+	}
+	if err != nil {
+		for _, txid := range txids {
+			pool.remove(txid, nil)
+		}
+		for _, txns := range evicted {
+			if txns[0].Txn.Group != group {
+				pool.restore(txns)
+			}
+		}
+		return err
+	}
+
+	pool.pendingGroups[group] = txids
+	return nil
+}
+
+// restore puts back the transactions of a group, or a single transaction, which were evicted from the pool;
+// pool.mu must be held
+func (pool *TransactionPool) restore(txns []transactions.SignedTxn) {
+	txids := make([]transactions.Txid, len(txns))
+	for i, t := range txns {
+		deductions, err := pool.algosPendingSpend.deductionsWithTransaction(t.Txn)
+		if err != nil {
+			pool.log.Errorf("TransactionPool.restore: %v", err)
+		}
+		pool.txPriorityQueue.Push(t)
+		pool.pendingTxns[t.ID()] = t
+		pool.pendingBytes += t.GetEncodedLength()
+		if txl, leased := t.Txn.Txlease(); leased {
+			pool.pendingLeases[txl] = t.ID()
+		}
+		pool.algosPendingSpend.accountForTransactionDeductions(t.Txn, deductions)
+		txids[i] = t.ID()
+	}
+	if group := txns[0].Txn.Group; group != (crypto.Digest{}) {
+		pool.pendingGroups[group] = txids
+	}
+}
+
+// PendingGroup returns the transactions of the given group pending in the pool, in the order of the group,
+// or nil if the group isn't pending.
+func (pool *TransactionPool) PendingGroup(group crypto.Digest) []transactions.SignedTxn {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	txids, has := pool.pendingGroups[group]
+	if !has {
+		return nil
+	}
+	txgroup := make([]transactions.SignedTxn, len(txids))
+	for i, txid := range txids {
+		txgroup[i] = pool.pendingTxns[txid]
+	}
+	return txgroup
+}
+
+// RememberBatch stores the provided transactions in turn, without letting other transactions in between, and
+// returns the error of each of them, nil for the remembered ones. The transactions of the batch see the pending
+// spend of the ones before them, as if they were remembered one by one.
+// Precondition: as for Remember
+func (pool *TransactionPool) RememberBatch(txns []transactions.SignedTxn) []error {
+	for i := range txns {
+		txns[i].InitCaches()
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	errs := make([]error, len(txns))
+	for i, t := range txns {
+		errs[i] = pool.rememberGroup([]transactions.SignedTxn{t})
+	}
+	return errs
+}
+
+// remember stores the provided transaction, appending the transactions it evicts to evicted unless it's nil;
+// pool.mu must be held
+func (pool *TransactionPool) remember(t transactions.SignedTxn, evicted *[][]transactions.SignedTxn) error {
 	deductions, isFull, minTransactionID, err := pool.test(t)
 	if err != nil {
+		transactionPoolRejectedTotal.Inc(nil)
 		return fmt.Errorf("TransactionPool.Remember: %v", err)
 	}
 
 	if isFull {
 		// remove old transaction. we want to do that before adding the new entry to ensure we
 		// won't exceed (temporarly) the total number of pending transactions.
+		if evicted != nil {
+			*evicted = append(*evicted, pool.pendingWithGroup(minTransactionID))
+		}
 		pool.remove(minTransactionID, fmt.Errorf("transaction evicted due to low priority"))
+		transactionPoolEvictedTotal.Inc(nil)
+
+		// the evicted transaction may have been pending spend from the same sender, in which case the deductions
+		// computed before evicting it still account for it.
+		deductions, err = pool.algosPendingSpend.deductionsWithTransaction(t.Txn)
+		if err != nil {
+			transactionPoolRejectedTotal.Inc(nil)
+			return fmt.Errorf("TransactionPool.Remember: %v", err)
+		}
 	}
 
 	// push to the priority queue
 	if !pool.txPriorityQueue.Push(t) {
 		// this should never happen, since we already tested that above.
-		logging.Base().Errorf("TransactionPool.Remember: Attempted to push a transaction %v into the priority queue while it's already there", t)
+		pool.log.Errorf("TransactionPool.Remember: Attempted to push a transaction %v into the priority queue while it's already there", t)
 		return fmt.Errorf("TransactionPool.Remember: cannot push txn %v as it's already in the pool", t)
 	}
 
 	// we're almost done; the transaction was already saved into the priority queue. now, save the transaction
 	// into the pending transactions list.
 	pool.pendingTxns[t.ID()] = t
+	pool.pendingBytes += t.GetEncodedLength()
+	if txl, leased := t.Txn.Txlease(); leased {
+		pool.pendingLeases[txl] = t.ID()
+	}
 	// last, update the spent algos from the sender account
 	pool.algosPendingSpend.accountForTransactionDeductions(t.Txn, deductions)
 
+	transactionPoolRememberedTotal.Inc(nil)
 	return nil
 }
 
+// pendingWithGroup returns the given pending transaction along with the rest of its group, in the order of the group;
+// pool.mu must be held
+func (pool *TransactionPool) pendingWithGroup(txid transactions.Txid) []transactions.SignedTxn {
+	t := pool.pendingTxns[txid]
+	txids, has := pool.pendingGroups[t.Txn.Group]
+	if t.Txn.Group == (crypto.Digest{}) || !has {
+		return []transactions.SignedTxn{t}
+	}
+	txns := make([]transactions.SignedTxn, len(txids))
+	for i, member := range txids {
+		txns[i] = pool.pendingTxns[member]
+	}
+	return txns
+}
+
 func (pool *TransactionPool) computeDeductions(t transactions.SignedTxn) (accountDeductions, error) {
 	// compute how this transaction would affect the number of MicroAlgos pending spend by the sender
 	algosPendingSpend, err := pool.algosPendingSpend.deductionsWithTransaction(t.Txn)
@@ -393,7 +703,7 @@ func (pool *TransactionPool) OnNewBlock(block bookkeeping.Block) {
 		pendingSpend := pool.algosPendingSpend[account]
 		spendable, _, _, _, _, err := pool.ledger.BalanceAndStatus(account)
 		if err != nil {
-			logging.Base().Errorf("TransactionPool.OnNewBlock: Cannot get balance for %v: %v", account, err)
+			pool.log.Errorf("TransactionPool.OnNewBlock: Cannot get balance for %v: %v", account, err)
 			break
 		}
 
@@ -431,7 +741,7 @@ func (pool *TransactionPool) OnNewBlock(block bookkeeping.Block) {
 			Round uint64
 		}
 		details.Round = uint64(block.Round())
-		logging.Base().Metrics(telemetryspec.Transaction, stats, details)
+		pool.log.Metrics(telemetryspec.Transaction, stats, details)
 	}
 }
 
@@ -444,20 +754,51 @@ func (pool *TransactionPool) Remove(txid transactions.Txid, txErr error) {
 	pool.remove(txid, txErr)
 }
 
+// Drop removes a pending transaction from the pool along with the rest of its group, and remembers txErr as their
+// error status. It returns the IDs of the transactions it removed, or nil if the transaction isn't pending.
+func (pool *TransactionPool) Drop(txid transactions.Txid, txErr error) []transactions.Txid {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	tx, has := pool.pendingTxns[txid]
+	if !has {
+		return nil
+	}
+	dropped := []transactions.Txid{txid}
+	if group := tx.Txn.Group; group != (crypto.Digest{}) {
+		dropped = append([]transactions.Txid(nil), pool.pendingGroups[group]...)
+	}
+	pool.remove(txid, txErr)
+	return dropped
+}
+
 func (pool *TransactionPool) remove(txid transactions.Txid, txErr error) {
 	tx, has := pool.pendingTxns[txid]
 	if !has {
 		return
 	}
 	if err := pool.algosPendingSpend.remove(tx.Txn); err != nil {
-		logging.Base().Errorf("TransactionPool::remove: %v", err)
+		pool.log.Errorf("TransactionPool::remove: %v", err)
 	}
 	pool.txPriorityQueue.Remove(txid)
 	delete(pool.pendingTxns, txid)
+	pool.pendingBytes -= tx.GetEncodedLength()
+	if txl, leased := tx.Txn.Txlease(); leased {
+		delete(pool.pendingLeases, txl)
+	}
 
 	// If the transaction was removed due to an error (instead of being
 	// committed to the ledger), remember the error in the statusCache.
 	if txErr != nil {
 		pool.statusCache.put(tx, txErr.Error())
 	}
+
+	// The rest of its group can't be committed without it
+	if group := tx.Txn.Group; group != (crypto.Digest{}) {
+		txids := pool.pendingGroups[group]
+		delete(pool.pendingGroups, group)
+		for _, member := range txids {
+			pool.remove(member, txErr)
+		}
+	}
 }
@@ -41,8 +41,11 @@ func keypair() *crypto.SignatureSecrets {
 }
 
 type mockSpendableBalancesUnbounded struct {
-	balance    uint64
-	exceptions map[basics.Address]uint64
+	balance        uint64
+	exceptions     map[basics.Address]uint64
+	maxTxGroupSize int
+	leases         map[transactions.Txlease]bool
+	authAddrs      map[basics.Address]basics.Address
 }
 
 func (b mockSpendableBalancesUnbounded) BalanceAndStatus(address basics.Address) (total basics.MicroAlgos, rewards basics.MicroAlgos, totalWithoutPendingRewards basics.MicroAlgos, status basics.Status, round basics.Round, err error) {
@@ -62,10 +65,19 @@ func (b mockSpendableBalancesUnbounded) Committed(transactions.SignedTxn) (bool,
 	return false, nil
 }
 
+func (b mockSpendableBalancesUnbounded) Leased(txn transactions.SignedTxn) (bool, error) {
+	txl, _ := txn.Txn.Txlease()
+	return b.leases[txl], nil
+}
+
+func (b mockSpendableBalancesUnbounded) Lookup(_ basics.Round, address basics.Address) (basics.AccountData, error) {
+	return basics.AccountData{AuthAddr: b.authAddrs[address]}, nil
+}
+
 const mockBalancesMinBalance = 1000
 
 func (b mockSpendableBalancesUnbounded) ConsensusParams(basics.Round) (config.ConsensusParams, error) {
-	return config.ConsensusParams{MinBalance: mockBalancesMinBalance}, nil
+	return config.ConsensusParams{MinBalance: mockBalancesMinBalance, MaxTxGroupSize: b.maxTxGroupSize}, nil
 }
 
 func (b mockSpendableBalancesUnbounded) BlockHdr(basics.Round) (bookkeeping.BlockHeader, error) {
@@ -95,7 +107,7 @@ func TestMinBalanceOK(t *testing.T) {
 	limitedAccounts := make(map[basics.Address]uint64)
 	limitedAccounts[addresses[0]] = 2*mockBalancesMinBalance + proto.MinTxnFee
 
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, 0, false)
 
 	// sender goes below min
 	tx := transactions.Transaction{
@@ -132,7 +144,7 @@ func TestSenderGoesBelowMinBalance(t *testing.T) {
 	limitedAccounts := make(map[basics.Address]uint64)
 	limitedAccounts[addresses[0]] = 2*mockBalancesMinBalance + proto.MinTxnFee
 
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, 0, false)
 
 	// sender goes below min
 	tx := transactions.Transaction{
@@ -169,7 +181,7 @@ func TestCloseAccount(t *testing.T) {
 	limitedAccounts := make(map[basics.Address]uint64)
 	limitedAccounts[addresses[0]] = 3*mockBalancesMinBalance + 2*proto.MinTxnFee
 
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, 0, false)
 
 	// sender goes below min
 	closeTx := transactions.Transaction{
@@ -228,7 +240,7 @@ func TestCloseAccountWhileTxIsPending(t *testing.T) {
 	limitedAccounts := make(map[basics.Address]uint64)
 	limitedAccounts[addresses[0]] = 2*mockBalancesMinBalance + 2*proto.MinTxnFee
 
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, 0, false)
 
 	// sender goes below min
 	tx := transactions.Transaction{
@@ -285,7 +297,7 @@ func TestClosingAccountBelowMinBalance(t *testing.T) {
 	limitedAccounts[addresses[0]] = 2*mockBalancesMinBalance - 1 + proto.MinTxnFee
 	limitedAccounts[addresses[2]] = 0
 
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, 0, false)
 
 	// sender goes below min
 	closeTx := transactions.Transaction{
@@ -323,7 +335,7 @@ func TestRecipientGoesBelowMinBalance(t *testing.T) {
 	limitedAccounts := make(map[basics.Address]uint64)
 	limitedAccounts[addresses[1]] = 0
 
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts}, exponentialGrowth, testPoolSize, 0, false)
 
 	// sender goes below min
 	tx := transactions.Transaction{
@@ -357,7 +369,7 @@ func TestRememberForget(t *testing.T) {
 		addresses[i] = addr
 	}
 
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, testPoolSize, 0, false)
 	var block bookkeeping.Block
 	block.Payset = make(transactions.Payset, 0)
 
@@ -410,7 +422,7 @@ func TestPendingIsOrdered(t *testing.T) {
 		addresses[i] = addr
 	}
 
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, testPoolSize, 0, false)
 
 	for i, sender := range addresses {
 		for j, receiver := range addresses {
@@ -474,7 +486,7 @@ func TestCleanUp(t *testing.T) {
 		addresses[i] = addr
 	}
 
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, testPoolSize, 0, false)
 
 	issuedTransactions := 0
 	for i, sender := range addresses {
@@ -545,7 +557,7 @@ func TestFixOverflowOnNewBlock(t *testing.T) {
 
 	balance := mockSpendableBalancesUnbounded{balance: 1 << 60}
 
-	transactionPool := MakeTransactionPool(&balance, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(&balance, exponentialGrowth, testPoolSize, 0, false)
 
 	overSpender := addresses[0]
 	overSpenderPendingSpend := make(accountsToPendingTransactions)
@@ -647,7 +659,7 @@ func TestExponentialPriorityGrowth(t *testing.T) {
 	}
 
 	poolSize := 2
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, poolSize, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, poolSize, 0, false)
 
 	sender := addresses[0]
 	receiver := addresses[1]
@@ -706,6 +718,259 @@ func TestExponentialPriorityGrowth(t *testing.T) {
 	require.NoError(t, transactionPool.Remember(txHighFee.Sign(secrets[0])))
 }
 
+func TestEvictionKeepsPendingSpend(t *testing.T) {
+	secret := keypair()
+	sender := basics.Address(secret.SignatureVerifier)
+	receiver := basics.Address(keypair().SignatureVerifier)
+
+	poolSize := 2
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, poolSize, 0, false)
+
+	makeTx := func(fee uint64, note byte) transactions.SignedTxn {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     sender,
+				Fee:        basics.MicroAlgos{Raw: fee},
+				FirstValid: 0,
+				LastValid:  basics.Round(proto.MaxTxnLife),
+				Note:       []byte{note},
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: receiver,
+				Amount:   basics.MicroAlgos{Raw: 1},
+			},
+		}
+		return tx.Sign(secret)
+	}
+
+	baseFee := uint64(2)
+	for i := 0; i < poolSize; i++ {
+		require.NoError(t, transactionPool.Remember(makeTx(baseFee, byte(i))))
+	}
+	require.NoError(t, transactionPool.Remember(makeTx(baseFee*exponentialGrowth, byte(poolSize))))
+	require.Equal(t, poolSize, transactionPool.PendingCount())
+
+	// the pending spend of the sender accounts for the remaining transactions only, not the evicted one
+	expected := uint64(0)
+	for _, txn := range transactionPool.PendingUnsorted() {
+		expected += txn.Txn.Fee.Raw + txn.Txn.Amount.Raw
+	}
+	require.Equal(t, expected, transactionPool.algosPendingSpend[sender].deductions.amount.Raw)
+	require.Len(t, transactionPool.algosPendingSpend[sender].txids, poolSize)
+}
+
+func TestPendingEqualPriorityArrivalOrder(t *testing.T) {
+	numOfAccounts := 5
+	secrets := make([]*crypto.SignatureSecrets, numOfAccounts)
+	for i := range secrets {
+		secrets[i] = keypair()
+	}
+	receiver := basics.Address(keypair().SignatureVerifier)
+
+	poolSize := numOfAccounts - 1
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, 1, poolSize, 0, false)
+
+	// transactions of the same length and fee have the same priority
+	signed := make([]transactions.SignedTxn, numOfAccounts)
+	for i, secret := range secrets {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     basics.Address(secret.SignatureVerifier),
+				Fee:        basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid: 0,
+				LastValid:  basics.Round(proto.MaxTxnLife),
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: receiver,
+				Amount:   basics.MicroAlgos{Raw: 1},
+			},
+		}
+		signed[i] = tx.Sign(secret)
+	}
+	for _, stxn := range signed[:poolSize] {
+		require.NoError(t, transactionPool.Remember(stxn))
+	}
+
+	pending := transactionPool.Pending()
+	require.Len(t, pending, poolSize)
+	for i := range pending {
+		require.Equal(t, signed[i].ID(), pending[i].ID())
+	}
+
+	count, bytes, minPriority := transactionPool.Occupancy()
+	require.Equal(t, poolSize, count)
+	require.Equal(t, poolSize*signed[0].GetEncodedLength(), bytes)
+	require.Equal(t, signed[0].Priority(), minPriority)
+	require.True(t, minPriority.FeePerByte() > 0)
+
+	// a full pool evicts the latest arrival among the lowest priority transactions
+	require.NoError(t, transactionPool.Remember(signed[poolSize]))
+	pending = transactionPool.Pending()
+	require.Len(t, pending, poolSize)
+	for i := 0; i < poolSize-1; i++ {
+		require.Equal(t, signed[i].ID(), pending[i].ID())
+	}
+	require.Equal(t, signed[poolSize].ID(), pending[poolSize-1].ID())
+	_, txErr, found := transactionPool.Lookup(signed[poolSize-1].ID())
+	require.True(t, found)
+	require.Contains(t, txErr, "evicted")
+}
+
+func TestCongestion(t *testing.T) {
+	numOfAccounts := 4
+	receiver := basics.Address(keypair().SignatureVerifier)
+
+	exponentialGrowth := uint64(2)
+	poolSize := numOfAccounts
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, poolSize, 0, false)
+
+	c := transactionPool.Congestion(proto.MaxTxnBytesPerBlock)
+	require.Equal(t, Congestion{Size: poolSize}, c)
+
+	// the transactions pay decreasing fees
+	signed := make([]transactions.SignedTxn, numOfAccounts)
+	for i := range signed {
+		secret := keypair()
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     basics.Address(secret.SignatureVerifier),
+				Fee:        basics.MicroAlgos{Raw: proto.MinTxnFee * uint64(numOfAccounts-i)},
+				FirstValid: 0,
+				LastValid:  basics.Round(proto.MaxTxnLife),
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: receiver,
+				Amount:   basics.MicroAlgos{Raw: 1},
+			},
+		}
+		signed[i] = tx.Sign(secret)
+		require.NoError(t, transactionPool.Remember(signed[i]))
+	}
+	txnBytes := signed[0].GetEncodedLength()
+
+	// everything fits in a block, but the pool is full
+	c = transactionPool.Congestion(proto.MaxTxnBytesPerBlock)
+	require.Equal(t, numOfAccounts, c.Count)
+	require.Equal(t, numOfAccounts*txnBytes, c.Bytes)
+	require.Equal(t, signed[numOfAccounts-1].Priority().Mul(exponentialGrowth), c.MinPriority)
+	require.Zero(t, c.NextBlockPriority)
+
+	// a block only has room for the first two transactions
+	c = transactionPool.Congestion(2*txnBytes + txnBytes/2)
+	require.Equal(t, signed[2].Priority(), c.NextBlockPriority)
+}
+
+func TestSenderPendingLimit(t *testing.T) {
+	spammer := keypair()
+	other := keypair()
+	receiver := basics.Address(keypair().SignatureVerifier)
+
+	maxPendingPerSender := 3
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, 1, 10, maxPendingPerSender, false)
+
+	payment := func(secret *crypto.SignatureSecrets, fee uint64, amount uint64) transactions.SignedTxn {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     basics.Address(secret.SignatureVerifier),
+				Fee:        basics.MicroAlgos{Raw: fee},
+				FirstValid: 0,
+				LastValid:  basics.Round(proto.MaxTxnLife),
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: receiver,
+				Amount:   basics.MicroAlgos{Raw: amount},
+			},
+		}
+		return tx.Sign(secret)
+	}
+
+	// the spammer pays higher fees, so its transactions come first
+	var spammed []transactions.SignedTxn
+	for i := 0; i < maxPendingPerSender; i++ {
+		stxn := payment(spammer, proto.MinTxnFee*uint64(10-i), uint64(i+1))
+		require.NoError(t, transactionPool.Remember(stxn))
+		spammed = append(spammed, stxn)
+	}
+	err := transactionPool.Remember(payment(spammer, proto.MinTxnFee*100, 100))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already has 3 transactions pending")
+
+	// other senders aren't affected by the spammer reaching its limit
+	otherTxn := payment(other, proto.MinTxnFee*20, 1)
+	require.NoError(t, transactionPool.Remember(otherTxn))
+
+	txns, positions, total := transactionPool.PendingFrom(basics.Address(spammer.SignatureVerifier))
+	require.Equal(t, maxPendingPerSender+1, total)
+	require.Len(t, txns, maxPendingPerSender)
+	for i := range txns {
+		require.Equal(t, spammed[i].ID(), txns[i].ID())
+		require.Equal(t, i+1, positions[i])
+	}
+
+	txns, positions, _ = transactionPool.PendingFrom(basics.Address(other.SignatureVerifier))
+	require.Len(t, txns, 1)
+	require.Equal(t, otherTxn.ID(), txns[0].ID())
+	require.Equal(t, []int{0}, positions)
+
+	// once one of its transactions leaves the pool, the spammer can submit another one
+	transactionPool.Remove(spammed[0].ID(), nil)
+	require.NoError(t, transactionPool.Remember(payment(spammer, proto.MinTxnFee, 200)))
+
+	// raising the limit of the running pool lets the spammer submit more, while removing it lifts the limit
+	transactionPool.Reconfigure(maxPendingPerSender+1, false)
+	require.NoError(t, transactionPool.Remember(payment(spammer, proto.MinTxnFee, 300)))
+	require.Error(t, transactionPool.Remember(payment(spammer, proto.MinTxnFee, 400)))
+	transactionPool.Reconfigure(0, false)
+	require.NoError(t, transactionPool.Remember(payment(spammer, proto.MinTxnFee, 400)))
+}
+
+func TestRememberBatch(t *testing.T) {
+	sender := keypair()
+	receiver := basics.Address(keypair().SignatureVerifier)
+
+	maxPendingPerSender := 2
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, 1, 10, maxPendingPerSender, false)
+
+	payment := func(amount uint64) transactions.SignedTxn {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     basics.Address(sender.SignatureVerifier),
+				Fee:        basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid: 0,
+				LastValid:  basics.Round(proto.MaxTxnLife),
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: receiver,
+				Amount:   basics.MicroAlgos{Raw: amount},
+			},
+		}
+		return tx.Sign(sender)
+	}
+
+	// the transactions of the batch count against each other, as if remembered one by one
+	first := payment(1)
+	batch := []transactions.SignedTxn{first, first, payment(2), payment(3)}
+	errs := transactionPool.RememberBatch(batch)
+	require.Len(t, errs, len(batch))
+	require.NoError(t, errs[0])
+	require.Error(t, errs[1])
+	require.Contains(t, errs[1].Error(), "already in the pool")
+	require.NoError(t, errs[2])
+	require.Error(t, errs[3])
+	require.Contains(t, errs[3].Error(), "already has 2 transactions pending")
+
+	require.Len(t, transactionPool.Pending(), 2)
+	txns, _, total := transactionPool.PendingFrom(basics.Address(sender.SignatureVerifier))
+	require.Equal(t, 2, total)
+	require.Equal(t, first.ID(), txns[0].ID())
+	require.Equal(t, batch[2].ID(), txns[1].ID())
+}
+
 func TestOverspender(t *testing.T) {
 	numOfAccounts := 2
 	// Genereate accounts
@@ -721,7 +986,7 @@ func TestOverspender(t *testing.T) {
 
 	balance := mockSpendableBalancesUnbounded{balance: 1 << 60}
 
-	transactionPool := MakeTransactionPool(&balance, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(&balance, exponentialGrowth, testPoolSize, 0, false)
 
 	overSpender := addresses[0]
 	overSpenderPendingSpend := make(accountsToPendingTransactions)
@@ -786,7 +1051,7 @@ func TestAddError(t *testing.T) {
 
 	balance := mockSpendableBalancesUnbounded{balance: 1 << 60}
 
-	transactionPool := MakeTransactionPool(&balance, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(&balance, exponentialGrowth, testPoolSize, 0, false)
 
 	sender := addresses[0]
 	senderPendingSpend := make(accountsToPendingTransactions)
@@ -836,7 +1101,7 @@ func TestRemove(t *testing.T) {
 
 	balance := mockSpendableBalancesUnbounded{balance: 1 << 60}
 
-	transactionPool := MakeTransactionPool(&balance, exponentialGrowth, testPoolSize, false)
+	transactionPool := MakeTransactionPool(&balance, exponentialGrowth, testPoolSize, 0, false)
 
 	sender := addresses[0]
 	receiver := addresses[1]
@@ -879,6 +1144,57 @@ func TestRemove(t *testing.T) {
 
 }
 
+func TestLease(t *testing.T) {
+	secrets := []*crypto.SignatureSecrets{keypair(), keypair()}
+	receiver := basics.Address(keypair().SignatureVerifier)
+
+	balance := mockSpendableBalancesUnbounded{balance: 1 << 60, leases: make(map[transactions.Txlease]bool)}
+	transactionPool := MakeTransactionPool(&balance, exponentialGrowth, testPoolSize, 0, false)
+
+	var lease [32]byte
+	crypto.RandBytes(lease[:])
+	makeTx := func(secret *crypto.SignatureSecrets, lease [32]byte, note byte) transactions.SignedTxn {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     basics.Address(secret.SignatureVerifier),
+				Fee:        basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid: 0,
+				LastValid:  10,
+				Note:       []byte{note},
+				Lease:      lease,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: receiver,
+				Amount:   basics.MicroAlgos{Raw: 1},
+			},
+		}
+		return tx.Sign(secret)
+	}
+
+	// a retry of a pending transaction with the same lease is rejected
+	first := makeTx(secrets[0], lease, 0)
+	require.NoError(t, transactionPool.Remember(first))
+	retry := makeTx(secrets[0], lease, 1)
+	require.Error(t, transactionPool.Remember(retry))
+
+	// leases are per sender, and transactions without a lease don't conflict
+	require.NoError(t, transactionPool.Remember(makeTx(secrets[1], lease, 1)))
+	require.NoError(t, transactionPool.Remember(makeTx(secrets[0], [32]byte{}, 2)))
+	require.NoError(t, transactionPool.Remember(makeTx(secrets[0], [32]byte{}, 3)))
+
+	// removing the first transaction releases its lease
+	transactionPool.Remove(first.ID(), fmt.Errorf("expired"))
+	require.NoError(t, transactionPool.Remember(retry))
+
+	// a lease held by a committed transaction
+	var other [32]byte
+	crypto.RandBytes(other[:])
+	txl, _ := makeTx(secrets[0], other, 4).Txn.Txlease()
+	balance.leases[txl] = true
+	require.Error(t, transactionPool.Remember(makeTx(secrets[0], other, 4)))
+}
+
 func BenchmarkTransactionPoolRemember(b *testing.B) {
 	numOfAccounts := 5
 	// Genereate accounts
@@ -892,7 +1208,7 @@ func BenchmarkTransactionPoolRemember(b *testing.B) {
 		addresses[i] = addr
 	}
 
-	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, b.N, false)
+	transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, b.N, 0, false)
 	signedTransactions := make([]transactions.SignedTxn, 0, b.N)
 	for i, sender := range addresses {
 		for j := 0; j < b.N/len(addresses); j++ {
@@ -922,7 +1238,7 @@ func BenchmarkTransactionPoolRemember(b *testing.B) {
 	}
 	b.StopTimer()
 	b.ResetTimer()
-	transactionPool = MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, b.N, false)
+	transactionPool = MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, b.N, 0, false)
 
 	b.StartTimer()
 	for _, signedTx := range signedTransactions {
@@ -946,7 +1262,7 @@ func BenchmarkTransactionPoolPending(b *testing.B) {
 	sub := func(b *testing.B, benchPoolSize int) {
 		b.StopTimer()
 		b.ResetTimer()
-		transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, benchPoolSize, false)
+		transactionPool := MakeTransactionPool(mockSpendableBalancesUnbounded{balance: 1 << 60}, exponentialGrowth, benchPoolSize, 0, false)
 		var block bookkeeping.Block
 		block.Payset = make(transactions.Payset, 0)
 
@@ -988,3 +1304,183 @@ func BenchmarkTransactionPoolPending(b *testing.B) {
 		})
 	}
 }
+
+func TestRememberGroup(t *testing.T) {
+	secrets := make([]*crypto.SignatureSecrets, 3)
+	addresses := make([]basics.Address, 3)
+	for i := range secrets {
+		secrets[i] = keypair()
+		addresses[i] = basics.Address(secrets[i].SignatureVerifier)
+	}
+
+	limitedAccounts := map[basics.Address]uint64{addresses[1]: 2*mockBalancesMinBalance + proto.MinTxnFee}
+	ledger := mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts, maxTxGroupSize: 16}
+	transactionPool := MakeTransactionPool(ledger, exponentialGrowth, testPoolSize, 0, false)
+
+	payment := func(sender int, amount uint64) transactions.Transaction {
+		return transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     addresses[sender],
+				Fee:        basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid: 0,
+				LastValid:  basics.Round(proto.MaxTxnLife),
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addresses[2],
+				Amount:   basics.MicroAlgos{Raw: amount},
+			},
+		}
+	}
+	makeGroup := func(txns ...transactions.Transaction) []transactions.SignedTxn {
+		gid := transactions.ComputeGroupID(txns)
+		txgroup := make([]transactions.SignedTxn, len(txns))
+		for i, txn := range txns {
+			txn.Group = gid
+			txgroup[i] = txn.Sign(secrets[i])
+		}
+		return txgroup
+	}
+
+	// the second sender can't afford its payment, so none of the group is remembered
+	txgroup := makeGroup(payment(0, mockBalancesMinBalance), payment(1, 2*mockBalancesMinBalance))
+	require.Error(t, transactionPool.RememberGroup(txgroup))
+	require.Equal(t, 0, transactionPool.PendingCount())
+
+	// a transaction of a group isn't remembered on its own
+	txgroup = makeGroup(payment(0, mockBalancesMinBalance), payment(1, mockBalancesMinBalance))
+	require.Error(t, transactionPool.Remember(txgroup[0]))
+	require.Equal(t, 0, transactionPool.PendingCount())
+
+	require.NoError(t, transactionPool.RememberGroup(txgroup))
+	require.Equal(t, 2, transactionPool.PendingCount())
+	require.Equal(t, txgroup, transactionPool.PendingGroup(txgroup[0].Txn.Group))
+	require.Error(t, transactionPool.RememberGroup(txgroup))
+
+	// removing a transaction of the group removes the whole group
+	transactionPool.Remove(txgroup[1].ID(), fmt.Errorf("test"))
+	require.Equal(t, 0, transactionPool.PendingCount())
+	require.Nil(t, transactionPool.PendingGroup(txgroup[0].Txn.Group))
+	_, txErr, found := transactionPool.Lookup(txgroup[0].ID())
+	require.True(t, found)
+	require.Equal(t, "test", txErr)
+
+	// dropping a transaction of the group drops and returns the whole group
+	txgroup = makeGroup(payment(0, mockBalancesMinBalance), payment(1, mockBalancesMinBalance), payment(2, 0))
+	require.NoError(t, transactionPool.RememberGroup(txgroup))
+	require.Equal(t, []transactions.Txid{txgroup[0].ID(), txgroup[1].ID(), txgroup[2].ID()}, transactionPool.Drop(txgroup[2].ID(), fmt.Errorf("dropped")))
+	require.Equal(t, 0, transactionPool.PendingCount())
+	_, txErr, found = transactionPool.Lookup(txgroup[1].ID())
+	require.True(t, found)
+	require.Equal(t, "dropped", txErr)
+	require.Nil(t, transactionPool.Drop(txgroup[2].ID(), fmt.Errorf("dropped")))
+
+	// groups aren't remembered when the protocol doesn't support them
+	ledger.maxTxGroupSize = 0
+	transactionPool = MakeTransactionPool(ledger, exponentialGrowth, testPoolSize, 0, false)
+	require.Error(t, transactionPool.RememberGroup(txgroup))
+}
+
+func TestRememberGroupRestoresEvicted(t *testing.T) {
+	secrets := make([]*crypto.SignatureSecrets, 4)
+	addresses := make([]basics.Address, 4)
+	for i := range secrets {
+		secrets[i] = keypair()
+		addresses[i] = basics.Address(secrets[i].SignatureVerifier)
+	}
+	receiver := basics.Address(keypair().SignatureVerifier)
+
+	// the last sender can't afford its payment
+	limitedAccounts := map[basics.Address]uint64{addresses[3]: mockBalancesMinBalance}
+	ledger := mockSpendableBalancesUnbounded{balance: 1 << 60, exceptions: limitedAccounts, maxTxGroupSize: 16}
+	poolSize := 2
+	transactionPool := MakeTransactionPool(ledger, exponentialGrowth, poolSize, 0, false)
+
+	payment := func(sender int, fee uint64) transactions.Transaction {
+		return transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     addresses[sender],
+				Fee:        basics.MicroAlgos{Raw: fee},
+				FirstValid: 0,
+				LastValid:  basics.Round(proto.MaxTxnLife),
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: receiver,
+				Amount:   basics.MicroAlgos{Raw: mockBalancesMinBalance},
+			},
+		}
+	}
+
+	baseFee := proto.MinTxnFee
+	pending := []transactions.SignedTxn{payment(0, baseFee).Sign(secrets[0]), payment(1, baseFee).Sign(secrets[1])}
+	for _, stxn := range pending {
+		require.NoError(t, transactionPool.Remember(stxn))
+	}
+
+	// the first transaction of the group evicts a pending one before the second one is rejected
+	txns := []transactions.Transaction{payment(2, baseFee*exponentialGrowth*10), payment(3, baseFee*exponentialGrowth*10)}
+	gid := transactions.ComputeGroupID(txns)
+	txgroup := make([]transactions.SignedTxn, len(txns))
+	for i, txn := range txns {
+		txn.Group = gid
+		txgroup[i] = txn.Sign(secrets[i+2])
+	}
+	require.Error(t, transactionPool.RememberGroup(txgroup))
+
+	// the evicted transaction is pending again, along with its pending spend
+	require.ElementsMatch(t, pending, transactionPool.PendingUnsorted())
+	for i, stxn := range pending {
+		_, txErr, found := transactionPool.Lookup(stxn.ID())
+		require.True(t, found)
+		require.Empty(t, txErr)
+		require.Len(t, transactionPool.algosPendingSpend[addresses[i]].txids, 1)
+	}
+	require.Empty(t, transactionPool.algosPendingSpend[addresses[2]].txids)
+}
+
+func TestAuthAddr(t *testing.T) {
+	secrets := make([]*crypto.SignatureSecrets, 3)
+	addresses := make([]basics.Address, 3)
+	for i := range secrets {
+		secrets[i] = keypair()
+		addresses[i] = basics.Address(secrets[i].SignatureVerifier)
+	}
+	sender, rekeyed, attacker := addresses[0], addresses[1], addresses[2]
+
+	// the sender was rekeyed to the second key
+	ledger := mockSpendableBalancesUnbounded{balance: 1 << 60, authAddrs: map[basics.Address]basics.Address{sender: rekeyed}}
+	transactionPool := MakeTransactionPool(ledger, exponentialGrowth, testPoolSize, 0, false)
+
+	payment := func(note byte) transactions.Transaction {
+		return transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     sender,
+				Fee:        basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid: 0,
+				LastValid:  10,
+				Note:       []byte{note},
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: attacker,
+				Amount:   basics.MicroAlgos{Raw: mockBalancesMinBalance},
+			},
+		}
+	}
+	signedBy := func(tx transactions.Transaction, signer int) transactions.SignedTxn {
+		stxn := tx.Sign(secrets[signer])
+		stxn.AuthAddr = addresses[signer]
+		return stxn
+	}
+
+	// neither a key that claims the sender as its own, nor the key of the sender before the rekey, is accepted
+	forged := signedBy(payment(0), 2)
+	require.Error(t, transactionPool.Test(forged))
+	require.Error(t, transactionPool.Remember(forged))
+	require.Error(t, transactionPool.Remember(payment(1).Sign(secrets[0])))
+	require.Equal(t, 0, transactionPool.PendingCount())
+
+	require.NoError(t, transactionPool.Remember(signedBy(payment(2), 1)))
+	require.Equal(t, 1, transactionPool.PendingCount())
+}
@@ -25,6 +25,8 @@ import (
 type txPriorityQueue struct {
 	pq         priorityQueue
 	txToPQItem map[transactions.Txid]*item
+	// nextSeq numbers the pushed transactions in arrival order, to break the ties between equal priorities.
+	nextSeq uint64
 }
 
 func makeTxPriorityQueue(sizeHint int) (tpq *txPriorityQueue) {
@@ -60,12 +62,22 @@ func (tpq *txPriorityQueue) Push(tx prioritizable) bool {
 		return false
 	}
 
-	item := item{value: tx.ID(), priority: tx.Priority()}
+	item := item{value: tx.ID(), priority: tx.Priority(), seq: tpq.nextSeq}
+	tpq.nextSeq++
 	heap.Push(&tpq.pq, &item)
 	tpq.txToPQItem[item.value] = &item
 	return true
 }
 
+// arrival returns the arrival sequence number of a transaction in the queue.
+func (tpq *txPriorityQueue) arrival(txid transactions.Txid) (seq uint64, ok bool) {
+	item, ok := tpq.txToPQItem[txid]
+	if !ok {
+		return 0, false
+	}
+	return item.seq, true
+}
+
 func (tpq *txPriorityQueue) Remove(txid transactions.Txid) {
 	item, hasItem := tpq.txToPQItem[txid]
 	if !hasItem {
@@ -87,6 +99,9 @@ type item struct {
 	// The priority of the item in the queue.
 	priority transactions.TxnPriority
 
+	// The arrival sequence number of the item; among items of equal priority, the latest arrival is the minimum.
+	seq uint64
+
 	// The index of the item in the heap.
 	// The index is needed by update and is maintained by the heap.Interface methods.
 	index int
@@ -99,6 +114,10 @@ type priorityQueue []*item
 func (pq priorityQueue) Len() int { return len(pq) }
 
 func (pq priorityQueue) Less(i, j int) bool {
+	if pq[i].priority == pq[j].priority {
+		// evict the latest arrivals first, so that the transactions paying the same fee are served in arrival order
+		return pq[i].seq > pq[j].seq
+	}
 	return pq[i].priority.LessThan(pq[j].priority)
 }
 
@@ -0,0 +1,367 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package transactions
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// AssetConfigTxnFields captures the fields used for asset
+// allocation, re-configuration, and destruction.
+type AssetConfigTxnFields struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	// ConfigAsset is the asset being configured or destroyed.
+	// A zero value means allocation: the asset is created by the
+	// sender, with the next index.
+	ConfigAsset basics.AssetID `codec:"caid"`
+
+	// AssetParams are the parameters for the asset being
+	// created or re-configured.  A zero value means destruction.
+	AssetParams basics.AssetParams `codec:"apar"`
+}
+
+// AssetTransferTxnFields captures the fields used for asset transfers.
+type AssetTransferTxnFields struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	XferAsset basics.AssetID `codec:"xaid"`
+
+	// AssetAmount is the amount of asset to transfer.
+	// A zero amount transferred to self allocates that asset
+	// in the account's Assets map.
+	AssetAmount uint64 `codec:"aamt"`
+
+	// AssetSender is the sender of the transfer.  If this is not
+	// a zero value, the real transaction sender must be the Clawback
+	// address from the AssetParams.  If this is the zero value,
+	// the asset is sent from the transaction's Sender.
+	AssetSender basics.Address `codec:"asnd"`
+
+	// AssetReceiver is the recipient of the transfer.
+	AssetReceiver basics.Address `codec:"arcv"`
+
+	// AssetCloseTo indicates that the asset should be removed
+	// from the account's Assets map, and specifies where the remaining
+	// asset holdings should be transferred.  It's always valid to transfer
+	// remaining asset holdings to the creator account.
+	AssetCloseTo basics.Address `codec:"aclose"`
+}
+
+// AssetFreezeTxnFields captures the fields used for freezing asset slots.
+type AssetFreezeTxnFields struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	// FreezeAccount is the address of the account whose asset
+	// slot is being frozen or un-frozen.
+	FreezeAccount basics.Address `codec:"fadd"`
+
+	// FreezeAsset is the asset ID being frozen or un-frozen.
+	FreezeAsset basics.AssetID `codec:"faid"`
+
+	// AssetFrozen is the new frozen value.
+	AssetFrozen bool `codec:"afrz"`
+}
+
+func (cc AssetConfigTxnFields) wellFormed(proto config.ConsensusParams) error {
+	if len(cc.AssetParams.UnitName) > proto.MaxAssetUnitNameBytes {
+		return fmt.Errorf("transaction asset unit name too big: %d > %d", len(cc.AssetParams.UnitName), proto.MaxAssetUnitNameBytes)
+	}
+	if len(cc.AssetParams.AssetName) > proto.MaxAssetNameBytes {
+		return fmt.Errorf("transaction asset name too big: %d > %d", len(cc.AssetParams.AssetName), proto.MaxAssetNameBytes)
+	}
+	if len(cc.AssetParams.URL) > proto.MaxAssetURLBytes {
+		return fmt.Errorf("transaction asset url too big: %d > %d", len(cc.AssetParams.URL), proto.MaxAssetURLBytes)
+	}
+	return nil
+}
+
+// cloneAssetHoldings returns a copy of assets that can be modified without
+// changing the holdings of the balance record it comes from.
+func cloneAssetHoldings(assets map[basics.AssetID]basics.AssetHolding) map[basics.AssetID]basics.AssetHolding {
+	res := make(map[basics.AssetID]basics.AssetHolding, len(assets)+1)
+	for id, holding := range assets {
+		res[id] = holding
+	}
+	return res
+}
+
+// cloneAssetParams returns a copy of params that can be modified without
+// changing the parameters of the balance record it comes from.
+func cloneAssetParams(params map[basics.AssetIndex]basics.AssetParams) map[basics.AssetIndex]basics.AssetParams {
+	res := make(map[basics.AssetIndex]basics.AssetParams, len(params)+1)
+	for index, p := range params {
+		res[index] = p
+	}
+	return res
+}
+
+// getParams returns the parameters of asset id, which must exist.
+func getParams(balances Balances, id basics.AssetID) (basics.AssetParams, error) {
+	creator, err := balances.Get(id.Creator)
+	if err != nil {
+		return basics.AssetParams{}, err
+	}
+
+	params, ok := creator.AssetParams[id.Index]
+	if !ok {
+		return basics.AssetParams{}, fmt.Errorf("asset %v does not exist", id)
+	}
+	return params, nil
+}
+
+// apply changes the balances according to this transaction.  ctr is the
+// number of transactions committed to the ledger before this one, from
+// which the index of a new asset is allocated.
+func (cc AssetConfigTxnFields) apply(header Header, balances Balances, spec SpecialAddresses, ad *ApplyData, ctr uint64) error {
+	proto := balances.ConsensusParams()
+	if proto.MaxAssetsPerAccount == 0 {
+		return fmt.Errorf("asset transactions not supported")
+	}
+
+	if cc.ConfigAsset == (basics.AssetID{}) {
+		// Allocating an asset.
+		record, err := balances.Get(header.Sender)
+		if err != nil {
+			return err
+		}
+		if len(record.AssetParams) >= proto.MaxAssetsPerAccount || len(record.Assets) >= proto.MaxAssetsPerAccount {
+			return fmt.Errorf("too many assets in account: %d created, %d held, maximum %d", len(record.AssetParams), len(record.Assets), proto.MaxAssetsPerAccount)
+		}
+
+		// Indices start at 1, the index of the first transaction.
+		index := basics.AssetIndex(ctr + 1)
+		record.AssetParams = cloneAssetParams(record.AssetParams)
+		record.AssetParams[index] = cc.AssetParams
+		record.Assets = cloneAssetHoldings(record.Assets)
+		record.Assets[basics.AssetID{Creator: header.Sender, Index: index}] = basics.AssetHolding{Amount: cc.AssetParams.Total}
+		return balances.Put(record)
+	}
+
+	// Re-configuration and destroying must be done by the manager key.
+	record, err := balances.Get(cc.ConfigAsset.Creator)
+	if err != nil {
+		return err
+	}
+	params, ok := record.AssetParams[cc.ConfigAsset.Index]
+	if !ok {
+		return fmt.Errorf("asset %v does not exist", cc.ConfigAsset)
+	}
+	if params.Manager == (basics.Address{}) || params.Manager != header.Sender {
+		return fmt.Errorf("transaction sender %v is not the manager of asset %v", header.Sender, cc.ConfigAsset)
+	}
+
+	record.AssetParams = cloneAssetParams(record.AssetParams)
+	if cc.AssetParams == (basics.AssetParams{}) {
+		// Destroying an asset.  The creator account must hold every unit.
+		record.Assets = cloneAssetHoldings(record.Assets)
+		holding := record.Assets[cc.ConfigAsset]
+		if holding.Amount != params.Total {
+			return fmt.Errorf("cannot destroy asset %v: creator holds %d of %d units", cc.ConfigAsset, holding.Amount, params.Total)
+		}
+		delete(record.Assets, cc.ConfigAsset)
+		delete(record.AssetParams, cc.ConfigAsset.Index)
+	} else {
+		// Changing keys in an asset.  A key that was cleared can't be set again.
+		if params.Manager != (basics.Address{}) {
+			params.Manager = cc.AssetParams.Manager
+		}
+		if params.Reserve != (basics.Address{}) {
+			params.Reserve = cc.AssetParams.Reserve
+		}
+		if params.Freeze != (basics.Address{}) {
+			params.Freeze = cc.AssetParams.Freeze
+		}
+		if params.Clawback != (basics.Address{}) {
+			params.Clawback = cc.AssetParams.Clawback
+		}
+		record.AssetParams[cc.ConfigAsset.Index] = params
+	}
+	return balances.Put(record)
+}
+
+// takeOut removes amount units of asset id from the holding of addr.
+func takeOut(balances Balances, addr basics.Address, id basics.AssetID, amount uint64, bypassFreeze bool) error {
+	record, err := balances.Get(addr)
+	if err != nil {
+		return err
+	}
+
+	holding, ok := record.Assets[id]
+	if !ok {
+		return fmt.Errorf("asset %v missing from %v", id, addr)
+	}
+	if holding.Frozen && !bypassFreeze {
+		return fmt.Errorf("asset %v frozen in %v", id, addr)
+	}
+	if holding.Amount < amount {
+		return fmt.Errorf("underflow on subtracting %d from sender amount %d", amount, holding.Amount)
+	}
+
+	holding.Amount -= amount
+	record.Assets = cloneAssetHoldings(record.Assets)
+	record.Assets[id] = holding
+	return balances.Put(record)
+}
+
+// putIn adds amount units of asset id to the holding of addr, which must
+// have accepted the asset.
+func putIn(balances Balances, addr basics.Address, id basics.AssetID, amount uint64, bypassFreeze bool) error {
+	record, err := balances.Get(addr)
+	if err != nil {
+		return err
+	}
+
+	holding, ok := record.Assets[id]
+	if !ok {
+		return fmt.Errorf("asset %v missing from %v", id, addr)
+	}
+	if holding.Frozen && !bypassFreeze {
+		return fmt.Errorf("asset %v frozen in %v", id, addr)
+	}
+
+	var overflowed bool
+	holding.Amount, overflowed = basics.OAdd(holding.Amount, amount)
+	if overflowed {
+		return fmt.Errorf("overflow on adding %d to receiver amount %d", amount, holding.Amount)
+	}
+	record.Assets = cloneAssetHoldings(record.Assets)
+	record.Assets[id] = holding
+	return balances.Put(record)
+}
+
+// apply changes the balances according to this transaction.
+func (ct AssetTransferTxnFields) apply(header Header, balances Balances, spec SpecialAddresses, ad *ApplyData) error {
+	proto := balances.ConsensusParams()
+	if proto.MaxAssetsPerAccount == 0 {
+		return fmt.Errorf("asset transactions not supported")
+	}
+
+	params, err := getParams(balances, ct.XferAsset)
+	if err != nil {
+		return err
+	}
+
+	// Accepting an asset: a transfer of zero units to oneself allocates
+	// the asset in the account.
+	if ct.AssetSender == (basics.Address{}) && ct.AssetReceiver == header.Sender && ct.AssetAmount == 0 && ct.AssetCloseTo == (basics.Address{}) {
+		record, err := balances.Get(header.Sender)
+		if err != nil {
+			return err
+		}
+		if _, ok := record.Assets[ct.XferAsset]; ok {
+			return nil
+		}
+		if len(record.Assets) >= proto.MaxAssetsPerAccount {
+			return fmt.Errorf("too many assets in account: %d held, maximum %d", len(record.Assets), proto.MaxAssetsPerAccount)
+		}
+		record.Assets = cloneAssetHoldings(record.Assets)
+		record.Assets[ct.XferAsset] = basics.AssetHolding{Frozen: params.DefaultFrozen}
+		return balances.Put(record)
+	}
+
+	// A clawback takes assets from AssetSender, regardless of freezing.
+	source := header.Sender
+	clawback := false
+	if ct.AssetSender != (basics.Address{}) {
+		if params.Clawback == (basics.Address{}) || params.Clawback != header.Sender {
+			return fmt.Errorf("clawback not allowed: sender %v is not the clawback address of asset %v", header.Sender, ct.XferAsset)
+		}
+		if ct.AssetCloseTo != (basics.Address{}) {
+			return fmt.Errorf("clawback cannot close %v out of asset %v", ct.AssetSender, ct.XferAsset)
+		}
+		source = ct.AssetSender
+		clawback = true
+	}
+
+	err = takeOut(balances, source, ct.XferAsset, ct.AssetAmount, clawback)
+	if err != nil {
+		return err
+	}
+	err = putIn(balances, ct.AssetReceiver, ct.XferAsset, ct.AssetAmount, clawback)
+	if err != nil {
+		return err
+	}
+
+	if ct.AssetCloseTo != (basics.Address{}) {
+		// The creator account holds the asset as long as the asset exists.
+		if source == ct.XferAsset.Creator {
+			return fmt.Errorf("cannot close asset %v out of its creator account", ct.XferAsset)
+		}
+
+		record, err := balances.Get(source)
+		if err != nil {
+			return err
+		}
+		remaining := record.Assets[ct.XferAsset].Amount
+		err = takeOut(balances, source, ct.XferAsset, remaining, false)
+		if err != nil {
+			return err
+		}
+		err = putIn(balances, ct.AssetCloseTo, ct.XferAsset, remaining, false)
+		if err != nil {
+			return err
+		}
+
+		record, err = balances.Get(source)
+		if err != nil {
+			return err
+		}
+		record.Assets = cloneAssetHoldings(record.Assets)
+		delete(record.Assets, ct.XferAsset)
+		err = balances.Put(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// apply changes the balances according to this transaction.
+func (cf AssetFreezeTxnFields) apply(header Header, balances Balances, spec SpecialAddresses, ad *ApplyData) error {
+	proto := balances.ConsensusParams()
+	if proto.MaxAssetsPerAccount == 0 {
+		return fmt.Errorf("asset transactions not supported")
+	}
+
+	// Only the freeze address can change the freeze value.
+	params, err := getParams(balances, cf.FreezeAsset)
+	if err != nil {
+		return err
+	}
+	if params.Freeze == (basics.Address{}) || params.Freeze != header.Sender {
+		return fmt.Errorf("freeze not allowed: sender %v is not the freeze address of asset %v", header.Sender, cf.FreezeAsset)
+	}
+
+	record, err := balances.Get(cf.FreezeAccount)
+	if err != nil {
+		return err
+	}
+	holding, ok := record.Assets[cf.FreezeAsset]
+	if !ok {
+		return fmt.Errorf("asset %v missing from %v", cf.FreezeAsset, cf.FreezeAccount)
+	}
+
+	holding.Frozen = cf.AssetFrozen
+	record.Assets = cloneAssetHoldings(record.Assets)
+	record.Assets[cf.FreezeAsset] = holding
+	return balances.Put(record)
+}
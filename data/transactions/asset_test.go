@@ -0,0 +1,157 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package transactions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestAssetLifecycle(t *testing.T) {
+	creator := basics.Address(keypair().SignatureVerifier)
+	holder := basics.Address(keypair().SignatureVerifier)
+	spec := SpecialAddresses{FeeSink: feeSink}
+	balances := keyregTestBalances{mockBalances{protocol.ConsensusFuture}, map[basics.Address]basics.BalanceRecord{}}
+
+	txn := func(sender basics.Address, txType protocol.TxType) Transaction {
+		return Transaction{
+			Type: txType,
+			Header: Header{
+				Sender:     sender,
+				Fee:        basics.MicroAlgos{Raw: 1000},
+				FirstValid: basics.Round(100),
+				LastValid:  basics.Round(1000),
+			},
+		}
+	}
+	apply := func(tx Transaction) error {
+		require.NoError(t, tx.WellFormed(spec, balances.ConsensusParams()))
+		_, err := tx.Apply(balances, spec, 41)
+		return err
+	}
+	// failed transactions are not committed, so roll back any partial changes
+	fails := func(tx Transaction) {
+		saved := make(map[basics.Address]basics.BalanceRecord, len(balances.records))
+		for addr, record := range balances.records {
+			saved[addr] = record
+		}
+		require.Error(t, apply(tx))
+		balances.records = saved
+	}
+
+	// assets are not supported by the current protocol
+	create := txn(creator, protocol.AssetConfigTx)
+	create.AssetParams = basics.AssetParams{Total: 100, UnitName: "tok", Manager: creator, Freeze: creator, Clawback: creator}
+	current := keyregTestBalances{mockBalances{protocol.ConsensusCurrentVersion}, map[basics.Address]basics.BalanceRecord{}}
+	require.Error(t, create.WellFormed(spec, current.ConsensusParams()))
+	_, err := create.Apply(current, spec, 41)
+	require.Error(t, err)
+
+	// the creator holds all the units of a new asset, whose index follows the transaction counter
+	require.NoError(t, apply(create))
+	id := basics.AssetID{Creator: creator, Index: 42}
+	require.Equal(t, create.AssetParams, balances.records[creator].AssetParams[42])
+	require.Equal(t, basics.AssetHolding{Amount: 100}, balances.records[creator].Assets[id])
+	created := balances.records[creator]
+
+	// the holder must accept the asset before receiving any
+	send := txn(creator, protocol.AssetTransferTx)
+	send.XferAsset = id
+	send.AssetAmount = 30
+	send.AssetReceiver = holder
+	fails(send)
+
+	accept := txn(holder, protocol.AssetTransferTx)
+	accept.XferAsset = id
+	accept.AssetReceiver = holder
+	require.NoError(t, apply(accept))
+	require.NoError(t, apply(send))
+	require.Equal(t, uint64(70), balances.records[creator].Assets[id].Amount)
+	require.Equal(t, uint64(30), balances.records[holder].Assets[id].Amount)
+
+	// the records already put are left alone
+	require.Equal(t, uint64(100), created.Assets[id].Amount)
+
+	// only the freeze address can freeze, and a frozen holding can't send
+	freeze := txn(holder, protocol.AssetFreezeTx)
+	freeze.FreezeAsset = id
+	freeze.FreezeAccount = holder
+	freeze.AssetFrozen = true
+	fails(freeze)
+	freeze.Sender = creator
+	require.NoError(t, apply(freeze))
+	require.True(t, balances.records[holder].Assets[id].Frozen)
+
+	sendBack := txn(holder, protocol.AssetTransferTx)
+	sendBack.XferAsset = id
+	sendBack.AssetAmount = 5
+	sendBack.AssetReceiver = creator
+	fails(sendBack)
+
+	// the clawback address takes units regardless of freezing
+	clawback := sendBack
+	clawback.Sender = creator
+	clawback.AssetSender = holder
+	require.NoError(t, apply(clawback))
+	require.Equal(t, uint64(25), balances.records[holder].Assets[id].Amount)
+
+	// the asset can't be destroyed while others hold units of it
+	destroy := txn(creator, protocol.AssetConfigTx)
+	destroy.ConfigAsset = id
+	fails(destroy)
+
+	// closing out sends the remaining units and removes the holding
+	freeze.AssetFrozen = false
+	require.NoError(t, apply(freeze))
+	closeOut := sendBack
+	closeOut.AssetCloseTo = creator
+	require.NoError(t, apply(closeOut))
+	require.NotContains(t, balances.records[holder].Assets, id)
+	require.Equal(t, uint64(100), balances.records[creator].Assets[id].Amount)
+
+	// the creator can't close out
+	require.NoError(t, apply(accept))
+	closeCreator := txn(creator, protocol.AssetTransferTx)
+	closeCreator.XferAsset = id
+	closeCreator.AssetReceiver = holder
+	closeCreator.AssetCloseTo = holder
+	fails(closeCreator)
+
+	// only the manager can reconfigure and destroy
+	reconfig := txn(holder, protocol.AssetConfigTx)
+	reconfig.ConfigAsset = id
+	reconfig.AssetParams = basics.AssetParams{Manager: creator, Freeze: holder}
+	fails(reconfig)
+	reconfig.Sender = creator
+	require.NoError(t, apply(reconfig))
+	params := balances.records[creator].AssetParams[42]
+	require.Equal(t, holder, params.Freeze)
+	require.Equal(t, basics.Address{}, params.Clawback)
+	require.Equal(t, uint64(100), params.Total)
+
+	destroy.Sender = holder
+	fails(destroy)
+	destroy.Sender = creator
+	require.NoError(t, apply(destroy))
+	require.Empty(t, balances.records[creator].AssetParams)
+	require.Empty(t, balances.records[creator].Assets)
+	require.True(t, balances.records[creator].IsZero())
+}
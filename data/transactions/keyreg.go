@@ -32,6 +32,10 @@ type KeyregTxnFields struct {
 	VoteFirst       basics.Round                    `codec:"votefst"`
 	VoteLast        basics.Round                    `codec:"votelst"`
 	VoteKeyDilution uint64                          `codec:"votekd"`
+
+	// Nonparticipation marks the sender as NotParticipating, which is irreversible: the account stops earning
+	// rewards and can never register participation keys again.
+	Nonparticipation bool `codec:"nonpart"`
 }
 
 // Apply changes the balances according to this transaction.
@@ -46,7 +50,12 @@ func (keyreg KeyregTxnFields) apply(header Header, balances Balances, spec Speci
 		return err
 	}
 
-	if !balances.ConsensusParams().ExplicitEphemeralParams {
+	params := balances.ConsensusParams()
+	if params.SupportBecomeNonParticipatingTransactions && record.Status == basics.NotParticipating {
+		return fmt.Errorf("cannot register participation keys for non-participating account %v", header.Sender)
+	}
+
+	if !params.ExplicitEphemeralParams {
 		if keyreg.VoteFirst != 0 {
 			return fmt.Errorf("keyreg VoteFirst=%d not allowed", keyreg.VoteFirst)
 		}
@@ -58,10 +67,19 @@ func (keyreg KeyregTxnFields) apply(header Header, balances Balances, spec Speci
 		}
 	}
 
-	// Update the registered keys and mark account as online (or, if the voting or selection keys are zero, offline)
+	// Update the registered keys and mark account as online (or, if the voting or selection keys are zero, offline,
+	// or, for a nonparticipation keyreg, non-participating)
 	record.VoteID = keyreg.VotePK
 	record.SelectionID = keyreg.SelectionPK
-	if (keyreg.VotePK == crypto.OneTimeSignatureVerifier{} || keyreg.SelectionPK == crypto.VRFVerifier{}) {
+	if keyreg.Nonparticipation {
+		if !params.SupportBecomeNonParticipatingTransactions {
+			return fmt.Errorf("transaction tries to mark an account as nonparticipating, but that transaction is not supported")
+		}
+		record.Status = basics.NotParticipating
+		record.VoteFirstValid = 0
+		record.VoteLastValid = 0
+		record.VoteKeyDilution = 0
+	} else if (keyreg.VotePK == crypto.OneTimeSignatureVerifier{} || keyreg.SelectionPK == crypto.VRFVerifier{}) {
 		record.Status = basics.Offline
 		record.VoteFirstValid = 0
 		record.VoteLastValid = 0
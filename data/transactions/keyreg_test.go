@@ -46,10 +46,82 @@ func TestKeyregApply(t *testing.T) {
 			SelectionPK: vrfSecrets.PK,
 		},
 	}
-	_, err := tx.Apply(mockBalances{protocol.ConsensusCurrentVersion}, SpecialAddresses{FeeSink: feeSink})
+	_, err := tx.Apply(mockBalances{protocol.ConsensusCurrentVersion}, SpecialAddresses{FeeSink: feeSink}, 0)
 	require.NoError(t, err)
 
 	tx.Sender = feeSink
-	_, err = tx.Apply(mockBalances{protocol.ConsensusCurrentVersion}, SpecialAddresses{FeeSink: feeSink})
+	_, err = tx.Apply(mockBalances{protocol.ConsensusCurrentVersion}, SpecialAddresses{FeeSink: feeSink}, 0)
 	require.Error(t, err)
 }
+
+// keyregTestBalances keeps the records put into it, so that status changes are visible
+type keyregTestBalances struct {
+	mockBalances
+	records map[basics.Address]basics.BalanceRecord
+}
+
+func (balances keyregTestBalances) Get(addr basics.Address) (basics.BalanceRecord, error) {
+	record, ok := balances.records[addr]
+	if !ok {
+		record.Addr = addr
+	}
+	return record, nil
+}
+
+func (balances keyregTestBalances) Put(record basics.BalanceRecord) error {
+	balances.records[record.Addr] = record
+	return nil
+}
+
+func TestKeyregNonparticipation(t *testing.T) {
+	secretSrc := keypair()
+	src := basics.Address(secretSrc.SignatureVerifier)
+	vrfSecrets := crypto.GenerateVRFSecrets()
+	secretParticipation := keypair()
+
+	nonpart := Transaction{
+		Type: protocol.KeyRegistrationTx,
+		Header: Header{
+			Sender:     src,
+			Fee:        basics.MicroAlgos{Raw: 1000},
+			FirstValid: basics.Round(100),
+			LastValid:  basics.Round(1000),
+		},
+		KeyregTxnFields: KeyregTxnFields{Nonparticipation: true},
+	}
+	online := nonpart
+	online.KeyregTxnFields = KeyregTxnFields{
+		VotePK:      crypto.OneTimeSignatureVerifier(secretParticipation.SignatureVerifier),
+		SelectionPK: vrfSecrets.PK,
+		VoteLast:    1000,
+	}
+
+	// not supported by the current protocol
+	current := keyregTestBalances{mockBalances{protocol.ConsensusCurrentVersion}, map[basics.Address]basics.BalanceRecord{}}
+	require.Error(t, nonpart.WellFormed(SpecialAddresses{FeeSink: feeSink}, current.ConsensusParams()))
+	_, err := nonpart.Apply(current, SpecialAddresses{FeeSink: feeSink}, 0)
+	require.Error(t, err)
+
+	future := keyregTestBalances{mockBalances{protocol.ConsensusFuture}, map[basics.Address]basics.BalanceRecord{}}
+	require.NoError(t, nonpart.WellFormed(SpecialAddresses{FeeSink: feeSink}, future.ConsensusParams()))
+	_, err = online.Apply(future, SpecialAddresses{FeeSink: feeSink}, 0)
+	require.NoError(t, err)
+	require.Equal(t, basics.Online, future.records[src].Status)
+
+	_, err = nonpart.Apply(future, SpecialAddresses{FeeSink: feeSink}, 0)
+	require.NoError(t, err)
+	require.Equal(t, basics.NotParticipating, future.records[src].Status)
+	require.Equal(t, crypto.OneTimeSignatureVerifier{}, future.records[src].VoteID)
+	require.Equal(t, basics.Round(0), future.records[src].VoteLastValid)
+
+	// marking an account as nonparticipating is irreversible
+	_, err = online.Apply(future, SpecialAddresses{FeeSink: feeSink}, 0)
+	require.Error(t, err)
+	_, err = nonpart.Apply(future, SpecialAddresses{FeeSink: feeSink}, 0)
+	require.Error(t, err)
+
+	// participation keys can't be registered along with nonparticipation
+	both := online
+	both.Nonparticipation = true
+	require.Error(t, both.WellFormed(SpecialAddresses{FeeSink: feeSink}, future.ConsensusParams()))
+}
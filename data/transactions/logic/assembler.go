@@ -0,0 +1,793 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+type labelReference struct {
+	sourceLine int
+
+	// position of the opcode start that refers to the label
+	position int
+
+	label string
+}
+
+// OpStream is destination for program and scratch space
+type OpStream struct {
+	Out   bytes.Buffer
+	intc  []uint64
+	bytec [][]byte
+
+	// whether the source has its own intcblock/bytecblock, which can't be
+	// mixed with the constants of the int/byte pseudo-ops
+	hasIntcBlock  bool
+	hasBytecBlock bool
+
+	// map label string to position within Out buffer
+	labels map[string]int
+
+	labelReferences []labelReference
+
+	sourceLine int
+}
+
+// SetLabelHere inserts a label reference to point to the next instruction
+func (ops *OpStream) SetLabelHere(label string) error {
+	if ops.labels == nil {
+		ops.labels = make(map[string]int)
+	}
+	if _, ok := ops.labels[label]; ok {
+		return fmt.Errorf("duplicate label %#v", label)
+	}
+	ops.labels[label] = ops.Out.Len()
+	return nil
+}
+
+// ReferToLabel records an opcode label refence to resolve later
+func (ops *OpStream) ReferToLabel(sourceLine, pc int, label string) {
+	ops.labelReferences = append(ops.labelReferences, labelReference{sourceLine, pc, label})
+}
+
+// Intc writes opcodes for loading a uint64 constant onto the stack.
+func (ops *OpStream) Intc(constIndex uint) error {
+	switch constIndex {
+	case 0:
+		ops.Out.WriteByte(0x22) // intc_0
+	case 1:
+		ops.Out.WriteByte(0x23) // intc_1
+	case 2:
+		ops.Out.WriteByte(0x24) // intc_2
+	case 3:
+		ops.Out.WriteByte(0x25) // intc_3
+	default:
+		if constIndex > 0xff {
+			return errors.New("cannot have more than 256 int constants")
+		}
+		ops.Out.WriteByte(0x21) // intc
+		ops.Out.WriteByte(uint8(constIndex))
+	}
+	return nil
+}
+
+// Uint writes opcodes for loading a uint literal
+func (ops *OpStream) Uint(val uint64) error {
+	found := false
+	var constIndex uint
+	for i, cv := range ops.intc {
+		if cv == val {
+			constIndex = uint(i)
+			found = true
+			break
+		}
+	}
+	if !found {
+		constIndex = uint(len(ops.intc))
+		ops.intc = append(ops.intc, val)
+	}
+	return ops.Intc(constIndex)
+}
+
+// Bytec writes opcodes for loading a []byte constant onto the stack.
+func (ops *OpStream) Bytec(constIndex uint) error {
+	switch constIndex {
+	case 0:
+		ops.Out.WriteByte(0x28) // bytec_0
+	case 1:
+		ops.Out.WriteByte(0x29) // bytec_1
+	case 2:
+		ops.Out.WriteByte(0x2a) // bytec_2
+	case 3:
+		ops.Out.WriteByte(0x2b) // bytec_3
+	default:
+		if constIndex > 0xff {
+			return errors.New("cannot have more than 256 byte constants")
+		}
+		ops.Out.WriteByte(0x27) // bytec
+		ops.Out.WriteByte(uint8(constIndex))
+	}
+	return nil
+}
+
+// ByteLiteral writes opcodes for loading a []byte literal
+func (ops *OpStream) ByteLiteral(val []byte) error {
+	found := false
+	var constIndex uint
+	for i, cv := range ops.bytec {
+		if bytes.Equal(cv, val) {
+			found = true
+			constIndex = uint(i)
+			break
+		}
+	}
+	if !found {
+		constIndex = uint(len(ops.bytec))
+		ops.bytec = append(ops.bytec, val)
+	}
+	return ops.Bytec(constIndex)
+}
+
+type assembleFunc func(ops *OpStream, spec OpSpec, args []string) error
+
+func assembleDefault(ops *OpStream, spec OpSpec, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%s expects no arguments", spec.Name)
+	}
+	return ops.Out.WriteByte(spec.Opcode)
+}
+
+// assembleInt is the int pseudo-op, which loads a constant through the
+// intcblock the assembler builds
+func assembleInt(ops *OpStream, args []string) error {
+	if len(args) != 1 {
+		return errors.New("int needs one argument")
+	}
+	// check txn type constants
+	val, ok := txnTypeToValue[args[0]]
+	if ok {
+		return ops.Uint(val)
+	}
+	val, err := strconv.ParseUint(args[0], 0, 64)
+	if err != nil {
+		return err
+	}
+	return ops.Uint(val)
+}
+
+// assembleByte is the byte pseudo-op, which loads a constant through the
+// bytecblock the assembler builds
+func assembleByte(ops *OpStream, args []string) error {
+	if len(args) == 0 {
+		return errors.New("byte operation needs byte literal argument")
+	}
+	val, err := parseBinaryArgs(args)
+	if err != nil {
+		return err
+	}
+	return ops.ByteLiteral(val)
+}
+
+// assembleAddr is the addr pseudo-op, which loads the 32 bytes of an
+// address given in its checksummed form
+func assembleAddr(ops *OpStream, args []string) error {
+	if len(args) != 1 {
+		return errors.New("addr operation needs one argument")
+	}
+	addr, err := basics.UnmarshalChecksumAddress(args[0])
+	if err != nil {
+		return err
+	}
+	return ops.ByteLiteral(addr[:])
+}
+
+// parseBinaryArgs decodes the byte string given in one of the forms:
+// base64 AAAA, b64 AAAA, base64(AAAA), b64(AAAA), base32 AAAA, b32 AAAA,
+// base32(AAAA), b32(AAAA), 0x0123 or "string"
+func parseBinaryArgs(args []string) ([]byte, error) {
+	arg := args[0]
+	if strings.HasPrefix(arg, "base32(") || strings.HasPrefix(arg, "b32(") {
+		if len(args) != 1 || !strings.HasSuffix(arg, ")") {
+			return nil, errors.New("byte base32(...) has no closing parenthesis")
+		}
+		open := strings.IndexRune(arg, '(')
+		return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(arg[open+1 : len(arg)-1])
+	}
+	if strings.HasPrefix(arg, "base64(") || strings.HasPrefix(arg, "b64(") {
+		if len(args) != 1 || !strings.HasSuffix(arg, ")") {
+			return nil, errors.New("byte base64(...) has no closing parenthesis")
+		}
+		open := strings.IndexRune(arg, '(')
+		return base64.StdEncoding.DecodeString(arg[open+1 : len(arg)-1])
+	}
+	if strings.HasPrefix(arg, "0x") {
+		if len(args) != 1 {
+			return nil, errors.New("byte 0x... takes one argument")
+		}
+		return hex.DecodeString(arg[2:])
+	}
+	if strings.HasPrefix(arg, "\"") {
+		if len(args) != 1 {
+			return nil, errors.New("byte \"...\" takes one argument")
+		}
+		str, err := strconv.Unquote(arg)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(str), nil
+	}
+	switch arg {
+	case "base32", "b32":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("byte %s needs one argument", arg)
+		}
+		return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(args[1])
+	case "base64", "b64":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("byte %s needs one argument", arg)
+		}
+		return base64.StdEncoding.DecodeString(args[1])
+	}
+	return nil, fmt.Errorf("byte arg did not parse: %v", arg)
+}
+
+// parseImmediate parses an immediate argument of op that must fit in a byte
+func parseImmediate(spec OpSpec, arg string) (uint8, error) {
+	val, err := strconv.ParseUint(arg, 0, 64)
+	if err != nil {
+		return 0, err
+	}
+	if val > 255 {
+		return 0, fmt.Errorf("%s argument %d is beyond 255", spec.Name, val)
+	}
+	return uint8(val), nil
+}
+
+func assembleIntCBlock(ops *OpStream, spec OpSpec, args []string) error {
+	if ops.hasIntcBlock {
+		return errors.New("intcblock may only appear once")
+	}
+	ops.hasIntcBlock = true
+	ops.Out.WriteByte(spec.Opcode)
+	var scratch [binary.MaxVarintLen64]byte
+	l := binary.PutUvarint(scratch[:], uint64(len(args)))
+	ops.Out.Write(scratch[:l])
+	for _, xs := range args {
+		cu, err := strconv.ParseUint(xs, 0, 64)
+		if err != nil {
+			return err
+		}
+		l = binary.PutUvarint(scratch[:], cu)
+		ops.Out.Write(scratch[:l])
+	}
+	return nil
+}
+
+func assembleByteCBlock(ops *OpStream, spec OpSpec, args []string) error {
+	if ops.hasBytecBlock {
+		return errors.New("bytecblock may only appear once")
+	}
+	ops.hasBytecBlock = true
+	ops.Out.WriteByte(spec.Opcode)
+	var scratch [binary.MaxVarintLen64]byte
+	l := binary.PutUvarint(scratch[:], uint64(len(args)))
+	ops.Out.Write(scratch[:l])
+	for _, xs := range args {
+		val, err := parseBinaryArgs([]string{xs})
+		if err != nil {
+			return err
+		}
+		l = binary.PutUvarint(scratch[:], uint64(len(val)))
+		ops.Out.Write(scratch[:l])
+		ops.Out.Write(val)
+	}
+	return nil
+}
+
+// assembleImmediate assembles an op that takes a single byte immediate
+func assembleImmediate(ops *OpStream, spec OpSpec, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s needs one argument", spec.Name)
+	}
+	val, err := parseImmediate(spec, args[0])
+	if err != nil {
+		return err
+	}
+	ops.Out.WriteByte(spec.Opcode)
+	ops.Out.WriteByte(val)
+	return nil
+}
+
+func assembleIntC(ops *OpStream, spec OpSpec, args []string) error {
+	return assembleImmediate(ops, spec, args)
+}
+
+func assembleByteC(ops *OpStream, spec OpSpec, args []string) error {
+	return assembleImmediate(ops, spec, args)
+}
+
+func assembleArg(ops *OpStream, spec OpSpec, args []string) error {
+	return assembleImmediate(ops, spec, args)
+}
+
+func assembleLoadStore(ops *OpStream, spec OpSpec, args []string) error {
+	return assembleImmediate(ops, spec, args)
+}
+
+func assembleTxn(ops *OpStream, spec OpSpec, args []string) error {
+	if len(args) != 1 {
+		return errors.New("txn expects one argument")
+	}
+	val, ok := txnFieldNameToValue[args[0]]
+	if !ok {
+		return fmt.Errorf("txn unknown arg %v", args[0])
+	}
+	ops.Out.WriteByte(spec.Opcode)
+	ops.Out.WriteByte(uint8(val))
+	return nil
+}
+
+func assembleGtxn(ops *OpStream, spec OpSpec, args []string) error {
+	if len(args) != 2 {
+		return errors.New("gtxn expects two arguments")
+	}
+	gtid, err := parseImmediate(spec, args[0])
+	if err != nil {
+		return err
+	}
+	val, ok := txnFieldNameToValue[args[1]]
+	if !ok {
+		return fmt.Errorf("gtxn unknown arg %v", args[1])
+	}
+	ops.Out.WriteByte(spec.Opcode)
+	ops.Out.WriteByte(gtid)
+	ops.Out.WriteByte(uint8(val))
+	return nil
+}
+
+func assembleGlobal(ops *OpStream, spec OpSpec, args []string) error {
+	if len(args) != 1 {
+		return errors.New("global expects one argument")
+	}
+	val, ok := globalFieldNameToValue[args[0]]
+	if !ok {
+		return fmt.Errorf("global unknown arg %v", args[0])
+	}
+	ops.Out.WriteByte(spec.Opcode)
+	ops.Out.WriteByte(uint8(val))
+	return nil
+}
+
+func assembleBnz(ops *OpStream, spec OpSpec, args []string) error {
+	if len(args) != 1 {
+		return errors.New("bnz operation needs label argument")
+	}
+	ops.ReferToLabel(ops.sourceLine, ops.Out.Len(), args[0])
+	ops.Out.WriteByte(spec.Opcode)
+	// zero bytes will get replaced with actual offset in resolveLabels()
+	ops.Out.WriteByte(0)
+	ops.Out.WriteByte(0)
+	return nil
+}
+
+// fieldsFromLine splits a line of source into whitespace separated fields,
+// keeping a double quoted string in one field and dropping // comments
+func fieldsFromLine(line string) []string {
+	var fields []string
+	start := -1
+	inQuote := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inQuote:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inQuote = false
+			}
+		case c == '"':
+			if start < 0 {
+				start = i
+			}
+			inQuote = true
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			if start >= 0 {
+				fields = append(fields, line[start:i])
+			}
+			return fields
+		case c == ' ' || c == '\t':
+			if start >= 0 {
+				fields = append(fields, line[start:i])
+				start = -1
+			}
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, line[start:])
+	}
+	return fields
+}
+
+// assemble reads text from an input and accumulates the program
+func (ops *OpStream) assemble(fin io.Reader) error {
+	scanner := bufio.NewScanner(fin)
+	ops.sourceLine = 0
+	for scanner.Scan() {
+		ops.sourceLine++
+		fields := fieldsFromLine(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		opstring := fields[0]
+		var err error
+		switch opstring {
+		case "int":
+			err = assembleInt(ops, fields[1:])
+		case "byte":
+			err = assembleByte(ops, fields[1:])
+		case "addr":
+			err = assembleAddr(ops, fields[1:])
+		default:
+			spec, ok := opsByName[opstring]
+			if ok {
+				err = spec.asm(ops, spec, fields[1:])
+			} else if strings.HasSuffix(opstring, ":") && len(fields) == 1 {
+				err = ops.SetLabelHere(opstring[:len(opstring)-1])
+			} else {
+				err = fmt.Errorf("unknown opcode %v", opstring)
+			}
+		}
+		if err != nil {
+			return lineErr(ops.sourceLine, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func (ops *OpStream) resolveLabels() error {
+	raw := ops.Out.Bytes()
+	for _, lr := range ops.labelReferences {
+		dest, ok := ops.labels[lr.label]
+		if !ok {
+			return lineErr(lr.sourceLine, fmt.Errorf("reference to undefined label %v", lr.label))
+		}
+		// all branch instructions (currently) are opcode byte and 2 offset bytes, and the destination is relative to the next pc as if the branch was not taken
+		naturalPc := lr.position + 3
+		if dest < naturalPc {
+			return lineErr(lr.sourceLine, fmt.Errorf("label %v is before reference but only forward jumps are allowed", lr.label))
+		}
+		jump := dest - naturalPc
+		if jump > 0x7fff {
+			return lineErr(lr.sourceLine, fmt.Errorf("label %v is too far away", lr.label))
+		}
+		raw[lr.position+1] = uint8(jump >> 8)
+		raw[lr.position+2] = uint8(jump & 0x0ff)
+	}
+	return nil
+}
+
+// Bytes returns the finished program bytes
+func (ops *OpStream) Bytes() (program []byte, err error) {
+	var scratch [binary.MaxVarintLen64]byte
+	prebytes := bytes.Buffer{}
+	vlen := binary.PutUvarint(scratch[:], LogicVersion)
+	prebytes.Write(scratch[:vlen])
+	if len(ops.intc) > 0 {
+		if ops.hasIntcBlock {
+			return nil, errors.New("int pseudo-ops can't be mixed with an explicit intcblock")
+		}
+		prebytes.WriteByte(0x20) // intcblock
+		vlen = binary.PutUvarint(scratch[:], uint64(len(ops.intc)))
+		prebytes.Write(scratch[:vlen])
+		for _, iv := range ops.intc {
+			vlen = binary.PutUvarint(scratch[:], iv)
+			prebytes.Write(scratch[:vlen])
+		}
+	}
+	if len(ops.bytec) > 0 {
+		if ops.hasBytecBlock {
+			return nil, errors.New("byte and addr pseudo-ops can't be mixed with an explicit bytecblock")
+		}
+		prebytes.WriteByte(0x26) // bytecblock
+		vlen = binary.PutUvarint(scratch[:], uint64(len(ops.bytec)))
+		prebytes.Write(scratch[:vlen])
+		for _, bv := range ops.bytec {
+			vlen = binary.PutUvarint(scratch[:], uint64(len(bv)))
+			prebytes.Write(scratch[:vlen])
+			prebytes.Write(bv)
+		}
+	}
+	// branch offsets are relative, so the constant blocks can go in front
+	// of the already assembled program
+	prebytes.Write(ops.Out.Bytes())
+	program = prebytes.Bytes()
+	return
+}
+
+func lineErr(line int, err error) error {
+	return fmt.Errorf("line %d: %s", line, err.Error())
+}
+
+// AssembleString takes an entire program in a string and assembles it to bytecode
+func AssembleString(text string) ([]byte, error) {
+	sr := strings.NewReader(text)
+	ops := OpStream{}
+	err := ops.assemble(sr)
+	if err != nil {
+		return nil, err
+	}
+	err = ops.resolveLabels()
+	if err != nil {
+		return nil, err
+	}
+	return ops.Bytes()
+}
+
+type disassembleState struct {
+	program []byte
+	pc      int
+	out     io.Writer
+
+	// labels of the branch targets seen so far, by pc
+	labels    map[int]string
+	nextLabel int
+	nextpc    int
+}
+
+type disassembleFunc func(dis *disassembleState, spec OpSpec) error
+
+func disDefault(dis *disassembleState, spec OpSpec) error {
+	dis.nextpc = dis.pc + 1
+	_, err := fmt.Fprintf(dis.out, "%s\n", spec.Name)
+	return err
+}
+
+var errShortIntcblock = errors.New("intcblock ran past end of program")
+var errTooManyIntc = errors.New("intcblock with too many items")
+
+func parseIntcblock(program []byte, pc int) (intc []uint64, nextpc int, err error) {
+	pos := pc + 1
+	numInts, bytesUsed := binary.Uvarint(program[pos:])
+	if bytesUsed <= 0 {
+		err = fmt.Errorf("could not decode int const block size at pc=%d", pos)
+		return
+	}
+	pos += bytesUsed
+	if numInts > uint64(len(program)) {
+		err = errTooManyIntc
+		return
+	}
+	intc = make([]uint64, numInts)
+	for i := uint64(0); i < numInts; i++ {
+		if pos >= len(program) {
+			err = errShortIntcblock
+			return
+		}
+		intc[i], bytesUsed = binary.Uvarint(program[pos:])
+		if bytesUsed <= 0 {
+			err = fmt.Errorf("could not decode int const[%d] at pc=%d", i, pos)
+			return
+		}
+		pos += bytesUsed
+	}
+	nextpc = pos
+	return
+}
+
+var errShortBytecblock = errors.New("bytecblock ran past end of program")
+var errTooManyItems = errors.New("bytecblock with too many items")
+
+func parseBytecBlock(program []byte, pc int) (bytec [][]byte, nextpc int, err error) {
+	pos := pc + 1
+	numItems, bytesUsed := binary.Uvarint(program[pos:])
+	if bytesUsed <= 0 {
+		err = fmt.Errorf("could not decode []byte const block size at pc=%d", pos)
+		return
+	}
+	pos += bytesUsed
+	if numItems > uint64(len(program)) {
+		err = errTooManyItems
+		return
+	}
+	bytec = make([][]byte, numItems)
+	for i := uint64(0); i < numItems; i++ {
+		if pos >= len(program) {
+			err = errShortBytecblock
+			return
+		}
+		itemLen, bytesUsed := binary.Uvarint(program[pos:])
+		if bytesUsed <= 0 {
+			err = fmt.Errorf("could not decode []byte const[%d] at pc=%d", i, pos)
+			return
+		}
+		pos += bytesUsed
+		end := uint64(pos) + itemLen
+		if end > uint64(len(program)) || end < uint64(pos) {
+			err = errShortBytecblock
+			return
+		}
+		bytec[i] = program[pos : pos+int(itemLen)]
+		pos += int(itemLen)
+	}
+	nextpc = pos
+	return
+}
+
+func disIntcblock(dis *disassembleState, spec OpSpec) error {
+	intc, nextpc, err := parseIntcblock(dis.program, dis.pc)
+	if err != nil {
+		return err
+	}
+	dis.nextpc = nextpc
+	_, err = dis.out.Write([]byte(spec.Name))
+	if err != nil {
+		return err
+	}
+	for _, iv := range intc {
+		_, err = fmt.Fprintf(dis.out, " %d", iv)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = dis.out.Write([]byte("\n"))
+	return err
+}
+
+func disBytecblock(dis *disassembleState, spec OpSpec) error {
+	bytec, nextpc, err := parseBytecBlock(dis.program, dis.pc)
+	if err != nil {
+		return err
+	}
+	dis.nextpc = nextpc
+	_, err = dis.out.Write([]byte(spec.Name))
+	if err != nil {
+		return err
+	}
+	for _, bv := range bytec {
+		_, err = fmt.Fprintf(dis.out, " 0x%s", hex.EncodeToString(bv))
+		if err != nil {
+			return err
+		}
+	}
+	_, err = dis.out.Write([]byte("\n"))
+	return err
+}
+
+// immediates returns the n immediate bytes of the op at pc
+func (dis *disassembleState) immediates(spec OpSpec, n int) ([]byte, error) {
+	if dis.pc+n >= len(dis.program) {
+		return nil, fmt.Errorf("program end while reading immediate of %s at pc=%d", spec.Name, dis.pc)
+	}
+	dis.nextpc = dis.pc + 1 + n
+	return dis.program[dis.pc+1 : dis.nextpc], nil
+}
+
+// disIntc disassembles an op with a single byte immediate
+func disIntc(dis *disassembleState, spec OpSpec) error {
+	imm, err := dis.immediates(spec, 1)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(dis.out, "%s %d\n", spec.Name, imm[0])
+	return err
+}
+
+func txnFieldName(field byte) string {
+	if int(field) >= len(TxnFieldNames) {
+		return fmt.Sprintf("invalid(%d)", field)
+	}
+	return TxnFieldNames[field]
+}
+
+func disTxn(dis *disassembleState, spec OpSpec) error {
+	imm, err := dis.immediates(spec, 1)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(dis.out, "%s %s\n", spec.Name, txnFieldName(imm[0]))
+	return err
+}
+
+func disGtxn(dis *disassembleState, spec OpSpec) error {
+	imm, err := dis.immediates(spec, 2)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(dis.out, "%s %d %s\n", spec.Name, imm[0], txnFieldName(imm[1]))
+	return err
+}
+
+func disGlobal(dis *disassembleState, spec OpSpec) error {
+	imm, err := dis.immediates(spec, 1)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("invalid(%d)", imm[0])
+	if int(imm[0]) < len(GlobalFieldNames) {
+		name = GlobalFieldNames[imm[0]]
+	}
+	_, err = fmt.Fprintf(dis.out, "%s %s\n", spec.Name, name)
+	return err
+}
+
+func disBnz(dis *disassembleState, spec OpSpec) error {
+	imm, err := dis.immediates(spec, 2)
+	if err != nil {
+		return err
+	}
+	offset := (int(imm[0]) << 8) | int(imm[1])
+	target := dis.nextpc + offset
+	label, ok := dis.labels[target]
+	if !ok {
+		label = fmt.Sprintf("label%d", dis.nextLabel+1)
+		dis.nextLabel++
+		dis.labels[target] = label
+	}
+	_, err = fmt.Fprintf(dis.out, "%s %s\n", spec.Name, label)
+	return err
+}
+
+// Disassemble produces a text form of a program, which AssembleString
+// assembles back to the same bytes.
+func Disassemble(program []byte) (text string, err error) {
+	out := strings.Builder{}
+	dis := disassembleState{program: program, out: &out, labels: make(map[int]string)}
+	version, vlen := binary.Uvarint(program)
+	if vlen <= 0 {
+		return "", errors.New("could not decode version")
+	}
+	if version > LogicVersion {
+		return "", fmt.Errorf("program version %d is beyond the supported version %d", version, LogicVersion)
+	}
+	dis.pc = vlen
+	for dis.pc < len(program) {
+		if label, ok := dis.labels[dis.pc]; ok {
+			fmt.Fprintf(dis.out, "%s:\n", label)
+		}
+		spec := opsByOpcode[program[dis.pc]]
+		if spec.Name == "" {
+			return out.String(), fmt.Errorf("invalid opcode %02x at pc=%d", program[dis.pc], dis.pc)
+		}
+		err = spec.dis(&dis, spec)
+		if err != nil {
+			return out.String(), err
+		}
+		dis.pc = dis.nextpc
+	}
+	// a branch may target the end of the program
+	if label, ok := dis.labels[dis.pc]; ok {
+		fmt.Fprintf(dis.out, "%s:\n", label)
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,206 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssemble(t *testing.T) {
+	// every op, with its immediates
+	text := `err
+sha256
+keccak256
+sha512_256
+ed25519verify
++
+-
+/
+*
+<
+>
+<=
+>=
+&&
+||
+==
+!=
+!
+len
+itob
+btoi
+%
+|
+&
+^
+~
+mulw
+intc 4
+intc_0
+intc_1
+intc_2
+intc_3
+bytec 4
+bytec_0
+bytec_1
+bytec_2
+bytec_3
+arg 4
+arg_0
+arg_1
+arg_2
+arg_3
+txn Sender
+txn TxID
+global MinTxnFee
+global GroupSize
+gtxn 1 Amount
+load 3
+store 3
+bnz done
+pop
+dup
+done:
+`
+	program, err := AssembleString(text)
+	require.NoError(t, err)
+	expected := "0100010203040809" + "0a0b0c0d0e0f1011" + "1213141516171819" + "1a1b1c1d" +
+		"2104" + "22232425" + "2704" + "28292a2b" + "2c04" + "2d2e2f30" +
+		"3100" + "3116" + "3200" + "3204" + "330106" + "3403" + "3503" +
+		"400002" + "4849"
+	require.Equal(t, expected, hex.EncodeToString(program))
+}
+
+func TestAssembleConstants(t *testing.T) {
+	text := `int 1
+int 0x10
+int 1
+int pay
+byte base64 aGVsbG8=
+byte b64(aGVsbG8=)
+byte 0x0102
+byte "a // b"
+addr 7777777777777777777777777777777777777777777777777774MSJUVU // all ones
+==
+`
+	program, err := AssembleString(text)
+	require.NoError(t, err)
+	// version, intcblock 1 16, bytecblock "hello" 0x0102 "a // b" 0xffff...ff, then the ops
+	expected := "01" + "20020110" + "2604" + "0568656c6c6f" + "020102" + "0661202f2f2062" +
+		"20" + "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+		"22232222" + "2828292a" + "2b" + "12"
+	require.Equal(t, expected, hex.EncodeToString(program))
+}
+
+func TestAssembleErrors(t *testing.T) {
+	sources := map[string]string{
+		"unknown opcode":     "frob",
+		"extra argument":     "sha256 1",
+		"bad int":            "int banana",
+		"bad byte":           "byte 0xzz",
+		"bad address":        "addr AAAA",
+		"undefined label":    "int 1\nbnz nowhere",
+		"backward jump":      "back:\nint 1\nbnz back",
+		"duplicate label":    "a:\na:",
+		"unknown txn field":  "txn Fame",
+		"unknown global":     "global Zero",
+		"big immediate":      "arg 256",
+		"mixed intcblock":    "intcblock 1\nint 2",
+		"mixed bytecblock":   "bytecblock 0x01\nbyte 0x02",
+		"repeated intcblock": "intcblock 1\nintcblock 2",
+	}
+	for name, text := range sources {
+		_, err := AssembleString(text)
+		require.Error(t, err, name)
+	}
+
+	_, err := AssembleString("int 1\nint 2\nfrob\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 3:")
+}
+
+func TestDisassembleRoundTrip(t *testing.T) {
+	text := `int 1
+int 2
++
+byte "x"
+len
+bnz ok
+err
+ok:
+txn Receiver
+gtxn 0 Amount
+global ZeroAddress
+store 1
+bnz end
+arg_0
+end:
+`
+	program, err := AssembleString(text)
+	require.NoError(t, err)
+
+	dis, err := Disassemble(program)
+	require.NoError(t, err)
+	require.Equal(t, `intcblock 1 2
+bytecblock 0x78
+intc_0
+intc_1
++
+bytec_0
+len
+bnz label1
+err
+label1:
+txn Receiver
+gtxn 0 Amount
+global ZeroAddress
+store 1
+bnz label2
+arg_0
+label2:
+`, dis)
+
+	again, err := AssembleString(dis)
+	require.NoError(t, err)
+	require.Equal(t, program, again)
+}
+
+func TestDisassembleErrors(t *testing.T) {
+	_, err := Disassemble([]byte{})
+	require.Error(t, err)
+	_, err = Disassemble([]byte{0x02})
+	require.Error(t, err)
+	_, err = Disassemble([]byte{0x01, 0xff})
+	require.Error(t, err)
+	_, err = Disassemble([]byte{0x01, 0x31})
+	require.Error(t, err)
+	_, err = Disassemble([]byte{0x01, 0x26, 0x01, 0x05, 0x00})
+	require.Error(t, err)
+}
+
+func TestHashProgram(t *testing.T) {
+	program, err := AssembleString("int 1")
+	require.NoError(t, err)
+	require.Equal(t, HashProgram(program), HashProgram(program))
+
+	other, err := AssembleString("int 2")
+	require.NoError(t, err)
+	require.NotEqual(t, HashProgram(program), HashProgram(other))
+}
@@ -0,0 +1,861 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"runtime"
+	"strings"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// MaxStackDepth should move to consensus params
+const MaxStackDepth = 1000
+
+// stackValue is the type for the operand stack.
+// Each stackValue is either a valid []byte value or a uint64 value.
+// If (.Bytes != nil) the stackValue is a []byte value, otherwise uint64 value.
+type stackValue struct {
+	Uint  uint64
+	Bytes []byte
+}
+
+func (sv *stackValue) argType() StackType {
+	if sv.Bytes != nil {
+		return StackBytes
+	}
+	return StackUint64
+}
+
+func (sv *stackValue) String() string {
+	if sv.Bytes != nil {
+		return fmt.Sprintf("0x%x", sv.Bytes)
+	}
+	return fmt.Sprintf("%d", sv.Uint)
+}
+
+// StackType describes the type of a value on the operand stack
+type StackType byte
+
+const (
+	// StackUint64 in an OpSpec shows that a value is a uint64
+	StackUint64 StackType = iota
+
+	// StackBytes in an OpSpec shows that a value is []byte
+	StackBytes
+)
+
+func (st StackType) String() string {
+	if st == StackBytes {
+		return "[]byte"
+	}
+	return "uint64"
+}
+
+// EvalParams contains data that comes into condition evaluation.
+type EvalParams struct {
+	// the transaction being evaluated
+	Txn *transactions.SignedTxn
+
+	Proto *config.ConsensusParams
+
+	// TxnGroup is the group of transactions the evaluated one is part of;
+	// it may be nil for a transaction that is not part of a group.
+	TxnGroup []transactions.SignedTxn
+
+	// GroupIndex is the index of Txn in TxnGroup
+	GroupIndex int
+
+	// optional debug tracing: each step of Eval writes the op it runs and
+	// the value on top of the stack after it
+	Trace *strings.Builder
+}
+
+type evalContext struct {
+	EvalParams
+
+	stack     []stackValue
+	program   []byte // txn.Lsig.Logic ?
+	pc        int
+	nextpc    int
+	err       error
+	intc      []uint64
+	bytec     [][]byte
+	version   uint64
+	scratch   [256]stackValue
+	stepCount int
+
+	// branch target labels of the Trace
+	traceLabels map[int]string
+}
+
+type opFunc func(cx *evalContext)
+
+// Eval checks to see if a transaction passes logic
+// A program passes successfully if it finishes with one int element on the stack that is non-zero.
+func Eval(program []byte, params EvalParams) (pass bool, err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			buf := make([]byte, 16*1024)
+			stlen := runtime.Stack(buf, false)
+			pass = false
+			err = PanicError{x, string(buf[:stlen])}
+		}
+	}()
+
+	var cx evalContext
+	version, vlen := binary.Uvarint(program)
+	if vlen <= 0 {
+		return false, errors.New("invalid version")
+	}
+	if version > LogicVersion || version > params.Proto.LogicSigVersion {
+		return false, fmt.Errorf("program version %d greater than protocol supported version %d", version, params.Proto.LogicSigVersion)
+	}
+	if version == 0 {
+		return false, errors.New("program version 0 is not supported")
+	}
+	cx.version = version
+	cx.pc = vlen
+	cx.EvalParams = params
+	cx.stack = make([]stackValue, 0, 10)
+	cx.program = program
+
+	for (cx.err == nil) && (cx.pc < len(cx.program)) {
+		cx.step()
+	}
+	if cx.err != nil {
+		return false, cx.err
+	}
+	if len(cx.stack) != 1 {
+		return false, fmt.Errorf("stack len is %d instead of 1", len(cx.stack))
+	}
+	if cx.stack[0].Bytes != nil {
+		return false, errors.New("stack finished with bytes not int")
+	}
+
+	return cx.stack[0].Uint != 0, nil
+}
+
+// Check should be faster than Eval.
+// Returns 'cost' which is an estimate of relative execution time.
+func Check(program []byte, params EvalParams) (cost int, err error) {
+	version, vlen := binary.Uvarint(program)
+	if vlen <= 0 {
+		return 0, errors.New("invalid version")
+	}
+	if version > LogicVersion || version > params.Proto.LogicSigVersion {
+		return 0, fmt.Errorf("program version %d greater than protocol supported version %d", version, params.Proto.LogicSigVersion)
+	}
+	if version == 0 {
+		return 0, errors.New("program version 0 is not supported")
+	}
+
+	pc := vlen
+	for pc < len(program) {
+		spec := opsByOpcode[program[pc]]
+		if spec.op == nil {
+			return 0, fmt.Errorf("illegal opcode %02x at pc=%d", program[pc], pc)
+		}
+		cost += spec.Cost
+
+		nextpc := pc + spec.Size
+		switch spec.Opcode {
+		case 0x20: // intcblock
+			_, nextpc, err = parseIntcblock(program, pc)
+		case 0x26: // bytecblock
+			_, nextpc, err = parseBytecBlock(program, pc)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if nextpc > len(program) {
+			return 0, fmt.Errorf("%s at pc=%d runs past the end of the program", spec.Name, pc)
+		}
+		if spec.Opcode == 0x40 { // bnz
+			offset := (int(program[pc+1]) << 8) | int(program[pc+2])
+			if nextpc+offset > len(program) {
+				return 0, fmt.Errorf("bnz at pc=%d branches beyond the end of the program", pc)
+			}
+		}
+		pc = nextpc
+	}
+	return cost, nil
+}
+
+// PanicError wraps a recover() catching a panic()
+type PanicError struct {
+	PanicValue interface{}
+	StackTrace string
+}
+
+func (pe PanicError) Error() string {
+	return fmt.Sprintf("panic in TEAL Eval: %v\n%s", pe.PanicValue, pe.StackTrace)
+}
+
+var errLoopDetected = errors.New("loop detected")
+
+func (cx *evalContext) step() {
+	opcode := cx.program[cx.pc]
+	spec := opsByOpcode[opcode]
+	if spec.op == nil {
+		cx.err = fmt.Errorf("illegal opcode %02x", opcode)
+		return
+	}
+	cx.nextpc = cx.pc + spec.Size
+	if spec.Size != 0 && cx.nextpc > len(cx.program) {
+		cx.err = fmt.Errorf("%s at pc=%d runs past the end of the program", spec.Name, cx.pc)
+		return
+	}
+	spec.op(cx)
+	if cx.Trace != nil {
+		cx.traceStep(spec)
+	}
+	if cx.err != nil {
+		cx.err = fmt.Errorf("%3d %s: %v", cx.pc, spec.Name, cx.err)
+		return
+	}
+	if len(cx.stack) > MaxStackDepth {
+		cx.err = errors.New("stack overflow")
+		return
+	}
+	cx.stepCount++
+	if cx.stepCount > len(cx.program) {
+		// branches only go forward, so a program can't take more steps than it has bytes
+		cx.err = errLoopDetected
+		return
+	}
+	cx.pc = cx.nextpc
+}
+
+// traceStep writes the op at pc, as it disassembles, and the top of the
+// stack left by it to the Trace
+func (cx *evalContext) traceStep(spec OpSpec) {
+	if cx.traceLabels == nil {
+		cx.traceLabels = make(map[int]string)
+	}
+	var line strings.Builder
+	dis := disassembleState{program: cx.program, pc: cx.pc, nextpc: cx.pc + spec.Size, out: &line, labels: cx.traceLabels}
+	if err := spec.dis(&dis, spec); err != nil {
+		line.Reset()
+		line.WriteString(spec.Name)
+	}
+	top := "<empty stack>"
+	if len(cx.stack) > 0 {
+		top = cx.stack[len(cx.stack)-1].String()
+	}
+	if cx.err != nil {
+		top = "ERROR: " + cx.err.Error()
+	}
+	fmt.Fprintf(cx.Trace, "%3d %-24s => %s\n", cx.pc, strings.TrimSpace(line.String()), top)
+}
+
+// need pops n values off the stack, failing if the stack is shorter
+func (cx *evalContext) need(n int) bool {
+	if len(cx.stack) < n {
+		cx.err = errors.New("stack underflow")
+		return false
+	}
+	return true
+}
+
+// needUints checks that the top n values of the stack are uint64
+func (cx *evalContext) needUints(n int) bool {
+	if !cx.need(n) {
+		return false
+	}
+	for _, sv := range cx.stack[len(cx.stack)-n:] {
+		if sv.Bytes != nil {
+			cx.err = errors.New("expected uint64 but got []byte")
+			return false
+		}
+	}
+	return true
+}
+
+// needBytes checks that the top n values of the stack are []byte
+func (cx *evalContext) needBytes(n int) bool {
+	if !cx.need(n) {
+		return false
+	}
+	for _, sv := range cx.stack[len(cx.stack)-n:] {
+		if sv.Bytes == nil {
+			cx.err = errors.New("expected []byte but got uint64")
+			return false
+		}
+	}
+	return true
+}
+
+func opErr(cx *evalContext) {
+	cx.err = errors.New("TEAL runtime encountered err opcode")
+}
+
+func opSHA256(cx *evalContext) {
+	if !cx.needBytes(1) {
+		return
+	}
+	last := len(cx.stack) - 1
+	hash := sha256.Sum256(cx.stack[last].Bytes)
+	cx.stack[last].Bytes = hash[:]
+}
+
+func opKeccak256(cx *evalContext) {
+	if !cx.needBytes(1) {
+		return
+	}
+	last := len(cx.stack) - 1
+	hash := crypto.Keccak256(cx.stack[last].Bytes)
+	cx.stack[last].Bytes = hash[:]
+}
+
+func opSHA512_256(cx *evalContext) {
+	if !cx.needBytes(1) {
+		return
+	}
+	last := len(cx.stack) - 1
+	hash := sha512.Sum512_256(cx.stack[last].Bytes)
+	cx.stack[last].Bytes = hash[:]
+}
+
+// binaryUint pops two uint64 values A and B and pushes f(A, B)
+func (cx *evalContext) binaryUint(f func(a, b uint64) (uint64, error)) {
+	if !cx.needUints(2) {
+		return
+	}
+	last := len(cx.stack) - 1
+	prev := last - 1
+	res, err := f(cx.stack[prev].Uint, cx.stack[last].Uint)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack[prev].Uint = res
+	cx.stack = cx.stack[:last]
+}
+
+func boolToUint(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func opPlus(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) {
+		sum, carry := bits.Add64(a, b, 0)
+		if carry != 0 {
+			return 0, errors.New("+ overflowed")
+		}
+		return sum, nil
+	})
+}
+
+func opMinus(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) {
+		if b > a {
+			return 0, errors.New("- would result negative")
+		}
+		return a - b, nil
+	})
+}
+
+func opDiv(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) {
+		if b == 0 {
+			return 0, errors.New("/ 0")
+		}
+		return a / b, nil
+	})
+}
+
+func opModulo(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) {
+		if b == 0 {
+			return 0, errors.New("% 0")
+		}
+		return a % b, nil
+	})
+}
+
+func opMul(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) {
+		hi, lo := bits.Mul64(a, b)
+		if hi != 0 {
+			return 0, errors.New("* overflowed")
+		}
+		return lo, nil
+	})
+}
+
+func opMulw(cx *evalContext) {
+	if !cx.needUints(2) {
+		return
+	}
+	last := len(cx.stack) - 1
+	prev := last - 1
+	hi, lo := bits.Mul64(cx.stack[prev].Uint, cx.stack[last].Uint)
+	cx.stack[prev].Uint = hi
+	cx.stack[last].Uint = lo
+}
+
+func opLt(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) { return boolToUint(a < b), nil })
+}
+
+func opGt(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) { return boolToUint(a > b), nil })
+}
+
+func opLe(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) { return boolToUint(a <= b), nil })
+}
+
+func opGe(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) { return boolToUint(a >= b), nil })
+}
+
+func opAnd(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) { return boolToUint(a != 0 && b != 0), nil })
+}
+
+func opOr(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) { return boolToUint(a != 0 || b != 0), nil })
+}
+
+func opBitOr(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) { return a | b, nil })
+}
+
+func opBitAnd(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) { return a & b, nil })
+}
+
+func opBitXor(cx *evalContext) {
+	cx.binaryUint(func(a, b uint64) (uint64, error) { return a ^ b, nil })
+}
+
+func opEq(cx *evalContext) {
+	if !cx.need(2) {
+		return
+	}
+	last := len(cx.stack) - 1
+	prev := last - 1
+	ta := cx.stack[prev].argType()
+	tb := cx.stack[last].argType()
+	if ta != tb {
+		cx.err = fmt.Errorf("cannot compare (%s to %s)", ta, tb)
+		return
+	}
+	var cond bool
+	if ta == StackBytes {
+		cond = bytes.Equal(cx.stack[prev].Bytes, cx.stack[last].Bytes)
+	} else {
+		cond = cx.stack[prev].Uint == cx.stack[last].Uint
+	}
+	cx.stack[prev] = stackValue{Uint: boolToUint(cond)}
+	cx.stack = cx.stack[:last]
+}
+
+func opNeq(cx *evalContext) {
+	opEq(cx)
+	if cx.err != nil {
+		return
+	}
+	last := len(cx.stack) - 1
+	cx.stack[last].Uint = boolToUint(cx.stack[last].Uint == 0)
+}
+
+func opNot(cx *evalContext) {
+	if !cx.needUints(1) {
+		return
+	}
+	last := len(cx.stack) - 1
+	cx.stack[last].Uint = boolToUint(cx.stack[last].Uint == 0)
+}
+
+func opBitNot(cx *evalContext) {
+	if !cx.needUints(1) {
+		return
+	}
+	last := len(cx.stack) - 1
+	cx.stack[last].Uint = ^cx.stack[last].Uint
+}
+
+func opLen(cx *evalContext) {
+	if !cx.needBytes(1) {
+		return
+	}
+	last := len(cx.stack) - 1
+	cx.stack[last] = stackValue{Uint: uint64(len(cx.stack[last].Bytes))}
+}
+
+func opItob(cx *evalContext) {
+	if !cx.needUints(1) {
+		return
+	}
+	last := len(cx.stack) - 1
+	ibytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(ibytes, cx.stack[last].Uint)
+	cx.stack[last] = stackValue{Bytes: ibytes}
+}
+
+func opBtoi(cx *evalContext) {
+	if !cx.needBytes(1) {
+		return
+	}
+	last := len(cx.stack) - 1
+	ibytes := cx.stack[last].Bytes
+	if len(ibytes) > 8 {
+		cx.err = fmt.Errorf("btoi arg too long, got %d bytes", len(ibytes))
+		return
+	}
+	value := uint64(0)
+	for _, b := range ibytes {
+		value = value << 8
+		value = value | (uint64(b) & 0x0ff)
+	}
+	cx.stack[last] = stackValue{Uint: value}
+}
+
+func opIntConstBlock(cx *evalContext) {
+	cx.intc, cx.nextpc, cx.err = parseIntcblock(cx.program, cx.pc)
+}
+
+func opIntConstN(cx *evalContext, n uint) {
+	if n >= uint(len(cx.intc)) {
+		cx.err = fmt.Errorf("intc [%d] beyond %d constants", n, len(cx.intc))
+		return
+	}
+	cx.stack = append(cx.stack, stackValue{Uint: cx.intc[n]})
+}
+
+func opIntConstLoad(cx *evalContext) {
+	opIntConstN(cx, uint(cx.program[cx.pc+1]))
+}
+
+func opIntConst0(cx *evalContext) {
+	opIntConstN(cx, 0)
+}
+
+func opIntConst1(cx *evalContext) {
+	opIntConstN(cx, 1)
+}
+
+func opIntConst2(cx *evalContext) {
+	opIntConstN(cx, 2)
+}
+
+func opIntConst3(cx *evalContext) {
+	opIntConstN(cx, 3)
+}
+
+func opByteConstBlock(cx *evalContext) {
+	cx.bytec, cx.nextpc, cx.err = parseBytecBlock(cx.program, cx.pc)
+}
+
+func opByteConstN(cx *evalContext, n uint) {
+	if n >= uint(len(cx.bytec)) {
+		cx.err = fmt.Errorf("bytec [%d] beyond %d constants", n, len(cx.bytec))
+		return
+	}
+	cx.stack = append(cx.stack, stackValue{Bytes: cx.bytec[n]})
+}
+
+func opByteConstLoad(cx *evalContext) {
+	opByteConstN(cx, uint(cx.program[cx.pc+1]))
+}
+
+func opByteConst0(cx *evalContext) {
+	opByteConstN(cx, 0)
+}
+
+func opByteConst1(cx *evalContext) {
+	opByteConstN(cx, 1)
+}
+
+func opByteConst2(cx *evalContext) {
+	opByteConstN(cx, 2)
+}
+
+func opByteConst3(cx *evalContext) {
+	opByteConstN(cx, 3)
+}
+
+func opArgN(cx *evalContext, n uint64) {
+	if n >= uint64(len(cx.Txn.Lsig.Args)) {
+		cx.err = fmt.Errorf("cannot load arg[%d] of %d", n, len(cx.Txn.Lsig.Args))
+		return
+	}
+	val := nilToEmpty(cx.Txn.Lsig.Args[n])
+	cx.stack = append(cx.stack, stackValue{Bytes: val})
+}
+
+func opArg(cx *evalContext) {
+	opArgN(cx, uint64(cx.program[cx.pc+1]))
+}
+
+func opArg0(cx *evalContext) {
+	opArgN(cx, 0)
+}
+
+func opArg1(cx *evalContext) {
+	opArgN(cx, 1)
+}
+
+func opArg2(cx *evalContext) {
+	opArgN(cx, 2)
+}
+
+func opArg3(cx *evalContext) {
+	opArgN(cx, 3)
+}
+
+func opBnz(cx *evalContext) {
+	if !cx.needUints(1) {
+		return
+	}
+	last := len(cx.stack) - 1
+	isNonZero := cx.stack[last].Uint != 0
+	cx.stack = cx.stack[:last] // pop
+	if isNonZero {
+		offset := (uint(cx.program[cx.pc+1]) << 8) | uint(cx.program[cx.pc+2])
+		target := cx.nextpc + int(offset)
+		if target > len(cx.program) {
+			cx.err = fmt.Errorf("bnz target beyond end of program")
+			return
+		}
+		cx.nextpc = target
+	}
+}
+
+func opPop(cx *evalContext) {
+	if !cx.need(1) {
+		return
+	}
+	cx.stack = cx.stack[:len(cx.stack)-1]
+}
+
+func opDup(cx *evalContext) {
+	if !cx.need(1) {
+		return
+	}
+	cx.stack = append(cx.stack, cx.stack[len(cx.stack)-1])
+}
+
+func opLoad(cx *evalContext) {
+	gi := cx.program[cx.pc+1]
+	cx.stack = append(cx.stack, cx.scratch[gi])
+}
+
+func opStore(cx *evalContext) {
+	if !cx.need(1) {
+		return
+	}
+	gi := cx.program[cx.pc+1]
+	last := len(cx.stack) - 1
+	cx.scratch[gi] = cx.stack[last]
+	cx.stack = cx.stack[:last]
+}
+
+// nilToEmpty makes sure a []byte value is not nil, which is how the stack
+// tells it apart from a uint64
+func nilToEmpty(x []byte) []byte {
+	if x == nil {
+		return make([]byte, 0)
+	}
+	return x
+}
+
+func typeEnumValue(txType protocol.TxType) uint64 {
+	for i, tt := range TxnTypeNames {
+		if tt == txType {
+			return uint64(i)
+		}
+	}
+	return 0
+}
+
+func (cx *evalContext) txnFieldToStack(txn *transactions.Transaction, field uint64, groupIndex int) (sv stackValue, err error) {
+	err = nil
+	switch TxnField(field) {
+	case Sender:
+		sv.Bytes = txn.Sender[:]
+	case Fee:
+		sv.Uint = txn.Fee.Raw
+	case FirstValid:
+		sv.Uint = uint64(txn.FirstValid)
+	case LastValid:
+		sv.Uint = uint64(txn.LastValid)
+	case Note:
+		sv.Bytes = nilToEmpty(txn.Note)
+	case Receiver:
+		sv.Bytes = txn.Receiver[:]
+	case Amount:
+		sv.Uint = txn.Amount.Raw
+	case CloseRemainderTo:
+		sv.Bytes = txn.CloseRemainderTo[:]
+	case VotePK:
+		sv.Bytes = txn.VotePK[:]
+	case SelectionPK:
+		sv.Bytes = txn.SelectionPK[:]
+	case VoteFirst:
+		sv.Uint = uint64(txn.VoteFirst)
+	case VoteLast:
+		sv.Uint = uint64(txn.VoteLast)
+	case VoteKeyDilution:
+		sv.Uint = txn.VoteKeyDilution
+	case Type:
+		sv.Bytes = []byte(txn.Type)
+	case TypeEnum:
+		sv.Uint = typeEnumValue(txn.Type)
+	case XferAsset:
+		sv.Uint = uint64(txn.XferAsset.Index)
+	case XferAssetCreator:
+		sv.Bytes = txn.XferAsset.Creator[:]
+	case AssetAmount:
+		sv.Uint = txn.AssetAmount
+	case AssetSender:
+		sv.Bytes = txn.AssetSender[:]
+	case AssetReceiver:
+		sv.Bytes = txn.AssetReceiver[:]
+	case AssetCloseTo:
+		sv.Bytes = txn.AssetCloseTo[:]
+	case GroupIndex:
+		sv.Uint = uint64(groupIndex)
+	case TxID:
+		txid := txn.ID()
+		sv.Bytes = txid[:]
+	case Lease:
+		sv.Bytes = txn.Lease[:]
+	case RekeyTo:
+		sv.Bytes = txn.RekeyTo[:]
+	default:
+		err = fmt.Errorf("invalid txn field %d", field)
+	}
+	return
+}
+
+func opTxn(cx *evalContext) {
+	field := uint64(cx.program[cx.pc+1])
+	sv, err := cx.txnFieldToStack(&cx.Txn.Txn, field, cx.GroupIndex)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack = append(cx.stack, sv)
+}
+
+func opGtxn(cx *evalContext) {
+	gtxid := int(cx.program[cx.pc+1])
+	if gtxid >= len(cx.TxnGroup) {
+		cx.err = fmt.Errorf("gtxn lookup TxnGroup[%d] but it only has %d", gtxid, len(cx.TxnGroup))
+		return
+	}
+	txn := &cx.TxnGroup[gtxid].Txn
+	field := uint64(cx.program[cx.pc+2])
+	sv, err := cx.txnFieldToStack(txn, field, gtxid)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack = append(cx.stack, sv)
+}
+
+var zeroAddress basics.Address
+
+func (cx *evalContext) globalFieldToStack(field GlobalField) (sv stackValue, err error) {
+	switch field {
+	case MinTxnFee:
+		sv.Uint = cx.Proto.MinTxnFee
+	case MinBalance:
+		sv.Uint = cx.Proto.MinBalance
+	case MaxTxnLife:
+		sv.Uint = cx.Proto.MaxTxnLife
+	case ZeroAddress:
+		sv.Bytes = zeroAddress[:]
+	case GroupSize:
+		sv.Uint = uint64(len(cx.TxnGroup))
+		if cx.TxnGroup == nil {
+			sv.Uint = 1
+		}
+	default:
+		err = fmt.Errorf("invalid global[%d]", field)
+	}
+	return
+}
+
+func opGlobal(cx *evalContext) {
+	gindex := uint64(cx.program[cx.pc+1])
+	globalField := GlobalField(gindex)
+	sv, err := cx.globalFieldToStack(globalField)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack = append(cx.stack, sv)
+}
+
+// Msg is data meant to be signed and then verified with the
+// ed25519verify opcode.
+type Msg struct {
+	_struct     struct{}      `codec:",omitempty,omitemptyarray"`
+	ProgramHash crypto.Digest `codec:"p"`
+	Data        []byte        `codec:"d"`
+}
+
+// ToBeHashed implements crypto.Hashable
+func (msg Msg) ToBeHashed() (protocol.HashID, []byte) {
+	return protocol.ProgramData, append(msg.ProgramHash[:], msg.Data...)
+}
+
+func opEd25519verify(cx *evalContext) {
+	if !cx.needBytes(3) {
+		return
+	}
+	last := len(cx.stack) - 1 // index of PK
+	prev := last - 1          // index of signature
+	pprev := prev - 1         // index of data
+
+	var sv crypto.SignatureVerifier
+	if len(cx.stack[last].Bytes) != len(sv) {
+		cx.err = errors.New("invalid public key")
+		return
+	}
+	copy(sv[:], cx.stack[last].Bytes)
+
+	var sig crypto.Signature
+	if len(cx.stack[prev].Bytes) != len(sig) {
+		cx.err = errors.New("invalid signature")
+		return
+	}
+	copy(sig[:], cx.stack[prev].Bytes)
+
+	msg := Msg{ProgramHash: HashProgram(cx.program), Data: cx.stack[pprev].Bytes}
+	cx.stack[pprev] = stackValue{Uint: boolToUint(sv.Verify(msg, sig))}
+	cx.stack = cx.stack[:prev]
+}
@@ -0,0 +1,302 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func testEvalParams(txn *transactions.SignedTxn) EvalParams {
+	proto := config.Consensus[protocol.ConsensusFuture]
+	return EvalParams{Txn: txn, Proto: &proto}
+}
+
+// assembleLines assembles a program written with "; " between its lines
+func assembleLines(t *testing.T, text string) []byte {
+	program, err := AssembleString(strings.Replace(text, "; ", "\n", -1))
+	require.NoError(t, err, text)
+	return program
+}
+
+func testEval(t *testing.T, text string, params EvalParams) (bool, error) {
+	program := assembleLines(t, text)
+	cost, err := Check(program, params)
+	require.NoError(t, err, text)
+	require.True(t, cost > 0)
+	return Eval(program, params)
+}
+
+func TestEvalArithmetic(t *testing.T) {
+	params := testEvalParams(&transactions.SignedTxn{})
+	passing := []string{
+		"int 1",
+		"int 2; int 3; +; int 5; ==",
+		"int 7; int 3; -; int 4; ==",
+		"int 7; int 2; /; int 3; ==",
+		"int 7; int 2; %; int 1; ==",
+		"int 6; int 7; *; int 42; ==",
+		"int 1; int 2; <; int 2; int 1; >; &&",
+		"int 0; int 1; ||",
+		"int 0; !",
+		"int 12; int 10; &; int 8; ==",
+		"int 12; int 10; |; int 14; ==",
+		"int 12; int 10; ^; int 6; ==",
+		"int 0; ~; int 0xffffffffffffffff; ==",
+		// mulw leaves the low word on top of the high word
+		"int 0x100000000; int 0x100000000; mulw; int 0; ==; store 0; int 1; ==; load 0; &&",
+		"int 1; itob; btoi",
+		"byte 0x0102; len; int 2; ==",
+		"byte 0x01; byte 0x01; ==",
+		"byte 0x01; byte 0x02; !=",
+		"int 5; store 7; load 7; int 5; ==",
+		"int 1; dup; pop",
+		"int 1; bnz done; err; done:; int 1",
+	}
+	for _, text := range passing {
+		pass, err := testEval(t, text, params)
+		require.NoError(t, err, text)
+		require.True(t, pass, text)
+	}
+
+	rejected := []string{
+		"int 0",
+		"int 1; int 1",
+		"byte 0x01",
+	}
+	for _, text := range rejected {
+		pass, _ := testEval(t, text, params)
+		require.False(t, pass, text)
+	}
+
+	failing := []string{
+		"err",
+		"int 0xffffffffffffffff; int 1; +",
+		"int 1; int 2; -",
+		"int 1; int 0; /",
+		"int 1; int 0; %",
+		"int 0x100000000; int 0x100000000; *",
+		"+",
+		"int 1; byte 0x01; ==",
+		"byte 0x010203040506070809; btoi",
+		"intc 3",
+		"arg 0",
+	}
+	for _, text := range failing {
+		pass, err := testEval(t, text, params)
+		require.Error(t, err, text)
+		require.False(t, pass, text)
+	}
+}
+
+func TestEvalHashes(t *testing.T) {
+	params := testEvalParams(&transactions.SignedTxn{})
+	pass, err := testEval(t, `byte "abc"
+sha256
+byte 0xba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad
+==
+byte "abc"
+keccak256
+byte 0x4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45
+==
+&&
+byte "abc"
+sha512_256
+byte 0x53048e2681941ef99b2e29b76b4c7dabe4c2d0c634fc6d46e0e2f13107e7af23
+==
+&&`, params)
+	require.NoError(t, err)
+	require.True(t, pass)
+}
+
+func TestEvalTxnFields(t *testing.T) {
+	var sender, receiver, rekeyTo basics.Address
+	crypto.RandBytes(sender[:])
+	crypto.RandBytes(receiver[:])
+	crypto.RandBytes(rekeyTo[:])
+	txn := transactions.SignedTxn{
+		Txn: transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     sender,
+				Fee:        basics.MicroAlgos{Raw: 1000},
+				FirstValid: 10,
+				LastValid:  20,
+				Lease:      [32]byte{1, 2, 3},
+				RekeyTo:    rekeyTo,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: receiver,
+				Amount:   basics.MicroAlgos{Raw: 5000},
+			},
+		},
+		Lsig: transactions.LogicSig{Args: [][]byte{[]byte("secret")}},
+	}
+	params := testEvalParams(&txn)
+	params.TxnGroup = []transactions.SignedTxn{txn, txn}
+	params.GroupIndex = 1
+	txid := txn.ID()
+
+	pass, err := testEval(t, `txn Sender
+addr `+sender.GetUserAddress()+`
+==
+txn Receiver
+addr `+receiver.GetUserAddress()+`
+==
+&&
+txn Amount
+int 5000
+==
+&&
+txn Fee
+global MinTxnFee
+>=
+&&
+txn TypeEnum
+int pay
+==
+&&
+txn GroupIndex
+int 1
+==
+&&
+global GroupSize
+int 2
+==
+&&
+gtxn 0 LastValid
+int 20
+==
+&&
+txn TxID
+byte 0x`+hex.EncodeToString(txid[:])+`
+==
+&&
+txn Lease
+byte 0x`+hex.EncodeToString(txn.Txn.Lease[:])+`
+==
+&&
+txn RekeyTo
+addr `+rekeyTo.GetUserAddress()+`
+==
+&&
+arg_0
+byte "secret"
+==
+&&`, params)
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	// gtxn beyond the group
+	pass, err = testEval(t, "gtxn 2 Amount; int 0; ==", params)
+	require.Error(t, err)
+	require.False(t, pass)
+}
+
+func TestEvalEd25519verify(t *testing.T) {
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	secrets := crypto.GenerateSignatureSecrets(seed)
+
+	text := "arg 0; arg 1; addr " + basics.Address(secrets.SignatureVerifier).GetUserAddress() + "; ed25519verify"
+	program := assembleLines(t, text)
+
+	data := []byte("data to verify")
+	sig := secrets.Sign(Msg{ProgramHash: HashProgram(program), Data: data})
+
+	txn := transactions.SignedTxn{}
+	txn.Lsig.Args = [][]byte{data, sig[:]}
+	pass, err := Eval(program, testEvalParams(&txn))
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	// a signature of the data alone, not bound to the program, is rejected
+	sig = secrets.SignBytes(data)
+	txn.Lsig.Args = [][]byte{data, sig[:]}
+	pass, err = Eval(program, testEvalParams(&txn))
+	require.NoError(t, err)
+	require.False(t, pass)
+}
+
+func TestCheck(t *testing.T) {
+	params := testEvalParams(&transactions.SignedTxn{})
+
+	program := assembleLines(t, "byte 0x01; sha256; len; int 32; ==")
+	cost, err := Check(program, params)
+	require.NoError(t, err)
+	// the constant blocks count along with the five ops
+	require.Equal(t, 2+1+7+1+1+1, cost)
+
+	_, err = Check([]byte{0x01, 0xff}, params)
+	require.Error(t, err)
+
+	// a bnz whose target is beyond the end of the program
+	_, err = Check([]byte{0x01, 0x22, 0x40, 0x00, 0x05}, params)
+	require.Error(t, err)
+
+	// truncated immediate
+	_, err = Check([]byte{0x01, 0x31}, params)
+	require.Error(t, err)
+
+	// unsupported versions
+	_, err = Check([]byte{0x00, 0x22}, params)
+	require.Error(t, err)
+	_, err = Check([]byte{0x02, 0x22}, params)
+	require.Error(t, err)
+
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	params.Proto = &proto
+	_, err = Check(program, params)
+	require.Error(t, err)
+	pass, err := Eval(program, params)
+	require.Error(t, err)
+	require.False(t, pass)
+}
+
+func TestEvalTrace(t *testing.T) {
+	program := assembleLines(t, "int 2; int 3; +; txn Fee; ==")
+	txn := transactions.SignedTxn{}
+	txn.Txn.Fee.Raw = 5
+	params := testEvalParams(&txn)
+	params.Trace = &strings.Builder{}
+	pass, err := Eval(program, params)
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	lines := strings.Split(strings.TrimSpace(params.Trace.String()), "\n")
+	require.Len(t, lines, 6)
+	require.Contains(t, lines[0], "intcblock 2 3")
+	require.Contains(t, lines[3], "+")
+	require.True(t, strings.HasSuffix(lines[3], "=> 5"))
+	require.Contains(t, lines[4], "txn Fee")
+	require.True(t, strings.HasSuffix(lines[5], "=> 1"))
+
+	// a failing op shows its error
+	params.Trace = &strings.Builder{}
+	_, err = Eval(assembleLines(t, "int 1; int 0; /"), params)
+	require.Error(t, err)
+	require.Contains(t, params.Trace.String(), "ERROR: / 0")
+}
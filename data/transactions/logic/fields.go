@@ -0,0 +1,144 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// TxnField is an enum type for `txn` and `gtxn`
+type TxnField int
+
+// The fields of a transaction a program can read. The values are part of
+// the bytecode, so new fields are only ever added at the end.
+const (
+	// Sender Transaction.Sender
+	Sender TxnField = iota
+	// Fee Transaction.Fee
+	Fee
+	// FirstValid Transaction.FirstValid
+	FirstValid
+	// LastValid Transaction.LastValid
+	LastValid
+	// Note Transaction.Note
+	Note
+	// Receiver Transaction.Receiver
+	Receiver
+	// Amount Transaction.Amount
+	Amount
+	// CloseRemainderTo Transaction.CloseRemainderTo
+	CloseRemainderTo
+	// VotePK Transaction.VotePK
+	VotePK
+	// SelectionPK Transaction.SelectionPK
+	SelectionPK
+	// VoteFirst Transaction.VoteFirst
+	VoteFirst
+	// VoteLast Transaction.VoteLast
+	VoteLast
+	// VoteKeyDilution Transaction.VoteKeyDilution
+	VoteKeyDilution
+	// Type Transaction.Type
+	Type
+	// TypeEnum int(Transaction.Type)
+	TypeEnum
+	// XferAsset Transaction.XferAsset.Index
+	XferAsset
+	// XferAssetCreator Transaction.XferAsset.Creator
+	XferAssetCreator
+	// AssetAmount Transaction.AssetAmount
+	AssetAmount
+	// AssetSender Transaction.AssetSender
+	AssetSender
+	// AssetReceiver Transaction.AssetReceiver
+	AssetReceiver
+	// AssetCloseTo Transaction.AssetCloseTo
+	AssetCloseTo
+	// GroupIndex i for txngroup[i] == Txn
+	GroupIndex
+	// TxID Transaction.ID()
+	TxID
+	// Lease Transaction.Lease
+	Lease
+	// RekeyTo Transaction.RekeyTo
+	RekeyTo
+
+	invalidTxnField // fence for some setup that loops from Sender..invalidTxnField
+)
+
+// TxnFieldNames are arguments to the 'txn' and 'gtxn' opcodes, in the order of their values
+var TxnFieldNames = []string{
+	"Sender", "Fee", "FirstValid", "LastValid", "Note",
+	"Receiver", "Amount", "CloseRemainderTo",
+	"VotePK", "SelectionPK", "VoteFirst", "VoteLast", "VoteKeyDilution",
+	"Type", "TypeEnum",
+	"XferAsset", "XferAssetCreator", "AssetAmount", "AssetSender", "AssetReceiver", "AssetCloseTo",
+	"GroupIndex", "TxID", "Lease", "RekeyTo",
+}
+
+// GlobalField is an enum for `global` opcode
+type GlobalField int
+
+const (
+	// MinTxnFee ConsensusParams.MinTxnFee
+	MinTxnFee GlobalField = iota
+	// MinBalance ConsensusParams.MinBalance
+	MinBalance
+	// MaxTxnLife ConsensusParams.MaxTxnLife
+	MaxTxnLife
+	// ZeroAddress [32]byte{0...}
+	ZeroAddress
+	// GroupSize len(txn group)
+	GroupSize
+
+	invalidGlobalField
+)
+
+// GlobalFieldNames are arguments to the 'global' opcode, in the order of their values
+var GlobalFieldNames = []string{
+	"MinTxnFee", "MinBalance", "MaxTxnLife", "ZeroAddress", "GroupSize",
+}
+
+// TxnTypeNames are the transaction types in the order of their TypeEnum
+// value. TypeEnum 0 is an unknown type.
+var TxnTypeNames = []protocol.TxType{
+	protocol.UnknownTx,
+	protocol.PaymentTx,
+	protocol.KeyRegistrationTx,
+	protocol.AssetConfigTx,
+	protocol.AssetTransferTx,
+	protocol.AssetFreezeTx,
+}
+
+var txnFieldNameToValue map[string]uint64
+var globalFieldNameToValue map[string]uint64
+var txnTypeToValue map[string]uint64
+
+func init() {
+	txnFieldNameToValue = make(map[string]uint64, len(TxnFieldNames))
+	for i, name := range TxnFieldNames {
+		txnFieldNameToValue[name] = uint64(i)
+	}
+	globalFieldNameToValue = make(map[string]uint64, len(GlobalFieldNames))
+	for i, name := range GlobalFieldNames {
+		globalFieldNameToValue[name] = uint64(i)
+	}
+	txnTypeToValue = make(map[string]uint64, len(TxnTypeNames))
+	for i, tt := range TxnTypeNames {
+		txnTypeToValue[string(tt)] = uint64(i)
+	}
+}
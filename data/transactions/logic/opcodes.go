@@ -0,0 +1,124 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+// LogicVersion defines default assembler and max eval versions
+const LogicVersion = 1
+
+// OpSpec defines one byte opcode
+type OpSpec struct {
+	Opcode byte
+	Name   string
+	op     opFunc // evaluate the op
+
+	// asm assembles the arguments of the op from source text, and
+	// defaults to assembling an op without immediate arguments.
+	asm assembleFunc
+
+	// dis disassembles the op at the pc of its state, and defaults to
+	// printing the name of an op without immediate arguments.
+	dis disassembleFunc
+
+	// Size is the number of bytes of the op and its immediate arguments,
+	// or 0 for the variable size constant blocks
+	Size int
+
+	// Cost is what the op counts for in the total cost of a program,
+	// which is limited by config.ConsensusParams.LogicSigMaxCost
+	Cost int
+}
+
+// OpSpecs is the table of operations that can be assembled and evaluated.
+//
+// Any changes should be reflected in README.md which serves as the language spec.
+var OpSpecs = []OpSpec{
+	{0x00, "err", opErr, nil, nil, 1, 1},
+	{0x01, "sha256", opSHA256, nil, nil, 1, 7},
+	{0x02, "keccak256", opKeccak256, nil, nil, 1, 26},
+	{0x03, "sha512_256", opSHA512_256, nil, nil, 1, 9},
+	{0x04, "ed25519verify", opEd25519verify, nil, nil, 1, 1900},
+	{0x08, "+", opPlus, nil, nil, 1, 1},
+	{0x09, "-", opMinus, nil, nil, 1, 1},
+	{0x0a, "/", opDiv, nil, nil, 1, 1},
+	{0x0b, "*", opMul, nil, nil, 1, 1},
+	{0x0c, "<", opLt, nil, nil, 1, 1},
+	{0x0d, ">", opGt, nil, nil, 1, 1},
+	{0x0e, "<=", opLe, nil, nil, 1, 1},
+	{0x0f, ">=", opGe, nil, nil, 1, 1},
+	{0x10, "&&", opAnd, nil, nil, 1, 1},
+	{0x11, "||", opOr, nil, nil, 1, 1},
+	{0x12, "==", opEq, nil, nil, 1, 1},
+	{0x13, "!=", opNeq, nil, nil, 1, 1},
+	{0x14, "!", opNot, nil, nil, 1, 1},
+	{0x15, "len", opLen, nil, nil, 1, 1},
+	{0x16, "itob", opItob, nil, nil, 1, 1},
+	{0x17, "btoi", opBtoi, nil, nil, 1, 1},
+	{0x18, "%", opModulo, nil, nil, 1, 1},
+	{0x19, "|", opBitOr, nil, nil, 1, 1},
+	{0x1a, "&", opBitAnd, nil, nil, 1, 1},
+	{0x1b, "^", opBitXor, nil, nil, 1, 1},
+	{0x1c, "~", opBitNot, nil, nil, 1, 1},
+	{0x1d, "mulw", opMulw, nil, nil, 1, 1},
+
+	{0x20, "intcblock", opIntConstBlock, assembleIntCBlock, disIntcblock, 0, 1},
+	{0x21, "intc", opIntConstLoad, assembleIntC, disIntc, 2, 1},
+	{0x22, "intc_0", opIntConst0, nil, nil, 1, 1},
+	{0x23, "intc_1", opIntConst1, nil, nil, 1, 1},
+	{0x24, "intc_2", opIntConst2, nil, nil, 1, 1},
+	{0x25, "intc_3", opIntConst3, nil, nil, 1, 1},
+	{0x26, "bytecblock", opByteConstBlock, assembleByteCBlock, disBytecblock, 0, 1},
+	{0x27, "bytec", opByteConstLoad, assembleByteC, disIntc, 2, 1},
+	{0x28, "bytec_0", opByteConst0, nil, nil, 1, 1},
+	{0x29, "bytec_1", opByteConst1, nil, nil, 1, 1},
+	{0x2a, "bytec_2", opByteConst2, nil, nil, 1, 1},
+	{0x2b, "bytec_3", opByteConst3, nil, nil, 1, 1},
+	{0x2c, "arg", opArg, assembleArg, disIntc, 2, 1},
+	{0x2d, "arg_0", opArg0, nil, nil, 1, 1},
+	{0x2e, "arg_1", opArg1, nil, nil, 1, 1},
+	{0x2f, "arg_2", opArg2, nil, nil, 1, 1},
+	{0x30, "arg_3", opArg3, nil, nil, 1, 1},
+	{0x31, "txn", opTxn, assembleTxn, disTxn, 2, 1},
+	{0x32, "global", opGlobal, assembleGlobal, disGlobal, 2, 1},
+	{0x33, "gtxn", opGtxn, assembleGtxn, disGtxn, 3, 1},
+	{0x34, "load", opLoad, assembleLoadStore, disIntc, 2, 1},
+	{0x35, "store", opStore, assembleLoadStore, disIntc, 2, 1},
+
+	{0x40, "bnz", opBnz, assembleBnz, disBnz, 3, 1},
+	{0x48, "pop", opPop, nil, nil, 1, 1},
+	{0x49, "dup", opDup, nil, nil, 1, 1},
+}
+
+// opsByOpcode is OpSpecs indexed by opcode, with a zero OpSpec for
+// undefined opcodes
+var opsByOpcode [256]OpSpec
+
+// opsByName is OpSpecs indexed by name
+var opsByName map[string]OpSpec
+
+func init() {
+	opsByName = make(map[string]OpSpec, len(OpSpecs))
+	for _, oi := range OpSpecs {
+		if oi.asm == nil {
+			oi.asm = assembleDefault
+		}
+		if oi.dis == nil {
+			oi.dis = disDefault
+		}
+		opsByOpcode[oi.Opcode] = oi
+		opsByName[oi.Name] = oi
+	}
+}
@@ -0,0 +1,389 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package templates fills in TEAL contract templates: programs controlling an
+// escrow account, which pay out of it when the transactions spending from it
+// meet the terms of the contract.
+//
+// Every template limits the fee of the transactions spending from the escrow,
+// and forbids them to rekey it.
+package templates
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+)
+
+// Template is a contract template filled in with its parameters
+type Template interface {
+	// Source returns the TEAL source of the contract, or an error if the
+	// parameters don't make a valid one
+	Source() (string, error)
+}
+
+// Compile assembles the source of a filled in template, and returns the
+// program along with the address of the account it controls, its escrow
+// address
+func Compile(t Template) (program []byte, escrow basics.Address, err error) {
+	source, err := t.Source()
+	if err != nil {
+		return
+	}
+	program, err = logic.AssembleString(source)
+	if err != nil {
+		return
+	}
+	return program, basics.Address(logic.HashProgram(program)), nil
+}
+
+var funcs = template.FuncMap{
+	"addr": basics.Address.GetUserAddress,
+	"b64":  base64.StdEncoding.EncodeToString,
+}
+
+func fill(tmpl *template.Template, params interface{}) (string, error) {
+	var out strings.Builder
+	err := tmpl.Execute(&out, params)
+	return out.String(), err
+}
+
+// header checks the fee and the rekeying of the transaction being approved
+const header = `txn Fee
+int {{.MaxFee}}
+<=
+txn RekeyTo
+global ZeroAddress
+==
+&&
+`
+
+// HTLC is a hash time-locked contract: Receiver can close the escrow to
+// itself with the preimage of HashImage as the first argument of the logic
+// signature, or Owner can close it to itself once round Timeout has passed.
+type HTLC struct {
+	Owner     basics.Address
+	Receiver  basics.Address
+	HashFn    string // sha256, keccak256 or sha512_256
+	HashImage []byte
+	Timeout   basics.Round
+	MaxFee    uint64
+}
+
+var htlcTemplate = template.Must(template.New("htlc").Funcs(funcs).Parse(`// Hash time-locked contract
+` + header + `txn TypeEnum
+int pay
+==
+&&
+txn Receiver
+txn CloseRemainderTo
+==
+&&
+txn CloseRemainderTo
+addr {{addr .Owner}}
+==
+txn FirstValid
+int {{.Timeout}}
+>
+&&
+bnz refund
+txn CloseRemainderTo
+addr {{addr .Receiver}}
+==
+arg 0
+{{.HashFn}}
+byte base64 {{b64 .HashImage}}
+==
+&&
+&&
+refund:
+`))
+
+// Source implements Template
+func (c HTLC) Source() (string, error) {
+	if c.Owner == (basics.Address{}) || c.Receiver == (basics.Address{}) {
+		return "", errors.New("the owner and the receiver must be set")
+	}
+	switch c.HashFn {
+	case "sha256", "keccak256", "sha512_256":
+	default:
+		return "", fmt.Errorf("unknown hash function %s, use sha256, keccak256 or sha512_256", c.HashFn)
+	}
+	if len(c.HashImage) != len(crypto.Digest{}) {
+		return "", fmt.Errorf("the hash image is %d bytes long instead of %d", len(c.HashImage), len(crypto.Digest{}))
+	}
+	return fill(htlcTemplate, c)
+}
+
+// Split splits the payments out of the escrow between Receiver1 and
+// Receiver2, Ratio1 to Ratio2: the escrow pays both in a group of two
+// transactions, of which the first pays at least MinPay to Receiver1. Owner
+// can close the escrow to itself once round Timeout has passed.
+type Split struct {
+	Owner     basics.Address
+	Receiver1 basics.Address
+	Receiver2 basics.Address
+	Ratio1    uint64
+	Ratio2    uint64
+	MinPay    uint64
+	Timeout   basics.Round
+	MaxFee    uint64
+}
+
+var splitTemplate = template.Must(template.New("split").Funcs(funcs).Parse(`// Split payment
+` + header + `txn TypeEnum
+int pay
+==
+&&
+global GroupSize
+int 2
+==
+bnz split
+txn CloseRemainderTo
+addr {{addr .Owner}}
+==
+txn Receiver
+addr {{addr .Owner}}
+==
+&&
+txn FirstValid
+int {{.Timeout}}
+>
+&&
+&&
+int 1
+bnz done
+split:
+gtxn 0 Sender
+gtxn 1 Sender
+==
+txn CloseRemainderTo
+global ZeroAddress
+==
+&&
+gtxn 0 Receiver
+addr {{addr .Receiver1}}
+==
+&&
+gtxn 1 Receiver
+addr {{addr .Receiver2}}
+==
+&&
+gtxn 0 Amount
+int {{.Ratio2}}
+*
+gtxn 1 Amount
+int {{.Ratio1}}
+*
+==
+&&
+gtxn 0 Amount
+int {{.MinPay}}
+>=
+&&
+&&
+done:
+`))
+
+// Source implements Template
+func (c Split) Source() (string, error) {
+	if c.Owner == (basics.Address{}) || c.Receiver1 == (basics.Address{}) || c.Receiver2 == (basics.Address{}) {
+		return "", errors.New("the owner and both receivers must be set")
+	}
+	if c.Ratio1 == 0 || c.Ratio2 == 0 {
+		return "", errors.New("both sides of the ratio must be positive")
+	}
+	return fill(splitTemplate, c)
+}
+
+// PeriodicPayment lets Receiver withdraw Amount from the escrow once every
+// Period rounds: the withdrawal must be valid for Duration rounds from a
+// multiple of Period, and carry Lease, so that no other withdrawal can commit
+// in the meantime. Once round Timeout has passed, Receiver can close the
+// escrow to itself instead.
+type PeriodicPayment struct {
+	Receiver basics.Address
+	Amount   uint64
+	Period   uint64
+	Duration uint64
+	Lease    [32]byte
+	Timeout  basics.Round
+	MaxFee   uint64
+}
+
+var periodicPaymentTemplate = template.Must(template.New("periodic").Funcs(funcs).Parse(`// Periodic payment
+` + header + `txn TypeEnum
+int pay
+==
+&&
+txn FirstValid
+int {{.Period}}
+%
+int 0
+==
+&&
+txn LastValid
+txn FirstValid
+int {{.Duration}}
++
+==
+&&
+txn Lease
+byte base64 {{b64 .LeaseBytes}}
+==
+&&
+txn Receiver
+addr {{addr .Receiver}}
+==
+&&
+txn CloseRemainderTo
+global ZeroAddress
+==
+txn Amount
+int {{.Amount}}
+==
+&&
+txn CloseRemainderTo
+addr {{addr .Receiver}}
+==
+txn FirstValid
+int {{.Timeout}}
+>
+&&
+||
+&&
+`))
+
+// Source implements Template
+func (c PeriodicPayment) Source() (string, error) {
+	if c.Receiver == (basics.Address{}) {
+		return "", errors.New("the receiver must be set")
+	}
+	if c.Period == 0 || c.Duration == 0 || c.Duration > c.Period {
+		return "", errors.New("the period must be positive, and the duration between 1 and the period")
+	}
+	if c.Lease == ([32]byte{}) {
+		return "", errors.New("the lease must be set")
+	}
+	return fill(periodicPaymentTemplate, struct {
+		PeriodicPayment
+		LeaseBytes []byte
+	}{c, c.Lease[:]})
+}
+
+// LimitOrder sells the Algos of the escrow for the asset AssetIndex of
+// AssetCreator, at SwapN microAlgos for every SwapD units of the asset or
+// better: the escrow pays at least MinTrade microAlgos as the first
+// transaction of a group of two, in which the second transfers the asset to
+// Owner. Owner can close the escrow to itself once round Timeout has passed.
+type LimitOrder struct {
+	Owner        basics.Address
+	AssetCreator basics.Address
+	AssetIndex   uint64
+	SwapN        uint64
+	SwapD        uint64
+	MinTrade     uint64
+	Timeout      basics.Round
+	MaxFee       uint64
+}
+
+var limitOrderTemplate = template.Must(template.New("limitorder").Funcs(funcs).Parse(`// Limit order
+` + header + `global GroupSize
+int 2
+==
+bnz trade
+txn TypeEnum
+int pay
+==
+txn CloseRemainderTo
+addr {{addr .Owner}}
+==
+&&
+txn Receiver
+addr {{addr .Owner}}
+==
+&&
+txn FirstValid
+int {{.Timeout}}
+>
+&&
+&&
+int 1
+bnz done
+trade:
+txn GroupIndex
+int 0
+==
+gtxn 0 TypeEnum
+int pay
+==
+&&
+gtxn 0 CloseRemainderTo
+global ZeroAddress
+==
+&&
+gtxn 0 Amount
+int {{.MinTrade}}
+>=
+&&
+gtxn 1 TypeEnum
+int axfer
+==
+&&
+gtxn 1 XferAssetCreator
+addr {{addr .AssetCreator}}
+==
+&&
+gtxn 1 XferAsset
+int {{.AssetIndex}}
+==
+&&
+gtxn 1 AssetReceiver
+addr {{addr .Owner}}
+==
+&&
+gtxn 1 AssetSender
+global ZeroAddress
+==
+&&
+gtxn 0 Amount
+int {{.SwapD}}
+*
+gtxn 1 AssetAmount
+int {{.SwapN}}
+*
+<=
+&&
+&&
+done:
+`))
+
+// Source implements Template
+func (c LimitOrder) Source() (string, error) {
+	if c.Owner == (basics.Address{}) || c.AssetCreator == (basics.Address{}) {
+		return "", errors.New("the owner and the asset creator must be set")
+	}
+	if c.SwapN == 0 || c.SwapD == 0 {
+		return "", errors.New("both sides of the exchange rate must be positive")
+	}
+	return fill(limitOrderTemplate, c)
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func randAddress() (a basics.Address) {
+	crypto.RandBytes(a[:])
+	return
+}
+
+// approves evaluates program for the transaction at index i of group, signed with a logic signature carrying args
+func approves(t *testing.T, program []byte, group []transactions.SignedTxn, i int, args ...[]byte) bool {
+	proto := config.Consensus[protocol.ConsensusFuture]
+	txn := group[i]
+	txn.Lsig = transactions.LogicSig{Logic: program, Args: args}
+	params := logic.EvalParams{Txn: &txn, Proto: &proto, TxnGroup: group, GroupIndex: i}
+	_, err := logic.Check(program, params)
+	require.NoError(t, err)
+	pass, err := logic.Eval(program, params)
+	return pass && err == nil
+}
+
+func payment(sender, receiver, closeTo basics.Address, amount uint64, first basics.Round) transactions.SignedTxn {
+	return transactions.SignedTxn{Txn: transactions.Transaction{
+		Type: protocol.PaymentTx,
+		Header: transactions.Header{
+			Sender:     sender,
+			Fee:        basics.MicroAlgos{Raw: 1000},
+			FirstValid: first,
+			LastValid:  first + 1000,
+		},
+		PaymentTxnFields: transactions.PaymentTxnFields{
+			Receiver:         receiver,
+			Amount:           basics.MicroAlgos{Raw: amount},
+			CloseRemainderTo: closeTo,
+		},
+	}}
+}
+
+func TestHTLC(t *testing.T) {
+	owner, receiver := randAddress(), randAddress()
+	preimage := []byte("secret")
+	image := crypto.Hash(preimage)
+	c := HTLC{Owner: owner, Receiver: receiver, HashFn: "sha512_256", HashImage: image[:], Timeout: 100, MaxFee: 2000}
+	program, escrow, err := Compile(c)
+	require.NoError(t, err)
+
+	claim := []transactions.SignedTxn{payment(escrow, receiver, receiver, 0, 50)}
+	require.True(t, approves(t, program, claim, 0, preimage))
+	require.False(t, approves(t, program, claim, 0, []byte("guess")))
+
+	refund := []transactions.SignedTxn{payment(escrow, owner, owner, 0, 50)}
+	require.False(t, approves(t, program, refund, 0))
+	refund = []transactions.SignedTxn{payment(escrow, owner, owner, 0, 101)}
+	require.True(t, approves(t, program, refund, 0))
+
+	// the fee is capped, and the escrow can't be rekeyed
+	expensive := []transactions.SignedTxn{payment(escrow, owner, owner, 0, 101)}
+	expensive[0].Txn.Fee.Raw = 2001
+	require.False(t, approves(t, program, expensive, 0))
+	rekey := []transactions.SignedTxn{payment(escrow, owner, owner, 0, 101)}
+	rekey[0].Txn.RekeyTo = randAddress()
+	require.False(t, approves(t, program, rekey, 0))
+
+	for _, bad := range []HTLC{
+		{Receiver: receiver, HashFn: "sha256", HashImage: image[:]},
+		{Owner: owner, Receiver: receiver, HashFn: "md5", HashImage: image[:]},
+		{Owner: owner, Receiver: receiver, HashFn: "sha256", HashImage: preimage},
+	} {
+		_, _, err = Compile(bad)
+		require.Error(t, err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	owner, rcv1, rcv2 := randAddress(), randAddress(), randAddress()
+	c := Split{Owner: owner, Receiver1: rcv1, Receiver2: rcv2, Ratio1: 1, Ratio2: 3, MinPay: 1000, Timeout: 100, MaxFee: 2000}
+	program, escrow, err := Compile(c)
+	require.NoError(t, err)
+
+	split := []transactions.SignedTxn{
+		payment(escrow, rcv1, basics.Address{}, 1000, 50),
+		payment(escrow, rcv2, basics.Address{}, 3000, 50),
+	}
+	require.True(t, approves(t, program, split, 0))
+	require.True(t, approves(t, program, split, 1))
+
+	split[1].Txn.Amount.Raw = 3001
+	require.False(t, approves(t, program, split, 0))
+
+	small := []transactions.SignedTxn{
+		payment(escrow, rcv1, basics.Address{}, 100, 50),
+		payment(escrow, rcv2, basics.Address{}, 300, 50),
+	}
+	require.False(t, approves(t, program, small, 0))
+
+	refund := []transactions.SignedTxn{payment(escrow, owner, owner, 0, 101)}
+	require.True(t, approves(t, program, refund, 0))
+	refund[0].Txn.FirstValid = 100
+	require.False(t, approves(t, program, refund, 0))
+
+	_, _, err = Compile(Split{Owner: owner, Receiver1: rcv1, Receiver2: rcv2, Ratio1: 1})
+	require.Error(t, err)
+}
+
+func TestPeriodicPayment(t *testing.T) {
+	receiver := randAddress()
+	c := PeriodicPayment{Receiver: receiver, Amount: 5000, Period: 100, Duration: 50, Lease: [32]byte{1}, Timeout: 10000, MaxFee: 2000}
+	program, escrow, err := Compile(c)
+	require.NoError(t, err)
+
+	withdraw := func(first basics.Round) []transactions.SignedTxn {
+		txn := payment(escrow, receiver, basics.Address{}, 5000, first)
+		txn.Txn.LastValid = first + 50
+		txn.Txn.Lease = c.Lease
+		return []transactions.SignedTxn{txn}
+	}
+	require.True(t, approves(t, program, withdraw(200), 0))
+	require.False(t, approves(t, program, withdraw(201), 0))
+
+	more := withdraw(200)
+	more[0].Txn.Amount.Raw = 5001
+	require.False(t, approves(t, program, more, 0))
+
+	noLease := withdraw(200)
+	noLease[0].Txn.Lease = [32]byte{}
+	require.False(t, approves(t, program, noLease, 0))
+
+	longer := withdraw(200)
+	longer[0].Txn.LastValid++
+	require.False(t, approves(t, program, longer, 0))
+
+	closing := withdraw(10100)
+	closing[0].Txn.Amount.Raw = 0
+	closing[0].Txn.CloseRemainderTo = receiver
+	require.True(t, approves(t, program, closing, 0))
+	closing = withdraw(9900)
+	closing[0].Txn.CloseRemainderTo = receiver
+	require.False(t, approves(t, program, closing, 0))
+
+	for _, bad := range []PeriodicPayment{
+		{Receiver: receiver, Period: 100, Duration: 50},
+		{Receiver: receiver, Period: 100, Duration: 150, Lease: [32]byte{1}},
+		{Receiver: receiver, Duration: 50, Lease: [32]byte{1}},
+	} {
+		_, _, err = Compile(bad)
+		require.Error(t, err)
+	}
+}
+
+func TestLimitOrder(t *testing.T) {
+	owner, creator, buyer := randAddress(), randAddress(), randAddress()
+	c := LimitOrder{Owner: owner, AssetCreator: creator, AssetIndex: 7, SwapN: 2, SwapD: 1, MinTrade: 1000, Timeout: 100, MaxFee: 2000}
+	program, escrow, err := Compile(c)
+	require.NoError(t, err)
+
+	trade := func(algos, units uint64) []transactions.SignedTxn {
+		xfer := transactions.SignedTxn{Txn: transactions.Transaction{
+			Type:   protocol.AssetTransferTx,
+			Header: transactions.Header{Sender: buyer, Fee: basics.MicroAlgos{Raw: 1000}, FirstValid: 50, LastValid: 1050},
+			AssetTransferTxnFields: transactions.AssetTransferTxnFields{
+				XferAsset:     basics.AssetID{Creator: creator, Index: 7},
+				AssetAmount:   units,
+				AssetReceiver: owner,
+			},
+		}}
+		return []transactions.SignedTxn{payment(escrow, buyer, basics.Address{}, algos, 50), xfer}
+	}
+	// 2 microAlgos for every unit, or better
+	require.True(t, approves(t, program, trade(2000, 1000), 0))
+	require.True(t, approves(t, program, trade(2000, 1500), 0))
+	require.False(t, approves(t, program, trade(2000, 999), 0))
+	require.False(t, approves(t, program, trade(500, 1000), 0))
+
+	wrongAsset := trade(2000, 1000)
+	wrongAsset[1].Txn.XferAsset.Index = 8
+	require.False(t, approves(t, program, wrongAsset, 0))
+
+	refund := []transactions.SignedTxn{payment(escrow, owner, owner, 0, 101)}
+	require.True(t, approves(t, program, refund, 0))
+	refund[0].Txn.FirstValid = 99
+	require.False(t, approves(t, program, refund, 0))
+
+	_, _, err = Compile(LimitOrder{Owner: owner, AssetCreator: creator, SwapN: 2})
+	require.Error(t, err)
+}
@@ -85,6 +85,11 @@ func (payment PaymentTxnFields) apply(header Header, balances Balances, spec Spe
 				return err
 			}
 
+			// Assets would be lost with the account record
+			if len(rec.AssetParams) > 0 || len(rec.Assets) > 0 {
+				return fmt.Errorf("cannot close account %v with %d created and %d held assets", header.Sender, len(rec.AssetParams), len(rec.Assets))
+			}
+
 			closeAmount := rec.AccountData.MicroAlgos
 			ad.ClosingAmount = closeAmount
 			err = balances.Move(header.Sender, payment.CloseRemainderTo, closeAmount, &ad.SenderRewards, &ad.CloseRewards)
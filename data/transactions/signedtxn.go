@@ -49,8 +49,13 @@ type SignedTxn struct {
 
 	Sig  crypto.Signature   `codec:"sig"`
 	Msig crypto.MultisigSig `codec:"msig"`
+	Lsig LogicSig           `codec:"lsig"`
 	Txn  Transaction        `codec:"txn"`
 
+	// AuthAddr is the address whose key signed the transaction, when it is
+	// not the sender's own key because the sender has been rekeyed.
+	AuthAddr basics.Address `codec:"sgnr"`
+
 	// The length of the encoded SignedTxn, used for computing the
 	// transaction's priority in the transaction pool.
 	cachedEncodingLen int
@@ -89,6 +94,11 @@ func (a TxnPriority) LessThan(b TxnPriority) bool {
 	return a < b
 }
 
+// FeePerByte returns the fee, in MicroAlgos per encoded byte, that a TxnPriority value stands for
+func (a TxnPriority) FeePerByte() float64 {
+	return float64(a) / maxTxnBytesForPriority
+}
+
 // Mul multiplies a TxnPriority by a scalar, with saturation on overflow
 func (a TxnPriority) Mul(b uint64) TxnPriority {
 	return TxnPriority(basics.MulSaturate(uint64(a), b))
@@ -109,6 +119,15 @@ func (s *SignedTxn) ResetCaches() {
 	s.Txn.ResetCaches()
 }
 
+// Authorizer returns the address against which the signature/msig was checked:
+// AuthAddr if it is set, the sender otherwise.
+func (s SignedTxn) Authorizer() basics.Address {
+	if s.AuthAddr == (basics.Address{}) {
+		return s.Txn.Sender
+	}
+	return s.AuthAddr
+}
+
 // ID returns the Txid (i.e., hash) of the underlying transaction.
 func (s SignedTxn) ID() Txid {
 	return s.Txn.ID()
@@ -158,6 +177,11 @@ func (s *SignedTxn) PtrPriority() TxnPriority {
 	return TxnPriority(basics.MulSaturate(s.Txn.TxFee().Raw, uint64(maxTxnBytesForPriority/encodingLen)))
 }
 
+// errLogicSigVerify is returned when verifying a transaction signed with a
+// LogicSig on its own: running its program takes the transaction group, so
+// such transactions are verified with the verify package.
+var errLogicSigVerify = errors.New("transactions with a logic signature must be verified with their group")
+
 // Verify that a SignedTxn has a good signature and that the underlying
 // transaction is properly constructed.
 // Note that this does not check whether a payset is valid against the ledger:
@@ -176,8 +200,16 @@ func (s SignedTxn) Verify(spec SpecialAddresses, proto config.ConsensusParams) e
 		return errors.New("signedtxn should only have one of Sig or Msig")
 	}
 
-	if !crypto.SignatureVerifier(s.Txn.Src()).Verify(s.Txn, s.Sig) {
-		if ok, _ := crypto.MultisigVerify(s.Txn, crypto.Digest(s.Txn.Src()), s.Msig); !ok {
+	if !s.Lsig.Blank() {
+		return errLogicSigVerify
+	}
+
+	if s.AuthAddr != (basics.Address{}) && !proto.SupportRekeying {
+		return errors.New("signedtxn has an AuthAddr, but rekeying is not supported")
+	}
+
+	if !crypto.SignatureVerifier(s.Authorizer()).Verify(s.Txn, s.Sig) {
+		if ok, _ := crypto.MultisigVerify(s.Txn, crypto.Digest(s.Authorizer()), s.Msig); !ok {
 			return errors.New("signature (and multisig) failed to verify")
 		}
 		return nil
@@ -206,6 +238,14 @@ func (s SignedTxn) PoolVerify(spec SpecialAddresses, proto config.ConsensusParam
 		return errors.New("signedtxn should only have one of Sig or Msig")
 	}
 
+	if !s.Lsig.Blank() {
+		return errLogicSigVerify
+	}
+
+	if s.AuthAddr != (basics.Address{}) && !proto.SupportRekeying {
+		return errors.New("signedtxn has an AuthAddr, but rekeying is not supported")
+	}
+
 	outCh := make(chan error, 1)
 	verificationPool.EnqueueBacklog(context.Background(), s.asyncVerify, outCh, nil)
 	if err, hasErr := <-outCh; hasErr {
@@ -216,8 +256,8 @@ func (s SignedTxn) PoolVerify(spec SpecialAddresses, proto config.ConsensusParam
 
 func (s SignedTxn) asyncVerify(arg interface{}) interface{} {
 	outCh := arg.(chan error)
-	if !crypto.SignatureVerifier(s.Txn.Src()).Verify(s.Txn, s.Sig) {
-		if ok, _ := crypto.MultisigVerify(s.Txn, crypto.Digest(s.Txn.Src()), s.Msig); !ok {
+	if !crypto.SignatureVerifier(s.Authorizer()).Verify(s.Txn, s.Sig) {
+		if ok, _ := crypto.MultisigVerify(s.Txn, crypto.Digest(s.Authorizer()), s.Msig); !ok {
 			outCh <- errors.New("signature (and multisig) failed to verify")
 		}
 	}
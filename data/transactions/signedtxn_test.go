@@ -23,6 +23,7 @@ import (
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/protocol"
 )
 
@@ -77,3 +78,38 @@ func TestDecodeNil(t *testing.T) {
 }
 
 //TODO: test multisig
+
+func TestSignedTxnAuthorizer(t *testing.T) {
+	senderSecrets := keypair()
+	authSecrets := keypair()
+	sender := basics.Address(senderSecrets.SignatureVerifier)
+	authAddr := basics.Address(authSecrets.SignatureVerifier)
+
+	proto := config.Consensus[protocol.ConsensusFuture]
+	tx := Transaction{
+		Type: protocol.PaymentTx,
+		Header: Header{
+			Sender:     sender,
+			Fee:        basics.MicroAlgos{Raw: proto.MinTxnFee},
+			FirstValid: 1,
+			LastValid:  100,
+		},
+		PaymentTxnFields: PaymentTxnFields{Receiver: sender},
+	}
+
+	stxn := tx.Sign(senderSecrets)
+	require.Equal(t, sender, stxn.Authorizer())
+	require.NoError(t, stxn.Verify(spec, proto))
+
+	// signed by the key the sender was rekeyed to
+	stxn = tx.Sign(authSecrets)
+	require.Error(t, stxn.Verify(spec, proto))
+	stxn.AuthAddr = authAddr
+	require.Equal(t, authAddr, stxn.Authorizer())
+	require.NoError(t, stxn.Verify(spec, proto))
+	require.Error(t, stxn.Verify(spec, config.Consensus[protocol.ConsensusCurrentVersion]))
+
+	var decoded SignedTxn
+	require.NoError(t, protocol.Decode(protocol.Encode(stxn), &decoded))
+	require.Equal(t, authAddr, decoded.AuthAddr)
+}
@@ -76,6 +76,94 @@ type Header struct {
 	Note        []byte            `codec:"note"` // Uniqueness or app-level data about txn
 	GenesisID   string            `codec:"gen"`
 	GenesisHash crypto.Digest     `codec:"gh"`
+
+	// RekeyTo, if nonzero, sets the sender's AuthAddr to the given address.
+	// If the RekeyTo address is the sender's actual address, the AuthAddr is set to zero.
+	// This allows "re-keying" a long-lived account -- rotating the signing key, changing
+	// membership of a multisig account, etc.
+	RekeyTo basics.Address `codec:"rekey"`
+
+	// Group, if nonzero, is the ID of the atomic transaction group this
+	// transaction belongs to: either all the transactions of the group are
+	// committed, in the order of the group, or none of them is.
+	Group crypto.Digest `codec:"grp"`
+
+	// Lease, if nonzero, enforces mutual exclusion of transactions: once
+	// this transaction is committed, it holds the (Sender, Lease) pair
+	// through its LastValid round, and no other transaction with the same
+	// sender and lease can be committed until then.
+	Lease [32]byte `codec:"lx"`
+}
+
+// Txlease is the lease held by a committed transaction with a nonzero Lease:
+// the pair of its sender and lease.
+type Txlease struct {
+	Sender basics.Address
+	Lease  [32]byte
+}
+
+// Txlease returns the lease of the transaction, and whether it has one.
+func (tx Header) Txlease() (Txlease, bool) {
+	return Txlease{Sender: tx.Sender, Lease: tx.Lease}, tx.Lease != [32]byte{}
+}
+
+// TxGroup describes a group of transactions that must appear
+// together in a specific order in a block.
+type TxGroup struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	// TxGroupHashes specifies a list of hashes of transactions that must appear
+	// together, sequentially, in a block in order for the group to be
+	// valid.  Each hash in the list is a hash of a transaction with
+	// the `Group` field omitted.
+	TxGroupHashes []crypto.Digest `codec:"txlist"`
+}
+
+// ToBeHashed implements the crypto.Hashable interface.
+func (tg TxGroup) ToBeHashed() (protocol.HashID, []byte) {
+	return protocol.TxGroup, protocol.Encode(tg)
+}
+
+// ComputeGroupID returns the ID of the group made of txgroup, in this order.
+// The Group field of the transactions is ignored.
+func ComputeGroupID(txgroup []Transaction) crypto.Digest {
+	var group TxGroup
+	for _, tx := range txgroup {
+		tx.Group = crypto.Digest{}
+		tx.ResetCaches()
+		group.TxGroupHashes = append(group.TxGroupHashes, crypto.Digest(tx.ID()))
+	}
+	return crypto.HashObj(group)
+}
+
+// WellFormedGroup checks that txgroup is a whole transaction group: either a
+// single transaction without a group, or transactions that all carry the ID
+// of the group they make up in this order.
+func WellFormedGroup(txgroup []SignedTxn, proto config.ConsensusParams) error {
+	if len(txgroup) == 0 {
+		return fmt.Errorf("empty transaction group")
+	}
+	group := txgroup[0].Txn.Group
+	if len(txgroup) == 1 && group == (crypto.Digest{}) {
+		return nil
+	}
+	if proto.MaxTxGroupSize == 0 {
+		return fmt.Errorf("transaction groups are not supported")
+	}
+	if len(txgroup) > proto.MaxTxGroupSize {
+		return fmt.Errorf("transaction group has %d transactions, more than the maximum %d", len(txgroup), proto.MaxTxGroupSize)
+	}
+	txns := make([]Transaction, len(txgroup))
+	for i, stxn := range txgroup {
+		if stxn.Txn.Group != group {
+			return fmt.Errorf("transaction %v is not in group %v", stxn.ID(), group)
+		}
+		txns[i] = stxn.Txn
+	}
+	if computed := ComputeGroupID(txns); computed != group {
+		return fmt.Errorf("transaction group %v does not match its transactions, whose group ID is %v", group, computed)
+	}
+	return nil
 }
 
 // Transaction describes a transaction that can appear in a block.
@@ -91,6 +179,9 @@ type Transaction struct {
 	// Fields for different types of transactions
 	KeyregTxnFields
 	PaymentTxnFields
+	AssetConfigTxnFields
+	AssetTransferTxnFields
+	AssetFreezeTxnFields
 
 	// The transaction's Txid is computed when we decode,
 	// and cached here, to avoid needlessly recomputing it.
@@ -223,7 +314,29 @@ func (tx Transaction) WellFormed(spec SpecialAddresses, proto config.ConsensusPa
 			return err
 		}
 	case protocol.KeyRegistrationTx:
-		// All OK
+		if tx.KeyregTxnFields.Nonparticipation {
+			if !proto.SupportBecomeNonParticipatingTransactions {
+				return fmt.Errorf("transaction tries to mark an account as nonparticipating, but that transaction is not supported")
+			}
+			// a nonparticipating account can't register participation keys in the same transaction
+			if tx.KeyregTxnFields.VotePK != (crypto.OneTimeSignatureVerifier{}) || tx.KeyregTxnFields.SelectionPK != (crypto.VRFVerifier{}) {
+				return fmt.Errorf("transaction registers participation keys for a nonparticipating account")
+			}
+		}
+
+	case protocol.AssetConfigTx, protocol.AssetTransferTx, protocol.AssetFreezeTx:
+		if proto.MaxAssetsPerAccount == 0 {
+			return fmt.Errorf("asset transaction not supported")
+		}
+		if tx.Type == protocol.AssetConfigTx {
+			err := tx.AssetConfigTxnFields.wellFormed(proto)
+			if err != nil {
+				return err
+			}
+		}
+		if tx.Type == protocol.AssetTransferTx && tx.AssetCloseTo != (basics.Address{}) && tx.AssetCloseTo == tx.Sender {
+			return fmt.Errorf("transaction cannot close asset %v to its sender %v", tx.XferAsset, tx.Sender)
+		}
 
 	default:
 		return fmt.Errorf("unknown tx type %v", tx.Type)
@@ -238,6 +351,18 @@ func (tx Transaction) WellFormed(spec SpecialAddresses, proto config.ConsensusPa
 		nonZeroFields[protocol.KeyRegistrationTx] = true
 	}
 
+	if tx.AssetConfigTxnFields != (AssetConfigTxnFields{}) {
+		nonZeroFields[protocol.AssetConfigTx] = true
+	}
+
+	if tx.AssetTransferTxnFields != (AssetTransferTxnFields{}) {
+		nonZeroFields[protocol.AssetTransferTx] = true
+	}
+
+	if tx.AssetFreezeTxnFields != (AssetFreezeTxnFields{}) {
+		nonZeroFields[protocol.AssetFreezeTx] = true
+	}
+
 	for t, nonZero := range nonZeroFields {
 		if nonZero && t != tx.Type {
 			return fmt.Errorf("transaction of type %v has non-zero fields for type %v", tx.Type, t)
@@ -260,6 +385,20 @@ func (tx Transaction) WellFormed(spec SpecialAddresses, proto config.ConsensusPa
 		// this check is just to be safe, but reaching here seems impossible, since it requires computing a preimage of rwpool
 		return fmt.Errorf("transaction from incentive pool is invalid")
 	}
+	if tx.RekeyTo != (basics.Address{}) {
+		if !proto.SupportRekeying {
+			return fmt.Errorf("transaction tries to rekey, but rekeying is not supported")
+		}
+		if tx.Sender == spec.FeeSink {
+			return fmt.Errorf("transaction tries to rekey the fee sink")
+		}
+	}
+	if tx.Group != (crypto.Digest{}) && proto.MaxTxGroupSize == 0 {
+		return fmt.Errorf("transaction has a group, but transaction groups are not supported")
+	}
+	if tx.Lease != [32]byte{} && !proto.SupportTransactionLeases {
+		return fmt.Errorf("transaction has a lease, but transaction leases are not supported")
+	}
 	return nil
 }
 
@@ -289,11 +428,45 @@ func (tx Transaction) RelevantAddrs(spec SpecialAddresses, proto config.Consensu
 		if tx.PaymentTxnFields.CloseRemainderTo != (basics.Address{}) {
 			addrs = append(addrs, tx.PaymentTxnFields.CloseRemainderTo)
 		}
+	case protocol.AssetConfigTx:
+		if tx.ConfigAsset.Creator != (basics.Address{}) {
+			addrs = append(addrs, tx.ConfigAsset.Creator)
+		}
+	case protocol.AssetTransferTx:
+		addrs = append(addrs, tx.XferAsset.Creator, tx.AssetReceiver)
+		if tx.AssetSender != (basics.Address{}) {
+			addrs = append(addrs, tx.AssetSender)
+		}
+		if tx.AssetCloseTo != (basics.Address{}) {
+			addrs = append(addrs, tx.AssetCloseTo)
+		}
+	case protocol.AssetFreezeTx:
+		addrs = append(addrs, tx.FreezeAsset.Creator, tx.FreezeAccount)
 	}
 
 	return addrs
 }
 
+// Addresses returns every address the transaction refers to, whatever its type, with the zero address left out.
+// Unlike RelevantAddrs, it includes the addresses whose balance records the transaction doesn't access, such as the
+// asset managers it configures.
+func (tx Transaction) Addresses() []basics.Address {
+	candidates := []basics.Address{
+		tx.Sender, tx.RekeyTo,
+		tx.Receiver, tx.CloseRemainderTo,
+		tx.ConfigAsset.Creator, tx.AssetParams.Manager, tx.AssetParams.Reserve, tx.AssetParams.Freeze, tx.AssetParams.Clawback,
+		tx.XferAsset.Creator, tx.AssetSender, tx.AssetReceiver, tx.AssetCloseTo,
+		tx.FreezeAsset.Creator, tx.FreezeAccount,
+	}
+	addrs := make([]basics.Address, 0, len(candidates))
+	for _, addr := range candidates {
+		if addr != (basics.Address{}) {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
 // TxAmount returns the amount paid to the recipient in this payment
 func (tx Transaction) TxAmount() basics.MicroAlgos {
 	switch tx.Type {
@@ -319,7 +492,7 @@ func (tx Transaction) SenderDeduction() (amount basics.MicroAlgos, empty bool, e
 		if overflow {
 			err = fmt.Errorf("overflowed computing sender deduction for transaction %v (fee %v, amount %v)", tx.ID(), tx.Fee, paymentAmount)
 		}
-	case protocol.KeyRegistrationTx:
+	case protocol.KeyRegistrationTx, protocol.AssetConfigTx, protocol.AssetTransferTx, protocol.AssetFreezeTx:
 		// no additional spend over the fee
 	default:
 		err = fmt.Errorf("unknown transaction type %v", tx.Type)
@@ -337,8 +510,9 @@ func (tx Transaction) EstimateEncodedSize() int {
 	return stx.GetEncodedLength()
 }
 
-// Apply changes the balances according to this transaction.
-func (tx Transaction) Apply(balances Balances, spec SpecialAddresses) (ad ApplyData, err error) {
+// Apply changes the balances according to this transaction.  ctr is the
+// number of transactions committed to the ledger before this one.
+func (tx Transaction) Apply(balances Balances, spec SpecialAddresses, ctr uint64) (ad ApplyData, err error) {
 	params := balances.ConsensusParams()
 
 	// move fee to pool
@@ -347,6 +521,15 @@ func (tx Transaction) Apply(balances Balances, spec SpecialAddresses) (ad ApplyD
 		return
 	}
 
+	// rekey the sender before applying the transaction, so that closing
+	// the account also clears its AuthAddr
+	if tx.RekeyTo != (basics.Address{}) {
+		err = tx.rekey(balances)
+		if err != nil {
+			return
+		}
+	}
+
 	switch tx.Type {
 	case protocol.PaymentTx:
 		err = tx.PaymentTxnFields.apply(tx.Header, balances, spec, &ad)
@@ -354,6 +537,15 @@ func (tx Transaction) Apply(balances Balances, spec SpecialAddresses) (ad ApplyD
 	case protocol.KeyRegistrationTx:
 		err = tx.KeyregTxnFields.apply(tx.Header, balances, spec, &ad)
 
+	case protocol.AssetConfigTx:
+		err = tx.AssetConfigTxnFields.apply(tx.Header, balances, spec, &ad, ctr)
+
+	case protocol.AssetTransferTx:
+		err = tx.AssetTransferTxnFields.apply(tx.Header, balances, spec, &ad)
+
+	case protocol.AssetFreezeTx:
+		err = tx.AssetFreezeTxnFields.apply(tx.Header, balances, spec, &ad)
+
 	default:
 		err = fmt.Errorf("Unknown transaction type %v", tx.Type)
 	}
@@ -369,6 +561,21 @@ func (tx Transaction) Apply(balances Balances, spec SpecialAddresses) (ad ApplyD
 	return
 }
 
+// rekey sets the AuthAddr of the sender to RekeyTo, or clears it when the
+// sender is rekeyed back to itself.
+func (tx Transaction) rekey(balances Balances) error {
+	record, err := balances.Get(tx.Sender)
+	if err != nil {
+		return err
+	}
+	if tx.RekeyTo == tx.Sender {
+		record.AuthAddr = basics.Address{}
+	} else {
+		record.AuthAddr = tx.RekeyTo
+	}
+	return balances.Put(record)
+}
+
 // TxnContext describes the context in which a transaction can appear
 // (pretty much, a block, but we don't have the definition of a block
 // here, since that would be a circular dependency).  This is used to
@@ -51,3 +51,152 @@ func TestTransaction_EstimateEncodedSize(t *testing.T) {
 
 	require.Equal(t, 200, tx.EstimateEncodedSize())
 }
+
+// rekeyBalances is a Balances that only tracks the records it is given.
+type rekeyBalances struct {
+	proto   protocol.ConsensusVersion
+	records map[basics.Address]basics.AccountData
+}
+
+func (balances *rekeyBalances) Get(addr basics.Address) (basics.BalanceRecord, error) {
+	return basics.BalanceRecord{Addr: addr, AccountData: balances.records[addr]}, nil
+}
+
+func (balances *rekeyBalances) Put(record basics.BalanceRecord) error {
+	balances.records[record.Addr] = record.AccountData
+	return nil
+}
+
+func (balances *rekeyBalances) Move(src, dst basics.Address, amount basics.MicroAlgos, srcRewards, dstRewards *basics.MicroAlgos) error {
+	return nil
+}
+
+func (balances *rekeyBalances) ConsensusParams() config.ConsensusParams {
+	return config.Consensus[balances.proto]
+}
+
+func TestTransaction_Rekey(t *testing.T) {
+	sender := basics.Address(keypair().SignatureVerifier)
+	newKey := basics.Address(keypair().SignatureVerifier)
+	tx := Transaction{
+		Type: protocol.PaymentTx,
+		Header: Header{
+			Sender:     sender,
+			Fee:        basics.MicroAlgos{Raw: config.Consensus[protocol.ConsensusFuture].MinTxnFee},
+			FirstValid: 1,
+			LastValid:  100,
+			RekeyTo:    newKey,
+		},
+		PaymentTxnFields: PaymentTxnFields{Receiver: sender},
+	}
+
+	require.Error(t, tx.WellFormed(spec, config.Consensus[protocol.ConsensusCurrentVersion]))
+	require.NoError(t, tx.WellFormed(spec, config.Consensus[protocol.ConsensusFuture]))
+	feeSinkTx := tx
+	feeSinkTx.Sender = spec.FeeSink
+	require.Error(t, feeSinkTx.WellFormed(spec, config.Consensus[protocol.ConsensusFuture]))
+
+	balances := &rekeyBalances{proto: protocol.ConsensusFuture, records: map[basics.Address]basics.AccountData{}}
+	_, err := tx.Apply(balances, spec, 0)
+	require.NoError(t, err)
+	require.Equal(t, newKey, balances.records[sender].AuthAddr)
+
+	// rekeying an account to itself clears its AuthAddr
+	tx.RekeyTo = sender
+	_, err = tx.Apply(balances, spec, 0)
+	require.NoError(t, err)
+	require.Equal(t, basics.Address{}, balances.records[sender].AuthAddr)
+}
+
+func TestWellFormedGroup(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusFuture]
+	secrets := keypair()
+	sender := basics.Address(secrets.SignatureVerifier)
+	payment := func(amount uint64) Transaction {
+		return Transaction{
+			Type: protocol.PaymentTx,
+			Header: Header{
+				Sender:     sender,
+				Fee:        basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid: 1,
+				LastValid:  100,
+			},
+			PaymentTxnFields: PaymentTxnFields{Receiver: sender, Amount: basics.MicroAlgos{Raw: amount}},
+		}
+	}
+
+	txns := []Transaction{payment(1), payment(2)}
+	gid := ComputeGroupID(txns)
+	require.NotEqual(t, crypto.Digest{}, gid)
+	require.NotEqual(t, gid, ComputeGroupID([]Transaction{payment(2), payment(1)}))
+
+	txgroup := make([]SignedTxn, len(txns))
+	for i, txn := range txns {
+		txn.Group = gid
+		txns[i] = txn
+		txgroup[i] = txn.Sign(secrets)
+	}
+	// the group ID doesn't depend on the group field
+	require.Equal(t, gid, ComputeGroupID(txns))
+
+	require.NoError(t, WellFormedGroup(txgroup, proto))
+	require.Error(t, WellFormedGroup(txgroup, config.Consensus[protocol.ConsensusCurrentVersion]))
+	require.Error(t, WellFormedGroup(txgroup[:1], proto))
+	require.Error(t, WellFormedGroup([]SignedTxn{txgroup[1], txgroup[0]}, proto))
+	require.Error(t, WellFormedGroup(nil, proto))
+	require.NoError(t, WellFormedGroup([]SignedTxn{payment(3).Sign(secrets)}, proto))
+
+	require.NoError(t, txns[0].WellFormed(spec, proto))
+	require.Error(t, txns[0].WellFormed(spec, config.Consensus[protocol.ConsensusCurrentVersion]))
+}
+
+func TestWellFormedLease(t *testing.T) {
+	sender := basics.Address(keypair().SignatureVerifier)
+	tx := Transaction{
+		Type: protocol.PaymentTx,
+		Header: Header{
+			Sender:     sender,
+			Fee:        basics.MicroAlgos{Raw: config.Consensus[protocol.ConsensusFuture].MinTxnFee},
+			FirstValid: 1,
+			LastValid:  100,
+		},
+		PaymentTxnFields: PaymentTxnFields{Receiver: sender},
+	}
+	_, leased := tx.Txlease()
+	require.False(t, leased)
+
+	crypto.RandBytes(tx.Lease[:])
+	txl, leased := tx.Txlease()
+	require.True(t, leased)
+	require.Equal(t, Txlease{Sender: sender, Lease: tx.Lease}, txl)
+
+	require.NoError(t, tx.WellFormed(spec, config.Consensus[protocol.ConsensusFuture]))
+	require.Error(t, tx.WellFormed(spec, config.Consensus[protocol.ConsensusCurrentVersion]))
+
+	// the lease is part of the transaction ID
+	other := tx
+	other.Lease[0]++
+	require.NotEqual(t, tx.ID(), other.ID())
+}
+
+func TestTransactionAddresses(t *testing.T) {
+	var sender, receiver, clawback, assetSender basics.Address
+	crypto.RandBytes(sender[:])
+	crypto.RandBytes(receiver[:])
+	crypto.RandBytes(clawback[:])
+	crypto.RandBytes(assetSender[:])
+
+	payment := Transaction{
+		Type:             protocol.PaymentTx,
+		Header:           Header{Sender: sender},
+		PaymentTxnFields: PaymentTxnFields{Receiver: receiver},
+	}
+	require.Equal(t, []basics.Address{sender, receiver}, payment.Addresses())
+
+	clawbackTx := Transaction{
+		Type:                   protocol.AssetTransferTx,
+		Header:                 Header{Sender: clawback},
+		AssetTransferTxnFields: AssetTransferTxnFields{XferAsset: basics.AssetID{Creator: sender, Index: 1}, AssetSender: assetSender, AssetReceiver: receiver},
+	}
+	require.Equal(t, []basics.Address{clawback, sender, assetSender, receiver}, clawbackTx.Addresses())
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/util/execpool"
+)
+
+// Context is the context a transaction is verified in: the transaction
+// group it belongs to and the position of the transaction in it. A
+// transaction that is not part of a group is a group of one.
+type Context struct {
+	Spec       transactions.SpecialAddresses
+	Proto      config.ConsensusParams
+	Group      []transactions.SignedTxn
+	GroupIndex int
+}
+
+// Txn verifies that a SignedTxn has a good signature, or a LogicSig whose
+// program approves it, and that the underlying transaction is properly
+// constructed.
+// Note that this does not check whether a payset is valid against the ledger:
+// a SignedTxn may be well-formed, but a payset might contain an overspend.
+func Txn(s *transactions.SignedTxn, ctx Context) error {
+	if s.Lsig.Blank() {
+		return s.Verify(ctx.Spec, ctx.Proto)
+	}
+
+	if err := wellFormedLogicSigTxn(s, ctx); err != nil {
+		return err
+	}
+	return LogicSig(s, ctx)
+}
+
+// TxnPool verifies a SignedTxn like Txn does, performing the
+// cryptographic checks and the program evaluation over the provided
+// execution pool.
+func TxnPool(s *transactions.SignedTxn, ctx Context, verificationPool execpool.BacklogPool) error {
+	if s.Lsig.Blank() {
+		return s.PoolVerify(ctx.Spec, ctx.Proto, verificationPool)
+	}
+
+	if err := wellFormedLogicSigTxn(s, ctx); err != nil {
+		return err
+	}
+
+	outCh := make(chan error, 1)
+	verificationPool.EnqueueBacklog(context.Background(), func(arg interface{}) interface{} {
+		outCh := arg.(chan error)
+		if err := LogicSig(s, ctx); err != nil {
+			outCh <- err
+		}
+		close(outCh)
+		return nil
+	}, outCh, nil)
+	if err, hasErr := <-outCh; hasErr {
+		return err
+	}
+	return nil
+}
+
+// wellFormedLogicSigTxn does the checks of a transaction with a LogicSig
+// that do not involve its program.
+func wellFormedLogicSigTxn(s *transactions.SignedTxn, ctx Context) error {
+	if err := s.Txn.WellFormed(ctx.Spec, ctx.Proto); err != nil {
+		return err
+	}
+
+	if s.Txn.Src() == (basics.Address{}) {
+		return errors.New("empty address")
+	}
+
+	if s.Sig != (crypto.Signature{}) || !s.Msig.Blank() {
+		return errors.New("signedtxn should only have one of Sig or Msig or LogicSig")
+	}
+
+	if s.AuthAddr != (basics.Address{}) && !ctx.Proto.SupportRekeying {
+		return errors.New("signedtxn has an AuthAddr, but rekeying is not supported")
+	}
+	return nil
+}
+
+// LogicSig checks that the LogicSig of a transaction is either the program
+// of the escrow account authorizing the transaction, or a program that the
+// authorizer signed to delegate to it, and that the program approves the
+// transaction.
+func LogicSig(s *transactions.SignedTxn, ctx Context) error {
+	lsig := &s.Lsig
+	if ctx.Proto.LogicSigVersion == 0 {
+		return errors.New("LogicSig not enabled")
+	}
+	if len(lsig.Logic) == 0 {
+		return errors.New("LogicSig.Logic empty")
+	}
+	if uint64(lsig.Len()) > ctx.Proto.LogicSigMaxSize {
+		return fmt.Errorf("LogicSig of %d bytes is over the limit of %d", lsig.Len(), ctx.Proto.LogicSigMaxSize)
+	}
+
+	ep := logic.EvalParams{
+		Txn:        s,
+		Proto:      &ctx.Proto,
+		TxnGroup:   ctx.Group,
+		GroupIndex: ctx.GroupIndex,
+	}
+	cost, err := logic.Check(lsig.Logic, ep)
+	if err != nil {
+		return err
+	}
+	if uint64(cost) > ctx.Proto.LogicSigMaxCost {
+		return fmt.Errorf("LogicSig cost %d is over the limit of %d", cost, ctx.Proto.LogicSigMaxCost)
+	}
+
+	hasMsig := !lsig.Msig.Blank()
+	hasSig := lsig.Sig != (crypto.Signature{})
+	authorizer := s.Authorizer()
+	switch {
+	case hasSig && hasMsig:
+		return errors.New("LogicSig should only have one of Sig or Msig")
+	case hasSig:
+		if !crypto.SignatureVerifier(authorizer).Verify(logic.Program(lsig.Logic), lsig.Sig) {
+			return errors.New("LogicSig signature failed to verify")
+		}
+	case hasMsig:
+		if ok, _ := crypto.MultisigVerify(logic.Program(lsig.Logic), crypto.Digest(authorizer), lsig.Msig); !ok {
+			return errors.New("LogicSig multisig failed to verify")
+		}
+	default:
+		// an escrow account, whose address is the hash of its program
+		if logic.HashProgram(lsig.Logic) != crypto.Digest(authorizer) {
+			return fmt.Errorf("LogicSig program hash does not match %v", authorizer)
+		}
+	}
+
+	pass, err := logic.Eval(lsig.Logic, ep)
+	if err != nil {
+		return fmt.Errorf("LogicSig program failed: %v", err)
+	}
+	if !pass {
+		return errors.New("rejected by LogicSig program")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/execpool"
+)
+
+func testPayment(sender basics.Address) transactions.Transaction {
+	var receiver basics.Address
+	crypto.RandBytes(receiver[:])
+	return transactions.Transaction{
+		Type: protocol.PaymentTx,
+		Header: transactions.Header{
+			Sender:     sender,
+			Fee:        basics.MicroAlgos{Raw: 1000},
+			FirstValid: 1,
+			LastValid:  100,
+		},
+		PaymentTxnFields: transactions.PaymentTxnFields{
+			Receiver: receiver,
+			Amount:   basics.MicroAlgos{Raw: 5000},
+		},
+	}
+}
+
+func testContext(s *transactions.SignedTxn, version protocol.ConsensusVersion) Context {
+	return Context{
+		Proto: config.Consensus[version],
+		Group: []transactions.SignedTxn{*s},
+	}
+}
+
+func TestLogicSigEscrow(t *testing.T) {
+	// pays out at most 10000 microalgos at a time
+	program, err := logic.AssembleString("txn Amount\nint 10000\n<=")
+	require.NoError(t, err)
+	escrow := basics.Address(logic.HashProgram(program))
+
+	s := transactions.SignedTxn{Txn: testPayment(escrow)}
+	s.Lsig.Logic = program
+	require.NoError(t, Txn(&s, testContext(&s, protocol.ConsensusFuture)))
+
+	pool := execpool.MakeBacklog(nil, 0, execpool.LowPriority, nil)
+	defer pool.Shutdown()
+	require.NoError(t, TxnPool(&s, testContext(&s, protocol.ConsensusFuture), pool))
+
+	// the program rejects larger payments
+	s.Txn.Amount.Raw = 20000
+	require.Error(t, Txn(&s, testContext(&s, protocol.ConsensusFuture)))
+	require.Error(t, TxnPool(&s, testContext(&s, protocol.ConsensusFuture), pool))
+	s.Txn.Amount.Raw = 5000
+
+	// not enabled yet
+	require.Error(t, Txn(&s, testContext(&s, protocol.ConsensusCurrentVersion)))
+
+	// the sender is not the program's address
+	var other basics.Address
+	crypto.RandBytes(other[:])
+	s.Txn.Sender = other
+	require.Error(t, Txn(&s, testContext(&s, protocol.ConsensusFuture)))
+}
+
+func TestLogicSigDelegated(t *testing.T) {
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	secrets := crypto.GenerateSignatureSecrets(seed)
+	sender := basics.Address(secrets.SignatureVerifier)
+
+	program, err := logic.AssembleString("txn Fee\nint 2000\n<=")
+	require.NoError(t, err)
+
+	s := transactions.SignedTxn{Txn: testPayment(sender)}
+	s.Lsig.Logic = program
+	s.Lsig.Sig = secrets.Sign(logic.Program(program))
+	require.NoError(t, Txn(&s, testContext(&s, protocol.ConsensusFuture)))
+
+	// the program must be signed, not the bytes alone
+	s.Lsig.Sig = secrets.SignBytes(program)
+	require.Error(t, Txn(&s, testContext(&s, protocol.ConsensusFuture)))
+	s.Lsig.Sig = secrets.Sign(logic.Program(program))
+
+	// a transaction signature along with the LogicSig
+	s.Sig = secrets.Sign(s.Txn)
+	require.Error(t, Txn(&s, testContext(&s, protocol.ConsensusFuture)))
+	s.Sig = crypto.Signature{}
+
+	// arguments count towards the size limit
+	proto := config.Consensus[protocol.ConsensusFuture]
+	s.Lsig.Args = [][]byte{make([]byte, proto.LogicSigMaxSize)}
+	require.Error(t, Txn(&s, testContext(&s, protocol.ConsensusFuture)))
+
+	// transactions with a LogicSig can't be verified without their group
+	s.Lsig.Args = nil
+	require.Error(t, s.Verify(transactions.SpecialAddresses{}, proto))
+}
@@ -19,12 +19,14 @@ package data
 import (
 	"context"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/data/pools"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/verify"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/network"
 	"github.com/algorand/go-algorand/protocol"
@@ -41,13 +43,13 @@ var transactionMessagesHandled = metrics.MakeCounter(metrics.TransactionMessages
 var transactionMessagesDroppedFromBacklog = metrics.MakeCounter(metrics.TransactionMessagesDroppedFromBacklog)
 var transactionMessagesDroppedFromPool = metrics.MakeCounter(metrics.TransactionMessagesDroppedFromPool)
 
-// The txBacklogMsg structure used to track a single incoming transaction from the gossip network,
+// The txBacklogMsg structure used to track a single incoming transaction group from the gossip network,
 type txBacklogMsg struct {
-	rawmsg          *network.IncomingMessage      // the raw message from the network
-	unverifiedTxn   *transactions.SignedTxn       // the unverified ( and signed ) transaction
-	proto           config.ConsensusParams        // the consensus parameters that corresponds to the latest round. Filled in during checkAlreadyCommitted execution.
-	spec            transactions.SpecialAddresses // corresponds to the latest round
-	verificationErr error                         // The verification error generated by the verification function, if any.
+	rawmsg            *network.IncomingMessage      // the raw message from the network
+	unverifiedTxGroup []transactions.SignedTxn      // the unverified ( and signed ) transactions of the group
+	proto             config.ConsensusParams        // the consensus parameters that corresponds to the latest round. Filled in during checkAlreadyCommitted execution.
+	spec              transactions.SpecialAddresses // corresponds to the latest round
+	verificationErr   error                         // The verification error generated by the verification function, if any.
 }
 
 // TxHandler handles transaction messages
@@ -63,6 +65,7 @@ type TxHandler struct {
 	net                   network.GossipNode
 	ctx                   context.Context
 	ctxCancel             context.CancelFunc
+	maxTxGroupSize        int // the largest group any known consensus version allows, to bound incoming messages
 }
 
 // MakeTxHandler makes a new handler for transaction messages
@@ -87,6 +90,12 @@ func MakeTxHandler(txPool *pools.TransactionPool, ledger *Ledger, net network.Go
 		backlogQueue:          make(chan *txBacklogMsg, txBacklogSize),
 		postVerificationQueue: make(chan *txBacklogMsg, txBacklogSize),
 		net:                   net,
+		maxTxGroupSize:        1,
+	}
+	for _, params := range config.Consensus {
+		if params.MaxTxGroupSize > handler.maxTxGroupSize {
+			handler.maxTxGroupSize = params.MaxTxGroupSize
+		}
 	}
 
 	net.RegisterHandlers([]network.TaggedMessageHandler{
@@ -122,15 +131,15 @@ func (handler *TxHandler) backlogWorker() {
 			}
 			if wi.verificationErr != nil {
 				// disconnect from peer.
-				logging.Base().Warnf("Received a malformed txn %v: %v", wi.unverifiedTxn, wi.verificationErr)
+				logging.Base().Warnf("Received a malformed tx group %v: %v", wi.unverifiedTxGroup, wi.verificationErr)
 				handler.net.Disconnect(wi.rawmsg.Sender)
 				continue
 			}
 			// at this point, we've verified the transaction, so we can safely treat the transaction as a verified transaction.
-			verifiedTxn := wi.unverifiedTxn
+			verifiedTxGroup := wi.unverifiedTxGroup
 
-			// save the transaction, if it has high enough fee and not already in the cache
-			err := handler.txPool.Remember(*verifiedTxn)
+			// save the transaction group, if it has high enough fee and not already in the cache
+			err := handler.txPool.RememberGroup(verifiedTxGroup)
 			if err != nil {
 				logging.Base().Debugf("could not remember tx: %v", err)
 				continue
@@ -159,7 +168,7 @@ func (handler *TxHandler) backlogWorker() {
 			}
 			if wi.verificationErr != nil {
 				// disconnect from peer.
-				logging.Base().Warnf("Received a malformed txn %v: %v", wi.unverifiedTxn, wi.verificationErr)
+				logging.Base().Warnf("Received a malformed tx group %v: %v", wi.unverifiedTxGroup, wi.verificationErr)
 				handler.net.Disconnect(wi.rawmsg.Sender)
 				continue
 			}
@@ -168,10 +177,10 @@ func (handler *TxHandler) backlogWorker() {
 			transactionMessagesHandled.Inc(nil)
 
 			// at this point, we've verified the transaction, so we can safely treat the transaction as a verified transaction.
-			verifiedTxn := wi.unverifiedTxn
+			verifiedTxGroup := wi.unverifiedTxGroup
 
-			// save the transaction, if it has high enough fee and not already in the cache
-			err := handler.txPool.Remember(*verifiedTxn)
+			// save the transaction group, if it has high enough fee and not already in the cache
+			err := handler.txPool.RememberGroup(verifiedTxGroup)
 			if err != nil {
 				logging.Base().Debugf("could not remember tx: %v", err)
 				continue
@@ -183,10 +192,17 @@ func (handler *TxHandler) backlogWorker() {
 	}
 }
 
-// asyncVerifySignature verifies that the given transaction is valid, and update the txBacklogMsg data structure accordingly.
+// asyncVerifySignature verifies that the given transaction group is valid, and update the txBacklogMsg data structure accordingly.
 func (handler *TxHandler) asyncVerifySignature(arg interface{}) interface{} {
 	tx := arg.(*txBacklogMsg)
-	tx.verificationErr = tx.unverifiedTxn.Verify(tx.spec, tx.proto)
+	tx.verificationErr = transactions.WellFormedGroup(tx.unverifiedTxGroup, tx.proto)
+	for i := range tx.unverifiedTxGroup {
+		if tx.verificationErr != nil {
+			break
+		}
+		ctx := verify.Context{Spec: tx.spec, Proto: tx.proto, Group: tx.unverifiedTxGroup, GroupIndex: i}
+		tx.verificationErr = verify.Txn(&tx.unverifiedTxGroup[i], ctx)
+	}
 	select {
 	case handler.postVerificationQueue <- tx:
 	default:
@@ -197,18 +213,36 @@ func (handler *TxHandler) asyncVerifySignature(arg interface{}) interface{} {
 	return nil
 }
 
+// processIncomingTxn handles a transaction message, which holds the concatenated encodings of the
+// transactions of a group, or a single transaction.
 func (handler *TxHandler) processIncomingTxn(rawmsg network.IncomingMessage) network.OutgoingMessage {
-	var unverifiedTxn transactions.SignedTxn
-	err := protocol.Decode(rawmsg.Data, &unverifiedTxn)
-	if err != nil {
-		logging.Base().Warnf("Received a non-decodable txn: %v", err)
+	var unverifiedTxGroup []transactions.SignedTxn
+	dec := protocol.NewDecoderBytes(rawmsg.Data)
+	for {
+		var unverifiedTxn transactions.SignedTxn
+		err := dec.Decode(&unverifiedTxn)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logging.Base().Warnf("Received a non-decodable txn: %v", err)
+			return network.OutgoingMessage{Action: network.Disconnect}
+		}
+		if len(unverifiedTxGroup) == handler.maxTxGroupSize {
+			logging.Base().Warnf("Received a tx group with more than %d transactions", handler.maxTxGroupSize)
+			return network.OutgoingMessage{Action: network.Disconnect}
+		}
+		unverifiedTxGroup = append(unverifiedTxGroup, unverifiedTxn)
+	}
+	if len(unverifiedTxGroup) == 0 {
+		logging.Base().Warnf("Received an empty tx group")
 		return network.OutgoingMessage{Action: network.Disconnect}
 	}
 
 	select {
 	case handler.backlogQueue <- &txBacklogMsg{
-		rawmsg:        &rawmsg,
-		unverifiedTxn: &unverifiedTxn,
+		rawmsg:            &rawmsg,
+		unverifiedTxGroup: unverifiedTxGroup,
 	}:
 	default:
 		// if we failed here we want to increase the corresponding metric. It might suggest that we
@@ -219,17 +253,21 @@ func (handler *TxHandler) processIncomingTxn(rawmsg network.IncomingMessage) net
 	return network.OutgoingMessage{Action: network.Ignore}
 }
 
-// checkAlreadyCommitted test to see if the given transaction ( in the txBacklogMsg ) was already commited, and
+// checkAlreadyCommitted test to see if the given transaction group ( in the txBacklogMsg ) was already commited, and
 // whether it would qualify as a candidate for the transaction pool.
 func (handler *TxHandler) checkAlreadyCommitted(tx *txBacklogMsg) (processingDone bool) {
-	tx.unverifiedTxn.Txn.InitCaches()
-	logging.Base().Debugf("got a tx with ID %v", tx.unverifiedTxn.ID())
+	for i := range tx.unverifiedTxGroup {
+		tx.unverifiedTxGroup[i].InitCaches()
+		logging.Base().Debugf("got a tx with ID %v", tx.unverifiedTxGroup[i].ID())
+	}
 
-	// do a quick test to check that this transaction could potentially be committed, to reject dup pending transactions
-	err := handler.txPool.Test(*tx.unverifiedTxn)
-	if err != nil {
-		logging.Base().Debugf("txPool rejected transaction: %v", err)
-		return true
+	// do a quick test to check that these transactions could potentially be committed, to reject dup pending transactions
+	for _, txn := range tx.unverifiedTxGroup {
+		err := handler.txPool.Test(txn)
+		if err != nil {
+			logging.Base().Debugf("txPool rejected transaction: %v", err)
+			return true
+		}
 	}
 
 	// build the transaction verification context
@@ -250,34 +288,37 @@ func (handler *TxHandler) checkAlreadyCommitted(tx *txBacklogMsg) (processingDon
 		GenHash:       handler.genesisHash,
 	}
 
-	err = tx.unverifiedTxn.Txn.Alive(tc)
-	if err != nil {
-		logging.Base().Debugf("Received a dead txn %s: %v", tx.unverifiedTxn.ID(), err)
-		return true
-	}
+	for _, txn := range tx.unverifiedTxGroup {
+		err = txn.Txn.Alive(tc)
+		if err != nil {
+			logging.Base().Debugf("Received a dead txn %s: %v", txn.ID(), err)
+			return true
+		}
 
-	committed, err := handler.ledger.Committed(*tx.unverifiedTxn)
-	if err != nil {
-		logging.Base().Errorf("Could not verify committed status of txn %v: %v", tx.unverifiedTxn, err)
-		return true
-	}
+		committed, err := handler.ledger.Committed(txn)
+		if err != nil {
+			logging.Base().Errorf("Could not verify committed status of txn %v: %v", txn, err)
+			return true
+		}
 
-	if committed {
-		logging.Base().Debugf("Already confirmed tx %v", tx.unverifiedTxn.ID())
-		return true
+		if committed {
+			logging.Base().Debugf("Already confirmed tx %v", txn.ID())
+			return true
+		}
 	}
 	return false
 }
 
 func (handler *TxHandler) processDecoded(unverifiedTxn transactions.SignedTxn) (outmsg network.OutgoingMessage, processingDone bool) {
 	tx := &txBacklogMsg{
-		unverifiedTxn: &unverifiedTxn,
+		unverifiedTxGroup: []transactions.SignedTxn{unverifiedTxn},
 	}
 	if handler.checkAlreadyCommitted(tx) {
 		return network.OutgoingMessage{}, true
 	}
 
-	err := tx.unverifiedTxn.PoolVerify(tx.spec, tx.proto, handler.txVerificationPool)
+	ctx := verify.Context{Spec: tx.spec, Proto: tx.proto, Group: tx.unverifiedTxGroup, GroupIndex: 0}
+	err := verify.TxnPool(&tx.unverifiedTxGroup[0], ctx, handler.txVerificationPool)
 	if err != nil {
 		// transaction is invalid
 		logging.Base().Warnf("Received a malformed txn %v: %v", unverifiedTxn, err)
@@ -285,7 +326,7 @@ func (handler *TxHandler) processDecoded(unverifiedTxn transactions.SignedTxn) (
 	}
 
 	// at this point, we've verified the transaction, so we can safely treat the transaction as a verified transaction.
-	verifiedTxn := unverifiedTxn
+	verifiedTxn := tx.unverifiedTxGroup[0]
 
 	// save the transaction, if it has high enough fee and not already in the cache
 	err = handler.txPool.Remember(verifiedTxn)
@@ -29,6 +29,7 @@ import (
 	"github.com/algorand/go-algorand/data/pools"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/network"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/util/execpool"
 )
@@ -62,7 +63,7 @@ func BenchmarkTxHandlerProcessDecoded(b *testing.B) {
 	l := ledger
 
 	const txPoolSize = 20000
-	tp := pools.MakeTransactionPool(l, 2, txPoolSize, false)
+	tp := pools.MakeTransactionPool(l, 2, txPoolSize, 0, false)
 	signedTransactions := make([]transactions.SignedTxn, 0, b.N)
 	for i := 0; i < b.N/numUsers; i++ {
 		for u := 0; u < numUsers; u++ {
@@ -92,3 +93,29 @@ func BenchmarkTxHandlerProcessDecoded(b *testing.B) {
 		txHandler.processDecoded(signedTxn)
 	}
 }
+
+func TestTxHandlerIncomingGroupSize(t *testing.T) {
+	const maxTxGroupSize = 4
+	handler := &TxHandler{
+		backlogQueue:   make(chan *txBacklogMsg, 1),
+		maxTxGroupSize: maxTxGroupSize,
+	}
+	message := func(n int) network.IncomingMessage {
+		var data []byte
+		for i := 0; i < n; i++ {
+			txn := transactions.Transaction{Type: protocol.PaymentTx, Header: transactions.Header{Sender: basics.Address{byte(i + 1)}}}
+			data = append(data, protocol.Encode(transactions.SignedTxn{Txn: txn})...)
+		}
+		return network.IncomingMessage{Data: data}
+	}
+
+	require.Equal(t, network.Disconnect, handler.processIncomingTxn(message(0)).Action)
+
+	require.Equal(t, network.Ignore, handler.processIncomingTxn(message(maxTxGroupSize)).Action)
+	queued := <-handler.backlogQueue
+	require.Len(t, queued.unverifiedTxGroup, maxTxGroupSize)
+
+	// oversized groups are rejected without being queued
+	require.Equal(t, network.Disconnect, handler.processIncomingTxn(message(maxTxGroupSize+1)).Action)
+	require.Len(t, handler.backlogQueue, 0)
+}
@@ -0,0 +1,449 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// Names of the special genesis accounts, as they appear in the allocation comments.
+const (
+	rewardsPoolComment = "RewardsPool"
+	feeSinkComment     = "FeeSink"
+)
+
+// AllocationSpec describes a single genesis account of a hand-built genesis file.
+// Online accounts take their participation keys either from PartKeyFile (as created by
+// 'goal account addpartkey' or 'algokey part generate'), or from the base64 encoded
+// SelectionID and VoteID along with the voting round range.
+type AllocationSpec struct {
+	Address         string `yaml:"address"`
+	Comment         string `yaml:"comment"`
+	MicroAlgos      uint64 `yaml:"microalgos"`
+	Status          string `yaml:"status"`
+	PartKeyFile     string `yaml:"partkey"`
+	SelectionID     string `yaml:"selectionkey"`
+	VoteID          string `yaml:"votekey"`
+	VoteFirstValid  uint64 `yaml:"votefirst"`
+	VoteLastValid   uint64 `yaml:"votelast"`
+	VoteKeyDilution uint64 `yaml:"votekd"`
+}
+
+// GenesisParams holds the network parameters of a hand-built genesis file.
+// Zero values are replaced by the defaults used by GenerateGenesisFiles.
+type GenesisParams struct {
+	NetworkName        string
+	SchemaID           string
+	ConsensusProtocol  protocol.ConsensusVersion
+	Timestamp          int64
+	Comment            string
+	FeeSink            basics.Address
+	RewardsPool        basics.Address
+	FeeSinkBalance     uint64
+	RewardsPoolBalance uint64
+}
+
+// GenesisSummary describes a built genesis, for reviewing it before it is distributed.
+type GenesisSummary struct {
+	ID                   string
+	Hash                 crypto.Digest
+	Proto                protocol.ConsensusVersion
+	Accounts             int
+	OnlineAccounts       int
+	TotalMicroAlgos      uint64
+	OnlineMicroAlgos     uint64
+	OnlineStakeByAccount map[string]uint64
+}
+
+// allocationColumns are the recognized columns of an allocations CSV file.
+var allocationColumns = []string{"address", "comment", "microalgos", "status", "partkey", "selectionkey", "votekey", "votefirst", "votelast", "votekd"}
+
+// LoadAllocations loads the genesis account specs from a CSV file (with a header line naming
+// the columns), or from a YAML file (.yaml or .yml) holding a list of accounts.
+func LoadAllocations(file string) (allocs []AllocationSpec, err error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		var data []byte
+		data, err = ioutil.ReadFile(file)
+		if err != nil {
+			return
+		}
+		err = yaml.UnmarshalStrict(data, &allocs)
+	default:
+		var f *os.File
+		f, err = os.Open(file)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		allocs, err = parseAllocationsCSV(f)
+	}
+	if err != nil {
+		err = fmt.Errorf("unable to parse %s: %v", file, err)
+		return
+	}
+	// relative participation key paths are relative to the allocations file.
+	for i := range allocs {
+		if allocs[i].PartKeyFile != "" && !filepath.IsAbs(allocs[i].PartKeyFile) {
+			allocs[i].PartKeyFile = filepath.Join(filepath.Dir(file), allocs[i].PartKeyFile)
+		}
+	}
+	return
+}
+
+func parseAllocationsCSV(r io.Reader) (allocs []AllocationSpec, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header line: %v", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		known := false
+		for _, column := range allocationColumns {
+			known = known || column == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown column '%s', expected some of %s", name, strings.Join(allocationColumns, ", "))
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"address", "microalgos"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing '%s' column", required)
+		}
+	}
+
+	for line := 1; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return allocs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		getUint := func(column string) (v uint64) {
+			text := strings.NewReplacer(",", "", "_", "").Replace(get(column))
+			if text == "" || err != nil {
+				return 0
+			}
+			v, err = strconv.ParseUint(text, 10, 64)
+			if err != nil {
+				err = fmt.Errorf("record %d: invalid %s '%s'", line, column, get(column))
+			}
+			return
+		}
+		alloc := AllocationSpec{
+			Address:         get("address"),
+			Comment:         get("comment"),
+			MicroAlgos:      getUint("microalgos"),
+			Status:          get("status"),
+			PartKeyFile:     get("partkey"),
+			SelectionID:     get("selectionkey"),
+			VoteID:          get("votekey"),
+			VoteFirstValid:  getUint("votefirst"),
+			VoteLastValid:   getUint("votelast"),
+			VoteKeyDilution: getUint("votekd"),
+		}
+		if err != nil {
+			return nil, err
+		}
+		allocs = append(allocs, alloc)
+	}
+}
+
+// parseStatus parses an account status, as spelled by basics.Status.String or by the incorporate tool.
+func parseStatus(status string) (basics.Status, error) {
+	switch strings.ToLower(strings.Replace(status, " ", "", -1)) {
+	case "online":
+		return basics.Online, nil
+	case "", "offline":
+		return basics.Offline, nil
+	case "notparticipating":
+		return basics.NotParticipating, nil
+	default:
+		return basics.Offline, fmt.Errorf("unknown status '%s', expected Online, Offline or NotParticipating", status)
+	}
+}
+
+// accountData builds the genesis state of a single account, filling in the participation keys of online accounts.
+func (alloc AllocationSpec) accountData(addr basics.Address, proto config.ConsensusParams) (data basics.AccountData, err error) {
+	data.Status, err = parseStatus(alloc.Status)
+	if err != nil {
+		return
+	}
+	data.MicroAlgos.Raw = alloc.MicroAlgos
+	if data.Status != basics.Online {
+		if alloc.PartKeyFile != "" || alloc.SelectionID != "" || alloc.VoteID != "" {
+			err = fmt.Errorf("participation keys given for %s account", data.Status)
+		}
+		return
+	}
+
+	if alloc.PartKeyFile != "" {
+		if alloc.SelectionID != "" || alloc.VoteID != "" {
+			return data, fmt.Errorf("both a participation key file and participation keys are given")
+		}
+		part, partDB, err := loadPartKeys(alloc.PartKeyFile)
+		if err != nil {
+			return data, err
+		}
+		defer partDB.Close()
+		if part.Address() != addr {
+			return data, fmt.Errorf("participation key file %s belongs to %v", alloc.PartKeyFile, part.Address())
+		}
+		data.SelectionID = part.VRFSecrets().PK
+		data.VoteID = part.VotingSecrets().OneTimeSignatureVerifier
+		data.VoteFirstValid = part.FirstValid
+		data.VoteLastValid = part.LastValid
+		data.VoteKeyDilution = part.KeyDilution
+	} else {
+		var selection, vote []byte
+		if selection, err = decodeKey(alloc.SelectionID, len(data.SelectionID)); err != nil {
+			return data, fmt.Errorf("invalid selection key: %v", err)
+		}
+		if vote, err = decodeKey(alloc.VoteID, len(data.VoteID)); err != nil {
+			return data, fmt.Errorf("invalid vote key: %v", err)
+		}
+		copy(data.SelectionID[:], selection)
+		copy(data.VoteID[:], vote)
+		data.VoteFirstValid = basics.Round(alloc.VoteFirstValid)
+		data.VoteLastValid = basics.Round(alloc.VoteLastValid)
+		data.VoteKeyDilution = alloc.VoteKeyDilution
+		if data.VoteKeyDilution == 0 {
+			data.VoteKeyDilution = proto.DefaultKeyDilution
+		}
+	}
+
+	if data.VoteFirstValid != 0 {
+		return data, fmt.Errorf("voting keys are first valid at round %d rather than 0", data.VoteFirstValid)
+	}
+	if !proto.ExplicitEphemeralParams {
+		data.VoteFirstValid, data.VoteLastValid, data.VoteKeyDilution = 0, 0, 0
+	} else if data.VoteLastValid == 0 {
+		return data, fmt.Errorf("voting keys have no last valid round")
+	}
+	return
+}
+
+func decodeKey(key string, size int) ([]byte, error) {
+	if key == "" {
+		return nil, fmt.Errorf("missing key")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) != size {
+		return nil, fmt.Errorf("key is %d bytes long rather than %d", len(decoded), size)
+	}
+	return decoded, nil
+}
+
+// BuildGenesis builds and validates a genesis from the given network parameters and account specs.
+// The rewards pool and the fee sink are the first two allocations; they are added with their
+// default balances unless the specs already include them.
+func BuildGenesis(params GenesisParams, allocs []AllocationSpec) (g bookkeeping.Genesis, err error) {
+	if params.NetworkName == "" {
+		return g, fmt.Errorf("network name not given")
+	}
+	if strings.Contains(params.NetworkName, "-") {
+		return g, fmt.Errorf("network name '%s' must not contain a '-'", params.NetworkName)
+	}
+	if params.SchemaID == "" {
+		params.SchemaID = schemaID
+	}
+	if params.ConsensusProtocol == "" {
+		params.ConsensusProtocol = protocol.ConsensusCurrentVersion
+	}
+	proto, ok := config.Consensus[params.ConsensusProtocol]
+	if !ok {
+		return g, fmt.Errorf("protocol %s not supported", params.ConsensusProtocol)
+	}
+	if (params.FeeSink == basics.Address{}) {
+		params.FeeSink = defaultSinkAddr
+	}
+	if (params.RewardsPool == basics.Address{}) {
+		params.RewardsPool = defaultPoolAddr
+	}
+	if params.FeeSink == params.RewardsPool {
+		return g, fmt.Errorf("the fee sink and the rewards pool must be different accounts")
+	}
+	if params.FeeSinkBalance == 0 {
+		params.FeeSinkBalance = proto.MinBalance
+	}
+	if params.RewardsPoolBalance == 0 {
+		params.RewardsPoolBalance = defaultIncentivePoolBalanceAtInception
+	}
+
+	g = bookkeeping.Genesis{
+		SchemaID:    params.SchemaID,
+		Network:     protocol.NetworkID(params.NetworkName),
+		Proto:       params.ConsensusProtocol,
+		RewardsPool: params.RewardsPool.GetChecksumAddress().String(),
+		FeeSink:     params.FeeSink.GetChecksumAddress().String(),
+		Timestamp:   params.Timestamp,
+		Comment:     params.Comment,
+	}
+	special := map[basics.Address]bookkeeping.GenesisAllocation{
+		params.RewardsPool: {
+			Address: g.RewardsPool,
+			Comment: rewardsPoolComment,
+			State:   basics.AccountData{Status: basics.NotParticipating, MicroAlgos: basics.MicroAlgos{Raw: params.RewardsPoolBalance}},
+		},
+		params.FeeSink: {
+			Address: g.FeeSink,
+			Comment: feeSinkComment,
+			State:   basics.AccountData{Status: basics.NotParticipating, MicroAlgos: basics.MicroAlgos{Raw: params.FeeSinkBalance}},
+		},
+	}
+
+	seen := make(map[basics.Address]bool)
+	var accounts []bookkeeping.GenesisAllocation
+	for i, alloc := range allocs {
+		addr, err := basics.UnmarshalChecksumAddress(alloc.Address)
+		if err != nil {
+			return g, fmt.Errorf("account %d: %v", i+1, err)
+		}
+		if seen[addr] {
+			return g, fmt.Errorf("account %s is allocated more than once", alloc.Address)
+		}
+		seen[addr] = true
+		def, isSpecial := special[addr]
+		if isSpecial && alloc.Status == "" {
+			alloc.Status = basics.NotParticipating.String()
+		}
+		data, err := alloc.accountData(addr, proto)
+		if err != nil {
+			return g, fmt.Errorf("account %s: %v", alloc.Address, err)
+		}
+		genesisAlloc := bookkeeping.GenesisAllocation{Address: addr.GetChecksumAddress().String(), Comment: alloc.Comment, State: data}
+		if isSpecial {
+			if data.Status != basics.NotParticipating {
+				return g, fmt.Errorf("%s account %s must be NotParticipating", def.Comment, alloc.Address)
+			}
+			if genesisAlloc.Comment == "" {
+				genesisAlloc.Comment = def.Comment
+			}
+			special[addr] = genesisAlloc
+			continue
+		}
+		accounts = append(accounts, genesisAlloc)
+	}
+	g.Allocation = append([]bookkeeping.GenesisAllocation{special[params.RewardsPool], special[params.FeeSink]}, accounts...)
+
+	err = ValidateGenesis(g)
+	return
+}
+
+// ValidateGenesis checks that the genesis allocations make up a ledger that can make progress:
+// every account holds at least the minimum balance, the total doesn't overflow and some stake is online.
+func ValidateGenesis(g bookkeeping.Genesis) error {
+	proto, ok := config.Consensus[g.Proto]
+	if !ok {
+		return fmt.Errorf("protocol %s not supported", g.Proto)
+	}
+	var ot basics.OverflowTracker
+	var total, online basics.MicroAlgos
+	for _, alloc := range g.Allocation {
+		if alloc.State.MicroAlgos.Raw < proto.MinBalance {
+			return fmt.Errorf("account %s has less than the minimum balance: %d < %d", alloc.Address, alloc.State.MicroAlgos.Raw, proto.MinBalance)
+		}
+		total = ot.AddA(total, alloc.State.MicroAlgos)
+		if alloc.State.Status == basics.Online {
+			online = ot.AddA(online, alloc.State.MicroAlgos)
+		}
+	}
+	if ot.Overflowed {
+		return fmt.Errorf("total genesis balance overflows")
+	}
+	if online.Raw == 0 {
+		return fmt.Errorf("no online accounts")
+	}
+	return nil
+}
+
+// SummarizeGenesis computes the identifiers and the stake distribution of the given genesis.
+func SummarizeGenesis(g bookkeeping.Genesis) (s GenesisSummary) {
+	s.ID = g.ID()
+	s.Hash = crypto.HashObj(g)
+	s.Proto = g.Proto
+	s.Accounts = len(g.Allocation)
+	s.OnlineStakeByAccount = make(map[string]uint64)
+	for _, alloc := range g.Allocation {
+		s.TotalMicroAlgos += alloc.State.MicroAlgos.Raw
+		if alloc.State.Status == basics.Online {
+			s.OnlineAccounts++
+			s.OnlineMicroAlgos += alloc.State.MicroAlgos.Raw
+			s.OnlineStakeByAccount[alloc.Address] = alloc.State.MicroAlgos.Raw
+		}
+	}
+	return
+}
+
+// Print writes a human readable report of the summary.
+func (s GenesisSummary) Print(w io.Writer) {
+	fmt.Fprintf(w, "Genesis ID:     %s\n", s.ID)
+	fmt.Fprintf(w, "Genesis hash:   %s\n", s.Hash.String())
+	fmt.Fprintf(w, "Protocol:       %s\n", s.Proto)
+	fmt.Fprintf(w, "Accounts:       %d (%d online)\n", s.Accounts, s.OnlineAccounts)
+	fmt.Fprintf(w, "Total stake:    %d microAlgos\n", s.TotalMicroAlgos)
+	fmt.Fprintf(w, "Online stake:   %d microAlgos\n", s.OnlineMicroAlgos)
+
+	addresses := make([]string, 0, len(s.OnlineStakeByAccount))
+	for addr := range s.OnlineStakeByAccount {
+		addresses = append(addresses, addr)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return s.OnlineStakeByAccount[addresses[i]] > s.OnlineStakeByAccount[addresses[j]] ||
+			(s.OnlineStakeByAccount[addresses[i]] == s.OnlineStakeByAccount[addresses[j]] && addresses[i] < addresses[j])
+	})
+	for _, addr := range addresses {
+		fmt.Fprintf(w, "  %s %6.2f%%\n", addr, 100*float64(s.OnlineStakeByAccount[addr])/float64(s.OnlineMicroAlgos))
+	}
+}
+
+// WriteGenesisFile writes the genesis the same way GenerateGenesisFiles does.
+func WriteGenesisFile(g bookkeeping.Genesis, file string) error {
+	return ioutil.WriteFile(file, append(protocol.EncodeJSON(g), '\n'), 0666)
+}
@@ -0,0 +1,222 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/account"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+func testAddress(i byte) basics.Address {
+	var seed crypto.Seed
+	seed[0] = i
+	return basics.Address(crypto.GenerateSignatureSecrets(seed).SignatureVerifier)
+}
+
+func testOnlineSpec(i byte, microAlgos uint64) AllocationSpec {
+	return AllocationSpec{
+		Address:        testAddress(i).GetChecksumAddress().String(),
+		MicroAlgos:     microAlgos,
+		Status:         "Online",
+		SelectionID:    base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{i}, 32)),
+		VoteID:         base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{i}, 32)),
+		VoteFirstValid: 0,
+		VoteLastValid:  1000,
+	}
+}
+
+func TestLoadAllocationsCSV(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	online := testOnlineSpec(1, 3000000)
+	offline := testAddress(2).GetChecksumAddress().String()
+	csvText := "address, comment, microalgos, status, selectionkey, votekey, votefirst, votelast, votekd, partkey\n" +
+		"# comments are skipped\n" +
+		fmt.Sprintf("%s, Wallet1, \"3,000,000\", Online, %s, %s, 0, 1000, , \n", online.Address, online.SelectionID, online.VoteID) +
+		fmt.Sprintf("%s, Wallet2, 1_000_000, Offline, , , , , , keys/wallet2.partkey\n", offline)
+	file := filepath.Join(dir, "accounts.csv")
+	require.NoError(t, ioutil.WriteFile(file, []byte(csvText), 0666))
+
+	allocs, err := LoadAllocations(file)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(allocs))
+	online.Comment = "Wallet1"
+	require.Equal(t, online, allocs[0])
+	require.Equal(t, AllocationSpec{Address: offline, Comment: "Wallet2", MicroAlgos: 1000000, Status: "Offline", PartKeyFile: filepath.Join(dir, "keys", "wallet2.partkey")}, allocs[1])
+
+	require.NoError(t, ioutil.WriteFile(file, []byte("address, balance\n"), 0666))
+	_, err = LoadAllocations(file)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown column 'balance'")
+
+	require.NoError(t, ioutil.WriteFile(file, []byte("address, comment\n"), 0666))
+	_, err = LoadAllocations(file)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing 'microalgos' column")
+
+	require.NoError(t, ioutil.WriteFile(file, []byte("address, microalgos\n"+offline+", lots\n"), 0666))
+	_, err = LoadAllocations(file)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "record 1: invalid microalgos 'lots'")
+}
+
+func TestLoadAllocationsYAML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	addr := testAddress(1).GetChecksumAddress().String()
+	file := filepath.Join(dir, "accounts.yaml")
+	yamlText := "- address: " + addr + "\n  comment: Wallet1\n  microalgos: 5000000\n  status: Online\n  partkey: /keys/wallet1.partkey\n"
+	require.NoError(t, ioutil.WriteFile(file, []byte(yamlText), 0666))
+
+	allocs, err := LoadAllocations(file)
+	require.NoError(t, err)
+	require.Equal(t, []AllocationSpec{{Address: addr, Comment: "Wallet1", MicroAlgos: 5000000, Status: "Online", PartKeyFile: "/keys/wallet1.partkey"}}, allocs)
+
+	require.NoError(t, ioutil.WriteFile(file, []byte("- address: "+addr+"\n  balance: 5\n"), 0666))
+	_, err = LoadAllocations(file)
+	require.Error(t, err)
+}
+
+func TestBuildGenesis(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	online := testOnlineSpec(1, 3000000)
+	offline := AllocationSpec{Address: testAddress(2).GetChecksumAddress().String(), Comment: "Wallet2", MicroAlgos: 1000000}
+
+	g, err := BuildGenesis(GenesisParams{NetworkName: "private", Comment: "test"}, []AllocationSpec{online, offline})
+	require.NoError(t, err)
+	require.Equal(t, "private-v1", g.ID())
+	require.Equal(t, protocol.ConsensusCurrentVersion, g.Proto)
+	require.Equal(t, "test", g.Comment)
+	require.Equal(t, defaultPoolAddr.GetChecksumAddress().String(), g.RewardsPool)
+	require.Equal(t, defaultSinkAddr.GetChecksumAddress().String(), g.FeeSink)
+
+	require.Equal(t, 4, len(g.Allocation))
+	require.Equal(t, rewardsPoolComment, g.Allocation[0].Comment)
+	require.Equal(t, defaultIncentivePoolBalanceAtInception, g.Allocation[0].State.MicroAlgos.Raw)
+	require.Equal(t, feeSinkComment, g.Allocation[1].Comment)
+	require.Equal(t, proto.MinBalance, g.Allocation[1].State.MicroAlgos.Raw)
+	require.Equal(t, basics.NotParticipating, g.Allocation[1].State.Status)
+
+	require.Equal(t, online.Address, g.Allocation[2].Address)
+	require.Equal(t, basics.Online, g.Allocation[2].State.Status)
+	require.Equal(t, bytes.Repeat([]byte{1}, 32), g.Allocation[2].State.VoteID[:])
+	if proto.ExplicitEphemeralParams {
+		require.Equal(t, basics.Round(1000), g.Allocation[2].State.VoteLastValid)
+		require.Equal(t, proto.DefaultKeyDilution, g.Allocation[2].State.VoteKeyDilution)
+	}
+	require.Equal(t, basics.Offline, g.Allocation[3].State.Status)
+
+	// a rewards pool given in the allocations replaces the default one, in place.
+	pool := AllocationSpec{Address: testAddress(3).GetChecksumAddress().String(), MicroAlgos: 7000000}
+	g, err = BuildGenesis(GenesisParams{NetworkName: "private", RewardsPool: testAddress(3)}, []AllocationSpec{online, pool})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(g.Allocation))
+	require.Equal(t, pool.Address, g.Allocation[0].Address)
+	require.Equal(t, rewardsPoolComment, g.Allocation[0].Comment)
+	require.Equal(t, uint64(7000000), g.Allocation[0].State.MicroAlgos.Raw)
+	require.Equal(t, basics.NotParticipating, g.Allocation[0].State.Status)
+
+	summary := SummarizeGenesis(g)
+	require.Equal(t, crypto.HashObj(g), summary.Hash)
+	require.Equal(t, 1, summary.OnlineAccounts)
+	require.Equal(t, uint64(3000000), summary.OnlineMicroAlgos)
+	require.Equal(t, uint64(3000000+7000000)+proto.MinBalance, summary.TotalMicroAlgos)
+	var report bytes.Buffer
+	summary.Print(&report)
+	require.Contains(t, report.String(), summary.Hash.String())
+	require.Contains(t, report.String(), online.Address+" 100.00%")
+}
+
+func TestBuildGenesisErrors(t *testing.T) {
+	online := testOnlineSpec(1, 3000000)
+	offline := AllocationSpec{Address: testAddress(2).GetChecksumAddress().String(), MicroAlgos: 1000000}
+	noKeys := online
+	noKeys.VoteID = ""
+	lateKeys := online
+	lateKeys.VoteFirstValid = 10
+	poor := online
+	poor.MicroAlgos = 1
+	participatingPool := online
+	badAddress := offline
+	badAddress.Address = strings.ToLower(offline.Address)
+
+	testcases := []struct {
+		params GenesisParams
+		allocs []AllocationSpec
+		err    string
+	}{
+		{GenesisParams{}, []AllocationSpec{online}, "network name not given"},
+		{GenesisParams{NetworkName: "my-net"}, []AllocationSpec{online}, "must not contain a '-'"},
+		{GenesisParams{NetworkName: "private", ConsensusProtocol: "nope"}, []AllocationSpec{online}, "protocol nope not supported"},
+		{GenesisParams{NetworkName: "private"}, []AllocationSpec{offline}, "no online accounts"},
+		{GenesisParams{NetworkName: "private"}, []AllocationSpec{online, online}, "allocated more than once"},
+		{GenesisParams{NetworkName: "private"}, []AllocationSpec{noKeys}, "invalid vote key"},
+		{GenesisParams{NetworkName: "private"}, []AllocationSpec{lateKeys}, "first valid at round 10"},
+		{GenesisParams{NetworkName: "private"}, []AllocationSpec{poor}, "less than the minimum balance"},
+		{GenesisParams{NetworkName: "private", FeeSink: testAddress(1)}, []AllocationSpec{participatingPool}, "FeeSink account"},
+		{GenesisParams{NetworkName: "private"}, []AllocationSpec{online, badAddress}, "account 2"},
+	}
+	for _, testcase := range testcases {
+		_, err := BuildGenesis(testcase.params, testcase.allocs)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), testcase.err)
+	}
+}
+
+func TestBuildGenesisPartKeyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	addr := testAddress(1)
+	partFile := filepath.Join(dir, "wallet1.partkey")
+	partDB, err := db.MakeErasableAccessor(partFile)
+	require.NoError(t, err)
+	part, err := account.FillDBWithParticipationKeys(partDB, addr, 0, 100, 10)
+	require.NoError(t, err)
+	partDB.Close()
+
+	spec := AllocationSpec{Address: addr.GetChecksumAddress().String(), MicroAlgos: 3000000, Status: "Online", PartKeyFile: partFile}
+	g, err := BuildGenesis(GenesisParams{NetworkName: "private"}, []AllocationSpec{spec})
+	require.NoError(t, err)
+	require.Equal(t, part.VotingSecrets().OneTimeSignatureVerifier, g.Allocation[2].State.VoteID)
+	require.Equal(t, part.VRFSecrets().PK, g.Allocation[2].State.SelectionID)
+
+	spec.Address = testAddress(2).GetChecksumAddress().String()
+	_, err = BuildGenesis(GenesisParams{NetworkName: "private"}, []AllocationSpec{spec})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "belongs to")
+}
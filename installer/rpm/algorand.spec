@@ -29,7 +29,7 @@ This package provides an implementation of the Algorand protocol.
 
 %install
 mkdir -p %{buildroot}/usr/bin
-for f in algod kmd carpenter msgpacktool algokey catchupsrv goal; do
+for f in algod kmd carpenter msgpacktool algokey algodump catchupsrv goal; do
   install -m 755 ${GOPATH}/bin/${f} %{buildroot}/usr/bin/${f}
 done
 
@@ -73,6 +73,7 @@ fi
 /usr/bin/carpenter
 /usr/bin/msgpacktool
 /usr/bin/algokey
+/usr/bin/algodump
 /usr/bin/catchupsrv
 /usr/bin/goal
 /var/lib/algorand/config.json.example
@@ -230,7 +230,7 @@ func accountsNewRound(tx *sql.Tx, rnd basics.Round, updates map[basics.Address]a
 	defer replaceStmt.Close()
 
 	for addr, data := range updates {
-		if (data.new == basics.AccountData{}) {
+		if data.new.IsZero() {
 			// prune empty accounts
 			_, err = deleteStmt.Exec(addr[:])
 		} else {
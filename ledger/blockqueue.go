@@ -44,6 +44,9 @@ type blockQueue struct {
 	mu      deadlock.Mutex
 	cond    *sync.Cond
 	running bool
+
+	// closed is closed once the syncer wrote the queued blocks and exited
+	closed chan struct{}
 }
 
 func bqInit(l *Ledger) (*blockQueue, error) {
@@ -51,6 +54,7 @@ func bqInit(l *Ledger) (*blockQueue, error) {
 	bq.cond = sync.NewCond(&bq.mu)
 	bq.l = l
 	bq.running = true
+	bq.closed = make(chan struct{})
 
 	err := bq.l.blockDBs.rdb.Atomic(func(tx *sql.Tx) error {
 		var err0 error
@@ -65,13 +69,15 @@ func bqInit(l *Ledger) (*blockQueue, error) {
 	return bq, nil
 }
 
+// close stops the syncer, once it wrote the blocks still in the queue to disk.
 func (bq *blockQueue) close() {
 	bq.mu.Lock()
-	defer bq.mu.Unlock()
 	if bq.running {
 		bq.running = false
 		bq.cond.Broadcast()
 	}
+	bq.mu.Unlock()
+	<-bq.closed
 }
 
 func (bq *blockQueue) syncer() {
@@ -81,8 +87,9 @@ func (bq *blockQueue) syncer() {
 			bq.cond.Wait()
 		}
 
-		if !bq.running {
+		if len(bq.q) == 0 {
 			bq.mu.Unlock()
+			close(bq.closed)
 			return
 		}
 
@@ -103,6 +110,12 @@ func (bq *blockQueue) syncer() {
 
 		if err != nil {
 			bq.l.log.Warnf("blockQueue.syncer: could not flush: %v", err)
+			if !bq.running {
+				bq.l.log.Warnf("blockQueue.syncer: dropping %d unwritten blocks on close", len(bq.q))
+				bq.mu.Unlock()
+				close(bq.closed)
+				return
+			}
 		} else {
 			bq.lastCommitted += basics.Round(len(workQ))
 			bq.q = bq.q[len(workQ):]
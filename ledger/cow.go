@@ -35,6 +35,7 @@ import (
 type roundCowParent interface {
 	lookup(basics.Address) (basics.AccountData, error)
 	isDup(basics.Round, transactions.Txid) (bool, error)
+	isLeased(transactions.Txlease) (bool, error)
 }
 
 type roundCowState struct {
@@ -51,6 +52,9 @@ type stateDelta struct {
 	// new Txids for the txtail
 	txids map[transactions.Txid]struct{}
 
+	// new leases for the txtail, with the last round they are held
+	txleases map[transactions.Txlease]basics.Round
+
 	// new block header; read-only
 	hdr *bookkeeping.BlockHeader
 }
@@ -61,9 +65,10 @@ func makeRoundCowState(b roundCowParent, hdr bookkeeping.BlockHeader) *roundCowS
 		commitParent: nil,
 		proto:        config.Consensus[hdr.CurrentProtocol],
 		mods: stateDelta{
-			accts: make(map[basics.Address]accountDelta),
-			txids: make(map[transactions.Txid]struct{}),
-			hdr:   &hdr,
+			accts:    make(map[basics.Address]accountDelta),
+			txids:    make(map[transactions.Txid]struct{}),
+			txleases: make(map[transactions.Txlease]basics.Round),
+			hdr:      &hdr,
 		},
 	}
 }
@@ -90,6 +95,18 @@ func (cb *roundCowState) isDup(firstValid basics.Round, txid transactions.Txid)
 	return cb.lookupParent.isDup(firstValid, txid)
 }
 
+// isLeased returns whether a committed transaction holds the lease in the
+// round of this state.  The leases taken in this round are held through it,
+// since their transactions are alive.
+func (cb *roundCowState) isLeased(txl transactions.Txlease) (bool, error) {
+	_, present := cb.mods.txleases[txl]
+	if present {
+		return true, nil
+	}
+
+	return cb.lookupParent.isLeased(txl)
+}
+
 func (cb *roundCowState) put(addr basics.Address, old basics.AccountData, new basics.AccountData) {
 	prev, present := cb.mods.accts[addr]
 	if present {
@@ -99,8 +116,11 @@ func (cb *roundCowState) put(addr basics.Address, old basics.AccountData, new ba
 	}
 }
 
-func (cb *roundCowState) addTx(txid transactions.Txid) {
-	cb.mods.txids[txid] = struct{}{}
+func (cb *roundCowState) addTx(txn transactions.Transaction) {
+	cb.mods.txids[txn.ID()] = struct{}{}
+	if txl, leased := txn.Txlease(); leased {
+		cb.mods.txleases[txl] = txn.LastValid
+	}
 }
 
 func (cb *roundCowState) child() *roundCowState {
@@ -109,9 +129,10 @@ func (cb *roundCowState) child() *roundCowState {
 		commitParent: cb,
 		proto:        cb.proto,
 		mods: stateDelta{
-			accts: make(map[basics.Address]accountDelta),
-			txids: make(map[transactions.Txid]struct{}),
-			hdr:   cb.mods.hdr,
+			accts:    make(map[basics.Address]accountDelta),
+			txids:    make(map[transactions.Txid]struct{}),
+			txleases: make(map[transactions.Txlease]basics.Round),
+			hdr:      cb.mods.hdr,
 		},
 	}
 }
@@ -132,6 +153,10 @@ func (cb *roundCowState) commitToParent() {
 	for txid := range cb.mods.txids {
 		cb.commitParent.mods.txids[txid] = struct{}{}
 	}
+
+	for txl, lastValid := range cb.mods.txleases {
+		cb.commitParent.mods.txleases[txl] = lastValid
+	}
 }
 
 func (cb *roundCowState) modifiedAccounts() []basics.Address {
@@ -34,6 +34,18 @@ func (tile TransactionInLedgerError) Error() string {
 	return fmt.Sprintf("transaction already in ledger: %v", tile.Txid)
 }
 
+// LeaseInLedgerError is returned when a transaction cannot be added because a committed transaction holds its lease
+type LeaseInLedgerError struct {
+	Txid   transactions.Txid
+	Sender basics.Address
+	Lease  [32]byte
+}
+
+// Error satisfies builtin interface `error`
+func (lile LeaseInLedgerError) Error() string {
+	return fmt.Sprintf("transaction %v: lease %x of %v is held by a committed transaction", lile.Txid, lile.Lease, lile.Sender)
+}
+
 // BlockInLedgerError is returned when a block cannot be added because it has already been done
 type BlockInLedgerError struct {
 	LastRound basics.Round
@@ -27,9 +27,11 @@ import (
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/committee"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/verify"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/util/execpool"
+	"github.com/algorand/go-algorand/util/tracing"
 )
 
 // ErrNoSpace indicates insufficient space for transaction in block
@@ -62,6 +64,10 @@ func (x *roundCowBase) isDup(firstValid basics.Round, txid transactions.Txid) (b
 	return x.l.isDup(firstValid, x.rnd, txid)
 }
 
+func (x *roundCowBase) isLeased(txl transactions.Txlease) (bool, error) {
+	return x.l.isLeased(x.rnd, txl)
+}
+
 // wrappers for roundCowState to satisfy the (current) transactions.Balances interface
 func (cs *roundCowState) Get(addr basics.Address) (basics.BalanceRecord, error) {
 	acctdata, err := cs.lookup(addr)
@@ -159,6 +165,7 @@ type ledgerForEvaluator interface {
 	Lookup(basics.Round, basics.Address) (basics.AccountData, error)
 	Totals(basics.Round) (AccountTotals, error)
 	isDup(basics.Round, basics.Round, transactions.Txid) (bool, error)
+	isLeased(basics.Round, transactions.Txlease) (bool, error)
 	lookupWithoutRewards(basics.Round, basics.Address) (basics.AccountData, error)
 }
 
@@ -278,9 +285,71 @@ func (eval *BlockEvaluator) Round() basics.Round {
 // If the transaction cannot be added to the block without violating some constraints,
 // an error is returned and the block evaluator state is unchanged.
 func (eval *BlockEvaluator) Transaction(txn transactions.SignedTxn, ad *transactions.ApplyData) error {
-	var err error
-	var thisTxBytes int
+	var ads []transactions.ApplyData
+	if ad != nil {
+		ads = []transactions.ApplyData{*ad}
+	}
+	return eval.TransactionGroup([]transactions.SignedTxn{txn}, ads)
+}
+
+// TransactionGroup tentatively adds a new transaction group as part of this block evaluation.
+// The transactions of the group are added together, in order, or not at all: if any of them
+// cannot be added to the block without violating some constraints, an error is returned and
+// the block evaluator state is unchanged.  ads holds the ApplyData of each transaction when
+// validating an existing block, and is nil otherwise.
+func (eval *BlockEvaluator) TransactionGroup(txgroup []transactions.SignedTxn, ads []transactions.ApplyData) error {
+	if ads != nil && len(ads) != len(txgroup) {
+		return fmt.Errorf("transaction group of %d transactions has %d applyData", len(txgroup), len(ads))
+	}
+
+	if eval.validate {
+		// A whole group, matching its group ID?
+		err := transactions.WellFormedGroup(txgroup, eval.proto)
+		if err != nil {
+			return fmt.Errorf("transaction %v: malformed group: %v", txgroup[0].ID(), err)
+		}
+	}
+
 	cow := eval.state.child()
+	txibs := make([]transactions.SignedTxnInBlock, 0, len(txgroup))
+	groupTxBytes := 0
+	for i := range txgroup {
+		var ad *transactions.ApplyData
+		if ads != nil {
+			ad = &ads[i]
+		}
+
+		txib, thisTxBytes, err := eval.transaction(cow, txgroup, i, ad, eval.txnCounter()+uint64(i))
+		if err != nil {
+			return err
+		}
+
+		groupTxBytes += thisTxBytes
+		if eval.validate && eval.totalTxBytes+groupTxBytes > eval.proto.MaxTxnBytesPerBlock {
+			return ErrNoSpace
+		}
+		txibs = append(txibs, txib)
+	}
+
+	eval.block.Payset = append(eval.block.Payset, txibs...)
+	eval.totalTxBytes += groupTxBytes
+	cow.commitToParent()
+	return nil
+}
+
+// txnCounter returns the number of transactions committed to the ledger
+// before the next transaction added to this block.
+func (eval *BlockEvaluator) txnCounter() uint64 {
+	return eval.prevHeader.TxnCounter + uint64(len(eval.block.Payset))
+}
+
+// transaction applies the transaction at groupIndex of a group on top of the
+// group's state, and returns the transaction as it goes in the block along
+// with its encoded length, which is only computed when validating.  ctr is
+// the number of transactions committed to the ledger before this one.
+func (eval *BlockEvaluator) transaction(groupCow *roundCowState, txgroup []transactions.SignedTxn, groupIndex int, ad *transactions.ApplyData, ctr uint64) (txib transactions.SignedTxnInBlock, thisTxBytes int, err error) {
+	cow := groupCow.child()
+	txn := txgroup[groupIndex]
 
 	spec := transactions.SpecialAddresses{
 		FeeSink:     eval.block.BlockHeader.FeeSink,
@@ -291,81 +360,116 @@ func (eval *BlockEvaluator) Transaction(txn transactions.SignedTxn, ad *transact
 		// Transaction valid (not expired)?
 		err = txn.Txn.Alive(eval.block)
 		if err != nil {
-			return err
+			return
 		}
 
 		// Transaction already in the ledger?
-		dup, err := cow.isDup(txn.Txn.First(), txn.ID())
+		var dup bool
+		dup, err = cow.isDup(txn.Txn.First(), txn.ID())
 		if err != nil {
-			return err
+			return
 		}
 		if dup {
-			return TransactionInLedgerError{txn.ID()}
+			err = TransactionInLedgerError{txn.ID()}
+			return
+		}
+
+		// Lease held by a committed transaction?
+		if txl, leased := txn.Txn.Txlease(); leased {
+			var held bool
+			held, err = cow.isLeased(txl)
+			if err != nil {
+				return
+			}
+			if held {
+				err = LeaseInLedgerError{Txid: txn.ID(), Sender: txl.Sender, Lease: txl.Lease}
+				return
+			}
 		}
 
 		// Well-formed on its own?
 		err = txn.Txn.WellFormed(spec, eval.proto)
 		if err != nil {
-			return fmt.Errorf("transaction %v: malformed: %v", txn.ID(), err)
+			err = fmt.Errorf("transaction %v: malformed: %v", txn.ID(), err)
+			return
 		}
 
 		// Properly signed?
 		if eval.txcache == nil || !eval.txcache.Verified(txn) {
-			err = txn.PoolVerify(spec, eval.proto, eval.verificationPool)
+			ctx := verify.Context{Spec: spec, Proto: eval.proto, Group: txgroup, GroupIndex: groupIndex}
+			err = verify.TxnPool(&txn, ctx, eval.verificationPool)
 			if err != nil {
-				return fmt.Errorf("transaction %v: failed to verify: %v", txn.ID(), err)
+				err = fmt.Errorf("transaction %v: failed to verify: %v", txn.ID(), err)
+				return
 			}
 		}
+
+		// Signed by the key that is currently authorized to spend from the sender?
+		var record basics.BalanceRecord
+		record, err = cow.Get(txn.Txn.Sender)
+		if err != nil {
+			return
+		}
+		authorizer := record.AuthAddr
+		if authorizer == (basics.Address{}) {
+			authorizer = txn.Txn.Sender
+		}
+		if txn.Authorizer() != authorizer {
+			err = fmt.Errorf("transaction %v: should have been authorized by %v but was actually authorized by %v", txn.ID(), authorizer, txn.Authorizer())
+			return
+		}
 	}
 
 	// Apply the transaction, updating the cow balances
-	applyData, err := txn.Txn.Apply(cow, spec)
+	applyData, err := txn.Txn.Apply(cow, spec, ctr)
 	if err != nil {
-		return fmt.Errorf("transaction %v: %v", txn.ID(), err)
+		err = fmt.Errorf("transaction %v: %v", txn.ID(), err)
+		return
 	}
 
 	// Validate applyData if we are validating an existing block.
 	// If we are validating and generating, we have no ApplyData yet.
 	if eval.validate && !eval.generate {
 		if ad == nil {
-			return fmt.Errorf("transaction %v: no applyData for validation", txn.ID())
+			err = fmt.Errorf("transaction %v: no applyData for validation", txn.ID())
+			return
 		}
 		if eval.proto.ApplyData {
 			if *ad != applyData {
-				return fmt.Errorf("transaction %v: applyData mismatch: %v != %v", txn.ID(), *ad, applyData)
+				err = fmt.Errorf("transaction %v: applyData mismatch: %v != %v", txn.ID(), *ad, applyData)
+				return
 			}
 		} else {
 			if *ad != (transactions.ApplyData{}) {
-				return fmt.Errorf("transaction %v: applyData not supported", txn.ID())
+				err = fmt.Errorf("transaction %v: applyData not supported", txn.ID())
+				return
 			}
 		}
 	}
 
-	// Check if the transaction fits in the block, now that we can encode it.
-	txib, err := eval.block.EncodeSignedTxn(txn, applyData)
+	// Encode the transaction, so that the group can check it fits in the block.
+	txib, err = eval.block.EncodeSignedTxn(txn, applyData)
 	if err != nil {
-		return err
+		return
 	}
 	if eval.validate {
 		thisTxBytes = len(protocol.Encode(txib))
-		if eval.totalTxBytes+thisTxBytes > eval.proto.MaxTxnBytesPerBlock {
-			return ErrNoSpace
-		}
 	}
 
 	// Check if any affected accounts dipped below MinBalance (unless they are
 	// completely zero, which means the account will be deleted.)
 	rewardlvl := cow.rewardsLevel()
 	for _, addr := range cow.modifiedAccounts() {
-		data, err := cow.lookup(addr)
+		var data basics.AccountData
+		data, err = cow.lookup(addr)
 		if err != nil {
-			return err
+			return
 		}
 
 		// It's always OK to have the account move to an empty state,
 		// because the accounts DB can delete it.  Otherwise, we will
 		// enforce MinBalance.
-		if data == (basics.AccountData{}) {
+		if data.IsZero() {
 			continue
 		}
 
@@ -378,19 +482,19 @@ func (eval *BlockEvaluator) Transaction(txn transactions.SignedTxn, ad *transact
 		}
 
 		dataNew := data.WithUpdatedRewards(eval.proto, rewardlvl)
-		if dataNew.MicroAlgos.Raw < eval.proto.MinBalance {
-			return fmt.Errorf("transaction %v: account %v balance %d below min %d",
-				txn.ID(), addr, dataNew.MicroAlgos.Raw, eval.proto.MinBalance)
+		minBalance := dataNew.MinBalance(eval.proto)
+		if dataNew.MicroAlgos.Raw < minBalance.Raw {
+			err = fmt.Errorf("transaction %v: account %v balance %d below min %d (%d assets)",
+				txn.ID(), addr, dataNew.MicroAlgos.Raw, minBalance.Raw, len(dataNew.Assets))
+			return
 		}
 	}
 
 	// Remember this TXID (to detect duplicates)
-	cow.addTx(txn.ID())
+	cow.addTx(txn.Txn)
 
-	eval.block.Payset = append(eval.block.Payset, txib)
-	eval.totalTxBytes += thisTxBytes
 	cow.commitToParent()
-	return nil
+	return
 }
 
 // Call "endOfBlock" after all the block's rewards and transactions are processed. Applies any deferred balance updates.
@@ -399,6 +503,11 @@ func (eval *BlockEvaluator) endOfBlock() error {
 
 	if eval.generate {
 		eval.block.TxnRoot = eval.block.Payset.Commit(eval.proto.PaysetCommitFlat)
+		if eval.proto.SupportTxnCounter {
+			eval.block.TxnCounter = eval.txnCounter()
+		} else {
+			eval.block.TxnCounter = 0
+		}
 	}
 
 	cow.commitToParent()
@@ -413,6 +522,14 @@ func (eval *BlockEvaluator) finalValidation() error {
 		if txnRoot != eval.block.TxnRoot {
 			return fmt.Errorf("txn root wrong: %v != %v", txnRoot, eval.block.TxnRoot)
 		}
+
+		var expectedTxnCount uint64
+		if eval.proto.SupportTxnCounter {
+			expectedTxnCount = eval.txnCounter()
+		}
+		if eval.block.TxnCounter != expectedTxnCount {
+			return fmt.Errorf("txn count wrong: %d != %d", eval.block.TxnCounter, expectedTxnCount)
+		}
 	}
 
 	return nil
@@ -457,17 +574,32 @@ func (l *Ledger) eval(ctx context.Context, blk bookkeeping.Block, aux *evalAux,
 		return stateDelta{}, evalAux{}, err
 	}
 
-	for _, txn := range payset {
+	for len(payset) > 0 {
 		select {
 		case <-ctx.Done():
 			return stateDelta{}, evalAux{}, ctx.Err()
 		default:
 		}
 
-		err = eval.Transaction(txn.SignedTxn, &txn.ApplyData)
+		// The transactions of a group are next to each other in the block
+		groupLen := 1
+		if group := payset[0].Txn.Group; group != (crypto.Digest{}) {
+			for groupLen < len(payset) && payset[groupLen].Txn.Group == group {
+				groupLen++
+			}
+		}
+
+		txgroup := make([]transactions.SignedTxn, groupLen)
+		ads := make([]transactions.ApplyData, groupLen)
+		for i, txn := range payset[:groupLen] {
+			txgroup[i] = txn.SignedTxn
+			ads[i] = txn.ApplyData
+		}
+		err = eval.TransactionGroup(txgroup, ads)
 		if err != nil {
 			return stateDelta{}, evalAux{}, err
 		}
+		payset = payset[groupLen:]
 	}
 
 	// Finally, procees any pending end-of-block state changes
@@ -492,7 +624,13 @@ func (l *Ledger) eval(ctx context.Context, blk bookkeeping.Block, aux *evalAux,
 // not a valid block (e.g., it has duplicate transactions, overspends some
 // account, etc).
 func (l *Ledger) Validate(ctx context.Context, blk bookkeeping.Block, txcache VerifiedTxnCache, executionPool execpool.BacklogPool) (*ValidatedBlock, error) {
+	ctx, span := tracing.StartSpan(ctx, "ledger.Validate", tracing.SpanKindInternal,
+		tracing.Attr(logging.RoundField, uint64(blk.Round())),
+		tracing.Attr("txns", len(blk.Payset)))
+	defer span.End()
+
 	delta, aux, err := l.eval(ctx, blk, nil, true, txcache, executionPool)
+	span.SetError(err)
 	if err != nil {
 		return nil, err
 	}
@@ -17,6 +17,7 @@
 package ledger
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -126,3 +127,303 @@ func TestBlockEvaluator(t *testing.T) {
 	require.Equal(t, bal1new.MicroAlgos.Raw, bal1.MicroAlgos.Raw+100)
 	require.Equal(t, bal2new.MicroAlgos.Raw, bal2.MicroAlgos.Raw-minFee.Raw)
 }
+
+func TestRekeying(t *testing.T) {
+	blks, accts, addrs, keys := genesis(10)
+	blks[0].CurrentProtocol = protocol.ConsensusFuture
+
+	backlogPool := execpool.MakeBacklog(nil, 0, execpool.LowPriority, nil)
+	defer backlogPool.Shutdown()
+
+	dbName := fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64())
+	l, err := OpenLedger(logging.Base(), dbName, true, blks, accts, blks[0].BlockHeader.GenesisHash)
+	require.NoError(t, err)
+	defer l.Close()
+
+	newBlock := bookkeeping.MakeBlock(blks[len(blks)-1].BlockHeader)
+	eval, err := l.StartEvaluator(newBlock.BlockHeader, nil, backlogPool)
+	require.NoError(t, err)
+
+	makeTxn := func(note byte, rekeyTo basics.Address) transactions.Transaction {
+		return transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      addrs[0],
+				Fee:         minFee,
+				FirstValid:  newBlock.Round(),
+				LastValid:   newBlock.Round(),
+				GenesisHash: blks[0].BlockHeader.GenesisHash,
+				Note:        []byte{note},
+				RekeyTo:     rekeyTo,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addrs[0],
+			},
+		}
+	}
+
+	// rekey addrs[0] to the key of addrs[1]
+	err = eval.Transaction(makeTxn(0, addrs[1]).Sign(keys[0]), &transactions.ApplyData{})
+	require.NoError(t, err)
+
+	// the old key can no longer spend
+	err = eval.Transaction(makeTxn(1, basics.Address{}).Sign(keys[0]), &transactions.ApplyData{})
+	require.Error(t, err)
+
+	// the new key can, once it names itself as the authorizer
+	st := makeTxn(2, basics.Address{}).Sign(keys[1])
+	err = eval.Transaction(st, &transactions.ApplyData{})
+	require.Error(t, err)
+	st.AuthAddr = addrs[1]
+	err = eval.Transaction(st, &transactions.ApplyData{})
+	require.NoError(t, err)
+
+	validatedBlock, err := eval.GenerateBlock()
+	require.NoError(t, err)
+	l.AddValidatedBlock(*validatedBlock, agreement.Certificate{})
+
+	data, err := l.Lookup(newBlock.Round(), addrs[0])
+	require.NoError(t, err)
+	require.Equal(t, addrs[1], data.AuthAddr)
+}
+
+func TestTransactionGroup(t *testing.T) {
+	blks, accts, addrs, keys := genesis(10)
+	blks[0].CurrentProtocol = protocol.ConsensusFuture
+
+	backlogPool := execpool.MakeBacklog(nil, 0, execpool.LowPriority, nil)
+	defer backlogPool.Shutdown()
+
+	dbName := fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64())
+	l, err := OpenLedger(logging.Base(), dbName, true, blks, accts, blks[0].BlockHeader.GenesisHash)
+	require.NoError(t, err)
+	defer l.Close()
+
+	newBlock := bookkeeping.MakeBlock(blks[len(blks)-1].BlockHeader)
+	eval, err := l.StartEvaluator(newBlock.BlockHeader, nil, backlogPool)
+	require.NoError(t, err)
+
+	bal0, err := l.Lookup(newBlock.Round()-1, addrs[0])
+	require.NoError(t, err)
+
+	makeTxn := func(sender int, amount uint64) transactions.Transaction {
+		return transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      addrs[sender],
+				Fee:         minFee,
+				FirstValid:  newBlock.Round(),
+				LastValid:   newBlock.Round(),
+				GenesisHash: blks[0].BlockHeader.GenesisHash,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addrs[2],
+				Amount:   basics.MicroAlgos{Raw: amount},
+			},
+		}
+	}
+	sign := func(txn transactions.Transaction) transactions.SignedTxn {
+		if txn.Sender == addrs[1] {
+			return txn.Sign(keys[1])
+		}
+		return txn.Sign(keys[0])
+	}
+	makeGroup := func(txns ...transactions.Transaction) []transactions.SignedTxn {
+		gid := transactions.ComputeGroupID(txns)
+		txgroup := make([]transactions.SignedTxn, len(txns))
+		for i, txn := range txns {
+			txn.Group = gid
+			txgroup[i] = sign(txn)
+		}
+		return txgroup
+	}
+
+	// the second transaction overspends, so neither is committed
+	txgroup := makeGroup(makeTxn(0, 1000), makeTxn(1, bal0.MicroAlgos.Raw*10))
+	err = eval.TransactionGroup(txgroup, nil)
+	require.Error(t, err)
+
+	// a transaction of a group can't go in on its own
+	err = eval.Transaction(txgroup[0], nil)
+	require.Error(t, err)
+
+	// nor can a group whose transactions were changed
+	txgroup = makeGroup(makeTxn(0, 1000), makeTxn(1, 2000))
+	txgroup[1] = sign(makeTxn(1, 3000))
+	err = eval.TransactionGroup(txgroup, nil)
+	require.Error(t, err)
+
+	txgroup = makeGroup(makeTxn(0, 1000), makeTxn(1, 2000))
+	err = eval.TransactionGroup(txgroup, nil)
+	require.NoError(t, err)
+
+	validatedBlock, err := eval.GenerateBlock()
+	require.NoError(t, err)
+	require.Len(t, validatedBlock.Block().Payset, 2)
+
+	// the block is valid, with the group in it
+	_, err = l.Validate(context.Background(), validatedBlock.Block(), nil, backlogPool)
+	require.NoError(t, err)
+
+	l.AddValidatedBlock(*validatedBlock, agreement.Certificate{})
+	bal2, err := l.Lookup(newBlock.Round()-1, addrs[2])
+	require.NoError(t, err)
+	bal2new, err := l.Lookup(newBlock.Round(), addrs[2])
+	require.NoError(t, err)
+	require.Equal(t, bal2.MicroAlgos.Raw+3000, bal2new.MicroAlgos.Raw)
+}
+
+func TestTransactionLease(t *testing.T) {
+	blks, accts, addrs, keys := genesis(10)
+	blks[0].CurrentProtocol = protocol.ConsensusFuture
+
+	backlogPool := execpool.MakeBacklog(nil, 0, execpool.LowPriority, nil)
+	defer backlogPool.Shutdown()
+
+	dbName := fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64())
+	l, err := OpenLedger(logging.Base(), dbName, true, blks, accts, blks[0].BlockHeader.GenesisHash)
+	require.NoError(t, err)
+	defer l.Close()
+
+	var lease [32]byte
+	crypto.RandBytes(lease[:])
+	first := l.Latest() + 1
+	lastValid := first + 2
+	makeTxn := func(sender int, note byte, lastValid basics.Round) transactions.SignedTxn {
+		txn := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      addrs[sender],
+				Fee:         minFee,
+				FirstValid:  first,
+				LastValid:   lastValid,
+				GenesisHash: blks[0].BlockHeader.GenesisHash,
+				Note:        []byte{note},
+				Lease:       lease,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addrs[2],
+			},
+		}
+		return txn.Sign(keys[sender])
+	}
+
+	// the lease is held through the LastValid round of the first transaction
+	stxn := makeTxn(0, 0, lastValid)
+
+	for rnd := first; rnd <= lastValid+1; rnd++ {
+		newBlock := bookkeeping.MakeBlock(blks[len(blks)-1].BlockHeader)
+		if rnd > first {
+			hdr, err := l.BlockHdr(rnd - 1)
+			require.NoError(t, err)
+			newBlock = bookkeeping.MakeBlock(hdr)
+		}
+		eval, err := l.StartEvaluator(newBlock.BlockHeader, nil, backlogPool)
+		require.NoError(t, err)
+
+		retry := makeTxn(0, byte(rnd), lastValid+10)
+		switch {
+		case rnd == first:
+			require.NoError(t, eval.Transaction(stxn, &transactions.ApplyData{}))
+			// another transaction can't take the lease in the same block
+			require.Error(t, eval.Transaction(retry, &transactions.ApplyData{}))
+			// leases are per sender
+			require.NoError(t, eval.Transaction(makeTxn(1, 0, lastValid), &transactions.ApplyData{}))
+		case rnd <= lastValid:
+			leased, err := l.Leased(retry)
+			require.NoError(t, err)
+			require.True(t, leased)
+			err = eval.Transaction(retry, &transactions.ApplyData{})
+			require.IsType(t, LeaseInLedgerError{}, err)
+		default:
+			leased, err := l.Leased(retry)
+			require.NoError(t, err)
+			require.False(t, leased)
+			require.NoError(t, eval.Transaction(retry, &transactions.ApplyData{}))
+		}
+
+		validatedBlock, err := eval.GenerateBlock()
+		require.NoError(t, err)
+		require.NoError(t, l.AddValidatedBlock(*validatedBlock, agreement.Certificate{}))
+	}
+}
+
+func TestAssetMinBalance(t *testing.T) {
+	blks, accts, addrs, keys := genesis(10)
+	blks[0].CurrentProtocol = protocol.ConsensusFuture
+	proto := config.Consensus[protocol.ConsensusFuture]
+
+	backlogPool := execpool.MakeBacklog(nil, 0, execpool.LowPriority, nil)
+	defer backlogPool.Shutdown()
+
+	dbName := fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64())
+	l, err := OpenLedger(logging.Base(), dbName, true, blks, accts, blks[0].BlockHeader.GenesisHash)
+	require.NoError(t, err)
+	defer l.Close()
+
+	newBlock := bookkeeping.MakeBlock(blks[len(blks)-1].BlockHeader)
+	eval, err := l.StartEvaluator(newBlock.BlockHeader, nil, backlogPool)
+	require.NoError(t, err)
+
+	makeTxn := func(sender basics.Address, txType protocol.TxType) transactions.Transaction {
+		return transactions.Transaction{
+			Type: txType,
+			Header: transactions.Header{
+				Sender:      sender,
+				Fee:         minFee,
+				FirstValid:  newBlock.Round(),
+				LastValid:   newBlock.Round(),
+				GenesisHash: blks[0].BlockHeader.GenesisHash,
+			},
+		}
+	}
+
+	// the first transaction of the ledger creates asset 1
+	create := makeTxn(addrs[0], protocol.AssetConfigTx)
+	create.AssetParams = basics.AssetParams{Total: 100, UnitName: "tok", Manager: addrs[0]}
+	require.NoError(t, eval.Transaction(create.Sign(keys[0]), &transactions.ApplyData{}))
+	id := basics.AssetID{Creator: addrs[0], Index: 1}
+
+	// fund a new account with enough for the plain MinBalance and a few fees
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	holderKey := crypto.GenerateSignatureSecrets(seed)
+	holder := basics.Address(holderKey.SignatureVerifier)
+	fund := func(note byte, amount uint64) {
+		pay := makeTxn(addrs[1], protocol.PaymentTx)
+		pay.Note = []byte{note}
+		pay.Receiver = holder
+		pay.Amount = basics.MicroAlgos{Raw: amount}
+		require.NoError(t, eval.Transaction(pay.Sign(keys[1]), &transactions.ApplyData{}))
+	}
+	fund(0, proto.MinBalance+10*minFee.Raw)
+
+	// holding the asset raises the minimum balance of the account beyond what it has
+	accept := makeTxn(holder, protocol.AssetTransferTx)
+	accept.XferAsset = id
+	accept.AssetReceiver = holder
+	err = eval.Transaction(accept.Sign(holderKey), &transactions.ApplyData{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "below min")
+
+	fund(1, proto.MinBalancePerAsset)
+	require.NoError(t, eval.Transaction(accept.Sign(holderKey), &transactions.ApplyData{}))
+
+	// nor can the holder then spend below the raised minimum
+	spend := makeTxn(holder, protocol.PaymentTx)
+	spend.Receiver = addrs[1]
+	spend.Amount = basics.MicroAlgos{Raw: 10 * minFee.Raw}
+	require.Error(t, eval.Transaction(spend.Sign(holderKey), &transactions.ApplyData{}))
+	spend.Amount = basics.MicroAlgos{Raw: 8 * minFee.Raw}
+	require.NoError(t, eval.Transaction(spend.Sign(holderKey), &transactions.ApplyData{}))
+
+	validatedBlock, err := eval.GenerateBlock()
+	require.NoError(t, err)
+	require.NoError(t, l.AddValidatedBlock(*validatedBlock, agreement.Certificate{}))
+
+	data, err := l.Lookup(newBlock.Round(), holder)
+	require.NoError(t, err)
+	require.Contains(t, data.Assets, id)
+	require.True(t, data.MicroAlgos.Raw >= data.MinBalance(proto).Raw)
+	require.Equal(t, proto.MinBalance+proto.MinBalancePerAsset, data.MinBalance(proto).Raw)
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/util/db"
 )
 
 // Ledger is a database storing the contents of the ledger.
@@ -86,9 +87,6 @@ func OpenLedger(log logging.Logger, dbPathPrefix string, dbMem bool, initBlocks
 	defer func() {
 		if err != nil {
 			l.Close()
-			if l.blockQ != nil {
-				l.blockQ.close()
-			}
 		}
 	}()
 
@@ -186,9 +184,47 @@ func OpenLedger(log logging.Logger, dbPathPrefix string, dbMem bool, initBlocks
 	return l, nil
 }
 
+// DBFilenames returns the tracker and block database files of the ledger stored under dbPathPrefix, which are the same
+// file for the ledgers that use the legacy single database.
+func DBFilenames(dbPathPrefix string) (trackerDBFilename string, blockDBFilename string) {
+	commonDBFilename := dbPathPrefix + ".sqlite"
+	if _, err := os.Stat(commonDBFilename); err == nil {
+		return commonDBFilename, commonDBFilename
+	}
+	return dbPathPrefix + ".tracker.sqlite", dbPathPrefix + ".block.sqlite"
+}
+
+// LatestBlockHeader reads the header of the latest block of the ledger stored under dbPathPrefix, without opening the
+// ledger. The error satisfies os.IsNotExist when there is no ledger there yet.
+func LatestBlockHeader(dbPathPrefix string) (hdr bookkeeping.BlockHeader, err error) {
+	_, blockDBFilename := DBFilenames(dbPathPrefix)
+	if _, err = os.Stat(blockDBFilename); err != nil {
+		return
+	}
+	accessor, err := db.MakeAccessor(blockDBFilename, true, false)
+	if err != nil {
+		return
+	}
+	defer accessor.Close()
+
+	err = accessor.Atomic(func(tx *sql.Tx) error {
+		latest, err0 := blockLatest(tx)
+		if err0 != nil {
+			return err0
+		}
+		hdr, err0 = blockGetHdr(tx, latest)
+		return err0
+	})
+	return
+}
+
 // Close reclaims resources used by the ledger (namely, the database connection
-// and goroutines used by trackers).
+// and goroutines used by trackers). The blocks added to the ledger but not yet
+// written to disk are flushed first.
 func (l *Ledger) Close() {
+	if l.blockQ != nil {
+		l.blockQ.close()
+	}
 	l.trackerDBs.close()
 	l.blockDBs.close()
 	l.trackers.close()
@@ -274,6 +310,12 @@ func (l *Ledger) isDup(firstValid basics.Round, lastValid basics.Round, txid tra
 	return l.txTail.isDup(firstValid, lastValid, txid)
 }
 
+func (l *Ledger) isLeased(current basics.Round, txl transactions.Txlease) (bool, error) {
+	l.trackerMu.RLock()
+	defer l.trackerMu.RUnlock()
+	return l.txTail.isLeased(current, txl)
+}
+
 // Latest returns the latest known block round added to the ledger.
 func (l *Ledger) Latest() basics.Round {
 	return l.blockQ.latest()
@@ -294,6 +336,19 @@ func (l *Ledger) Committed(txn transactions.SignedTxn) (bool, error) {
 	return l.txTail.isDup(txn.Txn.First(), l.Latest(), txn.ID())
 }
 
+// Leased uses the transaction tail tracker to check if the lease of txn is
+// held by a transaction that appeared in a block, so that txn can't be
+// committed in the next round.  A transaction without a lease is never leased.
+func (l *Ledger) Leased(txn transactions.SignedTxn) (bool, error) {
+	txl, leased := txn.Txn.Txlease()
+	if !leased {
+		return false, nil
+	}
+	l.trackerMu.RLock()
+	defer l.trackerMu.RUnlock()
+	return l.txTail.isLeased(l.Latest(), txl)
+}
+
 func (l *Ledger) blockAux(rnd basics.Round) (bookkeeping.Block, evalAux, error) {
 	return l.blockQ.getBlockAux(rnd)
 }
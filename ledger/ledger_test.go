@@ -18,6 +18,9 @@ package ledger
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -665,3 +668,44 @@ func TestLedgerSingleTxApplyData(t *testing.T) {
 
 	a.Error(l.appendUnvalidatedTx(t, initAccounts, initSecrets, correctKeyreg, ad), "added duplicate tx")
 }
+
+func TestLedgerCloseFlushesBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dbPrefix := filepath.Join(dir, t.Name())
+
+	blk := bookkeeping.Block{}
+	blk.CurrentProtocol = protocol.ConsensusCurrentVersion
+	blk.RewardsPool = testPoolAddr
+	blk.FeeSink = testSinkAddr
+
+	accts := make(map[basics.Address]basics.AccountData)
+	accts[testPoolAddr] = basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: 1234567890})
+	accts[testSinkAddr] = basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: 1234567890})
+
+	l, err := OpenLedger(logging.Base(), dbPrefix, false, []bookkeeping.Block{blk}, accts, crypto.Digest{})
+	require.NoError(t, err)
+
+	// the blocks are added without waiting for them to be written, so that Close has to flush them
+	const numBlocks = 50
+	added := make([]bookkeeping.Block, 0, numBlocks)
+	for i := 0; i < numBlocks; i++ {
+		blk.BlockHeader.Round++
+		blk.BlockHeader.TimeStamp += 1000
+		require.NoError(t, l.AddBlock(blk, agreement.Certificate{}))
+		added = append(added, blk)
+	}
+	l.Close()
+
+	l, err = OpenLedger(logging.Base(), dbPrefix, false, nil, accts, crypto.Digest{})
+	require.NoError(t, err)
+	defer l.Close()
+
+	require.Equal(t, blk.Round(), l.Latest())
+	for _, expected := range added {
+		stored, err := l.Block(expected.Round())
+		require.NoError(t, err)
+		require.Equal(t, expected.Hash(), stored.Hash())
+	}
+}
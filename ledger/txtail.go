@@ -26,8 +26,9 @@ import (
 )
 
 type roundTxMembers struct {
-	txids map[transactions.Txid]struct{}
-	proto config.ConsensusParams
+	txids    map[transactions.Txid]struct{}
+	txleases map[transactions.Txlease]basics.Round // the last round each lease is held
+	proto    config.ConsensusParams
 }
 
 type txTail struct {
@@ -61,11 +62,15 @@ func (t *txTail) loadFromDisk(l ledgerForTracker) error {
 		}
 
 		t.recent[old] = roundTxMembers{
-			txids: make(map[transactions.Txid]struct{}),
-			proto: config.Consensus[blk.CurrentProtocol],
+			txids:    make(map[transactions.Txid]struct{}),
+			txleases: make(map[transactions.Txlease]basics.Round),
+			proto:    config.Consensus[blk.CurrentProtocol],
 		}
 		for _, tx := range payset {
 			t.recent[old].txids[tx.ID()] = struct{}{}
+			if txl, leased := tx.Txn.Txlease(); leased {
+				t.recent[old].txleases[txl] = tx.Txn.LastValid
+			}
 		}
 	}
 
@@ -84,8 +89,9 @@ func (t *txTail) newBlock(blk bookkeeping.Block, delta stateDelta) {
 	}
 
 	t.recent[rnd] = roundTxMembers{
-		txids: delta.txids,
-		proto: config.Consensus[blk.CurrentProtocol],
+		txids:    delta.txids,
+		txleases: delta.txleases,
+		proto:    config.Consensus[blk.CurrentProtocol],
 	}
 }
 
@@ -115,3 +121,21 @@ func (t *txTail) isDup(firstValid basics.Round, lastValid basics.Round, txid tra
 
 	return false, nil
 }
+
+// isLeased returns whether a transaction committed up to round current holds
+// the lease after it.  The tail keeps enough rounds for this: a transaction
+// committed in round r holds its lease through its LastValid round, which is
+// at most r + MaxTxnLife.
+func (t *txTail) isLeased(current basics.Round, txl transactions.Txlease) (bool, error) {
+	for rnd, members := range t.recent {
+		if rnd > current {
+			continue
+		}
+		lastValid, present := members.txleases[txl]
+		if present && lastValid > current {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,210 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package libgoal
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// The MakeUnsignedAsset functions build asset transactions without their header, which FillUnsignedTxTemplate
+// fills in. An asset is named by the address of the account that created it and its index.
+
+// parseAddressOrEmpty parses addr, and returns the zero address for the empty string.
+func parseAddressOrEmpty(addr string) (basics.Address, error) {
+	if addr == "" {
+		return basics.Address{}, nil
+	}
+	return basics.UnmarshalChecksumAddress(addr)
+}
+
+func parseAssetID(creator string, index uint64) (basics.AssetID, error) {
+	creatorAddr, err := basics.UnmarshalChecksumAddress(creator)
+	if err != nil {
+		return basics.AssetID{}, err
+	}
+	return basics.AssetID{Creator: creatorAddr, Index: basics.AssetIndex(index)}, nil
+}
+
+// MakeUnsignedAssetCreateTx creates a transaction that creates an asset, held in full by the sender of the
+// transaction. An empty manager, reserve, freeze or clawback address disables the corresponding feature for good.
+func (c *Client) MakeUnsignedAssetCreateTx(total uint64, defaultFrozen bool, manager, reserve, freeze, clawback, unitName, assetName, url string) (transactions.Transaction, error) {
+	params := basics.AssetParams{
+		Total:         total,
+		DefaultFrozen: defaultFrozen,
+		UnitName:      unitName,
+		AssetName:     assetName,
+		URL:           url,
+	}
+
+	var err error
+	for _, field := range []struct {
+		addr string
+		dst  *basics.Address
+	}{{manager, &params.Manager}, {reserve, &params.Reserve}, {freeze, &params.Freeze}, {clawback, &params.Clawback}} {
+		*field.dst, err = parseAddressOrEmpty(field.addr)
+		if err != nil {
+			return transactions.Transaction{}, err
+		}
+	}
+
+	return transactions.Transaction{
+		Type: protocol.AssetConfigTx,
+		AssetConfigTxnFields: transactions.AssetConfigTxnFields{
+			AssetParams: params,
+		},
+	}, nil
+}
+
+// MakeUnsignedAssetConfigTx creates a transaction that changes the manager, reserve, freeze and clawback addresses of
+// an asset, which its manager must send. A nil address keeps the current one, and an empty one clears it for good.
+func (c *Client) MakeUnsignedAssetConfigTx(creator string, index uint64, newManager, newReserve, newFreeze, newClawback *string) (transactions.Transaction, error) {
+	id, err := parseAssetID(creator, index)
+	if err != nil {
+		return transactions.Transaction{}, err
+	}
+
+	// The transaction sets all the addresses at once, so start from the current ones
+	current, err := c.AssetInformation(creator, index)
+	if err != nil {
+		return transactions.Transaction{}, err
+	}
+
+	var params basics.AssetParams
+	for _, field := range []struct {
+		cur string
+		new *string
+		dst *basics.Address
+	}{
+		{current.ManagerAddr, newManager, &params.Manager},
+		{current.ReserveAddr, newReserve, &params.Reserve},
+		{current.FreezeAddr, newFreeze, &params.Freeze},
+		{current.ClawbackAddr, newClawback, &params.Clawback},
+	} {
+		addr := field.cur
+		if field.new != nil {
+			addr = *field.new
+		}
+		*field.dst, err = parseAddressOrEmpty(addr)
+		if err != nil {
+			return transactions.Transaction{}, err
+		}
+	}
+
+	// A zero AssetParams destroys the asset, so keep a non-zero field for a configuration that clears every address
+	if params == (basics.AssetParams{}) {
+		return transactions.Transaction{}, fmt.Errorf("the configuration clears every address of asset %d, which can't be changed anymore; destroy it instead", index)
+	}
+
+	return transactions.Transaction{
+		Type: protocol.AssetConfigTx,
+		AssetConfigTxnFields: transactions.AssetConfigTxnFields{
+			ConfigAsset: id,
+			AssetParams: params,
+		},
+	}, nil
+}
+
+// MakeUnsignedAssetDestroyTx creates a transaction that destroys an asset, which its manager must send. The
+// creator of the asset must hold all of its units.
+func (c *Client) MakeUnsignedAssetDestroyTx(creator string, index uint64) (transactions.Transaction, error) {
+	id, err := parseAssetID(creator, index)
+	if err != nil {
+		return transactions.Transaction{}, err
+	}
+
+	return transactions.Transaction{
+		Type: protocol.AssetConfigTx,
+		AssetConfigTxnFields: transactions.AssetConfigTxnFields{
+			ConfigAsset: id,
+		},
+	}, nil
+}
+
+// MakeUnsignedAssetSendTx creates a transaction that sends amount units of an asset to recipient. A transaction
+// sending 0 units from an account to itself makes the account accept the asset, which it must do before it receives
+// any. A non-empty closeTo sends the remaining units to closeTo and removes the asset from the sender account. A
+// non-empty senderForClawback takes the units from that account instead, which only the clawback address can do.
+func (c *Client) MakeUnsignedAssetSendTx(creator string, index uint64, amount uint64, recipient, closeTo, senderForClawback string) (transactions.Transaction, error) {
+	id, err := parseAssetID(creator, index)
+	if err != nil {
+		return transactions.Transaction{}, err
+	}
+
+	tx := transactions.Transaction{
+		Type: protocol.AssetTransferTx,
+		AssetTransferTxnFields: transactions.AssetTransferTxnFields{
+			XferAsset:   id,
+			AssetAmount: amount,
+		},
+	}
+
+	tx.AssetReceiver, err = basics.UnmarshalChecksumAddress(recipient)
+	if err != nil {
+		return transactions.Transaction{}, err
+	}
+	tx.AssetCloseTo, err = parseAddressOrEmpty(closeTo)
+	if err != nil {
+		return transactions.Transaction{}, err
+	}
+	tx.AssetSender, err = parseAddressOrEmpty(senderForClawback)
+	if err != nil {
+		return transactions.Transaction{}, err
+	}
+	return tx, nil
+}
+
+// MakeUnsignedAssetFreezeTx creates a transaction that freezes or unfreezes the holding of an asset by account,
+// which the freeze address of the asset must send.
+func (c *Client) MakeUnsignedAssetFreezeTx(creator string, index uint64, account string, freeze bool) (transactions.Transaction, error) {
+	id, err := parseAssetID(creator, index)
+	if err != nil {
+		return transactions.Transaction{}, err
+	}
+
+	accountAddr, err := basics.UnmarshalChecksumAddress(account)
+	if err != nil {
+		return transactions.Transaction{}, err
+	}
+
+	return transactions.Transaction{
+		Type: protocol.AssetFreezeTx,
+		AssetFreezeTxnFields: transactions.AssetFreezeTxnFields{
+			FreezeAccount: accountAddr,
+			FreezeAsset:   id,
+			AssetFrozen:   freeze,
+		},
+	}, nil
+}
+
+// AssetInformation returns the parameters of the asset with the given index created by creator
+func (c *Client) AssetInformation(creator string, index uint64) (models.AssetParams, error) {
+	info, err := c.AccountInformation(creator)
+	if err != nil {
+		return models.AssetParams{}, err
+	}
+
+	params, ok := info.AssetParams[index]
+	if !ok {
+		return models.AssetParams{}, fmt.Errorf("account %s has not created asset %d", creator, index)
+	}
+	return params, nil
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package libgoal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// The keystore file format. The account seed is encrypted with AES-256-GCM, under a key scrypt derives from the
+// passphrase, and the address of the account is authenticated along with it.
+const (
+	keystoreVersion = 1
+	keystoreKDF     = "scrypt"
+	keystoreCipher  = "aes-256-gcm"
+	keystoreSaltLen = 32
+	keystoreKeyLen  = 32
+	keystoreScryptR = 8
+)
+
+// keystoreScryptN is the scrypt cost of new keystore files, which takes about a second and 256MB
+var keystoreScryptN = 1 << 18
+
+// The bounds on the scrypt parameters of the keystore files being decrypted, so that a crafted file can neither weaken
+// the key derivation nor make it exhaust the memory or the CPU; the maximal cost takes 1GB.
+var (
+	keystoreMinScryptN = 1 << 14
+	keystoreMaxScryptN = 1 << 20
+	keystoreMaxScryptP = 4
+)
+
+// ErrKeystorePassphrase is returned when a keystore file can't be decrypted, most likely because of a wrong passphrase
+var ErrKeystorePassphrase = errors.New("couldn't decrypt the keystore, the passphrase is wrong or the file is corrupt")
+
+// Keystore is an account key encrypted with a passphrase, as written to the JSON keystore files of goal account
+// export --keyfile. It holds everything but the passphrase needed to decrypt the key, so a keystore file moves
+// between hosts and nodes.
+type Keystore struct {
+	Version int            `json:"version"`
+	Address string         `json:"address"`
+	Crypto  KeystoreCrypto `json:"crypto"`
+}
+
+// KeystoreCrypto describes how the key of a Keystore is encrypted
+type KeystoreCrypto struct {
+	Cipher     string         `json:"cipher"`
+	Ciphertext []byte         `json:"ciphertext"`
+	Nonce      []byte         `json:"nonce"`
+	KDF        string         `json:"kdf"`
+	KDFParams  KeystoreScrypt `json:"kdfparams"`
+}
+
+// KeystoreScrypt are the scrypt parameters deriving the encryption key of a Keystore from its passphrase
+type KeystoreScrypt struct {
+	N      int    `json:"n"`
+	R      int    `json:"r"`
+	P      int    `json:"p"`
+	KeyLen int    `json:"keylen"`
+	Salt   []byte `json:"salt"`
+}
+
+// EncryptKeystore encrypts the account seed with passphrase
+func EncryptKeystore(seed crypto.Seed, passphrase []byte) (ks Keystore, err error) {
+	addr := basics.Address(crypto.GenerateSignatureSecrets(seed).SignatureVerifier)
+	ks = Keystore{
+		Version: keystoreVersion,
+		Address: addr.GetUserAddress(),
+		Crypto: KeystoreCrypto{
+			Cipher: keystoreCipher,
+			KDF:    keystoreKDF,
+			KDFParams: KeystoreScrypt{
+				N:      keystoreScryptN,
+				R:      keystoreScryptR,
+				P:      1,
+				KeyLen: keystoreKeyLen,
+				Salt:   make([]byte, keystoreSaltLen),
+			},
+		},
+	}
+	crypto.RandBytes(ks.Crypto.KDFParams.Salt)
+
+	aead, err := ks.Crypto.aead(passphrase)
+	if err != nil {
+		return
+	}
+	ks.Crypto.Nonce = make([]byte, aead.NonceSize())
+	crypto.RandBytes(ks.Crypto.Nonce)
+	ks.Crypto.Ciphertext = aead.Seal(nil, ks.Crypto.Nonce, seed[:], addr[:])
+	return
+}
+
+// Decrypt returns the account seed of the keystore, after checking it matches the address of the keystore
+func (ks Keystore) Decrypt(passphrase []byte) (seed crypto.Seed, err error) {
+	if ks.Version != keystoreVersion {
+		return seed, fmt.Errorf("unsupported keystore version %d", ks.Version)
+	}
+	if ks.Crypto.Cipher != keystoreCipher || ks.Crypto.KDF != keystoreKDF {
+		return seed, fmt.Errorf("unsupported keystore encryption %s with %s", ks.Crypto.Cipher, ks.Crypto.KDF)
+	}
+	addr, err := basics.UnmarshalChecksumAddress(ks.Address)
+	if err != nil {
+		return
+	}
+	if err = ks.Crypto.KDFParams.check(); err != nil {
+		return
+	}
+
+	aead, err := ks.Crypto.aead(passphrase)
+	if err != nil {
+		return
+	}
+	if len(ks.Crypto.Nonce) != aead.NonceSize() {
+		return seed, fmt.Errorf("invalid keystore nonce length %d", len(ks.Crypto.Nonce))
+	}
+	plaintext, err := aead.Open(nil, ks.Crypto.Nonce, ks.Crypto.Ciphertext, addr[:])
+	if err != nil || len(plaintext) != len(seed) {
+		return seed, ErrKeystorePassphrase
+	}
+	copy(seed[:], plaintext)
+
+	if basics.Address(crypto.GenerateSignatureSecrets(seed).SignatureVerifier) != addr {
+		return crypto.Seed{}, fmt.Errorf("the keystore key doesn't match its address %s", ks.Address)
+	}
+	return seed, nil
+}
+
+// check verifies that the scrypt parameters are within the bounds keystore files are decrypted with
+func (params KeystoreScrypt) check() error {
+	if params.N < keystoreMinScryptN || params.N > keystoreMaxScryptN || params.N&(params.N-1) != 0 {
+		return fmt.Errorf("unsupported keystore scrypt cost N=%d, expected a power of 2 between %d and %d", params.N, keystoreMinScryptN, keystoreMaxScryptN)
+	}
+	if params.R != keystoreScryptR {
+		return fmt.Errorf("unsupported keystore scrypt block size r=%d, expected %d", params.R, keystoreScryptR)
+	}
+	if params.P < 1 || params.P > keystoreMaxScryptP {
+		return fmt.Errorf("unsupported keystore scrypt parallelization p=%d, expected 1 to %d", params.P, keystoreMaxScryptP)
+	}
+	if len(params.Salt) != keystoreSaltLen {
+		return fmt.Errorf("unsupported keystore salt length %d", len(params.Salt))
+	}
+	return nil
+}
+
+// aead returns the AES-GCM cipher keyed with the key the KDF parameters derive from passphrase
+func (kc KeystoreCrypto) aead(passphrase []byte) (cipher.AEAD, error) {
+	params := kc.KDFParams
+	if params.KeyLen != keystoreKeyLen {
+		return nil, fmt.Errorf("unsupported keystore key length %d", params.KeyLen)
+	}
+	key, err := scrypt.Key(passphrase, params.Salt, params.N, params.R, params.P, params.KeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// WriteKeystoreFile writes the keystore to a new file, readable by its owner only
+func WriteKeystoreFile(filename string, ks Keystore) error {
+	data, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if err != nil {
+		f.Close()
+		os.Remove(filename)
+		return err
+	}
+	return f.Close()
+}
+
+// ReadKeystoreFile reads a keystore file written by WriteKeystoreFile
+func ReadKeystoreFile(filename string) (ks Keystore, err error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &ks)
+	return
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package libgoal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	// keep the test fast
+	defer func(n, minN int) { keystoreScryptN, keystoreMinScryptN = n, minN }(keystoreScryptN, keystoreMinScryptN)
+	keystoreScryptN, keystoreMinScryptN = 1<<10, 1<<10
+
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	addr := basics.Address(crypto.GenerateSignatureSecrets(seed).SignatureVerifier)
+
+	ks, err := EncryptKeystore(seed, []byte("correct horse"))
+	require.NoError(t, err)
+	require.Equal(t, addr.GetUserAddress(), ks.Address)
+
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "key.json")
+	require.NoError(t, WriteKeystoreFile(filename, ks))
+	require.Error(t, WriteKeystoreFile(filename, ks), "an existing file must not be overwritten")
+
+	loaded, err := ReadKeystoreFile(filename)
+	require.NoError(t, err)
+	decrypted, err := loaded.Decrypt([]byte("correct horse"))
+	require.NoError(t, err)
+	require.Equal(t, seed, decrypted)
+
+	_, err = loaded.Decrypt([]byte("wrong horse"))
+	require.Equal(t, ErrKeystorePassphrase, err)
+
+	// The address is authenticated along with the key
+	var other basics.Address
+	crypto.RandBytes(other[:])
+	loaded.Address = other.GetUserAddress()
+	_, err = loaded.Decrypt([]byte("correct horse"))
+	require.Equal(t, ErrKeystorePassphrase, err)
+}
+
+func TestKeystoreScryptBounds(t *testing.T) {
+	defer func(n, minN int) { keystoreScryptN, keystoreMinScryptN = n, minN }(keystoreScryptN, keystoreMinScryptN)
+	keystoreScryptN, keystoreMinScryptN = 1<<10, 1<<10
+
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	ks, err := EncryptKeystore(seed, []byte("correct horse"))
+	require.NoError(t, err)
+
+	// crafted parameters are rejected before any key derivation
+	tests := map[string]func(params *KeystoreScrypt){
+		"weak N":        func(params *KeystoreScrypt) { params.N = 1 << 4 },
+		"huge N":        func(params *KeystoreScrypt) { params.N = 1 << 30 },
+		"N not a power": func(params *KeystoreScrypt) { params.N = 3 << 10 },
+		"small r":       func(params *KeystoreScrypt) { params.R = 1 },
+		"huge r":        func(params *KeystoreScrypt) { params.R = 1 << 20 },
+		"no p":          func(params *KeystoreScrypt) { params.P = 0 },
+		"huge p":        func(params *KeystoreScrypt) { params.P = 1 << 20 },
+		"short salt":    func(params *KeystoreScrypt) { params.Salt = params.Salt[:4] },
+		"huge key":      func(params *KeystoreScrypt) { params.KeyLen = 1 << 30 },
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			crafted := ks
+			crafted.Crypto.KDFParams.Salt = append([]byte(nil), ks.Crypto.KDFParams.Salt...)
+			modify(&crafted.Crypto.KDFParams)
+			_, err := crafted.Decrypt([]byte("correct horse"))
+			require.Error(t, err)
+			require.NotEqual(t, ErrKeystorePassphrase, err)
+		})
+	}
+
+	decrypted, err := ks.Decrypt([]byte("correct horse"))
+	require.NoError(t, err)
+	require.Equal(t, seed, decrypted)
+}
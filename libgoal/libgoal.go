@@ -17,7 +17,9 @@
 package libgoal
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -49,6 +51,11 @@ type Client struct {
 	kmdStartArgs nodecontrol.KMDStartArgs
 	dataDir      string
 	cacheDir     string
+
+	// algodURL and algodAPIToken, when set, replace the algod endpoint
+	// found in the data dir
+	algodURL      *url.URL
+	algodAPIToken string
 }
 
 // ClientConfig is data to configure a Client
@@ -64,6 +71,11 @@ type ClientConfig struct {
 
 	// BinDir may be "" and it will be guesed
 	BinDir string
+
+	// AlgodURL may be "", otherwise goal talks to the algod at this URL,
+	// authenticating with AlgodAPIToken, rather than to the one of AlgodDataDir
+	AlgodURL      string
+	AlgodAPIToken string
 }
 
 // ClientType represents the type of client you need
@@ -131,6 +143,13 @@ func (c *Client) init(config ClientConfig, clientType ClientType) error {
 	}
 	c.dataDir = dataDir
 	c.cacheDir = config.CacheDir
+	if config.AlgodURL != "" {
+		c.algodURL, err = url.Parse(config.AlgodURL)
+		if err != nil {
+			return err
+		}
+		c.algodAPIToken = config.AlgodAPIToken
+	}
 
 	// Get node controller
 	nc, err := getNodeController(config.BinDir, config.AlgodDataDir)
@@ -234,6 +253,9 @@ func (c *Client) getKMDClient() (kmdclient.KMDClient, error) {
 }
 
 func (c *Client) getAlgodClient() (algodclient.RestClient, error) {
+	if c.algodURL != nil {
+		return algodclient.MakeRestClient(*c.algodURL, c.algodAPIToken), nil
+	}
 	algodClient, err := c.nc.AlgodClient()
 	if err != nil {
 		return algodclient.RestClient{}, err
@@ -360,6 +382,21 @@ func (c *Client) GenerateAddress(walletHandle []byte) (string, error) {
 	return resp.Address, nil
 }
 
+// DeriveAddress takes a wallet handle and an index, derives the address at that index of the wallet's
+// deterministic key sequence, adds it to the wallet and returns the public address
+func (c *Client) DeriveAddress(walletHandle []byte, index uint64) (string, error) {
+	kmd, err := c.ensureKmdClient()
+	if err != nil {
+		return "", err
+	}
+	resp, err := kmd.DeriveKey(walletHandle, index)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Address, nil
+}
+
 // CreateMultisigAccount takes a wallet handle, a list of (nonmultisig) addresses, and a threshold and creates (and returns) a multisig adress
 // TODO: Should these be raw public keys instead of addresses so users can't shoot themselves in the foot by passing in a multisig addr? Probably will become irrelevant after CSID changes.
 func (c *Client) CreateMultisigAccount(walletHandle []byte, threshold uint8, addrs []string) (string, error) {
@@ -428,26 +465,19 @@ type MultisigInfo struct {
 }
 
 // SendPaymentFromWallet signs a transaction using the given wallet and returns the resulted transaction id
-func (c *Client) SendPaymentFromWallet(walletHandle, pw []byte, from, to string, fee, amount uint64, note []byte, closeTo string) (transactions.Transaction, error) {
+func (c *Client) SendPaymentFromWallet(walletHandle, pw []byte, from, to string, fee, amount uint64, note []byte, closeTo string, lease [32]byte) (transactions.Transaction, error) {
 	// Build the transaction
-	tx, err := c.ConstructPayment(from, to, fee, amount, note, closeTo)
+	tx, err := c.ConstructPayment(from, to, fee, amount, note, closeTo, lease)
 	if err != nil {
 		return transactions.Transaction{}, err
 	}
 
-	// Sign the transaction
-	kmd, err := c.ensureKmdClient()
+	// Sign the transaction, with the key the sender has been rekeyed to if any
+	info, err := c.AccountInformation(from)
 	if err != nil {
 		return transactions.Transaction{}, err
 	}
-	resp0, err := kmd.SignTransaction(walletHandle, pw, tx)
-	if err != nil {
-		return transactions.Transaction{}, err
-	}
-
-	// Decode the SignedTxn
-	var stx transactions.SignedTxn
-	err = protocol.Decode(resp0.SignedTransaction, &stx)
+	stx, err := c.SignTransactionWithWalletAndSigner(walletHandle, pw, info.AuthAddr, tx)
 	if err != nil {
 		return transactions.Transaction{}, err
 	}
@@ -467,25 +497,34 @@ func (c *Client) SendPaymentFromWallet(walletHandle, pw []byte, from, to string,
 
 // ConstructPayment builds a payment transaction to be signed
 // If the fee is 0, the function will use the suggested one form the network
-func (c *Client) ConstructPayment(from, to string, fee, amount uint64, note []byte, closeTo string) (transactions.Transaction, error) {
-	fromAddr, err := basics.UnmarshalChecksumAddress(from)
+// A nonzero lease makes the transaction hold it through its LastValid round once committed
+func (c *Client) ConstructPayment(from, to string, fee, amount uint64, note []byte, closeTo string, lease [32]byte) (transactions.Transaction, error) {
+	// Get current round, protocol, genesis ID
+	params, err := c.SuggestedParams()
 	if err != nil {
 		return transactions.Transaction{}, err
 	}
+	return MakePayment(params, from, to, fee, amount, note, closeTo, lease)
+}
 
-	toAddr, err := basics.UnmarshalChecksumAddress(to)
+// MakePayment builds a payment transaction to be signed like ConstructPayment, but from the given
+// transaction params rather than the ones the node suggests, so it needs no node
+func MakePayment(params models.TransactionParams, from, to string, fee, amount uint64, note []byte, closeTo string, lease [32]byte) (transactions.Transaction, error) {
+	fromAddr, err := basics.UnmarshalChecksumAddress(from)
 	if err != nil {
 		return transactions.Transaction{}, err
 	}
 
-	// Get current round, protocol, genesis ID
-	params, err := c.SuggestedParams()
+	toAddr, err := basics.UnmarshalChecksumAddress(to)
 	if err != nil {
 		return transactions.Transaction{}, err
 	}
 
 	round := params.LastRound
-	cp := config.Consensus[protocol.ConsensusVersion(params.ConsensusVersion)]
+	cp, ok := config.Consensus[protocol.ConsensusVersion(params.ConsensusVersion)]
+	if !ok {
+		return transactions.Transaction{}, errors.New("unknown consensus version")
+	}
 	tx := transactions.Transaction{
 		Type: protocol.PaymentTx,
 		Header: transactions.Header{
@@ -494,6 +533,7 @@ func (c *Client) ConstructPayment(from, to string, fee, amount uint64, note []by
 			FirstValid: basics.Round(round),
 			LastValid:  basics.Round(round) + basics.Round(cp.MaxTxnLife),
 			Note:       note,
+			Lease:      lease,
 		},
 		PaymentTxnFields: transactions.PaymentTxnFields{
 			Receiver: toAddr,
@@ -553,6 +593,35 @@ func (c *Client) AccountInformation(account string) (resp models.Account, err er
 	return
 }
 
+// BalancesAtRound returns the balances of addresses as of round
+func (c *Client) BalancesAtRound(round uint64, addresses []string) (resp models.Balances, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.BalancesAtRound(round, addresses)
+	}
+	return
+}
+
+// TransactionsByAddress returns the transactions of addr in the rounds first to last, which the node finds by
+// walking the blocks of these rounds
+func (c *Client) TransactionsByAddress(addr string, first, last uint64) (resp models.TransactionList, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.TransactionsByAddr(addr, first, last)
+	}
+	return
+}
+
+// RecentTransactionsByAddress returns the max most recent transactions of addr, which only nodes running the
+// transaction indexer can find
+func (c *Client) RecentTransactionsByAddress(addr string, max uint64) (resp models.TransactionList, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.RecentTransactionsByAddr(addr, max)
+	}
+	return
+}
+
 // TransactionInformation takes an address and associated txid and return its information
 func (c *Client) TransactionInformation(addr, txid string) (resp models.Transaction, err error) {
 	algod, err := c.ensureAlgodClient()
@@ -581,6 +650,15 @@ func (c *Client) Block(round uint64) (resp models.Block, err error) {
 	return
 }
 
+// RawBlock takes a round and returns its msgpack encoded block and certificate
+func (c *Client) RawBlock(round uint64) (resp []byte, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.RawBlock(round)
+	}
+	return
+}
+
 // HealthCheck returns an error if something is wrong
 func (c *Client) HealthCheck() error {
 	algod, err := c.ensureAlgodClient()
@@ -626,6 +704,33 @@ func (c Client) LedgerSupply() (resp models.Supply, err error) {
 	return
 }
 
+// LogLevels returns the log level of each of the node subsystems
+func (c Client) LogLevels() (resp models.LogLevels, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.LogLevels()
+	}
+	return
+}
+
+// SetLogLevel sets the log level of the given node subsystem, until the node restarts
+func (c Client) SetLogLevel(subsystem string, level string) (resp models.LogLevels, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.SetLogLevel(subsystem, level)
+	}
+	return
+}
+
+// Shutdown asks the node to shut down gracefully; it returns once the shutdown started
+func (c Client) Shutdown() error {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		err = algod.Shutdown()
+	}
+	return err
+}
+
 // CurrentRound returns the current known round
 func (c Client) CurrentRound() (lastRound uint64, err error) {
 	// Get current round
@@ -651,6 +756,15 @@ func (c *Client) SuggestedFee() (fee uint64, err error) {
 	return
 }
 
+// FeeInfo returns the suggested fee per byte along with the congestion of the node's transaction pool
+func (c *Client) FeeInfo() (info models.TransactionFeeInfo, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		info, err = algod.FeeInfo()
+	}
+	return
+}
+
 // SuggestedParams returns the suggested parameters for a new transaction
 func (c *Client) SuggestedParams() (params models.TransactionParams, err error) {
 	algod, err := c.ensureAlgodClient()
@@ -670,6 +784,44 @@ func (c *Client) GetPendingTransactions(maxTxns uint64) (resp models.PendingTran
 	return
 }
 
+// SenderPendingTransactions gets a snapshot of the pending transactions of the given sender on the node.
+// If maxTxns = 0, fetches all the pending transactions of the sender.
+func (c *Client) SenderPendingTransactions(address string, maxTxns uint64) (resp models.SenderPendingTransactions, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.SenderPendingTransactions(address, maxTxns)
+	}
+	return
+}
+
+// PoolTransactions lists the transactions pending in the node's pool along with their fees and order, only the
+// ones of sender if it isn't empty. If maxTxns = 0, fetches all the matching transactions.
+func (c *Client) PoolTransactions(sender string, maxTxns uint64) (resp models.PoolTransactions, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.PoolTransactions(sender, maxTxns)
+	}
+	return
+}
+
+// DropPoolTransaction evicts a transaction, along with the rest of its group, from the node's pool
+func (c *Client) DropPoolTransaction(txid string) (resp models.DroppedTransactions, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.DropPoolTransaction(txid)
+	}
+	return
+}
+
+// RebroadcastStatus gets the rebroadcast status of a transaction submitted through the node
+func (c *Client) RebroadcastStatus(txid string) (resp models.RebroadcastStatus, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		resp, err = algod.RebroadcastStatus(txid)
+	}
+	return
+}
+
 // ExportKey exports the private key of the passed account, assuming it's available
 func (c *Client) ExportKey(walletHandle []byte, password, account string) (resp kmdapi.APIV1POSTKeyExportResponse, err error) {
 	kmd, err := c.ensureKmdClient()
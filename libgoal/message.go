@@ -0,0 +1,65 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package libgoal
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/hashable"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// messageBytes returns the bytes signed for a message: the encoding of a
+// hashable.Message, which the key signs prefixed with protocol.Message so
+// that the signature can't pass as that of a transaction, or of a reserve
+// report, which encodes differently.
+func messageBytes(message string) []byte {
+	return protocol.Encode(hashable.Message{Message: message})
+}
+
+// SignMessage signs message with the key of addr, which must be in the
+// wallet, to prove control of the account.
+func (c *Client) SignMessage(walletHandle, pw []byte, addr string, message string) (sig crypto.Signature, err error) {
+	signer, err := basics.UnmarshalChecksumAddress(addr)
+	if err != nil {
+		return
+	}
+	kmd, err := c.ensureKmdClient()
+	if err != nil {
+		return
+	}
+	resp, err := kmd.SignData(walletHandle, pw, crypto.PublicKey(signer), messageBytes(message))
+	if err != nil {
+		return
+	}
+	return resp.Signature, nil
+}
+
+// VerifyMessage checks that sig is the signature of message made by
+// SignMessage with the key of addr. It needs neither a wallet nor a node.
+func VerifyMessage(addr string, message string, sig crypto.Signature) error {
+	signer, err := basics.UnmarshalChecksumAddress(addr)
+	if err != nil {
+		return err
+	}
+	if !crypto.SignatureVerifier(signer).VerifyBytes(messageBytes(message), sig) {
+		return fmt.Errorf("signature does not verify with the key of %s", addr)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package libgoal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+func TestVerifyMessage(t *testing.T) {
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	key := crypto.GenerateSignatureSecrets(seed)
+	addr := basics.Address(key.SignatureVerifier).GetUserAddress()
+
+	// kmd signs with SignBytes, as here
+	sig := key.SignBytes(messageBytes("I own this account"))
+	require.NoError(t, VerifyMessage(addr, "I own this account", sig))
+	require.Error(t, VerifyMessage(addr, "I own that account", sig))
+
+	var other crypto.Seed
+	crypto.RandBytes(other[:])
+	otherAddr := basics.Address(crypto.GenerateSignatureSecrets(other).SignatureVerifier).GetUserAddress()
+	require.Error(t, VerifyMessage(otherAddr, "I own this account", sig))
+
+	// the signature of the bare text doesn't pass for that of the message
+	require.Error(t, VerifyMessage(addr, "I own this account", key.SignBytes([]byte("I own this account"))))
+	require.Error(t, VerifyMessage("not an address", "I own this account", sig))
+}
@@ -23,9 +23,11 @@ import (
 	"path/filepath"
 
 	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/data/account"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util"
 	"github.com/algorand/go-algorand/util/db"
 )
 
@@ -117,6 +119,12 @@ func (c *Client) GenParticipationKeys(address string, firstValid, lastValid, key
 // GenParticipationKeysTo creates a .partkey database for a given address, fills
 // it with keys, and saves it in the specified output directory.
 func (c *Client) GenParticipationKeysTo(address string, firstValid, lastValid, keyDilution uint64, outDir string) (part account.Participation, filePath string, err error) {
+	return c.GenParticipationKeysProgress(address, firstValid, lastValid, keyDilution, outDir, nil)
+}
+
+// GenParticipationKeysProgress is a version of GenParticipationKeysTo that
+// reports the progress of the key generation to progress, if it isn't nil.
+func (c *Client) GenParticipationKeysProgress(address string, firstValid, lastValid, keyDilution uint64, outDir string, progress crypto.KeyGenProgress) (part account.Participation, filePath string, err error) {
 	// Parse the address
 	parsedAddr, err := basics.UnmarshalChecksumAddress(address)
 	if err != nil {
@@ -163,7 +171,7 @@ func (c *Client) GenParticipationKeysTo(address string, firstValid, lastValid, k
 	}
 
 	// Fill the database with new participation keys
-	newPart, err := account.FillDBWithParticipationKeys(partdb, parsedAddr, firstRound, lastRound, keyDilution)
+	newPart, err := account.FillDBWithParticipationKeysProgress(partdb, parsedAddr, firstRound, lastRound, keyDilution, progress)
 	return newPart, partKeyPath, err
 }
 
@@ -211,3 +219,58 @@ func (c *Client) ListParticipationKeys() (partKeyFiles map[string]account.Partic
 
 	return
 }
+
+// InstallParticipationKeys copies the .partkey database inputfile, generated
+// elsewhere, into the ledger directory, where the node picks it up.
+func (c *Client) InstallParticipationKeys(inputfile string) (part account.Participation, filePath string, err error) {
+	// Make sure the input is a participation key database we can use
+	inputdb, err := db.MakeErasableAccessor(inputfile)
+	if err != nil {
+		return
+	}
+	part, err = account.RestoreParticipation(inputdb)
+	inputdb.Close()
+	if err != nil {
+		return
+	}
+
+	genID, err := c.GenesisID()
+	if err != nil {
+		return
+	}
+	keyDir := filepath.Join(c.DataDir(), genID)
+	filePath, err = participationKeysPath(keyDir, part.Address(), part.FirstValid, part.LastValid)
+	if err != nil {
+		return
+	}
+	if util.FileExists(filePath) {
+		err = fmt.Errorf("participation key database %s already exists", filePath)
+		return
+	}
+
+	// Copy under a temporary name first, so that the node never loads a partial database
+	tmpPath := filePath + ".tmp"
+	_, err = util.CopyFile(inputfile, tmpPath)
+	if err == nil {
+		err = os.Rename(tmpPath, filePath)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+	}
+	return
+}
+
+// DeleteParticipationKeys removes the .partkey database filename, as listed by
+// ListParticipationKeys, from the ledger directory. The node stops using the
+// keys when it next scans the directory.
+func (c *Client) DeleteParticipationKeys(filename string) error {
+	if !config.IsPartKeyFilename(filename) || filepath.Base(filename) != filename {
+		return fmt.Errorf("%s is not the name of a participation key database", filename)
+	}
+
+	genID, err := c.GenesisID()
+	if err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(c.DataDir(), genID, filename))
+}
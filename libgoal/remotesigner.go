@@ -0,0 +1,145 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package libgoal
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/transactions"
+)
+
+// remoteSignerTimeout bounds a single request to a signing service, which
+// may have to wait on an HSM or a human approval.
+const remoteSignerTimeout = 60 * time.Second
+
+// RemoteSignerConfig locates a signing service and the TLS material used to
+// authenticate to it. CertFile and KeyFile hold the client certificate for
+// mutual TLS, and CAFile the authorities the service certificate is checked
+// against (the system ones when empty).
+type RemoteSignerConfig struct {
+	URL      string
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// RemoteSignRequest is what a RemoteSigner posts to the signing service.
+// Bytes is the exact message to sign with the Ed25519 key of Address: the
+// domain separated canonical msgpack encoding of the transaction.
+type RemoteSignRequest struct {
+	Address string `json:"address"`
+	TxID    string `json:"txid"`
+	Bytes   []byte `json:"bytes"`
+}
+
+// RemoteSignResponse is what the signing service answers with.
+type RemoteSignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// RemoteSigner signs transactions by handing them to a signing service over
+// HTTP, so that the keys never need to be imported into kmd.
+type RemoteSigner struct {
+	url    string
+	client *http.Client
+}
+
+// MakeRemoteSigner returns a RemoteSigner for the signing service described by cfg.
+func MakeRemoteSigner(cfg RemoteSignerConfig) (*RemoteSigner, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load the signer client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CAFile != "" {
+		pem, err := ioutil.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the signer CA file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in the signer CA file %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &RemoteSigner{
+		url: cfg.URL,
+		client: &http.Client{
+			Timeout:   remoteSignerTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+	}, nil
+}
+
+// SignTransaction has the signing service sign tx with the key of its sender.
+// The returned signature is checked before it is accepted, so that a
+// misbehaving service cannot produce an invalid transaction.
+func (rs *RemoteSigner) SignTransaction(tx transactions.Transaction) (stx transactions.SignedTxn, err error) {
+	id, data := tx.ToBeHashed()
+	body, err := json.Marshal(RemoteSignRequest{
+		Address: tx.Sender.String(),
+		TxID:    tx.ID().String(),
+		Bytes:   append([]byte(id), data...),
+	})
+	if err != nil {
+		return
+	}
+
+	resp, err := rs.client.Post(rs.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("signing service returned %s: %s", resp.Status, bytes.TrimSpace(respBody))
+		return
+	}
+
+	var signed RemoteSignResponse
+	err = json.Unmarshal(respBody, &signed)
+	if err != nil {
+		err = fmt.Errorf("cannot decode the signing service response: %v", err)
+		return
+	}
+	var sig crypto.Signature
+	if len(signed.Signature) != len(sig) {
+		err = fmt.Errorf("signing service returned a %d byte signature, expected %d", len(signed.Signature), len(sig))
+		return
+	}
+	copy(sig[:], signed.Signature)
+	if !crypto.SignatureVerifier(tx.Sender).Verify(tx, sig) {
+		err = fmt.Errorf("signing service returned a signature that does not verify for %s", tx.Sender)
+		return
+	}
+	return transactions.AssembleSignedTxn(tx, sig, crypto.MultisigSig{})
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package libgoal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	secrets := crypto.GenerateSignatureSecrets(seed)
+	sender := basics.Address(secrets.SignatureVerifier)
+
+	tx := transactions.Transaction{
+		Type: protocol.PaymentTx,
+		Header: transactions.Header{
+			Sender:     sender,
+			Fee:        basics.MicroAlgos{Raw: 1000},
+			FirstValid: 1,
+			LastValid:  100,
+		},
+		PaymentTxnFields: transactions.PaymentTxnFields{Amount: basics.MicroAlgos{Raw: 5}},
+	}
+	id, data := tx.ToBeHashed()
+
+	signature := secrets.Sign(tx)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req RemoteSignRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, sender.String(), req.Address)
+		require.Equal(t, tx.ID().String(), req.TxID)
+		require.Equal(t, append([]byte(id), data...), req.Bytes)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(RemoteSignResponse{Signature: signature[:]})
+	}))
+	defer server.Close()
+
+	signer, err := MakeRemoteSigner(RemoteSignerConfig{URL: server.URL})
+	require.NoError(t, err)
+	stxn, err := signer.SignTransaction(tx)
+	require.NoError(t, err)
+	require.Equal(t, signature, stxn.Sig)
+	require.Equal(t, tx.ID(), stxn.ID())
+
+	// a signature by another key is refused
+	signature = crypto.GenerateSignatureSecrets(crypto.Seed{1}).Sign(tx)
+	_, err = signer.SignTransaction(tx)
+	require.Error(t, err)
+
+	status = http.StatusForbidden
+	_, err = signer.SignTransaction(tx)
+	require.Contains(t, err.Error(), "403")
+}
+
+func TestRemoteSignerConfig(t *testing.T) {
+	_, err := MakeRemoteSigner(RemoteSignerConfig{URL: "https://localhost", CertFile: "missing.crt", KeyFile: "missing.key"})
+	require.Error(t, err)
+	_, err = MakeRemoteSigner(RemoteSignerConfig{URL: "https://localhost", CAFile: "missing.pem"})
+	require.Error(t, err)
+}
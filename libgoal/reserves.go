@@ -0,0 +1,178 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package libgoal
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// ReserveReport states the balances of a set of accounts as of a round. It
+// is pinned to the chain by the genesis and the hash of the block of the
+// round, so that a third party can check it against a node of its own.
+type ReserveReport struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	GenesisID   string            `codec:"gen"`
+	GenesisHash crypto.Digest     `codec:"gh"`
+	Round       basics.Round      `codec:"rnd"`
+	BlockHash   crypto.Digest     `codec:"bh"`
+	Accounts    []ReserveAccount  `codec:"accts"`
+	Total       basics.MicroAlgos `codec:"total"`
+}
+
+// ReserveAccount is the balance of one account of a ReserveReport
+type ReserveAccount struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Address basics.Address    `codec:"addr"`
+	Amount  basics.MicroAlgos `codec:"amt"`
+	// AuthAddr is the address the account was rekeyed to as of the round,
+	// whose key signs for the account
+	AuthAddr basics.Address `codec:"spend"`
+}
+
+// Signer returns the address whose key signs for the account
+func (ra ReserveAccount) Signer() basics.Address {
+	if ra.AuthAddr != (basics.Address{}) {
+		return ra.AuthAddr
+	}
+	return ra.Address
+}
+
+// SignedReserveReport is a ReserveReport signed by the key of every account
+// it lists, which proves that whoever made it controls these accounts.
+type SignedReserveReport struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Report ReserveReport `codec:"report"`
+	// Sigs holds the signature of the encoded Report by every account, in
+	// the order of Report.Accounts
+	Sigs []crypto.Signature `codec:"sigs"`
+}
+
+// MakeReserveReport looks up the balances of addresses as of round. The node
+// must still hold the balances of that round.
+func (c *Client) MakeReserveReport(round uint64, addresses []string) (report ReserveReport, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err != nil {
+		return
+	}
+
+	params, err := algod.SuggestedParams()
+	if err != nil {
+		return
+	}
+	block, err := algod.Block(round)
+	if err != nil {
+		return
+	}
+	balances, err := algod.BalancesAtRound(round, addresses)
+	if err != nil {
+		return
+	}
+
+	report.GenesisID = params.GenesisID
+	copy(report.GenesisHash[:], params.GenesisHash)
+	report.Round = basics.Round(balances.Round)
+	report.BlockHash, err = crypto.DigestFromString(block.Hash)
+	if err != nil {
+		return
+	}
+
+	seen := make(map[basics.Address]bool, len(balances.Accounts))
+	for _, balance := range balances.Accounts {
+		var acct ReserveAccount
+		acct.Address, err = basics.UnmarshalChecksumAddress(balance.Address)
+		if err != nil {
+			return
+		}
+		if seen[acct.Address] {
+			continue
+		}
+		seen[acct.Address] = true
+
+		if balance.AuthAddr != "" {
+			acct.AuthAddr, err = basics.UnmarshalChecksumAddress(balance.AuthAddr)
+			if err != nil {
+				return
+			}
+		}
+		acct.Amount.Raw = balance.Amount
+		report.Total.Raw += balance.Amount
+		report.Accounts = append(report.Accounts, acct)
+	}
+	return
+}
+
+// SignReserveReport signs report with the key of every account it lists,
+// which must all be in the wallet.
+func (c *Client) SignReserveReport(walletHandle, pw []byte, report ReserveReport) (signed SignedReserveReport, err error) {
+	kmd, err := c.ensureKmdClient()
+	if err != nil {
+		return
+	}
+
+	data := protocol.Encode(report)
+	signed.Report = report
+	for _, acct := range report.Accounts {
+		resp, err := kmd.SignData(walletHandle, pw, crypto.PublicKey(acct.Signer()), data)
+		if err != nil {
+			return SignedReserveReport{}, fmt.Errorf("signing for %s: %v", acct.Address.GetUserAddress(), err)
+		}
+		signed.Sigs = append(signed.Sigs, resp.Signature)
+	}
+	return
+}
+
+// Verify checks that the report is signed by every account it lists and that
+// its total adds up. It doesn't check the balances themselves, which takes a
+// node that still holds the balances of the round.
+func (sr SignedReserveReport) Verify() error {
+	report := sr.Report
+	if len(sr.Sigs) != len(report.Accounts) {
+		return fmt.Errorf("report lists %d accounts but has %d signatures", len(report.Accounts), len(sr.Sigs))
+	}
+
+	data := protocol.Encode(report)
+	var total basics.MicroAlgos
+	seen := make(map[basics.Address]bool, len(report.Accounts))
+	for i, acct := range report.Accounts {
+		if seen[acct.Address] {
+			return fmt.Errorf("account %s is listed more than once", acct.Address.GetUserAddress())
+		}
+		seen[acct.Address] = true
+
+		if !crypto.SignatureVerifier(acct.Signer()).VerifyBytes(data, sr.Sigs[i]) {
+			return fmt.Errorf("signature for account %s does not verify with the key of %s", acct.Address.GetUserAddress(), acct.Signer().GetUserAddress())
+		}
+
+		var overflowed bool
+		total, overflowed = basics.OAddA(total, acct.Amount)
+		if overflowed {
+			return fmt.Errorf("report total overflows")
+		}
+	}
+
+	if total != report.Total {
+		return fmt.Errorf("report total is %d but its accounts add up to %d", report.Total.Raw, total.Raw)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package libgoal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func makeTestSecrets() *crypto.SignatureSecrets {
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	return crypto.GenerateSignatureSecrets(seed)
+}
+
+func TestSignedReserveReportVerify(t *testing.T) {
+	owner, spender, rekeyed := makeTestSecrets(), makeTestSecrets(), makeTestSecrets()
+
+	report := ReserveReport{
+		GenesisID: "test",
+		Round:     10,
+		Accounts: []ReserveAccount{
+			{Address: basics.Address(owner.SignatureVerifier), Amount: basics.MicroAlgos{Raw: 100}},
+			{Address: basics.Address(rekeyed.SignatureVerifier), Amount: basics.MicroAlgos{Raw: 50}, AuthAddr: basics.Address(spender.SignatureVerifier)},
+		},
+		Total: basics.MicroAlgos{Raw: 150},
+	}
+	sign := func(report ReserveReport, keys ...*crypto.SignatureSecrets) SignedReserveReport {
+		signed := SignedReserveReport{Report: report}
+		for _, key := range keys {
+			signed.Sigs = append(signed.Sigs, key.SignBytes(protocol.Encode(report)))
+		}
+		return signed
+	}
+
+	require.NoError(t, sign(report, owner, spender).Verify())
+
+	// A rekeyed account signs with the key it was rekeyed to
+	require.Error(t, sign(report, owner, rekeyed).Verify())
+	require.Error(t, sign(report, owner).Verify())
+
+	// The signatures cover the whole report
+	signed := sign(report, owner, spender)
+	signed.Report.Accounts = append([]ReserveAccount(nil), report.Accounts...)
+	signed.Report.Accounts[0].Amount.Raw++
+	signed.Report.Total.Raw++
+	require.Error(t, signed.Verify())
+
+	wrongTotal := report
+	wrongTotal.Total.Raw = 1000
+	require.Error(t, sign(wrongTotal, owner, spender).Verify())
+
+	duplicated := report
+	duplicated.Accounts = []ReserveAccount{report.Accounts[0], report.Accounts[0]}
+	duplicated.Total.Raw = 200
+	require.Error(t, sign(duplicated, owner, owner).Verify())
+}
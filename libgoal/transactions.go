@@ -17,10 +17,13 @@
 package libgoal
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/daemon/algod/api/client/models"
 	"github.com/algorand/go-algorand/data/account"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
@@ -29,13 +32,28 @@ import (
 
 // SignTransactionWithWallet signs the passed transaction with keys from the wallet associated with the passed walletHandle
 func (c *Client) SignTransactionWithWallet(walletHandle, pw []byte, utx transactions.Transaction) (stx transactions.SignedTxn, err error) {
+	return c.SignTransactionWithWalletAndSigner(walletHandle, pw, "", utx)
+}
+
+// SignTransactionWithWalletAndSigner signs the passed transaction with the key of signerAddr, which is how a rekeyed sender spends. An empty signerAddr signs with the sender's key.
+func (c *Client) SignTransactionWithWalletAndSigner(walletHandle, pw []byte, signerAddr string, utx transactions.Transaction) (stx transactions.SignedTxn, err error) {
+	var signer crypto.PublicKey
+	if signerAddr != "" {
+		var addr basics.Address
+		addr, err = basics.UnmarshalChecksumAddress(signerAddr)
+		if err != nil {
+			return
+		}
+		signer = crypto.PublicKey(addr)
+	}
+
 	kmd, err := c.ensureKmdClient()
 	if err != nil {
 		return
 	}
 
 	// Sign the transaction
-	resp, err := kmd.SignTransaction(walletHandle, pw, utx)
+	resp, err := kmd.SignTransaction(walletHandle, pw, signer, utx)
 	if err != nil {
 		return
 	}
@@ -65,6 +83,25 @@ func (c *Client) MultisigSignTransactionWithWallet(walletHandle, pw []byte, utx
 	return
 }
 
+// SignProgramWithWallet signs a TEAL program with the key of signerAddr,
+// delegating to the program the authority to approve transactions from
+// signerAddr's account through a LogicSig
+func (c *Client) SignProgramWithWallet(walletHandle, pw []byte, signerAddr string, program []byte) (sig crypto.Signature, err error) {
+	addr, err := basics.UnmarshalChecksumAddress(signerAddr)
+	if err != nil {
+		return
+	}
+	kmd, err := c.ensureKmdClient()
+	if err != nil {
+		return
+	}
+	resp, err := kmd.SignProgram(walletHandle, pw, crypto.PublicKey(addr), program)
+	if err != nil {
+		return
+	}
+	return resp.Signature, nil
+}
+
 // BroadcastTransaction broadcasts a signed transaction to the network using algod
 func (c *Client) BroadcastTransaction(stx transactions.SignedTxn) (txid string, err error) {
 	algod, err := c.ensureAlgodClient()
@@ -78,6 +115,27 @@ func (c *Client) BroadcastTransaction(stx transactions.SignedTxn) (txid string,
 	return resp.TxID, nil
 }
 
+// BroadcastTransactionGroup broadcasts the signed transactions of an atomic transaction group to the network
+// using algod. Either all of them are accepted, or none is.
+func (c *Client) BroadcastTransactionGroup(txgroup []transactions.SignedTxn) error {
+	algod, err := c.ensureAlgodClient()
+	if err != nil {
+		return err
+	}
+	_, err = algod.SendRawTransactionGroup(txgroup)
+	return err
+}
+
+// BroadcastTransactionBatch broadcasts a batch of signed transactions to the network using algod, and returns
+// the outcome of each of them, in the order of the batch
+func (c *Client) BroadcastTransactionBatch(txns []transactions.SignedTxn) (resp models.TransactionBatchResults, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err != nil {
+		return
+	}
+	return algod.SendRawTransactionBatch(txns)
+}
+
 // SignAndBroadcastTransaction signs the unsigned transaction with keys from the default wallet, and broadcasts it
 func (c *Client) SignAndBroadcastTransaction(walletHandle, pw []byte, utx transactions.Transaction) (txid string, err error) {
 	// Sign the transaction
@@ -90,8 +148,49 @@ func (c *Client) SignAndBroadcastTransaction(walletHandle, pw []byte, utx transa
 	return c.BroadcastTransaction(stx)
 }
 
+// TxnPoolError is returned by WaitForConfirmation when the node kicked the transaction out of its pool
+type TxnPoolError struct {
+	TxID   string
+	Reason string
+}
+
+func (e TxnPoolError) Error() string {
+	return fmt.Sprintf("transaction %s kicked out of the node pool: %s", e.TxID, e.Reason)
+}
+
+// WaitForConfirmation waits, a round at a time, until the transaction txid commits and returns its information.
+// It returns a TxnPoolError if the node kicks the transaction out of its pool, and ctx.Err() if ctx is done
+// before the transaction commits; ctx is checked once a round, after looking the transaction up.
+func (c *Client) WaitForConfirmation(txid string, ctx context.Context) (txn models.Transaction, err error) {
+	stat, err := c.Status()
+	if err != nil {
+		return
+	}
+
+	for {
+		txn, err = c.PendingTransactionInformation(txid)
+		if err != nil {
+			return
+		}
+		if txn.ConfirmedRound > 0 {
+			return
+		}
+		if txn.PoolError != "" {
+			return txn, TxnPoolError{TxID: txid, Reason: txn.PoolError}
+		}
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		stat, err = c.WaitForRound(stat.LastRound + 1)
+		if err != nil {
+			return
+		}
+	}
+}
+
 // MakeUnsignedGoOnlineTx creates a transaction that will bring an address online using available participation keys
-func (c *Client) MakeUnsignedGoOnlineTx(address string, part *account.Participation, round, txValidRounds, fee uint64) (transactions.Transaction, error) {
+func (c *Client) MakeUnsignedGoOnlineTx(address string, part *account.Participation, round, txValidRounds, fee uint64, lease [32]byte) (transactions.Transaction, error) {
 	// Parse the address
 	parsedAddr, err := basics.UnmarshalChecksumAddress(address)
 	if err != nil {
@@ -135,6 +234,7 @@ func (c *Client) MakeUnsignedGoOnlineTx(address string, part *account.Participat
 	parsedFee := basics.MicroAlgos{Raw: fee}
 
 	goOnlineTransaction := part.GenerateRegistrationTransaction(parsedFee, parsedRound, lastRound, cparams)
+	goOnlineTransaction.Lease = lease
 	if cparams.SupportGenesisHash {
 		var genHash crypto.Digest
 		copy(genHash[:], params.GenesisHash)
@@ -159,7 +259,17 @@ func (c *Client) MakeUnsignedGoOnlineTx(address string, part *account.Participat
 }
 
 // MakeUnsignedGoOfflineTx creates a transaction that will bring an address offline
-func (c *Client) MakeUnsignedGoOfflineTx(address string, round, txValidRounds, fee uint64) (transactions.Transaction, error) {
+func (c *Client) MakeUnsignedGoOfflineTx(address string, round, txValidRounds, fee uint64, lease [32]byte) (transactions.Transaction, error) {
+	return c.makeUnsignedOfflineKeyregTx(address, round, txValidRounds, fee, lease, false)
+}
+
+// MakeUnsignedBecomeNonparticipatingTx creates a transaction that will irreversibly mark an address as nonparticipating
+func (c *Client) MakeUnsignedBecomeNonparticipatingTx(address string, round, txValidRounds, fee uint64, lease [32]byte) (transactions.Transaction, error) {
+	return c.makeUnsignedOfflineKeyregTx(address, round, txValidRounds, fee, lease, true)
+}
+
+// makeUnsignedOfflineKeyregTx creates a keyreg transaction without participation keys, optionally with the nonparticipation flag
+func (c *Client) makeUnsignedOfflineKeyregTx(address string, round, txValidRounds, fee uint64, lease [32]byte, nonparticipation bool) (transactions.Transaction, error) {
 	// Parse the address
 	parsedAddr, err := basics.UnmarshalChecksumAddress(address)
 	if err != nil {
@@ -175,6 +285,9 @@ func (c *Client) MakeUnsignedGoOfflineTx(address string, round, txValidRounds, f
 	if !ok {
 		return transactions.Transaction{}, errors.New("unknown consensus version")
 	}
+	if nonparticipation && !cparams.SupportBecomeNonParticipatingTransactions {
+		return transactions.Transaction{}, errors.New("the consensus protocol doesn't support marking accounts as nonparticipating")
+	}
 
 	// Determine the last round this tx will be valid
 	if round == 0 {
@@ -190,33 +303,87 @@ func (c *Client) MakeUnsignedGoOfflineTx(address string, round, txValidRounds, f
 	lastRound := parsedRound + parsedTXValidRounds
 	parsedFee := basics.MicroAlgos{Raw: fee}
 
-	goOfflineTransaction := transactions.Transaction{
+	keyregTransaction := transactions.Transaction{
 		Type: protocol.KeyRegistrationTx,
 		Header: transactions.Header{
 			Sender:     parsedAddr,
 			Fee:        parsedFee,
 			FirstValid: parsedRound,
 			LastValid:  lastRound,
+			Lease:      lease,
+		},
+		KeyregTxnFields: transactions.KeyregTxnFields{
+			Nonparticipation: nonparticipation,
 		},
 	}
 	if cparams.SupportGenesisHash {
 		var genHash crypto.Digest
 		copy(genHash[:], params.GenesisHash)
-		goOfflineTransaction.GenesisHash = genHash
+		keyregTransaction.GenesisHash = genHash
 		// Recompute the TXID
-		goOfflineTransaction.ResetCaches()
+		keyregTransaction.ResetCaches()
 	}
 
 	// Default to the suggested fee, if the caller didn't supply it
 	// Fee is tricky, should taken care last. We encode the final transaction to get the size post signing and encoding
 	// Then, we multiply it by the suggested fee per byte.
 	if fee == 0 {
-		goOfflineTransaction.Fee = basics.MulAIntSaturate(basics.MicroAlgos{Raw: params.Fee}, goOfflineTransaction.EstimateEncodedSize())
-		if goOfflineTransaction.Fee.Raw < cparams.MinTxnFee {
-			goOfflineTransaction.Fee.Raw = cparams.MinTxnFee
+		keyregTransaction.Fee = basics.MulAIntSaturate(basics.MicroAlgos{Raw: params.Fee}, keyregTransaction.EstimateEncodedSize())
+		if keyregTransaction.Fee.Raw < cparams.MinTxnFee {
+			keyregTransaction.Fee.Raw = cparams.MinTxnFee
 		}
 		// Recompute the TXID
-		goOfflineTransaction.ResetCaches()
+		keyregTransaction.ResetCaches()
+	}
+	return keyregTransaction, nil
+}
+
+// FillUnsignedTxTemplate fills in the header of tx, which is built by one of the MakeUnsignedAsset functions:
+// the sender, the validity range, the genesis ID and hash, and the fee. A zero firstValid, lastValid or fee picks
+// the next round, the longest validity range the protocol allows, and the suggested fee.
+func (c *Client) FillUnsignedTxTemplate(sender string, firstValid, lastValid, fee uint64, tx transactions.Transaction) (transactions.Transaction, error) {
+	parsedAddr, err := basics.UnmarshalChecksumAddress(sender)
+	if err != nil {
+		return transactions.Transaction{}, err
+	}
+
+	params, err := c.SuggestedParams()
+	if err != nil {
+		return transactions.Transaction{}, err
 	}
-	return goOfflineTransaction, nil
+
+	cparams, ok := config.Consensus[protocol.ConsensusVersion(params.ConsensusVersion)]
+	if !ok {
+		return transactions.Transaction{}, errors.New("unknown consensus version")
+	}
+
+	if firstValid == 0 {
+		firstValid = params.LastRound + 1
+	}
+	if lastValid == 0 {
+		lastValid = firstValid + cparams.MaxTxnLife
+	}
+
+	tx.Header.Sender = parsedAddr
+	tx.Header.Fee = basics.MicroAlgos{Raw: fee}
+	tx.Header.FirstValid = basics.Round(firstValid)
+	tx.Header.LastValid = basics.Round(lastValid)
+	tx.Header.GenesisID = params.GenesisID
+	if cparams.SupportGenesisHash {
+		copy(tx.Header.GenesisHash[:], params.GenesisHash)
+	}
+
+	// Default to the suggested fee, if the caller didn't supply it
+	// Fee is tricky, should taken care last. We encode the final transaction to get the size post signing and encoding
+	// Then, we multiply it by the suggested fee per byte.
+	if fee == 0 {
+		tx.Fee = basics.MulAIntSaturate(basics.MicroAlgos{Raw: params.Fee}, tx.EstimateEncodedSize())
+	}
+	if tx.Fee.Raw < cparams.MinTxnFee {
+		tx.Fee.Raw = cparams.MinTxnFee
+	}
+
+	// Recompute the TXID
+	tx.ResetCaches()
+	return tx, nil
 }
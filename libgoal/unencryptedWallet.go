@@ -35,7 +35,7 @@ func (c *Client) SendPaymentFromUnencryptedWallet(from, to string, fee, amount u
 		return transactions.Transaction{}, err
 	}
 
-	return c.SendPaymentFromWallet(wh, nil, from, to, fee, amount, note, "")
+	return c.SendPaymentFromWallet(wh, nil, from, to, fee, amount, note, "", [32]byte{})
 }
 
 // GetUnencryptedWalletHandle returns the unencrypted wallet handle. If there
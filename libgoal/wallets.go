@@ -26,6 +26,7 @@ import (
 
 const (
 	defaultWalletDriver = "sqlite"
+	ledgerWalletDriver  = "ledger"
 )
 
 // CreateWallet creates a kmd wallet with the specified parameters
@@ -79,6 +80,21 @@ func (c *Client) WalletIsUnencrypted(wid []byte) bool {
 	return true
 }
 
+// WalletIsHardware checks if the wallet behind the passed handle keeps its keys
+// on a hardware device, where every signature has to be approved. It returns
+// false if the wallet can't be looked up.
+func (c *Client) WalletIsHardware(walletHandle []byte) bool {
+	kmd, err := c.ensureKmdClient()
+	if err != nil {
+		return false
+	}
+	resp, err := kmd.GetWalletInfo(walletHandle)
+	if err != nil {
+		return false
+	}
+	return resp.WalletHandle.Wallet.DriverName == ledgerWalletDriver
+}
+
 // GetWalletHandleTokenCached first checks the cache for a valid token for this wallet
 // and renews it if possible. If there aren't any valid cached tokens, it generates
 // a new one and adds it to the cache.
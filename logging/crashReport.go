@@ -0,0 +1,274 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand/config"
+)
+
+// CrashReportsDirName is the name of the directory, in the node data directory, holding the crash reports
+const CrashReportsDirName = "crashreports"
+
+// crashReportsSentDirName is the name of the directory the crash reports are moved into once uploaded
+const crashReportsSentDirName = "sent"
+
+// crashLogTailSize is the size of the node log tail included in the crash reports
+const crashLogTailSize = 64 * 1024
+
+// crashStderrSizeLimit is the size limit of the stderr output included in the crash reports
+const crashStderrSizeLimit = 1024 * 1024
+
+// CrashReport is the information captured when the node panics, helping with its triage.
+type CrashReport struct {
+	Time time.Time
+	// Source is "panic" when the report was captured by the node as it panicked, and "stderr" when it was
+	// recovered from the node error output after the node died.
+	Source            string
+	Version           string
+	ConfigFingerprint string
+	Message           string
+	Stack             string
+	LogTail           string
+}
+
+// CrashReportFile is a crash report stored in the node data directory.
+type CrashReportFile struct {
+	Path   string
+	Sent   bool
+	Report CrashReport
+}
+
+var crashReports struct {
+	mu       sync.Mutex
+	dataDir  string
+	captured bool
+}
+
+// EnableCrashReports makes the node store a crash report into the given data directory when it panics.
+func EnableCrashReports(dataDir string) {
+	crashReports.mu.Lock()
+	defer crashReports.mu.Unlock()
+	crashReports.dataDir = dataDir
+}
+
+// CaptureCrash stores a crash report if the calling goroutine is panicking, and then resumes panicking.
+// It has to be deferred directly, such as defer logging.CaptureCrash()
+func CaptureCrash() {
+	if r := recover(); r != nil {
+		captureCrash(r, debug.Stack())
+		panic(r)
+	}
+}
+
+// captureCrash stores a crash report for the given panic, unless crash reports aren't enabled or a report was
+// already captured by this process.
+func captureCrash(r interface{}, stack []byte) {
+	crashReports.mu.Lock()
+	defer crashReports.mu.Unlock()
+	if crashReports.dataDir == "" || crashReports.captured {
+		return
+	}
+	crashReports.captured = true
+	report := makeCrashReport(crashReports.dataDir, "panic", fmt.Sprintf("%v", r), string(stack))
+	if _, err := saveCrashReport(crashReports.dataDir, report); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to save crash report: %v\n", err)
+	}
+}
+
+// SaveStderrCrashReport stores a crash report for the node that wrote the given error output, if it died of a panic
+// or a fatal runtime error. It returns whether a report was saved.
+func SaveStderrCrashReport(dataDir string, stderrPath string) (bool, error) {
+	f, err := os.Open(stderrPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+	output, err := ioutil.ReadAll(io.LimitReader(f, crashStderrSizeLimit))
+	if err != nil {
+		return false, err
+	}
+	start := bytes.Index(output, []byte("panic: "))
+	if fatal := bytes.Index(output, []byte("fatal error: ")); start < 0 || (fatal >= 0 && fatal < start) {
+		start = fatal
+	}
+	if start < 0 {
+		return false, nil
+	}
+	stack := string(output[start:])
+	message := strings.SplitN(stack, "\n", 2)[0]
+	report := makeCrashReport(dataDir, "stderr", message, stack)
+	if stat, err := f.Stat(); err == nil {
+		report.Time = stat.ModTime().UTC()
+	}
+	_, err = saveCrashReport(dataDir, report)
+	return err == nil, err
+}
+
+func makeCrashReport(dataDir string, source string, message string, stack string) CrashReport {
+	version := config.GetCurrentVersion()
+	return CrashReport{
+		Time:              time.Now().UTC(),
+		Source:            source,
+		Version:           fmt.Sprintf("%s.%s [%s] (commit #%s)", version.String(), version.Channel, version.Branch, version.GetCommitHash()),
+		ConfigFingerprint: configFingerprint(dataDir),
+		Message:           message,
+		Stack:             stack,
+		LogTail:           logTail(filepath.Join(dataDir, "node.log"), crashLogTailSize),
+	}
+}
+
+// configFingerprint identifies the node configuration, without disclosing it.
+func configFingerprint(dataDir string) string {
+	data, err := ioutil.ReadFile(filepath.Join(dataDir, config.ConfigFilename))
+	if err != nil {
+		return "default"
+	}
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:8])
+}
+
+// logTail returns up to size bytes of the end of the given file, starting at a line boundary.
+func logTail(path string, size int64) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return ""
+	}
+	offset := stat.Size() - size
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, stat.Size()-offset)
+	n, _ := f.ReadAt(tail, offset)
+	tail = tail[:n]
+	if offset > 0 {
+		if newline := bytes.IndexByte(tail, '\n'); newline >= 0 {
+			tail = tail[newline+1:]
+		}
+	}
+	return string(tail)
+}
+
+func saveCrashReport(dataDir string, report CrashReport) (string, error) {
+	dir := filepath.Join(dataDir, CrashReportsDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s.json", report.Time.Format("20060102T150405.000000000Z")))
+	return path, ioutil.WriteFile(path, data, 0600)
+}
+
+// ListCrashReports returns the crash reports stored in the given data directory, oldest first.
+func ListCrashReports(dataDir string) (reports []CrashReportFile, err error) {
+	dir := filepath.Join(dataDir, CrashReportsDirName)
+	for _, sent := range []bool{false, true} {
+		pattern := filepath.Join(dir, "crash-*.json")
+		if sent {
+			pattern = filepath.Join(dir, crashReportsSentDirName, "crash-*.json")
+		}
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			file := CrashReportFile{Path: path, Sent: sent}
+			if err = json.Unmarshal(data, &file.Report); err != nil {
+				return nil, fmt.Errorf("invalid crash report %s: %v", path, err)
+			}
+			reports = append(reports, file)
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Report.Time.Before(reports[j].Report.Time)
+	})
+	return reports, nil
+}
+
+// SendCrashReports uploads the crash reports of the given data directory that weren't sent yet, prefixing their
+// names with the given one, such as the telemetry GUID. The uploaded reports are kept in the sent subdirectory.
+func SendCrashReports(dataDir string, namePrefix string) (sent int, err error) {
+	reports, err := ListCrashReports(dataDir)
+	if err != nil {
+		return 0, err
+	}
+	var s3 s3Helper
+	for _, report := range reports {
+		if report.Sent {
+			continue
+		}
+		if s3.session == nil {
+			if s3, err = makeS3SessionForUpload(); err != nil {
+				return sent, err
+			}
+		}
+		if err = uploadCrashReport(s3, report.Path, namePrefix); err != nil {
+			return sent, err
+		}
+		sentDir := filepath.Join(dataDir, CrashReportsDirName, crashReportsSentDirName)
+		if err = os.MkdirAll(sentDir, 0700); err != nil {
+			return sent, err
+		}
+		if err = os.Rename(report.Path, filepath.Join(sentDir, filepath.Base(report.Path))); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func uploadCrashReport(s3 s3Helper, path string, namePrefix string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	name := filepath.Base(path)
+	if namePrefix != "" {
+		name = namePrefix + "_" + name
+	}
+	return s3.uploadFileStream(name, f)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureCrash(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crashreports")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer func() {
+		crashReports.dataDir = ""
+		crashReports.captured = false
+	}()
+
+	var logLines []string
+	for i := 0; i < 10000; i++ {
+		logLines = append(logLines, "log line")
+	}
+	logLines = append(logLines, "last log line")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "node.log"), []byte(strings.Join(logLines, "\n")), 0600))
+
+	EnableCrashReports(dir)
+	l := NewLogger()
+	l.SetOutput(ioutil.Discard)
+	require.Panics(t, func() {
+		defer CaptureCrash()
+		l.Panicf("something went wrong")
+	})
+
+	// the logger captured the crash, so CaptureCrash didn't store a second report
+	reports, err := ListCrashReports(dir)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	report := reports[0].Report
+	require.False(t, reports[0].Sent)
+	require.Equal(t, "panic", report.Source)
+	require.Contains(t, report.Message, "something went wrong")
+	require.Contains(t, report.Stack, "TestCaptureCrash")
+	require.Equal(t, "default", report.ConfigFingerprint)
+	require.True(t, strings.HasSuffix(report.LogTail, "last log line"))
+	require.True(t, len(report.LogTail) <= crashLogTailSize)
+	require.True(t, strings.HasPrefix(report.LogTail, "log line"))
+}
+
+func TestSaveStderrCrashReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crashreports")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	stderrPath := filepath.Join(dir, "algod-err.log")
+	saved, err := SaveStderrCrashReport(dir, stderrPath)
+	require.NoError(t, err)
+	require.False(t, saved)
+
+	require.NoError(t, ioutil.WriteFile(stderrPath, []byte("some warning\n"), 0600))
+	saved, err = SaveStderrCrashReport(dir, stderrPath)
+	require.NoError(t, err)
+	require.False(t, saved)
+
+	output := "some warning\npanic: runtime error: invalid memory address or nil pointer dereference\n\ngoroutine 1 [running]:\nmain.main()\n"
+	require.NoError(t, ioutil.WriteFile(stderrPath, []byte(output), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"EnableCrashReports": true}`), 0600))
+	saved, err = SaveStderrCrashReport(dir, stderrPath)
+	require.NoError(t, err)
+	require.True(t, saved)
+
+	reports, err := ListCrashReports(dir)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	report := reports[0].Report
+	require.Equal(t, "stderr", report.Source)
+	require.Equal(t, "panic: runtime error: invalid memory address or nil pointer dereference", report.Message)
+	require.True(t, strings.HasPrefix(report.Stack, "panic: "))
+	require.Contains(t, report.Stack, "goroutine 1 [running]")
+	require.NotEqual(t, "default", report.ConfigFingerprint)
+}
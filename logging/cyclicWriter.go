@@ -17,12 +17,30 @@
 package logging
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/algorand/go-deadlock"
 )
 
+// LogRotationPolicy controls when the live log file is rotated, and which of the rotated files are kept.
+type LogRotationPolicy struct {
+	// MaxSize is the size limit of the live log file, in bytes. The file is rotated once a write would exceed it.
+	MaxSize uint64
+	// MaxFiles is the number of rotated files to keep. Zero keeps a single rotated file.
+	MaxFiles int
+	// MaxAge is the age after which rotated files are deleted. Zero keeps them regardless of their age.
+	MaxAge time.Duration
+	// Compress makes the rotated files gzip compressed.
+	Compress bool
+}
+
 // CyclicFileWriter implements the io.Writer interface and wraps an underlying file.
 // It ensures that the file never grows over a limit.
 type CyclicFileWriter struct {
@@ -31,12 +49,24 @@ type CyclicFileWriter struct {
 	liveLog   string
 	archive   string
 	nextWrite uint64
-	limit     uint64
+	policy    LogRotationPolicy
+
+	// compressing is used for waiting on the compression of the previously rotated file.
+	compressing sync.WaitGroup
 }
 
 // MakeCyclicFileWriter returns a writer that wraps a file to ensure it never grows too large
 func MakeCyclicFileWriter(liveLogFilePath string, archiveFilePath string, sizeLimitBytes uint64) *CyclicFileWriter {
-	cyclic := CyclicFileWriter{writer: nil, liveLog: liveLogFilePath, archive: archiveFilePath, nextWrite: 0, limit: sizeLimitBytes}
+	return MakeCyclicFileWriterWithPolicy(liveLogFilePath, archiveFilePath, LogRotationPolicy{MaxSize: sizeLimitBytes})
+}
+
+// MakeCyclicFileWriterWithPolicy returns a writer that wraps a file, and rotates it according to the given policy.
+// The most recently rotated file is archiveFilePath, and older ones are numbered, such as node.archive.1.log
+func MakeCyclicFileWriterWithPolicy(liveLogFilePath string, archiveFilePath string, policy LogRotationPolicy) *CyclicFileWriter {
+	if policy.MaxFiles < 1 {
+		policy.MaxFiles = 1
+	}
+	cyclic := CyclicFileWriter{writer: nil, liveLog: liveLogFilePath, archive: archiveFilePath, nextWrite: 0, policy: policy}
 
 	fs, err := os.Stat(liveLogFilePath)
 	if err == nil {
@@ -51,32 +81,143 @@ func MakeCyclicFileWriter(liveLogFilePath string, archiveFilePath string, sizeLi
 	return &cyclic
 }
 
-// Write ensures the the underlying file can store an additional len(p) bytes. If there is not enough room left it seeks
-// to the beginning of the file.
+// archivePath returns the path of the rotated file with the given index, where index 0 is the most recent one.
+func (cyclic *CyclicFileWriter) archivePath(index int) string {
+	path := cyclic.archive
+	if index > 0 {
+		ext := filepath.Ext(path)
+		path = fmt.Sprintf("%s.%d%s", strings.TrimSuffix(path, ext), index, ext)
+	}
+	if cyclic.policy.Compress {
+		path += ".gz"
+	}
+	return path
+}
+
+// archivePaths returns the paths the rotated file with the given index may have. When compressing, a rotated file which
+// couldn't be compressed is kept as is, without the .gz extension.
+func (cyclic *CyclicFileWriter) archivePaths(index int) []string {
+	path := cyclic.archivePath(index)
+	if cyclic.policy.Compress {
+		return []string{path, strings.TrimSuffix(path, ".gz")}
+	}
+	return []string{path}
+}
+
+// Write ensures the the underlying file can store an additional len(p) bytes. If there is not enough room left it
+// rotates the file.
 func (cyclic *CyclicFileWriter) Write(p []byte) (n int, err error) {
 	cyclic.mu.Lock()
 	defer cyclic.mu.Unlock()
 
-	if uint64(len(p)) > cyclic.limit {
+	if uint64(len(p)) > cyclic.policy.MaxSize {
 		// there's no hope for writing this entry to the log
 		return 0, fmt.Errorf("CyclicFileWriter: input too long to write. Len = %v", len(p))
 	}
 
-	if cyclic.nextWrite+uint64(len(p)) > cyclic.limit {
+	if cyclic.nextWrite+uint64(len(p)) > cyclic.policy.MaxSize {
 		// we don't have enough space to write the entry, so archive data
-		cyclic.writer.Close()
-		var err error
-		if err = os.Rename(cyclic.liveLog, cyclic.archive); err != nil {
-			panic(fmt.Sprintf("CyclicFileWriter: cannot archive full log %v", err))
-		}
-		cyclic.writer, err = os.OpenFile(cyclic.liveLog, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
-		if err != nil {
-			panic(fmt.Sprintf("CyclicFileWriter: cannot open log file %v", err))
-		}
-		cyclic.nextWrite = 0
+		cyclic.rotate()
 	}
 	// write the data
 	n, err = cyclic.writer.Write(p)
 	cyclic.nextWrite += uint64(n)
 	return
 }
+
+// rotate archives the live log file and starts a new one. It is called with the mutex held.
+func (cyclic *CyclicFileWriter) rotate() {
+	cyclic.writer.Close()
+	cyclic.compressing.Wait()
+
+	// shift the older rotated files, dropping the oldest one
+	for _, path := range cyclic.archivePaths(cyclic.policy.MaxFiles - 1) {
+		os.Remove(path)
+	}
+	for i := cyclic.policy.MaxFiles - 2; i >= 0; i-- {
+		next := cyclic.archivePaths(i + 1)
+		for j, path := range cyclic.archivePaths(i) {
+			os.Rename(path, next[j])
+		}
+	}
+
+	var err error
+	if err = os.Rename(cyclic.liveLog, cyclic.archive); err != nil {
+		panic(fmt.Sprintf("CyclicFileWriter: cannot archive full log %v", err))
+	}
+	if cyclic.policy.Compress {
+		// compress in the background, so that logging doesn't block on it
+		cyclic.compressing.Add(1)
+		go func() {
+			defer cyclic.compressing.Done()
+			// the uncompressed file is kept on failure, to be shifted along with the compressed ones. The failure
+			// can't be logged through the logger, which may be writing to this very file.
+			if err := compressFile(cyclic.archive, cyclic.archivePath(0)); err != nil {
+				fmt.Fprintf(os.Stderr, "CyclicFileWriter: cannot compress %s: %v\n", cyclic.archive, err)
+			}
+		}()
+	}
+	cyclic.removeExpired()
+
+	cyclic.writer, err = os.OpenFile(cyclic.liveLog, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		panic(fmt.Sprintf("CyclicFileWriter: cannot open log file %v", err))
+	}
+	cyclic.nextWrite = 0
+}
+
+// removeExpired deletes the rotated files that are older than the policy allows.
+func (cyclic *CyclicFileWriter) removeExpired() {
+	if cyclic.policy.MaxAge == 0 {
+		return
+	}
+	for i := 0; i < cyclic.policy.MaxFiles; i++ {
+		for _, path := range cyclic.archivePaths(i) {
+			if path == cyclic.archive {
+				// the file just rotated, which may still be compressing
+				continue
+			}
+			fs, err := os.Stat(path)
+			if err == nil && time.Since(fs.ModTime()) > cyclic.policy.MaxAge {
+				os.Remove(path)
+			}
+		}
+	}
+}
+
+// compressFile gzips the source file into the destination file, and removes the source file once done.
+// On failure, the source file is kept as is.
+func compressFile(sourcePath string, destinationPath string) (err error) {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return
+	}
+	defer source.Close()
+
+	tempPath := destinationPath + ".tmp"
+	destination, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tempPath)
+		}
+	}()
+	gz := gzip.NewWriter(destination)
+	if _, err = io.Copy(gz, source); err != nil {
+		destination.Close()
+		return
+	}
+	if err = gz.Close(); err != nil {
+		destination.Close()
+		return
+	}
+	if err = destination.Close(); err != nil {
+		return
+	}
+	if err = os.Rename(tempPath, destinationPath); err != nil {
+		return
+	}
+	return os.Remove(sourcePath)
+}
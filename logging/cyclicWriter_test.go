@@ -17,9 +17,12 @@
 package logging
 
 import (
+	"compress/gzip"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -59,3 +62,129 @@ func TestCyclicWrite(t *testing.T) {
 		require.Equal(t, byte('A'), oldData[i])
 	}
 }
+
+func TestCyclicWriteMultipleArchives(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cyclic")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	liveFileName := filepath.Join(dir, "node.log")
+	archiveFileName := filepath.Join(dir, "node.archive.log")
+
+	cyclicWriter := MakeCyclicFileWriterWithPolicy(liveFileName, archiveFileName, LogRotationPolicy{MaxSize: 4, MaxFiles: 3})
+	for _, data := range []string{"AAAA", "BBBB", "CCCC", "DDDD", "EEEE"} {
+		n, err := cyclicWriter.Write([]byte(data))
+		require.NoError(t, err)
+		require.Equal(t, len(data), n)
+	}
+
+	for fileName, expected := range map[string]string{
+		"node.log":           "EEEE",
+		"node.archive.log":   "DDDD",
+		"node.archive.1.log": "CCCC",
+		"node.archive.2.log": "BBBB",
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, fileName))
+		require.NoError(t, err)
+		require.Equal(t, expected, string(data))
+	}
+	_, err = os.Stat(filepath.Join(dir, "node.archive.3.log"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestCyclicWriteCompressAndExpire(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cyclic")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	liveFileName := filepath.Join(dir, "node.log")
+	archiveFileName := filepath.Join(dir, "node.archive.log")
+
+	policy := LogRotationPolicy{MaxSize: 4, MaxFiles: 2, MaxAge: time.Hour, Compress: true}
+	cyclicWriter := MakeCyclicFileWriterWithPolicy(liveFileName, archiveFileName, policy)
+	for _, data := range []string{"AAAA", "BBBB", "CCCC"} {
+		_, err := cyclicWriter.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	cyclicWriter.compressing.Wait()
+
+	readCompressed := func(fileName string) string {
+		f, err := os.Open(filepath.Join(dir, fileName))
+		require.NoError(t, err)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(gz)
+		require.NoError(t, err)
+		return string(data)
+	}
+	require.Equal(t, "BBBB", readCompressed("node.archive.log.gz"))
+	require.Equal(t, "AAAA", readCompressed("node.archive.1.log.gz"))
+	_, err = os.Stat(archiveFileName)
+	require.True(t, os.IsNotExist(err))
+
+	// expired rotated files are removed on the next rotation
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "node.archive.log.gz"), old, old))
+	_, err = cyclicWriter.Write([]byte("DDDD"))
+	require.NoError(t, err)
+	cyclicWriter.compressing.Wait()
+	require.Equal(t, "CCCC", readCompressed("node.archive.log.gz"))
+	_, err = os.Stat(filepath.Join(dir, "node.archive.1.log.gz"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestCyclicWriteCompressFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cyclic")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	liveFileName := filepath.Join(dir, "node.log")
+	archiveFileName := filepath.Join(dir, "node.archive.log")
+
+	// compression fails while its temporary file can't be created
+	blocker := filepath.Join(dir, "node.archive.log.gz.tmp")
+	require.NoError(t, os.Mkdir(blocker, 0755))
+
+	policy := LogRotationPolicy{MaxSize: 4, MaxFiles: 3, Compress: true}
+	cyclicWriter := MakeCyclicFileWriterWithPolicy(liveFileName, archiveFileName, policy)
+	for _, data := range []string{"AAAA", "BBBB", "CCCC"} {
+		_, err := cyclicWriter.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	cyclicWriter.compressing.Wait()
+
+	readFile := func(fileName string) string {
+		data, err := ioutil.ReadFile(filepath.Join(dir, fileName))
+		require.NoError(t, err)
+		return string(data)
+	}
+	// the files which couldn't be compressed are kept, and rotated along
+	require.Equal(t, "BBBB", readFile("node.archive.log"))
+	require.Equal(t, "AAAA", readFile("node.archive.1.log"))
+
+	require.NoError(t, os.Remove(blocker))
+	_, err = cyclicWriter.Write([]byte("DDDD"))
+	require.NoError(t, err)
+	cyclicWriter.compressing.Wait()
+
+	f, err := os.Open(filepath.Join(dir, "node.archive.log.gz"))
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, "CCCC", string(data))
+	require.Equal(t, "BBBB", readFile("node.archive.1.log"))
+	require.Equal(t, "AAAA", readFile("node.archive.2.log"))
+	_, err = os.Stat(archiveFileName)
+	require.True(t, os.IsNotExist(err))
+
+	// the oldest file is dropped on the next rotation, compressed or not
+	_, err = cyclicWriter.Write([]byte("EEEE"))
+	require.NoError(t, err)
+	cyclicWriter.compressing.Wait()
+	require.Equal(t, "BBBB", readFile("node.archive.2.log"))
+	_, err = os.Stat(filepath.Join(dir, "node.archive.1.log"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "node.archive.1.log.gz"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/algorand/go-deadlock"
+	"github.com/sirupsen/logrus"
+)
+
+// warningDeduplicator suppresses the warnings that repeat an identical warning logged within the last window, and
+// logs a "repeated N times" summary of the suppressed ones once the window ends.
+type warningDeduplicator struct {
+	mu       deadlock.Mutex
+	window   time.Duration
+	entry    *logrus.Entry
+	warnings map[string]*duplicateWarning
+	timer    *time.Timer
+}
+
+type duplicateWarning struct {
+	// logged is the time the warning, or its last summary, was logged at
+	logged     time.Time
+	suppressed uint64
+	subsystem  string
+}
+
+// DuplicateWarningsObserver is notified of every warning suppressed by deduplication, and of every summary logged
+// for the suppressed warnings, so that they could be counted as metrics.
+type DuplicateWarningsObserver interface {
+	WarningSuppressed(subsystem string)
+	SummaryLogged(subsystem string, suppressed uint64)
+}
+
+var duplicateWarningsObserver atomic.Value
+
+// SetDuplicateWarningsObserver sets the observer notified of the warnings suppressed by deduplication.
+func SetDuplicateWarningsObserver(observer DuplicateWarningsObserver) {
+	duplicateWarningsObserver.Store(&observer)
+}
+
+func getDuplicateWarningsObserver() DuplicateWarningsObserver {
+	if observer, ok := duplicateWarningsObserver.Load().(*DuplicateWarningsObserver); ok {
+		return *observer
+	}
+	return nil
+}
+
+func makeWarningDeduplicator(entry *logrus.Entry, window time.Duration) *warningDeduplicator {
+	return &warningDeduplicator{
+		window:   window,
+		entry:    entry,
+		warnings: make(map[string]*duplicateWarning),
+	}
+}
+
+// allow returns whether the given warning should be logged, or counted as a repetition.
+func (d *warningDeduplicator) allow(message string, subsystem string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	key := subsystem + "\x00" + message
+	if d.timer == nil {
+		d.timer = time.AfterFunc(d.window, d.summarize)
+	}
+	w, ok := d.warnings[key]
+	if !ok || (w.suppressed == 0 && now.Sub(w.logged) >= d.window) {
+		d.warnings[key] = &duplicateWarning{logged: now, subsystem: subsystem}
+		return true
+	}
+	w.suppressed++
+	if observer := getDuplicateWarningsObserver(); observer != nil {
+		observer.WarningSuppressed(subsystem)
+	}
+	return false
+}
+
+// summarize logs a summary of every warning whose repetitions were suppressed for a whole window, and forgets the
+// warnings that weren't repeated within the last window. It reschedules itself for as long as any warning is
+// remembered.
+func (d *warningDeduplicator) summarize() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.timer = nil
+	now := time.Now()
+	var next time.Time
+	for key, w := range d.warnings {
+		due := w.logged.Add(d.window)
+		if due.After(now) {
+			if next.IsZero() || due.Before(next) {
+				next = due
+			}
+			continue
+		}
+		if w.suppressed == 0 {
+			delete(d.warnings, key)
+			continue
+		}
+		entry := d.entry.WithFields(logrus.Fields{
+			"repeated": w.suppressed,
+			"window":   d.window.String(),
+		})
+		if w.subsystem != "" {
+			entry = entry.WithField(SubsystemField, w.subsystem)
+		}
+		message := key[len(w.subsystem)+1:]
+		entry.Warnf("%s (repeated %d times in the last %v)", message, w.suppressed, now.Sub(w.logged).Round(time.Second))
+		if observer := getDuplicateWarningsObserver(); observer != nil {
+			observer.SummaryLogged(w.subsystem, w.suppressed)
+		}
+		w.suppressed = 0
+		w.logged = now
+		if due = now.Add(d.window); next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	if !next.IsZero() {
+		d.timer = time.AfterFunc(next.Sub(now), d.summarize)
+	}
+}
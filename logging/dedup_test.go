@@ -0,0 +1,110 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+type testDuplicateWarningsObserver struct {
+	mu         sync.Mutex
+	suppressed map[string]int
+	summaries  map[string]uint64
+}
+
+func (o *testDuplicateWarningsObserver) WarningSuppressed(subsystem string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.suppressed[subsystem]++
+}
+
+func (o *testDuplicateWarningsObserver) SummaryLogged(subsystem string, suppressed uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.summaries[subsystem] += suppressed
+}
+
+func TestWarningDeduplication(t *testing.T) {
+	a := require.New(t)
+
+	observer := &testDuplicateWarningsObserver{suppressed: make(map[string]int), summaries: make(map[string]uint64)}
+	SetDuplicateWarningsObserver(observer)
+	defer SetDuplicateWarningsObserver(nil)
+
+	var buf lockedBuffer
+	nl := NewLogger()
+	nl.SetOutput(&buf)
+	window := 200 * time.Millisecond
+	dl := nl.WithDeduplication(window)
+	network := dl.WithSubsystem(NetworkSubsystem)
+
+	for i := 0; i < 5; i++ {
+		network.Warnf("peer %s disconnected", "r1:4160")
+	}
+	network.Warn("peer r2:4160 disconnected")
+	dl.Warnf("peer %s disconnected", "r1:4160")
+	dl.Info("informational messages are never deduplicated")
+	dl.Info("informational messages are never deduplicated")
+
+	output := buf.String()
+	a.Equal(2, strings.Count(output, "peer r1:4160 disconnected"))
+	a.Equal(1, strings.Count(output, "peer r2:4160 disconnected"))
+	a.Equal(2, strings.Count(output, "informational messages are never deduplicated"))
+	a.NotContains(output, "repeated")
+
+	time.Sleep(3 * window)
+	output = buf.String()
+	a.Equal(1, strings.Count(output, "(repeated "))
+	a.Contains(output, "peer r1:4160 disconnected (repeated 4 times")
+
+	observer.mu.Lock()
+	a.Equal(map[string]int{NetworkSubsystem: 4}, observer.suppressed)
+	a.Equal(map[string]uint64{NetworkSubsystem: 4}, observer.summaries)
+	observer.mu.Unlock()
+
+	// once the window ended, the warning is logged again
+	network.Warnf("peer %s disconnected", "r1:4160")
+	a.Equal(4, strings.Count(buf.String(), "peer r1:4160 disconnected"))
+
+	// loggers without deduplication log every warning
+	nl.Warn("not deduplicated")
+	nl.Warn("not deduplicated")
+	a.Equal(2, strings.Count(buf.String(), "not deduplicated"))
+}
@@ -35,11 +35,14 @@ logger.Info("New wallet was created")
 package logging
 
 import (
+	"fmt"
 	"io"
 	"runtime"
 	"runtime/debug"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -128,18 +131,31 @@ type Logger interface {
 	// Add one key-value to log
 	With(key string, value interface{}) Logger
 
+	// WithSubsystem returns a logger for the given subsystem, whose log level may be overridden by SetSubsystemLevel
+	WithSubsystem(subsystem string) Logger
+
 	// WithFields logs a message with specific fields
 	WithFields(Fields) Logger
 
+	// WithDeduplication returns a logger that logs each distinct warning at most once per window, and summarizes the
+	// suppressed repetitions once the window ends
+	WithDeduplication(window time.Duration) Logger
+
 	// Set the logging version (Info by default)
 	SetLevel(Level)
 
+	// GetLevel returns the logging level set by SetLevel, regardless of the subsystem level overrides
+	GetLevel() Level
+
 	// Sets the output target
 	SetOutput(io.Writer)
 
 	// Sets the logger to JSON Format
 	SetJSONFormatter()
 
+	// Sets the logger to the structured JSON format, which uses stable field names
+	SetStructuredJSONFormatter()
+
 	IsLevelEnabled(level Level) bool
 
 	// source adds file, line and function fields to the event
@@ -162,67 +178,144 @@ type Logger interface {
 
 type loggerState struct {
 	telemetry *telemetryState
+	// level is the Level set by SetLevel. The underlying logrus logger always logs at the debug level, and the
+	// filtering by level is done by logger, as it depends on the subsystem the logger belongs to.
+	level uint32
 }
 
 type logger struct {
 	entry       *logrus.Entry
 	loggerState *loggerState
+	subsystem   string
+	dedup       *warningDeduplicator
 }
 
 func (l logger) With(key string, value interface{}) Logger {
 	return logger{
-		l.entry.WithField(key, value),
-		l.loggerState,
+		entry:       l.entry.WithField(key, value),
+		loggerState: l.loggerState,
+		subsystem:   l.subsystem,
+		dedup:       l.dedup,
+	}
+}
+
+func (l logger) WithSubsystem(subsystem string) Logger {
+	return logger{
+		entry:       l.entry.WithField(SubsystemField, subsystem),
+		loggerState: l.loggerState,
+		subsystem:   subsystem,
+		dedup:       l.dedup,
+	}
+}
+
+func (l logger) WithDeduplication(window time.Duration) Logger {
+	return logger{
+		entry:       l.entry,
+		loggerState: l.loggerState,
+		subsystem:   l.subsystem,
+		dedup:       makeWarningDeduplicator(l.entry, window),
+	}
+}
+
+// suppressed returns whether the given warning is suppressed as a repetition of a recently logged one.
+func (l logger) suppressed(message func() string) bool {
+	return l.dedup != nil && !l.dedup.allow(message(), l.subsystem)
+}
+
+// enabled returns whether events of the given level are logged, using the log level of the logger subsystem when
+// it is overridden.
+func (l logger) enabled(level Level) bool {
+	if l.subsystem != "" {
+		if subsystemLevel, ok := subsystemLevel(l.subsystem); ok {
+			return level <= subsystemLevel
+		}
 	}
+	return level <= l.GetLevel()
 }
 
 func (l logger) Debug(args ...interface{}) {
+	if !l.enabled(Debug) {
+		return
+	}
 	l.source().Debug(args...)
 }
 
 func (l logger) Debugln(args ...interface{}) {
+	if !l.enabled(Debug) {
+		return
+	}
 	l.source().Debugln(args...)
 }
 
 func (l logger) Debugf(format string, args ...interface{}) {
+	if !l.enabled(Debug) {
+		return
+	}
 	l.source().Debugf(format, args...)
 }
 
 func (l logger) Info(args ...interface{}) {
+	if !l.enabled(Info) {
+		return
+	}
 	l.source().Info(args...)
 }
 
 func (l logger) Infoln(args ...interface{}) {
+	if !l.enabled(Info) {
+		return
+	}
 	l.source().Infoln(args...)
 }
 
 func (l logger) Infof(format string, args ...interface{}) {
+	if !l.enabled(Info) {
+		return
+	}
 	l.source().Infof(format, args...)
 }
 
 func (l logger) Warn(args ...interface{}) {
+	if !l.enabled(Warn) || l.suppressed(func() string { return fmt.Sprint(args...) }) {
+		return
+	}
 	l.source().Warn(args...)
 }
 
 func (l logger) Warnln(args ...interface{}) {
+	if !l.enabled(Warn) || l.suppressed(func() string { return fmt.Sprint(args...) }) {
+		return
+	}
 	l.source().Warnln(args...)
 }
 
 func (l logger) Warnf(format string, args ...interface{}) {
+	if !l.enabled(Warn) || l.suppressed(func() string { return fmt.Sprintf(format, args...) }) {
+		return
+	}
 	l.source().Warnf(format, args...)
 }
 
 func (l logger) Error(args ...interface{}) {
+	if !l.enabled(Error) {
+		return
+	}
 	l.source().Errorln(stackPrefix, string(debug.Stack()))
 	l.source().Error(args...)
 }
 
 func (l logger) Errorln(args ...interface{}) {
+	if !l.enabled(Error) {
+		return
+	}
 	l.source().Errorln(stackPrefix, string(debug.Stack()))
 	l.source().Errorln(args...)
 }
 
 func (l logger) Errorf(format string, args ...interface{}) {
+	if !l.enabled(Error) {
+		return
+	}
 	l.source().Errorln(stackPrefix, string(debug.Stack()))
 	l.source().Errorf(format, args...)
 }
@@ -245,6 +338,7 @@ func (l logger) Fatalf(format string, args ...interface{}) {
 func (l logger) Panic(args ...interface{}) {
 	defer func() {
 		if r := recover(); r != nil {
+			captureCrash(r, debug.Stack())
 			l.FlushTelemetry()
 			panic(r)
 		}
@@ -256,6 +350,7 @@ func (l logger) Panic(args ...interface{}) {
 func (l logger) Panicln(args ...interface{}) {
 	defer func() {
 		if r := recover(); r != nil {
+			captureCrash(r, debug.Stack())
 			l.FlushTelemetry()
 			panic(r)
 		}
@@ -267,6 +362,7 @@ func (l logger) Panicln(args ...interface{}) {
 func (l logger) Panicf(format string, args ...interface{}) {
 	defer func() {
 		if r := recover(); r != nil {
+			captureCrash(r, debug.Stack())
 			l.FlushTelemetry()
 			panic(r)
 		}
@@ -277,17 +373,23 @@ func (l logger) Panicf(format string, args ...interface{}) {
 
 func (l logger) WithFields(fields Fields) Logger {
 	return logger{
-		l.source().WithFields(fields),
-		l.loggerState,
+		entry:       l.source().WithFields(fields),
+		loggerState: l.loggerState,
+		subsystem:   l.subsystem,
+		dedup:       l.dedup,
 	}
 }
 
 func (l logger) SetLevel(lvl Level) {
-	l.entry.Logger.Level = logrus.Level(lvl)
+	atomic.StoreUint32(&l.loggerState.level, uint32(lvl))
+}
+
+func (l logger) GetLevel() Level {
+	return Level(atomic.LoadUint32(&l.loggerState.level))
 }
 
 func (l logger) IsLevelEnabled(level Level) bool {
-	return l.entry.Logger.Level >= logrus.Level(level)
+	return l.enabled(level)
 }
 
 func (l logger) SetOutput(w io.Writer) {
@@ -310,6 +412,10 @@ func (l logger) SetJSONFormatter() {
 	l.entry.Logger.Formatter = &logrus.JSONFormatter{TimestampFormat: "2006-01-02T15:04:05.000000Z07:00"}
 }
 
+func (l logger) SetStructuredJSONFormatter() {
+	l.entry.Logger.Formatter = makeStructuredFormatter()
+}
+
 func (l logger) source() *logrus.Entry {
 	event := l.entry
 
@@ -346,9 +452,10 @@ func Base() Logger {
 // NewLogger returns a new Logger logging to out.
 func NewLogger() Logger {
 	l := logrus.New()
+	l.Level = logrus.DebugLevel
 	out := logger{
-		logrus.NewEntry(l),
-		&loggerState{},
+		entry:       logrus.NewEntry(l),
+		loggerState: &loggerState{level: uint32(Info)},
 	}
 	formatter := out.entry.Logger.Formatter
 	tf, ok := formatter.(*logrus.TextFormatter)
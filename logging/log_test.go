@@ -105,3 +105,40 @@ func TestSetJSONFormatter(t *testing.T) {
 	a.True(isJSON(bufNewLogger.String()))
 
 }
+
+func TestSetStructuredJSONFormatter(t *testing.T) {
+	a := require.New(t)
+
+	var bufNewLogger bytes.Buffer
+
+	nl := NewLogger()
+	nl.SetOutput(&bufNewLogger)
+	nl.SetStructuredJSONFormatter()
+	nl.WithFields(Fields{"Context": "sync", "Round": 5, "remote": "r1:4160", "txid": "ABC", "TxID": "DEF"}).Info("ABCDEFG")
+
+	var fields map[string]interface{}
+	a.NoError(json.Unmarshal(bufNewLogger.Bytes(), &fields))
+	a.Equal("sync", fields[SubsystemField])
+	a.Equal(float64(5), fields[RoundField])
+	a.Equal("r1:4160", fields[PeerField])
+	a.Equal("ABC", fields[TxIDField])
+	a.Equal("ABCDEFG", fields["msg"])
+	a.Equal("info", fields["level"])
+	a.NotContains(fields, "Context")
+	a.NotContains(fields, "Round")
+	a.NotContains(fields, "remote")
+	a.NotContains(fields, "TxID")
+
+	// when several aliases of a field are set, the first listed one wins, whatever the map order
+	for i := 0; i < 20; i++ {
+		bufNewLogger.Reset()
+		nl.WithFields(Fields{"txID": "C", "Txid": "B", "TxID": "A", "rnd": 2, "Round": 1}).Info("ABCDEFG")
+		fields = nil
+		a.NoError(json.Unmarshal(bufNewLogger.Bytes(), &fields))
+		a.Equal("A", fields[TxIDField])
+		a.Equal(float64(1), fields[RoundField])
+		a.NotContains(fields, "Txid")
+		a.NotContains(fields, "txID")
+		a.NotContains(fields, "rnd")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// Stable field names emitted by the structured JSON log format. Code that logs any of these values
+// should use these names, so that log aggregation systems can index them.
+const (
+	SubsystemField = "subsystem"
+	RoundField     = "round"
+	PeerField      = "peer"
+	TxIDField      = "txid"
+)
+
+// structuredFieldAliases lists the field names that are used across the code base along with their stable name. A field
+// that already uses the stable name takes precedence over its aliases, and an alias takes precedence over the ones
+// listed after it; the aliases that don't take precedence are dropped.
+var structuredFieldAliases = []struct {
+	alias  string
+	stable string
+}{
+	{"Context", SubsystemField},
+	{"Subsystem", SubsystemField},
+	{"Round", RoundField},
+	{"rnd", RoundField},
+	{"Peer", PeerField},
+	{"remote", PeerField},
+	{"TxID", TxIDField},
+	{"Txid", TxIDField},
+	{"txID", TxIDField},
+}
+
+// structuredFormatter formats entries as line-delimited JSON, renaming the aliased fields to their stable name.
+type structuredFormatter struct {
+	logrus.JSONFormatter
+}
+
+func makeStructuredFormatter() *structuredFormatter {
+	return &structuredFormatter{
+		JSONFormatter: logrus.JSONFormatter{
+			TimestampFormat: "2006-01-02T15:04:05.000000Z07:00",
+			FieldMap: logrus.FieldMap{
+				logrus.FieldKeyTime:  "time",
+				logrus.FieldKeyLevel: "level",
+				logrus.FieldKeyMsg:   "msg",
+			},
+		},
+	}
+}
+
+// Format implements the logrus.Formatter interface
+func (f *structuredFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	var data logrus.Fields
+	for _, field := range structuredFieldAliases {
+		value, has := entry.Data[field.alias]
+		if !has {
+			continue
+		}
+		if data == nil {
+			data = make(logrus.Fields, len(entry.Data))
+			for name, value := range entry.Data {
+				data[name] = value
+			}
+		}
+		delete(data, field.alias)
+		if _, exists := data[field.stable]; !exists {
+			data[field.stable] = value
+		}
+	}
+	if data != nil {
+		copied := *entry
+		copied.Data = data
+		entry = &copied
+	}
+	return f.JSONFormatter.Format(entry)
+}
@@ -0,0 +1,175 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// The subsystems whose log level can be configured independently of the node log level.
+const (
+	AgreementSubsystem = "agreement"
+	NetworkSubsystem   = "network"
+	LedgerSubsystem    = "ledger"
+	TxPoolSubsystem    = "txpool"
+	RESTSubsystem      = "rest"
+)
+
+// Subsystems lists the subsystems whose log level can be configured.
+var Subsystems = []string{AgreementSubsystem, NetworkSubsystem, LedgerSubsystem, TxPoolSubsystem, RESTSubsystem}
+
+var levelNames = map[Level]string{
+	Panic: "panic",
+	Fatal: "fatal",
+	Error: "error",
+	Warn:  "warn",
+	Info:  "info",
+	Debug: "debug",
+}
+
+// String returns the name of the log level, such as "debug"
+func (level Level) String() string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", uint32(level))
+}
+
+// ParseLevel returns the log level of the given name, such as "debug" or "warning"
+func ParseLevel(name string) (Level, error) {
+	name = strings.ToLower(name)
+	if name == "warning" {
+		name = "warn"
+	}
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level '%s'", name)
+}
+
+// subsystemLevels holds the map[string]Level of the subsystem level overrides. It is replaced rather than
+// modified, so that it could be read on every log call without locking.
+var subsystemLevels atomic.Value
+var subsystemLevelsMu sync.Mutex
+
+func init() {
+	subsystemLevels.Store(map[string]Level{})
+}
+
+func subsystemLevel(subsystem string) (level Level, ok bool) {
+	level, ok = subsystemLevels.Load().(map[string]Level)[subsystem]
+	return
+}
+
+func isSubsystem(subsystem string) bool {
+	for _, s := range Subsystems {
+		if s == subsystem {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSubsystemLevel overrides the log level of the given subsystem, so that, for instance, the agreement debug logs
+// could be enabled without enabling the debug logs of the whole node.
+func SetSubsystemLevel(subsystem string, level Level) error {
+	if !isSubsystem(subsystem) {
+		return fmt.Errorf("unknown log subsystem '%s'; the subsystems are %s", subsystem, strings.Join(Subsystems, ", "))
+	}
+	updateSubsystemLevels(func(levels map[string]Level) {
+		levels[subsystem] = level
+	})
+	return nil
+}
+
+// ClearSubsystemLevel removes the log level override of the given subsystem, which logs at the node log level again.
+func ClearSubsystemLevel(subsystem string) error {
+	if !isSubsystem(subsystem) {
+		return fmt.Errorf("unknown log subsystem '%s'; the subsystems are %s", subsystem, strings.Join(Subsystems, ", "))
+	}
+	updateSubsystemLevels(func(levels map[string]Level) {
+		delete(levels, subsystem)
+	})
+	return nil
+}
+
+// SetSubsystemLevels replaces all the subsystem log level overrides with the given ones, which map subsystem names to
+// level names, as they appear in logging.config
+func SetSubsystemLevels(levelNames map[string]string) error {
+	levels := make(map[string]Level, len(levelNames))
+	for subsystem, name := range levelNames {
+		if !isSubsystem(subsystem) {
+			return fmt.Errorf("unknown log subsystem '%s'; the subsystems are %s", subsystem, strings.Join(Subsystems, ", "))
+		}
+		level, err := ParseLevel(name)
+		if err != nil {
+			return err
+		}
+		levels[subsystem] = level
+	}
+	updateSubsystemLevels(func(current map[string]Level) {
+		for subsystem := range current {
+			delete(current, subsystem)
+		}
+		for subsystem, level := range levels {
+			current[subsystem] = level
+		}
+	})
+	return nil
+}
+
+// SubsystemLevels returns the effective log level of every subsystem, given the node log level.
+func SubsystemLevels() map[string]Level {
+	levels := make(map[string]Level, len(Subsystems))
+	for _, subsystem := range Subsystems {
+		if level, ok := subsystemLevel(subsystem); ok {
+			levels[subsystem] = level
+		} else {
+			levels[subsystem] = Base().GetLevel()
+		}
+	}
+	return levels
+}
+
+// SubsystemLevelOverrides returns the names of the subsystems whose log level is overridden, sorted.
+func SubsystemLevelOverrides() []string {
+	levels := subsystemLevels.Load().(map[string]Level)
+	subsystems := make([]string, 0, len(levels))
+	for subsystem := range levels {
+		subsystems = append(subsystems, subsystem)
+	}
+	sort.Strings(subsystems)
+	return subsystems
+}
+
+func updateSubsystemLevels(update func(levels map[string]Level)) {
+	subsystemLevelsMu.Lock()
+	defer subsystemLevelsMu.Unlock()
+	current := subsystemLevels.Load().(map[string]Level)
+	levels := make(map[string]Level, len(current)+1)
+	for subsystem, level := range current {
+		levels[subsystem] = level
+	}
+	update(levels)
+	subsystemLevels.Store(levels)
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsystemLevels(t *testing.T) {
+	defer SetSubsystemLevels(nil)
+
+	var buf bytes.Buffer
+	l := NewLogger()
+	l.SetOutput(&buf)
+	l.SetLevel(Info)
+	agreementLog := l.WithSubsystem(AgreementSubsystem)
+	networkLog := l.WithSubsystem(NetworkSubsystem)
+
+	agreementLog.Debug("agreement debug 1")
+	networkLog.Debug("network debug 1")
+	require.NotContains(t, buf.String(), "debug 1")
+
+	require.NoError(t, SetSubsystemLevel(AgreementSubsystem, Debug))
+	agreementLog.Debug("agreement debug 2")
+	agreementLog.With("round", 5).Debug("agreement debug 3")
+	networkLog.Debug("network debug 2")
+	l.Debug("node debug 2")
+	require.Contains(t, buf.String(), "agreement debug 2")
+	require.Contains(t, buf.String(), "agreement debug 3")
+	require.Contains(t, buf.String(), "subsystem=agreement")
+	require.NotContains(t, buf.String(), "network debug 2")
+	require.NotContains(t, buf.String(), "node debug 2")
+	require.True(t, agreementLog.IsLevelEnabled(Debug))
+	require.False(t, networkLog.IsLevelEnabled(Debug))
+
+	require.NoError(t, SetSubsystemLevel(NetworkSubsystem, Error))
+	networkLog.Warn("network warning")
+	require.NotContains(t, buf.String(), "network warning")
+
+	require.NoError(t, ClearSubsystemLevel(AgreementSubsystem))
+	agreementLog.Debug("agreement debug 4")
+	require.NotContains(t, buf.String(), "agreement debug 4")
+	require.Equal(t, []string{NetworkSubsystem}, SubsystemLevelOverrides())
+
+	require.Error(t, SetSubsystemLevel("consensus", Debug))
+	require.Error(t, SetSubsystemLevels(map[string]string{LedgerSubsystem: "verbose"}))
+	require.NoError(t, SetSubsystemLevels(map[string]string{LedgerSubsystem: "warning"}))
+	require.Equal(t, []string{LedgerSubsystem}, SubsystemLevelOverrides())
+	require.Equal(t, Warn, SubsystemLevels()[LedgerSubsystem])
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, level := range []Level{Panic, Fatal, Error, Warn, Info, Debug} {
+		parsed, err := ParseLevel(level.String())
+		require.NoError(t, err)
+		require.Equal(t, level, parsed)
+	}
+	_, err := ParseLevel("trace")
+	require.Error(t, err)
+}
@@ -57,6 +57,32 @@ type TelemetryConfig struct {
 	SessionGUID        string `json:"-"`
 	UserName           string
 	Password           string
+
+	// Index is the name of the elasticsearch index to send the telemetry to. Defaults to the chain ID when empty.
+	Index string
+	// APIKey, when set, authenticates with an elasticsearch API key rather than with UserName and Password.
+	APIKey string
+	// TLSSkipVerify disables the verification of the telemetry endpoint certificate.
+	TLSSkipVerify bool
+	// TLSCAFile is a PEM file of the certificate authorities to verify the telemetry endpoint certificate with,
+	// rather than the system ones.
+	TLSCAFile string
+	// SpoolMaxSize is the size limit, in bytes, of the local spool holding the telemetry that couldn't be sent
+	// while the endpoint was down. Zero uses a 16MB limit.
+	SpoolMaxSize uint64
+
+	// LogRotateMaxSize is the size, in bytes, at which node.log is rotated. Zero uses the node's LogSizeLimit.
+	LogRotateMaxSize uint64
+	// LogRotateMaxFiles is the number of rotated log files to keep. Zero keeps a single one, node.archive.log
+	LogRotateMaxFiles int
+	// LogRotateMaxAgeHours is the age after which rotated log files are deleted. Zero keeps them regardless of their age.
+	LogRotateMaxAgeHours uint
+	// LogRotateCompress makes the rotated log files gzip compressed.
+	LogRotateCompress bool
+
+	// SubsystemLogLevels overrides the node log level of the given subsystems, such as {"agreement": "debug"}.
+	// The subsystems are agreement, network, ledger, txpool and rest.
+	SubsystemLogLevels map[string]string `json:",omitempty"`
 }
 
 type asyncTelemetryHook struct {
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/satori/go.uuid"
 	"github.com/sirupsen/logrus"
@@ -97,6 +98,21 @@ func (cfg TelemetryConfig) Save(configPath string) error {
 	return err
 }
 
+// LogRotationPolicy returns the rotation policy of the node log files, using the given size limit
+// unless the config overrides it.
+func (cfg TelemetryConfig) LogRotationPolicy(sizeLimitBytes uint64) LogRotationPolicy {
+	policy := LogRotationPolicy{
+		MaxSize:  sizeLimitBytes,
+		MaxFiles: cfg.LogRotateMaxFiles,
+		MaxAge:   time.Duration(cfg.LogRotateMaxAgeHours) * time.Hour,
+		Compress: cfg.LogRotateCompress,
+	}
+	if cfg.LogRotateMaxSize != 0 {
+		policy.MaxSize = cfg.LogRotateMaxSize
+	}
+	return policy
+}
+
 // getHostName returns the HostName for telemetry (GUID:Name -- :Name is optional if blank)
 func (cfg TelemetryConfig) getHostName() string {
 	hostName := cfg.GUID
@@ -0,0 +1,173 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/algorand/go-deadlock"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// telemetrySpoolFilename is the name of the file, in the data directory, holding the telemetry entries that
+	// couldn't be sent yet.
+	telemetrySpoolFilename = "telemetry.spool"
+	// defaultTelemetrySpoolSize is the spool file size limit, used unless the config overrides it.
+	defaultTelemetrySpoolSize = 16 * 1024 * 1024
+	// telemetrySpoolRetryInterval is the time to wait after a failure before trying to reach the endpoint again.
+	telemetrySpoolRetryInterval = time.Minute
+)
+
+// spooledEntry is the representation of a logrus entry in the spool file.
+type spooledEntry struct {
+	Time    time.Time
+	Level   logrus.Level
+	Message string
+	Data    logrus.Fields
+}
+
+// telemetrySpoolHook wraps the telemetry endpoint hook. Entries that can't be sent, since the endpoint is down or
+// since the hook couldn't be created at all, are appended to a spool file, and are resent once the endpoint is
+// reachable again.
+type telemetrySpoolHook struct {
+	deadlock.Mutex
+	factory     func() (logrus.Hook, error)
+	wrappedHook logrus.Hook
+	levels      []logrus.Level
+	path        string
+	maxSize     int64
+	nextAttempt time.Time
+}
+
+func makeTelemetrySpoolHook(cfg TelemetryConfig, path string, hookFactory hookFactory) *telemetrySpoolHook {
+	maxSize := int64(cfg.SpoolMaxSize)
+	if maxSize == 0 {
+		maxSize = defaultTelemetrySpoolSize
+	}
+	levels := []logrus.Level{}
+	for _, l := range logrus.AllLevels {
+		if l <= cfg.MinLogLevel {
+			levels = append(levels, l)
+		}
+	}
+	return &telemetrySpoolHook{
+		factory: func() (logrus.Hook, error) { return hookFactory(cfg) },
+		levels:  levels,
+		path:    path,
+		maxSize: maxSize,
+	}
+}
+
+// Fire is required to implement logrus hook interface
+func (hook *telemetrySpoolHook) Fire(entry *logrus.Entry) error {
+	hook.Lock()
+	defer hook.Unlock()
+
+	if time.Now().Before(hook.nextAttempt) {
+		return hook.spool(entry)
+	}
+	if hook.wrappedHook == nil {
+		wrappedHook, err := hook.factory()
+		if err != nil {
+			hook.nextAttempt = time.Now().Add(telemetrySpoolRetryInterval)
+			return hook.spool(entry)
+		}
+		hook.wrappedHook = wrappedHook
+	}
+	if err := hook.resend(); err != nil {
+		hook.nextAttempt = time.Now().Add(telemetrySpoolRetryInterval)
+		return hook.spool(entry)
+	}
+	if err := hook.wrappedHook.Fire(entry); err != nil {
+		hook.nextAttempt = time.Now().Add(telemetrySpoolRetryInterval)
+		return hook.spool(entry)
+	}
+	return nil
+}
+
+// Levels Required for logrus hook interface
+func (hook *telemetrySpoolHook) Levels() []logrus.Level {
+	return hook.levels
+}
+
+// spool appends the entry to the spool file, unless the file has reached its size limit.
+func (hook *telemetrySpoolHook) spool(entry *logrus.Entry) error {
+	data := make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		data[k] = v
+	}
+	line, err := json.Marshal(spooledEntry{Time: entry.Time, Level: entry.Level, Message: entry.Message, Data: data})
+	if err != nil {
+		return err
+	}
+	if fs, err := os.Stat(hook.path); err == nil && fs.Size()+int64(len(line))+1 > hook.maxSize {
+		telemetryDrops.Inc(nil)
+		return nil
+	}
+	f, err := os.OpenFile(hook.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// resend sends the spooled entries, and removes them from the spool file. If sending fails, the entries that weren't
+// sent are kept in the spool file.
+func (hook *telemetrySpoolHook) resend() error {
+	f, err := os.Open(hook.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var lines [][]byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, int(hook.maxSize))
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	f.Close()
+
+	for i, line := range lines {
+		var spooled spooledEntry
+		if json.Unmarshal(line, &spooled) != nil {
+			// skip corrupted entries, such as a partially written last line
+			continue
+		}
+		entry := &logrus.Entry{Time: spooled.Time, Level: spooled.Level, Message: spooled.Message, Data: spooled.Data}
+		if err = hook.wrappedHook.Fire(entry); err != nil {
+			remaining := []byte{}
+			for _, l := range lines[i:] {
+				remaining = append(append(remaining, l...), '\n')
+			}
+			ioutil.WriteFile(hook.path, remaining, 0600)
+			return err
+		}
+	}
+	return os.Remove(hook.path)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// failingTelemetryHook fails firing entries while down is set.
+type failingTelemetryHook struct {
+	mockTelemetryHook
+	down bool
+}
+
+func (h *failingTelemetryHook) Fire(entry *logrus.Entry) error {
+	if h.down {
+		return errors.New("telemetry endpoint is down")
+	}
+	return h.mockTelemetryHook.Fire(entry)
+}
+
+func TestTelemetrySpool(t *testing.T) {
+	a := require.New(t)
+	dir, err := ioutil.TempDir("", "spool")
+	a.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cfg := createTelemetryConfig()
+	cfg.MinLogLevel = logrus.InfoLevel
+	hook := &failingTelemetryHook{mockTelemetryHook: makeMockTelemetryHook(logrus.InfoLevel)}
+	created := false
+	spoolHook := makeTelemetrySpoolHook(cfg, filepath.Join(dir, telemetrySpoolFilename), func(cfg TelemetryConfig) (logrus.Hook, error) {
+		if !created {
+			created = true
+			return nil, errors.New("telemetry endpoint is unreachable")
+		}
+		return hook, nil
+	})
+	a.Equal(len(hook.levels), len(spoolHook.Levels()))
+
+	fire := func(message string) {
+		a.NoError(spoolHook.Fire(&logrus.Entry{Message: message, Level: logrus.InfoLevel, Data: logrus.Fields{"error": errors.New(message)}}))
+	}
+
+	// the hook creation fails, so the entries are spooled
+	fire("first")
+	fire("second")
+	a.False(spoolHook.nextAttempt.IsZero())
+	a.Empty(hook.entries())
+
+	// once the endpoint is reachable again, the spooled entries are resent in order
+	spoolHook.nextAttempt = time.Time{}
+	fire("third")
+	a.Equal([]string{"first", "second", "third"}, hook.entries())
+	a.Equal("first", hook.data()[0]["error"])
+	_, err = os.Stat(spoolHook.path)
+	a.True(os.IsNotExist(err))
+
+	// failing to send spools the entry, and stops sending until the retry interval passes
+	hook.down = true
+	fire("fourth")
+	hook.down = false
+	fire("fifth")
+	a.Len(hook.entries(), 3)
+	spoolHook.nextAttempt = time.Time{}
+	fire("sixth")
+	a.Equal([]string{"first", "second", "third", "fourth", "fifth", "sixth"}, hook.entries())
+
+	// entries beyond the size limit are dropped
+	spoolHook.maxSize = 150
+	spoolHook.nextAttempt = time.Now().Add(time.Hour)
+	fire("seventh")
+	fire("eighth")
+	spoolHook.nextAttempt = time.Time{}
+	fire("ninth")
+	a.Equal([]string{"first", "second", "third", "fourth", "fifth", "sixth", "seventh", "ninth"}, hook.entries())
+}
@@ -17,12 +17,19 @@
 package logging
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"time"
 
 	"github.com/olivere/elastic"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/sohlich/elogrus.v3"
 
+	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/util/metrics"
 )
 
@@ -121,16 +128,70 @@ func (hook *asyncTelemetryHook) Flush() {
 	hook.wg.Wait()
 }
 
+// apiKeyTransport authenticates the requests to the telemetry endpoint with an elasticsearch API key.
+type apiKeyTransport struct {
+	apiKey  string
+	wrapped http.RoundTripper
+}
+
+func (t *apiKeyTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.Header.Set("Authorization", "ApiKey "+t.apiKey)
+	return t.wrapped.RoundTrip(request)
+}
+
+// makeTelemetryHTTPClient creates the http client for the telemetry endpoint, according to the TLS and
+// authentication settings of the config.
+func makeTelemetryHTTPClient(cfg TelemetryConfig) (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.TLSSkipVerify}
+	if cfg.TLSCAFile != "" {
+		pem, err := ioutil.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read telemetry CA file: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates were found in telemetry CA file %s", cfg.TLSCAFile)
+		}
+	}
+	var transport http.RoundTripper = &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	if cfg.APIKey != "" {
+		transport = &apiKeyTransport{apiKey: cfg.APIKey, wrapped: transport}
+	}
+	return &http.Client{Transport: transport, Timeout: 30 * time.Second}, nil
+}
+
+// getIndex returns the elasticsearch index to send the telemetry to.
+func (cfg TelemetryConfig) getIndex() string {
+	if cfg.Index != "" {
+		return cfg.Index
+	}
+	return cfg.ChainID
+}
+
 func createElasticHook(cfg TelemetryConfig) (hook logrus.Hook, err error) {
-	client, err := elastic.NewClient(elastic.SetURL(cfg.URI),
-		elastic.SetBasicAuth(cfg.UserName, cfg.Password),
+	httpClient, err := makeTelemetryHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	options := []elastic.ClientOptionFunc{
+		elastic.SetURL(cfg.URI),
+		elastic.SetHttpClient(httpClient),
 		elastic.SetSniff(false),
-		elastic.SetGzip(true))
+		elastic.SetGzip(true),
+	}
+	if cfg.APIKey == "" {
+		options = append(options, elastic.SetBasicAuth(cfg.UserName, cfg.Password))
+	}
+	client, err := elastic.NewClient(options...)
 	if err != nil {
 		return nil, err
 	}
 	hostName := cfg.getHostName()
-	hook, err = elogrus.NewElasticHook(client, hostName, cfg.MinLogLevel, cfg.ChainID)
+	hook, err = elogrus.NewElasticHook(client, hostName, cfg.MinLogLevel, cfg.getIndex())
 	return hook, err
 }
 
@@ -140,10 +201,14 @@ func createTelemetryHook(cfg TelemetryConfig, history *logBuffer, hookFactory ho
 		return nil, fmt.Errorf("createTelemetryHook called when telemetry not enabled")
 	}
 
-	hook, err = hookFactory(cfg)
-
-	if err != nil {
-		return nil, err
+	if dataDir := config.GetCurrentVersion().DataDirectory; dataDir != "" {
+		// spool the telemetry that can't be sent while the endpoint is down
+		hook = makeTelemetrySpoolHook(cfg, filepath.Join(dataDir, telemetrySpoolFilename), hookFactory)
+	} else {
+		hook, err = hookFactory(cfg)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	filteredHook, err := newTelemetryFilteredHook(hook, cfg.ReportHistoryLevel, history, cfg.SessionGUID)
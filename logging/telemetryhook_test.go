@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -62,7 +63,7 @@ func isDefault(cfg TelemetryConfig) bool {
 	cfg.GUID = ""
 	cfg.ChainID = ""
 	defaultCfg.GUID = ""
-	return cfg == defaultCfg
+	return reflect.DeepEqual(cfg, defaultCfg)
 }
 
 func TestEnsureErrorInvalidDirectory(t *testing.T) {
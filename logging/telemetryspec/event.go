@@ -63,6 +63,12 @@ type CatchupStopEventDetails struct {
 // ShutdownEvent event
 const ShutdownEvent Event = "Shutdown"
 
+// ShutdownEventDetails contains details for the ShutdownEvent
+type ShutdownEventDetails struct {
+	StepsMs map[string]int64
+	TotalMs int64
+}
+
 // BlockAcceptedEvent event
 const BlockAcceptedEvent Event = "BlockAccepted"
 
@@ -102,6 +108,28 @@ type PartKeyRegisteredEventDetails struct {
 	LastValid  uint64
 }
 
+// PartKeyRenewedEvent event
+const PartKeyRenewedEvent Event = "PartKeyRenewed"
+
+// PartKeyRenewedEventDetails contains details for the PartKeyRenewedEvent
+type PartKeyRenewedEventDetails struct {
+	Address       string
+	FirstValid    uint64
+	LastValid     uint64
+	PrevLastValid uint64
+	TxID          string
+}
+
+// PartKeyRenewalFailedEvent event
+const PartKeyRenewalFailedEvent Event = "PartKeyRenewalFailed"
+
+// PartKeyRenewalFailedEventDetails contains details for the PartKeyRenewalFailedEvent
+type PartKeyRenewalFailedEventDetails struct {
+	Address       string
+	PrevLastValid uint64
+	Error         string
+}
+
 // BlockProposedEvent event
 const BlockProposedEvent Event = "BlockProposed"
 
@@ -219,3 +247,23 @@ type BlockStatsEventDetails struct {
 	AgreementDurationMs uint64
 	NetworkDowntimeMs   uint64
 }
+
+// DiskSpaceLowEvent event
+const DiskSpaceLowEvent Event = "DiskSpaceLow"
+
+// DiskSpaceLowEventDetails contains details for DiskSpaceLowEvent
+type DiskSpaceLowEventDetails struct {
+	FreeBytes      uint64
+	ThresholdBytes uint64
+	Low            bool
+}
+
+// SafeModeEvent event
+const SafeModeEvent Event = "SafeMode"
+
+// SafeModeEventDetails contains details for SafeModeEvent
+type SafeModeEventDetails struct {
+	FreeBytes      uint64
+	ThresholdBytes uint64
+	Enabled        bool
+}
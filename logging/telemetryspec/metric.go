@@ -49,6 +49,13 @@ type AssembleBlockStats struct {
 	StopReason     string
 	TotalLength    uint64
 	Nanoseconds    int64
+	// BudgetNanoseconds is the time the payset selection was allowed to take, leaving the rest of the
+	// assembly time for generating the block
+	BudgetNanoseconds int64
+	// FinalizeNanoseconds is the time generating the block out of the selected payset took
+	FinalizeNanoseconds int64
+	// DeadlineMissed is set when the block was assembled after the proposal deadline
+	DeadlineMissed bool
 }
 
 // AssembleBlockTimeout represents AssemblePayset exiting due to timeout
@@ -42,3 +42,32 @@ func TestSaveNetworkCfg(t *testing.T) {
 	cfg1, err := loadNetworkCfg(cfgFile)
 	a.Equal(cfg, cfg1)
 }
+
+func TestSaveSnapshot(t *testing.T) {
+	a := require.New(t)
+
+	snapshot := NetworkSnapshot{
+		Name:   "testName",
+		Rounds: map[string]uint64{"Primary": 12, "Node": 11},
+	}
+
+	tmpFolder, _ := ioutil.TempDir("", "tmp")
+	defer os.RemoveAll(tmpFolder)
+	snapshotFile := filepath.Join(tmpFolder, snapshotFileName)
+	err := saveSnapshot(snapshot, snapshotFile)
+	a.NoError(err)
+	snapshot1, err := loadSnapshot(snapshotFile)
+	a.NoError(err)
+	a.Equal(snapshot, snapshot1)
+}
+
+func TestIsSubDir(t *testing.T) {
+	a := require.New(t)
+
+	a.True(isSubDir("/tmp/net", "/tmp/net"))
+	a.True(isSubDir("/tmp/net", "/tmp/net/snapshot"))
+	a.True(isSubDir("/tmp/net/", "/tmp/net/a/../snapshot"))
+	a.False(isSubDir("/tmp/net", "/tmp/net-snapshot"))
+	a.False(isSubDir("/tmp/net", "/tmp"))
+	a.False(isSubDir("/tmp/net", "/tmp/..net"))
+}
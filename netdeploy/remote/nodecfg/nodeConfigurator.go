@@ -29,16 +29,20 @@ import (
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/netdeploy/remote"
 	"github.com/algorand/go-algorand/tools/network/cloudflare"
+	"github.com/algorand/go-algorand/tools/network/route53"
 	"github.com/algorand/go-algorand/util"
 )
 
 type nodeConfigurator struct {
 	config           remote.HostConfig
+	dnsHostName      string
 	dnsName          string
 	genesisFile      string
 	genesisData      bookkeeping.Genesis
 	relayEndpoints   []srvEntry
 	metricsEndpoints []srvEntry
+	dnsProvider      string
+	dnsDryRun        bool
 }
 
 type srvEntry struct {
@@ -46,13 +50,32 @@ type srvEntry struct {
 	port    string
 }
 
+// DNSOptions controls the registration of the host DNS / SRV records.
+type DNSOptions struct {
+	// HostName is the name the host records are registered under; it defaults to the name of the host configuration,
+	// and differs from it when a shared host configuration is applied to several hosts.
+	HostName string
+	// PublicAddress is the public name or IP address the host records point to.
+	PublicAddress string
+	// Provider is the DNS provider to register the records with (DNSProviderCloudflare when empty).
+	Provider string
+	// DryRun prints the record changes instead of applying them.
+	DryRun bool
+}
+
 // ApplyConfigurationToHost attempts to apply the provided configuration to the local host,
 // based on the configuration specified for the provided hostName, with node
-// directories being created / updated under the specified rootNodeDir
-func ApplyConfigurationToHost(cfg remote.HostConfig, rootConfigDir, rootNodeDir string, dnsName string) (err error) {
+// directories being created / updated under the specified rootNodeDir.
+func ApplyConfigurationToHost(cfg remote.HostConfig, rootConfigDir, rootNodeDir string, dns DNSOptions) (err error) {
 	nc := nodeConfigurator{
-		config:  cfg,
-		dnsName: dnsName,
+		config:      cfg,
+		dnsHostName: dns.HostName,
+		dnsName:     dns.PublicAddress,
+		dnsProvider: dns.Provider,
+		dnsDryRun:   dns.DryRun,
+	}
+	if nc.dnsHostName == "" {
+		nc.dnsHostName = cfg.Name
 	}
 
 	return nc.apply(rootConfigDir, rootNodeDir)
@@ -144,23 +167,20 @@ func (nc *nodeConfigurator) prepareNodeDirs(configs []remote.NodeConfig, rootCon
 }
 
 func (nc *nodeConfigurator) registerDNSRecords() (err error) {
-	cfZoneID, cfEmail, cfKey, err := getClouldflareCredentials()
+	dns, err := makeDNSRegistrar(nc.dnsProvider, nc.dnsDryRun)
 	if err != nil {
 		return fmt.Errorf("error getting DNS credentials: %v", err)
 	}
 
-	cloudflareDNS := cloudflare.NewDNS(cfZoneID, cfEmail, cfKey)
-
 	const priority = 1
 	const weight = 1
 	const relayBootstrap = "_algobootstrap"
 	const metricsSrv = "_metrics"
-	const proxied = false
 
 	// If we need to register anything, first register a DNS entry
 	// to map our network DNS name to our public name (or IP) provided to nodecfg
 	// Network HostName = eg r1.testnet.algodev.network
-	networkHostName := nc.config.Name + "." + string(nc.genesisData.Network) + ".algodev.network"
+	networkHostName := nc.dnsHostName + "." + string(nc.genesisData.Network) + ".algodev.network"
 	isIP := net.ParseIP(nc.dnsName) != nil
 	var recordType string
 	if isIP {
@@ -170,34 +190,133 @@ func (nc *nodeConfigurator) registerDNSRecords() (err error) {
 	}
 
 	fmt.Fprintf(os.Stdout, "...... Adding DNS Record '%s' -> '%s' .\n", networkHostName, nc.dnsName)
-	cloudflareDNS.SetDNSRecord(context.Background(), recordType, networkHostName, nc.dnsName, cloudflare.AutomaticTTL, priority, proxied)
+	dns.SetDNSRecord(context.Background(), recordType, networkHostName, nc.dnsName)
 
-	for _, entry := range nc.relayEndpoints {
-		port, parseErr := strconv.ParseInt(strings.Split(entry.port, ":")[1], 10, 64)
-		if parseErr != nil {
-			return parseErr
+	type srvRecord struct {
+		kind    string
+		service string
+		entry   srvEntry
+		port    uint
+	}
+	var records []srvRecord
+	addRecords := func(kind string, service string, entries []srvEntry) error {
+		for _, entry := range entries {
+			port, parseErr := strconv.ParseInt(strings.Split(entry.port, ":")[1], 10, 64)
+			if parseErr != nil {
+				fmt.Fprintf(os.Stdout, "Error parsing port for srv record: %s (port %v)\n", parseErr, entry)
+				return parseErr
+			}
+			records = append(records, srvRecord{kind: kind, service: service, entry: entry, port: uint(port)})
 		}
-		fmt.Fprintf(os.Stdout, "...... Adding Relay SRV Record '%s' -> '%s' .\n", entry.srvName, networkHostName)
-		err = cloudflareDNS.SetSRVRecord(context.Background(), entry.srvName, networkHostName,
-			cloudflare.AutomaticTTL, priority, uint(port), relayBootstrap, "_tcp", weight)
-		if err != nil {
-			return
+		return nil
+	}
+	if err = addRecords("Relay", relayBootstrap, nc.relayEndpoints); err != nil {
+		return
+	}
+	if err = addRecords("Metrics", metricsSrv, nc.metricsEndpoints); err != nil {
+		return
+	}
+
+	// register the SRV records concurrently, as each of them takes a few DNS API calls
+	errs := make([]error, len(records))
+	util.RunConcurrently(len(records), dnsRegistrationConcurrency, func(i int) {
+		r := records[i]
+		fmt.Fprintf(os.Stdout, "...... Adding %s SRV Record '%s' -> '%s' .\n", r.kind, r.entry.srvName, networkHostName)
+		errs[i] = dns.SetSRVRecord(context.Background(), r.entry.srvName, networkHostName, priority, r.port, r.service, "_tcp", weight)
+	})
+	failed := 0
+	for i, recordErr := range errs {
+		if recordErr != nil {
+			fmt.Fprintf(os.Stdout, "Error creating srv record: %s (%v)\n", recordErr, records[i].entry)
+			failed++
 		}
 	}
+	if failed > 0 {
+		return fmt.Errorf("failed to register %d out of %d SRV records", failed, len(records))
+	}
+	return
+}
+
+// dnsRegistrationConcurrency is the number of DNS records registered concurrently.
+const dnsRegistrationConcurrency = 8
+
+// The supported DNS providers for registering the host records.
+const (
+	DNSProviderCloudflare = "cloudflare"
+	DNSProviderRoute53    = "route53"
+)
+
+// dnsRegistrar sets the DNS records of a host, hiding the differences between the DNS providers.
+type dnsRegistrar interface {
+	SetDNSRecord(ctx context.Context, recordType string, name string, content string) error
+	SetSRVRecord(ctx context.Context, name string, target string, priority uint, port uint, service string, protocol string, weight uint) error
+}
 
-	for _, entry := range nc.metricsEndpoints {
-		port, parseErr := strconv.ParseInt(strings.Split(entry.port, ":")[1], 10, 64)
-		if parseErr != nil {
-			fmt.Fprintf(os.Stdout, "Error parsing port for srv record: %s (port %v)\n", parseErr, entry)
-			return parseErr
+type cloudflareRegistrar struct {
+	*cloudflare.DNS
+}
+
+func (r cloudflareRegistrar) SetDNSRecord(ctx context.Context, recordType string, name string, content string) error {
+	return r.DNS.SetDNSRecord(ctx, recordType, name, content, cloudflare.AutomaticTTL, 1, false)
+}
+
+func (r cloudflareRegistrar) SetSRVRecord(ctx context.Context, name string, target string, priority uint, port uint, service string, protocol string, weight uint) error {
+	return r.DNS.SetSRVRecord(ctx, name, target, cloudflare.AutomaticTTL, priority, port, service, protocol, weight)
+}
+
+type route53Registrar struct {
+	*route53.DNS
+}
+
+func (r route53Registrar) SetDNSRecord(ctx context.Context, recordType string, name string, content string) error {
+	return r.DNS.SetDNSRecord(ctx, recordType, name, content, route53.DefaultTTL)
+}
+
+func (r route53Registrar) SetSRVRecord(ctx context.Context, name string, target string, priority uint, port uint, service string, protocol string, weight uint) error {
+	return r.DNS.SetSRVRecord(ctx, name, target, route53.DefaultTTL, priority, port, service, protocol, weight)
+}
+
+// makeDNSRegistrar creates the registrar of the given DNS provider, with the provider credentials taken from the environment.
+func makeDNSRegistrar(provider string, dryRun bool) (dnsRegistrar, error) {
+	switch provider {
+	case "", DNSProviderCloudflare:
+		cfZoneID, cfEmail, cfKey, err := getClouldflareCredentials()
+		if err != nil {
+			return nil, err
 		}
-		fmt.Fprintf(os.Stdout, "...... Adding Metrics SRV Record '%s' -> '%s' .\n", entry.srvName, networkHostName)
-		err = cloudflareDNS.SetSRVRecord(context.Background(), entry.srvName, networkHostName,
-			cloudflare.AutomaticTTL, priority, uint(port), metricsSrv, "_tcp", weight)
+		dns := cloudflare.NewDNS(cfZoneID, cfEmail, cfKey)
+		dns.SetDryRun(dryRun)
+		return cloudflareRegistrar{dns}, nil
+	case DNSProviderRoute53:
+		zoneID, keyID, secretKey, err := getRoute53Credentials()
 		if err != nil {
-			fmt.Fprintf(os.Stdout, "Error creating srv record: %s (%v)\n", err, entry)
-			return
+			return nil, err
 		}
+		dns := route53.NewDNS(zoneID, keyID, secretKey)
+		dns.SetDryRun(dryRun)
+		return route53Registrar{dns}, nil
+	default:
+		return nil, fmt.Errorf("unknown DNS provider '%s'", provider)
+	}
+}
+
+// DNSProviderEnvironment returns the names of the environment variables holding the credentials of the given DNS provider.
+func DNSProviderEnvironment(provider string) []string {
+	switch provider {
+	case "", DNSProviderCloudflare:
+		return []string{"CLOUDFLARE_ZONE_ID", "CLOUDFLARE_EMAIL", "CLOUDFLARE_AUTH_KEY"}
+	case DNSProviderRoute53:
+		return []string{"ROUTE53_HOSTED_ZONE_ID", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"}
+	}
+	return nil
+}
+
+func getRoute53Credentials() (zoneID string, accessKeyID string, secretAccessKey string, err error) {
+	zoneID = os.Getenv("ROUTE53_HOSTED_ZONE_ID")
+	accessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	secretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if zoneID == "" || accessKeyID == "" || secretAccessKey == "" {
+		err = fmt.Errorf("one or more credentials missing from ENV")
 	}
 	return
 }
@@ -0,0 +1,194 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package netdeploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/libgoal"
+	"github.com/algorand/go-algorand/util"
+)
+
+const snapshotFileName = "snapshot.json"
+
+// NetworkSnapshot describes a copy of a stopped private network, from which the network can be restored
+type NetworkSnapshot struct {
+	Name string
+	// Rounds holds the latest round of the ledger of each node, keyed by the node directory
+	Rounds map[string]uint64
+}
+
+// Snapshot waits for the primary node to reach round (unless it is 0), stops the network and copies the directories
+// of all its nodes, with their ledgers, wallets and configuration, into snapshotDir, which must not exist yet.
+// The network is left stopped.
+func (n Network) Snapshot(binDir, snapshotDir string, round uint64) (snapshot NetworkSnapshot, err error) {
+	if util.FileExists(snapshotDir) {
+		return snapshot, fmt.Errorf("snapshot directory %s already exists", snapshotDir)
+	}
+	if isSubDir(n.rootDir, snapshotDir) {
+		return snapshot, fmt.Errorf("snapshot directory %s is inside the network root directory", snapshotDir)
+	}
+
+	if round != 0 {
+		err = n.waitForRound(binDir, round)
+		if err != nil {
+			return
+		}
+	}
+	n.Stop(binDir)
+
+	snapshot.Name = n.cfg.Name
+	snapshot.Rounds, err = n.nodeRounds()
+	if err != nil {
+		return
+	}
+
+	err = util.CopyFolderWithFilter(n.rootDir, snapshotDir, excludeRuntimeFiles)
+	if err != nil {
+		return
+	}
+	err = saveSnapshot(snapshot, filepath.Join(snapshotDir, snapshotFileName))
+	return
+}
+
+// RestoreNetwork stops and replaces the network deployed under rootDir, if any, with the one saved in snapshotDir.
+// The restored network is left stopped.
+func RestoreNetwork(snapshotDir, rootDir, binDir string) (n Network, snapshot NetworkSnapshot, err error) {
+	snapshot, err = loadSnapshot(filepath.Join(snapshotDir, snapshotFileName))
+	if err != nil {
+		return n, snapshot, fmt.Errorf("%s does not appear to be a network snapshot: %v", snapshotDir, err)
+	}
+
+	if util.FileExists(rootDir) {
+		// only ever wipe a directory that holds a network, so that a mistyped root directory does not lose data
+		var existing Network
+		existing, err = LoadNetwork(rootDir)
+		if err != nil {
+			return
+		}
+		err = existing.Delete(binDir)
+		if err != nil {
+			return
+		}
+	}
+
+	err = util.CopyFolderWithFilter(snapshotDir, rootDir, func(name string, info os.FileInfo) bool {
+		return name != filepath.Join(snapshotDir, snapshotFileName)
+	})
+	if err != nil {
+		return
+	}
+
+	n, err = LoadNetwork(rootDir)
+	if err != nil {
+		return
+	}
+	rounds, err := n.nodeRounds()
+	if err != nil {
+		return
+	}
+	for nodeDir, round := range snapshot.Rounds {
+		if rounds[nodeDir] != round {
+			return n, snapshot, fmt.Errorf("node %s restored at round %d rather than %d", nodeDir, rounds[nodeDir], round)
+		}
+	}
+	return
+}
+
+func (n Network) waitForRound(binDir string, round uint64) error {
+	primaryDir := n.PrimaryDataDir()
+	client, err := libgoal.MakeClientWithBinDir(binDir, primaryDir, primaryDir, libgoal.AlgodClient)
+	if err != nil {
+		return err
+	}
+	status, err := client.Status()
+	for err == nil && status.LastRound < round {
+		status, err = client.WaitForRound(status.LastRound)
+	}
+	return err
+}
+
+// nodeRounds reads the latest round of the ledger of every node of the network, which must be stopped
+func (n Network) nodeRounds() (map[string]uint64, error) {
+	rounds := make(map[string]uint64)
+	nodeDirs := append([]string{}, n.cfg.RelayDirs...)
+	for _, nodeDir := range n.nodeDirs {
+		nodeDirs = append(nodeDirs, nodeDir)
+	}
+	for _, nodeDir := range nodeDirs {
+		dataDir := n.getNodeFullPath(nodeDir)
+		genesis, err := bookkeeping.LoadGenesisFromFile(filepath.Join(dataDir, genesisFileName))
+		if err != nil {
+			return nil, err
+		}
+		hdr, err := ledger.LatestBlockHeader(filepath.Join(dataDir, genesis.ID(), config.LedgerFilenamePrefix))
+		if os.IsNotExist(err) {
+			// the node never ran
+			rounds[nodeDir] = 0
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the ledger of node %s: %v", nodeDir, err)
+		}
+		rounds[nodeDir] = uint64(hdr.Round)
+	}
+	return rounds, nil
+}
+
+// excludeRuntimeFiles leaves out the pid and address files of running algod and kmd instances
+func excludeRuntimeFiles(name string, info os.FileInfo) bool {
+	ext := filepath.Ext(name)
+	return info.IsDir() || (ext != ".pid" && ext != ".net")
+}
+
+func isSubDir(parent, dir string) bool {
+	parent, err := filepath.Abs(parent)
+	if err != nil {
+		return false
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(parent, dir)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func loadSnapshot(snapshotFile string) (snapshot NetworkSnapshot, err error) {
+	data, err := ioutil.ReadFile(snapshotFile)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &snapshot)
+	return
+}
+
+func saveSnapshot(snapshot NetworkSnapshot, snapshotFile string) error {
+	data, err := json.Marshal(&snapshot)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(snapshotFile, data, 0644)
+}
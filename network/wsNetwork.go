@@ -43,11 +43,13 @@ import (
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/logging/telemetryspec"
 	"github.com/algorand/go-algorand/protocol"
 	tools_network "github.com/algorand/go-algorand/tools/network"
 	"github.com/algorand/go-algorand/util/metrics"
+	"github.com/algorand/go-algorand/util/tracing"
 )
 
 const incomingThreads = 20
@@ -245,6 +247,8 @@ type WebsocketNetwork struct {
 	upgrader websocket.Upgrader
 
 	config config.Local
+	// configLock protects the peer caps of config that SetPeerLimits changes while the network runs
+	configLock deadlock.RWMutex
 
 	log logging.Logger
 
@@ -266,9 +270,10 @@ type WebsocketNetwork struct {
 	phonebook    Phonebook
 	dnsPhonebook ThreadsafePhonebook
 
-	GenesisID string
-	NetworkID protocol.NetworkID
-	RandomID  string
+	GenesisID   string
+	GenesisHash crypto.Digest
+	NetworkID   protocol.NetworkID
+	RandomID    string
 
 	ready     int32
 	readyChan chan struct{}
@@ -335,6 +340,17 @@ func (wn *WebsocketNetwork) PublicAddress() string {
 // if wait is true then the call blocks until the packet has actually been sent to all neighbors.
 // TODO: add `priority` argument so that we don't have to guess it based on tag
 func (wn *WebsocketNetwork) Broadcast(ctx context.Context, tag protocol.Tag, data []byte, wait bool, except Peer) error {
+	ctx, span := tracing.StartSpan(ctx, "network.Broadcast", tracing.SpanKindClient,
+		tracing.Attr("network.tag", string(tag)),
+		tracing.Attr("network.message_size", len(data)),
+		tracing.Attr("network.wait", wait))
+	defer span.End()
+	err := wn.broadcast(ctx, tag, data, wait, except)
+	span.SetError(err)
+	return err
+}
+
+func (wn *WebsocketNetwork) broadcast(ctx context.Context, tag protocol.Tag, data []byte, wait bool, except Peer) error {
 	request := broadcastRequest{tag: tag, data: data, start: time.Now()}
 	if except != nil {
 		request.except = except.(*wsPeer)
@@ -379,8 +395,14 @@ func (wn *WebsocketNetwork) Broadcast(ctx context.Context, tag protocol.Tag, dat
 
 // Relay message
 func (wn *WebsocketNetwork) Relay(ctx context.Context, tag protocol.Tag, data []byte, wait bool, except Peer) error {
+	ctx, span := tracing.StartSpan(ctx, "network.Relay", tracing.SpanKindInternal,
+		tracing.Attr("network.tag", string(tag)),
+		tracing.Attr("network.relay", wn.relayMessages))
+	defer span.End()
 	if wn.relayMessages {
-		return wn.Broadcast(ctx, tag, data, wait, except)
+		err := wn.Broadcast(ctx, tag, data, wait, except)
+		span.SetError(err)
+		return err
 	}
 	return nil
 }
@@ -487,6 +509,24 @@ func (wn *WebsocketNetwork) GetPeers(options ...PeerOption) []Peer {
 	return outPeers
 }
 
+// currentConfig returns a copy of the network config, including any peer caps changed by SetPeerLimits
+func (wn *WebsocketNetwork) currentConfig() config.Local {
+	wn.configLock.RLock()
+	defer wn.configLock.RUnlock()
+	return wn.config
+}
+
+// SetPeerLimits changes the gossip fanout, the connections allowed per IP and the broadcast connections limit
+// of the running network to the ones of cfg. Connections above the new caps aren't closed, the caps only apply
+// to the connections made and the messages broadcast from then on.
+func (wn *WebsocketNetwork) SetPeerLimits(cfg config.Local) {
+	wn.configLock.Lock()
+	defer wn.configLock.Unlock()
+	wn.config.GossipFanout = cfg.GossipFanout
+	wn.config.MaxConnectionsPerIP = cfg.MaxConnectionsPerIP
+	wn.config.BroadcastConnectionsLimit = cfg.BroadcastConnectionsLimit
+}
+
 func (wn *WebsocketNetwork) setup() {
 	wn.upgrader.ReadBufferSize = 4096
 	wn.upgrader.WriteBufferSize = 4096
@@ -767,6 +807,9 @@ func (wn *WebsocketNetwork) updateURLHost(originalRootURL string, originIP net.I
 
 // ServerHTTP handles the gossip network functions over websockets
 func (wn *WebsocketNetwork) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	_, span := tracing.StartSpan(tracing.Extract(request.Context(), request.Header), "network.Accept", tracing.SpanKindServer,
+		tracing.Attr("network.remote", request.RemoteAddr))
+	defer span.End()
 	if wn.numIncomingPeers() >= wn.config.IncomingConnectionsLimit {
 		networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "incoming_connection_limit"})
 		wn.log.EventWithDetails(telemetryspec.Network, telemetryspec.ConnectPeerFailEvent,
@@ -790,7 +833,7 @@ func (wn *WebsocketNetwork) ServeHTTP(response http.ResponseWriter, request *htt
 	if originIP != nil {
 		remoteHost = originIP.String()
 	}
-	if wn.connectedForIP(remoteHost) >= wn.config.MaxConnectionsPerIP {
+	if wn.connectedForIP(remoteHost) >= wn.currentConfig().MaxConnectionsPerIP {
 		networkConnectionsDroppedTotal.Inc(map[string]string{"reason": "incoming_connection_limit"})
 		wn.log.EventWithDetails(telemetryspec.Network, telemetryspec.ConnectPeerFailEvent,
 			telemetryspec.ConnectPeerFailEventDetails{
@@ -838,7 +881,7 @@ func (wn *WebsocketNetwork) ServeHTTP(response http.ResponseWriter, request *htt
 		prioChallenge:     challenge,
 	}
 	peer.TelemetryGUID = otherTelemetryGUID
-	peer.init(wn.config)
+	peer.init(wn.currentConfig())
 	wn.addPeer(peer)
 	localAddr, _ := wn.Address()
 	wn.log.With("event", "ConnectedIn").With("remote", otherPublicAddr).With("local", localAddr).Infof("Accepted incoming connection from peer %s", otherPublicAddr)
@@ -973,10 +1016,11 @@ func (wn *WebsocketNetwork) innerBroadcast(request broadcastRequest, prio bool,
 
 	*ppeers = wn.peerSnapshot(*ppeers)
 	peers := *ppeers
+	broadcastLimit := wn.currentConfig().BroadcastConnectionsLimit
 
 	// first send to all the easy outbound peers who don't block, get them started.
 	for pi, peer := range peers {
-		if wn.config.BroadcastConnectionsLimit >= 0 && pi >= wn.config.BroadcastConnectionsLimit {
+		if broadcastLimit >= 0 && pi >= broadcastLimit {
 			break
 		}
 		if peer == request.except {
@@ -1109,7 +1153,7 @@ func (wn *WebsocketNetwork) meshThread() {
 		} else {
 			wn.log.Debugf("got no DNS addrs for network %#v", wn.NetworkID)
 		}
-		desired := wn.config.GossipFanout
+		desired := wn.currentConfig().GossipFanout
 		numOutgoing := wn.numOutgoingPeers() + wn.numOutgoingPending()
 		need := desired - numOutgoing
 		if need > 0 {
@@ -1247,6 +1291,10 @@ func (wn *WebsocketNetwork) peersToPing() []*wsPeer {
 
 func (wn *WebsocketNetwork) getDNSAddrs() []string {
 	dnsBootstrap := wn.config.DNSBootstrap(wn.NetworkID)
+	if err := wn.verifyBootstrapMetadata(dnsBootstrap, wn.lookupTXT); err != nil {
+		wn.log.Warnf("Not using SRV records of %s: %v", dnsBootstrap, err)
+		return nil
+	}
 	srvPhonebook, err := wn.readFromBootstrap(dnsBootstrap)
 	if err != nil {
 		// only log this warning on testnet or devnet
@@ -1258,6 +1306,52 @@ func (wn *WebsocketNetwork) getDNSAddrs() []string {
 	return srvPhonebook
 }
 
+// verifyBootstrapMetadata verifies the network metadata TXT record of the given bootstrap ID, if the node was
+// configured with a metadata signer. The metadata must be for the genesis of the node.
+func (wn *WebsocketNetwork) verifyBootstrapMetadata(bootstrapID string, lookupTXT func(name string) ([]string, error)) error {
+	if bootstrapID == "" || wn.config.DNSBootstrapMetadataSigner == "" {
+		return nil
+	}
+	signer, err := basics.UnmarshalChecksumAddress(wn.config.DNSBootstrapMetadataSigner)
+	if err != nil {
+		return fmt.Errorf("invalid DNSBootstrapMetadataSigner '%s': %v", wn.config.DNSBootstrapMetadataSigner, err)
+	}
+	metadata, err := tools_network.ReadNetworkMetadata(lookupTXT, bootstrapID, crypto.PublicKey(signer))
+	if err != nil {
+		return err
+	}
+	if metadata.GenesisID != wn.GenesisID {
+		return fmt.Errorf("network metadata is for genesis %s rather than %s", metadata.GenesisID, wn.GenesisID)
+	}
+	if metadata.GenesisHash != wn.GenesisHash {
+		return fmt.Errorf("network metadata is for genesis hash %v rather than %v", metadata.GenesisHash, wn.GenesisHash)
+	}
+	if metadata.MinProtocolVersion != "" {
+		if _, has := config.Consensus[metadata.MinProtocolVersion]; !has {
+			wn.log.Warnf("network %s recommends protocol version %s which this node does not support; please upgrade", bootstrapID, metadata.MinProtocolVersion)
+		}
+	}
+	wn.log.Infof("verified network metadata of %s: catchpoints %v", bootstrapID, metadata.CatchpointLabels)
+	return nil
+}
+
+// lookupTXT looks up TXT records using the system resolver, falling back to the configured fallback DNS resolver.
+func (wn *WebsocketNetwork) lookupTXT(name string) ([]string, error) {
+	records, sysLookupErr := net.LookupTXT(name)
+	if sysLookupErr == nil {
+		return records, nil
+	}
+	var resolver tools_network.Resolver
+	if DNSIPAddr, err := net.ResolveIPAddr("ip", wn.config.FallbackDNSResolverAddress); err == nil {
+		resolver.DNSAddress = *DNSIPAddr
+	}
+	records, err := resolver.LookupTXT(context.Background(), name)
+	if err != nil {
+		return nil, fmt.Errorf("DNS LookupTXT failed when using system resolver(%v) as well as via %s due to %v", sysLookupErr, resolver.EffectiveResolverDNS(), err)
+	}
+	return records, nil
+}
+
 func (wn *WebsocketNetwork) readFromBootstrap(bootstrapID string) (addrs []string, err error) {
 	if bootstrapID == "" {
 		wn.log.Debug("no dns lookup due to empty bootstrapID")
@@ -1422,8 +1516,13 @@ func (wn *WebsocketNetwork) tryConnect(addr, gossipAddr string) {
 	wn.setHeaders(requestHeader)
 	myInstanceName := wn.log.GetInstanceName()
 	requestHeader.Set(InstanceNameHeader, myInstanceName)
-	conn, response, err := websocketDialer.DialContext(wn.ctx, gossipAddr, requestHeader)
+	ctx, span := tracing.StartSpan(wn.ctx, "network.Connect", tracing.SpanKindClient,
+		tracing.Attr("network.remote", gossipAddr))
+	defer span.End()
+	tracing.Inject(ctx, requestHeader)
+	conn, response, err := websocketDialer.DialContext(ctx, gossipAddr, requestHeader)
 	if err != nil {
+		span.SetError(err)
 		wn.log.Warnf("ws connect(%s) fail: %s", gossipAddr, err)
 		return
 	}
@@ -1434,7 +1533,7 @@ func (wn *WebsocketNetwork) tryConnect(addr, gossipAddr string) {
 	}
 	peer := &wsPeer{wsPeerCore: wsPeerCore{net: wn, rootURL: addr}, conn: conn, outgoing: true, incomingMsgFilter: wn.incomingMsgFilter}
 	peer.TelemetryGUID = otherTelemetryGUID
-	peer.init(wn.config)
+	peer.init(wn.currentConfig())
 	wn.addPeer(peer)
 	localAddr, _ := wn.Address()
 	wn.log.With("event", "ConnectedOut").With("remote", addr).With("local", localAddr).Infof("Made outgoing connection to peer %v", addr)
@@ -1542,7 +1641,7 @@ func (wn *WebsocketNetwork) addPeer(peer *wsPeer) {
 	heap.Push(peersHeap{wn}, peer)
 	wn.prioTracker.setPriority(peer, peer.prioAddress, peer.prioWeight)
 	wn.countPeersSetGauges()
-	if len(wn.peers) >= wn.config.GossipFanout {
+	if len(wn.peers) >= wn.currentConfig().GossipFanout {
 		// we have a quorum of connected peers, if we weren't ready before, we are now
 		if atomic.CompareAndSwapInt32(&wn.ready, 0, 1) {
 			wn.log.Debug("ready")
@@ -43,8 +43,10 @@ import (
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/protocol"
+	tools_network "github.com/algorand/go-algorand/tools/network"
 	"github.com/algorand/go-algorand/util/metrics"
 )
 
@@ -1354,3 +1356,38 @@ func TestWebsocketNetwork_checkHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyBootstrapMetadata(t *testing.T) {
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	secrets := crypto.GenerateSignatureSecrets(seed)
+
+	conf := defaultConfig
+	conf.DNSBootstrapMetadataSigner = basics.Address(secrets.SignatureVerifier).GetUserAddress()
+	wn := makeTestWebsocketNodeWithConfig(t, conf)
+	wn.GenesisHash = crypto.Hash([]byte("go-test-network-genesis"))
+
+	metadata := tools_network.NetworkMetadata{
+		GenesisID:   wn.GenesisID,
+		GenesisHash: wn.GenesisHash,
+	}
+	lookupMetadata := func(metadata tools_network.NetworkMetadata) func(name string) ([]string, error) {
+		return func(name string) ([]string, error) {
+			return []string{tools_network.SignNetworkMetadata(secrets, metadata).EncodeTXT()}, nil
+		}
+	}
+	require.NoError(t, wn.verifyBootstrapMetadata("devtestnet.algodev.network", lookupMetadata(metadata)))
+
+	otherGenesisID := metadata
+	otherGenesisID.GenesisID = "go-test-other-genesis"
+	require.Error(t, wn.verifyBootstrapMetadata("devtestnet.algodev.network", lookupMetadata(otherGenesisID)))
+
+	// a network reusing the genesis ID of the node for a different genesis is rejected too.
+	otherGenesisHash := metadata
+	otherGenesisHash.GenesisHash = crypto.Hash([]byte("go-test-other-genesis"))
+	require.Error(t, wn.verifyBootstrapMetadata("devtestnet.algodev.network", lookupMetadata(otherGenesisHash)))
+
+	// without a signer, the metadata isn't looked up at all.
+	wn.config.DNSBootstrapMetadataSigner = ""
+	require.NoError(t, wn.verifyBootstrapMetadata("devtestnet.algodev.network", lookupMetadata(otherGenesisHash)))
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/util/metrics"
+)
+
+var apiTransactionsRefusedTotal = metrics.MakeCounter(metrics.APITransactionsRefusedTotal)
+
+// PolicyError is the error of a transaction submitted through the APIs that the local admission policy refuses.
+// The policy only applies to the transactions of the node's own clients, not to those gossiped by its peers.
+type PolicyError struct {
+	Reason string
+}
+
+func (err PolicyError) Error() string {
+	return fmt.Sprintf("refused by the node admission policy: %s", err.Reason)
+}
+
+// admissionPolicy is the local policy the transactions submitted through the APIs must pass, on top of the
+// protocol rules, as set by the APIMinFeeMultiplier, APIMaxNoteSize and APIBannedAddresses settings
+type admissionPolicy struct {
+	minFeeMultiplier uint64
+	maxNoteSize      int
+	banned           map[basics.Address]bool
+}
+
+// makeAdmissionPolicy returns the admission policy of cfg. Invalid banned addresses are logged and skipped.
+func makeAdmissionPolicy(cfg config.Local, log logging.Logger) admissionPolicy {
+	policy := admissionPolicy{
+		minFeeMultiplier: cfg.APIMinFeeMultiplier,
+		maxNoteSize:      cfg.APIMaxNoteSize,
+	}
+	for _, entry := range strings.Split(cfg.APIBannedAddresses, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		addr, err := basics.UnmarshalChecksumAddress(entry)
+		if err != nil {
+			log.Warnf("Ignoring invalid address %s in APIBannedAddresses: %v", entry, err)
+			continue
+		}
+		if policy.banned == nil {
+			policy.banned = make(map[basics.Address]bool)
+		}
+		policy.banned[addr] = true
+	}
+	return policy
+}
+
+// check returns a PolicyError if the policy refuses tx
+func (policy admissionPolicy) check(tx transactions.Transaction, proto config.ConsensusParams) error {
+	if policy.minFeeMultiplier > 1 {
+		minFee, overflowed := basics.OMul(proto.MinTxnFee, policy.minFeeMultiplier)
+		if !overflowed && tx.Fee.Raw < minFee {
+			return PolicyError{Reason: fmt.Sprintf("fee %d below the minimum of %d", tx.Fee.Raw, minFee)}
+		}
+	}
+	if policy.maxNoteSize > 0 && len(tx.Note) > policy.maxNoteSize {
+		return PolicyError{Reason: fmt.Sprintf("note of %d bytes over the limit of %d", len(tx.Note), policy.maxNoteSize)}
+	}
+	if len(policy.banned) > 0 {
+		for _, addr := range tx.Addresses() {
+			if policy.banned[addr] {
+				return PolicyError{Reason: fmt.Sprintf("address %s is banned", addr.GetUserAddress())}
+			}
+		}
+	}
+	return nil
+}
+
+// checkAdmission applies the admission policy of the node to a transaction submitted through the APIs
+func (node *AlgorandFullNode) checkAdmission(signed transactions.SignedTxn, proto config.ConsensusParams) error {
+	node.configMu.RLock()
+	policy := node.admission
+	node.configMu.RUnlock()
+
+	err := policy.check(signed.Txn, proto)
+	if err != nil {
+		apiTransactionsRefusedTotal.Inc(nil)
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestAdmissionPolicy(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+
+	var sender, receiver, banned basics.Address
+	crypto.RandBytes(sender[:])
+	crypto.RandBytes(receiver[:])
+	crypto.RandBytes(banned[:])
+
+	tx := transactions.Transaction{
+		Type:             protocol.PaymentTx,
+		Header:           transactions.Header{Sender: sender, Fee: basics.MicroAlgos{Raw: proto.MinTxnFee}, Note: make([]byte, 100)},
+		PaymentTxnFields: transactions.PaymentTxnFields{Receiver: receiver},
+	}
+
+	// The default policy takes whatever the protocol takes
+	cfg := config.GetDefaultLocal()
+	require.NoError(t, makeAdmissionPolicy(cfg, logging.TestingLog(t)).check(tx, proto))
+
+	cfg.APIMinFeeMultiplier = 3
+	cfg.APIMaxNoteSize = 50
+	cfg.APIBannedAddresses = "not an address; " + banned.GetUserAddress() + ";"
+	policy := makeAdmissionPolicy(cfg, logging.TestingLog(t))
+	require.Len(t, policy.banned, 1)
+
+	err := policy.check(tx, proto)
+	require.IsType(t, PolicyError{}, err)
+	require.Contains(t, err.Error(), "fee")
+
+	tx.Fee.Raw = 3 * proto.MinTxnFee
+	err = policy.check(tx, proto)
+	require.IsType(t, PolicyError{}, err)
+	require.Contains(t, err.Error(), "note")
+
+	tx.Note = tx.Note[:50]
+	require.NoError(t, policy.check(tx, proto))
+
+	// the check covers every address field, whatever the transaction type
+	addressFields := []*basics.Address{
+		&tx.Sender, &tx.Receiver, &tx.CloseRemainderTo, &tx.RekeyTo,
+		&tx.ConfigAsset.Creator, &tx.AssetParams.Manager, &tx.AssetParams.Reserve, &tx.AssetParams.Freeze, &tx.AssetParams.Clawback,
+		&tx.XferAsset.Creator, &tx.AssetSender, &tx.AssetReceiver, &tx.AssetCloseTo,
+		&tx.FreezeAsset.Creator, &tx.FreezeAccount,
+	}
+	for _, field := range addressFields {
+		saved := *field
+		*field = banned
+		err = policy.check(tx, proto)
+		require.IsType(t, PolicyError{}, err)
+		require.Contains(t, err.Error(), banned.GetUserAddress())
+		*field = saved
+	}
+}
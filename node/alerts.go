@@ -0,0 +1,223 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/network"
+	"github.com/algorand/go-algorand/util"
+	"github.com/algorand/go-algorand/util/webhook"
+)
+
+// The types of the alerts posted to the webhooks. An alert is posted when its condition starts, and the matching
+// ".resolved" alert when it ends.
+const (
+	alertNodeStalled      = "node.stalled"
+	alertNodeBehind       = "node.behind"
+	alertPartKeyExpiring  = "partkey.expiring"
+	alertDiskLow          = "disk.low"
+	alertSafeMode         = "node.safemode"
+	alertPeerCountZero    = "peers.zero"
+	alertResolvedSuffix   = ".resolved"
+	alertCheckInterval    = 30 * time.Second
+	defaultAlertStallTime = 120 * time.Second
+
+	defaultAlertBehindRounds        = 100
+	defaultAlertPartKeyExpiryRounds = 200000
+	defaultAlertMinFreeDiskMB       = 1024
+
+	// expectedRoundTime is the approximate duration of a round, used for estimating how far behind the network
+	// the node is from the timestamp of its latest block.
+	expectedRoundTime = 4500 * time.Millisecond
+)
+
+// alertState is a snapshot of the node state the alert conditions are evaluated on.
+type alertState struct {
+	now                time.Time
+	lastRound          basics.Round
+	lastRoundTimestamp time.Time
+	lastBlockTime      time.Time
+	peers              int
+	freeDisk           uint64
+	freeDiskErr        error
+	safeMode           bool
+	partKeys           map[basics.Address]basics.Round
+}
+
+// alerter evaluates the alert conditions, and posts an alert whenever one of them starts or ends.
+type alerter struct {
+	send                 func(webhook.Event)
+	node                 string
+	stallTime            time.Duration
+	behindRounds         uint64
+	partKeyExpiryRounds  uint64
+	minFreeDisk          uint64
+	active               map[string]bool
+	startTime            time.Time
+	connectedPeersBefore bool
+}
+
+func makeAlerter(cfg config.Local, node string, send func(webhook.Event)) *alerter {
+	a := &alerter{
+		send:                send,
+		node:                node,
+		stallTime:           time.Duration(cfg.AlertStallSeconds) * time.Second,
+		behindRounds:        cfg.AlertBehindRounds,
+		partKeyExpiryRounds: cfg.AlertPartKeyExpiryRounds,
+		minFreeDisk:         cfg.AlertMinFreeDiskMB * 1024 * 1024,
+		active:              make(map[string]bool),
+	}
+	if a.stallTime == 0 {
+		a.stallTime = defaultAlertStallTime
+	}
+	if a.behindRounds == 0 {
+		a.behindRounds = defaultAlertBehindRounds
+	}
+	if a.partKeyExpiryRounds == 0 {
+		a.partKeyExpiryRounds = defaultAlertPartKeyExpiryRounds
+	}
+	if a.minFreeDisk == 0 {
+		a.minFreeDisk = defaultAlertMinFreeDiskMB * 1024 * 1024
+	}
+	return a
+}
+
+// check evaluates the alert conditions on the given state.
+func (a *alerter) check(s alertState) {
+	if a.startTime.IsZero() {
+		a.startTime = s.now
+	}
+
+	lastProgress := s.lastRoundTimestamp
+	if lastProgress.IsZero() {
+		lastProgress = a.startTime
+	}
+	stalledFor := s.now.Sub(lastProgress)
+	a.update(alertNodeStalled, "", stalledFor >= a.stallTime, s.lastRound,
+		fmt.Sprintf("no new round for %v", stalledFor.Round(time.Second)),
+		map[string]interface{}{"stalledSeconds": int64(stalledFor.Seconds())})
+
+	var behind uint64
+	if !s.lastBlockTime.IsZero() && s.now.After(s.lastBlockTime) {
+		behind = uint64(s.now.Sub(s.lastBlockTime) / expectedRoundTime)
+	}
+	a.update(alertNodeBehind, "", behind >= a.behindRounds, s.lastRound,
+		fmt.Sprintf("about %d rounds behind the network", behind),
+		map[string]interface{}{"roundsBehind": behind})
+
+	for address, lastValid := range s.partKeys {
+		var remaining uint64
+		if lastValid > s.lastRound {
+			remaining = uint64(lastValid - s.lastRound)
+		}
+		a.update(alertPartKeyExpiring, address.String(), remaining <= a.partKeyExpiryRounds, s.lastRound,
+			fmt.Sprintf("participation key of %s expires in %d rounds", address, remaining),
+			map[string]interface{}{"address": address.String(), "lastValid": uint64(lastValid), "roundsRemaining": remaining})
+	}
+
+	if s.freeDiskErr == nil {
+		a.update(alertDiskLow, "", s.freeDisk < a.minFreeDisk, s.lastRound,
+			fmt.Sprintf("%dMB of disk space left", s.freeDisk/(1024*1024)),
+			map[string]interface{}{"freeBytes": s.freeDisk})
+	}
+	a.update(alertSafeMode, "", s.safeMode, s.lastRound,
+		"the node is running out of disk space and refuses new transactions",
+		map[string]interface{}{"freeBytes": s.freeDisk})
+
+	// don't alert about having no peers while the node is still connecting to its first ones
+	if s.peers > 0 {
+		a.connectedPeersBefore = true
+	}
+	a.update(alertPeerCountZero, "", s.peers == 0 && (a.connectedPeersBefore || s.now.Sub(a.startTime) >= a.stallTime), s.lastRound,
+		"not connected to any peer", nil)
+}
+
+// update posts an alert when the given condition changes.
+func (a *alerter) update(alertType string, key string, condition bool, round basics.Round, message string, details map[string]interface{}) {
+	id := alertType + "/" + key
+	if condition == a.active[id] {
+		return
+	}
+	if condition {
+		a.active[id] = true
+	} else {
+		delete(a.active, id)
+		alertType += alertResolvedSuffix
+	}
+	a.send(webhook.Event{
+		Type:    alertType,
+		Time:    time.Now().UTC(),
+		Node:    a.node,
+		Round:   uint64(round),
+		Message: message,
+		Details: details,
+	})
+}
+
+// alertThread periodically evaluates the alert conditions, and posts the alerts to the configured webhooks.
+func (node *AlgorandFullNode) alertThread() {
+	dispatcher := webhook.MakeDispatcher(webhook.Config{
+		URLs:   strings.Split(node.config.AlertWebhookURLs, ";"),
+		Secret: node.config.AlertWebhookSecret,
+	}, node.log)
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	name := node.genesisID
+	if node.config.NetAddress != "" {
+		name = node.config.NetAddress + "@" + name
+	}
+	a := makeAlerter(node.config, name, dispatcher.Send)
+	ticker := time.NewTicker(alertCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.check(node.alertState())
+		case <-node.ctx.Done():
+			return
+		}
+	}
+}
+
+func (node *AlgorandFullNode) alertState() alertState {
+	s := alertState{
+		now:       time.Now(),
+		lastRound: node.ledger.LastRound(),
+		peers:     len(node.net.GetPeers(network.PeersConnectedOut, network.PeersConnectedIn)),
+		partKeys:  make(map[basics.Address]basics.Round),
+	}
+	if hdr, err := node.ledger.BlockHdr(s.lastRound); err == nil && hdr.TimeStamp > 0 {
+		s.lastBlockTime = time.Unix(hdr.TimeStamp, 0)
+	}
+	node.mu.Lock()
+	s.lastRoundTimestamp = node.lastRoundTimestamp
+	node.mu.Unlock()
+	s.freeDisk, s.freeDiskErr = util.FreeDiskSpace(node.rootDir)
+	s.safeMode = node.SafeMode()
+	for _, part := range node.accountManager.Keys() {
+		if lastValid, ok := s.partKeys[part.Address()]; !ok || part.LastValid > lastValid {
+			s.partKeys[part.Address()] = part.LastValid
+		}
+	}
+	return s
+}
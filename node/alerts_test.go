@@ -0,0 +1,100 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/util/webhook"
+)
+
+func TestAlerter(t *testing.T) {
+	var events []webhook.Event
+	a := makeAlerter(config.Local{}, "node", func(event webhook.Event) {
+		events = append(events, event)
+	})
+	eventTypes := func() (types []string) {
+		for _, event := range events {
+			types = append(types, event.Type)
+		}
+		events = nil
+		return
+	}
+
+	var address basics.Address
+	address[0] = 1
+	start := time.Now()
+	healthy := alertState{
+		now:                start,
+		lastRound:          1000,
+		lastRoundTimestamp: start,
+		lastBlockTime:      start,
+		peers:              4,
+		freeDisk:           10 * 1024 * 1024 * 1024,
+		partKeys:           map[basics.Address]basics.Round{address: 1000000},
+	}
+	a.check(healthy)
+	require.Empty(t, eventTypes())
+
+	// conditions only alert when they start and end
+	s := healthy
+	s.now = start.Add(10 * time.Minute)
+	s.peers = 0
+	s.freeDisk = 100 * 1024 * 1024
+	s.safeMode = true
+	s.partKeys = map[basics.Address]basics.Round{address: 1100}
+	a.check(s)
+	require.ElementsMatch(t, []string{alertNodeStalled, alertNodeBehind, alertPartKeyExpiring, alertDiskLow, alertSafeMode, alertPeerCountZero}, eventTypes())
+	a.check(s)
+	require.Empty(t, eventTypes())
+
+	healthy.now = s.now
+	healthy.lastRoundTimestamp = s.now
+	healthy.lastBlockTime = s.now
+	a.check(healthy)
+	require.ElementsMatch(t, []string{
+		alertNodeStalled + alertResolvedSuffix,
+		alertNodeBehind + alertResolvedSuffix,
+		alertPartKeyExpiring + alertResolvedSuffix,
+		alertDiskLow + alertResolvedSuffix,
+		alertSafeMode + alertResolvedSuffix,
+		alertPeerCountZero + alertResolvedSuffix,
+	}, eventTypes())
+}
+
+func TestAlerterWaitsForFirstPeers(t *testing.T) {
+	var events []webhook.Event
+	a := makeAlerter(config.Local{AlertStallSeconds: 60}, "node", func(event webhook.Event) {
+		events = append(events, event)
+	})
+	start := time.Now()
+	s := alertState{now: start, lastRoundTimestamp: start, lastBlockTime: start, freeDisk: 10 * 1024 * 1024 * 1024}
+	a.check(s)
+	require.Empty(t, events)
+
+	s.now = start.Add(2 * time.Minute)
+	s.lastRoundTimestamp = s.now
+	s.lastBlockTime = s.now
+	a.check(s)
+	require.Len(t, events, 1)
+	require.Equal(t, alertPeerCountZero, events[0].Type)
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/logging/telemetryspec"
+	"github.com/algorand/go-algorand/util"
+	"github.com/algorand/go-algorand/util/metrics"
+)
+
+const (
+	diskCheckInterval         = 10 * time.Second
+	defaultDiskSafeModeFreeMB = 256
+)
+
+// ErrSafeMode is returned for the transactions submitted while the node is in safe mode.
+var ErrSafeMode = errors.New("the node is running out of disk space and doesn't accept new transactions")
+
+var diskFreeBytesGauge = metrics.MakeGauge(metrics.DiskFreeBytes)
+var safeModeGauge = metrics.MakeGauge(metrics.SafeMode)
+
+// diskMonitor tracks the free space of the data directory disk. It warns once the free space drops below warnBelow,
+// and enters safe mode below safeModeBelow. Both conditions clear only once twice their threshold is free again, so
+// that they don't flap while the free space hovers around the threshold.
+type diskMonitor struct {
+	warnBelow     uint64
+	safeModeBelow uint64 // 0 disables safe mode
+
+	low      bool
+	safeMode bool
+}
+
+func makeDiskMonitor(cfg config.Local) *diskMonitor {
+	m := &diskMonitor{
+		warnBelow: cfg.AlertMinFreeDiskMB * 1024 * 1024,
+	}
+	if m.warnBelow == 0 {
+		m.warnBelow = defaultAlertMinFreeDiskMB * 1024 * 1024
+	}
+	switch {
+	case cfg.DiskSafeModeFreeMB == 0:
+		m.safeModeBelow = defaultDiskSafeModeFreeMB * 1024 * 1024
+	case cfg.DiskSafeModeFreeMB > 0:
+		m.safeModeBelow = uint64(cfg.DiskSafeModeFreeMB) * 1024 * 1024
+	}
+	return m
+}
+
+// observe updates the state with the given free space, and reports which of the conditions changed.
+func (m *diskMonitor) observe(free uint64) (lowChanged bool, safeModeChanged bool) {
+	low := crossed(m.low, free, m.warnBelow)
+	safeMode := m.safeModeBelow > 0 && crossed(m.safeMode, free, m.safeModeBelow)
+	lowChanged, safeModeChanged = low != m.low, safeMode != m.safeMode
+	m.low, m.safeMode = low, safeMode
+	return
+}
+
+// crossed evaluates a low space condition, with the hysteresis of clearing it only at twice the threshold.
+func crossed(active bool, free uint64, threshold uint64) bool {
+	if active {
+		return free < 2*threshold
+	}
+	return free < threshold
+}
+
+// SafeMode returns whether the node refuses new transactions because its disk is running out of space.
+func (node *AlgorandFullNode) SafeMode() bool {
+	return atomic.LoadInt32(&node.safeMode) != 0
+}
+
+// diskMonitorThread periodically checks the free space of the data directory disk, and switches the node in and out
+// of safe mode.
+func (node *AlgorandFullNode) diskMonitorThread() {
+	m := makeDiskMonitor(node.Config())
+	ticker := time.NewTicker(diskCheckInterval)
+	defer ticker.Stop()
+	for {
+		node.checkDisk(m)
+		select {
+		case <-ticker.C:
+		case <-node.ctx.Done():
+			return
+		}
+	}
+}
+
+func (node *AlgorandFullNode) checkDisk(m *diskMonitor) {
+	free, err := util.FreeDiskSpace(node.rootDir)
+	if err != nil {
+		node.log.Warnf("diskMonitorThread: unable to get the free space of %s: %v", node.rootDir, err)
+		return
+	}
+	diskFreeBytesGauge.Set(float64(free), nil)
+
+	lowChanged, safeModeChanged := m.observe(free)
+	if lowChanged {
+		if m.low {
+			node.log.Warnf("the data directory disk has only %dMB of free space left", free/(1024*1024))
+		} else {
+			node.log.Infof("the data directory disk has %dMB of free space again", free/(1024*1024))
+		}
+		node.log.EventWithDetails(telemetryspec.ApplicationState, telemetryspec.DiskSpaceLowEvent, telemetryspec.DiskSpaceLowEventDetails{
+			FreeBytes:      free,
+			ThresholdBytes: m.warnBelow,
+			Low:            m.low,
+		})
+	}
+	if safeModeChanged {
+		if m.safeMode {
+			atomic.StoreInt32(&node.safeMode, 1)
+			safeModeGauge.Set(1, nil)
+			node.log.Errorf("entering safe mode with %dMB of free disk space left: new transactions are refused until at least %dMB are free", free/(1024*1024), 2*m.safeModeBelow/(1024*1024))
+		} else {
+			atomic.StoreInt32(&node.safeMode, 0)
+			safeModeGauge.Set(0, nil)
+			node.log.Infof("leaving safe mode with %dMB of free disk space", free/(1024*1024))
+		}
+		node.log.EventWithDetails(telemetryspec.ApplicationState, telemetryspec.SafeModeEvent, telemetryspec.SafeModeEventDetails{
+			FreeBytes:      free,
+			ThresholdBytes: m.safeModeBelow,
+			Enabled:        m.safeMode,
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+)
+
+func TestDiskMonitor(t *testing.T) {
+	const mb = 1024 * 1024
+	m := makeDiskMonitor(config.Local{})
+	require.Equal(t, uint64(defaultAlertMinFreeDiskMB*mb), m.warnBelow)
+	require.Equal(t, uint64(defaultDiskSafeModeFreeMB*mb), m.safeModeBelow)
+
+	low, safeMode := m.observe(10000 * mb)
+	require.False(t, low)
+	require.False(t, safeMode)
+
+	low, safeMode = m.observe(1000 * mb)
+	require.True(t, low)
+	require.False(t, safeMode)
+	require.True(t, m.low)
+
+	low, safeMode = m.observe(200 * mb)
+	require.False(t, low)
+	require.True(t, safeMode)
+	require.True(t, m.safeMode)
+
+	// safe mode is only left once twice the threshold is free again
+	low, safeMode = m.observe(300 * mb)
+	require.False(t, low)
+	require.False(t, safeMode)
+	require.True(t, m.safeMode)
+
+	low, safeMode = m.observe(600 * mb)
+	require.False(t, low)
+	require.True(t, safeMode)
+	require.False(t, m.safeMode)
+
+	low, safeMode = m.observe(3000 * mb)
+	require.True(t, low)
+	require.False(t, safeMode)
+	require.False(t, m.low)
+}
+
+func TestDiskMonitorSafeModeDisabled(t *testing.T) {
+	m := makeDiskMonitor(config.Local{AlertMinFreeDiskMB: 10, DiskSafeModeFreeMB: -1})
+	require.Equal(t, uint64(10*1024*1024), m.warnBelow)
+	require.Zero(t, m.safeModeBelow)
+
+	low, safeMode := m.observe(0)
+	require.True(t, low)
+	require.False(t, safeMode)
+	require.False(t, m.safeMode)
+}
@@ -34,6 +34,8 @@ import (
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/rpcs"
 	"github.com/algorand/go-algorand/util/execpool"
+	"github.com/algorand/go-algorand/util/metrics"
+	"github.com/algorand/go-deadlock"
 )
 
 // TODO these implementations should be pushed down into the corresponding structs or alternatively turned into new structs in the correct subpackages
@@ -62,19 +64,72 @@ type blockFactoryImpl struct {
 	tp               *pools.TransactionPool
 	logStats         bool
 	verificationPool execpool.BacklogPool
+
+	mu deadlock.Mutex
+	// finalizeTime is the moving average of the time generating a block out of its payset takes, in nanoseconds
+	finalizeTime *pools.EWMA
 }
 
+const (
+	// minFinalizeReserve is the least time set aside for generating the block once its payset is selected.
+	minFinalizeReserve = 5 * time.Millisecond
+	// finalizeReserveFactor is the safety margin applied to the recent block generation times.
+	finalizeReserveFactor = 2
+	// finalizeTimeAlpha weighs the latest block generation time in its moving average.
+	finalizeTimeAlpha = 0.2
+)
+
+var blockAssemblyTotal = metrics.MakeCounter(metrics.BlockAssemblyTotal)
+var blockAssemblyDeadlineMissedTotal = metrics.MakeCounter(metrics.BlockAssemblyDeadlineMissedTotal)
+var blockAssemblySeconds = metrics.MakeGauge(metrics.BlockAssemblySeconds)
+var blockAssemblyFinalizeSeconds = metrics.MakeGauge(metrics.BlockAssemblyFinalizeSeconds)
+var blockAssemblyPaysetCount = metrics.MakeGauge(metrics.BlockAssemblyPaysetCount)
+
 func makeBlockFactory(l *data.Ledger, tp *pools.TransactionPool, logStats bool, executionPool execpool.BacklogPool) *blockFactoryImpl {
+	finalizeTime, _ := pools.NewEMA(finalizeTimeAlpha)
 	bf := &blockFactoryImpl{
 		l:                l,
 		tp:               tp,
 		logStats:         logStats,
 		verificationPool: executionPool,
+		finalizeTime:     finalizeTime,
 	}
 	return bf
 }
 
+// paysetDeadline returns the time by which the payset selection has to stop, so that generating the block out of
+// it would still complete by the proposal deadline. No more than half of the remaining time is set aside, so that
+// a single slow block generation doesn't leave the following proposals empty.
+func paysetDeadline(now time.Time, deadline time.Time, finalizeEstimate time.Duration) time.Time {
+	reserve := finalizeReserveFactor * finalizeEstimate
+	if reserve < minFinalizeReserve {
+		reserve = minFinalizeReserve
+	}
+	remaining := deadline.Sub(now)
+	if remaining <= 0 {
+		return deadline
+	}
+	if reserve > remaining/2 {
+		reserve = remaining / 2
+	}
+	return deadline.Add(-reserve)
+}
+
+func (i *blockFactoryImpl) finalizeEstimate() time.Duration {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return time.Duration(i.finalizeTime.Value())
+}
+
+func (i *blockFactoryImpl) recordFinalizeTime(dt time.Duration) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.finalizeTime.Add(float64(dt.Nanoseconds()))
+}
+
 // AssembleBlock implements Ledger.AssembleBlock.
+// The payset selection is bounded so that the block is generated by the deadline: once the time budget runs out,
+// the block is proposed with the highest priority transactions selected until then.
 func (i *blockFactoryImpl) AssembleBlock(round basics.Round, deadline time.Time) (agreement.ValidatedBlock, error) {
 	start := time.Now()
 	prev, err := i.l.BlockHdr(round - 1)
@@ -90,16 +145,33 @@ func (i *blockFactoryImpl) AssembleBlock(round basics.Round, deadline time.Time)
 	}
 
 	var stats telemetryspec.AssembleBlockMetrics
-	stats.AssembleBlockStats = i.l.AssemblePayset(i.tp, eval, deadline)
+	budgetDeadline := paysetDeadline(time.Now(), deadline, i.finalizeEstimate())
+	stats.AssembleBlockStats = i.l.AssemblePayset(i.tp, eval, budgetDeadline)
+	stats.BudgetNanoseconds = budgetDeadline.Sub(start).Nanoseconds()
 
 	// Measure time here because we want to know how close to deadline we are
 	dt := time.Now().Sub(start)
 	stats.AssembleBlockStats.Nanoseconds = dt.Nanoseconds()
 
+	finalizeStart := time.Now()
 	lvb, err := eval.GenerateBlock()
 	if err != nil {
 		return nil, fmt.Errorf("could not make proposals at round %d: could not finish evaluator: %v", round, err)
 	}
+	end := time.Now()
+	finalizeTime := end.Sub(finalizeStart)
+	i.recordFinalizeTime(finalizeTime)
+	stats.FinalizeNanoseconds = finalizeTime.Nanoseconds()
+	stats.DeadlineMissed = end.After(deadline)
+
+	blockAssemblyTotal.Inc(map[string]string{"reason": stats.StopReason})
+	if stats.DeadlineMissed {
+		blockAssemblyDeadlineMissedTotal.Inc(nil)
+		logging.Base().Infof("assembling the block of round %d took %v, past its deadline by %v", round, end.Sub(start), end.Sub(deadline))
+	}
+	blockAssemblySeconds.Set(end.Sub(start).Seconds(), nil)
+	blockAssemblyFinalizeSeconds.Set(finalizeTime.Seconds(), nil)
+	blockAssemblyPaysetCount.Set(float64(stats.IncludedCount), nil)
 
 	if i.logStats {
 		var details struct {
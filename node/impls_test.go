@@ -0,0 +1,41 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaysetDeadline(t *testing.T) {
+	now := time.Now()
+	deadline := now.Add(250 * time.Millisecond)
+
+	// no block generated yet, so only the minimal reserve is set aside
+	require.Equal(t, deadline.Add(-minFinalizeReserve), paysetDeadline(now, deadline, 0))
+
+	// the recent block generation times are reserved with a margin
+	require.Equal(t, deadline.Add(-40*time.Millisecond), paysetDeadline(now, deadline, 20*time.Millisecond))
+
+	// slow block generation leaves at least half of the time to the payset selection
+	require.Equal(t, now.Add(125*time.Millisecond), paysetDeadline(now, deadline, time.Second))
+
+	// past the deadline, the payset selection stops right away
+	require.False(t, paysetDeadline(deadline.Add(time.Millisecond), deadline, 0).After(deadline))
+}
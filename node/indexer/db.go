@@ -17,6 +17,7 @@
 package indexer
 
 import (
+	"bytes"
 	"database/sql"
 	"fmt"
 
@@ -35,7 +36,8 @@ var schema = `
 		from_addr CHAR(58) DEFAULT NULL,
 		to_addr CHAR(58) DEFAULT NULL,
 		round INTEGER DEFAULT NULL,
-		created_at INTEGER
+		created_at INTEGER,
+		note_prefix BLOB DEFAULT NULL
 	);
 
 	CREATE TABLE IF NOT EXISTS params(
@@ -53,6 +55,12 @@ var schema = `
 	);
 `
 
+// noteSchema is applied once the note_prefix column exists, which indexers
+// created before notes were indexed get by migration.
+var noteSchema = `
+	CREATE INDEX IF NOT EXISTS note_idx ON transactions (note_prefix) WHERE note_prefix IS NOT NULL;
+`
+
 // Transaction represents a transaction in the system
 type Transaction struct {
 	TXID      string
@@ -70,11 +78,15 @@ type DB struct {
 
 	// DBPath holds the db file path
 	DBPath string
+
+	// NotePrefixLength is the number of leading note bytes stored for every
+	// transaction, 0 when notes are not indexed.
+	NotePrefixLength int
 }
 
-// MakeIndexerDB takes the db path, a bool for inMemory and returns the IndexerDB control obj
-func MakeIndexerDB(dbPath string, inMemory bool) (*DB, error) {
-	idb := &DB{}
+// MakeIndexerDB takes the db path, a bool for inMemory and the length of the note prefixes to index, and returns the IndexerDB control obj
+func MakeIndexerDB(dbPath string, inMemory bool, notePrefixLength int) (*DB, error) {
+	idb := &DB{NotePrefixLength: notePrefixLength}
 
 	idb.DBPath = dbPath + "/" + dbName
 
@@ -95,9 +107,44 @@ func MakeIndexerDB(dbPath string, inMemory bool) (*DB, error) {
 		return &DB{}, err
 	}
 
+	err = idb.migrateNotePrefix()
+	if err != nil {
+		return &DB{}, err
+	}
+
+	_, err = dbw.Handle.Exec(noteSchema)
+	if err != nil {
+		return &DB{}, err
+	}
+
 	return idb, nil
 }
 
+// migrateNotePrefix adds the note_prefix column to the transactions of an
+// indexer created before notes were indexed. The transactions indexed until
+// then have no note prefix.
+func (idb *DB) migrateNotePrefix() error {
+	var count int
+	err := idb.dbw.Handle.QueryRow("SELECT COUNT(*) FROM pragma_table_info('transactions') WHERE name = 'note_prefix'").Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = idb.dbw.Handle.Exec("ALTER TABLE transactions ADD COLUMN note_prefix BLOB DEFAULT NULL")
+	return err
+}
+
+// notePrefix returns the part of note that is indexed, or nil if there is none.
+func (idb *DB) notePrefix(note []byte) []byte {
+	if idb.NotePrefixLength <= 0 || len(note) == 0 {
+		return nil
+	}
+	if len(note) > idb.NotePrefixLength {
+		note = note[:idb.NotePrefixLength]
+	}
+	return note
+}
+
 // AddBlock takes an Algorand block and stores its transactions in the DB.
 func (idb *DB) AddBlock(b bookkeeping.Block) error {
 	err := idb.dbw.Atomic(func(tx *sql.Tx) error {
@@ -112,7 +159,7 @@ func (idb *DB) AddBlock(b bookkeeping.Block) error {
 			return fmt.Errorf("tryign to add a future block %d, where the last one is %d", b.Round(), rnd)
 		}
 
-		stmt, err := tx.Prepare("INSERT INTO transactions (txid, from_addr, to_addr, round, created_at) VALUES($1,  $2, $3, $4, $5);")
+		stmt, err := tx.Prepare("INSERT INTO transactions (txid, from_addr, to_addr, round, created_at, note_prefix) VALUES($1,  $2, $3, $4, $5, $6);")
 		if err != nil {
 			return err
 		}
@@ -123,7 +170,7 @@ func (idb *DB) AddBlock(b bookkeeping.Block) error {
 			return err
 		}
 		for _, txn := range payset {
-			_, err = stmt.Exec(txn.ID().String(), txn.Txn.Sender.GetChecksumAddress().String(), txn.Txn.Receiver.GetChecksumAddress().String(), b.Round(), b.TimeStamp)
+			_, err = stmt.Exec(txn.ID().String(), txn.Txn.Sender.GetChecksumAddress().String(), txn.Txn.Receiver.GetChecksumAddress().String(), b.Round(), b.TimeStamp, idb.notePrefix(txn.Txn.Note))
 			if err != nil {
 				return err
 			}
@@ -261,6 +308,65 @@ func (idb *DB) GetTransactionsRoundsByAddrAndDate(addr string, top uint64, from,
 	return rounds, nil
 }
 
+// GetTransactionsByNotePrefix takes a note prefix, no longer than NotePrefixLength, and returns the most recent
+// transactions whose note starts with it.
+// if top is 0, it will return 100 transactions by default
+func (idb *DB) GetTransactionsByNotePrefix(prefix []byte, top uint64) ([]Transaction, error) {
+	// the notes starting with prefix are the ones between prefix and the
+	// smallest value that is greater than all of them, when there is one
+	query := `
+		SELECT
+			txid,
+			round
+		FROM
+			transactions
+		WHERE
+		note_prefix >= $1 AND ($2 IS NULL OR note_prefix < $2)
+		ORDER BY round DESC
+		LIMIT $3;
+	`
+
+	// limit
+	if top == 0 {
+		top = maxRows
+	}
+
+	var txns []Transaction
+	rows, err := idb.dbr.Handle.Query(query, prefix, prefixUpperBound(prefix), top)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var txn Transaction
+		err := rows.Scan(&txn.TXID, &txn.Round)
+		if err != nil {
+			return nil, err
+		}
+		txns = append(txns, txn)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return txns, nil
+}
+
+// prefixUpperBound returns the smallest byte string that is greater than
+// every string starting with prefix, or nil if prefix is all 0xff.
+func prefixUpperBound(prefix []byte) []byte {
+	bound := bytes.TrimRight(prefix, "\xff")
+	if len(bound) == 0 {
+		return nil
+	}
+	bound = append([]byte(nil), bound...)
+	bound[len(bound)-1]++
+	return bound
+}
+
 // MaxRound returns the latest block in the DB
 func (idb *DB) MaxRound() (uint64, error) {
 	var rnd uint64
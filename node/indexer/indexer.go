@@ -18,6 +18,8 @@ package indexer
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/algorand/go-algorand/data/basics"
@@ -25,6 +27,19 @@ import (
 	"github.com/algorand/go-algorand/logging"
 )
 
+// ErrNotesNotIndexed is returned by note searches when the indexer does not index notes.
+var ErrNotesNotIndexed = errors.New("transaction notes are not indexed")
+
+// ErrNotePrefixTooLong is returned by note searches for a prefix longer than the indexed part of the notes.
+type ErrNotePrefixTooLong struct {
+	Length  int
+	Indexed int
+}
+
+func (err ErrNotePrefixTooLong) Error() string {
+	return fmt.Sprintf("the note prefix is %d bytes long, but only the first %d bytes of notes are indexed", err.Length, err.Indexed)
+}
+
 // Ledger interface to make testing easier
 type Ledger interface {
 	Block(rnd basics.Round) (blk bookkeeping.Block, err error)
@@ -42,9 +57,10 @@ type Indexer struct {
 	cancelCtx context.CancelFunc
 }
 
-// MakeIndexer makes a new indexer.
-func MakeIndexer(dataDir string, ledger Ledger, inMemory bool) (*Indexer, error) {
-	orm, err := MakeIndexerDB(dataDir, inMemory)
+// MakeIndexer makes a new indexer. The first notePrefixLength bytes of the
+// notes are indexed, none when it is 0.
+func MakeIndexer(dataDir string, ledger Ledger, inMemory bool, notePrefixLength int) (*Indexer, error) {
+	orm, err := MakeIndexerDB(dataDir, inMemory, notePrefixLength)
 	if err != nil {
 		return &Indexer{}, err
 	}
@@ -89,6 +105,18 @@ func (idx *Indexer) GetRoundsByAddress(addr string, top uint64) ([]uint64, error
 	return rounds, nil
 }
 
+// GetTransactionsByNotePrefix takes a note prefix and the maximum number of transactions to return, and returns
+// the most recent transactions whose note starts with prefix. if top is 0, it defaults to 100.
+func (idx *Indexer) GetTransactionsByNotePrefix(prefix []byte, top uint64) ([]Transaction, error) {
+	if idx.IDB.NotePrefixLength <= 0 {
+		return nil, ErrNotesNotIndexed
+	}
+	if len(prefix) > idx.IDB.NotePrefixLength {
+		return nil, ErrNotePrefixTooLong{Length: len(prefix), Indexed: idx.IDB.NotePrefixLength}
+	}
+	return idx.IDB.GetTransactionsByNotePrefix(prefix, top)
+}
+
 // NewBlock takes a block and updates the DB
 // If the block exists, return nil.the block must be the next block
 func (idx *Indexer) NewBlock(b bookkeeping.Block) error {
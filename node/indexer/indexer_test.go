@@ -31,6 +31,7 @@ import (
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
 )
 
 type IndexSuite struct {
@@ -44,7 +45,7 @@ type IndexSuite struct {
 
 func (s *IndexSuite) SetupSuite() {
 	var err error
-	s.idx, err = MakeIndexer(".", &TestLedger{}, true)
+	s.idx, err = MakeIndexer(".", &TestLedger{}, true, 4)
 	require.NoError(s.T(), err)
 
 	// Gen some simple txn
@@ -102,12 +103,82 @@ func (s *IndexSuite) TestIndexer_GetRoundsByAddress() {
 	require.Equal(s.T(), count, len(res))
 }
 
+func TestIndexer_GetTransactionsByNotePrefix(t *testing.T) {
+	idx, err := MakeIndexer("notes", &TestLedger{}, true, 4)
+	require.NoError(t, err)
+	defer idx.Shutdown()
+
+	_, txns, _, _ := generateTestObjects(5, 2)
+	notes := [][]byte{[]byte("inv-001"), []byte("inv-002"), []byte("abc"), {0xff, 0xff}, nil}
+	for i := range txns {
+		txns[i].Txn.Note = notes[i]
+		txns[i] = txns[i].Txn.Sign(keypair())
+	}
+	b := bookkeeping.Block{BlockHeader: bookkeeping.BlockHeader{Round: 2}}
+	for _, tx := range txns {
+		txib, err := b.EncodeSignedTxn(tx, transactions.ApplyData{})
+		require.NoError(t, err)
+		b.Payset = append(b.Payset, txib)
+	}
+	require.NoError(t, idx.NewBlock(b))
+
+	found, err := idx.GetTransactionsByNotePrefix([]byte("inv-"), 0)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{txns[0].ID().String(), txns[1].ID().String()}, []string{found[0].TXID, found[1].TXID})
+	require.Len(t, found, 2)
+	require.Equal(t, uint32(2), found[0].Round)
+
+	found, err = idx.GetTransactionsByNotePrefix([]byte("inv-"), 1)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+
+	found, err = idx.GetTransactionsByNotePrefix([]byte{0xff}, 0)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, txns[3].ID().String(), found[0].TXID)
+
+	found, err = idx.GetTransactionsByNotePrefix([]byte("b"), 0)
+	require.NoError(t, err)
+	require.Empty(t, found)
+
+	_, err = idx.GetTransactionsByNotePrefix([]byte("inv-0"), 0)
+	require.Equal(t, ErrNotePrefixTooLong{Length: 5, Indexed: 4}, err)
+
+	idx.IDB.NotePrefixLength = 0
+	_, err = idx.GetTransactionsByNotePrefix([]byte("inv-"), 0)
+	require.Equal(t, ErrNotesNotIndexed, err)
+}
+
+func TestIndexerDB_MigrateNotePrefix(t *testing.T) {
+	dbw, err := db.MakeAccessor("migrate/"+dbName, false, true)
+	require.NoError(t, err)
+	defer dbw.Close()
+	_, err = dbw.Handle.Exec("CREATE TABLE transactions(txid CHAR(52) PRIMARY KEY NOT NULL, from_addr CHAR(58) DEFAULT NULL, to_addr CHAR(58) DEFAULT NULL, round INTEGER DEFAULT NULL, created_at INTEGER)")
+	require.NoError(t, err)
+
+	idb, err := MakeIndexerDB("migrate", true, 4)
+	require.NoError(t, err)
+	defer idb.Close()
+	found, err := idb.GetTransactionsByNotePrefix([]byte("inv-"), 0)
+	require.NoError(t, err)
+	require.Empty(t, found)
+
+	// migrating again is a no-op
+	require.NoError(t, idb.migrateNotePrefix())
+}
+
+func TestPrefixUpperBound(t *testing.T) {
+	require.Equal(t, []byte("inv."), prefixUpperBound([]byte("inv-")))
+	require.Equal(t, []byte{0x02}, prefixUpperBound([]byte{0x01, 0xff}))
+	require.Nil(t, prefixUpperBound([]byte{0xff, 0xff}))
+}
+
 func TestExampleTestSuite(t *testing.T) {
 	suite.Run(t, new(IndexSuite))
 }
 
 func BenchmarkORM_AddTransactions(b *testing.B) {
-	idx, _ := MakeIndexer(".", &TestLedger{}, false)
+	idx, _ := MakeIndexer(".", &TestLedger{}, false, 0)
 	_, txns, _, _ := generateTestObjects(5000, 100)
 	b.ResetTimer()
 
@@ -131,7 +202,7 @@ func BenchmarkORM_AddTransactions(b *testing.B) {
 
 func BenchmarkORM_AddTransactions2(b *testing.B) {
 	numTxn := 5000
-	idx, _ := MakeIndexer(".", &TestLedger{}, false)
+	idx, _ := MakeIndexer(".", &TestLedger{}, false, 0)
 	_, txns, _, _ := generateTestObjects(numTxn, 100)
 	b.ResetTimer()
 
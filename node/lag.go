@@ -0,0 +1,147 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+)
+
+const (
+	// defaultAPIMaxRoundsBehind is the number of rounds the node may lag the network by and still accept
+	// transactions through the APIs, when APIMaxRoundsBehind is 0
+	defaultAPIMaxRoundsBehind = 10
+
+	// roundTimeWindow is the number of recent rounds whose block timestamps estimate the round time
+	roundTimeWindow = 10
+
+	// minRoundTime is the floor of the round time estimate, since block timestamps only have a one second resolution
+	minRoundTime = time.Second
+
+	// defaultRoundTime is the round time estimate until the ledger holds blocks with timestamps to estimate it from
+	defaultRoundTime = 5 * time.Second
+)
+
+// NodeBehindError is returned for the transactions submitted through the APIs while the node lags the network by
+// more rounds than the APIMaxRoundsBehind setting allows: such transactions might well expire before the node
+// catches up, so the clients should rather submit them to another node.
+type NodeBehindError struct {
+	// LastRound is the last round of the node's ledger
+	LastRound basics.Round
+	// RoundsBehind is the estimated number of rounds the network is ahead of LastRound
+	RoundsBehind uint64
+	// TimeSinceLastRound is the time elapsed since the timestamp of the block of LastRound
+	TimeSinceLastRound time.Duration
+	// CatchupTime is the time the node has been catching up for, 0 if it isn't
+	CatchupTime time.Duration
+}
+
+func (err NodeBehindError) Error() string {
+	return fmt.Sprintf("the node is about %d rounds behind the network at round %d and doesn't accept new transactions until it catches up",
+		err.RoundsBehind, err.LastRound)
+}
+
+// maxRoundsBehind returns the number of rounds the node may lag by under cfg, and false if the check is disabled
+func maxRoundsBehind(cfg config.Local) (uint64, bool) {
+	switch {
+	case cfg.APIMaxRoundsBehind == 0:
+		return defaultAPIMaxRoundsBehind, true
+	case cfg.APIMaxRoundsBehind > 0:
+		return uint64(cfg.APIMaxRoundsBehind), true
+	default:
+		return 0, false
+	}
+}
+
+// estimateRoundsBehind estimates how many rounds the network made since last, the header of the node's last
+// round, from the time elapsed since its timestamp and the round time between first, an earlier header, and last.
+// The node doesn't know the network's latest round until it catches up to it, but the network keeps producing
+// rounds at a steady pace, so the age of the last block tells how far behind the node is. That only holds while the
+// node is catching up though: each block timestamp is at most MaxTimestampIncrement after the previous one, so after
+// the network stalls the timestamps lag the clock for many rounds, and even the latest block looks old.
+func estimateRoundsBehind(first, last bookkeeping.BlockHeader, now time.Time) (uint64, time.Duration) {
+	if last.TimeStamp <= 0 {
+		// genesis blocks might not have a timestamp
+		return 0, 0
+	}
+	sinceLast := now.Sub(time.Unix(last.TimeStamp, 0))
+	if sinceLast <= 0 {
+		return 0, 0
+	}
+
+	roundTime := defaultRoundTime
+	if last.Round > first.Round && first.TimeStamp > 0 && last.TimeStamp > first.TimeStamp {
+		roundTime = time.Duration(last.TimeStamp-first.TimeStamp) * time.Second / time.Duration(last.Round-first.Round)
+		if roundTime < minRoundTime {
+			roundTime = minRoundTime
+		}
+	}
+	return uint64(sinceLast / roundTime), sinceLast
+}
+
+// behindError returns the NodeBehindError to refuse API transactions with, or nil if the node lags the network by
+// at most maxBehind rounds. catchupTime is the time the node has been catching up for: the node is only ever
+// considered behind while the catchup service is fetching blocks, since the age of the last block alone can't tell a
+// lagging node from a caught-up one after a network stall.
+func behindError(first, last bookkeeping.BlockHeader, now time.Time, catchupTime time.Duration, maxBehind uint64) error {
+	if catchupTime == 0 {
+		return nil
+	}
+	behind, sinceLast := estimateRoundsBehind(first, last, now)
+	if behind <= maxBehind {
+		return nil
+	}
+	return NodeBehindError{
+		LastRound:          last.Round,
+		RoundsBehind:       behind,
+		TimeSinceLastRound: sinceLast,
+		CatchupTime:        catchupTime,
+	}
+}
+
+// checkBehind returns a NodeBehindError if the node is catching up and lags the network by more rounds than the
+// APIMaxRoundsBehind setting allows
+func (node *AlgorandFullNode) checkBehind() error {
+	maxBehind, enabled := maxRoundsBehind(node.Config())
+	if !enabled {
+		return nil
+	}
+	catchupTime := node.syncer.SynchronizingTime()
+	if catchupTime == 0 {
+		return nil
+	}
+
+	lastRound := node.ledger.LastRound()
+	last, err := node.ledger.BlockHdr(lastRound)
+	if err != nil {
+		node.log.Errorf("could not get block header from last round %v: %v", lastRound, err)
+		return nil
+	}
+	firstRound := basics.Round(0)
+	if lastRound > roundTimeWindow {
+		firstRound = lastRound - roundTimeWindow
+	}
+	first, err := node.ledger.BlockHdr(firstRound)
+	if err != nil {
+		first = last
+	}
+	return behindError(first, last, time.Now(), catchupTime, maxBehind)
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestMaxRoundsBehind(t *testing.T) {
+	maxBehind, enabled := maxRoundsBehind(config.Local{})
+	require.True(t, enabled)
+	require.Equal(t, uint64(defaultAPIMaxRoundsBehind), maxBehind)
+
+	maxBehind, enabled = maxRoundsBehind(config.Local{APIMaxRoundsBehind: 50})
+	require.True(t, enabled)
+	require.Equal(t, uint64(50), maxBehind)
+
+	_, enabled = maxRoundsBehind(config.Local{APIMaxRoundsBehind: -1})
+	require.False(t, enabled)
+}
+
+func TestEstimateRoundsBehind(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	header := func(round uint64, timestamp int64) (hdr bookkeeping.BlockHeader) {
+		hdr.Round = basics.Round(round)
+		hdr.TimeStamp = timestamp
+		return
+	}
+
+	// 10 rounds in 40 seconds, the last one 20 seconds ago
+	behind, sinceLast := estimateRoundsBehind(header(90, now.Unix()-60), header(100, now.Unix()-20), now)
+	require.Equal(t, uint64(5), behind)
+	require.Equal(t, 20*time.Second, sinceLast)
+
+	// the round time doesn't go below minRoundTime
+	behind, _ = estimateRoundsBehind(header(90, now.Unix()-21), header(100, now.Unix()-20), now)
+	require.Equal(t, uint64(20/minRoundTime.Seconds()), behind)
+
+	// a single block uses defaultRoundTime
+	behind, _ = estimateRoundsBehind(header(100, now.Unix()-20), header(100, now.Unix()-20), now)
+	require.Equal(t, uint64(20*time.Second/defaultRoundTime), behind)
+
+	// a block from the future or without a timestamp isn't behind
+	behind, _ = estimateRoundsBehind(header(90, now.Unix()-30), header(100, now.Unix()+5), now)
+	require.Zero(t, behind)
+	behind, _ = estimateRoundsBehind(header(0, 0), header(0, 0), now)
+	require.Zero(t, behind)
+}
+
+func TestBehindError(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	header := func(round uint64, timestamp int64) (hdr bookkeeping.BlockHeader) {
+		hdr.Round = basics.Round(round)
+		hdr.TimeStamp = timestamp
+		return
+	}
+
+	// after a stall, every block is at most MaxTimestampIncrement after the previous one, so the timestamps of a
+	// caught-up node lag the clock by far more than maxBehind rounds
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	stall := 30 * time.Minute
+	first := header(90, now.Unix()-int64(stall.Seconds()))
+	last := header(100, first.TimeStamp+10*proto.MaxTimestampIncrement)
+	behind, _ := estimateRoundsBehind(first, last, now)
+	require.True(t, behind > defaultAPIMaxRoundsBehind)
+	require.NoError(t, behindError(first, last, now, 0, defaultAPIMaxRoundsBehind))
+
+	// while catching up, the same blocks make the node behind
+	err := behindError(first, last, now, time.Minute, defaultAPIMaxRoundsBehind)
+	require.Error(t, err)
+	require.Equal(t, NodeBehindError{LastRound: 100, RoundsBehind: behind, TimeSinceLastRound: now.Sub(time.Unix(last.TimeStamp, 0)), CatchupTime: time.Minute}, err)
+
+	// unless they are recent enough
+	require.NoError(t, behindError(header(90, now.Unix()-60), header(100, now.Unix()-20), now, time.Minute, defaultAPIMaxRoundsBehind))
+}
@@ -19,10 +19,12 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/algorand/go-algorand/agreement"
@@ -36,6 +38,7 @@ import (
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/pools"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/verify"
 	"github.com/algorand/go-algorand/ledger"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/network"
@@ -46,6 +49,7 @@ import (
 	"github.com/algorand/go-algorand/util/execpool"
 	"github.com/algorand/go-algorand/util/metrics"
 	"github.com/algorand/go-algorand/util/timers"
+	"github.com/algorand/go-algorand/util/tracing"
 	"github.com/algorand/go-deadlock"
 )
 
@@ -61,6 +65,7 @@ type StatusReport struct {
 	LastRoundTimestamp   time.Time
 	SynchronizingTime    time.Duration
 	CatchupTime          time.Duration
+	CatchupProgress      catchup.SyncProgress
 }
 
 // TimeSinceLastRound returns the time since the last block was approved (locally), or 0 if no blocks seen
@@ -76,17 +81,26 @@ func (status StatusReport) TimeSinceLastRound() time.Duration {
 type Full interface {
 	GetSupply() basics.SupplyDetail
 	GetBalanceAndStatus(address basics.Address) (money basics.MicroAlgos, rewards basics.MicroAlgos, moneyWithoutPendingRewards basics.MicroAlgos, status basics.Status, round basics.Round, err error)
-	BroadcastSignedTxn(signed transactions.SignedTxn) (transactions.Txid, error)
+	GetAccountData(address basics.Address) (data basics.AccountData, round basics.Round, err error)
+	GetAccountDataAtRound(address basics.Address, round basics.Round) (basics.AccountData, error)
+	GetBalanceAndStatusAtRound(address basics.Address, round basics.Round) (money basics.MicroAlgos, rewards basics.MicroAlgos, moneyWithoutPendingRewards basics.MicroAlgos, status basics.Status, err error)
+	BroadcastSignedTxn(ctx context.Context, signed transactions.SignedTxn) (transactions.Txid, error)
+	BroadcastSignedTxnGroup(ctx context.Context, txgroup []transactions.SignedTxn) error
+	BroadcastSignedTxnBatch(ctx context.Context, txns []transactions.SignedTxn) ([]error, error)
 	ListTxns(address basics.Address, minRound basics.Round, maxRound basics.Round) ([]TxnWithStatus, error)
 	GetTransaction(address basics.Address, txID transactions.Txid, minRound basics.Round, maxRound basics.Round) (TxnWithStatus, bool)
 	GetPendingTransaction(txID transactions.Txid) (TxnWithStatus, bool)
 	SuggestedFee() basics.MicroAlgos
+	FeeInfo() (FeeInfo, error)
 	PoolStats() PoolStats
 	GetBlock(r basics.Round) (bookkeeping.Block, agreement.Certificate, error)
 	ExtendPeerList(args ...string)
 	LatestRound() basics.Round
 	WaitForRound(r basics.Round) chan struct{}
 	GetPendingTxnsFromPool() ([]transactions.SignedTxn, error)
+	GetPendingTxnsFromSender(sender basics.Address) (SenderQueue, error)
+	DropPendingTransaction(txid transactions.Txid) ([]transactions.Txid, error)
+	RebroadcastStatus(txid transactions.Txid) (RebroadcastStatus, bool)
 	Start()
 	Stop()
 	IsArchival() bool
@@ -105,6 +119,10 @@ type AlgorandFullNode struct {
 	ctx       context.Context
 	cancelCtx context.CancelFunc
 	config    config.Local
+	// configMu protects config once the node started, since ApplyConfig can change it
+	configMu deadlock.RWMutex
+	// admission is the admission policy of config, protected by configMu as well
+	admission admissionPolicy
 
 	ledger    *data.Ledger
 	net       network.GossipNode
@@ -114,6 +132,7 @@ type AlgorandFullNode struct {
 	txHandler       *data.TxHandler
 	accountManager  *data.AccountManager
 	feeTracker      *pools.FeeTracker
+	rebroadcaster   *rebroadcaster
 
 	algorandService *agreement.Service
 	syncer          *catchup.Service
@@ -138,6 +157,14 @@ type AlgorandFullNode struct {
 	wsFetcherService *rpcs.WsFetcherService // to handle inbound gossip msgs for fetching over gossip
 
 	oldKeyDeletionNotify chan struct{}
+
+	// safeMode is set while the disk is running out of space, see diskMonitorThread
+	safeMode int32
+	// shuttingDown is set once the node started shutting down, see BeginShutdown
+	shuttingDown int32
+
+	// threads are the node goroutines that stop with ctx
+	threads sync.WaitGroup
 }
 
 // TxnWithStatus represents information about a single transaction,
@@ -159,6 +186,39 @@ type TxnWithStatus struct {
 	ApplyData transactions.ApplyData
 }
 
+const defaultWarningDeduplicationWindow = time.Minute
+
+var logWarningsSuppressedTotal = metrics.MakeCounter(metrics.LogWarningsSuppressedTotal)
+var logWarningSummariesTotal = metrics.MakeCounter(metrics.LogWarningSummariesTotal)
+
+// duplicateWarningsMetrics counts the warnings suppressed by the log deduplication, by subsystem.
+type duplicateWarningsMetrics struct{}
+
+func (duplicateWarningsMetrics) WarningSuppressed(subsystem string) {
+	logWarningsSuppressedTotal.Inc(map[string]string{"subsystem": subsystem})
+}
+
+func (duplicateWarningsMetrics) SummaryLogged(subsystem string, suppressed uint64) {
+	logWarningSummariesTotal.Inc(map[string]string{"subsystem": subsystem})
+}
+
+func init() {
+	logging.SetDuplicateWarningsObserver(duplicateWarningsMetrics{})
+}
+
+// warningDeduplicationWindow returns the window within which the node deduplicates identical warnings, or 0 when
+// it doesn't.
+func warningDeduplicationWindow(cfg config.Local) time.Duration {
+	switch {
+	case cfg.WarningDeduplicationSeconds < 0:
+		return 0
+	case cfg.WarningDeduplicationSeconds == 0:
+		return defaultWarningDeduplicationWindow
+	default:
+		return time.Duration(cfg.WarningDeduplicationSeconds) * time.Second
+	}
+}
+
 // MakeFull sets up an Algorand full node
 // (i.e., it returns a node that participates in consensus)
 func MakeFull(log logging.Logger, rootDir string, cfg config.Local, phonebookDir string, genesis bookkeeping.Genesis) (*AlgorandFullNode, error) {
@@ -167,6 +227,10 @@ func MakeFull(log logging.Logger, rootDir string, cfg config.Local, phonebookDir
 	node.rootDir = rootDir
 	node.config = cfg
 	node.log = log.With("name", cfg.NetAddress)
+	if window := warningDeduplicationWindow(cfg); window > 0 {
+		node.log = node.log.WithDeduplication(window)
+	}
+	node.admission = makeAdmissionPolicy(cfg, node.log)
 	node.genesisID = genesis.ID()
 	node.genesisHash = crypto.HashObj(genesis)
 
@@ -177,11 +241,12 @@ func MakeFull(log logging.Logger, rootDir string, cfg config.Local, phonebookDir
 	node.phonebook.ReplacePeerList(addrs)
 
 	// tie network, block fetcher, and agreement services together
-	p2pNode, err := network.NewWebsocketNetwork(node.log, node.config, &node.phonebook, genesis.ID(), genesis.Network)
+	p2pNode, err := network.NewWebsocketNetwork(node.log.WithSubsystem(logging.NetworkSubsystem), node.config, &node.phonebook, genesis.ID(), genesis.Network)
 	if err != nil {
 		log.Errorf("could not create websocket node: %v", err)
 		return nil, err
 	}
+	p2pNode.GenesisHash = node.genesisHash
 	p2pNode.SetPrioScheme(node)
 	node.net = p2pNode
 	node.accountManager = data.MakeAccountManager(log)
@@ -212,15 +277,16 @@ func MakeFull(log logging.Logger, rootDir string, cfg config.Local, phonebookDir
 	node.cryptoPool = execpool.MakePool(node)
 	node.lowPriorityCryptoVerificationPool = execpool.MakeBacklog(node.cryptoPool, 2*node.cryptoPool.GetParallelism(), execpool.LowPriority, node)
 	node.highPriorityCryptoVerificationPool = execpool.MakeBacklog(node.cryptoPool, 2*node.cryptoPool.GetParallelism(), execpool.HighPriority, node)
-	node.ledger, err = data.LoadLedger(node.log, ledgerPathnamePrefix, false, genesis.Proto, genalloc, node.genesisID, node.genesisHash, blockListeners)
+	node.ledger, err = data.LoadLedger(node.log.WithSubsystem(logging.LedgerSubsystem), ledgerPathnamePrefix, false, genesis.Proto, genalloc, node.genesisID, node.genesisHash, blockListeners)
 	if err != nil {
 		log.Errorf("Cannot initialize ledger (%s): %v", ledgerPathnamePrefix, err)
 		return nil, err
 	}
 
 	node.ledger.SetArchival(cfg.Archival)
-	node.transactionPool = pools.MakeTransactionPool(node.ledger, cfg.TxPoolExponentialIncreaseFactor, cfg.TxPoolSize, cfg.EnableAssembleStats)
+	node.transactionPool = pools.MakeTransactionPool(node.ledger, cfg.TxPoolExponentialIncreaseFactor, cfg.TxPoolSize, cfg.TxPoolSenderLimit(), cfg.EnableAssembleStats)
 	node.ledger.RegisterBlockListeners([]ledger.BlockListener{node.transactionPool})
+	node.rebroadcaster = makeRebroadcaster(cfg)
 	node.txHandler = data.MakeTxHandler(node.transactionPool, node.ledger, node.net, node.genesisID, node.genesisHash, node.lowPriorityCryptoVerificationPool)
 	node.feeTracker, err = pools.MakeFeeTracker()
 	if err != nil {
@@ -230,7 +296,7 @@ func MakeFull(log logging.Logger, rootDir string, cfg config.Local, phonebookDir
 
 	// Indexer setup
 	if cfg.IsIndexerActive && cfg.Archival {
-		node.indexer, err = indexer.MakeIndexer(genesisDir, node.ledger, false)
+		node.indexer, err = indexer.MakeIndexer(genesisDir, node.ledger, false, cfg.IndexerNotePrefixLength)
 		if err != nil {
 			logging.Base().Errorf("failed to make indexer -  %v", err)
 			return nil, err
@@ -253,7 +319,7 @@ func MakeFull(log logging.Logger, rootDir string, cfg config.Local, phonebookDir
 	agreementLedger := agreementLedger{Ledger: node.ledger, ff: rpcs.MakeNetworkFetcherFactory(node.net, blockQueryPeerLimit, node.wsFetcherService), n: node.net}
 
 	agreementParameters := agreement.Parameters{
-		Logger:         log,
+		Logger:         log.WithSubsystem(logging.AgreementSubsystem),
 		Accessor:       crashAccess,
 		Clock:          timers.MakeMonotonicClock(time.Now()),
 		Local:          node.config,
@@ -317,9 +383,33 @@ func bootstrapData(genesis bookkeeping.Genesis, log logging.Logger) (data.Genesi
 
 // Config returns a copy of the node's Local configuration
 func (node *AlgorandFullNode) Config() config.Local {
+	node.configMu.RLock()
+	defer node.configMu.RUnlock()
 	return node.config
 }
 
+// peerLimiter is implemented by networks whose peer caps can change while they run
+type peerLimiter interface {
+	SetPeerLimits(cfg config.Local)
+}
+
+// ApplyConfig applies the reloadable settings of cfg (see config.IsReloadable) to the running node, and
+// makes cfg the node's configuration. Changes to the other settings are only recorded; they take effect
+// once the node restarts.
+func (node *AlgorandFullNode) ApplyConfig(cfg config.Local) {
+	admission := makeAdmissionPolicy(cfg, node.log)
+	node.configMu.Lock()
+	node.config = cfg
+	node.admission = admission
+	node.configMu.Unlock()
+
+	node.log.SetLevel(logging.Level(cfg.BaseLoggerDebugLevel))
+	if limiter, ok := node.net.(peerLimiter); ok {
+		limiter.SetPeerLimits(cfg)
+	}
+	node.transactionPool.Reconfigure(cfg.TxPoolSenderLimit(), cfg.EnableAssembleStats)
+}
+
 // Start the node: connect to peers and run the agreement service while obtaining a lock. Doesn't wait for initial sync.
 func (node *AlgorandFullNode) Start() {
 	node.mu.Lock()
@@ -327,7 +417,9 @@ func (node *AlgorandFullNode) Start() {
 
 	// start accepting connections
 	node.net.Start()
+	node.configMu.Lock()
 	node.config.NetAddress, _ = node.net.Address()
+	node.configMu.Unlock()
 
 	node.syncer.Start()
 	node.algorandService.Start()
@@ -353,11 +445,18 @@ func (node *AlgorandFullNode) Start() {
 	}
 
 	// Periodically check for new participation keys
-	go node.checkForParticipationKeys()
+	node.startThread(node.checkForParticipationKeys)
 
-	go node.txPoolGaugeThread()
+	node.startThread(node.txPoolGaugeThread)
+	if node.rebroadcaster != nil {
+		node.startThread(node.rebroadcastThread)
+	}
+	node.startThread(node.diskMonitorThread)
+	if node.config.AlertWebhookURLs != "" {
+		node.startThread(node.alertThread)
+	}
 	// Delete old participation keys
-	go node.oldKeyDeletionThread()
+	node.startThread(node.oldKeyDeletionThread)
 
 	// TODO re-enable with configuration flag post V1
 	//go logging.UsageLogThread(node.ctx, node.log, 100*time.Millisecond, nil)
@@ -373,25 +472,52 @@ func (node *AlgorandFullNode) ListeningAddress() (string, bool) {
 
 // Stop stops running the node. Once a node is closed, it can never start again.
 func (node *AlgorandFullNode) Stop() {
-	node.mu.Lock()
-	defer node.mu.Unlock()
-
-	node.net.ClearHandlers()
+	node.stop()
+}
 
-	node.txHandler.Stop()
-	node.net.Stop()
-	node.algorandService.Shutdown()
-	node.syncer.Stop()
-	node.txPoolSyncer.Stop()
-	node.ledgerService.Stop()
-	node.highPriorityCryptoVerificationPool.Shutdown()
-	node.lowPriorityCryptoVerificationPool.Shutdown()
-	node.cryptoPool.Shutdown()
-	node.cancelCtx()
+// startThread runs one of the node goroutines that stop with ctx, so that stop can wait for it.
+func (node *AlgorandFullNode) startThread(thread func()) {
+	node.threads.Add(1)
+	go func() {
+		defer node.threads.Done()
+		thread()
+	}()
+}
 
+func (node *AlgorandFullNode) stop() []ShutdownStep {
+	var t shutdownTimer
+	node.mu.Lock()
+	t.step("txhandler", func() {
+		node.net.ClearHandlers()
+		node.txHandler.Stop()
+	})
+	t.step("network", node.net.Stop)
+	t.step("agreement", node.algorandService.Shutdown)
+	t.step("catchup", node.syncer.Stop)
+	t.step("txsync", node.txPoolSyncer.Stop)
+	t.step("ledgerservice", node.ledgerService.Stop)
+	t.step("verification", func() {
+		node.highPriorityCryptoVerificationPool.Shutdown()
+		node.lowPriorityCryptoVerificationPool.Shutdown()
+		node.cryptoPool.Shutdown()
+	})
+	node.mu.Unlock()
+
+	// the node threads may take node.mu
+	t.step("threads", func() {
+		node.cancelCtx()
+		node.threads.Wait()
+	})
 	if node.indexer != nil {
-		node.indexer.Shutdown()
+		t.step("indexer", node.indexer.Shutdown)
 	}
+	t.step("ledger", node.ledger.Close)
+	t.step("partkeys", func() {
+		for _, part := range node.accountManager.Keys() {
+			part.Close()
+		}
+	})
+	return t.steps
 }
 
 // note: unlike the other two functions, this accepts a whole filename
@@ -421,13 +547,52 @@ func (node *AlgorandFullNode) GetSupply() basics.SupplyDetail {
 	}
 }
 
+// GetAccountData returns the state of the account as of the latest round, without applying its pending rewards
+func (node *AlgorandFullNode) GetAccountData(address basics.Address) (data basics.AccountData, round basics.Round, err error) {
+	round = node.ledger.Latest()
+	data, err = node.ledger.Lookup(round, address)
+	return
+}
+
+// GetAccountDataAtRound returns the account data of address as of round,
+// with its rewards up to that round. The ledger only keeps the account state
+// of the most recent rounds, so older rounds return an error.
+func (node *AlgorandFullNode) GetAccountDataAtRound(address basics.Address, round basics.Round) (basics.AccountData, error) {
+	return node.ledger.Lookup(round, address)
+}
+
 // GetBalanceAndStatus returns both the Balance and the Delegator status of the account, in one call so they're from the same block
 func (node *AlgorandFullNode) GetBalanceAndStatus(address basics.Address) (money basics.MicroAlgos, rewards basics.MicroAlgos, moneyWithoutPendingRewards basics.MicroAlgos, status basics.Status, round basics.Round, err error) {
 	return node.ledger.BalanceAndStatus(address)
 }
 
+// GetBalanceAndStatusAtRound is GetBalanceAndStatus as of round, one of the recent rounds whose account state the
+// ledger still holds
+func (node *AlgorandFullNode) GetBalanceAndStatusAtRound(address basics.Address, round basics.Round) (money basics.MicroAlgos, rewards basics.MicroAlgos, moneyWithoutPendingRewards basics.MicroAlgos, status basics.Status, err error) {
+	return node.ledger.BalanceAndStatusAtRound(address, round)
+}
+
 // BroadcastSignedTxn broadcasts a transaction that has already been signed.
-func (node *AlgorandFullNode) BroadcastSignedTxn(signed transactions.SignedTxn) (transactions.Txid, error) {
+func (node *AlgorandFullNode) BroadcastSignedTxn(ctx context.Context, signed transactions.SignedTxn) (transactions.Txid, error) {
+	err := node.BroadcastSignedTxnGroup(ctx, []transactions.SignedTxn{signed})
+	if err != nil {
+		return transactions.Txid{}, err
+	}
+	return signed.ID(), nil
+}
+
+// BroadcastSignedTxnGroup broadcasts an atomic transaction group whose transactions have already been signed.
+// The group is admitted into the transaction pool, and broadcast, as a whole.
+func (node *AlgorandFullNode) BroadcastSignedTxnGroup(ctx context.Context, txgroup []transactions.SignedTxn) error {
+	if node.isShuttingDown() {
+		return ErrShuttingDown
+	}
+	if node.SafeMode() {
+		return ErrSafeMode
+	}
+	if err := node.checkBehind(); err != nil {
+		return err
+	}
 	lastRound := node.ledger.LastRound()
 	b, err := node.ledger.BlockHdr(lastRound)
 	if err != nil {
@@ -439,24 +604,120 @@ func (node *AlgorandFullNode) BroadcastSignedTxn(signed transactions.SignedTxn)
 	}
 	proto := config.Consensus[b.CurrentProtocol]
 
-	err = signed.Verify(spec, proto)
+	err = transactions.WellFormedGroup(txgroup, proto)
 	if err != nil {
-		node.log.Warnf("malformed transaction: %v - transaction was %+v", err, signed)
-		return transactions.Txid{}, err
+		node.log.Warnf("malformed transaction group: %v - transactions were %+v", err, txgroup)
+		return err
+	}
+	var enc []byte
+	for i, signed := range txgroup {
+		err = verify.Txn(&signed, verify.Context{Spec: spec, Proto: proto, Group: txgroup, GroupIndex: i})
+		if err != nil {
+			node.log.Warnf("malformed transaction: %v - transaction was %+v", err, signed)
+			return err
+		}
+		err = node.checkAdmission(signed, proto)
+		if err != nil {
+			node.log.Infof("%v - transaction was %+v", err, signed)
+			return err
+		}
+		enc = append(enc, protocol.Encode(signed)...)
 	}
-	err = node.transactionPool.Remember(signed)
+	_, span := tracing.StartSpan(ctx, "txpool.Remember", tracing.SpanKindInternal, tracing.Attr(logging.TxIDField, txgroup[0].ID().String()))
+	err = node.transactionPool.RememberGroup(txgroup)
+	span.SetError(err)
+	span.End()
 	if err != nil {
-		node.log.Infof("rejected by local pool: %v - transaction was %+v", err, signed)
-		return transactions.Txid{}, err
+		node.log.Infof("rejected by local pool: %v - transactions were %+v", err, txgroup)
+		return err
 	}
 
-	err = node.net.Broadcast(context.TODO(), protocol.TxnTag, protocol.Encode(signed), true, nil)
+	err = node.net.Broadcast(ctx, protocol.TxnTag, enc, true, nil)
 	if err != nil {
-		node.log.Infof("failure broadcasting transaction to network: %v - transaction was %+v", err, signed)
-		return transactions.Txid{}, err
+		node.log.Infof("failure broadcasting transactions to network: %v - transactions were %+v", err, txgroup)
+		return err
 	}
-	node.log.Infof("Sent signed tx %s", signed.ID())
-	return signed.ID(), nil
+	peers := node.net.GetPeers(network.PeersConnectedOut, network.PeersConnectedIn)
+	for _, signed := range txgroup {
+		node.log.Infof("Sent signed tx %s", signed.ID())
+		// a transaction of a group can only be rebroadcast along with the rest of its group
+		if signed.Txn.Group != (crypto.Digest{}) {
+			continue
+		}
+		if node.rebroadcaster != nil && !node.rebroadcaster.track(signed, peers, time.Now()) {
+			node.log.Infof("not tracking tx %s for rebroadcasting, as too many transactions are tracked already", signed.ID())
+		}
+	}
+	return nil
+}
+
+// BroadcastSignedTxnBatch verifies the transactions of txns, admits the valid ones into the transaction pool
+// in a row, and broadcasts those the pool accepted. It returns the error of each transaction, nil for the
+// broadcast ones; the returned error is set only when the node doesn't take transactions at all.
+func (node *AlgorandFullNode) BroadcastSignedTxnBatch(ctx context.Context, txns []transactions.SignedTxn) ([]error, error) {
+	if node.isShuttingDown() {
+		return nil, ErrShuttingDown
+	}
+	if node.SafeMode() {
+		return nil, ErrSafeMode
+	}
+	if err := node.checkBehind(); err != nil {
+		return nil, err
+	}
+	lastRound := node.ledger.LastRound()
+	b, err := node.ledger.BlockHdr(lastRound)
+	if err != nil {
+		node.log.Errorf("could not get block header from last round %v: %v", lastRound, err)
+	}
+	spec := transactions.SpecialAddresses{
+		FeeSink:     b.FeeSink,
+		RewardsPool: b.RewardsPool,
+	}
+	proto := config.Consensus[b.CurrentProtocol]
+
+	errs := make([]error, len(txns))
+	verified := make([]transactions.SignedTxn, 0, len(txns))
+	verifiedIdx := make([]int, 0, len(txns))
+	for i, signed := range txns {
+		errs[i] = verify.Txn(&signed, verify.Context{Spec: spec, Proto: proto, Group: txns[i : i+1], GroupIndex: 0})
+		if errs[i] != nil {
+			node.log.Warnf("malformed transaction: %v - transaction was %+v", errs[i], signed)
+			continue
+		}
+		errs[i] = node.checkAdmission(signed, proto)
+		if errs[i] != nil {
+			node.log.Infof("%v - transaction was %+v", errs[i], signed)
+			continue
+		}
+		verified = append(verified, signed)
+		verifiedIdx = append(verifiedIdx, i)
+	}
+
+	_, span := tracing.StartSpan(ctx, "txpool.RememberBatch", tracing.SpanKindInternal, tracing.Attr("algorand.batch_size", len(verified)))
+	poolErrs := node.transactionPool.RememberBatch(verified)
+	span.End()
+
+	peers := node.net.GetPeers(network.PeersConnectedOut, network.PeersConnectedIn)
+	for j, signed := range verified {
+		i := verifiedIdx[j]
+		if poolErrs[j] != nil {
+			node.log.Infof("rejected by local pool: %v - transaction was %+v", poolErrs[j], signed)
+			errs[i] = poolErrs[j]
+			continue
+		}
+
+		err = node.net.Broadcast(ctx, protocol.TxnTag, protocol.Encode(signed), true, nil)
+		if err != nil {
+			node.log.Infof("failure broadcasting transaction to network: %v - transaction was %+v", err, signed)
+			errs[i] = err
+			continue
+		}
+		if node.rebroadcaster != nil && !node.rebroadcaster.track(signed, peers, time.Now()) {
+			node.log.Infof("not tracking tx %s for rebroadcasting, as too many transactions are tracked already", signed.ID())
+		}
+	}
+	node.log.Infof("Sent a batch of %d signed txns", len(txns))
+	return errs, nil
 }
 
 // ListTxns returns SignedTxns associated with a specific account in a range of Rounds (inclusive).
@@ -582,6 +843,7 @@ func (node *AlgorandFullNode) Status() (s StatusReport, err error) {
 	s.SynchronizingTime = node.syncer.SynchronizingTime()
 	s.LastRoundTimestamp = node.lastRoundTimestamp
 	s.CatchupTime = node.syncer.SynchronizingTime()
+	s.CatchupProgress = node.syncer.Progress()
 	return
 }
 
@@ -648,12 +910,55 @@ func (node *AlgorandFullNode) SuggestedFee() basics.MicroAlgos {
 	return node.feeTracker.EstimateFee()
 }
 
+// FeeInfo returns the suggested fee per byte along with the congestion of the transaction pool, measured against
+// the block size of the current protocol.
+func (node *AlgorandFullNode) FeeInfo() (FeeInfo, error) {
+	proto, err := node.ledger.ConsensusParams(node.LatestRound())
+	if err != nil {
+		return FeeInfo{}, err
+	}
+	return FeeInfo{
+		SuggestedFee: node.feeTracker.EstimateFee(),
+		MinTxnFee:    basics.MicroAlgos{Raw: proto.MinTxnFee},
+		Congestion:   node.transactionPool.Congestion(proto.MaxTxnBytesPerBlock),
+	}, nil
+}
+
 // GetPendingTxnsFromPool returns a snapshot of every pending transactions from the node's transaction pool in a slice.
 // Transactions are sorted in decreasing order. If no transactions, returns an empty slice.
 func (node *AlgorandFullNode) GetPendingTxnsFromPool() ([]transactions.SignedTxn, error) {
 	return node.transactionPool.Pending(), nil
 }
 
+// GetPendingTxnsFromSender returns a snapshot of the pending transactions of the given sender, in the same order
+// as GetPendingTxnsFromPool, along with their positions among all the pending transactions.
+func (node *AlgorandFullNode) GetPendingTxnsFromSender(sender basics.Address) (SenderQueue, error) {
+	txns, positions, total := node.transactionPool.PendingFrom(sender)
+	return SenderQueue{
+		Txns:           txns,
+		Positions:      positions,
+		NumOutstanding: total,
+		SenderLimit:    node.Config().TxPoolSenderLimit(),
+	}, nil
+}
+
+// DropPendingTransaction evicts a transaction from the transaction pool along with the rest of its group, and stops
+// rebroadcasting them. It returns the IDs of the evicted transactions.
+func (node *AlgorandFullNode) DropPendingTransaction(txid transactions.Txid) ([]transactions.Txid, error) {
+	reason := errors.New("dropped by the node operator")
+	dropped := node.transactionPool.Drop(txid, reason)
+	if len(dropped) == 0 {
+		return nil, fmt.Errorf("transaction %v is not pending in the transaction pool", txid)
+	}
+	if node.rebroadcaster != nil {
+		for _, id := range dropped {
+			node.rebroadcaster.reject(id, reason)
+		}
+	}
+	node.log.Infof("dropped transactions %v from the transaction pool", dropped)
+	return dropped, nil
+}
+
 // Reload participation keys from disk periodically
 func (node *AlgorandFullNode) checkForParticipationKeys() {
 	ticker := time.NewTicker(participationKeyCheckSecs * time.Second)
@@ -677,6 +982,7 @@ func (node *AlgorandFullNode) loadParticipationKeys() error {
 	}
 
 	// For each of these files
+	onDisk := make(map[account.ParticipationInterval]bool)
 	for _, info := range files {
 		// If it can't be a participation key database, skip it
 		if !config.IsPartKeyFilename(info.Name()) {
@@ -705,6 +1011,9 @@ func (node *AlgorandFullNode) loadParticipationKeys() error {
 				return fmt.Errorf("AlgorandFullNode.loadParticipationKeys: cannot load account at %v: %v", info.Name(), err)
 			}
 		} else {
+			first, last := part.ValidInterval()
+			onDisk[account.ParticipationInterval{Address: part.Address(), FirstValid: first, LastValid: last}] = true
+
 			// Tell the AccountManager about the Participation (dupes don't matter)
 			added := node.accountManager.AddParticipation(part)
 			if added {
@@ -715,23 +1024,47 @@ func (node *AlgorandFullNode) loadParticipationKeys() error {
 		}
 	}
 
+	// Stop using the participation keys whose database has been deleted
+	for _, part := range node.accountManager.Keys() {
+		first, last := part.ValidInterval()
+		interval := account.ParticipationInterval{Address: part.Address(), FirstValid: first, LastValid: last}
+		if !onDisk[interval] && node.accountManager.RemoveParticipation(interval) {
+			node.log.Infof("Unloaded deleted participation keys: %s (%d - %d)", part.Address(), first, last)
+		}
+	}
+
 	return nil
 }
 
 func (node *AlgorandFullNode) txPoolGaugeThread() {
 	txPoolGuage := metrics.MakeGauge(metrics.MetricName{Name: "algod_tx_pool_count", Description: "current number of available transactions in pool"})
+	txPoolBytesGauge := metrics.MakeGauge(metrics.TransactionPoolBytes)
+	txPoolMinFeeGauge := metrics.MakeGauge(metrics.TransactionPoolMinFeePerByte)
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 	for true {
 		select {
 		case <-ticker.C:
-			txPoolGuage.Set(float64(node.transactionPool.PendingCount()), nil)
+			count, bytes, minPriority := node.transactionPool.Occupancy()
+			txPoolGuage.Set(float64(count), nil)
+			txPoolBytesGauge.Set(float64(bytes), nil)
+			txPoolMinFeeGauge.Set(minPriority.FeePerByte(), nil)
 		case <-node.ctx.Done():
 			return
 		}
 	}
 }
 
+// Ledger returns the node's ledger, for the programs that embed the node
+func (node *AlgorandFullNode) Ledger() *data.Ledger {
+	return node.ledger
+}
+
+// TransactionPool returns the node's transaction pool, for the programs that embed the node
+func (node *AlgorandFullNode) TransactionPool() *pools.TransactionPool {
+	return node.transactionPool
+}
+
 // IsArchival returns true the node is an archival node, false otherwise
 func (node *AlgorandFullNode) IsArchival() bool {
 	return node.config.Archival
@@ -752,6 +1085,10 @@ func (node *AlgorandFullNode) OnNewBlock(block bookkeeping.Block) {
 
 	// Update fee tracker
 	node.feeTracker.ProcessBlock(block)
+
+	if node.rebroadcaster != nil {
+		node.onNewBlockRebroadcast(block)
+	}
 }
 
 // oldKeyDeletionThread keeps deleting old participation keys.
@@ -16,9 +16,36 @@
 
 package node
 
+import (
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/pools"
+	"github.com/algorand/go-algorand/data/transactions"
+)
+
 // PoolStats represents some statistics about the transaction pool
 type PoolStats struct {
 	NumConfirmed   uint64
 	NumOutstanding uint64
 	NumExpired     uint64
 }
+
+// SenderQueue represents the transactions of a single sender that are pending in the transaction pool
+type SenderQueue struct {
+	// Txns are the pending transactions of the sender, in the order they are considered for inclusion in a block
+	Txns []transactions.SignedTxn
+	// Positions are the positions of the Txns among all the pending transactions
+	Positions []int
+	// NumOutstanding is the number of transactions pending in the pool
+	NumOutstanding int
+	// SenderLimit is the number of transactions a sender may have pending, or 0 if it isn't limited
+	SenderLimit int
+}
+
+// FeeInfo represents the fees a new transaction should pay, given how congested the transaction pool is
+type FeeInfo struct {
+	// SuggestedFee is the suggested fee per byte
+	SuggestedFee basics.MicroAlgos
+	// MinTxnFee is the minimum fee of a transaction in the current protocol
+	MinTxnFee basics.MicroAlgos
+	pools.Congestion
+}
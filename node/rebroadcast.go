@@ -0,0 +1,336 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"time"
+
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/network"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// The states of a transaction tracked for rebroadcasting.
+const (
+	// RebroadcastPending is the state of a transaction that is neither confirmed nor expired, and keeps being rebroadcast.
+	RebroadcastPending = "pending"
+	// RebroadcastConfirmed is the state of a transaction that was included in a block.
+	RebroadcastConfirmed = "confirmed"
+	// RebroadcastExpired is the state of a transaction whose last valid round passed before it was confirmed.
+	RebroadcastExpired = "expired"
+	// RebroadcastRejected is the state of a transaction that the transaction pool no longer accepts, such as
+	// when its sender can no longer afford it.
+	RebroadcastRejected = "rejected"
+)
+
+const (
+	defaultTxRebroadcastInterval   = 30 * time.Second
+	defaultTxRebroadcastMaxTracked = 10000
+
+	// rebroadcastRetentionRounds is the number of rounds the status of a confirmed, expired or rejected
+	// transaction is kept for after it stopped being rebroadcast.
+	rebroadcastRetentionRounds = 1000
+)
+
+// RebroadcastStatus reports the rebroadcasting of a transaction that was submitted through this node.
+type RebroadcastStatus struct {
+	TxID  transactions.Txid
+	State string
+
+	// Submitted is the time the transaction was submitted, and LastBroadcast the last time it was sent to peers
+	Submitted     time.Time
+	LastBroadcast time.Time
+	// Broadcasts is the number of times the transaction was sent, including its original broadcast
+	Broadcasts int
+	// Peers is the number of distinct peers the transaction was sent to
+	Peers int
+
+	LastValid basics.Round
+	// ConfirmedRound is the round the transaction was included in, if it's confirmed
+	ConfirmedRound basics.Round
+	// Error is the reason the transaction was rejected, if it's rejected
+	Error string
+}
+
+type rebroadcastEntry struct {
+	status RebroadcastStatus
+	txn    transactions.SignedTxn
+	sentTo map[network.Peer]bool
+	// doneRound is the round at which the transaction stopped being rebroadcast
+	doneRound basics.Round
+}
+
+// rebroadcaster tracks the transactions submitted through this node until they are confirmed or expire.
+type rebroadcaster struct {
+	mu         deadlock.Mutex
+	interval   time.Duration
+	maxTracked int
+	entries    map[transactions.Txid]*rebroadcastEntry
+	lastRound  basics.Round
+}
+
+// makeRebroadcaster creates a rebroadcaster according to the configuration, or returns nil if rebroadcasting is disabled.
+func makeRebroadcaster(cfg config.Local) *rebroadcaster {
+	if cfg.TxRebroadcastIntervalSeconds < 0 {
+		return nil
+	}
+	r := &rebroadcaster{
+		interval:   time.Duration(cfg.TxRebroadcastIntervalSeconds) * time.Second,
+		maxTracked: cfg.TxRebroadcastMaxTracked,
+		entries:    make(map[transactions.Txid]*rebroadcastEntry),
+	}
+	if r.interval == 0 {
+		r.interval = defaultTxRebroadcastInterval
+	}
+	if r.maxTracked == 0 {
+		r.maxTracked = defaultTxRebroadcastMaxTracked
+	}
+	return r
+}
+
+// track starts tracking a transaction that was just broadcast to the given peers. It returns false if too many
+// transactions are pending rebroadcast already.
+func (r *rebroadcaster) track(txn transactions.SignedTxn, peers []network.Peer, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	txid := txn.ID()
+	if _, has := r.entries[txid]; has {
+		return true
+	}
+	if len(r.entries) >= r.maxTracked && !r.forgetOldestDone() {
+		return false
+	}
+	e := &rebroadcastEntry{
+		status: RebroadcastStatus{
+			TxID:          txid,
+			State:         RebroadcastPending,
+			Submitted:     now,
+			LastBroadcast: now,
+			Broadcasts:    1,
+			LastValid:     txn.Txn.LastValid,
+		},
+		txn:    txn,
+		sentTo: make(map[network.Peer]bool),
+	}
+	e.markSent(peers)
+	r.entries[txid] = e
+	return true
+}
+
+// forgetOldestDone forgets the transaction that stopped being rebroadcast the earliest, if any.
+func (r *rebroadcaster) forgetOldestDone() bool {
+	var oldest *rebroadcastEntry
+	for _, e := range r.entries {
+		if e.status.State != RebroadcastPending && (oldest == nil || e.doneRound < oldest.doneRound) {
+			oldest = e
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	delete(r.entries, oldest.status.TxID)
+	return true
+}
+
+func (e *rebroadcastEntry) markSent(peers []network.Peer) {
+	for _, peer := range peers {
+		e.sentTo[peer] = true
+	}
+	e.status.Peers = len(e.sentTo)
+}
+
+func (e *rebroadcastEntry) finish(state string, round basics.Round) {
+	e.status.State = state
+	e.doneRound = round
+	// the peers aren't needed anymore, and holding them would keep closed connections around.
+	e.sentTo = nil
+}
+
+// status returns the rebroadcast status of the given transaction, if it's tracked.
+func (r *rebroadcaster) status(txid transactions.Txid) (RebroadcastStatus, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, has := r.entries[txid]
+	if !has {
+		return RebroadcastStatus{}, false
+	}
+	return e.status, true
+}
+
+// onNewBlock marks the tracked transactions included in the block as confirmed, and the ones that can no longer
+// be included in a block as expired.
+func (r *rebroadcaster) onNewBlock(round basics.Round, txids []transactions.Txid) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastRound = round
+	for _, txid := range txids {
+		if e, has := r.entries[txid]; has && e.status.State == RebroadcastPending {
+			e.status.ConfirmedRound = round
+			e.finish(RebroadcastConfirmed, round)
+		}
+	}
+	for txid, e := range r.entries {
+		switch {
+		case e.status.State == RebroadcastPending && e.status.LastValid <= round:
+			e.finish(RebroadcastExpired, round)
+		case e.status.State != RebroadcastPending && e.doneRound+rebroadcastRetentionRounds < round:
+			delete(r.entries, txid)
+		}
+	}
+}
+
+// reject stops rebroadcasting a transaction that isn't valid anymore.
+func (r *rebroadcaster) reject(txid transactions.Txid, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, has := r.entries[txid]; has && e.status.State == RebroadcastPending {
+		e.status.Error = err.Error()
+		e.finish(RebroadcastRejected, r.lastRound)
+	}
+}
+
+// due returns the pending transactions that weren't broadcast for at least the rebroadcast interval.
+func (r *rebroadcaster) due(now time.Time) []transactions.SignedTxn {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var txns []transactions.SignedTxn
+	for _, e := range r.entries {
+		if e.status.State == RebroadcastPending && now.Sub(e.status.LastBroadcast) >= r.interval {
+			txns = append(txns, e.txn)
+		}
+	}
+	return txns
+}
+
+// freshPeers returns the peers among the given ones that the transaction wasn't sent to yet.
+// If it was sent to all of them already, all the peers are returned, as they may have dropped it since.
+func (r *rebroadcaster) freshPeers(txid transactions.Txid, peers []network.Peer) []network.Peer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, has := r.entries[txid]
+	if !has {
+		return nil
+	}
+	var fresh []network.Peer
+	for _, peer := range peers {
+		if !e.sentTo[peer] {
+			fresh = append(fresh, peer)
+		}
+	}
+	if len(fresh) == 0 {
+		return peers
+	}
+	return fresh
+}
+
+// sent records that the transaction was rebroadcast to the given peers.
+func (r *rebroadcaster) sent(txid transactions.Txid, peers []network.Peer, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, has := r.entries[txid]; has && e.status.State == RebroadcastPending {
+		e.status.LastBroadcast = now
+		e.status.Broadcasts++
+		e.markSent(peers)
+	}
+}
+
+// rebroadcastThread periodically sends the unconfirmed transactions submitted through this node to the peers
+// that didn't get them yet, putting them back into the transaction pool if they were evicted from it.
+func (node *AlgorandFullNode) rebroadcastThread() {
+	ticker := time.NewTicker(node.rebroadcaster.interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, txn := range node.rebroadcaster.due(time.Now()) {
+				node.rebroadcast(txn)
+			}
+		case <-node.ctx.Done():
+			return
+		}
+	}
+}
+
+func (node *AlgorandFullNode) rebroadcast(txn transactions.SignedTxn) {
+	txid := txn.ID()
+	if _, txErr, found := node.transactionPool.Lookup(txid); !found || txErr != "" {
+		if err := node.transactionPool.Remember(txn); err != nil {
+			if committed, cerr := node.ledger.Committed(txn); cerr == nil && committed {
+				// the block listener marks it as confirmed.
+				return
+			}
+			node.log.Infof("not rebroadcasting transaction %s: %v", txid, err)
+			node.rebroadcaster.reject(txid, err)
+			return
+		}
+	}
+
+	peers := node.rebroadcaster.freshPeers(txid, node.net.GetPeers(network.PeersConnectedOut, network.PeersConnectedIn))
+	if len(peers) == 0 {
+		return
+	}
+	data := protocol.Encode(txn)
+	var sentTo []network.Peer
+	for _, peer := range peers {
+		unicastPeer, ok := peer.(network.UnicastPeer)
+		if !ok {
+			continue
+		}
+		if err := unicastPeer.Unicast(node.ctx, data, protocol.TxnTag); err != nil {
+			node.log.Debugf("failure rebroadcasting transaction %s: %v", txid, err)
+			continue
+		}
+		sentTo = append(sentTo, peer)
+	}
+	if len(sentTo) > 0 {
+		node.rebroadcaster.sent(txid, sentTo, time.Now())
+	}
+}
+
+// onNewBlockRebroadcast updates the rebroadcast state of the tracked transactions according to a new block.
+func (node *AlgorandFullNode) onNewBlockRebroadcast(block bookkeeping.Block) {
+	txns, err := block.DecodePayset()
+	if err != nil {
+		node.log.Warnf("could not decode the transactions of block %d: %v", block.Round(), err)
+		return
+	}
+	txids := make([]transactions.Txid, len(txns))
+	for i, txn := range txns {
+		txids[i] = txn.ID()
+	}
+	node.rebroadcaster.onNewBlock(block.Round(), txids)
+}
+
+// RebroadcastStatus returns the rebroadcast status of a transaction submitted through this node, if it's tracked.
+func (node *AlgorandFullNode) RebroadcastStatus(txid transactions.Txid) (RebroadcastStatus, bool) {
+	if node.rebroadcaster == nil {
+		return RebroadcastStatus{}, false
+	}
+	return node.rebroadcaster.status(txid)
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/network"
+)
+
+type testPeer struct {
+	name string
+}
+
+func rebroadcastTestTxn(note byte, lastValid basics.Round) transactions.SignedTxn {
+	var stxn transactions.SignedTxn
+	stxn.Txn.Note = []byte{note}
+	stxn.Txn.LastValid = lastValid
+	return stxn
+}
+
+func TestRebroadcaster(t *testing.T) {
+	require.Nil(t, makeRebroadcaster(config.Local{TxRebroadcastIntervalSeconds: -1}))
+
+	r := makeRebroadcaster(config.Local{})
+	require.Equal(t, defaultTxRebroadcastInterval, r.interval)
+
+	a, b, c := &testPeer{"a"}, &testPeer{"b"}, &testPeer{"c"}
+	now := time.Now()
+	stxn := rebroadcastTestTxn(1, 100)
+	require.True(t, r.track(stxn, []network.Peer{a, b}, now))
+
+	status, ok := r.status(stxn.ID())
+	require.True(t, ok)
+	require.Equal(t, RebroadcastPending, status.State)
+	require.Equal(t, 1, status.Broadcasts)
+	require.Equal(t, 2, status.Peers)
+
+	require.Empty(t, r.due(now.Add(r.interval/2)))
+	require.Len(t, r.due(now.Add(r.interval)), 1)
+
+	// peers that didn't get the transaction are preferred, and all of them are used once there are none
+	require.Equal(t, []network.Peer{c}, r.freshPeers(stxn.ID(), []network.Peer{a, b, c}))
+	r.sent(stxn.ID(), []network.Peer{c}, now.Add(r.interval))
+	require.Equal(t, []network.Peer{a, b, c}, r.freshPeers(stxn.ID(), []network.Peer{a, b, c}))
+	status, _ = r.status(stxn.ID())
+	require.Equal(t, 2, status.Broadcasts)
+	require.Equal(t, 3, status.Peers)
+	require.Empty(t, r.due(now.Add(r.interval)))
+
+	// confirmed and expired transactions are no longer rebroadcast
+	expiring := rebroadcastTestTxn(2, 10)
+	require.True(t, r.track(expiring, nil, now))
+	r.onNewBlock(10, []transactions.Txid{stxn.ID()})
+	status, _ = r.status(stxn.ID())
+	require.Equal(t, RebroadcastConfirmed, status.State)
+	require.Equal(t, basics.Round(10), status.ConfirmedRound)
+	status, _ = r.status(expiring.ID())
+	require.Equal(t, RebroadcastExpired, status.State)
+	require.Empty(t, r.due(now.Add(time.Hour)))
+
+	rejected := rebroadcastTestTxn(3, 100)
+	require.True(t, r.track(rejected, nil, now))
+	r.reject(rejected.ID(), errors.New("overspend"))
+	status, _ = r.status(rejected.ID())
+	require.Equal(t, RebroadcastRejected, status.State)
+	require.Equal(t, "overspend", status.Error)
+
+	// the status of finished transactions is eventually forgotten
+	r.onNewBlock(10+rebroadcastRetentionRounds+1, nil)
+	for _, stxn := range []transactions.SignedTxn{stxn, expiring, rejected} {
+		_, ok = r.status(stxn.ID())
+		require.False(t, ok)
+	}
+}
+
+func TestRebroadcasterMaxTracked(t *testing.T) {
+	r := makeRebroadcaster(config.Local{TxRebroadcastMaxTracked: 2})
+	now := time.Now()
+	first, second, third := rebroadcastTestTxn(1, 100), rebroadcastTestTxn(2, 100), rebroadcastTestTxn(3, 100)
+	require.True(t, r.track(first, nil, now))
+	require.True(t, r.track(second, nil, now))
+	require.False(t, r.track(third, nil, now))
+
+	// finished transactions make room for new ones
+	r.onNewBlock(1, []transactions.Txid{first.ID()})
+	require.True(t, r.track(third, nil, now))
+	_, ok := r.status(first.ID())
+	require.False(t, ok)
+	_, ok = r.status(third.ID())
+	require.True(t, ok)
+}
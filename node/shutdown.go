@@ -0,0 +1,95 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+const defaultShutdownRoundTimeout = 10 * time.Second
+
+// ErrShuttingDown is returned for the transactions submitted once the node started shutting down.
+var ErrShuttingDown = errors.New("the node is shutting down and doesn't accept new transactions")
+
+// ShutdownStep is the time it took to stop one of the node subsystems, and whether the node gave up waiting for it.
+type ShutdownStep struct {
+	Subsystem string
+	Duration  time.Duration
+	TimedOut  bool
+}
+
+// shutdownTimer runs the shutdown steps, and records how long each of them took.
+type shutdownTimer struct {
+	steps []ShutdownStep
+}
+
+func (t *shutdownTimer) step(subsystem string, stop func()) {
+	start := time.Now()
+	stop()
+	t.steps = append(t.steps, ShutdownStep{Subsystem: subsystem, Duration: time.Since(start)})
+}
+
+// waitStep waits for done to be closed, for up to timeout, and returns false if it timed out.
+func (t *shutdownTimer) waitStep(subsystem string, done <-chan struct{}, timeout time.Duration) bool {
+	timedOut := false
+	t.step(subsystem, func() {
+		select {
+		case <-done:
+		case <-time.After(timeout):
+			timedOut = true
+		}
+	})
+	t.steps[len(t.steps)-1].TimedOut = timedOut
+	return !timedOut
+}
+
+// BeginShutdown makes the node refuse new transactions. The node keeps running otherwise, until Shutdown or Stop.
+func (node *AlgorandFullNode) BeginShutdown() {
+	atomic.StoreInt32(&node.shuttingDown, 1)
+}
+
+func (node *AlgorandFullNode) isShuttingDown() bool {
+	return atomic.LoadInt32(&node.shuttingDown) != 0
+}
+
+// Shutdown stops the node gracefully: it refuses new transactions, lets the round in progress complete when the node
+// participates in it, then stops the subsystems and flushes the ledger and the participation keys to disk, so that the
+// next start doesn't have to recover them. It returns how long each step took.
+func (node *AlgorandFullNode) Shutdown() []ShutdownStep {
+	var t shutdownTimer
+	node.BeginShutdown()
+	node.finishRound(&t)
+	return append(t.steps, node.stop()...)
+}
+
+// finishRound waits for the round in progress to be written to the ledger, if the node participates in it.
+func (node *AlgorandFullNode) finishRound(t *shutdownTimer) {
+	timeout := defaultShutdownRoundTimeout
+	if seconds := node.Config().ShutdownRoundTimeoutSeconds; seconds != 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	next := node.ledger.Latest() + 1
+	if timeout <= 0 || !node.accountManager.HasLiveKeys(next, next) {
+		t.step("round", func() {})
+		return
+	}
+	if !t.waitStep("round", node.ledger.Wait(next), timeout) {
+		node.log.Warnf("shutting down before round %d completed", next)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownWaitStep(t *testing.T) {
+	var timer shutdownTimer
+
+	done := make(chan struct{})
+	close(done)
+	require.True(t, timer.waitStep("done", done, time.Minute))
+
+	// a step which never completes is given up on once its timeout expires
+	timeout := 50 * time.Millisecond
+	require.False(t, timer.waitStep("stuck", make(chan struct{}), timeout))
+
+	timer.step("stop", func() {})
+
+	require.Len(t, timer.steps, 3)
+	require.Equal(t, "done", timer.steps[0].Subsystem)
+	require.False(t, timer.steps[0].TimedOut)
+	require.True(t, timer.steps[0].Duration < timeout)
+	require.Equal(t, "stuck", timer.steps[1].Subsystem)
+	require.True(t, timer.steps[1].TimedOut)
+	require.True(t, timer.steps[1].Duration >= timeout)
+	require.Equal(t, "stop", timer.steps[2].Subsystem)
+	require.False(t, timer.steps[2].TimedOut)
+}
@@ -57,6 +57,8 @@ type AlgodStartArgs struct {
 	RedirectOutput    bool
 	RunUnderHost      bool
 	TelemetryOverride string
+	// Profile is the config profile algod runs with, if not empty
+	Profile string
 }
 
 // KMDStartArgs are the possible arguments for starting kmd
@@ -24,11 +24,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/daemon/algod/api/client"
 	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/util"
 	"github.com/algorand/go-algorand/util/tokens"
@@ -108,7 +110,12 @@ func (nc NodeController) buildAlgodCommand(args AlgodStartArgs) *exec.Cmd {
 		cmd = nc.algod
 	}
 
-	return exec.Command(cmd, startArgs...)
+	algodCmd := exec.Command(cmd, startArgs...)
+	// the profile is passed through the environment, so that it also reaches algod when it runs under algoh
+	if args.Profile != "" {
+		algodCmd.Env = append(os.Environ(), config.ProfileEnvVariable+"="+args.Profile)
+	}
+	return algodCmd
 }
 
 // algodRunning returns a boolean indicating if algod is running
@@ -146,6 +153,15 @@ func (nc *NodeController) StopAlgod() (alreadyStopped bool, err error) {
 	return
 }
 
+// ReloadAlgodConfig signals the running algod to reload its config file
+func (nc NodeController) ReloadAlgodConfig() error {
+	algodPID, err := nc.GetAlgodPID()
+	if err != nil {
+		return err
+	}
+	return syscall.Kill(int(algodPID), syscall.SIGHUP)
+}
+
 // StartAlgod spins up an algod process and waits for it to begin
 func (nc *NodeController) StartAlgod(args AlgodStartArgs) (alreadyRunning bool, err error) {
 	// If algod is already running, we can't start again
@@ -333,6 +349,12 @@ func (nc NodeController) GetGenesisDir() (string, error) {
 func (nc NodeController) setAlgodCmdLogFiles(cmd *exec.Cmd) (files []*os.File) {
 	{ // Scoped to ensure err and out variables aren't mixed up
 		errFileName := filepath.Join(nc.algodDataDir, StdErrFilename)
+		if cfg, err := config.LoadConfigFromDisk(nc.algodDataDir); err == nil && cfg.EnableCrashReports {
+			// keep the report of a previous crash before the error output is overwritten
+			if _, err := logging.SaveStderrCrashReport(nc.algodDataDir, errFileName); err != nil {
+				fmt.Fprintf(os.Stderr, "error saving crash report: %v\n", err)
+			}
+		}
 		errFile, err := os.OpenFile(errFileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 		if err == nil {
 			cmd.Stderr = errFile
@@ -34,14 +34,19 @@ const (
 	Credential        HashID = "CR"
 	Genesis           HashID = "GE"
 	Message           HashID = "MX"
+	NetworkMetadata   HashID = "NM"
 	NetPrioResponse   HashID = "NPR"
 	OneTimeSigKey1    HashID = "OT1"
 	OneTimeSigKey2    HashID = "OT2"
 	PaysetFlat        HashID = "PF"
 	Payload           HashID = "PL"
+	Program           HashID = "Program"
+	ProgramData       HashID = "ProgData"
 	ProposerSeed      HashID = "PS"
+	ReleaseBundle     HashID = "RB"
 	Seed              HashID = "SD"
 	TestHashable      HashID = "TE"
+	TxGroup           HashID = "TG"
 	Transaction       HashID = "TX"
 	Vote              HashID = "VO"
 )
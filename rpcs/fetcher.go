@@ -47,6 +47,10 @@ type Fetcher interface {
 	// NumPeers return the number of peers that this fetcher has available
 	NumPeers() int
 
+	// ReportBadBlock tells the fetcher that the client served a block that failed verification, so that it
+	// prefers other sources
+	ReportBadBlock(client FetcherClient)
+
 	// Close cleans up this fetcher
 	Close()
 }
@@ -137,10 +141,32 @@ func (factory NetworkFetcherFactory) NewOverGossip(tag protocol.Tag) Fetcher {
 	return &ComposedFetcher{fetchers: []Fetcher{factory.New(), f}}
 }
 
+// unscoredPeerRate is the rate, in blocks per second, assumed for the peers that haven't served a block yet. It is
+// high so that new peers get tried.
+const unscoredPeerRate = 100.0
+
+// peerStats are the fetch statistics by which a NetworkFetcher scores a peer as a source of blocks
+type peerStats struct {
+	blocks   uint64        // blocks the peer served
+	failures uint64        // failed fetches, and blocks that failed verification
+	elapsed  time.Duration // time spent fetching the blocks the peer served
+}
+
+// score estimates the rate, in blocks per second, at which the peer serves good blocks: its throughput discounted
+// by its error rate.
+func (stats peerStats) score() float64 {
+	rate := unscoredPeerRate
+	if stats.blocks > 0 && stats.elapsed > 0 {
+		rate = float64(stats.blocks) / stats.elapsed.Seconds()
+	}
+	return rate * float64(stats.blocks+1) / float64(stats.blocks+stats.failures+1)
+}
+
 // NetworkFetcher fetches data from remote RPC clients
 type NetworkFetcher struct {
 	roundUpperBound map[FetcherClient]basics.Round
 	activeFetches   map[FetcherClient]int
+	stats           map[FetcherClient]*peerStats
 	peers           []FetcherClient
 	mu              deadlock.RWMutex
 	log             logging.Logger
@@ -188,13 +214,48 @@ func (networkFetcher *NetworkFetcher) selectClient(r basics.Round) (FetcherClien
 		return nil, errors.New("no peers to ask")
 	}
 
-	// select one of the peers at random
-	i := rand.Uint64() % uint64(len(availableClients))
-	client := availableClients[i]
+	// select one of the peers at random, in proportion to their scores
+	var total float64
+	for _, client := range availableClients {
+		total += networkFetcher.peerStats(client).score()
+	}
+	client := availableClients[len(availableClients)-1]
+	pick := rand.Float64() * total
+	for _, candidate := range availableClients {
+		pick -= networkFetcher.peerStats(candidate).score()
+		if pick < 0 {
+			client = candidate
+			break
+		}
+	}
 	networkFetcher.activeFetches[client] = networkFetcher.activeFetches[client] + 1
 	return client, nil
 }
 
+// peerStats returns the statistics of the client. The caller must hold the lock.
+func (networkFetcher *NetworkFetcher) peerStats(client FetcherClient) peerStats {
+	if stats, ok := networkFetcher.stats[client]; ok {
+		return *stats
+	}
+	return peerStats{}
+}
+
+// updatePeerStats applies update to the statistics of the client
+func (networkFetcher *NetworkFetcher) updatePeerStats(client FetcherClient, update func(*peerStats)) {
+	networkFetcher.mu.Lock()
+	defer networkFetcher.mu.Unlock()
+
+	if networkFetcher.stats == nil {
+		networkFetcher.stats = make(map[FetcherClient]*peerStats)
+	}
+	stats, ok := networkFetcher.stats[client]
+	if !ok {
+		stats = &peerStats{}
+		networkFetcher.stats[client] = stats
+	}
+	update(stats)
+}
+
 func (networkFetcher *NetworkFetcher) releaseClient(client FetcherClient) {
 	networkFetcher.mu.Lock()
 	defer networkFetcher.mu.Unlock()
@@ -220,17 +281,25 @@ func (networkFetcher *NetworkFetcher) FetchBlock(ctx context.Context, r basics.R
 	defer networkFetcher.releaseClient(client)
 	networkFetcher.log.Infof("networkFetcher.FetchBlock: asking client %v for block %v", client.Address(), r)
 
+	start := time.Now()
 	fetchedBuf, err := client.GetBlockBytes(ctx, r)
 	if err != nil {
 		networkFetcher.markPeerLastRound(client, r)
+		networkFetcher.updatePeerStats(client, func(stats *peerStats) { stats.failures++ })
 		err = fmt.Errorf("Peer %v: %v", client.Address(), err)
 		return
 	}
 	block, cert, err := processBlockBytes(fetchedBuf, r, client.Address())
 	if err != nil {
 		networkFetcher.markPeerLastRound(client, r)
+		networkFetcher.updatePeerStats(client, func(stats *peerStats) { stats.failures++ })
 		return
 	}
+	elapsed := time.Since(start)
+	networkFetcher.updatePeerStats(client, func(stats *peerStats) {
+		stats.blocks++
+		stats.elapsed += elapsed
+	})
 	return block, cert, client, nil
 }
 
@@ -250,6 +319,17 @@ func (networkFetcher *NetworkFetcher) OutOfPeers(round basics.Round) bool {
 	return len(networkFetcher.availablePeers(round)) == 0
 }
 
+// ReportBadBlock implements Fetcher.ReportBadBlock
+func (networkFetcher *NetworkFetcher) ReportBadBlock(client FetcherClient) {
+	// the peers are set once, when the fetcher is made
+	for _, peer := range networkFetcher.peers {
+		if peer == client {
+			networkFetcher.updatePeerStats(client, func(stats *peerStats) { stats.failures++ })
+			return
+		}
+	}
+}
+
 // Close implements Fetcher. Nothing to clean up here.
 func (networkFetcher *NetworkFetcher) Close() {}
 
@@ -289,6 +369,13 @@ func (cf *ComposedFetcher) FetchBlock(ctx context.Context, r basics.Round) (blk
 	return
 }
 
+// ReportBadBlock implements Fetcher.ReportBadBlock
+func (cf *ComposedFetcher) ReportBadBlock(client FetcherClient) {
+	for _, f := range cf.fetchers {
+		f.ReportBadBlock(client)
+	}
+}
+
 // Close implements Fetcher.Close
 func (cf *ComposedFetcher) Close() {
 	for _, f := range cf.fetchers {
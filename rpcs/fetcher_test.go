@@ -185,6 +185,41 @@ func TestSelectValidRemote(t *testing.T) {
 	require.False(t, hasNew)
 }
 
+func TestPeerStatsScore(t *testing.T) {
+	fast := peerStats{blocks: 10, elapsed: time.Second}
+	slow := peerStats{blocks: 10, elapsed: 10 * time.Second}
+	failing := peerStats{blocks: 10, failures: 30, elapsed: time.Second}
+
+	require.Equal(t, unscoredPeerRate, peerStats{}.score())
+	require.True(t, fast.score() > slow.score())
+	require.True(t, fast.score() > failing.score())
+}
+
+func TestSelectClientByScore(t *testing.T) {
+	fetcher := &NetworkFetcher{
+		roundUpperBound: make(map[FetcherClient]basics.Round),
+		activeFetches:   make(map[FetcherClient]int),
+		peers:           makeDummyFetchers(false, false),
+		log:             logging.TestingLog(t),
+	}
+	good, bad := fetcher.peers[0], fetcher.peers[1]
+	for i := 0; i < 100; i++ {
+		fetcher.ReportBadBlock(bad)
+	}
+	// clients this fetcher doesn't have are ignored
+	fetcher.ReportBadBlock(&dummyFetcher{})
+	require.Len(t, fetcher.stats, 1)
+
+	selected := make(map[FetcherClient]int)
+	for i := 0; i < 1000; i++ {
+		client, err := fetcher.selectClient(1)
+		require.NoError(t, err)
+		selected[client]++
+		fetcher.releaseClient(client)
+	}
+	require.True(t, selected[good] > selected[bad])
+}
+
 type dummyFetcher struct {
 	failWithNil   bool
 	failWithError bool
@@ -27,6 +27,7 @@ import (
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/network"
+	"github.com/algorand/go-algorand/util/tracing"
 )
 
 // set max fetcher size to 5MB, this is enough to fit the block and certificate
@@ -71,9 +72,11 @@ func (hf *HTTPFetcher) GetBlockBytes(ctx context.Context, r basics.Round) (data
 	if err != nil {
 		return nil, err
 	}
-	request = request.WithContext(ctx)
+	request, span := tracing.StartRequestSpan(request.WithContext(ctx), "rpcs.GetBlockBytes", tracing.Attr(logging.RoundField, uint64(r)))
+	defer span.End()
 	response, err := hf.client.Do(request)
 	if err != nil {
+		span.SetError(err)
 		hf.log.Debugf("GET %#v : %s", blockURL, err)
 		return nil, err
 	}
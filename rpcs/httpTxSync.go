@@ -32,6 +32,7 @@ import (
 	"github.com/algorand/go-algorand/network"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/util/bloom"
+	"github.com/algorand/go-algorand/util/tracing"
 )
 
 // HTTPTxSync implements the TxSyncClient interface over HTTP
@@ -120,9 +121,11 @@ func (hts *HTTPTxSync) Sync(ctx context.Context, bloom *bloom.Filter) (txns []tr
 		return nil, err
 	}
 	request.Header.Set("Content-Type", requestContentType)
-	request = request.WithContext(ctx)
+	request, span := tracing.StartRequestSpan(request.WithContext(ctx), "rpcs.TxSync")
+	defer span.End()
 	response, err := client.Do(request)
 	if err != nil {
+		span.SetError(err)
 		hts.log.Warnf("txSync POST %v: %s", syncURL, err)
 		return nil, err
 	}
@@ -136,6 +136,11 @@ func (wsf *WsFetcher) NumPeers() int {
 	return wsf.f.NumPeers()
 }
 
+// ReportBadBlock implements Fetcher interface
+func (wsf *WsFetcher) ReportBadBlock(client FetcherClient) {
+	wsf.f.ReportBadBlock(client)
+}
+
 // Close calls a delegate close fn passed in by the parent of this fetcher
 func (wsf *WsFetcher) Close() {
 	wsf.f.Close()
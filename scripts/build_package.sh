@@ -58,12 +58,17 @@ DEFAULT_RELEASE_NETWORK=$(./scripts/compute_branch_release_network.sh "${DEFAULT
 mkdir ${PKG_ROOT}/bin
 
 # If you modify this list, also update this list in ./cmd/updater/update.sh backup_binaries()
-bin_files=("algod" "algoh" "algokey" "carpenter" "catchupsrv" "diagcfg" "find-nodes.sh" "goal" "kmd" "msgpacktool" "node_exporter" "update.sh" "updatekey.json" "updater" "COPYING")
+bin_files=("algod" "algodump" "algoh" "algokey" "carpenter" "catchupsrv" "diagcfg" "find-nodes.sh" "goal" "kmd" "msgpacktool" "node_exporter" "update.sh" "updatekey.json" "updater" "COPYING")
 for bin in "${bin_files[@]}"; do
     cp ${GOPATH}/bin/${bin} ${PKG_ROOT}/bin
     if [ $? -ne 0 ]; then exit 1; fi
 done
 
+# The pinned release signing keys are only shipped by builds which have them
+if [ -f ${GOPATH}/bin/releasekeys.json ]; then
+    cp ${GOPATH}/bin/releasekeys.json ${PKG_ROOT}/bin
+fi
+
 # Copy systemd setup script and templates
 cp "cmd/updater/systemd-setup.sh" ${PKG_ROOT}/bin
 if [ $? -ne 0 ]; then exit 1; fi
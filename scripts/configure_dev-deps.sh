@@ -4,3 +4,4 @@ go get -u golang.org/x/lint/golint
 go get -u github.com/golang/dep/cmd/dep
 go get -u golang.org/x/tools/cmd/stringer
 go get -u github.com/go-swagger/go-swagger/cmd/swagger
+go get -u github.com/golang/protobuf/protoc-gen-go
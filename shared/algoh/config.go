@@ -25,6 +25,16 @@ import (
 // ConfigFilename is the name of algoh's config file
 const ConfigFilename = "host-config.json"
 
+// The restart policies of algod.
+const (
+	// RestartNever exits algoh along with algod.
+	RestartNever = "never"
+	// RestartOnFailure restarts algod when it exits with an error, or when it is found unhealthy.
+	RestartOnFailure = "on-failure"
+	// RestartAlways restarts algod whenever it exits.
+	RestartAlways = "always"
+)
+
 // HostConfig is algoh's configuration structure
 type HostConfig struct {
 	SendBlockStats bool
@@ -32,6 +42,38 @@ type HostConfig struct {
 	DeadManTimeSec int64
 	StatusDelayMS  int64
 	StallDelayMS   int64
+
+	// RestartPolicy is one of never, on-failure or always.
+	RestartPolicy string
+	// RestartDelayMS is the delay before the first restart, which is multiplied by RestartBackoffFactor after every
+	// further restart, up to RestartMaxDelayMS.
+	RestartDelayMS       int64
+	RestartMaxDelayMS    int64
+	RestartBackoffFactor float64
+	// RestartResetSec is the time algod has to keep running for the restart delay to go back to RestartDelayMS.
+	RestartResetSec int64
+	// MaxRestarts is the number of restarts within MaxRestartsWindowSec after which algod is considered to be
+	// persistently failing, and algoh gives up on it; 0 restarts it indefinitely.
+	MaxRestarts          int
+	MaxRestartsWindowSec int64
+
+	// HealthCheckIntervalSec is the interval between the REST health probes of algod; 0 disables them.
+	HealthCheckIntervalSec int64
+	// HealthCheckFailures is the number of consecutive failed health probes after which algod is restarted.
+	HealthCheckFailures int
+	// RoundStallRestartSec is the time without a new round after which algod is restarted; 0 disables it.
+	RoundStallRestartSec int64
+
+	// NotifyWebhookURLs are posted a JSON event whenever algod is restarted, found unhealthy or given up on.
+	NotifyWebhookURLs []string `json:",omitempty"`
+	// NotifyWebhookSecret signs the webhook events with HMAC-SHA256, in the X-Algorand-Signature header.
+	NotifyWebhookSecret string `json:",omitempty"`
+	// NotifySMTPServer is the host:port of the SMTP server the notification emails are sent through.
+	NotifySMTPServer   string   `json:",omitempty"`
+	NotifySMTPUsername string   `json:",omitempty"`
+	NotifySMTPPassword string   `json:",omitempty"`
+	NotifyEmailFrom    string   `json:",omitempty"`
+	NotifyEmailTo      []string `json:",omitempty"`
 }
 
 var defaultConfig = HostConfig{
@@ -40,6 +82,18 @@ var defaultConfig = HostConfig{
 	DeadManTimeSec: 120,
 	StatusDelayMS:  500,
 	StallDelayMS:   60 * 1000,
+
+	RestartPolicy:        RestartNever,
+	RestartDelayMS:       1000,
+	RestartMaxDelayMS:    5 * 60 * 1000,
+	RestartBackoffFactor: 2,
+	RestartResetSec:      10 * 60,
+	MaxRestarts:          5,
+	MaxRestartsWindowSec: 60 * 60,
+
+	HealthCheckIntervalSec: 0,
+	HealthCheckFailures:    3,
+	RoundStallRestartSec:   0,
 }
 
 // LoadConfigFromFile loads the configuration from the specified file, merging into the default configuration.
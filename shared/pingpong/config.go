@@ -45,6 +45,8 @@ type PpConfig struct {
 	MinAccountFunds uint64
 	Quiet           bool
 	RandomNote      bool
+	// Profile, when set, replaces the simple payments with the transaction mix of a workload profile
+	Profile *WorkloadProfile `json:",omitempty"`
 }
 
 // DefaultConfig object for Ping Pong
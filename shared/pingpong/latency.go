@@ -0,0 +1,160 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package pingpong
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds of the latency histogram buckets; the last bucket holds everything above them.
+var latencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// latencyHistogram counts latencies in the latencyBuckets.
+type latencyHistogram struct {
+	counts []uint64
+	count  uint64
+	sum    time.Duration
+	max    time.Duration
+}
+
+func makeLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{counts: make([]uint64, len(latencyBuckets)+1)}
+}
+
+func (h *latencyHistogram) add(latency time.Duration) {
+	i := sort.Search(len(latencyBuckets), func(i int) bool { return latency <= latencyBuckets[i] })
+	h.counts[i]++
+	h.count++
+	h.sum += latency
+	if latency > h.max {
+		h.max = latency
+	}
+}
+
+// percentile returns the upper bound of the bucket holding the given percentile, or the maximal latency when it
+// falls in the last bucket.
+func (h *latencyHistogram) percentile(p float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	target := uint64(p * float64(h.count))
+	if target == 0 {
+		target = 1
+	}
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= target {
+			if i < len(latencyBuckets) && latencyBuckets[i] < h.max {
+				return latencyBuckets[i]
+			}
+			return h.max
+		}
+	}
+	return h.max
+}
+
+func (h *latencyHistogram) write(w io.Writer, name string) {
+	if h.count == 0 {
+		fmt.Fprintf(w, "  %-8s no samples\n", name)
+		return
+	}
+	fmt.Fprintf(w, "  %-8s n=%d avg=%v p50<=%v p90<=%v p99<=%v max=%v\n", name, h.count,
+		(h.sum / time.Duration(h.count)).Round(time.Millisecond), h.percentile(0.5), h.percentile(0.9), h.percentile(0.99),
+		h.max.Round(time.Millisecond))
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		if i < len(latencyBuckets) {
+			fmt.Fprintf(w, "    <= %-8v %d\n", latencyBuckets[i], c)
+		} else {
+			fmt.Fprintf(w, "     > %-8v %d\n", latencyBuckets[len(latencyBuckets)-1], c)
+		}
+	}
+}
+
+// latencyStats keeps the submission and confirmation latency histograms of every transaction type.
+type latencyStats struct {
+	mu           sync.Mutex
+	submit       map[string]*latencyHistogram
+	confirmation map[string]*latencyHistogram
+}
+
+func makeLatencyStats() *latencyStats {
+	return &latencyStats{
+		submit:       make(map[string]*latencyHistogram),
+		confirmation: make(map[string]*latencyHistogram),
+	}
+}
+
+func (s *latencyStats) record(histograms map[string]*latencyHistogram, txnType string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := histograms[txnType]
+	if !ok {
+		h = makeLatencyHistogram()
+		histograms[txnType] = h
+	}
+	h.add(latency)
+}
+
+func (s *latencyStats) recordSubmit(txnType string, latency time.Duration) {
+	s.record(s.submit, txnType, latency)
+}
+
+func (s *latencyStats) recordConfirmation(txnType string, latency time.Duration) {
+	s.record(s.confirmation, txnType, latency)
+}
+
+// write outputs the latency histograms of every transaction type, and resets them.
+func (s *latencyStats) write(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	types := make([]string, 0, len(s.submit))
+	for txnType := range s.submit {
+		types = append(types, txnType)
+	}
+	sort.Strings(types)
+	for _, txnType := range types {
+		fmt.Fprintf(w, "Latency of %s transactions:\n", txnType)
+		s.submit[txnType].write(w, "submit")
+		confirmation, ok := s.confirmation[txnType]
+		if !ok {
+			confirmation = makeLatencyHistogram()
+		}
+		confirmation.write(w, "confirm")
+	}
+	s.submit = make(map[string]*latencyHistogram)
+	s.confirmation = make(map[string]*latencyHistogram)
+}
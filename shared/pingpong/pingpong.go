@@ -22,6 +22,7 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/algorand/go-algorand/crypto"
@@ -123,6 +124,12 @@ func RunPingPong(ctx context.Context, ac libgoal.Client, accounts map[string]uin
 	restTime := cfg.RestTime
 	refreshTime := time.Now().Add(cfg.RefreshTime)
 
+	var profile *profileSender
+	if cfg.Profile != nil {
+		profile = makeProfileSender(ac, cfg)
+		go profile.tracker.run(ctx)
+	}
+
 	for {
 		if ctx.Err() != nil {
 			break
@@ -135,7 +142,13 @@ func RunPingPong(ctx context.Context, ac libgoal.Client, accounts map[string]uin
 			fromList := listSufficientAccounts(accounts, (cfg.MaxAmt+cfg.MaxFee)*2, cfg.SrcAccount)
 			toList := listSufficientAccounts(accounts, 0, cfg.SrcAccount)
 
-			sent, succeded, err := sendFromTo(fromList, toList, ac, cfg)
+			var sent, succeded uint64
+			var err error
+			if profile != nil {
+				sent, succeded, err = profile.send(fromList, toList)
+			} else {
+				sent, succeded, err = sendFromTo(fromList, toList, ac, cfg)
+			}
 			totalSent += sent
 			totalSucceeded += succeded
 			if err != nil {
@@ -153,6 +166,9 @@ func RunPingPong(ctx context.Context, ac libgoal.Client, accounts map[string]uin
 		}
 		timeDelta := time.Now().Sub(startTime)
 		fmt.Fprintf(os.Stdout, "Sent %d transactions (%d attempted) in %d seconds\n", totalSucceeded, totalSent, int(math.Round(timeDelta.Seconds())))
+		if profile != nil {
+			profile.stats.write(os.Stdout)
+		}
 		if cfg.RestTime > 0 {
 			fmt.Fprintf(os.Stdout, "Pausing %d seconds before sending more transactions\n", int(math.Round(cfg.RestTime.Seconds())))
 			time.Sleep(restTime)
@@ -184,22 +200,15 @@ func sendFromTo(fromList, toList []string, client libgoal.Client, cfg PpConfig)
 			to = addr.GetChecksumAddress().String()
 		}
 
-		var noteField []byte
-		const pingpongTag = "pingpong"
-		const tagLen = uint32(len(pingpongTag))
-		const randomBaseLen = uint32(8)
-		const maxNoteFieldLen = uint32(1024)
-		var noteLength = uint32(tagLen) + randomBaseLen
+		noteLength := uint32(defaultNoteSize)
 		// if random note flag set, then append a random number of additional bytes
 		if cfg.RandomNote {
-			noteLength = noteLength + rand.Uint32()%(maxNoteFieldLen-noteLength)
+			noteLength = noteLength + rand.Uint32()%(maxNoteSize-noteLength)
 		}
-		noteField = make([]byte, noteLength, noteLength)
-		copy(noteField, pingpongTag)
-		crypto.RandBytes(noteField[tagLen:])
+		noteField := makeNote(noteLength)
 
 		sentCount++
-		_, sendErr := client.SendPaymentFromUnencryptedWallet(from, to, fee, amt, noteField[:])
+		_, sendErr := client.SendPaymentFromUnencryptedWallet(from, to, fee, amt, noteField)
 		if sendErr != nil && !cfg.Quiet {
 			fmt.Fprintf(os.Stderr, "error sending transaction: %v\n", err)
 		} else {
@@ -215,3 +224,198 @@ func sendFromTo(fromList, toList []string, client libgoal.Client, cfg PpConfig)
 	}
 	return
 }
+
+const pingpongTag = "pingpong"
+
+// defaultNoteSize is the size of the notes holding the ping pong tag followed by 8 random bytes.
+const defaultNoteSize = len(pingpongTag) + 8
+
+// makeNote returns a note of the given size, starting with as much of the ping pong tag as fits, followed by random bytes.
+func makeNote(size uint32) []byte {
+	if size == 0 {
+		return nil
+	}
+	note := make([]byte, size)
+	n := copy(note, pingpongTag)
+	crypto.RandBytes(note[n:])
+	return note
+}
+
+// profileSender sends the transactions of a workload profile, at the rate of the profile.
+type profileSender struct {
+	client  libgoal.Client
+	cfg     PpConfig
+	rand    *rand.Rand
+	next    time.Time
+	stats   *latencyStats
+	tracker *confirmationTracker
+}
+
+func makeProfileSender(client libgoal.Client, cfg PpConfig) *profileSender {
+	stats := makeLatencyStats()
+	return &profileSender{
+		client:  client,
+		cfg:     cfg,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		stats:   stats,
+		tracker: makeConfirmationTracker(client, stats),
+	}
+}
+
+// pace waits for the time the next transaction is due at, according to the rate of the profile.
+func (ps *profileSender) pace() {
+	if ps.cfg.Profile.TxnsPerSecond == 0 {
+		if ps.cfg.DelayBetweenTxn > 0 {
+			time.Sleep(ps.cfg.DelayBetweenTxn)
+		}
+		return
+	}
+	interval := time.Duration(float64(time.Second) / ps.cfg.Profile.TxnsPerSecond)
+	now := time.Now()
+	if ps.next.Before(now) {
+		// don't try to catch up with the transactions that weren't sent in time
+		ps.next = now
+	}
+	time.Sleep(ps.next.Sub(now))
+	ps.next = ps.next.Add(interval)
+}
+
+// send sends a transaction from every account of fromList, whose type and note size are drawn from the profile.
+func (ps *profileSender) send(fromList, toList []string) (sentCount, successCount uint64, err error) {
+	cfg := ps.cfg
+	for i, from := range fromList {
+		amt := cfg.MaxAmt
+		if cfg.RandomizeAmt {
+			amt = rand.Uint64()%cfg.MaxAmt + 1
+		}
+		fee := cfg.MaxFee
+		if cfg.RandomizeFee {
+			fee = rand.Uint64()%(cfg.MaxFee-cfg.MinFee) + cfg.MinFee
+		}
+		to := toList[i]
+		if cfg.RandomizeDst {
+			var addr basics.Address
+			crypto.RandBytes(addr[:])
+			to = addr.GetChecksumAddress().String()
+		}
+		txn := cfg.Profile.pickTxn(ps.rand)
+		note := makeNote(cfg.Profile.pickNoteSize(ps.rand, txn))
+
+		ps.pace()
+		if !cfg.Quiet {
+			fmt.Fprintf(os.Stdout, "Sending %s (%d byte note) : %s -> %s\n", txn.Type, len(note), from, to)
+		}
+		sentCount++
+		start := time.Now()
+		txid, sendErr := ps.sendTxn(txn.Type, from, to, fee, amt, note)
+		if sendErr != nil {
+			if !cfg.Quiet {
+				fmt.Fprintf(os.Stderr, "error sending %s transaction: %v\n", txn.Type, sendErr)
+			}
+			err = sendErr
+			return
+		}
+		successCount++
+		ps.stats.recordSubmit(txn.Type, time.Since(start))
+		ps.tracker.track(txid, txn.Type, start)
+	}
+	return
+}
+
+func (ps *profileSender) sendTxn(txnType string, from, to string, fee, amt uint64, note []byte) (string, error) {
+	switch txnType {
+	case PaymentTxnType:
+		tx, err := ps.client.SendPaymentFromUnencryptedWallet(from, to, fee, amt, note)
+		if err != nil {
+			return "", err
+		}
+		return tx.ID().String(), nil
+	case KeyregTxnType:
+		if fee == 0 {
+			fee = ps.cfg.MinFee
+		}
+		tx, err := ps.client.MakeUnsignedGoOfflineTx(from, 0, 0, fee, [32]byte{})
+		if err != nil {
+			return "", err
+		}
+		tx.Note = note
+		wh, err := ps.client.GetUnencryptedWalletHandle()
+		if err != nil {
+			return "", err
+		}
+		return ps.client.SignAndBroadcastTransaction(wh, nil, tx)
+	default:
+		return "", fmt.Errorf("unsupported transaction type '%s'", txnType)
+	}
+}
+
+// maxTrackedTxns is the number of unconfirmed transactions whose confirmation is tracked at once; the confirmation
+// latency of the transactions sent while that many are pending isn't measured.
+const maxTrackedTxns = 1000
+
+// untrackedTxnTimeout is the time after which a transaction that wasn't confirmed is no longer tracked.
+const untrackedTxnTimeout = 5 * time.Minute
+
+type trackedTxn struct {
+	txnType string
+	sent    time.Time
+}
+
+// confirmationTracker polls the node for the confirmation of the sent transactions, and records their latency.
+type confirmationTracker struct {
+	client  libgoal.Client
+	stats   *latencyStats
+	mu      sync.Mutex
+	pending map[string]trackedTxn
+}
+
+func makeConfirmationTracker(client libgoal.Client, stats *latencyStats) *confirmationTracker {
+	return &confirmationTracker{
+		client:  client,
+		stats:   stats,
+		pending: make(map[string]trackedTxn),
+	}
+}
+
+func (t *confirmationTracker) track(txid string, txnType string, sent time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.pending) < maxTrackedTxns {
+		t.pending[txid] = trackedTxn{txnType: txnType, sent: sent}
+	}
+}
+
+func (t *confirmationTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		t.mu.Lock()
+		txids := make([]string, 0, len(t.pending))
+		for txid := range t.pending {
+			txids = append(txids, txid)
+		}
+		t.mu.Unlock()
+
+		for _, txid := range txids {
+			info, err := t.client.PendingTransactionInformation(txid)
+			now := time.Now()
+			t.mu.Lock()
+			tracked := t.pending[txid]
+			switch {
+			case err == nil && info.ConfirmedRound > 0:
+				t.stats.recordConfirmation(tracked.txnType, now.Sub(tracked.sent))
+				delete(t.pending, txid)
+			case err == nil && info.PoolError != "":
+				delete(t.pending, txid)
+			case now.Sub(tracked.sent) > untrackedTxnTimeout:
+				delete(t.pending, txid)
+			}
+			t.mu.Unlock()
+		}
+	}
+}
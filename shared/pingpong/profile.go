@@ -0,0 +1,180 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package pingpong
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+)
+
+// The transaction types a workload profile may generate.
+const (
+	// PaymentTxnType sends payments between the participating accounts.
+	PaymentTxnType = "pay"
+	// KeyregTxnType sends offline key registrations from the participating accounts.
+	KeyregTxnType = "keyreg"
+)
+
+var supportedTxnTypes = []string{PaymentTxnType, KeyregTxnType}
+
+// unsupportedTxnTypes are the transaction types that profiles may name, but that the protocol doesn't have yet.
+var unsupportedTxnTypes = map[string]string{
+	"axfer": "asset transfers",
+	"appl":  "application calls",
+	"group": "grouped transactions",
+}
+
+// WorkloadProfile describes the mix of transactions generated by ping pong, and the rate they are sent at.
+type WorkloadProfile struct {
+	// Accounts is the number of participating accounts; 0 keeps the NumPartAccounts of the configuration.
+	Accounts uint32
+	// TxnsPerSecond is the rate the transactions of all the types are sent at; 0 sends them as fast as possible,
+	// with the DelayBetweenTxn of the configuration between them.
+	TxnsPerSecond float64
+	// Txns lists the generated transaction types along with their relative weights.
+	Txns []TxnProfile
+	// NoteSizes is the distribution of the note sizes of the transactions that don't override it.
+	NoteSizes []NoteSizeProfile `json:",omitempty"`
+}
+
+// TxnProfile is the share of a single transaction type in the workload.
+type TxnProfile struct {
+	// Type is one of pay or keyreg.
+	Type   string
+	Weight uint32
+	// NoteSizes overrides the note size distribution of the profile for this transaction type.
+	NoteSizes []NoteSizeProfile `json:",omitempty"`
+}
+
+// NoteSizeProfile is the share of the transactions whose note has the given size.
+type NoteSizeProfile struct {
+	Size   uint32
+	Weight uint32
+}
+
+// maxNoteSize is the largest note the protocol accepts.
+const maxNoteSize = 1024
+
+// LoadProfileFromFile reads and validates a workload profile.
+func LoadProfileFromFile(file string) (profile WorkloadProfile, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&profile); err != nil {
+		return
+	}
+	err = profile.Validate()
+	return
+}
+
+// Validate checks that the profile only uses the supported transaction types, and that its weights and sizes are valid.
+func (p WorkloadProfile) Validate() error {
+	if p.TxnsPerSecond < 0 {
+		return fmt.Errorf("TxnsPerSecond must not be negative")
+	}
+	if len(p.Txns) == 0 {
+		return fmt.Errorf("the profile must list at least one transaction type")
+	}
+	var totalWeight uint64
+	for _, txn := range p.Txns {
+		if description, ok := unsupportedTxnTypes[txn.Type]; ok {
+			return fmt.Errorf("the current protocol doesn't support %s ('%s'); the supported types are %s", description, txn.Type, strings.Join(supportedTxnTypes, ", "))
+		}
+		if !isSupportedTxnType(txn.Type) {
+			return fmt.Errorf("unknown transaction type '%s'; the supported types are %s", txn.Type, strings.Join(supportedTxnTypes, ", "))
+		}
+		if err := validateNoteSizes(txn.NoteSizes); err != nil {
+			return fmt.Errorf("%s: %v", txn.Type, err)
+		}
+		totalWeight += uint64(txn.Weight)
+	}
+	if totalWeight == 0 {
+		return fmt.Errorf("at least one transaction type must have a positive weight")
+	}
+	return validateNoteSizes(p.NoteSizes)
+}
+
+func isSupportedTxnType(txnType string) bool {
+	for _, t := range supportedTxnTypes {
+		if t == txnType {
+			return true
+		}
+	}
+	return false
+}
+
+func validateNoteSizes(sizes []NoteSizeProfile) error {
+	var totalWeight uint64
+	for _, s := range sizes {
+		if s.Size > maxNoteSize {
+			return fmt.Errorf("note size %d exceeds the maximal note size %d", s.Size, maxNoteSize)
+		}
+		totalWeight += uint64(s.Weight)
+	}
+	if len(sizes) > 0 && totalWeight == 0 {
+		return fmt.Errorf("at least one note size must have a positive weight")
+	}
+	return nil
+}
+
+// pickTxn returns a random transaction type of the profile, according to their weights.
+func (p WorkloadProfile) pickTxn(r *rand.Rand) TxnProfile {
+	var totalWeight uint64
+	for _, txn := range p.Txns {
+		totalWeight += uint64(txn.Weight)
+	}
+	pick := uint64(r.Int63n(int64(totalWeight)))
+	for _, txn := range p.Txns {
+		if pick < uint64(txn.Weight) {
+			return txn
+		}
+		pick -= uint64(txn.Weight)
+	}
+	return p.Txns[len(p.Txns)-1]
+}
+
+// pickNoteSize returns a random note size for the given transaction type. Without any note size distribution, the
+// notes are just large enough for the ping pong tag and a random suffix.
+func (p WorkloadProfile) pickNoteSize(r *rand.Rand, txn TxnProfile) uint32 {
+	sizes := txn.NoteSizes
+	if len(sizes) == 0 {
+		sizes = p.NoteSizes
+	}
+	if len(sizes) == 0 {
+		return uint32(defaultNoteSize)
+	}
+	var totalWeight uint64
+	for _, s := range sizes {
+		totalWeight += uint64(s.Weight)
+	}
+	pick := uint64(r.Int63n(int64(totalWeight)))
+	for _, s := range sizes {
+		if pick < uint64(s.Weight) {
+			return s.Size
+		}
+		pick -= uint64(s.Weight)
+	}
+	return sizes[len(sizes)-1].Size
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package pingpong
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProfileFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pingpong")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "profile.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{
+		"Accounts": 20,
+		"TxnsPerSecond": 50,
+		"Txns": [
+			{"Type": "pay", "Weight": 9},
+			{"Type": "keyreg", "Weight": 1, "NoteSizes": [{"Size": 0, "Weight": 1}]}
+		],
+		"NoteSizes": [{"Size": 16, "Weight": 3}, {"Size": 1024, "Weight": 1}]
+	}`), 0600))
+	profile, err := LoadProfileFromFile(file)
+	require.NoError(t, err)
+	require.Equal(t, uint32(20), profile.Accounts)
+	require.Equal(t, float64(50), profile.TxnsPerSecond)
+	require.Len(t, profile.Txns, 2)
+
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"Txns": [{"Type": "pay", "Weight": 1}], "Rate": 5}`), 0600))
+	_, err = LoadProfileFromFile(file)
+	require.Error(t, err)
+}
+
+func TestProfileValidate(t *testing.T) {
+	require.NoError(t, WorkloadProfile{Txns: []TxnProfile{{Type: PaymentTxnType, Weight: 1}}}.Validate())
+
+	invalid := []WorkloadProfile{
+		{},
+		{Txns: []TxnProfile{{Type: PaymentTxnType}}},
+		{Txns: []TxnProfile{{Type: "transfer", Weight: 1}}},
+		{Txns: []TxnProfile{{Type: PaymentTxnType, Weight: 1}}, TxnsPerSecond: -1},
+		{Txns: []TxnProfile{{Type: PaymentTxnType, Weight: 1}}, NoteSizes: []NoteSizeProfile{{Size: 2048, Weight: 1}}},
+		{Txns: []TxnProfile{{Type: PaymentTxnType, Weight: 1, NoteSizes: []NoteSizeProfile{{Size: 10}}}}},
+	}
+	for _, p := range invalid {
+		require.Error(t, p.Validate(), "%+v", p)
+	}
+
+	for txnType := range unsupportedTxnTypes {
+		err := WorkloadProfile{Txns: []TxnProfile{{Type: txnType, Weight: 1}}}.Validate()
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "doesn't support"), err.Error())
+	}
+}
+
+func TestProfilePick(t *testing.T) {
+	profile := WorkloadProfile{
+		Txns: []TxnProfile{
+			{Type: PaymentTxnType, Weight: 3},
+			{Type: KeyregTxnType, Weight: 1, NoteSizes: []NoteSizeProfile{{Size: 0, Weight: 1}}},
+		},
+		NoteSizes: []NoteSizeProfile{{Size: 100, Weight: 1}, {Size: 200, Weight: 1}, {Size: 300}},
+	}
+	r := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	sizes := make(map[string]map[uint32]int)
+	const samples = 10000
+	for i := 0; i < samples; i++ {
+		txn := profile.pickTxn(r)
+		counts[txn.Type]++
+		if sizes[txn.Type] == nil {
+			sizes[txn.Type] = make(map[uint32]int)
+		}
+		sizes[txn.Type][profile.pickNoteSize(r, txn)]++
+	}
+	require.InDelta(t, 0.75, float64(counts[PaymentTxnType])/samples, 0.03)
+	require.InDelta(t, 0.25, float64(counts[KeyregTxnType])/samples, 0.03)
+	require.Equal(t, map[uint32]int{0: counts[KeyregTxnType]}, sizes[KeyregTxnType])
+	require.Len(t, sizes[PaymentTxnType], 2)
+	require.Zero(t, sizes[PaymentTxnType][300])
+
+	require.Equal(t, uint32(defaultNoteSize), WorkloadProfile{}.pickNoteSize(r, TxnProfile{}))
+}
+
+func TestMakeNote(t *testing.T) {
+	require.Nil(t, makeNote(0))
+	require.Equal(t, "ping", string(makeNote(4)))
+	note := makeNote(100)
+	require.Len(t, note, 100)
+	require.True(t, strings.HasPrefix(string(note), pingpongTag))
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	h := makeLatencyHistogram()
+	require.Zero(t, h.percentile(0.5))
+	for i := 0; i < 90; i++ {
+		h.add(20 * time.Millisecond)
+	}
+	for i := 0; i < 9; i++ {
+		h.add(400 * time.Millisecond)
+	}
+	h.add(time.Minute)
+	require.Equal(t, uint64(100), h.count)
+	require.Equal(t, 25*time.Millisecond, h.percentile(0.5))
+	require.Equal(t, 25*time.Millisecond, h.percentile(0.9))
+	require.Equal(t, 500*time.Millisecond, h.percentile(0.99))
+	require.Equal(t, time.Minute, h.percentile(1))
+
+	stats := makeLatencyStats()
+	stats.recordSubmit(PaymentTxnType, 5*time.Millisecond)
+	stats.recordConfirmation(PaymentTxnType, 4*time.Second)
+	stats.recordSubmit(KeyregTxnType, 5*time.Millisecond)
+	var out strings.Builder
+	stats.write(&out)
+	output := out.String()
+	require.Contains(t, output, "Latency of keyreg transactions:")
+	require.Contains(t, output, "Latency of pay transactions:")
+	require.Contains(t, output, "confirm  no samples")
+	require.Contains(t, output, "<= 5s")
+	require.Empty(t, stats.submit)
+}
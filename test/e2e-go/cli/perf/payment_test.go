@@ -58,7 +58,7 @@ func BenchmarkSendPayment(b *testing.B) {
 		for i := 0; i < b.N; i++ {
 			var nonce [8]byte
 			crypto.RandBytes(nonce[:])
-			tx, err = c.ConstructPayment(addr, addr, 1, 1, nonce[:], "")
+			tx, err = c.ConstructPayment(addr, addr, 1, 1, nonce[:], "", [32]byte{})
 			require.NoError(b, err)
 		}
 	})
@@ -74,7 +74,7 @@ func BenchmarkSendPayment(b *testing.B) {
 		for i := 0; i < b.N; i++ {
 			var nonce [8]byte
 			crypto.RandBytes(nonce[:])
-			_, err := c.SendPaymentFromWallet(wallet, nil, addr, addr, 1, 1, nonce[:], "")
+			_, err := c.SendPaymentFromWallet(wallet, nil, addr, addr, 1, 1, nonce[:], "", [32]byte{})
 			require.NoError(b, err)
 		}
 	})
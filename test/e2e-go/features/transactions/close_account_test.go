@@ -62,7 +62,7 @@ func TestAccountsCanClose(t *testing.T) {
 	a.NoError(err)
 	fixture.WaitForConfirmedTxn(status.LastRound+10, baseAcct, tx.ID().String())
 
-	tx, err = client.SendPaymentFromWallet(walletHandle, nil, acct0, acct1, 1, 100000, nil, acct2)
+	tx, err = client.SendPaymentFromWallet(walletHandle, nil, acct0, acct1, 1, 100000, nil, acct2, [32]byte{})
 	a.NoError(err)
 	fixture.WaitForConfirmedTxn(status.LastRound+10, acct0, tx.ID().String())
 
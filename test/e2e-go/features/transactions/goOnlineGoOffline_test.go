@@ -72,7 +72,7 @@ func testAccountsCanChangeOnlineState(t *testing.T, templatePath string) {
 	a.NoError(err, "should be no errors when creating partkeys")
 	a.Equal(initiallyOffline, partkeyResponse.Address().GetChecksumAddress().String(), "successful partkey creation should echo account")
 
-	goOnlineUTx, err := client.MakeUnsignedGoOnlineTx(initiallyOffline, nil, curRound, transactionValidityPeriod, transactionFee)
+	goOnlineUTx, err := client.MakeUnsignedGoOnlineTx(initiallyOffline, nil, curRound, transactionValidityPeriod, transactionFee, [32]byte{})
 	a.NoError(err, "should be able to make go online tx")
 	wh, err := client.GetUnencryptedWalletHandle()
 	a.NoError(err, "should be able to get unencrypted wallet handle")
@@ -84,7 +84,7 @@ func testAccountsCanChangeOnlineState(t *testing.T, templatePath string) {
 	a.NoError(err, "should be no errors when creating partkeys")
 	a.Equal(initiallyOnline, partkeyResponse.Address().GetChecksumAddress().String(), "successful partkey creation should echo account")
 
-	goOfflineUTx, err := client.MakeUnsignedGoOfflineTx(initiallyOnline, curRound, transactionValidityPeriod, transactionFee)
+	goOfflineUTx, err := client.MakeUnsignedGoOfflineTx(initiallyOnline, curRound, transactionValidityPeriod, transactionFee, [32]byte{})
 	a.NoError(err, "should be able to make go offline tx")
 	wh, err = client.GetUnencryptedWalletHandle()
 	offlineTxID, err := client.SignAndBroadcastTransaction(wh, nil, goOfflineUTx)
@@ -26,6 +26,7 @@ import (
 	"github.com/algorand/go-algorand/daemon/kmd/lib/kmdapi"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/test/framework/fixtures"
 )
@@ -311,6 +312,130 @@ func TestSignTransaction(t *testing.T) {
 	// require.NoError(t, stx.Verify())
 }
 
+func TestSignTransactionWithSigner(t *testing.T) {
+	t.Parallel()
+	var f fixtures.KMDFixture
+	walletHandleToken := f.SetupWithWallet(t)
+	defer f.Shutdown()
+
+	// Import a key to sign with, for a sender kmd doesn't know
+	seed := crypto.Seed{}
+	crypto.RandBytes(seed[:])
+	secrets := crypto.GenerateSignatureSecrets(seed)
+	signer := crypto.PublicKey(secrets.SignatureVerifier)
+
+	req0 := kmdapi.APIV1POSTKeyImportRequest{
+		WalletHandleToken: walletHandleToken,
+		PrivateKey:        crypto.PrivateKey(secrets.SK),
+	}
+	resp0 := kmdapi.APIV1POSTKeyImportResponse{}
+	err := f.Client.DoV1Request(req0, &resp0)
+	require.NoError(t, err)
+
+	var sender basics.Address
+	crypto.RandBytes(sender[:])
+	tx := transactions.Transaction{
+		Type: protocol.PaymentTx,
+		Header: transactions.Header{
+			Sender:     sender,
+			Fee:        basics.MicroAlgos{Raw: config.Consensus[protocol.ConsensusCurrentVersion].MinTxnFee},
+			FirstValid: basics.Round(1),
+			LastValid:  basics.Round(1),
+		},
+	}
+
+	// Signing with the sender's key fails, since the wallet doesn't have it
+	req1 := kmdapi.APIV1POSTTransactionSignRequest{
+		WalletHandleToken: walletHandleToken,
+		Transaction:       protocol.Encode(tx),
+		WalletPassword:    f.WalletPassword,
+	}
+	resp1 := kmdapi.APIV1POSTTransactionSignResponse{}
+	err = f.Client.DoV1Request(req1, &resp1)
+	require.Error(t, err)
+
+	// Signing with the imported key records it as the authorizer
+	req1.PublicKey = signer
+	resp2 := kmdapi.APIV1POSTTransactionSignResponse{}
+	err = f.Client.DoV1Request(req1, &resp2)
+	require.NoError(t, err)
+
+	var stx transactions.SignedTxn
+	err = protocol.Decode(resp2.SignedTransaction, &stx)
+	require.NoError(t, err)
+	require.Equal(t, basics.Address(signer), stx.AuthAddr)
+	require.True(t, secrets.SignatureVerifier.Verify(tx, stx.Sig))
+}
+
+func TestSignData(t *testing.T) {
+	t.Parallel()
+	var f fixtures.KMDFixture
+	walletHandleToken := f.SetupWithWallet(t)
+	defer f.Shutdown()
+
+	// Generate a key outside of kmd
+	seed := crypto.Seed{}
+	crypto.RandBytes(seed[:])
+	secrets := crypto.GenerateSignatureSecrets(seed)
+
+	// Import the key
+	req0 := kmdapi.APIV1POSTKeyImportRequest{
+		WalletHandleToken: walletHandleToken,
+		PrivateKey:        crypto.PrivateKey(secrets.SK),
+	}
+	resp0 := kmdapi.APIV1POSTKeyImportResponse{}
+	err := f.Client.DoV1Request(req0, &resp0)
+	require.NoError(t, err)
+
+	// Request a signature
+	data := []byte("data")
+	req1 := kmdapi.APIV1POSTDataSignRequest{
+		WalletHandleToken: walletHandleToken,
+		Data:              data,
+		PublicKey:         crypto.PublicKey(secrets.SignatureVerifier),
+		WalletPassword:    f.WalletPassword,
+	}
+	resp1 := kmdapi.APIV1POSTDataSignResponse{}
+	err = f.Client.DoV1Request(req1, &resp1)
+	require.NoError(t, err)
+	require.True(t, secrets.SignatureVerifier.VerifyBytes(data, resp1.Signature))
+}
+
+func TestSignProgram(t *testing.T) {
+	t.Parallel()
+	var f fixtures.KMDFixture
+	walletHandleToken := f.SetupWithWallet(t)
+	defer f.Shutdown()
+
+	// Generate a key outside of kmd
+	seed := crypto.Seed{}
+	crypto.RandBytes(seed[:])
+	secrets := crypto.GenerateSignatureSecrets(seed)
+
+	// Import the key
+	req0 := kmdapi.APIV1POSTKeyImportRequest{
+		WalletHandleToken: walletHandleToken,
+		PrivateKey:        crypto.PrivateKey(secrets.SK),
+	}
+	resp0 := kmdapi.APIV1POSTKeyImportResponse{}
+	err := f.Client.DoV1Request(req0, &resp0)
+	require.NoError(t, err)
+
+	// Request a signature
+	program, err := logic.AssembleString("int 1")
+	require.NoError(t, err)
+	req1 := kmdapi.APIV1POSTProgramSignRequest{
+		WalletHandleToken: walletHandleToken,
+		Program:           program,
+		PublicKey:         crypto.PublicKey(secrets.SignatureVerifier),
+		WalletPassword:    f.WalletPassword,
+	}
+	resp1 := kmdapi.APIV1POSTProgramSignResponse{}
+	err = f.Client.DoV1Request(req1, &resp1)
+	require.NoError(t, err)
+	require.True(t, secrets.SignatureVerifier.Verify(logic.Program(program), resp1.Signature))
+}
+
 func BenchmarkSignTransaction(b *testing.B) {
 	var f fixtures.KMDFixture
 	walletHandleToken := f.SetupWithWallet(b)
@@ -646,3 +771,99 @@ func TestMasterKeyGeneratePastImportedKeys(t *testing.T) {
 	// Address should be equal to addrs[2]
 	require.Equal(t, addr1, addrs[2])
 }
+
+func TestDeriveKey(t *testing.T) {
+	t.Parallel()
+	var f fixtures.KMDFixture
+	walletHandleToken := f.SetupWithWallet(t)
+	defer f.Shutdown()
+
+	// Generate some keys in sequence
+	var addrs []string
+	for i := 0; i < 3; i++ {
+		req := kmdapi.APIV1POSTKeyRequest{
+			WalletHandleToken: walletHandleToken,
+		}
+		resp := kmdapi.APIV1POSTKeyResponse{}
+		err := f.Client.DoV1Request(req, &resp)
+		require.NoError(t, err)
+		addrs = append(addrs, resp.Address)
+	}
+
+	// Deriving an index that was already generated should fail
+	req0 := kmdapi.APIV1POSTKeyDeriveRequest{
+		WalletHandleToken: walletHandleToken,
+		Index:             2,
+	}
+	resp0 := kmdapi.APIV1POSTKeyDeriveResponse{}
+	err := f.Client.DoV1Request(req0, &resp0)
+	require.Error(t, err)
+
+	// Index 0 is not part of the sequence
+	req1 := kmdapi.APIV1POSTKeyDeriveRequest{
+		WalletHandleToken: walletHandleToken,
+		Index:             0,
+	}
+	resp1 := kmdapi.APIV1POSTKeyDeriveResponse{}
+	err = f.Client.DoV1Request(req1, &resp1)
+	require.Error(t, err)
+
+	// Export the master derivation key
+	req2 := kmdapi.APIV1POSTMasterKeyExportRequest{
+		WalletHandleToken: walletHandleToken,
+		WalletPassword:    f.WalletPassword,
+	}
+	resp2 := kmdapi.APIV1POSTMasterKeyExportResponse{}
+	err = f.Client.DoV1Request(req2, &resp2)
+	require.NoError(t, err)
+
+	// Create a related wallet from the MDK
+	pw := "related-password"
+	req3 := kmdapi.APIV1POSTWalletRequest{
+		WalletName:          "related-wallet",
+		WalletPassword:      pw,
+		WalletDriverName:    "sqlite",
+		MasterDerivationKey: resp2.MasterDerivationKey,
+	}
+	resp3 := kmdapi.APIV1POSTWalletResponse{}
+	err = f.Client.DoV1Request(req3, &resp3)
+	require.NoError(t, err)
+
+	req4 := kmdapi.APIV1POSTWalletInitRequest{
+		WalletID:       resp3.Wallet.ID,
+		WalletPassword: pw,
+	}
+	resp4 := kmdapi.APIV1POSTWalletInitResponse{}
+	err = f.Client.DoV1Request(req4, &resp4)
+	require.NoError(t, err)
+	relatedWalletHandleToken := resp4.WalletHandleToken
+
+	// Deriving index 2 in the related wallet should give the second key
+	req5 := kmdapi.APIV1POSTKeyDeriveRequest{
+		WalletHandleToken: relatedWalletHandleToken,
+		Index:             2,
+	}
+	resp5 := kmdapi.APIV1POSTKeyDeriveResponse{}
+	err = f.Client.DoV1Request(req5, &resp5)
+	require.NoError(t, err)
+	require.Equal(t, addrs[1], resp5.Address)
+
+	// Generating a key afterwards should continue after the derived index
+	req6 := kmdapi.APIV1POSTKeyRequest{
+		WalletHandleToken: relatedWalletHandleToken,
+	}
+	resp6 := kmdapi.APIV1POSTKeyResponse{}
+	err = f.Client.DoV1Request(req6, &resp6)
+	require.NoError(t, err)
+	require.Equal(t, addrs[2], resp6.Address)
+
+	// Index 1 can still be derived explicitly
+	req7 := kmdapi.APIV1POSTKeyDeriveRequest{
+		WalletHandleToken: relatedWalletHandleToken,
+		Index:             1,
+	}
+	resp7 := kmdapi.APIV1POSTKeyDeriveResponse{}
+	err = f.Client.DoV1Request(req7, &resp7)
+	require.NoError(t, err)
+	require.Equal(t, addrs[0], resp7.Address)
+}
@@ -193,7 +193,7 @@ func TestTransactionsByAddr(t *testing.T) {
 		t.Error("no addr with funds")
 	}
 	toAddress := getDestAddr(t, testClient, addresses, someAddress, wh)
-	tx, err := testClient.SendPaymentFromWallet(wh, nil, someAddress, toAddress, 10000, 100000, nil, "")
+	tx, err := testClient.SendPaymentFromWallet(wh, nil, someAddress, toAddress, 10000, 100000, nil, "", [32]byte{})
 	require.NoError(t, err)
 	txID := tx.ID()
 	rnd, err := testClient.Status()
@@ -238,6 +238,31 @@ func TestClientCanGetSuggestedFee(t *testing.T) {
 	_ = suggestedFeeResponse // per-byte-fee is allowed to be zero
 }
 
+func TestClientCanGetFeeInfo(t *testing.T) {
+	defer fixture.SetTestContext(t)()
+	testClient := fixture.LibGoalClient
+	feeInfo, err := testClient.FeeInfo()
+	require.NoError(t, err)
+	require.NotZero(t, feeInfo.MinFee)
+	require.NotZero(t, feeInfo.PoolSize)
+	require.True(t, feeInfo.PoolTxns <= feeInfo.PoolSize)
+}
+
+func TestClientCanListPoolTransactions(t *testing.T) {
+	defer fixture.SetTestContext(t)()
+	testClient := fixture.LibGoalClient
+	pool, err := testClient.PoolTransactions("", 0)
+	require.NoError(t, err)
+	require.True(t, uint64(len(pool.Transactions)) <= pool.TotalTxns)
+	for i, txn := range pool.Transactions {
+		require.Equal(t, uint64(i), txn.Position)
+	}
+
+	// there is nothing to drop for a transaction that isn't pending
+	_, err = testClient.DropPoolTransaction("BUTRHWZ4PBMMXOPVT3IVPN3VMX2PUUJ6K3OAJYYNAGYXFDLWBUSA")
+	require.Error(t, err)
+}
+
 func TestClientCanGetBlockInfo(t *testing.T) {
 	defer fixture.SetTestContext(t)()
 	testClient := fixture.LibGoalClient
@@ -256,7 +281,7 @@ func TestClientRejectsBadFromAddressWhenSending(t *testing.T) {
 	require.NoError(t, err)
 	badAccountAddress := "This is absolutely not a valid account address."
 	goodAccountAddress := addresses[0]
-	_, err = testClient.SendPaymentFromWallet(wh, nil, badAccountAddress, goodAccountAddress, 10000, 100000, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, badAccountAddress, goodAccountAddress, 10000, 100000, nil, "", [32]byte{})
 	require.Error(t, err)
 }
 
@@ -269,7 +294,7 @@ func TestClientRejectsBadToAddressWhenSending(t *testing.T) {
 	require.NoError(t, err)
 	badAccountAddress := "This is absolutely not a valid account address."
 	goodAccountAddress := addresses[0]
-	_, err = testClient.SendPaymentFromWallet(wh, nil, goodAccountAddress, badAccountAddress, 10000, 100000, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, goodAccountAddress, badAccountAddress, 10000, 100000, nil, "", [32]byte{})
 	require.Error(t, err)
 }
 
@@ -289,7 +314,7 @@ func TestClientRejectsMutatedFromAddressWhenSending(t *testing.T) {
 		require.NoError(t, err)
 	}
 	mutatedAccountAddress := mutateStringAtIndex(unmutatedAccountAddress, 0)
-	_, err = testClient.SendPaymentFromWallet(wh, nil, mutatedAccountAddress, goodAccountAddress, 10000, 100000, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, mutatedAccountAddress, goodAccountAddress, 10000, 100000, nil, "", [32]byte{})
 	require.Error(t, err)
 }
 
@@ -309,7 +334,7 @@ func TestClientRejectsMutatedToAddressWhenSending(t *testing.T) {
 		require.NoError(t, err)
 	}
 	mutatedAccountAddress := mutateStringAtIndex(unmutatedAccountAddress, 0)
-	_, err = testClient.SendPaymentFromWallet(wh, nil, goodAccountAddress, mutatedAccountAddress, 10000, 100000, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, goodAccountAddress, mutatedAccountAddress, 10000, 100000, nil, "", [32]byte{})
 	require.Error(t, err)
 }
 
@@ -322,7 +347,7 @@ func TestClientRejectsSendingMoneyFromAccountForWhichItHasNoKey(t *testing.T) {
 	require.NoError(t, err)
 	goodAccountAddress := addresses[0]
 	nodeDoesNotHaveKeyForThisAddress := "NJY27OQ2ZXK6OWBN44LE4K43TA2AV3DPILPYTHAJAMKIVZDWTEJKZJKO4A"
-	_, err = testClient.SendPaymentFromWallet(wh, nil, nodeDoesNotHaveKeyForThisAddress, goodAccountAddress, 10000, 100000, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, nodeDoesNotHaveKeyForThisAddress, goodAccountAddress, 10000, 100000, nil, "", [32]byte{})
 	require.Error(t, err)
 }
 
@@ -344,7 +369,7 @@ func TestClientOversizedNote(t *testing.T) {
 	}
 	maxTxnNoteBytes := config.Consensus[protocol.ConsensusCurrentVersion].MaxTxnNoteBytes
 	note := make([]byte, maxTxnNoteBytes+1)
-	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, 10000, 100000, note, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, 10000, 100000, note, "", [32]byte{})
 	require.Error(t, err)
 }
 
@@ -363,7 +388,7 @@ func TestClientCanSendAndGetNote(t *testing.T) {
 	toAddress := getDestAddr(t, testClient, addresses, someAddress, wh)
 	maxTxnNoteBytes := config.Consensus[protocol.ConsensusCurrentVersion].MaxTxnNoteBytes
 	note := make([]byte, maxTxnNoteBytes)
-	tx, err := testClient.SendPaymentFromWallet(wh, nil, someAddress, toAddress, 10000, 100000, note, "")
+	tx, err := testClient.SendPaymentFromWallet(wh, nil, someAddress, toAddress, 10000, 100000, note, "", [32]byte{})
 	require.NoError(t, err)
 	txStatus, err := waitForTransaction(t, testClient, someAddress, tx.ID().String(), 15*time.Second)
 	require.NoError(t, err)
@@ -383,7 +408,7 @@ func TestClientCanGetTransactionStatus(t *testing.T) {
 		t.Error("no addr with funds")
 	}
 	toAddress := getDestAddr(t, testClient, addresses, someAddress, wh)
-	tx, err := testClient.SendPaymentFromWallet(wh, nil, someAddress, toAddress, 10000, 100000, nil, "")
+	tx, err := testClient.SendPaymentFromWallet(wh, nil, someAddress, toAddress, 10000, 100000, nil, "", [32]byte{})
 	t.Log(string(protocol.EncodeJSON(tx)))
 	require.NoError(t, err)
 	t.Log(tx.ID().String())
@@ -430,22 +455,22 @@ func TestSendingTooMuchFails(t *testing.T) {
 	fromBalance, err := testClient.GetBalance(fromAddress)
 	require.NoError(t, err)
 	// too much amount
-	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, 10000, fromBalance+100, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, 10000, fromBalance+100, nil, "", [32]byte{})
 	t.Log(err)
 	require.Error(t, err)
 
 	// waaaay too much amount
-	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, 10000, math.MaxUint64, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, 10000, math.MaxUint64, nil, "", [32]byte{})
 	t.Log(err)
 	require.Error(t, err)
 
 	// too much fee
-	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, fromBalance+100, 10000, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, fromBalance+100, 10000, nil, "", [32]byte{})
 	t.Log(err)
 	require.Error(t, err)
 
 	// waaaay too much fee
-	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, math.MaxUint64, 10000, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, math.MaxUint64, 10000, nil, "", [32]byte{})
 	t.Log(err)
 	require.Error(t, err)
 }
@@ -482,7 +507,7 @@ func TestSendingFromEmptyAccountFails(t *testing.T) {
 		toAddress, err = testClient.GenerateAddress(wh)
 		require.NoError(t, err)
 	}
-	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, 10000, 100000, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, fromAddress, toAddress, 10000, 100000, nil, "", [32]byte{})
 	require.Error(t, err)
 }
 
@@ -511,7 +536,7 @@ func TestSendingTooLittleToEmptyAccountFails(t *testing.T) {
 	if someAddress == "" {
 		t.Error("no addr with funds")
 	}
-	_, err = testClient.SendPaymentFromWallet(wh, nil, someAddress, emptyAddress, 10000, 1, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, someAddress, emptyAddress, 10000, 1, nil, "", [32]byte{})
 	require.Error(t, err)
 }
 
@@ -531,7 +556,7 @@ func TestSendingLowFeeFails(t *testing.T) {
 		t.Errorf("balance too low %d < %d", someBal, sendAmount)
 	}
 	toAddress := getDestAddr(t, testClient, addresses, someAddress, wh)
-	utx, err := testClient.ConstructPayment(someAddress, toAddress, 1, sendAmount, nil, "")
+	utx, err := testClient.ConstructPayment(someAddress, toAddress, 1, sendAmount, nil, "", [32]byte{})
 	require.NoError(t, err)
 	utx.Fee.Raw = 1
 	stx, err := testClient.SignTransactionWithWallet(wh, nil, utx)
@@ -586,7 +611,7 @@ func TestSendingNotClosingAccountFails(t *testing.T) {
 		t.Error("no addr with funds")
 	}
 	amt := someBal - 10000 - 1
-	_, err = testClient.SendPaymentFromWallet(wh, nil, someAddress, emptyAddress, 10000, amt, nil, "")
+	_, err = testClient.SendPaymentFromWallet(wh, nil, someAddress, emptyAddress, 10000, amt, nil, "", [32]byte{})
 	require.Error(t, err)
 }
 
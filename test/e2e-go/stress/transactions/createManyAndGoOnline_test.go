@@ -36,7 +36,7 @@ func cascadeCreateAndFundAccounts(amountToSend, transactionFee uint64, fundingAc
 		a.NoError(err, "should be able to get unencrypted wallet handle")
 		newAddress, err := client.GenerateAddress(wh)
 		a.NoError(err, "should be able to generate new address")
-		tx, err := client.SendPaymentFromWallet(wh, nil, fundingAccount, newAddress, transactionFee, amountToSend, nil, "")
+		tx, err := client.SendPaymentFromWallet(wh, nil, fundingAccount, newAddress, transactionFee, amountToSend, nil, "", [32]byte{})
 		a.NoError(err, "should be no errors when funding new accounts, send number %v", i)
 		i++
 		outputTxidsToAccounts[tx.ID().String()] = newAddress
@@ -124,7 +124,7 @@ func TestManyAccountsCanGoOnline(t *testing.T) {
 		partkeyResponse, _, err := client.GenParticipationKeys(account, curRound-10, curRound+1000, 0)
 		a.NoError(err, "should be no errors when creating many partkeys, creation number %v", i)
 		a.Equal(account, partkeyResponse.Address, "successful partkey creation should echo account")
-		goOnlineUTx, err := client.MakeUnsignedGoOnlineTx(account, nil, curRound, transactionValidityPeriod, transactionFee)
+		goOnlineUTx, err := client.MakeUnsignedGoOnlineTx(account, nil, curRound, transactionValidityPeriod, transactionFee, [32]byte{})
 		a.NoError(err, "should be able to make go online tx %v", i)
 		wh, err := client.GetUnencryptedWalletHandle()
 		a.NoError(err, "should be able to get unencrypted wallet handle")
@@ -145,7 +145,7 @@ func TestManyAccountsCanGoOnline(t *testing.T) {
 		a.NoError(err, "should be no errors when creating many partkeys, creation number %v", i)
 		a.Equal(account, partkeyResponse.Address, "successful partkey creation should echo account")
 
-		goOnlineUTx, err := client.MakeUnsignedGoOnlineTx(account, nil, curRound, transactionValidityPeriod, transactionFee)
+		goOnlineUTx, err := client.MakeUnsignedGoOnlineTx(account, nil, curRound, transactionValidityPeriod, transactionFee, [32]byte{})
 		a.NoError(err, "should be able to make go online tx %v", i)
 		wh, err := client.GetUnencryptedWalletHandle()
 		a.NoError(err, "should be able to get unencrypted wallet handle")
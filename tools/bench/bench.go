@@ -0,0 +1,296 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package bench implements standardized benchmarks of the resources an algod node depends on - signature
+// verification throughput, block evaluation rate and database commit latency - producing a machine-readable report,
+// for sizing node hardware and for detecting performance regressions between releases.
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand/config"
+)
+
+// ReportVersion is the version of the report format.
+const ReportVersion = 1
+
+// Options controls how the benchmarks are run.
+type Options struct {
+	// Dir is the directory the disk benchmarks run in; it should be on the disk the node data directory is on.
+	Dir string
+	// Duration is the time each benchmark runs for, after its setup.
+	Duration time.Duration
+	// Parallelism is the number of goroutines used by the parallel benchmarks; 0 uses all the CPUs.
+	Parallelism int
+	// Accounts is the number of accounts of the synthetic ledger.
+	Accounts int
+}
+
+// DefaultOptions returns the options used when none are given, running in the given directory.
+func DefaultOptions(dir string) Options {
+	return Options{
+		Dir:         dir,
+		Duration:    5 * time.Second,
+		Parallelism: runtime.NumCPU(),
+		Accounts:    10000,
+	}
+}
+
+// Latency summarizes the latency distribution of the operations of a benchmark.
+type Latency struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// Result is the outcome of a single benchmark.
+type Result struct {
+	Name string `json:"name"`
+	// Operations is the number of operations completed within Elapsed.
+	Operations uint64        `json:"operations"`
+	Elapsed    time.Duration `json:"elapsed"`
+	// Rate is the number of operations per second, in the given Unit. Higher is better.
+	Rate    float64  `json:"rate"`
+	Unit    string   `json:"unit"`
+	Latency *Latency `json:"latency,omitempty"`
+	// Extra holds secondary rates, such as the transactions per second of the block evaluation benchmark.
+	Extra map[string]float64 `json:"extra,omitempty"`
+	Error string             `json:"error,omitempty"`
+}
+
+// Host describes the machine the benchmarks ran on.
+type Host struct {
+	Hostname  string `json:"hostname"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	CPUs      int    `json:"cpus"`
+	GoVersion string `json:"goversion"`
+}
+
+// Report is the machine-readable outcome of a benchmark run.
+type Report struct {
+	Version      int       `json:"version"`
+	BuildVersion string    `json:"buildversion"`
+	Started      time.Time `json:"started"`
+	Host         Host      `json:"host"`
+	Dir          string    `json:"dir"`
+	Parallelism  int       `json:"parallelism"`
+	Results      []Result  `json:"results"`
+}
+
+type benchmark struct {
+	name        string
+	description string
+	run         func(opts Options) (Result, error)
+}
+
+var benchmarks = []benchmark{
+	{"sigverify", "ed25519 signature verifications per second, on a single CPU", runSigVerify},
+	{"sigverify-parallel", "ed25519 signature verifications per second, on all the CPUs", runSigVerifyParallel},
+	{"blockeval", "evaluations per second of full payment blocks, on an in-memory synthetic ledger", runBlockEval},
+	{"dbcommit", "sqlite transaction commits per second on the target disk, and their latency", runDBCommit},
+}
+
+// Names returns the names of the available benchmarks, in the order they run.
+func Names() []string {
+	names := make([]string, len(benchmarks))
+	for i, b := range benchmarks {
+		names[i] = b.name
+	}
+	return names
+}
+
+// Describe returns the description of the given benchmark.
+func Describe(name string) string {
+	for _, b := range benchmarks {
+		if b.name == name {
+			return b.description
+		}
+	}
+	return ""
+}
+
+// Run runs the given benchmarks (all of them when none are given), calling progress before each one.
+// A benchmark that fails is reported with its error rather than aborting the run.
+func Run(names []string, opts Options, progress func(name string)) (report Report, err error) {
+	if len(names) == 0 {
+		names = Names()
+	}
+	var selected []benchmark
+	for _, name := range names {
+		found := false
+		for _, b := range benchmarks {
+			if b.name == name {
+				selected = append(selected, b)
+				found = true
+			}
+		}
+		if !found {
+			return report, fmt.Errorf("unknown benchmark '%s', expected one of %s", name, strings.Join(Names(), ", "))
+		}
+	}
+	if opts.Duration <= 0 {
+		opts.Duration = DefaultOptions(opts.Dir).Duration
+	}
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = runtime.NumCPU()
+	}
+	if opts.Accounts <= 0 {
+		opts.Accounts = DefaultOptions(opts.Dir).Accounts
+	}
+
+	hostname, _ := os.Hostname()
+	report = Report{
+		Version:      ReportVersion,
+		BuildVersion: config.GetCurrentVersion().String(),
+		Started:      time.Now().UTC(),
+		Host: Host{
+			Hostname:  hostname,
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			CPUs:      runtime.NumCPU(),
+			GoVersion: runtime.Version(),
+		},
+		Dir:         opts.Dir,
+		Parallelism: opts.Parallelism,
+	}
+	for _, b := range selected {
+		if progress != nil {
+			progress(b.name)
+		}
+		result, err := b.run(opts)
+		result.Name = b.name
+		if err != nil {
+			result.Error = err.Error()
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// Result returns the result of the given benchmark, if the report has one.
+func (r Report) Result(name string) (Result, bool) {
+	for _, result := range r.Results {
+		if result.Name == name {
+			return result, true
+		}
+	}
+	return Result{}, false
+}
+
+// SaveReport writes the report as JSON to the given file.
+func SaveReport(report Report, file string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(data, '\n'), 0666)
+}
+
+// LoadReport reads a report written by SaveReport.
+func LoadReport(file string) (report Report, err error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &report)
+	if err == nil && report.Version > ReportVersion {
+		err = fmt.Errorf("report version %d is newer than the supported version %d", report.Version, ReportVersion)
+	}
+	return
+}
+
+// Comparison is the change of a benchmark rate relative to a baseline report.
+type Comparison struct {
+	Name     string
+	Baseline float64
+	Current  float64
+	// Change is the relative change of the rate, in percent; negative changes are slowdowns.
+	Change float64
+	// Regression is set when the slowdown exceeds the comparison threshold.
+	Regression bool
+}
+
+// Compare compares the rates of the benchmarks found in both reports. A benchmark whose rate dropped by more than
+// threshold percent is flagged as a regression.
+func Compare(baseline, current Report, threshold float64) []Comparison {
+	var comparisons []Comparison
+	for _, result := range current.Results {
+		base, ok := baseline.Result(result.Name)
+		if !ok || base.Error != "" || result.Error != "" || base.Rate == 0 {
+			continue
+		}
+		change := 100 * (result.Rate - base.Rate) / base.Rate
+		comparisons = append(comparisons, Comparison{
+			Name:       result.Name,
+			Baseline:   base.Rate,
+			Current:    result.Rate,
+			Change:     change,
+			Regression: change < -threshold,
+		})
+	}
+	return comparisons
+}
+
+// measure calls op repeatedly until the duration elapses, and returns the number of calls and the time they took.
+// When latencies is given, the duration of every call is appended to it.
+func measure(duration time.Duration, op func() error, latencies *[]time.Duration) (ops uint64, elapsed time.Duration, err error) {
+	start := time.Now()
+	deadline := start.Add(duration)
+	for {
+		opStart := time.Now()
+		if err = op(); err != nil {
+			return
+		}
+		now := time.Now()
+		ops++
+		if latencies != nil {
+			*latencies = append(*latencies, now.Sub(opStart))
+		}
+		if now.After(deadline) {
+			return ops, now.Sub(start), nil
+		}
+	}
+}
+
+func rate(ops uint64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(ops) / elapsed.Seconds()
+}
+
+// summarizeLatencies computes the latency distribution of the given operation durations.
+func summarizeLatencies(latencies []time.Duration) *Latency {
+	if len(latencies) == 0 {
+		return nil
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p int) time.Duration {
+		return sorted[(len(sorted)-1)*p/100]
+	}
+	return &Latency{P50: percentile(50), P90: percentile(90), P99: percentile(99), Max: sorted[len(sorted)-1]}
+}
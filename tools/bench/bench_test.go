@@ -0,0 +1,110 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package bench
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBenchmarks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bench")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := Options{Dir: dir, Duration: 50 * time.Millisecond, Parallelism: 2, Accounts: 100}
+	var started []string
+	report, err := Run(nil, opts, func(name string) { started = append(started, name) })
+	require.NoError(t, err)
+	require.Equal(t, Names(), started)
+	require.Equal(t, 2, report.Parallelism)
+	require.Equal(t, len(Names()), len(report.Results))
+	for _, result := range report.Results {
+		require.Empty(t, result.Error, result.Name)
+		require.True(t, result.Operations > 0, result.Name)
+		require.True(t, result.Rate > 0, result.Name)
+		require.NotEmpty(t, result.Unit)
+		require.NotEmpty(t, Describe(result.Name))
+	}
+
+	blockEval, ok := report.Result("blockeval")
+	require.True(t, ok)
+	require.Equal(t, float64(100), blockEval.Extra["txnsperblock"])
+	dbCommit, ok := report.Result("dbcommit")
+	require.True(t, ok)
+	require.NotNil(t, dbCommit.Latency)
+	require.True(t, dbCommit.Latency.P50 <= dbCommit.Latency.P99 && dbCommit.Latency.P99 <= dbCommit.Latency.Max)
+
+	// the disk benchmark cleans up after itself.
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	for _, file := range files {
+		require.NotContains(t, file.Name(), "bench-commit")
+	}
+
+	_, err = Run([]string{"sigverify", "nope"}, opts, nil)
+	require.Error(t, err)
+}
+
+func TestReportRoundTripAndCompare(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bench")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	baseline := Report{Version: ReportVersion, Results: []Result{
+		{Name: "sigverify", Rate: 1000},
+		{Name: "blockeval", Rate: 10},
+		{Name: "dbcommit", Rate: 100, Latency: &Latency{P50: time.Millisecond, P99: 5 * time.Millisecond}},
+		{Name: "sigverify-parallel", Error: "failed"},
+	}}
+	file := filepath.Join(dir, "baseline.json")
+	require.NoError(t, SaveReport(baseline, file))
+	loaded, err := LoadReport(file)
+	require.NoError(t, err)
+	require.Equal(t, baseline, loaded)
+
+	current := Report{Version: ReportVersion, Results: []Result{
+		{Name: "sigverify", Rate: 950},
+		{Name: "blockeval", Rate: 8},
+		{Name: "dbcommit", Rate: 150},
+		{Name: "sigverify-parallel", Rate: 4000},
+	}}
+	comparisons := Compare(loaded, current, 10)
+	require.Equal(t, 3, len(comparisons))
+	require.Equal(t, Comparison{Name: "sigverify", Baseline: 1000, Current: 950, Change: -5}, comparisons[0])
+	require.Equal(t, Comparison{Name: "blockeval", Baseline: 10, Current: 8, Change: -20, Regression: true}, comparisons[1])
+	require.Equal(t, Comparison{Name: "dbcommit", Baseline: 100, Current: 150, Change: 50}, comparisons[2])
+
+	loaded.Version = ReportVersion + 1
+	require.NoError(t, SaveReport(loaded, file))
+	_, err = LoadReport(file)
+	require.Error(t, err)
+}
+
+func TestSummarizeLatencies(t *testing.T) {
+	var latencies []time.Duration
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, &Latency{P50: 50 * time.Millisecond, P90: 90 * time.Millisecond, P99: 99 * time.Millisecond, Max: 100 * time.Millisecond}, summarizeLatencies(latencies))
+	require.Nil(t, summarizeLatencies(nil))
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package bench
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// sigVerifyBatch is the number of distinct signed transactions the signature benchmarks cycle through.
+const sigVerifyBatch = 1024
+
+// signedPayments returns signed payment transactions, as the verifier sees them in blocks and in the transaction pool.
+func signedPayments(count int) []transactions.SignedTxn {
+	txns := make([]transactions.SignedTxn, count)
+	for i := range txns {
+		var seed crypto.Seed
+		crypto.RandBytes(seed[:])
+		key := crypto.GenerateSignatureSecrets(seed)
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:     basics.Address(key.SignatureVerifier),
+				Fee:        basics.MicroAlgos{Raw: 1000},
+				FirstValid: 1,
+				LastValid:  1000,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Amount: basics.MicroAlgos{Raw: uint64(i + 1)},
+			},
+		}
+		crypto.RandBytes(tx.Receiver[:])
+		txns[i] = tx.Sign(key)
+	}
+	return txns
+}
+
+func verifySignedTxn(stxn transactions.SignedTxn) error {
+	if !crypto.SignatureVerifier(stxn.Txn.Sender).Verify(stxn.Txn, stxn.Sig) {
+		return fmt.Errorf("signature of transaction %v failed to verify", stxn.ID())
+	}
+	return nil
+}
+
+func runSigVerify(opts Options) (result Result, err error) {
+	txns := signedPayments(sigVerifyBatch)
+	i := 0
+	result.Operations, result.Elapsed, err = measure(opts.Duration, func() error {
+		i = (i + 1) % len(txns)
+		return verifySignedTxn(txns[i])
+	}, nil)
+	result.Rate = rate(result.Operations, result.Elapsed)
+	result.Unit = "signatures/s"
+	return
+}
+
+func runSigVerifyParallel(opts Options) (result Result, err error) {
+	txns := signedPayments(sigVerifyBatch)
+	var ops uint64
+	var failed atomic.Value
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < opts.Parallelism; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			i := w
+			n, _, err := measure(opts.Duration, func() error {
+				i = (i + 1) % len(txns)
+				return verifySignedTxn(txns[i])
+			}, nil)
+			if err != nil {
+				failed.Store(err)
+			}
+			atomic.AddUint64(&ops, n)
+		}(w)
+	}
+	wg.Wait()
+	if e := failed.Load(); e != nil {
+		return result, e.(error)
+	}
+	result.Operations = ops
+	result.Elapsed = time.Since(start)
+	result.Rate = rate(result.Operations, result.Elapsed)
+	result.Unit = "signatures/s"
+	result.Extra = map[string]float64{"goroutines": float64(opts.Parallelism)}
+	return
+}
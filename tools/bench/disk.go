@@ -0,0 +1,82 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package bench
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// dbCommitRowSize is the size of the rows written by each commit, about the size of an account record and its key.
+const dbCommitRowSize = 256
+
+// dbCommitRows is the number of rows written by each commit, as a block updating a few accounts does.
+const dbCommitRows = 16
+
+// runDBCommit measures the latency of committing small write transactions to a sqlite database in the target
+// directory, with the same database settings as the ledger. It is dominated by the disk fsync latency.
+func runDBCommit(opts Options) (result Result, err error) {
+	dbFile := filepath.Join(opts.Dir, fmt.Sprintf("bench-commit.%d.sqlite", crypto.RandUint64()))
+	accessor, err := db.MakeAccessor(dbFile, false, false)
+	if err != nil {
+		return
+	}
+	defer func() {
+		accessor.Close()
+		for _, suffix := range []string{"", "-shm", "-wal", "-journal"} {
+			os.Remove(dbFile + suffix)
+		}
+	}()
+
+	err = accessor.Atomic(func(tx *sql.Tx) error {
+		_, err := tx.Exec("CREATE TABLE IF NOT EXISTS bench (id INTEGER PRIMARY KEY, data BLOB)")
+		return err
+	})
+	if err != nil {
+		return
+	}
+
+	row := make([]byte, dbCommitRowSize)
+	crypto.RandBytes(row)
+	var id int64
+	var latencies []time.Duration
+	result.Operations, result.Elapsed, err = measure(opts.Duration, func() error {
+		return accessor.Atomic(func(tx *sql.Tx) error {
+			for i := 0; i < dbCommitRows; i++ {
+				// keep the table at a constant size, so that the run length doesn't affect the commit cost.
+				if _, err := tx.Exec("INSERT OR REPLACE INTO bench (id, data) VALUES (?, ?)", (id+int64(i))%65536, row); err != nil {
+					return err
+				}
+			}
+			id += dbCommitRows
+			return nil
+		})
+	}, &latencies)
+	if err != nil {
+		return
+	}
+	result.Rate = rate(result.Operations, result.Elapsed)
+	result.Unit = "commits/s"
+	result.Latency = summarizeLatencies(latencies)
+	return
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/execpool"
+)
+
+var benchPoolAddr = basics.Address{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+var benchSinkAddr = basics.Address{0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe}
+
+// syntheticLedger creates an in-memory ledger holding the given number of funded accounts, and returns their keys.
+func syntheticLedger(dir string, accounts int) (*ledger.Ledger, bookkeeping.Block, []*crypto.SignatureSecrets, error) {
+	genesisBlock := bookkeeping.Block{}
+	genesisBlock.CurrentProtocol = protocol.ConsensusCurrentVersion
+	genesisBlock.BlockHeader.GenesisID = "bench"
+	genesisBlock.FeeSink = benchSinkAddr
+	genesisBlock.RewardsPool = benchPoolAddr
+	crypto.RandBytes(genesisBlock.BlockHeader.GenesisHash[:])
+
+	keys := make([]*crypto.SignatureSecrets, accounts)
+	accts := make(map[basics.Address]basics.AccountData)
+	for i := range keys {
+		var seed crypto.Seed
+		crypto.RandBytes(seed[:])
+		keys[i] = crypto.GenerateSignatureSecrets(seed)
+		accts[basics.Address(keys[i].SignatureVerifier)] = basics.AccountData{
+			Status:     basics.Offline,
+			MicroAlgos: basics.MicroAlgos{Raw: 1000 * 1000 * 1000 * 1000 / uint64(accounts)},
+		}
+	}
+	special := basics.AccountData{Status: basics.NotParticipating, MicroAlgos: basics.MicroAlgos{Raw: 1000 * 1000 * 1000 * 1000}}
+	accts[benchPoolAddr] = special
+	accts[benchSinkAddr] = special
+
+	log := logging.NewLogger()
+	log.SetLevel(logging.Error)
+	dbPrefix := filepath.Join(dir, fmt.Sprintf("bench-ledger.%d", crypto.RandUint64()))
+	l, err := ledger.OpenLedger(log, dbPrefix, true, []bookkeeping.Block{genesisBlock}, accts, genesisBlock.BlockHeader.GenesisHash)
+	return l, genesisBlock, keys, err
+}
+
+// fullPaymentBlock builds the block following prev, filled with payments signed by the given keys, one per key,
+// until the block runs out of space.
+func fullPaymentBlock(l *ledger.Ledger, prev bookkeeping.BlockHeader, keys []*crypto.SignatureSecrets, backlogPool execpool.BacklogPool) (bookkeeping.Block, error) {
+	blk := bookkeeping.MakeBlock(prev)
+	proto, ok := config.Consensus[blk.CurrentProtocol]
+	if !ok {
+		return blk, fmt.Errorf("protocol %s not supported", blk.CurrentProtocol)
+	}
+	eval, err := l.StartEvaluator(blk.BlockHeader, nil, backlogPool)
+	if err != nil {
+		return blk, err
+	}
+	for _, key := range keys {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      basics.Address(key.SignatureVerifier),
+				Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid:  blk.Round(),
+				LastValid:   blk.Round(),
+				GenesisID:   blk.GenesisID(),
+				GenesisHash: blk.GenesisHash(),
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Amount: basics.MicroAlgos{Raw: proto.MinBalance},
+			},
+		}
+		crypto.RandBytes(tx.Receiver[:])
+		err = eval.Transaction(tx.Sign(key), nil)
+		if err == ledger.ErrNoSpace {
+			break
+		}
+		if err != nil {
+			return blk, err
+		}
+	}
+	vb, err := eval.GenerateBlock()
+	if err != nil {
+		return blk, err
+	}
+	return vb.Block(), nil
+}
+
+// runBlockEval measures the rate at which full payment blocks are validated - signatures included - as when a node
+// receives a proposed block or catches up.
+func runBlockEval(opts Options) (result Result, err error) {
+	l, genesisBlock, keys, err := syntheticLedger(opts.Dir, opts.Accounts)
+	if err != nil {
+		return result, fmt.Errorf("error creating the synthetic ledger: %v", err)
+	}
+	defer l.Close()
+
+	backlogPool := execpool.MakeBacklog(nil, 0, execpool.LowPriority, nil)
+	defer backlogPool.Shutdown()
+
+	blk, err := fullPaymentBlock(l, genesisBlock.BlockHeader, keys, backlogPool)
+	if err != nil {
+		return result, fmt.Errorf("error creating the block: %v", err)
+	}
+
+	result.Operations, result.Elapsed, err = measure(opts.Duration, func() error {
+		_, err := l.Validate(context.Background(), blk, nil, backlogPool)
+		return err
+	}, nil)
+	if err != nil {
+		return result, fmt.Errorf("error validating the block: %v", err)
+	}
+	result.Rate = rate(result.Operations, result.Elapsed)
+	result.Unit = "blocks/s"
+	result.Extra = map[string]float64{
+		"txnsperblock": float64(len(blk.Payset)),
+		"txns/s":       result.Rate * float64(len(blk.Payset)),
+	}
+	return
+}
@@ -34,6 +34,7 @@ type DNS struct {
 	zoneID    string
 	authEmail string
 	authKey   string
+	dryRun    bool
 }
 
 // NewDNS create a new instance of clouldflare DNS services class
@@ -45,6 +46,12 @@ func NewDNS(zoneID string, authEmail string, authKey string) *DNS {
 	}
 }
 
+// SetDryRun enables or disables the dry-run mode. In dry-run mode, the DNS records are still listed, but requests that would
+// create, update or delete records are printed instead of being sent.
+func (d *DNS) SetDryRun(dryRun bool) {
+	d.dryRun = dryRun
+}
+
 // SetDNSRecord sets the DNS record to the given content.
 func (d *DNS) SetDNSRecord(ctx context.Context, recordType string, name string, content string, ttl uint, priority uint, proxied bool) error {
 	entries, err := d.ListDNSRecord(ctx, recordType, name, content, "", "", "")
@@ -138,6 +145,9 @@ func (d *DNS) CreateDNSRecord(ctx context.Context, recordType string, name strin
 	if err != nil {
 		return err
 	}
+	if d.dryRun {
+		return printDryRunRequest(request)
+	}
 	client := &http.Client{}
 	response, err := client.Do(request.WithContext(ctx))
 	if err != nil {
@@ -160,6 +170,9 @@ func (d *DNS) CreateSRVRecord(ctx context.Context, name string, target string, t
 	if err != nil {
 		return err
 	}
+	if d.dryRun {
+		return printDryRunRequest(request)
+	}
 	client := &http.Client{}
 	response, err := client.Do(request.WithContext(ctx))
 	if err != nil {
@@ -182,6 +195,9 @@ func (d *DNS) DeleteDNSRecord(ctx context.Context, recordID string) error {
 	if err != nil {
 		return err
 	}
+	if d.dryRun {
+		return printDryRunRequest(request)
+	}
 	client := &http.Client{}
 	response, err := client.Do(request.WithContext(ctx))
 	if err != nil {
@@ -204,6 +220,9 @@ func (d *DNS) UpdateDNSRecord(ctx context.Context, recordID string, recordType s
 	if err != nil {
 		return err
 	}
+	if d.dryRun {
+		return printDryRunRequest(request)
+	}
 	client := &http.Client{}
 	response, err := client.Do(request.WithContext(ctx))
 	if err != nil {
@@ -226,6 +245,9 @@ func (d *DNS) UpdateSRVRecord(ctx context.Context, recordID string, name string,
 	if err != nil {
 		return err
 	}
+	if d.dryRun {
+		return printDryRunRequest(request)
+	}
 	client := &http.Client{}
 	response, err := client.Do(request.WithContext(ctx))
 	if err != nil {
@@ -241,3 +263,70 @@ func (d *DNS) UpdateSRVRecord(ctx context.Context, recordID string, name string,
 	}
 	return nil
 }
+
+// GetDNSSEC returns the DNSSEC state of the zone, including its DS record material.
+func (d *DNS) GetDNSSEC(ctx context.Context) (DNSSECResult, error) {
+	request, err := getDNSSECRequest(d.zoneID, d.authEmail, d.authKey)
+	if err != nil {
+		return DNSSECResult{}, err
+	}
+	client := &http.Client{}
+	response, err := client.Do(request.WithContext(ctx))
+	if err != nil {
+		return DNSSECResult{}, err
+	}
+
+	parsedResponse, err := parseDNSSECResponse(response)
+	if err != nil {
+		return DNSSECResult{}, err
+	}
+	if parsedResponse.Success == false {
+		return DNSSECResult{}, fmt.Errorf("failed to get DNSSEC details : %v", parsedResponse)
+	}
+	return parsedResponse.Result, nil
+}
+
+// EnableDNSSEC enables DNSSEC signing of the zone. The returned result holds the DS record material that needs to be
+// added at the registrar before the zone becomes active.
+func (d *DNS) EnableDNSSEC(ctx context.Context) (DNSSECResult, error) {
+	return d.updateDNSSEC(ctx, DNSSECStatusActive)
+}
+
+// DisableDNSSEC disables DNSSEC signing of the zone. The DS record should be removed at the registrar first, otherwise
+// validating resolvers would fail resolving the zone.
+func (d *DNS) DisableDNSSEC(ctx context.Context) (DNSSECResult, error) {
+	return d.updateDNSSEC(ctx, DNSSECStatusDisabled)
+}
+
+// RotateDNSSECKeys replaces the zone signing keys by disabling and re-enabling DNSSEC, which makes cloudflare generate
+// new keys. The returned result holds the new DS record material, which has to replace the old DS record at the registrar.
+func (d *DNS) RotateDNSSECKeys(ctx context.Context) (DNSSECResult, error) {
+	if _, err := d.DisableDNSSEC(ctx); err != nil {
+		return DNSSECResult{}, err
+	}
+	return d.EnableDNSSEC(ctx)
+}
+
+func (d *DNS) updateDNSSEC(ctx context.Context, status string) (DNSSECResult, error) {
+	request, err := updateDNSSECRequest(d.zoneID, d.authEmail, d.authKey, status)
+	if err != nil {
+		return DNSSECResult{}, err
+	}
+	if d.dryRun {
+		return DNSSECResult{Status: status}, printDryRunRequest(request)
+	}
+	client := &http.Client{}
+	response, err := client.Do(request.WithContext(ctx))
+	if err != nil {
+		return DNSSECResult{}, err
+	}
+
+	parsedResponse, err := parseDNSSECResponse(response)
+	if err != nil {
+		return DNSSECResult{}, err
+	}
+	if parsedResponse.Success == false {
+		return DNSSECResult{}, fmt.Errorf("failed to update DNSSEC status to %s : %v", status, parsedResponse)
+	}
+	return parsedResponse.Result, nil
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunMutations(t *testing.T) {
+	d := NewDNS("zone", "email", "key")
+	d.SetDryRun(true)
+
+	// none of these calls should reach the cloudflare API.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, d.CreateDNSRecord(ctx, "A", "r1.test.algodev.network", "10.0.0.1", AutomaticTTL, 1, false))
+	require.NoError(t, d.UpdateDNSRecord(ctx, "id", "CNAME", "r1.test.algodev.network", "r1.algodev.network", AutomaticTTL, 1, false))
+	require.NoError(t, d.CreateSRVRecord(ctx, "test.algodev.network", "r1.test.algodev.network", AutomaticTTL, 1, 4160, "_algobootstrap", "_tcp", 1))
+	require.NoError(t, d.UpdateSRVRecord(ctx, "id", "test.algodev.network", "r1.test.algodev.network", AutomaticTTL, 1, 4160, "_algobootstrap", "_tcp", 1))
+	require.NoError(t, d.DeleteDNSRecord(ctx, "id"))
+
+	d.SetDryRun(false)
+	require.Error(t, d.DeleteDNSRecord(ctx, "id"))
+}
+
+func TestDryRunDNSSEC(t *testing.T) {
+	d := NewDNS("zone", "email", "key")
+	d.SetDryRun(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := d.EnableDNSSEC(ctx)
+	require.NoError(t, err)
+	require.Equal(t, DNSSECStatusActive, result.Status)
+	result, err = d.RotateDNSSECKeys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, DNSSECStatusActive, result.Status)
+	result, err = d.DisableDNSSEC(ctx)
+	require.NoError(t, err)
+	require.Equal(t, DNSSECStatusDisabled, result.Status)
+}
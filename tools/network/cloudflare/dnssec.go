@@ -0,0 +1,110 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// DNSSECStatusActive is the status of a zone whose DNSSEC signing is enabled and whose DS record was found at the registrar.
+	DNSSECStatusActive = "active"
+	// DNSSECStatusPending is the status of a zone whose DNSSEC signing is enabled, but whose DS record wasn't found at the registrar yet.
+	DNSSECStatusPending = "pending"
+	// DNSSECStatusDisabled is the status of a zone that isn't DNSSEC signed.
+	DNSSECStatusDisabled = "disabled"
+)
+
+type updateDNSSEC struct {
+	Status string `json:"status"`
+}
+
+// getDNSSECRequest creates a new http request for retrieving the DNSSEC details of a zone.
+func getDNSSECRequest(zoneID string, authEmail string, authKey string) (*http.Request, error) {
+	// construct the query
+	uri, err := url.Parse(fmt.Sprintf("%szones/%s/dnssec", cloudFlareURI, zoneID))
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest("GET", uri.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	addHeaders(request, authEmail, authKey)
+	return request, nil
+}
+
+// updateDNSSECRequest creates a new http request for enabling or disabling DNSSEC on a zone.
+func updateDNSSECRequest(zoneID string, authEmail string, authKey string, status string) (*http.Request, error) {
+	requestBodyBytes, err := json.Marshal(updateDNSSEC{Status: status})
+	if err != nil {
+		return nil, err
+	}
+	// construct the query
+	uri, err := url.Parse(fmt.Sprintf("%szones/%s/dnssec", cloudFlareURI, zoneID))
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest("PATCH", uri.String(), bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	addHeaders(request, authEmail, authKey)
+	return request, nil
+}
+
+// DNSSECResponse is the JSON response for a DNSSEC get or update request
+type DNSSECResponse struct {
+	Success  bool          `json:"success"`
+	Errors   []interface{} `json:"errors"`
+	Messages []interface{} `json:"messages"`
+	Result   DNSSECResult  `json:"result"`
+}
+
+// DNSSECResult is the DNSSEC state of a zone, including the material needed for creating its DS record at the registrar.
+type DNSSECResult struct {
+	Status          string `json:"status"`
+	Flags           int    `json:"flags"`
+	Algorithm       string `json:"algorithm"`
+	KeyType         string `json:"key_type"`
+	DigestType      string `json:"digest_type"`
+	DigestAlgorithm string `json:"digest_algorithm"`
+	Digest          string `json:"digest"`
+	DS              string `json:"ds"`
+	KeyTag          int    `json:"key_tag"`
+	PublicKey       string `json:"public_key"`
+	ModifiedOn      string `json:"modified_on"`
+}
+
+// parseDNSSECResponse parses the reponse that was received as a result of a getDNSSECRequest or an updateDNSSECRequest
+func parseDNSSECResponse(response *http.Response) (*DNSSECResponse, error) {
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	var parsedResponse DNSSECResponse
+	if err := json.Unmarshal(body, &parsedResponse); err != nil {
+		return nil, err
+	}
+	return &parsedResponse, nil
+}
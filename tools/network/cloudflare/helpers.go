@@ -17,6 +17,8 @@
 package cloudflare
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net/http"
 )
 
@@ -25,3 +27,21 @@ func addHeaders(request *http.Request, authEmail string, authKey string) {
 	request.Header.Add("X-Auth-Key", authKey)
 	request.Header.Add("Content-Type", "application/json")
 }
+
+// printDryRunRequest prints the method, uri and body of a request that would have been sent if we weren't in dry-run mode.
+func printDryRunRequest(request *http.Request) error {
+	body := []byte{}
+	if request.GetBody != nil {
+		reader, err := request.GetBody()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		body, err = ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("[dry-run] %s %s %s\n", request.Method, request.URL.String(), string(body))
+	return nil
+}
@@ -0,0 +1,253 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package dnsserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// defaultQueryTimeout is the time a Query may take when its context has no deadline.
+const defaultQueryTimeout = 5 * time.Second
+
+// maxCompressionPointers bounds the number of compression pointers followed while parsing a single name, to protect
+// against pointer loops.
+const maxCompressionPointers = 32
+
+// Query sends a single recursive query of the given name and record type to the DNS server at the given address, and
+// returns the records of the answer section. Unlike the net.Resolver lookups, the returned records carry their TTLs,
+// which for a caching resolver are the remaining time the records would be served from its cache.
+// Truncated UDP responses are retried over TCP. A name that doesn't exist yields no records and no error.
+func Query(ctx context.Context, address string, name string, recordType string) ([]Record, error) {
+	qtype, has := recordTypes[strings.ToUpper(recordType)]
+	if !has {
+		return nil, fmt.Errorf("unsupported record type '%s'", recordType)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "53")
+	}
+	if _, has := ctx.Deadline(); !has {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultQueryTimeout)
+		defer cancel()
+	}
+
+	idBytes := make([]byte, 2)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, err
+	}
+	id := binary.BigEndian.Uint16(idBytes)
+	query := header{id: id, flags: flagRecursion, qdCount: 1}.append(nil)
+	query = question{name: canonicalName(name), qtype: qtype, qclass: classINET}.append(query)
+
+	response, err := exchange(ctx, "udp", address, query)
+	if err != nil {
+		return nil, err
+	}
+	if h, err := parseHeader(response); err == nil && h.flags&flagTruncated != 0 {
+		if response, err = exchange(ctx, "tcp", address, query); err != nil {
+			return nil, err
+		}
+	}
+	return parseResponse(response, id)
+}
+
+// exchange sends the query over the given network and returns the response message.
+func exchange(ctx context.Context, network string, address string, query []byte) ([]byte, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, has := ctx.Deadline(); has {
+		conn.SetDeadline(deadline)
+	}
+
+	if network == "tcp" {
+		if _, err = conn.Write(append(appendUint16(nil, uint16(len(query))), query...)); err != nil {
+			return nil, err
+		}
+		lengthBytes := make([]byte, 2)
+		if _, err = io.ReadFull(conn, lengthBytes); err != nil {
+			return nil, err
+		}
+		response := make([]byte, binary.BigEndian.Uint16(lengthBytes))
+		if _, err = io.ReadFull(conn, response); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+
+	if _, err = conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 65536)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// parseResponse verifies that the response answers the query with the given id, and parses its answer section.
+// Records of unsupported types, such as the DNSSEC signatures, are skipped.
+func parseResponse(msg []byte, id uint16) ([]Record, error) {
+	h, err := parseHeader(msg)
+	if err != nil {
+		return nil, err
+	}
+	if h.id != id || h.flags&flagResponse == 0 {
+		return nil, fmt.Errorf("unexpected response message")
+	}
+	switch rcode := h.flags & 0xf; rcode {
+	case rcodeSuccess:
+	case rcodeNameError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("query failed with response code %d", rcode)
+	}
+
+	offset := headerSize
+	for i := 0; i < int(h.qdCount); i++ {
+		if _, offset, err = parseName(msg, offset); err != nil {
+			return nil, err
+		}
+		offset += 4
+	}
+	records := []Record{}
+	for i := 0; i < int(h.anCount); i++ {
+		var r Record
+		var supported bool
+		r, supported, offset, err = parseRecord(msg, offset)
+		if err != nil {
+			return nil, err
+		}
+		if supported {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// parseRecord parses the resource record starting at the given offset, and returns the offset following it.
+func parseRecord(msg []byte, offset int) (r Record, supported bool, next int, err error) {
+	r.Name, offset, err = parseName(msg, offset)
+	if err != nil {
+		return
+	}
+	if offset+10 > len(msg) {
+		err = fmt.Errorf("record header exceeds the message")
+		return
+	}
+	rtype := binary.BigEndian.Uint16(msg[offset:])
+	r.TTL = binary.BigEndian.Uint32(msg[offset+4:])
+	dataLength := int(binary.BigEndian.Uint16(msg[offset+8:]))
+	offset += 10
+	next = offset + dataLength
+	if next > len(msg) {
+		err = fmt.Errorf("record data exceeds the message")
+		return
+	}
+	data := msg[offset:next]
+
+	for t, v := range recordTypes {
+		if v == rtype {
+			r.Type = t
+		}
+	}
+	switch r.Type {
+	case "A", "AAAA":
+		r.IP = net.IP(append([]byte{}, data...))
+	case "CNAME":
+		r.Target, _, err = parseName(msg, offset)
+	case "SRV":
+		if dataLength < 7 {
+			err = fmt.Errorf("SRV record %s is too short", r.Name)
+			return
+		}
+		r.Priority = binary.BigEndian.Uint16(data[0:])
+		r.Weight = binary.BigEndian.Uint16(data[2:])
+		r.Port = binary.BigEndian.Uint16(data[4:])
+		r.Target, _, err = parseName(msg, offset+6)
+	case "TXT":
+		var text []byte
+		for len(data) > 0 {
+			length := int(data[0])
+			if 1+length > len(data) {
+				err = fmt.Errorf("TXT record %s string exceeds the record", r.Name)
+				return
+			}
+			text = append(text, data[1:1+length]...)
+			data = data[1+length:]
+		}
+		r.Text = string(text)
+	default:
+		return r, false, next, nil
+	}
+	return r, err == nil, next, err
+}
+
+// parseName parses the possibly compressed domain name starting at the given offset of the message, and returns it in
+// its canonical form along with the offset following it.
+func parseName(msg []byte, offset int) (name string, next int, err error) {
+	labels := []string{}
+	next = -1
+	for pointers := 0; ; {
+		if offset >= len(msg) {
+			err = fmt.Errorf("name exceeds the message")
+			return
+		}
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return canonicalName(strings.Join(labels, ".")), next, nil
+		case length&0xc0 == 0xc0:
+			if offset+1 >= len(msg) {
+				err = fmt.Errorf("name compression pointer exceeds the message")
+				return
+			}
+			pointers++
+			if pointers > maxCompressionPointers {
+				err = fmt.Errorf("too many name compression pointers")
+				return
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(msg[offset:]) & 0x3fff)
+		case length > maxLabelLength:
+			err = fmt.Errorf("unsupported label length %d", length)
+			return
+		default:
+			if offset+1+length > len(msg) {
+				err = fmt.Errorf("label exceeds the message")
+				return
+			}
+			labels = append(labels, string(msg[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
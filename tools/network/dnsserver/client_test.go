@@ -0,0 +1,103 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package dnsserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	records := []Record{
+		{Type: "A", Name: "r1.private.algodev.network", IP: net.ParseIP("10.0.0.1"), TTL: 60},
+		{Type: "CNAME", Name: "r2.private.algodev.network", Target: "r1.private.algodev.network", TTL: 120},
+		{Type: "TXT", Name: "_algometadata.private.algodev.network", Text: "metadata", TTL: 300},
+	}
+	for i := 0; i < 40; i++ {
+		records = append(records, Record{Type: "SRV", Name: "_algobootstrap._tcp.private.algodev.network", Target: fmt.Sprintf("relay-%d.private.algodev.network", i), Port: 4160, Priority: 1, Weight: 2, TTL: 30})
+	}
+	address, stop := serveTestRecords(t, records)
+	defer stop()
+	ctx := context.Background()
+
+	answers, err := Query(ctx, address, "r1.private.algodev.network.", "A")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(answers))
+	require.Equal(t, "r1.private.algodev.network", answers[0].Name)
+	require.True(t, answers[0].IP.Equal(net.ParseIP("10.0.0.1")))
+	require.Equal(t, uint32(60), answers[0].TTL)
+
+	answers, err = Query(ctx, address, "r2.private.algodev.network", "CNAME")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(answers))
+	require.Equal(t, "r1.private.algodev.network", answers[0].Target)
+	require.Equal(t, uint32(120), answers[0].TTL)
+
+	answers, err = Query(ctx, address, "_algometadata.private.algodev.network", "txt")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(answers))
+	require.Equal(t, "metadata", answers[0].Text)
+
+	// the SRV answer doesn't fit in a UDP message, and is retried over TCP.
+	answers, err = Query(ctx, address, "_algobootstrap._tcp.private.algodev.network", "SRV")
+	require.NoError(t, err)
+	require.Equal(t, 40, len(answers))
+	require.Equal(t, uint16(4160), answers[0].Port)
+	require.Equal(t, uint16(1), answers[0].Priority)
+	require.Equal(t, uint16(2), answers[0].Weight)
+
+	answers, err = Query(ctx, address, "r3.private.algodev.network", "A")
+	require.NoError(t, err)
+	require.Empty(t, answers)
+
+	_, err = Query(ctx, address, "www.example.com", "A")
+	require.Error(t, err)
+	_, err = Query(ctx, address, "r1.private.algodev.network", "MX")
+	require.Error(t, err)
+}
+
+func TestParseCompressedNames(t *testing.T) {
+	msg := header{id: 1, flags: flagResponse, qdCount: 1, anCount: 1}.append(nil)
+	msg = question{name: "r2.private.algodev.network", qtype: typeCNAME, qclass: classINET}.append(msg)
+	// the answer name points at the question name, and the target reuses its suffix.
+	msg = appendUint16(msg, 0xc000|headerSize)
+	msg = appendUint16(msg, typeCNAME)
+	msg = appendUint16(msg, classINET)
+	msg = appendUint32(msg, 42)
+	msg = appendUint16(msg, 5)
+	msg = append(msg, 2, 'r', '1', 0xc0, headerSize+3)
+
+	answers, err := parseResponse(msg, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(answers))
+	require.Equal(t, "r2.private.algodev.network", answers[0].Name)
+	require.Equal(t, "r1.private.algodev.network", answers[0].Target)
+	require.Equal(t, uint32(42), answers[0].TTL)
+
+	_, err = parseResponse(msg, 2)
+	require.Error(t, err)
+
+	// a pointer to itself never terminates.
+	loop := header{id: 1, flags: flagResponse, qdCount: 1}.append(nil)
+	loop = appendUint16(loop, 0xc000|headerSize)
+	_, err = parseResponse(loop, 1)
+	require.Error(t, err)
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package dnsserver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// DNS wire format constants, see RFC 1035 section 4.
+const (
+	headerSize = 12
+
+	typeA     uint16 = 1
+	typeCNAME uint16 = 5
+	typeTXT   uint16 = 16
+	typeAAAA  uint16 = 28
+	typeSRV   uint16 = 33
+	typeANY   uint16 = 255
+
+	classINET uint16 = 1
+
+	flagResponse      uint16 = 1 << 15
+	flagAuthoritative uint16 = 1 << 10
+	flagTruncated     uint16 = 1 << 9
+	flagRecursion     uint16 = 1 << 8
+	opcodeMask        uint16 = 0xf << 11
+
+	rcodeSuccess        uint16 = 0
+	rcodeFormatError    uint16 = 1
+	rcodeNameError      uint16 = 3
+	rcodeNotImplemented uint16 = 4
+	rcodeRefused        uint16 = 5
+
+	maxUDPMessageSize = 512
+	maxLabelLength    = 63
+	maxNameLength     = 255
+)
+
+var recordTypes = map[string]uint16{
+	"A":     typeA,
+	"AAAA":  typeAAAA,
+	"CNAME": typeCNAME,
+	"SRV":   typeSRV,
+	"TXT":   typeTXT,
+}
+
+// header is the fixed size header of a DNS message.
+type header struct {
+	id      uint16
+	flags   uint16
+	qdCount uint16
+	anCount uint16
+	nsCount uint16
+	arCount uint16
+}
+
+// question is a single entry of the DNS message question section.
+type question struct {
+	name   string
+	qtype  uint16
+	qclass uint16
+}
+
+func parseHeader(msg []byte) (h header, err error) {
+	if len(msg) < headerSize {
+		err = fmt.Errorf("message is too short (%d bytes)", len(msg))
+		return
+	}
+	h.id = binary.BigEndian.Uint16(msg[0:])
+	h.flags = binary.BigEndian.Uint16(msg[2:])
+	h.qdCount = binary.BigEndian.Uint16(msg[4:])
+	h.anCount = binary.BigEndian.Uint16(msg[6:])
+	h.nsCount = binary.BigEndian.Uint16(msg[8:])
+	h.arCount = binary.BigEndian.Uint16(msg[10:])
+	return
+}
+
+func (h header) append(buf []byte) []byte {
+	for _, v := range []uint16{h.id, h.flags, h.qdCount, h.anCount, h.nsCount, h.arCount} {
+		buf = append(buf, byte(v>>8), byte(v))
+	}
+	return buf
+}
+
+// parseQuestion parses the question starting at the given offset of the message, and returns the offset following it.
+// Queries don't use name compression, so compression pointers are rejected.
+func parseQuestion(msg []byte, offset int) (q question, next int, err error) {
+	labels := []string{}
+	nameLength := 0
+	for {
+		if offset >= len(msg) {
+			err = fmt.Errorf("question name exceeds the message")
+			return
+		}
+		length := int(msg[offset])
+		offset++
+		if length == 0 {
+			break
+		}
+		if length > maxLabelLength {
+			err = fmt.Errorf("unsupported question label length %d", length)
+			return
+		}
+		if offset+length > len(msg) {
+			err = fmt.Errorf("question label exceeds the message")
+			return
+		}
+		nameLength += length + 1
+		if nameLength > maxNameLength {
+			err = fmt.Errorf("question name is too long")
+			return
+		}
+		labels = append(labels, string(msg[offset:offset+length]))
+		offset += length
+	}
+	if offset+4 > len(msg) {
+		err = fmt.Errorf("question type exceeds the message")
+		return
+	}
+	q.name = strings.Join(labels, ".")
+	q.qtype = binary.BigEndian.Uint16(msg[offset:])
+	q.qclass = binary.BigEndian.Uint16(msg[offset+2:])
+	return q, offset + 4, nil
+}
+
+// appendName appends the uncompressed wire encoding of the given domain name.
+func appendName(buf []byte, name string) []byte {
+	name = strings.TrimSuffix(name, ".")
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			buf = append(buf, byte(len(label)))
+			buf = append(buf, label...)
+		}
+	}
+	return append(buf, 0)
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v>>8), byte(v))
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func (q question) append(buf []byte) []byte {
+	buf = appendName(buf, q.name)
+	buf = appendUint16(buf, q.qtype)
+	return appendUint16(buf, q.qclass)
+}
@@ -0,0 +1,284 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package dnsserver implements a minimal authoritative DNS server, serving a static set of A, AAAA, CNAME, SRV and
+// TXT records. It allows private networks to use the DNS bootstrapping without depending on an external DNS provider.
+// The package also provides Query, a minimal client reporting the record TTLs that the net.Resolver lookups hide.
+package dnsserver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Record is a single DNS record served by the Server.
+type Record struct {
+	// Type is one of A, AAAA, CNAME, SRV or TXT.
+	Type string
+	// Name is the fully qualified name of the record, such as _algobootstrap._tcp.private.algodev.network
+	Name string
+	TTL  uint32
+
+	// IP is the address of A and AAAA records.
+	IP net.IP
+	// Target is the target name of CNAME and SRV records.
+	Target string
+	// Priority, Weight and Port are the SRV record attributes.
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	// Text is the content of TXT records.
+	Text string
+}
+
+// Server is an authoritative DNS server for a single zone.
+type Server struct {
+	zone    string
+	records map[string][]Record
+}
+
+// tcpIdleTimeout is the time a TCP client connection may stay idle before the server closes it.
+const tcpIdleTimeout = 10 * time.Second
+
+// MakeServer creates a server for the given zone, serving the given records. All the records must belong to the zone.
+func MakeServer(zone string, records []Record) (*Server, error) {
+	s := &Server{
+		zone:    canonicalName(zone),
+		records: make(map[string][]Record),
+	}
+	for _, r := range records {
+		r.Type = strings.ToUpper(r.Type)
+		r.Name = canonicalName(r.Name)
+		r.Target = canonicalName(r.Target)
+		if !s.inZone(r.Name) {
+			return nil, fmt.Errorf("record %s does not belong to zone %s", r.Name, s.zone)
+		}
+		switch r.Type {
+		case "A":
+			if r.IP.To4() == nil {
+				return nil, fmt.Errorf("A record %s has an invalid IPv4 address %v", r.Name, r.IP)
+			}
+		case "AAAA":
+			if r.IP.To16() == nil || r.IP.To4() != nil {
+				return nil, fmt.Errorf("AAAA record %s has an invalid IPv6 address %v", r.Name, r.IP)
+			}
+		case "CNAME", "SRV":
+			if r.Target == "" {
+				return nil, fmt.Errorf("%s record %s has no target", r.Type, r.Name)
+			}
+		case "TXT":
+		default:
+			return nil, fmt.Errorf("record %s has unsupported type '%s'", r.Name, r.Type)
+		}
+		s.records[r.Name] = append(s.records[r.Name], r)
+	}
+	return s, nil
+}
+
+func canonicalName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func (s *Server) inZone(name string) bool {
+	return name == s.zone || strings.HasSuffix(name, "."+s.zone)
+}
+
+// ServeUDP answers the queries arriving on the given packet connection, until the connection is closed.
+func (s *Server) ServeUDP(conn net.PacketConn) error {
+	buf := make([]byte, 65536)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		response, err := s.handleQuery(buf[:n], maxUDPMessageSize)
+		if err != nil {
+			continue
+		}
+		conn.WriteTo(response, addr)
+	}
+}
+
+// ServeTCP answers the queries arriving on the connections accepted by the given listener, until it is closed.
+func (s *Server) ServeTCP(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveTCPConn(conn)
+	}
+}
+
+func (s *Server) serveTCPConn(conn net.Conn) {
+	defer conn.Close()
+	lengthBytes := make([]byte, 2)
+	for {
+		conn.SetDeadline(time.Now().Add(tcpIdleTimeout))
+		if _, err := io.ReadFull(conn, lengthBytes); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(lengthBytes))
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		response, err := s.handleQuery(query, 65535)
+		if err != nil {
+			return
+		}
+		if _, err := conn.Write(append(appendUint16(nil, uint16(len(response))), response...)); err != nil {
+			return
+		}
+	}
+}
+
+// handleQuery builds the response to the given query. Responses larger than maxSize are truncated and flagged as such,
+// so that the client would retry over TCP. An error is returned only for queries that don't deserve a response.
+func (s *Server) handleQuery(query []byte, maxSize int) ([]byte, error) {
+	h, err := parseHeader(query)
+	if err != nil {
+		return nil, err
+	}
+	if h.flags&flagResponse != 0 {
+		return nil, fmt.Errorf("message is not a query")
+	}
+	response := header{
+		id:    h.id,
+		flags: flagResponse | (h.flags & opcodeMask) | (h.flags & flagRecursion),
+	}
+	if h.flags&opcodeMask != 0 {
+		response.flags |= rcodeNotImplemented
+		return response.append(nil), nil
+	}
+	if h.qdCount != 1 {
+		response.flags |= rcodeFormatError
+		return response.append(nil), nil
+	}
+	q, _, err := parseQuestion(query, headerSize)
+	if err != nil {
+		response.flags |= rcodeFormatError
+		return response.append(nil), nil
+	}
+	response.qdCount = 1
+	name := canonicalName(q.name)
+	if !s.inZone(name) || (q.qclass != classINET && q.qclass != typeANY) {
+		response.flags |= rcodeRefused
+		return q.append(response.append(nil)), nil
+	}
+	response.flags |= flagAuthoritative
+
+	answers, additionals := s.lookup(name, q.qtype)
+	if len(answers) == 0 && len(s.records[name]) == 0 {
+		response.flags |= rcodeNameError
+	}
+
+	for {
+		response.anCount = uint16(len(answers))
+		response.arCount = uint16(len(additionals))
+		msg := q.append(response.append(nil))
+		for _, r := range answers {
+			msg = appendRecord(msg, r)
+		}
+		for _, r := range additionals {
+			msg = appendRecord(msg, r)
+		}
+		if len(msg) <= maxSize || len(answers) == 0 {
+			return msg, nil
+		}
+		// drop the additional records first, and then the answers, until the response fits.
+		response.flags |= flagTruncated
+		if len(additionals) > 0 {
+			additionals = nil
+		} else {
+			answers = answers[:len(answers)-1]
+		}
+	}
+}
+
+// lookup returns the answers for the given name and type, following CNAME records within the zone,
+// and the additional address records of the returned SRV targets.
+func (s *Server) lookup(name string, qtype uint16) (answers []Record, additionals []Record) {
+	for hops := 0; hops < 8; hops++ {
+		records := s.records[name]
+		var cname *Record
+		for i, r := range records {
+			if r.Type == "CNAME" {
+				cname = &records[i]
+			}
+			if qtype == typeANY || recordTypes[r.Type] == qtype {
+				answers = append(answers, r)
+			}
+		}
+		if cname == nil || qtype == typeCNAME || qtype == typeANY {
+			break
+		}
+		answers = append(answers, *cname)
+		name = cname.Target
+	}
+	for _, r := range answers {
+		if r.Type != "SRV" {
+			continue
+		}
+		for _, target := range s.records[r.Target] {
+			if target.Type == "A" || target.Type == "AAAA" {
+				additionals = append(additionals, target)
+			}
+		}
+	}
+	return
+}
+
+// appendRecord appends the wire encoding of the given resource record.
+func appendRecord(buf []byte, r Record) []byte {
+	buf = appendName(buf, r.Name)
+	buf = appendUint16(buf, recordTypes[r.Type])
+	buf = appendUint16(buf, classINET)
+	buf = appendUint32(buf, r.TTL)
+	var data []byte
+	switch r.Type {
+	case "A":
+		data = r.IP.To4()
+	case "AAAA":
+		data = r.IP.To16()
+	case "CNAME":
+		data = appendName(nil, r.Target)
+	case "SRV":
+		data = appendUint16(data, r.Priority)
+		data = appendUint16(data, r.Weight)
+		data = appendUint16(data, r.Port)
+		data = appendName(data, r.Target)
+	case "TXT":
+		text := r.Text
+		for {
+			chunk := text
+			if len(chunk) > 255 {
+				chunk = chunk[:255]
+			}
+			data = append(data, byte(len(chunk)))
+			data = append(data, chunk...)
+			text = text[len(chunk):]
+			if text == "" {
+				break
+			}
+		}
+	}
+	buf = appendUint16(buf, uint16(len(data)))
+	return append(buf, data...)
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package dnsserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func serveTestRecords(t *testing.T, records []Record) (address string, stop func()) {
+	server, err := MakeServer("private.algodev.network.", records)
+	require.NoError(t, err)
+
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := udpConn.LocalAddr().(*net.UDPAddr).Port
+	tcpListener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	require.NoError(t, err)
+	go server.ServeUDP(udpConn)
+	go server.ServeTCP(tcpListener)
+
+	return fmt.Sprintf("127.0.0.1:%d", port), func() {
+		udpConn.Close()
+		tcpListener.Close()
+	}
+}
+
+func startTestServer(t *testing.T, records []Record) (resolver *net.Resolver, stop func()) {
+	address, stop := serveTestRecords(t, records)
+	resolver = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		},
+	}
+	return resolver, stop
+}
+
+func TestServerLookups(t *testing.T) {
+	records := []Record{
+		{Type: "A", Name: "r1.private.algodev.network", IP: net.ParseIP("10.0.0.1"), TTL: 60},
+		{Type: "AAAA", Name: "r1.private.algodev.network", IP: net.ParseIP("fd00::1"), TTL: 60},
+		{Type: "CNAME", Name: "r2.private.algodev.network", Target: "r1.private.algodev.network", TTL: 60},
+		{Type: "SRV", Name: "_algobootstrap._tcp.private.algodev.network", Target: "r1.private.algodev.network", Port: 4160, Priority: 1, Weight: 1, TTL: 60},
+		{Type: "SRV", Name: "_algobootstrap._tcp.private.algodev.network", Target: "r2.private.algodev.network", Port: 4161, Priority: 1, Weight: 1, TTL: 60},
+		{Type: "TXT", Name: "_algometadata.private.algodev.network", Text: strings.Repeat("x", 300), TTL: 60},
+	}
+	resolver, stop := startTestServer(t, records)
+	defer stop()
+	ctx := context.Background()
+
+	_, srvs, err := resolver.LookupSRV(ctx, "algobootstrap", "tcp", "private.algodev.network")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(srvs))
+	ports := map[uint16]string{}
+	for _, srv := range srvs {
+		ports[srv.Port] = srv.Target
+	}
+	require.Equal(t, "r1.private.algodev.network.", ports[4160])
+	require.Equal(t, "r2.private.algodev.network.", ports[4161])
+
+	addrs, err := resolver.LookupHost(ctx, "r2.private.algodev.network")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.0.1", "fd00::1"}, addrs)
+
+	txts, err := resolver.LookupTXT(ctx, "_algometadata.private.algodev.network")
+	require.NoError(t, err)
+	require.Equal(t, []string{strings.Repeat("x", 300)}, txts)
+
+	_, err = resolver.LookupHost(ctx, "r3.private.algodev.network")
+	require.Error(t, err)
+	dnsErr, ok := err.(*net.DNSError)
+	require.True(t, ok)
+	require.True(t, dnsErr.IsNotFound)
+
+	_, err = resolver.LookupHost(ctx, "www.example.com")
+	require.Error(t, err)
+}
+
+func TestServerTruncatesLargeUDPResponses(t *testing.T) {
+	records := []Record{}
+	for i := 0; i < 40; i++ {
+		records = append(records, Record{Type: "SRV", Name: "_algobootstrap._tcp.private.algodev.network", Target: fmt.Sprintf("relay-%d.private.algodev.network", i), Port: 4160, TTL: 60})
+	}
+	server, err := MakeServer("private.algodev.network", records)
+	require.NoError(t, err)
+
+	query := header{id: 7, flags: flagRecursion, qdCount: 1}.append(nil)
+	query = question{name: "_algobootstrap._tcp.private.algodev.network", qtype: typeSRV, qclass: classINET}.append(query)
+
+	response, err := server.handleQuery(query, maxUDPMessageSize)
+	require.NoError(t, err)
+	require.True(t, len(response) <= maxUDPMessageSize)
+	h, err := parseHeader(response)
+	require.NoError(t, err)
+	require.NotZero(t, h.flags&flagTruncated)
+	require.True(t, h.anCount < 40)
+
+	// the TCP-sized response carries all the records; the Go resolver retries truncated responses over TCP.
+	resolver, stop := startTestServer(t, records)
+	defer stop()
+	_, srvs, err := resolver.LookupSRV(context.Background(), "algobootstrap", "tcp", "private.algodev.network")
+	require.NoError(t, err)
+	require.Equal(t, 40, len(srvs))
+}
+
+func TestMakeServerValidation(t *testing.T) {
+	_, err := MakeServer("private.algodev.network", []Record{{Type: "A", Name: "r1.other.algodev.network", IP: net.ParseIP("10.0.0.1")}})
+	require.Error(t, err)
+	_, err = MakeServer("private.algodev.network", []Record{{Type: "A", Name: "r1.private.algodev.network", IP: net.ParseIP("fd00::1")}})
+	require.Error(t, err)
+	_, err = MakeServer("private.algodev.network", []Record{{Type: "MX", Name: "private.algodev.network"}})
+	require.Error(t, err)
+	_, err = MakeServer("private.algodev.network", []Record{{Type: "SRV", Name: "_algobootstrap._tcp.private.algodev.network"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// NetworkMetadataPrefix is the name prefix of the TXT record holding the network metadata; the record for the
+// devnet.algodev.network bootstrap would be _algometadata.devnet.algodev.network
+const NetworkMetadataPrefix = "_algometadata."
+
+// networkMetadataTXTPrefix identifies the TXT records content as network metadata, and versions their encoding.
+const networkMetadataTXTPrefix = "algometa1:"
+
+// NetworkMetadata is the network information published as a TXT record alongside the SRV bootstrap records.
+type NetworkMetadata struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	GenesisID   string        `codec:"gen"`
+	GenesisHash crypto.Digest `codec:"gh"`
+
+	// MinProtocolVersion is the earliest consensus protocol version that nodes are recommended to support.
+	MinProtocolVersion protocol.ConsensusVersion `codec:"minproto"`
+
+	// CatchpointLabels lists catchpoint labels that are known to be good for this network.
+	CatchpointLabels []string `codec:"catchpoints"`
+}
+
+// SignedNetworkMetadata is a NetworkMetadata, signed by the network operator.
+type SignedNetworkMetadata struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Metadata NetworkMetadata  `codec:"m"`
+	Signer   crypto.PublicKey `codec:"signer"`
+	Sig      crypto.Signature `codec:"sig"`
+}
+
+// ToBeHashed implements the crypto.Hashable interface
+func (m NetworkMetadata) ToBeHashed() (protocol.HashID, []byte) {
+	return protocol.NetworkMetadata, protocol.Encode(m)
+}
+
+// SignNetworkMetadata signs the given metadata using the given secrets.
+func SignNetworkMetadata(secrets *crypto.SignatureSecrets, metadata NetworkMetadata) SignedNetworkMetadata {
+	return SignedNetworkMetadata{
+		Metadata: metadata,
+		Signer:   secrets.SignatureVerifier,
+		Sig:      secrets.Sign(metadata),
+	}
+}
+
+// Verify verifies that the metadata was signed by the given signer.
+func (s SignedNetworkMetadata) Verify(signer crypto.PublicKey) error {
+	if s.Signer != signer {
+		return fmt.Errorf("network metadata is signed by an unexpected key")
+	}
+	if !signer.Verify(s.Metadata, s.Sig) {
+		return fmt.Errorf("network metadata signature is invalid")
+	}
+	return nil
+}
+
+// EncodeTXT encodes the signed metadata as a TXT record content.
+func (s SignedNetworkMetadata) EncodeTXT() string {
+	return networkMetadataTXTPrefix + base64.StdEncoding.EncodeToString(protocol.Encode(s))
+}
+
+// DecodeNetworkMetadataTXT decodes a TXT record content that was created by EncodeTXT.
+func DecodeNetworkMetadataTXT(txt string) (s SignedNetworkMetadata, err error) {
+	// some DNS providers quote the TXT content.
+	txt = strings.Trim(txt, "\"")
+	if !strings.HasPrefix(txt, networkMetadataTXTPrefix) {
+		err = fmt.Errorf("TXT record is not a network metadata record")
+		return
+	}
+	encoded, err := base64.StdEncoding.DecodeString(txt[len(networkMetadataTXTPrefix):])
+	if err != nil {
+		err = fmt.Errorf("unable to decode network metadata TXT record: %v", err)
+		return
+	}
+	err = protocol.Decode(encoded, &s)
+	if err != nil {
+		err = fmt.Errorf("unable to decode network metadata TXT record: %v", err)
+	}
+	return
+}
+
+// ReadNetworkMetadata looks up the network metadata TXT records of the given bootstrap ID using the given lookup
+// function, and returns the first record that was signed by signer.
+func ReadNetworkMetadata(lookupTXT func(name string) ([]string, error), bootstrapID string, signer crypto.PublicKey) (metadata NetworkMetadata, err error) {
+	records, err := lookupTXT(NetworkMetadataPrefix + bootstrapID)
+	if err != nil {
+		return
+	}
+	err = fmt.Errorf("no network metadata records found for %s", bootstrapID)
+	for _, txt := range records {
+		signed, decodeErr := DecodeNetworkMetadataTXT(txt)
+		if decodeErr != nil {
+			continue
+		}
+		if verifyErr := signed.Verify(signer); verifyErr != nil {
+			err = verifyErr
+			continue
+		}
+		return signed.Metadata, nil
+	}
+	return
+}
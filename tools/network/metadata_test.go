@@ -0,0 +1,71 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+)
+
+func TestNetworkMetadataTXT(t *testing.T) {
+	var seed crypto.Seed
+	crypto.RandBytes(seed[:])
+	secrets := crypto.GenerateSignatureSecrets(seed)
+
+	metadata := NetworkMetadata{
+		GenesisID:          "devnet-v1.0",
+		GenesisHash:        crypto.Hash([]byte("genesis")),
+		MinProtocolVersion: "https://github.com/algorandfoundation/specs/tree/5615adc36bad610c7f165fa2967f4ecfa75125f0",
+		CatchpointLabels:   []string{"1000#ABCD"},
+	}
+	txt := SignNetworkMetadata(secrets, metadata).EncodeTXT()
+
+	decoded, err := DecodeNetworkMetadataTXT("\"" + txt + "\"")
+	require.NoError(t, err)
+	require.Equal(t, metadata, decoded.Metadata)
+	require.NoError(t, decoded.Verify(secrets.SignatureVerifier))
+
+	// a different signer should be rejected.
+	var otherSeed crypto.Seed
+	crypto.RandBytes(otherSeed[:])
+	other := crypto.GenerateSignatureSecrets(otherSeed)
+	require.Error(t, decoded.Verify(other.SignatureVerifier))
+
+	// a tampered metadata should be rejected.
+	decoded.Metadata.GenesisID = "mainnet-v1.0"
+	require.Error(t, decoded.Verify(secrets.SignatureVerifier))
+
+	_, err = DecodeNetworkMetadataTXT("v=spf1 -all")
+	require.Error(t, err)
+
+	lookup := func(name string) ([]string, error) {
+		if name != NetworkMetadataPrefix+"devnet.algodev.network" {
+			return nil, fmt.Errorf("no such host")
+		}
+		return []string{"v=spf1 -all", SignNetworkMetadata(other, metadata).EncodeTXT(), txt}, nil
+	}
+	read, err := ReadNetworkMetadata(lookup, "devnet.algodev.network", secrets.SignatureVerifier)
+	require.NoError(t, err)
+	require.Equal(t, metadata, read)
+
+	_, err = ReadNetworkMetadata(lookup, "testnet.algodev.network", secrets.SignatureVerifier)
+	require.Error(t, err)
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package route53 implements the few Amazon Route 53 DNS operations needed to register relays and metrics endpoints,
+// mirroring the record setting functions of the cloudflare package.
+package route53
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+const (
+	route53URI    = "https://route53.amazonaws.com/2013-04-01/"
+	route53XMLNS  = "https://route53.amazonaws.com/doc/2013-04-01/"
+	signingName   = "route53"
+	signingRegion = "us-east-1"
+
+	// DefaultTTL is the TTL of the records set with a zero TTL; unlike cloudflare, route53 has no automatic TTL.
+	DefaultTTL = 300
+)
+
+// DNS provides access to the records of a single Route 53 hosted zone.
+type DNS struct {
+	zoneID   string
+	signer   *v4.Signer
+	endpoint string
+	dryRun   bool
+}
+
+// NewDNS creates a new instance of the route53 DNS services class for the given hosted zone.
+func NewDNS(zoneID string, accessKeyID string, secretAccessKey string) *DNS {
+	return &DNS{
+		zoneID:   strings.TrimPrefix(zoneID, "/hostedzone/"),
+		signer:   v4.NewSigner(credentials.NewStaticCredentials(accessKeyID, secretAccessKey, "")),
+		endpoint: route53URI,
+	}
+}
+
+// SetDryRun enables or disables the dry-run mode. In dry-run mode, the DNS records are still listed, but requests that would
+// change records are printed instead of being sent.
+func (d *DNS) SetDryRun(dryRun bool) {
+	d.dryRun = dryRun
+}
+
+type resourceRecord struct {
+	Value string `xml:"Value"`
+}
+
+type resourceRecordSet struct {
+	Name            string           `xml:"Name"`
+	Type            string           `xml:"Type"`
+	TTL             uint             `xml:"TTL"`
+	ResourceRecords []resourceRecord `xml:"ResourceRecords>ResourceRecord"`
+}
+
+type change struct {
+	Action            string            `xml:"Action"`
+	ResourceRecordSet resourceRecordSet `xml:"ResourceRecordSet"`
+}
+
+type changeResourceRecordSetsRequest struct {
+	XMLName xml.Name `xml:"ChangeResourceRecordSetsRequest"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Changes []change `xml:"ChangeBatch>Changes>Change"`
+}
+
+type listResourceRecordSetsResponse struct {
+	ResourceRecordSets []resourceRecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+}
+
+type errorResponse struct {
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// SetDNSRecord sets the A or CNAME record of the given name to the given content, replacing its previous content.
+func (d *DNS) SetDNSRecord(ctx context.Context, recordType string, name string, content string, ttl uint) error {
+	return d.upsert(ctx, resourceRecordSet{
+		Name:            fqdn(name),
+		Type:            recordType,
+		TTL:             ttlOrDefault(ttl),
+		ResourceRecords: []resourceRecord{{Value: content}},
+	})
+}
+
+// SetSRVRecord adds the given target to the SRV record set of <service>.<protocol>.<name>. Since route53 keeps all the
+// targets of an SRV name in a single record set, the existing targets are preserved, and an existing entry for the same
+// target is replaced.
+func (d *DNS) SetSRVRecord(ctx context.Context, name string, target string, ttl uint, priority uint, port uint, service string, protocol string, weight uint) error {
+	srvName := fqdn(service + "." + protocol + "." + name)
+	existing, err := d.getRecordSet(ctx, "SRV", srvName)
+	if err != nil {
+		return err
+	}
+	set := resourceRecordSet{Name: srvName, Type: "SRV", TTL: ttlOrDefault(ttl)}
+	for _, record := range existing.ResourceRecords {
+		fields := strings.Fields(record.Value)
+		if len(fields) == 4 && strings.EqualFold(fqdn(fields[3]), fqdn(target)) {
+			fmt.Printf("SRV entry for '%s'='%s' already exists, updating\n", name, target)
+			continue
+		}
+		set.ResourceRecords = append(set.ResourceRecords, record)
+	}
+	set.ResourceRecords = append(set.ResourceRecords, resourceRecord{Value: fmt.Sprintf("%d %d %d %s", priority, weight, port, fqdn(target))})
+	return d.upsert(ctx, set)
+}
+
+// getRecordSet returns the record set of the given name and type; the returned set has no records if there is none.
+func (d *DNS) getRecordSet(ctx context.Context, recordType string, name string) (set resourceRecordSet, err error) {
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("type", recordType)
+	query.Set("maxitems", "1")
+	request, err := http.NewRequest("GET", d.endpoint+"hostedzone/"+d.zoneID+"/rrset?"+query.Encode(), nil)
+	if err != nil {
+		return
+	}
+	body, err := d.do(ctx, request, nil)
+	if err != nil {
+		return
+	}
+	var response listResourceRecordSetsResponse
+	if err = xml.Unmarshal(body, &response); err != nil {
+		return
+	}
+	// the listing starts at the given name, so the first set may belong to another name.
+	for _, s := range response.ResourceRecordSets {
+		if strings.EqualFold(fqdn(s.Name), name) && s.Type == recordType {
+			return s, nil
+		}
+	}
+	return
+}
+
+func (d *DNS) upsert(ctx context.Context, set resourceRecordSet) error {
+	body, err := xml.Marshal(changeResourceRecordSetsRequest{
+		XMLNS:   route53XMLNS,
+		Changes: []change{{Action: "UPSERT", ResourceRecordSet: set}},
+	})
+	if err != nil {
+		return err
+	}
+	body = append([]byte(xml.Header), body...)
+	request, err := http.NewRequest("POST", d.endpoint+"hostedzone/"+d.zoneID+"/rrset/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "text/xml")
+	if d.dryRun {
+		fmt.Printf("[dry-run] %s %s %s\n", request.Method, request.URL.String(), string(body))
+		return nil
+	}
+	_, err = d.do(ctx, request, body)
+	return err
+}
+
+// do signs and sends the given request, and returns the response body of successful requests.
+func (d *DNS) do(ctx context.Context, request *http.Request, body []byte) ([]byte, error) {
+	if _, err := d.signer.Sign(request, bytes.NewReader(body), signingName, signingRegion, time.Now()); err != nil {
+		return nil, err
+	}
+	client := &http.Client{}
+	response, err := client.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		var errResponse errorResponse
+		if xml.Unmarshal(responseBody, &errResponse) == nil && errResponse.Code != "" {
+			return nil, fmt.Errorf("route53 %s request failed: %s: %s", request.Method, errResponse.Code, errResponse.Message)
+		}
+		return nil, fmt.Errorf("route53 %s request failed: %s", request.Method, response.Status)
+	}
+	return responseBody, nil
+}
+
+func fqdn(name string) string {
+	return strings.TrimSuffix(name, ".") + "."
+}
+
+func ttlOrDefault(ttl uint) uint {
+	if ttl == 0 {
+		return DefaultTTL
+	}
+	return ttl
+}
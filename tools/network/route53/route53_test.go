@@ -0,0 +1,133 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package route53
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testZone is a fake hosted zone, answering the list and change requests the way route53 does.
+type testZone struct {
+	sets     []resourceRecordSet
+	requests []*http.Request
+}
+
+func (z *testZone) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	z.requests = append(z.requests, r)
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=id/") {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`<ErrorResponse><Error><Code>SignatureDoesNotMatch</Code><Message>unsigned</Message></Error></ErrorResponse>`))
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, "/hostedzone/ZONE/rrset") {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`<ErrorResponse><Error><Code>NoSuchHostedZone</Code><Message>no zone</Message></Error></ErrorResponse>`))
+		return
+	}
+	switch r.Method {
+	case "GET":
+		response := listResourceRecordSetsResponse{}
+		for _, set := range z.sets {
+			if set.Name >= r.URL.Query().Get("name") {
+				response.ResourceRecordSets = append(response.ResourceRecordSets, set)
+				break
+			}
+		}
+		body, _ := xml.Marshal(response)
+		w.Write(body)
+	case "POST":
+		body, _ := ioutil.ReadAll(r.Body)
+		var request changeResourceRecordSetsRequest
+		if err := xml.Unmarshal(body, &request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, c := range request.Changes {
+			replaced := false
+			for i := range z.sets {
+				if z.sets[i].Name == c.ResourceRecordSet.Name && z.sets[i].Type == c.ResourceRecordSet.Type {
+					z.sets[i] = c.ResourceRecordSet
+					replaced = true
+				}
+			}
+			if !replaced {
+				z.sets = append(z.sets, c.ResourceRecordSet)
+			}
+		}
+		w.Write([]byte(`<ChangeResourceRecordSetsResponse/>`))
+	}
+}
+
+func makeTestDNS(t *testing.T, zoneID string) (*DNS, *testZone, func()) {
+	zone := &testZone{}
+	server := httptest.NewServer(zone)
+	d := NewDNS(zoneID, "id", "secret")
+	d.endpoint = server.URL + "/"
+	return d, zone, server.Close
+}
+
+func TestSetDNSRecord(t *testing.T) {
+	d, zone, done := makeTestDNS(t, "/hostedzone/ZONE")
+	defer done()
+
+	require.NoError(t, d.SetDNSRecord(context.Background(), "A", "r1.test.algodev.network", "10.0.0.1", 0))
+	require.NoError(t, d.SetDNSRecord(context.Background(), "A", "r1.test.algodev.network.", "10.0.0.2", 60))
+	require.Equal(t, []resourceRecordSet{{Name: "r1.test.algodev.network.", Type: "A", TTL: 60, ResourceRecords: []resourceRecord{{Value: "10.0.0.2"}}}}, zone.sets)
+}
+
+func TestSetSRVRecordKeepsOtherTargets(t *testing.T) {
+	d, zone, done := makeTestDNS(t, "ZONE")
+	defer done()
+	zone.sets = []resourceRecordSet{
+		{Name: "_algobootstrap._tcp.test.algodev.network.", Type: "SRV", TTL: 300, ResourceRecords: []resourceRecord{{Value: "1 1 4160 r1.test.algodev.network."}, {Value: "1 1 4160 r2.test.algodev.network."}}},
+	}
+
+	ctx := context.Background()
+	require.NoError(t, d.SetSRVRecord(ctx, "test.algodev.network", "r3.test.algodev.network", 0, 1, 4160, "_algobootstrap", "_tcp", 1))
+	require.NoError(t, d.SetSRVRecord(ctx, "test.algodev.network", "r1.test.algodev.network", 0, 1, 4161, "_algobootstrap", "_tcp", 1))
+	require.Equal(t, []resourceRecord{
+		{Value: "1 1 4160 r2.test.algodev.network."},
+		{Value: "1 1 4160 r3.test.algodev.network."},
+		{Value: "1 1 4161 r1.test.algodev.network."},
+	}, zone.sets[0].ResourceRecords)
+
+	require.NoError(t, d.SetSRVRecord(ctx, "test.algodev.network", "r1.test.algodev.network", 0, 1, 9100, "_metrics", "_tcp", 1))
+	require.Equal(t, 2, len(zone.sets))
+	require.Equal(t, []resourceRecord{{Value: "1 1 9100 r1.test.algodev.network."}}, zone.sets[1].ResourceRecords)
+}
+
+func TestErrorsAndDryRun(t *testing.T) {
+	d, zone, done := makeTestDNS(t, "OTHER")
+	defer done()
+
+	err := d.SetDNSRecord(context.Background(), "CNAME", "r1.test.algodev.network", "r1.algodev.network", 0)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "NoSuchHostedZone")
+
+	d.SetDryRun(true)
+	requests := len(zone.requests)
+	require.NoError(t, d.SetDNSRecord(context.Background(), "CNAME", "r1.test.algodev.network", "r1.algodev.network", 0))
+	require.Equal(t, requests, len(zone.requests))
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MetricsListenerPath is the path the MetricsListener serves the metrics on.
+const MetricsListenerPath = "/metrics"
+
+// MetricsListener serves the metrics of a registry in the prometheus text format, on a dedicated address that doesn't
+// require the REST API token. This allows scraping the node without exposing the authenticated API.
+type MetricsListener struct {
+	address  string
+	registry *Registry
+	listener net.Listener
+	server   *http.Server
+}
+
+// MakeMetricsListener creates a metrics listener for the given address, serving the given registry,
+// or the default registry if nil.
+func MakeMetricsListener(address string, registry *Registry) *MetricsListener {
+	if registry == nil {
+		registry = DefaultRegistry()
+	}
+	return &MetricsListener{address: address, registry: registry}
+}
+
+// Start starts listening on the address, and serving the metrics in the background.
+func (l *MetricsListener) Start() (err error) {
+	l.listener, err = net.Listen("tcp", l.address)
+	if err != nil {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(MetricsListenerPath, l.serveMetrics)
+	l.server = &http.Server{Handler: mux, ReadTimeout: 10 * time.Second, WriteTimeout: 10 * time.Second}
+	go l.server.Serve(l.listener)
+	return nil
+}
+
+// Addr returns the address the listener is listening on.
+func (l *MetricsListener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Shutdown stops serving the metrics.
+func (l *MetricsListener) Shutdown() error {
+	if l.server == nil {
+		return ErrMetricServiceNotRunning
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := l.server.Shutdown(ctx)
+	l.server = nil
+	return err
+}
+
+func (l *MetricsListener) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var buf strings.Builder
+	l.registry.WriteMetrics(&buf, "")
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(buf.String()))
+}
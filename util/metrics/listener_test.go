@@ -0,0 +1,50 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsListener(t *testing.T) {
+	registry := MakeRegistry()
+	counter := MakeCounter(MetricName{Name: "algod_listener_test_total", Description: "listener test counter"})
+	counter.Deregister(nil)
+	counter.Register(registry)
+	counter.Inc(nil)
+
+	listener := MakeMetricsListener("127.0.0.1:0", registry)
+	require.NoError(t, listener.Start())
+	defer listener.Shutdown()
+
+	response, err := http.Get("http://" + listener.Addr().String() + MetricsListenerPath)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	body, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "algod_listener_test_total{} 1")
+
+	response, err = http.Post("http://"+listener.Addr().String()+MetricsListenerPath, "text/plain", nil)
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+}
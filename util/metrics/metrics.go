@@ -82,6 +82,12 @@ var (
 	AgreementMessagesHandled = MetricName{Name: "algod_agreement_handled", Description: "Number of agreement messages handled"}
 	// AgreementMessagesDropped "Number of agreement messages dropped"
 	AgreementMessagesDropped = MetricName{Name: "algod_agreement_dropped", Description: "Number of agreement messages dropped"}
+	// AgreementRound "Round of the agreement state machine"
+	AgreementRound = MetricName{Name: "algod_agreement_round", Description: "Round of the agreement state machine"}
+	// AgreementPeriod "Period of the agreement state machine"
+	AgreementPeriod = MetricName{Name: "algod_agreement_period", Description: "Period of the agreement state machine"}
+	// AgreementStep "Step of the agreement state machine"
+	AgreementStep = MetricName{Name: "algod_agreement_step", Description: "Step of the agreement state machine"}
 
 	// TransactionMessagesHandled "Number of transaction messages handled"
 	TransactionMessagesHandled = MetricName{Name: "algod_transaction_messages_handled", Description: "Number of transaction messages handled"}
@@ -89,4 +95,44 @@ var (
 	TransactionMessagesDroppedFromBacklog = MetricName{Name: "algod_transaction_messages_dropped_backlog", Description: "Number of transaction messages dropped from backlog"}
 	// TransactionMessagesDroppedFromPool "Number of transaction messages dropped from pool"
 	TransactionMessagesDroppedFromPool = MetricName{Name: "algod_transaction_messages_dropped_pool", Description: "Number of transaction messages dropped from pool"}
+
+	// TransactionPoolRememberedTotal "Number of transactions admitted into the transaction pool"
+	TransactionPoolRememberedTotal = MetricName{Name: "algod_tx_pool_remembered_total", Description: "Number of transactions admitted into the transaction pool"}
+	// TransactionPoolRejectedTotal "Number of transactions rejected by the transaction pool"
+	TransactionPoolRejectedTotal = MetricName{Name: "algod_tx_pool_rejected_total", Description: "Number of transactions rejected by the transaction pool"}
+	// TransactionPoolEvictedTotal "Number of transactions evicted from a full transaction pool"
+	TransactionPoolEvictedTotal = MetricName{Name: "algod_tx_pool_evicted_total", Description: "Number of transactions evicted from a full transaction pool"}
+	// TransactionPoolBytes "Total encoded length of the transactions in the transaction pool"
+	TransactionPoolBytes = MetricName{Name: "algod_tx_pool_bytes", Description: "Total encoded length of the transactions in the transaction pool"}
+	// TransactionPoolMinFeePerByte "Lowest fee per byte among the transactions in the transaction pool"
+	TransactionPoolMinFeePerByte = MetricName{Name: "algod_tx_pool_min_fee_per_byte", Description: "Lowest fee per byte among the transactions in the transaction pool"}
+
+	// BlockAssemblyTotal "Number of proposal blocks assembled, by the reason the payset selection stopped"
+	BlockAssemblyTotal = MetricName{Name: "algod_block_assembly_total", Description: "Number of proposal blocks assembled, by the reason the payset selection stopped"}
+	// BlockAssemblyDeadlineMissedTotal "Number of proposal blocks assembled after the proposal deadline"
+	BlockAssemblyDeadlineMissedTotal = MetricName{Name: "algod_block_assembly_deadline_missed_total", Description: "Number of proposal blocks assembled after the proposal deadline"}
+	// BlockAssemblySeconds "Time it took to assemble the latest proposal block"
+	BlockAssemblySeconds = MetricName{Name: "algod_block_assembly_seconds", Description: "Time it took to assemble the latest proposal block"}
+	// BlockAssemblyFinalizeSeconds "Time it took to generate the latest proposal block out of its payset"
+	BlockAssemblyFinalizeSeconds = MetricName{Name: "algod_block_assembly_finalize_seconds", Description: "Time it took to generate the latest proposal block out of its payset"}
+	// BlockAssemblyPaysetCount "Number of transactions included in the latest proposal block"
+	BlockAssemblyPaysetCount = MetricName{Name: "algod_block_assembly_payset_count", Description: "Number of transactions included in the latest proposal block"}
+
+	// LogWarningsSuppressedTotal "Number of repeated warnings that were suppressed by the log deduplication"
+	LogWarningsSuppressedTotal = MetricName{Name: "algod_log_warnings_suppressed_total", Description: "Number of repeated warnings that were suppressed by the log deduplication"}
+	// LogWarningSummariesTotal "Number of 'repeated N times' summaries logged for suppressed warnings"
+	LogWarningSummariesTotal = MetricName{Name: "algod_log_warning_summaries_total", Description: "Number of 'repeated N times' summaries logged for suppressed warnings"}
+
+	// DiskFreeBytes "Free space of the disk holding the node data directory"
+	DiskFreeBytes = MetricName{Name: "algod_disk_free_bytes", Description: "Free space of the disk holding the node data directory"}
+	// SafeMode "Whether the node refuses new transactions because its disk is running out of space"
+	SafeMode = MetricName{Name: "algod_safe_mode", Description: "Whether the node refuses new transactions because its disk is running out of space"}
+
+	// APITransactionsRefusedTotal "Number of transactions submitted through the APIs refused by the local admission policy"
+	APITransactionsRefusedTotal = MetricName{Name: "algod_api_transactions_refused_total", Description: "Number of transactions submitted through the APIs refused by the local admission policy"}
+
+	// CatchupBlocksTotal "Number of blocks the catchup service fetched, verified and wrote to the ledger"
+	CatchupBlocksTotal = MetricName{Name: "algod_catchup_blocks_total", Description: "Number of blocks the catchup service fetched, verified and wrote to the ledger"}
+	// CatchupBadBlocksTotal "Number of blocks fetched by the catchup service that failed verification"
+	CatchupBadBlocksTotal = MetricName{Name: "algod_catchup_bad_blocks_total", Description: "Number of blocks fetched by the catchup service that failed verification"}
 )
@@ -0,0 +1,66 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func putNTPTime(b []byte, t time.Time) {
+	binary.BigEndian.PutUint32(b[0:4], uint32(t.Unix()+ntpEpochOffset))
+	binary.BigEndian.PutUint32(b[4:8], uint32((int64(t.Nanosecond())<<32)/int64(time.Second)))
+}
+
+func TestNTPClockOffset(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// a server whose clock is an hour ahead
+	go func() {
+		request := make([]byte, 48)
+		_, from, err := conn.ReadFrom(request)
+		if err != nil {
+			return
+		}
+		response := make([]byte, 48)
+		response[0] = 3<<3 | 4
+		response[1] = 1
+		now := time.Now().Add(time.Hour)
+		putNTPTime(response[32:40], now)
+		putNTPTime(response[40:48], now)
+		conn.WriteTo(response, from)
+	}()
+
+	offset, err := NTPClockOffset(conn.LocalAddr().String(), time.Second)
+	require.NoError(t, err)
+	require.InDelta(t, float64(time.Hour), float64(offset), float64(time.Second))
+}
+
+func TestNTPClockOffsetTimeout(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = NTPClockOffset(conn.LocalAddr().String(), 100*time.Millisecond)
+	require.Error(t, err)
+}
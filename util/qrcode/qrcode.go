@@ -0,0 +1,106 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package qrcode
+
+import (
+	"os"
+	"strings"
+
+	"rsc.io/qr"
+)
+
+// quietZone is the number of light modules around the code, as the QR code specification requires
+const quietZone = 4
+
+// pngScale is the number of image pixels per module in PNG output
+const pngScale = 8
+
+// ANSI escape sequences setting the foreground and background colors of the terminal
+const (
+	ansiFgDark  = "\x1b[30m"
+	ansiFgLight = "\x1b[97m"
+	ansiBgDark  = "\x1b[40m"
+	ansiBgLight = "\x1b[107m"
+	ansiReset   = "\x1b[0m"
+)
+
+// upperHalfBlock fills the top half of a character cell with the foreground color, and leaves the bottom
+// half in the background color, so that each character shows two rows of modules
+const upperHalfBlock = "▀"
+
+// Code is a QR code of some text, such as a mnemonic or an encoded transaction
+type Code struct {
+	code *qr.Code
+}
+
+// Encode returns the QR code of text, at the medium error correction level
+func Encode(text string) (*Code, error) {
+	code, err := qr.Encode(text, qr.M)
+	if err != nil {
+		return nil, err
+	}
+	code.Scale = pngScale
+	return &Code{code: code}, nil
+}
+
+// Size returns the number of modules on each side of the code, without the quiet zone
+func (c *Code) Size() int {
+	return c.code.Size
+}
+
+// Terminal renders the code with ANSI colors and half block characters, for printing on a terminal
+func (c *Code) Terminal() string {
+	var b strings.Builder
+	for y := -quietZone; y < c.code.Size+quietZone; y += 2 {
+		for x := -quietZone; x < c.code.Size+quietZone; x++ {
+			if c.code.Black(x, y) {
+				b.WriteString(ansiFgDark)
+			} else {
+				b.WriteString(ansiFgLight)
+			}
+			if c.code.Black(x, y+1) {
+				b.WriteString(ansiBgDark)
+			} else {
+				b.WriteString(ansiBgLight)
+			}
+			b.WriteString(upperHalfBlock)
+		}
+		b.WriteString(ansiReset)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// PNG returns the code as a PNG image
+func (c *Code) PNG() []byte {
+	return c.code.PNG()
+}
+
+// WritePNG writes the code as a PNG image to a new file, which only the user can read since the code may
+// hold a secret. It does not overwrite existing files.
+func (c *Code) WritePNG(filename string) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(c.PNG())
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package qrcode
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon invest"
+
+func TestTerminal(t *testing.T) {
+	code, err := Encode(testMnemonic)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(code.Terminal(), "\n"), "\n")
+	side := code.Size() + 2*quietZone
+	require.Len(t, lines, (side+1)/2)
+	for _, line := range lines {
+		require.Equal(t, side, strings.Count(line, upperHalfBlock))
+		require.True(t, strings.HasSuffix(line, ansiReset))
+	}
+
+	// The first row of characters is all quiet zone
+	require.NotContains(t, lines[0], ansiFgDark)
+	require.NotContains(t, lines[0], ansiBgDark)
+}
+
+func TestPNG(t *testing.T) {
+	code, err := Encode(testMnemonic)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(code.PNG()))
+	require.NoError(t, err)
+	side := (code.Size() + 2*quietZone) * pngScale
+	require.Equal(t, side, img.Bounds().Dx())
+	require.Equal(t, side, img.Bounds().Dy())
+
+	// The corner of the quiet zone is light, and the corner of the top left finder pattern is dark
+	dark := func(x, y int) bool {
+		return color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y < 0x80
+	}
+	require.False(t, dark(0, 0))
+	require.True(t, dark(quietZone*pngScale, quietZone*pngScale))
+}
+
+func TestWritePNG(t *testing.T) {
+	dir, err := ioutil.TempDir("", "qrcode")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	code, err := Encode(testMnemonic)
+	require.NoError(t, err)
+
+	filename := filepath.Join(dir, "code.png")
+	require.NoError(t, code.WritePNG(filename))
+	data, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, code.PNG(), data)
+
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// Existing files are not overwritten
+	require.Error(t, code.WritePNG(filename))
+}
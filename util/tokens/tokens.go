@@ -58,7 +58,7 @@ func GetAndValidateAPIToken(dataDir, tokenFilename string) (string, error) {
 // writeAPITokenToDisk persists the APIToken to the datadir
 func writeAPITokenToDisk(dataDir, tokenFilename, apiToken string) error {
 	filepath := tokenFilepath(dataDir, tokenFilename)
-	return ioutil.WriteFile(filepath, []byte(apiToken), 0644)
+	return ioutil.WriteFile(filepath, []byte(apiToken), 0600)
 }
 
 // GenerateAPIToken writes a cryptographically secure APIToken to disk
@@ -0,0 +1,304 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	exportQueueSize     = 4096
+	exportBatchSize     = 512
+	exportInterval      = 5 * time.Second
+	exportClientTimeout = 10 * time.Second
+)
+
+// Config configures the exported traces.
+type Config struct {
+	// Endpoint is the base URL of the OTLP/HTTP collector, such as http://localhost:4318
+	Endpoint string
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string
+	// ResourceAttributes are additional attributes describing the traced process.
+	ResourceAttributes []Attribute
+}
+
+// Tracer batches the ended spans and exports them to the collector.
+type Tracer struct {
+	config  Config
+	client  *http.Client
+	queue   chan *Span
+	flush   chan chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+	dropped uint64
+}
+
+// Start starts exporting the spans started by StartSpan to the configured collector.
+func Start(config Config) error {
+	if config.Endpoint == "" {
+		return fmt.Errorf("tracing endpoint was not specified")
+	}
+	if !strings.HasPrefix(config.Endpoint, "http://") && !strings.HasPrefix(config.Endpoint, "https://") {
+		return fmt.Errorf("tracing endpoint '%s' must be an http or https URL", config.Endpoint)
+	}
+	tracer := &Tracer{
+		config:  config,
+		client:  &http.Client{Timeout: exportClientTimeout},
+		queue:   make(chan *Span, exportQueueSize),
+		flush:   make(chan chan struct{}),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go tracer.exportLoop()
+	activeTracer.Store(tracer)
+	return nil
+}
+
+// Shutdown stops the tracing, after exporting the spans that were already ended.
+func Shutdown() {
+	tracer := currentTracer()
+	if tracer == nil {
+		return
+	}
+	activeTracer.Store((*Tracer)(nil))
+	close(tracer.done)
+	<-tracer.stopped
+}
+
+// DroppedSpans returns the number of spans that were dropped since the export queue was full.
+func DroppedSpans() uint64 {
+	tracer := currentTracer()
+	if tracer == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&tracer.dropped)
+}
+
+// Flush exports the spans that were already ended, and waits for the export to complete.
+func Flush() {
+	tracer := currentTracer()
+	if tracer == nil {
+		return
+	}
+	flushed := make(chan struct{})
+	select {
+	case tracer.flush <- flushed:
+		<-flushed
+	case <-tracer.done:
+	}
+}
+
+// enqueue queues an ended span for export, dropping it if the queue is full so that tracing never blocks.
+func (t *Tracer) enqueue(s *Span) {
+	select {
+	case t.queue <- s:
+	default:
+		atomic.AddUint64(&t.dropped, 1)
+	}
+}
+
+func (t *Tracer) exportLoop() {
+	defer close(t.stopped)
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+	batch := make([]*Span, 0, exportBatchSize)
+	// drain exports all the queued spans
+	drain := func() {
+		for {
+			select {
+			case s := <-t.queue:
+				batch = append(batch, s)
+				if len(batch) >= exportBatchSize {
+					t.export(batch)
+					batch = batch[:0]
+				}
+			default:
+				if len(batch) > 0 {
+					t.export(batch)
+					batch = batch[:0]
+				}
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case s := <-t.queue:
+			batch = append(batch, s)
+			if len(batch) >= exportBatchSize {
+				t.export(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			drain()
+		case flushed := <-t.flush:
+			drain()
+			close(flushed)
+		case <-t.done:
+			drain()
+			return
+		}
+	}
+}
+
+// export sends the given spans to the collector. Failed exports are dropped, as tracing is best effort.
+func (t *Tracer) export(spans []*Span) error {
+	body, err := json.Marshal(t.encode(spans))
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest("POST", strings.TrimSuffix(t.config.Endpoint, "/")+"/v1/traces", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := t.client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("trace collector responded with %s", response.Status)
+	}
+	return nil
+}
+
+// The OTLP/HTTP JSON encoding of the exported spans.
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// otlp status codes
+const (
+	otlpStatusOk    = 1
+	otlpStatusError = 2
+)
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func (t *Tracer) encode(spans []*Span) otlpTraces {
+	resource := otlpResource{Attributes: encodeAttributes(append([]Attribute{Attr("service.name", t.config.ServiceName)}, t.config.ResourceAttributes...))}
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.mu.Lock()
+		span := otlpSpan{
+			TraceID:           hex.EncodeToString(s.context.TraceID[:]),
+			SpanID:            hex.EncodeToString(s.context.SpanID[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        encodeAttributes(s.attributes),
+			Status:            otlpStatus{Code: otlpStatusOk},
+		}
+		if s.parent != (SpanID{}) {
+			span.ParentSpanID = hex.EncodeToString(s.parent[:])
+		}
+		if s.err != nil {
+			span.Status = otlpStatus{Code: otlpStatusError, Message: s.err.Error()}
+		}
+		s.mu.Unlock()
+		encoded = append(encoded, span)
+	}
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource:   resource,
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "github.com/algorand/go-algorand"}, Spans: encoded}},
+	}}}
+}
+
+func encodeAttributes(attributes []Attribute) []otlpKeyValue {
+	encoded := make([]otlpKeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		var v otlpValue
+		switch value := a.Value.(type) {
+		case bool:
+			v.BoolValue = &value
+		case int:
+			s := strconv.FormatInt(int64(value), 10)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(value, 10)
+			v.IntValue = &s
+		case uint64:
+			s := strconv.FormatUint(value, 10)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &value
+		case string:
+			v.StringValue = &value
+		default:
+			s := fmt.Sprintf("%v", value)
+			v.StringValue = &s
+		}
+		encoded = append(encoded, otlpKeyValue{Key: a.Key, Value: v})
+	}
+	return encoded
+}
@@ -0,0 +1,252 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package tracing implements opt-in distributed tracing. Spans are batched and exported to an OpenTelemetry
+// collector using the OTLP/HTTP JSON encoding, and the trace context is propagated using the W3C traceparent header.
+//
+// Tracing is disabled until Start is called; until then, StartSpan returns a nil span whose methods do nothing, so
+// instrumented code paths don't pay for tracing when it's off.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/algorand/go-deadlock"
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// TraceparentHeader is the W3C trace context header used for propagating traces across HTTP calls.
+const TraceparentHeader = "traceparent"
+
+// SpanKind describes the relationship of a span to its remote parent or children.
+type SpanKind int
+
+// The span kinds, using the OTLP numbering.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// Attribute is a key-value pair attached to a span.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr returns an attribute with the given key and value.
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanContext identifies a span across process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid returns true if both the trace and span identifiers are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// Span is a single timed operation within a trace.
+type Span struct {
+	tracer *Tracer
+
+	mu         deadlock.Mutex
+	context    SpanContext
+	parent     SpanID
+	name       string
+	kind       SpanKind
+	start      time.Time
+	end        time.Time
+	attributes []Attribute
+	err        error
+	ended      bool
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attributes = append(s.attributes, attributes...)
+}
+
+// SetError marks the span as failed with the given error. A nil error is ignored.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// End completes the span and queues it for export. Calling End more than once has no effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+	s.tracer.enqueue(s)
+}
+
+// Context returns the span context, for propagating it to remote services.
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.context
+}
+
+// activeTracer holds the started *Tracer, which is nil while tracing is disabled.
+var activeTracer atomic.Value
+
+func currentTracer() *Tracer {
+	tracer, _ := activeTracer.Load().(*Tracer)
+	return tracer
+}
+
+// Enabled returns true if a tracer was started.
+func Enabled() bool {
+	return currentTracer() != nil
+}
+
+type spanContextKey struct{}
+type remoteContextKey struct{}
+
+// StartSpan starts a new span, which is a child of the span carried by the given context, if any, or of the remote
+// span extracted into it. It returns a context carrying the new span, which must be ended by the caller.
+func StartSpan(ctx context.Context, name string, kind SpanKind, attributes ...Attribute) (context.Context, *Span) {
+	tracer := currentTracer()
+	if tracer == nil {
+		return ctx, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	span := &Span{
+		tracer:     tracer,
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		attributes: attributes,
+	}
+	var parent SpanContext
+	if parentSpan, ok := ctx.Value(spanContextKey{}).(*Span); ok {
+		parent = parentSpan.context
+	} else if remote, ok := ctx.Value(remoteContextKey{}).(SpanContext); ok {
+		parent = remote
+	}
+	if parent.IsValid() {
+		span.context.TraceID = parent.TraceID
+		span.parent = parent.SpanID
+	} else {
+		rand.Read(span.context.TraceID[:])
+	}
+	rand.Read(span.context.SpanID[:])
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// SpanFromContext returns the span carried by the given context, or nil if there's none.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// Extract returns a context carrying the remote span context of the traceparent header, if the header is valid.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, err := ParseTraceparent(header.Get(TraceparentHeader))
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteContextKey{}, sc)
+}
+
+// Inject sets the traceparent header to the span carried by the given context, if any.
+func Inject(ctx context.Context, header http.Header) {
+	if span := SpanFromContext(ctx); span != nil {
+		header.Set(TraceparentHeader, FormatTraceparent(span.context))
+	}
+}
+
+// StartRequestSpan starts a client span for the given outbound HTTP request, as a child of the span carried by the
+// request context, and injects it into the request headers so that the server continues the trace. It returns the
+// request with a context carrying the new span, which must be ended by the caller once the response was handled.
+func StartRequestSpan(request *http.Request, name string, attributes ...Attribute) (*http.Request, *Span) {
+	ctx, span := StartSpan(request.Context(), name, SpanKindClient, append(attributes,
+		Attr("http.method", request.Method),
+		Attr("http.url", request.URL.String()))...)
+	if span == nil {
+		return request, nil
+	}
+	request = request.WithContext(ctx)
+	Inject(ctx, request.Header)
+	return request, span
+}
+
+// FormatTraceparent encodes the span context as a version 00 traceparent header value, with the sampled flag set.
+func FormatTraceparent(sc SpanContext) string {
+	return fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(sc.TraceID[:]), hex.EncodeToString(sc.SpanID[:]))
+}
+
+// ParseTraceparent decodes a traceparent header value.
+func ParseTraceparent(value string) (sc SpanContext, err error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return sc, fmt.Errorf("invalid traceparent '%s'", value)
+	}
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(sc.TraceID) {
+		return sc, fmt.Errorf("invalid traceparent trace id '%s'", parts[1])
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(sc.SpanID) {
+		return sc, fmt.Errorf("invalid traceparent parent id '%s'", parts[2])
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid traceparent '%s'", value)
+	}
+	return sc, nil
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceparent(t *testing.T) {
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	require.True(t, sc.IsValid())
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", FormatTraceparent(sc))
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		_, err = ParseTraceparent(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestDisabledTracing(t *testing.T) {
+	ctx, span := StartSpan(context.Background(), "disabled", SpanKindInternal)
+	require.Nil(t, span)
+	require.Nil(t, SpanFromContext(ctx))
+	request, span := StartRequestSpan(httptest.NewRequest("GET", "http://relay.algodev.network/v1/block", nil), "disabled")
+	require.Nil(t, span)
+	require.Empty(t, request.Header.Get(TraceparentHeader))
+	span.SetAttributes(Attr("key", "value"))
+	span.SetError(errors.New("error"))
+	span.End()
+}
+
+func TestExportSpans(t *testing.T) {
+	received := make(chan otlpTraces, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/traces", r.URL.Path)
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var traces otlpTraces
+		require.NoError(t, json.Unmarshal(body, &traces))
+		received <- traces
+	}))
+	defer server.Close()
+
+	require.NoError(t, Start(Config{Endpoint: server.URL, ServiceName: "test"}))
+	defer Shutdown()
+
+	header := http.Header{}
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, parent := StartSpan(Extract(context.Background(), header), "parent", SpanKindServer, Attr("round", uint64(5)))
+	_, child := StartSpan(ctx, "child", SpanKindInternal)
+	child.SetError(errors.New("failed"))
+	child.End()
+	parent.End()
+
+	outgoing := http.Header{}
+	Inject(ctx, outgoing)
+	require.Equal(t, FormatTraceparent(parent.Context()), outgoing.Get(TraceparentHeader))
+
+	request := httptest.NewRequest("GET", "http://relay.algodev.network/v1/block", nil).WithContext(ctx)
+	request, client := StartRequestSpan(request, "fetch block")
+	require.Equal(t, FormatTraceparent(client.Context()), request.Header.Get(TraceparentHeader))
+	require.Equal(t, client, SpanFromContext(request.Context()))
+
+	Flush()
+	traces := <-received
+	require.Len(t, traces.ResourceSpans, 1)
+	require.Equal(t, "service.name", traces.ResourceSpans[0].Resource.Attributes[0].Key)
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "child", spans[0].Name)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].TraceID)
+	require.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+	require.Equal(t, otlpStatusError, spans[0].Status.Code)
+
+	require.Equal(t, "parent", spans[1].Name)
+	require.Equal(t, "00f067aa0ba902b7", spans[1].ParentSpanID)
+	require.Equal(t, SpanKindServer, spans[1].Kind)
+	require.Equal(t, "5", *spans[1].Attributes[0].Value.IntValue)
+}
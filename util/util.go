@@ -37,3 +37,12 @@ func RaiseRlimit(amount uint64) error {
 	}
 	return nil
 }
+
+// FreeDiskSpace returns the number of bytes available to unprivileged users on the filesystem holding the given path
+func FreeDiskSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,220 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package webhook posts JSON events to operator defined URLs, retrying failed deliveries and signing the events
+// with an HMAC so that the receivers could authenticate them.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/algorand/go-algorand/logging"
+)
+
+const (
+	// SignatureHeader is the header holding the hex encoded HMAC-SHA256 of the request body, prefixed with "sha256="
+	SignatureHeader = "X-Algorand-Signature"
+	// EventHeader is the header holding the type of the posted event
+	EventHeader = "X-Algorand-Event"
+
+	defaultMaxRetries   = 5
+	defaultTimeout      = 10 * time.Second
+	defaultRetryBackoff = time.Second
+	maxRetryBackoff     = time.Minute
+	queueSize           = 100
+)
+
+// Event is a single operational event, posted as JSON.
+type Event struct {
+	Type    string                 `json:"type"`
+	Time    time.Time              `json:"time"`
+	Node    string                 `json:"node,omitempty"`
+	Round   uint64                 `json:"round,omitempty"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Config configures a Dispatcher.
+type Config struct {
+	// URLs are the endpoints every event is posted to.
+	URLs []string
+	// Secret is the HMAC key the events are signed with. The events aren't signed when it is empty.
+	Secret string
+	// MaxRetries is the number of times a failed delivery is retried. Zero uses a default of 5.
+	MaxRetries int
+	// Timeout is the timeout of a single delivery attempt. Zero uses a default of 10 seconds.
+	Timeout time.Duration
+	// RetryBackoff is the delay before the first retry, which doubles with every further retry. Zero uses a default
+	// of a second.
+	RetryBackoff time.Duration
+}
+
+// Dispatcher posts events to the configured URLs in the background.
+type Dispatcher struct {
+	cfg    Config
+	log    logging.Logger
+	client *http.Client
+	events chan Event
+	// pending counts the events that were queued but not delivered yet
+	pending int64
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// MakeDispatcher creates a dispatcher, which posts events once started.
+func MakeDispatcher(cfg Config, log logging.Logger) *Dispatcher {
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		cfg:    cfg,
+		log:    log,
+		client: &http.Client{Timeout: cfg.Timeout},
+		events: make(chan Event, queueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start starts posting the sent events.
+func (d *Dispatcher) Start() {
+	d.wg.Add(1)
+	go d.dispatchLoop()
+}
+
+// Stop stops posting events, abandoning the ones that weren't delivered yet.
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// Drain waits up to the given timeout for the queued events to be delivered, and then stops posting events.
+func (d *Dispatcher) Drain(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for atomic.LoadInt64(&d.pending) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	d.Stop()
+}
+
+// Send queues the event for posting. The event is dropped if the queue is full, so that a slow endpoint would
+// never block the caller.
+func (d *Dispatcher) Send(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	atomic.AddInt64(&d.pending, 1)
+	select {
+	case d.events <- event:
+	default:
+		atomic.AddInt64(&d.pending, -1)
+		d.log.Warnf("webhook queue is full, dropping %s event", event.Type)
+	}
+}
+
+func (d *Dispatcher) dispatchLoop() {
+	defer d.wg.Done()
+	for {
+		select {
+		case event := <-d.events:
+			body, err := json.Marshal(event)
+			if err != nil {
+				d.log.Warnf("unable to encode %s event: %v", event.Type, err)
+				atomic.AddInt64(&d.pending, -1)
+				continue
+			}
+			for _, url := range d.cfg.URLs {
+				if err := d.deliver(url, event.Type, body); err != nil {
+					d.log.Warnf("unable to post %s event to %s: %v", event.Type, url, err)
+				}
+			}
+			atomic.AddInt64(&d.pending, -1)
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// deliver posts the event body to the given url, retrying with an exponential backoff.
+func (d *Dispatcher) deliver(url string, eventType string, body []byte) (err error) {
+	backoff := d.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		if err = d.post(url, eventType, body); err == nil || attempt >= d.cfg.MaxRetries {
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-d.ctx.Done():
+			return
+		}
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+func (d *Dispatcher) post(url string, eventType string, body []byte) error {
+	request, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(d.ctx)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, eventType)
+	if d.cfg.Secret != "" {
+		request.Header.Set(SignatureHeader, Sign(d.cfg.Secret, body))
+	}
+	response, err := d.client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("%s", response.Status)
+	}
+	return nil
+}
+
+// Sign returns the signature header value of the given body: its hex encoded HMAC-SHA256, prefixed with "sha256="
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns whether the given signature header value matches the body.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/logging"
+)
+
+func TestDispatcherRetriesAndSigns(t *testing.T) {
+	var attempts int32
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.True(t, Verify("secret", body, r.Header.Get(SignatureHeader)))
+		require.False(t, Verify("other secret", body, r.Header.Get(SignatureHeader)))
+		require.Equal(t, "node.stalled", r.Header.Get(EventHeader))
+		var event Event
+		require.NoError(t, json.Unmarshal(body, &event))
+		received <- event
+	}))
+	defer server.Close()
+
+	d := MakeDispatcher(Config{URLs: []string{server.URL}, Secret: "secret", RetryBackoff: time.Millisecond}, logging.TestingLog(t))
+	d.Start()
+	defer d.Stop()
+	d.Send(Event{Type: "node.stalled", Round: 5, Message: "no new round"})
+
+	select {
+	case event := <-received:
+		require.Equal(t, "node.stalled", event.Type)
+		require.Equal(t, uint64(5), event.Round)
+		require.False(t, event.Time.IsZero())
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "event wasn't delivered")
+	}
+	require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	d := MakeDispatcher(Config{URLs: []string{server.URL}, MaxRetries: 2, RetryBackoff: time.Millisecond}, logging.TestingLog(t))
+	err := d.deliver(server.URL, "disk.low", []byte("{}"))
+	require.Error(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestDispatcherDrain(t *testing.T) {
+	var delivered int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&delivered, 1)
+	}))
+	defer server.Close()
+
+	d := MakeDispatcher(Config{URLs: []string{server.URL}}, logging.TestingLog(t))
+	d.Start()
+	for i := 0; i < 5; i++ {
+		d.Send(Event{Type: "algod.restart"})
+	}
+	d.Drain(10 * time.Second)
+	require.Equal(t, int32(5), atomic.LoadInt32(&delivered))
+	require.Equal(t, int64(0), atomic.LoadInt64(&d.pending))
+}